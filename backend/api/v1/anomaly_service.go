@@ -134,6 +134,10 @@ func (s *AnomalyService) SearchAnomalies(ctx context.Context, request *v1pb.Sear
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, err.Error())
 		}
+		// Skip the anomaly types not defined in the v1 API yet, such as the certificate expiry anomalies.
+		if pbAnomaly.Type == v1pb.Anomaly_ANOMALY_TYPE_UNSPECIFIED {
+			continue
+		}
 		response.Anomalies = append(response.Anomalies, pbAnomaly)
 	}
 	return &response, nil
@@ -28,6 +28,7 @@ import (
 	"github.com/bytebase/bytebase/backend/plugin/idp/oidc"
 	"github.com/bytebase/bytebase/backend/plugin/metric"
 	"github.com/bytebase/bytebase/backend/runner/metricreport"
+	"github.com/bytebase/bytebase/backend/runner/ssogroupsync"
 	"github.com/bytebase/bytebase/backend/store"
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
@@ -40,17 +41,19 @@ type AuthService struct {
 	secret         string
 	licenseService enterpriseAPI.LicenseService
 	metricReporter *metricreport.Reporter
+	ssoGroupSyncer *ssogroupsync.Syncer
 	profile        *config.Profile
 	postCreateUser func(ctx context.Context, user *store.UserMessage, firstEndUser bool) error
 }
 
 // NewAuthService creates a new AuthService.
-func NewAuthService(store *store.Store, secret string, licenseService enterpriseAPI.LicenseService, metricReporter *metricreport.Reporter, profile *config.Profile, postCreateUser func(ctx context.Context, user *store.UserMessage, firstEndUser bool) error) *AuthService {
+func NewAuthService(store *store.Store, secret string, licenseService enterpriseAPI.LicenseService, metricReporter *metricreport.Reporter, ssoGroupSyncer *ssogroupsync.Syncer, profile *config.Profile, postCreateUser func(ctx context.Context, user *store.UserMessage, firstEndUser bool) error) *AuthService {
 	return &AuthService{
 		store:          store,
 		secret:         secret,
 		licenseService: licenseService,
 		metricReporter: metricReporter,
+		ssoGroupSyncer: ssoGroupSyncer,
 		profile:        profile,
 		postCreateUser: postCreateUser,
 	}
@@ -643,6 +646,7 @@ func (s *AuthService) getOrCreateUserWithIDP(ctx context.Context, request *v1pb.
 	}
 
 	var userInfo *storepb.IdentityProviderUserInfo
+	var claims map[string]any
 	var fieldMapping *storepb.FieldMapping
 	if idp.Type == storepb.IdentityProviderType_OAUTH2 {
 		oauth2Context := request.IdpContext.GetOauth2Context()
@@ -658,7 +662,7 @@ func (s *AuthService) getOrCreateUserWithIDP(ctx context.Context, request *v1pb.
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to exchange token: %v", err)
 		}
-		userInfo, err = oauth2IdentityProvider.UserInfo(token)
+		userInfo, claims, err = oauth2IdentityProvider.UserInfo(token)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to get user info: %v", err)
 		}
@@ -688,7 +692,7 @@ func (s *AuthService) getOrCreateUserWithIDP(ctx context.Context, request *v1pb.
 			return nil, status.Errorf(codes.Internal, "failed to exchange token: %v", err)
 		}
 
-		userInfo, err = oidcIDP.UserInfo(ctx, token, "")
+		userInfo, claims, err = oidcIDP.UserInfo(ctx, token, "")
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to get user info: %v", err)
 		}
@@ -746,6 +750,22 @@ func (s *AuthService) getOrCreateUserWithIDP(ctx context.Context, request *v1pb.
 		user = users[0]
 	}
 
+	if !user.MemberDeleted && common.FeatureFlag(common.FeatureFlagSSOGroupSync) {
+		// Map the groups in the claims to the workspace role and the project memberships on every login, instead of
+		// only assigning the default role to the new users.
+		mapping, err := s.ssoGroupSyncer.GetMapping(ctx, idp.ResourceID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get SSO group mapping: %v", err)
+		}
+		var groups []string
+		if mapping != nil {
+			groups = api.GetSSOGroups(claims, mapping.GetGroupClaim())
+		}
+		if user, err = s.ssoGroupSyncer.Apply(ctx, user, idp.ResourceID, groups); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to apply SSO group mapping: %v", err)
+		}
+	}
+
 	return user, nil
 }
 
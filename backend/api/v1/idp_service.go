@@ -232,7 +232,7 @@ func (s *IdentityProviderService) TestIdentityProvider(ctx context.Context, requ
 		if err != nil {
 			return nil, status.Errorf(codes.InvalidArgument, "failed to exchange access token, error: %s", err.Error())
 		}
-		if _, err := oauth2IdentityProvider.UserInfo(token); err != nil {
+		if _, _, err := oauth2IdentityProvider.UserInfo(token); err != nil {
 			return nil, status.Errorf(codes.InvalidArgument, "failed to get user info, error: %s", err.Error())
 		}
 	} else if identityProvider.Type == v1pb.IdentityProviderType_OIDC {
@@ -267,7 +267,7 @@ func (s *IdentityProviderService) TestIdentityProvider(ctx context.Context, requ
 		if err != nil {
 			return nil, status.Errorf(codes.InvalidArgument, "failed to exchange access token, error: %s", err.Error())
 		}
-		if _, err := oidcIdentityProvider.UserInfo(ctx, token, ""); err != nil {
+		if _, _, err := oidcIdentityProvider.UserInfo(ctx, token, ""); err != nil {
 			return nil, status.Errorf(codes.InvalidArgument, "failed to get user info, error: %s", err.Error())
 		}
 	} else {
@@ -298,6 +298,10 @@ func validateRole(dbType db.Type, upsert *db.DatabaseRoleUpsertMessage) error {
 				return status.Error(codes.InvalidArgument, "Invalid number for valid_until, mysql valid_until should be an integer.")
 			}
 		}
+	case db.Neo4j:
+		if upsert.Password != nil || upsert.ConnectionLimit != nil || upsert.ValidUntil != nil || upsert.Attribute != nil {
+			return status.Errorf(codes.InvalidArgument, "Neo4j role only supports the role name, please set the password on the users")
+		}
 	}
 
 	return nil
@@ -159,6 +159,36 @@ func (s *InstanceService) UpdateInstance(ctx context.Context, request *v1pb.Upda
 			if err != nil {
 				return nil, status.Errorf(codes.InvalidArgument, err.Error())
 			}
+			// The v1 data source doesn't carry the proxy chain, the authentication, the external secret, the read replica
+			// routing and the connection pool, so we keep the existing ones. The read-only data sources are distinguished
+			// by their titles.
+			for _, ds := range datasourceList {
+				for _, origin := range instance.DataSources {
+					if origin.Type == ds.Type && origin.Title == ds.Title {
+						ds.Proxy = origin.Proxy
+						ds.AuthenticationType = origin.AuthenticationType
+						ds.AWSRegion = origin.AWSRegion
+						ds.AWSAccessKeyID = origin.AWSAccessKeyID
+						ds.AWSObfuscatedSecretAccessKey = origin.AWSObfuscatedSecretAccessKey
+						ds.GCPCloudSQLConnectionName = origin.GCPCloudSQLConnectionName
+						ds.GCPCloudSQLUsePrivateIP = origin.GCPCloudSQLUsePrivateIP
+						ds.GCPObfuscatedCredentialsJSON = origin.GCPObfuscatedCredentialsJSON
+						ds.AzureTenantID = origin.AzureTenantID
+						ds.AzureClientID = origin.AzureClientID
+						ds.AzureObfuscatedClientSecret = origin.AzureObfuscatedClientSecret
+						ds.KerberosRealm = origin.KerberosRealm
+						ds.KerberosKDC = origin.KerberosKDC
+						ds.KerberosSPN = origin.KerberosSPN
+						ds.KerberosObfuscatedKeytab = origin.KerberosObfuscatedKeytab
+						ds.ExternalSecretReference = origin.ExternalSecretReference
+						ds.ReadReplicaRoutingPolicy = origin.ReadReplicaRoutingPolicy
+						ds.ReadReplicaFallbackToPrimary = origin.ReadReplicaFallbackToPrimary
+						ds.MaxConnections = origin.MaxConnections
+						ds.ConnectionIdleTimeoutSeconds = origin.ConnectionIdleTimeoutSeconds
+						break
+					}
+				}
+			}
 			patch.DataSources = &datasourceList
 		}
 	}
@@ -524,17 +524,18 @@ func (s *ProjectService) TestWebhook(ctx context.Context, request *v1pb.TestWebh
 	err = webhookPlugin.Post(
 		webhook.Type,
 		webhookPlugin.Context{
-			URL:          webhook.URL,
-			Level:        webhookPlugin.WebhookInfo,
-			ActivityType: string(api.ActivityIssueCreate),
-			Title:        fmt.Sprintf("Test webhook %q", webhook.Title),
-			Description:  "This is a test",
-			Link:         fmt.Sprintf("%s/project/%s/webhook/%s", setting.ExternalUrl, fmt.Sprintf("%s-%d", slug.Make(project.Title), project.UID), fmt.Sprintf("%s-%d", slug.Make(webhook.Title), webhook.ID)),
-			CreatorID:    api.SystemBotID,
-			CreatorName:  "Bytebase",
-			CreatorEmail: "support@bytebase.com",
-			CreatedTs:    time.Now().Unix(),
-			Project:      &webhookPlugin.Project{Name: project.Title},
+			URL:             webhook.URL,
+			Level:           webhookPlugin.WebhookInfo,
+			ActivityType:    string(api.ActivityIssueCreate),
+			Title:           fmt.Sprintf("Test webhook %q", webhook.Title),
+			Description:     "This is a test",
+			Link:            fmt.Sprintf("%s/project/%s/webhook/%s", setting.ExternalUrl, fmt.Sprintf("%s-%d", slug.Make(project.Title), project.UID), fmt.Sprintf("%s-%d", slug.Make(webhook.Title), webhook.ID)),
+			CreatorID:       api.SystemBotID,
+			CreatorName:     "Bytebase",
+			CreatorEmail:    "support@bytebase.com",
+			CreatedTs:       time.Now().Unix(),
+			Project:         &webhookPlugin.Project{Name: project.Title},
+			PayloadTemplate: webhookPlugin.GetPayloadTemplate(webhook.PayloadTemplates, string(api.ActivityIssueCreate)),
 		},
 	)
 
@@ -3,6 +3,7 @@ package v1
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
@@ -101,7 +102,7 @@ func (s *ReviewService) ApproveReview(ctx context.Context, request *v1pb.Approve
 		return nil, status.Errorf(codes.Internal, "failed to find user by id %v", principalID)
 	}
 
-	policy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{UID: &issue.Project.UID})
+	policy, err := utils.GetApproverProjectPolicy(ctx, s.store, issue.Project.UID)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get project policy, error: %v", err)
 	}
@@ -110,6 +111,12 @@ func (s *ReviewService) ApproveReview(ctx context.Context, request *v1pb.Approve
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to check if principal can approve step, error: %v", err)
 	}
+	if !canApprove {
+		canApprove, err = utils.CanUserApproveEscalatedStep(ctx, s.store, issue.UID, user, policy)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check if principal can approve the escalated step, error: %v", err)
+		}
+	}
 	if !canApprove {
 		return nil, status.Errorf(codes.PermissionDenied, "cannot approve because the user does not have the required permission")
 	}
@@ -290,6 +297,13 @@ func canUserApproveStep(step *storepb.ApprovalStep, user *store.UserMessage, pol
 			return false, errors.Errorf("invalid group value")
 		}
 	case *storepb.ApprovalNode_Role:
+		if email, ok := strings.CutPrefix(val.Role, api.ApprovalNodeUserPrefix); ok {
+			return user.Email == email, nil
+		}
+		// The external approver approves the step by the callback.
+		if strings.HasPrefix(val.Role, api.ApprovalNodeExternalApproverPrefix) {
+			return false, nil
+		}
 		if userHasProjectRole[val.Role] {
 			return true, nil
 		}
@@ -179,6 +179,66 @@ func TestCanUserApproveStep(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			step: &storepb.ApprovalStep{
+				Type: storepb.ApprovalStep_ANY,
+				Nodes: []*storepb.ApprovalNode{
+					{
+						Type: storepb.ApprovalNode_ANY_IN_GROUP,
+						Payload: &storepb.ApprovalNode_Role{
+							Role: "users/dev@example.com",
+						},
+					},
+				},
+			},
+			user: &store.UserMessage{
+				ID:    1,
+				Email: "dev@example.com",
+				Role:  api.Owner,
+			},
+			policy: &store.IAMPolicyMessage{},
+			want:   true,
+		},
+		{
+			step: &storepb.ApprovalStep{
+				Type: storepb.ApprovalStep_ANY,
+				Nodes: []*storepb.ApprovalNode{
+					{
+						Type: storepb.ApprovalNode_ANY_IN_GROUP,
+						Payload: &storepb.ApprovalNode_Role{
+							Role: "users/dev@example.com",
+						},
+					},
+				},
+			},
+			user: &store.UserMessage{
+				ID:    1,
+				Email: "dba@example.com",
+				Role:  api.Owner,
+			},
+			policy: &store.IAMPolicyMessage{},
+			want:   false,
+		},
+		{
+			step: &storepb.ApprovalStep{
+				Type: storepb.ApprovalStep_ANY,
+				Nodes: []*storepb.ApprovalNode{
+					{
+						Type: storepb.ApprovalNode_ANY_IN_GROUP,
+						Payload: &storepb.ApprovalNode_Role{
+							Role: "externalApprovers/change-board",
+						},
+					},
+				},
+			},
+			user: &store.UserMessage{
+				ID:    1,
+				Email: "dba@example.com",
+				Role:  api.Owner,
+			},
+			policy: &store.IAMPolicyMessage{},
+			want:   false,
+		},
 	}
 
 	a := require.New(t)
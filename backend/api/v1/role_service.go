@@ -119,6 +119,15 @@ func (s *RoleService) DeleteRole(ctx context.Context, request *v1pb.DeleteRoleRe
 	if has {
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete because role %s is used in project %s", convertToRoleName(roleID), fmt.Sprintf("%s%s", projectNamePrefix, project))
 	}
+	if common.FeatureFlag(common.FeatureFlagRoleMember) {
+		members, err := s.store.ListRoleMembers(ctx, &store.FindRoleMemberMessage{Role: &roleID})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check if the role is used: %v", err)
+		}
+		if len(members) > 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot delete because role %s is assigned in the workspace", convertToRoleName(roleID))
+		}
+	}
 	if err := s.store.DeleteRole(ctx, roleID); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete role: %v", err)
 	}
@@ -55,6 +55,13 @@ var whitelistSettings = []api.SettingName{
 	api.SettingPluginOpenAIEndpoint,
 	api.SettingWorkspaceApproval,
 	api.SettingWorkspaceMailDelivery,
+	api.SettingWorkspaceCloudDiscovery,
+	api.SettingWorkspaceQueryHistoryRetention,
+	api.SettingWorkspaceApprovalChain,
+	api.SettingWorkspaceAuditSink,
+	api.SettingWorkspaceAlerting,
+	api.SettingWorkspaceSCIM,
+	api.SettingWorkspaceSSOGroupMapping,
 }
 
 var (
@@ -216,6 +223,96 @@ func (s *SettingService) SetSetting(ctx context.Context, request *v1pb.SetSettin
 			return nil, status.Errorf(codes.Internal, "failed to marshal setting value: %v", err)
 		}
 		storeSettingValue = string(bytes)
+	case api.SettingWorkspaceCloudDiscovery:
+		settingValue := request.Setting.Value.GetStringValue()
+		if _, err := api.ValidateAndGetCloudDiscoverySetting(settingValue); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cloud discovery setting: %v", err)
+		}
+		storeSettingValue = settingValue
+	case api.SettingWorkspaceQueryHistoryRetention:
+		settingValue := request.Setting.Value.GetStringValue()
+		if _, err := api.ValidateAndGetQueryHistoryRetentionSetting(settingValue); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid query history retention setting: %v", err)
+		}
+		storeSettingValue = settingValue
+	case api.SettingWorkspaceApprovalChain:
+		settingValue := request.Setting.Value.GetStringValue()
+		if _, err := api.ValidateAndGetApprovalChainSetting(settingValue); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid approval chain setting: %v", err)
+		}
+		storeSettingValue = settingValue
+	case api.SettingAppSlack:
+		if !s.licenseService.IsFeatureEnabled(api.FeatureIMApproval) {
+			return nil, status.Errorf(codes.PermissionDenied, api.FeatureIMApproval.AccessErrorMessage())
+		}
+		settingValue := request.Setting.Value.GetStringValue()
+		if _, err := api.ValidateAndGetSlackSetting(settingValue); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid Slack setting: %v", err)
+		}
+		storeSettingValue = settingValue
+	case api.SettingAppTeams:
+		if !s.licenseService.IsFeatureEnabled(api.FeatureIMApproval) {
+			return nil, status.Errorf(codes.PermissionDenied, api.FeatureIMApproval.AccessErrorMessage())
+		}
+		settingValue := request.Setting.Value.GetStringValue()
+		if _, err := api.ValidateAndGetTeamsSetting(settingValue); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid Teams setting: %v", err)
+		}
+		storeSettingValue = settingValue
+	case api.SettingWorkspaceAuditSink:
+		oldSetting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &apiSettingName})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get audit sink setting: %v", err)
+		}
+		oldValue := ""
+		if oldSetting != nil {
+			oldValue = oldSetting.Value
+		}
+		settingValue, err := api.InheritAuditSinkTokens(oldValue, request.Setting.Value.GetStringValue())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid audit sink setting: %v", err)
+		}
+		storeSettingValue = settingValue
+	case api.SettingWorkspaceAlerting:
+		oldSetting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &apiSettingName})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get alerting setting: %v", err)
+		}
+		oldValue := ""
+		if oldSetting != nil {
+			oldValue = oldSetting.Value
+		}
+		settingValue, err := api.InheritAlertReceiverKeys(oldValue, request.Setting.Value.GetStringValue())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid alerting setting: %v", err)
+		}
+		storeSettingValue = settingValue
+	case api.SettingWorkspaceSCIM:
+		if !s.licenseService.IsFeatureEnabled(api.FeatureSSO) {
+			return nil, status.Errorf(codes.PermissionDenied, api.FeatureSSO.AccessErrorMessage())
+		}
+		oldSetting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &apiSettingName})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get SCIM setting: %v", err)
+		}
+		oldValue := ""
+		if oldSetting != nil {
+			oldValue = oldSetting.Value
+		}
+		settingValue, err := api.InheritSCIMToken(oldValue, request.Setting.Value.GetStringValue())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid SCIM setting: %v", err)
+		}
+		storeSettingValue = settingValue
+	case api.SettingWorkspaceSSOGroupMapping:
+		if !s.licenseService.IsFeatureEnabled(api.FeatureSSO) {
+			return nil, status.Errorf(codes.PermissionDenied, api.FeatureSSO.AccessErrorMessage())
+		}
+		settingValue := request.Setting.Value.GetStringValue()
+		if _, err := api.ValidateAndGetSSOGroupMappingSetting(settingValue); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid SSO group mapping setting: %v", err)
+		}
+		storeSettingValue = settingValue
 	default:
 		storeSettingValue = request.Setting.Value.GetStringValue()
 	}
@@ -462,6 +559,24 @@ func stripSensitiveData(setting *v1pb.Setting) (*v1pb.Setting, error) {
 		mailDeliveryValue.SmtpMailDeliverySettingValue.Cert = nil
 		mailDeliveryValue.SmtpMailDeliverySettingValue.Key = nil
 		setting.Value.Value = mailDeliveryValue
+	case api.SettingWorkspaceAuditSink:
+		value, err := api.RedactAuditSinkTokens(setting.Value.GetStringValue())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to redact audit sink setting: %v", err)
+		}
+		setting.Value.Value = &v1pb.Value_StringValue{StringValue: value}
+	case api.SettingWorkspaceAlerting:
+		value, err := api.RedactAlertReceiverKeys(setting.Value.GetStringValue())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to redact alerting setting: %v", err)
+		}
+		setting.Value.Value = &v1pb.Value_StringValue{StringValue: value}
+	case api.SettingWorkspaceSCIM:
+		value, err := api.RedactSCIMToken(setting.Value.GetStringValue())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to redact SCIM setting: %v", err)
+		}
+		setting.Value.Value = &v1pb.Value_StringValue{StringValue: value}
 	default:
 	}
 	return setting, nil
@@ -34,6 +34,7 @@ func getBaseProfile(dataDir string) config.Profile {
 		BackupBucket:         flags.backupBucket,
 		BackupCredentialFile: flags.backupCredential,
 		FeishuAPIURL:         feishu.APIPath,
+		OTLPEndpoint:         flags.otelEndpoint,
 		LastActiveTs:         time.Now().Unix(),
 	}
 }
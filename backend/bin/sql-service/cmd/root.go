@@ -24,6 +24,20 @@ import (
 	_ "github.com/bytebase/bytebase/backend/plugin/advisor/mysql"
 	// Register postgresql advisor.
 	_ "github.com/bytebase/bytebase/backend/plugin/advisor/pg"
+	// Register mongodb advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/mongodb"
+	// Register redis advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/redis"
+	// Register elasticsearch advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/elasticsearch"
+	// Register cassandra advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/cassandra"
+	// Register dynamodb advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/dynamodb"
+	// Register neo4j advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/neo4j"
+	// Register snowflake advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/snowflake"
 
 	// Register postgres parser driver.
 	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/engine/pg"
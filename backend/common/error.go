@@ -35,7 +35,8 @@ const (
 	MigrationFailed  Code = 206
 
 	// 301 task error.
-	TaskTimingNotAllowed Code = 301
+	TaskTimingNotAllowed      Code = 301
+	TaskDependentObjectBroken Code = 302
 
 	// 401 task sql type error.
 	TaskTypeNotDML         Code = 401
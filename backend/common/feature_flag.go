@@ -22,4 +22,58 @@ type FeatureFlagType string
 const (
 	// FeatureFlagNoop is a noop feature flag for demonstration purpose.
 	FeatureFlagNoop FeatureFlagType = "bb.feature-flag.noop"
+	// FeatureFlagUnusedIndex is the feature flag for collecting unused indexes from the engine statistics.
+	FeatureFlagUnusedIndex FeatureFlagType = "bb.feature-flag.unused-index"
+	// FeatureFlagDatabaseTLS is the feature flag for the database level TLS configuration.
+	FeatureFlagDatabaseTLS FeatureFlagType = "bb.feature-flag.database-tls"
+	// FeatureFlagCloudDiscovery is the feature flag for discovering the instances from the cloud providers.
+	FeatureFlagCloudDiscovery FeatureFlagType = "bb.feature-flag.cloud-discovery"
+	// FeatureFlagScheduledQuery is the feature flag for running the saved queries on schedule.
+	FeatureFlagScheduledQuery FeatureFlagType = "bb.feature-flag.scheduled-query"
+	// FeatureFlagNotebook is the feature flag for the notebook sheets with the persisted cell results.
+	FeatureFlagNotebook FeatureFlagType = "bb.feature-flag.notebook"
+	// FeatureFlagQueryHistory is the feature flag for searching the query histories of the SQL editor.
+	FeatureFlagQueryHistory FeatureFlagType = "bb.feature-flag.query-history"
+	// FeatureFlagSnippet is the feature flag for the shared snippet library of the SQL editor.
+	FeatureFlagSnippet FeatureFlagType = "bb.feature-flag.snippet"
+	// FeatureFlagAdminSession is the feature flag for recording the transcripts of the admin mode editor sessions.
+	FeatureFlagAdminSession FeatureFlagType = "bb.feature-flag.admin-session"
+	// FeatureFlagPIIScan is the feature flag for scanning the database columns for the PII.
+	FeatureFlagPIIScan FeatureFlagType = "bb.feature-flag.pii-scan"
+	// FeatureFlagRoleMember is the feature flag for assigning the custom roles at the workspace scope.
+	FeatureFlagRoleMember FeatureFlagType = "bb.feature-flag.role-member"
+	// FeatureFlagApprovalChain is the feature flag for escalating the pending approval steps and the external approvers.
+	FeatureFlagApprovalChain FeatureFlagType = "bb.feature-flag.approval-chain"
+	// FeatureFlagAutoApproval is the feature flag for approving the issues by the auto-approval rules.
+	FeatureFlagAutoApproval FeatureFlagType = "bb.feature-flag.auto-approval"
+	// FeatureFlagDatabaseGrant is the feature flag for the temporary access to the databases.
+	FeatureFlagDatabaseGrant FeatureFlagType = "bb.feature-flag.database-grant"
+	// FeatureFlagAuditSink is the feature flag for streaming the audit logs to the external sinks.
+	FeatureFlagAuditSink FeatureFlagType = "bb.feature-flag.audit-sink"
+	// FeatureFlagExportRequest is the feature flag for approving the export of the query results.
+	FeatureFlagExportRequest FeatureFlagType = "bb.feature-flag.export-request"
+	// FeatureFlagDatabaseClone is the feature flag for cloning the databases into the lower environments.
+	FeatureFlagDatabaseClone FeatureFlagType = "bb.feature-flag.database-clone"
+	// FeatureFlagRowLevelSecurity is the feature flag for syncing the row level security of the PostgreSQL tables.
+	FeatureFlagRowLevelSecurity FeatureFlagType = "bb.feature-flag.row-level-security"
+	// FeatureFlagVCSRelease is the feature flag for rolling out the releases built from the tags of the linked repositories.
+	FeatureFlagVCSRelease FeatureFlagType = "bb.feature-flag.vcs-release"
+	// FeatureFlagRoutingRule is the feature flag for routing the migration files of the linked repositories to the other projects.
+	FeatureFlagRoutingRule FeatureFlagType = "bb.feature-flag.routing-rule"
+	// FeatureFlagRollbackPR is the feature flag for opening the rollback pull requests of the data changes.
+	FeatureFlagRollbackPR FeatureFlagType = "bb.feature-flag.rollback-pr"
+	// FeatureFlagWebhookEvent is the feature flag for recording and replaying the VCS webhook deliveries.
+	FeatureFlagWebhookEvent FeatureFlagType = "bb.feature-flag.webhook-event"
+	// FeatureFlagSchemaWriteBack is the feature flag for writing back the schema by the pull requests and the database metadata files.
+	FeatureFlagSchemaWriteBack FeatureFlagType = "bb.feature-flag.schema-write-back"
+	// FeatureFlagBranchRule is the feature flag for rolling out the pushes to the environments by the branch rules of the linked repositories.
+	FeatureFlagBranchRule FeatureFlagType = "bb.feature-flag.branch-rule"
+	// FeatureFlagWebhookPayloadTemplate is the feature flag for customizing the payloads of the project webhooks by the templates.
+	FeatureFlagWebhookPayloadTemplate FeatureFlagType = "bb.feature-flag.webhook-payload-template"
+	// FeatureFlagAlerting is the feature flag for opening the incidents of the failed tasks, the schema drift and the unreachable instances in PagerDuty and Opsgenie.
+	FeatureFlagAlerting FeatureFlagType = "bb.feature-flag.alerting"
+	// FeatureFlagSCIM is the feature flag for provisioning the users and the user groups from the identity providers through SCIM.
+	FeatureFlagSCIM FeatureFlagType = "bb.feature-flag.scim"
+	// FeatureFlagSSOGroupSync is the feature flag for mapping the identity provider groups to the workspace roles and the project memberships on SSO login.
+	FeatureFlagSSOGroupSync FeatureFlagType = "bb.feature-flag.sso-group-sync"
 )
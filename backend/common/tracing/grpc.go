@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadataCarrier is the carrier of the propagated context in the gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	var keys []string
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// UnaryServerInterceptor creates a server span for each of the gRPC calls, as the child of the span propagated by the
+// caller if any.
+func UnaryServerInterceptor(ctx context.Context, request any, serverInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	}
+	service, method := splitFullMethod(serverInfo.FullMethod)
+	ctx, span := StartWithKind(ctx, strings.TrimPrefix(serverInfo.FullMethod, "/"), trace.SpanKindServer,
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", method),
+	)
+	defer span.End()
+
+	response, err := handler(ctx, request)
+	s, _ := status.FromError(err)
+	span.SetAttributes(attribute.Int64("rpc.grpc.status_code", int64(s.Code())))
+	if err != nil {
+		span.SetStatus(codes.Error, s.Message())
+	}
+	return response, err
+}
+
+// splitFullMethod splits the full method, e.g. /bytebase.v1.SQLService/Query, into the service and the method.
+func splitFullMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "", fullMethod
+}
+
+// GatewayHeaderMatcher forwards the trace context headers of the HTTP requests to the gRPC metadata, in addition to the
+// ones forwarded by default.
+func GatewayHeaderMatcher(key string) (string, bool) {
+	switch strings.ToLower(key) {
+	case "traceparent", "tracestate", "baggage":
+		return strings.ToLower(key), true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
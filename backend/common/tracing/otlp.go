@@ -0,0 +1,217 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// otlpExporter exports the spans by OTLP/HTTP in the JSON encoding.
+// https://opentelemetry.io/docs/specs/otlp/#otlphttp
+type otlpExporter struct {
+	client *http.Client
+	url    string
+}
+
+func newOTLPExporter(endpoint string) *otlpExporter {
+	return &otlpExporter{
+		client: &http.Client{Timeout: exportInterval},
+		url:    strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+	}
+}
+
+type otlpTracesRequest struct {
+	ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   *otlpResource     `json:"resource"`
+	ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []*otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope *otlpScope  `json:"scope"`
+	Spans []*otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	TraceState        string          `json:"traceState,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []*otlpKeyValue `json:"attributes,omitempty"`
+	Events            []*otlpEvent    `json:"events,omitempty"`
+	Status            *otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string          `json:"timeUnixNano"`
+	Name         string          `json:"name"`
+	Attributes   []*otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string        `json:"key"`
+	Value *otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []*otlpAnyValue `json:"values"`
+}
+
+func (e *otlpExporter) export(ctx context.Context, resource []attribute.KeyValue, spans []*spanData) error {
+	body, err := json.Marshal(convertToOTLP(resource, spans))
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal spans")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct request to %s", e.url)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to post to %s", e.url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("failed to post to %s, status %d: %s", e.url, resp.StatusCode, string(b))
+	}
+	return nil
+}
+
+func convertToOTLP(resource []attribute.KeyValue, spans []*spanData) *otlpTracesRequest {
+	var scopeSpansList []*otlpScopeSpans
+	scopeSpansByName := make(map[string]*otlpScopeSpans)
+	for _, s := range spans {
+		scopeSpans, ok := scopeSpansByName[s.scope]
+		if !ok {
+			scopeSpans = &otlpScopeSpans{Scope: &otlpScope{Name: s.scope}}
+			scopeSpansByName[s.scope] = scopeSpans
+			scopeSpansList = append(scopeSpansList, scopeSpans)
+		}
+		span := &otlpSpan{
+			TraceID:           s.spanContext.TraceID().String(),
+			SpanID:            s.spanContext.SpanID().String(),
+			TraceState:        s.spanContext.TraceState().String(),
+			Name:              s.name,
+			Kind:              int(s.kind),
+			StartTimeUnixNano: strconv.FormatInt(s.startTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.endTime.UnixNano(), 10),
+			Attributes:        convertToOTLPAttributes(s.attributes),
+			Status:            &otlpStatus{Code: convertToOTLPStatusCode(s.statusCode), Message: s.statusDetail},
+		}
+		if s.parent.SpanID().IsValid() {
+			span.ParentSpanID = s.parent.SpanID().String()
+		}
+		for _, event := range s.events {
+			span.Events = append(span.Events, &otlpEvent{
+				TimeUnixNano: strconv.FormatInt(event.time.UnixNano(), 10),
+				Name:         event.name,
+				Attributes:   convertToOTLPAttributes(event.attributes),
+			})
+		}
+		scopeSpans.Spans = append(scopeSpans.Spans, span)
+	}
+	return &otlpTracesRequest{
+		ResourceSpans: []*otlpResourceSpans{
+			{
+				Resource:   &otlpResource{Attributes: convertToOTLPAttributes(resource)},
+				ScopeSpans: scopeSpansList,
+			},
+		},
+	}
+}
+
+func convertToOTLPAttributes(attributes []attribute.KeyValue) []*otlpKeyValue {
+	var list []*otlpKeyValue
+	for _, kv := range attributes {
+		list = append(list, &otlpKeyValue{Key: string(kv.Key), Value: convertToOTLPValue(kv.Value)})
+	}
+	return list
+}
+
+func convertToOTLPValue(value attribute.Value) *otlpAnyValue {
+	switch value.Type() {
+	case attribute.BOOL:
+		v := value.AsBool()
+		return &otlpAnyValue{BoolValue: &v}
+	case attribute.INT64:
+		v := strconv.FormatInt(value.AsInt64(), 10)
+		return &otlpAnyValue{IntValue: &v}
+	case attribute.FLOAT64:
+		v := value.AsFloat64()
+		return &otlpAnyValue{DoubleValue: &v}
+	case attribute.BOOLSLICE:
+		array := &otlpArrayValue{}
+		for _, v := range value.AsBoolSlice() {
+			array.Values = append(array.Values, convertToOTLPValue(attribute.BoolValue(v)))
+		}
+		return &otlpAnyValue{ArrayValue: array}
+	case attribute.INT64SLICE:
+		array := &otlpArrayValue{}
+		for _, v := range value.AsInt64Slice() {
+			array.Values = append(array.Values, convertToOTLPValue(attribute.Int64Value(v)))
+		}
+		return &otlpAnyValue{ArrayValue: array}
+	case attribute.FLOAT64SLICE:
+		array := &otlpArrayValue{}
+		for _, v := range value.AsFloat64Slice() {
+			array.Values = append(array.Values, convertToOTLPValue(attribute.Float64Value(v)))
+		}
+		return &otlpAnyValue{ArrayValue: array}
+	case attribute.STRINGSLICE:
+		array := &otlpArrayValue{}
+		for _, v := range value.AsStringSlice() {
+			array.Values = append(array.Values, convertToOTLPValue(attribute.StringValue(v)))
+		}
+		return &otlpAnyValue{ArrayValue: array}
+	}
+	v := value.Emit()
+	return &otlpAnyValue{StringValue: &v}
+}
+
+// convertToOTLPStatusCode converts the status code, the values of Ok and Error are swapped in OTLP.
+func convertToOTLPStatusCode(code codes.Code) int {
+	switch code {
+	case codes.Ok:
+		return 1
+	case codes.Error:
+		return 2
+	}
+	return 0
+}
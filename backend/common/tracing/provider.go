@@ -0,0 +1,307 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+)
+
+const (
+	// exportInterval is the interval of exporting the ended spans.
+	exportInterval = 5 * time.Second
+	// exportBatchSize is the number of the ended spans triggering an export before the interval.
+	exportBatchSize = 512
+	// queueSize is the size of the queue of the ended spans, the spans are dropped if the queue is full.
+	queueSize = 2048
+	// maxEventsPerSpan is the maximum number of the events of a span, the later ones are dropped.
+	maxEventsPerSpan = 128
+)
+
+// exporter exports the batches of the ended spans.
+type exporter interface {
+	export(ctx context.Context, resource []attribute.KeyValue, spans []*spanData) error
+}
+
+// spanData is the data of an ended span.
+type spanData struct {
+	scope        string
+	name         string
+	kind         trace.SpanKind
+	spanContext  trace.SpanContext
+	parent       trace.SpanContext
+	startTime    time.Time
+	endTime      time.Time
+	attributes   []attribute.KeyValue
+	events       []*eventData
+	statusCode   codes.Code
+	statusDetail string
+}
+
+type eventData struct {
+	name       string
+	time       time.Time
+	attributes []attribute.KeyValue
+}
+
+// Provider is the tracer provider sampling all the spans, except the ones whose remote parents are not sampled. The
+// ended spans are exported in batches in the background.
+type Provider struct {
+	exporter exporter
+	resource []attribute.KeyValue
+	queue    chan *spanData
+	done     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// NewProvider creates a tracer provider exporting the spans with the resource attributes.
+func NewProvider(exporter exporter, resource []attribute.KeyValue) *Provider {
+	p := &Provider{
+		exporter: exporter,
+		resource: resource,
+		queue:    make(chan *spanData, queueSize),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Tracer returns the tracer of the instrumentation.
+func (p *Provider) Tracer(name string, _ ...trace.TracerOption) trace.Tracer {
+	return &tracer{provider: p, scope: name}
+}
+
+// Shutdown exports the queued spans and stops the provider, the spans ended afterwards are dropped.
+func (p *Provider) Shutdown(ctx context.Context) {
+	p.stopOnce.Do(func() {
+		close(p.done)
+	})
+	select {
+	case <-p.stopped:
+	case <-ctx.Done():
+	}
+}
+
+func (p *Provider) run() {
+	defer close(p.stopped)
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+	var batch []*spanData
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), exportInterval)
+		defer cancel()
+		if err := p.exporter.export(ctx, p.resource, batch); err != nil {
+			log.Debug(fmt.Sprintf("Failed to export %d spans: %v", len(batch), err))
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case span := <-p.queue:
+			batch = append(batch, span)
+			if len(batch) >= exportBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-p.done:
+			for {
+				select {
+				case span := <-p.queue:
+					batch = append(batch, span)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *Provider) enqueue(data *spanData) {
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+	select {
+	case p.queue <- data:
+	default:
+		// Drop the span rather than blocking the traced code.
+	}
+}
+
+type tracer struct {
+	provider *Provider
+	scope    string
+}
+
+func (t *tracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	config := trace.NewSpanStartConfig(opts...)
+	parent := trace.SpanContextFromContext(ctx)
+	if config.NewRoot() {
+		parent = trace.SpanContext{}
+	}
+
+	traceID := parent.TraceID()
+	if !parent.TraceID().IsValid() {
+		traceID = newTraceID()
+	}
+	flags := trace.FlagsSampled
+	if parent.IsValid() && parent.IsRemote() && !parent.IsSampled() {
+		flags = 0
+	}
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     newSpanID(),
+		TraceFlags: flags,
+		TraceState: parent.TraceState(),
+	})
+	s := &span{
+		tracer:    t,
+		recording: spanContext.IsSampled(),
+		data: &spanData{
+			scope:       t.scope,
+			name:        spanName,
+			kind:        config.SpanKind(),
+			spanContext: spanContext,
+			parent:      parent,
+			startTime:   config.Timestamp(),
+			attributes:  config.Attributes(),
+		},
+	}
+	if s.data.startTime.IsZero() {
+		s.data.startTime = time.Now()
+	}
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+// span is the span of the tracer, it's only recorded if it's sampled.
+type span struct {
+	tracer    *tracer
+	recording bool
+
+	mu    sync.Mutex
+	ended bool
+	data  *spanData
+}
+
+func (s *span) End(opts ...trace.SpanEndOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.ended = true
+	if !s.recording {
+		return
+	}
+	config := trace.NewSpanEndConfig(opts...)
+	s.data.endTime = config.Timestamp()
+	if s.data.endTime.IsZero() {
+		s.data.endTime = time.Now()
+	}
+	s.tracer.provider.enqueue(s.data)
+}
+
+func (s *span) AddEvent(name string, opts ...trace.EventOption) {
+	config := trace.NewEventConfig(opts...)
+	s.addEvent(name, config.Timestamp(), config.Attributes())
+}
+
+func (s *span) addEvent(name string, t time.Time, attributes []attribute.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.recording || s.ended || len(s.data.events) >= maxEventsPerSpan {
+		return
+	}
+	if t.IsZero() {
+		t = time.Now()
+	}
+	s.data.events = append(s.data.events, &eventData{name: name, time: t, attributes: attributes})
+}
+
+func (s *span) IsRecording() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recording && !s.ended
+}
+
+func (s *span) RecordError(err error, opts ...trace.EventOption) {
+	if err == nil {
+		return
+	}
+	config := trace.NewEventConfig(opts...)
+	attributes := append([]attribute.KeyValue{
+		attribute.String("exception.type", fmt.Sprintf("%T", err)),
+		attribute.String("exception.message", err.Error()),
+	}, config.Attributes()...)
+	s.addEvent("exception", config.Timestamp(), attributes)
+}
+
+func (s *span) SpanContext() trace.SpanContext {
+	return s.data.spanContext
+}
+
+func (s *span) SetStatus(code codes.Code, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.recording || s.ended {
+		return
+	}
+	// The Ok status is final, and the description is only for the Error status.
+	if s.data.statusCode == codes.Ok {
+		return
+	}
+	s.data.statusCode = code
+	s.data.statusDetail = ""
+	if code == codes.Error {
+		s.data.statusDetail = description
+	}
+}
+
+func (s *span) SetName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.recording || s.ended {
+		return
+	}
+	s.data.name = name
+}
+
+func (s *span) SetAttributes(kv ...attribute.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.recording || s.ended {
+		return
+	}
+	s.data.attributes = append(s.data.attributes, kv...)
+}
+
+func (s *span) TracerProvider() trace.TracerProvider {
+	return s.tracer.provider
+}
+
+func newTraceID() trace.TraceID {
+	var id trace.TraceID
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() trace.SpanID {
+	var id trace.SpanID
+	_, _ = rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,53 @@
+// Package tracing provides the OpenTelemetry tracing of Bytebase. The spans are created by the OpenTelemetry API, and
+// exported to the OTLP/HTTP endpoint, e.g. the OpenTelemetry Collector, Jaeger or Tempo, if the endpoint is set.
+// Otherwise, the global tracer provider is the no-op one, and the spans cost nothing.
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer of Bytebase.
+const instrumentationName = "github.com/bytebase/bytebase"
+
+// Init sets the global tracer provider exporting the spans to the OTLP/HTTP endpoint, e.g. http://localhost:4318, and
+// the W3C trace context propagator. The returned provider needs to be shut down to flush the spans on exit.
+func Init(endpoint, serviceName, serviceVersion string) (*Provider, error) {
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, errors.Errorf("OTLP endpoint %q must start with http:// or https://", endpoint)
+	}
+	provider := NewProvider(newOTLPExporter(endpoint), []attribute.KeyValue{
+		attribute.String("service.name", serviceName),
+		attribute.String("service.version", serviceVersion),
+	})
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider, nil
+}
+
+// Start starts a span of Bytebase as the child of the span in the context.
+func Start(ctx context.Context, spanName string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return StartWithKind(ctx, spanName, trace.SpanKindInternal, attributes...)
+}
+
+// StartWithKind starts a span of the kind, e.g. the server span of the API or the client span of the database.
+func StartWithKind(ctx context.Context, spanName string, kind trace.SpanKind, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, spanName, trace.WithSpanKind(kind), trace.WithAttributes(attributes...))
+}
+
+// End ends the span, the error is recorded on the span if it's not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
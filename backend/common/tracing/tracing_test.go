@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestProviderExport(t *testing.T) {
+	a := require.New(t)
+	var requests []*otlpTracesRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.Equal("/v1/traces", r.URL.Path)
+		a.Equal("application/json", r.Header.Get("Content-Type"))
+		request := &otlpTracesRequest{}
+		a.NoError(json.NewDecoder(r.Body).Decode(request))
+		requests = append(requests, request)
+	}))
+	defer server.Close()
+
+	provider := NewProvider(newOTLPExporter(server.URL+"/"), []attribute.KeyValue{attribute.String("service.name", "bytebase")})
+	tracer := provider.Tracer(instrumentationName)
+	ctx, parent := tracer.Start(context.Background(), "task.run", trace.WithAttributes(attribute.Int("task.id", 101)))
+	_, child := tracer.Start(ctx, "db.execute", trace.WithSpanKind(trace.SpanKindClient))
+	child.RecordError(errors.New("deadlock found"))
+	child.SetStatus(codes.Error, "deadlock found")
+	child.End()
+	parent.End()
+
+	// The spans of the unsampled remote parents aren't recorded.
+	remote := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: newTraceID(),
+		SpanID:  newSpanID(),
+		Remote:  true,
+	}))
+	_, unsampled := tracer.Start(remote, "bytebase.v1.SQLService/Query")
+	a.False(unsampled.IsRecording())
+	unsampled.End()
+
+	provider.Shutdown(context.Background())
+	a.Len(requests, 1)
+	a.Equal("service.name", requests[0].ResourceSpans[0].Resource.Attributes[0].Key)
+	spans := requests[0].ResourceSpans[0].ScopeSpans[0].Spans
+	a.Len(spans, 2)
+	a.Equal("db.execute", spans[0].Name)
+	a.Equal(int(trace.SpanKindClient), spans[0].Kind)
+	a.Equal(2, spans[0].Status.Code)
+	a.Equal("deadlock found", spans[0].Status.Message)
+	a.Equal("exception", spans[0].Events[0].Name)
+	a.Equal("task.run", spans[1].Name)
+	a.Equal(spans[1].TraceID, spans[0].TraceID)
+	a.Equal(spans[1].SpanID, spans[0].ParentSpanID)
+	a.Empty(spans[1].ParentSpanID)
+	a.Equal("101", *spans[1].Attributes[0].Value.IntValue)
+}
+
+func TestProviderPropagation(t *testing.T) {
+	a := require.New(t)
+	provider := NewProvider(newOTLPExporter("http://localhost:4318"), nil)
+	defer provider.Shutdown(context.Background())
+	propagator := propagation.TraceContext{}
+	traceID := newTraceID()
+	carrier := propagation.MapCarrier{"traceparent": "00-" + traceID.String() + "-00f067aa0ba902b7-01"}
+
+	ctx, span := provider.Tracer(instrumentationName).Start(propagator.Extract(context.Background(), carrier), "bytebase.v1.ReviewService/ApproveReview")
+	a.True(span.IsRecording())
+	a.Equal(traceID, span.SpanContext().TraceID())
+
+	outgoing := propagation.MapCarrier{}
+	propagator.Inject(ctx, outgoing)
+	a.Equal("00-"+traceID.String()+"-"+span.SpanContext().SpanID().String()+"-01", outgoing["traceparent"])
+}
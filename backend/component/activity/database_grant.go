@@ -0,0 +1,98 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// CreateDatabaseGrantActivity records the request or the status update of a database grant in the audit log, and
+// notifies the requester and the workspace Owners and DBAs in their inboxes, except for the updater.
+func (m *Manager) CreateDatabaseGrantActivity(ctx context.Context, grant *store.DatabaseGrantMessage, oldStatus api.DatabaseGrantStatus) error {
+	database, err := m.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &grant.DatabaseUID})
+	if err != nil {
+		return err
+	}
+	if database == nil {
+		return errors.Errorf("database %d not found", grant.DatabaseUID)
+	}
+	project, err := m.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &database.ProjectID})
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(api.ActivityDatabaseGrantPayload{
+		GrantID:      grant.UID,
+		DatabaseID:   database.UID,
+		DatabaseName: database.DatabaseName,
+		Level:        grant.Level,
+		OldStatus:    oldStatus,
+		NewStatus:    grant.Status,
+		ExpireTs:     grant.ExpireTs,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal activity payload")
+	}
+	create := &api.ActivityCreate{
+		CreatorID:   grant.UpdaterID,
+		ContainerID: project.UID,
+		Type:        api.ActivityDatabaseGrantStatusUpdate,
+		Level:       api.ActivityInfo,
+		Comment:     databaseGrantComment(grant, database.DatabaseName),
+		Payload:     string(payload),
+	}
+	if oldStatus == "" {
+		create.Type = api.ActivityDatabaseGrantRequest
+	}
+	if grant.Level == api.DatabaseGrantAdmin {
+		create.Level = api.ActivityWarn
+	}
+	activity, err := m.store.CreateActivity(ctx, create)
+	if err != nil {
+		return err
+	}
+
+	receivers := map[int]bool{grant.CreatorID: true}
+	for _, role := range []api.Role{api.Owner, api.DBA} {
+		role := role
+		users, err := m.store.ListUsers(ctx, &store.FindUserMessage{Role: &role})
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			receivers[user.ID] = true
+		}
+	}
+	delete(receivers, grant.UpdaterID)
+	delete(receivers, api.SystemBotID)
+	for receiverID := range receivers {
+		if _, err := m.store.CreateInbox(ctx, &api.InboxCreate{
+			ReceiverID: receiverID,
+			ActivityID: activity.ID,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to post activity to inbox: %d", receiverID)
+		}
+	}
+	return nil
+}
+
+func databaseGrantComment(grant *store.DatabaseGrantMessage, databaseName string) string {
+	switch grant.Status {
+	case api.DatabaseGrantPending:
+		return fmt.Sprintf("Requested %s access to database %q for %d seconds: %s", grant.Level, databaseName, grant.DurationSeconds, grant.Justification)
+	case api.DatabaseGrantActive:
+		return fmt.Sprintf("Granted %s access to database %q until %s", grant.Level, databaseName, time.Unix(grant.ExpireTs, 0).UTC().Format(time.RFC3339))
+	case api.DatabaseGrantRejected:
+		return fmt.Sprintf("Rejected %s access to database %q", grant.Level, databaseName)
+	case api.DatabaseGrantRevoked:
+		return fmt.Sprintf("Revoked %s access to database %q", grant.Level, databaseName)
+	case api.DatabaseGrantExpired:
+		return fmt.Sprintf("%s access to database %q expired", grant.Level, databaseName)
+	}
+	return ""
+}
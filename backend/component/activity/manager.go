@@ -30,6 +30,8 @@ type Manager struct {
 // Metadata is the activity metadata.
 type Metadata struct {
 	Issue *store.IssueMessage
+	// Project is the project of the activities without the issue, whose webhooks will be posted.
+	Project *store.ProjectMessage
 }
 
 // NewManager creates an activity manager.
@@ -126,6 +128,14 @@ func (m *Manager) CreateActivity(ctx context.Context, create *api.ActivityCreate
 	}
 
 	if meta.Issue == nil {
+		if meta.Project != nil {
+			if err := m.postProjectActivityWebhook(ctx, activity, meta.Project); err != nil {
+				log.Warn("Failed to post webhook event on project activity",
+					zap.String("project", meta.Project.ResourceID),
+					zap.String("activity type", string(activity.Type)),
+					zap.Error(err))
+			}
+		}
 		return activity, nil
 	}
 	postInbox, err := shouldPostInbox(activity, create.Type)
@@ -170,9 +180,70 @@ func (m *Manager) CreateActivity(ctx context.Context, create *api.ActivityCreate
 	return activity, nil
 }
 
+// postProjectActivityWebhook posts the webhooks of the project for the activity without the issue.
+func (m *Manager) postProjectActivityWebhook(ctx context.Context, activity *api.Activity, project *store.ProjectMessage) error {
+	webhookList, err := m.store.FindProjectWebhookV2(ctx, &store.FindProjectWebhookMessage{
+		ProjectID:    &project.UID,
+		ActivityType: &activity.Type,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find project webhook")
+	}
+	if len(webhookList) == 0 {
+		return nil
+	}
+
+	setting, err := m.store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get workspace setting")
+	}
+	creator, err := m.store.GetUserByID(ctx, activity.CreatorID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find creator")
+	}
+	if creator == nil {
+		return errors.Errorf("creator user not found for ID %v", activity.CreatorID)
+	}
+
+	level := webhook.WebhookInfo
+	title := ""
+	link := setting.ExternalUrl
+	switch activity.Type {
+	case api.ActivityDatabaseSchemaDrift:
+		payload := new(api.ActivityDatabaseSchemaDriftPayload)
+		if err := json.Unmarshal([]byte(activity.Payload), payload); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal payload")
+		}
+		level = webhook.WebhookWarn
+		title = fmt.Sprintf("Schema drift detected - %s", payload.DatabaseName)
+		link = fmt.Sprintf("%s/anomaly-center", setting.ExternalUrl)
+	default:
+		return errors.Errorf("unsupported project activity type %s", activity.Type)
+	}
+
+	webhookCtx := webhook.Context{
+		Level:        level,
+		ActivityType: string(activity.Type),
+		Title:        title,
+		Project: &webhook.Project{
+			ID:   project.UID,
+			Name: project.Title,
+		},
+		Description:  activity.Comment,
+		Link:         link,
+		CreatorID:    creator.ID,
+		CreatorName:  creator.Name,
+		CreatorEmail: creator.Email,
+	}
+	// Call external webhook endpoint in Go routine to avoid blocking the caller.
+	go postWebhookList(webhookCtx, webhookList)
+	return nil
+}
+
 func postWebhookList(webhookCtx webhook.Context, webhookList []*store.ProjectWebhookMessage) {
 	for _, hook := range webhookList {
 		webhookCtx.URL = hook.URL
+		webhookCtx.PayloadTemplate = webhook.GetPayloadTemplate(hook.PayloadTemplates, webhookCtx.ActivityType)
 		webhookCtx.CreatedTs = time.Now().Unix()
 		if err := common.Retry(func() error {
 			return webhook.Post(hook.Type, webhookCtx)
@@ -423,6 +494,14 @@ func (m *Manager) getWebhookContext(ctx context.Context, activity *api.Activity,
 		CreatorName:  updater.Name,
 		CreatorEmail: updater.Email,
 	}
+	if activity.Type == api.ActivityIssueCreate {
+		// The approval actions are handled by the Slack app, so they're only added if it's configured.
+		slackSetting, err := utils.GetSlackSetting(ctx, m.store)
+		if err != nil {
+			log.Warn("Failed to get Slack setting for the webhook approval actions", zap.Error(err))
+		}
+		webhookCtx.ApprovalActions = slackSetting != nil
+	}
 	return webhookCtx, nil
 }
 
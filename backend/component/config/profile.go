@@ -65,6 +65,8 @@ type Profile struct {
 	MetricConnectionKey string
 	// EnableMetric will enable the metric collector.
 	EnableMetric bool
+	// OTLPEndpoint is the OTLP/HTTP endpoint exporting the traces to, empty means no tracing.
+	OTLPEndpoint string
 
 	// Test only flag to skip generating onboarding data.
 	TestOnlySkipOnboardingData bool
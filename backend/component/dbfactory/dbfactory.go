@@ -3,8 +3,15 @@ package dbfactory
 
 import (
 	"context"
+	"encoding/base64"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
 
 	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/externalsecret"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/store"
@@ -18,22 +25,39 @@ type DBFactory struct {
 	mongoBinDir string
 	dataDir     string
 	secret      string
+	store       *store.Store
+	// externalSecretManager resolves the credentials of the data sources referencing the external secrets.
+	externalSecretManager *externalsecret.Manager
+	// readReplicaRouter routes the reads among the read-only data sources by their health.
+	readReplicaRouter *readReplicaRouter
+	// connectionPool limits and reuses the connections to the instances.
+	connectionPool *connectionPool
 }
 
 // New creates a new database driver factory.
-func New(mysqlBinDir, mongoBinDir, pgBinDir, dataDir, secret string) *DBFactory {
+func New(mysqlBinDir, mongoBinDir, pgBinDir, dataDir, secret string, store *store.Store, externalSecretManager *externalsecret.Manager) *DBFactory {
 	return &DBFactory{
-		mysqlBinDir: mysqlBinDir,
-		mongoBinDir: mongoBinDir,
-		pgBinDir:    pgBinDir,
-		dataDir:     dataDir,
-		secret:      secret,
+		mysqlBinDir:           mysqlBinDir,
+		mongoBinDir:           mongoBinDir,
+		pgBinDir:              pgBinDir,
+		dataDir:               dataDir,
+		secret:                secret,
+		store:                 store,
+		externalSecretManager: externalSecretManager,
+		readReplicaRouter:     newReadReplicaRouter(),
+		connectionPool:        newConnectionPool(),
 	}
 }
 
 // GetAdminDatabaseDriver gets the admin database driver using the instance's admin data source.
 // Upon successful return, caller must call driver.Close(). Otherwise, it will leak the database connection.
 func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *store.InstanceMessage, databaseName string) (db.Driver, error) {
+	return d.getAdminDatabaseDriver(ctx, instance, databaseName, false /* reusable */)
+}
+
+// getAdminDatabaseDriver gets the admin database driver, which is kept idle for reuse after closing if it's reusable.
+// The admin drivers used to change the databases are never reused, since they may change the session state.
+func (d *DBFactory) getAdminDatabaseDriver(ctx context.Context, instance *store.InstanceMessage, databaseName string, reusable bool) (db.Driver, error) {
 	adminDataSource := utils.DataSourceFromInstanceWithType(instance, api.Admin)
 	if adminDataSource == nil {
 		return nil, common.Errorf(common.Internal, "admin data source not found for instance %q", instance.Title)
@@ -57,6 +81,10 @@ func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *store.
 	if err != nil {
 		return nil, err
 	}
+	username, password, err := d.resolveExternalSecret(ctx, adminDataSource.ExternalSecretReference, adminDataSource.Username, password)
+	if err != nil {
+		return nil, err
+	}
 	sslCA, err := common.Unobfuscate(adminDataSource.ObfuscatedSslCa, d.secret)
 	if err != nil {
 		return nil, err
@@ -77,28 +105,58 @@ func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *store.
 	if err != nil {
 		return nil, err
 	}
+	awsSecretAccessKey, err := common.Unobfuscate(adminDataSource.AWSObfuscatedSecretAccessKey, d.secret)
+	if err != nil {
+		return nil, err
+	}
+	gcpCredentialsJSON, err := common.Unobfuscate(adminDataSource.GCPObfuscatedCredentialsJSON, d.secret)
+	if err != nil {
+		return nil, err
+	}
+	azureClientSecret, err := common.Unobfuscate(adminDataSource.AzureObfuscatedClientSecret, d.secret)
+	if err != nil {
+		return nil, err
+	}
+	kerberosKeytab, err := common.Unobfuscate(adminDataSource.KerberosObfuscatedKeytab, d.secret)
+	if err != nil {
+		return nil, err
+	}
+	kerberosConfig, err := GetKerberosConfig(adminDataSource.KerberosRealm, adminDataSource.KerberosKDC, adminDataSource.KerberosSPN, kerberosKeytab)
+	if err != nil {
+		return nil, err
+	}
+	jumpHostList, socks5Config, err := GetProxyConfig(adminDataSource.Proxy, d.secret)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := d.getDatabaseTLSConfig(ctx, instance, databaseName, db.TLSConfig{
+		SslCA:   sslCA,
+		SslCert: sslCert,
+		SslKey:  sslKey,
+	})
+	if err != nil {
+		return nil, err
+	}
 	sshConfig := db.SSHConfig{
-		Host:       adminDataSource.SSHHost,
-		Port:       adminDataSource.SSHPort,
-		User:       adminDataSource.SSHUser,
-		Password:   sshPassword,
-		PrivateKey: sshPrivateKey,
+		Host:         adminDataSource.SSHHost,
+		Port:         adminDataSource.SSHPort,
+		User:         adminDataSource.SSHUser,
+		Password:     sshPassword,
+		PrivateKey:   sshPrivateKey,
+		JumpHostList: jumpHostList,
 	}
-	driver, err := getDatabaseDriver(
+	driver, err := d.getDatabaseDriver(
 		ctx,
-		instance.Engine,
+		instance,
+		reusable,
 		db.DriverConfig{
 			DbBinDir:  dbBinDir,
 			BinlogDir: common.GetBinlogAbsDir(d.dataDir, instance.UID),
 		},
 		db.ConnectionConfig{
-			Username: adminDataSource.Username,
-			Password: password,
-			TLSConfig: db.TLSConfig{
-				SslCA:   sslCA,
-				SslCert: sslCert,
-				SslKey:  sslKey,
-			},
+			Username:               username,
+			Password:               password,
+			TLSConfig:              tlsConfig,
 			Host:                   adminDataSource.Host,
 			Port:                   adminDataSource.Port,
 			Database:               databaseName,
@@ -107,6 +165,24 @@ func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *store.
 			SID:                    adminDataSource.SID,
 			ServiceName:            adminDataSource.ServiceName,
 			SSHConfig:              sshConfig,
+			SOCKS5Config:           socks5Config,
+			AuthenticationType:     adminDataSource.AuthenticationType,
+			AWSCredential: db.AWSCredential{
+				Region:          adminDataSource.AWSRegion,
+				AccessKeyID:     adminDataSource.AWSAccessKeyID,
+				SecretAccessKey: awsSecretAccessKey,
+			},
+			GCPCloudSQLConfig: db.GCPCloudSQLConfig{
+				ConnectionName:  adminDataSource.GCPCloudSQLConnectionName,
+				UsePrivateIP:    adminDataSource.GCPCloudSQLUsePrivateIP,
+				CredentialsJSON: gcpCredentialsJSON,
+			},
+			AzureCredential: db.AzureCredential{
+				TenantID:     adminDataSource.AzureTenantID,
+				ClientID:     adminDataSource.AzureClientID,
+				ClientSecret: azureClientSecret,
+			},
+			KerberosConfig: kerberosConfig,
 		},
 		db.ConnectionContext{
 			EnvironmentID: instance.EnvironmentID,
@@ -120,20 +196,66 @@ func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *store.
 	return driver, nil
 }
 
-// GetReadOnlyDatabaseDriver gets the read-only database driver using the instance's read-only data source.
+// GetReadOnlyDatabaseDriver gets the read-only database driver using the instance's read-only data sources.
+// The read-only data source is picked by the read replica routing policy of the admin data source among the healthy ones.
 // If the read-only data source is not defined, we will fallback to admin data source.
 // Upon successful return, caller must call driver.Close(). Otherwise, it will leak the database connection.
 func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *store.InstanceMessage, databaseName string) (db.Driver, error) {
-	dataSource := utils.DataSourceFromInstanceWithType(instance, api.RO)
 	adminDataSource := utils.DataSourceFromInstanceWithType(instance, api.Admin)
+	var dataSources []*store.DataSourceMessage
+	for _, dataSource := range instance.DataSources {
+		if dataSource.Type == api.RO {
+			dataSources = append(dataSources, dataSource)
+		}
+	}
 	// If there are no read-only data source, fall back to admin data source.
-	if dataSource == nil {
-		dataSource = adminDataSource
+	if len(dataSources) == 0 {
+		if adminDataSource == nil {
+			return nil, common.Errorf(common.Internal, "data source not found for instance %q", instance.Title)
+		}
+		return d.getReadOnlyDataSourceDriver(ctx, instance, adminDataSource, adminDataSource, databaseName)
 	}
-	if dataSource == nil {
-		return nil, common.Errorf(common.Internal, "data source not found for instance %q", instance.Title)
+
+	policy, fallbackToPrimary := api.ReadReplicaRoutingUnspecified, false
+	if adminDataSource != nil {
+		policy, fallbackToPrimary = adminDataSource.ReadReplicaRoutingPolicy, adminDataSource.ReadReplicaFallbackToPrimary
+	}
+	candidates := d.readReplicaRouter.route(instance.UID, policy, dataSources)
+	if len(candidates) == 0 && !fallbackToPrimary {
+		// Retry the unhealthy read-only data sources rather than failing right away if the reads cannot fall back to the admin data source.
+		candidates = dataSources
+	}
+	var err error
+	for _, dataSource := range candidates {
+		var driver db.Driver
+		if driver, err = d.openReadReplica(ctx, instance, dataSource, adminDataSource, databaseName); err == nil {
+			return driver, nil
+		}
+		log.Warn("Failed to connect to the read-only data source",
+			zap.String("instance", instance.ResourceID),
+			zap.String("dataSource", dataSource.Title),
+			zap.Error(err))
+	}
+	if fallbackToPrimary {
+		return d.getReadOnlyDataSourceDriver(ctx, instance, adminDataSource, adminDataSource, databaseName)
+	}
+	return nil, err
+}
+
+// GetSchemaSyncDatabaseDriver gets the database driver to sync the database schema.
+// The schema is read from the read-only data sources if the read replica routing policy is specified, otherwise from the admin data source.
+// Upon successful return, caller must call driver.Close(). Otherwise, it will leak the database connection.
+func (d *DBFactory) GetSchemaSyncDatabaseDriver(ctx context.Context, instance *store.InstanceMessage, databaseName string) (db.Driver, error) {
+	adminDataSource := utils.DataSourceFromInstanceWithType(instance, api.Admin)
+	if adminDataSource == nil || adminDataSource.ReadReplicaRoutingPolicy == api.ReadReplicaRoutingUnspecified {
+		return d.getAdminDatabaseDriver(ctx, instance, databaseName, true /* reusable */)
 	}
+	return d.GetReadOnlyDatabaseDriver(ctx, instance, databaseName)
+}
 
+// getReadOnlyDataSourceDriver gets the read-only database driver using the given data source.
+// The host and port of the admin data source are used if the data source doesn't have its own.
+func (d *DBFactory) getReadOnlyDataSourceDriver(ctx context.Context, instance *store.InstanceMessage, dataSource, adminDataSource *store.DataSourceMessage, databaseName string) (db.Driver, error) {
 	host, port := dataSource.Host, dataSource.Port
 	if host == "" {
 		host = adminDataSource.Host
@@ -155,6 +277,10 @@ func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *sto
 	if err != nil {
 		return nil, err
 	}
+	username, password, err := d.resolveExternalSecret(ctx, dataSource.ExternalSecretReference, dataSource.Username, password)
+	if err != nil {
+		return nil, err
+	}
 	sslCa, err := common.Unobfuscate(dataSource.ObfuscatedSslCa, d.secret)
 	if err != nil {
 		return nil, err
@@ -175,37 +301,85 @@ func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *sto
 	if err != nil {
 		return nil, err
 	}
+	awsSecretAccessKey, err := common.Unobfuscate(dataSource.AWSObfuscatedSecretAccessKey, d.secret)
+	if err != nil {
+		return nil, err
+	}
+	gcpCredentialsJSON, err := common.Unobfuscate(dataSource.GCPObfuscatedCredentialsJSON, d.secret)
+	if err != nil {
+		return nil, err
+	}
+	azureClientSecret, err := common.Unobfuscate(dataSource.AzureObfuscatedClientSecret, d.secret)
+	if err != nil {
+		return nil, err
+	}
+	kerberosKeytab, err := common.Unobfuscate(dataSource.KerberosObfuscatedKeytab, d.secret)
+	if err != nil {
+		return nil, err
+	}
+	kerberosConfig, err := GetKerberosConfig(dataSource.KerberosRealm, dataSource.KerberosKDC, dataSource.KerberosSPN, kerberosKeytab)
+	if err != nil {
+		return nil, err
+	}
+	jumpHostList, socks5Config, err := GetProxyConfig(dataSource.Proxy, d.secret)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := d.getDatabaseTLSConfig(ctx, instance, databaseName, db.TLSConfig{
+		SslCA:   sslCa,
+		SslCert: sslCert,
+		SslKey:  sslKey,
+	})
+	if err != nil {
+		return nil, err
+	}
 	sshConfig := db.SSHConfig{
-		Host:       dataSource.SSHHost,
-		Port:       dataSource.SSHPort,
-		User:       dataSource.SSHUser,
-		Password:   sshPassword,
-		PrivateKey: sshPrivateKey,
+		Host:         dataSource.SSHHost,
+		Port:         dataSource.SSHPort,
+		User:         dataSource.SSHUser,
+		Password:     sshPassword,
+		PrivateKey:   sshPrivateKey,
+		JumpHostList: jumpHostList,
 	}
-	driver, err := getDatabaseDriver(
+	driver, err := d.getDatabaseDriver(
 		ctx,
-		instance.Engine,
+		instance,
+		true, /* reusable */
 		db.DriverConfig{
 			DbBinDir:  dbBinDir,
 			BinlogDir: common.GetBinlogAbsDir(d.dataDir, instance.UID),
 		},
 		db.ConnectionConfig{
-			Username: dataSource.Username,
-			Password: password,
-			Host:     host,
-			Port:     port,
-			Database: databaseName,
-			TLSConfig: db.TLSConfig{
-				SslCA:   sslCa,
-				SslCert: sslCert,
-				SslKey:  sslKey,
-			},
+			Username:               username,
+			Password:               password,
+			Host:                   host,
+			Port:                   port,
+			Database:               databaseName,
+			TLSConfig:              tlsConfig,
 			SRV:                    dataSource.SRV,
 			AuthenticationDatabase: dataSource.AuthenticationDatabase,
 			SID:                    dataSource.SID,
 			ServiceName:            dataSource.ServiceName,
 			SSHConfig:              sshConfig,
-			ReadOnly:               true,
+			SOCKS5Config:           socks5Config,
+			AuthenticationType:     dataSource.AuthenticationType,
+			AWSCredential: db.AWSCredential{
+				Region:          dataSource.AWSRegion,
+				AccessKeyID:     dataSource.AWSAccessKeyID,
+				SecretAccessKey: awsSecretAccessKey,
+			},
+			GCPCloudSQLConfig: db.GCPCloudSQLConfig{
+				ConnectionName:  dataSource.GCPCloudSQLConnectionName,
+				UsePrivateIP:    dataSource.GCPCloudSQLUsePrivateIP,
+				CredentialsJSON: gcpCredentialsJSON,
+			},
+			AzureCredential: db.AzureCredential{
+				TenantID:     dataSource.AzureTenantID,
+				ClientID:     dataSource.AzureClientID,
+				ClientSecret: azureClientSecret,
+			},
+			KerberosConfig: kerberosConfig,
+			ReadOnly:       true,
 		},
 		db.ConnectionContext{
 			EnvironmentID: instance.EnvironmentID,
@@ -219,17 +393,149 @@ func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *sto
 	return driver, nil
 }
 
+// getDatabaseTLSConfig overrides the TLS config of the data source with the database level TLS configuration.
+// The CA bundle and the client certificate are overridden separately, so that a database can only set its own client certificate.
+func (d *DBFactory) getDatabaseTLSConfig(ctx context.Context, instance *store.InstanceMessage, databaseName string, tlsConfig db.TLSConfig) (db.TLSConfig, error) {
+	if !common.FeatureFlag(common.FeatureFlagDatabaseTLS) || databaseName == "" {
+		return tlsConfig, nil
+	}
+	databaseTLS, err := d.store.GetDatabaseTLS(ctx, &store.FindDatabaseTLSMessage{
+		InstanceUID:  &instance.UID,
+		DatabaseName: &databaseName,
+	})
+	if err != nil {
+		return db.TLSConfig{}, err
+	}
+	if databaseTLS == nil {
+		return tlsConfig, nil
+	}
+	if databaseTLS.ObfuscatedSslCa != "" {
+		sslCA, err := common.Unobfuscate(databaseTLS.ObfuscatedSslCa, d.secret)
+		if err != nil {
+			return db.TLSConfig{}, err
+		}
+		tlsConfig.SslCA = sslCA
+	}
+	if databaseTLS.ObfuscatedSslCert != "" {
+		sslCert, err := common.Unobfuscate(databaseTLS.ObfuscatedSslCert, d.secret)
+		if err != nil {
+			return db.TLSConfig{}, err
+		}
+		sslKey, err := common.Unobfuscate(databaseTLS.ObfuscatedSslKey, d.secret)
+		if err != nil {
+			return db.TLSConfig{}, err
+		}
+		tlsConfig.SslCert, tlsConfig.SslKey = sslCert, sslKey
+	}
+	return tlsConfig, nil
+}
+
+// resolveExternalSecret returns the credential in the external secret manager if the data source references one,
+// otherwise the username and the password of the data source.
+func (d *DBFactory) resolveExternalSecret(ctx context.Context, reference, username, password string) (string, string, error) {
+	if reference == "" {
+		return username, password, nil
+	}
+	credential, err := d.externalSecretManager.GetCredential(ctx, reference)
+	if err != nil {
+		return "", "", common.Wrapf(err, common.DbConnectionFailure, "failed to get the credential from external secret %q", reference)
+	}
+	if credential.Username != "" {
+		username = credential.Username
+	}
+	return username, credential.Password, nil
+}
+
 // Retrieve db.Driver connection with standard parameters for all type data source.
-func getDatabaseDriver(ctx context.Context, engine db.Type, driverConfig db.DriverConfig, connectionConfig db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
-	driver, err := db.Open(
+// The connection is acquired from the pool of the instance, limited by the max connections of the admin data source.
+// The idle driver of the same connection is reused if the driver is reusable.
+func (d *DBFactory) getDatabaseDriver(ctx context.Context, instance *store.InstanceMessage, reusable bool, driverConfig db.DriverConfig, connectionConfig db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
+	maxConnections, idleTimeout := 0, defaultConnectionIdleTimeout
+	if adminDataSource := utils.DataSourceFromInstanceWithType(instance, api.Admin); adminDataSource != nil {
+		maxConnections = adminDataSource.MaxConnections
+		if adminDataSource.ConnectionIdleTimeoutSeconds > 0 {
+			idleTimeout = time.Duration(adminDataSource.ConnectionIdleTimeoutSeconds) * time.Second
+		}
+	}
+	key := ""
+	if reusable {
+		var err error
+		if key, err = getConnectionKey(instance.Engine, driverConfig, connectionConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	driver, err := d.connectionPool.acquire(ctx, instance.ResourceID, maxConnections, idleTimeout, key)
+	if err != nil {
+		return nil, common.Wrapf(err, common.DbConnectionFailure, "failed to acquire connection to instance %q", instance.Title)
+	}
+	if driver != nil {
+		if err := driver.Ping(ctx); err == nil {
+			return newTracedDriver(&pooledDriver{Driver: driver, pool: d.connectionPool, scope: getConnectionScope(ctx), instanceID: instance.ResourceID, key: key}, instance.Engine, instance.ResourceID, connectionConfig.Database), nil
+		}
+		// The idle connection is broken, open a new one in its place.
+		closeDrivers(ctx, []db.Driver{driver})
+	}
+	driver, err = db.Open(
 		ctx,
-		engine,
+		instance.Engine,
 		driverConfig,
 		connectionConfig,
 		connCtx,
 	)
 	if err != nil {
+		d.connectionPool.release(ctx, getConnectionScope(ctx), instance.ResourceID, key, nil)
 		return nil, common.Wrapf(err, common.DbConnectionFailure, "failed to connect database at %s:%s with user %q", connectionConfig.Host, connectionConfig.Port, connectionConfig.Username)
 	}
-	return driver, nil
+	return newTracedDriver(&pooledDriver{Driver: driver, pool: d.connectionPool, scope: getConnectionScope(ctx), instanceID: instance.ResourceID, key: key}, instance.Engine, instance.ResourceID, connectionConfig.Database), nil
+}
+
+// GetKerberosConfig decodes the base64-encoded keytab of the Kerberos authentication.
+func GetKerberosConfig(realm, kdc, spn, keytab string) (db.KerberosConfig, error) {
+	keytabBytes, err := base64.StdEncoding.DecodeString(keytab)
+	if err != nil {
+		return db.KerberosConfig{}, errors.Wrap(err, "failed to decode the Kerberos keytab")
+	}
+	return db.KerberosConfig{
+		Realm:  realm,
+		KDC:    kdc,
+		SPN:    spn,
+		Keytab: keytabBytes,
+	}, nil
+}
+
+// GetProxyConfig unobfuscates the proxy chain of the data source to the SSH jump hosts and the SOCKS5 proxy.
+func GetProxyConfig(proxy store.DataSourceProxy, secret string) ([]db.SSHConfig, db.SOCKS5Config, error) {
+	var jumpHostList []db.SSHConfig
+	for _, jumpHost := range proxy.SSHJumpHostList {
+		password, err := common.Unobfuscate(jumpHost.ObfuscatedPassword, secret)
+		if err != nil {
+			return nil, db.SOCKS5Config{}, err
+		}
+		privateKey, err := common.Unobfuscate(jumpHost.ObfuscatedPrivateKey, secret)
+		if err != nil {
+			return nil, db.SOCKS5Config{}, err
+		}
+		jumpHostList = append(jumpHostList, db.SSHConfig{
+			Host:       jumpHost.Host,
+			Port:       jumpHost.Port,
+			User:       jumpHost.User,
+			Password:   password,
+			PrivateKey: privateKey,
+		})
+	}
+	var socks5Config db.SOCKS5Config
+	if proxy.SOCKS5 != nil {
+		password, err := common.Unobfuscate(proxy.SOCKS5.ObfuscatedPassword, secret)
+		if err != nil {
+			return nil, db.SOCKS5Config{}, err
+		}
+		socks5Config = db.SOCKS5Config{
+			Host:     proxy.SOCKS5.Host,
+			Port:     proxy.SOCKS5.Port,
+			User:     proxy.SOCKS5.User,
+			Password: password,
+		}
+	}
+	return jumpHostList, socks5Config, nil
 }
@@ -0,0 +1,344 @@
+package dbfactory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+const (
+	// defaultConnectionIdleTimeout is the default time to keep a released driver idle for reuse.
+	defaultConnectionIdleTimeout = time.Minute
+	// connectionQueueTimeout is the time to wait in the queue if the instance has reached its max connections.
+	connectionQueueTimeout = time.Minute
+	// idleConnectionReapInterval is the interval to close the idle drivers exceeding the idle timeout.
+	idleConnectionReapInterval = 10 * time.Second
+)
+
+// ConnectionPoolStats is the metrics of the connection pool of an instance.
+type ConnectionPoolStats struct {
+	MaxConnections int
+	InUse          int
+	Idle           int
+	Waiting        int
+	// The cumulative counters since the server starts.
+	OpenCount    int64
+	ReuseCount   int64
+	WaitCount    int64
+	WaitDuration time.Duration
+	TimeoutCount int64
+}
+
+// idleDriver is a released driver kept for reuse by the acquirers with the same connection key.
+type idleDriver struct {
+	key       string
+	driver    db.Driver
+	idleSince time.Time
+}
+
+// instancePool is the connection pool of an instance.
+// Each driver, or all the drivers of a connection scope, counts as one connection no matter how many connections the
+// drivers open underneath.
+type instancePool struct {
+	maxConnections int
+	idleTimeout    time.Duration
+	inUse          int
+	// idleList is ordered by the time the drivers become idle.
+	idleList []*idleDriver
+	// waiters are the channels of the queued acquirers in order, the first one is closed to hand over a released connection.
+	waiters []chan struct{}
+	stats   ConnectionPoolStats
+}
+
+func (p *instancePool) isFull() bool {
+	return p.maxConnections > 0 && p.inUse+len(p.idleList) >= p.maxConnections
+}
+
+// takeIdle takes the most recently released idle driver with the connection key.
+func (p *instancePool) takeIdle(key string) db.Driver {
+	for i := len(p.idleList) - 1; i >= 0; i-- {
+		if p.idleList[i].key == key {
+			driver := p.idleList[i].driver
+			p.idleList = append(p.idleList[:i], p.idleList[i+1:]...)
+			return driver
+		}
+	}
+	return nil
+}
+
+// removeWaiter removes the waiter from the queue, and returns false if it has been handed over a connection.
+func (p *instancePool) removeWaiter(waiter chan struct{}) bool {
+	for i, w := range p.waiters {
+		if w == waiter {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// connectionScope is the scope in which the drivers of an instance share one connection, such as a task run.
+// The nested acquisitions of the scope never wait for the connection held by the scope itself.
+type connectionScope struct {
+	// held is the number of the drivers held by the scope keyed by the instance resource ID, guarded by the pool lock.
+	held map[string]int
+}
+
+type connectionScopeKey struct{}
+
+// WithConnectionScope returns the context in which the drivers acquired from the pool of an instance share one
+// connection, so that the callers opening another driver while holding one don't deadlock on the max connections.
+func WithConnectionScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, connectionScopeKey{}, &connectionScope{held: make(map[string]int)})
+}
+
+func getConnectionScope(ctx context.Context) *connectionScope {
+	scope, _ := ctx.Value(connectionScopeKey{}).(*connectionScope)
+	return scope
+}
+
+func (s *connectionScope) hold(instanceID string) {
+	if s != nil {
+		s.held[instanceID]++
+	}
+}
+
+// connectionPool pools the database drivers by the instances, so that the connections to an instance are limited and reused.
+type connectionPool struct {
+	sync.Mutex
+	// pools is keyed by the instance resource ID.
+	pools map[string]*instancePool
+}
+
+func newConnectionPool() *connectionPool {
+	return &connectionPool{
+		pools: make(map[string]*instancePool),
+	}
+}
+
+// acquire acquires a connection of the instance, waiting in the queue if the instance has reached its max connections.
+// The idle driver with the connection key is returned for reuse if there is one, otherwise the caller should open a new driver.
+// The idle drivers are never reused if the connection key is empty.
+// The connection already held by the scope of the context is shared instead of acquiring another one.
+func (p *connectionPool) acquire(ctx context.Context, instanceID string, maxConnections int, idleTimeout time.Duration, key string) (db.Driver, error) {
+	scope := getConnectionScope(ctx)
+	p.Lock()
+	pool, ok := p.pools[instanceID]
+	if !ok {
+		pool = &instancePool{}
+		p.pools[instanceID] = pool
+	}
+	pool.maxConnections, pool.idleTimeout = maxConnections, idleTimeout
+	if scope != nil && scope.held[instanceID] > 0 {
+		scope.hold(instanceID)
+		p.Unlock()
+		return nil, nil
+	}
+	if key != "" {
+		if driver := pool.takeIdle(key); driver != nil {
+			pool.inUse++
+			pool.stats.ReuseCount++
+			scope.hold(instanceID)
+			p.Unlock()
+			return driver, nil
+		}
+	}
+	// Evict the least recently released idle drivers of the other connections to make room.
+	var evicted []db.Driver
+	for pool.isFull() && len(pool.idleList) > 0 {
+		evicted = append(evicted, pool.idleList[0].driver)
+		pool.idleList = pool.idleList[1:]
+	}
+	if !pool.isFull() {
+		pool.inUse++
+		pool.stats.OpenCount++
+		scope.hold(instanceID)
+		p.Unlock()
+		closeDrivers(ctx, evicted)
+		return nil, nil
+	}
+	waiter := make(chan struct{})
+	pool.waiters = append(pool.waiters, waiter)
+	pool.stats.WaitCount++
+	p.Unlock()
+
+	start := time.Now()
+	timer := time.NewTimer(connectionQueueTimeout)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-waiter:
+	case <-timer.C:
+		err = errors.Errorf("timed out waiting for a connection in the queue, instance %q has reached the max connections %d", instanceID, maxConnections)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	pool.stats.WaitDuration += time.Since(start)
+	// The connection could be handed over right after the timeout, we take it anyway in this case.
+	if err != nil && pool.removeWaiter(waiter) {
+		pool.stats.TimeoutCount++
+		return nil, err
+	}
+	pool.stats.OpenCount++
+	scope.hold(instanceID)
+	return nil, nil
+}
+
+// release releases a connection of the instance.
+// The driver is kept idle for reuse if the connection key is not empty and there is no waiter, otherwise it's closed.
+// A nil driver releases the connection which failed to open.
+// The connection shared in the scope is only released by the last driver of the scope.
+func (p *connectionPool) release(ctx context.Context, scope *connectionScope, instanceID string, key string, driver db.Driver) {
+	p.Lock()
+	if scope != nil {
+		if scope.held[instanceID] > 1 {
+			scope.held[instanceID]--
+			p.Unlock()
+			if driver != nil {
+				closeDrivers(ctx, []db.Driver{driver})
+			}
+			return
+		}
+		delete(scope.held, instanceID)
+	}
+	pool := p.pools[instanceID]
+	toClose := driver
+	if len(pool.waiters) > 0 {
+		// Hand over the connection to the first waiter.
+		close(pool.waiters[0])
+		pool.waiters = pool.waiters[1:]
+	} else {
+		pool.inUse--
+		if key != "" && driver != nil {
+			pool.idleList = append(pool.idleList, &idleDriver{
+				key:       key,
+				driver:    driver,
+				idleSince: time.Now(),
+			})
+			toClose = nil
+		}
+	}
+	p.Unlock()
+
+	if toClose != nil {
+		closeDrivers(ctx, []db.Driver{toClose})
+	}
+}
+
+// reap closes the idle drivers exceeding the idle timeout, or all of them if force is set.
+func (p *connectionPool) reap(ctx context.Context, force bool) {
+	var expired []db.Driver
+	p.Lock()
+	for _, pool := range p.pools {
+		var idleList []*idleDriver
+		for _, idle := range pool.idleList {
+			if force || time.Since(idle.idleSince) >= pool.idleTimeout {
+				expired = append(expired, idle.driver)
+				continue
+			}
+			idleList = append(idleList, idle)
+		}
+		pool.idleList = idleList
+	}
+	p.Unlock()
+
+	closeDrivers(ctx, expired)
+}
+
+// getStats gets the metrics of the connection pool of the instance.
+func (p *connectionPool) getStats(instanceID string) ConnectionPoolStats {
+	p.Lock()
+	defer p.Unlock()
+
+	pool, ok := p.pools[instanceID]
+	if !ok {
+		return ConnectionPoolStats{}
+	}
+	stats := pool.stats
+	stats.MaxConnections = pool.maxConnections
+	stats.InUse = pool.inUse
+	stats.Idle = len(pool.idleList)
+	stats.Waiting = len(pool.waiters)
+	return stats
+}
+
+func closeDrivers(ctx context.Context, drivers []db.Driver) {
+	for _, driver := range drivers {
+		if err := driver.Close(ctx); err != nil {
+			log.Debug(fmt.Sprintf("Failed to close the pooled driver: %v", err))
+		}
+	}
+}
+
+// getConnectionKey gets the key of the connection to reuse the idle drivers.
+// The resolved credentials are part of the key so that the drivers are never reused after the credentials change.
+func getConnectionKey(engine db.Type, driverConfig db.DriverConfig, connectionConfig db.ConnectionConfig) (string, error) {
+	bytes, err := json.Marshal(struct {
+		Engine           db.Type
+		DriverConfig     db.DriverConfig
+		ConnectionConfig db.ConnectionConfig
+	}{engine, driverConfig, connectionConfig})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal connection config")
+	}
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// pooledDriver is the driver acquired from the connection pool, which is released to the pool on closing.
+type pooledDriver struct {
+	db.Driver
+	pool       *connectionPool
+	scope      *connectionScope
+	instanceID string
+	key        string
+	closeOnce  sync.Once
+}
+
+// Close releases the driver to the connection pool.
+func (d *pooledDriver) Close(ctx context.Context) error {
+	d.closeOnce.Do(func() {
+		d.pool.release(ctx, d.scope, d.instanceID, d.key, d.Driver)
+	})
+	return nil
+}
+
+// Unwrap returns the underlying driver.
+func (d *pooledDriver) Unwrap() db.Driver {
+	return d.Driver
+}
+
+// Run runs the reaper of the idle drivers, and closes all of them on exit.
+func (d *DBFactory) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(idleConnectionReapInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Idle database connection reaper started and will run every %v", idleConnectionReapInterval))
+	for {
+		select {
+		case <-ticker.C:
+			d.connectionPool.reap(ctx, false /* force */)
+		case <-ctx.Done():
+			// The context is canceled, use a new one to close the connections.
+			d.connectionPool.reap(context.Background(), true /* force */)
+			return
+		}
+	}
+}
+
+// GetConnectionPoolStats gets the connection pool metrics of the instance.
+func (d *DBFactory) GetConnectionPoolStats(instanceID string) ConnectionPoolStats {
+	return d.connectionPool.getStats(instanceID)
+}
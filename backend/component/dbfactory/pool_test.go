@@ -0,0 +1,142 @@
+package dbfactory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+type fakeDriver struct {
+	db.Driver
+	closed bool
+}
+
+func (d *fakeDriver) Close(_ context.Context) error {
+	d.closed = true
+	return nil
+}
+
+func TestConnectionPool(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pool := newConnectionPool()
+
+	// Open two connections up to the limit.
+	driver, err := pool.acquire(ctx, "prod", 2, time.Minute, "a")
+	a.NoError(err)
+	a.Nil(driver)
+	driver, err = pool.acquire(ctx, "prod", 2, time.Minute, "")
+	a.NoError(err)
+	a.Nil(driver)
+
+	// The reusable driver is kept idle after releasing, and reused by the same connection.
+	driverA := &fakeDriver{}
+	pool.release(ctx, nil, "prod", "a", driverA)
+	a.False(driverA.closed)
+	a.Equal(ConnectionPoolStats{MaxConnections: 2, InUse: 1, Idle: 1, OpenCount: 2}, pool.getStats("prod"))
+	driver, err = pool.acquire(ctx, "prod", 2, time.Minute, "a")
+	a.NoError(err)
+	a.Equal(driverA, driver)
+
+	// The acquirer waits in the queue until a connection is released.
+	acquired := make(chan error)
+	go func() {
+		_, err := pool.acquire(ctx, "prod", 2, time.Minute, "b")
+		acquired <- err
+	}()
+	a.Eventually(func() bool { return pool.getStats("prod").Waiting == 1 }, time.Second, time.Millisecond)
+	pool.release(ctx, nil, "prod", "a", driverA)
+	a.NoError(<-acquired)
+	// The released driver is closed rather than kept idle since the connection is handed over.
+	a.True(driverA.closed)
+	a.Equal(ConnectionPoolStats{MaxConnections: 2, InUse: 2, OpenCount: 3, ReuseCount: 1, WaitCount: 1}, withoutWaitDuration(pool.getStats("prod")))
+
+	// The acquirer gives up if the context is canceled while waiting.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		_, err := pool.acquire(canceledCtx, "prod", 2, time.Minute, "")
+		acquired <- err
+	}()
+	a.Eventually(func() bool { return pool.getStats("prod").Waiting == 1 }, time.Second, time.Millisecond)
+	cancel()
+	a.ErrorIs(<-acquired, context.Canceled)
+	a.Equal(0, pool.getStats("prod").Waiting)
+	a.Equal(int64(1), pool.getStats("prod").TimeoutCount)
+
+	// The idle driver of another connection is evicted to make room.
+	driverB := &fakeDriver{}
+	pool.release(ctx, nil, "prod", "b", driverB)
+	driver, err = pool.acquire(ctx, "prod", 2, time.Minute, "c")
+	a.NoError(err)
+	a.Nil(driver)
+	a.True(driverB.closed)
+
+	// The other instances are not limited by the pool of the instance.
+	driver, err = pool.acquire(ctx, "test", 2, time.Minute, "")
+	a.NoError(err)
+	a.Nil(driver)
+}
+
+func TestConnectionPoolReap(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pool := newConnectionPool()
+
+	_, err := pool.acquire(ctx, "prod", 0, time.Hour, "a")
+	a.NoError(err)
+	_, err = pool.acquire(ctx, "test", 0, 0, "a")
+	a.NoError(err)
+	driverProd, driverTest := &fakeDriver{}, &fakeDriver{}
+	pool.release(ctx, nil, "prod", "a", driverProd)
+	pool.release(ctx, nil, "test", "a", driverTest)
+
+	pool.reap(ctx, false /* force */)
+	a.False(driverProd.closed)
+	a.True(driverTest.closed)
+	pool.reap(ctx, true /* force */)
+	a.True(driverProd.closed)
+	a.Equal(0, pool.getStats("prod").Idle)
+}
+
+func TestConnectionPoolScope(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pool := newConnectionPool()
+	scopeCtx := WithConnectionScope(ctx)
+	scope := getConnectionScope(scopeCtx)
+
+	// The second driver acquired in the scope shares the only connection held by the first one instead of waiting.
+	_, err := pool.acquire(scopeCtx, "prod", 1, time.Minute, "a")
+	a.NoError(err)
+	driver, err := pool.acquire(scopeCtx, "prod", 1, time.Minute, "b")
+	a.NoError(err)
+	a.Nil(driver)
+	a.Equal(ConnectionPoolStats{MaxConnections: 1, InUse: 1, OpenCount: 1}, pool.getStats("prod"))
+
+	// The acquirers out of the scope still wait for the connection.
+	acquired := make(chan error)
+	go func() {
+		_, err := pool.acquire(ctx, "prod", 1, time.Minute, "")
+		acquired <- err
+	}()
+	a.Eventually(func() bool { return pool.getStats("prod").Waiting == 1 }, time.Second, time.Millisecond)
+
+	// The connection is handed over after the last driver of the scope is released.
+	driverA, driverB := &fakeDriver{}, &fakeDriver{}
+	pool.release(ctx, scope, "prod", "a", driverA)
+	a.True(driverA.closed)
+	a.Equal(1, pool.getStats("prod").Waiting)
+	pool.release(ctx, scope, "prod", "b", driverB)
+	a.NoError(<-acquired)
+	a.True(driverB.closed)
+	a.Equal(ConnectionPoolStats{MaxConnections: 1, InUse: 1, OpenCount: 2, WaitCount: 1}, withoutWaitDuration(pool.getStats("prod")))
+}
+
+func withoutWaitDuration(stats ConnectionPoolStats) ConnectionPoolStats {
+	stats.WaitDuration = 0
+	return stats
+}
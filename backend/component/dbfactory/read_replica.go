@@ -0,0 +1,141 @@
+package dbfactory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+// unhealthyReadReplicaRetryInterval is the interval before routing the reads to an unhealthy read-only data source again.
+const unhealthyReadReplicaRetryInterval = 30 * time.Second
+
+// ReadReplicaHealth is the health of a read-only data source, which is checked whenever connecting to it.
+type ReadReplicaHealth struct {
+	Healthy bool
+	// Latency is the time to connect to and ping the read-only data source.
+	Latency   time.Duration
+	CheckedTs int64
+	Error     string
+}
+
+// readReplicaRouter routes the reads among the read-only data sources of the instances.
+type readReplicaRouter struct {
+	sync.Mutex
+	// healthByUID is the latest health of the read-only data sources by their UIDs.
+	healthByUID map[int]*ReadReplicaHealth
+	// nextByInstanceUID is the offset of the next read-only data source to route for the round-robin policy by the instance UIDs.
+	nextByInstanceUID map[int]int
+}
+
+func newReadReplicaRouter() *readReplicaRouter {
+	return &readReplicaRouter{
+		healthByUID:       make(map[int]*ReadReplicaHealth),
+		nextByInstanceUID: make(map[int]int),
+	}
+}
+
+// route returns the read-only data sources in the order to try by the routing policy.
+// The read-only data sources which have been unhealthy within the retry interval are skipped.
+func (r *readReplicaRouter) route(instanceUID int, policy api.ReadReplicaRoutingPolicy, dataSources []*store.DataSourceMessage) []*store.DataSourceMessage {
+	r.Lock()
+	defer r.Unlock()
+
+	var candidates []*store.DataSourceMessage
+	for _, dataSource := range dataSources {
+		if health, ok := r.healthByUID[dataSource.UID]; ok && !health.Healthy && time.Since(time.Unix(health.CheckedTs, 0)) < unhealthyReadReplicaRetryInterval {
+			continue
+		}
+		candidates = append(candidates, dataSource)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	switch policy {
+	case api.ReadReplicaRoutingRoundRobin:
+		next := r.nextByInstanceUID[instanceUID] % len(candidates)
+		r.nextByInstanceUID[instanceUID] = next + 1
+		rotated := append([]*store.DataSourceMessage{}, candidates[next:]...)
+		candidates = append(rotated, candidates[:next]...)
+	case api.ReadReplicaRoutingNearest:
+		// The read-only data sources which haven't been checked come first so that their latencies get measured.
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return r.getLatency(candidates[i].UID) < r.getLatency(candidates[j].UID)
+		})
+	}
+	return candidates
+}
+
+func (r *readReplicaRouter) getLatency(dataSourceUID int) time.Duration {
+	if health, ok := r.healthByUID[dataSourceUID]; ok {
+		return health.Latency
+	}
+	return 0
+}
+
+// record records the health of the read-only data source by the result of connecting to it.
+func (r *readReplicaRouter) record(dataSourceUID int, latency time.Duration, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	health := &ReadReplicaHealth{
+		Healthy:   err == nil,
+		Latency:   latency,
+		CheckedTs: time.Now().Unix(),
+	}
+	if err != nil {
+		health.Error = err.Error()
+	}
+	r.healthByUID[dataSourceUID] = health
+}
+
+func (r *readReplicaRouter) getHealth(dataSourceUID int) *ReadReplicaHealth {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.healthByUID[dataSourceUID]
+}
+
+// openReadReplica opens the driver of the read-only data source and records its health by the result.
+func (d *DBFactory) openReadReplica(ctx context.Context, instance *store.InstanceMessage, dataSource, adminDataSource *store.DataSourceMessage, databaseName string) (db.Driver, error) {
+	start := time.Now()
+	driver, err := d.getReadOnlyDataSourceDriver(ctx, instance, dataSource, adminDataSource, databaseName)
+	if err == nil {
+		if err = driver.Ping(ctx); err != nil {
+			driver.Close(ctx)
+		}
+	}
+	// Don't blame the read-only data source if the request has been canceled.
+	if ctx.Err() == nil {
+		d.readReplicaRouter.record(dataSource.UID, time.Since(start), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+// CheckReadReplicaHealth checks the health of the read-only data sources of the instance by connecting to them.
+// It returns the health by the data source UIDs.
+func (d *DBFactory) CheckReadReplicaHealth(ctx context.Context, instance *store.InstanceMessage) map[int]*ReadReplicaHealth {
+	adminDataSource := utils.DataSourceFromInstanceWithType(instance, api.Admin)
+	healthByUID := make(map[int]*ReadReplicaHealth)
+	for _, dataSource := range instance.DataSources {
+		if dataSource.Type != api.RO {
+			continue
+		}
+		if driver, err := d.openReadReplica(ctx, instance, dataSource, adminDataSource, ""); err == nil {
+			driver.Close(ctx)
+		}
+		if health := d.readReplicaRouter.getHealth(dataSource.UID); health != nil {
+			healthByUID[dataSource.UID] = health
+		}
+	}
+	return healthByUID
+}
@@ -0,0 +1,53 @@
+package dbfactory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func getRoutedUIDs(dataSources []*store.DataSourceMessage) []int {
+	var uids []int
+	for _, dataSource := range dataSources {
+		uids = append(uids, dataSource.UID)
+	}
+	return uids
+}
+
+func TestReadReplicaRouterRoute(t *testing.T) {
+	a := require.New(t)
+	dataSources := []*store.DataSourceMessage{{UID: 1}, {UID: 2}, {UID: 3}}
+
+	router := newReadReplicaRouter()
+	a.Equal([]int{1, 2, 3}, getRoutedUIDs(router.route(101, api.ReadReplicaRoutingUnspecified, dataSources)))
+	a.Equal([]int{1, 2, 3}, getRoutedUIDs(router.route(101, api.ReadReplicaRoutingRoundRobin, dataSources)))
+	a.Equal([]int{2, 3, 1}, getRoutedUIDs(router.route(101, api.ReadReplicaRoutingRoundRobin, dataSources)))
+	a.Equal([]int{3, 1, 2}, getRoutedUIDs(router.route(101, api.ReadReplicaRoutingRoundRobin, dataSources)))
+	a.Equal([]int{1, 2, 3}, getRoutedUIDs(router.route(101, api.ReadReplicaRoutingRoundRobin, dataSources)))
+	// The round-robin offsets are kept per instance.
+	a.Equal([]int{1, 2, 3}, getRoutedUIDs(router.route(102, api.ReadReplicaRoutingRoundRobin, dataSources)))
+
+	router = newReadReplicaRouter()
+	router.record(1, 30*time.Millisecond, nil)
+	router.record(2, 10*time.Millisecond, nil)
+	// The unchecked read-only data source comes first.
+	a.Equal([]int{3, 2, 1}, getRoutedUIDs(router.route(101, api.ReadReplicaRoutingNearest, dataSources)))
+	router.record(3, 20*time.Millisecond, nil)
+	a.Equal([]int{2, 3, 1}, getRoutedUIDs(router.route(101, api.ReadReplicaRoutingNearest, dataSources)))
+
+	// The unhealthy read-only data sources are skipped.
+	router.record(2, time.Millisecond, errors.New("connection refused"))
+	a.Equal([]int{3, 1}, getRoutedUIDs(router.route(101, api.ReadReplicaRoutingNearest, dataSources)))
+	a.Equal([]int{1, 3}, getRoutedUIDs(router.route(101, api.ReadReplicaRoutingUnspecified, dataSources)))
+	router.record(1, time.Millisecond, errors.New("connection refused"))
+	router.record(3, time.Millisecond, errors.New("connection refused"))
+	a.Empty(router.route(101, api.ReadReplicaRoutingUnspecified, dataSources))
+	// The unhealthy read-only data sources are retried after the retry interval.
+	router.healthByUID[2].CheckedTs = time.Now().Add(-unhealthyReadReplicaRetryInterval).Unix()
+	a.Equal([]int{2}, getRoutedUIDs(router.route(101, api.ReadReplicaRoutingUnspecified, dataSources)))
+}
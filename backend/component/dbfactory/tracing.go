@@ -0,0 +1,93 @@
+package dbfactory
+
+import (
+	"context"
+	"database/sql"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/bytebase/bytebase/backend/common/tracing"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// tracedDriver is the driver creating a client span for each of the calls to the database.
+type tracedDriver struct {
+	db.Driver
+	attributes []attribute.KeyValue
+}
+
+func newTracedDriver(driver db.Driver, engine db.Type, instanceID, databaseName string) *tracedDriver {
+	return &tracedDriver{
+		Driver: driver,
+		attributes: []attribute.KeyValue{
+			attribute.String("db.system", string(engine)),
+			attribute.String("db.name", databaseName),
+			attribute.String("bytebase.instance", instanceID),
+		},
+	}
+}
+
+func (d *tracedDriver) start(ctx context.Context, operation string) (context.Context, trace.Span) {
+	attributes := append([]attribute.KeyValue{attribute.String("db.operation", operation)}, d.attributes...)
+	return tracing.StartWithKind(ctx, "db."+operation, trace.SpanKindClient, attributes...)
+}
+
+// Ping pings the database.
+func (d *tracedDriver) Ping(ctx context.Context) (err error) {
+	ctx, span := d.start(ctx, "ping")
+	defer func() { tracing.End(span, err) }()
+	return d.Driver.Ping(ctx)
+}
+
+// Execute executes the statement.
+func (d *tracedDriver) Execute(ctx context.Context, statement string, createDatabase bool) (_ int64, err error) {
+	ctx, span := d.start(ctx, "execute")
+	span.SetAttributes(attribute.Int("db.statement.length", len(statement)))
+	defer func() { tracing.End(span, err) }()
+	return d.Driver.Execute(ctx, statement, createDatabase)
+}
+
+// QueryConn queries the statement on the connection.
+func (d *tracedDriver) QueryConn(ctx context.Context, conn *sql.Conn, statement string, queryContext *db.QueryContext) (_ []any, err error) {
+	ctx, span := d.start(ctx, "query")
+	span.SetAttributes(attribute.Int("db.statement.length", len(statement)))
+	defer func() { tracing.End(span, err) }()
+	return d.Driver.QueryConn(ctx, conn, statement, queryContext)
+}
+
+// SyncInstance syncs the instance metadata.
+func (d *tracedDriver) SyncInstance(ctx context.Context) (_ *db.InstanceMetadata, err error) {
+	ctx, span := d.start(ctx, "sync_instance")
+	defer func() { tracing.End(span, err) }()
+	return d.Driver.SyncInstance(ctx)
+}
+
+// SyncDBSchema syncs the database schema.
+func (d *tracedDriver) SyncDBSchema(ctx context.Context) (_ *storepb.DatabaseMetadata, err error) {
+	ctx, span := d.start(ctx, "sync_schema")
+	defer func() { tracing.End(span, err) }()
+	return d.Driver.SyncDBSchema(ctx)
+}
+
+// Dump dumps the database.
+func (d *tracedDriver) Dump(ctx context.Context, out io.Writer, schemaOnly bool) (_ string, err error) {
+	ctx, span := d.start(ctx, "dump")
+	span.SetAttributes(attribute.Bool("db.dump.schema_only", schemaOnly))
+	defer func() { tracing.End(span, err) }()
+	return d.Driver.Dump(ctx, out, schemaOnly)
+}
+
+// Restore restores the database.
+func (d *tracedDriver) Restore(ctx context.Context, src io.Reader) (err error) {
+	ctx, span := d.start(ctx, "restore")
+	defer func() { tracing.End(span, err) }()
+	return d.Driver.Restore(ctx, src)
+}
+
+// Unwrap returns the underlying driver.
+func (d *tracedDriver) Unwrap() db.Driver {
+	return d.Driver
+}
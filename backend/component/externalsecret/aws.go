@@ -0,0 +1,34 @@
+package externalsecret
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/pkg/errors"
+)
+
+// getAWSSecret gets the current version of the secret from AWS Secrets Manager, in the region of the ARN.
+func getAWSSecret(ctx context.Context, arn string) (*secret, error) {
+	// The ARN is in the form of arn:aws:secretsmanager:<region>:<account-id>:secret:<name>.
+	parts := strings.Split(arn, ":")
+	if len(parts) < 7 || parts[0] != "arn" || parts[2] != "secretsmanager" {
+		return nil, errors.Errorf("invalid AWS Secrets Manager secret ARN %q", arn)
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(parts[3]))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load AWS config")
+	}
+	output, err := secretsmanager.NewFromConfig(cfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(arn),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get AWS secret %q", arn)
+	}
+	if output.SecretString == nil {
+		return nil, errors.Errorf("AWS secret %q is not stored as a string", arn)
+	}
+	return &secret{data: parseSecretString(*output.SecretString)}, nil
+}
@@ -0,0 +1,228 @@
+// Package externalsecret resolves the data source credentials stored in the external secret managers,
+// so that rotating the credentials doesn't require editing the instances.
+package externalsecret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+)
+
+const (
+	// VaultScheme is the reference scheme for HashiCorp Vault, such as vault://secret/data/mysql#password.
+	VaultScheme = "vault"
+	// AWSSecretsManagerScheme is the reference scheme for AWS Secrets Manager, such as awssm://arn:aws:secretsmanager:us-east-1:123456789012:secret:mysql#password.
+	AWSSecretsManagerScheme = "awssm"
+
+	defaultPasswordKey = "password"
+	usernameKey        = "username"
+
+	// refreshInterval is how long the secrets without a lease are cached before being fetched again to pick up the rotations.
+	refreshInterval = 5 * time.Minute
+	// refreshCheckInterval is the interval the runner checks the secrets to renew or to refresh.
+	refreshCheckInterval = time.Minute
+)
+
+// Reference is the parsed reference to a secret in the external secret manager.
+type Reference struct {
+	Scheme string
+	// Path is the API path of the secret for Vault, or the ARN of the secret for AWS Secrets Manager.
+	Path string
+	// Key is the key of the password in the secret.
+	Key string
+}
+
+// ParseReference parses the reference in the form of "<scheme>://<path>#<key>".
+// The key defaults to "password", and it's ignored for the AWS secret stored as plain text.
+func ParseReference(reference string) (*Reference, error) {
+	scheme, rest, ok := strings.Cut(reference, "://")
+	if !ok {
+		return nil, errors.Errorf("invalid external secret reference %q, expect <scheme>://<path>#<key>", reference)
+	}
+	if scheme != VaultScheme && scheme != AWSSecretsManagerScheme {
+		return nil, errors.Errorf("unsupported external secret scheme %q, expect %q or %q", scheme, VaultScheme, AWSSecretsManagerScheme)
+	}
+	path, key, _ := strings.Cut(rest, "#")
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil, errors.Errorf("empty path in external secret reference %q", reference)
+	}
+	if key == "" {
+		key = defaultPasswordKey
+	}
+	return &Reference{Scheme: scheme, Path: path, Key: key}, nil
+}
+
+// Credential is the credential resolved from the external secret.
+type Credential struct {
+	// Username is empty if the secret doesn't have the username key.
+	Username string
+	Password string
+}
+
+// secret is the secret fetched from the external secret manager.
+type secret struct {
+	data map[string]any
+	// The lease is only set for the Vault secrets, such as the dynamic database credentials.
+	leaseID       string
+	leaseDuration time.Duration
+	renewable     bool
+}
+
+type cachedSecret struct {
+	credential *Credential
+	leaseID    string
+	renewable  bool
+	// expireAt is the time the lease expires, zero if the secret doesn't have a lease.
+	expireAt  time.Time
+	refreshAt time.Time
+}
+
+// Manager resolves and caches the external secrets, and renews the Vault leases before they expire.
+type Manager struct {
+	vault *vaultClient
+
+	mu    sync.Mutex
+	cache map[string]*cachedSecret
+}
+
+// NewManager creates the external secret manager.
+// Vault is configured by the standard VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE environment variables,
+// and AWS Secrets Manager uses the default AWS credential chain.
+func NewManager() *Manager {
+	return &Manager{
+		vault: newVaultClientFromEnv(),
+		cache: make(map[string]*cachedSecret),
+	}
+}
+
+// GetCredential returns the credential of the reference, which is served from the cache until it's due to refresh.
+func (m *Manager) GetCredential(ctx context.Context, reference string) (*Credential, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cached, ok := m.cache[reference]; ok && time.Now().Before(cached.refreshAt) {
+		return cached.credential, nil
+	}
+	cached, err := m.refreshLocked(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	return cached.credential, nil
+}
+
+// Run periodically renews the leases and refreshes the cached secrets, so that the Vault dynamic credentials
+// are not revoked while they're in use.
+func (m *Manager) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(refreshCheckInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("External secret manager started and will run every %v", refreshCheckInterval))
+	for {
+		select {
+		case <-ticker.C:
+			m.refreshDue(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Manager) refreshDue(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for reference, cached := range m.cache {
+		if now.Before(cached.refreshAt) {
+			continue
+		}
+		if _, err := m.refreshLocked(ctx, reference); err != nil {
+			log.Warn("Failed to refresh external secret", zap.String("reference", reference), zap.Error(err))
+		}
+	}
+}
+
+// refreshLocked renews the lease of the cached secret if it's renewable and not expired yet, otherwise fetches the secret again.
+// The caller must hold the lock.
+func (m *Manager) refreshLocked(ctx context.Context, reference string) (*cachedSecret, error) {
+	now := time.Now()
+	if cached, ok := m.cache[reference]; ok && cached.renewable && now.Before(cached.expireAt) {
+		leaseDuration, err := m.vault.renewLease(ctx, cached.leaseID)
+		if err == nil {
+			cached.expireAt = now.Add(leaseDuration)
+			cached.refreshAt = getRefreshAt(now, leaseDuration)
+			return cached, nil
+		}
+		log.Warn("Failed to renew Vault lease, fetching the secret again", zap.String("reference", reference), zap.Error(err))
+	}
+
+	ref, err := ParseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	var s *secret
+	switch ref.Scheme {
+	case VaultScheme:
+		s, err = m.vault.read(ctx, ref.Path)
+	case AWSSecretsManagerScheme:
+		s, err = getAWSSecret(ctx, ref.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	credential, err := getCredential(ref, s.data)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := &cachedSecret{
+		credential: credential,
+		leaseID:    s.leaseID,
+		renewable:  s.renewable && s.leaseID != "",
+		refreshAt:  now.Add(refreshInterval),
+	}
+	if s.leaseDuration > 0 {
+		cached.expireAt = now.Add(s.leaseDuration)
+		cached.refreshAt = getRefreshAt(now, s.leaseDuration)
+	}
+	m.cache[reference] = cached
+	return cached, nil
+}
+
+// getRefreshAt returns the time to renew the lease, which is halfway through the lease and no later than the refresh interval.
+func getRefreshAt(now time.Time, leaseDuration time.Duration) time.Time {
+	if leaseDuration/2 < refreshInterval {
+		return now.Add(leaseDuration / 2)
+	}
+	return now.Add(refreshInterval)
+}
+
+func getCredential(ref *Reference, data map[string]any) (*Credential, error) {
+	password, ok := data[ref.Key].(string)
+	if !ok {
+		return nil, errors.Errorf("key %q not found in external secret %q", ref.Key, ref.Path)
+	}
+	credential := &Credential{Password: password}
+	if username, ok := data[usernameKey].(string); ok {
+		credential.Username = username
+	}
+	return credential, nil
+}
+
+// parseSecretString parses the secret stored as a JSON object, or as plain text for the password only.
+func parseSecretString(secretString string) map[string]any {
+	data := make(map[string]any)
+	if err := json.Unmarshal([]byte(secretString), &data); err != nil {
+		return map[string]any{defaultPasswordKey: secretString}
+	}
+	return data
+}
@@ -0,0 +1,137 @@
+package externalsecret
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		reference string
+		want      *Reference
+		wantErr   string
+	}{
+		{
+			reference: "vault://secret/data/mysql#root_password",
+			want:      &Reference{Scheme: VaultScheme, Path: "secret/data/mysql", Key: "root_password"},
+		},
+		{
+			reference: "vault:///database/creds/readonly/",
+			want:      &Reference{Scheme: VaultScheme, Path: "database/creds/readonly", Key: "password"},
+		},
+		{
+			reference: "awssm://arn:aws:secretsmanager:us-east-1:123456789012:secret:mysql-AbCdEf",
+			want:      &Reference{Scheme: AWSSecretsManagerScheme, Path: "arn:aws:secretsmanager:us-east-1:123456789012:secret:mysql-AbCdEf", Key: "password"},
+		},
+		{
+			reference: "secret/data/mysql",
+			wantErr:   "invalid external secret reference",
+		},
+		{
+			reference: "gcpsm://projects/p/secrets/s",
+			wantErr:   "unsupported external secret scheme",
+		},
+		{
+			reference: "vault://#password",
+			wantErr:   "empty path",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseReference(test.reference)
+		if test.wantErr != "" {
+			require.ErrorContains(t, err, test.wantErr, test.reference)
+			continue
+		}
+		require.NoError(t, err, test.reference)
+		require.Equal(t, test.want, got, test.reference)
+	}
+}
+
+func TestParseSecretString(t *testing.T) {
+	a := require.New(t)
+	a.Equal(map[string]any{"username": "admin", "password": "pass"}, parseSecretString(`{"username":"admin","password":"pass"}`))
+	a.Equal(map[string]any{"password": "plain"}, parseSecretString("plain"))
+}
+
+func TestVaultCredential(t *testing.T) {
+	a := require.New(t)
+
+	var renewCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]any{"errors": []string{"permission denied"}})
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/mysql":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{
+					"data":     map[string]any{"password": "kv"},
+					"metadata": map[string]any{"version": 1},
+				},
+			})
+		case "/v1/database/creds/readonly":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"lease_id":       "database/creds/readonly/lease",
+				"lease_duration": 60,
+				"renewable":      true,
+				"data":           map[string]any{"username": "v-token-readonly", "password": "dynamic"},
+			})
+		case "/v1/sys/leases/renew":
+			renewCount++
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"lease_id":       "database/creds/readonly/lease",
+				"lease_duration": 60,
+				"renewable":      true,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"errors": []string{}})
+		}
+	}))
+	defer server.Close()
+
+	m := &Manager{
+		vault: &vaultClient{addr: server.URL, token: "token", client: server.Client()},
+		cache: make(map[string]*cachedSecret),
+	}
+	ctx := context.Background()
+
+	credential, err := m.GetCredential(ctx, "vault://secret/data/mysql")
+	a.NoError(err)
+	a.Equal(&Credential{Password: "kv"}, credential)
+
+	_, err = m.GetCredential(ctx, "vault://secret/data/mysql#missing")
+	a.ErrorContains(err, `key "missing" not found`)
+
+	_, err = m.GetCredential(ctx, "vault://secret/data/unknown")
+	a.ErrorContains(err, "status code 404")
+
+	reference := "vault://database/creds/readonly"
+	credential, err = m.GetCredential(ctx, reference)
+	a.NoError(err)
+	a.Equal(&Credential{Username: "v-token-readonly", Password: "dynamic"}, credential)
+	a.Equal(0, renewCount)
+
+	// The lease is renewed instead of fetching the new credential once it's due to refresh.
+	m.cache[reference].refreshAt = time.Now().Add(-time.Second)
+	m.refreshDue(ctx)
+	a.Equal(1, renewCount)
+	a.True(m.cache[reference].refreshAt.After(time.Now()))
+	credential, err = m.GetCredential(ctx, reference)
+	a.NoError(err)
+	a.Equal("dynamic", credential.Password)
+
+	m.vault.token = "invalid"
+	m.cache[reference].refreshAt = time.Now().Add(-time.Second)
+	_, err = m.GetCredential(ctx, reference)
+	a.ErrorContains(err, "permission denied")
+}
@@ -0,0 +1,113 @@
+package externalsecret
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const vaultTimeout = 10 * time.Second
+
+// vaultClient is the client of the HashiCorp Vault HTTP API.
+type vaultClient struct {
+	addr      string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+type vaultResponse struct {
+	LeaseID       string          `json:"lease_id"`
+	LeaseDuration int64           `json:"lease_duration"`
+	Renewable     bool            `json:"renewable"`
+	Data          json.RawMessage `json:"data"`
+	Errors        []string        `json:"errors"`
+}
+
+func newVaultClientFromEnv() *vaultClient {
+	return &vaultClient{
+		addr:      strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/"),
+		token:     os.Getenv("VAULT_TOKEN"),
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		client:    &http.Client{Timeout: vaultTimeout},
+	}
+}
+
+// read reads the secret at the path, the data of the KV version 2 secrets is unwrapped.
+func (c *vaultClient) read(ctx context.Context, path string) (*secret, error) {
+	resp, err := c.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]any)
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal Vault secret %q", path)
+	}
+	// The KV version 2 secret engine nests the secret in the data field along with the metadata.
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	return &secret{
+		data:          data,
+		leaseID:       resp.LeaseID,
+		leaseDuration: time.Duration(resp.LeaseDuration) * time.Second,
+		renewable:     resp.Renewable,
+	}, nil
+}
+
+// renewLease renews the lease and returns the new lease duration.
+func (c *vaultClient) renewLease(ctx context.Context, leaseID string) (time.Duration, error) {
+	body, err := json.Marshal(map[string]string{"lease_id": leaseID})
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.do(ctx, http.MethodPut, "sys/leases/renew", body)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(resp.LeaseDuration) * time.Second, nil
+}
+
+func (c *vaultClient) do(ctx context.Context, method, path string, body []byte) (*vaultResponse, error) {
+	if c.addr == "" {
+		return nil, errors.New("VAULT_ADDR is not set")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v1/%s", c.addr, path), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to request Vault %q", path)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read Vault response for %q", path)
+	}
+	vaultResp := &vaultResponse{}
+	if err := json.Unmarshal(respBody, vaultResp); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal Vault response for %q, status code %d", path, resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("failed to request Vault %q, status code %d: %s", path, resp.StatusCode, strings.Join(vaultResp.Errors, "; "))
+	}
+	return vaultResp, nil
+}
@@ -0,0 +1,295 @@
+// Package querycursor materializes the query results of the SQL editor into the temporary files, so that the users
+// can page through the large results without holding them in the server memory.
+package querycursor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
+)
+
+const (
+	// MaxRowCount is the maximum number of the rows materialized for a cursor, the rest of the rows are dropped.
+	MaxRowCount = 10000000
+	// maxFileSize is the maximum size of the materialized rows of a cursor.
+	maxFileSize = 2 * 1024 * 1024 * 1024
+	// maxCursorCountPerPrincipal is the maximum number of the open cursors of a user, the least recently used
+	// cursor is closed when a user opens more.
+	maxCursorCountPerPrincipal = 5
+	// indexInterval is the number of the rows between the indexed offsets in the file.
+	indexInterval = 1000
+	// idleTimeout is the time the cursor is kept after it's last fetched.
+	idleTimeout         = 30 * time.Minute
+	expireCheckInterval = time.Minute
+)
+
+// Cursor is a materialized query result.
+type Cursor struct {
+	ID              string
+	PrincipalID     int
+	ColumnNames     []string
+	ColumnTypeNames []string
+	SensitiveList   []bool
+	RowCount        int
+	// Truncated is true if the rows exceeding the row count or the file size limit are dropped.
+	Truncated bool
+
+	maxRowCount int
+	path        string
+	// offsets are the file offsets of every indexInterval-th row.
+	offsets    []int64
+	lastUsedTs time.Time
+}
+
+// Materialize runs the query, and calls onRow for each row of the result. The onRow returns util.ErrSkipRemainingRows
+// when the cursor is full. It returns the column names, the column type names and the sensitive flags.
+type Materialize func(onRow func(row []any) error) ([]string, []string, []bool, error)
+
+// Manager manages the open cursors.
+type Manager struct {
+	dir string
+
+	mu      sync.Mutex
+	cursors map[string]*Cursor
+}
+
+// NewManager creates the cursor manager, the files are stored under the temporary directory in the data directory.
+func NewManager(dataDir string) *Manager {
+	dir := filepath.Join(dataDir, "tmp", "query-cursor")
+	// The cursors don't survive the restarts.
+	if err := os.RemoveAll(dir); err != nil {
+		log.Warn("Failed to remove the query cursor directory", zap.String("dir", dir), zap.Error(err))
+	}
+	return &Manager{
+		dir:     dir,
+		cursors: make(map[string]*Cursor),
+	}
+}
+
+// Create materializes the query result into a new cursor, with at most maxRowCount rows. Zero or the count beyond the
+// MaxRowCount means the MaxRowCount.
+func (m *Manager) Create(principalID int, maxRowCount int, materialize Materialize) (*Cursor, error) {
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create the query cursor directory")
+	}
+	cursor := &Cursor{
+		ID:          uuid.NewString(),
+		PrincipalID: principalID,
+		maxRowCount: maxRowCount,
+	}
+	if cursor.maxRowCount <= 0 || cursor.maxRowCount > MaxRowCount {
+		cursor.maxRowCount = MaxRowCount
+	}
+	cursor.path = filepath.Join(m.dir, cursor.ID)
+	file, err := os.Create(cursor.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the query cursor file")
+	}
+	if err := cursor.materialize(file, materialize); err != nil {
+		file.Close()
+		os.Remove(cursor.path)
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(cursor.path)
+		return nil, errors.Wrap(err, "failed to write the query cursor file")
+	}
+	cursor.lastUsedTs = time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cursors[cursor.ID] = cursor
+	m.evictLocked(principalID)
+	return cursor, nil
+}
+
+func (c *Cursor) materialize(w io.Writer, materialize Materialize) error {
+	writer := bufio.NewWriter(w)
+	var size int64
+	columnNames, columnTypeNames, sensitiveList, err := materialize(func(row []any) error {
+		if c.RowCount >= c.maxRowCount || size >= maxFileSize {
+			c.Truncated = true
+			return util.ErrSkipRemainingRows
+		}
+		line, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		if c.RowCount%indexInterval == 0 {
+			c.offsets = append(c.offsets, size)
+		}
+		line = append(line, '\n')
+		if _, err := writer.Write(line); err != nil {
+			return err
+		}
+		size += int64(len(line))
+		c.RowCount++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.ColumnNames = columnNames
+	c.ColumnTypeNames = columnTypeNames
+	c.SensitiveList = sensitiveList
+	return writer.Flush()
+}
+
+// evictLocked closes the least recently used cursors of the user beyond the limit.
+func (m *Manager) evictLocked(principalID int) {
+	var list []*Cursor
+	for _, cursor := range m.cursors {
+		if cursor.PrincipalID == principalID {
+			list = append(list, cursor)
+		}
+	}
+	if len(list) <= maxCursorCountPerPrincipal {
+		return
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].lastUsedTs.Before(list[j].lastUsedTs)
+	})
+	for _, cursor := range list[:len(list)-maxCursorCountPerPrincipal] {
+		m.closeLocked(cursor)
+	}
+}
+
+// Fetch returns the rows in [offset, offset+limit) of the cursor as the JSON arrays.
+func (m *Manager) Fetch(id string, principalID int, offset, limit int) (*Cursor, []json.RawMessage, error) {
+	m.mu.Lock()
+	cursor, ok := m.cursors[id]
+	if ok && cursor.PrincipalID == principalID {
+		cursor.lastUsedTs = time.Now()
+	}
+	m.mu.Unlock()
+	if !ok || cursor.PrincipalID != principalID {
+		return nil, nil, errors.Errorf("query cursor %q not found", id)
+	}
+	if offset < 0 || limit < 0 {
+		return nil, nil, errors.Errorf("invalid offset %d and limit %d", offset, limit)
+	}
+	if offset >= cursor.RowCount || limit == 0 {
+		return cursor, []json.RawMessage{}, nil
+	}
+
+	file, err := os.Open(cursor.path)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to open query cursor %q", id)
+	}
+	defer file.Close()
+	if _, err := file.Seek(cursor.offsets[offset/indexInterval], io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+	reader := bufio.NewReader(file)
+	rows := []json.RawMessage{}
+	for i := offset / indexInterval * indexInterval; i < offset+limit && i < cursor.RowCount; i++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read row %d of query cursor %q", i, id)
+		}
+		if i >= offset {
+			rows = append(rows, json.RawMessage(bytes.TrimSuffix(line, []byte("\n"))))
+		}
+	}
+	return cursor, rows, nil
+}
+
+// Scan calls onRow for each row of the cursor in order, the numbers are decoded as json.Number to keep the precision.
+// The onRow returns util.ErrSkipRemainingRows to stop the scan.
+func (m *Manager) Scan(id string, principalID int, onRow func(row []any) error) (*Cursor, error) {
+	m.mu.Lock()
+	cursor, ok := m.cursors[id]
+	if ok && cursor.PrincipalID == principalID {
+		cursor.lastUsedTs = time.Now()
+	}
+	m.mu.Unlock()
+	if !ok || cursor.PrincipalID != principalID {
+		return nil, errors.Errorf("query cursor %q not found", id)
+	}
+
+	file, err := os.Open(cursor.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open query cursor %q", id)
+	}
+	defer file.Close()
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	decoder.UseNumber()
+	for i := 0; i < cursor.RowCount; i++ {
+		var row []any
+		if err := decoder.Decode(&row); err != nil {
+			return nil, errors.Wrapf(err, "failed to read row %d of query cursor %q", i, id)
+		}
+		if err := onRow(row); err != nil {
+			if err == util.ErrSkipRemainingRows {
+				break
+			}
+			return nil, err
+		}
+	}
+	return cursor, nil
+}
+
+// Close closes the cursor and removes its file.
+func (m *Manager) Close(id string, principalID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cursor, ok := m.cursors[id]
+	if !ok || cursor.PrincipalID != principalID {
+		return errors.Errorf("query cursor %q not found", id)
+	}
+	m.closeLocked(cursor)
+	return nil
+}
+
+func (m *Manager) closeLocked(cursor *Cursor) {
+	delete(m.cursors, cursor.ID)
+	if err := os.Remove(cursor.path); err != nil && !os.IsNotExist(err) {
+		log.Warn("Failed to remove the query cursor file", zap.String("path", cursor.path), zap.Error(err))
+	}
+}
+
+// Run closes the idle cursors periodically.
+func (m *Manager) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(expireCheckInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Query cursor manager started and will run every %v", expireCheckInterval))
+	for {
+		select {
+		case <-ticker.C:
+			m.expire(time.Now())
+		case <-ctx.Done():
+			m.mu.Lock()
+			for _, cursor := range m.cursors {
+				m.closeLocked(cursor)
+			}
+			m.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (m *Manager) expire(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, cursor := range m.cursors {
+		if now.Sub(cursor.lastUsedTs) > idleTimeout {
+			m.closeLocked(cursor)
+		}
+	}
+}
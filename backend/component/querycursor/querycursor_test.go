@@ -0,0 +1,89 @@
+package querycursor
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	m := NewManager(t.TempDir())
+	rowCount := 2500
+	cursor, err := m.Create(1, 0, func(onRow func(row []any) error) ([]string, []string, []bool, error) {
+		for i := 0; i < rowCount; i++ {
+			if err := onRow([]any{i, "name"}); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		return []string{"id", "name"}, []string{"INT", "TEXT"}, []bool{false, false}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, rowCount, cursor.RowCount)
+	require.False(t, cursor.Truncated)
+	require.Equal(t, []string{"id", "name"}, cursor.ColumnNames)
+
+	// The page crosses the indexed offsets.
+	_, rows, err := m.Fetch(cursor.ID, 1, 999, 3)
+	require.NoError(t, err)
+	require.Equal(t, []json.RawMessage{
+		json.RawMessage(`[999,"name"]`),
+		json.RawMessage(`[1000,"name"]`),
+		json.RawMessage(`[1001,"name"]`),
+	}, rows)
+	_, rows, err = m.Fetch(cursor.ID, 1, 2498, 100)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	_, rows, err = m.Fetch(cursor.ID, 1, 3000, 100)
+	require.NoError(t, err)
+	require.Empty(t, rows)
+
+	var sum int64
+	_, err = m.Scan(cursor.ID, 1, func(row []any) error {
+		v, err := row[0].(json.Number).Int64()
+		sum += v
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(rowCount*(rowCount-1)/2), sum)
+
+	// The cursor is only visible to its creator.
+	_, _, err = m.Fetch(cursor.ID, 2, 0, 10)
+	require.Error(t, err)
+	_, err = m.Scan(cursor.ID, 2, func([]any) error { return nil })
+	require.Error(t, err)
+	require.Error(t, m.Close(cursor.ID, 2))
+
+	require.NoError(t, m.Close(cursor.ID, 1))
+	_, err = os.Stat(cursor.path)
+	require.True(t, os.IsNotExist(err))
+	_, _, err = m.Fetch(cursor.ID, 1, 0, 10)
+	require.Error(t, err)
+}
+
+func TestCursorEviction(t *testing.T) {
+	m := NewManager(t.TempDir())
+	materialize := func(onRow func(row []any) error) ([]string, []string, []bool, error) {
+		if err := onRow([]any{1}); err != nil {
+			return nil, nil, nil, err
+		}
+		return []string{"id"}, []string{"INT"}, []bool{false}, nil
+	}
+	var ids []string
+	for i := 0; i < maxCursorCountPerPrincipal+1; i++ {
+		cursor, err := m.Create(1, 0, materialize)
+		require.NoError(t, err)
+		ids = append(ids, cursor.ID)
+		time.Sleep(time.Millisecond)
+	}
+	// The least recently used cursor is closed.
+	_, _, err := m.Fetch(ids[0], 1, 0, 1)
+	require.Error(t, err)
+	_, _, err = m.Fetch(ids[1], 1, 0, 1)
+	require.NoError(t, err)
+
+	m.expire(time.Now().Add(idleTimeout + time.Minute))
+	require.Empty(t, m.cursors)
+}
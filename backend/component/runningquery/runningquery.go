@@ -0,0 +1,225 @@
+// Package runningquery tracks the running queries of the SQL editor, so that the users can cancel them and the queries
+// abandoned by the disconnected clients are killed on the database servers instead of running to completion.
+package runningquery
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+const (
+	// reapInterval is the interval the queries of the disconnected or timed out clients are checked.
+	reapInterval = time.Second
+	// killTimeout is the timeout of killing the query on the database server.
+	killTimeout = 10 * time.Second
+)
+
+// Query is a running query.
+type Query struct {
+	ID          string
+	PrincipalID int
+	InstanceUID int
+	StartedTs   time.Time
+
+	engine db.Type
+	// db is the connection pool of the query, the kill statement is issued on another connection of the pool.
+	db *sql.DB
+	// backendID is the connection ID of MySQL or the backend PID of PostgreSQL, zero if the engine doesn't support
+	// killing the query.
+	backendID int64
+	// clientCtx is the context of the request, which is canceled when the client disconnects or times out.
+	clientCtx context.Context
+	cancel    context.CancelFunc
+
+	mu       sync.Mutex
+	finished bool
+	killed   bool
+}
+
+// Manager manages the running queries.
+type Manager struct {
+	mu      sync.Mutex
+	queries map[string]*Query
+}
+
+// NewManager creates the running query manager.
+func NewManager() *Manager {
+	return &Manager{
+		queries: make(map[string]*Query),
+	}
+}
+
+// Start registers the query to run on the conn, a nil conn means the engine doesn't use the database/sql connections.
+// The ID is given by the client so that it can cancel the query before the response, a random ID is used if empty.
+// It returns the context to run the query with, which is detached from the cancellation of the client context. The
+// query is killed on the database server first when the client context is done, before the context is canceled,
+// since the drivers just drop the connection and leave the query running. The returned finish must be called after
+// the query, before the connection is closed.
+func (m *Manager) Start(clientCtx context.Context, id string, principalID int, instanceUID int, engine db.Type, sqlDB *sql.DB, conn *sql.Conn) (context.Context, func(), error) {
+	if id == "" {
+		id = uuid.NewString()
+	}
+	query := &Query{
+		ID:          id,
+		PrincipalID: principalID,
+		InstanceUID: instanceUID,
+		StartedTs:   time.Now(),
+		engine:      engine,
+		db:          sqlDB,
+		clientCtx:   clientCtx,
+	}
+	if conn != nil && sqlDB != nil {
+		backendID, err := getBackendID(clientCtx, engine, conn)
+		if err != nil {
+			return nil, nil, err
+		}
+		query.backendID = backendID
+	}
+	ctx, cancel := context.WithCancel(detachedContext{parent: clientCtx})
+	query.cancel = cancel
+
+	m.mu.Lock()
+	if _, ok := m.queries[id]; ok {
+		m.mu.Unlock()
+		cancel()
+		return nil, nil, errors.Errorf("query %q is already running", id)
+	}
+	m.queries[id] = query
+	m.mu.Unlock()
+
+	finish := func() {
+		m.mu.Lock()
+		delete(m.queries, id)
+		m.mu.Unlock()
+		// Wait for the kill in progress, so that it isn't issued after the connection returns to the pool.
+		query.mu.Lock()
+		query.finished = true
+		query.mu.Unlock()
+		cancel()
+	}
+	return ctx, finish, nil
+}
+
+// Cancel kills the running query of the principal.
+func (m *Manager) Cancel(id string, principalID int) error {
+	m.mu.Lock()
+	query, ok := m.queries[id]
+	m.mu.Unlock()
+	if !ok || query.PrincipalID != principalID {
+		return errors.Errorf("running query %q not found", id)
+	}
+	query.kill()
+	return nil
+}
+
+// Run kills the queries of the disconnected or timed out clients periodically.
+func (m *Manager) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(reapInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Running query reaper started and will run every %v", reapInterval))
+	for {
+		select {
+		case <-ticker.C:
+			m.reap()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Manager) reap() {
+	var orphans []*Query
+	m.mu.Lock()
+	for _, query := range m.queries {
+		if query.clientCtx.Err() != nil {
+			orphans = append(orphans, query)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, query := range orphans {
+		log.Debug("Killing the orphan query", zap.String("id", query.ID), zap.Int("instance", query.InstanceUID), zap.Error(query.clientCtx.Err()))
+		query.kill()
+	}
+}
+
+// kill kills the query on the database server if supported, and cancels its context.
+func (q *Query) kill() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.finished || q.killed {
+		return
+	}
+	q.killed = true
+	if q.backendID != 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), killTimeout)
+		defer cancel()
+		if err := killBackendQuery(ctx, q.engine, q.db, q.backendID); err != nil {
+			log.Warn("Failed to kill the query on the database server", zap.String("id", q.ID), zap.Int("instance", q.InstanceUID), zap.Error(err))
+		}
+	}
+	q.cancel()
+}
+
+// getBackendID returns the ID of the connection on the database server, zero if killing the query isn't supported.
+func getBackendID(ctx context.Context, engine db.Type, conn *sql.Conn) (int64, error) {
+	var statement string
+	switch engine {
+	case db.MySQL, db.MariaDB, db.OceanBase:
+		statement = "SELECT CONNECTION_ID()"
+	case db.Postgres, db.Redshift:
+		statement = "SELECT pg_backend_pid()"
+	default:
+		return 0, nil
+	}
+	var backendID int64
+	if err := conn.QueryRowContext(ctx, statement).Scan(&backendID); err != nil {
+		return 0, errors.Wrapf(err, "failed to get the connection ID")
+	}
+	return backendID, nil
+}
+
+func killBackendQuery(ctx context.Context, engine db.Type, sqlDB *sql.DB, backendID int64) error {
+	switch engine {
+	case db.MySQL, db.MariaDB, db.OceanBase:
+		// KILL QUERY terminates the statement and keeps the connection.
+		_, err := sqlDB.ExecContext(ctx, fmt.Sprintf("KILL QUERY %d", backendID))
+		return err
+	case db.Postgres, db.Redshift:
+		_, err := sqlDB.ExecContext(ctx, "SELECT pg_cancel_backend($1)", backendID)
+		return err
+	}
+	return nil
+}
+
+// detachedContext carries the values of the parent but not its cancellation and deadline.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key any) any {
+	return c.parent.Value(key)
+}
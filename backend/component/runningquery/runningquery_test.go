@@ -0,0 +1,51 @@
+package runningquery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestCancel(t *testing.T) {
+	m := NewManager()
+	ctx, finish, err := m.Start(context.Background(), "q1", 1, 1, db.MongoDB, nil, nil)
+	require.NoError(t, err)
+	defer finish()
+
+	_, _, err = m.Start(context.Background(), "q1", 1, 1, db.MongoDB, nil, nil)
+	require.Error(t, err)
+	// Only the principal running the query can cancel it.
+	require.Error(t, m.Cancel("q1", 2))
+	require.NoError(t, ctx.Err())
+	require.NoError(t, m.Cancel("q1", 1))
+	require.Error(t, ctx.Err())
+}
+
+func TestReap(t *testing.T) {
+	m := NewManager()
+	clientCtx, cancelClient := context.WithCancel(context.Background())
+	ctx, finish, err := m.Start(clientCtx, "", 1, 1, db.MongoDB, nil, nil)
+	require.NoError(t, err)
+	defer finish()
+
+	// The query isn't canceled with the client until it's reaped.
+	cancelClient()
+	require.NoError(t, ctx.Err())
+	m.reap()
+	require.Error(t, ctx.Err())
+}
+
+func TestFinish(t *testing.T) {
+	m := NewManager()
+	_, finish, err := m.Start(context.Background(), "q1", 1, 1, db.MongoDB, nil, nil)
+	require.NoError(t, err)
+	finish()
+	require.Error(t, m.Cancel("q1", 1))
+	// The ID can be reused after the query finishes.
+	_, finish, err = m.Start(context.Background(), "q1", 1, 1, db.MongoDB, nil, nil)
+	require.NoError(t, err)
+	finish()
+}
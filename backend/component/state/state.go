@@ -29,6 +29,8 @@ type State struct {
 	TaskProgress sync.Map // map[taskID]api.Progress
 	// GhostTaskState is the map from task ID to gh-ost state.
 	GhostTaskState sync.Map // map[taskID]sharedGhostState
+	// ChunkedDMLPaused is the set of the paused chunked DML tasks.
+	ChunkedDMLPaused sync.Map // map[taskID]bool
 
 	// RunningBackupDatabases is the set of databases running backups.
 	RunningBackupDatabases sync.Map // map[databaseID]bool
@@ -0,0 +1,52 @@
+package api
+
+// ColumnAccessSource is how the principal is allowed to query the database.
+type ColumnAccessSource string
+
+const (
+	// ColumnAccessSourceWorkspaceRole is the access of the workspace Owners and DBAs.
+	ColumnAccessSourceWorkspaceRole ColumnAccessSource = "WORKSPACE_ROLE"
+	// ColumnAccessSourceDatabaseGrant is the access by an active temporary database grant.
+	ColumnAccessSourceDatabaseGrant ColumnAccessSource = "DATABASE_GRANT"
+	// ColumnAccessSourceProjectRole is the access by the project IAM bindings.
+	ColumnAccessSourceProjectRole ColumnAccessSource = "PROJECT_ROLE"
+)
+
+// ColumnAccessMasking is how the values of the column are returned to the principal.
+type ColumnAccessMasking string
+
+const (
+	// ColumnAccessMasked means the values are always masked.
+	ColumnAccessMasked ColumnAccessMasking = "MASKED"
+	// ColumnAccessUnmasked means the values are never masked, because the column isn't in the sensitive data policy
+	// or the mask condition excepts the principal.
+	ColumnAccessUnmasked ColumnAccessMasking = "UNMASKED"
+	// ColumnAccessConditional means whether the values are masked depends on the rows.
+	ColumnAccessConditional ColumnAccessMasking = "CONDITIONAL"
+)
+
+// SensitiveColumnAccess is the API message for a principal who can currently query a sensitive column, which is
+// either in the sensitive data policy, found with PII or classified by the DLP policy.
+type SensitiveColumnAccess struct {
+	DatabaseID   int    `json:"databaseId"`
+	DatabaseName string `json:"databaseName"`
+	// Schema is empty for the engines without schema, such as MySQL.
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	// ClassificationList is the classifications of the PII findings not dismissed.
+	ClassificationList []PIIClassification `json:"classificationList"`
+	// Level is the classification level in the DLP policy.
+	Level int `json:"level"`
+	// MaskType is empty if the column isn't in the sensitive data policy.
+	MaskType      SensitiveDataMaskType `json:"maskType"`
+	MaskCondition string                `json:"maskCondition"`
+
+	PrincipalID    int                 `json:"principalId"`
+	PrincipalEmail string              `json:"principalEmail"`
+	PrincipalRole  Role                `json:"principalRole"`
+	Source         ColumnAccessSource  `json:"source"`
+	Masking        ColumnAccessMasking `json:"masking"`
+	// Exportable is true if the clearance of the principal isn't below the level.
+	Exportable bool `json:"exportable"`
+}
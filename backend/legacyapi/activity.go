@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/plugin/vcs"
 )
 
@@ -60,11 +61,20 @@ const (
 
 	// ActivitySQLEditorQuery is the type for executing query.
 	ActivitySQLEditorQuery ActivityType = "bb.sql-editor.query"
+	// ActivitySQLEditorExport is the type for exporting the query result.
+	ActivitySQLEditorExport ActivityType = "bb.sql-editor.export"
 
 	// Database related.
 
 	// ActivityDatabaseRecoveryPITRDone is the type for performing PITR on the database successfully.
 	ActivityDatabaseRecoveryPITRDone ActivityType = "bb.database.recovery.pitr.done"
+	// ActivityDatabaseSchemaDrift is the type for detecting the out-of-band schema changes on the database.
+	ActivityDatabaseSchemaDrift ActivityType = "bb.database.schema.drift"
+	// ActivityDatabaseGrantRequest is the type for requesting the temporary access to the database.
+	ActivityDatabaseGrantRequest ActivityType = "bb.database.grant.request"
+	// ActivityDatabaseGrantStatusUpdate is the type for approving, rejecting, revoking and expiring the temporary access
+	// to the database.
+	ActivityDatabaseGrantStatusUpdate ActivityType = "bb.database.grant.status.update"
 )
 
 // ActivityLevel is the level of activities.
@@ -226,6 +236,41 @@ type ActivityProjectDatabaseTransferPayload struct {
 	DatabaseName string `json:"databaseName,omitempty"`
 }
 
+// ActivityDatabaseSchemaDriftPayload is the API message payloads for detecting the schema drift.
+type ActivityDatabaseSchemaDriftPayload struct {
+	DatabaseID int `json:"databaseId"`
+	// Used by activity table to display info without paying the join cost
+	DatabaseName string `json:"databaseName"`
+	// The schema version corresponds to the expected schema
+	Version string               `json:"version"`
+	Objects []*SchemaDriftObject `json:"objects"`
+}
+
+// ActivityDatabaseGrantPayload is the API message payloads for the temporary access to the database.
+type ActivityDatabaseGrantPayload struct {
+	GrantID    int `json:"grantId"`
+	DatabaseID int `json:"databaseId"`
+	// Used by activity table to display info without paying the join cost
+	DatabaseName string              `json:"databaseName"`
+	Level        DatabaseGrantLevel  `json:"level"`
+	OldStatus    DatabaseGrantStatus `json:"oldStatus,omitempty"`
+	NewStatus    DatabaseGrantStatus `json:"newStatus"`
+	ExpireTs     int64               `json:"expireTs"`
+}
+
+// ActivitySQLEditorExportPayload is the API message payloads for the exported query result.
+type ActivitySQLEditorExportPayload struct {
+	// ExportID is the ID in the watermark of the exported file.
+	ExportID       string   `json:"exportId"`
+	InstanceID     int      `json:"instanceId"`
+	DatabaseID     int      `json:"databaseId"`
+	Format         string   `json:"format"`
+	RowCount       int      `json:"rowCount"`
+	ColumnNameList []string `json:"columnNameList"`
+	// ExportRequestID is the approved export request used for the export, it's 0 if the approval isn't required.
+	ExportRequestID int `json:"exportRequestId,omitempty"`
+}
+
 // ActivitySQLEditorQueryPayload is the API message payloads for the executed query info.
 type ActivitySQLEditorQueryPayload struct {
 	// Used by activity table to display info without paying the join cost
@@ -238,6 +283,10 @@ type ActivitySQLEditorQueryPayload struct {
 	DatabaseName           string           `json:"databaseName"`
 	Error                  string           `json:"error"`
 	AdviceList             []advisor.Advice `json:"adviceList"`
+	// SessionVariableList is the session variables set for the statement.
+	SessionVariableList []db.SessionVariable `json:"sessionVariableList,omitempty"`
+	// AdminSessionID is the admin session recording the transcript of the statement in the admin mode.
+	AdminSessionID int `json:"adminSessionId,omitempty"`
 }
 
 // Activity is the API message for an activity.
@@ -298,6 +347,8 @@ type ActivityFind struct {
 	CreatedTsBefore *int64
 	// If specified, only find activities whose ID is smaller than SinceID.
 	SinceID *int
+	// If specified, only find activities whose ID is larger than AfterID.
+	AfterID *int
 	// If specified, sorts the returned list by created_ts in <<ORDER>>
 	// Different use cases want different orders.
 	// e.g. Issue activity list wants ASC, while view recent activity list wants DESC.
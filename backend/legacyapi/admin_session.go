@@ -0,0 +1,60 @@
+package api
+
+// MaxAdminSessionEntryContentSize is the maximum size of the content of an admin session entry, the rest is dropped.
+const MaxAdminSessionEntryContentSize = 1024 * 1024
+
+// AdminSessionEntryKind is the kind of an admin session entry.
+type AdminSessionEntryKind string
+
+const (
+	// AdminSessionEntryCommand is the statement executed, which is recorded before the execution.
+	AdminSessionEntryCommand AdminSessionEntryKind = "COMMAND"
+	// AdminSessionEntryResponse is the results of the statement in JSON.
+	AdminSessionEntryResponse AdminSessionEntryKind = "RESPONSE"
+)
+
+// AdminSession is the API message for an admin mode session of the SQL editor.
+type AdminSession struct {
+	ID int `json:"id"`
+
+	// Standard fields
+	CreatorID int   `json:"creatorId"`
+	CreatedTs int64 `json:"createdTs"`
+
+	// Related fields
+	InstanceID   int    `json:"instanceId"`
+	DatabaseName string `json:"databaseName"`
+
+	// Domain specific fields
+	// EndedTs is zero if the session is open.
+	EndedTs    int64 `json:"endedTs"`
+	EntryCount int   `json:"entryCount"`
+}
+
+// AdminSessionCreate is the API message for opening an admin session.
+type AdminSessionCreate struct {
+	InstanceID   int    `json:"instanceId"`
+	DatabaseName string `json:"databaseName"`
+}
+
+// AdminSessionEntry is the API message for an entry of the admin session transcript.
+type AdminSessionEntry struct {
+	Seq       int                   `json:"seq"`
+	CreatedTs int64                 `json:"createdTs"`
+	Kind      AdminSessionEntryKind `json:"kind"`
+	Content   string                `json:"content"`
+	// Truncated is true if the content beyond the MaxAdminSessionEntryContentSize is dropped.
+	Truncated bool   `json:"truncated"`
+	PrevHash  string `json:"prevHash"`
+	Hash      string `json:"hash"`
+}
+
+// AdminSessionTranscript is the API message for the transcript of an admin session.
+type AdminSessionTranscript struct {
+	Session   *AdminSession        `json:"session"`
+	EntryList []*AdminSessionEntry `json:"entryList"`
+	// Verified is true if the hash chain of the entries is intact.
+	Verified bool `json:"verified"`
+	// VerifyError describes the first broken entry if the transcript isn't verified.
+	VerifyError string `json:"verifyError"`
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/alert"
+)
+
+// AlertCondition is the condition opening the incidents on the alert receivers.
+type AlertCondition string
+
+const (
+	// AlertConditionTaskRunFailed is the failed task of an open issue, it's resolved after the task is retried,
+	// skipped or the issue is closed.
+	AlertConditionTaskRunFailed AlertCondition = "TASK_RUN_FAILED"
+	// AlertConditionSchemaDrift is the schema drift anomaly of a database.
+	AlertConditionSchemaDrift AlertCondition = "SCHEMA_DRIFT"
+	// AlertConditionInstanceUnreachable is the connection anomaly of an instance.
+	AlertConditionInstanceUnreachable AlertCondition = "INSTANCE_UNREACHABLE"
+)
+
+// SettingWorkspaceAlertingValue is the setting value of the alert receivers.
+type SettingWorkspaceAlertingValue struct {
+	ReceiverList []*AlertReceiver `json:"receiverList"`
+}
+
+// AlertReceiver is an alerting system the incidents are opened on.
+type AlertReceiver struct {
+	// ID identifies the open incidents of the receiver.
+	ID       string     `json:"id"`
+	Type     alert.Type `json:"type"`
+	Disabled bool       `json:"disabled"`
+	// URL overrides the default API URL, e.g. https://api.eu.opsgenie.com for the EU instance of Opsgenie.
+	URL string `json:"url"`
+	// Key is the routing key for PagerDuty and the API key for Opsgenie, it's never returned to the clients. The key
+	// is kept if it's empty in the update.
+	Key string `json:"key"`
+	// ConditionList is the conditions alerted to the receiver, all conditions are alerted if it's empty.
+	ConditionList []AlertCondition `json:"conditionList"`
+}
+
+// Config returns the configuration of the receiver for the sender.
+func (receiver *AlertReceiver) Config() *alert.Config {
+	return &alert.Config{
+		Type: receiver.Type,
+		URL:  receiver.URL,
+		Key:  receiver.Key,
+	}
+}
+
+// HasCondition returns whether the condition is alerted to the receiver.
+func (receiver *AlertReceiver) HasCondition(condition AlertCondition) bool {
+	if len(receiver.ConditionList) == 0 {
+		return true
+	}
+	for _, c := range receiver.ConditionList {
+		if c == condition {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAndGetAlertingSetting validates the setting value and returns the parsed one.
+func ValidateAndGetAlertingSetting(settingValue string) (*SettingWorkspaceAlertingValue, error) {
+	value := new(SettingWorkspaceAlertingValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	ids := make(map[string]bool)
+	for _, receiver := range value.ReceiverList {
+		if receiver.ID == "" {
+			return nil, errors.Errorf("alert receiver ID is required")
+		}
+		if ids[receiver.ID] {
+			return nil, errors.Errorf("duplicate alert receiver %q", receiver.ID)
+		}
+		ids[receiver.ID] = true
+		if err := alert.Validate(receiver.Config()); err != nil {
+			return nil, errors.Wrapf(err, "invalid alert receiver %q", receiver.ID)
+		}
+		for _, condition := range receiver.ConditionList {
+			switch condition {
+			case AlertConditionTaskRunFailed, AlertConditionSchemaDrift, AlertConditionInstanceUnreachable:
+			default:
+				return nil, errors.Errorf("invalid condition %q of alert receiver %q", condition, receiver.ID)
+			}
+		}
+	}
+	return value, nil
+}
+
+// InheritAlertReceiverKeys fills the empty keys of the receivers in the new setting value with the ones of the same
+// receivers in the old value, as the keys aren't returned to the clients. It returns the new setting value after
+// validation.
+func InheritAlertReceiverKeys(oldSettingValue, settingValue string) (string, error) {
+	value := new(SettingWorkspaceAlertingValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	if oldSettingValue != "" {
+		oldValue := new(SettingWorkspaceAlertingValue)
+		if err := json.Unmarshal([]byte(oldSettingValue), oldValue); err != nil {
+			return "", errors.Wrapf(err, "failed to unmarshal setting value")
+		}
+		oldKeys := make(map[string]string)
+		for _, receiver := range oldValue.ReceiverList {
+			oldKeys[receiver.ID] = receiver.Key
+		}
+		for _, receiver := range value.ReceiverList {
+			if receiver.Key == "" {
+				receiver.Key = oldKeys[receiver.ID]
+			}
+		}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal setting value")
+	}
+	if _, err := ValidateAndGetAlertingSetting(string(b)); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// RedactAlertReceiverKeys removes the keys from the setting value returned to the clients.
+func RedactAlertReceiverKeys(settingValue string) (string, error) {
+	value := new(SettingWorkspaceAlertingValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	for _, receiver := range value.ReceiverList {
+		receiver.Key = ""
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal setting value")
+	}
+	return string(b), nil
+}
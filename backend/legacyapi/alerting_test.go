@@ -0,0 +1,32 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertReceiverKeys(t *testing.T) {
+	a := require.New(t)
+	old := `{"receiverList":[{"id":"pagerduty","type":"PAGERDUTY","key":"secret","conditionList":["TASK_RUN_FAILED"]}]}`
+
+	redacted, err := RedactAlertReceiverKeys(old)
+	a.NoError(err)
+	a.NotContains(redacted, "secret")
+
+	// The client sends back the redacted value, and the saved key is kept.
+	value, err := InheritAlertReceiverKeys(old, redacted)
+	a.NoError(err)
+	parsed, err := ValidateAndGetAlertingSetting(value)
+	a.NoError(err)
+	a.Equal("secret", parsed.ReceiverList[0].Key)
+	a.True(parsed.ReceiverList[0].HasCondition(AlertConditionTaskRunFailed))
+	a.False(parsed.ReceiverList[0].HasCondition(AlertConditionSchemaDrift))
+
+	// The key of a renamed receiver isn't inherited.
+	_, err = InheritAlertReceiverKeys(old, `{"receiverList":[{"id":"oncall","type":"PAGERDUTY"}]}`)
+	a.Error(err)
+
+	_, err = ValidateAndGetAlertingSetting(`{"receiverList":[{"id":"opsgenie","type":"OPSGENIE","key":"secret","conditionList":["BACKUP_FAILED"]}]}`)
+	a.Error(err)
+}
@@ -16,6 +16,10 @@ const (
 	AnomalyDatabaseConnection AnomalyType = "bb.anomaly.database.connection"
 	// AnomalyDatabaseSchemaDrift is the anomaly type for database schema drifts.
 	AnomalyDatabaseSchemaDrift AnomalyType = "bb.anomaly.database.schema.drift"
+	// AnomalyInstanceCertificateExpiry is the anomaly type for the expiring certificates of the instance data source.
+	AnomalyInstanceCertificateExpiry AnomalyType = "bb.anomaly.instance.certificate.expiry"
+	// AnomalyDatabaseCertificateExpiry is the anomaly type for the expiring certificates of the database TLS configuration.
+	AnomalyDatabaseCertificateExpiry AnomalyType = "bb.anomaly.database.certificate.expiry"
 )
 
 // AnomalySeverity is the severity of anomaly.
@@ -35,7 +39,7 @@ func AnomalySeverityFromType(anomalyType AnomalyType) AnomalySeverity {
 	switch anomalyType {
 	case AnomalyDatabaseBackupPolicyViolation:
 		return AnomalySeverityMedium
-	case AnomalyDatabaseBackupMissing:
+	case AnomalyDatabaseBackupMissing, AnomalyInstanceCertificateExpiry, AnomalyDatabaseCertificateExpiry:
 		return AnomalySeverityHigh
 	case AnomalyInstanceConnection:
 	case AnomalyInstanceMigrationSchema:
@@ -80,6 +84,49 @@ type AnomalyDatabaseSchemaDriftPayload struct {
 	Expect string `json:"expect,omitempty"`
 	// The actual schema dumped from the database
 	Actual string `json:"actual,omitempty"`
+	// The drifted objects of the actual schema
+	Objects []*SchemaDriftObject `json:"objects,omitempty"`
+}
+
+// SchemaDriftAction is the out-of-band change on a drifted object.
+type SchemaDriftAction string
+
+const (
+	// SchemaDriftCreated means the object is created out of band.
+	SchemaDriftCreated SchemaDriftAction = "CREATED"
+	// SchemaDriftDropped means the object is dropped out of band.
+	SchemaDriftDropped SchemaDriftAction = "DROPPED"
+	// SchemaDriftAltered means the object is altered out of band.
+	SchemaDriftAltered SchemaDriftAction = "ALTERED"
+)
+
+// SchemaDriftObject is the API message for a drifted object.
+type SchemaDriftObject struct {
+	// The object type such as TABLE, VIEW and FUNCTION
+	Type   string            `json:"type"`
+	Name   string            `json:"name"`
+	Action SchemaDriftAction `json:"action"`
+}
+
+// SchemaDriftCorrectiveMigration is the API message for the migration reverting the drift to the expected schema.
+type SchemaDriftCorrectiveMigration struct {
+	Statement string `json:"statement"`
+}
+
+// AnomalyInstanceCertificateExpiryPayload is the API message for instance certificate expiry payloads.
+type AnomalyInstanceCertificateExpiryPayload struct {
+	// The subject of the certificate expiring first
+	Subject string `json:"subject,omitempty"`
+	// Time when the certificate expires
+	ExpireTs int64 `json:"expireTs,omitempty"`
+}
+
+// AnomalyDatabaseCertificateExpiryPayload is the API message for database certificate expiry payloads.
+type AnomalyDatabaseCertificateExpiryPayload struct {
+	// The subject of the certificate expiring first
+	Subject string `json:"subject,omitempty"`
+	// Time when the certificate expires
+	ExpireTs int64 `json:"expireTs,omitempty"`
 }
 
 // Anomaly is the API message for an anomaly.
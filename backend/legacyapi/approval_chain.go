@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ApprovalNodeRolePrefix is the prefix of the approval nodes approved by the users with a project role, i.e.
+	// roles/{role}.
+	ApprovalNodeRolePrefix = "roles/"
+	// ApprovalNodeUserPrefix is the prefix of the approval nodes approved by a named user, i.e. users/{email}.
+	ApprovalNodeUserPrefix = "users/"
+	// ApprovalNodeExternalApproverPrefix is the prefix of the approval nodes approved by an external approver, i.e.
+	// externalApprovers/{id}.
+	ApprovalNodeExternalApproverPrefix = "externalApprovers/"
+)
+
+// SettingWorkspaceApprovalChainValue is the setting value of the approval chains, which extends the approval flows
+// with the escalations and the external approvers.
+type SettingWorkspaceApprovalChainValue struct {
+	// EscalationList is the escalations of the approval steps by the risk levels.
+	EscalationList []*ApprovalEscalation `json:"escalationList"`
+	// ExternalApproverList is the external approvers referred by the approval nodes.
+	ExternalApproverList []*ExternalApprover `json:"externalApproverList"`
+}
+
+// ApprovalEscalation lets the escalation approver approve the step pending for longer than the timeout, for the
+// issues of the risk level.
+type ApprovalEscalation struct {
+	// Level is the risk level, e.g. 300 for the high risk.
+	Level          int64 `json:"level"`
+	TimeoutSeconds int64 `json:"timeoutSeconds"`
+	// Approver is in the format of the approval node, either roles/{role} or users/{email}.
+	Approver string `json:"approver"`
+}
+
+// ExternalApproverType is the type of the external approver.
+type ExternalApproverType string
+
+const (
+	// ExternalApproverWebhook posts the pending step to the URL, and the external system approves or rejects it by
+	// calling back. It's the default type.
+	ExternalApproverWebhook ExternalApproverType = "WEBHOOK"
+	// ExternalApproverFeishu creates a Feishu approval instance of the pending step for the approver, and the step is
+	// approved or rejected when the instance is. It needs the Feishu external approval of the IM setting.
+	ExternalApproverFeishu ExternalApproverType = "FEISHU"
+)
+
+// ExternalApprover is an external system approving the approval steps.
+type ExternalApprover struct {
+	ID    string               `json:"id"`
+	Title string               `json:"title"`
+	Type  ExternalApproverType `json:"type"`
+	// URL is the URL of the WEBHOOK external approver.
+	URL string `json:"url"`
+	// Approver is the email of the Feishu user approving the instances of the FEISHU external approver.
+	Approver string `json:"approver"`
+}
+
+// ExternalApprovalRequest is the request posted to the external approver when the step is pending.
+type ExternalApprovalRequest struct {
+	IssueID    int    `json:"issueId"`
+	IssueTitle string `json:"issueTitle"`
+	ProjectID  string `json:"projectId"`
+	Step       int    `json:"step"`
+	RiskLevel  int64  `json:"riskLevel"`
+	// CallbackURL is where to post the ExternalApprovalCallback, it's only valid for the step.
+	CallbackURL string `json:"callbackUrl"`
+}
+
+// ExternalApprovalCallback is the API message for the external approver to approve or reject the step.
+type ExternalApprovalCallback struct {
+	Action  ExternalApprovalEventActionType `json:"action"`
+	Comment string                          `json:"comment"`
+}
+
+// ApprovalStepExternalStatus is the status of the external approval of the pending step.
+type ApprovalStepExternalStatus string
+
+const (
+	// ApprovalStepExternalNone means the step isn't sent to the external approver.
+	ApprovalStepExternalNone ApprovalStepExternalStatus = ""
+	// ApprovalStepExternalRequested means the step is sent to the external approver.
+	ApprovalStepExternalRequested ApprovalStepExternalStatus = "REQUESTED"
+	// ApprovalStepExternalRejected means the external approver rejected the step, it's sent again after the
+	// approval flow is found again, e.g. the statement is changed.
+	ApprovalStepExternalRejected ApprovalStepExternalStatus = "REJECTED"
+)
+
+// FindExternalApprover returns the external approver of the ID.
+func (v *SettingWorkspaceApprovalChainValue) FindExternalApprover(id string) *ExternalApprover {
+	for _, approver := range v.ExternalApproverList {
+		if approver.ID == id {
+			return approver
+		}
+	}
+	return nil
+}
+
+// FindEscalation returns the escalation of the risk level.
+func (v *SettingWorkspaceApprovalChainValue) FindEscalation(level int64) *ApprovalEscalation {
+	for _, escalation := range v.EscalationList {
+		if escalation.Level == level {
+			return escalation
+		}
+	}
+	return nil
+}
+
+// ValidateAndGetApprovalChainSetting validates the setting value and returns the parsed one.
+func ValidateAndGetApprovalChainSetting(settingValue string) (*SettingWorkspaceApprovalChainValue, error) {
+	value := new(SettingWorkspaceApprovalChainValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	levels := make(map[int64]bool)
+	for _, escalation := range value.EscalationList {
+		if levels[escalation.Level] {
+			return nil, errors.Errorf("duplicate escalation for risk level %d", escalation.Level)
+		}
+		levels[escalation.Level] = true
+		if escalation.TimeoutSeconds <= 0 {
+			return nil, errors.Errorf("escalation timeout must be positive")
+		}
+		if !strings.HasPrefix(escalation.Approver, ApprovalNodeRolePrefix) && !strings.HasPrefix(escalation.Approver, ApprovalNodeUserPrefix) {
+			return nil, errors.Errorf("escalation approver %q must be roles/{role} or users/{email}", escalation.Approver)
+		}
+	}
+	ids := make(map[string]bool)
+	for _, approver := range value.ExternalApproverList {
+		if approver.ID == "" {
+			return nil, errors.Errorf("external approver ID is required")
+		}
+		if ids[approver.ID] {
+			return nil, errors.Errorf("duplicate external approver %q", approver.ID)
+		}
+		ids[approver.ID] = true
+		switch approver.Type {
+		case "", ExternalApproverWebhook:
+			u, err := url.Parse(approver.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, errors.Errorf("invalid URL %q of external approver %q", approver.URL, approver.ID)
+			}
+		case ExternalApproverFeishu:
+			if approver.Approver == "" {
+				return nil, errors.Errorf("approver email is required for Feishu external approver %q", approver.ID)
+			}
+		default:
+			return nil, errors.Errorf("invalid type %q of external approver %q", approver.Type, approver.ID)
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAndGetApprovalChainSetting(t *testing.T) {
+	value, err := ValidateAndGetApprovalChainSetting(`{
+		"escalationList": [{"level": 300, "timeoutSeconds": 3600, "approver": "users/cto@example.com"}],
+		"externalApproverList": [
+			{"id": "change-board", "title": "Change board", "url": "https://cab.example.com/bytebase"},
+			{"id": "dba-lead", "title": "DBA lead", "type": "FEISHU", "approver": "dba-lead@example.com"}
+		]
+	}`)
+	require.NoError(t, err)
+	require.Equal(t, "users/cto@example.com", value.FindEscalation(300).Approver)
+	require.Nil(t, value.FindEscalation(200))
+	require.Equal(t, "Change board", value.FindExternalApprover("change-board").Title)
+	require.Equal(t, "dba-lead@example.com", value.FindExternalApprover("dba-lead").Approver)
+	require.Nil(t, value.FindExternalApprover("security"))
+
+	for _, settingValue := range []string{
+		`{"escalationList": [{"level": 300, "timeoutSeconds": 0, "approver": "roles/OWNER"}]}`,
+		`{"escalationList": [{"level": 300, "timeoutSeconds": 60, "approver": "WORKSPACE_OWNER"}]}`,
+		`{"escalationList": [{"level": 300, "timeoutSeconds": 60, "approver": "roles/OWNER"}, {"level": 300, "timeoutSeconds": 60, "approver": "roles/OWNER"}]}`,
+		`{"externalApproverList": [{"id": "change-board", "url": "ftp://cab.example.com"}]}`,
+		`{"externalApproverList": [{"id": "", "url": "https://cab.example.com"}]}`,
+		`{"externalApproverList": [{"id": "dba-lead", "type": "FEISHU"}]}`,
+		`{"externalApproverList": [{"id": "dba-lead", "type": "EMAIL", "approver": "dba-lead@example.com"}]}`,
+	} {
+		_, err := ValidateAndGetApprovalChainSetting(settingValue)
+		require.Error(t, err, settingValue)
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/auditsink"
+)
+
+// AuditActivityTypePrefixList is the prefixes of the activity types in the audit log.
+var AuditActivityTypePrefixList = []string{
+	"bb.member.",
+	string(ActivityProjectDatabaseTransfer),
+	string(ActivityProjectMemberRoleUpdate),
+	"bb.database.grant.",
+	string(ActivitySQLEditorQuery),
+	string(ActivitySQLEditorExport),
+}
+
+// SettingWorkspaceAuditSinkValue is the setting value of the audit sinks.
+type SettingWorkspaceAuditSinkValue struct {
+	SinkList []*AuditSink `json:"sinkList"`
+}
+
+// AuditSink is a SIEM system the audit entries are streamed to.
+type AuditSink struct {
+	// ID identifies the delivery progress of the sink, changing it streams the entries from the latest one.
+	ID       string         `json:"id"`
+	Type     auditsink.Type `json:"type"`
+	Disabled bool           `json:"disabled"`
+	// URL is tcp://host:port or udp://host:port for syslog, the HEC endpoint for Splunk HEC, and the REST Proxy base
+	// URL for Kafka.
+	URL string `json:"url"`
+	// Token is the HEC token for Splunk HEC, it's never returned to the clients. The token is kept if it's empty in
+	// the update.
+	Token string `json:"token"`
+	// Topic is the topic for Kafka.
+	Topic string `json:"topic"`
+}
+
+// Config returns the configuration of the sink for the sender.
+func (sink *AuditSink) Config() *auditsink.Config {
+	return &auditsink.Config{
+		Type:  sink.Type,
+		URL:   sink.URL,
+		Token: sink.Token,
+		Topic: sink.Topic,
+	}
+}
+
+// ValidateAndGetAuditSinkSetting validates the setting value and returns the parsed one.
+func ValidateAndGetAuditSinkSetting(settingValue string) (*SettingWorkspaceAuditSinkValue, error) {
+	value := new(SettingWorkspaceAuditSinkValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	ids := make(map[string]bool)
+	for _, sink := range value.SinkList {
+		if sink.ID == "" {
+			return nil, errors.Errorf("audit sink ID is required")
+		}
+		if ids[sink.ID] {
+			return nil, errors.Errorf("duplicate audit sink %q", sink.ID)
+		}
+		ids[sink.ID] = true
+		if err := auditsink.Validate(sink.Config()); err != nil {
+			return nil, errors.Wrapf(err, "invalid audit sink %q", sink.ID)
+		}
+	}
+	return value, nil
+}
+
+// InheritAuditSinkTokens fills the empty tokens of the sinks in the new setting value with the ones of the same sinks
+// in the old value, as the tokens aren't returned to the clients. It returns the new setting value after validation.
+func InheritAuditSinkTokens(oldSettingValue, settingValue string) (string, error) {
+	value := new(SettingWorkspaceAuditSinkValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	if oldSettingValue != "" {
+		oldValue := new(SettingWorkspaceAuditSinkValue)
+		if err := json.Unmarshal([]byte(oldSettingValue), oldValue); err != nil {
+			return "", errors.Wrapf(err, "failed to unmarshal setting value")
+		}
+		oldTokens := make(map[string]string)
+		for _, sink := range oldValue.SinkList {
+			oldTokens[sink.ID] = sink.Token
+		}
+		for _, sink := range value.SinkList {
+			if sink.Token == "" {
+				sink.Token = oldTokens[sink.ID]
+			}
+		}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal setting value")
+	}
+	if _, err := ValidateAndGetAuditSinkSetting(string(b)); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// RedactAuditSinkTokens removes the tokens from the setting value returned to the clients.
+func RedactAuditSinkTokens(settingValue string) (string, error) {
+	value := new(SettingWorkspaceAuditSinkValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	for _, sink := range value.SinkList {
+		sink.Token = ""
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal setting value")
+	}
+	return string(b), nil
+}
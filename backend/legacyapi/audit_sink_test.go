@@ -0,0 +1,30 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditSinkTokens(t *testing.T) {
+	a := require.New(t)
+	old := `{"sinkList":[{"id":"splunk","type":"SPLUNK_HEC","url":"https://splunk:8088/services/collector/event","token":"secret"}]}`
+
+	redacted, err := RedactAuditSinkTokens(old)
+	a.NoError(err)
+	a.NotContains(redacted, "secret")
+
+	// The client sends back the redacted value, and the saved token is kept.
+	value, err := InheritAuditSinkTokens(old, redacted)
+	a.NoError(err)
+	parsed, err := ValidateAndGetAuditSinkSetting(value)
+	a.NoError(err)
+	a.Equal("secret", parsed.SinkList[0].Token)
+
+	// The token of a renamed sink isn't inherited.
+	_, err = InheritAuditSinkTokens(old, `{"sinkList":[{"id":"siem","type":"SPLUNK_HEC","url":"https://splunk:8088/services/collector/event"}]}`)
+	a.Error(err)
+
+	_, err = ValidateAndGetAuditSinkSetting(`{"sinkList":[{"id":"a","type":"SYSLOG","url":"tcp://syslog:514"},{"id":"a","type":"SYSLOG","url":"udp://syslog:514"}]}`)
+	a.Error(err)
+}
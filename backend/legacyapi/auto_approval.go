@@ -0,0 +1,18 @@
+package api
+
+// AutoApproval is the API message for an issue approved by an auto-approval rule.
+type AutoApproval struct {
+	ID int `json:"id"`
+
+	// Standard fields
+	CreatedTs int64 `json:"createdTs"`
+
+	// Domain specific fields
+	IssueID   int    `json:"issueId"`
+	RiskLevel int64  `json:"riskLevel"`
+	RuleID    string `json:"ruleId"`
+	Condition string `json:"condition"`
+	// RevokerID is 0 if the decision isn't revoked.
+	RevokerID int   `json:"revokerId"`
+	RevokedTs int64 `json:"revokedTs"`
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"path"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// CloudProvider is the cloud provider to discover the instances from.
+type CloudProvider string
+
+const (
+	// CloudProviderAWS discovers the RDS instances.
+	CloudProviderAWS CloudProvider = "AWS"
+	// CloudProviderGCP discovers the Cloud SQL instances.
+	CloudProviderGCP CloudProvider = "GCP"
+	// CloudProviderAzure discovers the Azure Database flexible servers and the Azure SQL servers.
+	CloudProviderAzure CloudProvider = "AZURE"
+)
+
+// DiscoveredInstanceStatus is the status of a discovered instance.
+type DiscoveredInstanceStatus string
+
+const (
+	// DiscoveredInstanceProposed is the status of the unregistered instance proposed for onboarding.
+	DiscoveredInstanceProposed DiscoveredInstanceStatus = "PROPOSED"
+	// DiscoveredInstanceRegistered is the status of the instance registered with the same host and port.
+	DiscoveredInstanceRegistered DiscoveredInstanceStatus = "REGISTERED"
+	// DiscoveredInstanceDismissed is the status of the unregistered instance dismissed by the user.
+	DiscoveredInstanceDismissed DiscoveredInstanceStatus = "DISMISSED"
+	// DiscoveredInstanceDeletedUpstream is the status of the registered instance no longer found in the cloud provider.
+	DiscoveredInstanceDeletedUpstream DiscoveredInstanceStatus = "DELETED_UPSTREAM"
+)
+
+// SettingWorkspaceCloudDiscoveryValue is the setting value of SettingWorkspaceCloudDiscovery type setting.
+// The cloud providers are accessed with the default credentials of the server, such as the AWS default credential chain,
+// the GCP application default credentials and the Azure default credential.
+type SettingWorkspaceCloudDiscoveryValue struct {
+	Providers []*CloudDiscoveryProvider `json:"providers"`
+	// EnvironmentMappings suggests the environment of the discovered instances, the first matched rule wins.
+	EnvironmentMappings []*EnvironmentMappingRule `json:"environmentMappings"`
+}
+
+// ValidateAndGetCloudDiscoverySetting validates the setting value and returns the parsed one.
+func ValidateAndGetCloudDiscoverySetting(settingValue string) (*SettingWorkspaceCloudDiscoveryValue, error) {
+	value := new(SettingWorkspaceCloudDiscoveryValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	for _, provider := range value.Providers {
+		switch provider.Provider {
+		case CloudProviderAWS:
+		case CloudProviderGCP:
+			if len(provider.Projects) == 0 {
+				return nil, errors.Errorf("projects cannot be empty for GCP")
+			}
+		case CloudProviderAzure:
+			if len(provider.Subscriptions) == 0 {
+				return nil, errors.Errorf("subscriptions cannot be empty for Azure")
+			}
+		default:
+			return nil, errors.Errorf("unsupported cloud provider %q", provider.Provider)
+		}
+	}
+	for _, rule := range value.EnvironmentMappings {
+		if rule.Environment == "" {
+			return nil, errors.Errorf("environment cannot be empty in the environment mapping rule")
+		}
+		if _, err := path.Match(rule.NamePattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid name pattern %q", rule.NamePattern)
+		}
+	}
+	return value, nil
+}
+
+// CloudDiscoveryProvider is the scope to discover the instances from a cloud provider.
+type CloudDiscoveryProvider struct {
+	Provider CloudProvider `json:"provider"`
+	// Regions are the AWS regions, the region of the default AWS config is used if empty.
+	Regions []string `json:"regions"`
+	// Projects are the GCP project IDs.
+	Projects []string `json:"projects"`
+	// Subscriptions are the Azure subscription IDs.
+	Subscriptions []string `json:"subscriptions"`
+}
+
+// EnvironmentMappingRule maps the discovered instances to an environment.
+// The empty conditions match any instance.
+type EnvironmentMappingRule struct {
+	// LabelKey and LabelValue match the AWS tags, the GCP user labels or the Azure tags.
+	// The empty value matches any value of the key.
+	LabelKey   string `json:"labelKey"`
+	LabelValue string `json:"labelValue"`
+	// NamePattern is the shell pattern of the instance name, such as "prod-*".
+	NamePattern string `json:"namePattern"`
+	// Environment is the resource ID of the environment.
+	Environment string `json:"environment"`
+}
+
+// DiscoveredInstance is the API message for a discovered instance.
+type DiscoveredInstance struct {
+	ID         int               `json:"id"`
+	Provider   CloudProvider     `json:"provider"`
+	ExternalID string            `json:"externalId"`
+	Name       string            `json:"name"`
+	Engine     db.Type           `json:"engine"`
+	Host       string            `json:"host"`
+	Port       string            `json:"port"`
+	Region     string            `json:"region"`
+	Labels     map[string]string `json:"labels"`
+	// EnvironmentID is the suggested environment, zero if no environment mapping rule matches.
+	EnvironmentID int `json:"environmentId"`
+	// InstanceID is the registered instance, zero if not registered.
+	InstanceID int                      `json:"instanceId"`
+	Status     DiscoveredInstanceStatus `json:"status"`
+	LastSeenTs int64                    `json:"lastSeenTs"`
+}
+
+// DiscoveredInstancePatch is the API message for patching a discovered instance.
+// Only the proposed instances can be dismissed, and only the dismissed ones can be proposed again.
+type DiscoveredInstancePatch struct {
+	Status *DiscoveredInstanceStatus `json:"status"`
+	// InstanceID links the instance registered from the discovered instance, so that it's no longer proposed
+	// without waiting for the next discovery.
+	InstanceID *int `json:"instanceId"`
+}
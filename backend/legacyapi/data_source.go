@@ -3,6 +3,8 @@ package api
 import (
 	"encoding/json"
 	"errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
 )
 
 const (
@@ -35,6 +37,18 @@ const (
 	RO DataSourceType = "RO"
 )
 
+// ReadReplicaRoutingPolicy is the policy to route the reads among the read-only data sources of an instance.
+type ReadReplicaRoutingPolicy string
+
+const (
+	// ReadReplicaRoutingUnspecified always routes the reads to the first healthy read-only data source.
+	ReadReplicaRoutingUnspecified ReadReplicaRoutingPolicy = ""
+	// ReadReplicaRoutingRoundRobin routes the reads to the healthy read-only data sources in turn.
+	ReadReplicaRoutingRoundRobin ReadReplicaRoutingPolicy = "ROUND_ROBIN"
+	// ReadReplicaRoutingNearest routes the reads to the healthy read-only data source with the lowest connection latency.
+	ReadReplicaRoutingNearest ReadReplicaRoutingPolicy = "NEAREST"
+)
+
 // DataSourceOptions is the options for a data source.
 type DataSourceOptions struct {
 	// SRV is used for MongoDB only.
@@ -49,6 +63,69 @@ type DataSourceOptions struct {
 	SSHUser       string `json:"sshUser" jsonapi:"attr,sshUser"`
 	SSHPassword   string `json:"sshPassword" jsonapi:"attr,sshPassword"`
 	SSHPrivateKey string `json:"sshPrivateKey" jsonapi:"attr,sshPrivateKey"`
+	// SSHJumpHostList is the list of SSH bastions hopped through in order before reaching the SSH host.
+	SSHJumpHostList []SSHJumpHost `json:"sshJumpHostList" jsonapi:"attr,sshJumpHostList"`
+	// SOCKS5Proxy is the SOCKS5 proxy to dial the database, or the first SSH hop, through.
+	SOCKS5Proxy *SOCKS5Proxy `json:"socks5Proxy" jsonapi:"attr,socks5Proxy"`
+	// AuthenticationType is the type of authentication, the password is used if it's empty.
+	AuthenticationType db.AuthenticationType `json:"authenticationType" jsonapi:"attr,authenticationType"`
+	// AWSRegion, AWSAccessKeyID and AWSSecretAccessKey are used for the AWS RDS IAM authentication.
+	// The default AWS credential chain, such as the instance role, is used if AWSAccessKeyID is empty.
+	// AWSSecretAccessKey is write-only.
+	AWSRegion          string `json:"awsRegion" jsonapi:"attr,awsRegion"`
+	AWSAccessKeyID     string `json:"awsAccessKeyId" jsonapi:"attr,awsAccessKeyId"`
+	AWSSecretAccessKey string `json:"awsSecretAccessKey" jsonapi:"attr,awsSecretAccessKey"`
+	// GCPCloudSQLConnectionName is the Cloud SQL instance connection name in the form of "project:region:instance".
+	// The host and port are ignored if it's set, and the Cloud SQL connector dials with the automatic TLS.
+	// The application default credentials are used if GCPCredentialsJSON is empty, which is write-only.
+	GCPCloudSQLConnectionName string `json:"gcpCloudSqlConnectionName" jsonapi:"attr,gcpCloudSqlConnectionName"`
+	GCPCloudSQLUsePrivateIP   bool   `json:"gcpCloudSqlUsePrivateIp" jsonapi:"attr,gcpCloudSqlUsePrivateIp"`
+	GCPCredentialsJSON        string `json:"gcpCredentialsJson" jsonapi:"attr,gcpCredentialsJson"`
+	// AzureTenantID, AzureClientID and AzureClientSecret are used for the Azure AD authentication.
+	// The service principal is used if AzureClientSecret is set, which is write-only, otherwise the managed identity is used.
+	AzureTenantID     string `json:"azureTenantId" jsonapi:"attr,azureTenantId"`
+	AzureClientID     string `json:"azureClientId" jsonapi:"attr,azureClientId"`
+	AzureClientSecret string `json:"azureClientSecret" jsonapi:"attr,azureClientSecret"`
+	// KerberosRealm, KerberosKDC, KerberosSPN and KerberosKeytab are used for the Kerberos authentication of the username in the realm.
+	// KerberosKDC is the comma-separated KDC addresses, which are looked up in the DNS if it's empty.
+	// KerberosKeytab is the base64-encoded keytab, which is write-only.
+	KerberosRealm  string `json:"kerberosRealm" jsonapi:"attr,kerberosRealm"`
+	KerberosKDC    string `json:"kerberosKdc" jsonapi:"attr,kerberosKdc"`
+	KerberosSPN    string `json:"kerberosSpn" jsonapi:"attr,kerberosSpn"`
+	KerberosKeytab string `json:"kerberosKeytab" jsonapi:"attr,kerberosKeytab"`
+	// ExternalSecretReference is the reference to the password in the external secret manager, the password is ignored if it's set.
+	// The supported forms are "vault://<path>#<key>" for HashiCorp Vault and "awssm://<arn>#<key>" for AWS Secrets Manager.
+	// The username is also taken from the "username" key of the secret if it exists, such as the Vault dynamic database credentials.
+	ExternalSecretReference string `json:"externalSecretReference" jsonapi:"attr,externalSecretReference"`
+	// ReadReplicaRoutingPolicy and ReadReplicaFallbackToPrimary are used for the admin data source only.
+	// The schema sync also reads from the read-only data sources if ReadReplicaRoutingPolicy is specified.
+	// The reads fall back to the admin data source if there is no healthy read-only data source and ReadReplicaFallbackToPrimary is set.
+	ReadReplicaRoutingPolicy     ReadReplicaRoutingPolicy `json:"readReplicaRoutingPolicy" jsonapi:"attr,readReplicaRoutingPolicy"`
+	ReadReplicaFallbackToPrimary bool                     `json:"readReplicaFallbackToPrimary" jsonapi:"attr,readReplicaFallbackToPrimary"`
+	// MaxConnections and ConnectionIdleTimeoutSeconds are the connection pool limits of the instance, used for the admin data source only.
+	// MaxConnections limits the connections to the instance across all data sources, the connections are queued if it's reached, and 0 means unlimited.
+	// The released connections are kept idle for reuse within ConnectionIdleTimeoutSeconds, and the default is used if it's 0.
+	MaxConnections               int `json:"maxConnections" jsonapi:"attr,maxConnections"`
+	ConnectionIdleTimeoutSeconds int `json:"connectionIdleTimeoutSeconds" jsonapi:"attr,connectionIdleTimeoutSeconds"`
+}
+
+// SSHJumpHost is the API message for an SSH bastion.
+// Password and PrivateKey are write-only.
+type SSHJumpHost struct {
+	Host       string `json:"host" jsonapi:"attr,host"`
+	Port       string `json:"port" jsonapi:"attr,port"`
+	User       string `json:"user" jsonapi:"attr,user"`
+	Password   string `json:"password" jsonapi:"attr,password"`
+	PrivateKey string `json:"privateKey" jsonapi:"attr,privateKey"`
+}
+
+// SOCKS5Proxy is the API message for a SOCKS5 proxy.
+// Password is write-only.
+type SOCKS5Proxy struct {
+	Host     string `json:"host" jsonapi:"attr,host"`
+	Port     string `json:"port" jsonapi:"attr,port"`
+	User     string `json:"user" jsonapi:"attr,user"`
+	Password string `json:"password" jsonapi:"attr,password"`
 }
 
 // getDefaultDataSourceOptions returns the default data source options.
@@ -116,3 +193,26 @@ type DataSourcePatch struct {
 	Options          *DataSourceOptions `jsonapi:"attr,options"`
 	Database         *string            `jsonapi:"attr,database"`
 }
+
+// ReadReplicaHealth is the API message for the health of a read-only data source.
+type ReadReplicaHealth struct {
+	DataSourceID int    `json:"dataSourceId"`
+	Healthy      bool   `json:"healthy"`
+	LatencyMs    int64  `json:"latencyMs"`
+	CheckedTs    int64  `json:"checkedTs"`
+	Error        string `json:"error"`
+}
+
+// ConnectionPoolStats is the API message for the connection pool metrics of an instance.
+type ConnectionPoolStats struct {
+	MaxConnections int `json:"maxConnections"`
+	InUse          int `json:"inUse"`
+	Idle           int `json:"idle"`
+	Waiting        int `json:"waiting"`
+	// The cumulative counters since the server starts.
+	OpenCount      int64 `json:"openCount"`
+	ReuseCount     int64 `json:"reuseCount"`
+	WaitCount      int64 `json:"waitCount"`
+	WaitDurationMs int64 `json:"waitDurationMs"`
+	TimeoutCount   int64 `json:"timeoutCount"`
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"encoding/json"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
 )
 
 const (
@@ -223,3 +225,150 @@ type DatabaseEditResult struct {
 	Statement          string            `jsonapi:"attr,statement"`
 	ValidateResultList []*ValidateResult `jsonapi:"attr,validateResultList"`
 }
+
+// UnusedIndex is the API message for an index observed unused from the engine statistics.
+// This returns json instead of jsonapi since it's a report rather than a resource.
+type UnusedIndex struct {
+	// SchemaName is empty for the engines without schema, such as MySQL.
+	SchemaName string `json:"schemaName"`
+	TableName  string `json:"tableName"`
+	IndexName  string `json:"indexName"`
+	// UnusedSinceTs is the timestamp when the index was first observed unused.
+	UnusedSinceTs int64 `json:"unusedSinceTs"`
+}
+
+// DatabaseTLS is the API message for the database level TLS configuration, which overrides the one of the instance data sources.
+// The certificates and the key are never returned, so it only reports whether they are set and when they expire.
+type DatabaseTLS struct {
+	HasSslCa   bool `json:"hasSslCa"`
+	HasSslCert bool `json:"hasSslCert"`
+	// CertificateExpireTs is the expiry of the certificate expiring first, 0 if no certificate is set.
+	CertificateExpireTs int64 `json:"certificateExpireTs"`
+}
+
+// DatabaseTLSPatch is the API message for patching the database level TLS configuration.
+// Omitted fields are kept, and the fields set to empty string are cleared.
+type DatabaseTLSPatch struct {
+	SslCa   *string `json:"sslCa"`
+	SslCert *string `json:"sslCert"`
+	SslKey  *string `json:"sslKey"`
+}
+
+// DatabaseSchemaImport is the API message for importing the schema designed in other formats as the desired schema of the database.
+type DatabaseSchemaImport struct {
+	// Format is the format of the content, only "dbml" is supported now.
+	Format  string `json:"format"`
+	Content string `json:"content"`
+}
+
+// DatabaseSchemaImportResult is the API message for the result of the schema import.
+type DatabaseSchemaImportResult struct {
+	// Schema is the desired schema in the SDL format.
+	Schema string `json:"schema"`
+	// Statement is the migration statement from the current schema to the desired schema.
+	Statement string `json:"statement"`
+}
+
+// DatabaseSchemaConvert is the API message for converting the MySQL schema to the schema of another engine.
+type DatabaseSchemaConvert struct {
+	// TargetEngine is the engine to convert to, only POSTGRES and TIDB are supported now.
+	TargetEngine db.Type `json:"targetEngine"`
+	// Schema is the MySQL schema to convert, the synced schema of the database is used if it's empty.
+	Schema string `json:"schema"`
+}
+
+// DatabaseSchemaConvertResult is the API message for the result of the schema conversion.
+type DatabaseSchemaConvertResult struct {
+	// Schema is the converted schema of the target engine.
+	Schema string `json:"schema"`
+	// LossyMappingList is the list of the definitions that cannot be converted without loss.
+	LossyMappingList []*SchemaLossyMapping `json:"lossyMappingList"`
+}
+
+// SchemaLossyMapping is the definition changed or dropped during the schema conversion.
+type SchemaLossyMapping struct {
+	Table string `json:"table"`
+	// Column is the column or the index of the table, it's empty for the table-level definitions.
+	Column string `json:"column"`
+	// Source is the definition in the source schema.
+	Source string `json:"source"`
+	// Target is the definition in the converted schema, it's empty if the definition is dropped.
+	Target  string `json:"target"`
+	Message string `json:"message"`
+}
+
+// DatabaseSchemaCompare is the API message for comparing the schema with the same database in another environment.
+type DatabaseSchemaCompare struct {
+	// EnvironmentID is the environment of the compared database, which has the same name in the same project.
+	EnvironmentID int `json:"environmentId"`
+}
+
+// DatabaseSchemaCompareResult is the API message for the result of the schema comparison.
+type DatabaseSchemaCompareResult struct {
+	// TargetDatabaseID is the compared database, the promotion migration is applied to it.
+	TargetDatabaseID int `json:"targetDatabaseId"`
+	// DiffList is the object-level differences to promote the schema to the target database.
+	DiffList []*SchemaObjectDiff `json:"diffList"`
+	// Statement is the promotion migration changing the schema of the target database to the schema of the database.
+	Statement string `json:"statement"`
+}
+
+// SchemaObjectType is the type of the schema object.
+type SchemaObjectType string
+
+const (
+	// SchemaObjectSchema is the schema.
+	SchemaObjectSchema SchemaObjectType = "SCHEMA"
+	// SchemaObjectTable is the table.
+	SchemaObjectTable SchemaObjectType = "TABLE"
+	// SchemaObjectColumn is the column of the table.
+	SchemaObjectColumn SchemaObjectType = "COLUMN"
+	// SchemaObjectIndex is the index of the table.
+	SchemaObjectIndex SchemaObjectType = "INDEX"
+	// SchemaObjectForeignKey is the foreign key of the table.
+	SchemaObjectForeignKey SchemaObjectType = "FOREIGN_KEY"
+	// SchemaObjectView is the view.
+	SchemaObjectView SchemaObjectType = "VIEW"
+	// SchemaObjectFunction is the function.
+	SchemaObjectFunction SchemaObjectType = "FUNCTION"
+)
+
+// SchemaObjectDiffAction is the action to promote the schema object to the target database.
+type SchemaObjectDiffAction string
+
+const (
+	// SchemaObjectDiffCreate means the object only exists in the source database.
+	SchemaObjectDiffCreate SchemaObjectDiffAction = "CREATE"
+	// SchemaObjectDiffDrop means the object only exists in the target database.
+	SchemaObjectDiffDrop SchemaObjectDiffAction = "DROP"
+	// SchemaObjectDiffAlter means the object is different between the databases.
+	SchemaObjectDiffAlter SchemaObjectDiffAction = "ALTER"
+)
+
+// SchemaObjectDiff is the difference of a schema object between the source and target databases.
+type SchemaObjectDiff struct {
+	Type   SchemaObjectType       `json:"type"`
+	Action SchemaObjectDiffAction `json:"action"`
+	Schema string                 `json:"schema"`
+	// Table is the table of the column, index and foreign key.
+	Table string `json:"table"`
+	Name  string `json:"name"`
+	// Source and Target are the definitions in the source and target databases, they are empty if the object doesn't exist.
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// ChangeHistoryImport is the API message for importing the history of Flyway or Liquibase as the change history of the database.
+type ChangeHistoryImport struct {
+	// Source is the migration tool, only FLYWAY and LIQUIBASE are supported.
+	Source db.MigrationSource `json:"source"`
+	// Table is the history table of the tool, the default table of the tool is used if it's empty.
+	Table string `json:"table"`
+}
+
+// ChangeHistoryImportResult is the API message for the result of the change history import.
+type ChangeHistoryImportResult struct {
+	ImportedCount int `json:"importedCount"`
+	// SkippedList is the list of the skipped history with the reasons.
+	SkippedList []string `json:"skippedList"`
+}
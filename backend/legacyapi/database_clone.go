@@ -0,0 +1,65 @@
+package api
+
+import (
+	"github.com/pkg/errors"
+)
+
+// DatabaseCloneStatus is the status of a database clone.
+type DatabaseCloneStatus string
+
+const (
+	// DatabaseClonePending is the status of the clones waiting for the runner.
+	DatabaseClonePending DatabaseCloneStatus = "PENDING"
+	// DatabaseCloneRunning is the status of the clones being copied.
+	DatabaseCloneRunning DatabaseCloneStatus = "RUNNING"
+	// DatabaseCloneDone is the status of the clones copied completely.
+	DatabaseCloneDone DatabaseCloneStatus = "DONE"
+	// DatabaseCloneFailed is the status of the failed clones, the partially copied target database is kept for
+	// the investigation.
+	DatabaseCloneFailed DatabaseCloneStatus = "FAILED"
+)
+
+// DatabaseClone is the API message for copying the schema and the data of a database into an instance of a lower
+// environment, with the classified columns masked during the copy.
+type DatabaseClone struct {
+	ID int `json:"id"`
+
+	// Standard fields
+	CreatorID int   `json:"creatorId"`
+	CreatedTs int64 `json:"createdTs"`
+	UpdaterID int   `json:"updaterId"`
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Domain specific fields
+	SourceDatabaseID   int                 `json:"sourceDatabaseId"`
+	TargetInstanceID   int                 `json:"targetInstanceId"`
+	TargetDatabaseName string              `json:"targetDatabaseName"`
+	Status             DatabaseCloneStatus `json:"status"`
+	// TableCount and RowCount are the progress of the copy.
+	TableCount int    `json:"tableCount"`
+	RowCount   int64  `json:"rowCount"`
+	Error      string `json:"error"`
+}
+
+// DatabaseCloneCreate is the API message for cloning a database.
+type DatabaseCloneCreate struct {
+	SourceDatabaseID int `json:"sourceDatabaseId"`
+	// TargetInstanceID is the instance of the same engine in an unprotected environment.
+	TargetInstanceID int `json:"targetInstanceId"`
+	// TargetDatabaseName is the name of the database created in the target instance, it must not exist.
+	TargetDatabaseName string `json:"targetDatabaseName"`
+}
+
+// Validate validates the request.
+func (create *DatabaseCloneCreate) Validate() error {
+	if create.SourceDatabaseID <= 0 {
+		return errors.Errorf("source database is required")
+	}
+	if create.TargetInstanceID <= 0 {
+		return errors.Errorf("target instance is required")
+	}
+	if create.TargetDatabaseName == "" {
+		return errors.Errorf("target database name is required")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DatabaseGrantLevel is the level of the temporary access to a database.
+type DatabaseGrantLevel string
+
+const (
+	// DatabaseGrantQuerier grants querying the database in the SQL editor.
+	DatabaseGrantQuerier DatabaseGrantLevel = "QUERIER"
+	// DatabaseGrantAdmin grants executing any statements on the database in the admin mode, as well as querying it.
+	DatabaseGrantAdmin DatabaseGrantLevel = "ADMIN"
+)
+
+// DatabaseGrantStatus is the status of the temporary access to a database.
+type DatabaseGrantStatus string
+
+const (
+	// DatabaseGrantPending is the status of the grants waiting for the approval.
+	DatabaseGrantPending DatabaseGrantStatus = "PENDING"
+	// DatabaseGrantActive is the status of the approved grants before they expire.
+	DatabaseGrantActive DatabaseGrantStatus = "ACTIVE"
+	// DatabaseGrantRejected is the status of the rejected grants.
+	DatabaseGrantRejected DatabaseGrantStatus = "REJECTED"
+	// DatabaseGrantRevoked is the status of the grants revoked before they expire.
+	DatabaseGrantRevoked DatabaseGrantStatus = "REVOKED"
+	// DatabaseGrantExpired is the status of the grants revoked automatically after they expire.
+	DatabaseGrantExpired DatabaseGrantStatus = "EXPIRED"
+)
+
+// DatabaseGrantMaxDuration is the longest duration of a grant.
+const DatabaseGrantMaxDuration = 7 * 24 * time.Hour
+
+// DatabaseGrant is the API message for the temporary elevated access to a database, a.k.a. break-glass access.
+type DatabaseGrant struct {
+	ID int `json:"id"`
+
+	// Standard fields
+	CreatorID int   `json:"creatorId"`
+	CreatedTs int64 `json:"createdTs"`
+	UpdaterID int   `json:"updaterId"`
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Domain specific fields
+	DatabaseID      int                 `json:"databaseId"`
+	Level           DatabaseGrantLevel  `json:"level"`
+	Status          DatabaseGrantStatus `json:"status"`
+	DurationSeconds int64               `json:"durationSeconds"`
+	Justification   string              `json:"justification"`
+	// ExpireTs is 0 before the grant is approved.
+	ExpireTs int64 `json:"expireTs"`
+}
+
+// DatabaseGrantCreate is the API message for requesting the temporary access to a database.
+type DatabaseGrantCreate struct {
+	DatabaseID      int                `json:"databaseId"`
+	Level           DatabaseGrantLevel `json:"level"`
+	DurationSeconds int64              `json:"durationSeconds"`
+	Justification   string             `json:"justification"`
+}
+
+// Validate validates the request.
+func (create *DatabaseGrantCreate) Validate() error {
+	if create.Level != DatabaseGrantQuerier && create.Level != DatabaseGrantAdmin {
+		return errors.Errorf("invalid grant level %q", create.Level)
+	}
+	if create.DurationSeconds <= 0 || create.DurationSeconds > int64(DatabaseGrantMaxDuration/time.Second) {
+		return errors.Errorf("grant duration must be between 1 second and %s", DatabaseGrantMaxDuration)
+	}
+	if create.Justification == "" {
+		return errors.Errorf("justification is required")
+	}
+	return nil
+}
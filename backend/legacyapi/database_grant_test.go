@@ -0,0 +1,29 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseGrantCreateValidate(t *testing.T) {
+	tests := []struct {
+		create  DatabaseGrantCreate
+		wantErr bool
+	}{
+		{DatabaseGrantCreate{DatabaseID: 101, Level: DatabaseGrantQuerier, DurationSeconds: 3600, Justification: "INC-42"}, false},
+		{DatabaseGrantCreate{DatabaseID: 101, Level: DatabaseGrantAdmin, DurationSeconds: 7 * 24 * 3600, Justification: "INC-42"}, false},
+		{DatabaseGrantCreate{DatabaseID: 101, Level: "OWNER", DurationSeconds: 3600, Justification: "INC-42"}, true},
+		{DatabaseGrantCreate{DatabaseID: 101, Level: DatabaseGrantAdmin, DurationSeconds: 0, Justification: "INC-42"}, true},
+		{DatabaseGrantCreate{DatabaseID: 101, Level: DatabaseGrantAdmin, DurationSeconds: 7*24*3600 + 1, Justification: "INC-42"}, true},
+		{DatabaseGrantCreate{DatabaseID: 101, Level: DatabaseGrantQuerier, DurationSeconds: 3600}, true},
+	}
+	for _, test := range tests {
+		err := test.create.Validate()
+		if test.wantErr {
+			require.Error(t, err, test.create)
+		} else {
+			require.NoError(t, err, test.create)
+		}
+	}
+}
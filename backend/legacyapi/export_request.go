@@ -0,0 +1,85 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ExportRequestStatus is the status of the approval of a query result export.
+type ExportRequestStatus string
+
+const (
+	// ExportRequestPending is the status of the requests waiting for the approval.
+	ExportRequestPending ExportRequestStatus = "PENDING"
+	// ExportRequestApproved is the status of the approved requests before they're used.
+	ExportRequestApproved ExportRequestStatus = "APPROVED"
+	// ExportRequestRejected is the status of the rejected requests.
+	ExportRequestRejected ExportRequestStatus = "REJECTED"
+	// ExportRequestUsed is the status of the approved requests after the export, each request allows one export.
+	ExportRequestUsed ExportRequestStatus = "USED"
+)
+
+// ExportRequestValidDuration is how long an approved request can be used for the export.
+const ExportRequestValidDuration = 24 * time.Hour
+
+// ExportRequest is the API message for the approval of exporting the query result of a database, which is required
+// by the export policy for the large or the sensitive results.
+type ExportRequest struct {
+	ID int `json:"id"`
+
+	// Standard fields
+	CreatorID int   `json:"creatorId"`
+	CreatedTs int64 `json:"createdTs"`
+	UpdaterID int   `json:"updaterId"`
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Domain specific fields
+	DatabaseID int                 `json:"databaseId"`
+	Status     ExportRequestStatus `json:"status"`
+	// RowCount is the most rows allowed to export.
+	RowCount int `json:"rowCount"`
+	// ColumnNameList is the columns of the approved result.
+	ColumnNameList []string `json:"columnNameList"`
+	Justification  string   `json:"justification"`
+	// ExpireTs is 0 before the request is approved.
+	ExpireTs int64 `json:"expireTs"`
+}
+
+// ExportRequestCreate is the API message for requesting the approval of exporting a query result.
+type ExportRequestCreate struct {
+	DatabaseID     int      `json:"databaseId"`
+	RowCount       int      `json:"rowCount"`
+	ColumnNameList []string `json:"columnNameList"`
+	Justification  string   `json:"justification"`
+}
+
+// Validate validates the request.
+func (create *ExportRequestCreate) Validate() error {
+	if create.RowCount <= 0 {
+		return errors.Errorf("row count must be positive")
+	}
+	if len(create.ColumnNameList) == 0 {
+		return errors.Errorf("column names are required")
+	}
+	if create.Justification == "" {
+		return errors.Errorf("justification is required")
+	}
+	return nil
+}
+
+// Allows returns true if the approved request allows exporting the rows of the columns at the time.
+func (r *ExportRequest) Allows(rowCount int, columnNameList []string, now time.Time) bool {
+	if r.Status != ExportRequestApproved || r.ExpireTs <= now.Unix() || rowCount > r.RowCount {
+		return false
+	}
+	if len(columnNameList) != len(r.ColumnNameList) {
+		return false
+	}
+	for i, name := range columnNameList {
+		if name != r.ColumnNameList[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportRequestAllows(t *testing.T) {
+	now := time.Unix(1697000000, 0)
+	request := &ExportRequest{
+		Status:         ExportRequestApproved,
+		RowCount:       1000,
+		ColumnNameList: []string{"id", "email"},
+		ExpireTs:       now.Unix() + 3600,
+	}
+	require.True(t, request.Allows(1000, []string{"id", "email"}, now))
+	require.False(t, request.Allows(1001, []string{"id", "email"}, now))
+	require.False(t, request.Allows(10, []string{"id", "email", "phone"}, now))
+	require.False(t, request.Allows(10, []string{"email", "id"}, now))
+	require.False(t, request.Allows(10, []string{"id", "email"}, now.Add(time.Hour)))
+	request.Status = ExportRequestUsed
+	require.False(t, request.Allows(10, []string{"id", "email"}, now))
+}
+
+func TestExportPolicyNeedApproval(t *testing.T) {
+	policy := &ExportPolicy{Watermark: ExportWatermarkNone}
+	require.False(t, policy.NeedApproval(1000000, true))
+	policy.ApprovalRowCount = 100
+	require.False(t, policy.NeedApproval(100, true))
+	require.True(t, policy.NeedApproval(101, false))
+	policy.ApprovalForSensitiveData = true
+	require.True(t, policy.NeedApproval(1, true))
+	require.False(t, policy.NeedApproval(1, false))
+}
@@ -63,6 +63,29 @@ type InstanceCreate struct {
 	SSHUser       string `json:"sshUser" jsonapi:"attr,sshUser"`
 	SSHPassword   string `json:"sshPassword" jsonapi:"attr,sshPassword"`
 	SSHPrivateKey string `json:"sshPrivateKey" jsonapi:"attr,sshPrivateKey"`
+	// Proxy configuration.
+	SSHJumpHostList []SSHJumpHost `json:"sshJumpHostList" jsonapi:"attr,sshJumpHostList"`
+	SOCKS5Proxy     *SOCKS5Proxy  `json:"socks5Proxy" jsonapi:"attr,socks5Proxy"`
+	// Authentication configuration.
+	AuthenticationType db.AuthenticationType `json:"authenticationType" jsonapi:"attr,authenticationType"`
+	AWSRegion          string                `json:"awsRegion" jsonapi:"attr,awsRegion"`
+	AWSAccessKeyID     string                `json:"awsAccessKeyId" jsonapi:"attr,awsAccessKeyId"`
+	AWSSecretAccessKey string                `json:"awsSecretAccessKey" jsonapi:"attr,awsSecretAccessKey"`
+	// GCP Cloud SQL connector.
+	GCPCloudSQLConnectionName string `json:"gcpCloudSqlConnectionName" jsonapi:"attr,gcpCloudSqlConnectionName"`
+	GCPCloudSQLUsePrivateIP   bool   `json:"gcpCloudSqlUsePrivateIp" jsonapi:"attr,gcpCloudSqlUsePrivateIp"`
+	GCPCredentialsJSON        string `json:"gcpCredentialsJson" jsonapi:"attr,gcpCredentialsJson"`
+	// Azure AD.
+	AzureTenantID     string `json:"azureTenantId" jsonapi:"attr,azureTenantId"`
+	AzureClientID     string `json:"azureClientId" jsonapi:"attr,azureClientId"`
+	AzureClientSecret string `json:"azureClientSecret" jsonapi:"attr,azureClientSecret"`
+	// Kerberos.
+	KerberosRealm  string `json:"kerberosRealm" jsonapi:"attr,kerberosRealm"`
+	KerberosKDC    string `json:"kerberosKdc" jsonapi:"attr,kerberosKdc"`
+	KerberosSPN    string `json:"kerberosSpn" jsonapi:"attr,kerberosSpn"`
+	KerberosKeytab string `json:"kerberosKeytab" jsonapi:"attr,kerberosKeytab"`
+	// External secret manager.
+	ExternalSecretReference string `json:"externalSecretReference" jsonapi:"attr,externalSecretReference"`
 }
 
 // InstanceFind is the API message for finding instances.
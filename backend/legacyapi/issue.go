@@ -167,6 +167,20 @@ type MigrationDetail struct {
 	RollbackEnabled bool `json:"rollbackEnabled"`
 	// if RollbackDetail is not nil, then this task is for rolling back another task.
 	RollbackDetail *RollbackDetail `json:"rollbackDetail"`
+	// ZeroDowntime executes the PostgreSQL schema update in the zero-downtime steps if it's not nil.
+	ZeroDowntime *ZeroDowntimeMigrationConfig `json:"zeroDowntime"`
+	// Chunked executes the UPDATE and DELETE statements of the data update in the primary key range chunks if it's not nil.
+	Chunked *ChunkedDMLConfig `json:"chunked"`
+	// Seed syncs the table contents with the seed dataset in the data update if it's not nil.
+	Seed *SeedDataConfig `json:"seed"`
+	// ShadowDryRun applies the migration to a shadow database before the rollout if it's not nil.
+	ShadowDryRun *ShadowDryRunConfig `json:"shadowDryRun"`
+	// Resumable executes the statements one by one, so the retries of the failed task skip the executed statements.
+	Resumable bool `json:"resumable"`
+	// Hooks run before and after the migration of each task.
+	Hooks []*MigrationHook `json:"hooks"`
+	// Assertions run after the migration of each task, and hold the rollout of the next stage if any fails.
+	Assertions []*MigrationAssertion `json:"assertions"`
 }
 
 // MigrationContext is the issue create context for database migration such as Migrate, Data.
@@ -176,6 +190,26 @@ type MigrationContext struct {
 	DetailList []*MigrationDetail `json:"detailList"`
 	// VCSPushEvent is the event information for VCS push.
 	VCSPushEvent *vcs.PushEvent `json:"vcsPushEvent"`
+	// OnlineMigrationConfig overrides the online migration policy of the environments for the gh-ost issue.
+	OnlineMigrationConfig *OnlineMigrationConfig `json:"onlineMigrationConfig"`
+	// RolloutStrategy controls the rollout of the migration to the tenant databases.
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy"`
+	// ManualRollout keeps the tasks waiting for the manual rollout even if the environments roll out automatically.
+	ManualRollout bool `json:"manualRollout"`
+}
+
+// RolloutStrategy is the strategy to roll out a migration to the tenant databases of a project.
+// The zero values mean no canary, unlimited parallelism and never stopping on failures.
+type RolloutStrategy struct {
+	// CanaryPercentage is the percentage of the databases in each deployment rolled out first as the canaries.
+	CanaryPercentage int `json:"canaryPercentage,omitempty"`
+	// CanaryDatabaseIDList is the databases rolled out first as the canaries in addition to the percentage.
+	CanaryDatabaseIDList []int `json:"canaryDatabaseIdList,omitempty"`
+	// MaxParallelism is the maximum number of the tasks running at the same time in the pipeline.
+	MaxParallelism int `json:"maxParallelism,omitempty"`
+	// StopOnFailureCount stops scheduling the pending tasks of the pipeline once this number of tasks failed.
+	// The failed tasks can be retried or skipped to resume the rollout.
+	StopOnFailureCount int `json:"stopOnFailureCount,omitempty"`
 }
 
 // MigrationFileYAMLDatabase contains the information of a database in a YAML
@@ -0,0 +1,44 @@
+package api
+
+// MaskingBundleVersion is the version of the masking bundle format.
+const MaskingBundleVersion = 1
+
+// MaskingBundle is the versionable bundle of the masking configuration of the workspace, which is exported and
+// imported to promote the configuration across the workspaces, e.g. from the staging Bytebase to the production one.
+// The databases are referred by the instance resource IDs and the database names, as the IDs differ across the
+// workspaces. The masking secret is never exported.
+type MaskingBundle struct {
+	Version int `json:"version"`
+	// SensitiveDataPolicies are the sensitive data policies of the databases, which replace the ones of the same
+	// databases on import.
+	SensitiveDataPolicies []*MaskingBundleSensitiveDataPolicy `json:"sensitiveDataPolicies"`
+	// DismissedPIIFindings are the columns reviewed not to contain the personal data of the classifications. The
+	// accepted findings are in the sensitive data policies already.
+	DismissedPIIFindings []*MaskingBundlePIIFinding `json:"dismissedPiiFindings"`
+}
+
+// MaskingBundleSensitiveDataPolicy is the sensitive data policy of a database in the masking bundle.
+type MaskingBundleSensitiveDataPolicy struct {
+	Instance          string          `json:"instance"`
+	Database          string          `json:"database"`
+	SensitiveDataList []SensitiveData `json:"sensitiveDataList"`
+}
+
+// MaskingBundlePIIFinding is a reviewed PII finding in the masking bundle.
+type MaskingBundlePIIFinding struct {
+	Instance       string            `json:"instance"`
+	Database       string            `json:"database"`
+	Schema         string            `json:"schema"`
+	Table          string            `json:"table"`
+	Column         string            `json:"column"`
+	Classification PIIClassification `json:"classification"`
+}
+
+// MaskingBundleImportResult is the result of importing a masking bundle. The databases and the PII findings not
+// found in the workspace are skipped, so the bundle can be imported before they're all synced or scanned.
+type MaskingBundleImportResult struct {
+	UpdatedPolicyCount       int      `json:"updatedPolicyCount"`
+	SkippedDatabaseList      []string `json:"skippedDatabaseList"`
+	DismissedPIIFindingCount int      `json:"dismissedPiiFindingCount"`
+	SkippedPIIFindingCount   int      `json:"skippedPiiFindingCount"`
+}
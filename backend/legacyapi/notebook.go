@@ -0,0 +1,60 @@
+package api
+
+// MaxNotebookCellRowCount is the maximum number of the rows in the persisted result of a notebook cell.
+const MaxNotebookCellRowCount = 1000
+
+// SheetNotebookPayload is the payload of the notebook sheet.
+type SheetNotebookPayload struct {
+	CellList      []*NotebookCell      `json:"cellList"`
+	ParameterList []*NotebookParameter `json:"parameterList"`
+}
+
+// NotebookCell is a cell of the notebook sheet.
+type NotebookCell struct {
+	// ID is unique in the notebook, and identifies the persisted result of the cell.
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// Statement refers the parameters by "{{name}}".
+	Statement string `json:"statement"`
+}
+
+// NotebookParameterType is the type of the notebook parameter.
+type NotebookParameterType string
+
+const (
+	// NotebookParameterText is the parameter rendered as a string literal.
+	NotebookParameterText NotebookParameterType = "TEXT"
+	// NotebookParameterNumber is the parameter rendered as a number literal.
+	NotebookParameterNumber NotebookParameterType = "NUMBER"
+)
+
+// NotebookParameter is a named parameter of the notebook sheet prompted at run time.
+type NotebookParameter struct {
+	Name         string                `json:"name"`
+	Type         NotebookParameterType `json:"type"`
+	DefaultValue string                `json:"defaultValue"`
+	Description  string                `json:"description"`
+}
+
+// NotebookCellRun is the API message for running a cell of the notebook sheet.
+type NotebookCellRun struct {
+	CellID string `json:"cellId"`
+	// ParameterValues is the values of the parameters, the default values are used for the missing ones.
+	ParameterValues map[string]string `json:"parameterValues"`
+}
+
+// NotebookCellResult is the API message for the persisted result of the latest run of a notebook cell.
+type NotebookCellResult struct {
+	SheetID         int               `json:"sheetId"`
+	CellID          string            `json:"cellId"`
+	CreatorID       int               `json:"creatorId"`
+	CreatedTs       int64             `json:"createdTs"`
+	ParameterValues map[string]string `json:"parameterValues"`
+	ColumnNames     []string          `json:"columnNames"`
+	ColumnTypeNames []string          `json:"columnTypeNames"`
+	Rows            [][]any           `json:"rows"`
+	// Truncated is true if the rows beyond the MaxNotebookCellRowCount are dropped.
+	Truncated bool `json:"truncated"`
+	// Error is the error of the run, the result is empty if it's set.
+	Error string `json:"error"`
+}
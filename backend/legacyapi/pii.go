@@ -0,0 +1,52 @@
+package api
+
+// PIIClassification is the classification of the personal data detected in a column.
+type PIIClassification string
+
+const (
+	// PIIClassificationEmail is the email addresses.
+	PIIClassificationEmail PIIClassification = "EMAIL"
+	// PIIClassificationPhone is the phone numbers.
+	PIIClassificationPhone PIIClassification = "PHONE"
+	// PIIClassificationSSN is the US social security numbers.
+	PIIClassificationSSN PIIClassification = "SSN"
+	// PIIClassificationCreditCard is the payment card numbers passing the Luhn check.
+	PIIClassificationCreditCard PIIClassification = "CREDIT_CARD"
+)
+
+// PIIFindingStatus is the status of a PII finding.
+type PIIFindingStatus string
+
+const (
+	// PIIFindingProposed is the status of the finding proposed for review.
+	PIIFindingProposed PIIFindingStatus = "PROPOSED"
+	// PIIFindingAccepted is the status of the finding added to the sensitive data policy of the database.
+	PIIFindingAccepted PIIFindingStatus = "ACCEPTED"
+	// PIIFindingDismissed is the status of the finding dismissed by the user, which isn't proposed again unless the
+	// classification changes.
+	PIIFindingDismissed PIIFindingStatus = "DISMISSED"
+)
+
+// PIIFinding is the API message for a column detected to contain the personal data.
+type PIIFinding struct {
+	ID             int               `json:"id"`
+	DatabaseID     int               `json:"databaseId"`
+	SchemaName     string            `json:"schemaName"`
+	TableName      string            `json:"tableName"`
+	ColumnName     string            `json:"columnName"`
+	Classification PIIClassification `json:"classification"`
+	// MaskType is the mask type proposed for the sensitive data policy.
+	MaskType SensitiveDataMaskType `json:"maskType"`
+	// NameMatched is true if the column name suggests the classification.
+	NameMatched bool `json:"nameMatched"`
+	// SampleCount is the number of the non-empty values sampled, and MatchCount is the number matching the classification.
+	SampleCount   int              `json:"sampleCount"`
+	MatchCount    int              `json:"matchCount"`
+	Status        PIIFindingStatus `json:"status"`
+	LastScannedTs int64            `json:"lastScannedTs"`
+}
+
+// PIIFindingPatch is the API message to review a PII finding.
+type PIIFindingPatch struct {
+	Status PIIFindingStatus `json:"status"`
+}
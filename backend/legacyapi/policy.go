@@ -2,10 +2,13 @@ package api
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/autoapproval"
+	"github.com/bytebase/bytebase/backend/plugin/masker"
 )
 
 // PolicyType is the type or name of a policy.
@@ -36,6 +39,8 @@ const (
 	PolicyTypeBackupPlan PolicyType = "bb.policy.backup-plan"
 	// PolicyTypeSQLReview is the sql review policy type.
 	PolicyTypeSQLReview PolicyType = "bb.policy.sql-review"
+	// PolicyTypeSQLReviewOverride is the project level override policy type for the sql review policy.
+	PolicyTypeSQLReviewOverride PolicyType = "bb.policy.sql-review-override"
 	// PolicyTypeEnvironmentTier is the tier of an environment.
 	PolicyTypeEnvironmentTier PolicyType = "bb.policy.environment-tier"
 	// PolicyTypeSensitiveData is the sensitive data policy type.
@@ -44,6 +49,20 @@ const (
 	PolicyTypeAccessControl PolicyType = "bb.policy.access-control"
 	// PolicyTypeSlowQuery is the slow query policy type.
 	PolicyTypeSlowQuery PolicyType = "bb.policy.slow-query"
+	// PolicyTypeAffectedRowsApproval is the affected rows approval policy type.
+	PolicyTypeAffectedRowsApproval PolicyType = "bb.policy.affected-rows-approval"
+	// PolicyTypeOnlineMigration is the online migration policy type.
+	PolicyTypeOnlineMigration PolicyType = "bb.policy.online-migration"
+	// PolicyTypePartitionRetention is the partition retention policy type.
+	PolicyTypePartitionRetention PolicyType = "bb.policy.partition-retention"
+	// PolicyTypeQueryLimit is the query limit policy type.
+	PolicyTypeQueryLimit PolicyType = "bb.policy.query-limit"
+	// PolicyTypeAutoApproval is the auto-approval policy type.
+	PolicyTypeAutoApproval PolicyType = "bb.policy.auto-approval"
+	// PolicyTypeExport is the query result export policy type.
+	PolicyTypeExport PolicyType = "bb.policy.export"
+	// PolicyTypeDLP is the data loss prevention policy type.
+	PolicyTypeDLP PolicyType = "bb.policy.dlp"
 
 	// PipelineApprovalValueManualNever means the pipeline will automatically be approved without user intervention.
 	PipelineApprovalValueManualNever PipelineApprovalValue = "MANUAL_APPROVAL_NEVER"
@@ -84,13 +103,21 @@ const (
 var (
 	// allowedResourceTypes includes allowed resource types for each policy type.
 	allowedResourceTypes = map[PolicyType][]PolicyResourceType{
-		PolicyTypePipelineApproval: {PolicyResourceTypeEnvironment},
-		PolicyTypeBackupPlan:       {PolicyResourceTypeEnvironment},
-		PolicyTypeSQLReview:        {PolicyResourceTypeEnvironment},
-		PolicyTypeEnvironmentTier:  {PolicyResourceTypeEnvironment},
-		PolicyTypeSensitiveData:    {PolicyResourceTypeDatabase},
-		PolicyTypeAccessControl:    {PolicyResourceTypeEnvironment, PolicyResourceTypeDatabase},
-		PolicyTypeSlowQuery:        {PolicyResourceTypeInstance},
+		PolicyTypePipelineApproval:     {PolicyResourceTypeEnvironment},
+		PolicyTypeBackupPlan:           {PolicyResourceTypeEnvironment},
+		PolicyTypeSQLReview:            {PolicyResourceTypeEnvironment},
+		PolicyTypeSQLReviewOverride:    {PolicyResourceTypeProject},
+		PolicyTypeEnvironmentTier:      {PolicyResourceTypeEnvironment},
+		PolicyTypeSensitiveData:        {PolicyResourceTypeDatabase},
+		PolicyTypeAccessControl:        {PolicyResourceTypeEnvironment, PolicyResourceTypeDatabase},
+		PolicyTypeSlowQuery:            {PolicyResourceTypeInstance},
+		PolicyTypeAffectedRowsApproval: {PolicyResourceTypeEnvironment},
+		PolicyTypeOnlineMigration:      {PolicyResourceTypeEnvironment},
+		PolicyTypePartitionRetention:   {PolicyResourceTypeDatabase},
+		PolicyTypeQueryLimit:           {PolicyResourceTypeEnvironment},
+		PolicyTypeAutoApproval:         {PolicyResourceTypeWorkspace},
+		PolicyTypeExport:               {PolicyResourceTypeWorkspace},
+		PolicyTypeDLP:                  {PolicyResourceTypeWorkspace},
 	}
 )
 
@@ -205,6 +232,32 @@ func UnmarshalSQLReviewPolicy(payload string) (*advisor.SQLReviewPolicy, error)
 	return &sr, nil
 }
 
+// SQLReviewOverridePolicy is the project level override for the SQL review policy of the environments.
+type SQLReviewOverridePolicy struct {
+	// PinnedVersion is the version of the rule set used by the project, zero means the latest version.
+	PinnedVersion int `json:"pinnedVersion"`
+	// RuleList is the rules overriding the level or payload of the environment rules with the same type.
+	RuleList []*advisor.SQLReviewRule `json:"ruleList"`
+}
+
+// UnmarshalSQLReviewOverridePolicy will unmarshal payload to SQL review override policy.
+func UnmarshalSQLReviewOverridePolicy(payload string) (*SQLReviewOverridePolicy, error) {
+	var p SQLReviewOverridePolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal SQL review override policy %q", payload)
+	}
+	return &p, nil
+}
+
+// String will return the string representation of the policy.
+func (p *SQLReviewOverridePolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
 // EnvironmentTierPolicy is the tier of an environment.
 type EnvironmentTierPolicy struct {
 	EnvironmentTier EnvironmentTierValue `json:"environmentTier"`
@@ -230,6 +283,9 @@ type SensitiveData struct {
 	Table  string                `json:"table"`
 	Column string                `json:"column"`
 	Type   SensitiveDataMaskType `json:"maskType"`
+	// Condition is the CEL expression over the row and the requester deciding whether to mask the value, e.g.
+	// `row.country == "DE"`. Empty means always masked.
+	Condition string `json:"condition,omitempty"`
 }
 
 // SensitiveDataMaskType is the mask type for sensitive data.
@@ -239,6 +295,16 @@ const (
 	// SensitiveDataMaskTypeDefault is the sensitive data type to hide data with a default method.
 	// The default method is subject to change.
 	SensitiveDataMaskTypeDefault SensitiveDataMaskType = "DEFAULT"
+	// SensitiveDataMaskTypeSHA256 is the sensitive data type to hide data with the hex of the salted SHA-256.
+	SensitiveDataMaskTypeSHA256 SensitiveDataMaskType = "SHA256"
+	// SensitiveDataMaskTypeHMAC is the sensitive data type to hide data with the hex of the HMAC-SHA256.
+	SensitiveDataMaskTypeHMAC SensitiveDataMaskType = "HMAC"
+	// SensitiveDataMaskTypeFF1 is the sensitive data type to encrypt the digits and letters of data with the
+	// format-preserving encryption FF1.
+	SensitiveDataMaskTypeFF1 SensitiveDataMaskType = "FF1"
+	// SensitiveDataMaskTypeFF3 is the sensitive data type to encrypt the digits and letters of data with the
+	// format-preserving encryption FF3-1.
+	SensitiveDataMaskTypeFF3 SensitiveDataMaskType = "FF3"
 )
 
 // UnmarshalSensitiveDataPolicy will unmarshal payload to sensitive data policy.
@@ -313,6 +379,340 @@ func (p *SlowQueryPolicy) String() (string, error) {
 	return string(s), nil
 }
 
+// AffectedRowsApprovalPolicy is the policy configuration for escalating the risk level by the estimated affected rows.
+// It is only applicable to environment resource type.
+type AffectedRowsApprovalPolicy struct {
+	// MaxAffectedRows is the maximum estimated rows affected by a task, zero means no limit.
+	MaxAffectedRows int64 `json:"maxAffectedRows"`
+	// RiskLevel is the risk level of the task whose estimated affected rows exceed MaxAffectedRows.
+	RiskLevel int64 `json:"riskLevel"`
+}
+
+const (
+	// RiskLevelLow is the preset LOW risk level.
+	RiskLevelLow int64 = 100
+	// RiskLevelModerate is the preset MODERATE risk level.
+	RiskLevelModerate int64 = 200
+	// RiskLevelHigh is the preset HIGH risk level.
+	RiskLevelHigh int64 = 300
+)
+
+// UnmarshalAffectedRowsApprovalPolicy will unmarshal payload to affected rows approval policy.
+func UnmarshalAffectedRowsApprovalPolicy(payload string) (*AffectedRowsApprovalPolicy, error) {
+	var p AffectedRowsApprovalPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal affected rows approval policy %q", payload)
+	}
+	return &p, nil
+}
+
+// String will return the string representation of the policy.
+func (p *AffectedRowsApprovalPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// OnlineMigrationPolicy is the policy configuration for the default online migration engine and throttling of an environment.
+// It is only applicable to environment resource type.
+type OnlineMigrationPolicy struct {
+	OnlineMigrationConfig
+}
+
+// UnmarshalOnlineMigrationPolicy will unmarshal payload to online migration policy.
+func UnmarshalOnlineMigrationPolicy(payload string) (*OnlineMigrationPolicy, error) {
+	var p OnlineMigrationPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal online migration policy %q", payload)
+	}
+	return &p, nil
+}
+
+// String will return the string representation of the policy.
+func (p *OnlineMigrationPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// PartitionInterval is the interval of the rolling range partitions.
+type PartitionInterval string
+
+const (
+	// PartitionIntervalDay is the daily partition interval, the partitions are named as pYYYYMMDD.
+	PartitionIntervalDay PartitionInterval = "DAY"
+	// PartitionIntervalMonth is the monthly partition interval, the partitions are named as pYYYYMM.
+	PartitionIntervalMonth PartitionInterval = "MONTH"
+)
+
+// PartitionExpireAction is the action on the expired partitions.
+type PartitionExpireAction string
+
+const (
+	// PartitionExpireActionDrop drops the expired partitions.
+	PartitionExpireActionDrop PartitionExpireAction = "DROP"
+	// PartitionExpireActionArchive moves the expired partitions out of the table into the standalone tables.
+	PartitionExpireActionArchive PartitionExpireAction = "ARCHIVE"
+)
+
+// PartitionRetentionPolicy is the policy configuration for the rolling range partitions of the tables in a database.
+// It is only applicable to database resource type.
+type PartitionRetentionPolicy struct {
+	RuleList []*PartitionRetentionRule `json:"ruleList"`
+}
+
+// PartitionRetentionRule is the retention rule of a table partitioned by the range of a date or time column.
+// Only the partitions following the naming convention of the interval are maintained.
+type PartitionRetentionRule struct {
+	// Schema is the schema of the table, it's only used by PostgreSQL.
+	Schema   string            `json:"schema"`
+	Table    string            `json:"table"`
+	Interval PartitionInterval `json:"interval"`
+	// PrecreateCount is the number of the future partitions created ahead of the current one.
+	PrecreateCount int `json:"precreateCount"`
+	// RetentionCount is the number of the past partitions retained before the current one.
+	// The older partitions expire, and zero means the partitions never expire.
+	RetentionCount int                   `json:"retentionCount"`
+	ExpireAction   PartitionExpireAction `json:"expireAction"`
+}
+
+// UnmarshalPartitionRetentionPolicy will unmarshal payload to partition retention policy.
+func UnmarshalPartitionRetentionPolicy(payload string) (*PartitionRetentionPolicy, error) {
+	var p PartitionRetentionPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal partition retention policy %q", payload)
+	}
+	return &p, nil
+}
+
+// String will return the string representation of the policy.
+func (p *PartitionRetentionPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// QueryLimitPolicy is the policy configuration for the limits of the queries in the SQL editor by project roles.
+// It is only applicable to environment resource type.
+type QueryLimitPolicy struct {
+	RuleList []*QueryLimitRule `json:"ruleList"`
+}
+
+// QueryLimitRule is the limits of the queries run by the members of a project role, the workspace Owners and DBAs have
+// the project Owner role. Zero means no limit.
+type QueryLimitRule struct {
+	// Role is the project role, including the custom ones.
+	Role                Role `json:"role"`
+	MaxExecutionSeconds int  `json:"maxExecutionSeconds"`
+	MaxRowCount         int  `json:"maxRowCount"`
+}
+
+// UnmarshalQueryLimitPolicy will unmarshal payload to query limit policy.
+func UnmarshalQueryLimitPolicy(payload string) (*QueryLimitPolicy, error) {
+	var p QueryLimitPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal query limit policy %q", payload)
+	}
+	return &p, nil
+}
+
+// String will return the string representation of the policy.
+func (p *QueryLimitPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// AutoApprovalPolicy is the policy configuration for approving the issues without the approvers.
+// It is only applicable to workspace resource type.
+type AutoApprovalPolicy struct {
+	RuleList []*AutoApprovalRule `json:"ruleList"`
+}
+
+// AutoApprovalRule approves an issue if the condition is true for every database changed by the issue.
+type AutoApprovalRule struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// Condition is the CEL expression over the requester, environment, database labels and risk level, e.g.
+	// `risk_level <= 100 && environment_id == "test"`.
+	Condition string `json:"condition"`
+}
+
+// UnmarshalAutoApprovalPolicy will unmarshal payload to auto-approval policy.
+func UnmarshalAutoApprovalPolicy(payload string) (*AutoApprovalPolicy, error) {
+	var p AutoApprovalPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal auto-approval policy %q", payload)
+	}
+	return &p, nil
+}
+
+// String will return the string representation of the policy.
+func (p *AutoApprovalPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// ExportWatermarkType is the type of the watermark of the exported query results.
+type ExportWatermarkType string
+
+const (
+	// ExportWatermarkNone exports the query results without the watermark.
+	ExportWatermarkNone ExportWatermarkType = "NONE"
+	// ExportWatermarkMetadata writes the export ID, the requester and the timestamp to the file metadata.
+	ExportWatermarkMetadata ExportWatermarkType = "METADATA"
+	// ExportWatermarkColumn appends the watermark column to the rows besides the file metadata.
+	ExportWatermarkColumn ExportWatermarkType = "COLUMN"
+)
+
+// ExportPolicy is the policy configuration for exporting the query results in the SQL editor.
+// It is only applicable to workspace resource type.
+type ExportPolicy struct {
+	Watermark ExportWatermarkType `json:"watermark"`
+	// ApprovalRowCount requires the export approval for the results with more rows, 0 means no limit.
+	ApprovalRowCount int `json:"approvalRowCount"`
+	// ApprovalForSensitiveData requires the export approval for the results with the sensitive columns.
+	ApprovalForSensitiveData bool `json:"approvalForSensitiveData"`
+}
+
+// NeedApproval returns true if exporting the result requires the export approval.
+func (p *ExportPolicy) NeedApproval(rowCount int, sensitive bool) bool {
+	if p.ApprovalRowCount > 0 && rowCount > p.ApprovalRowCount {
+		return true
+	}
+	return p.ApprovalForSensitiveData && sensitive
+}
+
+// UnmarshalExportPolicy will unmarshal payload to export policy.
+func UnmarshalExportPolicy(payload string) (*ExportPolicy, error) {
+	var p ExportPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal export policy %q", payload)
+	}
+	return &p, nil
+}
+
+// String will return the string representation of the policy.
+func (p *ExportPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// DLPAction is the action on the exported columns classified above the clearance of the requester.
+type DLPAction string
+
+const (
+	// DLPActionBlock refuses the export.
+	DLPActionBlock DLPAction = "BLOCK"
+	// DLPActionRedact exports the restricted columns with the null values.
+	DLPActionRedact DLPAction = "REDACT"
+)
+
+// DLPPolicy is the data loss prevention policy configuration for exporting the query results, which restricts the
+// result columns derived from the columns classified above the clearance of the requester.
+// It is only applicable to workspace resource type.
+type DLPPolicy struct {
+	Action DLPAction `json:"action"`
+	// ClassificationList classifies the columns with the PII findings, the dismissed findings are ignored.
+	ClassificationList []*DLPClassification `json:"classificationList"`
+	// ColumnList classifies the columns explicitly, which overrides the level of the PII findings.
+	ColumnList []*DLPColumn `json:"columnList"`
+	// ClearanceList is the clearances of the requesters, the requesters without any clearance have the level 0.
+	ClearanceList []*DLPClearance `json:"clearanceList"`
+}
+
+// DLPClassification is the classification level of the columns of the PII findings of the classification.
+type DLPClassification struct {
+	Classification PIIClassification `json:"classification"`
+	Level          int               `json:"level"`
+}
+
+// DLPColumn is the classification level of a column.
+type DLPColumn struct {
+	DatabaseID int `json:"databaseId"`
+	// Schema is empty for the engines without schema, such as MySQL.
+	Schema string `json:"schema"`
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Level  int    `json:"level"`
+}
+
+// DLPClearance is the clearance level of the requesters.
+type DLPClearance struct {
+	// Member is either roles/{workspace role} or users/{email}.
+	Member string `json:"member"`
+	Level  int    `json:"level"`
+}
+
+// GetClearance returns the highest clearance level of the requester.
+func (p *DLPPolicy) GetClearance(email string, role Role) int {
+	clearance := 0
+	for _, c := range p.ClearanceList {
+		if (c.Member == ApprovalNodeRolePrefix+string(role) || c.Member == ApprovalNodeUserPrefix+email) && c.Level > clearance {
+			clearance = c.Level
+		}
+	}
+	return clearance
+}
+
+// GetClassificationLevel returns the level of the PII classification, it's 0 if the classification isn't restricted.
+func (p *DLPPolicy) GetClassificationLevel(classification PIIClassification) int {
+	for _, c := range p.ClassificationList {
+		if c.Classification == classification {
+			return c.Level
+		}
+	}
+	return 0
+}
+
+// GetMaxLevel returns the highest level of the classifications and the columns.
+func (p *DLPPolicy) GetMaxLevel() int {
+	level := 0
+	for _, c := range p.ClassificationList {
+		if c.Level > level {
+			level = c.Level
+		}
+	}
+	for _, c := range p.ColumnList {
+		if c.Level > level {
+			level = c.Level
+		}
+	}
+	return level
+}
+
+// UnmarshalDLPPolicy will unmarshal payload to DLP policy.
+func UnmarshalDLPPolicy(payload string) (*DLPPolicy, error) {
+	var p DLPPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal DLP policy %q", payload)
+	}
+	return &p, nil
+}
+
+// String will return the string representation of the policy.
+func (p *DLPPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
 // UnmarshalEnvironmentTierPolicy will unmarshal payload to environment tier policy.
 func UnmarshalEnvironmentTierPolicy(payload string) (*EnvironmentTierPolicy, error) {
 	var p EnvironmentTierPolicy
@@ -386,6 +786,30 @@ func ValidatePolicy(resourceType PolicyResourceType, pType PolicyType, payload *
 			return errors.Wrap(err, "invalid SQL review policy")
 		}
 		return nil
+	case PolicyTypeSQLReviewOverride:
+		p, err := UnmarshalSQLReviewOverridePolicy(*payload)
+		if err != nil {
+			return err
+		}
+		if p.PinnedVersion < 0 {
+			return errors.Errorf("invalid pinned version %d", p.PinnedVersion)
+		}
+		for _, rule := range p.RuleList {
+			if rule.Type == "" {
+				return errors.Errorf("invalid SQL review override policy, rule type cannot be empty")
+			}
+			if rule.Level != "" && rule.Level != advisor.SchemaRuleLevelError && rule.Level != advisor.SchemaRuleLevelWarning && rule.Level != advisor.SchemaRuleLevelDisabled {
+				return errors.Errorf("invalid level %q for rule %s", rule.Level, rule.Type)
+			}
+			// The empty payload means using the payload of the environment rule.
+			if rule.Payload == "" {
+				continue
+			}
+			if err := rule.Validate(); err != nil {
+				return errors.Wrap(err, "invalid SQL review override policy")
+			}
+		}
+		return nil
 	case PolicyTypeEnvironmentTier:
 		p, err := UnmarshalEnvironmentTierPolicy(*payload)
 		if err != nil {
@@ -404,8 +828,15 @@ func ValidatePolicy(resourceType PolicyResourceType, pType PolicyType, payload *
 			if v.Table == "" || v.Column == "" {
 				return errors.Errorf("sensitive data policy rule cannot have empty table or column name")
 			}
-			if v.Type != SensitiveDataMaskTypeDefault {
-				return errors.Errorf("sensitive data policy rule must have mask type %q", SensitiveDataMaskTypeDefault)
+			switch v.Type {
+			case SensitiveDataMaskTypeDefault, SensitiveDataMaskTypeSHA256, SensitiveDataMaskTypeHMAC, SensitiveDataMaskTypeFF1, SensitiveDataMaskTypeFF3:
+			default:
+				return errors.Errorf("sensitive data policy rule has invalid mask type %q", v.Type)
+			}
+			if v.Condition != "" {
+				if err := masker.ValidateCondition(v.Condition); err != nil {
+					return err
+				}
 			}
 		}
 		return nil
@@ -414,6 +845,120 @@ func ValidatePolicy(resourceType PolicyResourceType, pType PolicyType, payload *
 			return err
 		}
 		return nil
+	case PolicyTypeAffectedRowsApproval:
+		p, err := UnmarshalAffectedRowsApprovalPolicy(*payload)
+		if err != nil {
+			return err
+		}
+		if p.MaxAffectedRows < 0 {
+			return errors.Errorf("invalid max affected rows %d", p.MaxAffectedRows)
+		}
+		if p.MaxAffectedRows > 0 && p.RiskLevel != RiskLevelLow && p.RiskLevel != RiskLevelModerate && p.RiskLevel != RiskLevelHigh {
+			return errors.Errorf("invalid risk level %d", p.RiskLevel)
+		}
+		return nil
+	case PolicyTypePartitionRetention:
+		p, err := UnmarshalPartitionRetentionPolicy(*payload)
+		if err != nil {
+			return err
+		}
+		tableSeen := make(map[string]bool)
+		for _, rule := range p.RuleList {
+			if rule.Table == "" {
+				return errors.Errorf("partition retention rule cannot have empty table name")
+			}
+			key := rule.Schema + "." + rule.Table
+			if tableSeen[key] {
+				return errors.Errorf("duplicate partition retention rule for table %q", rule.Table)
+			}
+			tableSeen[key] = true
+			if rule.Interval != PartitionIntervalDay && rule.Interval != PartitionIntervalMonth {
+				return errors.Errorf("invalid partition interval %q for table %q", rule.Interval, rule.Table)
+			}
+			if rule.ExpireAction != PartitionExpireActionDrop && rule.ExpireAction != PartitionExpireActionArchive {
+				return errors.Errorf("invalid partition expire action %q for table %q", rule.ExpireAction, rule.Table)
+			}
+			if rule.PrecreateCount < 0 || rule.RetentionCount < 0 {
+				return errors.Errorf("invalid partition precreate count %d or retention count %d for table %q", rule.PrecreateCount, rule.RetentionCount, rule.Table)
+			}
+		}
+		return nil
+	case PolicyTypeQueryLimit:
+		p, err := UnmarshalQueryLimitPolicy(*payload)
+		if err != nil {
+			return err
+		}
+		roleSeen := make(map[Role]bool)
+		for _, rule := range p.RuleList {
+			if rule.Role == "" {
+				return errors.Errorf("query limit rule cannot have empty role")
+			}
+			if roleSeen[rule.Role] {
+				return errors.Errorf("duplicate query limit rule for role %q", rule.Role)
+			}
+			roleSeen[rule.Role] = true
+			if rule.MaxExecutionSeconds < 0 || rule.MaxRowCount < 0 {
+				return errors.Errorf("invalid max execution seconds %d or max row count %d for role %q", rule.MaxExecutionSeconds, rule.MaxRowCount, rule.Role)
+			}
+		}
+		return nil
+	case PolicyTypeAutoApproval:
+		p, err := UnmarshalAutoApprovalPolicy(*payload)
+		if err != nil {
+			return err
+		}
+		idSeen := make(map[string]bool)
+		for _, rule := range p.RuleList {
+			if rule.ID == "" {
+				return errors.Errorf("auto-approval rule cannot have empty ID")
+			}
+			if idSeen[rule.ID] {
+				return errors.Errorf("duplicate auto-approval rule %q", rule.ID)
+			}
+			idSeen[rule.ID] = true
+			if err := autoapproval.ValidateCondition(rule.Condition); err != nil {
+				return err
+			}
+		}
+		return nil
+	case PolicyTypeExport:
+		p, err := UnmarshalExportPolicy(*payload)
+		if err != nil {
+			return err
+		}
+		switch p.Watermark {
+		case ExportWatermarkNone, ExportWatermarkMetadata, ExportWatermarkColumn:
+		default:
+			return errors.Errorf("invalid export watermark %q", p.Watermark)
+		}
+		if p.ApprovalRowCount < 0 {
+			return errors.Errorf("invalid export approval row count %d", p.ApprovalRowCount)
+		}
+		return nil
+	case PolicyTypeDLP:
+		p, err := UnmarshalDLPPolicy(*payload)
+		if err != nil {
+			return err
+		}
+		if p.Action != DLPActionBlock && p.Action != DLPActionRedact {
+			return errors.Errorf("invalid DLP action %q", p.Action)
+		}
+		for _, c := range p.ClassificationList {
+			if c.Level < 0 {
+				return errors.Errorf("invalid level %d of classification %q", c.Level, c.Classification)
+			}
+		}
+		for _, c := range p.ColumnList {
+			if c.Level < 0 || c.DatabaseID <= 0 || c.Table == "" || c.Column == "" {
+				return errors.Errorf("invalid DLP column %d/%s/%s/%s with level %d", c.DatabaseID, c.Schema, c.Table, c.Column, c.Level)
+			}
+		}
+		for _, c := range p.ClearanceList {
+			if c.Level < 0 || (!strings.HasPrefix(c.Member, ApprovalNodeRolePrefix) && !strings.HasPrefix(c.Member, ApprovalNodeUserPrefix)) {
+				return errors.Errorf("invalid clearance %d of %q, the member must be roles/{role} or users/{email}", c.Level, c.Member)
+			}
+		}
+		return nil
 	}
 	return nil
 }
@@ -443,6 +988,34 @@ func GetDefaultPolicy(pType PolicyType) (string, error) {
 	case PolicyTypeSensitiveData:
 		policy := SensitiveDataPolicy{}
 		return policy.String()
+	case PolicyTypeAffectedRowsApproval:
+		policy := AffectedRowsApprovalPolicy{}
+		return policy.String()
+	case PolicyTypeSQLReviewOverride:
+		policy := SQLReviewOverridePolicy{}
+		return policy.String()
+	case PolicyTypeOnlineMigration:
+		policy := OnlineMigrationPolicy{}
+		return policy.String()
+	case PolicyTypePartitionRetention:
+		policy := PartitionRetentionPolicy{}
+		return policy.String()
+	case PolicyTypeQueryLimit:
+		policy := QueryLimitPolicy{}
+		return policy.String()
+	case PolicyTypeAutoApproval:
+		policy := AutoApprovalPolicy{}
+		return policy.String()
+	case PolicyTypeExport:
+		policy := ExportPolicy{
+			Watermark: ExportWatermarkNone,
+		}
+		return policy.String()
+	case PolicyTypeDLP:
+		policy := DLPPolicy{
+			Action: DLPActionBlock,
+		}
+		return policy.String()
 	}
 	return "", nil
 }
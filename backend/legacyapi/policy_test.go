@@ -0,0 +1,34 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDLPPolicyClearance(t *testing.T) {
+	policy := &DLPPolicy{
+		Action: DLPActionBlock,
+		ClassificationList: []*DLPClassification{
+			{Classification: PIIClassificationEmail, Level: 1},
+			{Classification: PIIClassificationSSN, Level: 3},
+		},
+		ClearanceList: []*DLPClearance{
+			{Member: "roles/DBA", Level: 2},
+			{Member: "users/alice@example.com", Level: 3},
+		},
+	}
+	require.Equal(t, 3, policy.GetMaxLevel())
+	require.Equal(t, 0, policy.GetClearance("bob@example.com", Developer))
+	require.Equal(t, 2, policy.GetClearance("bob@example.com", DBA))
+	require.Equal(t, 3, policy.GetClearance("alice@example.com", DBA))
+	require.Equal(t, 0, policy.GetClassificationLevel(PIIClassificationPhone))
+
+	payload, err := policy.String()
+	require.NoError(t, err)
+	require.NoError(t, ValidatePolicy(PolicyResourceTypeWorkspace, PolicyTypeDLP, &payload))
+	policy.ClearanceList[0].Member = "DBA"
+	payload, err = policy.String()
+	require.NoError(t, err)
+	require.Error(t, ValidatePolicy(PolicyResourceTypeWorkspace, PolicyTypeDLP, &payload))
+}
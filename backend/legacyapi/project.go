@@ -207,10 +207,29 @@ func ValidateRepositoryFilePathTemplate(filePathTemplate string, tenantMode Proj
 
 // ValidateRepositorySchemaPathTemplate validates the repository schema path template.
 func ValidateRepositorySchemaPathTemplate(schemaPathTemplate string, tenantMode ProjectTenantMode) error {
+	return validateSchemaPathTemplateTokens(schemaPathTemplate, "schema path template", tenantMode)
+}
+
+// ValidateRepositoryMetadataPathTemplate validates the repository metadata path template. The metadata is written back
+// with the latest schema, so the schema path template is required.
+func ValidateRepositoryMetadataPathTemplate(metadataPathTemplate, schemaPathTemplate string, tenantMode ProjectTenantMode) error {
+	if metadataPathTemplate == "" {
+		return nil
+	}
 	if schemaPathTemplate == "" {
+		return errors.Errorf("metadata path template requires the schema path template")
+	}
+	if metadataPathTemplate == schemaPathTemplate {
+		return errors.Errorf("metadata path template must be different from the schema path template")
+	}
+	return validateSchemaPathTemplateTokens(metadataPathTemplate, "metadata path template", tenantMode)
+}
+
+func validateSchemaPathTemplateTokens(template, name string, tenantMode ProjectTenantMode) error {
+	if template == "" {
 		return nil
 	}
-	tokens, _ := common.ParseTemplateTokens(schemaPathTemplate)
+	tokens, _ := common.ParseTemplateTokens(template)
 	tokenMap := make(map[string]bool)
 	for _, token := range tokens {
 		tokenMap[token] = true
@@ -224,14 +243,14 @@ func ValidateRepositorySchemaPathTemplate(schemaPathTemplate string, tenantMode
 	for token, required := range allowedTokens {
 		if required {
 			if _, ok := tokenMap[token]; !ok {
-				return errors.Errorf("missing %s in schema path template", token)
+				return errors.Errorf("missing %s in %s", token, name)
 			}
 		}
 	}
 
 	for token := range tokenMap {
 		if _, ok := allowedTokens[token]; !ok {
-			return errors.Errorf("unknown token %s in schema path template", token)
+			return errors.Errorf("unknown token %s in %s", token, name)
 		}
 	}
 	return nil
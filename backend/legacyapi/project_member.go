@@ -1,6 +1,8 @@
 package api
 
 import (
+	"github.com/pkg/errors"
+
 	"github.com/bytebase/bytebase/backend/common"
 )
 
@@ -54,8 +56,54 @@ const (
 	ProjectPermissionCreateDatabase ProjectPermissionType = "bb.permission.project.create-database"
 	// ProjectPermissionTransferDatabase allows user to transfer database out of/into the project.
 	ProjectPermissionTransferDatabase ProjectPermissionType = "bb.permission.project.transfer-database"
+	// ProjectPermissionQueryDatabase allows user to query the databases in the project in the SQL editor.
+	ProjectPermissionQueryDatabase ProjectPermissionType = "bb.permission.project.query-database"
+	// ProjectPermissionExportData allows user to export the query results of the databases in the project.
+	ProjectPermissionExportData ProjectPermissionType = "bb.permission.project.export-data"
+	// ProjectPermissionApproveIssue allows user to approve the issues in the project if the approval step needs the role.
+	ProjectPermissionApproveIssue ProjectPermissionType = "bb.permission.project.approve-issue"
 )
 
+// CustomRolePermissionList is the list of the permissions to compose the custom roles from.
+var CustomRolePermissionList = []ProjectPermissionType{
+	ProjectPermissionManageGeneral,
+	ProjectPermissionManageMember,
+	ProjectPermissionCreateSheet,
+	ProjectPermissionAdminSheet,
+	ProjectPermissionOrganizeSheet,
+	ProjectPermissionSyncSheet,
+	ProjectPermissionChangeDatabase,
+	ProjectPermissionAdminDatabase,
+	ProjectPermissionCreateDatabase,
+	ProjectPermissionTransferDatabase,
+	ProjectPermissionQueryDatabase,
+	ProjectPermissionExportData,
+	ProjectPermissionApproveIssue,
+}
+
+// DefaultCustomRolePermissionList is the permission list of the custom roles created before the roles had
+// permissions, which were only used in the approval flows.
+var DefaultCustomRolePermissionList = []ProjectPermissionType{
+	ProjectPermissionApproveIssue,
+}
+
+// ValidateCustomRolePermissionList validates the permission list of a custom role.
+func ValidateCustomRolePermissionList(permissionList []ProjectPermissionType) error {
+	for _, permission := range permissionList {
+		valid := false
+		for _, p := range CustomRolePermissionList {
+			if p == permission {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return errors.Errorf("invalid permission %q", permission)
+		}
+	}
+	return nil
+}
+
 // ProjectPermission returns whether a particular permission is granted to a particular project role in a particular plan.
 func ProjectPermission(permission ProjectPermissionType, plan PlanType, roles map[common.ProjectRole]bool) bool {
 	// a map from the a particular feature to the respective enablement of a project developer and owner.
@@ -72,6 +120,9 @@ func ProjectPermission(permission ProjectPermissionType, plan PlanType, roles ma
 		ProjectPermissionCreateDatabase: {!Feature(FeatureDBAWorkflow, plan), true},
 		// If dba-workflow is disabled, then project developer can also transfer database.
 		ProjectPermissionTransferDatabase: {!Feature(FeatureDBAWorkflow, plan), true},
+		ProjectPermissionQueryDatabase:    {true, true},
+		ProjectPermissionExportData:       {true, true},
+		ProjectPermissionApproveIssue:     {true, true},
 	}
 
 	for role := range roles {
@@ -89,3 +140,22 @@ func ProjectPermission(permission ProjectPermissionType, plan PlanType, roles ma
 
 	return false
 }
+
+// CustomRolePermission returns whether a particular permission is granted to any of the custom roles, given the
+// permission list of each custom role. The custom roles are only effective in the plan with the custom role feature.
+func CustomRolePermission(permission ProjectPermissionType, plan PlanType, roles map[common.ProjectRole]bool, rolePermissions map[common.ProjectRole][]ProjectPermissionType) bool {
+	if !Feature(FeatureCustomRole, plan) {
+		return false
+	}
+	for role := range roles {
+		if role == common.ProjectOwner || role == common.ProjectDeveloper {
+			continue
+		}
+		for _, p := range rolePermissions[role] {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+)
+
+func TestCustomRolePermission(t *testing.T) {
+	rolePermissions := map[common.ProjectRole][]ProjectPermissionType{
+		"ANALYST":  {ProjectPermissionQueryDatabase},
+		"APPROVER": DefaultCustomRolePermissionList,
+	}
+	tests := []struct {
+		permission ProjectPermissionType
+		plan       PlanType
+		roles      map[common.ProjectRole]bool
+		want       bool
+	}{
+		{ProjectPermissionQueryDatabase, ENTERPRISE, map[common.ProjectRole]bool{"ANALYST": true}, true},
+		{ProjectPermissionExportData, ENTERPRISE, map[common.ProjectRole]bool{"ANALYST": true}, false},
+		{ProjectPermissionApproveIssue, ENTERPRISE, map[common.ProjectRole]bool{"ANALYST": true, "APPROVER": true}, true},
+		{ProjectPermissionChangeDatabase, ENTERPRISE, map[common.ProjectRole]bool{"APPROVER": true}, false},
+		// The custom roles need the custom role feature.
+		{ProjectPermissionQueryDatabase, TEAM, map[common.ProjectRole]bool{"ANALYST": true}, false},
+		// The built-in roles aren't custom roles.
+		{ProjectPermissionQueryDatabase, ENTERPRISE, map[common.ProjectRole]bool{common.ProjectOwner: true}, false},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, CustomRolePermission(test.permission, test.plan, test.roles, rolePermissions), "%s %v", test.permission, test.roles)
+	}
+
+	require.NoError(t, ValidateCustomRolePermissionList([]ProjectPermissionType{ProjectPermissionQueryDatabase, ProjectPermissionApproveIssue}))
+	require.Error(t, ValidateCustomRolePermissionList([]ProjectPermissionType{"bb.permission.project.drop-database"}))
+}
@@ -151,6 +151,51 @@ func TestValidateRepositorySchemaPathTemplate(t *testing.T) {
 	}
 }
 
+func TestValidateRepositoryMetadataPathTemplate(t *testing.T) {
+	tests := []struct {
+		name               string
+		template           string
+		schemaPathTemplate string
+		errPart            string
+	}{
+		{
+			"OK",
+			"{{ENV_ID}}/{{DB_NAME}}.json",
+			"{{ENV_ID}}/{{DB_NAME}}.sql",
+			"",
+		}, {
+			"Empty",
+			"",
+			"",
+			"",
+		}, {
+			"No schema path template",
+			"{{DB_NAME}}.json",
+			"",
+			"requires the schema path template",
+		}, {
+			"Same as schema path template",
+			"{{DB_NAME}}.sql",
+			"{{DB_NAME}}.sql",
+			"must be different",
+		}, {
+			"UnknownToken",
+			"{{DB_NAME}}_{{TYPE}}.json",
+			"{{DB_NAME}}.sql",
+			"unknown token {{TYPE}} in metadata path template",
+		},
+	}
+
+	for _, test := range tests {
+		err := ValidateRepositoryMetadataPathTemplate(test.template, test.schemaPathTemplate, TenantModeDisabled)
+		if test.errPart == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, test.errPart, test.name)
+		}
+	}
+}
+
 func TestValidateProjectDBNameTemplate(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -13,6 +13,9 @@ type ProjectWebhook struct {
 	Name         string   `jsonapi:"attr,name"`
 	URL          string   `jsonapi:"attr,url"`
 	ActivityList []string `jsonapi:"attr,activityList"`
+	// PayloadTemplates is the JSON object of the Go templates of the JSON payloads keyed by the activity type, "*" for
+	// the other activity types. The templates are executed with the webhook context.
+	PayloadTemplates string `jsonapi:"attr,payloadTemplates"`
 }
 
 // ProjectWebhookCreate is the API message for creating a project webhook.
@@ -25,10 +28,11 @@ type ProjectWebhookCreate struct {
 	ProjectID int
 
 	// Domain specific fields
-	Type         string   `jsonapi:"attr,type"`
-	Name         string   `jsonapi:"attr,name"`
-	URL          string   `jsonapi:"attr,url"`
-	ActivityList []string `jsonapi:"attr,activityList"`
+	Type             string   `jsonapi:"attr,type"`
+	Name             string   `jsonapi:"attr,name"`
+	URL              string   `jsonapi:"attr,url"`
+	ActivityList     []string `jsonapi:"attr,activityList"`
+	PayloadTemplates string   `jsonapi:"attr,payloadTemplates"`
 }
 
 // ProjectWebhookPatch is the API message for patching a project webhook.
@@ -40,9 +44,10 @@ type ProjectWebhookPatch struct {
 	UpdaterID int
 
 	// Domain specific fields
-	Name         *string `jsonapi:"attr,name"`
-	URL          *string `jsonapi:"attr,url"`
-	ActivityList *string `jsonapi:"attr,activityList"`
+	Name             *string `jsonapi:"attr,name"`
+	URL              *string `jsonapi:"attr,url"`
+	ActivityList     *string `jsonapi:"attr,activityList"`
+	PayloadTemplates *string `jsonapi:"attr,payloadTemplates"`
 }
 
 // ProjectWebhookTestResult is the test result of a project webhook.
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// MaxQueryHistoryPageSize is the maximum number of the query histories returned in a page.
+const MaxQueryHistoryPageSize = 100
+
+// SettingWorkspaceQueryHistoryRetentionValue is the setting value of the query history retention.
+type SettingWorkspaceQueryHistoryRetentionValue struct {
+	// RetentionDays is the days the query histories are kept, 0 keeps them forever.
+	RetentionDays int `json:"retentionDays"`
+	// MaxCountPerUser is the number of the latest query histories kept for each user, 0 keeps all of them.
+	MaxCountPerUser int `json:"maxCountPerUser"`
+}
+
+// ValidateAndGetQueryHistoryRetentionSetting validates the setting value and returns the parsed one.
+func ValidateAndGetQueryHistoryRetentionSetting(settingValue string) (*SettingWorkspaceQueryHistoryRetentionValue, error) {
+	value := new(SettingWorkspaceQueryHistoryRetentionValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	if value.RetentionDays < 0 {
+		return nil, errors.Errorf("retention days cannot be negative")
+	}
+	if value.MaxCountPerUser < 0 {
+		return nil, errors.Errorf("max count per user cannot be negative")
+	}
+	return value, nil
+}
+
+// QueryHistory is the API message for a statement executed in the SQL editor.
+// Only the statement is kept, the result isn't.
+type QueryHistory struct {
+	ID           int    `json:"id"`
+	CreatorID    int    `json:"creatorId"`
+	CreatedTs    int64  `json:"createdTs"`
+	InstanceID   int    `json:"instanceId"`
+	DatabaseID   int    `json:"databaseId"`
+	DatabaseName string `json:"databaseName"`
+	Statement    string `json:"statement"`
+	DurationNs   int64  `json:"durationNs"`
+	Error        string `json:"error"`
+}
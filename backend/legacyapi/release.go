@@ -0,0 +1,42 @@
+package api
+
+import (
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// ReleaseFile is a migration file in the release.
+type ReleaseFile struct {
+	Path     string           `json:"path"`
+	Database string           `json:"database"`
+	Version  string           `json:"version"`
+	Type     db.MigrationType `json:"type"`
+	// Checksum is the hex encoded SHA-256 of the file content.
+	Checksum string `json:"checksum"`
+	// Applied is true if the file has been applied to the databases before the release, the file is not rolled out
+	// by the release.
+	Applied bool `json:"applied"`
+	// AdviceList is the SQL review result of the file rolled out by the release.
+	AdviceList []advisor.Advice `json:"adviceList"`
+}
+
+// Release is the API message for an immutable release built from a git tag of the repository in the release mode.
+// The rollouts of the release use the file contents at the tagged commit, which are the ones reviewed.
+type Release struct {
+	ID int `json:"id"`
+
+	// Standard fields
+	CreatorID int   `json:"creatorId"`
+	CreatedTs int64 `json:"createdTs"`
+
+	// Related fields
+	ProjectID int `json:"projectId"`
+
+	// Domain specific fields
+	Tag      string `json:"tag"`
+	CommitID string `json:"commitId"`
+	// FileList is the migration files at the tagged commit ordered by the schema version.
+	FileList []*ReleaseFile `json:"fileList"`
+	// Checksum is the hex encoded SHA-256 of the paths and the checksums of the files.
+	Checksum string `json:"checksum"`
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"encoding/json"
+
+	"github.com/pkg/errors"
 )
 
 // Repository is the API message for a repository.
@@ -28,7 +30,21 @@ type Repository struct {
 	// The file path template for matching the sql files for sheet.
 	SheetPathTemplate string `jsonapi:"attr,sheetPathTemplate"`
 	// Setup CI to do SQL review for all PRs.
-	EnableSQLReviewCI  bool   `jsonapi:"attr,enableSQLReviewCI"`
+	EnableSQLReviewCI bool `jsonapi:"attr,enableSQLReviewCI"`
+	// The tag filter for the release mode. If it's set, the branch pushes are ignored and the tag pushes matching the
+	// filter build the immutable releases to roll out.
+	ReleaseTagFilter string `jsonapi:"attr,releaseTagFilter"`
+	// The JSON encoded list of RepositoryRoutingRule, which routes the files in a monorepo to the other projects.
+	RoutingRules string `jsonapi:"attr,routingRules"`
+	// Open a pull request adding the rollback migration file when a change from the repository is rolled back.
+	EnableRollbackPR bool `jsonapi:"attr,enableRollbackPR"`
+	// Open a pull request instead of committing to the branch directly when writing back the latest schema.
+	SchemaWriteBackPR bool `jsonapi:"attr,schemaWriteBackPR"`
+	// The path template of the database metadata JSON file written back with the latest schema, e.g. for the ER
+	// diagrams. Empty means not writing back the metadata.
+	MetadataPathTemplate string `jsonapi:"attr,metadataPathTemplate"`
+	// The JSON encoded list of RepositoryBranchRule, which fans out the pushes to the environments by the branches.
+	BranchRules        string `jsonapi:"attr,branchRules"`
 	ExternalID         string `jsonapi:"attr,externalId"`
 	ExternalWebhookID  string
 	WebhookURLHost     string
@@ -61,14 +77,20 @@ type RepositoryCreate struct {
 	ProjectResourceID string
 
 	// Domain specific fields
-	Name               string `jsonapi:"attr,name"`
-	FullPath           string `jsonapi:"attr,fullPath"`
-	WebURL             string `jsonapi:"attr,webUrl"`
-	BranchFilter       string `jsonapi:"attr,branchFilter"`
-	BaseDirectory      string `jsonapi:"attr,baseDirectory"`
-	FilePathTemplate   string `jsonapi:"attr,filePathTemplate"`
-	SchemaPathTemplate string `jsonapi:"attr,schemaPathTemplate"`
-	SheetPathTemplate  string `jsonapi:"attr,sheetPathTemplate"`
+	Name                 string `jsonapi:"attr,name"`
+	FullPath             string `jsonapi:"attr,fullPath"`
+	WebURL               string `jsonapi:"attr,webUrl"`
+	BranchFilter         string `jsonapi:"attr,branchFilter"`
+	BaseDirectory        string `jsonapi:"attr,baseDirectory"`
+	FilePathTemplate     string `jsonapi:"attr,filePathTemplate"`
+	SchemaPathTemplate   string `jsonapi:"attr,schemaPathTemplate"`
+	SheetPathTemplate    string `jsonapi:"attr,sheetPathTemplate"`
+	ReleaseTagFilter     string `jsonapi:"attr,releaseTagFilter"`
+	RoutingRules         string `jsonapi:"attr,routingRules"`
+	EnableRollbackPR     bool   `jsonapi:"attr,enableRollbackPR"`
+	SchemaWriteBackPR    bool   `jsonapi:"attr,schemaWriteBackPR"`
+	MetadataPathTemplate string `jsonapi:"attr,metadataPathTemplate"`
+	BranchRules          string `jsonapi:"attr,branchRules"`
 	// EnableSQLReviewCI is only supported in the patch API.
 	ExternalID string `jsonapi:"attr,externalId"`
 	// Token belonged by the user linking the project to the VCS repository. We store this token together
@@ -115,15 +137,21 @@ type RepositoryPatch struct {
 	UpdaterID int
 
 	// Domain specific fields
-	BranchFilter       *string `jsonapi:"attr,branchFilter"`
-	BaseDirectory      *string `jsonapi:"attr,baseDirectory"`
-	FilePathTemplate   *string `jsonapi:"attr,filePathTemplate"`
-	SchemaPathTemplate *string `jsonapi:"attr,schemaPathTemplate"`
-	SheetPathTemplate  *string `jsonapi:"attr,sheetPathTemplate"`
-	EnableSQLReviewCI  *bool   `jsonapi:"attr,enableSQLReviewCI"`
-	AccessToken        *string
-	ExpiresTs          *int64
-	RefreshToken       *string
+	BranchFilter         *string `jsonapi:"attr,branchFilter"`
+	BaseDirectory        *string `jsonapi:"attr,baseDirectory"`
+	FilePathTemplate     *string `jsonapi:"attr,filePathTemplate"`
+	SchemaPathTemplate   *string `jsonapi:"attr,schemaPathTemplate"`
+	SheetPathTemplate    *string `jsonapi:"attr,sheetPathTemplate"`
+	EnableSQLReviewCI    *bool   `jsonapi:"attr,enableSQLReviewCI"`
+	ReleaseTagFilter     *string `jsonapi:"attr,releaseTagFilter"`
+	RoutingRules         *string `jsonapi:"attr,routingRules"`
+	EnableRollbackPR     *bool   `jsonapi:"attr,enableRollbackPR"`
+	SchemaWriteBackPR    *bool   `jsonapi:"attr,schemaWriteBackPR"`
+	MetadataPathTemplate *string `jsonapi:"attr,metadataPathTemplate"`
+	BranchRules          *string `jsonapi:"attr,branchRules"`
+	AccessToken          *string
+	ExpiresTs            *int64
+	RefreshToken         *string
 }
 
 // RepositoryDelete is the API message for deleting a repository.
@@ -138,3 +166,53 @@ type RepositoryDelete struct {
 	// Value is assigned from the jwt subject field passed by the client.
 	DeleterID int
 }
+
+// RepositoryRoutingRule routes the files matching the path pattern to a project other than the repository's one, so
+// that a monorepo connects a single repository for all of its services.
+type RepositoryRoutingRule struct {
+	// PathPattern is the glob of the file path relative to the base directory, where "*" matches a path segment and
+	// "**" matches any number of segments.
+	PathPattern string `json:"pathPattern"`
+	// ProjectID is the resource ID of the project the files are routed to.
+	ProjectID string `json:"projectId"`
+	// DatabaseTemplate overrides the database name parsed from the file path if not empty, the {{DB_NAME}} placeholder
+	// is replaced by the parsed database name.
+	DatabaseTemplate string `json:"databaseTemplate"`
+}
+
+// ParseRepositoryRoutingRules parses the JSON encoded routing rules of the repository.
+func ParseRepositoryRoutingRules(routingRules string) ([]*RepositoryRoutingRule, error) {
+	if routingRules == "" {
+		return nil, nil
+	}
+	var ruleList []*RepositoryRoutingRule
+	if err := json.Unmarshal([]byte(routingRules), &ruleList); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal routing rules %q", routingRules)
+	}
+	return ruleList, nil
+}
+
+// RepositoryBranchRule rolls out the pushes to the branches matching the pattern to a single environment, e.g.
+// "release/staging" to the staging environment and "release/prod" to the prod environment, so that each branch has its
+// own pipeline.
+type RepositoryBranchRule struct {
+	// Branch is the glob of the branch name, e.g. "release/staging".
+	Branch string `json:"branch"`
+	// EnvironmentID is the resource ID of the environment the pushes are rolled out to.
+	EnvironmentID string `json:"environmentId"`
+	// AutoRollout rolls out the tasks automatically by the pipeline approval policy of the environment if true,
+	// otherwise the tasks always wait for the manual rollout.
+	AutoRollout bool `json:"autoRollout"`
+}
+
+// ParseRepositoryBranchRules parses the JSON encoded branch rules of the repository.
+func ParseRepositoryBranchRules(branchRules string) ([]*RepositoryBranchRule, error) {
+	if branchRules == "" {
+		return nil, nil
+	}
+	var ruleList []*RepositoryBranchRule
+	if err := json.Unmarshal([]byte(branchRules), &ruleList); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal branch rules %q", branchRules)
+	}
+	return ruleList, nil
+}
@@ -0,0 +1,33 @@
+package api
+
+// CustomRole is the API message for a role with the permissions it's composed from. The built-in project roles have
+// the fixed permissions, and the custom roles have the permissions chosen by the workspace Owners.
+type CustomRole struct {
+	ResourceID     string                  `json:"resourceId"`
+	Name           string                  `json:"name"`
+	Description    string                  `json:"description"`
+	BuiltIn        bool                    `json:"builtIn"`
+	PermissionList []ProjectPermissionType `json:"permissionList"`
+}
+
+// CustomRolePatch is the API message for patching the permissions of a custom role.
+type CustomRolePatch struct {
+	PermissionList []ProjectPermissionType `json:"permissionList"`
+}
+
+// RoleMember is the API message for a custom role assigned at the workspace scope, whose permissions are granted in
+// all projects.
+type RoleMember struct {
+	// Standard fields
+	CreatorID int   `json:"creatorId"`
+	CreatedTs int64 `json:"createdTs"`
+
+	// Domain specific fields
+	Role        string `json:"role"`
+	PrincipalID int    `json:"principalId"`
+}
+
+// RoleMemberCreate is the API message for assigning a custom role at the workspace scope.
+type RoleMemberCreate struct {
+	PrincipalID int `json:"principalId"`
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"net/mail"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxScheduledQueryDeliveryCount is the maximum number of the deliveries of a scheduled query.
+	MaxScheduledQueryDeliveryCount = 10
+	// MaxScheduledQueryRowCount is the maximum number of the rows in the result snapshot, the rest of the rows are dropped.
+	MaxScheduledQueryRowCount = 1000
+)
+
+// ScheduledQueryDeliveryType is the type of the scheduled query delivery.
+type ScheduledQueryDeliveryType string
+
+const (
+	// ScheduledQueryDeliveryEmail sends the result to the email addresses with the workspace mail delivery setting.
+	ScheduledQueryDeliveryEmail ScheduledQueryDeliveryType = "EMAIL"
+	// ScheduledQueryDeliverySlack posts the result to the Slack incoming webhook.
+	ScheduledQueryDeliverySlack ScheduledQueryDeliveryType = "SLACK"
+	// ScheduledQueryDeliveryWebhook POSTs the result in JSON to the URL.
+	ScheduledQueryDeliveryWebhook ScheduledQueryDeliveryType = "WEBHOOK"
+)
+
+// ScheduledQueryDelivery is where the result of a scheduled query is delivered.
+type ScheduledQueryDelivery struct {
+	Type ScheduledQueryDeliveryType `json:"type"`
+	// Target is the comma separated email addresses for EMAIL, and the URL for SLACK and WEBHOOK.
+	Target string `json:"target"`
+}
+
+// ValidateScheduledQueryDeliveryList validates the deliveries of a scheduled query.
+func ValidateScheduledQueryDeliveryList(deliveryList []*ScheduledQueryDelivery) error {
+	if len(deliveryList) > MaxScheduledQueryDeliveryCount {
+		return errors.Errorf("a scheduled query can have at most %d deliveries", MaxScheduledQueryDeliveryCount)
+	}
+	for i, delivery := range deliveryList {
+		switch delivery.Type {
+		case ScheduledQueryDeliveryEmail:
+			for _, address := range strings.Split(delivery.Target, ",") {
+				if _, err := mail.ParseAddress(strings.TrimSpace(address)); err != nil {
+					return errors.Errorf("delivery %d has invalid email address %q", i+1, address)
+				}
+			}
+		case ScheduledQueryDeliverySlack, ScheduledQueryDeliveryWebhook:
+			u, err := url.Parse(delivery.Target)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return errors.Errorf("delivery %d has invalid URL %q", i+1, delivery.Target)
+			}
+		default:
+			return errors.Errorf("delivery %d has invalid type %q", i+1, delivery.Type)
+		}
+	}
+	return nil
+}
+
+// ScheduledQuery is the API message for a scheduled query.
+type ScheduledQuery struct {
+	ID int `json:"id"`
+
+	// Standard fields
+	CreatorID int   `json:"creatorId"`
+	CreatedTs int64 `json:"createdTs"`
+	UpdaterID int   `json:"updaterId"`
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Related fields
+	SheetID int `json:"sheetId"`
+
+	// Domain specific fields
+	Name string `json:"name"`
+	// Schedule is the cron expression with 5 fields, such as "0 9 * * 1" for 9:00 every Monday.
+	Schedule string `json:"schedule"`
+	// Timezone is the IANA time zone the schedule is evaluated in.
+	Timezone     string                    `json:"timezone"`
+	DeliveryList []*ScheduledQueryDelivery `json:"deliveryList"`
+	Enabled      bool                      `json:"enabled"`
+	NextRunTs    int64                     `json:"nextRunTs"`
+}
+
+// ScheduledQueryCreate is the API message for creating a scheduled query.
+type ScheduledQueryCreate struct {
+	SheetID      int                       `json:"sheetId"`
+	Name         string                    `json:"name"`
+	Schedule     string                    `json:"schedule"`
+	Timezone     string                    `json:"timezone"`
+	DeliveryList []*ScheduledQueryDelivery `json:"deliveryList"`
+}
+
+// ScheduledQueryPatch is the API message for patching a scheduled query.
+type ScheduledQueryPatch struct {
+	Name         *string                    `json:"name"`
+	Schedule     *string                    `json:"schedule"`
+	Timezone     *string                    `json:"timezone"`
+	DeliveryList *[]*ScheduledQueryDelivery `json:"deliveryList"`
+	Enabled      *bool                      `json:"enabled"`
+}
+
+// ScheduledQueryRunStatus is the status of a scheduled query run.
+type ScheduledQueryRunStatus string
+
+const (
+	// ScheduledQueryRunSuccess is the status of the run whose query succeeded, the deliveries may fail individually.
+	ScheduledQueryRunSuccess ScheduledQueryRunStatus = "SUCCESS"
+	// ScheduledQueryRunFailed is the status of the run whose query failed.
+	ScheduledQueryRunFailed ScheduledQueryRunStatus = "FAILED"
+)
+
+// ScheduledQueryResult is the masked result snapshot of a scheduled query run.
+type ScheduledQueryResult struct {
+	ColumnNames     []string `json:"columnNames"`
+	ColumnTypeNames []string `json:"columnTypeNames"`
+	Rows            [][]any  `json:"rows"`
+	// Truncated is true if the rows beyond the MaxScheduledQueryRowCount are dropped.
+	Truncated bool `json:"truncated"`
+}
+
+// ScheduledQueryDeliveryResult is the result of a delivery of a scheduled query run.
+type ScheduledQueryDeliveryResult struct {
+	Type   ScheduledQueryDeliveryType `json:"type"`
+	Target string                     `json:"target"`
+	// Error is empty if the result is delivered.
+	Error string `json:"error"`
+}
+
+// ScheduledQueryRun is the API message for a run of a scheduled query.
+type ScheduledQueryRun struct {
+	ID                 int                             `json:"id"`
+	CreatedTs          int64                           `json:"createdTs"`
+	ScheduledQueryID   int                             `json:"scheduledQueryId"`
+	Status             ScheduledQueryRunStatus         `json:"status"`
+	Result             *ScheduledQueryResult           `json:"result"`
+	Error              string                          `json:"error"`
+	DeliveryResultList []*ScheduledQueryDeliveryResult `json:"deliveryResultList"`
+}
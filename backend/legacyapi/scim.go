@@ -0,0 +1,407 @@
+package api
+
+import (
+	"encoding/json"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The SCIM 2.0 schemas, see https://datatracker.ietf.org/doc/html/rfc7643 and
+// https://datatracker.ietf.org/doc/html/rfc7644.
+const (
+	SCIMUserSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMGroupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIMListResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMPatchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMErrorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SCIMServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// scimTokenMinLength is the minimum length of the SCIM bearer token.
+const scimTokenMinLength = 32
+
+// SettingWorkspaceSCIMValue is the setting value of the SCIM provisioning.
+type SettingWorkspaceSCIMValue struct {
+	Enabled bool `json:"enabled"`
+	// Token is the bearer token of the identity providers calling the SCIM endpoint, it's never returned to the
+	// clients. The token is kept if it's empty in the update.
+	Token string `json:"token"`
+}
+
+// ValidateAndGetSCIMSetting validates the setting value and returns the parsed one.
+func ValidateAndGetSCIMSetting(settingValue string) (*SettingWorkspaceSCIMValue, error) {
+	value := new(SettingWorkspaceSCIMValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	if value.Enabled && len(value.Token) < scimTokenMinLength {
+		return nil, errors.Errorf("SCIM token must be at least %d characters", scimTokenMinLength)
+	}
+	return value, nil
+}
+
+// InheritSCIMToken fills the empty token in the new setting value with the old one, as the token isn't returned to
+// the clients. It returns the new setting value after validation.
+func InheritSCIMToken(oldSettingValue, settingValue string) (string, error) {
+	value := new(SettingWorkspaceSCIMValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	if oldSettingValue != "" && value.Token == "" {
+		oldValue := new(SettingWorkspaceSCIMValue)
+		if err := json.Unmarshal([]byte(oldSettingValue), oldValue); err != nil {
+			return "", errors.Wrapf(err, "failed to unmarshal setting value")
+		}
+		value.Token = oldValue.Token
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal setting value")
+	}
+	if _, err := ValidateAndGetSCIMSetting(string(b)); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// RedactSCIMToken removes the token from the setting value returned to the clients.
+func RedactSCIMToken(settingValue string) (string, error) {
+	value := new(SettingWorkspaceSCIMValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	value.Token = ""
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal setting value")
+	}
+	return string(b), nil
+}
+
+// SCIMUser is the SCIM user resource. The userName is the email of the Bytebase user.
+type SCIMUser struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *SCIMName    `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []*SCIMEmail `json:"emails,omitempty"`
+	Active      *bool        `json:"active,omitempty"`
+	Meta        *SCIMMeta    `json:"meta,omitempty"`
+}
+
+// SCIMName is the name of the SCIM user.
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// SCIMEmail is the email of the SCIM user.
+type SCIMEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// SCIMGroup is the SCIM group resource.
+type SCIMGroup struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	ExternalID  string        `json:"externalId,omitempty"`
+	DisplayName string        `json:"displayName"`
+	Members     []*SCIMMember `json:"members,omitempty"`
+	Meta        *SCIMMeta     `json:"meta,omitempty"`
+}
+
+// SCIMMember is the member of the SCIM group, the value is the ID of the SCIM user.
+type SCIMMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// SCIMMeta is the metadata of the SCIM resources.
+type SCIMMeta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// SCIMListResponse is the response of listing the SCIM resources.
+type SCIMListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// SCIMPatchRequest is the request of patching the SCIM resources.
+type SCIMPatchRequest struct {
+	Schemas    []string              `json:"schemas"`
+	Operations []*SCIMPatchOperation `json:"Operations"`
+}
+
+// SCIMPatchOperation is an operation of patching the SCIM resources.
+type SCIMPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// SCIMError is the error response of the SCIM endpoint.
+type SCIMError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	SCIMType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// SCIMFilter is the filter of listing the SCIM resources, only the equality filter is supported, which is the one
+// used by Okta and Azure AD to look up the resources before provisioning them.
+type SCIMFilter struct {
+	// Attribute is the lower case attribute name, e.g. username.
+	Attribute string
+	Value     string
+}
+
+var scimFilterRegexp = regexp.MustCompile(`(?i)^\s*([a-z][a-z0-9.]*)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// ParseSCIMFilter parses the filter, e.g. userName eq "alice@example.com". It returns nil if the filter is empty.
+func ParseSCIMFilter(filter string) (*SCIMFilter, error) {
+	if strings.TrimSpace(filter) == "" {
+		return nil, nil
+	}
+	matches := scimFilterRegexp.FindStringSubmatch(filter)
+	if matches == nil {
+		return nil, errors.Errorf("unsupported filter %q, only the eq operator is supported", filter)
+	}
+	value, err := strconv.Unquote(matches[2])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid value of filter %q", filter)
+	}
+	return &SCIMFilter{Attribute: strings.ToLower(matches[1]), Value: value}, nil
+}
+
+// Email returns the lower case email of the user by the userName.
+func (user *SCIMUser) Email() (string, error) {
+	address, err := mail.ParseAddress(user.UserName)
+	if err != nil || address.Address != user.UserName {
+		return "", errors.Errorf("userName %q must be an email", user.UserName)
+	}
+	return strings.ToLower(user.UserName), nil
+}
+
+// GetDisplayName returns the display name of the user, falls back to the name and the userName.
+func (user *SCIMUser) GetDisplayName() string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	if user.Name != nil {
+		if user.Name.Formatted != "" {
+			return user.Name.Formatted
+		}
+		if name := strings.TrimSpace(user.Name.GivenName + " " + user.Name.FamilyName); name != "" {
+			return name
+		}
+	}
+	return strings.Split(user.UserName, "@")[0]
+}
+
+// IsActive returns whether the user is active, the users are active by default.
+func (user *SCIMUser) IsActive() bool {
+	return user.Active == nil || *user.Active
+}
+
+// ApplySCIMUserPatch applies the patch operations to the user. The unsupported attributes are ignored.
+func ApplySCIMUserPatch(user *SCIMUser, operations []*SCIMPatchOperation) error {
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if op == "remove" {
+			// None of the supported attributes can be removed.
+			continue
+		}
+		if op != "add" && op != "replace" {
+			return errors.Errorf("unsupported operation %q", operation.Op)
+		}
+		values, err := getSCIMPatchValues(operation, SCIMUserSchema)
+		if err != nil {
+			return err
+		}
+		for attribute, value := range values {
+			if err := setSCIMUserAttribute(user, attribute, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setSCIMUserAttribute(user *SCIMUser, attribute string, value json.RawMessage) error {
+	switch attribute {
+	case "active":
+		active, err := parseSCIMBool(value)
+		if err != nil {
+			return err
+		}
+		user.Active = &active
+	case "username":
+		return unmarshalSCIMValue(attribute, value, &user.UserName)
+	case "displayname":
+		return unmarshalSCIMValue(attribute, value, &user.DisplayName)
+	case "externalid":
+		return unmarshalSCIMValue(attribute, value, &user.ExternalID)
+	case "name":
+		user.Name = &SCIMName{}
+		return unmarshalSCIMValue(attribute, value, user.Name)
+	case "name.formatted", "name.givenname", "name.familyname":
+		if user.Name == nil {
+			user.Name = &SCIMName{}
+		}
+		switch attribute {
+		case "name.formatted":
+			return unmarshalSCIMValue(attribute, value, &user.Name.Formatted)
+		case "name.givenname":
+			return unmarshalSCIMValue(attribute, value, &user.Name.GivenName)
+		default:
+			return unmarshalSCIMValue(attribute, value, &user.Name.FamilyName)
+		}
+	}
+	return nil
+}
+
+var scimMemberPathRegexp = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+("(?:[^"\\]|\\.)*")\s*\]$`)
+
+// ApplySCIMGroupPatch applies the patch operations to the group. The unsupported attributes are ignored.
+func ApplySCIMGroupPatch(group *SCIMGroup, operations []*SCIMPatchOperation) error {
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		switch op {
+		case "add", "replace":
+			values, err := getSCIMPatchValues(operation, SCIMGroupSchema)
+			if err != nil {
+				return err
+			}
+			for attribute, value := range values {
+				switch attribute {
+				case "displayname":
+					if err := unmarshalSCIMValue(attribute, value, &group.DisplayName); err != nil {
+						return err
+					}
+				case "externalid":
+					if err := unmarshalSCIMValue(attribute, value, &group.ExternalID); err != nil {
+						return err
+					}
+				case "members":
+					var members []*SCIMMember
+					if err := unmarshalSCIMValue(attribute, value, &members); err != nil {
+						return err
+					}
+					if op == "replace" {
+						group.Members = nil
+					}
+					group.Members = addSCIMMembers(group.Members, members)
+				}
+			}
+		case "remove":
+			if matches := scimMemberPathRegexp.FindStringSubmatch(operation.Path); matches != nil {
+				value, err := strconv.Unquote(matches[1])
+				if err != nil {
+					return errors.Wrapf(err, "invalid path %q", operation.Path)
+				}
+				group.Members = removeSCIMMembers(group.Members, []*SCIMMember{{Value: value}})
+				continue
+			}
+			if strings.ToLower(operation.Path) != "members" {
+				continue
+			}
+			if len(operation.Value) == 0 || string(operation.Value) == "null" {
+				group.Members = nil
+				continue
+			}
+			var members []*SCIMMember
+			if err := unmarshalSCIMValue("members", operation.Value, &members); err != nil {
+				return err
+			}
+			group.Members = removeSCIMMembers(group.Members, members)
+		default:
+			return errors.Errorf("unsupported operation %q", operation.Op)
+		}
+	}
+	return nil
+}
+
+// getSCIMPatchValues returns the values of the operation by the lower case attribute names. The value of the
+// operation without the path is the object of the attributes.
+func getSCIMPatchValues(operation *SCIMPatchOperation, schema string) (map[string]json.RawMessage, error) {
+	if operation.Path != "" {
+		attribute := strings.TrimPrefix(strings.ToLower(operation.Path), strings.ToLower(schema)+":")
+		return map[string]json.RawMessage{attribute: operation.Value}, nil
+	}
+	object := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(operation.Value, &object); err != nil {
+		return nil, errors.Wrapf(err, "value of the operation without path must be an object")
+	}
+	values := make(map[string]json.RawMessage)
+	for attribute, value := range object {
+		values[strings.ToLower(attribute)] = value
+	}
+	return values, nil
+}
+
+func unmarshalSCIMValue(attribute string, value json.RawMessage, v any) error {
+	if err := json.Unmarshal(value, v); err != nil {
+		return errors.Wrapf(err, "invalid value of %q", attribute)
+	}
+	return nil
+}
+
+// parseSCIMBool parses the boolean value, Azure AD sends the boolean values as the strings, e.g. "False".
+func parseSCIMBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return false, errors.Errorf("invalid boolean value %s", string(value))
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, errors.Errorf("invalid boolean value %s", string(value))
+	}
+	return b, nil
+}
+
+func addSCIMMembers(members []*SCIMMember, added []*SCIMMember) []*SCIMMember {
+	exists := make(map[string]bool)
+	for _, member := range members {
+		exists[member.Value] = true
+	}
+	for _, member := range added {
+		if !exists[member.Value] {
+			exists[member.Value] = true
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+func removeSCIMMembers(members []*SCIMMember, removed []*SCIMMember) []*SCIMMember {
+	removing := make(map[string]bool)
+	for _, member := range removed {
+		removing[member.Value] = true
+	}
+	var result []*SCIMMember
+	for _, member := range members {
+		if !removing[member.Value] {
+			result = append(result, member)
+		}
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSCIMFilter(t *testing.T) {
+	a := require.New(t)
+	filter, err := ParseSCIMFilter(`userName eq "Alice@example.com"`)
+	a.NoError(err)
+	a.Equal(&SCIMFilter{Attribute: "username", Value: "Alice@example.com"}, filter)
+
+	filter, err = ParseSCIMFilter(`displayName EQ "DBA \"core\""`)
+	a.NoError(err)
+	a.Equal(&SCIMFilter{Attribute: "displayname", Value: `DBA "core"`}, filter)
+
+	filter, err = ParseSCIMFilter("")
+	a.NoError(err)
+	a.Nil(filter)
+
+	_, err = ParseSCIMFilter(`userName sw "alice"`)
+	a.Error(err)
+}
+
+func TestApplySCIMUserPatch(t *testing.T) {
+	a := require.New(t)
+	user := &SCIMUser{UserName: "alice@example.com", DisplayName: "Alice"}
+
+	// Okta deactivates the users by the operation without the path.
+	var request SCIMPatchRequest
+	a.NoError(json.Unmarshal([]byte(`{"Operations":[{"op":"replace","value":{"active":false}}]}`), &request))
+	a.NoError(ApplySCIMUserPatch(user, request.Operations))
+	a.False(user.IsActive())
+
+	// Azure AD sends the boolean values as the strings.
+	a.NoError(json.Unmarshal([]byte(`{"Operations":[
+		{"op":"Replace","path":"active","value":"True"},
+		{"op":"Replace","path":"name.givenName","value":"Alicia"},
+		{"op":"Add","path":"emails[type eq \"work\"].value","value":"alicia@example.com"}
+	]}`), &request))
+	a.NoError(ApplySCIMUserPatch(user, request.Operations))
+	a.True(user.IsActive())
+	a.Equal("Alicia", user.Name.GivenName)
+	a.Equal("alice@example.com", user.UserName)
+
+	a.Error(ApplySCIMUserPatch(user, []*SCIMPatchOperation{{Op: "move", Path: "active"}}))
+}
+
+func TestApplySCIMGroupPatch(t *testing.T) {
+	a := require.New(t)
+	group := &SCIMGroup{DisplayName: "DBA", Members: []*SCIMMember{{Value: "101"}}}
+
+	var request SCIMPatchRequest
+	a.NoError(json.Unmarshal([]byte(`{"Operations":[
+		{"op":"add","path":"members","value":[{"value":"102"},{"value":"101"}]},
+		{"op":"replace","value":{"id":"201","displayName":"Database Admins"}}
+	]}`), &request))
+	a.NoError(ApplySCIMGroupPatch(group, request.Operations))
+	a.Equal("Database Admins", group.DisplayName)
+	a.Equal([]*SCIMMember{{Value: "101"}, {Value: "102"}}, group.Members)
+
+	a.NoError(json.Unmarshal([]byte(`{"Operations":[{"op":"remove","path":"members[value eq \"101\"]"}]}`), &request))
+	a.NoError(ApplySCIMGroupPatch(group, request.Operations))
+	a.Equal([]*SCIMMember{{Value: "102"}}, group.Members)
+
+	a.NoError(json.Unmarshal([]byte(`{"Operations":[{"op":"replace","path":"members","value":[{"value":"103"}]}]}`), &request))
+	a.NoError(ApplySCIMGroupPatch(group, request.Operations))
+	a.Equal([]*SCIMMember{{Value: "103"}}, group.Members)
+
+	a.NoError(ApplySCIMGroupPatch(group, []*SCIMPatchOperation{{Op: "remove", Path: "members"}}))
+	a.Empty(group.Members)
+}
+
+func TestSCIMSetting(t *testing.T) {
+	a := require.New(t)
+	old := `{"enabled":true,"token":"0123456789abcdef0123456789abcdef"}`
+
+	redacted, err := RedactSCIMToken(old)
+	a.NoError(err)
+	a.NotContains(redacted, "0123456789abcdef")
+
+	value, err := InheritSCIMToken(old, redacted)
+	a.NoError(err)
+	parsed, err := ValidateAndGetSCIMSetting(value)
+	a.NoError(err)
+	a.Equal("0123456789abcdef0123456789abcdef", parsed.Token)
+
+	_, err = InheritSCIMToken("", `{"enabled":true,"token":"short"}`)
+	a.Error(err)
+}
@@ -1,8 +1,11 @@
 package api
 
 import (
+	"encoding/base64"
 	"encoding/json"
 
+	"github.com/pkg/errors"
+
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 )
 
@@ -26,6 +29,10 @@ const (
 	SettingEnterpriseTrial SettingName = "bb.enterprise.trial"
 	// SettingAppIM is the setting name for IM applications.
 	SettingAppIM SettingName = "bb.app.im"
+	// SettingAppSlack is the setting name for the Slack app handling the interactive approvals and the slash command.
+	SettingAppSlack SettingName = "bb.app.slack"
+	// SettingAppTeams is the setting name for the Teams bot and outgoing webhook handling the approvals.
+	SettingAppTeams SettingName = "bb.app.teams"
 	// SettingWatermark is the setting name for watermark displaying.
 	SettingWatermark SettingName = "bb.workspace.watermark"
 	// SettingPluginOpenAIKey is used for OpenAI's API key.
@@ -38,6 +45,25 @@ const (
 	SettingPluginAgent SettingName = "bb.plugin.agent"
 	// SettingWorkspaceMailDelivery is the setting name for workspace mail delivery.
 	SettingWorkspaceMailDelivery SettingName = "bb.workspace.mail-delivery"
+	// SettingWorkspaceCloudDiscovery is the setting name for discovering the instances from the cloud providers.
+	SettingWorkspaceCloudDiscovery SettingName = "bb.workspace.cloud-discovery"
+	// SettingWorkspaceQueryHistoryRetention is the setting name for the retention of the SQL editor query history.
+	SettingWorkspaceQueryHistoryRetention SettingName = "bb.workspace.query-history-retention"
+	// SettingMaskingSecret is the setting name for the secret the keys of the deterministic masking algorithms are
+	// derived from.
+	SettingMaskingSecret SettingName = "bb.masking.secret"
+	// SettingWorkspaceApprovalChain is the setting name for the escalations and the external approvers of the
+	// approval flows.
+	SettingWorkspaceApprovalChain SettingName = "bb.workspace.approval-chain"
+	// SettingWorkspaceAuditSink is the setting name for the sinks the audit entries are streamed to.
+	SettingWorkspaceAuditSink SettingName = "bb.workspace.audit-sink"
+	// SettingWorkspaceAlerting is the setting name for the receivers the incidents are opened on.
+	SettingWorkspaceAlerting SettingName = "bb.workspace.alerting"
+	// SettingWorkspaceSCIM is the setting name for the SCIM provisioning of the users and the groups.
+	SettingWorkspaceSCIM SettingName = "bb.workspace.scim"
+	// SettingWorkspaceSSOGroupMapping is the setting name for the rules mapping the identity provider groups to the
+	// workspace roles and the project memberships.
+	SettingWorkspaceSSOGroupMapping SettingName = "bb.workspace.sso-group-mapping"
 )
 
 // IMType is the type of IM.
@@ -97,6 +123,55 @@ type SettingAppIMValue struct {
 	} `json:"externalApproval"`
 }
 
+// SettingAppSlackValue is the setting value of SettingAppSlack type setting. It's never returned to the clients.
+type SettingAppSlackValue struct {
+	// SigningSecret verifies the requests from the Slack app.
+	SigningSecret string `json:"signingSecret"`
+	// BotToken maps the Slack users to the Bytebase users by email, it needs the users:read and users:read.email
+	// scopes.
+	BotToken string `json:"botToken"`
+}
+
+// ValidateAndGetSlackSetting validates the setting value and returns the parsed one.
+func ValidateAndGetSlackSetting(settingValue string) (*SettingAppSlackValue, error) {
+	value := new(SettingAppSlackValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	if value.SigningSecret == "" || value.BotToken == "" {
+		return nil, errors.Errorf("signing secret and bot token cannot be empty")
+	}
+	return value, nil
+}
+
+// SettingAppTeamsValue is the setting value of SettingAppTeams type setting. It's never returned to the clients.
+type SettingAppTeamsValue struct {
+	// TenantID, AppID and AppPassword are the Azure app registration of the bot, it needs the User.Read.All
+	// application permission of Microsoft Graph to map the Teams users to the Bytebase users by email.
+	TenantID    string `json:"tenantId"`
+	AppID       string `json:"appId"`
+	AppPassword string `json:"appPassword"`
+	// OutgoingWebhookToken is the base64 security token of the outgoing webhook, it's optional.
+	OutgoingWebhookToken string `json:"outgoingWebhookToken"`
+}
+
+// ValidateAndGetTeamsSetting validates the setting value and returns the parsed one.
+func ValidateAndGetTeamsSetting(settingValue string) (*SettingAppTeamsValue, error) {
+	value := new(SettingAppTeamsValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	if value.TenantID == "" || value.AppID == "" || value.AppPassword == "" {
+		return nil, errors.Errorf("tenant ID, app ID and app password cannot be empty")
+	}
+	if value.OutgoingWebhookToken != "" {
+		if _, err := base64.StdEncoding.DecodeString(value.OutgoingWebhookToken); err != nil {
+			return nil, errors.Errorf("outgoing webhook token must be base64 encoded")
+		}
+	}
+	return value, nil
+}
+
 // SettingWorkspaceMailDeliveryValue is the setting value of SettingMailDelivery type setting.
 type SettingWorkspaceMailDeliveryValue struct {
 	SMTPServerHost         string                                         `json:"smtpServerHost"`
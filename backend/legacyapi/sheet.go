@@ -30,6 +30,10 @@ const (
 	SheetFromGitHub SheetSource = "GITHUB"
 	// SheetFromBitbucket is the sheet synced from Bitbucket.
 	SheetFromBitbucket SheetSource = "BITBUCKET"
+	// SheetFromGitea is the sheet synced from Gitea or Forgejo.
+	SheetFromGitea SheetSource = "GITEA"
+	// SheetFromBitbucketServer is the sheet synced from Bitbucket Server or Data Center.
+	SheetFromBitbucketServer SheetSource = "BITBUCKET_SERVER"
 )
 
 // SheetType is the type of sheet.
@@ -38,6 +42,8 @@ type SheetType string
 const (
 	// SheetForSQL is the sheet that used for saving SQL statements.
 	SheetForSQL SheetType = "SQL"
+	// SheetForNotebook is the sheet with multiple parameterized cells, whose payload is the SheetNotebookPayload.
+	SheetForNotebook SheetType = "NOTEBOOK"
 )
 
 // SheetVCSPayload is the additional data payload of the VCS sheet.
@@ -101,8 +107,8 @@ type SheetCreate struct {
 	Statement  string          `jsonapi:"attr,statement"`
 	Visibility SheetVisibility `jsonapi:"attr,visibility"`
 	Source     SheetSource     `jsonapi:"attr,source"`
-	Type       SheetType
-	Payload    string `jsonapi:"attr,payload"`
+	Type       SheetType       `jsonapi:"attr,type"`
+	Payload    string          `jsonapi:"attr,payload"`
 }
 
 // SheetPatch is the API message for patching a sheet.
@@ -0,0 +1,75 @@
+package api
+
+// SnippetVisibility is the visibility of a snippet.
+type SnippetVisibility string
+
+const (
+	// SnippetVisibilityPrivate is the snippet only visible to its creator.
+	SnippetVisibilityPrivate SnippetVisibility = "PRIVATE"
+	// SnippetVisibilityProject is the snippet visible to the members of its project.
+	SnippetVisibilityProject SnippetVisibility = "PROJECT"
+	// SnippetVisibilityWorkspace is the snippet visible to everyone in the workspace.
+	SnippetVisibilityWorkspace SnippetVisibility = "WORKSPACE"
+)
+
+// SnippetVariable is a variable of the "{{name}}" placeholders in the content of a snippet.
+type SnippetVariable struct {
+	Name         string `json:"name"`
+	DefaultValue string `json:"defaultValue"`
+	Description  string `json:"description"`
+}
+
+// Snippet is the API message for a snippet, which is a short statement template inserted in the SQL editor.
+type Snippet struct {
+	ID int `json:"id"`
+
+	// Standard fields
+	CreatorID int   `json:"creatorId"`
+	CreatedTs int64 `json:"createdTs"`
+	UpdaterID int   `json:"updaterId"`
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Related fields
+	// ProjectID is only set for the PROJECT visibility.
+	ProjectID *int `json:"projectId"`
+
+	// Domain specific fields
+	Visibility   SnippetVisibility  `json:"visibility"`
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	Content      string             `json:"content"`
+	VariableList []*SnippetVariable `json:"variableList"`
+	UsageCount   int                `json:"usageCount"`
+	LastUsedTs   int64              `json:"lastUsedTs"`
+}
+
+// SnippetCreate is the API message for creating a snippet.
+type SnippetCreate struct {
+	// ProjectID is required by the PROJECT visibility.
+	ProjectID    *int               `json:"projectId"`
+	Visibility   SnippetVisibility  `json:"visibility"`
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	Content      string             `json:"content"`
+	VariableList []*SnippetVariable `json:"variableList"`
+}
+
+// SnippetPatch is the API message for patching a snippet.
+type SnippetPatch struct {
+	ProjectID    *int                `json:"projectId"`
+	Visibility   *SnippetVisibility  `json:"visibility"`
+	Name         *string             `json:"name"`
+	Description  *string             `json:"description"`
+	Content      *string             `json:"content"`
+	VariableList *[]*SnippetVariable `json:"variableList"`
+}
+
+// SnippetInsert is the API message for inserting a snippet in the SQL editor, the missing variables use the defaults.
+type SnippetInsert struct {
+	VariableValues map[string]string `json:"variableValues"`
+}
+
+// SnippetInsertResult is the API message for the content of the snippet inserted.
+type SnippetInsertResult struct {
+	Content string `json:"content"`
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
 	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/completion"
 )
 
 // ConnectionInfo is the API message for connection infos.
@@ -33,6 +34,29 @@ type ConnectionInfo struct {
 	SSHUser       string `json:"sshUser" jsonapi:"attr,sshUser"`
 	SSHPassword   string `json:"sshPassword" jsonapi:"attr,sshPassword"`
 	SSHPrivateKey string `json:"sshPrivateKey" jsonapi:"attr,sshPrivateKey"`
+	// Proxy configuration.
+	SSHJumpHostList []SSHJumpHost `json:"sshJumpHostList" jsonapi:"attr,sshJumpHostList"`
+	SOCKS5Proxy     *SOCKS5Proxy  `json:"socks5Proxy" jsonapi:"attr,socks5Proxy"`
+	// Authentication configuration.
+	AuthenticationType db.AuthenticationType `json:"authenticationType" jsonapi:"attr,authenticationType"`
+	AWSRegion          string                `json:"awsRegion" jsonapi:"attr,awsRegion"`
+	AWSAccessKeyID     string                `json:"awsAccessKeyId" jsonapi:"attr,awsAccessKeyId"`
+	AWSSecretAccessKey string                `json:"awsSecretAccessKey" jsonapi:"attr,awsSecretAccessKey"`
+	// GCP Cloud SQL connector.
+	GCPCloudSQLConnectionName string `json:"gcpCloudSqlConnectionName" jsonapi:"attr,gcpCloudSqlConnectionName"`
+	GCPCloudSQLUsePrivateIP   bool   `json:"gcpCloudSqlUsePrivateIp" jsonapi:"attr,gcpCloudSqlUsePrivateIp"`
+	GCPCredentialsJSON        string `json:"gcpCredentialsJson" jsonapi:"attr,gcpCredentialsJson"`
+	// Azure AD.
+	AzureTenantID     string `json:"azureTenantId" jsonapi:"attr,azureTenantId"`
+	AzureClientID     string `json:"azureClientId" jsonapi:"attr,azureClientId"`
+	AzureClientSecret string `json:"azureClientSecret" jsonapi:"attr,azureClientSecret"`
+	// Kerberos.
+	KerberosRealm  string `json:"kerberosRealm" jsonapi:"attr,kerberosRealm"`
+	KerberosKDC    string `json:"kerberosKdc" jsonapi:"attr,kerberosKdc"`
+	KerberosSPN    string `json:"kerberosSpn" jsonapi:"attr,kerberosSpn"`
+	KerberosKeytab string `json:"kerberosKeytab" jsonapi:"attr,kerberosKeytab"`
+	// External secret manager.
+	ExternalSecretReference string `json:"externalSecretReference" jsonapi:"attr,externalSecretReference"`
 }
 
 // SQLSyncSchema is the API message for sync schemas.
@@ -53,6 +77,241 @@ type SQLExecute struct {
 	// The maximum row count returned, only applicable to SELECT query.
 	// Not enforced if limit <= 0.
 	Limit int `jsonapi:"attr,limit"`
+	// Cursor materializes the whole result on the server if the engine supports it, and returns the first Limit
+	// rows with the cursor to page through the rest.
+	Cursor bool `jsonapi:"attr,cursor"`
+	// SessionVariableList is the whitelisted session variables set on the connection before the statement, the SQL
+	// editor sends them with every statement of the session.
+	SessionVariableList []db.SessionVariable `jsonapi:"attr,sessionVariableList"`
+	// AdminSessionID is the admin session recording the statement, only applicable to the admin mode. A session only
+	// recording the statement is created if it's not set.
+	AdminSessionID int `jsonapi:"attr,adminSessionId"`
+	// QueryID is given by the client to cancel the running query before the response, a random ID is used if empty.
+	QueryID string `jsonapi:"attr,queryId"`
+}
+
+// MaxSQLCursorPageSize is the maximum number of the rows fetched from a cursor at a time.
+const MaxSQLCursorPageSize = 10000
+
+// IsSQLCursorSupported returns true if the engine supports the query cursor.
+func IsSQLCursorSupported(engine db.Type) bool {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase, db.Postgres, db.Redshift, db.SQLite, db.MSSQL, db.Oracle, db.Snowflake, db.ClickHouse:
+		return true
+	default:
+		return false
+	}
+}
+
+// SQLCursorPage is the API message for a page of the query cursor.
+type SQLCursorPage struct {
+	// Data is the rows of the page in the form of the SingleSQLResult data.
+	Data      []any `json:"data"`
+	RowCount  int   `json:"rowCount"`
+	Truncated bool  `json:"truncated"`
+}
+
+// SQLResultExport is the API message for exporting the query result in the SQL editor.
+// The result is the one returned by the SQL execute, so it's masked and limited already.
+type SQLResultExport struct {
+	InstanceID int `json:"instanceId"`
+	// DatabaseID is the database queried, it's 0 for the instance level queries.
+	DatabaseID int `json:"databaseId"`
+	// Format is either "parquet" or "avro".
+	Format          string   `json:"format"`
+	ColumnNames     []string `json:"columnNames"`
+	ColumnTypeNames []string `json:"columnTypeNames"`
+	Rows            [][]any  `json:"rows"`
+	SensitiveList   []bool   `json:"sensitiveList"`
+	// ExportRequestID is the approved export request, if the export policy requires the approval for the result.
+	ExportRequestID int `json:"exportRequestId"`
+	// Statement is the statement querying the result. It's required if the DLP policy restricts any column of the
+	// database for the requester, and the result is queried again with it on the server.
+	Statement string `json:"statement"`
+}
+
+// SQLExplain is the API message for explaining the statement in the SQL editor.
+type SQLExplain struct {
+	InstanceID   int    `json:"instanceId"`
+	DatabaseName string `json:"databaseName"`
+	Statement    string `json:"statement"`
+	// Analyze runs the statement to collect the actual rows and time, it's only allowed for the workspace Owners and DBAs.
+	Analyze bool `json:"analyze"`
+}
+
+// IsSQLExplainSupported returns true if the engine supports explaining the statement in the SQL editor.
+func IsSQLExplainSupported(engine db.Type) bool {
+	return engine == db.Postgres || engine == db.MySQL || engine == db.MariaDB
+}
+
+// IsSQLExplainAnalyzeSupported returns true if the engine supports explaining the statement with the actual execution statistics.
+func IsSQLExplainAnalyzeSupported(engine db.Type) bool {
+	return engine == db.Postgres
+}
+
+// QueryPlan is the normalized query plan tree.
+type QueryPlan struct {
+	Root     *QueryPlanNode `json:"root"`
+	Analyzed bool           `json:"analyzed"`
+	// PlanningTimeMs and ExecutionTimeMs are only reported by the analyzed Postgres plans.
+	PlanningTimeMs  *float64 `json:"planningTimeMs,omitempty"`
+	ExecutionTimeMs *float64 `json:"executionTimeMs,omitempty"`
+	// Raw is the plan returned by the database.
+	Raw string `json:"raw"`
+}
+
+// QueryPlanNode is a node of the query plan tree.
+type QueryPlanNode struct {
+	Operation string `json:"operation"`
+	Relation  string `json:"relation,omitempty"`
+	Index     string `json:"index,omitempty"`
+	// Detail is the conditions and the notes of the node such as the filter.
+	Detail string `json:"detail,omitempty"`
+	// StartupCost is the cost before the first row is returned, it's only reported by Postgres.
+	StartupCost float64 `json:"startupCost"`
+	// TotalCost is the cost including the children.
+	TotalCost     float64 `json:"totalCost"`
+	EstimatedRows float64 `json:"estimatedRows"`
+	// ActualRows, ActualTimeMs and Loops are only set in the analyzed plans, the rows and time are the totals of all loops.
+	ActualRows   *float64         `json:"actualRows,omitempty"`
+	ActualTimeMs *float64         `json:"actualTimeMs,omitempty"`
+	Loops        *float64         `json:"loops,omitempty"`
+	Children     []*QueryPlanNode `json:"children,omitempty"`
+}
+
+// SQLCompletion is the API message for completing the statement at the caret in the SQL editor.
+type SQLCompletion struct {
+	InstanceID   int    `json:"instanceId"`
+	DatabaseName string `json:"databaseName"`
+	Statement    string `json:"statement"`
+	// CaretLine and CaretColumn are the 1-based position of the caret.
+	CaretLine   int `json:"caretLine"`
+	CaretColumn int `json:"caretColumn"`
+}
+
+// SQLCompletionResult is the API message for the completion candidates ranked from the best.
+type SQLCompletionResult struct {
+	CandidateList []*completion.Candidate `json:"candidateList"`
+}
+
+// IsSQLCompletionSupported returns true if the engine supports the schema-aware completion.
+func IsSQLCompletionSupported(engine db.Type) bool {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase, db.Postgres:
+		return true
+	default:
+		return false
+	}
+}
+
+// MaxSQLResultDiffRowCount is the maximum number of the rows of each result compared by the result diff.
+const MaxSQLResultDiffRowCount = 10000
+
+// SQLResultDiffDatabase is the database the statement of the result diff runs against.
+type SQLResultDiffDatabase struct {
+	InstanceID   int    `json:"instanceId"`
+	DatabaseName string `json:"databaseName"`
+}
+
+// SQLResultDiff is the API message for running the statement against two databases and diffing the results.
+type SQLResultDiff struct {
+	Statement string                 `json:"statement"`
+	Source    *SQLResultDiffDatabase `json:"source"`
+	Target    *SQLResultDiffDatabase `json:"target"`
+	// KeyColumnList is the columns identifying a row, so the rows with the same key and different values are changed
+	// rows. The whole rows are compared if it's empty.
+	KeyColumnList []string `json:"keyColumnList"`
+}
+
+// SQLResultDiffChangedRow is the row with the same key and different values in the source and target results.
+type SQLResultDiffChangedRow struct {
+	Source            []any    `json:"source"`
+	Target            []any    `json:"target"`
+	ChangedColumnList []string `json:"changedColumnList"`
+}
+
+// SQLResultDiffResult is the API message for the row-level diff of the source and target results.
+type SQLResultDiffResult struct {
+	ColumnNames    []string `json:"columnNames"`
+	SourceRowCount int      `json:"sourceRowCount"`
+	TargetRowCount int      `json:"targetRowCount"`
+	SameRowCount   int      `json:"sameRowCount"`
+	// AddedRows are only in the target result, and RemovedRows are only in the source result.
+	AddedRows   [][]any                    `json:"addedRows"`
+	RemovedRows [][]any                    `json:"removedRows"`
+	ChangedRows []*SQLResultDiffChangedRow `json:"changedRows"`
+}
+
+const (
+	// MaxSQLResultChartCategoryCount is the maximum number of the categories of a result chart.
+	MaxSQLResultChartCategoryCount = 1000
+	// MaxSQLResultChartSeriesCount is the maximum number of the series of a result chart.
+	MaxSQLResultChartSeriesCount = 50
+)
+
+// SQLResultChartAggregation is the aggregation of the values in a category of the result chart.
+type SQLResultChartAggregation string
+
+const (
+	// SQLResultChartAggregationCount counts the rows, the value column is optional.
+	SQLResultChartAggregationCount SQLResultChartAggregation = "COUNT"
+	// SQLResultChartAggregationSum sums the values.
+	SQLResultChartAggregationSum SQLResultChartAggregation = "SUM"
+	// SQLResultChartAggregationAvg averages the values.
+	SQLResultChartAggregationAvg SQLResultChartAggregation = "AVG"
+	// SQLResultChartAggregationMin is the minimum of the values.
+	SQLResultChartAggregationMin SQLResultChartAggregation = "MIN"
+	// SQLResultChartAggregationMax is the maximum of the values.
+	SQLResultChartAggregationMax SQLResultChartAggregation = "MAX"
+)
+
+// SQLResultChartTimeBucket is the time bucket the values of the category column are truncated to.
+type SQLResultChartTimeBucket string
+
+const (
+	// SQLResultChartTimeBucketNone uses the values of the category column as they are.
+	SQLResultChartTimeBucketNone SQLResultChartTimeBucket = ""
+	// SQLResultChartTimeBucketMinute truncates the time to the minute.
+	SQLResultChartTimeBucketMinute SQLResultChartTimeBucket = "MINUTE"
+	// SQLResultChartTimeBucketHour truncates the time to the hour.
+	SQLResultChartTimeBucketHour SQLResultChartTimeBucket = "HOUR"
+	// SQLResultChartTimeBucketDay truncates the time to the day.
+	SQLResultChartTimeBucketDay SQLResultChartTimeBucket = "DAY"
+	// SQLResultChartTimeBucketWeek truncates the time to the Monday of the week.
+	SQLResultChartTimeBucketWeek SQLResultChartTimeBucket = "WEEK"
+	// SQLResultChartTimeBucketMonth truncates the time to the month.
+	SQLResultChartTimeBucketMonth SQLResultChartTimeBucket = "MONTH"
+	// SQLResultChartTimeBucketYear truncates the time to the year.
+	SQLResultChartTimeBucketYear SQLResultChartTimeBucket = "YEAR"
+)
+
+// SQLResultChart is the API message for aggregating the result of a query cursor into the chart series.
+type SQLResultChart struct {
+	// CategoryColumn is the column grouped by, which is the x-axis of the chart.
+	CategoryColumn string                   `json:"categoryColumn"`
+	TimeBucket     SQLResultChartTimeBucket `json:"timeBucket"`
+	// SeriesColumn pivots the distinct values of the column into the series, it's optional.
+	SeriesColumn string `json:"seriesColumn"`
+	// ValueColumn is the column aggregated, it's optional for COUNT.
+	ValueColumn string                    `json:"valueColumn"`
+	Aggregation SQLResultChartAggregation `json:"aggregation"`
+}
+
+// SQLResultChartSeries is a series of the result chart, the data are aligned with the categories.
+type SQLResultChartSeries struct {
+	Name string `json:"name"`
+	// Data has the nil values for the categories without the rows.
+	Data []*float64 `json:"data"`
+}
+
+// SQLResultChartResult is the API message for the result chart.
+type SQLResultChartResult struct {
+	// CategoryList is in the order of the first appearance, or in the time order with the time bucket.
+	CategoryList []string                `json:"categoryList"`
+	SeriesList   []*SQLResultChartSeries `json:"seriesList"`
+	RowCount     int                     `json:"rowCount"`
+	// Truncated is true if the cursor dropped the rows beyond its limit.
+	Truncated bool `json:"truncated"`
 }
 
 // SingleSQLResult is the API message for single SQL result.
@@ -61,6 +320,12 @@ type SingleSQLResult struct {
 	Data string `jsonapi:"attr,data" json:"data"`
 	// SQL operation may fail for connection issue and there is no proper http status code for it, so we return error in the response body.
 	Error string `jsonapi:"attr,error" json:"error"`
+	// CursorID is the cursor to page through the result, and the Data only contains the first page if it's set.
+	CursorID string `jsonapi:"attr,cursorId" json:"cursorId,omitempty"`
+	// RowCount is the total number of the rows of the cursor.
+	RowCount int `jsonapi:"attr,rowCount" json:"rowCount,omitempty"`
+	// Truncated is true if the rows beyond the cursor limit are dropped.
+	Truncated bool `jsonapi:"attr,truncated" json:"truncated,omitempty"`
 }
 
 // SQLResultSet is the API message for SQL results.
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultSSOGroupClaim is the claim of the groups in the tokens of most identity providers, e.g. Okta, Keycloak and
+// Azure AD.
+const DefaultSSOGroupClaim = "groups"
+
+// SettingWorkspaceSSOGroupMappingValue is the setting value of the rules mapping the identity provider groups to the
+// workspace roles and the project memberships.
+type SettingWorkspaceSSOGroupMappingValue struct {
+	MappingList []*SSOGroupMapping `json:"mappingList"`
+}
+
+// SSOGroupMapping is the rules of an identity provider.
+type SSOGroupMapping struct {
+	// IdentityProvider is the resource ID of the identity provider.
+	IdentityProvider string `json:"identityProvider"`
+	// GroupClaim is the claim of the groups in the user info, the DefaultSSOGroupClaim is used if it's empty.
+	GroupClaim string          `json:"groupClaim"`
+	RuleList   []*SSOGroupRule `json:"ruleList"`
+}
+
+// SSOGroupRule maps the users in the group to the workspace role and the project roles.
+type SSOGroupRule struct {
+	Group string `json:"group"`
+	// WorkspaceRole is optional. The users get the highest workspace role of their groups, and the ones in none of the
+	// groups with workspace roles are reset to the developers.
+	WorkspaceRole   Role              `json:"workspaceRole"`
+	ProjectRoleList []*SSOProjectRole `json:"projectRoleList"`
+}
+
+// SSOProjectRole is a project role granted by the SSO group mapping.
+type SSOProjectRole struct {
+	// Project is the resource ID of the project.
+	Project string `json:"project"`
+	Role    Role   `json:"role"`
+}
+
+// SSOGroupGrant is the workspace role and the project roles of the user evaluated from the groups.
+type SSOGroupGrant struct {
+	// WorkspaceRole is empty if the mapping doesn't manage the workspace roles.
+	WorkspaceRole   Role
+	ProjectRoleList []*SSOProjectRole
+}
+
+// ValidateAndGetSSOGroupMappingSetting validates the setting value and returns the parsed one.
+func ValidateAndGetSSOGroupMappingSetting(settingValue string) (*SettingWorkspaceSSOGroupMappingValue, error) {
+	value := new(SettingWorkspaceSSOGroupMappingValue)
+	if err := json.Unmarshal([]byte(settingValue), value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal setting value")
+	}
+	idps := make(map[string]bool)
+	for _, mapping := range value.MappingList {
+		if mapping.IdentityProvider == "" {
+			return nil, errors.Errorf("identity provider is required")
+		}
+		if idps[mapping.IdentityProvider] {
+			return nil, errors.Errorf("duplicate mapping of identity provider %q", mapping.IdentityProvider)
+		}
+		idps[mapping.IdentityProvider] = true
+		for _, rule := range mapping.RuleList {
+			if rule.Group == "" {
+				return nil, errors.Errorf("group is required in the mapping of identity provider %q", mapping.IdentityProvider)
+			}
+			switch rule.WorkspaceRole {
+			case "", Owner, DBA, Developer:
+			default:
+				return nil, errors.Errorf("invalid workspace role %q of group %q", rule.WorkspaceRole, rule.Group)
+			}
+			for _, projectRole := range rule.ProjectRoleList {
+				if projectRole.Project == "" || projectRole.Role == "" {
+					return nil, errors.Errorf("project and role are required in the project roles of group %q", rule.Group)
+				}
+			}
+		}
+	}
+	return value, nil
+}
+
+// GetMapping returns the mapping of the identity provider, or nil if there's none.
+func (value *SettingWorkspaceSSOGroupMappingValue) GetMapping(idp string) *SSOGroupMapping {
+	if value == nil {
+		return nil
+	}
+	for _, mapping := range value.MappingList {
+		if mapping.IdentityProvider == idp {
+			return mapping
+		}
+	}
+	return nil
+}
+
+// GetGroupClaim returns the claim of the groups in the user info.
+func (mapping *SSOGroupMapping) GetGroupClaim() string {
+	if mapping.GroupClaim == "" {
+		return DefaultSSOGroupClaim
+	}
+	return mapping.GroupClaim
+}
+
+// Evaluate returns the workspace role and the project roles of the user in the groups. The nil mapping grants
+// nothing.
+func (mapping *SSOGroupMapping) Evaluate(groups []string) *SSOGroupGrant {
+	grant := &SSOGroupGrant{}
+	if mapping == nil {
+		return grant
+	}
+	inGroup := make(map[string]bool)
+	for _, group := range groups {
+		inGroup[group] = true
+	}
+	granted := make(map[SSOProjectRole]bool)
+	for _, rule := range mapping.RuleList {
+		if rule.WorkspaceRole != "" && grant.WorkspaceRole == "" {
+			grant.WorkspaceRole = Developer
+		}
+		if !inGroup[rule.Group] {
+			continue
+		}
+		if workspaceRoleRank(rule.WorkspaceRole) > workspaceRoleRank(grant.WorkspaceRole) {
+			grant.WorkspaceRole = rule.WorkspaceRole
+		}
+		for _, projectRole := range rule.ProjectRoleList {
+			if granted[*projectRole] {
+				continue
+			}
+			granted[*projectRole] = true
+			grant.ProjectRoleList = append(grant.ProjectRoleList, &SSOProjectRole{Project: projectRole.Project, Role: projectRole.Role})
+		}
+	}
+	sort.Slice(grant.ProjectRoleList, func(i, j int) bool {
+		if grant.ProjectRoleList[i].Project != grant.ProjectRoleList[j].Project {
+			return grant.ProjectRoleList[i].Project < grant.ProjectRoleList[j].Project
+		}
+		return grant.ProjectRoleList[i].Role < grant.ProjectRoleList[j].Role
+	})
+	return grant
+}
+
+func workspaceRoleRank(role Role) int {
+	switch role {
+	case Owner:
+		return 3
+	case DBA:
+		return 2
+	case Developer:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// GetSSOGroups returns the sorted groups in the claim of the user info. The identity providers return the groups as
+// either a list or a single string.
+func GetSSOGroups(claims map[string]any, claim string) []string {
+	var groups []string
+	switch v := claims[claim].(type) {
+	case string:
+		if v != "" {
+			groups = append(groups, v)
+		}
+	case []any:
+		for _, group := range v {
+			if s, ok := group.(string); ok && s != "" {
+				groups = append(groups, s)
+			}
+		}
+	case []string:
+		for _, group := range v {
+			if group != "" {
+				groups = append(groups, group)
+			}
+		}
+	}
+	sort.Strings(groups)
+	var result []string
+	for i, group := range groups {
+		if i > 0 && group == groups[i-1] {
+			continue
+		}
+		result = append(result, group)
+	}
+	return result
+}
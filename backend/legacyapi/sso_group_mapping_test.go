@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAndGetSSOGroupMappingSetting(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{`{"mappingList":[{"identityProvider":"okta","ruleList":[{"group":"dba","workspaceRole":"DBA","projectRoleList":[{"project":"hr","role":"OWNER"}]}]}]}`, false},
+		{`{"mappingList":[{"identityProvider":"okta"},{"identityProvider":"okta"}]}`, true},
+		{`{"mappingList":[{"ruleList":[{"group":"dba"}]}]}`, true},
+		{`{"mappingList":[{"identityProvider":"okta","ruleList":[{"workspaceRole":"DBA"}]}]}`, true},
+		{`{"mappingList":[{"identityProvider":"okta","ruleList":[{"group":"dba","workspaceRole":"ADMIN"}]}]}`, true},
+		{`{"mappingList":[{"identityProvider":"okta","ruleList":[{"group":"dba","projectRoleList":[{"project":"hr"}]}]}]}`, true},
+	}
+	for _, test := range tests {
+		_, err := ValidateAndGetSSOGroupMappingSetting(test.value)
+		if test.wantErr {
+			require.Error(t, err, test.value)
+		} else {
+			require.NoError(t, err, test.value)
+		}
+	}
+}
+
+func TestSSOGroupMappingEvaluate(t *testing.T) {
+	a := require.New(t)
+	mapping := &SSOGroupMapping{
+		IdentityProvider: "okta",
+		RuleList: []*SSOGroupRule{
+			{Group: "engineering", ProjectRoleList: []*SSOProjectRole{{Project: "web", Role: Developer}}},
+			{Group: "dba", WorkspaceRole: DBA, ProjectRoleList: []*SSOProjectRole{{Project: "web", Role: Developer}, {Project: "hr", Role: Owner}}},
+			{Group: "admin", WorkspaceRole: Owner},
+		},
+	}
+
+	a.Equal(&SSOGroupGrant{
+		WorkspaceRole: DBA,
+		ProjectRoleList: []*SSOProjectRole{
+			{Project: "hr", Role: Owner},
+			{Project: "web", Role: Developer},
+		},
+	}, mapping.Evaluate([]string{"dba", "engineering"}))
+	a.Equal(Owner, mapping.Evaluate([]string{"admin", "dba"}).WorkspaceRole)
+	// The users in none of the groups with workspace roles are reset to the developers.
+	a.Equal(&SSOGroupGrant{WorkspaceRole: Developer}, mapping.Evaluate([]string{"sales"}))
+
+	// The mapping doesn't manage the workspace roles without the rules of them.
+	noWorkspaceRole := &SSOGroupMapping{RuleList: []*SSOGroupRule{{Group: "engineering"}}}
+	a.Empty(noWorkspaceRole.Evaluate([]string{"engineering"}).WorkspaceRole)
+	var removed *SSOGroupMapping
+	a.Equal(&SSOGroupGrant{}, removed.Evaluate([]string{"dba"}))
+}
+
+func TestGetSSOGroups(t *testing.T) {
+	a := require.New(t)
+	var claims map[string]any
+	a.NoError(json.Unmarshal([]byte(`{"groups":["engineering","dba","engineering",1],"role":"admin"}`), &claims))
+	a.Equal([]string{"dba", "engineering"}, GetSSOGroups(claims, "groups"))
+	a.Equal([]string{"admin"}, GetSSOGroups(claims, "role"))
+	a.Empty(GetSSOGroups(claims, "roles"))
+}
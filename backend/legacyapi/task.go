@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/plugin/vcs"
 )
 
@@ -115,6 +116,12 @@ type TaskDatabaseSchemaBaselinePayload struct {
 	SkippedReason string `json:"skippedReason,omitempty"`
 
 	SchemaVersion string `json:"schemaVersion,omitempty"`
+
+	// RolloutStrategy throttles the tasks of the tenant pipeline if it's not nil.
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+	// ManualRollout skips the automatic rollout of the task, e.g. for the pushes to the branches requiring the manual
+	// approval.
+	ManualRollout bool `json:"manualRollout,omitempty"`
 }
 
 // TaskDatabaseSchemaUpdatePayload is the task payload for database schema update (DDL).
@@ -126,6 +133,181 @@ type TaskDatabaseSchemaUpdatePayload struct {
 	SheetID       int            `json:"sheetId,omitempty"`
 	SchemaVersion string         `json:"schemaVersion,omitempty"`
 	VCSPushEvent  *vcs.PushEvent `json:"pushEvent,omitempty"`
+
+	// ZeroDowntime executes the PostgreSQL schema update in the zero-downtime steps if it's not nil.
+	ZeroDowntime *ZeroDowntimeMigrationConfig `json:"zeroDowntime,omitempty"`
+	// ShadowDryRun applies the schema update to a shadow database before the rollout if it's not nil.
+	ShadowDryRun *ShadowDryRunConfig `json:"shadowDryRun,omitempty"`
+	// DependencyOverride allows the schema update to break the dependent objects if it's not nil.
+	DependencyOverride *DependencyOverride `json:"dependencyOverride,omitempty"`
+	// Resumable executes the statements one by one and records the checkpoint, so the retries skip the executed statements.
+	Resumable  bool                 `json:"resumable,omitempty"`
+	Checkpoint *StatementCheckpoint `json:"checkpoint,omitempty"`
+	// Hooks run before and after the migration of the task.
+	Hooks []*MigrationHook `json:"hooks,omitempty"`
+	// Assertions run after the migration, and fail the task to hold the rollout of the next stage if any fails.
+	Assertions []*MigrationAssertion `json:"assertions,omitempty"`
+
+	// RolloutStrategy throttles the tasks of the tenant pipeline if it's not nil.
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+	// ManualRollout skips the automatic rollout of the task, e.g. for the pushes to the branches requiring the manual
+	// approval.
+	ManualRollout bool `json:"manualRollout,omitempty"`
+}
+
+// DependencyOverride is the approval to roll out the schema update which breaks the views, functions or foreign keys
+// referencing the changed objects. It only applies to the sheet approved, and is reset by the statement changes.
+type DependencyOverride struct {
+	SheetID    int    `json:"sheetId"`
+	ApproverID int    `json:"approverId"`
+	Reason     string `json:"reason"`
+	CreatedTs  int64  `json:"createdTs"`
+}
+
+// DependencyOverridePatch is the API message for overriding the failed dependent object check of a task.
+type DependencyOverridePatch struct {
+	Reason string `json:"reason"`
+}
+
+// MigrationHookStage is the stage of the migration hook.
+type MigrationHookStage string
+
+const (
+	// MigrationHookPre runs before the migration, and fails the task if it fails.
+	MigrationHookPre MigrationHookStage = "PRE"
+	// MigrationHookPost runs after the migration succeeds. It doesn't fail the task as the migration is applied already.
+	MigrationHookPost MigrationHookStage = "POST"
+)
+
+// MigrationHookType is the type of the migration hook.
+type MigrationHookType string
+
+const (
+	// MigrationHookSQL executes the statement in the database of the task.
+	MigrationHookSQL MigrationHookType = "SQL"
+	// MigrationHookWebhook POSTs the task and the database to the URL.
+	MigrationHookWebhook MigrationHookType = "WEBHOOK"
+)
+
+// MaxMigrationHookCount is the maximum number of the hooks of a task.
+const MaxMigrationHookCount = 10
+
+// MigrationHook is a script run before or after the migration of the task, such as disabling the triggers,
+// warming the caches or notifying the downstream ETL.
+type MigrationHook struct {
+	Stage MigrationHookStage `json:"stage"`
+	Type  MigrationHookType  `json:"type"`
+	// Statement is the statement of the SQL hook.
+	Statement string `json:"statement,omitempty"`
+	// URL is the URL of the webhook hook.
+	URL string `json:"url,omitempty"`
+}
+
+// MigrationHookResult is the result of a migration hook in the task run.
+type MigrationHookResult struct {
+	Stage MigrationHookStage `json:"stage"`
+	Type  MigrationHookType  `json:"type"`
+	// Error is empty if the hook succeeds.
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// MigrationAssertionExpect is the expected result of the assertion query.
+type MigrationAssertionExpect string
+
+const (
+	// MigrationAssertionExpectEmpty expects the query to return no rows, e.g. the rows with NULL in the new NOT NULL column.
+	MigrationAssertionExpectEmpty MigrationAssertionExpect = "EMPTY"
+	// MigrationAssertionExpectNotEmpty expects the query to return at least one row.
+	MigrationAssertionExpectNotEmpty MigrationAssertionExpect = "NOT_EMPTY"
+	// MigrationAssertionExpectValue expects the query to return a single value equal to the Value, e.g. the difference
+	// of the row counts or the result of the checksum comparison.
+	MigrationAssertionExpectValue MigrationAssertionExpect = "VALUE"
+)
+
+// MaxMigrationAssertionCount is the maximum number of the assertions of a task.
+const MaxMigrationAssertionCount = 20
+
+// MigrationAssertion is a validation query run after the migration of the task.
+type MigrationAssertion struct {
+	Name      string                   `json:"name"`
+	Statement string                   `json:"statement"`
+	Expect    MigrationAssertionExpect `json:"expect"`
+	// Value is the expected value of the VALUE assertion. NULL is compared as "NULL".
+	Value string `json:"value,omitempty"`
+}
+
+// MigrationAssertionResult is the result of a migration assertion in the task run.
+type MigrationAssertionResult struct {
+	Name string `json:"name"`
+	// Error is empty if the assertion passes.
+	Error string `json:"error,omitempty"`
+}
+
+// IsMigrationAssertionSupported returns true if the engine supports the migration assertions.
+func IsMigrationAssertionSupported(engine db.Type) bool {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.Postgres:
+		return true
+	default:
+		return false
+	}
+}
+
+// StatementCheckpoint is the progress of the resumable task, which records the statements executed by the previous
+// attempts. It only applies to the sheet executed, and is reset by the statement changes.
+type StatementCheckpoint struct {
+	SheetID int `json:"sheetId"`
+	// CompletedCount is the number of the leading statements executed successfully.
+	CompletedCount int `json:"completedCount"`
+	TotalCount     int `json:"totalCount"`
+}
+
+// IsResumableMigrationSupported returns true if the engine supports the resumable migration.
+func IsResumableMigrationSupported(engine db.Type) bool {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.Postgres:
+		return true
+	default:
+		return false
+	}
+}
+
+// ShadowDryRunConfig is the configuration of the shadow database dry run, which clones the schema of the target
+// database into a scratch database on the same instance, applies the migration there and reports the timing, the
+// locks and the post-check results as a task check.
+type ShadowDryRunConfig struct {
+	// SampleRows is the number of the rows copied from each table into the shadow database, zero clones the structure only.
+	SampleRows int `json:"sampleRows,omitempty"`
+}
+
+// MaxShadowDryRunSampleRows is the maximum number of the sampled rows of each table in the shadow database dry run.
+const MaxShadowDryRunSampleRows = 10000
+
+// ZeroDowntimeMigrationConfig is the configuration of the PostgreSQL zero-downtime migration.
+// The zero values mean the defaults.
+type ZeroDowntimeMigrationConfig struct {
+	// LockTimeoutMs is the lock_timeout of each step in milliseconds.
+	LockTimeoutMs int64 `json:"lockTimeoutMs,omitempty"`
+	// MaxRetries is the number of retries for each step failing to acquire the lock in time.
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// BatchSize is the number of rows updated by each batch of the backfill.
+	BatchSize int `json:"batchSize,omitempty"`
+}
+
+// ZeroDowntimeMigrationPreview is the API message for previewing the zero-downtime steps of the statement.
+type ZeroDowntimeMigrationPreview struct {
+	Statement string `json:"statement"`
+	BatchSize int    `json:"batchSize"`
+}
+
+// ZeroDowntimeMigrationStep is the API message for a step of the zero-downtime migration.
+type ZeroDowntimeMigrationStep struct {
+	Statement string `json:"statement"`
+	// Batched is true for the backfill step, which runs repeatedly until no rows are affected.
+	Batched bool `json:"batched"`
+	// Rewritten is true if the step is rewritten from a risky statement.
+	Rewritten bool `json:"rewritten"`
 }
 
 // TaskDatabaseSchemaUpdateSDLPayload is the task payload for database schema update (SDL).
@@ -137,6 +319,12 @@ type TaskDatabaseSchemaUpdateSDLPayload struct {
 	SheetID       int            `json:"sheetId,omitempty"`
 	SchemaVersion string         `json:"schemaVersion,omitempty"`
 	VCSPushEvent  *vcs.PushEvent `json:"pushEvent,omitempty"`
+
+	// RolloutStrategy throttles the tasks of the tenant pipeline if it's not nil.
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+	// ManualRollout skips the automatic rollout of the task, e.g. for the pushes to the branches requiring the manual
+	// approval.
+	ManualRollout bool `json:"manualRollout,omitempty"`
 }
 
 // TaskDatabaseSchemaUpdateGhostSyncPayload is the task payload for gh-ost syncing ghost table.
@@ -148,12 +336,51 @@ type TaskDatabaseSchemaUpdateGhostSyncPayload struct {
 	SheetID       int            `json:"sheetId,omitempty"`
 	SchemaVersion string         `json:"schemaVersion,omitempty"`
 	VCSPushEvent  *vcs.PushEvent `json:"pushEvent,omitempty"`
+	// OnlineMigrationConfig is the engine and the throttling parameters used by the sync, nil means gh-ost.
+	OnlineMigrationConfig *OnlineMigrationConfig `json:"onlineMigrationConfig,omitempty"`
+	// DependencyOverride allows the schema update to break the dependent objects if it's not nil.
+	DependencyOverride *DependencyOverride `json:"dependencyOverride,omitempty"`
 	// SocketFileName is the socket file that gh-ost listens on.
 	// The name follows this template,
 	// `./tmp/gh-ost.{{ISSUE_ID}}.{{TASK_ID}}.{{DATABASE_ID}}.{{DATABASE_NAME}}.{{TABLE_NAME}}.sock`
 	// SocketFileName will be composed when needed. We don't store it explicitly.
 }
 
+// OnlineMigrationEngine is the engine of the online schema migration.
+type OnlineMigrationEngine string
+
+const (
+	// OnlineMigrationEngineGhost is the gh-ost engine, it's the default engine.
+	OnlineMigrationEngineGhost OnlineMigrationEngine = "GH_OST"
+	// OnlineMigrationEnginePtOSC is the pt-online-schema-change engine.
+	// It copies the rows with triggers instead of the binlog replication, so it works on the setups which can't use gh-ost.
+	OnlineMigrationEnginePtOSC OnlineMigrationEngine = "PT_OSC"
+)
+
+// OnlineMigrationConfig is the configuration of the online schema migration.
+type OnlineMigrationConfig struct {
+	// Engine is the online migration engine, empty means gh-ost.
+	Engine OnlineMigrationEngine `json:"engine,omitempty"`
+
+	// The throttling parameters are only used by pt-osc, the zero values mean the pt-osc defaults.
+	// ChunkSize is the number of rows copied per chunk.
+	ChunkSize int64 `json:"chunkSize,omitempty"`
+	// MaxLagSeconds pauses the copy while any replica lags behind more than the seconds.
+	MaxLagSeconds int64 `json:"maxLagSeconds,omitempty"`
+	// MaxLoad pauses the copy while the status variables exceed the thresholds, e.g. "Threads_running=25".
+	MaxLoad string `json:"maxLoad,omitempty"`
+	// CriticalLoad aborts the copy when the status variables exceed the thresholds, e.g. "Threads_running=50".
+	CriticalLoad string `json:"criticalLoad,omitempty"`
+}
+
+// GetEngine returns the online migration engine, defaults to gh-ost.
+func (c *OnlineMigrationConfig) GetEngine() OnlineMigrationEngine {
+	if c == nil || c.Engine == "" {
+		return OnlineMigrationEngineGhost
+	}
+	return c.Engine
+}
+
 // TaskDatabaseSchemaUpdateGhostCutoverPayload is the task payload for gh-ost switching the original table and the ghost table.
 type TaskDatabaseSchemaUpdateGhostCutoverPayload struct {
 	// Common fields
@@ -211,6 +438,88 @@ type TaskDatabaseDataUpdatePayload struct {
 	RollbackFromIssueID int `json:"rollbackFromIssueId,omitempty"`
 	// RollbackFromTaskID is the task ID from which the rollback SQL statement is generated for this task.
 	RollbackFromTaskID int `json:"rollbackFromTaskId,omitempty"`
+
+	// Chunked executes the UPDATE and DELETE statements in the primary key range chunks if it's not nil.
+	Chunked *ChunkedDMLConfig `json:"chunked,omitempty"`
+	// Seed syncs the table contents with the seed dataset if it's not nil.
+	// The upsert statements are generated against the table contents when the task runs, and the sheet is ignored.
+	Seed *SeedDataConfig `json:"seed,omitempty"`
+	// ShadowDryRun applies the data update to a shadow database before the rollout if it's not nil.
+	ShadowDryRun *ShadowDryRunConfig `json:"shadowDryRun,omitempty"`
+	// Resumable executes the statements one by one and records the checkpoint, so the retries skip the executed statements.
+	Resumable  bool                 `json:"resumable,omitempty"`
+	Checkpoint *StatementCheckpoint `json:"checkpoint,omitempty"`
+	// Hooks run before and after the migration of the task.
+	Hooks []*MigrationHook `json:"hooks,omitempty"`
+	// Assertions run after the migration, and fail the task to hold the rollout of the next stage if any fails.
+	Assertions []*MigrationAssertion `json:"assertions,omitempty"`
+
+	// RolloutStrategy throttles the tasks of the tenant pipeline if it's not nil.
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+	// ManualRollout skips the automatic rollout of the task, e.g. for the pushes to the branches requiring the manual
+	// approval.
+	ManualRollout bool `json:"manualRollout,omitempty"`
+}
+
+// ChunkedDMLConfig is the configuration of the chunked DML execution, which splits the large UPDATE and DELETE
+// into the primary key range chunks committed one by one.
+// The zero values mean the defaults.
+type ChunkedDMLConfig struct {
+	// BatchSize is the size of the primary key range of each chunk.
+	BatchSize int64 `json:"batchSize,omitempty"`
+	// SleepMs is the interval between the chunks in milliseconds.
+	SleepMs int64 `json:"sleepMs,omitempty"`
+	// MaxReplicationLagSeconds throttles the chunks while the replication lag exceeds it, zero disables the throttling.
+	MaxReplicationLagSeconds int64 `json:"maxReplicationLagSeconds,omitempty"`
+}
+
+// ChunkedDMLPatch is the API message for pausing or resuming the running chunked DML task.
+type ChunkedDMLPatch struct {
+	Paused bool `json:"paused"`
+}
+
+// ChunkedDMLProgressPayload is the payload of the chunked DML task progress.
+type ChunkedDMLProgressPayload struct {
+	Paused bool `json:"paused"`
+	// Comment is the reason of the postponing, such as the replication lag.
+	Comment string `json:"comment,omitempty"`
+}
+
+// DDLProgressPayload is the payload of the long-running ALTER TABLE or CREATE INDEX task progress.
+type DDLProgressPayload struct {
+	// Phase is the current phase reported by the database, such as "alter table (merge sort)".
+	Phase string `json:"phase"`
+}
+
+// SeedDataFormat is the format of the seed dataset.
+type SeedDataFormat string
+
+const (
+	// SeedDataFormatCSV is the CSV dataset, the first record is the header of the column names.
+	SeedDataFormatCSV SeedDataFormat = "csv"
+	// SeedDataFormatJSON is the JSON dataset, which is an array of objects keyed by the column names.
+	SeedDataFormatJSON SeedDataFormat = "json"
+)
+
+// SeedDataConfig is the configuration of the seed data change, which keeps the reference data of the table in sync with the dataset.
+type SeedDataConfig struct {
+	// Table is the target table, it could be qualified by the schema, e.g. "public.country".
+	Table   string         `json:"table"`
+	Format  SeedDataFormat `json:"format"`
+	Dataset string         `json:"dataset"`
+	// KeyColumns are the columns identifying the rows, usually the primary key.
+	// They must be covered by a primary key or unique constraint for the upsert statements.
+	KeyColumns []string `json:"keyColumns"`
+	// DeleteMissing deletes the rows of the table absent from the dataset.
+	DeleteMissing bool `json:"deleteMissing,omitempty"`
+}
+
+// SeedDataChange is the API message for the change syncing the table contents with the seed dataset.
+type SeedDataChange struct {
+	Statement   string `json:"statement"`
+	InsertCount int    `json:"insertCount"`
+	UpdateCount int    `json:"updateCount"`
+	DeleteCount int    `json:"deleteCount"`
 }
 
 // TaskDatabaseBackupPayload is the task payload for database backup.
@@ -352,6 +661,10 @@ type TaskPatch struct {
 	RollbackSQLStatus *RollbackSQLStatus
 	RollbackStatement *string
 	RollbackError     *string
+	// DependencyOverride is merged into the payload of the schema update tasks.
+	DependencyOverride *DependencyOverride
+	// StatementCheckpoint is merged into the payload of the resumable tasks.
+	StatementCheckpoint *StatementCheckpoint
 }
 
 // TaskStatusPatch is the API message for patching a task status.
@@ -371,4 +684,6 @@ type TaskStatusPatch struct {
 	// And SkippedReason is Comment.
 	Skipped       *bool
 	SkippedReason *string
+	// RerunFromScratch resets the checkpoint of the resumable task when retrying it.
+	RerunFromScratch bool `jsonapi:"attr,rerunFromScratch"`
 }
@@ -71,6 +71,10 @@ const (
 	TaskCheckDatabaseStatementTypeReport TaskCheckType = "bb.task-check.database.statement.type.report"
 	// TaskCheckDatabaseStatementAffectedRowsReport is the task check type for statement affected rows.
 	TaskCheckDatabaseStatementAffectedRowsReport TaskCheckType = "bb.task-check.database.statement.affected-rows.report"
+	// TaskCheckDatabaseStatementShadowDryRun is the task check type for applying the statement to a shadow database.
+	TaskCheckDatabaseStatementShadowDryRun TaskCheckType = "bb.task-check.database.statement.shadow-dry-run"
+	// TaskCheckDatabaseStatementDependency is the task check type for the objects depending on the changed objects.
+	TaskCheckDatabaseStatementDependency TaskCheckType = "bb.task-check.database.statement.dependency"
 	// TaskCheckDatabaseConnect is the task check type for database connection.
 	TaskCheckDatabaseConnect TaskCheckType = "bb.task-check.database.connect"
 	// TaskCheckGhostSync is the task check type for the gh-ost sync task.
@@ -151,7 +155,7 @@ func IsSyntaxCheckSupported(dbType db.Type) bool {
 
 // IsSQLReviewSupported checks the engine type if SQL review supports it.
 func IsSQLReviewSupported(dbType db.Type) bool {
-	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB || dbType == db.MariaDB {
+	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB || dbType == db.MariaDB || dbType == db.Snowflake || dbType == db.Redis || dbType == db.MongoDB || dbType == db.Elasticsearch || dbType == db.Cassandra || dbType == db.DynamoDB || dbType == db.Neo4j {
 		advisorDB, err := advisorDB.ConvertToAdvisorDBType(string(dbType))
 		if err != nil {
 			return false
@@ -173,6 +177,26 @@ func IsStatementTypeCheckSupported(dbType db.Type) bool {
 	}
 }
 
+// IsShadowDryRunSupported checks if the shadow database dry run supports the engine type.
+func IsShadowDryRunSupported(dbType db.Type) bool {
+	switch dbType {
+	case db.Postgres, db.MySQL:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsDependencyCheckSupported checks if the dependent object check supports the engine type.
+func IsDependencyCheckSupported(dbType db.Type) bool {
+	switch dbType {
+	case db.Postgres, db.MySQL, db.TiDB, db.MariaDB:
+		return true
+	default:
+		return false
+	}
+}
+
 // IsTaskCheckReportSupported checks if the task report supports the engine type.
 func IsTaskCheckReportSupported(dbType db.Type) bool {
 	switch dbType {
@@ -25,6 +25,10 @@ type TaskRunResultPayload struct {
 	Detail      string `json:"detail,omitempty"`
 	MigrationID string `json:"migrationId,omitempty"`
 	Version     string `json:"version,omitempty"`
+	// HookResultList is the results of the migration hooks run.
+	HookResultList []*MigrationHookResult `json:"hookResultList,omitempty"`
+	// AssertionResultList is the results of the migration assertions run.
+	AssertionResultList []*MigrationAssertionResult `json:"assertionResultList,omitempty"`
 }
 
 // TaskRun is the API message for a task run.
@@ -50,4 +54,41 @@ type TaskRun struct {
 	Comment string        `jsonapi:"attr,comment"`
 	Result  string        `jsonapi:"attr,result"`
 	Payload string        `jsonapi:"attr,payload"`
+	// Progress is the progress of the running task run, loaded from the task scheduler in memory.
+	Progress Progress `jsonapi:"attr,progress"`
+}
+
+// TaskRunLogEntry is a change of a task run or task check run streamed to the clients tailing the rollout, one JSON
+// object per line.
+type TaskRunLogEntry struct {
+	// TaskRun is set for the task run changes.
+	TaskRun *TaskRunLogTaskRun `json:"taskRun,omitempty"`
+	// TaskCheckRun is set for the task check run changes.
+	TaskCheckRun *TaskRunLogTaskCheckRun `json:"taskCheckRun,omitempty"`
+}
+
+// TaskRunLogTaskRun is the task run state in the streamed log entry.
+type TaskRunLogTaskRun struct {
+	ID        int           `json:"id"`
+	TaskID    int           `json:"taskId"`
+	Name      string        `json:"name"`
+	Status    TaskRunStatus `json:"status"`
+	Code      common.Code   `json:"code"`
+	Comment   string        `json:"comment"`
+	Result    string        `json:"result"`
+	UpdatedTs int64         `json:"updatedTs"`
+	// Progress is set for the running task run reporting the progress.
+	Progress *Progress `json:"progress,omitempty"`
+}
+
+// TaskRunLogTaskCheckRun is the task check run state in the streamed log entry.
+type TaskRunLogTaskCheckRun struct {
+	ID        int                `json:"id"`
+	TaskID    int                `json:"taskId"`
+	Type      TaskCheckType      `json:"type"`
+	Status    TaskCheckRunStatus `json:"status"`
+	Code      common.Code        `json:"code"`
+	Comment   string             `json:"comment"`
+	Result    string             `json:"result"`
+	UpdatedTs int64              `json:"updatedTs"`
 }
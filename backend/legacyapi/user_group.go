@@ -0,0 +1,11 @@
+package api
+
+// UserGroup is the API message for a group of users, which is provisioned by the identity provider through SCIM.
+type UserGroup struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	// ExternalID is the ID of the group in the identity provider.
+	ExternalID string `json:"externalId"`
+	// MemberList is the emails of the members.
+	MemberList []string `json:"memberList"`
+}
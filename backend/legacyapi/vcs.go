@@ -5,6 +5,8 @@ import (
 
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
 	"github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/github"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/gitlab"
 )
 
 // VCS is the API message for a VCS (Version Control System).
@@ -95,6 +97,10 @@ type VCSExchangeToken struct {
 type VCSSQLReviewResult struct {
 	Status  advisor.Status `json:"status"`
 	Content []string       `json:"content"`
+	// CheckRunAnnotations is the inline annotations of the GitHub check run.
+	CheckRunAnnotations []*github.CheckRunAnnotation `json:"checkRunAnnotations,omitempty"`
+	// CodeQualityIssues is the GitLab code quality report to annotate the merge request diff inline.
+	CodeQualityIssues []*gitlab.CodeQualityIssue `json:"codeQualityIssues,omitempty"`
 }
 
 // VCSSQLReviewRequest is the request from SQL review CI in VCS workflow.
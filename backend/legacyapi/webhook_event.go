@@ -0,0 +1,29 @@
+package api
+
+// WebhookEventStatus is the status of a VCS webhook delivery.
+type WebhookEventStatus string
+
+const (
+	// WebhookEventProcessing is the status of the deliveries being processed.
+	WebhookEventProcessing WebhookEventStatus = "PROCESSING"
+	// WebhookEventDone is the status of the successfully processed deliveries.
+	WebhookEventDone WebhookEventStatus = "DONE"
+	// WebhookEventFailed is the status of the failed deliveries, which can be replayed.
+	WebhookEventFailed WebhookEventStatus = "FAILED"
+)
+
+// WebhookEvent is the API message for a raw VCS webhook delivery.
+type WebhookEvent struct {
+	ID int `json:"id"`
+
+	// Standard fields
+	CreatedTs int64 `json:"createdTs"`
+	UpdatedTs int64 `json:"updatedTs"`
+
+	// Domain specific fields
+	Path   string             `json:"path"`
+	Status WebhookEventStatus `json:"status"`
+	// Error is the error of the last delivery or replay.
+	Error        string `json:"error"`
+	AttemptCount int    `json:"attemptCount"`
+}
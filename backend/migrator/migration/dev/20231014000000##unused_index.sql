@@ -0,0 +1,23 @@
+-- unused_index stores the indexes observed unused from the engine statistics for each database.
+CREATE TABLE unused_index (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- schema_name is empty for the engines without schema, such as MySQL.
+    schema_name TEXT NOT NULL,
+    table_name TEXT NOT NULL,
+    index_name TEXT NOT NULL,
+    -- unused_since_ts is the timestamp when the index was first observed unused.
+    unused_since_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_unused_index_unique_database_id_schema_name_table_name_index_name ON unused_index(database_id, schema_name, table_name, index_name);
+
+ALTER SEQUENCE unused_index_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_unused_index_updated_ts
+BEFORE
+UPDATE
+    ON unused_index FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,23 @@
+-- db_tls stores the TLS configuration of the database, which overrides the one of the instance data sources.
+-- The ssl_ca, ssl_cert and ssl_key are obfuscated as the ones in data_source.
+CREATE TABLE db_tls (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    ssl_ca TEXT NOT NULL DEFAULT '',
+    ssl_cert TEXT NOT NULL DEFAULT '',
+    ssl_key TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX idx_db_tls_unique_database_id ON db_tls(database_id);
+
+ALTER SEQUENCE db_tls_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_tls_updated_ts
+BEFORE
+UPDATE
+    ON db_tls FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
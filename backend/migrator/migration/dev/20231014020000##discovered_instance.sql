@@ -0,0 +1,32 @@
+-- discovered_instance stores the database instances discovered from the cloud providers.
+-- The unregistered instances are proposed for onboarding, and the registered ones are flagged once deleted upstream.
+CREATE TABLE discovered_instance (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    provider TEXT NOT NULL CHECK (provider IN ('AWS', 'GCP', 'AZURE')),
+    -- external_id is the ARN for AWS, the connection name for GCP and the resource ID for Azure.
+    external_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    engine TEXT NOT NULL,
+    host TEXT NOT NULL,
+    port TEXT NOT NULL,
+    region TEXT NOT NULL,
+    labels JSONB NOT NULL DEFAULT '{}',
+    -- environment_id is the environment suggested by the environment mapping rules.
+    environment_id INTEGER REFERENCES environment (id),
+    -- instance_id is the registered instance with the same host and port.
+    instance_id INTEGER REFERENCES instance (id),
+    status TEXT NOT NULL CHECK (status IN ('PROPOSED', 'REGISTERED', 'DISMISSED', 'DELETED_UPSTREAM')),
+    last_seen_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_discovered_instance_unique_provider_external_id ON discovered_instance(provider, external_id);
+
+ALTER SEQUENCE discovered_instance_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_discovered_instance_updated_ts
+BEFORE
+UPDATE
+    ON discovered_instance FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
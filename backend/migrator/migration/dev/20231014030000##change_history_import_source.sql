@@ -0,0 +1,3 @@
+-- The change history could be imported from the flyway_schema_history and DATABASECHANGELOG tables.
+ALTER TABLE instance_change_history DROP CONSTRAINT instance_change_history_source_check;
+ALTER TABLE instance_change_history ADD CONSTRAINT instance_change_history_source_check CHECK (source IN ('UI', 'VCS', 'LIBRARY', 'FLYWAY', 'LIQUIBASE'));
@@ -0,0 +1,42 @@
+-- scheduled_query stores the sheets run on the cron schedules, whose results are delivered to the recipients.
+CREATE TABLE scheduled_query (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    sheet_id INTEGER NOT NULL REFERENCES sheet (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    -- schedule is the cron expression with 5 fields evaluated in the timezone.
+    schedule TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    -- delivery_list is the list of the email, Slack and webhook deliveries.
+    delivery_list JSONB NOT NULL DEFAULT '[]',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_ts BIGINT NOT NULL
+);
+
+CREATE INDEX idx_scheduled_query_sheet_id ON scheduled_query(sheet_id);
+
+ALTER SEQUENCE scheduled_query_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_scheduled_query_updated_ts
+BEFORE
+UPDATE
+    ON scheduled_query FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- scheduled_query_run stores the masked result snapshot and the delivery results of each run of the scheduled queries.
+CREATE TABLE scheduled_query_run (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    scheduled_query_id INTEGER NOT NULL REFERENCES scheduled_query (id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('SUCCESS', 'FAILED')),
+    result JSONB NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    delivery_result_list JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX idx_scheduled_query_run_scheduled_query_id ON scheduled_query_run(scheduled_query_id);
+
+ALTER SEQUENCE scheduled_query_run_id_seq RESTART WITH 101;
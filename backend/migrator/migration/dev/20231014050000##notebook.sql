@@ -0,0 +1,20 @@
+ALTER TABLE sheet DROP CONSTRAINT sheet_type_check;
+
+ALTER TABLE sheet ADD CONSTRAINT sheet_type_check CHECK (type IN ('SQL', 'NOTEBOOK'));
+
+-- notebook_cell_result stores the result of the latest run of each cell of the notebook sheets.
+CREATE TABLE notebook_cell_result (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    sheet_id INTEGER NOT NULL REFERENCES sheet (id) ON DELETE CASCADE,
+    cell_id TEXT NOT NULL,
+    -- parameter_values is the values of the parameters the cell ran with.
+    parameter_values JSONB NOT NULL DEFAULT '{}',
+    result JSONB NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX idx_notebook_cell_result_unique_sheet_id_cell_id ON notebook_cell_result(sheet_id, cell_id);
+
+ALTER SEQUENCE notebook_cell_result_id_seq RESTART WITH 101;
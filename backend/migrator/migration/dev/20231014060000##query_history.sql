@@ -0,0 +1,19 @@
+-- query_history stores the statements executed in the SQL editor for the full-text search, the results aren't kept.
+CREATE TABLE query_history (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL,
+    -- database_id is 0 if the statement isn't executed in a database.
+    database_id INTEGER NOT NULL DEFAULT 0,
+    database_name TEXT NOT NULL DEFAULT '',
+    statement TEXT NOT NULL,
+    duration_ns BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_query_history_creator_id_created_ts ON query_history(creator_id, created_ts);
+
+CREATE INDEX idx_query_history_statement_tsv ON query_history USING GIN (to_tsvector('simple', statement));
+
+ALTER SEQUENCE query_history_id_seq RESTART WITH 101;
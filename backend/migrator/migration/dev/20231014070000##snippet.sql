@@ -0,0 +1,26 @@
+-- snippet stores the short parameterized statement templates inserted in the SQL editor, shared in a project or the
+-- workspace.
+CREATE TABLE snippet (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    -- updated_ts is set by the updates explicitly instead of the trigger, so recording the usage doesn't change it.
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- project_id is the project the snippet is shared in, it's required by the PROJECT visibility.
+    project_id INTEGER REFERENCES project (id) ON DELETE CASCADE,
+    visibility TEXT NOT NULL CHECK (visibility IN ('PRIVATE', 'PROJECT', 'WORKSPACE')),
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    -- variable_list is the list of the variables of the "{{name}}" placeholders in the content.
+    variable_list JSONB NOT NULL DEFAULT '[]',
+    usage_count INTEGER NOT NULL DEFAULT 0,
+    last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_snippet_creator_id ON snippet(creator_id);
+
+CREATE INDEX idx_snippet_project_id ON snippet(project_id);
+
+ALTER SEQUENCE snippet_id_seq RESTART WITH 101;
@@ -0,0 +1,35 @@
+-- admin_session stores the admin mode sessions of the SQL editor, whose transcripts are recorded for the audit.
+CREATE TABLE admin_session (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    database_name TEXT NOT NULL DEFAULT '',
+    ended_ts BIGINT NOT NULL DEFAULT 0,
+    -- entry_count and last_hash are the head of the hash chain of the entries, so removing the last entries is detected.
+    entry_count INTEGER NOT NULL DEFAULT 0,
+    last_hash TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_admin_session_creator_id ON admin_session(creator_id);
+
+ALTER SEQUENCE admin_session_id_seq RESTART WITH 101;
+
+-- admin_session_entry stores the commands and the responses of the admin sessions in order. Each entry has the hash of
+-- its content and the hash of the previous entry, so the transcript is tamper-evident.
+CREATE TABLE admin_session_entry (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL REFERENCES admin_session (id),
+    seq INTEGER NOT NULL,
+    -- created_ts is set by the server instead of the default, as it's covered by the hash.
+    created_ts BIGINT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('COMMAND', 'RESPONSE')),
+    content TEXT NOT NULL,
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_admin_session_entry_unique_session_id_seq ON admin_session_entry(session_id, seq);
+
+ALTER SEQUENCE admin_session_entry_id_seq RESTART WITH 101;
@@ -0,0 +1,30 @@
+-- pii_finding stores the columns detected to contain the personal data by the PII scanner. The findings are proposed
+-- for review, and the accepted ones are added to the sensitive data policies of the databases.
+-- The sampled values are never stored, only the match counts.
+CREATE TABLE pii_finding (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    schema_name TEXT NOT NULL,
+    table_name TEXT NOT NULL,
+    column_name TEXT NOT NULL,
+    classification TEXT NOT NULL CHECK (classification IN ('EMAIL', 'PHONE', 'SSN', 'CREDIT_CARD')),
+    -- mask_type is the mask type proposed for the sensitive data policy.
+    mask_type TEXT NOT NULL,
+    name_matched BOOLEAN NOT NULL,
+    sample_count INTEGER NOT NULL,
+    match_count INTEGER NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PROPOSED', 'ACCEPTED', 'DISMISSED')),
+    last_scanned_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_pii_finding_unique_database_id_schema_name_table_name_column_name ON pii_finding(database_id, schema_name, table_name, column_name);
+
+ALTER SEQUENCE pii_finding_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_pii_finding_updated_ts
+BEFORE
+UPDATE
+    ON pii_finding FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,14 @@
+-- role_member stores the custom roles assigned at the workspace scope, whose permissions are granted in all projects.
+CREATE TABLE role_member (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    role TEXT NOT NULL REFERENCES role (resource_id),
+    principal_id INTEGER NOT NULL REFERENCES principal (id)
+);
+
+CREATE UNIQUE INDEX idx_role_member_unique_role_principal_id ON role_member(role, principal_id);
+
+CREATE INDEX idx_role_member_principal_id ON role_member(principal_id);
+
+ALTER SEQUENCE role_member_id_seq RESTART WITH 101;
@@ -0,0 +1,23 @@
+-- issue_approval_step stores the state of the pending approval step of the issues for the escalations and the
+-- external approvers, it's reset whenever the pending step changes.
+CREATE TABLE issue_approval_step (
+    issue_id INTEGER PRIMARY KEY REFERENCES issue (id) ON DELETE CASCADE,
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- risk_level is the risk level the approval flow is found for.
+    risk_level BIGINT NOT NULL DEFAULT 0,
+    -- step is the index of the pending step in the approval flow, -1 before the approval flow is found.
+    step INTEGER NOT NULL DEFAULT -1,
+    started_ts BIGINT NOT NULL DEFAULT 0,
+    escalated_ts BIGINT NOT NULL DEFAULT 0,
+    external_status TEXT NOT NULL DEFAULT '' CHECK (external_status IN ('', 'REQUESTED', 'REJECTED')),
+    -- external_token is the token of the callback URL for the external approver of the step.
+    external_token TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_issue_approval_step_external_token ON issue_approval_step(external_token);
+
+CREATE TRIGGER update_issue_approval_step_updated_ts
+BEFORE
+UPDATE
+    ON issue_approval_step FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
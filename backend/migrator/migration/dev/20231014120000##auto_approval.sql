@@ -0,0 +1,17 @@
+-- auto_approval logs the issues approved by the auto-approval rules, the decision is revoked to require the approvers
+-- again.
+CREATE TABLE auto_approval (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    issue_id INTEGER NOT NULL REFERENCES issue (id) ON DELETE CASCADE,
+    risk_level BIGINT NOT NULL DEFAULT 0,
+    rule_id TEXT NOT NULL,
+    -- condition is the condition of the rule at the decision, the rule may be changed or removed later.
+    condition TEXT NOT NULL,
+    revoker_id INTEGER REFERENCES principal (id),
+    revoked_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_auto_approval_issue_id ON auto_approval(issue_id);
+
+ALTER SEQUENCE auto_approval_id_seq RESTART WITH 101;
@@ -0,0 +1,28 @@
+-- database_grant stores the temporary elevated access to a database requested by the users, which is revoked
+-- automatically after it expires.
+CREATE TABLE database_grant (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    level TEXT NOT NULL CHECK (level IN ('QUERIER', 'ADMIN')),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'ACTIVE', 'REJECTED', 'REVOKED', 'EXPIRED')),
+    duration_seconds BIGINT NOT NULL,
+    justification TEXT NOT NULL,
+    -- expire_ts is set when the grant is approved.
+    expire_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_database_grant_database_id ON database_grant(database_id);
+
+CREATE INDEX idx_database_grant_status ON database_grant(status);
+
+ALTER SEQUENCE database_grant_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_database_grant_updated_ts
+BEFORE
+UPDATE
+    ON database_grant FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
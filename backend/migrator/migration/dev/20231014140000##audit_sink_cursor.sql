@@ -0,0 +1,6 @@
+-- audit_sink_cursor stores the ID of the last activity delivered to each audit sink.
+CREATE TABLE audit_sink_cursor (
+    sink_id TEXT PRIMARY KEY,
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    activity_id INTEGER NOT NULL
+);
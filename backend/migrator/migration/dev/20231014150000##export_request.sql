@@ -0,0 +1,25 @@
+-- export_request stores the approvals of exporting the query results required by the export policy.
+CREATE TABLE export_request (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'USED')),
+    row_count INTEGER NOT NULL,
+    column_names TEXT[] NOT NULL,
+    justification TEXT NOT NULL,
+    -- expire_ts is set when the request is approved.
+    expire_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_export_request_creator_id ON export_request(creator_id);
+
+ALTER SEQUENCE export_request_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_export_request_updated_ts
+BEFORE
+UPDATE
+    ON export_request FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
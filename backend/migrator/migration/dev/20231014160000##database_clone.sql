@@ -0,0 +1,25 @@
+-- database_clone stores the copies of the databases into the lower environments, with the classified columns masked.
+CREATE TABLE database_clone (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    source_database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    target_instance_id INTEGER NOT NULL REFERENCES instance (id) ON DELETE CASCADE,
+    target_database_name TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'RUNNING', 'DONE', 'FAILED')),
+    table_count INTEGER NOT NULL DEFAULT 0,
+    row_count BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_database_clone_status ON database_clone(status);
+
+ALTER SEQUENCE database_clone_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_database_clone_updated_ts
+BEFORE
+UPDATE
+    ON database_clone FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
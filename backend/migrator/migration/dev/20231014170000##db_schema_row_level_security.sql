@@ -0,0 +1,2 @@
+-- row_level_security is the row level security of the PostgreSQL tables, which isn't in the metadata yet.
+ALTER TABLE db_schema ADD COLUMN row_level_security JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,19 @@
+ALTER TABLE repository ADD COLUMN release_tag_filter TEXT NOT NULL DEFAULT '';
+
+-- repository_release stores the immutable releases built from the git tags of the repositories in the release mode.
+CREATE TABLE repository_release (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    tag TEXT NOT NULL,
+    commit_id TEXT NOT NULL,
+    -- files is the ordered migration files with the checksums and the SQL review results.
+    files JSONB NOT NULL DEFAULT '[]',
+    -- checksum is the checksum of the files to verify the release is not changed.
+    checksum TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_repository_release_unique_project_id_tag ON repository_release(project_id, tag);
+
+ALTER SEQUENCE repository_release_id_seq RESTART WITH 101;
@@ -0,0 +1 @@
+ALTER TABLE repository ADD COLUMN routing_rules JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1 @@
+ALTER TABLE repository ADD COLUMN enable_rollback_pr BOOLEAN NOT NULL DEFAULT FALSE;
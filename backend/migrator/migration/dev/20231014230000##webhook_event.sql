@@ -0,0 +1,24 @@
+-- webhook_event stores the raw VCS webhook deliveries, so that the failed ones can be replayed.
+CREATE TABLE webhook_event (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- path is the request path, e.g. /hook/github/{webhook_endpoint_id}.
+    path TEXT NOT NULL,
+    header JSONB NOT NULL DEFAULT '{}',
+    body TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PROCESSING', 'DONE', 'FAILED')),
+    -- error is the error of the last delivery or replay.
+    error TEXT NOT NULL DEFAULT '',
+    attempt_count INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX idx_webhook_event_status ON webhook_event(status);
+
+ALTER SEQUENCE webhook_event_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_webhook_event_updated_ts
+BEFORE
+UPDATE
+    ON webhook_event FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
@@ -0,0 +1,2 @@
+ALTER TABLE repository ADD COLUMN schema_write_back_pr BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE repository ADD COLUMN metadata_path_template TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE repository ADD COLUMN branch_rules JSONB NOT NULL DEFAULT '[]';
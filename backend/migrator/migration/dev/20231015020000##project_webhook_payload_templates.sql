@@ -0,0 +1 @@
+ALTER TABLE project_webhook ADD COLUMN payload_templates JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,7 @@
+-- alert_incident stores the incidents opened on each alert receiver, they are resolved after the conditions clear.
+CREATE TABLE alert_incident (
+    receiver_id TEXT NOT NULL,
+    dedup_key TEXT NOT NULL,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    PRIMARY KEY (receiver_id, dedup_key)
+);
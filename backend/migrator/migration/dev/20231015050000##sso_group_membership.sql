@@ -0,0 +1,16 @@
+-- sso_group_membership stores the identity provider groups of the users on their last SSO login, and the project
+-- roles granted to them by the SSO group mapping, so the mapping can be re-evaluated and the roles can be revoked.
+CREATE TABLE sso_group_membership (
+    principal_id INTEGER PRIMARY KEY REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    idp TEXT NOT NULL,
+    group_list TEXT[] NOT NULL DEFAULT '{}',
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE TRIGGER update_sso_group_membership_updated_ts
+BEFORE
+UPDATE
+    ON sso_group_membership FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
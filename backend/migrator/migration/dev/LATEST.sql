@@ -301,7 +301,9 @@ CREATE TABLE project_webhook (
     type TEXT NOT NULL CHECK (type LIKE 'bb.plugin.webhook.%'),
     name TEXT NOT NULL,
     url TEXT NOT NULL,
-    activity_list TEXT ARRAY NOT NULL
+    activity_list TEXT ARRAY NOT NULL,
+    -- The Go templates of the JSON payloads keyed by the activity type, "*" for the other activity types.
+    payload_templates JSONB NOT NULL DEFAULT '{}'
 );
 
 CREATE INDEX idx_project_webhook_project_id ON project_webhook(project_id);
@@ -409,7 +411,9 @@ CREATE TABLE db_schema (
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
     metadata JSONB NOT NULL DEFAULT '{}',
-    raw_dump TEXT NOT NULL DEFAULT ''
+    raw_dump TEXT NOT NULL DEFAULT '',
+    -- row_level_security is the row level security of the PostgreSQL tables, which isn't in the metadata yet.
+    row_level_security JSONB NOT NULL DEFAULT '[]'
 );
 
 CREATE UNIQUE INDEX idx_db_schema_unique_database_id ON db_schema(database_id);
@@ -747,8 +751,9 @@ CREATE TABLE instance_change_history (
     -- Used to detect out of order change history together with 'namespace' and 'version' column.
     sequence BIGINT NOT NULL CONSTRAINT instance_change_history_sequence_check CHECK (sequence >= 0),
     -- We call it source because maybe we could load history from other migration tool.
-    -- Currently allowed values are UI, VCS, LIBRARY.
-    source TEXT NOT NULL CONSTRAINT instance_change_history_source_check CHECK (source IN ('UI', 'VCS', 'LIBRARY')),
+    -- Currently allowed values are UI, VCS, LIBRARY, FLYWAY, LIQUIBASE.
+    -- FLYWAY and LIQUIBASE are the history imported from the flyway_schema_history and DATABASECHANGELOG tables.
+    source TEXT NOT NULL CONSTRAINT instance_change_history_source_check CHECK (source IN ('UI', 'VCS', 'LIBRARY', 'FLYWAY', 'LIQUIBASE')),
     -- Currently allowed values are BASELINE, MIGRATE, MIGRATE_SDL, BRANCH, DATA.
     type TEXT NOT NULL CONSTRAINT instance_change_history_type_check CHECK (type IN ('BASELINE', 'MIGRATE', 'MIGRATE_SDL', 'BRANCH', 'DATA')),
     -- Currently allowed values are PENDING, DONE, FAILED.
@@ -857,7 +862,7 @@ CREATE TABLE vcs (
     updater_id INTEGER NOT NULL REFERENCES principal (id),
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     name TEXT NOT NULL,
-    type TEXT NOT NULL CHECK (type IN ('GITLAB', 'GITHUB', 'BITBUCKET')),
+    type TEXT NOT NULL CHECK (type IN ('GITLAB', 'GITHUB', 'BITBUCKET', 'GITEA', 'BITBUCKET_SERVER')),
     instance_url TEXT NOT NULL CHECK ((instance_url LIKE 'http://%' OR instance_url LIKE 'https://%') AND instance_url = rtrim(instance_url, '/')),
     api_url TEXT NOT NULL CHECK ((api_url LIKE 'http://%' OR api_url LIKE 'https://%') AND api_url = rtrim(api_url, '/')),
     application_id TEXT NOT NULL,
@@ -907,6 +912,18 @@ CREATE TABLE repository (
     schema_path_template TEXT NOT NULL DEFAULT '',
     -- The file path template to match the script file for sheet.
     sheet_path_template TEXT NOT NULL DEFAULT '',
+    -- The tag we are interested for the releases, the branch pushes are ignored if it's set. Wildcard is supported.
+    release_tag_filter TEXT NOT NULL DEFAULT '',
+    -- The rules routing the files matching the path patterns to the other projects for the monorepos.
+    routing_rules JSONB NOT NULL DEFAULT '[]',
+    -- Open a pull request adding the rollback migration file when a change from the repository is rolled back.
+    enable_rollback_pr BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Open a pull request instead of committing to the branch directly when writing back the latest schema.
+    schema_write_back_pr BOOLEAN NOT NULL DEFAULT FALSE,
+    -- The path template of the database metadata JSON file written back with the latest schema.
+    metadata_path_template TEXT NOT NULL DEFAULT '',
+    -- The rules rolling out the pushes to the environments by the branches, and whether to roll out automatically.
+    branch_rules JSONB NOT NULL DEFAULT '[]',
     -- Repository id from the corresponding VCS provider.
     -- For GitLab, this is the project id. e.g. 123
     external_id TEXT NOT NULL,
@@ -1070,8 +1087,8 @@ CREATE TABLE sheet (
     name TEXT NOT NULL,
     statement TEXT NOT NULL,
     visibility TEXT NOT NULL CHECK (visibility IN ('PRIVATE', 'PROJECT', 'PUBLIC')) DEFAULT 'PRIVATE',
-    source TEXT NOT NULL CONSTRAINT sheet_source_check CHECK (source IN ('BYTEBASE', 'GITLAB', 'GITHUB', 'BITBUCKET', 'BYTEBASE_ARTIFACT')) DEFAULT 'BYTEBASE',
-    type TEXT NOT NULL CHECK (type IN ('SQL')) DEFAULT 'SQL',
+    source TEXT NOT NULL CONSTRAINT sheet_source_check CHECK (source IN ('BYTEBASE', 'GITLAB', 'GITHUB', 'BITBUCKET', 'GITEA', 'BITBUCKET_SERVER', 'BYTEBASE_ARTIFACT')) DEFAULT 'BYTEBASE',
+    type TEXT NOT NULL CONSTRAINT sheet_type_check CHECK (type IN ('SQL', 'NOTEBOOK')) DEFAULT 'SQL',
     payload JSONB NOT NULL DEFAULT '{}'
 );
 
@@ -1185,3 +1202,502 @@ BEFORE
 UPDATE
     ON slow_query FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- unused_index stores the indexes observed unused from the engine statistics for each database.
+CREATE TABLE unused_index (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    -- schema_name is empty for the engines without schema, such as MySQL.
+    schema_name TEXT NOT NULL,
+    table_name TEXT NOT NULL,
+    index_name TEXT NOT NULL,
+    -- unused_since_ts is the timestamp when the index was first observed unused.
+    unused_since_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_unused_index_unique_database_id_schema_name_table_name_index_name ON unused_index(database_id, schema_name, table_name, index_name);
+
+ALTER SEQUENCE unused_index_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_unused_index_updated_ts
+BEFORE
+UPDATE
+    ON unused_index FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- db_tls stores the TLS configuration of the database, which overrides the one of the instance data sources.
+-- The ssl_ca, ssl_cert and ssl_key are obfuscated as the ones in data_source.
+CREATE TABLE db_tls (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    ssl_ca TEXT NOT NULL DEFAULT '',
+    ssl_cert TEXT NOT NULL DEFAULT '',
+    ssl_key TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX idx_db_tls_unique_database_id ON db_tls(database_id);
+
+ALTER SEQUENCE db_tls_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_db_tls_updated_ts
+BEFORE
+UPDATE
+    ON db_tls FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- discovered_instance stores the database instances discovered from the cloud providers.
+-- The unregistered instances are proposed for onboarding, and the registered ones are flagged once deleted upstream.
+CREATE TABLE discovered_instance (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    provider TEXT NOT NULL CHECK (provider IN ('AWS', 'GCP', 'AZURE')),
+    -- external_id is the ARN for AWS, the connection name for GCP and the resource ID for Azure.
+    external_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    engine TEXT NOT NULL,
+    host TEXT NOT NULL,
+    port TEXT NOT NULL,
+    region TEXT NOT NULL,
+    labels JSONB NOT NULL DEFAULT '{}',
+    -- environment_id is the environment suggested by the environment mapping rules.
+    environment_id INTEGER REFERENCES environment (id),
+    -- instance_id is the registered instance with the same host and port.
+    instance_id INTEGER REFERENCES instance (id),
+    status TEXT NOT NULL CHECK (status IN ('PROPOSED', 'REGISTERED', 'DISMISSED', 'DELETED_UPSTREAM')),
+    last_seen_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_discovered_instance_unique_provider_external_id ON discovered_instance(provider, external_id);
+
+ALTER SEQUENCE discovered_instance_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_discovered_instance_updated_ts
+BEFORE
+UPDATE
+    ON discovered_instance FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- scheduled_query stores the sheets run on the cron schedules, whose results are delivered to the recipients.
+CREATE TABLE scheduled_query (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    sheet_id INTEGER NOT NULL REFERENCES sheet (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    -- schedule is the cron expression with 5 fields evaluated in the timezone.
+    schedule TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    -- delivery_list is the list of the email, Slack and webhook deliveries.
+    delivery_list JSONB NOT NULL DEFAULT '[]',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_ts BIGINT NOT NULL
+);
+
+CREATE INDEX idx_scheduled_query_sheet_id ON scheduled_query(sheet_id);
+
+ALTER SEQUENCE scheduled_query_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_scheduled_query_updated_ts
+BEFORE
+UPDATE
+    ON scheduled_query FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- scheduled_query_run stores the masked result snapshot and the delivery results of each run of the scheduled queries.
+CREATE TABLE scheduled_query_run (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    scheduled_query_id INTEGER NOT NULL REFERENCES scheduled_query (id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('SUCCESS', 'FAILED')),
+    result JSONB NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    delivery_result_list JSONB NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX idx_scheduled_query_run_scheduled_query_id ON scheduled_query_run(scheduled_query_id);
+
+ALTER SEQUENCE scheduled_query_run_id_seq RESTART WITH 101;
+
+-- notebook_cell_result stores the result of the latest run of each cell of the notebook sheets.
+CREATE TABLE notebook_cell_result (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    sheet_id INTEGER NOT NULL REFERENCES sheet (id) ON DELETE CASCADE,
+    cell_id TEXT NOT NULL,
+    -- parameter_values is the values of the parameters the cell ran with.
+    parameter_values JSONB NOT NULL DEFAULT '{}',
+    result JSONB NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX idx_notebook_cell_result_unique_sheet_id_cell_id ON notebook_cell_result(sheet_id, cell_id);
+
+ALTER SEQUENCE notebook_cell_result_id_seq RESTART WITH 101;
+
+-- query_history stores the statements executed in the SQL editor for the full-text search, the results aren't kept.
+CREATE TABLE query_history (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL,
+    -- database_id is 0 if the statement isn't executed in a database.
+    database_id INTEGER NOT NULL DEFAULT 0,
+    database_name TEXT NOT NULL DEFAULT '',
+    statement TEXT NOT NULL,
+    duration_ns BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_query_history_creator_id_created_ts ON query_history(creator_id, created_ts);
+
+CREATE INDEX idx_query_history_statement_tsv ON query_history USING GIN (to_tsvector('simple', statement));
+
+ALTER SEQUENCE query_history_id_seq RESTART WITH 101;
+
+-- snippet stores the short parameterized statement templates inserted in the SQL editor, shared in a project or the
+-- workspace.
+CREATE TABLE snippet (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    -- updated_ts is set by the updates explicitly instead of the trigger, so recording the usage doesn't change it.
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- project_id is the project the snippet is shared in, it's required by the PROJECT visibility.
+    project_id INTEGER REFERENCES project (id) ON DELETE CASCADE,
+    visibility TEXT NOT NULL CHECK (visibility IN ('PRIVATE', 'PROJECT', 'WORKSPACE')),
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    -- variable_list is the list of the variables of the "{{name}}" placeholders in the content.
+    variable_list JSONB NOT NULL DEFAULT '[]',
+    usage_count INTEGER NOT NULL DEFAULT 0,
+    last_used_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_snippet_creator_id ON snippet(creator_id);
+
+CREATE INDEX idx_snippet_project_id ON snippet(project_id);
+
+ALTER SEQUENCE snippet_id_seq RESTART WITH 101;
+
+-- admin_session stores the admin mode sessions of the SQL editor, whose transcripts are recorded for the audit.
+CREATE TABLE admin_session (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    instance_id INTEGER NOT NULL REFERENCES instance (id),
+    database_name TEXT NOT NULL DEFAULT '',
+    ended_ts BIGINT NOT NULL DEFAULT 0,
+    -- entry_count and last_hash are the head of the hash chain of the entries, so removing the last entries is detected.
+    entry_count INTEGER NOT NULL DEFAULT 0,
+    last_hash TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_admin_session_creator_id ON admin_session(creator_id);
+
+ALTER SEQUENCE admin_session_id_seq RESTART WITH 101;
+
+-- admin_session_entry stores the commands and the responses of the admin sessions in order. Each entry has the hash of
+-- its content and the hash of the previous entry, so the transcript is tamper-evident.
+CREATE TABLE admin_session_entry (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL REFERENCES admin_session (id),
+    seq INTEGER NOT NULL,
+    -- created_ts is set by the server instead of the default, as it's covered by the hash.
+    created_ts BIGINT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('COMMAND', 'RESPONSE')),
+    content TEXT NOT NULL,
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_admin_session_entry_unique_session_id_seq ON admin_session_entry(session_id, seq);
+
+ALTER SEQUENCE admin_session_entry_id_seq RESTART WITH 101;
+
+-- pii_finding stores the columns detected to contain the personal data by the PII scanner. The findings are proposed
+-- for review, and the accepted ones are added to the sensitive data policies of the databases.
+-- The sampled values are never stored, only the match counts.
+CREATE TABLE pii_finding (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    schema_name TEXT NOT NULL,
+    table_name TEXT NOT NULL,
+    column_name TEXT NOT NULL,
+    classification TEXT NOT NULL CHECK (classification IN ('EMAIL', 'PHONE', 'SSN', 'CREDIT_CARD')),
+    -- mask_type is the mask type proposed for the sensitive data policy.
+    mask_type TEXT NOT NULL,
+    name_matched BOOLEAN NOT NULL,
+    sample_count INTEGER NOT NULL,
+    match_count INTEGER NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PROPOSED', 'ACCEPTED', 'DISMISSED')),
+    last_scanned_ts BIGINT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_pii_finding_unique_database_id_schema_name_table_name_column_name ON pii_finding(database_id, schema_name, table_name, column_name);
+
+ALTER SEQUENCE pii_finding_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_pii_finding_updated_ts
+BEFORE
+UPDATE
+    ON pii_finding FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- role_member stores the custom roles assigned at the workspace scope, whose permissions are granted in all projects.
+CREATE TABLE role_member (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    role TEXT NOT NULL REFERENCES role (resource_id),
+    principal_id INTEGER NOT NULL REFERENCES principal (id)
+);
+
+CREATE UNIQUE INDEX idx_role_member_unique_role_principal_id ON role_member(role, principal_id);
+
+CREATE INDEX idx_role_member_principal_id ON role_member(principal_id);
+
+ALTER SEQUENCE role_member_id_seq RESTART WITH 101;
+
+-- issue_approval_step stores the state of the pending approval step of the issues for the escalations and the
+-- external approvers, it's reset whenever the pending step changes.
+CREATE TABLE issue_approval_step (
+    issue_id INTEGER PRIMARY KEY REFERENCES issue (id) ON DELETE CASCADE,
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- risk_level is the risk level the approval flow is found for.
+    risk_level BIGINT NOT NULL DEFAULT 0,
+    -- step is the index of the pending step in the approval flow, -1 before the approval flow is found.
+    step INTEGER NOT NULL DEFAULT -1,
+    started_ts BIGINT NOT NULL DEFAULT 0,
+    escalated_ts BIGINT NOT NULL DEFAULT 0,
+    external_status TEXT NOT NULL DEFAULT '' CHECK (external_status IN ('', 'REQUESTED', 'REJECTED')),
+    -- external_token is the token of the callback URL for the external approver of the step.
+    external_token TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_issue_approval_step_external_token ON issue_approval_step(external_token);
+
+CREATE TRIGGER update_issue_approval_step_updated_ts
+BEFORE
+UPDATE
+    ON issue_approval_step FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- auto_approval logs the issues approved by the auto-approval rules, the decision is revoked to require the approvers
+-- again.
+CREATE TABLE auto_approval (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    issue_id INTEGER NOT NULL REFERENCES issue (id) ON DELETE CASCADE,
+    risk_level BIGINT NOT NULL DEFAULT 0,
+    rule_id TEXT NOT NULL,
+    -- condition is the condition of the rule at the decision, the rule may be changed or removed later.
+    condition TEXT NOT NULL,
+    revoker_id INTEGER REFERENCES principal (id),
+    revoked_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_auto_approval_issue_id ON auto_approval(issue_id);
+
+ALTER SEQUENCE auto_approval_id_seq RESTART WITH 101;
+
+-- database_grant stores the temporary elevated access to a database requested by the users, which is revoked
+-- automatically after it expires.
+CREATE TABLE database_grant (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    level TEXT NOT NULL CHECK (level IN ('QUERIER', 'ADMIN')),
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'ACTIVE', 'REJECTED', 'REVOKED', 'EXPIRED')),
+    duration_seconds BIGINT NOT NULL,
+    justification TEXT NOT NULL,
+    -- expire_ts is set when the grant is approved.
+    expire_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_database_grant_database_id ON database_grant(database_id);
+
+CREATE INDEX idx_database_grant_status ON database_grant(status);
+
+ALTER SEQUENCE database_grant_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_database_grant_updated_ts
+BEFORE
+UPDATE
+    ON database_grant FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- audit_sink_cursor stores the ID of the last activity delivered to each audit sink.
+CREATE TABLE audit_sink_cursor (
+    sink_id TEXT PRIMARY KEY,
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    activity_id INTEGER NOT NULL
+);
+
+-- export_request stores the approvals of exporting the query results required by the export policy.
+CREATE TABLE export_request (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'USED')),
+    row_count INTEGER NOT NULL,
+    column_names TEXT[] NOT NULL,
+    justification TEXT NOT NULL,
+    -- expire_ts is set when the request is approved.
+    expire_ts BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_export_request_creator_id ON export_request(creator_id);
+
+ALTER SEQUENCE export_request_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_export_request_updated_ts
+BEFORE
+UPDATE
+    ON export_request FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- database_clone stores the copies of the databases into the lower environments, with the classified columns masked.
+CREATE TABLE database_clone (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    source_database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    target_instance_id INTEGER NOT NULL REFERENCES instance (id) ON DELETE CASCADE,
+    target_database_name TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'RUNNING', 'DONE', 'FAILED')),
+    table_count INTEGER NOT NULL DEFAULT 0,
+    row_count BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_database_clone_status ON database_clone(status);
+
+ALTER SEQUENCE database_clone_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_database_clone_updated_ts
+BEFORE
+UPDATE
+    ON database_clone FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- repository_release stores the immutable releases built from the git tags of the repositories in the release mode.
+CREATE TABLE repository_release (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    project_id INTEGER NOT NULL REFERENCES project (id),
+    tag TEXT NOT NULL,
+    commit_id TEXT NOT NULL,
+    -- files is the ordered migration files with the checksums and the SQL review results.
+    files JSONB NOT NULL DEFAULT '[]',
+    -- checksum is the checksum of the files to verify the release is not changed.
+    checksum TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_repository_release_unique_project_id_tag ON repository_release(project_id, tag);
+
+ALTER SEQUENCE repository_release_id_seq RESTART WITH 101;
+
+-- webhook_event stores the raw VCS webhook deliveries, so that the failed ones can be replayed.
+CREATE TABLE webhook_event (
+    id SERIAL PRIMARY KEY,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    -- path is the request path, e.g. /hook/github/{webhook_endpoint_id}.
+    path TEXT NOT NULL,
+    header JSONB NOT NULL DEFAULT '{}',
+    body TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PROCESSING', 'DONE', 'FAILED')),
+    -- error is the error of the last delivery or replay.
+    error TEXT NOT NULL DEFAULT '',
+    attempt_count INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX idx_webhook_event_status ON webhook_event(status);
+
+ALTER SEQUENCE webhook_event_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_webhook_event_updated_ts
+BEFORE
+UPDATE
+    ON webhook_event FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- alert_incident stores the incidents opened on each alert receiver, they are resolved after the conditions clear.
+CREATE TABLE alert_incident (
+    receiver_id TEXT NOT NULL,
+    dedup_key TEXT NOT NULL,
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    PRIMARY KEY (receiver_id, dedup_key)
+);
+
+-- user_group stores the groups of the users, they're provisioned by the identity providers through SCIM.
+CREATE TABLE user_group (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    title TEXT NOT NULL,
+    external_id TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX idx_user_group_unique_title ON user_group(title);
+
+ALTER SEQUENCE user_group_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_user_group_updated_ts
+BEFORE
+UPDATE
+    ON user_group FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- user_group_member stores the members of the user groups.
+CREATE TABLE user_group_member (
+    group_id INTEGER NOT NULL REFERENCES user_group (id) ON DELETE CASCADE,
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    PRIMARY KEY (group_id, principal_id)
+);
+
+CREATE INDEX idx_user_group_member_principal_id ON user_group_member(principal_id);
+
+-- sso_group_membership stores the identity provider groups of the users on their last SSO login, and the project
+-- roles granted to them by the SSO group mapping, so the mapping can be re-evaluated and the roles can be revoked.
+CREATE TABLE sso_group_membership (
+    principal_id INTEGER PRIMARY KEY REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    idp TEXT NOT NULL,
+    group_list TEXT[] NOT NULL DEFAULT '{}',
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE TRIGGER update_sso_group_membership_updated_ts
+BEFORE
+UPDATE
+    ON sso_group_membership FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
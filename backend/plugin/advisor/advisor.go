@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap/zapcore"
@@ -60,6 +61,9 @@ const (
 	// MySQLMigrationCompatibility is an advisor type for MySQL migration compatibility.
 	MySQLMigrationCompatibility Type = "bb.plugin.advisor.mysql.migration-compatibility"
 
+	// MySQLRequireIdempotentMigration is an advisor type for MySQL requiring idempotent migrations.
+	MySQLRequireIdempotentMigration Type = "bb.plugin.advisor.mysql.schema.require-idempotent-migration"
+
 	// MySQLWhereRequirement is an advisor type for MySQL WHERE clause requirement.
 	MySQLWhereRequirement Type = "bb.plugin.advisor.mysql.where.require"
 
@@ -147,6 +151,9 @@ const (
 	// MySQLTableDisallowPartition is an advisor type for MySQL disallow table partition.
 	MySQLTableDisallowPartition Type = "bb.plugin.advisor.mysql.table.disallow-partition"
 
+	// MySQLTableRequirePartition is an advisor type for MySQL requiring the high-volume tables to be partitioned.
+	MySQLTableRequirePartition Type = "bb.plugin.advisor.mysql.table.require-partition"
+
 	// MySQLDatabaseAllowDropIfEmpty is an advisor type for MySQL only allow drop empty database.
 	MySQLDatabaseAllowDropIfEmpty Type = "bb.plugin.advisor.mysql.database.drop-empty-database"
 
@@ -165,6 +172,16 @@ const (
 	// MySQLIndexTotalNumberLimit is an advisor type for MySQL index total number limit.
 	MySQLIndexTotalNumberLimit Type = "bb.plugin.advisor.mysql.index.total-number-limit"
 
+	// MySQLIndexNoRedundant is an advisor type for MySQL no duplicate or redundant index.
+	MySQLIndexNoRedundant Type = "bb.plugin.advisor.mysql.index.no-redundant"
+
+	// MySQLIndexDropRequireInvisible is an advisor type for MySQL requiring invisible indexes before dropping them.
+	MySQLIndexDropRequireInvisible Type = "bb.plugin.advisor.mysql.index.drop-require-invisible"
+
+	// MySQLIndexUsage is an advisor type for attaching MySQL index usage context to the dropped indexes.
+	// It's not a SQL review rule and runs whenever the unused indexes are provided.
+	MySQLIndexUsage Type = "bb.plugin.advisor.mysql.index.usage"
+
 	// MySQLCharsetAllowlist is an advisor type for MySQL charset allowlist.
 	MySQLCharsetAllowlist Type = "bb.plugin.advisor.mysql.charset.allowlist"
 
@@ -177,6 +194,9 @@ const (
 	// MySQLStatementDisallowCommit is an advisor type for MySQL to disallow commit.
 	MySQLStatementDisallowCommit Type = "bb.plugin.advisor.mysql.statement.disallow-commit"
 
+	// MySQLStatementDisallowHardCodedSecret is an advisor type for MySQL to disallow the hard-coded secrets.
+	MySQLStatementDisallowHardCodedSecret Type = "bb.plugin.advisor.mysql.statement.disallow-hard-coded-secret"
+
 	// MySQLDisallowLimit is an advisor type for MySQL no LIMIT clause in INSERT/UPDATE/DELETE statement.
 	MySQLDisallowLimit Type = "bb.plugin.advisor.mysql.statement.disallow-limit"
 
@@ -248,12 +268,24 @@ const (
 	// PostgreSQLMigrationCompatibility is an advisor type for PostgreSQL migration compatibility.
 	PostgreSQLMigrationCompatibility Type = "bb.plugin.advisor.postgresql.migration-compatibility"
 
+	// PostgreSQLRequireIdempotentMigration is an advisor type for PostgreSQL requiring idempotent migrations.
+	PostgreSQLRequireIdempotentMigration Type = "bb.plugin.advisor.postgresql.schema.require-idempotent-migration"
+
 	// PostgreSQLTableNoFK is an advisor type for PostgreSQL table disallow foreign key.
 	PostgreSQLTableNoFK Type = "bb.plugin.advisor.postgresql.table.no-foreign-key"
 
 	// PostgreSQLTableDisallowPartition is an advisor type for PostgreSQL disallow table partition.
 	PostgreSQLTableDisallowPartition Type = "bb.plugin.advisor.postgresql.table.disallow-partition"
 
+	// PostgreSQLTableRequirePartition is an advisor type for PostgreSQL requiring the high-volume tables to be partitioned.
+	PostgreSQLTableRequirePartition Type = "bb.plugin.advisor.postgresql.table.require-partition"
+
+	// PostgreSQLTableRequireHypertable is an advisor type for PostgreSQL requiring the time-series tables to be TimescaleDB hypertables.
+	PostgreSQLTableRequireHypertable Type = "bb.plugin.advisor.postgresql.table.require-hypertable"
+
+	// PostgreSQLTableRequireRowLevelSecurity is an advisor type for PostgreSQL requiring the labeled tables to have row level security policies.
+	PostgreSQLTableRequireRowLevelSecurity Type = "bb.plugin.advisor.postgresql.table.require-row-level-security"
+
 	// PostgreSQLInsertRowLimit is an advisor type for PostgreSQL to limit INSERT rows.
 	PostgreSQLInsertRowLimit Type = "bb.plugin.advisor.postgresql.insert.row-limit"
 
@@ -278,6 +310,13 @@ const (
 	// PostgreSQLIndexNoDuplicateColumn is an advisor type for Postgresql no duplicate columns in index.
 	PostgreSQLIndexNoDuplicateColumn Type = "bb.plugin.advisor.postgresql.index.no-duplicate-column"
 
+	// PostgreSQLIndexNoRedundant is an advisor type for PostgreSQL no duplicate or redundant index.
+	PostgreSQLIndexNoRedundant Type = "bb.plugin.advisor.postgresql.index.no-redundant"
+
+	// PostgreSQLIndexUsage is an advisor type for attaching PostgreSQL index usage context to the dropped indexes.
+	// It's not a SQL review rule and runs whenever the unused indexes are provided.
+	PostgreSQLIndexUsage Type = "bb.plugin.advisor.postgresql.index.usage"
+
 	// PostgreSQLCreateIndexConcurrently is an advisor type for PostgreSQL to create index concurrently.
 	PostgreSQLCreateIndexConcurrently Type = "bb.plugin.advisor.postgresql.index.create-concurrently"
 
@@ -296,6 +335,9 @@ const (
 	// PostgreSQLStatementDisallowCommit is an advisor type for PostgreSQL to disallow commit.
 	PostgreSQLStatementDisallowCommit Type = "bb.plugin.advisor.postgresql.statement.disallow-commit"
 
+	// PostgreSQLStatementDisallowHardCodedSecret is an advisor type for PostgreSQL to disallow the hard-coded secrets.
+	PostgreSQLStatementDisallowHardCodedSecret Type = "bb.plugin.advisor.postgresql.statement.disallow-hard-coded-secret"
+
 	// PostgreSQLStatementDMLDryRun is an advisor type for PostgreSQL DML dry run.
 	PostgreSQLStatementDMLDryRun Type = "bb.plugin.advisor.postgresql.statement.dml-dry-run"
 
@@ -319,6 +361,56 @@ const (
 
 	// PostgreSQLCollationAllowlist is an advisor type for PostgreSQL collation allowlist.
 	PostgreSQLCollationAllowlist Type = "bb.plugin.advisor.postgresql.collation.allowlist"
+
+	// Snowflake Advisor.
+
+	// SnowflakeNamingStage is an advisor type for Snowflake stage naming convention.
+	SnowflakeNamingStage Type = "bb.plugin.advisor.snowflake.naming.stage"
+
+	// SnowflakeNamingPipe is an advisor type for Snowflake pipe naming convention.
+	SnowflakeNamingPipe Type = "bb.plugin.advisor.snowflake.naming.pipe"
+
+	// SnowflakeTableRequireClusteringKey is an advisor type for Snowflake requiring the clustering key for the large tables.
+	SnowflakeTableRequireClusteringKey Type = "bb.plugin.advisor.snowflake.table.require-clustering-key"
+
+	// SnowflakeSchemaDisallowPublicObject is an advisor type for Snowflake disallowing creating objects in the PUBLIC schema.
+	SnowflakeSchemaDisallowPublicObject Type = "bb.plugin.advisor.snowflake.schema.disallow-public-object"
+
+	// Redis Advisor.
+
+	// RedisCommandDisallowList is an advisor type for Redis command disallow list.
+	RedisCommandDisallowList Type = "bb.plugin.advisor.redis.statement.command-disallow-list"
+
+	// MongoDB Advisor.
+
+	// MongoDBCollectionDisallowDrop is an advisor type for MongoDB disallowing dropping collections.
+	MongoDBCollectionDisallowDrop Type = "bb.plugin.advisor.mongodb.table.disallow-drop-collection"
+
+	// MongoDBIndexRequireBackground is an advisor type for MongoDB requiring creating indexes in the background.
+	MongoDBIndexRequireBackground Type = "bb.plugin.advisor.mongodb.index.require-background"
+
+	// MongoDBStatementRequireFilter is an advisor type for MongoDB requiring the filter document for updateMany and deleteMany.
+	MongoDBStatementRequireFilter Type = "bb.plugin.advisor.mongodb.statement.require-filter"
+
+	// Elasticsearch Advisor.
+
+	// ElasticsearchStatementRequireFilter is an advisor type for Elasticsearch requiring the query for _delete_by_query and _update_by_query.
+	ElasticsearchStatementRequireFilter Type = "bb.plugin.advisor.elasticsearch.statement.require-filter"
+
+	// Cassandra Advisor.
+
+	// CassandraStatementDisallowAllowFiltering is an advisor type for Cassandra disallowing ALLOW FILTERING.
+	CassandraStatementDisallowAllowFiltering Type = "bb.plugin.advisor.cassandra.statement.disallow-allow-filtering"
+
+	// DynamoDB Advisor.
+
+	// DynamoDBStatementDisallowFullTableScan is an advisor type for DynamoDB disallowing the PartiQL SELECT without WHERE.
+	DynamoDBStatementDisallowFullTableScan Type = "bb.plugin.advisor.dynamodb.statement.disallow-full-table-scan"
+
+	// Neo4j Advisor.
+
+	// Neo4jRequireIdempotentMigration is an advisor type for Neo4j requiring idempotent constraint and index changes.
+	Neo4jRequireIdempotentMigration Type = "bb.plugin.advisor.neo4j.schema.require-idempotent-migration"
 )
 
 // Advice is the result of an advisor.
@@ -378,6 +470,20 @@ type Context struct {
 	Catalog *catalog.Finder
 	Driver  *sql.DB
 	Context context.Context
+
+	// UnusedIndexList is the indexes observed unused from the engine statistics.
+	UnusedIndexList []*UnusedIndex
+	// ChangeHistoryList is the applied changes of the database, ordered from the latest to the earliest.
+	ChangeHistoryList []*ChangeHistory
+	// TemplateStatement is the statement before rendering the database secrets, empty if not rendered.
+	TemplateStatement string
+}
+
+// ChangeHistory is a change applied to the database.
+type ChangeHistory struct {
+	Statement string
+	// CreatedTime is the time when the change was applied.
+	CreatedTime time.Time
 }
 
 // Advisor is the interface for advisor.
@@ -447,7 +553,7 @@ func IsSyntaxCheckSupported(dbType db.Type) bool {
 // IsSQLReviewSupported checks the engine type if SQL review supports it.
 func IsSQLReviewSupported(dbType db.Type) bool {
 	switch dbType {
-	case db.MySQL, db.TiDB, db.MariaDB, db.Postgres:
+	case db.MySQL, db.TiDB, db.MariaDB, db.Postgres, db.Snowflake, db.Redis, db.MongoDB, db.Elasticsearch, db.Cassandra, db.DynamoDB, db.Neo4j:
 		return true
 	}
 	return false
@@ -0,0 +1,78 @@
+// Package cassandra implements the SQL review rules for Cassandra.
+package cassandra
+
+import (
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/cql"
+)
+
+var (
+	_ advisor.Advisor = (*StatementDisallowAllowFilteringAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Cassandra, advisor.CassandraStatementDisallowAllowFiltering, &StatementDisallowAllowFilteringAdvisor{})
+}
+
+// StatementDisallowAllowFilteringAdvisor is the advisor checking for ALLOW FILTERING.
+type StatementDisallowAllowFilteringAdvisor struct {
+}
+
+// Check checks for ALLOW FILTERING.
+func (*StatementDisallowAllowFilteringAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		i := stmt.FindKeywords("ALLOW", "FILTERING")
+		if i < 0 {
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.StatementAllowFiltering,
+			Title:   string(ctx.Rule.Type),
+			Content: "ALLOW FILTERING scans all the partitions, please query by the partition key or use an index or a materialized view instead",
+			Line:    stmt.Tokens[i].Line,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+func parseStatement(statement string) ([]*cql.Statement, []advisor.Advice) {
+	stmtList, err := cql.SplitStatements(statement)
+	if err != nil {
+		line := 0
+		if syntaxErr, ok := err.(*cql.SyntaxError); ok {
+			line = syntaxErr.Line
+		}
+		return nil, []advisor.Advice{
+			{
+				Status:  advisor.Error,
+				Code:    advisor.StatementSyntaxError,
+				Title:   advisor.SyntaxErrorTitle,
+				Content: err.Error(),
+				Line:    line,
+			},
+		}
+	}
+	return stmtList, nil
+}
@@ -0,0 +1,18 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+func TestCassandraRules(t *testing.T) {
+	cassandraRules := []advisor.SQLReviewRuleType{
+		advisor.SchemaRuleStatementCassandraDisallowAllowFiltering,
+	}
+
+	for _, rule := range cassandraRules {
+		advisor.RunSQLReviewRuleTest(t, rule, db.Cassandra, false /* record */)
+	}
+}
@@ -6,6 +6,7 @@ package catalog
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
@@ -350,6 +351,18 @@ func (table *TableState) CountIndex() int {
 	return len(table.indexSet)
 }
 
+// IndexList returns the index list of the table in lexicographical order of the index name.
+func (table *TableState) IndexList() []*IndexState {
+	var indexList []*IndexState
+	for _, index := range table.indexSet {
+		indexList = append(indexList, index)
+	}
+	sort.Slice(indexList, func(i, j int) bool {
+		return indexList[i].name < indexList[j].name
+	})
+	return indexList
+}
+
 func (table *TableState) copy() *TableState {
 	return &TableState{
 		name:      table.name,
@@ -396,6 +409,11 @@ func (idx *IndexState) copy() *IndexState {
 	}
 }
 
+// Name returns the name for the index.
+func (idx *IndexState) Name() string {
+	return idx.name
+}
+
 // Unique returns the unique for the index.
 func (idx *IndexState) Unique() bool {
 	if idx.unique != nil {
@@ -412,6 +430,15 @@ func (idx *IndexState) Primary() bool {
 	return false
 }
 
+// Visible returns the visible for the index.
+// The index is visible if the visibility is unknown.
+func (idx *IndexState) Visible() bool {
+	if idx.visible != nil {
+		return *idx.visible
+	}
+	return true
+}
+
 // ExpressionList returns the expression list for the index.
 func (idx *IndexState) ExpressionList() []string {
 	return idx.expressionList
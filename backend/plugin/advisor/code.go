@@ -36,6 +36,11 @@ const (
 	StatementAddColumnWithDefault    Code = 210
 	StatementAddCheckWithValidation  Code = 211
 	StatementAddNotNull              Code = 212
+	StatementDisallowedCommand       Code = 213
+	StatementNoFilter                Code = 214
+	StatementHardCodedSecret         Code = 215
+	StatementAllowFiltering          Code = 216
+	StatementFullTableScan           Code = 217
 
 	// 301 ～ 399 naming error code
 	// 301 table naming advisor error code.
@@ -52,6 +57,10 @@ const (
 	NamingPKConventionMismatch Code = 306
 	// 307 auto_increment  column naming advisor error code.
 	NamingAutoIncrementColumnConventionMismatch Code = 307
+	// 308 stage naming advisor error code.
+	NamingStageConventionMismatch Code = 308
+	// 309 pipe naming advisor error code.
+	NamingPipeConventionMismatch Code = 309
 
 	// 401 ~ 499 column error code.
 	NoRequiredColumn                           Code = 401
@@ -89,6 +98,11 @@ const (
 	TableExists                       Code = 607
 	CreateTablePartition              Code = 608
 	TableIsReferencedByView           Code = 609
+	TableNoClusteringKey              Code = 610
+	CollectionDrop                    Code = 611
+	TableNoPartition                  Code = 612
+	TableNotHypertable                Code = 613
+	TableNoRowLevelSecurity           Code = 614
 
 	// 701 ~ 799 database advisor error code.
 	DatabaseNotEmpty   Code = 701
@@ -110,6 +124,10 @@ const (
 	DuplicateColumnInIndex     Code = 812
 	IndexCountExceedsLimit     Code = 813
 	CreateIndexUnconcurrently  Code = 814
+	RedundantIndex             Code = 815
+	IndexUnused                Code = 816
+	IndexDropBeforeInvisible   Code = 817
+	IndexCreateNotBackground   Code = 818
 
 	// 1001 ~ 1099 charset error code.
 	DisabledCharset Code = 1001
@@ -129,6 +147,10 @@ const (
 
 	// 1301 ~ 1399 comment error code.
 	CommentTooLong Code = 1301
+
+	// 1401 ~ 1499 schema error code.
+	CreateObjectInPublicSchema Code = 1401
+	MigrationNotIdempotent     Code = 1402
 )
 
 // Int returns the int type of code.
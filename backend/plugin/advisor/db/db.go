@@ -21,6 +21,20 @@ const (
 	TiDB Type = "TIDB"
 	// MariaDB is the database type for MariaDB.
 	MariaDB Type = "MARIADB"
+	// Snowflake is the database type for Snowflake.
+	Snowflake Type = "SNOWFLAKE"
+	// Redis is the database type for Redis.
+	Redis Type = "REDIS"
+	// MongoDB is the database type for MongoDB.
+	MongoDB Type = "MONGODB"
+	// Elasticsearch is the database type for Elasticsearch.
+	Elasticsearch Type = "ELASTICSEARCH"
+	// Cassandra is the database type for Cassandra.
+	Cassandra Type = "CASSANDRA"
+	// DynamoDB is the database type for DynamoDB.
+	DynamoDB Type = "DYNAMODB"
+	// Neo4j is the database type for Neo4j.
+	Neo4j Type = "NEO4J"
 )
 
 // ConvertToAdvisorDBType will convert db type into advisor db type.
@@ -32,6 +46,20 @@ func ConvertToAdvisorDBType(dbType string) (Type, error) {
 		return Postgres, nil
 	case string(TiDB):
 		return TiDB, nil
+	case string(Snowflake):
+		return Snowflake, nil
+	case string(Redis):
+		return Redis, nil
+	case string(MongoDB):
+		return MongoDB, nil
+	case string(Elasticsearch):
+		return Elasticsearch, nil
+	case string(Cassandra):
+		return Cassandra, nil
+	case string(DynamoDB):
+		return DynamoDB, nil
+	case string(Neo4j):
+		return Neo4j, nil
 	}
 
 	return "", errors.Errorf("unsupported db type %s for advisor", dbType)
@@ -0,0 +1,80 @@
+// Package dynamodb implements the SQL review rules for DynamoDB PartiQL.
+package dynamodb
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/partiql"
+)
+
+var (
+	_ advisor.Advisor = (*StatementDisallowFullTableScanAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.DynamoDB, advisor.DynamoDBStatementDisallowFullTableScan, &StatementDisallowFullTableScanAdvisor{})
+}
+
+// StatementDisallowFullTableScanAdvisor is the advisor checking for the SELECT without WHERE.
+type StatementDisallowFullTableScanAdvisor struct {
+}
+
+// Check checks for the SELECT without WHERE.
+func (*StatementDisallowFullTableScanAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		// DynamoDB requires the full primary key in the WHERE of UPDATE and DELETE, so only SELECT could scan the table.
+		if stmt.Kind() != "SELECT" || stmt.FindKeyword("WHERE") >= 0 {
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.StatementFullTableScan,
+			Title:   string(ctx.Rule.Type),
+			Content: fmt.Sprintf("SELECT on table \"%s\" without WHERE scans the whole table", stmt.Table()),
+			Line:    stmt.Line,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+func parseStatement(statement string) ([]*partiql.Statement, []advisor.Advice) {
+	stmtList, err := partiql.SplitStatements(statement)
+	if err != nil {
+		line := 0
+		if syntaxErr, ok := err.(*partiql.SyntaxError); ok {
+			line = syntaxErr.Line
+		}
+		return nil, []advisor.Advice{
+			{
+				Status:  advisor.Error,
+				Code:    advisor.StatementSyntaxError,
+				Title:   advisor.SyntaxErrorTitle,
+				Content: err.Error(),
+				Line:    line,
+			},
+		}
+	}
+	return stmtList, nil
+}
@@ -0,0 +1,18 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+func TestDynamoDBRules(t *testing.T) {
+	dynamodbRules := []advisor.SQLReviewRuleType{
+		advisor.SchemaRuleStatementDynamoDBDisallowFullTableScan,
+	}
+
+	for _, rule := range dynamodbRules {
+		advisor.RunSQLReviewRuleTest(t, rule, db.DynamoDB, false /* record */)
+	}
+}
@@ -0,0 +1,111 @@
+// Package elasticsearch implements the SQL review rules for Elasticsearch.
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/elasticsearch"
+)
+
+var (
+	_ advisor.Advisor = (*StatementRequireFilterAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Elasticsearch, advisor.ElasticsearchStatementRequireFilter, &StatementRequireFilterAdvisor{})
+}
+
+// StatementRequireFilterAdvisor is the advisor checking for the query of _delete_by_query and _update_by_query.
+type StatementRequireFilterAdvisor struct {
+}
+
+// Check checks for the query of _delete_by_query and _update_by_query.
+func (*StatementRequireFilterAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	requestList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, request := range requestList {
+		endpoint := request.Endpoint()
+		if endpoint != "_delete_by_query" && endpoint != "_update_by_query" {
+			continue
+		}
+		if hasFilter(request) {
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.StatementNoFilter,
+			Title:   string(ctx.Rule.Type),
+			Content: fmt.Sprintf("%s on index \"%s\" requires a query other than match_all", endpoint, request.Index()),
+			Line:    request.Line,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+func parseStatement(statement string) ([]*parser.Request, []advisor.Advice) {
+	requestList, err := parser.ParseRequests(statement)
+	if err != nil {
+		line := 0
+		if syntaxErr, ok := err.(*parser.SyntaxError); ok {
+			line = syntaxErr.Line
+		}
+		return nil, []advisor.Advice{
+			{
+				Status:  advisor.Error,
+				Code:    advisor.StatementSyntaxError,
+				Title:   advisor.SyntaxErrorTitle,
+				Content: err.Error(),
+				Line:    line,
+			},
+		}
+	}
+	return requestList, nil
+}
+
+// hasFilter returns true if the request has the query in the body or the "q" parameter, and the query is not match_all.
+func hasFilter(request *parser.Request) bool {
+	if _, rawQuery, found := strings.Cut(request.Path, "?"); found {
+		if values, err := url.ParseQuery(rawQuery); err == nil {
+			if q := strings.TrimSpace(values.Get("q")); q != "" {
+				return q != "*"
+			}
+		}
+	}
+
+	var body struct {
+		Query map[string]json.RawMessage `json:"query"`
+	}
+	if request.Body == "" || json.Unmarshal([]byte(request.Body), &body) != nil {
+		return false
+	}
+	if len(body.Query) == 0 {
+		return false
+	}
+	if _, ok := body.Query["match_all"]; ok && len(body.Query) == 1 {
+		return false
+	}
+	return true
+}
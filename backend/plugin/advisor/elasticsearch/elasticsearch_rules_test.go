@@ -0,0 +1,18 @@
+package elasticsearch
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+func TestElasticsearchRules(t *testing.T) {
+	elasticsearchRules := []advisor.SQLReviewRuleType{
+		advisor.SchemaRuleStatementElasticsearchRequireFilter,
+	}
+
+	for _, rule := range elasticsearchRules {
+		advisor.RunSQLReviewRuleTest(t, rule, db.Elasticsearch, false /* record */)
+	}
+}
@@ -0,0 +1,70 @@
+package advisor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+// UnusedIndexTitle is the advice title for the index usage context.
+const UnusedIndexTitle = "Unused index"
+
+var indexUsageAdvisorType = map[db.Type]Type{
+	db.MySQL:    MySQLIndexUsage,
+	db.Postgres: PostgreSQLIndexUsage,
+}
+
+// UnusedIndex is an index observed unused from the engine statistics.
+type UnusedIndex struct {
+	// Schema is empty for the engines without schema, such as MySQL.
+	Schema string
+	Table  string
+	Name   string
+	// UnusedSince is the time when the index was first observed unused.
+	UnusedSince time.Time
+}
+
+// FindUnusedIndex finds the unused index by schema, table and index name.
+// The table is skipped if it's empty, because some engines can drop the index without the table name.
+// The names are compared case-insensitively if caseSensitive is false.
+func FindUnusedIndex(list []*UnusedIndex, schema string, table string, name string, caseSensitive bool) *UnusedIndex {
+	equal := func(a, b string) bool {
+		if caseSensitive {
+			return a == b
+		}
+		return strings.EqualFold(a, b)
+	}
+	for _, index := range list {
+		if !equal(index.Schema, schema) || !equal(index.Name, name) {
+			continue
+		}
+		if table != "" && !equal(index.Table, table) {
+			continue
+		}
+		return index
+	}
+	return nil
+}
+
+// NewUnusedIndexAdvice returns the index usage context advice for the dropped index.
+// The indexName and tableName are the quoted names used in the advice content.
+// It returns nil if the index is observed unused for less than one day.
+func NewUnusedIndexAdvice(index *UnusedIndex, indexName string, tableName string, line int) *Advice {
+	days := int(time.Since(index.UnusedSince).Hours() / 24)
+	if days < 1 {
+		return nil
+	}
+	unit := "days"
+	if days == 1 {
+		unit = "day"
+	}
+	return &Advice{
+		Status:  Success,
+		Code:    IndexUnused,
+		Title:   UnusedIndexTitle,
+		Content: fmt.Sprintf("Index %s on table %s appears unused for %d %s", indexName, tableName, days, unit),
+		Line:    line,
+	}
+}
@@ -0,0 +1,36 @@
+package advisor
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+// slowRuleThreshold is the latency threshold to log a slow SQL review rule.
+const slowRuleThreshold = 5 * time.Second
+
+var ruleLatency = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "bytebase",
+		Subsystem: "sql_review",
+		Name:      "rule_duration_seconds",
+		Help:      "The latency of checking the statements with a SQL review rule.",
+		Buckets:   []float64{.001, .005, .01, .05, .1, .5, 1, 5, 10, 30},
+	},
+	[]string{"engine", "rule"},
+)
+
+func init() {
+	prometheus.MustRegister(ruleLatency)
+}
+
+func observeRuleLatency(engine db.Type, ruleType SQLReviewRuleType, duration time.Duration) {
+	ruleLatency.WithLabelValues(string(engine), string(ruleType)).Observe(duration.Seconds())
+	if duration >= slowRuleThreshold {
+		log.Warn("slow SQL review rule", zap.String("engine", string(engine)), zap.String("rule", string(ruleType)), zap.Duration("duration", duration))
+	}
+}
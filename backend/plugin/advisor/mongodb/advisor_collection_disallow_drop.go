@@ -0,0 +1,52 @@
+package mongodb
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*CollectionDisallowDropAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.MongoDB, advisor.MongoDBCollectionDisallowDrop, &CollectionDisallowDropAdvisor{})
+}
+
+// CollectionDisallowDropAdvisor is the advisor checking for disallowing dropping collections.
+type CollectionDisallowDropAdvisor struct {
+}
+
+// Check checks for disallowing dropping collections.
+func (*CollectionDisallowDropAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, call := range parseMethodCallList(statement) {
+		if call.method != "drop" {
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.CollectionDrop,
+			Title:   string(ctx.Rule.Type),
+			Content: fmt.Sprintf("Dropping collection \"%s\" is disallowed", call.collection),
+			Line:    call.line,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
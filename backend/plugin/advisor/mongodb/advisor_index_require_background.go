@@ -0,0 +1,61 @@
+package mongodb
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*IndexRequireBackgroundAdvisor)(nil)
+
+	backgroundOptionRegexp = regexp.MustCompile(`(?:^|[{,\s])["']?background["']?\s*:\s*true\b`)
+)
+
+func init() {
+	advisor.Register(db.MongoDB, advisor.MongoDBIndexRequireBackground, &IndexRequireBackgroundAdvisor{})
+}
+
+// IndexRequireBackgroundAdvisor is the advisor checking for creating indexes in the background.
+type IndexRequireBackgroundAdvisor struct {
+}
+
+// Check checks for creating indexes in the background.
+func (*IndexRequireBackgroundAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, call := range parseMethodCallList(statement) {
+		switch call.method {
+		case "createIndex", "createIndexes", "ensureIndex":
+		default:
+			continue
+		}
+		// The options are the second argument for all the index creation methods.
+		if len(call.argumentList) > 1 && backgroundOptionRegexp.MatchString(call.argumentList[1]) {
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.IndexCreateNotBackground,
+			Title:   string(ctx.Rule.Type),
+			Content: fmt.Sprintf("The index on collection \"%s\" should be created with { background: true }, or built in a rolling fashion on the replica set members", call.collection),
+			Line:    call.line,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
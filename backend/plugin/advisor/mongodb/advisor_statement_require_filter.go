@@ -0,0 +1,67 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*StatementRequireFilterAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.MongoDB, advisor.MongoDBStatementRequireFilter, &StatementRequireFilterAdvisor{})
+}
+
+// StatementRequireFilterAdvisor is the advisor checking for the filter document of updateMany and deleteMany.
+type StatementRequireFilterAdvisor struct {
+}
+
+// Check checks for the filter document of updateMany and deleteMany.
+func (*StatementRequireFilterAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, call := range parseMethodCallList(statement) {
+		if call.method != "updateMany" && call.method != "deleteMany" {
+			continue
+		}
+		if len(call.argumentList) > 0 && !isEmptyDocument(call.argumentList[0]) {
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.StatementNoFilter,
+			Title:   string(ctx.Rule.Type),
+			Content: fmt.Sprintf("%s on collection \"%s\" requires a non-empty filter document", call.method, call.collection),
+			Line:    call.line,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// isEmptyDocument returns true for the empty document, e.g. {} and { }.
+func isEmptyDocument(document string) bool {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, document) == "{}"
+}
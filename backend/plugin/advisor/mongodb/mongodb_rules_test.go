@@ -0,0 +1,20 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+func TestMongoDBRules(t *testing.T) {
+	mongodbRules := []advisor.SQLReviewRuleType{
+		advisor.SchemaRuleTableMongoDBDisallowDropCollection,
+		advisor.SchemaRuleIndexMongoDBRequireBackground,
+		advisor.SchemaRuleStatementMongoDBRequireFilter,
+	}
+
+	for _, rule := range mongodbRules {
+		advisor.RunSQLReviewRuleTest(t, rule, db.MongoDB, false /* record */)
+	}
+}
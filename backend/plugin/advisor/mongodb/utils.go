@@ -0,0 +1,129 @@
+// Package mongodb implements the SQL review rules for MongoDB.
+package mongodb
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// collectionMethodCallRegexp matches the collection method calls in the masked statement,
+	// e.g. db.users.drop(, db["users"].drop( and db.getCollection("users").drop(.
+	collectionMethodCallRegexp = regexp.MustCompile(`db\s*(?:\.\s*([A-Za-z_$][\w$]*)|\[\s*(["'])([^"']*)["']\s*\]|\.\s*getCollection\s*\(\s*(["'])([^"']*)["']\s*\))\s*\.\s*([A-Za-z_$][\w$]*)\s*\(`)
+)
+
+// methodCall is the collection method call in the mongosh statement.
+type methodCall struct {
+	collection string
+	method     string
+	// argumentList is the top-level arguments with the surrounding whitespaces trimmed.
+	argumentList []string
+	line         int
+}
+
+// parseMethodCallList returns the collection method calls in the mongosh statement.
+// There is no JavaScript parser, so we recognize the calls on the statement with the comments and string literals masked.
+func parseMethodCallList(statement string) []*methodCall {
+	masked := maskStatement(statement)
+	var callList []*methodCall
+	for _, match := range collectionMethodCallRegexp.FindAllStringSubmatchIndex(masked, -1) {
+		start := match[0]
+		// Skip the identifiers ending with db, e.g. mydb.users.drop().
+		if start > 0 && (isIdentifierChar(rune(masked[start-1])) || masked[start-1] == '.') {
+			continue
+		}
+		var collection string
+		switch {
+		case match[2] >= 0:
+			collection = statement[match[2]:match[3]]
+		case match[6] >= 0:
+			collection = statement[match[6]:match[7]]
+		case match[10] >= 0:
+			collection = statement[match[10]:match[11]]
+		}
+		callList = append(callList, &methodCall{
+			collection:   collection,
+			method:       statement[match[12]:match[13]],
+			argumentList: splitArgumentList(statement, masked, match[1]),
+			line:         strings.Count(statement[:start], "\n") + 1,
+		})
+	}
+	return callList
+}
+
+// maskStatement replaces the comments by spaces and the string literal contents by 'x', keeping the offsets and newlines.
+func maskStatement(statement string) string {
+	b := []byte(statement)
+	n := len(b)
+	for i := 0; i < n; {
+		switch {
+		case b[i] == '/' && i+1 < n && b[i+1] == '/':
+			for i < n && b[i] != '\n' {
+				b[i] = ' '
+				i++
+			}
+		case b[i] == '/' && i+1 < n && b[i+1] == '*':
+			for i < n && !(b[i] == '*' && i+1 < n && b[i+1] == '/') {
+				if b[i] != '\n' {
+					b[i] = ' '
+				}
+				i++
+			}
+			for j := i; j < i+2 && j < n; j++ {
+				b[j] = ' '
+			}
+			i += 2
+		case b[i] == '"' || b[i] == '\'' || b[i] == '`':
+			quote := b[i]
+			i++
+			for i < n && b[i] != quote {
+				if b[i] == '\\' && i+1 < n {
+					b[i] = 'x'
+					i++
+				}
+				if b[i] != '\n' {
+					b[i] = 'x'
+				}
+				i++
+			}
+			i++
+		default:
+			i++
+		}
+	}
+	return string(b)
+}
+
+// splitArgumentList splits the arguments from the start offset, which is right after the opening parenthesis.
+func splitArgumentList(statement string, masked string, start int) []string {
+	var argumentList []string
+	depth := 0
+	argumentStart := start
+	for i := start; i < len(masked); i++ {
+		switch masked[i] {
+		case '(', '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+		case ')':
+			if depth == 0 {
+				if argument := strings.TrimSpace(statement[argumentStart:i]); argument != "" || len(argumentList) > 0 {
+					argumentList = append(argumentList, argument)
+				}
+				return argumentList
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				argumentList = append(argumentList, strings.TrimSpace(statement[argumentStart:i]))
+				argumentStart = i + 1
+			}
+		}
+	}
+	// The call is not closed.
+	return argumentList
+}
+
+func isIdentifierChar(r rune) bool {
+	return r == '_' || r == '$' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+}
@@ -0,0 +1,273 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb/parser/ast"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*IndexDropRequireInvisibleAdvisor)(nil)
+	_ ast.Visitor     = (*indexDropRequireInvisibleChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLIndexDropRequireInvisible, &IndexDropRequireInvisibleAdvisor{})
+}
+
+// IndexDropRequireInvisibleAdvisor is the advisor checking for the indexes to be invisible for a period before dropping them.
+type IndexDropRequireInvisibleAdvisor struct {
+}
+
+// Check checks for the indexes to be invisible for a period before dropping them.
+func (*IndexDropRequireInvisibleAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalNumberTypeRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	checker := &indexDropRequireInvisibleChecker{
+		level:             level,
+		title:             string(ctx.Rule.Type),
+		days:              payload.Number,
+		catalog:           ctx.Catalog,
+		charset:           ctx.Charset,
+		collation:         ctx.Collation,
+		changeHistoryList: ctx.ChangeHistoryList,
+		changedVisibility: make(map[string]bool),
+		createdIndex:      make(map[string]bool),
+	}
+
+	for _, stmt := range stmtList {
+		checker.line = stmt.OriginTextPosition()
+		(stmt).Accept(checker)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type indexDropRequireInvisibleChecker struct {
+	adviceList        []advisor.Advice
+	level             advisor.Status
+	title             string
+	line              int
+	days              int
+	catalog           *catalog.Finder
+	charset           string
+	collation         string
+	changeHistoryList []*advisor.ChangeHistory
+	// changedVisibility is the visibility changed in the reviewed statements for each index.
+	changedVisibility map[string]bool
+	// createdIndex is the index set created in the reviewed statements.
+	createdIndex map[string]bool
+	// invisibleSince is the time when the index was made invisible found in the change history.
+	// It's lazily built because parsing the change history is expensive.
+	invisibleSince map[string]*time.Time
+}
+
+// Enter implements the ast.Visitor interface.
+func (checker *indexDropRequireInvisibleChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.DropIndexStmt:
+		checker.dropIndex(node.Table.Name.O, node.IndexName)
+	case *ast.AlterTableStmt:
+		for _, spec := range node.Specs {
+			if spec.Tp == ast.AlterTableDropIndex {
+				checker.dropIndex(node.Table.Name.O, spec.Name)
+			}
+		}
+	}
+
+	if stmt, ok := in.(ast.StmtNode); ok {
+		for _, change := range getIndexVisibilityChangeList(stmt) {
+			key := indexKey(change.table, change.index)
+			if change.create {
+				checker.createdIndex[key] = true
+			}
+			checker.changedVisibility[key] = change.visible
+		}
+	}
+
+	return in, true
+}
+
+// Leave implements the ast.Visitor interface.
+func (*indexDropRequireInvisibleChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (checker *indexDropRequireInvisibleChecker) dropIndex(table string, index string) {
+	key := indexKey(table, index)
+	if strings.EqualFold(index, primaryKeyName) || checker.createdIndex[key] {
+		// The primary key cannot be invisible, and the index created in the reviewed statements has never been used.
+		return
+	}
+	if visible, ok := checker.changedVisibility[key]; ok {
+		if visible {
+			checker.addVisibleAdvice(table, index)
+		} else {
+			checker.addAdvice(fmt.Sprintf("Index `%s` on table `%s` is made invisible in the same change, it must be invisible for at least %d days before dropping it", index, table, checker.days))
+		}
+		return
+	}
+
+	indexState := checker.findOriginIndex(table, index)
+	if indexState == nil {
+		return
+	}
+	if indexState.Visible() {
+		checker.addVisibleAdvice(table, index)
+		return
+	}
+	since := checker.findInvisibleSince(key)
+	if since == nil {
+		checker.addAdvice(fmt.Sprintf("Cannot verify how long index `%s` on table `%s` has been invisible, the INVISIBLE step is not found in the change history", index, table))
+		return
+	}
+	if days := int(time.Since(*since).Hours() / 24); days < checker.days {
+		checker.addAdvice(fmt.Sprintf("Index `%s` on table `%s` has been invisible for %d days, it must be invisible for at least %d days before dropping it", index, table, days, checker.days))
+	}
+}
+
+func (checker *indexDropRequireInvisibleChecker) addVisibleAdvice(table string, index string) {
+	checker.addAdvice(fmt.Sprintf("Index `%s` on table `%s` must be made INVISIBLE for at least %d days before dropping it, e.g. ALTER TABLE `%s` ALTER INDEX `%s` INVISIBLE", index, table, checker.days, table, index))
+}
+
+func (checker *indexDropRequireInvisibleChecker) addAdvice(content string) {
+	checker.adviceList = append(checker.adviceList, advisor.Advice{
+		Status:  checker.level,
+		Code:    advisor.IndexDropBeforeInvisible,
+		Title:   checker.title,
+		Content: content,
+		Line:    checker.line,
+	})
+}
+
+func (checker *indexDropRequireInvisibleChecker) findOriginIndex(table string, index string) *catalog.IndexState {
+	if checker.catalog == nil {
+		return nil
+	}
+	tableState := checker.catalog.Origin.FindTable(&catalog.TableFind{TableName: table})
+	if tableState == nil {
+		return nil
+	}
+	for _, indexState := range tableState.IndexList() {
+		if strings.EqualFold(indexState.Name(), index) {
+			return indexState
+		}
+	}
+	return nil
+}
+
+// findInvisibleSince returns the time when the index was made invisible, or nil if the latest visibility change in the change history isn't INVISIBLE.
+func (checker *indexDropRequireInvisibleChecker) findInvisibleSince(key string) *time.Time {
+	if checker.invisibleSince == nil {
+		checker.invisibleSince = make(map[string]*time.Time)
+		found := make(map[string]bool)
+		// The change history is ordered from the latest to the earliest, so the first change found for each index is the latest one.
+		for _, history := range checker.changeHistoryList {
+			// Skip parsing the statements without visibility changes.
+			if !strings.Contains(strings.ToUpper(history.Statement), "VISIBLE") {
+				continue
+			}
+			stmtList, errAdvice := parseStatement(history.Statement, checker.charset, checker.collation)
+			if errAdvice != nil {
+				continue
+			}
+			visibility := make(map[string]bool)
+			for _, stmt := range stmtList {
+				for _, change := range getIndexVisibilityChangeList(stmt) {
+					visibility[indexKey(change.table, change.index)] = change.visible
+				}
+			}
+			for k, visible := range visibility {
+				if found[k] {
+					continue
+				}
+				found[k] = true
+				if !visible {
+					createdTime := history.CreatedTime
+					checker.invisibleSince[k] = &createdTime
+				}
+			}
+		}
+	}
+	return checker.invisibleSince[key]
+}
+
+type indexVisibilityChange struct {
+	table   string
+	index   string
+	visible bool
+	create  bool
+}
+
+// getIndexVisibilityChangeList returns the index visibility changes in the statement, including the created indexes.
+func getIndexVisibilityChangeList(stmt ast.StmtNode) []*indexVisibilityChange {
+	var changeList []*indexVisibilityChange
+	addConstraint := func(table string, constraint *ast.Constraint) {
+		index := newConstraintIndexDefinition(constraint)
+		if index == nil || index.Primary {
+			return
+		}
+		changeList = append(changeList, &indexVisibilityChange{
+			table:   table,
+			index:   index.Name,
+			visible: constraint.Option == nil || constraint.Option.Visibility != ast.IndexVisibilityInvisible,
+			create:  true,
+		})
+	}
+	switch node := stmt.(type) {
+	case *ast.CreateTableStmt:
+		for _, constraint := range node.Constraints {
+			addConstraint(node.Table.Name.O, constraint)
+		}
+	case *ast.CreateIndexStmt:
+		changeList = append(changeList, &indexVisibilityChange{
+			table:   node.Table.Name.O,
+			index:   node.IndexName,
+			visible: node.IndexOption == nil || node.IndexOption.Visibility != ast.IndexVisibilityInvisible,
+			create:  true,
+		})
+	case *ast.AlterTableStmt:
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			case ast.AlterTableAddConstraint:
+				addConstraint(node.Table.Name.O, spec.Constraint)
+			case ast.AlterTableIndexInvisible:
+				changeList = append(changeList, &indexVisibilityChange{
+					table:   node.Table.Name.O,
+					index:   spec.IndexName.O,
+					visible: spec.Visibility != ast.IndexVisibilityInvisible,
+				})
+			}
+		}
+	}
+	return changeList
+}
+
+func indexKey(table string, index string) string {
+	return fmt.Sprintf("%s.%s", strings.ToLower(table), strings.ToLower(index))
+}
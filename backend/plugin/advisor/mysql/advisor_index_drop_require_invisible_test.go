@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+func TestMySQLIndexDropRequireInvisibleWithChangeHistory(t *testing.T) {
+	daysAgo := func(days int) time.Time {
+		return time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	}
+	statement := "DROP INDEX old_index ON tech_book"
+	tests := []struct {
+		changeHistoryList []*advisor.ChangeHistory
+		want              string
+	}{
+		{
+			changeHistoryList: []*advisor.ChangeHistory{
+				{Statement: "ALTER TABLE tech_book ADD COLUMN a int", CreatedTime: daysAgo(1)},
+				{Statement: "ALTER TABLE tech_book ALTER INDEX old_index INVISIBLE", CreatedTime: daysAgo(10)},
+			},
+			want: "",
+		},
+		{
+			changeHistoryList: []*advisor.ChangeHistory{
+				{Statement: "CREATE INDEX old_index ON tech_book(id, name) INVISIBLE", CreatedTime: daysAgo(30)},
+			},
+			want: "",
+		},
+		{
+			changeHistoryList: []*advisor.ChangeHistory{
+				{Statement: "ALTER TABLE tech_book ALTER INDEX old_index INVISIBLE", CreatedTime: daysAgo(3)},
+			},
+			want: "Index `old_index` on table `tech_book` has been invisible for 3 days, it must be invisible for at least 7 days before dropping it",
+		},
+		{
+			changeHistoryList: []*advisor.ChangeHistory{
+				{Statement: "ALTER TABLE tech_book ALTER INDEX old_index VISIBLE", CreatedTime: daysAgo(1)},
+				{Statement: "ALTER TABLE tech_book ALTER INDEX old_index INVISIBLE", CreatedTime: daysAgo(20)},
+			},
+			want: "Cannot verify how long index `old_index` on table `tech_book` has been invisible, the INVISIBLE step is not found in the change history",
+		},
+	}
+
+	payload, err := advisor.SetDefaultSQLReviewRulePayload(advisor.SchemaRuleIndexDropRequireInvisible)
+	require.NoError(t, err)
+	adv := &IndexDropRequireInvisibleAdvisor{}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(advisor.Context{
+			Rule: &advisor.SQLReviewRule{
+				Type:    advisor.SchemaRuleIndexDropRequireInvisible,
+				Level:   advisor.SchemaRuleLevelError,
+				Payload: payload,
+			},
+			Catalog:           catalog.NewFinder(advisor.MockMySQLDatabase, &catalog.FinderContext{CheckIntegrity: true, EngineType: db.MySQL}),
+			ChangeHistoryList: tc.changeHistoryList,
+		}, statement)
+		require.NoError(t, err)
+		require.Len(t, adviceList, 1)
+		if tc.want == "" {
+			assert.Equal(t, advisor.Success, adviceList[0].Status)
+		} else {
+			assert.Equal(t, advisor.Error, adviceList[0].Status)
+			assert.Equal(t, tc.want, adviceList[0].Content)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/parser/ast"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*IndexNoRedundantAdvisor)(nil)
+	_ ast.Visitor     = (*indexNoRedundantChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLIndexNoRedundant, &IndexNoRedundantAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLIndexNoRedundant, &IndexNoRedundantAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLIndexNoRedundant, &IndexNoRedundantAdvisor{})
+}
+
+// IndexNoRedundantAdvisor is the advisor checking for no duplicate or redundant index.
+type IndexNoRedundantAdvisor struct {
+}
+
+// Check checks for no duplicate or redundant index.
+func (*IndexNoRedundantAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &indexNoRedundantChecker{
+		level:        level,
+		title:        string(ctx.Rule.Type),
+		catalog:      ctx.Catalog,
+		tableIndexes: make(map[string][]*advisor.IndexDefinition),
+		droppedIndex: make(map[string]map[string]bool),
+	}
+
+	for _, stmt := range stmtList {
+		checker.text = stmt.Text()
+		checker.line = stmt.OriginTextPosition()
+		(stmt).Accept(checker)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type indexNoRedundantChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	text       string
+	line       int
+	catalog    *catalog.Finder
+	// tableIndexes is the index list created in the reviewed statements for each table.
+	tableIndexes map[string][]*advisor.IndexDefinition
+	// droppedIndex is the index set dropped in the reviewed statements for each table.
+	droppedIndex map[string]map[string]bool
+}
+
+// Enter implements the ast.Visitor interface.
+func (checker *indexNoRedundantChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.CreateTableStmt:
+		table := node.Table.Name.O
+		for _, column := range node.Cols {
+			if index := newColumnIndexDefinition(column); index != nil {
+				checker.checkIndex(table, index, column.OriginTextPosition())
+			}
+		}
+		for _, constraint := range node.Constraints {
+			if index := newConstraintIndexDefinition(constraint); index != nil {
+				checker.checkIndex(table, index, constraint.OriginTextPosition())
+			}
+		}
+	case *ast.CreateIndexStmt:
+		if node.KeyType != ast.IndexKeyTypeNone && node.KeyType != ast.IndexKeyTypeUnique {
+			break
+		}
+		columnList, ok := getIndexColumnList(node.IndexPartSpecifications)
+		if !ok {
+			break
+		}
+		checker.checkIndex(node.Table.Name.O, &advisor.IndexDefinition{
+			Name:       node.IndexName,
+			ColumnList: columnList,
+			Unique:     node.KeyType == ast.IndexKeyTypeUnique,
+		}, checker.line)
+	case *ast.AlterTableStmt:
+		table := node.Table.Name.O
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			case ast.AlterTableAddColumns:
+				for _, column := range spec.NewColumns {
+					if index := newColumnIndexDefinition(column); index != nil {
+						checker.checkIndex(table, index, checker.line)
+					}
+				}
+			case ast.AlterTableAddConstraint:
+				if index := newConstraintIndexDefinition(spec.Constraint); index != nil {
+					checker.checkIndex(table, index, checker.line)
+				}
+			case ast.AlterTableDropIndex:
+				checker.dropIndex(table, spec.Name)
+			case ast.AlterTableDropPrimaryKey:
+				checker.dropIndex(table, primaryKeyName)
+			}
+		}
+	case *ast.DropIndexStmt:
+		checker.dropIndex(node.Table.Name.O, node.IndexName)
+	}
+
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*indexNoRedundantChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (checker *indexNoRedundantChecker) dropIndex(table string, index string) {
+	if _, ok := checker.droppedIndex[table]; !ok {
+		checker.droppedIndex[table] = make(map[string]bool)
+	}
+	checker.droppedIndex[table][strings.ToLower(index)] = true
+	var indexList []*advisor.IndexDefinition
+	for _, created := range checker.tableIndexes[table] {
+		if !strings.EqualFold(created.Name, index) {
+			indexList = append(indexList, created)
+		}
+	}
+	checker.tableIndexes[table] = indexList
+}
+
+// existingIndexList returns the synced indexes of the table which are not dropped in the reviewed statements.
+func (checker *indexNoRedundantChecker) existingIndexList(table string) []*advisor.IndexDefinition {
+	if checker.catalog == nil {
+		return nil
+	}
+	tableState := checker.catalog.Origin.FindTable(&catalog.TableFind{TableName: table})
+	if tableState == nil {
+		return nil
+	}
+	var indexList []*advisor.IndexDefinition
+	for _, index := range tableState.IndexList() {
+		if checker.droppedIndex[table][strings.ToLower(index.Name())] {
+			continue
+		}
+		indexList = append(indexList, &advisor.IndexDefinition{
+			Name:       index.Name(),
+			ColumnList: index.ExpressionList(),
+			Unique:     index.Unique(),
+			Primary:    index.Primary(),
+		})
+	}
+	return indexList
+}
+
+func (checker *indexNoRedundantChecker) checkIndex(table string, index *advisor.IndexDefinition, line int) {
+	candidateList := append(checker.existingIndexList(table), checker.tableIndexes[table]...)
+	checker.tableIndexes[table] = append(checker.tableIndexes[table], index)
+
+	for _, candidate := range candidateList {
+		if advisor.IsRedundantIndex(index, candidate) {
+			checker.adviceList = append(checker.adviceList, advisor.Advice{
+				Status:  checker.level,
+				Code:    advisor.RedundantIndex,
+				Title:   checker.title,
+				Content: fmt.Sprintf("Index `%s`(%s) on table `%s` is redundant, it's covered by index `%s`(%s)", index.Name, strings.Join(index.ColumnList, ", "), table, candidate.Name, strings.Join(candidate.ColumnList, ", ")),
+				Line:    line,
+			})
+			return
+		}
+	}
+	for _, candidate := range candidateList {
+		if advisor.IsRedundantIndex(candidate, index) {
+			checker.adviceList = append(checker.adviceList, advisor.Advice{
+				Status:  checker.level,
+				Code:    advisor.RedundantIndex,
+				Title:   checker.title,
+				Content: fmt.Sprintf("Index `%s`(%s) on table `%s` becomes redundant, it's covered by the new index `%s`(%s)", candidate.Name, strings.Join(candidate.ColumnList, ", "), table, index.Name, strings.Join(index.ColumnList, ", ")),
+				Line:    line,
+				Details: fmt.Sprintf("DROP INDEX `%s` ON `%s`;", candidate.Name, table),
+			})
+			return
+		}
+	}
+}
+
+func newColumnIndexDefinition(column *ast.ColumnDef) *advisor.IndexDefinition {
+	for _, option := range column.Options {
+		switch option.Tp {
+		case ast.ColumnOptionPrimaryKey:
+			return &advisor.IndexDefinition{
+				Name:       primaryKeyName,
+				ColumnList: []string{column.Name.Name.O},
+				Unique:     true,
+				Primary:    true,
+			}
+		case ast.ColumnOptionUniqKey:
+			return &advisor.IndexDefinition{
+				Name:       column.Name.Name.O,
+				ColumnList: []string{column.Name.Name.O},
+				Unique:     true,
+			}
+		}
+	}
+	return nil
+}
+
+func newConstraintIndexDefinition(constraint *ast.Constraint) *advisor.IndexDefinition {
+	index := &advisor.IndexDefinition{Name: constraint.Name}
+	switch constraint.Tp {
+	case ast.ConstraintPrimaryKey:
+		index.Name = primaryKeyName
+		index.Primary = true
+		index.Unique = true
+	case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		index.Unique = true
+	case ast.ConstraintKey, ast.ConstraintIndex:
+	default:
+		return nil
+	}
+	columnList, ok := getIndexColumnList(constraint.Keys)
+	if !ok {
+		return nil
+	}
+	index.ColumnList = columnList
+	if index.Name == "" {
+		// MySQL uses the first column name as the index name if the index name is not specified.
+		index.Name = columnList[0]
+	}
+	return index
+}
+
+// getIndexColumnList returns the column list of the index, returns false if the index contains expressions.
+func getIndexColumnList(keyList []*ast.IndexPartSpecification) ([]string, bool) {
+	var columnList []string
+	for _, key := range keyList {
+		if key.Expr != nil || key.Column == nil {
+			return nil, false
+		}
+		columnList = append(columnList, key.Column.Name.O)
+	}
+	return columnList, len(columnList) > 0
+}
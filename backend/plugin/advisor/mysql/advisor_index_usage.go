@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb/parser/ast"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*IndexUsageAdvisor)(nil)
+	_ ast.Visitor     = (*indexUsageChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLIndexUsage, &IndexUsageAdvisor{})
+}
+
+// IndexUsageAdvisor is the advisor attaching the index usage context to the dropped indexes.
+type IndexUsageAdvisor struct {
+}
+
+// Check attaches the index usage context to the dropped indexes.
+func (*IndexUsageAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return nil, nil
+	}
+
+	checker := &indexUsageChecker{
+		unusedIndexList: ctx.UnusedIndexList,
+	}
+	for _, stmt := range stmtList {
+		checker.line = stmt.OriginTextPosition()
+		(stmt).Accept(checker)
+	}
+	return checker.adviceList, nil
+}
+
+type indexUsageChecker struct {
+	adviceList      []advisor.Advice
+	line            int
+	unusedIndexList []*advisor.UnusedIndex
+}
+
+// Enter implements the ast.Visitor interface.
+func (checker *indexUsageChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.DropIndexStmt:
+		checker.dropIndex(node.Table.Name.O, node.IndexName)
+	case *ast.AlterTableStmt:
+		for _, spec := range node.Specs {
+			if spec.Tp == ast.AlterTableDropIndex {
+				checker.dropIndex(node.Table.Name.O, spec.Name)
+			}
+		}
+	}
+
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*indexUsageChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (checker *indexUsageChecker) dropIndex(table string, index string) {
+	unusedIndex := advisor.FindUnusedIndex(checker.unusedIndexList, "", table, index, false /* caseSensitive */)
+	if unusedIndex == nil {
+		return
+	}
+	if advice := advisor.NewUnusedIndexAdvice(unusedIndex, fmt.Sprintf("`%s`", unusedIndex.Name), fmt.Sprintf("`%s`", unusedIndex.Table), checker.line); advice != nil {
+		checker.adviceList = append(checker.adviceList, *advice)
+	}
+}
@@ -0,0 +1,55 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+)
+
+func TestMySQLIndexUsage(t *testing.T) {
+	unusedIndexList := []*advisor.UnusedIndex{
+		{Table: "tech_book", Name: "idx_name", UnusedSince: time.Now().Add(-3 * 24 * time.Hour)},
+	}
+	tests := []advisor.TestCase{
+		{
+			Statement: "DROP INDEX IDX_NAME ON tech_book;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.IndexUnused,
+					Title:   advisor.UnusedIndexTitle,
+					Content: "Index `idx_name` on table `tech_book` appears unused for 3 days",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE tech_book ADD COLUMN a int;\nALTER TABLE tech_book DROP INDEX idx_name;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.IndexUnused,
+					Title:   advisor.UnusedIndexTitle,
+					Content: "Index `idx_name` on table `tech_book` appears unused for 3 days",
+					Line:    2,
+				},
+			},
+		},
+		{
+			Statement: "DROP INDEX idx_name ON other_book;",
+			Want:      nil,
+		},
+	}
+
+	adv := &IndexUsageAdvisor{}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(advisor.Context{UnusedIndexList: unusedIndexList}, tc.Statement)
+		require.NoError(t, err)
+		assert.Equal(t, tc.Want, adviceList, tc.Statement)
+	}
+}
@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*RequireIdempotentMigrationAdvisor)(nil)
+
+	createViewRegexp = regexp.MustCompile(`(?is)^(\s*CREATE)\s+`)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLRequireIdempotentMigration, &RequireIdempotentMigrationAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLRequireIdempotentMigration, &RequireIdempotentMigrationAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLRequireIdempotentMigration, &RequireIdempotentMigrationAdvisor{})
+}
+
+// RequireIdempotentMigrationAdvisor is the advisor checking for the CREATE and DROP statements to be re-runnable.
+type RequireIdempotentMigrationAdvisor struct {
+}
+
+// Check checks for the CREATE and DROP statements to be re-runnable.
+func (*RequireIdempotentMigrationAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		// MySQL doesn't support IF [NOT] EXISTS for CREATE INDEX and DROP INDEX, so the index changes are skipped.
+		var content, fix string
+		switch node := stmt.(type) {
+		case *ast.CreateTableStmt:
+			if node.IfNotExists {
+				continue
+			}
+			content = fmt.Sprintf("Creating table `%s` without IF NOT EXISTS is not idempotent", node.Table.Name.O)
+			node.IfNotExists = true
+			fix = restoreStatement(node)
+		case *ast.DropTableStmt:
+			if node.IfExists {
+				continue
+			}
+			objectType := "table"
+			if node.IsView {
+				objectType = "view"
+			}
+			content = fmt.Sprintf("Dropping %s %s without IF EXISTS is not idempotent", objectType, joinTableNames(node.Tables))
+			node.IfExists = true
+			fix = restoreStatement(node)
+		case *ast.CreateViewStmt:
+			if node.OrReplace {
+				continue
+			}
+			content = fmt.Sprintf("Creating view `%s` without OR REPLACE is not idempotent", node.ViewName.Name.O)
+			// Restoring the view fills in the default ALGORITHM, DEFINER and SQL SECURITY, so we change the text instead.
+			fix = createViewRegexp.ReplaceAllString(strings.TrimRight(strings.TrimSpace(node.Text()), ";"), "${1} OR REPLACE ") + ";"
+		case *ast.CreateDatabaseStmt:
+			if node.IfNotExists {
+				continue
+			}
+			content = fmt.Sprintf("Creating database `%s` without IF NOT EXISTS is not idempotent", node.Name.O)
+			node.IfNotExists = true
+			fix = restoreStatement(node)
+		case *ast.DropDatabaseStmt:
+			if node.IfExists {
+				continue
+			}
+			content = fmt.Sprintf("Dropping database `%s` without IF EXISTS is not idempotent", node.Name.O)
+			node.IfExists = true
+			fix = restoreStatement(node)
+		default:
+			continue
+		}
+
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.MigrationNotIdempotent,
+			Title:   string(ctx.Rule.Type),
+			Content: content,
+			Line:    stmt.OriginTextPosition(),
+			Details: fix,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// restoreStatement restores the statement changed to the idempotent one as the suggestion.
+func restoreStatement(node ast.StmtNode) string {
+	text, err := restoreNode(node, format.DefaultRestoreFlags)
+	if err != nil {
+		return ""
+	}
+	return text + ";"
+}
+
+func joinTableNames(tableList []*ast.TableName) string {
+	var nameList []string
+	for _, table := range tableList {
+		nameList = append(nameList, fmt.Sprintf("`%s`", table.Name.O))
+	}
+	return strings.Join(nameList, ", ")
+}
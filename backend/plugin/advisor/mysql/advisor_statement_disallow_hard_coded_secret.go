@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*StatementDisallowHardCodedSecretAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLStatementDisallowHardCodedSecret, &StatementDisallowHardCodedSecretAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLStatementDisallowHardCodedSecret, &StatementDisallowHardCodedSecretAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLStatementDisallowHardCodedSecret, &StatementDisallowHardCodedSecretAdvisor{})
+}
+
+// StatementDisallowHardCodedSecretAdvisor is the advisor checking for the hard-coded secrets.
+type StatementDisallowHardCodedSecretAdvisor struct {
+}
+
+// Check checks for the hard-coded secrets.
+// The statement text is scanned directly, so the secrets in the comments and the unparsable statements are also found.
+func (*StatementDisallowHardCodedSecretAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	// The secrets rendered from the database secret variables are not hard-coded.
+	if ctx.TemplateStatement != "" {
+		statement = ctx.TemplateStatement
+	}
+
+	var adviceList []advisor.Advice
+	for _, secret := range advisor.DetectHardCodedSecrets(statement) {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.StatementHardCodedSecret,
+			Title:   string(ctx.Rule.Type),
+			Content: fmt.Sprintf("Hard-coded %s is found, please use the database secrets instead", secret.Kind),
+			Line:    secret.Line,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
@@ -0,0 +1,124 @@
+package mysql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pingcap/tidb/parser/ast"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*TableRequirePartitionAdvisor)(nil)
+	_ ast.Visitor     = (*tableRequirePartitionChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLTableRequirePartition, &TableRequirePartitionAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLTableRequirePartition, &TableRequirePartitionAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLTableRequirePartition, &TableRequirePartitionAdvisor{})
+}
+
+// TableRequirePartitionAdvisor is the advisor checking for the high-volume tables to be partitioned.
+type TableRequirePartitionAdvisor struct {
+}
+
+// Check checks for the high-volume tables to be partitioned.
+func (*TableRequirePartitionAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	format, strategyList, err := advisor.UnmarshalRequirePartitionRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	checker := &tableRequirePartitionChecker{
+		level:        level,
+		title:        string(ctx.Rule.Type),
+		format:       format,
+		strategyList: strategyList,
+	}
+
+	for _, stmt := range stmtList {
+		checker.line = stmt.OriginTextPosition()
+		(stmt).Accept(checker)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type tableRequirePartitionChecker struct {
+	adviceList   []advisor.Advice
+	level        advisor.Status
+	title        string
+	line         int
+	format       *regexp.Regexp
+	strategyList []string
+}
+
+// Enter implements the ast.Visitor interface.
+func (checker *tableRequirePartitionChecker) Enter(in ast.Node) (ast.Node, bool) {
+	node, ok := in.(*ast.CreateTableStmt)
+	// CREATE TABLE ... LIKE copies the partitioning of the source table.
+	if !ok || node.ReferTable != nil {
+		return in, false
+	}
+	tableName := node.Table.Name.O
+	if !checker.format.MatchString(tableName) {
+		return in, false
+	}
+
+	content := ""
+	if node.Partition == nil {
+		content = fmt.Sprintf("High-volume table `%s` requires partitioning", tableName)
+	} else if strategy := node.Partition.PartitionMethod.Tp.String(); !isApprovedPartitionStrategy(strategy, checker.strategyList) {
+		content = fmt.Sprintf("High-volume table `%s` is partitioned by %s, but only %s are approved", tableName, strategy, strings.Join(checker.strategyList, ", "))
+	}
+
+	if content != "" {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    advisor.TableNoPartition,
+			Title:   checker.title,
+			Content: content,
+			Line:    checker.line,
+		})
+	}
+
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*tableRequirePartitionChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// isApprovedPartitionStrategy returns true if the strategy is in the approved list, or the list is empty.
+func isApprovedPartitionStrategy(strategy string, strategyList []string) bool {
+	if len(strategyList) == 0 {
+		return true
+	}
+	for _, approved := range strategyList {
+		if approved == strategy {
+			return true
+		}
+	}
+	return false
+}
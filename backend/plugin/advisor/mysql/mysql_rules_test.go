@@ -34,6 +34,8 @@ func TestMySQLRules(t *testing.T) {
 		advisor.SchemaRuleStatementNoLeadingWildcardLike,
 		// advisor.SchemaRuleStatementDisallowCommit disallow using commit in the issue.
 		advisor.SchemaRuleStatementDisallowCommit,
+		// advisor.SchemaRuleStatementDisallowHardCodedSecret disallow the hard-coded secrets.
+		advisor.SchemaRuleStatementDisallowHardCodedSecret,
 		// advisor.SchemaRuleStatementDisallowLimit disallow the LIMIT clause in INSERT, DELETE and UPDATE statements.
 		advisor.SchemaRuleStatementDisallowLimit,
 		// advisor.SchemaRuleStatementDisallowOrderBy disallow the ORDER BY clause in DELETE and UPDATE statements.
@@ -61,6 +63,8 @@ func TestMySQLRules(t *testing.T) {
 		advisor.SchemaRuleTableCommentConvention,
 		// advisor.SchemaRuleTableDisallowPartition disallow the table partition.
 		advisor.SchemaRuleTableDisallowPartition,
+		// advisor.SchemaRuleTableRequirePartition require the high-volume tables to be partitioned with the approved strategies.
+		advisor.SchemaRuleTableRequirePartition,
 
 		// advisor.SchemaRuleRequiredColumn enforce the required columns in each table.
 		advisor.SchemaRuleRequiredColumn,
@@ -110,12 +114,19 @@ func TestMySQLRules(t *testing.T) {
 		// advisor.SchemaRuleIndexTotalNumberLimit enforce the index total number limit.
 		advisor.SchemaRuleIndexTotalNumberLimit,
 		advisor.SchemaRuleIndexPrimaryKeyTypeAllowlist,
+		// advisor.SchemaRuleIndexNoRedundant disallow the duplicate and left-prefix redundant indexes.
+		advisor.SchemaRuleIndexNoRedundant,
+		// advisor.SchemaRuleIndexDropRequireInvisible require the indexes to be invisible for a period before dropping them.
+		advisor.SchemaRuleIndexDropRequireInvisible,
 
 		// advisor.SchemaRuleCharsetAllowlist enforce the charset allowlist.
 		advisor.SchemaRuleCharsetAllowlist,
 
 		// advisor.SchemaRuleCollationAllowlist enforce the collation allowlist.
 		advisor.SchemaRuleCollationAllowlist,
+
+		// advisor.SchemaRuleSchemaRequireIdempotentMigration require the CREATE and DROP statements to be re-runnable.
+		advisor.SchemaRuleSchemaRequireIdempotentMigration,
 	}
 
 	for _, rule := range mysqlRules {
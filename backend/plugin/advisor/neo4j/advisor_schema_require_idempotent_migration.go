@@ -0,0 +1,139 @@
+// Package neo4j implements the SQL review rules for Neo4j Cypher.
+package neo4j
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/cypher"
+)
+
+var (
+	_ advisor.Advisor = (*RequireIdempotentMigrationAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Neo4j, advisor.Neo4jRequireIdempotentMigration, &RequireIdempotentMigrationAdvisor{})
+}
+
+// RequireIdempotentMigrationAdvisor is the advisor checking for the constraint and index changes to be re-runnable.
+type RequireIdempotentMigrationAdvisor struct {
+}
+
+// Check checks for the constraint and index changes to be re-runnable.
+func (*RequireIdempotentMigrationAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		content, fix := checkIdempotent(stmt)
+		if content == "" {
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.MigrationNotIdempotent,
+			Title:   string(ctx.Rule.Type),
+			Content: content,
+			Line:    stmt.Line,
+			Details: fix,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// checkIdempotent returns the advice content and the suggested statement if the statement is not idempotent.
+func checkIdempotent(stmt *cypher.Statement) (string, string) {
+	if !stmt.IsSchemaCommand() {
+		return "", ""
+	}
+	kind := stmt.Kind()
+	objectType := strings.ToLower(strings.Fields(kind)[1])
+	name := stmt.ObjectName()
+	if name != "" && !strings.HasPrefix(name, "`") {
+		name = fmt.Sprintf("`%s`", name)
+	}
+
+	if strings.HasPrefix(kind, "CREATE") {
+		if stmt.FindKeywords("IF", "NOT", "EXISTS") >= 0 {
+			return "", ""
+		}
+		if name == "" {
+			if isDeprecatedSyntax(stmt) {
+				return fmt.Sprintf("Creating %s with the ON syntax is not idempotent, please use the FOR syntax with IF NOT EXISTS", objectType), ""
+			}
+			return fmt.Sprintf("Creating %s without IF NOT EXISTS is not idempotent", objectType), insertClause(stmt, "IF NOT EXISTS")
+		}
+		return fmt.Sprintf("Creating %s %s without IF NOT EXISTS is not idempotent", objectType, name), insertClause(stmt, "IF NOT EXISTS")
+	}
+
+	if stmt.FindKeywords("IF", "EXISTS") >= 0 {
+		return "", ""
+	}
+	// DROP INDEX ON :Label(property) and DROP CONSTRAINT ON ... ASSERT are removed in Neo4j 5.
+	if name == "" {
+		return fmt.Sprintf("Dropping %s without the name is not idempotent, please drop it by name with IF EXISTS", objectType), ""
+	}
+	return fmt.Sprintf("Dropping %s %s without IF EXISTS is not idempotent", objectType, name), insertClause(stmt, "IF EXISTS")
+}
+
+// isDeprecatedSyntax returns true for CREATE INDEX ON :Label(property) and CREATE CONSTRAINT ON (n:Label) ASSERT.
+func isDeprecatedSyntax(stmt *cypher.Statement) bool {
+	for i := range stmt.Tokens {
+		switch stmt.Keyword(i) {
+		case "ON":
+			return true
+		case "FOR":
+			return false
+		}
+	}
+	return false
+}
+
+// insertClause inserts the clause after the name of the index or the constraint.
+func insertClause(stmt *cypher.Statement, clause string) string {
+	end := stmt.ObjectNameEnd()
+	if end < 0 {
+		return ""
+	}
+	return stmt.Text[:end] + " " + clause + stmt.Text[end:] + ";"
+}
+
+func parseStatement(statement string) ([]*cypher.Statement, []advisor.Advice) {
+	stmtList, err := cypher.SplitStatements(statement)
+	if err != nil {
+		line := 0
+		if syntaxErr, ok := err.(*cypher.SyntaxError); ok {
+			line = syntaxErr.Line
+		}
+		return nil, []advisor.Advice{
+			{
+				Status:  advisor.Error,
+				Code:    advisor.StatementSyntaxError,
+				Title:   advisor.SyntaxErrorTitle,
+				Content: err.Error(),
+				Line:    line,
+			},
+		}
+	}
+	return stmtList, nil
+}
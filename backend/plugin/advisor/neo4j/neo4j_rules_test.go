@@ -0,0 +1,18 @@
+package neo4j
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+func TestNeo4jRules(t *testing.T) {
+	neo4jRules := []advisor.SQLReviewRuleType{
+		advisor.SchemaRuleSchemaRequireIdempotentMigration,
+	}
+
+	for _, rule := range neo4jRules {
+		advisor.RunSQLReviewRuleTest(t, rule, db.Neo4j, false /* record */)
+	}
+}
@@ -0,0 +1,240 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
+)
+
+var (
+	_ advisor.Advisor = (*IndexNoRedundantAdvisor)(nil)
+	_ ast.Visitor     = (*indexNoRedundantChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLIndexNoRedundant, &IndexNoRedundantAdvisor{})
+}
+
+// IndexNoRedundantAdvisor is the advisor checking for no duplicate or redundant index.
+type IndexNoRedundantAdvisor struct {
+}
+
+// Check checks for no duplicate or redundant index.
+func (*IndexNoRedundantAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &indexNoRedundantChecker{
+		level:        level,
+		title:        string(ctx.Rule.Type),
+		catalog:      ctx.Catalog,
+		tableIndexes: make(map[string][]*advisor.IndexDefinition),
+		droppedIndex: make(map[string]bool),
+	}
+
+	for _, stmt := range stmtList {
+		checker.text = stmt.Text()
+		checker.line = stmt.LastLine()
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+
+	return checker.adviceList, nil
+}
+
+type indexNoRedundantChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	text       string
+	line       int
+	catalog    *catalog.Finder
+	// tableIndexes is the index list created in the reviewed statements for each normalized table name.
+	tableIndexes map[string][]*advisor.IndexDefinition
+	// droppedIndex is the set of normalized index names dropped in the reviewed statements.
+	// The index name is unique in a schema in PostgreSQL.
+	droppedIndex map[string]bool
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *indexNoRedundantChecker) Visit(node ast.Node) ast.Visitor {
+	switch node := node.(type) {
+	case *ast.CreateTableStmt:
+		for _, column := range node.ColumnList {
+			for _, constraint := range column.ConstraintList {
+				if index := newConstraintIndexDefinition(node.Name.Name, constraint); index != nil {
+					checker.checkIndex(node.Name, index)
+				}
+			}
+		}
+		for _, constraint := range node.ConstraintList {
+			if index := newConstraintIndexDefinition(node.Name.Name, constraint); index != nil {
+				checker.checkIndex(node.Name, index)
+			}
+		}
+	case *ast.CreateIndexStmt:
+		var columnList []string
+		for _, key := range node.Index.KeyList {
+			if key.Type != ast.IndexKeyTypeColumn {
+				return checker
+			}
+			columnList = append(columnList, key.Key)
+		}
+		if len(columnList) == 0 {
+			return checker
+		}
+		name := node.Index.Name
+		if name == "" {
+			name = fmt.Sprintf("%s_%s_idx", node.Index.Table.Name, strings.Join(columnList, "_"))
+		}
+		checker.checkIndex(node.Index.Table, &advisor.IndexDefinition{
+			Name:       name,
+			ColumnList: columnList,
+			Unique:     node.Index.Unique,
+		})
+	case *ast.AlterTableStmt:
+		for _, item := range node.AlterItemList {
+			switch cmd := item.(type) {
+			case *ast.AddColumnListStmt:
+				for _, column := range cmd.ColumnList {
+					for _, constraint := range column.ConstraintList {
+						if index := newConstraintIndexDefinition(cmd.Table.Name, constraint); index != nil {
+							checker.checkIndex(cmd.Table, index)
+						}
+					}
+				}
+			case *ast.AddConstraintStmt:
+				if index := newConstraintIndexDefinition(cmd.Table.Name, cmd.Constraint); index != nil {
+					checker.checkIndex(cmd.Table, index)
+				}
+			case *ast.DropConstraintStmt:
+				checker.dropIndex(cmd.Table.Schema, cmd.ConstraintName)
+			}
+		}
+	case *ast.DropIndexStmt:
+		for _, index := range node.IndexList {
+			schema := ""
+			if index.Table != nil {
+				schema = index.Table.Schema
+			}
+			checker.dropIndex(schema, index.Name)
+		}
+	}
+
+	return checker
+}
+
+func (checker *indexNoRedundantChecker) dropIndex(schema string, index string) {
+	checker.droppedIndex[fmt.Sprintf("%q.%q", normalizeSchemaName(schema), index)] = true
+	for table, indexList := range checker.tableIndexes {
+		var list []*advisor.IndexDefinition
+		for _, created := range indexList {
+			if created.Name != index {
+				list = append(list, created)
+			}
+		}
+		checker.tableIndexes[table] = list
+	}
+}
+
+// existingIndexList returns the synced indexes of the table which are not dropped in the reviewed statements.
+func (checker *indexNoRedundantChecker) existingIndexList(table *ast.TableDef) []*advisor.IndexDefinition {
+	if checker.catalog == nil {
+		return nil
+	}
+	schema := normalizeSchemaName(table.Schema)
+	tableState := checker.catalog.Origin.FindTable(&catalog.TableFind{SchemaName: schema, TableName: table.Name})
+	if tableState == nil {
+		return nil
+	}
+	var indexList []*advisor.IndexDefinition
+	for _, index := range tableState.IndexList() {
+		if checker.droppedIndex[fmt.Sprintf("%q.%q", schema, index.Name())] {
+			continue
+		}
+		indexList = append(indexList, &advisor.IndexDefinition{
+			Name:       index.Name(),
+			ColumnList: index.ExpressionList(),
+			Unique:     index.Unique(),
+			Primary:    index.Primary(),
+		})
+	}
+	return indexList
+}
+
+func (checker *indexNoRedundantChecker) checkIndex(table *ast.TableDef, index *advisor.IndexDefinition) {
+	tableName := normalizeTableName(table, PostgreSQLPublicSchema)
+	candidateList := append(checker.existingIndexList(table), checker.tableIndexes[tableName]...)
+	checker.tableIndexes[tableName] = append(checker.tableIndexes[tableName], index)
+
+	for _, candidate := range candidateList {
+		if advisor.IsRedundantIndex(index, candidate) {
+			checker.adviceList = append(checker.adviceList, advisor.Advice{
+				Status:  checker.level,
+				Code:    advisor.RedundantIndex,
+				Title:   checker.title,
+				Content: fmt.Sprintf("Index %q(%s) on table %s is redundant, it's covered by index %q(%s)", index.Name, strings.Join(index.ColumnList, ", "), tableName, candidate.Name, strings.Join(candidate.ColumnList, ", ")),
+				Line:    checker.line,
+			})
+			return
+		}
+	}
+	for _, candidate := range candidateList {
+		if advisor.IsRedundantIndex(candidate, index) {
+			checker.adviceList = append(checker.adviceList, advisor.Advice{
+				Status:  checker.level,
+				Code:    advisor.RedundantIndex,
+				Title:   checker.title,
+				Content: fmt.Sprintf("Index %q(%s) on table %s becomes redundant, it's covered by the new index %q(%s)", candidate.Name, strings.Join(candidate.ColumnList, ", "), tableName, index.Name, strings.Join(index.ColumnList, ", ")),
+				Line:    checker.line,
+				Details: fmt.Sprintf("DROP INDEX %q.%q;", normalizeSchemaName(table.Schema), candidate.Name),
+			})
+			return
+		}
+	}
+}
+
+// newConstraintIndexDefinition returns the index definition for the constraint backed by an index.
+// The index name follows the PostgreSQL default naming convention if the constraint name is not specified.
+func newConstraintIndexDefinition(table string, constraint *ast.ConstraintDef) *advisor.IndexDefinition {
+	if len(constraint.KeyList) == 0 {
+		return nil
+	}
+	index := &advisor.IndexDefinition{
+		Name:       constraint.Name,
+		ColumnList: constraint.KeyList,
+		Unique:     true,
+	}
+	switch constraint.Type {
+	case ast.ConstraintTypePrimary:
+		index.Primary = true
+		if index.Name == "" {
+			index.Name = fmt.Sprintf("%s_pkey", table)
+		}
+	case ast.ConstraintTypeUnique:
+		if index.Name == "" {
+			index.Name = fmt.Sprintf("%s_%s_key", table, strings.Join(constraint.KeyList, "_"))
+		}
+	default:
+		return nil
+	}
+	return index
+}
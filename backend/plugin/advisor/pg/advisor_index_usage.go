@@ -0,0 +1,78 @@
+package pg
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
+)
+
+var (
+	_ advisor.Advisor = (*IndexUsageAdvisor)(nil)
+	_ ast.Visitor     = (*indexUsageChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLIndexUsage, &IndexUsageAdvisor{})
+}
+
+// IndexUsageAdvisor is the advisor attaching the index usage context to the dropped indexes.
+type IndexUsageAdvisor struct {
+}
+
+// Check attaches the index usage context to the dropped indexes.
+func (*IndexUsageAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return nil, nil
+	}
+
+	checker := &indexUsageChecker{
+		unusedIndexList: ctx.UnusedIndexList,
+	}
+	for _, stmt := range stmtList {
+		checker.line = stmt.LastLine()
+		ast.Walk(checker, stmt)
+	}
+	return checker.adviceList, nil
+}
+
+type indexUsageChecker struct {
+	adviceList      []advisor.Advice
+	line            int
+	unusedIndexList []*advisor.UnusedIndex
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *indexUsageChecker) Visit(node ast.Node) ast.Visitor {
+	switch node := node.(type) {
+	case *ast.DropIndexStmt:
+		for _, index := range node.IndexList {
+			schema := ""
+			if index.Table != nil {
+				schema = index.Table.Schema
+			}
+			// The index name is unique in a schema in PostgreSQL, so we don't need the table name.
+			checker.dropIndex(schema, "", index.Name)
+		}
+	case *ast.AlterTableStmt:
+		for _, item := range node.AlterItemList {
+			if cmd, ok := item.(*ast.DropConstraintStmt); ok {
+				checker.dropIndex(cmd.Table.Schema, cmd.Table.Name, cmd.ConstraintName)
+			}
+		}
+	}
+
+	return checker
+}
+
+func (checker *indexUsageChecker) dropIndex(schema string, table string, index string) {
+	unusedIndex := advisor.FindUnusedIndex(checker.unusedIndexList, normalizeSchemaName(schema), table, index, true /* caseSensitive */)
+	if unusedIndex == nil {
+		return
+	}
+	if advice := advisor.NewUnusedIndexAdvice(unusedIndex, fmt.Sprintf("%q", unusedIndex.Name), fmt.Sprintf("%q.%q", unusedIndex.Schema, unusedIndex.Table), checker.line); advice != nil {
+		checker.adviceList = append(checker.adviceList, *advice)
+	}
+}
@@ -0,0 +1,64 @@
+package pg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+
+	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/engine/pg"
+)
+
+func TestPostgreSQLIndexUsage(t *testing.T) {
+	unusedIndexList := []*advisor.UnusedIndex{
+		{Schema: "public", Table: "tech_book", Name: "idx_tech_book_name", UnusedSince: time.Now().Add(-10 * 24 * time.Hour)},
+		{Schema: "public", Table: "tech_book", Name: "uk_tech_book_id", UnusedSince: time.Now().Add(-1 * 24 * time.Hour)},
+		{Schema: "public", Table: "tech_book", Name: "idx_tech_book_id", UnusedSince: time.Now()},
+	}
+	tests := []advisor.TestCase{
+		{
+			Statement: "DROP INDEX idx_tech_book_name;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.IndexUnused,
+					Title:   advisor.UnusedIndexTitle,
+					Content: "Index \"idx_tech_book_name\" on table \"public\".\"tech_book\" appears unused for 10 days",
+					Line:    1,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE tech_book DROP CONSTRAINT uk_tech_book_id;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.IndexUnused,
+					Title:   advisor.UnusedIndexTitle,
+					Content: "Index \"uk_tech_book_id\" on table \"public\".\"tech_book\" appears unused for 1 day",
+					Line:    1,
+				},
+			},
+		},
+		{
+			// The index is observed unused for less than one day.
+			Statement: "DROP INDEX idx_tech_book_id;",
+			Want:      nil,
+		},
+		{
+			Statement: "DROP INDEX other.idx_tech_book_name;",
+			Want:      nil,
+		},
+	}
+
+	adv := &IndexUsageAdvisor{}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(advisor.Context{UnusedIndexList: unusedIndexList}, tc.Statement)
+		require.NoError(t, err)
+		assert.Equal(t, tc.Want, adviceList, tc.Statement)
+	}
+}
@@ -0,0 +1,239 @@
+package pg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
+)
+
+var (
+	_ advisor.Advisor = (*RequireIdempotentMigrationAdvisor)(nil)
+)
+
+// The regexps only locate where to insert the clauses for the suggested statements, whether the statements are
+// idempotent is decided by the AST nodes. The prefixes skip the leading comments of the statements.
+const leadingComments = `(?:\s*(?:--[^\n]*(?:\n|$)|/\*.*?\*/))*`
+
+var (
+	createTableRegexp            = regexp.MustCompile(`(?is)^(` + leadingComments + `\s*CREATE\s+(?:(?:GLOBAL|LOCAL)\s+)?(?:(?:TEMPORARY|TEMP|UNLOGGED)\s+)?TABLE)\s+`)
+	createIndexRegexp            = regexp.MustCompile(`(?is)^(` + leadingComments + `\s*CREATE\s+(?:UNIQUE\s+)?INDEX(?:\s+CONCURRENTLY)?)\s+`)
+	createSchemaRegexp           = regexp.MustCompile(`(?is)^(` + leadingComments + `\s*CREATE\s+SCHEMA)\s+`)
+	createSequenceRegexp         = regexp.MustCompile(`(?is)^(` + leadingComments + `\s*CREATE\s+(?:(?:TEMPORARY|TEMP|UNLOGGED)\s+)?SEQUENCE)\s+`)
+	createExtensionRegexp        = regexp.MustCompile(`(?is)^(` + leadingComments + `\s*CREATE\s+EXTENSION)\s+`)
+	createMaterializedViewRegexp = regexp.MustCompile(`(?is)^(` + leadingComments + `\s*CREATE\s+MATERIALIZED\s+VIEW)\s+`)
+	createRegexp                 = regexp.MustCompile(`(?is)^(` + leadingComments + `\s*CREATE)\s+`)
+	dropRegexp                   = regexp.MustCompile(`(?is)^(` + leadingComments + `\s*DROP\s+(?:MATERIALIZED\s+VIEW|INDEX\s+CONCURRENTLY|TABLE|VIEW|INDEX|SCHEMA|SEQUENCE|EXTENSION|TYPE|FUNCTION|PROCEDURE|TRIGGER|DATABASE))\s+`)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLRequireIdempotentMigration, &RequireIdempotentMigrationAdvisor{})
+}
+
+// RequireIdempotentMigrationAdvisor is the advisor checking for the CREATE and DROP statements to be re-runnable.
+type RequireIdempotentMigrationAdvisor struct {
+}
+
+// Check checks for the CREATE and DROP statements to be re-runnable.
+func (*RequireIdempotentMigrationAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		// The statements wrapped in the DO blocks are unconverted and skipped, so the guarded statements are allowed.
+		content, fix := checkIdempotent(stmt)
+		if content == "" {
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.MigrationNotIdempotent,
+			Title:   string(ctx.Rule.Type),
+			Content: content,
+			Line:    stmt.LastLine(),
+			Details: fix,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// checkIdempotent returns the advice content and the suggested statement if the statement is not idempotent.
+// The suggestion is empty if we cannot rewrite the statement.
+func checkIdempotent(stmt ast.Node) (string, string) {
+	text := stmt.Text()
+	switch node := stmt.(type) {
+	case *ast.CreateTableStmt:
+		if node.IfNotExists {
+			return "", ""
+		}
+		return fmt.Sprintf("Creating table %q without IF NOT EXISTS is not idempotent", node.Name.Name), insertClause(text, createTableRegexp, "IF NOT EXISTS")
+	case *ast.CreateIndexStmt:
+		if node.IfNotExists {
+			return "", ""
+		}
+		// PostgreSQL requires the index name for CREATE INDEX IF NOT EXISTS.
+		if node.Index.Name == "" {
+			return "Creating index without the name is not idempotent, please specify the index name and use IF NOT EXISTS", ""
+		}
+		return fmt.Sprintf("Creating index %q without IF NOT EXISTS is not idempotent", node.Index.Name), insertClause(text, createIndexRegexp, "IF NOT EXISTS")
+	case *ast.CreateSchemaStmt:
+		if node.IfNotExists {
+			return "", ""
+		}
+		return fmt.Sprintf("Creating schema %q without IF NOT EXISTS is not idempotent", node.Name), insertClause(text, createSchemaRegexp, "IF NOT EXISTS")
+	case *ast.CreateSequenceStmt:
+		if node.IfNotExists {
+			return "", ""
+		}
+		return fmt.Sprintf("Creating sequence %q without IF NOT EXISTS is not idempotent", node.SequenceDef.SequenceName.Name), insertClause(text, createSequenceRegexp, "IF NOT EXISTS")
+	case *ast.CreateExtensionStmt:
+		if node.IfNotExists {
+			return "", ""
+		}
+		return fmt.Sprintf("Creating extension %q without IF NOT EXISTS is not idempotent", node.Name), insertClause(text, createExtensionRegexp, "IF NOT EXISTS")
+	case *ast.CreateFunctionStmt:
+		if node.OrReplace {
+			return "", ""
+		}
+		return fmt.Sprintf("Creating function %q without OR REPLACE is not idempotent", node.Function.Name), insertClause(text, createRegexp, "OR REPLACE")
+	case *ast.CreateViewStmt:
+		if node.Name.Type == ast.TableTypeMaterializedView {
+			// PostgreSQL doesn't support CREATE OR REPLACE MATERIALIZED VIEW.
+			if node.IfNotExists {
+				return "", ""
+			}
+			return fmt.Sprintf("Creating materialized view %q without IF NOT EXISTS is not idempotent", node.Name.Name), insertClause(text, createMaterializedViewRegexp, "IF NOT EXISTS")
+		}
+		if node.Replace {
+			return "", ""
+		}
+		return fmt.Sprintf("Creating view %q without OR REPLACE is not idempotent", node.Name.Name), insertClause(text, createRegexp, "OR REPLACE")
+	case *ast.CreateTypeStmt:
+		// PostgreSQL doesn't support CREATE TYPE IF NOT EXISTS.
+		name := ""
+		if typeName := node.Type.TypeName(); typeName != nil {
+			name = typeName.Name
+		}
+		return fmt.Sprintf("Creating type %q is not idempotent, please guard it with a DO block", name), guardWithDoBlock(text)
+	case *ast.CreateTriggerStmt:
+		// CREATE OR REPLACE TRIGGER is only available since PostgreSQL 14 and isn't supported by the parser, so we
+		// suggest the DO block.
+		return fmt.Sprintf("Creating trigger %q is not idempotent, please guard it with a DO block", node.Trigger.Name), guardWithDoBlock(text)
+	case *ast.DropTableStmt:
+		if node.IfExists {
+			return "", ""
+		}
+		objectType := "table"
+		var nameList []string
+		for _, table := range node.TableList {
+			if table.Type == ast.TableTypeView {
+				objectType = "view"
+			}
+			nameList = append(nameList, table.Name)
+		}
+		return dropContent(objectType, nameList), insertClause(text, dropRegexp, "IF EXISTS")
+	case *ast.DropIndexStmt:
+		if node.IfExists {
+			return "", ""
+		}
+		var nameList []string
+		for _, index := range node.IndexList {
+			nameList = append(nameList, index.Name)
+		}
+		return dropContent("index", nameList), insertClause(text, dropRegexp, "IF EXISTS")
+	case *ast.DropSchemaStmt:
+		if node.IfExists {
+			return "", ""
+		}
+		return dropContent("schema", node.SchemaList), insertClause(text, dropRegexp, "IF EXISTS")
+	case *ast.DropSequenceStmt:
+		if node.IfExists {
+			return "", ""
+		}
+		var nameList []string
+		for _, sequence := range node.SequenceNameList {
+			nameList = append(nameList, sequence.Name)
+		}
+		return dropContent("sequence", nameList), insertClause(text, dropRegexp, "IF EXISTS")
+	case *ast.DropExtensionStmt:
+		if node.IfExists {
+			return "", ""
+		}
+		return dropContent("extension", node.NameList), insertClause(text, dropRegexp, "IF EXISTS")
+	case *ast.DropTypeStmt:
+		if node.IfExists {
+			return "", ""
+		}
+		var nameList []string
+		for _, typeName := range node.TypeNameList {
+			nameList = append(nameList, typeName.Name)
+		}
+		return dropContent("type", nameList), insertClause(text, dropRegexp, "IF EXISTS")
+	case *ast.DropFunctionStmt:
+		if node.IfExists {
+			return "", ""
+		}
+		var nameList []string
+		for _, function := range node.FunctionList {
+			nameList = append(nameList, function.Name)
+		}
+		return dropContent("function", nameList), insertClause(text, dropRegexp, "IF EXISTS")
+	case *ast.DropTriggerStmt:
+		if node.IfExists {
+			return "", ""
+		}
+		return dropContent("trigger", []string{node.Trigger.Name}), insertClause(text, dropRegexp, "IF EXISTS")
+	case *ast.DropDatabaseStmt:
+		if node.IfExists {
+			return "", ""
+		}
+		return dropContent("database", []string{node.DatabaseName}), insertClause(text, dropRegexp, "IF EXISTS")
+	}
+	return "", ""
+}
+
+func dropContent(objectType string, nameList []string) string {
+	var quotedList []string
+	for _, name := range nameList {
+		quotedList = append(quotedList, fmt.Sprintf("%q", name))
+	}
+	return fmt.Sprintf("Dropping %s %s without IF EXISTS is not idempotent", objectType, strings.Join(quotedList, ", "))
+}
+
+// insertClause inserts the clause after the prefix matched by the regexp, returns empty string if not matched.
+func insertClause(text string, prefix *regexp.Regexp, clause string) string {
+	loc := prefix.FindStringSubmatchIndex(text)
+	if loc == nil {
+		return ""
+	}
+	// loc[3] is the end of the first group.
+	return normalizeStatementText(text[:loc[3]] + " " + clause + " " + text[loc[1]:])
+}
+
+func guardWithDoBlock(text string) string {
+	return fmt.Sprintf("DO $$\nBEGIN\n    %s\nEXCEPTION\n    WHEN duplicate_object THEN NULL;\nEND $$;", normalizeStatementText(text))
+}
+
+func normalizeStatementText(text string) string {
+	return strings.TrimRight(strings.TrimSpace(text), ";") + ";"
+}
@@ -0,0 +1,119 @@
+package pg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
+)
+
+var (
+	_ advisor.Advisor = (*TableRequireHypertableAdvisor)(nil)
+
+	createHypertableRegexp = regexp.MustCompile(`(?is)create_hypertable\s*\(\s*(?:relation\s*=>\s*)?'([^']+)'`)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLTableRequireHypertable, &TableRequireHypertableAdvisor{})
+}
+
+// TableRequireHypertableAdvisor is the advisor checking for the time-series tables to be TimescaleDB hypertables.
+type TableRequireHypertableAdvisor struct {
+}
+
+// Check checks for the time-series tables to be TimescaleDB hypertables.
+func (*TableRequireHypertableAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	format, _, err := advisor.UnamrshalNamingRulePayloadAsRegexp(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var tableList []*ast.CreateTableStmt
+	hypertableSet := make(map[string]bool)
+	for _, stmt := range stmtList {
+		switch node := stmt.(type) {
+		case *ast.CreateTableStmt:
+			if !format.MatchString(node.Name.Name) {
+				continue
+			}
+			// TimescaleDB 2.13+ supports declaring hypertables in the CREATE TABLE options.
+			text := strings.ToLower(node.Text())
+			if strings.Contains(text, "tsdb.hypertable") || strings.Contains(text, "timescaledb.hypertable") {
+				continue
+			}
+			tableList = append(tableList, node)
+		case *ast.SelectStmt:
+			for _, match := range createHypertableRegexp.FindAllStringSubmatch(node.Text(), -1) {
+				hypertableSet[normalizeHypertableName(match[1])] = true
+			}
+		}
+	}
+
+	var adviceList []advisor.Advice
+	for _, table := range tableList {
+		if hypertableSet[strings.ToLower(table.Name.Name)] {
+			continue
+		}
+		advice := advisor.Advice{
+			Status: level,
+			Code:   advisor.TableNotHypertable,
+			Title:  string(ctx.Rule.Type),
+			Line:   table.LastLine(),
+		}
+		if column := getTimeColumn(table); column != "" {
+			advice.Content = fmt.Sprintf("Time-series table %q must be a hypertable, please call create_hypertable after creating it", table.Name.Name)
+			advice.Details = fmt.Sprintf("SELECT create_hypertable('%s', '%s');", getHypertableRelation(table.Name), column)
+		} else {
+			advice.Content = fmt.Sprintf("Time-series table %q must be a hypertable, but it has no timestamp, timestamptz or date column to partition by", table.Name.Name)
+		}
+		adviceList = append(adviceList, advice)
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// normalizeHypertableName strips the schema and quotes of the create_hypertable relation argument.
+func normalizeHypertableName(relation string) string {
+	if i := strings.LastIndex(relation, "."); i >= 0 {
+		relation = relation[i+1:]
+	}
+	return strings.ToLower(strings.Trim(relation, `"`))
+}
+
+// getTimeColumn returns the first column that can be used as the hypertable time dimension.
+func getTimeColumn(table *ast.CreateTableStmt) string {
+	for _, column := range table.ColumnList {
+		tp := strings.ToLower(column.Type.Text())
+		if strings.HasPrefix(tp, "timestamp") || tp == "date" {
+			return column.ColumnName
+		}
+	}
+	return ""
+}
+
+func getHypertableRelation(table *ast.TableDef) string {
+	if table.Schema != "" {
+		return fmt.Sprintf("%s.%s", table.Schema, table.Name)
+	}
+	return table.Name
+}
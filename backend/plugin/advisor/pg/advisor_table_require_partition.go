@@ -0,0 +1,115 @@
+package pg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
+)
+
+var (
+	_ advisor.Advisor = (*TableRequirePartitionAdvisor)(nil)
+	_ ast.Visitor     = (*tableRequirePartitionChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLTableRequirePartition, &TableRequirePartitionAdvisor{})
+}
+
+// TableRequirePartitionAdvisor is the advisor checking for the high-volume tables to be partitioned.
+type TableRequirePartitionAdvisor struct {
+}
+
+// Check checks for the high-volume tables to be partitioned.
+func (*TableRequirePartitionAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	format, strategyList, err := advisor.UnmarshalRequirePartitionRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	checker := &tableRequirePartitionChecker{
+		level:        level,
+		title:        string(ctx.Rule.Type),
+		format:       format,
+		strategyList: strategyList,
+	}
+
+	for _, stmt := range stmtList {
+		checker.line = stmt.LastLine()
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type tableRequirePartitionChecker struct {
+	adviceList   []advisor.Advice
+	level        advisor.Status
+	title        string
+	line         int
+	format       *regexp.Regexp
+	strategyList []string
+}
+
+// Visit implements ast.Visitor interface.
+func (checker *tableRequirePartitionChecker) Visit(in ast.Node) ast.Visitor {
+	node, ok := in.(*ast.CreateTableStmt)
+	if !ok {
+		return checker
+	}
+	tableName := node.Name.Name
+	if !checker.format.MatchString(tableName) {
+		return checker
+	}
+
+	content := ""
+	if node.PartitionDef == nil {
+		content = fmt.Sprintf("High-volume table %q requires partitioning", tableName)
+	} else if strategy := string(node.PartitionDef.Strategy); !isApprovedPartitionStrategy(strategy, checker.strategyList) {
+		content = fmt.Sprintf("High-volume table %q is partitioned by %s, but only %s are approved", tableName, strategy, strings.Join(checker.strategyList, ", "))
+	}
+
+	if content != "" {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    advisor.TableNoPartition,
+			Title:   checker.title,
+			Content: content,
+			Line:    checker.line,
+		})
+	}
+
+	return checker
+}
+
+// isApprovedPartitionStrategy returns true if the strategy is in the approved list, or the list is empty.
+func isApprovedPartitionStrategy(strategy string, strategyList []string) bool {
+	if len(strategyList) == 0 {
+		return true
+	}
+	for _, approved := range strategyList {
+		if approved == strategy {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
+)
+
+var (
+	_ advisor.Advisor = (*TableRequireRowLevelSecurityAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLTableRequireRowLevelSecurity, &TableRequireRowLevelSecurityAdvisor{})
+}
+
+// TableRequireRowLevelSecurityAdvisor is the advisor checking for the labeled tables to have row level security policies.
+type TableRequireRowLevelSecurityAdvisor struct {
+}
+
+// Check checks for the tables labeled in the comments, e.g. multi-tenant, to enable the row level security and create
+// a policy in the same change as creating them.
+func (*TableRequireRowLevelSecurityAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalStringArrayTypeRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var tableList []*ast.CreateTableStmt
+	labelMap := make(map[string]string)
+	enabledSet := make(map[string]bool)
+	policySet := make(map[string]bool)
+	for _, stmt := range stmtList {
+		switch node := stmt.(type) {
+		case *ast.CreateTableStmt:
+			tableList = append(tableList, node)
+			continue
+		case *ast.CommentStmt, *ast.UnconvertedStmt:
+		case *ast.AlterTableStmt:
+			// ENABLE ROW LEVEL SECURITY isn't converted to an alter item.
+			if len(node.AlterItemList) > 0 {
+				continue
+			}
+		default:
+			continue
+		}
+		tree, err := pgquery.Parse(stmt.Text())
+		if err != nil {
+			continue
+		}
+		for _, rawStmt := range tree.Stmts {
+			switch n := rawStmt.Stmt.Node.(type) {
+			case *pgquery.Node_CommentStmt:
+				if n.CommentStmt.Objtype != pgquery.ObjectType_OBJECT_TABLE {
+					continue
+				}
+				if label := findLabel(n.CommentStmt.Comment, payload.List); label != "" {
+					labelMap[getCommentTableName(n.CommentStmt.Object)] = label
+				}
+			case *pgquery.Node_AlterTableStmt:
+				for _, cmd := range n.AlterTableStmt.Cmds {
+					if alterCmd, ok := cmd.Node.(*pgquery.Node_AlterTableCmd); ok && alterCmd.AlterTableCmd.Subtype == pgquery.AlterTableType_AT_EnableRowSecurity {
+						enabledSet[strings.ToLower(n.AlterTableStmt.Relation.Relname)] = true
+					}
+				}
+			case *pgquery.Node_CreatePolicyStmt:
+				policySet[strings.ToLower(n.CreatePolicyStmt.Table.Relname)] = true
+			}
+		}
+	}
+
+	var adviceList []advisor.Advice
+	for _, table := range tableList {
+		name := strings.ToLower(table.Name.Name)
+		label, ok := labelMap[name]
+		if !ok {
+			continue
+		}
+		var missing []string
+		if !enabledSet[name] {
+			missing = append(missing, fmt.Sprintf("ALTER TABLE %s ENABLE ROW LEVEL SECURITY", table.Name.Name))
+		}
+		if !policySet[name] {
+			missing = append(missing, "CREATE POLICY")
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.TableNoRowLevelSecurity,
+			Title:   string(ctx.Rule.Type),
+			Content: fmt.Sprintf("Table %q is labeled %s and requires row level security, missing %s", table.Name.Name, label, strings.Join(missing, " and ")),
+			Line:    table.LastLine(),
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// findLabel returns the first label found in the comment, case-insensitively.
+func findLabel(comment string, labelList []string) string {
+	comment = strings.ToLower(comment)
+	for _, label := range labelList {
+		if label != "" && strings.Contains(comment, strings.ToLower(label)) {
+			return label
+		}
+	}
+	return ""
+}
+
+// getCommentTableName returns the lower-cased name of the table in the COMMENT ON TABLE statement, without the schema.
+func getCommentTableName(object *pgquery.Node) string {
+	list, ok := object.Node.(*pgquery.Node_List)
+	if !ok || len(list.List.Items) == 0 {
+		return ""
+	}
+	name, ok := list.List.Items[len(list.List.Items)-1].Node.(*pgquery.Node_String_)
+	if !ok {
+		return ""
+	}
+	return strings.ToLower(name.String_.Str)
+}
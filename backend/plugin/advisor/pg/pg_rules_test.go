@@ -31,9 +31,13 @@ func TestPostgreSQLRules(t *testing.T) {
 		advisor.SchemaRuleTableRequirePK,
 		advisor.SchemaRuleColumnDisallowChangeType,
 		advisor.SchemaRuleTableDisallowPartition,
+		advisor.SchemaRuleTableRequirePartition,
+		advisor.SchemaRuleTableRequireHypertable,
+		advisor.SchemaRuleTableRequireRowLevelSecurity,
 		advisor.SchemaRuleIndexPrimaryKeyTypeAllowlist,
 		advisor.SchemaRuleColumnMaximumCharacterLength,
 		advisor.SchemaRuleStatementDisallowCommit,
+		advisor.SchemaRuleStatementDisallowHardCodedSecret,
 		advisor.SchemaRuleStatementDMLDryRun,
 		advisor.SchemaRuleStatementInsertMustSpecifyColumn,
 		advisor.SchemaRuleStatementInsertDisallowOrderByRand,
@@ -47,6 +51,8 @@ func TestPostgreSQLRules(t *testing.T) {
 		advisor.SchemaRuleCreateIndexConcurrently,
 		advisor.SchemaRuleStatementAddCheckNotValid,
 		advisor.SchemaRuleStatementDisallowAddNotNull,
+		advisor.SchemaRuleIndexNoRedundant,
+		advisor.SchemaRuleSchemaRequireIdempotentMigration,
 	}
 
 	for _, rule := range pgRules {
@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*CommandDisallowListAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Redis, advisor.RedisCommandDisallowList, &CommandDisallowListAdvisor{})
+}
+
+// CommandDisallowListAdvisor is the advisor checking for the disallowed commands.
+type CommandDisallowListAdvisor struct {
+}
+
+// Check checks for the disallowed commands.
+func (*CommandDisallowListAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalStringArrayTypeRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	var disallowList [][]string
+	for _, disallowed := range payload.List {
+		if fieldList := strings.Fields(disallowed); len(fieldList) > 0 {
+			disallowList = append(disallowList, fieldList)
+		}
+	}
+
+	var adviceList []advisor.Advice
+	for _, command := range parseCommandList(statement) {
+		for _, disallowed := range disallowList {
+			if !command.hasPrefix(disallowed) {
+				continue
+			}
+			adviceList = append(adviceList, advisor.Advice{
+				Status:  level,
+				Code:    advisor.StatementDisallowedCommand,
+				Title:   string(ctx.Rule.Type),
+				Content: fmt.Sprintf("Command \"%s\" is disallowed, the disallowed commands are [%s]", strings.ToUpper(strings.Join(disallowed, " ")), strings.Join(payload.List, ", ")),
+				Line:    command.line,
+			})
+			break
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
@@ -0,0 +1,18 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+func TestRedisRules(t *testing.T) {
+	redisRules := []advisor.SQLReviewRuleType{
+		advisor.SchemaRuleStatementRedisCommandDisallowList,
+	}
+
+	for _, rule := range redisRules {
+		advisor.RunSQLReviewRuleTest(t, rule, db.Redis, false /* record */)
+	}
+}
@@ -0,0 +1,48 @@
+// Package redis implements the SQL review rules for Redis.
+package redis
+
+import (
+	"strings"
+)
+
+type command struct {
+	// fieldList is the command name and arguments split by spaces.
+	fieldList []string
+	line      int
+}
+
+// parseCommandList splits the statement into commands in the same way as the Redis driver executes them,
+// which is one command per line with the arguments separated by spaces.
+func parseCommandList(statement string) []*command {
+	var commandList []*command
+	for i, line := range strings.Split(statement, "\n") {
+		line = strings.Trim(line, " \n\t\r")
+		if line == "" {
+			continue
+		}
+		var fieldList []string
+		for _, field := range strings.Split(line, " ") {
+			if field != "" {
+				fieldList = append(fieldList, field)
+			}
+		}
+		commandList = append(commandList, &command{
+			fieldList: fieldList,
+			line:      i + 1,
+		})
+	}
+	return commandList
+}
+
+// hasPrefix returns true if the command starts with the command prefix, e.g. CONFIG SET, case-insensitively.
+func (c *command) hasPrefix(prefix []string) bool {
+	if len(prefix) == 0 || len(prefix) > len(c.fieldList) {
+		return false
+	}
+	for i, field := range prefix {
+		if !strings.EqualFold(field, c.fieldList[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,188 @@
+package advisor
+
+import "fmt"
+
+const (
+	// SARIFVersion is the SARIF specification version we produce.
+	SARIFVersion = "2.1.0"
+	// SARIFSchema is the JSON schema location for SARIF 2.1.0.
+	SARIFSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	sarifToolName           = "Bytebase SQL Review"
+	sarifToolInformationURI = "https://www.bytebase.com/docs/sql-review/review-rules"
+)
+
+// SARIFLevel is the result level in SARIF.
+type SARIFLevel string
+
+const (
+	// SARIFLevelError is the SARIF level for errors.
+	SARIFLevelError SARIFLevel = "error"
+	// SARIFLevelWarning is the SARIF level for warnings.
+	SARIFLevelWarning SARIFLevel = "warning"
+)
+
+// SARIFReport is the root object of a SARIF log.
+// Only the subset consumed by GitHub Code Scanning is modeled here.
+type SARIFReport struct {
+	Version string      `json:"version"`
+	Schema  string      `json:"$schema"`
+	Runs    []*SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single run of the analysis tool.
+type SARIFRun struct {
+	Tool    *SARIFTool     `json:"tool"`
+	Results []*SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the analysis tool.
+type SARIFTool struct {
+	Driver *SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver describes the tool component and the rules it reports.
+type SARIFDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*SARIFRule `json:"rules"`
+}
+
+// SARIFRule is the metadata of a reporting rule.
+type SARIFRule struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name,omitempty"`
+	ShortDescription *SARIFMessage     `json:"shortDescription"`
+	DefaultConfig    *SARIFRuleDefault `json:"defaultConfiguration,omitempty"`
+}
+
+// SARIFRuleDefault is the default configuration of a rule.
+type SARIFRuleDefault struct {
+	Level SARIFLevel `json:"level"`
+}
+
+// SARIFMessage is a SARIF message object.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is a single finding.
+type SARIFResult struct {
+	RuleID    string           `json:"ruleId"`
+	RuleIndex int              `json:"ruleIndex"`
+	Level     SARIFLevel       `json:"level"`
+	Message   *SARIFMessage    `json:"message"`
+	Locations []*SARIFLocation `json:"locations,omitempty"`
+}
+
+// SARIFLocation is the location of a finding.
+type SARIFLocation struct {
+	PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is the physical location of a finding.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation *SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion           `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation is the file of a finding.
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is the region in the file of a finding.
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIFFileAdvice is the advice list for a single file.
+type SARIFFileAdvice struct {
+	// FilePath is the path of the file relative to the repository root.
+	FilePath string
+	// LineOffset is the line of the file where the reviewed statement starts, 0 if the statement is the whole file.
+	LineOffset int
+	AdviceList []Advice
+}
+
+// ConvertToSARIF converts the advice lists to a SARIF report.
+// Advices with SUCCESS status are skipped.
+func ConvertToSARIF(fileAdviceList []*SARIFFileAdvice) *SARIFReport {
+	ruleIndex := make(map[string]int)
+	rules := []*SARIFRule{}
+	results := []*SARIFResult{}
+
+	for _, file := range fileAdviceList {
+		for _, advice := range file.AdviceList {
+			level, ok := convertStatusToSARIFLevel(advice.Status)
+			if !ok {
+				continue
+			}
+			ruleID := getSARIFRuleID(advice.Code)
+			index, ok := ruleIndex[ruleID]
+			if !ok {
+				index = len(rules)
+				ruleIndex[ruleID] = index
+				rules = append(rules, &SARIFRule{
+					ID:               ruleID,
+					Name:             advice.Title,
+					ShortDescription: &SARIFMessage{Text: advice.Title},
+					DefaultConfig:    &SARIFRuleDefault{Level: level},
+				})
+			}
+
+			message := advice.Content
+			if message == "" {
+				message = advice.Title
+			}
+			result := &SARIFResult{
+				RuleID:    ruleID,
+				RuleIndex: index,
+				Level:     level,
+				Message:   &SARIFMessage{Text: message},
+			}
+			if file.FilePath != "" {
+				location := &SARIFPhysicalLocation{
+					ArtifactLocation: &SARIFArtifactLocation{URI: file.FilePath},
+				}
+				// SARIF lines are 1-based. Line 0 in advice means the position is unknown.
+				if advice.Line > 0 {
+					location.Region = &SARIFRegion{StartLine: advice.Line + file.LineOffset}
+				}
+				result.Locations = []*SARIFLocation{{PhysicalLocation: location}}
+			}
+			results = append(results, result)
+		}
+	}
+
+	return &SARIFReport{
+		Version: SARIFVersion,
+		Schema:  SARIFSchema,
+		Runs: []*SARIFRun{
+			{
+				Tool: &SARIFTool{
+					Driver: &SARIFDriver{
+						Name:           sarifToolName,
+						InformationURI: sarifToolInformationURI,
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+}
+
+func getSARIFRuleID(code Code) string {
+	return fmt.Sprintf("BB%d", code)
+}
+
+func convertStatusToSARIFLevel(status Status) (SARIFLevel, bool) {
+	switch status {
+	case Error:
+		return SARIFLevelError, true
+	case Warn:
+		return SARIFLevelWarning, true
+	}
+	return "", false
+}
@@ -0,0 +1,51 @@
+package advisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertToSARIF(t *testing.T) {
+	a := require.New(t)
+
+	report := ConvertToSARIF([]*SARIFFileAdvice{
+		{
+			FilePath: "migrations/1.0__create_table.sql",
+			AdviceList: []Advice{
+				{Status: Success, Code: Ok, Title: "OK"},
+				{Status: Warn, Code: StatementSelectAll, Title: "statement.select.no-select-all", Content: "\"SELECT * FROM t\" uses SELECT all", Line: 3},
+				{Status: Error, Code: StatementNoWhere, Title: "statement.where.require", Content: "\"DELETE FROM t\" requires WHERE clause", Line: 5},
+			},
+		},
+		{
+			FilePath:   "migrations/1.1__update.sql",
+			LineOffset: 10,
+			AdviceList: []Advice{
+				{Status: Warn, Code: StatementSelectAll, Title: "statement.select.no-select-all", Content: "\"SELECT * FROM t2\" uses SELECT all", Line: 1},
+				{Status: Error, Code: Internal, Title: "Internal error"},
+			},
+		},
+	})
+
+	a.Equal(SARIFVersion, report.Version)
+	a.Len(report.Runs, 1)
+	run := report.Runs[0]
+	a.Equal([]*SARIFRule{
+		{ID: "BB203", Name: "statement.select.no-select-all", ShortDescription: &SARIFMessage{Text: "statement.select.no-select-all"}, DefaultConfig: &SARIFRuleDefault{Level: SARIFLevelWarning}},
+		{ID: "BB202", Name: "statement.where.require", ShortDescription: &SARIFMessage{Text: "statement.where.require"}, DefaultConfig: &SARIFRuleDefault{Level: SARIFLevelError}},
+		{ID: "BB1", Name: "Internal error", ShortDescription: &SARIFMessage{Text: "Internal error"}, DefaultConfig: &SARIFRuleDefault{Level: SARIFLevelError}},
+	}, run.Tool.Driver.Rules)
+
+	a.Len(run.Results, 4)
+	a.Equal("BB203", run.Results[0].RuleID)
+	a.Equal(0, run.Results[0].RuleIndex)
+	a.Equal(3, run.Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+	a.Equal(SARIFLevelError, run.Results[1].Level)
+	a.Equal("migrations/1.0__create_table.sql", run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	a.Equal(0, run.Results[2].RuleIndex)
+	a.Equal(11, run.Results[2].Locations[0].PhysicalLocation.Region.StartLine)
+	// Unknown line has no region.
+	a.Nil(run.Results[3].Locations[0].PhysicalLocation.Region)
+	a.Equal("Internal error", run.Results[3].Message.Text)
+}
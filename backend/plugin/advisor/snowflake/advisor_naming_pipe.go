@@ -0,0 +1,23 @@
+package snowflake
+
+import (
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*NamingPipeAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Snowflake, advisor.SnowflakeNamingPipe, &NamingPipeAdvisor{})
+}
+
+// NamingPipeAdvisor is the advisor checking for pipe naming convention.
+type NamingPipeAdvisor struct {
+}
+
+// Check checks for pipe naming convention.
+func (*NamingPipeAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	return checkObjectNaming(ctx, statement, "PIPE", advisor.NamingPipeConventionMismatch)
+}
@@ -0,0 +1,23 @@
+package snowflake
+
+import (
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*NamingStageAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Snowflake, advisor.SnowflakeNamingStage, &NamingStageAdvisor{})
+}
+
+// NamingStageAdvisor is the advisor checking for stage naming convention.
+type NamingStageAdvisor struct {
+}
+
+// Check checks for stage naming convention.
+func (*NamingStageAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	return checkObjectNaming(ctx, statement, "STAGE", advisor.NamingStageConventionMismatch)
+}
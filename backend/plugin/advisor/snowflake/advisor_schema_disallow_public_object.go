@@ -0,0 +1,65 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*SchemaDisallowPublicObjectAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Snowflake, advisor.SnowflakeSchemaDisallowPublicObject, &SchemaDisallowPublicObjectAdvisor{})
+}
+
+// SchemaDisallowPublicObjectAdvisor is the advisor checking for disallowing creating objects in the PUBLIC schema.
+type SchemaDisallowPublicObjectAdvisor struct {
+}
+
+// Check checks for disallowing creating objects in the PUBLIC schema.
+func (*SchemaDisallowPublicObjectAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	// The unqualified objects are created in the PUBLIC schema unless the USE statements switch the schema.
+	session := newSession()
+	for _, stmt := range stmtList {
+		session.use(stmt)
+		object := stmt.parseCreateObject()
+		if object == nil {
+			continue
+		}
+		if _, schema := session.resolve(object.name); schema != publicSchemaName {
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  level,
+			Code:    advisor.CreateObjectInPublicSchema,
+			Title:   string(ctx.Rule.Type),
+			Content: fmt.Sprintf("Cannot create %s \"%s\" in the PUBLIC schema, please use a dedicated schema", strings.ToLower(object.objectType), object.name.originName),
+			Line:    stmt.line,
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
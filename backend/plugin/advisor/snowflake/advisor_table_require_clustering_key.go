@@ -0,0 +1,202 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*TableRequireClusteringKeyAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Snowflake, advisor.SnowflakeTableRequireClusteringKey, &TableRequireClusteringKeyAdvisor{})
+}
+
+// TableRequireClusteringKeyAdvisor is the advisor checking for the clustering key of the large tables.
+type TableRequireClusteringKeyAdvisor struct {
+}
+
+// Check checks for the clustering key of the large tables.
+func (*TableRequireClusteringKeyAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalNumberTypeRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	checker := &tableRequireClusteringKeyChecker{
+		level:     level,
+		title:     string(ctx.Rule.Type),
+		maxSizeGB: payload.Number,
+	}
+	// The table size and clustering key are only available in the database.
+	if payload.Number > 0 && ctx.Driver != nil {
+		checker.getTable = func(database string, schema string, table string) (*clusteringTable, error) {
+			return getClusteringTable(ctx.Context, ctx.Driver, database, schema, table)
+		}
+		checker.check(stmtList)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type clusteringTable struct {
+	clusteringKey string
+	bytes         int64
+}
+
+type tableReference struct {
+	database string
+	schema   string
+	name     string
+	line     int
+}
+
+type tableRequireClusteringKeyChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	maxSizeGB  int
+	// getTable returns nil if the table doesn't exist.
+	getTable func(database string, schema string, table string) (*clusteringTable, error)
+}
+
+func (checker *tableRequireClusteringKeyChecker) check(stmtList []*singleStatement) {
+	var referenceList []*tableReference
+	referenced := make(map[string]bool)
+	created := make(map[string]bool)
+	// clustered is whether the table has the clustering key after the reviewed statements change it.
+	clustered := make(map[string]bool)
+
+	session := newSession()
+	for _, stmt := range stmtList {
+		session.use(stmt)
+		if object := stmt.parseCreateObject(); object != nil && object.objectType == "TABLE" {
+			database, schema := session.resolve(object.name)
+			created[tableKey(database, schema, object.name.name)] = true
+			continue
+		}
+		name, i := getWrittenTable(stmt)
+		if name == nil {
+			continue
+		}
+		database, schema := session.resolve(name)
+		key := tableKey(database, schema, name.name)
+		if _, ok := stmt.matchKeywords(0, "ALTER"); ok {
+			if _, ok := stmt.matchKeywords(i, "CLUSTER", "BY"); ok {
+				clustered[key] = true
+			} else if stmt.containsKeywords(i, "DROP", "CLUSTERING", "KEY") {
+				clustered[key] = false
+			}
+		}
+		if referenced[key] || created[key] {
+			continue
+		}
+		referenced[key] = true
+		referenceList = append(referenceList, &tableReference{
+			database: database,
+			schema:   schema,
+			name:     name.name,
+			line:     stmt.line,
+		})
+	}
+
+	for _, reference := range referenceList {
+		key := tableKey(reference.database, reference.schema, reference.name)
+		if clustered[key] {
+			continue
+		}
+		table, err := checker.getTable(reference.database, reference.schema, reference.name)
+		if err != nil {
+			checker.adviceList = append(checker.adviceList, advisor.Advice{
+				Status:  checker.level,
+				Code:    advisor.Internal,
+				Title:   checker.title,
+				Content: fmt.Sprintf("Failed to get the clustering key of table \"%s\".\"%s\": %s", reference.schema, reference.name, err.Error()),
+				Line:    reference.line,
+			})
+			continue
+		}
+		if table == nil {
+			continue
+		}
+		if _, ok := clustered[key]; !ok && table.clusteringKey != "" {
+			continue
+		}
+		if sizeGB := table.bytes >> 30; sizeGB > int64(checker.maxSizeGB) {
+			checker.adviceList = append(checker.adviceList, advisor.Advice{
+				Status:  checker.level,
+				Code:    advisor.TableNoClusteringKey,
+				Title:   checker.title,
+				Content: fmt.Sprintf("Table \"%s\".\"%s\" has %d GB data without the clustering key, which exceeds %d GB, please define one by ALTER TABLE ... CLUSTER BY (...)", reference.schema, reference.name, sizeGB, checker.maxSizeGB),
+				Line:    reference.line,
+			})
+		}
+	}
+}
+
+// getWrittenTable returns the table altered or written by the statement, and the index after the table name.
+func getWrittenTable(stmt *singleStatement) (*objectName, int) {
+	if i, ok := stmt.matchKeywords(0, "ALTER", "TABLE"); ok {
+		i, _ = stmt.matchKeywords(i, "IF", "EXISTS")
+		return stmt.parseObjectName(i)
+	}
+	if i, ok := stmt.matchKeywords(0, "INSERT"); ok {
+		i, _ = stmt.matchKeywords(i, "OVERWRITE")
+		if i, ok = stmt.matchKeywords(i, "INTO"); ok {
+			return stmt.parseObjectName(i)
+		}
+		return nil, i
+	}
+	// COPY INTO @stage unloads the data, the stage name is not an identifier so it's skipped.
+	for _, keywords := range [][]string{{"COPY", "INTO"}, {"MERGE", "INTO"}, {"UPDATE"}, {"DELETE", "FROM"}} {
+		if i, ok := stmt.matchKeywords(0, keywords...); ok {
+			return stmt.parseObjectName(i)
+		}
+	}
+	return nil, 0
+}
+
+func getClusteringTable(ctx context.Context, driver *sql.DB, database string, schema string, table string) (*clusteringTable, error) {
+	informationSchema := "INFORMATION_SCHEMA"
+	if database != "" {
+		informationSchema = fmt.Sprintf(`"%s".INFORMATION_SCHEMA`, strings.ReplaceAll(database, `"`, `""`))
+	}
+	query := fmt.Sprintf(`
+		SELECT IFNULL(CLUSTERING_KEY, ''), IFNULL(BYTES, 0)
+		FROM %s.TABLES
+		WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA = ? AND TABLE_NAME = ?`, informationSchema)
+	result := &clusteringTable{}
+	if err := driver.QueryRowContext(ctx, query, schema, table).Scan(&result.clusteringKey, &result.bytes); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+func tableKey(database string, schema string, table string) string {
+	return fmt.Sprintf("%q.%q.%q", database, schema, table)
+}
@@ -0,0 +1,102 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+)
+
+func TestTableRequireClusteringKey(t *testing.T) {
+	tableMap := map[string]*clusteringTable{
+		tableKey("", "PUBLIC", "EVENTS"):           {bytes: 2048 << 30},
+		tableKey("", "PUBLIC", "ORDERS"):           {bytes: 2048 << 30, clusteringKey: "LINEAR(CREATED_AT)"},
+		tableKey("", "PUBLIC", "USERS"):            {bytes: 1 << 30},
+		tableKey("", "ANALYTICS", "EVENTS"):        {bytes: 4096 << 30},
+		tableKey("SALES", "PUBLIC", "Transaction"): {bytes: 1025 << 30},
+	}
+	tests := []advisor.TestCase{
+		{
+			Statement: "INSERT INTO events SELECT * FROM raw_events;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.TableNoClusteringKey,
+					Title:   string(advisor.SchemaRuleTableRequireClusteringKey),
+					Content: "Table \"PUBLIC\".\"EVENTS\" has 2048 GB data without the clustering key, which exceeds 1024 GB, please define one by ALTER TABLE ... CLUSTER BY (...)",
+					Line:    1,
+				},
+			},
+		},
+		{
+			// The table is only reported once.
+			Statement: "USE SCHEMA analytics;\nDELETE FROM events WHERE id = 1;\nUPDATE analytics.events SET name = 'a';\nCOPY INTO sales..\"Transaction\" FROM @my_stage;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.TableNoClusteringKey,
+					Title:   string(advisor.SchemaRuleTableRequireClusteringKey),
+					Content: "Table \"ANALYTICS\".\"EVENTS\" has 4096 GB data without the clustering key, which exceeds 1024 GB, please define one by ALTER TABLE ... CLUSTER BY (...)",
+					Line:    2,
+				},
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.TableNoClusteringKey,
+					Title:   string(advisor.SchemaRuleTableRequireClusteringKey),
+					Content: "Table \"PUBLIC\".\"Transaction\" has 1025 GB data without the clustering key, which exceeds 1024 GB, please define one by ALTER TABLE ... CLUSTER BY (...)",
+					Line:    4,
+				},
+			},
+		},
+		{
+			Statement: "ALTER TABLE orders DROP CLUSTERING KEY;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.TableNoClusteringKey,
+					Title:   string(advisor.SchemaRuleTableRequireClusteringKey),
+					Content: "Table \"PUBLIC\".\"ORDERS\" has 2048 GB data without the clustering key, which exceeds 1024 GB, please define one by ALTER TABLE ... CLUSTER BY (...)",
+					Line:    1,
+				},
+			},
+		},
+		{
+			// The table is clustered in the same change, the small tables, the new tables and the unknown tables are skipped.
+			Statement: "INSERT INTO events SELECT * FROM raw_events;\nALTER TABLE events CLUSTER BY (created_at);\nMERGE INTO orders USING s ON orders.id = s.id WHEN MATCHED THEN DELETE;\nINSERT OVERWRITE INTO users SELECT 1;\nCREATE TABLE analytics.events(id INT);\nINSERT INTO analytics.events VALUES (1);\nUPDATE unknown SET a = 1;",
+			Want:      nil,
+		},
+		{
+			Statement: "ALTER TABLE IF EXISTS failed ADD COLUMN a INT;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Warn,
+					Code:    advisor.Internal,
+					Title:   string(advisor.SchemaRuleTableRequireClusteringKey),
+					Content: "Failed to get the clustering key of table \"PUBLIC\".\"FAILED\": connection refused",
+					Line:    1,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		stmtList, errAdvice := parseStatement(test.Statement)
+		require.Nil(t, errAdvice)
+		checker := &tableRequireClusteringKeyChecker{
+			level:     advisor.Warn,
+			title:     string(advisor.SchemaRuleTableRequireClusteringKey),
+			maxSizeGB: 1024,
+			getTable: func(database string, schema string, table string) (*clusteringTable, error) {
+				if table == "FAILED" {
+					return nil, errors.New("connection refused")
+				}
+				return tableMap[tableKey(database, schema, table)], nil
+			},
+		}
+		checker.check(stmtList)
+		assert.Equal(t, test.Want, checker.adviceList, test.Statement)
+	}
+}
@@ -0,0 +1,259 @@
+package snowflake
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+)
+
+// There is no Snowflake parser yet, so we split the statements by the tokenizer and
+// recognize the statements by their leading keywords, which is enough for the DDL rules.
+
+type tokenType int
+
+const (
+	tokenIdentifier tokenType = iota
+	tokenQuotedIdentifier
+	tokenLiteral
+	tokenPunctuation
+)
+
+type token struct {
+	tp   tokenType
+	text string
+}
+
+type singleStatement struct {
+	text   string
+	line   int
+	tokens []token
+}
+
+func parseStatement(statement string) ([]*singleStatement, []advisor.Advice) {
+	list, err := parser.SplitMultiSQL(parser.Snowflake, statement)
+	if err != nil {
+		return nil, []advisor.Advice{
+			{
+				Status:  advisor.Error,
+				Code:    advisor.StatementSyntaxError,
+				Title:   advisor.SyntaxErrorTitle,
+				Content: err.Error(),
+				Line:    1,
+			},
+		}
+	}
+
+	var result []*singleStatement
+	for _, sql := range list {
+		tokens := tokenize(sql.Text)
+		if len(tokens) == 0 {
+			continue
+		}
+		result = append(result, &singleStatement{
+			text:   sql.Text,
+			line:   sql.LastLine,
+			tokens: tokens,
+		})
+	}
+	return result, nil
+}
+
+// tokenize splits the statement into tokens, the comments and whitespaces are skipped.
+func tokenize(text string) []token {
+	var tokens []token
+	runes := []rune(text)
+	n := len(runes)
+	for i := 0; i < n; {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case (r == '-' && i+1 < n && runes[i+1] == '-') || (r == '/' && i+1 < n && runes[i+1] == '/'):
+			for i < n && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < n && runes[i+1] == '*':
+			i += 2
+			for i < n && !(runes[i] == '*' && i+1 < n && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case r == '\'':
+			start := i
+			i++
+			for i < n {
+				if runes[i] == '\\' {
+					i += 2
+					continue
+				}
+				if runes[i] == '\'' {
+					if i+1 < n && runes[i+1] == '\'' {
+						i += 2
+						continue
+					}
+					break
+				}
+				i++
+			}
+			i = minInt(i+1, n)
+			tokens = append(tokens, token{tp: tokenLiteral, text: string(runes[start:i])})
+		case r == '$' && i+1 < n && runes[i+1] == '$':
+			start := i
+			i += 2
+			for i < n && !(runes[i] == '$' && i+1 < n && runes[i+1] == '$') {
+				i++
+			}
+			i = minInt(i+2, n)
+			tokens = append(tokens, token{tp: tokenLiteral, text: string(runes[start:i])})
+		case r == '"':
+			var b strings.Builder
+			i++
+			for i < n {
+				if runes[i] == '"' {
+					if i+1 < n && runes[i+1] == '"' {
+						b.WriteRune('"')
+						i += 2
+						continue
+					}
+					break
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			i++
+			tokens = append(tokens, token{tp: tokenQuotedIdentifier, text: b.String()})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < n && (runes[i] == '_' || runes[i] == '$' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tp: tokenIdentifier, text: string(runes[start:i])})
+		case unicode.IsDigit(r):
+			start := i
+			for i < n && (runes[i] == '.' || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tp: tokenLiteral, text: string(runes[start:i])})
+		default:
+			tokens = append(tokens, token{tp: tokenPunctuation, text: string(r)})
+			i++
+		}
+	}
+	return tokens
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// normalize returns the identifier as Snowflake stores it, the unquoted identifiers are stored in uppercase.
+func (t token) normalize() string {
+	if t.tp == tokenQuotedIdentifier {
+		return t.text
+	}
+	return strings.ToUpper(t.text)
+}
+
+func (t token) isIdentifier() bool {
+	return t.tp == tokenIdentifier || t.tp == tokenQuotedIdentifier
+}
+
+// keywordAt returns true if the i-th token is the keyword.
+func (s *singleStatement) keywordAt(i int, keyword string) bool {
+	return i < len(s.tokens) && s.tokens[i].tp == tokenIdentifier && strings.EqualFold(s.tokens[i].text, keyword)
+}
+
+// punctuationAt returns true if the i-th token is the punctuation.
+func (s *singleStatement) punctuationAt(i int, punctuation string) bool {
+	return i < len(s.tokens) && s.tokens[i].tp == tokenPunctuation && s.tokens[i].text == punctuation
+}
+
+// matchKeywords matches the keyword sequence from the i-th token, and returns the index after the sequence.
+func (s *singleStatement) matchKeywords(i int, keywords ...string) (int, bool) {
+	for _, keyword := range keywords {
+		if !s.keywordAt(i, keyword) {
+			return i, false
+		}
+		i++
+	}
+	return i, true
+}
+
+// skipKeywords skips any of the keywords from the i-th token, and returns the index after them.
+func (s *singleStatement) skipKeywords(i int, keywords ...string) int {
+	for {
+		matched := false
+		for _, keyword := range keywords {
+			if s.keywordAt(i, keyword) {
+				matched = true
+				i++
+				break
+			}
+		}
+		if !matched {
+			return i
+		}
+	}
+}
+
+// containsKeywords returns true if the keyword sequence appears from the i-th token.
+func (s *singleStatement) containsKeywords(i int, keywords ...string) bool {
+	for ; i < len(s.tokens); i++ {
+		if _, ok := s.matchKeywords(i, keywords...); ok {
+			return true
+		}
+	}
+	return false
+}
+
+type objectName struct {
+	database string
+	schema   string
+	name     string
+	// originName is the object name as written in the statement without the quotes.
+	originName string
+}
+
+// parseObjectName parses the [database.][schema.]name from the i-th token, and returns the index after the name.
+func (s *singleStatement) parseObjectName(i int) (*objectName, int) {
+	// The IDENTIFIER('name') can only be resolved at runtime.
+	if s.keywordAt(i, "IDENTIFIER") && s.punctuationAt(i+1, "(") {
+		return nil, i
+	}
+	var parts []token
+	for {
+		if i >= len(s.tokens) || !s.tokens[i].isIdentifier() {
+			return nil, i
+		}
+		parts = append(parts, s.tokens[i])
+		i++
+		if len(parts) == 3 || !s.punctuationAt(i, ".") {
+			break
+		}
+		i++
+		// The database..name refers to the PUBLIC schema.
+		if len(parts) == 1 && s.punctuationAt(i, ".") {
+			parts = append(parts, token{tp: tokenQuotedIdentifier, text: publicSchemaName})
+			i++
+		}
+	}
+
+	last := parts[len(parts)-1]
+	name := &objectName{
+		name:       last.normalize(),
+		originName: last.text,
+	}
+	switch len(parts) {
+	case 2:
+		name.schema = parts[0].normalize()
+	case 3:
+		name.database = parts[0].normalize()
+		name.schema = parts[1].normalize()
+	}
+	return name, i
+}
@@ -0,0 +1,21 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+func TestSnowflakeRules(t *testing.T) {
+	snowflakeRules := []advisor.SQLReviewRuleType{
+		advisor.SchemaRuleStageNaming,
+		advisor.SchemaRulePipeNaming,
+		advisor.SchemaRuleSchemaDisallowPublicObject,
+		advisor.SchemaRuleTableRequireClusteringKey,
+	}
+
+	for _, rule := range snowflakeRules {
+		advisor.RunSQLReviewRuleTest(t, rule, db.Snowflake, false /* record */)
+	}
+}
@@ -0,0 +1,198 @@
+// Package snowflake implements the SQL advisor rules for Snowflake.
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+)
+
+const (
+	// publicSchemaName is the default schema of the Snowflake databases.
+	publicSchemaName = "PUBLIC"
+)
+
+// createObjectTypeList is the schema object types recognized in the CREATE statements.
+var createObjectTypeList = [][]string{
+	{"TABLE"},
+	{"VIEW"},
+	{"STAGE"},
+	{"PIPE"},
+	{"SEQUENCE"},
+	{"STREAM"},
+	{"TASK"},
+	{"FUNCTION"},
+	{"PROCEDURE"},
+	{"FILE", "FORMAT"},
+	{"TAG"},
+	{"ALERT"},
+	{"MASKING", "POLICY"},
+	{"ROW", "ACCESS", "POLICY"},
+}
+
+type createObject struct {
+	// objectType is the object type keywords joined by space, e.g. TABLE, FILE FORMAT.
+	objectType string
+	name       *objectName
+}
+
+// parseCreateObject returns the created schema object, or nil if the statement doesn't create a schema object.
+func (s *singleStatement) parseCreateObject() *createObject {
+	i, ok := s.matchKeywords(0, "CREATE")
+	if !ok {
+		return nil
+	}
+	i = s.skipKeywords(i, "OR", "REPLACE", "SECURE", "LOCAL", "GLOBAL", "TEMP", "TEMPORARY", "VOLATILE", "TRANSIENT",
+		"RECURSIVE", "MATERIALIZED", "EXTERNAL", "DYNAMIC", "EVENT", "HYBRID", "ICEBERG")
+	for _, objectType := range createObjectTypeList {
+		next, ok := s.matchKeywords(i, objectType...)
+		if !ok {
+			continue
+		}
+		next, _ = s.matchKeywords(next, "IF", "NOT", "EXISTS")
+		name, _ := s.parseObjectName(next)
+		if name == nil {
+			return nil
+		}
+		return &createObject{
+			objectType: strings.Join(objectType, " "),
+			name:       name,
+		}
+	}
+	return nil
+}
+
+// parseRenameObject returns the new name in ALTER <objectType> [IF EXISTS] <name> RENAME TO <new_name>, or nil for other statements.
+func (s *singleStatement) parseRenameObject(objectType string) *objectName {
+	i, ok := s.matchKeywords(0, "ALTER", objectType)
+	if !ok {
+		return nil
+	}
+	i, _ = s.matchKeywords(i, "IF", "EXISTS")
+	name, i := s.parseObjectName(i)
+	if name == nil {
+		return nil
+	}
+	i, ok = s.matchKeywords(i, "RENAME", "TO")
+	if !ok {
+		return nil
+	}
+	newName, _ := s.parseObjectName(i)
+	return newName
+}
+
+// session is the current database and schema changed by the USE statements.
+type session struct {
+	// database is empty if it's the database connected.
+	database string
+	schema   string
+}
+
+func newSession() *session {
+	return &session{
+		schema: publicSchemaName,
+	}
+}
+
+// use updates the session by the USE statement, and does nothing for other statements.
+func (s *session) use(stmt *singleStatement) {
+	i, ok := stmt.matchKeywords(0, "USE")
+	if !ok {
+		return
+	}
+	if next, ok := stmt.matchKeywords(i, "SCHEMA"); ok {
+		// The schema [database.]name is parsed as the [schema.]name object name.
+		if name, _ := stmt.parseObjectName(next); name != nil {
+			if name.schema != "" {
+				s.database = name.schema
+			}
+			s.schema = name.name
+		}
+		return
+	}
+	if stmt.keywordAt(i, "ROLE") || stmt.keywordAt(i, "WAREHOUSE") || stmt.keywordAt(i, "SECONDARY") {
+		return
+	}
+	// USE [DATABASE] name switches the schema to PUBLIC, while USE database.schema switches both.
+	i, _ = stmt.matchKeywords(i, "DATABASE")
+	name, _ := stmt.parseObjectName(i)
+	if name == nil {
+		return
+	}
+	if name.schema != "" {
+		s.database = name.schema
+		s.schema = name.name
+		return
+	}
+	s.database = name.name
+	s.schema = publicSchemaName
+}
+
+// resolve returns the database and schema of the object name in the session.
+func (s *session) resolve(name *objectName) (string, string) {
+	if name.schema == "" {
+		return s.database, s.schema
+	}
+	if name.database == "" {
+		return s.database, name.schema
+	}
+	return name.database, name.schema
+}
+
+// checkObjectNaming checks the naming convention for the created and renamed objects of the object type.
+func checkObjectNaming(ctx advisor.Context, statement string, objectType string, code advisor.Code) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	format, maxLength, err := advisor.UnamrshalNamingRulePayloadAsRegexp(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		name := stmt.parseRenameObject(objectType)
+		if object := stmt.parseCreateObject(); object != nil && object.objectType == objectType {
+			name = object.name
+		}
+		if name == nil {
+			continue
+		}
+		// The unquoted identifiers are stored in uppercase, so we check the name as written in the statement.
+		if !format.MatchString(name.originName) {
+			adviceList = append(adviceList, advisor.Advice{
+				Status:  level,
+				Code:    code,
+				Title:   string(ctx.Rule.Type),
+				Content: fmt.Sprintf(`"%s" mismatches %s naming convention, naming format should be %q`, name.originName, strings.ToLower(objectType), format),
+				Line:    stmt.line,
+			})
+		}
+		if maxLength > 0 && len(name.originName) > maxLength {
+			adviceList = append(adviceList, advisor.Advice{
+				Status:  level,
+				Code:    code,
+				Title:   string(ctx.Rule.Type),
+				Content: fmt.Sprintf("\"%s\" mismatches %s naming convention, its length should be within %d characters", name.originName, strings.ToLower(objectType), maxLength),
+				Line:    stmt.line,
+			})
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
@@ -7,7 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -18,7 +21,7 @@ import (
 )
 
 // How to add a SQL review rule:
-//   1. Implement an advisor.(plugin/advisor/mysql or plugin/advisor/pg)
+//   1. Implement an advisor.(plugin/advisor/mysql, plugin/advisor/pg or plugin/advisor/snowflake)
 //   2. Register this advisor in map[db.Type][AdvisorType].(plugin/advisor.go)
 //   3. Add advisor error code if needed(plugin/advisor/code.go).
 //   4. Map SQLReviewRuleType to advisor.Type in getAdvisorTypeByRule(current file).
@@ -54,6 +57,10 @@ const (
 	SchemaRuleIDXNaming SQLReviewRuleType = "naming.index.idx"
 	// SchemaRuleAutoIncrementColumnNaming enforce the auto_increment column name format.
 	SchemaRuleAutoIncrementColumnNaming SQLReviewRuleType = "naming.column.auto-increment"
+	// SchemaRuleStageNaming enforce the stage name format.
+	SchemaRuleStageNaming SQLReviewRuleType = "naming.stage"
+	// SchemaRulePipeNaming enforce the pipe name format.
+	SchemaRulePipeNaming SQLReviewRuleType = "naming.pipe"
 
 	// SchemaRuleStatementNoSelectAll disallow 'SELECT *'.
 	SchemaRuleStatementNoSelectAll SQLReviewRuleType = "statement.select.no-select-all"
@@ -63,6 +70,8 @@ const (
 	SchemaRuleStatementNoLeadingWildcardLike SQLReviewRuleType = "statement.where.no-leading-wildcard-like"
 	// SchemaRuleStatementDisallowCommit disallow using commit in the issue.
 	SchemaRuleStatementDisallowCommit SQLReviewRuleType = "statement.disallow-commit"
+	// SchemaRuleStatementDisallowHardCodedSecret disallow the hard-coded passwords, API keys and connection strings with credentials.
+	SchemaRuleStatementDisallowHardCodedSecret SQLReviewRuleType = "statement.disallow-hard-coded-secret"
 	// SchemaRuleStatementDisallowLimit disallow the LIMIT clause in INSERT, DELETE and UPDATE statements.
 	SchemaRuleStatementDisallowLimit SQLReviewRuleType = "statement.disallow-limit"
 	// SchemaRuleStatementDisallowOrderBy disallow the ORDER BY clause in DELETE and UPDATE statements.
@@ -85,6 +94,16 @@ const (
 	SchemaRuleStatementAddCheckNotValid = "statement.add-check-not-valid"
 	// SchemaRuleStatementDisallowAddNotNull disallow to add NOT NULL.
 	SchemaRuleStatementDisallowAddNotNull = "statement.disallow-add-not-null"
+	// SchemaRuleStatementRedisCommandDisallowList enforce the Redis command disallow list.
+	SchemaRuleStatementRedisCommandDisallowList SQLReviewRuleType = "statement.redis.command-disallow-list"
+	// SchemaRuleStatementMongoDBRequireFilter require the filter document for MongoDB updateMany and deleteMany.
+	SchemaRuleStatementMongoDBRequireFilter SQLReviewRuleType = "statement.mongodb.require-filter"
+	// SchemaRuleStatementElasticsearchRequireFilter require the query for Elasticsearch _delete_by_query and _update_by_query.
+	SchemaRuleStatementElasticsearchRequireFilter SQLReviewRuleType = "statement.elasticsearch.require-filter"
+	// SchemaRuleStatementCassandraDisallowAllowFiltering disallow ALLOW FILTERING in Cassandra queries.
+	SchemaRuleStatementCassandraDisallowAllowFiltering SQLReviewRuleType = "statement.cassandra.disallow-allow-filtering"
+	// SchemaRuleStatementDynamoDBDisallowFullTableScan disallow the DynamoDB PartiQL SELECT without WHERE.
+	SchemaRuleStatementDynamoDBDisallowFullTableScan SQLReviewRuleType = "statement.dynamodb.disallow-full-table-scan"
 
 	// SchemaRuleTableRequirePK require the table to have a primary key.
 	SchemaRuleTableRequirePK SQLReviewRuleType = "table.require-pk"
@@ -96,6 +115,16 @@ const (
 	SchemaRuleTableCommentConvention SQLReviewRuleType = "table.comment"
 	// SchemaRuleTableDisallowPartition disallow the table partition.
 	SchemaRuleTableDisallowPartition SQLReviewRuleType = "table.disallow-partition"
+	// SchemaRuleTableRequireClusteringKey require the large tables to have a clustering key.
+	SchemaRuleTableRequireClusteringKey SQLReviewRuleType = "table.require-clustering-key"
+	// SchemaRuleTableRequirePartition require the high-volume tables to be partitioned with the approved strategies.
+	SchemaRuleTableRequirePartition SQLReviewRuleType = "table.require-partition"
+	// SchemaRuleTableRequireHypertable require the time-series tables to be TimescaleDB hypertables.
+	SchemaRuleTableRequireHypertable SQLReviewRuleType = "table.require-hypertable"
+	// SchemaRuleTableRequireRowLevelSecurity require the tables labeled in the comments, e.g. multi-tenant, to enable the row level security with policies.
+	SchemaRuleTableRequireRowLevelSecurity SQLReviewRuleType = "table.require-row-level-security"
+	// SchemaRuleTableMongoDBDisallowDropCollection disallow dropping MongoDB collections.
+	SchemaRuleTableMongoDBDisallowDropCollection SQLReviewRuleType = "table.mongodb.disallow-drop-collection"
 
 	// SchemaRuleRequiredColumn enforce the required columns in each table.
 	SchemaRuleRequiredColumn SQLReviewRuleType = "column.required"
@@ -130,6 +159,10 @@ const (
 
 	// SchemaRuleSchemaBackwardCompatibility enforce the MySQL and TiDB support check whether the schema change is backward compatible.
 	SchemaRuleSchemaBackwardCompatibility SQLReviewRuleType = "schema.backward-compatibility"
+	// SchemaRuleSchemaDisallowPublicObject disallow creating objects in the PUBLIC schema.
+	SchemaRuleSchemaDisallowPublicObject SQLReviewRuleType = "schema.disallow-public-object"
+	// SchemaRuleSchemaRequireIdempotentMigration require the CREATE and DROP statements to be re-runnable, e.g. IF [NOT] EXISTS.
+	SchemaRuleSchemaRequireIdempotentMigration SQLReviewRuleType = "schema.require-idempotent-migration"
 
 	// SchemaRuleDropEmptyDatabase enforce the MySQL and TiDB support check if the database is empty before users drop it.
 	SchemaRuleDropEmptyDatabase SQLReviewRuleType = "database.drop-empty-database"
@@ -148,6 +181,12 @@ const (
 	SchemaRuleIndexPrimaryKeyTypeAllowlist SQLReviewRuleType = "index.primary-key-type-allowlist"
 	// SchemaRuleCreateIndexConcurrently require creating indexes concurrently.
 	SchemaRuleCreateIndexConcurrently SQLReviewRuleType = "index.create-concurrently"
+	// SchemaRuleIndexNoRedundant disallow the duplicate and left-prefix redundant indexes.
+	SchemaRuleIndexNoRedundant SQLReviewRuleType = "index.no-redundant"
+	// SchemaRuleIndexDropRequireInvisible require the indexes to be invisible for a period before dropping them.
+	SchemaRuleIndexDropRequireInvisible SQLReviewRuleType = "index.drop-require-invisible"
+	// SchemaRuleIndexMongoDBRequireBackground require creating MongoDB indexes in the background.
+	SchemaRuleIndexMongoDBRequireBackground SQLReviewRuleType = "index.mongodb.require-background"
 
 	// SchemaRuleCharsetAllowlist enforce the charset allowlist.
 	SchemaRuleCharsetAllowlist SQLReviewRuleType = "system.charset.allowlist"
@@ -206,6 +245,8 @@ var (
 type SQLReviewPolicy struct {
 	Name     string           `json:"name"`
 	RuleList []*SQLReviewRule `json:"ruleList"`
+	// Version is the version of the rule set, it should be increased when rolling out new rules.
+	Version int `json:"version,omitempty"`
 }
 
 // Validate validates the SQLReviewPolicy. It also validates the each review rule.
@@ -221,6 +262,41 @@ func (policy *SQLReviewPolicy) Validate() error {
 	return nil
 }
 
+// Override returns the SQL review policy with the project level overrides.
+// The rules introduced after the pinned version are skipped, and zero pinned version means the latest version.
+// The override rules change the level and payload of the rules with the same type, and the same engine if the engine is specified.
+func (policy *SQLReviewPolicy) Override(pinnedVersion int, overrideList []*SQLReviewRule) *SQLReviewPolicy {
+	result := &SQLReviewPolicy{
+		Name:    policy.Name,
+		Version: policy.Version,
+	}
+	if pinnedVersion > 0 && pinnedVersion < policy.Version {
+		result.Version = pinnedVersion
+	}
+	for _, rule := range policy.RuleList {
+		if pinnedVersion > 0 && rule.Version > pinnedVersion {
+			continue
+		}
+		newRule := *rule
+		for _, override := range overrideList {
+			if override.Type != rule.Type || (override.Engine != "" && override.Engine != rule.Engine) {
+				continue
+			}
+			if override.Level != "" {
+				newRule.Level = override.Level
+			}
+			if override.Payload != "" {
+				newRule.Payload = override.Payload
+			}
+			if override.Comment != "" {
+				newRule.Comment = override.Comment
+			}
+		}
+		result.RuleList = append(result.RuleList, &newRule)
+	}
+	return result
+}
+
 // String returns the marshal string value for SQL review policy.
 func (policy *SQLReviewPolicy) String() (string, error) {
 	s, err := json.Marshal(policy)
@@ -239,13 +315,15 @@ type SQLReviewRule struct {
 	// Payload is the stringify value for XXXRulePayload (e.g. NamingRulePayload, StringArrayTypeRulePayload)
 	// If the rule doesn't have any payload configuration, the payload would be "{}"
 	Payload string `json:"payload"`
+	// Version is the version of the rule set in which the rule is rolled out, zero means the initial version.
+	Version int `json:"version,omitempty"`
 }
 
 // Validate validates the SQL review rule.
 func (rule *SQLReviewRule) Validate() error {
 	// TODO(rebelice): add other SQL review rule validation.
 	switch rule.Type {
-	case SchemaRuleTableNaming, SchemaRuleColumnNaming, SchemaRuleAutoIncrementColumnNaming:
+	case SchemaRuleTableNaming, SchemaRuleColumnNaming, SchemaRuleAutoIncrementColumnNaming, SchemaRuleStageNaming, SchemaRulePipeNaming:
 		if _, _, err := UnamrshalNamingRulePayloadAsRegexp(rule.Payload); err != nil {
 			return err
 		}
@@ -262,11 +340,25 @@ func (rule *SQLReviewRule) Validate() error {
 			return err
 		}
 	case SchemaRuleIndexKeyNumberLimit, SchemaRuleStatementInsertRowLimit, SchemaRuleIndexTotalNumberLimit,
-		SchemaRuleColumnMaximumCharacterLength, SchemaRuleColumnAutoIncrementInitialValue, SchemaRuleStatementAffectedRowLimit:
+		SchemaRuleColumnMaximumCharacterLength, SchemaRuleColumnAutoIncrementInitialValue, SchemaRuleStatementAffectedRowLimit,
+		SchemaRuleIndexDropRequireInvisible, SchemaRuleTableRequireClusteringKey:
 		if _, err := UnmarshalNumberTypeRulePayload(rule.Payload); err != nil {
 			return err
 		}
-	case SchemaRuleColumnTypeDisallowList, SchemaRuleCharsetAllowlist, SchemaRuleCollationAllowlist, SchemaRuleIndexPrimaryKeyTypeAllowlist:
+	case SchemaRuleColumnTypeDisallowList, SchemaRuleCharsetAllowlist, SchemaRuleCollationAllowlist, SchemaRuleIndexPrimaryKeyTypeAllowlist,
+		SchemaRuleStatementRedisCommandDisallowList:
+		if _, err := UnmarshalStringArrayTypeRulePayload(rule.Payload); err != nil {
+			return err
+		}
+	case SchemaRuleTableRequirePartition:
+		if _, _, err := UnmarshalRequirePartitionRulePayload(rule.Payload); err != nil {
+			return err
+		}
+	case SchemaRuleTableRequireHypertable:
+		if _, _, err := UnamrshalNamingRulePayloadAsRegexp(rule.Payload); err != nil {
+			return err
+		}
+	case SchemaRuleTableRequireRowLevelSecurity:
 		if _, err := UnmarshalStringArrayTypeRulePayload(rule.Payload); err != nil {
 			return err
 		}
@@ -301,6 +393,14 @@ type NumberTypeRulePayload struct {
 	Number int `json:"number"`
 }
 
+// RequirePartitionRulePayload is the payload for the require partition rule.
+type RequirePartitionRulePayload struct {
+	// Format is the regular expression matching the names of the high-volume tables.
+	Format string `json:"format"`
+	// List is the approved partition strategies, e.g. RANGE, HASH.
+	List []string `json:"list"`
+}
+
 // UnamrshalNamingRulePayloadAsRegexp will unmarshal payload to NamingRulePayload and compile it as regular expression.
 func UnamrshalNamingRulePayloadAsRegexp(payload string) (*regexp.Regexp, int, error) {
 	var nr NamingRulePayload
@@ -420,6 +520,27 @@ func UnmarshalNumberTypeRulePayload(payload string) (*NumberTypeRulePayload, err
 	return &nlr, nil
 }
 
+// UnmarshalRequirePartitionRulePayload will unmarshal payload to RequirePartitionRulePayload,
+// and return the compiled table name format and the upper-cased approved partition strategies.
+func UnmarshalRequirePartitionRulePayload(payload string) (*regexp.Regexp, []string, error) {
+	var rpr RequirePartitionRulePayload
+	if err := json.Unmarshal([]byte(payload), &rpr); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to unmarshal require partition rule payload %q", payload)
+	}
+	if rpr.Format == "" {
+		return nil, nil, errors.Errorf("invalid require partition rule payload, format cannot be empty")
+	}
+	format, err := regexp.Compile(rpr.Format)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to compile regular expression \"%s\"", rpr.Format)
+	}
+	var strategyList []string
+	for _, strategy := range rpr.List {
+		strategyList = append(strategyList, strings.ToUpper(strategy))
+	}
+	return format, strategyList, nil
+}
+
 // UnmarshalStringArrayTypeRulePayload will unmarshal payload to StringArrayTypeRulePayload.
 func UnmarshalStringArrayTypeRulePayload(payload string) (*StringArrayTypeRulePayload, error) {
 	var trr StringArrayTypeRulePayload
@@ -437,6 +558,20 @@ type SQLReviewCheckContext struct {
 	Catalog   catalog.Catalog
 	Driver    *sql.DB
 	Context   context.Context
+
+	// MaxConcurrency is the max number of rules checked at the same time.
+	// Use the number of CPUs if it's not positive.
+	MaxConcurrency int
+
+	// UnusedIndexList is the indexes observed unused from the engine statistics.
+	// It's used to attach the index usage context to the dropped indexes.
+	UnusedIndexList []*UnusedIndex
+	// ChangeHistoryList is the applied changes of the database, ordered from the latest to the earliest.
+	// It's only required by the rules checking the previous changes.
+	ChangeHistoryList []*ChangeHistory
+	// TemplateStatement is the statement before rendering the database secrets.
+	// It's used by the hard-coded secret rule so that the rendered secrets aren't reported.
+	TemplateStatement string
 }
 
 // SQLReviewCheck checks the statements with sql review rules.
@@ -451,6 +586,11 @@ func SQLReviewCheck(statements string, ruleList []*SQLReviewRule, checkContext S
 		}
 	}
 
+	type ruleTask struct {
+		rule        *SQLReviewRule
+		advisorType Type
+	}
+	var taskList []ruleTask
 	for _, rule := range ruleList {
 		if rule.Engine != "" && rule.Engine != checkContext.DbType {
 			continue
@@ -466,31 +606,73 @@ func SQLReviewCheck(statements string, ruleList []*SQLReviewRule, checkContext S
 			}
 			continue
 		}
+		taskList = append(taskList, ruleTask{rule: rule, advisorType: advisorType})
+	}
 
-		adviceList, err := Check(
-			checkContext.DbType,
-			advisorType,
-			Context{
-				Charset:   checkContext.Charset,
-				Collation: checkContext.Collation,
-				Rule:      rule,
-				Catalog:   finder,
-				Driver:    checkContext.Driver,
-				Context:   checkContext.Context,
-			},
-			statements,
-		)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to check statement")
-		}
+	// The rules are independent of each other and only read the catalog after the walk-through,
+	// so we run them concurrently and keep the advice order the same as the rule order.
+	concurrency := checkContext.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	adviceLists := make([][]Advice, len(taskList))
+	errs := make([]error, len(taskList))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, task := range taskList {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, task ruleTask) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			startTime := time.Now()
+			adviceLists[i], errs[i] = Check(
+				checkContext.DbType,
+				task.advisorType,
+				Context{
+					Charset:   checkContext.Charset,
+					Collation: checkContext.Collation,
+					Rule:      task.rule,
+					Catalog:   finder,
+					Driver:    checkContext.Driver,
+					Context:   checkContext.Context,
+
+					ChangeHistoryList: checkContext.ChangeHistoryList,
+					TemplateStatement: checkContext.TemplateStatement,
+				},
+				statements,
+			)
+			observeRuleLatency(checkContext.DbType, task.rule.Type, time.Since(startTime))
+		}(i, task)
+	}
+	wg.Wait()
 
-		result = append(result, adviceList...)
+	for i := range taskList {
+		if errs[i] != nil {
+			return nil, errors.Wrap(errs[i], "failed to check statement")
+		}
+		result = append(result, adviceLists[i]...)
 	}
 
 	// There may be multiple syntax errors, return one only.
 	if len(result) > 0 && result[0].Title == SyntaxErrorTitle {
 		return result[:1], nil
 	}
+	if advisorType, ok := indexUsageAdvisorType[checkContext.DbType]; ok && len(checkContext.UnusedIndexList) > 0 {
+		adviceList, err := Check(checkContext.DbType, advisorType, Context{
+			Charset:         checkContext.Charset,
+			Collation:       checkContext.Collation,
+			Catalog:         finder,
+			Context:         checkContext.Context,
+			UnusedIndexList: checkContext.UnusedIndexList,
+		}, statements)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to check index usage")
+		}
+		result = append(result, adviceList...)
+	}
 	if len(result) == 0 {
 		result = append(result, Advice{
 			Status:  Success,
@@ -983,6 +1165,13 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		case db.Postgres:
 			return PostgreSQLStatementDisallowCommit, nil
 		}
+	case SchemaRuleStatementDisallowHardCodedSecret:
+		switch engine {
+		case db.MySQL, db.TiDB, db.MariaDB:
+			return MySQLStatementDisallowHardCodedSecret, nil
+		case db.Postgres:
+			return PostgreSQLStatementDisallowHardCodedSecret, nil
+		}
 	case SchemaRuleCharsetAllowlist:
 		switch engine {
 		case db.MySQL, db.TiDB, db.MariaDB:
@@ -1018,6 +1207,86 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		if engine == db.Postgres {
 			return PostgreSQLCreateIndexConcurrently, nil
 		}
+	case SchemaRuleIndexNoRedundant:
+		switch engine {
+		case db.MySQL, db.TiDB, db.MariaDB:
+			return MySQLIndexNoRedundant, nil
+		case db.Postgres:
+			return PostgreSQLIndexNoRedundant, nil
+		}
+	case SchemaRuleTableMongoDBDisallowDropCollection:
+		if engine == db.MongoDB {
+			return MongoDBCollectionDisallowDrop, nil
+		}
+	case SchemaRuleIndexMongoDBRequireBackground:
+		if engine == db.MongoDB {
+			return MongoDBIndexRequireBackground, nil
+		}
+	case SchemaRuleStatementMongoDBRequireFilter:
+		if engine == db.MongoDB {
+			return MongoDBStatementRequireFilter, nil
+		}
+	case SchemaRuleStatementRedisCommandDisallowList:
+		if engine == db.Redis {
+			return RedisCommandDisallowList, nil
+		}
+	case SchemaRuleStatementElasticsearchRequireFilter:
+		if engine == db.Elasticsearch {
+			return ElasticsearchStatementRequireFilter, nil
+		}
+	case SchemaRuleStatementCassandraDisallowAllowFiltering:
+		if engine == db.Cassandra {
+			return CassandraStatementDisallowAllowFiltering, nil
+		}
+	case SchemaRuleStatementDynamoDBDisallowFullTableScan:
+		if engine == db.DynamoDB {
+			return DynamoDBStatementDisallowFullTableScan, nil
+		}
+	case SchemaRuleStageNaming:
+		if engine == db.Snowflake {
+			return SnowflakeNamingStage, nil
+		}
+	case SchemaRulePipeNaming:
+		if engine == db.Snowflake {
+			return SnowflakeNamingPipe, nil
+		}
+	case SchemaRuleTableRequireClusteringKey:
+		if engine == db.Snowflake {
+			return SnowflakeTableRequireClusteringKey, nil
+		}
+	case SchemaRuleSchemaDisallowPublicObject:
+		if engine == db.Snowflake {
+			return SnowflakeSchemaDisallowPublicObject, nil
+		}
+	case SchemaRuleTableRequirePartition:
+		switch engine {
+		case db.MySQL, db.TiDB, db.MariaDB:
+			return MySQLTableRequirePartition, nil
+		case db.Postgres:
+			return PostgreSQLTableRequirePartition, nil
+		}
+	case SchemaRuleTableRequireHypertable:
+		if engine == db.Postgres {
+			return PostgreSQLTableRequireHypertable, nil
+		}
+	case SchemaRuleTableRequireRowLevelSecurity:
+		if engine == db.Postgres {
+			return PostgreSQLTableRequireRowLevelSecurity, nil
+		}
+	case SchemaRuleSchemaRequireIdempotentMigration:
+		switch engine {
+		case db.MySQL, db.TiDB, db.MariaDB:
+			return MySQLRequireIdempotentMigration, nil
+		case db.Postgres:
+			return PostgreSQLRequireIdempotentMigration, nil
+		case db.Neo4j:
+			return Neo4jRequireIdempotentMigration, nil
+		}
+	case SchemaRuleIndexDropRequireInvisible:
+		// TiDB and MariaDB don't support making indexes invisible in the same way as MySQL 8.0.
+		if engine == db.MySQL {
+			return MySQLIndexDropRequireInvisible, nil
+		}
 	case SchemaRuleStatementInsertRowLimit:
 		switch engine {
 		case db.MySQL, db.TiDB, db.MariaDB:
@@ -0,0 +1,134 @@
+package advisor
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/backend/plugin/advisor/db"
+)
+
+func TestSQLReviewPolicyOverride(t *testing.T) {
+	policy := &SQLReviewPolicy{
+		Name:    "prod",
+		Version: 2,
+		RuleList: []*SQLReviewRule{
+			{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelError, Engine: db.MySQL, Payload: "{}"},
+			{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelError, Engine: db.Postgres, Payload: "{}"},
+			{Type: SchemaRuleStatementInsertRowLimit, Level: SchemaRuleLevelWarning, Engine: db.MySQL, Payload: `{"number":1000}`, Version: 1},
+			{Type: SchemaRuleTableRequirePartition, Level: SchemaRuleLevelError, Engine: db.MySQL, Payload: `{"format":"_log$"}`, Version: 2},
+		},
+	}
+
+	tests := []struct {
+		pinnedVersion int
+		overrideList  []*SQLReviewRule
+		want          *SQLReviewPolicy
+	}{
+		{
+			pinnedVersion: 0,
+			overrideList:  nil,
+			want:          policy,
+		},
+		{
+			pinnedVersion: 1,
+			overrideList: []*SQLReviewRule{
+				{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelWarning, Engine: db.Postgres},
+				{Type: SchemaRuleStatementInsertRowLimit, Payload: `{"number":100}`},
+			},
+			want: &SQLReviewPolicy{
+				Name:    "prod",
+				Version: 1,
+				RuleList: []*SQLReviewRule{
+					{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelError, Engine: db.MySQL, Payload: "{}"},
+					{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelWarning, Engine: db.Postgres, Payload: "{}"},
+					{Type: SchemaRuleStatementInsertRowLimit, Level: SchemaRuleLevelWarning, Engine: db.MySQL, Payload: `{"number":100}`, Version: 1},
+				},
+			},
+		},
+		{
+			pinnedVersion: 3,
+			overrideList: []*SQLReviewRule{
+				{Type: SchemaRuleTableRequirePartition, Level: SchemaRuleLevelDisabled},
+			},
+			want: &SQLReviewPolicy{
+				Name:    "prod",
+				Version: 2,
+				RuleList: []*SQLReviewRule{
+					{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelError, Engine: db.MySQL, Payload: "{}"},
+					{Type: SchemaRuleTableRequirePK, Level: SchemaRuleLevelError, Engine: db.Postgres, Payload: "{}"},
+					{Type: SchemaRuleStatementInsertRowLimit, Level: SchemaRuleLevelWarning, Engine: db.MySQL, Payload: `{"number":1000}`, Version: 1},
+					{Type: SchemaRuleTableRequirePartition, Level: SchemaRuleLevelDisabled, Engine: db.MySQL, Payload: `{"format":"_log$"}`, Version: 2},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := policy.Override(test.pinnedVersion, test.overrideList)
+		require.Equal(t, test.want, got)
+	}
+}
+
+// registerSleepAdvisors registers the sleep advisors for the Snowflake rules, whose advisors aren't linked in the test.
+var registerSleepAdvisors sync.Once
+
+// sleepAdvisor returns the advice titled with its rule after the delay, so the later rules finish first.
+type sleepAdvisor struct {
+	delay time.Duration
+}
+
+func (a *sleepAdvisor) Check(ctx Context, _ string) ([]Advice, error) {
+	time.Sleep(a.delay)
+	return []Advice{{Status: Warn, Title: string(ctx.Rule.Type)}}, nil
+}
+
+func TestSQLReviewCheckConcurrency(t *testing.T) {
+	a := require.New(t)
+	registerSleepAdvisors.Do(func() {
+		Register(db.Snowflake, SnowflakeNamingStage, &sleepAdvisor{delay: 30 * time.Millisecond})
+		Register(db.Snowflake, SnowflakeNamingPipe, &sleepAdvisor{delay: 20 * time.Millisecond})
+		Register(db.Snowflake, SnowflakeTableRequireClusteringKey, &sleepAdvisor{delay: 10 * time.Millisecond})
+		Register(db.Snowflake, SnowflakeSchemaDisallowPublicObject, &sleepAdvisor{})
+	})
+	ruleList := []*SQLReviewRule{
+		{Type: SchemaRuleStageNaming, Level: SchemaRuleLevelWarning},
+		{Type: SchemaRulePipeNaming, Level: SchemaRuleLevelWarning},
+		{Type: SchemaRuleTableRequireClusteringKey, Level: SchemaRuleLevelWarning},
+		{Type: SchemaRuleSchemaDisallowPublicObject, Level: SchemaRuleLevelWarning},
+	}
+	pipeLatency, ok := ruleLatency.WithLabelValues(string(db.Snowflake), string(SchemaRulePipeNaming)).(prometheus.Histogram)
+	a.True(ok)
+	countBefore := histogramSampleCount(t, pipeLatency)
+
+	check := func(maxConcurrency int) []Advice {
+		adviceList, err := SQLReviewCheck("CREATE STAGE s;", ruleList, SQLReviewCheckContext{
+			DbType:         db.Snowflake,
+			Catalog:        &testCatalog{finder: catalog.NewEmptyFinder(&catalog.FinderContext{CheckIntegrity: true})},
+			MaxConcurrency: maxConcurrency,
+		})
+		a.NoError(err)
+		return adviceList
+	}
+	sequential := check(1)
+	a.Len(sequential, len(ruleList))
+	for i, rule := range ruleList {
+		a.Equal(string(rule.Type), sequential[i].Title)
+	}
+	// The advice order is the rule order no matter which rule finishes first.
+	a.Equal(sequential, check(len(ruleList)))
+	a.Equal(sequential, check(2))
+
+	a.Equal(countBefore+3, histogramSampleCount(t, pipeLatency))
+}
+
+func histogramSampleCount(t *testing.T, histogram prometheus.Histogram) uint64 {
+	metric := &dto.Metric{}
+	require.NoError(t, histogram.Write(metric))
+	return metric.GetHistogram().GetSampleCount()
+}
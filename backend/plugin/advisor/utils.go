@@ -105,3 +105,31 @@ func Query(ctx context.Context, connection *sql.DB, statement string) ([]any, er
 
 	return []any{columnNames, columnTypeNames, data}, nil
 }
+
+// IndexDefinition is the index definition used by the redundant index check.
+type IndexDefinition struct {
+	Name       string
+	ColumnList []string
+	Unique     bool
+	Primary    bool
+}
+
+// IsRedundantIndex returns true if the index is covered by the other index.
+// An index is covered if its columns are the leftmost prefix of the other index's columns,
+// and it does not enforce a uniqueness that the other index doesn't enforce.
+// For example, index (a) is redundant if index (a, b) exists.
+func IsRedundantIndex(index, other *IndexDefinition) bool {
+	if index.Primary || len(index.ColumnList) == 0 || len(index.ColumnList) > len(other.ColumnList) {
+		return false
+	}
+	for i, column := range index.ColumnList {
+		if !strings.EqualFold(column, other.ColumnList[i]) {
+			return false
+		}
+	}
+	if index.Unique {
+		// A unique index is only covered by another unique index on exactly the same columns.
+		return other.Unique && len(index.ColumnList) == len(other.ColumnList)
+	}
+	return true
+}
@@ -347,6 +347,7 @@ func SetDefaultSQLReviewRulePayload(ruleTp SQLReviewRuleType) (string, error) {
 		SchemaRuleStatementRequireWhere,
 		SchemaRuleStatementNoLeadingWildcardLike,
 		SchemaRuleStatementDisallowCommit,
+		SchemaRuleStatementDisallowHardCodedSecret,
 		SchemaRuleStatementDisallowLimit,
 		SchemaRuleStatementDisallowOrderBy,
 		SchemaRuleStatementMergeAlterTable,
@@ -370,16 +371,25 @@ func SetDefaultSQLReviewRulePayload(ruleTp SQLReviewRuleType) (string, error) {
 		SchemaRuleDropEmptyDatabase,
 		SchemaRuleIndexNoDuplicateColumn,
 		SchemaRuleIndexPKTypeLimit,
+		SchemaRuleIndexNoRedundant,
 		SchemaRuleStatementDisallowAddColumnWithDefault,
 		SchemaRuleCreateIndexConcurrently,
 		SchemaRuleStatementAddCheckNotValid,
 		SchemaRuleStatementDisallowAddNotNull,
-		SchemaRuleIndexTypeNoBlob:
+		SchemaRuleIndexTypeNoBlob,
+		SchemaRuleSchemaDisallowPublicObject,
+		SchemaRuleSchemaRequireIdempotentMigration,
+		SchemaRuleTableMongoDBDisallowDropCollection,
+		SchemaRuleIndexMongoDBRequireBackground,
+		SchemaRuleStatementMongoDBRequireFilter,
+		SchemaRuleStatementElasticsearchRequireFilter,
+		SchemaRuleStatementCassandraDisallowAllowFiltering,
+		SchemaRuleStatementDynamoDBDisallowFullTableScan:
 	case SchemaRuleTableDropNamingConvention:
 		payload, err = json.Marshal(NamingRulePayload{
 			Format: "_delete$",
 		})
-	case SchemaRuleTableNaming:
+	case SchemaRuleTableNaming, SchemaRuleStageNaming, SchemaRulePipeNaming:
 		fallthrough
 	case SchemaRuleColumnNaming:
 		payload, err = json.Marshal(NamingRulePayload{
@@ -415,6 +425,23 @@ func SetDefaultSQLReviewRulePayload(ruleTp SQLReviewRuleType) (string, error) {
 		payload, err = json.Marshal(NumberTypeRulePayload{
 			Number: 5,
 		})
+	case SchemaRuleTableRequireClusteringKey:
+		payload, err = json.Marshal(NumberTypeRulePayload{
+			Number: 1024,
+		})
+	case SchemaRuleTableRequireHypertable:
+		payload, err = json.Marshal(NamingRulePayload{
+			Format: "_(metrics|events)$",
+		})
+	case SchemaRuleTableRequireRowLevelSecurity:
+		payload, err = json.Marshal(StringArrayTypeRulePayload{
+			List: []string{"multi-tenant"},
+		})
+	case SchemaRuleTableRequirePartition:
+		payload, err = json.Marshal(RequirePartitionRulePayload{
+			Format: "_(log|event|history)$",
+			List:   []string{"RANGE", "HASH"},
+		})
 	case SchemaRuleTableCommentConvention, SchemaRuleColumnCommentConvention:
 		payload, err = json.Marshal(CommentConventionRulePayload{
 			Required:  true,
@@ -434,6 +461,10 @@ func SetDefaultSQLReviewRulePayload(ruleTp SQLReviewRuleType) (string, error) {
 		payload, err = json.Marshal(StringArrayTypeRulePayload{
 			List: []string{"JSON"},
 		})
+	case SchemaRuleStatementRedisCommandDisallowList:
+		payload, err = json.Marshal(StringArrayTypeRulePayload{
+			List: []string{"KEYS", "FLUSHALL", "FLUSHDB", "CONFIG SET", "DEBUG"},
+		})
 	case SchemaRuleColumnMaximumCharacterLength:
 		payload, err = json.Marshal(NumberTypeRulePayload{
 			Number: 20,
@@ -446,6 +477,10 @@ func SetDefaultSQLReviewRulePayload(ruleTp SQLReviewRuleType) (string, error) {
 		payload, err = json.Marshal(NumberTypeRulePayload{
 			Number: 5,
 		})
+	case SchemaRuleIndexDropRequireInvisible:
+		payload, err = json.Marshal(NumberTypeRulePayload{
+			Number: 7,
+		})
 	case SchemaRuleCharsetAllowlist:
 		payload, err = json.Marshal(StringArrayTypeRulePayload{
 			List: []string{"utf8mb4", "UTF8"},
@@ -0,0 +1,114 @@
+// Package alert provides the senders opening and resolving the incidents on the alerting systems.
+package alert
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Type is the type of the alert receiver.
+type Type string
+
+const (
+	// TypePagerDuty sends the alerts as the events of the PagerDuty Events API v2.
+	TypePagerDuty Type = "PAGERDUTY"
+	// TypeOpsgenie creates and closes the alerts by the Opsgenie Alert API.
+	TypeOpsgenie Type = "OPSGENIE"
+)
+
+const (
+	// pagerDutyURL is the default URL of the PagerDuty Events API v2.
+	pagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	// opsgenieURL is the default URL of the Opsgenie API, the EU instance is https://api.eu.opsgenie.com.
+	opsgenieURL = "https://api.opsgenie.com"
+	// timeout is the timeout of sending an alert.
+	timeout = 10 * time.Second
+)
+
+// Severity is the severity of the alert.
+type Severity string
+
+const (
+	// SeverityCritical is the critical severity, e.g. the instance is unreachable.
+	SeverityCritical Severity = "critical"
+	// SeverityError is the error severity, e.g. the task run failed.
+	SeverityError Severity = "error"
+	// SeverityWarning is the warning severity, e.g. the schema drifted.
+	SeverityWarning Severity = "warning"
+)
+
+// Alert is an alert opening an incident.
+type Alert struct {
+	// DedupKey identifies the condition of the alert, the alerts with the same key are the same incident, and the
+	// incident is resolved by the key.
+	DedupKey string
+	Summary  string
+	// Source is the affected resource, e.g. the instance.
+	Source   string
+	Severity Severity
+	// Link is the link to the resource in Bytebase.
+	Link    string
+	Details map[string]string
+}
+
+// Config is the configuration of an alert receiver.
+type Config struct {
+	Type Type
+	// URL overrides the default API URL, e.g. the EU instance of Opsgenie.
+	URL string
+	// Key is the integration key, i.e. the routing key of PagerDuty, and the API key of Opsgenie.
+	Key string
+}
+
+// Sender opens and resolves the incidents on a receiver. Both are idempotent by the dedup key, so they are safe to be
+// sent again after the failures.
+type Sender interface {
+	Trigger(ctx context.Context, alert *Alert) error
+	Resolve(ctx context.Context, dedupKey string) error
+}
+
+// NewSender creates the sender of the receiver.
+func NewSender(config *Config) (Sender, error) {
+	if err := Validate(config); err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: timeout}
+	switch config.Type {
+	case TypePagerDuty:
+		u := config.URL
+		if u == "" {
+			u = pagerDutyURL
+		}
+		return &pagerDutySender{client: client, url: u, routingKey: config.Key}, nil
+	case TypeOpsgenie:
+		u := config.URL
+		if u == "" {
+			u = opsgenieURL
+		}
+		return &opsgenieSender{client: client, url: u, apiKey: config.Key}, nil
+	}
+	return nil, errors.Errorf("unsupported alert receiver type %q", config.Type)
+}
+
+// Validate validates the configuration of the receiver.
+func Validate(config *Config) error {
+	switch config.Type {
+	case TypePagerDuty, TypeOpsgenie:
+	default:
+		return errors.Errorf("unsupported alert receiver type %q", config.Type)
+	}
+	if config.URL != "" {
+		u, err := url.Parse(config.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf("invalid URL %q", config.URL)
+		}
+	}
+	if config.Key == "" {
+		return errors.Errorf("integration key is required")
+	}
+	return nil
+}
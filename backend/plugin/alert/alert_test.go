@@ -0,0 +1,93 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testAlert = &Alert{
+	DedupKey: "bytebase/instance/101/unreachable",
+	Summary:  "Instance \"prod-mysql\" is unreachable: " + strings.Repeat("dial tcp 10.0.0.1:3306: i/o timeout ", 5),
+	Source:   "prod-mysql",
+	Severity: SeverityCritical,
+	Link:     "https://bytebase.example.com/instance/prod-mysql-101",
+	Details:  map[string]string{"environment": "prod"},
+}
+
+func TestPagerDutySender(t *testing.T) {
+	a := require.New(t)
+	var events []pagerDutyEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event pagerDutyEvent
+		a.NoError(json.NewDecoder(r.Body).Decode(&event))
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender, err := NewSender(&Config{Type: TypePagerDuty, URL: server.URL, Key: "routing-key"})
+	a.NoError(err)
+	a.NoError(sender.Trigger(context.Background(), testAlert))
+	a.NoError(sender.Resolve(context.Background(), testAlert.DedupKey))
+	a.Len(events, 2)
+	a.Equal("routing-key", events[0].RoutingKey)
+	a.Equal("trigger", events[0].EventAction)
+	a.Equal(testAlert.DedupKey, events[0].DedupKey)
+	a.Equal(SeverityCritical, events[0].Payload.Severity)
+	a.Equal(testAlert.Link, events[0].Links[0].Href)
+	a.Equal("resolve", events[1].EventAction)
+	a.Equal(testAlert.DedupKey, events[1].DedupKey)
+	a.Nil(events[1].Payload)
+}
+
+func TestOpsgenieSender(t *testing.T) {
+	a := require.New(t)
+	var paths []string
+	var create opsgenieCreateAlertRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.Equal("GenieKey api-key", r.Header.Get("Authorization"))
+		paths = append(paths, r.URL.RequestURI())
+		if r.URL.Path == "/v2/alerts" {
+			a.NoError(json.NewDecoder(r.Body).Decode(&create))
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender, err := NewSender(&Config{Type: TypeOpsgenie, URL: server.URL + "/", Key: "api-key"})
+	a.NoError(err)
+	a.NoError(sender.Trigger(context.Background(), testAlert))
+	a.NoError(sender.Resolve(context.Background(), testAlert.DedupKey))
+	a.Equal([]string{"/v2/alerts", "/v2/alerts/bytebase%2Finstance%2F101%2Funreachable/close?identifierType=alias"}, paths)
+	a.Equal(testAlert.DedupKey, create.Alias)
+	a.Equal("P1", create.Priority)
+	a.Len([]rune(create.Message), opsgenieMessageLimit)
+	a.Contains(create.Description, testAlert.Link)
+}
+
+func TestSenderError(t *testing.T) {
+	a := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sender, err := NewSender(&Config{Type: TypePagerDuty, URL: server.URL, Key: "routing-key"})
+	a.NoError(err)
+	a.Error(sender.Trigger(context.Background(), testAlert))
+}
+
+func TestValidate(t *testing.T) {
+	a := require.New(t)
+	a.NoError(Validate(&Config{Type: TypePagerDuty, Key: "routing-key"}))
+	a.NoError(Validate(&Config{Type: TypeOpsgenie, URL: "https://api.eu.opsgenie.com", Key: "api-key"}))
+	a.Error(Validate(&Config{Type: TypePagerDuty}))
+	a.Error(Validate(&Config{Type: TypeOpsgenie, URL: "api.opsgenie.com", Key: "api-key"}))
+	a.Error(Validate(&Config{Type: "VICTOROPS", Key: "key"}))
+}
@@ -0,0 +1,101 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// opsgenieMessageLimit is the maximum length of the message of an Opsgenie alert.
+const opsgenieMessageLimit = 130
+
+// opsgenieSender creates and closes the alerts by the Opsgenie Alert API, the alias of the alert is the dedup key, so
+// Opsgenie deduplicates the open alerts of the same condition.
+type opsgenieSender struct {
+	client *http.Client
+	url    string
+	apiKey string
+}
+
+type opsgenieCreateAlertRequest struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Source      string            `json:"source"`
+	Entity      string            `json:"entity,omitempty"`
+	Priority    string            `json:"priority"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+type opsgenieCloseAlertRequest struct {
+	Source string `json:"source"`
+}
+
+func (s *opsgenieSender) Trigger(ctx context.Context, alert *Alert) error {
+	message := alert.Summary
+	if r := []rune(message); len(r) > opsgenieMessageLimit {
+		message = string(r[:opsgenieMessageLimit-3]) + "..."
+	}
+	description := alert.Summary
+	if alert.Link != "" {
+		description = fmt.Sprintf("%s\n\n%s", description, alert.Link)
+	}
+	return s.post(ctx, "/v2/alerts", &opsgenieCreateAlertRequest{
+		Message:     message,
+		Alias:       alert.DedupKey,
+		Description: description,
+		Source:      "Bytebase",
+		Entity:      alert.Source,
+		Priority:    getOpsgeniePriority(alert.Severity),
+		Details:     alert.Details,
+	})
+}
+
+func (s *opsgenieSender) Resolve(ctx context.Context, dedupKey string) error {
+	return s.post(ctx, fmt.Sprintf("/v2/alerts/%s/close?identifierType=alias", url.PathEscape(dedupKey)), &opsgenieCloseAlertRequest{
+		Source: "Bytebase",
+	})
+}
+
+func (s *opsgenieSender) post(ctx context.Context, path string, request any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal Opsgenie request")
+	}
+	endpoint := strings.TrimSuffix(s.url, "/") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct request to %s", endpoint)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("GenieKey %s", s.apiKey))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to post to %s", endpoint)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("failed to post to %s, status %d: %s", endpoint, resp.StatusCode, string(b))
+	}
+	return nil
+}
+
+func getOpsgeniePriority(severity Severity) string {
+	switch severity {
+	case SeverityCritical:
+		return "P1"
+	case SeverityError:
+		return "P2"
+	case SeverityWarning:
+		return "P3"
+	}
+	return "P3"
+}
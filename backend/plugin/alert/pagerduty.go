@@ -0,0 +1,90 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// pagerDutySender sends the trigger and resolve events of the PagerDuty Events API v2, the dedup key of the event is
+// the dedup key of the alert.
+type pagerDutySender struct {
+	client     *http.Client
+	url        string
+	routingKey string
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Links       []*pagerDutyLink  `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      Severity          `json:"severity"`
+	Component     string            `json:"component"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+func (s *pagerDutySender) Trigger(ctx context.Context, alert *Alert) error {
+	event := &pagerDutyEvent{
+		RoutingKey:  s.routingKey,
+		EventAction: "trigger",
+		DedupKey:    alert.DedupKey,
+		Payload: &pagerDutyPayload{
+			// The summary is truncated to 1024 characters by PagerDuty.
+			Summary:       alert.Summary,
+			Source:        alert.Source,
+			Severity:      alert.Severity,
+			Component:     "bytebase",
+			CustomDetails: alert.Details,
+		},
+	}
+	if alert.Link != "" {
+		event.Links = []*pagerDutyLink{{Href: alert.Link, Text: "View in Bytebase"}}
+	}
+	return s.send(ctx, event)
+}
+
+func (s *pagerDutySender) Resolve(ctx context.Context, dedupKey string) error {
+	return s.send(ctx, &pagerDutyEvent{
+		RoutingKey:  s.routingKey,
+		EventAction: "resolve",
+		DedupKey:    dedupKey,
+	})
+}
+
+func (s *pagerDutySender) send(ctx context.Context, event *pagerDutyEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal PagerDuty event")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct request to %s", s.url)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to post to %s", s.url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("failed to send PagerDuty %s event, status %d: %s", event.EventAction, resp.StatusCode, string(b))
+	}
+	return nil
+}
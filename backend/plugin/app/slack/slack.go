@@ -0,0 +1,199 @@
+// Package slack implements the Slack API callers and the verification of the requests from the Slack app.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	timeout = 30 * time.Second
+	// APIPath is the path of the Slack Web API server.
+	APIPath = "https://slack.com/api"
+	// requestTimestampTolerance is how old the requests can be, Slack recommends 5 minutes against the replay attacks.
+	requestTimestampTolerance = 5 * time.Minute
+)
+
+// ResponseTypeEphemeral is the response type only visible to the user.
+const ResponseTypeEphemeral = "ephemeral"
+
+// Provider is the provider for the Slack app.
+type Provider struct {
+	APIPath string
+	client  *http.Client
+}
+
+// NewProvider returns a Provider.
+func NewProvider(apiPath string) *Provider {
+	return &Provider{
+		APIPath: apiPath,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// InteractionPayload is the payload posted by Slack when the user clicks the buttons in the messages.
+// https://api.slack.com/reference/interaction-payloads/block-actions
+type InteractionPayload struct {
+	Type        string    `json:"type"`
+	User        User      `json:"user"`
+	Actions     []*Action `json:"actions"`
+	ResponseURL string    `json:"response_url"`
+}
+
+// User is the Slack user of the interaction.
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// Action is the clicked button.
+type Action struct {
+	ActionID string `json:"action_id"`
+	Value    string `json:"value"`
+}
+
+// SlashCommand is the slash command posted by Slack.
+// https://api.slack.com/interactivity/slash-commands#app_command_handling
+type SlashCommand struct {
+	Command string
+	Text    string
+	UserID  string
+}
+
+// Message is the message responding to the interactions and the slash commands.
+type Message struct {
+	ResponseType    string `json:"response_type,omitempty"`
+	ReplaceOriginal bool   `json:"replace_original"`
+	Text            string `json:"text"`
+}
+
+// UserInfoResponse is the response of users.info.
+type UserInfoResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	User  struct {
+		Profile struct {
+			Email string `json:"email"`
+		} `json:"profile"`
+	} `json:"user"`
+}
+
+// ParseSlashCommand parses the form encoded slash command.
+func ParseSlashCommand(body []byte) (*SlashCommand, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse slash command")
+	}
+	return &SlashCommand{
+		Command: values.Get("command"),
+		Text:    values.Get("text"),
+		UserID:  values.Get("user_id"),
+	}, nil
+}
+
+// ParseInteractionPayload parses the form encoded interaction payload.
+func ParseInteractionPayload(body []byte) (*InteractionPayload, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse interaction")
+	}
+	payload := &InteractionPayload{}
+	if err := json.Unmarshal([]byte(values.Get("payload")), payload); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal interaction payload")
+	}
+	return payload, nil
+}
+
+// VerifyRequest verifies the signature of the request with the signing secret of the Slack app.
+// https://api.slack.com/authentication/verifying-requests-from-slack
+func VerifyRequest(signingSecret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Errorf("invalid request timestamp %q", timestamp)
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > requestTimestampTolerance || d < -requestTimestampTolerance {
+		return errors.Errorf("request timestamp %q is out of tolerance", timestamp)
+	}
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	_, _ = fmt.Fprintf(mac, "v0:%s:", timestamp)
+	_, _ = mac.Write(body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(header.Get("X-Slack-Signature"))) {
+		return errors.New("invalid request signature")
+	}
+	return nil
+}
+
+// GetUserEmail gets the email of the Slack user, which needs the users:read and users:read.email scopes.
+// https://api.slack.com/methods/users.info
+func (p *Provider) GetUserEmail(ctx context.Context, botToken, userID string) (string, error) {
+	u := fmt.Sprintf("%s/users.info?user=%s", p.APIPath, url.QueryEscape(userID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to construct GET %s", u)
+	}
+	req.Header.Set("Authorization", "Bearer "+botToken)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to GET %s", u)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read response body of GET %s", u)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("non-200 GET status code %d with body %q", resp.StatusCode, b)
+	}
+
+	var response UserInfoResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return "", err
+	}
+	if !response.OK {
+		return "", errors.Errorf("failed to get user info, error %s", response.Error)
+	}
+	if response.User.Profile.Email == "" {
+		return "", errors.Errorf("email of Slack user %s is not visible, hint: check if the app has the users:read.email scope", userID)
+	}
+	return response.User.Profile.Email, nil
+}
+
+// PostResponse posts the message to the response URL of the interaction.
+// https://api.slack.com/interactivity/handling#message_responses
+func (p *Provider) PostResponse(ctx context.Context, responseURL string, message *Message) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct response POST request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to POST response")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return errors.Errorf("non-200 POST status code %d with body %q", resp.StatusCode, b)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+)
+
+func TestVerifyRequest(t *testing.T) {
+	a := require.New(t)
+	// The example in https://api.slack.com/authentication/verifying-requests-from-slack.
+	signingSecret := "8f742231b10e8888abcd99yyyzzz85a5"
+	body := []byte("token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c")
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", "1531420618")
+	header.Set("X-Slack-Signature", "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503")
+	now := time.Unix(1531420618, 0)
+
+	a.NoError(VerifyRequest(signingSecret, header, body, now))
+	a.Error(VerifyRequest("wrong", header, body, now))
+	a.Error(VerifyRequest(signingSecret, header, append(body, 'x'), now))
+	a.Error(VerifyRequest(signingSecret, header, body, now.Add(10*time.Minute)))
+
+	command, err := ParseSlashCommand(body)
+	a.NoError(err)
+	a.Equal(&SlashCommand{Command: "/webhook-collect", Text: "", UserID: "U2CERLKJA"}, command)
+}
+
+func TestProvider_GetUserEmail(t *testing.T) {
+	a := require.New(t)
+	p := NewProvider(APIPath)
+	p.client = &http.Client{
+		Transport: &common.MockRoundTripper{
+			MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+				a.Equal("Bearer xoxb-token", r.Header.Get("Authorization"))
+				a.Equal("U2CERLKJA", r.URL.Query().Get("user"))
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body: io.NopCloser(strings.NewReader(`
+{
+    "ok": true,
+    "user": {
+        "id": "U2CERLKJA",
+        "profile": {
+            "email": "roadrunner@example.com"
+        }
+    }
+}
+`)),
+				}, nil
+			},
+		},
+	}
+	email, err := p.GetUserEmail(context.Background(), "xoxb-token", "U2CERLKJA")
+	a.NoError(err)
+	a.Equal("roadrunner@example.com", email)
+}
@@ -0,0 +1,382 @@
+// Package teams implements the Microsoft Teams bot and outgoing webhook callers, and the verification of their requests.
+package teams
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	timeout = 30 * time.Second
+	// LoginURL is the URL of the Microsoft identity platform issuing the access tokens of the app.
+	LoginURL = "https://login.microsoftonline.com"
+	// GraphURL is the URL of the Microsoft Graph API.
+	GraphURL = "https://graph.microsoft.com/v1.0"
+	// BotKeysURL is the URL of the keys signing the Bot Framework requests.
+	// https://learn.microsoft.com/en-us/azure/bot-service/rest-api/bot-framework-rest-connector-authentication#connector-to-bot
+	BotKeysURL = "https://login.botframework.com/v1/.well-known/keys"
+
+	botTokenIssuer = "https://api.botframework.com"
+	botScope       = "https://api.botframework.com/.default"
+	graphScope     = "https://graph.microsoft.com/.default"
+	// botKeysTTL is how long the bot keys are cached, the Bot Framework recommends to refresh them every day.
+	botKeysTTL = 24 * time.Hour
+)
+
+const (
+	// ActionApprove is the action of the approve button in the cards, the value is the issue ID.
+	ActionApprove = "bb.issue.approve"
+	// AdaptiveCardContentType is the content type of the adaptive card attachments.
+	AdaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+)
+
+// Credential is the credential of the Azure app registration of the bot. The app also needs the User.Read.All
+// application permission of Microsoft Graph to map the Teams users to the Bytebase users by email.
+type Credential struct {
+	TenantID    string
+	AppID       string
+	AppPassword string
+}
+
+// Provider is the provider for the Teams bot and outgoing webhook.
+type Provider struct {
+	LoginURL   string
+	GraphURL   string
+	BotKeysURL string
+	client     *http.Client
+
+	mu          sync.Mutex
+	botKeys     map[string]*rsa.PublicKey
+	botKeysTime time.Time
+	tokens      map[string]*accessToken
+}
+
+type accessToken struct {
+	token    string
+	expireAt time.Time
+}
+
+// NewProvider returns a Provider.
+func NewProvider() *Provider {
+	return &Provider{
+		LoginURL:   LoginURL,
+		GraphURL:   GraphURL,
+		BotKeysURL: BotKeysURL,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+		tokens: make(map[string]*accessToken),
+	}
+}
+
+// Activity is the Bot Framework activity, which is also posted by the outgoing webhooks.
+// https://learn.microsoft.com/en-us/azure/bot-service/rest-api/bot-framework-rest-connector-api-reference#activity-object
+type Activity struct {
+	Type         string               `json:"type"`
+	ID           string               `json:"id,omitempty"`
+	Text         string               `json:"text,omitempty"`
+	ServiceURL   string               `json:"serviceUrl,omitempty"`
+	From         *ChannelAccount      `json:"from,omitempty"`
+	Conversation *ConversationAccount `json:"conversation,omitempty"`
+	ReplyToID    string               `json:"replyToId,omitempty"`
+	// Value is the data of the clicked Action.Submit button.
+	Value       json.RawMessage `json:"value,omitempty"`
+	Attachments []*Attachment   `json:"attachments,omitempty"`
+}
+
+// ChannelAccount is the Teams user.
+type ChannelAccount struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	AADObjectID string `json:"aadObjectId,omitempty"`
+}
+
+// ConversationAccount is the conversation of the activity.
+type ConversationAccount struct {
+	ID string `json:"id"`
+}
+
+// Attachment is the attachment of the activity, e.g. the adaptive card.
+type Attachment struct {
+	ContentType string `json:"contentType"`
+	Content     any    `json:"content"`
+}
+
+// ActionValue is the data of the approve button.
+type ActionValue struct {
+	Action  string `json:"action"`
+	IssueID int    `json:"issueId"`
+}
+
+// Fact is the fact of the issue in the card.
+type Fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// NewIssueCard returns the adaptive card of the issue. The approve button is added if approvable, which
+// only work in the cards sent by the bot.
+// https://adaptivecards.io/explorer/
+func NewIssueCard(title, link string, issueID int, facts []*Fact, approvable bool) *Attachment {
+	actions := []any{
+		map[string]any{
+			"type":  "Action.OpenUrl",
+			"title": "View in Bytebase",
+			"url":   link,
+		},
+	}
+	if approvable {
+		actions = append(actions, map[string]any{
+			"type":  "Action.Submit",
+			"title": "Approve",
+			"style": "positive",
+			"data":  &ActionValue{Action: ActionApprove, IssueID: issueID},
+		})
+	}
+	return &Attachment{
+		ContentType: AdaptiveCardContentType,
+		Content: map[string]any{
+			"type":    "AdaptiveCard",
+			"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+			"version": "1.4",
+			"body": []any{
+				map[string]any{
+					"type":   "TextBlock",
+					"text":   title,
+					"weight": "bolder",
+					"size":   "medium",
+					"wrap":   true,
+				},
+				map[string]any{
+					"type":  "FactSet",
+					"facts": facts,
+				},
+			},
+			"actions": actions,
+		},
+	}
+}
+
+// VerifyOutgoingWebhook verifies the HMAC signature of the outgoing webhook request with the security token.
+// https://learn.microsoft.com/en-us/microsoftteams/platform/webhooks-and-connectors/how-to/add-outgoing-webhook#2-create-a-method-to-verify-the-outgoing-webhook-hmac-token
+func VerifyOutgoingWebhook(securityToken string, header http.Header, body []byte) error {
+	key, err := base64.StdEncoding.DecodeString(securityToken)
+	if err != nil {
+		return errors.Wrap(err, "invalid security token")
+	}
+	signature, ok := strings.CutPrefix(header.Get("Authorization"), "HMAC ")
+	if !ok {
+		return errors.New("missing HMAC signature")
+	}
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(body)
+	want := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(signature)) {
+		return errors.New("invalid HMAC signature")
+	}
+	return nil
+}
+
+// VerifyBotRequest verifies the bearer token of the Bot Framework request to the bot of the app ID, the service URL
+// of the token must be the one of the activity.
+func (p *Provider) VerifyBotRequest(ctx context.Context, appID string, header http.Header, activity *Activity) error {
+	tokenString, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return errors.New("missing bearer token")
+	}
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, errors.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return p.getBotKey(ctx, kid)
+	}); err != nil {
+		return errors.Wrap(err, "invalid bearer token")
+	}
+	if !claims.VerifyIssuer(botTokenIssuer, true) {
+		return errors.Errorf("invalid issuer %v", claims["iss"])
+	}
+	if !claims.VerifyAudience(appID, true) {
+		return errors.Errorf("invalid audience %v", claims["aud"])
+	}
+	if serviceURL, _ := claims["serviceurl"].(string); serviceURL != activity.ServiceURL {
+		return errors.Errorf("service URL %q doesn't match the token", activity.ServiceURL)
+	}
+	return nil
+}
+
+func (p *Provider) getBotKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.botKeys[kid]; ok && time.Since(p.botKeysTime) < botKeysTTL {
+		return key, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.BotKeysURL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to construct GET %s", p.BotKeysURL)
+	}
+	b, err := p.do(req)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal bot keys")
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range response.Keys {
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	p.botKeys = keys
+	p.botKeysTime = time.Now()
+	key, ok := keys[kid]
+	if !ok {
+		return nil, errors.Errorf("unknown key %q", kid)
+	}
+	return key, nil
+}
+
+// GetUserEmail gets the email of the Teams user by the Azure AD object ID, or the user principal name if the user has
+// no mailbox.
+// https://learn.microsoft.com/en-us/graph/api/user-get
+func (p *Provider) GetUserEmail(ctx context.Context, credential *Credential, aadObjectID string) (string, error) {
+	token, err := p.getToken(ctx, credential, credential.TenantID, graphScope)
+	if err != nil {
+		return "", err
+	}
+	u := fmt.Sprintf("%s/users/%s?$select=mail,userPrincipalName", p.GraphURL, url.PathEscape(aadObjectID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to construct GET %s", u)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	b, err := p.do(req)
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		Mail              string `json:"mail"`
+		UserPrincipalName string `json:"userPrincipalName"`
+	}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal user")
+	}
+	if response.Mail != "" {
+		return response.Mail, nil
+	}
+	return response.UserPrincipalName, nil
+}
+
+// ReplyToActivity sends the reply to the activity through the Bot Framework.
+// https://learn.microsoft.com/en-us/azure/bot-service/rest-api/bot-framework-rest-connector-api-reference#reply-to-activity
+func (p *Provider) ReplyToActivity(ctx context.Context, credential *Credential, activity *Activity, reply *Activity) error {
+	token, err := p.getToken(ctx, credential, credential.TenantID, botScope)
+	if err != nil {
+		return err
+	}
+	reply.ReplyToID = activity.ID
+	body, err := json.Marshal(reply)
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/v3/conversations/%s/activities/%s", strings.TrimSuffix(activity.ServiceURL, "/"), url.PathEscape(activity.Conversation.ID), url.PathEscape(activity.ID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct POST %s", u)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	_, err = p.do(req)
+	return err
+}
+
+// getToken gets the access token of the app with the client credentials, which is cached until it expires.
+func (p *Provider) getToken(ctx context.Context, credential *Credential, tenantID, scope string) (string, error) {
+	key := strings.Join([]string{tenantID, credential.AppID, scope}, "/")
+	p.mu.Lock()
+	token, ok := p.tokens[key]
+	p.mu.Unlock()
+	if ok && time.Now().Before(token.expireAt) {
+		return token.token, nil
+	}
+
+	u := fmt.Sprintf("%s/%s/oauth2/v2.0/token", p.LoginURL, url.PathEscape(tenantID))
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {credential.AppID},
+		"client_secret": {credential.AppPassword},
+		"scope":         {scope},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to construct POST %s", u)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	b, err := p.do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get access token")
+	}
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal access token")
+	}
+	p.mu.Lock()
+	// Refresh the token a minute before it expires.
+	p.tokens[key] = &accessToken{
+		token:    response.AccessToken,
+		expireAt: time.Now().Add(time.Duration(response.ExpiresIn)*time.Second - time.Minute),
+	}
+	p.mu.Unlock()
+	return response.AccessToken, nil
+}
+
+func (p *Provider) do(req *http.Request) ([]byte, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to %s %s", req.Method, req.URL)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read response body of %s %s", req.Method, req.URL)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("non-2xx %s status code %d with body %q", req.Method, resp.StatusCode, b)
+	}
+	return b, nil
+}
@@ -0,0 +1,95 @@
+package teams
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+)
+
+func TestVerifyOutgoingWebhook(t *testing.T) {
+	a := require.New(t)
+	securityToken := base64.StdEncoding.EncodeToString([]byte("security-token"))
+	body := []byte(`{"type":"message","text":"<at>Bytebase</at> status 1"}`)
+	mac := hmac.New(sha256.New, []byte("security-token"))
+	_, _ = mac.Write(body)
+	header := http.Header{}
+	header.Set("Authorization", "HMAC "+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+
+	a.NoError(VerifyOutgoingWebhook(securityToken, header, body))
+	a.Error(VerifyOutgoingWebhook(base64.StdEncoding.EncodeToString([]byte("wrong")), header, body))
+	a.Error(VerifyOutgoingWebhook(securityToken, header, append(body, ' ')))
+	a.Error(VerifyOutgoingWebhook(securityToken, http.Header{}, body))
+}
+
+func TestProvider_VerifyBotRequest(t *testing.T) {
+	a := require.New(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	a.NoError(err)
+	p := NewProvider()
+	p.client = &http.Client{
+		Transport: &common.MockRoundTripper{
+			MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+				a.Equal(BotKeysURL, r.URL.String())
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body: io.NopCloser(strings.NewReader(fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"key-1","n":%q,"e":%q}]}`,
+						base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+						base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+					))),
+				}, nil
+			},
+		},
+	}
+	sign := func(claims jwt.MapClaims, signingKey *rsa.PrivateKey) http.Header {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key-1"
+		s, err := token.SignedString(signingKey)
+		a.NoError(err)
+		header := http.Header{}
+		header.Set("Authorization", "Bearer "+s)
+		return header
+	}
+	activity := &Activity{ServiceURL: "https://smba.trafficmanager.net/amer/"}
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":        botTokenIssuer,
+			"aud":        "app-id",
+			"exp":        time.Now().Add(time.Hour).Unix(),
+			"serviceurl": activity.ServiceURL,
+		}
+	}
+	ctx := context.Background()
+
+	a.NoError(p.VerifyBotRequest(ctx, "app-id", sign(claims(), key), activity))
+	a.Error(p.VerifyBotRequest(ctx, "other-app-id", sign(claims(), key), activity))
+	a.Error(p.VerifyBotRequest(ctx, "app-id", http.Header{}, activity))
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	a.NoError(err)
+	a.Error(p.VerifyBotRequest(ctx, "app-id", sign(claims(), otherKey), activity))
+
+	expired := claims()
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	a.Error(p.VerifyBotRequest(ctx, "app-id", sign(expired, key), activity))
+
+	otherIssuer := claims()
+	otherIssuer["iss"] = "https://example.com"
+	a.Error(p.VerifyBotRequest(ctx, "app-id", sign(otherIssuer, key), activity))
+
+	a.Error(p.VerifyBotRequest(ctx, "app-id", sign(claims(), key), &Activity{ServiceURL: "https://example.com/"}))
+}
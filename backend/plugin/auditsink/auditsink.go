@@ -0,0 +1,100 @@
+// Package auditsink provides the senders streaming the audit entries to the SIEM systems.
+package auditsink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Type is the type of the audit sink.
+type Type string
+
+const (
+	// TypeSyslog sends the entries as RFC 5424 messages to a syslog server over TCP or UDP.
+	TypeSyslog Type = "SYSLOG"
+	// TypeSplunkHEC sends the entries to the Splunk HTTP Event Collector.
+	TypeSplunkHEC Type = "SPLUNK_HEC"
+	// TypeKafka produces the entries to a Kafka topic through the Confluent REST Proxy.
+	TypeKafka Type = "KAFKA"
+)
+
+// timeout is the timeout of sending a batch of entries.
+const timeout = 10 * time.Second
+
+// Entry is an audit entry.
+type Entry struct {
+	ID           int    `json:"id"`
+	CreatedTs    int64  `json:"createdTs"`
+	CreatorEmail string `json:"creatorEmail"`
+	Type         string `json:"type"`
+	Level        string `json:"level"`
+	ContainerID  int    `json:"containerId"`
+	Comment      string `json:"comment"`
+	// Payload is the JSON payload of the activity, it's null if the payload isn't valid JSON.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Config is the configuration of an audit sink.
+type Config struct {
+	Type Type
+	// URL is tcp://host:port or udp://host:port for syslog, the collector endpoint for Splunk HEC, e.g.
+	// https://splunk:8088/services/collector/event, and the REST Proxy base URL for Kafka.
+	URL string
+	// Token is the HEC token for Splunk HEC.
+	Token string
+	// Topic is the topic for Kafka.
+	Topic string
+}
+
+// Sender sends the batches of audit entries to a sink. The entries of a failed batch are sent again, so the sinks
+// should tolerate the duplicates, e.g. deduplicate by the entry ID.
+type Sender interface {
+	Send(ctx context.Context, entries []*Entry) error
+}
+
+// NewSender creates the sender of the sink.
+func NewSender(config *Config) (Sender, error) {
+	if err := Validate(config); err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: timeout}
+	switch config.Type {
+	case TypeSyslog:
+		return newSyslogSender(config.URL)
+	case TypeSplunkHEC:
+		return &splunkSender{client: client, url: config.URL, token: config.Token}, nil
+	case TypeKafka:
+		return &kafkaSender{client: client, url: config.URL, topic: config.Topic}, nil
+	}
+	return nil, errors.Errorf("unsupported audit sink type %q", config.Type)
+}
+
+// Validate validates the configuration of the sink.
+func Validate(config *Config) error {
+	switch config.Type {
+	case TypeSyslog:
+		_, err := parseSyslogURL(config.URL)
+		return err
+	case TypeSplunkHEC:
+		if err := validateHTTPURL(config.URL); err != nil {
+			return err
+		}
+		if config.Token == "" {
+			return errors.Errorf("HEC token is required for Splunk HEC")
+		}
+		return nil
+	case TypeKafka:
+		if err := validateHTTPURL(config.URL); err != nil {
+			return err
+		}
+		if config.Topic == "" {
+			return errors.Errorf("topic is required for Kafka")
+		}
+		return nil
+	}
+	return errors.Errorf("unsupported audit sink type %q", config.Type)
+}
@@ -0,0 +1,112 @@
+package auditsink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testEntries = []*Entry{
+	{ID: 101, CreatedTs: 1697270400, CreatorEmail: "alice@example.com", Type: "bb.member.create", Level: "INFO", Payload: json.RawMessage(`{"principalId":102}`)},
+	{ID: 102, CreatedTs: 1697270401, CreatorEmail: "bob@example.com", Type: "bb.database.grant.request", Level: "WARN", Payload: json.RawMessage(`null`)},
+}
+
+func TestSplunkSender(t *testing.T) {
+	a := require.New(t)
+	var events []splunkEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.Equal("Splunk secret", r.Header.Get("Authorization"))
+		decoder := json.NewDecoder(r.Body)
+		for decoder.More() {
+			var event splunkEvent
+			a.NoError(decoder.Decode(&event))
+			events = append(events, event)
+		}
+	}))
+	defer server.Close()
+
+	sender, err := NewSender(&Config{Type: TypeSplunkHEC, URL: server.URL, Token: "secret"})
+	a.NoError(err)
+	a.NoError(sender.Send(context.Background(), testEntries))
+	a.Len(events, 2)
+	a.Equal(int64(1697270400), events[0].Time)
+	a.Equal("bb.database.grant.request", events[1].Event.Type)
+}
+
+func TestKafkaSender(t *testing.T) {
+	a := require.New(t)
+	var request kafkaProduceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.Equal("/topics/bytebase-audit", r.URL.Path)
+		a.Equal("application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+		a.NoError(json.NewDecoder(r.Body).Decode(&request))
+	}))
+	defer server.Close()
+
+	sender, err := NewSender(&Config{Type: TypeKafka, URL: server.URL + "/", Topic: "bytebase-audit"})
+	a.NoError(err)
+	a.NoError(sender.Send(context.Background(), testEntries))
+	a.Len(request.Records, 2)
+	a.Equal("101", request.Records[0].Key)
+	a.Equal("alice@example.com", request.Records[0].Value.CreatorEmail)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	sender, err = NewSender(&Config{Type: TypeKafka, URL: failing.URL, Topic: "bytebase-audit"})
+	a.NoError(err)
+	a.Error(sender.Send(context.Background(), testEntries))
+}
+
+func TestSyslogSender(t *testing.T) {
+	a := require.New(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	a.NoError(err)
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		b, _ := io.ReadAll(bufio.NewReader(conn))
+		received <- string(b)
+	}()
+
+	sender, err := NewSender(&Config{Type: TypeSyslog, URL: "tcp://" + listener.Addr().String()})
+	a.NoError(err)
+	a.NoError(sender.Send(context.Background(), testEntries))
+	content := <-received
+	// The messages are framed by the octet counting.
+	a.Regexp(`^\d+ <134>1 2023-10-14T08:00:00Z \S+ bytebase - bb\.member\.create - \{"id":101,`, content)
+	a.Contains(content, `<132>1 2023-10-14T08:00:01Z`)
+	a.Equal(2, strings.Count(content, " bytebase - "))
+}
+
+func TestValidate(t *testing.T) {
+	for _, config := range []*Config{
+		{Type: TypeSyslog, URL: "udp://syslog:514"},
+		{Type: TypeSplunkHEC, URL: "https://splunk:8088/services/collector/event", Token: "secret"},
+		{Type: TypeKafka, URL: "http://rest-proxy:8082", Topic: "audit"},
+	} {
+		require.NoError(t, Validate(config), config)
+	}
+	for _, config := range []*Config{
+		{Type: TypeSyslog, URL: "syslog:514"},
+		{Type: TypeSplunkHEC, URL: "https://splunk:8088/services/collector/event"},
+		{Type: TypeKafka, URL: "http://rest-proxy:8082"},
+		{Type: "FLUENTD", URL: "http://fluentd"},
+	} {
+		require.Error(t, Validate(config), config)
+	}
+}
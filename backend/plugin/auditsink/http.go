@@ -0,0 +1,103 @@
+package auditsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+func validateHTTPURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Errorf("invalid URL %q", rawURL)
+	}
+	return nil
+}
+
+// splunkSender sends the entries to the Splunk HTTP Event Collector, a batch is a request with the concatenated
+// events.
+type splunkSender struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+type splunkEvent struct {
+	Time       int64  `json:"time"`
+	Source     string `json:"source"`
+	SourceType string `json:"sourcetype"`
+	Event      *Entry `json:"event"`
+}
+
+func (s *splunkSender) Send(ctx context.Context, entries []*Entry) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, entry := range entries {
+		if err := encoder.Encode(&splunkEvent{
+			Time:       entry.CreatedTs,
+			Source:     "bytebase",
+			SourceType: "bytebase:audit",
+			Event:      entry,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to marshal audit entry %d", entry.ID)
+		}
+	}
+	return post(ctx, s.client, s.url, "application/json", fmt.Sprintf("Splunk %s", s.token), body.Bytes())
+}
+
+// kafkaSender produces the entries to a topic by the Confluent REST Proxy API v2, keyed by the entry ID.
+type kafkaSender struct {
+	client *http.Client
+	url    string
+	topic  string
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value *Entry `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []*kafkaRecord `json:"records"`
+}
+
+func (s *kafkaSender) Send(ctx context.Context, entries []*Entry) error {
+	request := &kafkaProduceRequest{}
+	for _, entry := range entries {
+		request.Records = append(request.Records, &kafkaRecord{Key: fmt.Sprintf("%d", entry.ID), Value: entry})
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal audit entries")
+	}
+	endpoint := fmt.Sprintf("%s/topics/%s", strings.TrimSuffix(s.url, "/"), url.PathEscape(s.topic))
+	return post(ctx, s.client, endpoint, "application/vnd.kafka.json.v2+json", "", body)
+}
+
+func post(ctx context.Context, client *http.Client, url, contentType, authorization string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct request to %s", url)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to post to %s", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("failed to post to %s, status %d: %s", url, resp.StatusCode, string(b))
+	}
+	return nil
+}
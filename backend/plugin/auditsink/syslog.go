@@ -0,0 +1,105 @@
+package auditsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// syslogFacility is the local0 facility.
+	syslogFacility = 16
+	// syslogMaxMsgIDLength is the longest MSGID allowed by RFC 5424.
+	syslogMaxMsgIDLength = 32
+)
+
+// syslogSender sends the entries as RFC 5424 messages, one datagram per message over UDP and the octet-counting
+// framing of RFC 6587 over TCP. The connection is set up for each batch.
+type syslogSender struct {
+	network  string
+	address  string
+	hostname string
+}
+
+func parseSyslogURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "tcp" && u.Scheme != "udp") || u.Host == "" {
+		return nil, errors.Errorf("invalid syslog URL %q, expecting tcp://host:port or udp://host:port", rawURL)
+	}
+	return u, nil
+}
+
+func newSyslogSender(rawURL string) (*syslogSender, error) {
+	u, err := parseSyslogURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &syslogSender{network: u.Scheme, address: u.Host, hostname: hostname}, nil
+}
+
+func (s *syslogSender) Send(ctx context.Context, entries []*Entry) error {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to syslog server %s", s.address)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		message, err := formatSyslogMessage(entry, s.hostname)
+		if err != nil {
+			return err
+		}
+		if s.network == "tcp" {
+			message = fmt.Sprintf("%d %s", len(message), message)
+		}
+		if _, err := conn.Write([]byte(message)); err != nil {
+			return errors.Wrapf(err, "failed to send audit entry %d to syslog server %s", entry.ID, s.address)
+		}
+	}
+	return nil
+}
+
+// formatSyslogMessage formats the entry as an RFC 5424 message with the entry in JSON as the message, and the
+// activity type as the MSGID.
+func formatSyslogMessage(entry *Entry, hostname string) (string, error) {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal audit entry %d", entry.ID)
+	}
+	msgID := entry.Type
+	if len(msgID) > syslogMaxMsgIDLength {
+		msgID = msgID[:syslogMaxMsgIDLength]
+	}
+	return fmt.Sprintf("<%d>1 %s %s bytebase - %s - %s",
+		syslogFacility*8+syslogSeverity(entry.Level),
+		time.Unix(entry.CreatedTs, 0).UTC().Format(time.RFC3339),
+		hostname,
+		msgID,
+		content,
+	), nil
+}
+
+func syslogSeverity(level string) int {
+	switch level {
+	case "ERROR":
+		return 3
+	case "WARN":
+		return 4
+	default:
+		// Informational.
+		return 6
+	}
+}
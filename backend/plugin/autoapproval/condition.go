@@ -0,0 +1,90 @@
+// Package autoapproval evaluates the auto-approval conditions of issues.
+package autoapproval
+
+import (
+	"github.com/google/cel-go/cel"
+	"github.com/pkg/errors"
+)
+
+// conditionEnvOptions declares the variables of the auto-approval conditions. The requester is the issue creator with
+// the email, name and workspace role, and the database is the one changed by a task with the name and labels.
+var conditionEnvOptions = []cel.EnvOption{
+	cel.Variable("requester", cel.MapType(cel.StringType, cel.StringType)),
+	// use environment.resource_id
+	cel.Variable("environment_id", cel.StringType),
+	// use project.resource_id
+	cel.Variable("project_id", cel.StringType),
+	cel.Variable("database_name", cel.StringType),
+	cel.Variable("database_labels", cel.MapType(cel.StringType, cel.StringType)),
+	cel.Variable("issue_type", cel.StringType),
+	cel.Variable("risk_level", cel.IntType),
+}
+
+// Input is the values of the variables to evaluate the condition with.
+type Input struct {
+	Requester      map[string]string
+	EnvironmentID  string
+	ProjectID      string
+	DatabaseName   string
+	DatabaseLabels map[string]string
+	IssueType      string
+	RiskLevel      int64
+}
+
+// Condition decides whether to approve an issue without the approvers.
+type Condition struct {
+	program cel.Program
+}
+
+// NewCondition compiles the auto-approval condition, which is a CEL expression returning true if the issue should be
+// approved, e.g. `risk_level <= 100 && environment_id == "test"` or `database_labels["tier"] == "sandbox"`.
+func NewCondition(expression string) (*Condition, error) {
+	e, err := cel.NewEnv(conditionEnvOptions...)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := e.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Wrapf(issues.Err(), "invalid auto-approval condition %q", expression)
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, errors.Errorf("auto-approval condition %q must return a bool, got %s", expression, ast.OutputType())
+	}
+	program, err := e.Program(ast)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid auto-approval condition %q", expression)
+	}
+	return &Condition{program: program}, nil
+}
+
+// ValidateCondition validates the auto-approval condition.
+func ValidateCondition(expression string) error {
+	_, err := NewCondition(expression)
+	return err
+}
+
+// ShouldApprove returns true if the issue should be approved for the input. It fails safe not to approve if the
+// condition can't be evaluated, e.g. the label it refers to isn't set on the database.
+func (c *Condition) ShouldApprove(input *Input) bool {
+	requester, labels := input.Requester, input.DatabaseLabels
+	if requester == nil {
+		requester = map[string]string{}
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	out, _, err := c.program.Eval(map[string]any{
+		"requester":       requester,
+		"environment_id":  input.EnvironmentID,
+		"project_id":      input.ProjectID,
+		"database_name":   input.DatabaseName,
+		"database_labels": labels,
+		"issue_type":      input.IssueType,
+		"risk_level":      input.RiskLevel,
+	})
+	if err != nil {
+		return false
+	}
+	v, ok := out.Value().(bool)
+	return ok && v
+}
@@ -0,0 +1,41 @@
+package autoapproval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCondition(t *testing.T) {
+	input := &Input{
+		Requester:      map[string]string{"email": "alice@example.com", "name": "Alice", "role": "DEVELOPER"},
+		EnvironmentID:  "test",
+		ProjectID:      "shop",
+		DatabaseName:   "orders",
+		DatabaseLabels: map[string]string{"tier": "sandbox"},
+		IssueType:      "bb.issue.database.data.update",
+		RiskLevel:      100,
+	}
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{`risk_level <= 100 && environment_id == "test"`, true},
+		{`risk_level < 100`, false},
+		{`database_labels["tier"] == "sandbox" && project_id == "shop"`, true},
+		{`requester.email.endsWith("@example.com") && requester.role != "DBA"`, true},
+		{`issue_type == "bb.issue.database.schema.update"`, false},
+		{`database_name.startsWith("tmp_")`, false},
+		// The conditions which can't be evaluated fail safe not to approve.
+		{`database_labels["owner"] == "alice"`, false},
+	}
+	for _, test := range tests {
+		condition, err := NewCondition(test.expression)
+		require.NoError(t, err, test.expression)
+		require.Equal(t, test.want, condition.ShouldApprove(input), test.expression)
+	}
+
+	for _, expression := range []string{`risk_level <=`, `"test"`, `unknown == "test"`} {
+		require.Error(t, ValidateCondition(expression), expression)
+	}
+}
@@ -0,0 +1,203 @@
+// Package cassandra implements the Cassandra driver, the keyspaces are synced as the databases.
+package cassandra
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/cql"
+)
+
+var (
+	_ db.Driver = (*Driver)(nil)
+)
+
+func init() {
+	db.Register(db.Cassandra, newDriver)
+}
+
+// Driver is the Cassandra driver.
+type Driver struct {
+	session      *gocql.Session
+	databaseName string
+}
+
+func newDriver(_ db.DriverConfig) db.Driver {
+	return &Driver{}
+}
+
+// Open opens the Cassandra driver.
+func (d *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionConfig, _ db.ConnectionContext) (db.Driver, error) {
+	port := config.Port
+	if port == "" {
+		port = "9042"
+	}
+	// The host could be a comma-separated list of the contact points.
+	var hosts []string
+	for _, host := range strings.Split(config.Host, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, fmt.Sprintf("%s:%s", host, port))
+		}
+	}
+	cluster := gocql.NewCluster(hosts...)
+	cluster.Keyspace = config.Database
+	cluster.Consistency = gocql.LocalQuorum
+	cluster.Timeout = 30 * time.Second
+	cluster.ConnectTimeout = 10 * time.Second
+	if config.Username != "" {
+		cluster.Authenticator = gocql.PasswordAuthenticator{
+			Username: config.Username,
+			Password: config.Password,
+		}
+	}
+	tlsConfig, err := config.TLSConfig.GetSslConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "cassandra: failed to get tls config")
+	}
+	if tlsConfig != nil {
+		cluster.SslOpts = &gocql.SslOptions{
+			Config:                 tlsConfig,
+			EnableHostVerification: !tlsConfig.InsecureSkipVerify,
+		}
+	}
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "cassandra: failed to create session")
+	}
+	d.session = session
+	d.databaseName = config.Database
+	return d, nil
+}
+
+// Close closes the Cassandra driver.
+func (d *Driver) Close(context.Context) error {
+	d.session.Close()
+	return nil
+}
+
+// Ping pings the Cassandra cluster.
+func (d *Driver) Ping(ctx context.Context) error {
+	return d.session.Query("SELECT release_version FROM system.local").WithContext(ctx).Exec()
+}
+
+// GetType returns Cassandra.
+func (*Driver) GetType() db.Type {
+	return db.Cassandra
+}
+
+// GetDB gets the database.
+// Cassandra doesn't support database/sql, so it returns nil for the callers that take an optional connection such as SQL review.
+func (*Driver) GetDB() *sql.DB {
+	return nil
+}
+
+// Execute executes the statements one by one, Cassandra doesn't support transactions.
+func (d *Driver) Execute(ctx context.Context, statement string, _ bool) (int64, error) {
+	statementList, err := cql.SplitStatements(statement)
+	if err != nil {
+		return 0, err
+	}
+	for _, stmt := range statementList {
+		if err := d.session.Query(stmt.Text).WithContext(ctx).Exec(); err != nil {
+			return 0, errors.Wrapf(err, "failed to execute statement at line %d", stmt.Line)
+		}
+	}
+	// Cassandra doesn't return the affected rows.
+	return 0, nil
+}
+
+// QueryConn executes the statement, returns the results.
+func (d *Driver) QueryConn(ctx context.Context, _ *sql.Conn, statement string, queryContext *db.QueryContext) ([]any, error) {
+	statementList, err := cql.SplitStatements(statement)
+	if err != nil {
+		return nil, err
+	}
+	if len(statementList) != 1 {
+		return nil, errors.Errorf("expect to get 1 statement, get %d", len(statementList))
+	}
+	stmt := statementList[0]
+	if queryContext != nil && queryContext.ReadOnly && stmt.Kind() != "SELECT" {
+		return nil, errors.Errorf("cassandra: only the SELECT statements are allowed, but got %s", stmt.Kind())
+	}
+
+	limit := 0
+	if queryContext != nil {
+		limit = queryContext.Limit
+	}
+	query := d.session.Query(stmt.Text).WithContext(ctx)
+	if limit > 0 {
+		query = query.PageSize(limit)
+	}
+	iter := query.Iter()
+
+	var columnNames, columnTypeNames []string
+	for _, column := range iter.Columns() {
+		columnNames = append(columnNames, column.Name)
+		columnTypeNames = append(columnTypeNames, strings.ToUpper(column.TypeInfo.Type().String()))
+	}
+	data := []any{}
+	for {
+		if limit > 0 && len(data) >= limit {
+			break
+		}
+		row := make(map[string]any)
+		if !iter.MapScan(row) {
+			break
+		}
+		var rowData []any
+		for _, name := range columnNames {
+			rowData = append(rowData, convertValue(row[name]))
+		}
+		data = append(data, rowData)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	// Cassandra doesn't mask the sensitive fields.
+	// Return the all false boolean slice here as the placeholder.
+	sensitiveInfo := make([]bool, len(columnNames))
+	return []any{columnNames, columnTypeNames, data, sensitiveInfo}, nil
+}
+
+// convertValue converts the values which cannot be marshalled to JSON properly.
+func convertValue(value any) any {
+	switch v := value.(type) {
+	case gocql.UUID:
+		return v.String()
+	case []byte:
+		return fmt.Sprintf("0x%x", v)
+	}
+	return value
+}
+
+// Dump and restore
+// Dump dumps the keyspace schema as the CQL statements.
+// Cassandra data is not dumped currently.
+func (d *Driver) Dump(ctx context.Context, out io.Writer, schemaOnly bool) (string, error) {
+	if !schemaOnly {
+		return "", errors.New("cassandra: not supported")
+	}
+	schema, err := d.getKeyspaceSchema(ctx, d.databaseName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(out, schema.dump()); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+// Restore the database from src, which is a full backup.
+func (*Driver) Restore(context.Context, io.Reader) error {
+	return errors.New("cassandra: not supported")
+}
@@ -0,0 +1,301 @@
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+var (
+	// unquotedIdentifierRegexp matches the identifiers which don't need the double quotes.
+	unquotedIdentifierRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+type keyspaceSchema struct {
+	name          string
+	durableWrites bool
+	replication   map[string]string
+	tables        []*tableSchema
+}
+
+type tableSchema struct {
+	name       string
+	comment    string
+	defaultTTL int
+	compaction map[string]string
+	// columns are ordered by the partition keys, the clustering columns and the other columns by name.
+	columns []*columnSchema
+	indexes []*indexSchema
+}
+
+type columnSchema struct {
+	name string
+	// kind is one of partition_key, clustering, static and regular.
+	kind            string
+	columnType      string
+	clusteringOrder string
+	position        int
+}
+
+type indexSchema struct {
+	name    string
+	kind    string
+	options map[string]string
+}
+
+// Sync schema
+
+// SyncInstance syncs the instance metadata.
+func (d *Driver) SyncInstance(ctx context.Context) (*db.InstanceMetadata, error) {
+	var version string
+	if err := d.session.Query("SELECT release_version FROM system.local").WithContext(ctx).Scan(&version); err != nil {
+		return nil, errors.Wrap(err, "failed to get server version")
+	}
+
+	var databases []*storepb.DatabaseMetadata
+	iter := d.session.Query("SELECT keyspace_name FROM system_schema.keyspaces").WithContext(ctx).Iter()
+	var keyspace string
+	for iter.Scan(&keyspace) {
+		// Skip the system keyspaces such as system, system_schema and system_auth.
+		if strings.HasPrefix(keyspace, "system") {
+			continue
+		}
+		databases = append(databases, &storepb.DatabaseMetadata{Name: keyspace})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to get keyspaces")
+	}
+	sort.Slice(databases, func(i, j int) bool {
+		return databases[i].Name < databases[j].Name
+	})
+
+	return &db.InstanceMetadata{
+		Version:   version,
+		Databases: databases,
+	}, nil
+}
+
+// SyncDBSchema syncs a single database schema.
+func (d *Driver) SyncDBSchema(ctx context.Context) (*storepb.DatabaseMetadata, error) {
+	schema, err := d.getKeyspaceSchema(ctx, d.databaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaMetadata := &storepb.SchemaMetadata{
+		Name: "",
+	}
+	for _, table := range schema.tables {
+		tableMetadata := &storepb.TableMetadata{
+			Name:    table.name,
+			Comment: table.comment,
+		}
+		primaryIndex := &storepb.IndexMetadata{
+			Name:    "PRIMARY",
+			Type:    "PRIMARY KEY",
+			Unique:  true,
+			Primary: true,
+			Visible: true,
+		}
+		for i, column := range table.columns {
+			tableMetadata.Columns = append(tableMetadata.Columns, &storepb.ColumnMetadata{
+				Name:     column.name,
+				Position: int32(i + 1),
+				Type:     column.columnType,
+				Nullable: column.kind == "regular" || column.kind == "static",
+			})
+			if column.kind == "partition_key" || column.kind == "clustering" {
+				primaryIndex.Expressions = append(primaryIndex.Expressions, column.name)
+			}
+		}
+		tableMetadata.Indexes = append(tableMetadata.Indexes, primaryIndex)
+		for _, index := range table.indexes {
+			tableMetadata.Indexes = append(tableMetadata.Indexes, &storepb.IndexMetadata{
+				Name:        index.name,
+				Expressions: []string{index.options["target"]},
+				Type:        index.kind,
+				Visible:     true,
+			})
+		}
+		schemaMetadata.Tables = append(schemaMetadata.Tables, tableMetadata)
+	}
+
+	return &storepb.DatabaseMetadata{
+		Name:    schema.name,
+		Schemas: []*storepb.SchemaMetadata{schemaMetadata},
+	}, nil
+}
+
+func (d *Driver) getKeyspaceSchema(ctx context.Context, keyspace string) (*keyspaceSchema, error) {
+	if keyspace == "" {
+		return nil, errors.New("cassandra: keyspace is required")
+	}
+	schema := &keyspaceSchema{name: keyspace}
+	if err := d.session.Query("SELECT durable_writes, replication FROM system_schema.keyspaces WHERE keyspace_name = ?", keyspace).WithContext(ctx).Scan(&schema.durableWrites, &schema.replication); err != nil {
+		return nil, errors.Wrapf(err, "failed to get keyspace %q", keyspace)
+	}
+
+	tableMap := make(map[string]*tableSchema)
+	tableIter := d.session.Query("SELECT table_name, comment, default_time_to_live, compaction FROM system_schema.tables WHERE keyspace_name = ?", keyspace).WithContext(ctx).Iter()
+	for {
+		table := &tableSchema{}
+		if !tableIter.Scan(&table.name, &table.comment, &table.defaultTTL, &table.compaction) {
+			break
+		}
+		tableMap[table.name] = table
+		schema.tables = append(schema.tables, table)
+	}
+	if err := tableIter.Close(); err != nil {
+		return nil, errors.Wrapf(err, "failed to get tables of keyspace %q", keyspace)
+	}
+	sort.Slice(schema.tables, func(i, j int) bool {
+		return schema.tables[i].name < schema.tables[j].name
+	})
+
+	columnIter := d.session.Query("SELECT table_name, column_name, kind, type, clustering_order, position FROM system_schema.columns WHERE keyspace_name = ?", keyspace).WithContext(ctx).Iter()
+	for {
+		var tableName string
+		column := &columnSchema{}
+		if !columnIter.Scan(&tableName, &column.name, &column.kind, &column.columnType, &column.clusteringOrder, &column.position) {
+			break
+		}
+		// The columns of the materialized views are also in system_schema.columns.
+		if table, ok := tableMap[tableName]; ok {
+			table.columns = append(table.columns, column)
+		}
+	}
+	if err := columnIter.Close(); err != nil {
+		return nil, errors.Wrapf(err, "failed to get columns of keyspace %q", keyspace)
+	}
+
+	indexIter := d.session.Query("SELECT table_name, index_name, kind, options FROM system_schema.indexes WHERE keyspace_name = ?", keyspace).WithContext(ctx).Iter()
+	for {
+		var tableName string
+		index := &indexSchema{}
+		if !indexIter.Scan(&tableName, &index.name, &index.kind, &index.options) {
+			break
+		}
+		if table, ok := tableMap[tableName]; ok {
+			table.indexes = append(table.indexes, index)
+		}
+	}
+	if err := indexIter.Close(); err != nil {
+		return nil, errors.Wrapf(err, "failed to get indexes of keyspace %q", keyspace)
+	}
+
+	for _, table := range schema.tables {
+		sortColumns(table.columns)
+		sort.Slice(table.indexes, func(i, j int) bool {
+			return table.indexes[i].name < table.indexes[j].name
+		})
+	}
+	return schema, nil
+}
+
+func sortColumns(columns []*columnSchema) {
+	kindOrder := map[string]int{
+		"partition_key": 0,
+		"clustering":    1,
+	}
+	rank := func(column *columnSchema) int {
+		if order, ok := kindOrder[column.kind]; ok {
+			return order
+		}
+		return 2
+	}
+	sort.SliceStable(columns, func(i, j int) bool {
+		if rank(columns[i]) != rank(columns[j]) {
+			return rank(columns[i]) < rank(columns[j])
+		}
+		if rank(columns[i]) < 2 {
+			return columns[i].position < columns[j].position
+		}
+		return columns[i].name < columns[j].name
+	})
+}
+
+// dump returns the CQL statements creating the keyspace, the tables and the indexes.
+func (s *keyspaceSchema) dump() string {
+	var buf strings.Builder
+	keyspace := quoteIdentifier(s.name)
+	fmt.Fprintf(&buf, "CREATE KEYSPACE %s WITH replication = %s AND durable_writes = %t;\n", keyspace, formatMap(s.replication), s.durableWrites)
+
+	for _, table := range s.tables {
+		var partitionKeys, clusteringColumns, clusteringOrders []string
+		fmt.Fprintf(&buf, "\nCREATE TABLE %s.%s (\n", keyspace, quoteIdentifier(table.name))
+		for _, column := range table.columns {
+			static := ""
+			switch column.kind {
+			case "partition_key":
+				partitionKeys = append(partitionKeys, quoteIdentifier(column.name))
+			case "clustering":
+				clusteringColumns = append(clusteringColumns, quoteIdentifier(column.name))
+				clusteringOrders = append(clusteringOrders, fmt.Sprintf("%s %s", quoteIdentifier(column.name), strings.ToUpper(column.clusteringOrder)))
+			case "static":
+				static = " static"
+			}
+			fmt.Fprintf(&buf, "    %s %s%s,\n", quoteIdentifier(column.name), column.columnType, static)
+		}
+		primaryKey := append([]string{fmt.Sprintf("(%s)", strings.Join(partitionKeys, ", "))}, clusteringColumns...)
+		fmt.Fprintf(&buf, "    PRIMARY KEY (%s)\n)", strings.Join(primaryKey, ", "))
+
+		var optionList []string
+		if len(clusteringOrders) > 0 {
+			optionList = append(optionList, fmt.Sprintf("CLUSTERING ORDER BY (%s)", strings.Join(clusteringOrders, ", ")))
+		}
+		optionList = append(optionList,
+			fmt.Sprintf("comment = '%s'", strings.ReplaceAll(table.comment, "'", "''")),
+			fmt.Sprintf("compaction = %s", formatMap(table.compaction)),
+			fmt.Sprintf("default_time_to_live = %d", table.defaultTTL),
+		)
+		fmt.Fprintf(&buf, " WITH %s;\n", strings.Join(optionList, "\n    AND "))
+
+		for _, index := range table.indexes {
+			if index.kind == "CUSTOM" {
+				fmt.Fprintf(&buf, "CREATE CUSTOM INDEX %s ON %s.%s (%s) USING '%s';\n", quoteIdentifier(index.name), keyspace, quoteIdentifier(table.name), index.options["target"], index.options["class_name"])
+				continue
+			}
+			fmt.Fprintf(&buf, "CREATE INDEX %s ON %s.%s (%s);\n", quoteIdentifier(index.name), keyspace, quoteIdentifier(table.name), index.options["target"])
+		}
+	}
+	return buf.String()
+}
+
+func quoteIdentifier(identifier string) string {
+	if unquotedIdentifierRegexp.MatchString(identifier) {
+		return identifier
+	}
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(identifier, `"`, `""`))
+}
+
+// formatMap formats the map literal with the sorted keys, e.g. {'class': 'SimpleStrategy', 'replication_factor': '3'}.
+func formatMap(m map[string]string) string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("'%s': '%s'", strings.ReplaceAll(key, "'", "''"), strings.ReplaceAll(m[key], "'", "''")))
+	}
+	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
+}
+
+// SyncSlowQuery syncs the slow query.
+func (*Driver) SyncSlowQuery(_ context.Context, _ time.Time) (map[string]*storepb.SlowQueryStatistics, error) {
+	return nil, errors.Errorf("not implemented")
+}
+
+// CheckSlowQueryLogEnabled checks if slow query log is enabled.
+func (*Driver) CheckSlowQueryLogEnabled(_ context.Context) error {
+	return errors.Errorf("not implemented")
+}
@@ -0,0 +1,52 @@
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpKeyspaceSchema(t *testing.T) {
+	columns := []*columnSchema{
+		{name: "payload", kind: "regular", columnType: "text", clusteringOrder: "none", position: -1},
+		{name: "ts", kind: "clustering", columnType: "timestamp", clusteringOrder: "desc", position: 0},
+		{name: "deviceId", kind: "partition_key", columnType: "uuid", clusteringOrder: "none", position: 0},
+		{name: "bucket", kind: "partition_key", columnType: "int", clusteringOrder: "none", position: 1},
+		{name: "owner", kind: "static", columnType: "text", clusteringOrder: "none", position: -1},
+	}
+	sortColumns(columns)
+	schema := &keyspaceSchema{
+		name:          "iot",
+		durableWrites: true,
+		replication:   map[string]string{"replication_factor": "3", "class": "org.apache.cassandra.locator.SimpleStrategy"},
+		tables: []*tableSchema{
+			{
+				name:       "events",
+				comment:    "device's events",
+				defaultTTL: 86400,
+				compaction: map[string]string{"class": "org.apache.cassandra.db.compaction.TimeWindowCompactionStrategy", "compaction_window_size": "1"},
+				columns:    columns,
+				indexes: []*indexSchema{
+					{name: "events_owner_idx", kind: "COMPOSITES", options: map[string]string{"target": "owner"}},
+				},
+			},
+		},
+	}
+
+	want := `CREATE KEYSPACE iot WITH replication = {'class': 'org.apache.cassandra.locator.SimpleStrategy', 'replication_factor': '3'} AND durable_writes = true;
+
+CREATE TABLE iot.events (
+    "deviceId" uuid,
+    bucket int,
+    ts timestamp,
+    owner text static,
+    payload text,
+    PRIMARY KEY (("deviceId", bucket), ts)
+) WITH CLUSTERING ORDER BY (ts DESC)
+    AND comment = 'device''s events'
+    AND compaction = {'class': 'org.apache.cassandra.db.compaction.TimeWindowCompactionStrategy', 'compaction_window_size': '1'}
+    AND default_time_to_live = 86400;
+CREATE INDEX events_owner_idx ON iot.events (owner);
+`
+	require.Equal(t, want, schema.dump())
+}
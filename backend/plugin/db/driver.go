@@ -51,6 +51,14 @@ const (
 	Redshift Type = "REDSHIFT"
 	// MariaDB is the database type for MariaDB.
 	MariaDB Type = "MARIADB"
+	// Elasticsearch is the database type for Elasticsearch and OpenSearch.
+	Elasticsearch Type = "ELASTICSEARCH"
+	// Cassandra is the database type for Cassandra.
+	Cassandra Type = "CASSANDRA"
+	// DynamoDB is the database type for Amazon DynamoDB.
+	DynamoDB Type = "DYNAMODB"
+	// Neo4j is the database type for Neo4j.
+	Neo4j Type = "NEO4J"
 	// UnknownType is the database type for UNKNOWN.
 	UnknownType Type = "UNKNOWN"
 
@@ -116,6 +124,10 @@ const (
 	VCS MigrationSource = "VCS"
 	// LIBRARY is the migration source type for LIBRARY.
 	LIBRARY MigrationSource = "LIBRARY"
+	// Flyway is the migration source type for the history imported from Flyway.
+	Flyway MigrationSource = "FLYWAY"
+	// Liquibase is the migration source type for the history imported from Liquibase.
+	Liquibase MigrationSource = "LIQUIBASE"
 )
 
 // MigrationType is the type of a migration.
@@ -156,6 +168,8 @@ const (
 // MigrationInfoPayload is the API message for migration info payload.
 type MigrationInfoPayload struct {
 	VCSPushEvent *vcs.PushEvent `json:"pushEvent,omitempty"`
+	// RollbackStatement is the generated statement reverting the schema change of the DDL migration.
+	RollbackStatement string `json:"rollbackStatement,omitempty"`
 }
 
 // MigrationInfo is the API message for migration info.
@@ -403,9 +417,73 @@ type ConnectionConfig struct {
 	// AuthenticationDatabase is only supported for MongoDB now.
 	AuthenticationDatabase string
 	// SID and ServiceName are Oracle only.
-	SID         string
-	ServiceName string
-	SSHConfig   SSHConfig
+	SID          string
+	ServiceName  string
+	SSHConfig    SSHConfig
+	SOCKS5Config SOCKS5Config
+	// AuthenticationType, AWSCredential and GCPCloudSQLConfig are only supported for MySQL and Postgres now.
+	// AzureCredential and KerberosConfig are only supported for MSSQL and Postgres now.
+	AuthenticationType AuthenticationType
+	AWSCredential      AWSCredential
+	GCPCloudSQLConfig  GCPCloudSQLConfig
+	AzureCredential    AzureCredential
+	KerberosConfig     KerberosConfig
+}
+
+// AuthenticationType is the type of authentication for connection.
+type AuthenticationType string
+
+const (
+	// AuthenticationTypePassword authenticates with the password, it's the default.
+	AuthenticationTypePassword AuthenticationType = "PASSWORD"
+	// AuthenticationTypeAWSRDSIAM authenticates with the AWS RDS IAM auth token generated at connect time.
+	AuthenticationTypeAWSRDSIAM AuthenticationType = "AWS_RDS_IAM"
+	// AuthenticationTypeGCPCloudSQLIAM authenticates with the IAM principal through the Cloud SQL connector.
+	AuthenticationTypeGCPCloudSQLIAM AuthenticationType = "GCP_CLOUD_SQL_IAM"
+	// AuthenticationTypeAzureAD authenticates with the Azure AD (Microsoft Entra ID) access token.
+	AuthenticationTypeAzureAD AuthenticationType = "AZURE_AD"
+	// AuthenticationTypeKerberos authenticates with the Kerberos (GSSAPI) ticket of the principal in the keytab.
+	AuthenticationTypeKerberos AuthenticationType = "KERBEROS"
+)
+
+// AWSCredential is the AWS credential to generate the RDS IAM auth token.
+// The default credential chain, such as the instance role, is used if AccessKeyID is empty.
+type AWSCredential struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// GCPCloudSQLConfig is the config to connect to the GCP Cloud SQL instance through the Cloud SQL connector,
+// which handles the TLS with the ephemeral certificate, so the host, port and TLS config are ignored.
+type GCPCloudSQLConfig struct {
+	// ConnectionName is the instance connection name in the form of "project:region:instance".
+	// The connector is not used if it's empty.
+	ConnectionName string
+	// UsePrivateIP connects with the private IP instead of the public IP.
+	UsePrivateIP bool
+	// CredentialsJSON is the service account key, the application default credentials are used if it's empty.
+	CredentialsJSON string
+}
+
+// AzureCredential is the Azure AD credential to get the access token.
+// The service principal is used if ClientSecret is set, otherwise the managed identity is used,
+// which is the user-assigned identity of ClientID, or the system-assigned identity if ClientID is empty.
+type AzureCredential struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+}
+
+// KerberosConfig is the Kerberos configuration to get the service ticket with the keytab.
+// The principal is the username in the realm, and the KDCs are looked up in the DNS if KDC is empty.
+// SPN defaults to "postgres/<host>" for Postgres and "MSSQLSvc/<host>:<port>" for MSSQL.
+type KerberosConfig struct {
+	Realm string
+	// KDC is the comma-separated KDC addresses in the form of "host[:port]".
+	KDC    string
+	SPN    string
+	Keytab []byte
 }
 
 // SSHConfig is the configuration for connection over SSH.
@@ -415,6 +493,17 @@ type SSHConfig struct {
 	User       string
 	Password   string
 	PrivateKey string
+	// JumpHostList is the list of SSH bastions hopped through in order before reaching Host.
+	JumpHostList []SSHConfig
+}
+
+// SOCKS5Config is the configuration for connection over a SOCKS5 proxy.
+// If SSH is also configured, only the first SSH hop is dialed through the proxy.
+type SOCKS5Config struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
 }
 
 // ConnectionContext is the context for connection.
@@ -436,6 +525,12 @@ type QueryContext struct {
 	CurrentDatabase string
 }
 
+// SessionVariable is a session variable set on the connection before the queries.
+type SessionVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
 // DatabaseRoleMessage is the API message for database role.
 type DatabaseRoleMessage struct {
 	// The role unique name.
@@ -512,6 +607,18 @@ type Driver interface {
 	Restore(ctx context.Context, src io.Reader) error
 }
 
+// UnwrapDriver returns the underlying driver of the ones wrapping another driver, such as the pooled drivers.
+// Use it before asserting the engine specific driver type.
+func UnwrapDriver(driver Driver) Driver {
+	for {
+		wrapper, ok := driver.(interface{ Unwrap() Driver })
+		if !ok {
+			return driver
+		}
+		driver = wrapper.Unwrap()
+	}
+}
+
 // Register makes a database driver available by the provided type.
 // If Register is called twice with the same name or if driver is nil,
 // it panics.
@@ -579,11 +686,32 @@ const (
 	// SensitiveDataMaskTypeDefault is the sensitive data type to hide data with a default method.
 	// The default method is subject to change.
 	SensitiveDataMaskTypeDefault SensitiveDataMaskType = "DEFAULT"
+	// SensitiveDataMaskTypeSHA256 is the sensitive data type to hide data with the hex of the salted SHA-256.
+	SensitiveDataMaskTypeSHA256 SensitiveDataMaskType = "SHA256"
+	// SensitiveDataMaskTypeHMAC is the sensitive data type to hide data with the hex of the HMAC-SHA256.
+	SensitiveDataMaskTypeHMAC SensitiveDataMaskType = "HMAC"
+	// SensitiveDataMaskTypeFF1 is the sensitive data type to encrypt the digits and letters of data with FF1, keeping
+	// the format.
+	SensitiveDataMaskTypeFF1 SensitiveDataMaskType = "FF1"
+	// SensitiveDataMaskTypeFF3 is the sensitive data type to encrypt the digits and letters of data with FF3-1,
+	// keeping the format.
+	SensitiveDataMaskTypeFF3 SensitiveDataMaskType = "FF3"
 )
 
 // SensitiveSchemaInfo is the schema info using to extract sensitive fields.
 type SensitiveSchemaInfo struct {
 	DatabaseList []DatabaseSchema
+	// MaskingSecret is the secret the keys of the deterministic mask types are derived from.
+	MaskingSecret string
+	// Requester is the principal querying, whom the mask conditions are evaluated for.
+	Requester *MaskingRequester
+}
+
+// MaskingRequester is the principal querying the sensitive data.
+type MaskingRequester struct {
+	Email string
+	Name  string
+	Role  string
 }
 
 // DatabaseSchema is the database schema using to extract sensitive fields.
@@ -602,10 +730,44 @@ type TableSchema struct {
 type ColumnInfo struct {
 	Name      string
 	Sensitive bool
+	// MaskType is the algorithm masking the sensitive column, empty means the default.
+	MaskType SensitiveDataMaskType
+	// MaskCondition is the CEL expression over the row and the requester deciding whether to mask the value of the
+	// sensitive column, empty means always masked.
+	MaskCondition string
 }
 
 // SensitiveField is the struct about SELECT fields.
 type SensitiveField struct {
 	Name      string
 	Sensitive bool
+	// MaskType is the algorithm masking the sensitive field. It's only kept for the fields selecting the sensitive
+	// columns as is, the fields derived from the sensitive columns are masked with the default algorithm.
+	MaskType SensitiveDataMaskType
+	// MaskCondition is the condition masking the sensitive field. It's kept in the same way as the MaskType, the
+	// fields derived from the sensitive columns are always masked.
+	MaskCondition string
+}
+
+// TableRowLevelSecurity is the row level security of a PostgreSQL table, which isn't in the database metadata yet.
+type TableRowLevelSecurity struct {
+	Schema  string `json:"schema"`
+	Table   string `json:"table"`
+	Enabled bool   `json:"enabled"`
+	// Forced applies the policies to the table owner as well.
+	Forced     bool                      `json:"forced"`
+	PolicyList []*RowLevelSecurityPolicy `json:"policyList"`
+}
+
+// RowLevelSecurityPolicy is a row level security policy of a table.
+type RowLevelSecurityPolicy struct {
+	Name string `json:"name"`
+	// Command is ALL, SELECT, INSERT, UPDATE or DELETE.
+	Command string `json:"command"`
+	// Permissive is false for the restrictive policies, which are combined with AND.
+	Permissive bool     `json:"permissive"`
+	RoleList   []string `json:"roleList"`
+	// Using and WithCheck are the expressions of the policy, empty if absent.
+	Using     string `json:"using"`
+	WithCheck string `json:"withCheck"`
 }
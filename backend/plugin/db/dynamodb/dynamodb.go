@@ -0,0 +1,284 @@
+// Package dynamodb implements the Amazon DynamoDB driver, which executes the PartiQL statements.
+package dynamodb
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	awscredentials "github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/partiql"
+)
+
+var (
+	_ db.Driver = (*Driver)(nil)
+)
+
+func init() {
+	db.Register(db.DynamoDB, newDriver)
+}
+
+// Driver is the DynamoDB driver.
+type Driver struct {
+	client *dynamodb.Client
+	// region is also the database name, because the DynamoDB tables are scoped by the region.
+	region string
+}
+
+func newDriver(_ db.DriverConfig) db.Driver {
+	return &Driver{}
+}
+
+// Open opens the DynamoDB driver.
+// The host is the region, e.g. us-east-1, or the endpoint URL such as http://localhost:8000 for DynamoDB local.
+// The username and password are the access key ID and the secret access key, the default credential chain is used if they are empty.
+func (d *Driver) Open(ctx context.Context, _ db.Type, config db.ConnectionConfig, _ db.ConnectionContext) (db.Driver, error) {
+	region, endpoint := config.Host, ""
+	if strings.HasPrefix(config.Host, "http://") || strings.HasPrefix(config.Host, "https://") {
+		endpoint = config.Host
+		if config.Port != "" {
+			endpoint = endpoint + ":" + config.Port
+		}
+		// The region is required by the SDK even for DynamoDB local.
+		region = config.Database
+		if region == "" {
+			region = "us-east-1"
+		}
+	}
+
+	optFns := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithRegion(region),
+	}
+	if config.Username != "" {
+		optFns = append(optFns, awsconfig.WithCredentialsProvider(awscredentials.NewStaticCredentialsProvider(config.Username, config.Password, "")))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, errors.Wrap(err, "dynamodb: failed to load AWS config")
+	}
+	d.client = dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		if endpoint != "" {
+			o.EndpointResolver = dynamodb.EndpointResolverFromURL(endpoint)
+		}
+	})
+	d.region = region
+	return d, nil
+}
+
+// Close closes the DynamoDB driver.
+func (*Driver) Close(context.Context) error {
+	return nil
+}
+
+// Ping pings DynamoDB by listing one table.
+func (d *Driver) Ping(ctx context.Context) error {
+	_, err := d.client.ListTables(ctx, &dynamodb.ListTablesInput{Limit: aws.Int32(1)})
+	return err
+}
+
+// GetType returns DynamoDB.
+func (*Driver) GetType() db.Type {
+	return db.DynamoDB
+}
+
+// GetDB gets the database.
+// DynamoDB doesn't support database/sql, so it returns nil for the callers that take an optional connection such as SQL review.
+func (*Driver) GetDB() *sql.DB {
+	return nil
+}
+
+// Execute executes the PartiQL statements one by one.
+func (d *Driver) Execute(ctx context.Context, statement string, createDatabase bool) (int64, error) {
+	if createDatabase {
+		return 0, errors.New("dynamodb: cannot create database")
+	}
+	statementList, err := partiql.SplitStatements(statement)
+	if err != nil {
+		return 0, err
+	}
+	var affectedRows int64
+	for _, stmt := range statementList {
+		if _, err := d.client.ExecuteStatement(ctx, &dynamodb.ExecuteStatementInput{Statement: aws.String(stmt.Text)}); err != nil {
+			return affectedRows, errors.Wrapf(err, "failed to execute statement at line %d", stmt.Line)
+		}
+		// The INSERT, UPDATE and DELETE statements change a single item.
+		if stmt.Kind() != "SELECT" {
+			affectedRows++
+		}
+	}
+	return affectedRows, nil
+}
+
+// QueryConn executes the statement, returns the results.
+func (d *Driver) QueryConn(ctx context.Context, _ *sql.Conn, statement string, queryContext *db.QueryContext) ([]any, error) {
+	statementList, err := partiql.SplitStatements(statement)
+	if err != nil {
+		return nil, err
+	}
+	if len(statementList) != 1 {
+		return nil, errors.Errorf("expect to get 1 statement, get %d", len(statementList))
+	}
+	stmt := statementList[0]
+	if queryContext != nil && queryContext.ReadOnly && stmt.Kind() != "SELECT" {
+		return nil, errors.Errorf("dynamodb: only the SELECT statements are allowed, but got %s", stmt.Kind())
+	}
+	limit := 0
+	if queryContext != nil {
+		limit = queryContext.Limit
+	}
+
+	var items []map[string]types.AttributeValue
+	input := &dynamodb.ExecuteStatementInput{Statement: aws.String(stmt.Text)}
+	for {
+		output, err := d.client.ExecuteStatement(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, output.Items...)
+		if output.NextToken == nil || (limit > 0 && len(items) >= limit) {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+
+	// The items are schemaless, so the columns are the union of the attribute names.
+	columnSet := make(map[string]bool)
+	for _, item := range items {
+		for name := range item {
+			columnSet[name] = true
+		}
+	}
+	var columnNames []string
+	for name := range columnSet {
+		columnNames = append(columnNames, name)
+	}
+	sort.Strings(columnNames)
+	columnTypeNames := make([]string, len(columnNames))
+	data := []any{}
+	for _, item := range items {
+		var row []any
+		for i, name := range columnNames {
+			value, ok := item[name]
+			if !ok {
+				row = append(row, nil)
+				continue
+			}
+			if columnTypeNames[i] == "" {
+				columnTypeNames[i] = attributeValueType(value)
+			}
+			row = append(row, convertAttributeValue(value))
+		}
+		data = append(data, row)
+	}
+
+	// DynamoDB doesn't mask the sensitive fields.
+	// Return the all false boolean slice here as the placeholder.
+	sensitiveInfo := make([]bool, len(columnNames))
+	return []any{columnNames, columnTypeNames, data, sensitiveInfo}, nil
+}
+
+// attributeValueType returns the DynamoDB data type descriptor, e.g. S, N and M.
+func attributeValueType(value types.AttributeValue) string {
+	switch value.(type) {
+	case *types.AttributeValueMemberS:
+		return "S"
+	case *types.AttributeValueMemberN:
+		return "N"
+	case *types.AttributeValueMemberB:
+		return "B"
+	case *types.AttributeValueMemberBOOL:
+		return "BOOL"
+	case *types.AttributeValueMemberNULL:
+		return "NULL"
+	case *types.AttributeValueMemberL:
+		return "L"
+	case *types.AttributeValueMemberM:
+		return "M"
+	case *types.AttributeValueMemberSS:
+		return "SS"
+	case *types.AttributeValueMemberNS:
+		return "NS"
+	case *types.AttributeValueMemberBS:
+		return "BS"
+	}
+	return ""
+}
+
+// convertAttributeValue converts the attribute value to the JSON value, the numbers are kept as strings to avoid losing the precision.
+func convertAttributeValue(value types.AttributeValue) any {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberS:
+		return v.Value
+	case *types.AttributeValueMemberN:
+		return v.Value
+	case *types.AttributeValueMemberB:
+		return v.Value
+	case *types.AttributeValueMemberBOOL:
+		return v.Value
+	case *types.AttributeValueMemberNULL:
+		return nil
+	case *types.AttributeValueMemberL:
+		list := make([]any, 0, len(v.Value))
+		for _, item := range v.Value {
+			list = append(list, convertAttributeValue(item))
+		}
+		return list
+	case *types.AttributeValueMemberM:
+		m := make(map[string]any, len(v.Value))
+		for key, item := range v.Value {
+			m[key] = convertAttributeValue(item)
+		}
+		return m
+	case *types.AttributeValueMemberSS:
+		return v.Value
+	case *types.AttributeValueMemberNS:
+		return v.Value
+	case *types.AttributeValueMemberBS:
+		return v.Value
+	}
+	return nil
+}
+
+// Dump and restore
+// Dump dumps the table definitions as JSON in the shape of the CreateTable requests sorted by the table name,
+// so that the dumps can be compared for the schema drift.
+// DynamoDB data is not dumped currently.
+func (d *Driver) Dump(ctx context.Context, out io.Writer, schemaOnly bool) (string, error) {
+	if !schemaOnly {
+		return "", errors.New("dynamodb: not supported")
+	}
+	tables, err := d.describeTables(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, table := range tables {
+		content, err := dumpTable(table)
+		if err != nil {
+			return "", err
+		}
+		if _, err := out.Write(content); err != nil {
+			return "", err
+		}
+		if _, err := io.WriteString(out, "\n\n"); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// Restore the database from src, which is a full backup.
+func (*Driver) Restore(context.Context, io.Reader) error {
+	return errors.New("dynamodb: not supported")
+}
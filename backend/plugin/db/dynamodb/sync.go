@@ -0,0 +1,301 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// tableDefinition is the table description with the time to live setting, which is described by another API.
+type tableDefinition struct {
+	description *types.TableDescription
+	ttl         *types.TimeToLiveDescription
+}
+
+// tableSpec is the stable table definition for the schema dump, the runtime fields such as the item count and the status are excluded.
+type tableSpec struct {
+	TableName               string           `json:"TableName"`
+	AttributeDefinitions    []*attributeSpec `json:"AttributeDefinitions"`
+	KeySchema               []*keySpec       `json:"KeySchema"`
+	BillingMode             string           `json:"BillingMode"`
+	ProvisionedThroughput   *throughputSpec  `json:"ProvisionedThroughput,omitempty"`
+	GlobalSecondaryIndexes  []*indexSpec     `json:"GlobalSecondaryIndexes,omitempty"`
+	LocalSecondaryIndexes   []*indexSpec     `json:"LocalSecondaryIndexes,omitempty"`
+	StreamSpecification     *streamSpec      `json:"StreamSpecification,omitempty"`
+	TableClass              string           `json:"TableClass,omitempty"`
+	TimeToLiveSpecification *ttlSpec         `json:"TimeToLiveSpecification,omitempty"`
+}
+
+type attributeSpec struct {
+	AttributeName string `json:"AttributeName"`
+	AttributeType string `json:"AttributeType"`
+}
+
+type keySpec struct {
+	AttributeName string `json:"AttributeName"`
+	KeyType       string `json:"KeyType"`
+}
+
+type throughputSpec struct {
+	ReadCapacityUnits  int64 `json:"ReadCapacityUnits"`
+	WriteCapacityUnits int64 `json:"WriteCapacityUnits"`
+}
+
+type indexSpec struct {
+	IndexName             string          `json:"IndexName"`
+	KeySchema             []*keySpec      `json:"KeySchema"`
+	Projection            *projectionSpec `json:"Projection"`
+	ProvisionedThroughput *throughputSpec `json:"ProvisionedThroughput,omitempty"`
+}
+
+type projectionSpec struct {
+	ProjectionType   string   `json:"ProjectionType"`
+	NonKeyAttributes []string `json:"NonKeyAttributes,omitempty"`
+}
+
+type streamSpec struct {
+	StreamEnabled  bool   `json:"StreamEnabled"`
+	StreamViewType string `json:"StreamViewType,omitempty"`
+}
+
+type ttlSpec struct {
+	AttributeName string `json:"AttributeName"`
+	Enabled       bool   `json:"Enabled"`
+}
+
+// Sync schema
+
+// SyncInstance syncs the instance metadata.
+// DynamoDB doesn't have the databases and the versions, so the tables in the region are synced as a single database named after the region.
+func (d *Driver) SyncInstance(context.Context) (*db.InstanceMetadata, error) {
+	return &db.InstanceMetadata{
+		Databases: []*storepb.DatabaseMetadata{
+			{Name: d.region},
+		},
+	}, nil
+}
+
+// SyncDBSchema syncs a single database schema.
+func (d *Driver) SyncDBSchema(ctx context.Context) (*storepb.DatabaseMetadata, error) {
+	tables, err := d.describeTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaMetadata := &storepb.SchemaMetadata{
+		Name: "",
+	}
+	for _, table := range tables {
+		description := table.description
+		tableMetadata := &storepb.TableMetadata{
+			Name:     aws.ToString(description.TableName),
+			RowCount: aws.ToInt64(description.ItemCount),
+			DataSize: aws.ToInt64(description.TableSizeBytes),
+		}
+		// Only the key attributes of the table and the indexes are defined, the other attributes are schemaless.
+		for i, attribute := range sortedAttributes(description.AttributeDefinitions) {
+			tableMetadata.Columns = append(tableMetadata.Columns, &storepb.ColumnMetadata{
+				Name:     attribute.AttributeName,
+				Position: int32(i + 1),
+				Type:     attribute.AttributeType,
+			})
+		}
+		tableMetadata.Indexes = append(tableMetadata.Indexes, &storepb.IndexMetadata{
+			Name:        "PRIMARY",
+			Expressions: keyAttributeNames(description.KeySchema),
+			Type:        "PRIMARY KEY",
+			Unique:      true,
+			Primary:     true,
+			Visible:     true,
+		})
+		for _, index := range description.GlobalSecondaryIndexes {
+			tableMetadata.Indexes = append(tableMetadata.Indexes, &storepb.IndexMetadata{
+				Name:        aws.ToString(index.IndexName),
+				Expressions: keyAttributeNames(index.KeySchema),
+				Type:        "GLOBAL",
+				Visible:     true,
+			})
+		}
+		for _, index := range description.LocalSecondaryIndexes {
+			tableMetadata.Indexes = append(tableMetadata.Indexes, &storepb.IndexMetadata{
+				Name:        aws.ToString(index.IndexName),
+				Expressions: keyAttributeNames(index.KeySchema),
+				Type:        "LOCAL",
+				Visible:     true,
+			})
+		}
+		schemaMetadata.Tables = append(schemaMetadata.Tables, tableMetadata)
+	}
+
+	return &storepb.DatabaseMetadata{
+		Name:    d.region,
+		Schemas: []*storepb.SchemaMetadata{schemaMetadata},
+	}, nil
+}
+
+// describeTables returns the definitions of all the tables sorted by the table name.
+func (d *Driver) describeTables(ctx context.Context) ([]*tableDefinition, error) {
+	var tableNames []string
+	paginator := dynamodb.NewListTablesPaginator(d.client, &dynamodb.ListTablesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list tables")
+		}
+		tableNames = append(tableNames, output.TableNames...)
+	}
+	sort.Strings(tableNames)
+
+	var tables []*tableDefinition
+	for _, tableName := range tableNames {
+		tableOutput, err := d.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe table %q", tableName)
+		}
+		ttlOutput, err := d.client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String(tableName)})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe time to live of table %q", tableName)
+		}
+		tables = append(tables, &tableDefinition{
+			description: tableOutput.Table,
+			ttl:         ttlOutput.TimeToLiveDescription,
+		})
+	}
+	return tables, nil
+}
+
+// dumpTable returns the indented JSON of the table definition.
+func dumpTable(table *tableDefinition) ([]byte, error) {
+	description := table.description
+	spec := &tableSpec{
+		TableName:            aws.ToString(description.TableName),
+		AttributeDefinitions: sortedAttributes(description.AttributeDefinitions),
+		KeySchema:            convertKeySchema(description.KeySchema),
+		// The tables created before the on-demand mode was introduced don't have the billing mode summary.
+		BillingMode: string(types.BillingModeProvisioned),
+	}
+	if description.BillingModeSummary != nil && description.BillingModeSummary.BillingMode != "" {
+		spec.BillingMode = string(description.BillingModeSummary.BillingMode)
+	}
+	provisioned := spec.BillingMode == string(types.BillingModeProvisioned)
+	if provisioned {
+		spec.ProvisionedThroughput = convertThroughput(description.ProvisionedThroughput)
+	}
+	for _, index := range description.GlobalSecondaryIndexes {
+		indexSpec := &indexSpec{
+			IndexName:  aws.ToString(index.IndexName),
+			KeySchema:  convertKeySchema(index.KeySchema),
+			Projection: convertProjection(index.Projection),
+		}
+		if provisioned {
+			indexSpec.ProvisionedThroughput = convertThroughput(index.ProvisionedThroughput)
+		}
+		spec.GlobalSecondaryIndexes = append(spec.GlobalSecondaryIndexes, indexSpec)
+	}
+	for _, index := range description.LocalSecondaryIndexes {
+		spec.LocalSecondaryIndexes = append(spec.LocalSecondaryIndexes, &indexSpec{
+			IndexName:  aws.ToString(index.IndexName),
+			KeySchema:  convertKeySchema(index.KeySchema),
+			Projection: convertProjection(index.Projection),
+		})
+	}
+	sortIndexes(spec.GlobalSecondaryIndexes)
+	sortIndexes(spec.LocalSecondaryIndexes)
+	if stream := description.StreamSpecification; stream != nil && aws.ToBool(stream.StreamEnabled) {
+		spec.StreamSpecification = &streamSpec{
+			StreamEnabled:  true,
+			StreamViewType: string(stream.StreamViewType),
+		}
+	}
+	if description.TableClassSummary != nil && description.TableClassSummary.TableClass != types.TableClassStandard {
+		spec.TableClass = string(description.TableClassSummary.TableClass)
+	}
+	if table.ttl != nil && table.ttl.TimeToLiveStatus == types.TimeToLiveStatusEnabled {
+		spec.TimeToLiveSpecification = &ttlSpec{
+			AttributeName: aws.ToString(table.ttl.AttributeName),
+			Enabled:       true,
+		}
+	}
+	return json.MarshalIndent(spec, "", "  ")
+}
+
+func sortedAttributes(attributes []types.AttributeDefinition) []*attributeSpec {
+	var result []*attributeSpec
+	for _, attribute := range attributes {
+		result = append(result, &attributeSpec{
+			AttributeName: aws.ToString(attribute.AttributeName),
+			AttributeType: string(attribute.AttributeType),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].AttributeName < result[j].AttributeName
+	})
+	return result
+}
+
+// convertKeySchema keeps the order of the key schema, which is the partition key followed by the sort key.
+func convertKeySchema(keySchema []types.KeySchemaElement) []*keySpec {
+	var result []*keySpec
+	for _, key := range keySchema {
+		result = append(result, &keySpec{
+			AttributeName: aws.ToString(key.AttributeName),
+			KeyType:       string(key.KeyType),
+		})
+	}
+	return result
+}
+
+func keyAttributeNames(keySchema []types.KeySchemaElement) []string {
+	var result []string
+	for _, key := range keySchema {
+		result = append(result, aws.ToString(key.AttributeName))
+	}
+	return result
+}
+
+func convertThroughput(throughput *types.ProvisionedThroughputDescription) *throughputSpec {
+	if throughput == nil {
+		return nil
+	}
+	return &throughputSpec{
+		ReadCapacityUnits:  aws.ToInt64(throughput.ReadCapacityUnits),
+		WriteCapacityUnits: aws.ToInt64(throughput.WriteCapacityUnits),
+	}
+}
+
+func convertProjection(projection *types.Projection) *projectionSpec {
+	if projection == nil {
+		return nil
+	}
+	nonKeyAttributes := append([]string{}, projection.NonKeyAttributes...)
+	sort.Strings(nonKeyAttributes)
+	return &projectionSpec{
+		ProjectionType:   string(projection.ProjectionType),
+		NonKeyAttributes: nonKeyAttributes,
+	}
+}
+
+func sortIndexes(indexes []*indexSpec) {
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].IndexName < indexes[j].IndexName
+	})
+}
+
+// SyncSlowQuery syncs the slow query.
+func (*Driver) SyncSlowQuery(_ context.Context, _ time.Time) (map[string]*storepb.SlowQueryStatistics, error) {
+	return nil, errors.Errorf("not implemented")
+}
+
+// CheckSlowQueryLogEnabled checks if slow query log is enabled.
+func (*Driver) CheckSlowQueryLogEnabled(_ context.Context) error {
+	return errors.Errorf("not implemented")
+}
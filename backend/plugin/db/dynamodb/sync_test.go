@@ -0,0 +1,95 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpTable(t *testing.T) {
+	table := &tableDefinition{
+		description: &types.TableDescription{
+			TableName: aws.String("Music"),
+			AttributeDefinitions: []types.AttributeDefinition{
+				{AttributeName: aws.String("SongTitle"), AttributeType: types.ScalarAttributeTypeS},
+				{AttributeName: aws.String("Artist"), AttributeType: types.ScalarAttributeTypeS},
+				{AttributeName: aws.String("Genre"), AttributeType: types.ScalarAttributeTypeS},
+			},
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("Artist"), KeyType: types.KeyTypeHash},
+				{AttributeName: aws.String("SongTitle"), KeyType: types.KeyTypeRange},
+			},
+			BillingModeSummary: &types.BillingModeSummary{BillingMode: types.BillingModePayPerRequest},
+			// The provisioned throughput of the on-demand tables is zero and ignored.
+			ProvisionedThroughput: &types.ProvisionedThroughputDescription{ReadCapacityUnits: aws.Int64(0), WriteCapacityUnits: aws.Int64(0)},
+			GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+				{
+					IndexName:  aws.String("GenreIndex"),
+					KeySchema:  []types.KeySchemaElement{{AttributeName: aws.String("Genre"), KeyType: types.KeyTypeHash}},
+					Projection: &types.Projection{ProjectionType: types.ProjectionTypeInclude, NonKeyAttributes: []string{"Year", "Album"}},
+					ItemCount:  aws.Int64(42),
+				},
+			},
+			ItemCount:      aws.Int64(42),
+			TableSizeBytes: aws.Int64(1024),
+			TableStatus:    types.TableStatusActive,
+		},
+		ttl: &types.TimeToLiveDescription{AttributeName: aws.String("ExpiresAt"), TimeToLiveStatus: types.TimeToLiveStatusEnabled},
+	}
+
+	want := `{
+  "TableName": "Music",
+  "AttributeDefinitions": [
+    {
+      "AttributeName": "Artist",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "Genre",
+      "AttributeType": "S"
+    },
+    {
+      "AttributeName": "SongTitle",
+      "AttributeType": "S"
+    }
+  ],
+  "KeySchema": [
+    {
+      "AttributeName": "Artist",
+      "KeyType": "HASH"
+    },
+    {
+      "AttributeName": "SongTitle",
+      "KeyType": "RANGE"
+    }
+  ],
+  "BillingMode": "PAY_PER_REQUEST",
+  "GlobalSecondaryIndexes": [
+    {
+      "IndexName": "GenreIndex",
+      "KeySchema": [
+        {
+          "AttributeName": "Genre",
+          "KeyType": "HASH"
+        }
+      ],
+      "Projection": {
+        "ProjectionType": "INCLUDE",
+        "NonKeyAttributes": [
+          "Album",
+          "Year"
+        ]
+      }
+    }
+  ],
+  "TimeToLiveSpecification": {
+    "AttributeName": "ExpiresAt",
+    "Enabled": true
+  }
+}`
+	got, err := dumpTable(table)
+	require.NoError(t, err)
+	require.Equal(t, want, string(got))
+}
@@ -0,0 +1,252 @@
+// Package elasticsearch implements the Elasticsearch driver, which also works for OpenSearch.
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/elasticsearch"
+)
+
+var (
+	_ db.Driver = (*Driver)(nil)
+)
+
+func init() {
+	db.Register(db.Elasticsearch, newDriver)
+}
+
+// Driver is the Elasticsearch driver.
+type Driver struct {
+	client       *http.Client
+	baseURL      string
+	username     string
+	password     string
+	databaseName string
+}
+
+func newDriver(_ db.DriverConfig) db.Driver {
+	return &Driver{}
+}
+
+// Open opens the Elasticsearch driver.
+func (d *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionConfig, _ db.ConnectionContext) (db.Driver, error) {
+	port := config.Port
+	if port == "" {
+		port = "9200"
+	}
+	tlsConfig, err := config.TLSConfig.GetSslConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "elasticsearch: failed to get tls config")
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+	d.baseURL = fmt.Sprintf("%s://%s:%s", scheme, config.Host, port)
+	d.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+	d.username = config.Username
+	d.password = config.Password
+	// Elasticsearch has no databases, so the whole cluster is synced as a single database named after the cluster.
+	d.databaseName = config.Database
+	return d, nil
+}
+
+// Close closes the Elasticsearch driver.
+func (d *Driver) Close(context.Context) error {
+	d.client.CloseIdleConnections()
+	return nil
+}
+
+// Ping pings the Elasticsearch cluster.
+func (d *Driver) Ping(ctx context.Context) error {
+	_, err := d.do(ctx, http.MethodGet, "/", "")
+	return err
+}
+
+// GetType returns Elasticsearch.
+func (*Driver) GetType() db.Type {
+	return db.Elasticsearch
+}
+
+// GetDB gets the database.
+// Elasticsearch doesn't support database/sql, so it returns nil for the callers that take an optional connection such as SQL review.
+func (*Driver) GetDB() *sql.DB {
+	return nil
+}
+
+// Execute executes the requests in the Kibana console syntax one by one, and stops at the first failed request.
+func (d *Driver) Execute(ctx context.Context, statement string, createDatabase bool) (int64, error) {
+	if createDatabase {
+		return 0, errors.New("elasticsearch: cannot create database")
+	}
+
+	requests, err := parser.ParseRequests(statement)
+	if err != nil {
+		return 0, err
+	}
+	var affectedRows int64
+	for _, request := range requests {
+		body, err := d.do(ctx, request.Method, request.Path, request.Body)
+		if err != nil {
+			return affectedRows, errors.Wrapf(err, "failed to execute request %s %s at line %d", request.Method, request.Path, request.Line)
+		}
+		result, err := checkResponse(body)
+		if err != nil {
+			return affectedRows, errors.Wrapf(err, "failed to execute request %s %s at line %d", request.Method, request.Path, request.Line)
+		}
+		affectedRows += result.Deleted + result.Updated + result.Created
+	}
+	return affectedRows, nil
+}
+
+// QueryConn executes the requests, returns the response bodies.
+func (d *Driver) QueryConn(ctx context.Context, _ *sql.Conn, statement string, queryContext *db.QueryContext) ([]any, error) {
+	requests, err := parser.ParseRequests(statement)
+	if err != nil {
+		return nil, err
+	}
+	if queryContext != nil && queryContext.ReadOnly {
+		for _, request := range requests {
+			if !request.IsReadOnly() {
+				return nil, errors.Errorf("elasticsearch: only the read-only requests are allowed, but got %s %s at line %d", request.Method, request.Path, request.Line)
+			}
+		}
+	}
+
+	var data []any
+	for _, request := range requests {
+		body, err := d.do(ctx, request.Method, request.Path, request.Body)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, []any{string(body)})
+	}
+
+	return []any{[]string{"result"}, []string{"TEXT"}, data}, nil
+}
+
+// Dump and restore
+// Dump dumps the index mappings as the PUT requests sorted by the index name, so that the dumps can be compared for the schema drift.
+// Elasticsearch data is not dumped currently.
+func (d *Driver) Dump(ctx context.Context, out io.Writer, schemaOnly bool) (string, error) {
+	if !schemaOnly {
+		return "", errors.New("elasticsearch: not supported")
+	}
+
+	mappings, err := d.getMappings(ctx)
+	if err != nil {
+		return "", err
+	}
+	var indexList []string
+	for index := range mappings {
+		indexList = append(indexList, index)
+	}
+	sort.Strings(indexList)
+
+	for _, index := range indexList {
+		mapping := mappings[index].Mappings
+		if mapping == nil {
+			mapping = map[string]any{}
+		}
+		// Marshalling the maps sorts the keys, which makes the dump stable.
+		body, err := json.MarshalIndent(map[string]any{"mappings": mapping}, "", "  ")
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to marshal the mappings of index %q", index)
+		}
+		if _, err := fmt.Fprintf(out, "PUT /%s\n%s\n\n", index, body); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// Restore the database from src, which is a full backup.
+func (*Driver) Restore(context.Context, io.Reader) error {
+	return errors.New("elasticsearch: not supported")
+}
+
+// do sends the request to the cluster and returns the response body, returns error if the status code is not 2xx.
+func (d *Driver) do(ctx context.Context, method, path, body string) ([]byte, error) {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	req, err := http.NewRequestWithContext(ctx, method, d.baseURL+path, bytes.NewBufferString(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != "" {
+		contentType := "application/json"
+		if strings.Contains(body, "\n") && isNDJSONPath(path) {
+			contentType = "application/x-ndjson"
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+	if d.username != "" {
+		req.SetBasicAuth(d.username, d.password)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the response body")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("elasticsearch: %s %s returns status %d: %s", method, path, resp.StatusCode, string(content))
+	}
+	return content, nil
+}
+
+func isNDJSONPath(path string) bool {
+	request := &parser.Request{Path: path}
+	switch request.Endpoint() {
+	case "_bulk", "_msearch", "_msearch/template":
+		return true
+	}
+	return false
+}
+
+// writeResult is the part of the write API responses we care about.
+type writeResult struct {
+	// Errors is set by the bulk API if any of the actions fails.
+	Errors   bool  `json:"errors"`
+	Deleted  int64 `json:"deleted"`
+	Updated  int64 `json:"updated"`
+	Created  int64 `json:"created"`
+	Failures []any `json:"failures"`
+}
+
+// checkResponse returns error if the request succeeded with the partial failures, such as the bulk and the by-query requests.
+func checkResponse(body []byte) (*writeResult, error) {
+	var result writeResult
+	// The responses of some APIs such as _cat are not JSON objects.
+	if err := json.Unmarshal(body, &result); err != nil {
+		return &writeResult{}, nil
+	}
+	if result.Errors {
+		return nil, errors.Errorf("some actions of the bulk request failed: %s", string(body))
+	}
+	if len(result.Failures) > 0 {
+		return nil, errors.Errorf("the request has %d failures: %s", len(result.Failures), string(body))
+	}
+	return &result, nil
+}
@@ -0,0 +1,200 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// clusterInfo is the response of GET /.
+type clusterInfo struct {
+	ClusterName string `json:"cluster_name"`
+	Version     struct {
+		Number string `json:"number"`
+	} `json:"version"`
+}
+
+// indexMapping is the mapping of an index in the response of GET /_mapping.
+type indexMapping struct {
+	Mappings map[string]any `json:"mappings"`
+}
+
+// catIndex is an item of the response of GET /_cat/indices?format=json.
+type catIndex struct {
+	Index     string `json:"index"`
+	DocsCount string `json:"docs.count"`
+	StoreSize string `json:"store.size"`
+}
+
+// Sync schema
+
+// SyncInstance syncs the instance metadata.
+func (d *Driver) SyncInstance(ctx context.Context) (*db.InstanceMetadata, error) {
+	info, err := d.getClusterInfo(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster info")
+	}
+	return &db.InstanceMetadata{
+		Version: info.Version.Number,
+		Databases: []*storepb.DatabaseMetadata{
+			{Name: info.ClusterName},
+		},
+	}, nil
+}
+
+// SyncDBSchema syncs a single database schema.
+// The indices are synced as the tables, and the mapping fields are synced as the columns.
+func (d *Driver) SyncDBSchema(ctx context.Context) (*storepb.DatabaseMetadata, error) {
+	databaseName := d.databaseName
+	if databaseName == "" {
+		info, err := d.getClusterInfo(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get cluster info")
+		}
+		databaseName = info.ClusterName
+	}
+
+	mappings, err := d.getMappings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statsMap, err := d.getIndexStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexList []string
+	for index := range mappings {
+		indexList = append(indexList, index)
+	}
+	sort.Strings(indexList)
+
+	schemaMetadata := &storepb.SchemaMetadata{
+		Name: "",
+	}
+	for _, index := range indexList {
+		table := &storepb.TableMetadata{
+			Name: index,
+		}
+		var fieldList []*storepb.ColumnMetadata
+		properties, _ := mappings[index].Mappings["properties"].(map[string]any)
+		flattenProperties("", properties, &fieldList)
+		for i, field := range fieldList {
+			field.Position = int32(i + 1)
+			field.Nullable = true
+		}
+		table.Columns = fieldList
+		if stats, ok := statsMap[index]; ok {
+			// The counts are empty for the closed indices.
+			if count, err := strconv.ParseInt(stats.DocsCount, 10, 64); err == nil {
+				table.RowCount = count
+			}
+			if size, err := strconv.ParseInt(stats.StoreSize, 10, 64); err == nil {
+				table.DataSize = size
+			}
+		}
+		schemaMetadata.Tables = append(schemaMetadata.Tables, table)
+	}
+
+	return &storepb.DatabaseMetadata{
+		Name:    databaseName,
+		Schemas: []*storepb.SchemaMetadata{schemaMetadata},
+	}, nil
+}
+
+// flattenProperties flattens the nested object fields into the dotted field names, e.g. "user.name".
+func flattenProperties(prefix string, properties map[string]any, fieldList *[]*storepb.ColumnMetadata) {
+	var nameList []string
+	for name := range properties {
+		nameList = append(nameList, name)
+	}
+	sort.Strings(nameList)
+
+	for _, name := range nameList {
+		property, ok := properties[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		fullName := prefix + name
+		fieldType, _ := property["type"].(string)
+		subProperties, hasSubProperties := property["properties"].(map[string]any)
+		if fieldType == "" && hasSubProperties {
+			fieldType = "object"
+		}
+		*fieldList = append(*fieldList, &storepb.ColumnMetadata{
+			Name: fullName,
+			Type: fieldType,
+		})
+		if hasSubProperties {
+			flattenProperties(fullName+".", subProperties, fieldList)
+		}
+	}
+}
+
+func (d *Driver) getClusterInfo(ctx context.Context) (*clusterInfo, error) {
+	body, err := d.do(ctx, http.MethodGet, "/", "")
+	if err != nil {
+		return nil, err
+	}
+	var info clusterInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal cluster info")
+	}
+	if info.Version.Number == "" {
+		return nil, errors.New("failed to get version.number")
+	}
+	return &info, nil
+}
+
+// getMappings returns the mappings keyed by the index name, the system indices starting with "." are skipped.
+func (d *Driver) getMappings(ctx context.Context) (map[string]*indexMapping, error) {
+	body, err := d.do(ctx, http.MethodGet, "/_mapping", "")
+	if err != nil {
+		return nil, err
+	}
+	mappings := make(map[string]*indexMapping)
+	if err := json.Unmarshal(body, &mappings); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal mappings")
+	}
+	for index := range mappings {
+		if strings.HasPrefix(index, ".") {
+			delete(mappings, index)
+		}
+	}
+	return mappings, nil
+}
+
+func (d *Driver) getIndexStats(ctx context.Context) (map[string]*catIndex, error) {
+	body, err := d.do(ctx, http.MethodGet, "/_cat/indices?format=json&bytes=b", "")
+	if err != nil {
+		return nil, err
+	}
+	var indexList []*catIndex
+	if err := json.Unmarshal(body, &indexList); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal index stats")
+	}
+	statsMap := make(map[string]*catIndex)
+	for _, index := range indexList {
+		statsMap[index.Index] = index
+	}
+	return statsMap, nil
+}
+
+// SyncSlowQuery syncs the slow query.
+func (*Driver) SyncSlowQuery(_ context.Context, _ time.Time) (map[string]*storepb.SlowQueryStatistics, error) {
+	return nil, errors.Errorf("not implemented")
+}
+
+// CheckSlowQueryLogEnabled checks if slow query log is enabled.
+func (*Driver) CheckSlowQueryLogEnabled(_ context.Context) error {
+	return errors.Errorf("not implemented")
+}
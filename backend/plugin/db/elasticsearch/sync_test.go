@@ -0,0 +1,94 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func newTestDriver(t *testing.T) *Driver {
+	responses := map[string]string{
+		"/":             `{"cluster_name": "es-cluster", "version": {"number": "8.8.0"}}`,
+		"/_mapping":     `{"orders": {"mappings": {"properties": {"user": {"properties": {"name": {"type": "keyword"}}}, "amount": {"type": "long"}}}}, ".kibana": {"mappings": {}}, "logs": {"mappings": {}}}`,
+		"/_cat/indices": `[{"index": "orders", "docs.count": "42", "store.size": "1024"}, {"index": "logs", "docs.count": null, "store.size": null}]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	driver, err := newDriver(db.DriverConfig{}).Open(context.Background(), db.Elasticsearch, db.ConnectionConfig{
+		Host: serverURL.Hostname(),
+		Port: serverURL.Port(),
+	}, db.ConnectionContext{})
+	require.NoError(t, err)
+	return driver.(*Driver)
+}
+
+func TestSyncDBSchema(t *testing.T) {
+	driver := newTestDriver(t)
+	metadata, err := driver.SyncDBSchema(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "es-cluster", metadata.Name)
+	require.Len(t, metadata.Schemas, 1)
+
+	tables := metadata.Schemas[0].Tables
+	require.Len(t, tables, 2)
+	require.Equal(t, "logs", tables[0].Name)
+	require.Empty(t, tables[0].Columns)
+	require.Equal(t, "orders", tables[1].Name)
+	require.Equal(t, int64(42), tables[1].RowCount)
+	require.Equal(t, int64(1024), tables[1].DataSize)
+
+	var columns [][]string
+	for _, column := range tables[1].Columns {
+		columns = append(columns, []string{column.Name, column.Type})
+	}
+	require.Equal(t, [][]string{{"amount", "long"}, {"user", "object"}, {"user.name", "keyword"}}, columns)
+}
+
+func TestDump(t *testing.T) {
+	driver := newTestDriver(t)
+	var buf bytes.Buffer
+	_, err := driver.Dump(context.Background(), &buf, true /* schemaOnly */)
+	require.NoError(t, err)
+	want := `PUT /logs
+{
+  "mappings": {}
+}
+
+PUT /orders
+{
+  "mappings": {
+    "properties": {
+      "amount": {
+        "type": "long"
+      },
+      "user": {
+        "properties": {
+          "name": {
+            "type": "keyword"
+          }
+        }
+      }
+    }
+  }
+}
+
+`
+	require.Equal(t, want, buf.String())
+}
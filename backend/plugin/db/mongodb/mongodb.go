@@ -78,8 +78,9 @@ func (*Driver) GetType() db.Type {
 }
 
 // GetDB gets the database.
+// MongoDB doesn't support database/sql, so it returns nil for the callers that take an optional connection such as SQL review.
 func (*Driver) GetDB() *sql.DB {
-	panic("mongodb doesn't support GetD")
+	return nil
 }
 
 // Execute executes a statement, always returns 0 as the number of rows affected because we execute the statement by mongosh, it's hard to catch the row effected number.
@@ -2,15 +2,472 @@ package mssql
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"io"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
+// dumpTable is the table to dump, the elements are the column and constraint definitions.
+type dumpTable struct {
+	name     string
+	elements []string
+}
+
 // Dump dumps the database.
-func (*Driver) Dump(_ context.Context, _ io.Writer, _ bool) (string, error) {
-	// TODO(d): implement it.
+// Only the schema is dumped, the objects are in the SDL-friendly format.
+func (driver *Driver) Dump(ctx context.Context, out io.Writer, schemaOnly bool) (string, error) {
+	if !schemaOnly {
+		// TODO(d): dump the data.
+		return "", nil
+	}
+
+	txn, err := driver.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer txn.Rollback()
+
+	var stmtList []string
+	sequenceList, err := dumpSequences(txn)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to dump sequences from database %q", driver.databaseName)
+	}
+	stmtList = append(stmtList, sequenceList...)
+	tableList, err := dumpTables(txn)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to dump tables from database %q", driver.databaseName)
+	}
+	stmtList = append(stmtList, tableList...)
+	indexList, err := dumpIndexes(txn)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to dump indexes from database %q", driver.databaseName)
+	}
+	stmtList = append(stmtList, indexList...)
+	moduleList, err := dumpModules(txn)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to dump views, functions, procedures and triggers from database %q", driver.databaseName)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return "", err
+	}
+
+	for _, stmt := range stmtList {
+		if _, err := io.WriteString(out, stmt+";\n\n"); err != nil {
+			return "", err
+		}
+	}
+	// The program units must be the only statement in the batch.
+	for _, module := range moduleList {
+		if _, err := io.WriteString(out, module+"\nGO\n\n"); err != nil {
+			return "", err
+		}
+	}
 	return "", nil
 }
 
+func dumpSequences(txn *sql.Tx) ([]string, error) {
+	query := `
+		SELECT SCHEMA_NAME(schema_id), name, CONVERT(NVARCHAR(64), increment), is_cycling
+		FROM sys.sequences
+		ORDER BY 1, 2;`
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var schemaName, name, increment string
+		var isCycling bool
+		if err := rows.Scan(&schemaName, &name, &increment, &isCycling); err != nil {
+			return nil, err
+		}
+		stmt := fmt.Sprintf("CREATE SEQUENCE %s.%s INCREMENT BY %s", quoteIdentifier(schemaName), quoteIdentifier(name), increment)
+		if isCycling {
+			stmt += " CYCLE"
+		}
+		result = append(result, stmt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func dumpTables(txn *sql.Tx) ([]string, error) {
+	var tableList []*dumpTable
+	tableMap := make(map[string]*dumpTable)
+	getTable := func(schemaName, tableName string) *dumpTable {
+		name := fmt.Sprintf("%s.%s", quoteIdentifier(schemaName), quoteIdentifier(tableName))
+		table, ok := tableMap[name]
+		if !ok {
+			table = &dumpTable{name: name}
+			tableMap[name] = table
+			tableList = append(tableList, table)
+		}
+		return table
+	}
+
+	columnQuery := `
+		SELECT
+			SCHEMA_NAME(t.schema_id),
+			t.name,
+			c.name,
+			TYPE_NAME(c.user_type_id),
+			c.max_length,
+			c.precision,
+			c.scale,
+			c.is_nullable,
+			CONVERT(NVARCHAR(64), ic.seed_value),
+			CONVERT(NVARCHAR(64), ic.increment_value),
+			cc.definition,
+			dc.definition
+		FROM sys.tables t
+		INNER JOIN sys.columns c ON c.object_id = t.object_id
+		LEFT JOIN sys.identity_columns ic ON ic.object_id = c.object_id AND ic.column_id = c.column_id
+		LEFT JOIN sys.computed_columns cc ON cc.object_id = c.object_id AND cc.column_id = c.column_id
+		LEFT JOIN sys.default_constraints dc ON dc.parent_object_id = c.object_id AND dc.parent_column_id = c.column_id
+		WHERE t.is_ms_shipped = 0
+		ORDER BY 1, 2, c.column_id;`
+	rows, err := txn.Query(columnQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var schemaName, tableName, columnName, typeName string
+		var maxLength, precision, scale int
+		var isNullable bool
+		var seed, increment, computed, defaultValue sql.NullString
+		if err := rows.Scan(&schemaName, &tableName, &columnName, &typeName, &maxLength, &precision, &scale, &isNullable, &seed, &increment, &computed, &defaultValue); err != nil {
+			return nil, err
+		}
+		table := getTable(schemaName, tableName)
+		if computed.Valid {
+			table.elements = append(table.elements, fmt.Sprintf("%s AS %s", quoteIdentifier(columnName), computed.String))
+			continue
+		}
+		column := fmt.Sprintf("%s %s", quoteIdentifier(columnName), formatColumnType(typeName, maxLength, precision, scale))
+		if seed.Valid && increment.Valid {
+			column += fmt.Sprintf(" IDENTITY(%s,%s)", seed.String, increment.String)
+		}
+		if !isNullable {
+			column += " NOT NULL"
+		}
+		if defaultValue.Valid {
+			column += " DEFAULT " + defaultValue.String
+		}
+		table.elements = append(table.elements, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	keyConstraintQuery := `
+		SELECT
+			SCHEMA_NAME(t.schema_id),
+			t.name,
+			kc.name,
+			kc.is_system_named,
+			kc.type,
+			col.name,
+			ic.is_descending_key
+		FROM sys.key_constraints kc
+		INNER JOIN sys.tables t ON t.object_id = kc.parent_object_id
+		INNER JOIN sys.index_columns ic ON ic.object_id = kc.parent_object_id AND ic.index_id = kc.unique_index_id
+		INNER JOIN sys.columns col ON col.object_id = ic.object_id AND col.column_id = ic.column_id
+		WHERE t.is_ms_shipped = 0
+		ORDER BY 1, 2, 3, ic.key_ordinal;`
+	if err := queryConstraints(txn, keyConstraintQuery, getTable, func(rows *sql.Rows, c *dumpConstraint) error {
+		var constraintType, columnName string
+		var isDescending bool
+		if err := rows.Scan(&c.schemaName, &c.tableName, &c.name, &c.isSystemNamed, &constraintType, &columnName, &isDescending); err != nil {
+			return err
+		}
+		c.definition = "UNIQUE"
+		if strings.TrimSpace(constraintType) == "PK" {
+			c.definition = "PRIMARY KEY"
+		}
+		c.column = quoteIdentifier(columnName)
+		if isDescending {
+			c.column += " DESC"
+		}
+		return nil
+	}, func(definition string, columnList, _ []string) string {
+		return fmt.Sprintf("%s (%s)", definition, strings.Join(columnList, ", "))
+	}); err != nil {
+		return nil, err
+	}
+
+	checkQuery := `
+		SELECT
+			SCHEMA_NAME(t.schema_id),
+			t.name,
+			cc.name,
+			cc.is_system_named,
+			cc.definition
+		FROM sys.check_constraints cc
+		INNER JOIN sys.tables t ON t.object_id = cc.parent_object_id
+		WHERE t.is_ms_shipped = 0
+		ORDER BY 1, 2, 3;`
+	if err := queryConstraints(txn, checkQuery, getTable, func(rows *sql.Rows, c *dumpConstraint) error {
+		return rows.Scan(&c.schemaName, &c.tableName, &c.name, &c.isSystemNamed, &c.definition)
+	}, func(definition string, _, _ []string) string {
+		return fmt.Sprintf("CHECK %s", definition)
+	}); err != nil {
+		return nil, err
+	}
+
+	foreignKeyQuery := `
+		SELECT
+			SCHEMA_NAME(t.schema_id),
+			t.name,
+			fk.name,
+			fk.is_system_named,
+			SCHEMA_NAME(rt.schema_id),
+			rt.name,
+			fk.delete_referential_action_desc,
+			fk.update_referential_action_desc,
+			pc.name,
+			rc.name
+		FROM sys.foreign_keys fk
+		INNER JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+		INNER JOIN sys.tables t ON t.object_id = fk.parent_object_id
+		INNER JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+		INNER JOIN sys.tables rt ON rt.object_id = fk.referenced_object_id
+		INNER JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+		WHERE t.is_ms_shipped = 0
+		ORDER BY 1, 2, 3, fkc.constraint_column_id;`
+	if err := queryConstraints(txn, foreignKeyQuery, getTable, func(rows *sql.Rows, c *dumpConstraint) error {
+		var referencedSchema, referencedTable, onDelete, onUpdate, columnName, referencedColumn string
+		if err := rows.Scan(&c.schemaName, &c.tableName, &c.name, &c.isSystemNamed, &referencedSchema, &referencedTable, &onDelete, &onUpdate, &columnName, &referencedColumn); err != nil {
+			return err
+		}
+		// The definition is the referential actions, the referenced table is formatted with the columns.
+		c.definition = fmt.Sprintf("%s.%s", quoteIdentifier(referencedSchema), quoteIdentifier(referencedTable))
+		for _, action := range [][2]string{{"DELETE", onDelete}, {"UPDATE", onUpdate}} {
+			if action[1] != "NO_ACTION" {
+				c.definition += fmt.Sprintf(" ON %s %s", action[0], strings.ReplaceAll(action[1], "_", " "))
+			}
+		}
+		c.column = quoteIdentifier(columnName)
+		c.referencedColumn = quoteIdentifier(referencedColumn)
+		return nil
+	}, func(definition string, columnList, referencedColumnList []string) string {
+		referencedTable, actions, _ := strings.Cut(definition, " ")
+		result := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)", strings.Join(columnList, ", "), referencedTable, strings.Join(referencedColumnList, ", "))
+		if actions != "" {
+			result += " " + actions
+		}
+		return result
+	}); err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, table := range tableList {
+		result = append(result, fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", table.name, strings.Join(table.elements, ",\n  ")))
+	}
+	return result, nil
+}
+
+// dumpConstraint is a row of the constraint query, the rows of the multi-column constraint share the same name.
+type dumpConstraint struct {
+	schemaName       string
+	tableName        string
+	name             string
+	isSystemNamed    bool
+	definition       string
+	column           string
+	referencedColumn string
+}
+
+// queryConstraints appends the table constraints to the tables. The system-generated constraint names are omitted.
+func queryConstraints(txn *sql.Tx, query string, getTable func(schemaName, tableName string) *dumpTable, scan func(*sql.Rows, *dumpConstraint) error, format func(definition string, columnList, referencedColumnList []string) string) error {
+	type constraint struct {
+		table                *dumpTable
+		row                  *dumpConstraint
+		columnList           []string
+		referencedColumnList []string
+	}
+	var constraintList []*constraint
+
+	rows, err := txn.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		row := &dumpConstraint{}
+		if err := scan(rows, row); err != nil {
+			return err
+		}
+		table := getTable(row.schemaName, row.tableName)
+		if n := len(constraintList); n > 0 && constraintList[n-1].table == table && constraintList[n-1].row.name == row.name {
+			constraintList[n-1].columnList = append(constraintList[n-1].columnList, row.column)
+			constraintList[n-1].referencedColumnList = append(constraintList[n-1].referencedColumnList, row.referencedColumn)
+			continue
+		}
+		constraintList = append(constraintList, &constraint{
+			table:                table,
+			row:                  row,
+			columnList:           []string{row.column},
+			referencedColumnList: []string{row.referencedColumn},
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range constraintList {
+		definition := format(c.row.definition, c.columnList, c.referencedColumnList)
+		if !c.row.isSystemNamed {
+			definition = fmt.Sprintf("CONSTRAINT %s %s", quoteIdentifier(c.row.name), definition)
+		}
+		c.table.elements = append(c.table.elements, definition)
+	}
+	return nil
+}
+
+func dumpIndexes(txn *sql.Tx) ([]string, error) {
+	query := `
+		SELECT
+			SCHEMA_NAME(t.schema_id),
+			t.name,
+			i.name,
+			i.is_unique,
+			i.type_desc,
+			i.filter_definition,
+			col.name,
+			ic.is_descending_key,
+			ic.is_included_column
+		FROM sys.indexes i
+		INNER JOIN sys.tables t ON t.object_id = i.object_id
+		INNER JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		INNER JOIN sys.columns col ON col.object_id = ic.object_id AND col.column_id = ic.column_id
+		WHERE t.is_ms_shipped = 0 AND i.is_primary_key = 0 AND i.is_unique_constraint = 0 AND i.is_hypothetical = 0 AND i.type IN (1, 2)
+		ORDER BY 1, 2, 3, ic.is_included_column, ic.key_ordinal, ic.index_column_id;`
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type index struct {
+		key         string
+		prefix      string
+		filter      string
+		columnList  []string
+		includeList []string
+	}
+	var indexList []*index
+	for rows.Next() {
+		var schemaName, tableName, name, typeDesc, columnName string
+		var filter sql.NullString
+		var isUnique, isDescending, isIncluded bool
+		if err := rows.Scan(&schemaName, &tableName, &name, &isUnique, &typeDesc, &filter, &columnName, &isDescending, &isIncluded); err != nil {
+			return nil, err
+		}
+		key := fmt.Sprintf("%s.%s.%s", schemaName, tableName, name)
+		if len(indexList) == 0 || indexList[len(indexList)-1].key != key {
+			prefix := "CREATE "
+			if isUnique {
+				prefix += "UNIQUE "
+			}
+			if typeDesc == "CLUSTERED" {
+				prefix += "CLUSTERED "
+			}
+			prefix += fmt.Sprintf("INDEX %s ON %s.%s", quoteIdentifier(name), quoteIdentifier(schemaName), quoteIdentifier(tableName))
+			indexList = append(indexList, &index{key: key, prefix: prefix, filter: filter.String})
+		}
+		idx := indexList[len(indexList)-1]
+		column := quoteIdentifier(columnName)
+		switch {
+		case isIncluded:
+			idx.includeList = append(idx.includeList, column)
+		case isDescending:
+			idx.columnList = append(idx.columnList, column+" DESC")
+		default:
+			idx.columnList = append(idx.columnList, column)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, idx := range indexList {
+		stmt := fmt.Sprintf("%s (%s)", idx.prefix, strings.Join(idx.columnList, ", "))
+		if len(idx.includeList) > 0 {
+			stmt += fmt.Sprintf(" INCLUDE (%s)", strings.Join(idx.includeList, ", "))
+		}
+		if idx.filter != "" {
+			stmt += " WHERE " + idx.filter
+		}
+		result = append(result, stmt)
+	}
+	return result, nil
+}
+
+// dumpModules dumps the definitions of the functions, procedures, views and triggers.
+func dumpModules(txn *sql.Tx) ([]string, error) {
+	query := `
+		SELECT m.definition
+		FROM sys.sql_modules m
+		INNER JOIN sys.objects o ON o.object_id = m.object_id
+		WHERE o.is_ms_shipped = 0 AND o.type IN ('FN', 'IF', 'TF', 'P', 'V', 'TR') AND m.definition IS NOT NULL
+		ORDER BY CASE o.type WHEN 'V' THEN 2 WHEN 'TR' THEN 3 ELSE 1 END, SCHEMA_NAME(o.schema_id), o.name;`
+	rows, err := txn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var definition string
+		if err := rows.Scan(&definition); err != nil {
+			return nil, err
+		}
+		result = append(result, strings.TrimSpace(definition))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func formatColumnType(typeName string, maxLength, precision, scale int) string {
+	switch strings.ToLower(typeName) {
+	case "varchar", "char", "varbinary", "binary":
+		if maxLength == -1 {
+			return fmt.Sprintf("%s(max)", typeName)
+		}
+		return fmt.Sprintf("%s(%d)", typeName, maxLength)
+	case "nvarchar", "nchar":
+		if maxLength == -1 {
+			return fmt.Sprintf("%s(max)", typeName)
+		}
+		// The max_length is in bytes, and the Unicode characters take two bytes.
+		return fmt.Sprintf("%s(%d)", typeName, maxLength/2)
+	case "decimal", "numeric":
+		return fmt.Sprintf("%s(%d,%d)", typeName, precision, scale)
+	case "datetime2", "time", "datetimeoffset":
+		return fmt.Sprintf("%s(%d)", typeName, scale)
+	default:
+		return typeName
+	}
+}
+
+func quoteIdentifier(name string) string {
+	return fmt.Sprintf("[%s]", strings.ReplaceAll(name, "]", "]]"))
+}
+
 // Restore restores a database.
 func (*Driver) Restore(_ context.Context, _ io.Reader) (err error) {
 	// TODO(d): implement it.
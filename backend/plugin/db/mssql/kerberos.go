@@ -0,0 +1,56 @@
+package mssql
+
+import (
+	"github.com/microsoft/go-mssqldb/integratedauth"
+	"github.com/microsoft/go-mssqldb/msdsn"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
+)
+
+const (
+	// kerberosAuthenticator is the name of the integrated authentication provider with the registered Kerberos clients.
+	kerberosAuthenticator = "bytebase-krb5"
+	// kerberosClientParameter is the connection parameter of the Kerberos client ID, since go-mssqldb only supports the global providers.
+	kerberosClientParameter = "bytebase-kerberos-client"
+)
+
+func init() {
+	if err := integratedauth.SetIntegratedAuthenticationProvider(kerberosAuthenticator, integratedauth.ProviderFunc(getKerberosAuth)); err != nil {
+		panic(err)
+	}
+}
+
+func getKerberosAuth(config msdsn.Config) (integratedauth.IntegratedAuthenticator, error) {
+	clientID, ok := config.Parameters[kerberosClientParameter]
+	if !ok {
+		return nil, errors.Errorf("Kerberos client ID must be set")
+	}
+	return &kerberosAuth{
+		clientID: clientID,
+		spn:      config.ServerSPN,
+	}, nil
+}
+
+// kerberosAuth authenticates with the registered Kerberos client.
+type kerberosAuth struct {
+	clientID string
+	// spn is "MSSQLSvc/<host>:<port>" set by the dialer if the data source doesn't specify the SPN.
+	spn string
+}
+
+// InitialBytes gets the initial token for the SPN.
+func (a *kerberosAuth) InitialBytes() ([]byte, error) {
+	return util.GetKerberosInitToken(a.clientID, a.spn)
+}
+
+// NextBytes verifies the response token of the server, the authentication completes in one round trip.
+func (*kerberosAuth) NextBytes(bytes []byte) ([]byte, error) {
+	if err := util.VerifyKerberosToken(bytes); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// Free is a no-op since the Kerberos client is shared by the connections of the driver.
+func (*kerberosAuth) Free() {}
@@ -6,10 +6,9 @@ import (
 	"database/sql"
 	"fmt"
 	"net/url"
-	"strings"
 
 	// Import go-ora Oracle driver.
-	_ "github.com/microsoft/go-mssqldb"
+	mssql "github.com/microsoft/go-mssqldb"
 	_ "github.com/microsoft/go-mssqldb/integratedauth/krb5"
 
 	"go.uber.org/zap"
@@ -32,6 +31,8 @@ func init() {
 type Driver struct {
 	db           *sql.DB
 	databaseName string
+	// kerberosClientID is the ID of the registered Kerberos client for the Kerberos authentication.
+	kerberosClientID string
 }
 
 func newDriver(db.DriverConfig) db.Driver {
@@ -51,8 +52,41 @@ func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionCon
 		Host:     fmt.Sprintf("%s:%s", config.Host, config.Port),
 		RawQuery: query.Encode(),
 	}
+	if config.AuthenticationType == db.AuthenticationTypeAzureAD {
+		// The Azure AD access token is the credential, so the password is not sent.
+		u.User = nil
+		tokenProvider, err := util.GetAzureADTokenProvider(config.AzureCredential, util.AzureSQLScope)
+		if err != nil {
+			return nil, err
+		}
+		connector, err := mssql.NewConnectorWithAccessTokenProvider(u.String(), tokenProvider)
+		if err != nil {
+			return nil, err
+		}
+		driver.db = sql.OpenDB(connector)
+		driver.databaseName = config.Database
+		return driver, nil
+	}
+	if config.AuthenticationType == db.AuthenticationTypeKerberos {
+		// The Kerberos service ticket is the credential, so the password is not sent.
+		clientID, err := util.RegisterKerberosClient(config.Username, config.KerberosConfig)
+		if err != nil {
+			return nil, err
+		}
+		query.Add("authenticator", kerberosAuthenticator)
+		query.Add(kerberosClientParameter, clientID)
+		if config.KerberosConfig.SPN != "" {
+			query.Add("ServerSPN", config.KerberosConfig.SPN)
+		}
+		u.User = nil
+		u.RawQuery = query.Encode()
+		driver.kerberosClientID = clientID
+	}
 	db, err := sql.Open("sqlserver", u.String())
 	if err != nil {
+		if driver.kerberosClientID != "" {
+			util.UnregisterKerberosClient(driver.kerberosClientID)
+		}
 		return nil, err
 	}
 	driver.db = db
@@ -62,7 +96,11 @@ func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionCon
 
 // Close closes the driver.
 func (driver *Driver) Close(_ context.Context) error {
-	return driver.db.Close()
+	err := driver.db.Close()
+	if driver.kerberosClientID != "" {
+		util.UnregisterKerberosClient(driver.kerberosClientID)
+	}
+	return err
 }
 
 // Ping pings the database.
@@ -110,9 +148,16 @@ func (driver *Driver) Execute(ctx context.Context, statement string, createDatab
 		return nil
 	}
 
-	if _, err := parser.SplitMultiSQLStream(parser.MSSQL, strings.NewReader(statement), f); err != nil {
+	// The "GO" batch separator is not T-SQL, so we split the statement by it and keep the program units as a whole.
+	list, err := parser.SplitBatchMultiSQL(parser.MSSQL, statement)
+	if err != nil {
 		return 0, err
 	}
+	for _, singleSQL := range list {
+		if err := f(singleSQL.Text); err != nil {
+			return 0, err
+		}
+	}
 
 	if err := tx.Commit(); err != nil {
 		return 0, err
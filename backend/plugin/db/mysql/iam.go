@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
+)
+
+var _ driver.Connector = (*iamConnector)(nil)
+
+// openDB opens the database with the DSN.
+// For AWS RDS IAM authentication, the auth token expires in 15 minutes, so it's regenerated for every new connection in the pool.
+func openDB(dsn string, connCfg db.ConnectionConfig, port string) (*sql.DB, error) {
+	if connCfg.AuthenticationType != db.AuthenticationTypeAWSRDSIAM {
+		return sql.Open("mysql", dsn)
+	}
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(&iamConnector{cfg: cfg, connCfg: connCfg, port: port}), nil
+}
+
+// iamConnector is the connector using the AWS RDS IAM auth token as the password.
+type iamConnector struct {
+	cfg     *mysql.Config
+	connCfg db.ConnectionConfig
+	port    string
+}
+
+// Connect implements the driver.Connector interface.
+func (c *iamConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	token, err := util.GetRDSIAMAuthToken(ctx, c.connCfg, c.port)
+	if err != nil {
+		return nil, err
+	}
+	cfg := c.cfg.Clone()
+	cfg.Passwd = token
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(ctx)
+}
+
+// Driver implements the driver.Connector interface.
+func (*iamConnector) Driver() driver.Driver {
+	return &mysql.MySQLDriver{}
+}
@@ -7,15 +7,12 @@ import (
 	"database/sql"
 	"fmt"
 	"net"
-	"os"
 	"strings"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/ssh"
-	"golang.org/x/crypto/ssh/agent"
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
@@ -51,7 +48,8 @@ type Driver struct {
 	// Use a single connection for executing migrations in the lifetime of the driver can keep the thread ID unchanged.
 	// So that it's easy to get the thread ID for rollback SQL.
 	migrationConn *sql.Conn
-	sshConn       *ssh.Client
+	sshTunnel     *util.SSHTunnel
+	cloudSQLProxy *util.CloudSQLProxy
 
 	replayedBinlogBytes *common.CountingReader
 	restoredBackupBytes *common.CountingReader
@@ -85,49 +83,38 @@ func (driver *Driver) Open(ctx context.Context, dbType db.Type, connCfg db.Conne
 		}
 	}
 
-	if connCfg.SSHConfig.Host != "" {
-		sshConfig := &ssh.ClientConfig{
-			User:            connCfg.SSHConfig.User,
-			Auth:            []ssh.AuthMethod{},
-			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		}
-		if connCfg.SSHConfig.PrivateKey != "" {
-			signer, err := ssh.ParsePrivateKey([]byte(connCfg.SSHConfig.PrivateKey))
-			if err != nil {
-				return nil, err
-			}
-			sshConfig.Auth = append(sshConfig.Auth, ssh.PublicKeys(signer))
-		} else {
-			// Establish a connection to the local ssh-agent
-			conn, err := net.Dial("unix", os.Getenv("SSH_AUTH_SOCK"))
-			if err != nil {
-				return nil, err
-			}
-			defer conn.Close()
-			// Create a new instance of the ssh agent
-			agentClient := agent.NewClient(conn)
-			// When the agentClient connection succeeded, add them as AuthMethod
-			if agentClient != nil {
-				sshConfig.Auth = append(sshConfig.Auth, ssh.PublicKeysCallback(agentClient.Signers))
-			}
-		}
-		// When there's a non empty password add the password AuthMethod.
-		if connCfg.SSHConfig.Password != "" {
-			sshConfig.Auth = append(sshConfig.Auth, ssh.PasswordCallback(func() (string, error) {
-				return connCfg.SSHConfig.Password, nil
-			}))
+	if connCfg.GCPCloudSQLConfig.ConnectionName != "" {
+		cloudSQLProxy, err := util.NewCloudSQLProxy(ctx, connCfg)
+		if err != nil {
+			return nil, err
 		}
-		// Connect to the SSH Server
-		sshConn, err := ssh.Dial("tcp", fmt.Sprintf("%s:%s", connCfg.SSHConfig.Host, connCfg.SSHConfig.Port), sshConfig)
+		driver.cloudSQLProxy = cloudSQLProxy
+		// The connector handles the TLS, so we connect to the local proxy in plain text.
+		port = cloudSQLProxy.Port()
+		connCfg.Host, connCfg.Port = cloudSQLProxy.Host(), port
+		connCfg.TLSConfig = db.TLSConfig{}
+	} else if connCfg.AuthenticationType == db.AuthenticationTypeGCPCloudSQLIAM {
+		return nil, errors.Errorf("Cloud SQL connection name must be set for the Cloud SQL IAM authentication")
+	} else if connCfg.SSHConfig.Host != "" {
+		sshTunnel, err := util.NewSSHTunnel(connCfg.SSHConfig, connCfg.SOCKS5Config)
 		if err != nil {
 			return nil, err
 		}
-		driver.sshConn = sshConn
-		// Now we register the dialer with the ssh connection as a parameter.
+		driver.sshTunnel = sshTunnel
+		// Now we register the dialer with the ssh tunnel as a parameter.
 		mysql.RegisterDialContext("mysql+tcp", func(ctx context.Context, addr string) (net.Conn, error) {
-			return sshConn.Dial("tcp", addr)
+			return sshTunnel.Dial("tcp", addr)
 		})
 		protocol = "mysql+tcp"
+	} else if connCfg.SOCKS5Config.Host != "" {
+		dialer, err := util.GetSOCKS5Dialer(connCfg.SOCKS5Config)
+		if err != nil {
+			return nil, err
+		}
+		mysql.RegisterDialContext("mysql+socks5", func(ctx context.Context, addr string) (net.Conn, error) {
+			return dialer.Dial("tcp", addr)
+		})
+		protocol = "mysql+socks5"
 	}
 
 	tlsConfig, err := connCfg.TLSConfig.GetSslConfig()
@@ -144,16 +131,25 @@ func (driver *Driver) Open(ctx context.Context, dbType db.Type, connCfg db.Conne
 		params = append(params, fmt.Sprintf("tls=%s", tlsKey))
 	}
 
+	if connCfg.AuthenticationType == db.AuthenticationTypeAWSRDSIAM {
+		// RDS requires the IAM auth token to be sent in clear text over TLS.
+		params = append(params, "allowCleartextPasswords=true")
+		if tlsConfig == nil {
+			params = append(params, "tls=preferred")
+		}
+	}
+
 	dsn := fmt.Sprintf("%s:%s@%s(%s:%s)/%s?%s", connCfg.Username, connCfg.Password, protocol, connCfg.Host, port, connCfg.Database, strings.Join(params, "&"))
-	db, err := sql.Open("mysql", dsn)
+	db, err := openDB(dsn, connCfg, port)
 	if err != nil {
-		return nil, err
+		return nil, multierr.Append(err, driver.closeCloudSQLProxy())
 	}
 	conn, err := db.Conn(ctx)
 	if err != nil {
 		var errList error
 		errList = multierr.Append(errList, err)
 		errList = multierr.Append(errList, db.Close())
+		errList = multierr.Append(errList, driver.closeCloudSQLProxy())
 		return nil, errList
 	}
 	driver.dbType = dbType
@@ -171,9 +167,19 @@ func (driver *Driver) Close(context.Context) error {
 	var err error
 	err = multierr.Append(err, driver.db.Close())
 	err = multierr.Append(err, driver.migrationConn.Close())
-	if driver.sshConn != nil {
-		err = multierr.Append(err, driver.sshConn.Close())
+	if driver.sshTunnel != nil {
+		err = multierr.Append(err, driver.sshTunnel.Close())
+	}
+	err = multierr.Append(err, driver.closeCloudSQLProxy())
+	return err
+}
+
+func (driver *Driver) closeCloudSQLProxy() error {
+	if driver.cloudSQLProxy == nil {
+		return nil
 	}
+	err := driver.cloudSQLProxy.Close()
+	driver.cloudSQLProxy = nil
 	return err
 }
 
@@ -0,0 +1,370 @@
+// Package neo4j implements the Neo4j driver, which executes the Cypher statements through the Bolt protocol.
+package neo4j
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/parser/cypher"
+)
+
+var (
+	_ db.Driver = (*Driver)(nil)
+)
+
+const (
+	// systemDatabase is the database for the administration commands, e.g. SHOW DATABASES and CREATE ROLE.
+	systemDatabase = "system"
+)
+
+func init() {
+	db.Register(db.Neo4j, newDriver)
+}
+
+// Driver is the Neo4j driver.
+type Driver struct {
+	driver       neo4j.DriverWithContext
+	databaseName string
+}
+
+func newDriver(_ db.DriverConfig) db.Driver {
+	return &Driver{}
+}
+
+// Open opens the Neo4j driver.
+// The host could be the URI with the scheme such as neo4j+s://xxx.databases.neo4j.io, otherwise the neo4j scheme is used.
+func (d *Driver) Open(ctx context.Context, _ db.Type, config db.ConnectionConfig, _ db.ConnectionContext) (db.Driver, error) {
+	tlsConfig, err := config.TLSConfig.GetSslConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "neo4j: failed to get tls config")
+	}
+	target := config.Host
+	if !strings.Contains(target, "://") {
+		scheme := "neo4j"
+		if tlsConfig != nil {
+			scheme = "neo4j+s"
+			if tlsConfig.InsecureSkipVerify {
+				scheme = "neo4j+ssc"
+			}
+		}
+		port := config.Port
+		if port == "" {
+			port = "7687"
+		}
+		target = fmt.Sprintf("%s://%s:%s", scheme, config.Host, port)
+	}
+
+	auth := neo4j.NoAuth()
+	if config.Username != "" {
+		auth = neo4j.BasicAuth(config.Username, config.Password, "")
+	}
+	driver, err := neo4j.NewDriverWithContext(target, auth, func(c *neo4j.Config) {
+		c.TlsConfig = tlsConfig
+		c.SocketConnectTimeout = 10 * time.Second
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "neo4j: failed to create driver")
+	}
+	if err := driver.VerifyConnectivity(ctx); err != nil {
+		_ = driver.Close(ctx)
+		return nil, errors.Wrap(err, "neo4j: failed to connect")
+	}
+	d.driver = driver
+	d.databaseName = config.Database
+	return d, nil
+}
+
+// Close closes the Neo4j driver.
+func (d *Driver) Close(ctx context.Context) error {
+	return d.driver.Close(ctx)
+}
+
+// Ping pings the Neo4j server.
+func (d *Driver) Ping(ctx context.Context) error {
+	return d.driver.VerifyConnectivity(ctx)
+}
+
+// GetType returns Neo4j.
+func (*Driver) GetType() db.Type {
+	return db.Neo4j
+}
+
+// GetDB gets the database.
+// Neo4j doesn't support database/sql, so it returns nil for the callers that take an optional connection such as SQL review.
+func (*Driver) GetDB() *sql.DB {
+	return nil
+}
+
+// Execute executes the statements one by one in the auto-commit transactions,
+// because the schema commands cannot be mixed with the data changes in a transaction.
+func (d *Driver) Execute(ctx context.Context, statement string, createDatabase bool) (int64, error) {
+	if createDatabase {
+		return 0, errors.New("neo4j: cannot create database")
+	}
+	statementList, err := cypher.SplitStatements(statement)
+	if err != nil {
+		return 0, err
+	}
+	session := d.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: d.databaseName, AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	var affectedRows int64
+	for _, stmt := range statementList {
+		result, err := session.Run(ctx, stmt.Text, nil)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to execute statement at line %d", stmt.Line)
+		}
+		summary, err := result.Consume(ctx)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to execute statement at line %d", stmt.Line)
+		}
+		counters := summary.Counters()
+		affectedRows += int64(counters.NodesCreated() + counters.NodesDeleted() + counters.RelationshipsCreated() + counters.RelationshipsDeleted())
+	}
+	return affectedRows, nil
+}
+
+// QueryConn executes the statement, returns the results.
+// The read-only queries run in the read transactions, so that Neo4j rejects the writes.
+func (d *Driver) QueryConn(ctx context.Context, _ *sql.Conn, statement string, queryContext *db.QueryContext) ([]any, error) {
+	statementList, err := cypher.SplitStatements(statement)
+	if err != nil {
+		return nil, err
+	}
+	if len(statementList) != 1 {
+		return nil, errors.Errorf("expect to get 1 statement, get %d", len(statementList))
+	}
+	stmt := statementList[0]
+
+	readOnly, limit := false, 0
+	if queryContext != nil {
+		readOnly, limit = queryContext.ReadOnly, queryContext.Limit
+	}
+	if readOnly && stmt.IsSchemaCommand() {
+		return nil, errors.Errorf("neo4j: %s is not allowed in the read-only mode", stmt.Kind())
+	}
+	accessMode := neo4j.AccessModeWrite
+	if readOnly {
+		accessMode = neo4j.AccessModeRead
+	}
+	session := d.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: d.databaseName, AccessMode: accessMode})
+	defer session.Close(ctx)
+
+	work := func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, stmt.Text, nil)
+		if err != nil {
+			return nil, err
+		}
+		return collectRecords(ctx, result, limit)
+	}
+	var records any
+	if readOnly {
+		records, err = session.ExecuteRead(ctx, work)
+	} else {
+		records, err = session.ExecuteWrite(ctx, work)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return records.([]any), nil
+}
+
+// collectRecords converts the records to the query result, the rest records are discarded if exceeding the limit.
+func collectRecords(ctx context.Context, result neo4j.ResultWithContext, limit int) ([]any, error) {
+	columnNames, err := result.Keys()
+	if err != nil {
+		return nil, err
+	}
+	columnTypeNames := make([]string, len(columnNames))
+	data := []any{}
+	var record *neo4j.Record
+	for (limit <= 0 || len(data) < limit) && result.NextRecord(ctx, &record) {
+		var rowData []any
+		for i, value := range record.Values {
+			// Cypher results are not typed, so we use the type of the first non-null value.
+			if columnTypeNames[i] == "" && value != nil {
+				columnTypeNames[i] = valueType(value)
+			}
+			rowData = append(rowData, convertValue(value))
+		}
+		data = append(data, rowData)
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := result.Consume(ctx); err != nil {
+		return nil, err
+	}
+	for i := range columnTypeNames {
+		if columnTypeNames[i] == "" {
+			columnTypeNames[i] = "NULL"
+		}
+	}
+
+	// Neo4j doesn't mask the sensitive fields.
+	// Return the all false boolean slice here as the placeholder.
+	sensitiveInfo := make([]bool, len(columnNames))
+	return []any{columnNames, columnTypeNames, data, sensitiveInfo}, nil
+}
+
+// valueType returns the Cypher type name of the value.
+func valueType(value any) string {
+	switch value.(type) {
+	case bool:
+		return "BOOLEAN"
+	case int64:
+		return "INTEGER"
+	case float64:
+		return "FLOAT"
+	case string:
+		return "STRING"
+	case []byte:
+		return "BYTES"
+	case []any:
+		return "LIST"
+	case map[string]any:
+		return "MAP"
+	case dbtype.Node:
+		return "NODE"
+	case dbtype.Relationship:
+		return "RELATIONSHIP"
+	case dbtype.Path:
+		return "PATH"
+	case dbtype.Date:
+		return "DATE"
+	case dbtype.Time:
+		return "ZONED TIME"
+	case dbtype.LocalTime:
+		return "LOCAL TIME"
+	case dbtype.LocalDateTime:
+		return "LOCAL DATETIME"
+	case time.Time:
+		return "ZONED DATETIME"
+	case dbtype.Duration:
+		return "DURATION"
+	case dbtype.Point2D, dbtype.Point3D:
+		return "POINT"
+	}
+	return "ANY"
+}
+
+// convertValue converts the graph, temporal and spatial values which cannot be marshalled to JSON properly.
+func convertValue(value any) any {
+	switch v := value.(type) {
+	case []byte:
+		return fmt.Sprintf("0x%x", v)
+	case []any:
+		var list []any
+		for _, item := range v {
+			list = append(list, convertValue(item))
+		}
+		return list
+	case map[string]any:
+		return convertMap(v)
+	case dbtype.Node:
+		return map[string]any{
+			"elementId":  v.ElementId,
+			"labels":     v.Labels,
+			"properties": convertMap(v.Props),
+		}
+	case dbtype.Relationship:
+		return map[string]any{
+			"elementId":      v.ElementId,
+			"type":           v.Type,
+			"startElementId": v.StartElementId,
+			"endElementId":   v.EndElementId,
+			"properties":     convertMap(v.Props),
+		}
+	case dbtype.Path:
+		var nodes, relationships []any
+		for _, node := range v.Nodes {
+			nodes = append(nodes, convertValue(node))
+		}
+		for _, relationship := range v.Relationships {
+			relationships = append(relationships, convertValue(relationship))
+		}
+		return map[string]any{
+			"nodes":         nodes,
+			"relationships": relationships,
+		}
+	case dbtype.Date:
+		return v.Time().Format("2006-01-02")
+	case dbtype.Time:
+		return v.Time().Format("15:04:05.999999999Z07:00")
+	case dbtype.LocalTime:
+		return v.Time().Format("15:04:05.999999999")
+	case dbtype.LocalDateTime:
+		return v.Time().Format("2006-01-02T15:04:05.999999999")
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case dbtype.Duration:
+		return v.String()
+	case dbtype.Point2D:
+		return v.String()
+	case dbtype.Point3D:
+		return v.String()
+	}
+	return value
+}
+
+func convertMap(m map[string]any) map[string]any {
+	result := make(map[string]any)
+	for key, value := range m {
+		result[key] = convertValue(value)
+	}
+	return result
+}
+
+// Dump and restore
+// Dump dumps the constraints and the indexes as the Cypher statements.
+// Neo4j data is not dumped currently.
+func (d *Driver) Dump(ctx context.Context, out io.Writer, schemaOnly bool) (string, error) {
+	if !schemaOnly {
+		return "", errors.New("neo4j: not supported")
+	}
+	constraints, indexes, err := d.getSchema(ctx)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(out, dumpSchema(constraints, indexes)); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+// Restore the database from src, which is a full backup.
+func (*Driver) Restore(context.Context, io.Reader) error {
+	return errors.New("neo4j: not supported")
+}
+
+// dumpSchema returns the CREATE statements of the constraints and the indexes ordered by name.
+// The indexes backing the constraints are skipped, because they are created by the constraints.
+func dumpSchema(constraints []*constraintSchema, indexes []*indexSchema) string {
+	var statements []string
+	sort.Slice(constraints, func(i, j int) bool {
+		return constraints[i].name < constraints[j].name
+	})
+	for _, constraint := range constraints {
+		statements = append(statements, constraint.createStatement+";\n")
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].name < indexes[j].name
+	})
+	for _, index := range indexes {
+		if index.owningConstraint != "" {
+			continue
+		}
+		statements = append(statements, index.createStatement+";\n")
+	}
+	return strings.Join(statements, "")
+}
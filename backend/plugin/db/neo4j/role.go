@@ -0,0 +1,76 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// Role
+
+// CreateRole creates the Neo4j role, the roles are only available in the enterprise edition.
+// Neo4j roles don't have the password, the connection limit and the expiration, they are granted to the users instead.
+func (d *Driver) CreateRole(ctx context.Context, upsert *db.DatabaseRoleUpsertMessage) (*db.DatabaseRoleMessage, error) {
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	if _, err := d.query(ctx, systemDatabase, "CREATE ROLE $role", map[string]any{"role": upsert.Name}); err != nil {
+		return nil, err
+	}
+	return d.FindRole(ctx, upsert.Name)
+}
+
+// UpdateRole renames the Neo4j role.
+func (d *Driver) UpdateRole(ctx context.Context, roleName string, upsert *db.DatabaseRoleUpsertMessage) (*db.DatabaseRoleMessage, error) {
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	if upsert.Name != roleName {
+		if _, err := d.query(ctx, systemDatabase, "RENAME ROLE $role TO $name", map[string]any{"role": roleName, "name": upsert.Name}); err != nil {
+			return nil, err
+		}
+	}
+	return d.FindRole(ctx, upsert.Name)
+}
+
+// FindRole finds the Neo4j role by name.
+func (d *Driver) FindRole(ctx context.Context, roleName string) (*db.DatabaseRoleMessage, error) {
+	records, err := d.query(ctx, systemDatabase, "SHOW ROLES YIELD role WHERE role = $role RETURN role", map[string]any{"role": roleName})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, common.Errorf(common.NotFound, fmt.Sprintf("cannot find the role %s", roleName))
+	}
+	return &db.DatabaseRoleMessage{Name: stringValue(records[0], "role")}, nil
+}
+
+// ListRole lists the Neo4j roles.
+func (d *Driver) ListRole(ctx context.Context) ([]*db.DatabaseRoleMessage, error) {
+	records, err := d.query(ctx, systemDatabase, "SHOW ROLES YIELD role RETURN role ORDER BY role", nil)
+	if err != nil {
+		return nil, err
+	}
+	var roles []*db.DatabaseRoleMessage
+	for _, record := range records {
+		roles = append(roles, &db.DatabaseRoleMessage{Name: stringValue(record, "role")})
+	}
+	return roles, nil
+}
+
+// DeleteRole deletes the Neo4j role by name.
+func (d *Driver) DeleteRole(ctx context.Context, roleName string) error {
+	_, err := d.query(ctx, systemDatabase, "DROP ROLE $role", map[string]any{"role": roleName})
+	return err
+}
+
+func validateRoleUpsert(upsert *db.DatabaseRoleUpsertMessage) error {
+	if upsert.Password != nil || upsert.ConnectionLimit != nil || upsert.ValidUntil != nil || upsert.Attribute != nil {
+		return errors.New("neo4j: the role only supports the name")
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+type constraintSchema struct {
+	name            string
+	createStatement string
+}
+
+type indexSchema struct {
+	name string
+	// indexType is one of RANGE, TEXT, POINT, FULLTEXT, LOOKUP and so on.
+	indexType string
+	// labelsOrTypes is empty for the LOOKUP indexes.
+	labelsOrTypes    []string
+	properties       []string
+	owningConstraint string
+	createStatement  string
+}
+
+// Sync schema
+
+// SyncInstance syncs the instance metadata.
+func (d *Driver) SyncInstance(ctx context.Context) (*db.InstanceMetadata, error) {
+	records, err := d.query(ctx, systemDatabase, "CALL dbms.components() YIELD versions, edition RETURN versions[0] AS version, edition", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get server version")
+	}
+	if len(records) == 0 {
+		return nil, errors.New("failed to get server version")
+	}
+	version, edition := stringValue(records[0], "version"), stringValue(records[0], "edition")
+
+	// The databases are listed once for each server in a cluster.
+	records, err = d.query(ctx, systemDatabase, "SHOW DATABASES YIELD name", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get databases")
+	}
+	databaseMap := make(map[string]bool)
+	var databases []*storepb.DatabaseMetadata
+	for _, record := range records {
+		name := stringValue(record, "name")
+		if name == systemDatabase || databaseMap[name] {
+			continue
+		}
+		databaseMap[name] = true
+		databases = append(databases, &storepb.DatabaseMetadata{Name: name})
+	}
+	sort.Slice(databases, func(i, j int) bool {
+		return databases[i].Name < databases[j].Name
+	})
+
+	// The roles are only available in the enterprise edition.
+	var instanceRoles []*storepb.InstanceRoleMetadata
+	if edition == "enterprise" {
+		roles, err := d.ListRole(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range roles {
+			instanceRoles = append(instanceRoles, &storepb.InstanceRoleMetadata{Name: role.Name})
+		}
+	}
+
+	return &db.InstanceMetadata{
+		Version:       version,
+		Databases:     databases,
+		InstanceRoles: instanceRoles,
+	}, nil
+}
+
+// SyncDBSchema syncs a single database schema.
+// The node labels and the relationship types are synced as the tables, with the indexes on them.
+func (d *Driver) SyncDBSchema(ctx context.Context) (*storepb.DatabaseMetadata, error) {
+	tableMap := make(map[string]*storepb.TableMetadata)
+	for _, query := range []string{
+		"CALL db.labels() YIELD label AS name",
+		"CALL db.relationshipTypes() YIELD relationshipType AS name",
+	} {
+		records, err := d.query(ctx, d.databaseName, query, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the labels and the relationship types of database %q", d.databaseName)
+		}
+		for _, record := range records {
+			name := stringValue(record, "name")
+			tableMap[name] = &storepb.TableMetadata{Name: name}
+		}
+	}
+
+	_, indexes, err := d.getSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		// The LOOKUP indexes are not on any label or relationship type.
+		for _, name := range index.labelsOrTypes {
+			table, ok := tableMap[name]
+			if !ok {
+				// The indexes could be created before any node with the label.
+				table = &storepb.TableMetadata{Name: name}
+				tableMap[name] = table
+			}
+			table.Indexes = append(table.Indexes, &storepb.IndexMetadata{
+				Name:        index.name,
+				Expressions: index.properties,
+				Type:        index.indexType,
+				Unique:      index.owningConstraint != "",
+				Visible:     true,
+			})
+		}
+	}
+
+	schemaMetadata := &storepb.SchemaMetadata{
+		Name: "",
+	}
+	for _, table := range tableMap {
+		schemaMetadata.Tables = append(schemaMetadata.Tables, table)
+	}
+	sort.Slice(schemaMetadata.Tables, func(i, j int) bool {
+		return schemaMetadata.Tables[i].Name < schemaMetadata.Tables[j].Name
+	})
+
+	return &storepb.DatabaseMetadata{
+		Name:    d.databaseName,
+		Schemas: []*storepb.SchemaMetadata{schemaMetadata},
+	}, nil
+}
+
+// getSchema gets the constraints and the indexes of the database.
+func (d *Driver) getSchema(ctx context.Context) ([]*constraintSchema, []*indexSchema, error) {
+	if d.databaseName == "" {
+		return nil, nil, errors.New("neo4j: database is required")
+	}
+	records, err := d.query(ctx, d.databaseName, "SHOW CONSTRAINTS YIELD name, createStatement", nil)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get constraints of database %q", d.databaseName)
+	}
+	var constraints []*constraintSchema
+	for _, record := range records {
+		constraints = append(constraints, &constraintSchema{
+			name:            stringValue(record, "name"),
+			createStatement: stringValue(record, "createStatement"),
+		})
+	}
+
+	records, err = d.query(ctx, d.databaseName, "SHOW INDEXES YIELD name, type, labelsOrTypes, properties, owningConstraint, createStatement", nil)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get indexes of database %q", d.databaseName)
+	}
+	var indexes []*indexSchema
+	for _, record := range records {
+		indexes = append(indexes, &indexSchema{
+			name:             stringValue(record, "name"),
+			indexType:        stringValue(record, "type"),
+			labelsOrTypes:    stringListValue(record, "labelsOrTypes"),
+			properties:       stringListValue(record, "properties"),
+			owningConstraint: stringValue(record, "owningConstraint"),
+			createStatement:  stringValue(record, "createStatement"),
+		})
+	}
+	return constraints, indexes, nil
+}
+
+// query runs the query in an auto-commit transaction and collects all the records.
+func (d *Driver) query(ctx context.Context, databaseName string, query string, params map[string]any) ([]*neo4j.Record, error) {
+	session := d.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: databaseName})
+	defer session.Close(ctx)
+	result, err := session.Run(ctx, query, params)
+	return neo4j.CollectWithContext(ctx, result, err)
+}
+
+// stringValue returns the string value of the key, empty if the value is null.
+func stringValue(record *neo4j.Record, key string) string {
+	value, _ := record.Get(key)
+	if value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// stringListValue returns the string list value of the key, nil if the value is null.
+func stringListValue(record *neo4j.Record, key string) []string {
+	value, _ := record.Get(key)
+	list, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, item := range list {
+		result = append(result, fmt.Sprintf("%v", item))
+	}
+	return result
+}
+
+// SyncSlowQuery syncs the slow query.
+func (*Driver) SyncSlowQuery(_ context.Context, _ time.Time) (map[string]*storepb.SlowQueryStatistics, error) {
+	return nil, errors.Errorf("not implemented")
+}
+
+// CheckSlowQueryLogEnabled checks if slow query log is enabled.
+func (*Driver) CheckSlowQueryLogEnabled(_ context.Context) error {
+	return errors.Errorf("not implemented")
+}
@@ -0,0 +1,25 @@
+package neo4j
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpSchema(t *testing.T) {
+	constraints := []*constraintSchema{
+		{name: "person_id", createStatement: "CREATE CONSTRAINT `person_id` FOR (n:`Person`) REQUIRE (n.`id`) IS UNIQUE"},
+		{name: "movie_title", createStatement: "CREATE CONSTRAINT `movie_title` FOR (n:`Movie`) REQUIRE (n.`title`) IS NOT NULL"},
+	}
+	indexes := []*indexSchema{
+		{name: "person_name", indexType: "RANGE", labelsOrTypes: []string{"Person"}, properties: []string{"name"}, createStatement: "CREATE RANGE INDEX `person_name` FOR (n:`Person`) ON (n.`name`)"},
+		{name: "person_id", indexType: "RANGE", labelsOrTypes: []string{"Person"}, properties: []string{"id"}, owningConstraint: "person_id", createStatement: "CREATE RANGE INDEX `person_id` FOR (n:`Person`) ON (n.`id`)"},
+		{name: "index_343aff4e", indexType: "LOOKUP", createStatement: "CREATE LOOKUP INDEX `index_343aff4e` FOR (n) ON EACH labels(n)"},
+	}
+
+	want := "CREATE CONSTRAINT `movie_title` FOR (n:`Movie`) REQUIRE (n.`title`) IS NOT NULL;\n" +
+		"CREATE CONSTRAINT `person_id` FOR (n:`Person`) REQUIRE (n.`id`) IS UNIQUE;\n" +
+		"CREATE LOOKUP INDEX `index_343aff4e` FOR (n) ON EACH labels(n);\n" +
+		"CREATE RANGE INDEX `person_name` FOR (n:`Person`) ON (n.`name`);\n"
+	require.Equal(t, want, dumpSchema(constraints, indexes))
+}
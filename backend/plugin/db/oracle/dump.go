@@ -2,15 +2,174 @@ package oracle
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// systemConstraintNameRegexp matches the system-generated constraint names, such as CONSTRAINT "SYS_C008178".
+	systemConstraintNameRegexp = regexp.MustCompile(`CONSTRAINT "SYS_C\d+" `)
+
+	// transformParamStmt removes the physical attributes and the schema from the DDL generated by DBMS_METADATA,
+	// so that the dumped schema is comparable with the SDL schema.
+	transformParamStmt = `
+		BEGIN
+			DBMS_METADATA.SET_TRANSFORM_PARAM(DBMS_METADATA.SESSION_TRANSFORM, 'SEGMENT_ATTRIBUTES', FALSE);
+			DBMS_METADATA.SET_TRANSFORM_PARAM(DBMS_METADATA.SESSION_TRANSFORM, 'STORAGE', FALSE);
+			DBMS_METADATA.SET_TRANSFORM_PARAM(DBMS_METADATA.SESSION_TRANSFORM, 'TABLESPACE', FALSE);
+			DBMS_METADATA.SET_TRANSFORM_PARAM(DBMS_METADATA.SESSION_TRANSFORM, 'EMIT_SCHEMA', FALSE);
+			DBMS_METADATA.SET_TRANSFORM_PARAM(DBMS_METADATA.SESSION_TRANSFORM, 'SQLTERMINATOR', FALSE);
+		END;`
 )
 
 // Dump dumps the database.
-func (*Driver) Dump(_ context.Context, _ io.Writer, _ bool) (string, error) {
-	// TODO(d): implement it.
+// Only the schema of the current schema is dumped, the objects are in the SDL-friendly format.
+func (driver *Driver) Dump(ctx context.Context, out io.Writer, schemaOnly bool) (string, error) {
+	if !schemaOnly {
+		// TODO(d): dump the data.
+		return "", nil
+	}
+
+	conn, err := driver.db.Conn(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get connection")
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, transformParamStmt); err != nil {
+		return "", errors.Wrapf(err, "failed to set the metadata transform parameters")
+	}
+
+	dumpers := []struct {
+		objectType string
+		query      string
+		format     func(name, text string) string
+	}{
+		{
+			objectType: "sequence",
+			query: `
+				SELECT SEQUENCE_NAME, 'CREATE SEQUENCE "' || SEQUENCE_NAME || '" INCREMENT BY ' || INCREMENT_BY ||
+					CASE WHEN CACHE_SIZE > 0 THEN ' CACHE ' || CACHE_SIZE ELSE ' NOCACHE' END ||
+					CASE WHEN CYCLE_FLAG = 'Y' THEN ' CYCLE' ELSE '' END
+				FROM ALL_SEQUENCES
+				WHERE SEQUENCE_OWNER = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') AND SEQUENCE_NAME NOT LIKE 'ISEQ$$_%'
+				ORDER BY SEQUENCE_NAME`,
+		},
+		{
+			objectType: "table",
+			query: `
+				SELECT TABLE_NAME, DBMS_METADATA.GET_DDL('TABLE', TABLE_NAME, OWNER)
+				FROM ALL_TABLES t
+				WHERE OWNER = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') AND NESTED = 'NO' AND DROPPED = 'NO' AND IOT_NAME IS NULL
+					AND NOT EXISTS (SELECT 1 FROM ALL_MVIEWS m WHERE m.OWNER = t.OWNER AND m.MVIEW_NAME = t.TABLE_NAME)
+				ORDER BY TABLE_NAME`,
+			format: func(_, text string) string {
+				return systemConstraintNameRegexp.ReplaceAllString(text, "")
+			},
+		},
+		{
+			objectType: "index",
+			query: `
+				SELECT INDEX_NAME, DBMS_METADATA.GET_DDL('INDEX', INDEX_NAME, OWNER)
+				FROM ALL_INDEXES i
+				WHERE OWNER = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') AND GENERATED = 'N' AND INDEX_TYPE != 'LOB'
+					AND NOT EXISTS (SELECT 1 FROM ALL_CONSTRAINTS c WHERE c.OWNER = i.OWNER AND c.INDEX_NAME = i.INDEX_NAME)
+					AND NOT EXISTS (SELECT 1 FROM ALL_MVIEWS m WHERE m.OWNER = i.TABLE_OWNER AND m.MVIEW_NAME = i.TABLE_NAME)
+				ORDER BY TABLE_NAME, INDEX_NAME`,
+		},
+		{
+			objectType: "function, procedure and trigger",
+			query: `
+				SELECT NAME, TEXT
+				FROM ALL_SOURCE
+				WHERE OWNER = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') AND TYPE IN ('FUNCTION', 'PROCEDURE', 'TRIGGER')
+				ORDER BY TYPE, NAME, LINE`,
+			format: func(_, text string) string {
+				return fmt.Sprintf("CREATE OR REPLACE %s\n/", strings.TrimSpace(text))
+			},
+		},
+		{
+			objectType: "view",
+			query: `
+				SELECT VIEW_NAME, TEXT
+				FROM ALL_VIEWS
+				WHERE OWNER = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+				ORDER BY VIEW_NAME`,
+			format: func(name, text string) string {
+				return fmt.Sprintf("CREATE VIEW %q AS %s", name, strings.TrimSpace(text))
+			},
+		},
+		{
+			objectType: "materialized view",
+			query: `
+				SELECT MVIEW_NAME, QUERY
+				FROM ALL_MVIEWS
+				WHERE OWNER = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+				ORDER BY MVIEW_NAME`,
+			format: func(name, text string) string {
+				return fmt.Sprintf("CREATE MATERIALIZED VIEW %q AS %s", name, strings.TrimSpace(text))
+			},
+		},
+	}
+
+	for _, dumper := range dumpers {
+		stmtList, err := queryObjectList(ctx, conn, dumper.query)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to dump %s", dumper.objectType)
+		}
+		for _, stmt := range stmtList {
+			text := strings.TrimSpace(stmt.text)
+			if dumper.format != nil {
+				text = dumper.format(stmt.name, text)
+			}
+			if !strings.HasSuffix(text, "\n/") {
+				text += ";"
+			}
+			if _, err := io.WriteString(out, text+"\n\n"); err != nil {
+				return "", err
+			}
+		}
+	}
 	return "", nil
 }
 
+type objectStmt struct {
+	name string
+	text string
+}
+
+// queryObjectList returns the statements of the objects, the text of the rows with the same name are concatenated.
+func queryObjectList(ctx context.Context, conn *sql.Conn, query string) ([]*objectStmt, error) {
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*objectStmt
+	for rows.Next() {
+		var name string
+		var text sql.NullString
+		if err := rows.Scan(&name, &text); err != nil {
+			return nil, err
+		}
+		if len(result) > 0 && result[len(result)-1].name == name {
+			result[len(result)-1].text += text.String
+			continue
+		}
+		result = append(result, &objectStmt{name: name, text: text.String})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // Restore restores a database.
 func (*Driver) Restore(_ context.Context, _ io.Reader) (err error) {
 	// TODO(d): implement it.
@@ -5,7 +5,6 @@ import (
 	"context"
 	"database/sql"
 	"strconv"
-	"strings"
 
 	// Import go-ora Oracle driver.
 	"github.com/pkg/errors"
@@ -100,8 +99,6 @@ func (driver *Driver) executeWithBeforeCommitTxFunc(ctx context.Context, stateme
 
 	totalRowsAffected := int64(0)
 	f := func(stmt string) error {
-		// The underlying oracle golang driver go-ora does not support semicolon, so we should trim the suffix semicolon.
-		stmt = strings.TrimSuffix(stmt, ";")
 		sqlResult, err := tx.ExecContext(ctx, stmt)
 		if err != nil {
 			return err
@@ -116,9 +113,17 @@ func (driver *Driver) executeWithBeforeCommitTxFunc(ctx context.Context, stateme
 		return nil
 	}
 
-	if _, err := parser.SplitMultiSQLStream(parser.Oracle, strings.NewReader(statement), f); err != nil {
+	// The underlying oracle golang driver go-ora does not support semicolon, the splitter trims the suffix semicolon
+	// of the SQL statements and keeps the PL/SQL blocks as a whole.
+	list, err := parser.SplitBatchMultiSQL(parser.Oracle, statement)
+	if err != nil {
 		return 0, err
 	}
+	for _, singleSQL := range list {
+		if err := f(singleSQL.Text); err != nil {
+			return 0, err
+		}
+	}
 
 	if beforeCommitTx != nil {
 		if err := beforeCommitTx(tx); err != nil {
@@ -3,8 +3,10 @@ package pg
 import (
 	"bufio"
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -12,6 +14,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
 	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
 )
 
@@ -48,7 +51,17 @@ func (driver *Driver) Dump(ctx context.Context, out io.Writer, schemaOnly bool)
 	}
 
 	for _, dbName := range dumpableDbNames {
-		if err := driver.dumpOneDatabaseWithPgDump(ctx, dbName, out, schemaOnly); err != nil {
+		// The hypertables can only be read from the connected database.
+		var hypertables []*hypertable
+		if schemaOnly && dbName == driver.databaseName {
+			if hypertables, err = driver.getDumpHypertables(ctx); err != nil {
+				return "", err
+			}
+		}
+		if err := driver.dumpOneDatabaseWithPgDump(ctx, dbName, out, schemaOnly, len(hypertables) > 0); err != nil {
+			return "", err
+		}
+		if _, err := io.WriteString(out, dumpHypertables(hypertables)); err != nil {
 			return "", err
 		}
 	}
@@ -56,10 +69,44 @@ func (driver *Driver) Dump(ctx context.Context, out io.Writer, schemaOnly bool)
 	return "", nil
 }
 
-func (driver *Driver) dumpOneDatabaseWithPgDump(ctx context.Context, database string, out io.Writer, schemaOnly bool) error {
+// getDumpHypertables gets the hypertables of the connected database, returns nil if TimescaleDB is not installed.
+func (driver *Driver) getDumpHypertables(ctx context.Context) ([]*hypertable, error) {
+	txn, err := driver.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Rollback()
+
+	extensions, err := getExtensions(txn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get extensions from database %q", driver.databaseName)
+	}
+	if !hasTimescaleDB(extensions) {
+		return nil, nil
+	}
+	hypertables, err := getHypertables(txn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get hypertables from database %q", driver.databaseName)
+	}
+	return hypertables, txn.Commit()
+}
+
+// dumpOneDatabaseWithPgDump dumps the database with pg_dump.
+// The hypertable chunks are excluded from the schema dump, because they are created by TimescaleDB for the inserted data.
+func (driver *Driver) dumpOneDatabaseWithPgDump(ctx context.Context, database string, out io.Writer, schemaOnly bool, excludeChunks bool) error {
+	password := driver.config.Password
+	if driver.tokenProvider != nil {
+		// The auth token got when opening the driver may have expired.
+		token, err := driver.tokenProvider(ctx)
+		if err != nil {
+			return err
+		}
+		password = token
+	}
+
 	var args []string
 	args = append(args, fmt.Sprintf("--username=%s", driver.config.Username))
-	if driver.config.Password == "" {
+	if password == "" {
 		args = append(args, "--no-password")
 	}
 	args = append(args, fmt.Sprintf("--host=%s", driver.config.Host))
@@ -67,6 +114,9 @@ func (driver *Driver) dumpOneDatabaseWithPgDump(ctx context.Context, database st
 	if schemaOnly {
 		args = append(args, "--schema-only")
 	}
+	if excludeChunks {
+		args = append(args, fmt.Sprintf("--exclude-schema=%s", timescaleDBChunkSchema))
+	}
 	args = append(args, "--inserts")
 	args = append(args, "--use-set-session-authorization")
 	// Avoid pg_dump v15 generate "ALTER SCHEMA public OWNER TO" statement.
@@ -77,9 +127,25 @@ func (driver *Driver) dumpOneDatabaseWithPgDump(ctx context.Context, database st
 
 	pgDumpPath := filepath.Join(driver.dbBinDir, "pg_dump")
 	cmd := exec.CommandContext(ctx, pgDumpPath, args...)
-	if driver.config.Password != "" {
+	if password != "" {
 		// Unlike MySQL, PostgreSQL does not support specifying commands in commands, we can do this by means of environment variables.
-		cmd.Env = append(cmd.Env, fmt.Sprintf("PGPASSWORD=%s", driver.config.Password))
+		cmd.Env = append(cmd.Env, fmt.Sprintf("PGPASSWORD=%s", password))
+	}
+	if driver.cloudSQLProxy != nil {
+		// The Cloud SQL connector handles the TLS behind the local proxy.
+		cmd.Env = append(cmd.Env, "PGSSLMODE=disable")
+	}
+	if driver.kerberosClientID != "" {
+		// pg_dump gets the Kerberos tickets with the client keytab by itself.
+		dir, env, err := util.WriteKerberosFiles(driver.config.KerberosConfig)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		cmd.Env = append(cmd.Env, env...)
+		if service, _, ok := strings.Cut(driver.config.KerberosConfig.SPN, "/"); ok {
+			cmd.Env = append(cmd.Env, fmt.Sprintf("PGKRBSRVNAME=%s", service))
+		}
 	}
 	cmd.Env = append(cmd.Env, "OPENSSL_CONF=/etc/ssl/")
 	outPipe, err := cmd.StdoutPipe()
@@ -0,0 +1,50 @@
+package pg
+
+import (
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
+)
+
+// kerberosClientSeparator separates the service principal name and the Kerberos client ID in the krbspn connection parameter,
+// since pgx only supports the global GSSAPI provider.
+const kerberosClientSeparator = "|"
+
+func init() {
+	pgconn.RegisterGSSProvider(func() (pgconn.GSS, error) {
+		return &kerberosGSS{}, nil
+	})
+}
+
+// getKerberosSPN gets the krbspn connection parameter with the Kerberos client ID.
+func getKerberosSPN(spn, clientID string) string {
+	return spn + kerberosClientSeparator + clientID
+}
+
+// kerberosGSS is the GSSAPI provider authenticating with the registered Kerberos clients.
+type kerberosGSS struct{}
+
+// GetInitToken is called if krbspn is not set, which means the Kerberos authentication is not configured for the data source.
+func (*kerberosGSS) GetInitToken(host string, _ string) ([]byte, error) {
+	return nil, errors.Errorf("server %s requests GSSAPI authentication, but Kerberos authentication is not configured", host)
+}
+
+// GetInitTokenFromSPN gets the initial token with the Kerberos client in the krbspn connection parameter.
+func (*kerberosGSS) GetInitTokenFromSPN(spn string) ([]byte, error) {
+	spn, clientID, ok := strings.Cut(spn, kerberosClientSeparator)
+	if !ok {
+		return nil, errors.Errorf("Kerberos client not found for SPN %q", spn)
+	}
+	return util.GetKerberosInitToken(clientID, spn)
+}
+
+// Continue verifies the response token of the server, the authentication completes in one round trip.
+func (*kerberosGSS) Continue(inToken []byte) (bool, []byte, error) {
+	if err := util.VerifyKerberosToken(inToken); err != nil {
+		return true, nil, err
+	}
+	return true, nil, nil
+}
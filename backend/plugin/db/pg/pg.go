@@ -13,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/bytebase/bytebase/backend/common"
@@ -42,6 +43,8 @@ var (
 
 	// driverName is the driver name that our driver dependence register, now is "pgx".
 	driverName = "pgx"
+	// defaultPort is the default port of Postgres used when the port is not specified.
+	defaultPort = "5432"
 
 	_ db.Driver = (*Driver)(nil)
 )
@@ -62,6 +65,11 @@ type Driver struct {
 	connectionString string
 	baseDSN          string
 	databaseName     string
+	cloudSQLProxy    *util.CloudSQLProxy
+	// tokenProvider returns the short-lived auth token used as the password for the AWS RDS IAM and Azure AD authentication.
+	tokenProvider func(ctx context.Context) (string, error)
+	// kerberosClientID is the ID of the registered Kerberos client for the Kerberos authentication.
+	kerberosClientID string
 }
 
 func newDriver(config db.DriverConfig) db.Driver {
@@ -71,7 +79,7 @@ func newDriver(config db.DriverConfig) db.Driver {
 }
 
 // Open opens a Postgres driver.
-func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
+func (driver *Driver) Open(ctx context.Context, _ db.Type, config db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
 	// Require username for Postgres, as the guessDSN 1st guess is to use the username as the connecting database
 	// if database name is not explicitly specified.
 	if config.Username == "" {
@@ -83,6 +91,66 @@ func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionCon
 		return nil, errors.Errorf("ssl-cert and ssl-key must be both set or unset")
 	}
 
+	switch config.AuthenticationType {
+	case db.AuthenticationTypeAWSRDSIAM:
+		awsConfig := config
+		driver.tokenProvider = func(ctx context.Context) (string, error) {
+			return util.GetRDSIAMAuthToken(ctx, awsConfig, defaultPort)
+		}
+	case db.AuthenticationTypeAzureAD:
+		tokenProvider, err := util.GetAzureADTokenProvider(config.AzureCredential, util.AzurePostgreSQLScope)
+		if err != nil {
+			return nil, err
+		}
+		driver.tokenProvider = tokenProvider
+	case db.AuthenticationTypeKerberos:
+		clientID, err := util.RegisterKerberosClient(config.Username, config.KerberosConfig)
+		if err != nil {
+			return nil, err
+		}
+		driver.kerberosClientID = clientID
+	}
+	if driver.tokenProvider != nil {
+		token, err := driver.tokenProvider(ctx)
+		if err != nil {
+			return nil, err
+		}
+		config.Password = token
+	}
+
+	sslMode := ""
+	if config.GCPCloudSQLConfig.ConnectionName != "" {
+		cloudSQLProxy, err := util.NewCloudSQLProxy(ctx, config)
+		if err != nil {
+			driver.unregisterKerberosClient()
+			return nil, err
+		}
+		driver.cloudSQLProxy = cloudSQLProxy
+		// The connector handles the TLS, so we connect to the local proxy in plain text.
+		config.Host, config.Port = cloudSQLProxy.Host(), cloudSQLProxy.Port()
+		config.TLSConfig = db.TLSConfig{}
+		sslMode = "disable"
+	} else if config.AuthenticationType == db.AuthenticationTypeGCPCloudSQLIAM {
+		return nil, errors.Errorf("Cloud SQL connection name must be set for the Cloud SQL IAM authentication")
+	}
+
+	if err := driver.open(config, connCtx, sslMode); err != nil {
+		driver.unregisterKerberosClient()
+		return nil, multierr.Append(err, driver.closeCloudSQLProxy())
+	}
+	return driver, nil
+}
+
+// open guesses the DSN and opens the database, the config is the final one to connect.
+func (driver *Driver) open(config db.ConnectionConfig, connCtx db.ConnectionContext, sslMode string) error {
+	kerberosSPN := ""
+	if driver.kerberosClientID != "" {
+		spn := config.KerberosConfig.SPN
+		if spn == "" {
+			spn = fmt.Sprintf("postgres/%s", config.Host)
+		}
+		kerberosSPN = getKerberosSPN(spn, driver.kerberosClientID)
+	}
 	databaseName, dsn, err := guessDSN(
 		config.Username,
 		config.Password,
@@ -92,9 +160,11 @@ func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionCon
 		config.TLSConfig.SslCA,
 		config.TLSConfig.SslCert,
 		config.TLSConfig.SslKey,
+		sslMode,
+		kerberosSPN,
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if config.ReadOnly {
 		dsn = fmt.Sprintf("%s default_transaction_read_only=true", dsn)
@@ -104,35 +174,77 @@ func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionCon
 	driver.connectionCtx = connCtx
 	driver.config = config
 
+	if driver.tokenProvider != nil {
+		connConfig, err := parseConnectionConfig(dsn, driver.config.TLSConfig)
+		if err != nil {
+			return err
+		}
+		// The auth token expires soon, e.g. the AWS RDS IAM auth token expires in 15 minutes,
+		// so we get a fresh one for every new connection in the pool.
+		driver.db = stdlib.OpenDB(*connConfig, stdlib.OptionBeforeConnect(func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+			token, err := driver.tokenProvider(ctx)
+			if err != nil {
+				return err
+			}
+			connConfig.Password = token
+			return nil
+		}))
+		return nil
+	}
+
 	connectionString, err := registerConnectionConfig(dsn, driver.config.TLSConfig)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	driver.connectionString = connectionString
 
 	db, err := sql.Open(driverName, driver.connectionString)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	driver.db = db
-	return driver, nil
+	return nil
+}
+
+func (driver *Driver) unregisterKerberosClient() {
+	if driver.kerberosClientID == "" {
+		return
+	}
+	util.UnregisterKerberosClient(driver.kerberosClientID)
+	driver.kerberosClientID = ""
+}
+
+func (driver *Driver) closeCloudSQLProxy() error {
+	if driver.cloudSQLProxy == nil {
+		return nil
+	}
+	err := driver.cloudSQLProxy.Close()
+	driver.cloudSQLProxy = nil
+	return err
 }
 
 func registerConnectionConfig(dsn string, tlsConfig db.TLSConfig) (string, error) {
-	connConfig, err := pgx.ParseConfig(dsn)
+	connConfig, err := parseConnectionConfig(dsn, tlsConfig)
 	if err != nil {
 		return "", err
 	}
+	return stdlib.RegisterConnConfig(connConfig), nil
+}
+
+func parseConnectionConfig(dsn string, tlsConfig db.TLSConfig) (*pgx.ConnConfig, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
 
 	if tlsConfig.SslCA != "" {
 		sslConfig, err := tlsConfig.GetSslConfig()
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		connConfig.TLSConfig = sslConfig
 	}
-
-	return stdlib.RegisterConnConfig(connConfig), nil
+	return connConfig, nil
 }
 
 func unregisterConnectionConfig(connectionString string) {
@@ -140,13 +252,15 @@ func unregisterConnectionConfig(connectionString string) {
 }
 
 // guessDSN will guess a valid DB connection and its database name.
-func guessDSN(username, password, hostname, port, database, sslCA, sslCert, sslKey string) (string, string, error) {
+func guessDSN(username, password, hostname, port, database, sslCA, sslCert, sslKey, sslMode, kerberosSPN string) (string, string, error) {
 	// dbname is guessed if not specified.
 	m := map[string]string{
 		"host":     hostname,
 		"port":     port,
 		"user":     username,
 		"password": password,
+		"sslmode":  sslMode,
+		"krbspn":   kerberosSPN,
 	}
 	if database != "" {
 		m["dbname"] = database
@@ -200,7 +314,9 @@ func guessDSN(username, password, hostname, port, database, sslCA, sslCert, sslK
 // Close closes the driver.
 func (driver *Driver) Close(context.Context) error {
 	unregisterConnectionConfig(driver.connectionString)
-	return driver.db.Close()
+	err := multierr.Append(driver.db.Close(), driver.closeCloudSQLProxy())
+	driver.unregisterKerberosClient()
+	return err
 }
 
 // Ping pings the database.
@@ -0,0 +1,90 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+var (
+	listRowLevelSecurityTableQuery = fmt.Sprintf(`
+	SELECT n.nspname, c.relname, c.relrowsecurity, c.relforcerowsecurity
+	FROM pg_catalog.pg_class c
+	JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	WHERE c.relkind IN ('r', 'p') AND n.nspname NOT IN (%s)
+		AND (c.relrowsecurity OR c.relforcerowsecurity OR EXISTS (SELECT 1 FROM pg_catalog.pg_policy p WHERE p.polrelid = c.oid))
+	ORDER BY n.nspname, c.relname;`, systemSchemas)
+
+	listRowLevelSecurityPolicyQuery = fmt.Sprintf(`
+	SELECT schemaname, tablename, policyname, permissive = 'PERMISSIVE', array_to_string(roles, ','), cmd,
+		COALESCE(qual, ''), COALESCE(with_check, '')
+	FROM pg_catalog.pg_policies
+	WHERE schemaname NOT IN (%s)
+	ORDER BY schemaname, tablename, policyname;`, systemSchemas)
+)
+
+// SyncRowLevelSecurity syncs the row level security of the tables with the row level security enabled or with
+// policies, ordered by schema and table name.
+func (driver *Driver) SyncRowLevelSecurity(ctx context.Context) ([]*db.TableRowLevelSecurity, error) {
+	txn, err := driver.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Rollback()
+
+	tableList, err := getRowLevelSecurity(txn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get row level security from database %q", driver.databaseName)
+	}
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+	return tableList, nil
+}
+
+func getRowLevelSecurity(txn *sql.Tx) ([]*db.TableRowLevelSecurity, error) {
+	var tableList []*db.TableRowLevelSecurity
+	tableMap := make(map[db.TableKey]*db.TableRowLevelSecurity)
+	rows, err := txn.Query(listRowLevelSecurityTableQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		table := &db.TableRowLevelSecurity{PolicyList: []*db.RowLevelSecurityPolicy{}}
+		if err := rows.Scan(&table.Schema, &table.Table, &table.Enabled, &table.Forced); err != nil {
+			return nil, err
+		}
+		tableList = append(tableList, table)
+		tableMap[db.TableKey{Schema: table.Schema, Table: table.Table}] = table
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	policyRows, err := txn.Query(listRowLevelSecurityPolicyQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer policyRows.Close()
+	for policyRows.Next() {
+		var schemaName, tableName, roles string
+		policy := &db.RowLevelSecurityPolicy{}
+		if err := policyRows.Scan(&schemaName, &tableName, &policy.Name, &policy.Permissive, &roles, &policy.Command, &policy.Using, &policy.WithCheck); err != nil {
+			return nil, err
+		}
+		policy.RoleList = strings.Split(roles, ",")
+		if table, ok := tableMap[db.TableKey{Schema: schemaName, Table: tableName}]; ok {
+			table.PolicyList = append(table.PolicyList, policy)
+		}
+	}
+	if err := policyRows.Err(); err != nil {
+		return nil, err
+	}
+	return tableList, nil
+}
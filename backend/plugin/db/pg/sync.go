@@ -104,6 +104,13 @@ func (driver *Driver) SyncDBSchema(ctx context.Context) (*storepb.DatabaseMetada
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get extensions from database %q", driver.databaseName)
 	}
+	if hasTimescaleDB(extensions) {
+		hypertables, err := getHypertables(txn)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get hypertables from database %q", driver.databaseName)
+		}
+		setHypertableOptions(tableMap, hypertables)
+	}
 
 	if err := txn.Commit(); err != nil {
 		return nil, err
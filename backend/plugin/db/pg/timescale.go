@@ -0,0 +1,266 @@
+package pg
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+const (
+	// timescaleDBExtension is the extension name of TimescaleDB.
+	timescaleDBExtension = "timescaledb"
+	// timescaleDBChunkSchema is the schema of the hypertable chunks, which are managed by TimescaleDB.
+	timescaleDBChunkSchema = "_timescaledb_internal"
+)
+
+var (
+	// simpleIdentifierRegexp matches the identifiers which don't need the double quotes.
+	simpleIdentifierRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+)
+
+// hypertable is the TimescaleDB hypertable metadata.
+type hypertable struct {
+	schema string
+	name   string
+	// timeColumn is the column of the time dimension.
+	timeColumn string
+	// chunkInterval is the interval of the time dimension, e.g. 7 days, or the integer interval for the integer time columns.
+	chunkInterval string
+	// integerInterval is true if the time column is an integer column.
+	integerInterval bool
+	// spaceColumn and numPartitions are for the optional space dimension.
+	spaceColumn   string
+	numPartitions int
+
+	compressionEnabled bool
+	compressSegmentBy  []string
+	compressOrderBy    []string
+	// compressAfter is the compress_after of the compression policy, empty if there is no compression policy.
+	compressAfter string
+}
+
+var listHypertableDimensionQuery = `
+	SELECT hypertable_schema, hypertable_name, column_name, dimension_type,
+		COALESCE(time_interval::TEXT, ''), COALESCE(integer_interval::TEXT, ''), COALESCE(num_partitions, 0)
+	FROM timescaledb_information.dimensions
+	ORDER BY hypertable_schema, hypertable_name, dimension_number;`
+
+var listHypertableCompressionQuery = `
+	SELECT h.hypertable_schema, h.hypertable_name, h.compression_enabled,
+		COALESCE(j.config->>'compress_after', '')
+	FROM timescaledb_information.hypertables h
+	LEFT JOIN timescaledb_information.jobs j
+		ON j.hypertable_schema = h.hypertable_schema AND j.hypertable_name = h.hypertable_name AND j.proc_name = 'policy_compression';`
+
+var listHypertableCompressionSettingQuery = `
+	SELECT hypertable_schema, hypertable_name, attname,
+		COALESCE(segmentby_column_index, 0), COALESCE(orderby_column_index, 0), COALESCE(orderby_asc, TRUE), COALESCE(orderby_nullsfirst, FALSE)
+	FROM timescaledb_information.compression_settings
+	ORDER BY hypertable_schema, hypertable_name, segmentby_column_index, orderby_column_index;`
+
+// hasTimescaleDB returns true if the TimescaleDB extension is installed in the database.
+func hasTimescaleDB(extensions []*storepb.ExtensionMetadata) bool {
+	for _, extension := range extensions {
+		if extension.Name == timescaleDBExtension {
+			return true
+		}
+	}
+	return false
+}
+
+// getHypertables gets the hypertables with the chunk intervals and the compression settings, ordered by schema and name.
+// It requires the TimescaleDB 2 information views.
+func getHypertables(txn *sql.Tx) ([]*hypertable, error) {
+	hypertableMap := make(map[string]*hypertable)
+	var hypertables []*hypertable
+	get := func(schema, name string) *hypertable {
+		key := fmt.Sprintf("%s.%s", schema, name)
+		if h, ok := hypertableMap[key]; ok {
+			return h
+		}
+		h := &hypertable{schema: schema, name: name}
+		hypertableMap[key] = h
+		hypertables = append(hypertables, h)
+		return h
+	}
+
+	rows, err := txn.Query(listHypertableDimensionQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get hypertable dimensions")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var schema, name, column, dimensionType, timeInterval, integerInterval string
+		var numPartitions int
+		if err := rows.Scan(&schema, &name, &column, &dimensionType, &timeInterval, &integerInterval, &numPartitions); err != nil {
+			return nil, err
+		}
+		h := get(schema, name)
+		if dimensionType == "Time" && h.timeColumn == "" {
+			h.timeColumn = column
+			h.chunkInterval = timeInterval
+			if integerInterval != "" {
+				h.chunkInterval = integerInterval
+				h.integerInterval = true
+			}
+		} else if dimensionType == "Space" && h.spaceColumn == "" {
+			h.spaceColumn = column
+			h.numPartitions = numPartitions
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	compressionRows, err := txn.Query(listHypertableCompressionQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get hypertable compression policies")
+	}
+	defer compressionRows.Close()
+	for compressionRows.Next() {
+		var schema, name, compressAfter string
+		var compressionEnabled bool
+		if err := compressionRows.Scan(&schema, &name, &compressionEnabled, &compressAfter); err != nil {
+			return nil, err
+		}
+		h := get(schema, name)
+		h.compressionEnabled = compressionEnabled
+		h.compressAfter = compressAfter
+	}
+	if err := compressionRows.Err(); err != nil {
+		return nil, err
+	}
+
+	settingRows, err := txn.Query(listHypertableCompressionSettingQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get hypertable compression settings")
+	}
+	defer settingRows.Close()
+	for settingRows.Next() {
+		var schema, name, column string
+		var segmentByIndex, orderByIndex int
+		var orderByAsc, orderByNullsFirst bool
+		if err := settingRows.Scan(&schema, &name, &column, &segmentByIndex, &orderByIndex, &orderByAsc, &orderByNullsFirst); err != nil {
+			return nil, err
+		}
+		h := get(schema, name)
+		if segmentByIndex > 0 {
+			h.compressSegmentBy = append(h.compressSegmentBy, quoteIdentifier(column))
+		}
+		if orderByIndex > 0 {
+			orderBy := quoteIdentifier(column)
+			if !orderByAsc {
+				orderBy += " DESC"
+			}
+			// The default is NULLS LAST for ASC and NULLS FIRST for DESC.
+			if orderByNullsFirst == orderByAsc {
+				if orderByNullsFirst {
+					orderBy += " NULLS FIRST"
+				} else {
+					orderBy += " NULLS LAST"
+				}
+			}
+			h.compressOrderBy = append(h.compressOrderBy, orderBy)
+		}
+	}
+	if err := settingRows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(hypertables, func(i, j int) bool {
+		if hypertables[i].schema != hypertables[j].schema {
+			return hypertables[i].schema < hypertables[j].schema
+		}
+		return hypertables[i].name < hypertables[j].name
+	})
+	return hypertables, nil
+}
+
+// setHypertableOptions sets the hypertable options as the create options of the tables.
+func setHypertableOptions(tableMap map[string][]*storepb.TableMetadata, hypertables []*hypertable) {
+	for _, h := range hypertables {
+		for _, table := range tableMap[h.schema] {
+			if table.Name == h.name {
+				table.CreateOptions = h.createOptions()
+				break
+			}
+		}
+	}
+}
+
+// createOptions returns the hypertable options, e.g. "hypertable time_column=time chunk_time_interval=7 days compress_after=30 days".
+func (h *hypertable) createOptions() string {
+	options := []string{"hypertable", fmt.Sprintf("time_column=%s", h.timeColumn), fmt.Sprintf("chunk_time_interval=%s", h.chunkInterval)}
+	if h.spaceColumn != "" {
+		options = append(options, fmt.Sprintf("partitioning_column=%s", h.spaceColumn), fmt.Sprintf("number_partitions=%d", h.numPartitions))
+	}
+	if h.compressionEnabled {
+		options = append(options, "compress")
+		if len(h.compressSegmentBy) > 0 {
+			options = append(options, fmt.Sprintf("compress_segmentby=%s", strings.Join(h.compressSegmentBy, ", ")))
+		}
+		if len(h.compressOrderBy) > 0 {
+			options = append(options, fmt.Sprintf("compress_orderby=%s", strings.Join(h.compressOrderBy, ", ")))
+		}
+	}
+	if h.compressAfter != "" {
+		options = append(options, fmt.Sprintf("compress_after=%s", h.compressAfter))
+	}
+	return strings.Join(options, " ")
+}
+
+// dumpHypertables returns the statements converting the dumped tables into the hypertables,
+// because pg_dump dumps the hypertables as the plain tables.
+func dumpHypertables(hypertables []*hypertable) string {
+	var buf strings.Builder
+	for _, h := range hypertables {
+		table := fmt.Sprintf("%s.%s", quoteIdentifier(h.schema), quoteIdentifier(h.name))
+		args := []string{quoteLiteral(table), quoteLiteral(h.timeColumn)}
+		if h.spaceColumn != "" {
+			args = append(args, fmt.Sprintf("partitioning_column => %s", quoteLiteral(h.spaceColumn)), fmt.Sprintf("number_partitions => %d", h.numPartitions))
+		}
+		args = append(args, fmt.Sprintf("chunk_time_interval => %s", h.interval(h.chunkInterval)), "if_not_exists => TRUE")
+		fmt.Fprintf(&buf, "SELECT create_hypertable(%s);\n", strings.Join(args, ", "))
+
+		if h.compressionEnabled {
+			options := []string{"timescaledb.compress"}
+			if len(h.compressSegmentBy) > 0 {
+				options = append(options, fmt.Sprintf("timescaledb.compress_segmentby = %s", quoteLiteral(strings.Join(h.compressSegmentBy, ", "))))
+			}
+			if len(h.compressOrderBy) > 0 {
+				options = append(options, fmt.Sprintf("timescaledb.compress_orderby = %s", quoteLiteral(strings.Join(h.compressOrderBy, ", "))))
+			}
+			fmt.Fprintf(&buf, "ALTER TABLE %s SET (%s);\n", table, strings.Join(options, ", "))
+		}
+		if h.compressAfter != "" {
+			fmt.Fprintf(&buf, "SELECT add_compression_policy(%s, %s, if_not_exists => TRUE);\n", quoteLiteral(table), h.interval(h.compressAfter))
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// interval returns the interval expression, the integer time columns use the integer intervals.
+func (h *hypertable) interval(value string) string {
+	if h.integerInterval {
+		return value
+	}
+	return fmt.Sprintf("INTERVAL %s", quoteLiteral(value))
+}
+
+func quoteIdentifier(name string) string {
+	if simpleIdentifierRegexp.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+}
+
+func quoteLiteral(value string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
+}
@@ -0,0 +1,42 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpHypertables(t *testing.T) {
+	hypertables := []*hypertable{
+		{
+			schema:             "public",
+			name:               "conditions",
+			timeColumn:         "time",
+			chunkInterval:      "7 days",
+			spaceColumn:        "device_id",
+			numPartitions:      4,
+			compressionEnabled: true,
+			compressSegmentBy:  []string{"device_id"},
+			compressOrderBy:    []string{"time DESC"},
+			compressAfter:      "30 days",
+		},
+		{
+			schema:          "iot",
+			name:            "Readings",
+			timeColumn:      "ts",
+			chunkInterval:   "86400",
+			integerInterval: true,
+		},
+	}
+
+	want := `SELECT create_hypertable('public.conditions', 'time', partitioning_column => 'device_id', number_partitions => 4, chunk_time_interval => INTERVAL '7 days', if_not_exists => TRUE);
+ALTER TABLE public.conditions SET (timescaledb.compress, timescaledb.compress_segmentby = 'device_id', timescaledb.compress_orderby = 'time DESC');
+SELECT add_compression_policy('public.conditions', INTERVAL '30 days', if_not_exists => TRUE);
+
+SELECT create_hypertable('iot."Readings"', 'ts', chunk_time_interval => 86400, if_not_exists => TRUE);
+
+`
+	require.Equal(t, want, dumpHypertables(hypertables))
+	require.Equal(t, "hypertable time_column=time chunk_time_interval=7 days partitioning_column=device_id number_partitions=4 compress compress_segmentby=device_id compress_orderby=time DESC compress_after=30 days", hypertables[0].createOptions())
+	require.Equal(t, "hypertable time_column=ts chunk_time_interval=86400", hypertables[1].createOptions())
+}
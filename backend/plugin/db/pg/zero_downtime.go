@@ -0,0 +1,345 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
+)
+
+const (
+	// lockNotAvailableCode is the SQLSTATE raised when the statement fails to acquire the lock within lock_timeout.
+	// https://www.postgresql.org/docs/current/errcodes-appendix.html
+	lockNotAvailableCode = "55P03"
+
+	defaultZeroDowntimeLockTimeout   = 3 * time.Second
+	defaultZeroDowntimeMaxRetries    = 10
+	defaultZeroDowntimeBatchSize     = 1000
+	zeroDowntimeRetryInterval        = 1 * time.Second
+	zeroDowntimeMaxRetryInterval     = 30 * time.Second
+	zeroDowntimeVersionFastAddColumn = 110000
+	zeroDowntimeVersionCheckNotNull  = 120000
+)
+
+var createIndexPrefixReg = regexp.MustCompile(`(?is)^(\s*CREATE\s+(UNIQUE\s+)?INDEX)\s`)
+
+// ZeroDowntimeOption is the option of the zero-downtime migration.
+type ZeroDowntimeOption struct {
+	// LockTimeout is the lock_timeout of each step, defaults to 3s.
+	LockTimeout time.Duration
+	// MaxRetries is the number of retries for each step failing to acquire the lock in time, defaults to 10.
+	MaxRetries int
+	// BatchSize is the number of rows updated by each batch of the backfill, defaults to 1000.
+	BatchSize int
+}
+
+func (o ZeroDowntimeOption) withDefaults() ZeroDowntimeOption {
+	if o.LockTimeout <= 0 {
+		o.LockTimeout = defaultZeroDowntimeLockTimeout
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = defaultZeroDowntimeMaxRetries
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = defaultZeroDowntimeBatchSize
+	}
+	return o
+}
+
+// ZeroDowntimeStep is a step of the zero-downtime migration.
+type ZeroDowntimeStep struct {
+	Statement string `json:"statement"`
+	// Batched is true for the backfill step, which runs repeatedly until no rows are affected.
+	Batched bool `json:"batched"`
+	// Cleanup is the statement executed before retrying the step, e.g. dropping the invalid index left by a failed CREATE INDEX CONCURRENTLY.
+	Cleanup string `json:"cleanup,omitempty"`
+	// Rewritten is true if the step is rewritten from a risky statement.
+	Rewritten bool `json:"rewritten"`
+}
+
+// TransformZeroDowntimeMigration rewrites the risky DDL statements into the steps holding the heavy locks as short as possible.
+//   - CREATE INDEX is rewritten to CREATE INDEX CONCURRENTLY.
+//   - SET NOT NULL is rewritten to a NOT VALID CHECK constraint validated separately. Since PostgreSQL 12, SET NOT NULL
+//     skips the table scan with the validated constraint, so the constraint is replaced by the NOT NULL afterwards.
+//   - Before PostgreSQL 11, ADD COLUMN with DEFAULT rewrites the whole table. It's rewritten to adding the column without
+//     the default, setting the default and backfilling the existing rows in batches.
+//
+// The other statements are kept as is.
+func TransformZeroDowntimeMigration(statement string, serverVersionNum int, batchSize int) ([]*ZeroDowntimeStep, error) {
+	if batchSize <= 0 {
+		batchSize = defaultZeroDowntimeBatchSize
+	}
+	singleSQLs, err := parser.SplitMultiSQL(parser.Postgres, statement)
+	if err != nil {
+		return nil, err
+	}
+	var steps []*ZeroDowntimeStep
+	for _, singleSQL := range singleSQLs {
+		if singleSQL.Empty {
+			continue
+		}
+		nodes, err := parser.Parse(parser.Postgres, parser.ParseContext{}, singleSQL.Text)
+		if err != nil {
+			return nil, err
+		}
+		if len(nodes) != 1 {
+			return nil, errors.Errorf("expect one statement, but got %d, statement: %s", len(nodes), singleSQL.Text)
+		}
+		rewritten, err := transformZeroDowntimeNode(nodes[0], singleSQL.Text, serverVersionNum, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		if rewritten == nil {
+			steps = append(steps, &ZeroDowntimeStep{Statement: strings.TrimSpace(singleSQL.Text)})
+			continue
+		}
+		steps = append(steps, rewritten...)
+	}
+	return steps, nil
+}
+
+// transformZeroDowntimeNode returns nil if the statement is kept as is.
+func transformZeroDowntimeNode(node ast.Node, text string, serverVersionNum int, batchSize int) ([]*ZeroDowntimeStep, error) {
+	switch node := node.(type) {
+	case *ast.CreateIndexStmt:
+		if node.Concurrently {
+			return nil, nil
+		}
+		step := &ZeroDowntimeStep{
+			Statement: strings.TrimSpace(createIndexPrefixReg.ReplaceAllString(text, "$1 CONCURRENTLY ")),
+			Rewritten: true,
+		}
+		if node.Index.Name != "" {
+			index := quoteIdentifier(node.Index.Name)
+			if node.Index.Table != nil && node.Index.Table.Schema != "" {
+				index = fmt.Sprintf("%s.%s", quoteIdentifier(node.Index.Table.Schema), index)
+			}
+			step.Cleanup = fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s;", index)
+		}
+		return []*ZeroDowntimeStep{step}, nil
+	case *ast.AlterTableStmt:
+		if len(node.AlterItemList) != 1 {
+			return nil, nil
+		}
+		switch item := node.AlterItemList[0].(type) {
+		case *ast.SetNotNullStmt:
+			return setNotNullSteps(node.Table, item.ColumnName, serverVersionNum), nil
+		case *ast.AddColumnListStmt:
+			if serverVersionNum >= zeroDowntimeVersionFastAddColumn || len(item.ColumnList) != 1 {
+				return nil, nil
+			}
+			return addColumnWithDefaultSteps(node.Table, item, serverVersionNum, batchSize)
+		}
+	}
+	return nil, nil
+}
+
+func addColumnWithDefaultSteps(table *ast.TableDef, item *ast.AddColumnListStmt, serverVersionNum int, batchSize int) ([]*ZeroDowntimeStep, error) {
+	column := item.ColumnList[0]
+	var defaultExpression string
+	notNull := false
+	var constraints []*ast.ConstraintDef
+	for _, constraint := range column.ConstraintList {
+		switch constraint.Type {
+		case ast.ConstraintTypeDefault:
+			defaultExpression = constraint.Expression.Text()
+		case ast.ConstraintTypeNotNull:
+			notNull = true
+		default:
+			constraints = append(constraints, constraint)
+		}
+	}
+	// Adding a column with the NULL default doesn't rewrite the table, and the other constraints
+	// such as PRIMARY KEY and UNIQUE can't be added without blocking anyway.
+	if defaultExpression == "" || strings.EqualFold(defaultExpression, "NULL") || len(constraints) > 0 {
+		return nil, nil
+	}
+
+	addColumn, err := parser.Deparse(parser.Postgres, parser.DeparseContext{}, &ast.AlterTableStmt{
+		Table: table,
+		AlterItemList: []ast.Node{
+			&ast.AddColumnListStmt{
+				Table:       table,
+				IfNotExists: item.IfNotExists,
+				ColumnList: []*ast.ColumnDef{
+					{
+						ColumnName: column.ColumnName,
+						Type:       column.Type,
+						Collation:  column.Collation,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tableName, columnName := quoteTable(table), quoteIdentifier(column.ColumnName)
+	steps := []*ZeroDowntimeStep{
+		{
+			Statement: strings.Join(strings.Fields(addColumn), " "),
+			Rewritten: true,
+		},
+		{
+			Statement: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", tableName, columnName, defaultExpression),
+			Rewritten: true,
+		},
+		{
+			Statement: fmt.Sprintf("UPDATE %s SET %s = DEFAULT WHERE ctid IN (SELECT ctid FROM %s WHERE %s IS NULL LIMIT %d);", tableName, columnName, tableName, columnName, batchSize),
+			Batched:   true,
+			Rewritten: true,
+		},
+	}
+	if notNull {
+		steps = append(steps, setNotNullSteps(table, column.ColumnName, serverVersionNum)...)
+	}
+	return steps, nil
+}
+
+func setNotNullSteps(table *ast.TableDef, column string, serverVersionNum int) []*ZeroDowntimeStep {
+	tableName, columnName := quoteTable(table), quoteIdentifier(column)
+	constraintName := quoteIdentifier(fmt.Sprintf("%s_%s_not_null", table.Name, column))
+	steps := []*ZeroDowntimeStep{
+		{
+			Statement: fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s IS NOT NULL) NOT VALID;", tableName, constraintName, columnName),
+			Rewritten: true,
+		},
+		{
+			Statement: fmt.Sprintf("ALTER TABLE %s VALIDATE CONSTRAINT %s;", tableName, constraintName),
+			Rewritten: true,
+		},
+	}
+	// Before PostgreSQL 12, SET NOT NULL always scans the table, so the validated CHECK constraint is kept instead.
+	if serverVersionNum >= zeroDowntimeVersionCheckNotNull {
+		steps = append(steps,
+			&ZeroDowntimeStep{
+				Statement: fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", tableName, columnName),
+				Rewritten: true,
+			},
+			&ZeroDowntimeStep{
+				Statement: fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", tableName, constraintName),
+				Rewritten: true,
+			},
+		)
+	}
+	return steps
+}
+
+func quoteTable(table *ast.TableDef) string {
+	if table.Schema != "" {
+		return fmt.Sprintf("%s.%s", quoteIdentifier(table.Schema), quoteIdentifier(table.Name))
+	}
+	return quoteIdentifier(table.Name)
+}
+
+// ExecuteZeroDowntimeMigration executes the statement in the zero-downtime steps rewritten by TransformZeroDowntimeMigration.
+// Each step runs in its own transaction with the lock_timeout, so that a step waiting for the lock never blocks the other
+// queries queued behind it for long. The step failing to acquire the lock is retried with backoff.
+func (driver *Driver) ExecuteZeroDowntimeMigration(ctx context.Context, statement string, option ZeroDowntimeOption) (int64, error) {
+	option = option.withDefaults()
+	version, err := driver.getVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+	serverVersionNum, err := strconv.Atoi(version)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse server version %q", version)
+	}
+	steps, err := TransformZeroDowntimeMigration(statement, serverVersionNum, option.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	owner, err := driver.GetCurrentDatabaseOwner()
+	if err != nil {
+		return 0, err
+	}
+
+	conn, err := driver.db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	// Set the session role to the database owner so that the owner of created objects will be the same as the database owner.
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET ROLE '%s'", owner)); err != nil {
+		return 0, err
+	}
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET lock_timeout = %d", option.LockTimeout.Milliseconds())); err != nil {
+		return 0, err
+	}
+	defer func() {
+		// Reset the session before returning the connection to the pool.
+		if _, err := conn.ExecContext(context.Background(), "RESET lock_timeout; RESET ROLE;"); err != nil {
+			log.Warn("failed to reset the session of zero-downtime migration", zap.Error(err))
+		}
+	}()
+
+	totalRowsAffected := int64(0)
+	for _, step := range steps {
+		for {
+			rowsAffected, err := executeZeroDowntimeStep(ctx, conn, step, option.MaxRetries)
+			if err != nil {
+				return 0, errors.Wrapf(err, "failed to execute zero-downtime migration step %q", step.Statement)
+			}
+			totalRowsAffected += rowsAffected
+			if !step.Batched || rowsAffected == 0 {
+				break
+			}
+		}
+	}
+	return totalRowsAffected, nil
+}
+
+func executeZeroDowntimeStep(ctx context.Context, conn *sql.Conn, step *ZeroDowntimeStep, maxRetries int) (int64, error) {
+	interval := zeroDowntimeRetryInterval
+	for retry := 0; ; retry++ {
+		sqlResult, err := conn.ExecContext(ctx, step.Statement)
+		if err == nil {
+			rowsAffected, err := sqlResult.RowsAffected()
+			if err != nil {
+				// Since we cannot differentiate DDL and DML yet, we have to ignore the error.
+				log.Debug("rowsAffected returns error", zap.Error(err))
+				return 0, nil
+			}
+			return rowsAffected, nil
+		}
+		if !isLockNotAvailableError(err) || retry >= maxRetries {
+			return 0, err
+		}
+		log.Debug("zero-downtime migration step failed to acquire the lock, retrying",
+			zap.String("statement", step.Statement),
+			zap.Int("retry", retry+1),
+			zap.Duration("interval", interval),
+		)
+		if step.Cleanup != "" {
+			if _, err := conn.ExecContext(ctx, step.Cleanup); err != nil {
+				return 0, err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+		if interval > zeroDowntimeMaxRetryInterval {
+			interval = zeroDowntimeMaxRetryInterval
+		}
+	}
+}
+
+func isLockNotAvailableError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == lockNotAvailableCode
+}
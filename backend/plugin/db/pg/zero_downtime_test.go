@@ -0,0 +1,88 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	// Register the PostgreSQL parser.
+	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/engine/pg"
+)
+
+func TestTransformZeroDowntimeMigration(t *testing.T) {
+	tests := []struct {
+		statement        string
+		serverVersionNum int
+		want             []*ZeroDowntimeStep
+	}{
+		{
+			statement:        `CREATE INDEX idx_t_a ON t(a);`,
+			serverVersionNum: 140000,
+			want: []*ZeroDowntimeStep{
+				{
+					Statement: `CREATE INDEX CONCURRENTLY idx_t_a ON t(a);`,
+					Cleanup:   `DROP INDEX CONCURRENTLY IF EXISTS idx_t_a;`,
+					Rewritten: true,
+				},
+			},
+		},
+		{
+			statement:        `CREATE UNIQUE INDEX CONCURRENTLY idx_t_a ON t(a);`,
+			serverVersionNum: 140000,
+			want: []*ZeroDowntimeStep{
+				{Statement: `CREATE UNIQUE INDEX CONCURRENTLY idx_t_a ON t(a);`},
+			},
+		},
+		{
+			statement:        `ALTER TABLE public.t ALTER COLUMN a SET NOT NULL;`,
+			serverVersionNum: 140000,
+			want: []*ZeroDowntimeStep{
+				{Statement: `ALTER TABLE public.t ADD CONSTRAINT t_a_not_null CHECK (a IS NOT NULL) NOT VALID;`, Rewritten: true},
+				{Statement: `ALTER TABLE public.t VALIDATE CONSTRAINT t_a_not_null;`, Rewritten: true},
+				{Statement: `ALTER TABLE public.t ALTER COLUMN a SET NOT NULL;`, Rewritten: true},
+				{Statement: `ALTER TABLE public.t DROP CONSTRAINT t_a_not_null;`, Rewritten: true},
+			},
+		},
+		{
+			statement:        `ALTER TABLE t ALTER COLUMN a SET NOT NULL;`,
+			serverVersionNum: 110005,
+			want: []*ZeroDowntimeStep{
+				{Statement: `ALTER TABLE t ADD CONSTRAINT t_a_not_null CHECK (a IS NOT NULL) NOT VALID;`, Rewritten: true},
+				{Statement: `ALTER TABLE t VALIDATE CONSTRAINT t_a_not_null;`, Rewritten: true},
+			},
+		},
+		{
+			statement:        `ALTER TABLE t ADD COLUMN b int NOT NULL DEFAULT 0;`,
+			serverVersionNum: 140000,
+			want: []*ZeroDowntimeStep{
+				{Statement: `ALTER TABLE t ADD COLUMN b int NOT NULL DEFAULT 0;`},
+			},
+		},
+		{
+			statement:        `ALTER TABLE t ADD COLUMN b int NOT NULL DEFAULT 0;`,
+			serverVersionNum: 100005,
+			want: []*ZeroDowntimeStep{
+				{Statement: `ALTER TABLE "t" ADD COLUMN "b" integer;`, Rewritten: true},
+				{Statement: `ALTER TABLE t ALTER COLUMN b SET DEFAULT 0;`, Rewritten: true},
+				{Statement: `UPDATE t SET b = DEFAULT WHERE ctid IN (SELECT ctid FROM t WHERE b IS NULL LIMIT 500);`, Batched: true, Rewritten: true},
+				{Statement: `ALTER TABLE t ADD CONSTRAINT t_b_not_null CHECK (b IS NOT NULL) NOT VALID;`, Rewritten: true},
+				{Statement: `ALTER TABLE t VALIDATE CONSTRAINT t_b_not_null;`, Rewritten: true},
+			},
+		},
+		{
+			statement:        "ALTER TABLE t ADD COLUMN b int UNIQUE DEFAULT 0;\nDROP TABLE t2;",
+			serverVersionNum: 100005,
+			want: []*ZeroDowntimeStep{
+				{Statement: `ALTER TABLE t ADD COLUMN b int UNIQUE DEFAULT 0;`},
+				{Statement: `DROP TABLE t2;`},
+			},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := TransformZeroDowntimeMigration(test.statement, test.serverVersionNum, 500)
+		a.NoError(err, test.statement)
+		a.Equal(test.want, got, test.statement)
+	}
+}
@@ -105,8 +105,9 @@ func (*Driver) GetType() db.Type {
 }
 
 // GetDB gets the database.
+// Redis doesn't support database/sql, so it returns nil for the callers that take an optional connection such as SQL review.
 func (*Driver) GetDB() *sql.DB {
-	panic("redis: not supported")
+	return nil
 }
 
 // Execute will execute the statement. For CREATE DATABASE statement, some types of databases such as Postgres
@@ -3,6 +3,7 @@ package db
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
 
 	"github.com/pkg/errors"
 )
@@ -72,3 +73,28 @@ func (tc TLSConfig) GetSslConfig() (*tls.Config, error) {
 	}
 	return cfg, nil
 }
+
+// GetEarliestExpiringCertificate returns the certificate expiring first in the PEM bundle,
+// or nil if there is no certificate in the bundle.
+func GetEarliestExpiringCertificate(pemBundle string) (*x509.Certificate, error) {
+	var earliest *x509.Certificate
+	rest := []byte(pemBundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse certificate")
+		}
+		if earliest == nil || cert.NotAfter.Before(earliest.NotAfter) {
+			earliest = cert
+		}
+	}
+	return earliest, nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEarliestExpiringCertificate(t *testing.T) {
+	a := require.New(t)
+
+	now := time.Now()
+	later := generateCertificatePEM(t, "later", now.Add(48*time.Hour))
+	sooner := generateCertificatePEM(t, "sooner", now.Add(24*time.Hour))
+
+	cert, err := GetEarliestExpiringCertificate(later + sooner)
+	a.NoError(err)
+	a.Equal("sooner", cert.Subject.CommonName)
+
+	// Non-certificate blocks such as the private key are skipped.
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("key")}))
+	cert, err = GetEarliestExpiringCertificate(privateKey + later)
+	a.NoError(err)
+	a.Equal("later", cert.Subject.CommonName)
+
+	cert, err = GetEarliestExpiringCertificate("")
+	a.NoError(err)
+	a.Nil(cert)
+
+	_, err = GetEarliestExpiringCertificate(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")})))
+	a.ErrorContains(err, "failed to parse certificate")
+}
+
+func generateCertificatePEM(t *testing.T, commonName string, notAfter time.Time) string {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
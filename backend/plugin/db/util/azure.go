@@ -0,0 +1,49 @@
+package util
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+const (
+	// AzureSQLScope is the scope of the access token for Azure SQL Database.
+	AzureSQLScope = "https://database.windows.net//.default"
+	// AzurePostgreSQLScope is the scope of the access token for Azure Database for PostgreSQL.
+	AzurePostgreSQLScope = "https://ossrdbms-aad.database.windows.net/.default"
+)
+
+// GetAzureADTokenProvider returns the function to get the Azure AD access token of the scope.
+// The credential caches the token and refreshes it before expiry, so the function should be called for every new connection.
+func GetAzureADTokenProvider(azureCredential db.AzureCredential, scope string) (func(ctx context.Context) (string, error), error) {
+	credential, err := getAzureTokenCredential(azureCredential)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Azure credential")
+	}
+	return func(ctx context.Context) (string, error) {
+		token, err := credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{scope}})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get Azure AD access token")
+		}
+		return token.Token, nil
+	}, nil
+}
+
+func getAzureTokenCredential(azureCredential db.AzureCredential) (azcore.TokenCredential, error) {
+	if azureCredential.ClientSecret != "" {
+		if azureCredential.TenantID == "" || azureCredential.ClientID == "" {
+			return nil, errors.Errorf("tenant ID and client ID must be set for the service principal")
+		}
+		return azidentity.NewClientSecretCredential(azureCredential.TenantID, azureCredential.ClientID, azureCredential.ClientSecret, nil)
+	}
+	options := &azidentity.ManagedIdentityCredentialOptions{}
+	if azureCredential.ClientID != "" {
+		options.ID = azidentity.ClientID(azureCredential.ClientID)
+	}
+	return azidentity.NewManagedIdentityCredential(options)
+}
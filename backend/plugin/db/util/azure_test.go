@@ -0,0 +1,35 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestGetAzureADTokenProvider(t *testing.T) {
+	a := require.New(t)
+
+	tests := []struct {
+		credential db.AzureCredential
+		wantErr    bool
+	}{
+		// System-assigned managed identity.
+		{credential: db.AzureCredential{}},
+		// User-assigned managed identity.
+		{credential: db.AzureCredential{ClientID: "00000000-0000-0000-0000-000000000000"}},
+		// Service principal.
+		{credential: db.AzureCredential{TenantID: "00000000-0000-0000-0000-000000000000", ClientID: "00000000-0000-0000-0000-000000000000", ClientSecret: "secret"}},
+		{credential: db.AzureCredential{ClientID: "00000000-0000-0000-0000-000000000000", ClientSecret: "secret"}, wantErr: true},
+	}
+	for _, test := range tests {
+		provider, err := GetAzureADTokenProvider(test.credential, AzureSQLScope)
+		if test.wantErr {
+			a.Error(err)
+			continue
+		}
+		a.NoError(err)
+		a.NotNil(provider)
+	}
+}
@@ -0,0 +1,113 @@
+package util
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+
+	"cloud.google.com/go/cloudsqlconn"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// CloudSQLProxy is the local proxy forwarding the connections to the GCP Cloud SQL instance through the Cloud SQL connector.
+// The connector dials with the ephemeral certificate, and the IAM principal for the IAM authentication, so the drivers
+// and the CLI tools such as pg_dump and mysqldump connect to the local address in plain text without the client certificates.
+type CloudSQLProxy struct {
+	connectionName string
+	dialOptions    []cloudsqlconn.DialOption
+	dialer         *cloudsqlconn.Dialer
+	listener       net.Listener
+	wg             sync.WaitGroup
+}
+
+// NewCloudSQLProxy creates the dialer for the Cloud SQL instance and starts listening on a random loopback port.
+func NewCloudSQLProxy(ctx context.Context, connCfg db.ConnectionConfig) (*CloudSQLProxy, error) {
+	cloudSQLConfig := connCfg.GCPCloudSQLConfig
+	var options []cloudsqlconn.Option
+	if cloudSQLConfig.CredentialsJSON != "" {
+		options = append(options, cloudsqlconn.WithCredentialsJSON([]byte(cloudSQLConfig.CredentialsJSON)))
+	}
+	if connCfg.AuthenticationType == db.AuthenticationTypeGCPCloudSQLIAM {
+		options = append(options, cloudsqlconn.WithIAMAuthN())
+	}
+	dialer, err := cloudsqlconn.NewDialer(ctx, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Cloud SQL dialer")
+	}
+	// Fail early for the invalid connection name or the missing permissions, instead of resetting the forwarded connections.
+	if _, err := dialer.EngineVersion(ctx, cloudSQLConfig.ConnectionName); err != nil {
+		return nil, multierr.Append(errors.Wrapf(err, "failed to get Cloud SQL instance %q", cloudSQLConfig.ConnectionName), dialer.Close())
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, multierr.Append(errors.Wrap(err, "failed to listen for Cloud SQL proxy"), dialer.Close())
+	}
+
+	proxy := &CloudSQLProxy{
+		connectionName: cloudSQLConfig.ConnectionName,
+		dialer:         dialer,
+		listener:       listener,
+	}
+	if cloudSQLConfig.UsePrivateIP {
+		proxy.dialOptions = append(proxy.dialOptions, cloudsqlconn.WithPrivateIP())
+	}
+	proxy.wg.Add(1)
+	go proxy.serve()
+	return proxy, nil
+}
+
+// Host returns the host of the local address.
+func (proxy *CloudSQLProxy) Host() string {
+	return proxy.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port returns the port of the local address.
+func (proxy *CloudSQLProxy) Port() string {
+	return strconv.Itoa(proxy.listener.Addr().(*net.TCPAddr).Port)
+}
+
+func (proxy *CloudSQLProxy) serve() {
+	defer proxy.wg.Done()
+	for {
+		conn, err := proxy.listener.Accept()
+		if err != nil {
+			return
+		}
+		go proxy.forward(conn)
+	}
+}
+
+func (proxy *CloudSQLProxy) forward(conn net.Conn) {
+	defer conn.Close()
+	remote, err := proxy.dialer.Dial(context.Background(), proxy.connectionName, proxy.dialOptions...)
+	if err != nil {
+		log.Warn("failed to dial Cloud SQL instance", zap.String("connectionName", proxy.connectionName), zap.Error(err))
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(remote, conn)
+		// Unblock the copy below once the client is gone.
+		remote.Close()
+		close(done)
+	}()
+	_, _ = io.Copy(conn, remote)
+	conn.Close()
+	<-done
+}
+
+// Close stops listening and closes the dialer.
+func (proxy *CloudSQLProxy) Close() error {
+	err := proxy.listener.Close()
+	proxy.wg.Wait()
+	return multierr.Append(err, proxy.dialer.Close())
+}
@@ -0,0 +1,26 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestNewCloudSQLProxyInvalidConnectionName(t *testing.T) {
+	a := require.New(t)
+
+	credentialsJSON := `{"type": "authorized_user", "client_id": "bytebase", "client_secret": "bytebase", "refresh_token": "bytebase"}`
+	for _, connectionName := range []string{"", "instance", "project:instance"} {
+		_, err := NewCloudSQLProxy(context.Background(), db.ConnectionConfig{
+			AuthenticationType: db.AuthenticationTypeGCPCloudSQLIAM,
+			GCPCloudSQLConfig: db.GCPCloudSQLConfig{
+				ConnectionName:  connectionName,
+				CredentialsJSON: credentialsJSON,
+			},
+		})
+		a.ErrorContains(err, "failed to get Cloud SQL instance")
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/masker"
 )
 
 // FormatErrorWithQuery will format the error with failed query.
@@ -138,6 +139,25 @@ func Query(ctx context.Context, dbType db.Type, conn *sql.Conn, statement string
 		statement = getStatementWithResultLimit(statement, limit)
 	}
 
+	data := []any{}
+	columnNames, columnTypeNames, fieldMaskInfo, err := QueryRows(ctx, dbType, conn, statement, queryContext, func(row []any) error {
+		data = append(data, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []any{columnNames, columnTypeNames, data, fieldMaskInfo}, nil
+}
+
+// ErrSkipRemainingRows is returned by the onRow of QueryRows to skip the remaining rows. It's not returned by QueryRows.
+var ErrSkipRemainingRows = errors.New("skip remaining rows")
+
+// QueryRows executes the readonly query and calls onRow for each row, so that the caller can consume a large
+// result without holding it in memory. It returns the column names, the column type names and the sensitive flags.
+// The statement is executed as it is, and the queryContext.Limit isn't applied.
+func QueryRows(ctx context.Context, dbType db.Type, conn *sql.Conn, statement string, queryContext *db.QueryContext, onRow func(row []any) error) ([]string, []string, []bool, error) {
+	readOnly := queryContext.ReadOnly
 	// TiDB doesn't support READ ONLY transactions. We have to skip the flag for it.
 	// https://github.com/pingcap/tidb/issues/34626
 	// Clickhouse doesn't support READ ONLY transactions (Error: sql: driver does not support read-only transactions).
@@ -148,42 +168,57 @@ func Query(ctx context.Context, dbType db.Type, conn *sql.Conn, statement string
 	}
 	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: readOnly})
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	defer tx.Rollback()
 
 	rows, err := tx.QueryContext(ctx, statement)
 	if err != nil {
-		return nil, FormatErrorWithQuery(err, statement)
+		return nil, nil, nil, FormatErrorWithQuery(err, statement)
 	}
 	defer rows.Close()
 
 	columnNames, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	fieldList, err := extractSensitiveField(dbType, statement, queryContext.CurrentDatabase, queryContext.SensitiveSchemaInfo)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to extract sensitive fields: %q", statement)
+		return nil, nil, nil, errors.Wrapf(err, "failed to extract sensitive fields: %q", statement)
 	}
 
 	if len(fieldList) != 0 && len(fieldList) != len(columnNames) {
-		return nil, errors.Errorf("failed to extract sensitive fields: %q", statement)
+		return nil, nil, nil, errors.Errorf("failed to extract sensitive fields: %q", statement)
 	}
 
 	var fieldMaskInfo []bool
+	rowMasker := newRowMasker(columnNames, queryContext.SensitiveSchemaInfo)
 	for i := range columnNames {
 		if len(fieldList) > 0 && fieldList[i].Sensitive {
 			fieldMaskInfo = append(fieldMaskInfo, true)
+			m, err := masker.NewMasker(fieldList[i].MaskType, queryContext.SensitiveSchemaInfo.MaskingSecret)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			var condition *masker.Condition
+			if fieldList[i].MaskCondition != "" {
+				if condition, err = masker.NewCondition(fieldList[i].MaskCondition); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+			rowMasker.maskers = append(rowMasker.maskers, m)
+			rowMasker.conditions = append(rowMasker.conditions, condition)
 		} else {
 			fieldMaskInfo = append(fieldMaskInfo, false)
+			rowMasker.maskers = append(rowMasker.maskers, nil)
+			rowMasker.conditions = append(rowMasker.conditions, nil)
 		}
 	}
 
 	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	var columnTypeNames []string
@@ -193,16 +228,24 @@ func Query(ctx context.Context, dbType db.Type, conn *sql.Conn, statement string
 		columnTypeNames = append(columnTypeNames, strings.ToUpper(v.DatabaseTypeName()))
 	}
 
-	data, err := readRows(rows, dbType, columnTypes, columnTypeNames, fieldList)
-	if err != nil {
-		return nil, err
+	for rows.Next() {
+		row, err := scanRow(rows, dbType, columnTypes, columnTypeNames, rowMasker)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if err := onRow(row); err != nil {
+			if errors.Is(err, ErrSkipRemainingRows) {
+				return columnNames, columnTypeNames, fieldMaskInfo, nil
+			}
+			return nil, nil, nil, err
+		}
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	return []any{columnNames, columnTypeNames, data, fieldMaskInfo}, nil
+	return columnNames, columnTypeNames, fieldMaskInfo, nil
 }
 
 // query will execute a query.
@@ -249,67 +292,134 @@ func queryAdmin(ctx context.Context, dbType db.Type, conn *sql.Conn, statement s
 	return []any{columnNames, columnTypeNames, data, sensitiveInfo}, nil
 }
 
-func readRows(rows *sql.Rows, dbType db.Type, columnTypes []*sql.ColumnType, columnTypeNames []string, fieldList []db.SensitiveField) ([]any, error) {
-	if dbType == db.ClickHouse {
-		return readRowsForClickhouse(rows, columnTypes, columnTypeNames, fieldList)
-	}
+func readRows(rows *sql.Rows, dbType db.Type, columnTypes []*sql.ColumnType, columnTypeNames []string, rowMasker *rowMasker) ([]any, error) {
 	data := []any{}
 	for rows.Next() {
-		scanArgs := make([]any, len(columnTypes))
-		for i, v := range columnTypeNames {
-			// TODO(steven need help): Consult a common list of data types from database driver documentation. e.g. MySQL,PostgreSQL.
-			switch v {
-			case "VARCHAR", "TEXT", "UUID", "TIMESTAMP":
-				scanArgs[i] = new(sql.NullString)
-			case "BOOL":
-				scanArgs[i] = new(sql.NullBool)
-			case "INT", "INTEGER":
-				scanArgs[i] = new(sql.NullInt64)
-			case "FLOAT":
-				scanArgs[i] = new(sql.NullFloat64)
-			default:
-				scanArgs[i] = new(sql.NullString)
-			}
-		}
-
-		if err := rows.Scan(scanArgs...); err != nil {
+		rowData, err := scanRow(rows, dbType, columnTypes, columnTypeNames, rowMasker)
+		if err != nil {
 			return nil, err
 		}
+		data = append(data, rowData)
+	}
+	return data, nil
+}
 
-		rowData := []any{}
-		for i := range columnTypes {
-			if len(fieldList) > 0 && fieldList[i].Sensitive {
-				rowData = append(rowData, "******")
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullBool); ok && v.Valid {
-				rowData = append(rowData, v.Bool)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullString); ok && v.Valid {
-				rowData = append(rowData, v.String)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullInt64); ok && v.Valid {
-				rowData = append(rowData, v.Int64)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullInt32); ok && v.Valid {
-				rowData = append(rowData, v.Int32)
-				continue
+// scanRow scans the row, and masks the sensitive values with the row masker, nil means not masked.
+func scanRow(rows *sql.Rows, dbType db.Type, columnTypes []*sql.ColumnType, columnTypeNames []string, rowMasker *rowMasker) ([]any, error) {
+	var rowData []any
+	var err error
+	if dbType == db.ClickHouse {
+		rowData, err = scanRowForClickhouse(rows, columnTypes, columnTypeNames)
+	} else {
+		rowData, err = scanRowForSQL(rows, columnTypes, columnTypeNames)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rowMasker != nil {
+		rowMasker.mask(rowData)
+	}
+	return rowData, nil
+}
+
+// rowMasker masks the sensitive values of the rows.
+type rowMasker struct {
+	columnNames []string
+	requester   map[string]string
+	// maskers are the maskers of the columns, nil means not masked.
+	maskers []masker.Masker
+	// conditions are the conditions deciding whether to mask the columns, nil means always masked.
+	conditions []*masker.Condition
+}
+
+func newRowMasker(columnNames []string, schemaInfo *db.SensitiveSchemaInfo) *rowMasker {
+	requester := map[string]string{}
+	if schemaInfo != nil && schemaInfo.Requester != nil {
+		requester["email"] = schemaInfo.Requester.Email
+		requester["name"] = schemaInfo.Requester.Name
+		requester["role"] = schemaInfo.Requester.Role
+	}
+	return &rowMasker{
+		columnNames: columnNames,
+		requester:   requester,
+	}
+}
+
+// mask masks the sensitive values of the row in place. The conditions are evaluated over the unmasked values, so the
+// mask of a column doesn't depend on the others.
+func (m *rowMasker) mask(rowData []any) {
+	var row map[string]any
+	masked := make([]any, len(rowData))
+	copy(masked, rowData)
+	for i, maskerOfColumn := range m.maskers {
+		if maskerOfColumn == nil {
+			continue
+		}
+		if condition := m.conditions[i]; condition != nil {
+			if row == nil {
+				row = make(map[string]any)
+				for j, name := range m.columnNames {
+					row[name] = rowData[j]
+				}
 			}
-			if v, ok := (scanArgs[i]).(*sql.NullFloat64); ok && v.Valid {
-				rowData = append(rowData, v.Float64)
+			if !condition.ShouldMask(row, m.requester) {
 				continue
 			}
-			// If none of them match, set nil to its value.
-			rowData = append(rowData, nil)
 		}
+		masked[i] = maskerOfColumn.Mask(rowData[i])
+	}
+	copy(rowData, masked)
+}
 
-		data = append(data, rowData)
+func scanRowForSQL(rows *sql.Rows, columnTypes []*sql.ColumnType, columnTypeNames []string) ([]any, error) {
+	scanArgs := make([]any, len(columnTypes))
+	for i, v := range columnTypeNames {
+		// TODO(steven need help): Consult a common list of data types from database driver documentation. e.g. MySQL,PostgreSQL.
+		switch v {
+		case "VARCHAR", "TEXT", "UUID", "TIMESTAMP":
+			scanArgs[i] = new(sql.NullString)
+		case "BOOL":
+			scanArgs[i] = new(sql.NullBool)
+		case "INT", "INTEGER":
+			scanArgs[i] = new(sql.NullInt64)
+		case "FLOAT":
+			scanArgs[i] = new(sql.NullFloat64)
+		default:
+			scanArgs[i] = new(sql.NullString)
+		}
 	}
 
-	return data, nil
+	if err := rows.Scan(scanArgs...); err != nil {
+		return nil, err
+	}
+
+	rowData := []any{}
+	for i := range columnTypes {
+		if v, ok := (scanArgs[i]).(*sql.NullBool); ok && v.Valid {
+			rowData = append(rowData, v.Bool)
+			continue
+		}
+		if v, ok := (scanArgs[i]).(*sql.NullString); ok && v.Valid {
+			rowData = append(rowData, v.String)
+			continue
+		}
+		if v, ok := (scanArgs[i]).(*sql.NullInt64); ok && v.Valid {
+			rowData = append(rowData, v.Int64)
+			continue
+		}
+		if v, ok := (scanArgs[i]).(*sql.NullInt32); ok && v.Valid {
+			rowData = append(rowData, v.Int32)
+			continue
+		}
+		if v, ok := (scanArgs[i]).(*sql.NullFloat64); ok && v.Valid {
+			rowData = append(rowData, v.Float64)
+			continue
+		}
+		// If none of them match, set nil to its value.
+		rowData = append(rowData, nil)
+	}
+
+	return rowData, nil
 }
 
 func getStatementWithResultLimit(stmt string, limit int) string {
@@ -501,211 +611,200 @@ func ConvertYesNo(s string) (bool, error) {
 	}
 }
 
-func readRowsForClickhouse(rows *sql.Rows, columnTypes []*sql.ColumnType, columnTypeNames []string, fieldList []db.SensitiveField) ([]any, error) {
-	data := []any{}
-
-	for rows.Next() {
-		cols := make([]any, len(columnTypes))
-		for i, name := range columnTypeNames {
-			// The ClickHouse driver uses *Type rather than sql.NullType to scan nullable fields
-			// as described in https://github.com/ClickHouse/clickhouse-go/issues/754
-			// TODO: remove this workaround once fixed.
-			if strings.HasPrefix(name, "TUPLE") || strings.HasPrefix(name, "ARRAY") || strings.HasPrefix(name, "MAP") {
-				// For TUPLE, ARRAY, MAP type in ClickHouse, we pass any and the driver will do the rest.
-				var it any
-				cols[i] = &it
-			} else {
-				// We use ScanType to get the correct *Type and then do type assertions
-				// following https://github.com/ClickHouse/clickhouse-go/blob/main/TYPES.md
-				cols[i] = reflect.New(columnTypes[i].ScanType()).Interface()
-			}
-		}
-
-		if err := rows.Scan(cols...); err != nil {
-			return nil, err
+func scanRowForClickhouse(rows *sql.Rows, columnTypes []*sql.ColumnType, columnTypeNames []string) ([]any, error) {
+	cols := make([]any, len(columnTypes))
+	for i, name := range columnTypeNames {
+		// The ClickHouse driver uses *Type rather than sql.NullType to scan nullable fields
+		// as described in https://github.com/ClickHouse/clickhouse-go/issues/754
+		// TODO: remove this workaround once fixed.
+		if strings.HasPrefix(name, "TUPLE") || strings.HasPrefix(name, "ARRAY") || strings.HasPrefix(name, "MAP") {
+			// For TUPLE, ARRAY, MAP type in ClickHouse, we pass any and the driver will do the rest.
+			var it any
+			cols[i] = &it
+		} else {
+			// We use ScanType to get the correct *Type and then do type assertions
+			// following https://github.com/ClickHouse/clickhouse-go/blob/main/TYPES.md
+			cols[i] = reflect.New(columnTypes[i].ScanType()).Interface()
 		}
+	}
 
-		rowData := []any{}
-		for i := range cols {
-			if len(fieldList) > 0 && fieldList[i].Sensitive {
-				rowData = append(rowData, "******")
-				continue
-			}
-
-			// handle TUPLE ARRAY MAP
-			if v, ok := cols[i].(*any); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
+	if err := rows.Scan(cols...); err != nil {
+		return nil, err
+	}
 
-			// not nullable
-			if v, ok := cols[i].(*int); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*int8); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*int16); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*int32); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*int64); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*uint); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*uint8); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*uint16); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*uint32); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*uint64); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*float32); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*float64); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*string); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*bool); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*time.Time); ok && v != nil {
-				rowData = append(rowData, *v)
-				continue
-			}
-			if v, ok := cols[i].(*big.Int); ok && v != nil {
-				rowData = append(rowData, v.String())
-				continue
-			}
-			if v, ok := cols[i].(*decimal.Decimal); ok && v != nil {
-				rowData = append(rowData, v.String())
-				continue
-			}
-			if v, ok := cols[i].(*uuid.UUID); ok && v != nil {
-				rowData = append(rowData, v.String())
-				continue
-			}
-			if v, ok := cols[i].(*orb.Point); ok && v != nil {
-				rowData = append(rowData, wkt.MarshalString(*v))
-				continue
-			}
-			if v, ok := cols[i].(*orb.Polygon); ok && v != nil {
-				rowData = append(rowData, wkt.MarshalString(*v))
-				continue
-			}
-			if v, ok := cols[i].(*orb.Ring); ok && v != nil {
-				rowData = append(rowData, wkt.MarshalString(*v))
-				continue
-			}
-			if v, ok := cols[i].(*orb.MultiPolygon); ok && v != nil {
-				rowData = append(rowData, wkt.MarshalString(*v))
-				continue
-			}
+	rowData := []any{}
+	for i := range cols {
+		// handle TUPLE ARRAY MAP
+		if v, ok := cols[i].(*any); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
 
-			// nullable
-			if v, ok := cols[i].(**int); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**int8); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**int16); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**int32); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**int64); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**uint); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**uint8); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**uint16); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**uint32); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**uint64); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**float32); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**float64); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**string); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**bool); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**time.Time); ok && *v != nil {
-				rowData = append(rowData, **v)
-				continue
-			}
-			if v, ok := cols[i].(**big.Int); ok && *v != nil {
-				rowData = append(rowData, (*v).String())
-				continue
-			}
-			if v, ok := cols[i].(**decimal.Decimal); ok && *v != nil {
-				rowData = append(rowData, (*v).String())
-				continue
-			}
-			if v, ok := cols[i].(**uuid.UUID); ok && *v != nil {
-				rowData = append(rowData, (*v).String())
-				continue
-			}
-			rowData = append(rowData, nil)
+		// not nullable
+		if v, ok := cols[i].(*int); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*int8); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*int16); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*int32); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*int64); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*uint); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*uint8); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*uint16); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*uint32); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*uint64); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*float32); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*float64); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*string); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*bool); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*time.Time); ok && v != nil {
+			rowData = append(rowData, *v)
+			continue
+		}
+		if v, ok := cols[i].(*big.Int); ok && v != nil {
+			rowData = append(rowData, v.String())
+			continue
+		}
+		if v, ok := cols[i].(*decimal.Decimal); ok && v != nil {
+			rowData = append(rowData, v.String())
+			continue
+		}
+		if v, ok := cols[i].(*uuid.UUID); ok && v != nil {
+			rowData = append(rowData, v.String())
+			continue
+		}
+		if v, ok := cols[i].(*orb.Point); ok && v != nil {
+			rowData = append(rowData, wkt.MarshalString(*v))
+			continue
+		}
+		if v, ok := cols[i].(*orb.Polygon); ok && v != nil {
+			rowData = append(rowData, wkt.MarshalString(*v))
+			continue
+		}
+		if v, ok := cols[i].(*orb.Ring); ok && v != nil {
+			rowData = append(rowData, wkt.MarshalString(*v))
+			continue
+		}
+		if v, ok := cols[i].(*orb.MultiPolygon); ok && v != nil {
+			rowData = append(rowData, wkt.MarshalString(*v))
+			continue
 		}
 
-		data = append(data, rowData)
+		// nullable
+		if v, ok := cols[i].(**int); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**int8); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**int16); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**int32); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**int64); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**uint); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**uint8); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**uint16); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**uint32); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**uint64); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**float32); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**float64); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**string); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**bool); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**time.Time); ok && *v != nil {
+			rowData = append(rowData, **v)
+			continue
+		}
+		if v, ok := cols[i].(**big.Int); ok && *v != nil {
+			rowData = append(rowData, (*v).String())
+			continue
+		}
+		if v, ok := cols[i].(**decimal.Decimal); ok && *v != nil {
+			rowData = append(rowData, (*v).String())
+			continue
+		}
+		if v, ok := cols[i].(**uuid.UUID); ok && *v != nil {
+			rowData = append(rowData, (*v).String())
+			continue
+		}
+		rowData = append(rowData, nil)
 	}
 
-	return data, nil
+	return rowData, nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/masker"
 
 	// Register pingcap parser driver.
 	_ "github.com/pingcap/tidb/types/parser_driver"
@@ -1277,3 +1278,161 @@ func TestPostgreSQLExtractSensitiveField(t *testing.T) {
 		require.Equal(t, test.fieldList, res, test.statement)
 	}
 }
+
+func TestExtractSensitiveFieldMaskType(t *testing.T) {
+	const (
+		defaultDatabase = "db"
+	)
+	newSchemaInfo := func(tableName string) *db.SensitiveSchemaInfo {
+		return &db.SensitiveSchemaInfo{
+			DatabaseList: []db.DatabaseSchema{
+				{
+					Name: defaultDatabase,
+					TableList: []db.TableSchema{
+						{
+							Name: tableName,
+							ColumnList: []db.ColumnInfo{
+								{Name: "a", Sensitive: true, MaskType: db.SensitiveDataMaskTypeFF1},
+								{Name: "b", Sensitive: false},
+								{Name: "d", Sensitive: true, MaskType: db.SensitiveDataMaskTypeHMAC},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		statement string
+		fieldList []db.SensitiveField
+	}{
+		{
+			statement: `SELECT * FROM t`,
+			fieldList: []db.SensitiveField{
+				{Name: "a", Sensitive: true, MaskType: db.SensitiveDataMaskTypeFF1},
+				{Name: "b", Sensitive: false},
+				{Name: "d", Sensitive: true, MaskType: db.SensitiveDataMaskTypeHMAC},
+			},
+		},
+		{
+			// The mask type is kept for the columns selected as is, and the derived fields use the default.
+			statement: `SELECT x.a AS p, d, concat(a, b) AS q FROM (SELECT * FROM t) x`,
+			fieldList: []db.SensitiveField{
+				{Name: "p", Sensitive: true, MaskType: db.SensitiveDataMaskTypeFF1},
+				{Name: "d", Sensitive: true, MaskType: db.SensitiveDataMaskTypeHMAC},
+				{Name: "q", Sensitive: true},
+			},
+		},
+		{
+			statement: `SELECT a, b, d FROM t UNION SELECT a, a, a FROM t`,
+			fieldList: []db.SensitiveField{
+				{Name: "a", Sensitive: true, MaskType: db.SensitiveDataMaskTypeFF1},
+				{Name: "b", Sensitive: true, MaskType: db.SensitiveDataMaskTypeFF1},
+				{Name: "d", Sensitive: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		res, err := extractSensitiveField(db.MySQL, test.statement, defaultDatabase, newSchemaInfo("t"))
+		require.NoError(t, err)
+		require.Equal(t, test.fieldList, res, test.statement)
+		res, err = extractSensitiveField(db.Postgres, test.statement, defaultDatabase, newSchemaInfo("public.t"))
+		require.NoError(t, err)
+		require.Equal(t, test.fieldList, res, test.statement)
+	}
+}
+
+func TestExtractSensitiveFieldMaskCondition(t *testing.T) {
+	const (
+		defaultDatabase = "db"
+	)
+	newSchemaInfo := func(tableName string) *db.SensitiveSchemaInfo {
+		return &db.SensitiveSchemaInfo{
+			DatabaseList: []db.DatabaseSchema{
+				{
+					Name: defaultDatabase,
+					TableList: []db.TableSchema{
+						{
+							Name: tableName,
+							ColumnList: []db.ColumnInfo{
+								{Name: "a", Sensitive: true, MaskCondition: `row.b == "DE"`},
+								{Name: "b", Sensitive: false},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	statement := `SELECT a, b, concat(a, b) AS c FROM t`
+	// The derived fields are always masked.
+	fieldList := []db.SensitiveField{
+		{Name: "a", Sensitive: true, MaskCondition: `row.b == "DE"`},
+		{Name: "b", Sensitive: false},
+		{Name: "c", Sensitive: true},
+	}
+	res, err := extractSensitiveField(db.MySQL, statement, defaultDatabase, newSchemaInfo("t"))
+	require.NoError(t, err)
+	require.Equal(t, fieldList, res)
+	res, err = extractSensitiveField(db.Postgres, statement, defaultDatabase, newSchemaInfo("public.t"))
+	require.NoError(t, err)
+	require.Equal(t, fieldList, res)
+}
+
+func TestRowMasker(t *testing.T) {
+	rowMasker := newRowMasker([]string{"salary", "country", "owner"}, &db.SensitiveSchemaInfo{
+		Requester: &db.MaskingRequester{Email: "alice@example.com", Role: "DEVELOPER"},
+	})
+	m, err := masker.NewMasker(db.SensitiveDataMaskTypeDefault, "")
+	require.NoError(t, err)
+	salaryCondition, err := masker.NewCondition(`row.owner != requester.email`)
+	require.NoError(t, err)
+	countryCondition, err := masker.NewCondition(`row.country == "DE"`)
+	require.NoError(t, err)
+	rowMasker.maskers = []masker.Masker{m, m, nil}
+	rowMasker.conditions = []*masker.Condition{salaryCondition, countryCondition, nil}
+
+	row := []any{int64(100), "DE", "alice@example.com"}
+	rowMasker.mask(row)
+	require.Equal(t, []any{int64(100), "******", "alice@example.com"}, row)
+
+	row = []any{int64(100), "US", "bob@example.com"}
+	rowMasker.mask(row)
+	require.Equal(t, []any{"******", "US", "bob@example.com"}, row)
+}
+
+func TestExtractSensitiveFieldsThroughView(t *testing.T) {
+	a := require.New(t)
+	schemaInfo := &db.SensitiveSchemaInfo{
+		DatabaseList: []db.DatabaseSchema{
+			{
+				Name: "db",
+				TableList: []db.TableSchema{
+					{
+						Name: "t",
+						ColumnList: []db.ColumnInfo{
+							{Name: "a", Sensitive: true},
+							{Name: "b", Sensitive: false},
+						},
+					},
+				},
+			},
+		},
+	}
+	// The view is inspected as a table with the columns derived from its definition.
+	viewFields, err := ExtractSensitiveFields(db.MySQL, "SELECT a AS x, b FROM t", "db", schemaInfo)
+	a.NoError(err)
+	view := db.TableSchema{Name: "v", ColumnList: []db.ColumnInfo{}}
+	for _, field := range viewFields {
+		view.ColumnList = append(view.ColumnList, db.ColumnInfo{Name: field.Name, Sensitive: field.Sensitive})
+	}
+	schemaInfo.DatabaseList[0].TableList = append(schemaInfo.DatabaseList[0].TableList, view)
+
+	fields, err := ExtractSensitiveFields(db.MySQL, "WITH c AS (SELECT x AS y, b FROM v) SELECT y AS z, b FROM c", "db", schemaInfo)
+	a.NoError(err)
+	a.Equal([]db.SensitiveField{{Name: "z", Sensitive: true}, {Name: "b", Sensitive: false}}, fields)
+
+	_, err = ExtractSensitiveFields(db.Snowflake, "SELECT a FROM t", "db", schemaInfo)
+	a.Error(err)
+}
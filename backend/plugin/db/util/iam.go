@@ -0,0 +1,42 @@
+package util
+
+import (
+	"context"
+	"net"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	awscredentials "github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/rds/auth"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// GetRDSIAMAuthToken generates the AWS RDS IAM auth token for the user in the connection config.
+// The token expires in 15 minutes, so it should be generated right before connecting.
+func GetRDSIAMAuthToken(ctx context.Context, connCfg db.ConnectionConfig, defaultPort string) (string, error) {
+	var optFns []func(*awsconfig.LoadOptions) error
+	if connCfg.AWSCredential.Region != "" {
+		optFns = append(optFns, awsconfig.WithRegion(connCfg.AWSCredential.Region))
+	}
+	if connCfg.AWSCredential.AccessKeyID != "" {
+		optFns = append(optFns, awsconfig.WithCredentialsProvider(awscredentials.NewStaticCredentialsProvider(connCfg.AWSCredential.AccessKeyID, connCfg.AWSCredential.SecretAccessKey, "")))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to load AWS config")
+	}
+	if cfg.Region == "" {
+		return "", errors.Errorf("AWS region must be set for RDS IAM authentication")
+	}
+
+	port := connCfg.Port
+	if port == "" {
+		port = defaultPort
+	}
+	token, err := auth.BuildAuthToken(ctx, net.JoinHostPort(connCfg.Host, port), cfg.Region, connCfg.Username, cfg.Credentials)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to build RDS IAM auth token")
+	}
+	return token, nil
+}
@@ -0,0 +1,145 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jcmturner/gokrb5/v8/client"
+	"github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// Both pgx and go-mssqldb only support the global GSSAPI providers without the connection config,
+// so the Kerberos clients are registered by the IDs, and the drivers pass the IDs to the providers in the connection parameters.
+var (
+	kerberosClientsMu  sync.Mutex
+	kerberosClients    = make(map[string]*client.Client)
+	nextKerberosClient atomic.Int64
+)
+
+// RegisterKerberosClient logs in the principal of the username with the keytab, and registers the Kerberos client.
+// It returns the client ID, and the caller must call UnregisterKerberosClient() after closing the connections.
+func RegisterKerberosClient(username string, kerberosConfig db.KerberosConfig) (string, error) {
+	if kerberosConfig.Realm == "" {
+		return "", errors.Errorf("realm must be set for the Kerberos authentication")
+	}
+	if len(kerberosConfig.Keytab) == 0 {
+		return "", errors.Errorf("keytab must be set for the Kerberos authentication")
+	}
+	krb5Config, err := config.NewFromString(GetKerberosConfigString(kerberosConfig))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to build Kerberos config")
+	}
+	kt := keytab.New()
+	if err := kt.Unmarshal(kerberosConfig.Keytab); err != nil {
+		return "", errors.Wrap(err, "failed to parse keytab")
+	}
+	// Active Directory doesn't support the FAST pre-authentication.
+	kerberosClient := client.NewWithKeytab(username, kerberosConfig.Realm, kt, krb5Config, client.DisablePAFXFAST(true))
+	if err := kerberosClient.Login(); err != nil {
+		return "", errors.Wrapf(err, "failed to login Kerberos principal %s@%s", username, kerberosConfig.Realm)
+	}
+
+	id := strconv.FormatInt(nextKerberosClient.Add(1), 10)
+	kerberosClientsMu.Lock()
+	defer kerberosClientsMu.Unlock()
+	kerberosClients[id] = kerberosClient
+	return id, nil
+}
+
+// UnregisterKerberosClient unregisters the Kerberos client and destroys its tickets.
+func UnregisterKerberosClient(id string) {
+	kerberosClientsMu.Lock()
+	kerberosClient, ok := kerberosClients[id]
+	delete(kerberosClients, id)
+	kerberosClientsMu.Unlock()
+	if ok {
+		kerberosClient.Destroy()
+	}
+}
+
+// GetKerberosInitToken gets the initial SPNEGO token for the service principal name with the registered Kerberos client.
+// The client renews the ticket granting ticket automatically.
+func GetKerberosInitToken(id, spn string) ([]byte, error) {
+	kerberosClientsMu.Lock()
+	kerberosClient, ok := kerberosClients[id]
+	kerberosClientsMu.Unlock()
+	if !ok {
+		return nil, errors.Errorf("Kerberos client %q not found", id)
+	}
+	token, err := spnego.SPNEGOClient(kerberosClient, spn).InitSecContext()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Kerberos service ticket for %q", spn)
+	}
+	return token.Marshal()
+}
+
+// VerifyKerberosToken verifies the SPNEGO token responded by the service.
+func VerifyKerberosToken(token []byte) error {
+	var resp spnego.SPNEGOToken
+	if err := resp.Unmarshal(token); err != nil {
+		return errors.Wrap(err, "failed to unmarshal Kerberos response token")
+	}
+	if !resp.Resp {
+		return errors.Errorf("expect Kerberos response token")
+	}
+	if resp.NegTokenResp.State() != spnego.NegStateAcceptCompleted {
+		return errors.Errorf("Kerberos authentication is not completed, state %d", resp.NegTokenResp.State())
+	}
+	return nil
+}
+
+// GetKerberosConfigString gets the krb5.conf content of the realm, the KDCs are looked up in the DNS if they're not set.
+func GetKerberosConfigString(kerberosConfig db.KerberosConfig) string {
+	var kdcs []string
+	for _, kdc := range strings.Split(kerberosConfig.KDC, ",") {
+		if kdc = strings.TrimSpace(kdc); kdc != "" {
+			kdcs = append(kdcs, kdc)
+		}
+	}
+
+	var buf strings.Builder
+	_, _ = fmt.Fprintf(&buf, "[libdefaults]\n  default_realm = %s\n  dns_lookup_realm = false\n  dns_lookup_kdc = %t\n  rdns = false\n", kerberosConfig.Realm, len(kdcs) == 0)
+	if len(kdcs) > 0 {
+		_, _ = fmt.Fprintf(&buf, "[realms]\n  %s = {\n", kerberosConfig.Realm)
+		for _, kdc := range kdcs {
+			_, _ = fmt.Fprintf(&buf, "    kdc = %s\n", kdc)
+		}
+		buf.WriteString("  }\n")
+	}
+	return buf.String()
+}
+
+// WriteKerberosFiles writes the krb5.conf and the client keytab into a temporary directory for the command line tools,
+// and returns the directory and the environment variables for the MIT Kerberos library to get the tickets with the client keytab.
+// The caller must remove the directory after the command exits.
+func WriteKerberosFiles(kerberosConfig db.KerberosConfig) (string, []string, error) {
+	dir, err := os.MkdirTemp("", "bytebase-krb5-")
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to create temporary directory for Kerberos")
+	}
+	configPath, keytabPath := filepath.Join(dir, "krb5.conf"), filepath.Join(dir, "client.keytab")
+	if err := os.WriteFile(configPath, []byte(GetKerberosConfigString(kerberosConfig)), 0600); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", nil, errors.Wrap(err, "failed to write krb5.conf")
+	}
+	if err := os.WriteFile(keytabPath, kerberosConfig.Keytab, 0600); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", nil, errors.Wrap(err, "failed to write keytab")
+	}
+	return dir, []string{
+		fmt.Sprintf("KRB5_CONFIG=%s", configPath),
+		fmt.Sprintf("KRB5_CLIENT_KTNAME=FILE:%s", keytabPath),
+		// Keep the tickets in the process memory.
+		"KRB5CCNAME=MEMORY:bytebase",
+	}, nil
+}
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jcmturner/gokrb5/v8/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestGetKerberosConfigString(t *testing.T) {
+	a := require.New(t)
+
+	tests := []struct {
+		kerberosConfig db.KerberosConfig
+		wantKDCs       []string
+	}{
+		// The KDCs are looked up in the DNS.
+		{kerberosConfig: db.KerberosConfig{Realm: "EXAMPLE.COM"}},
+		{kerberosConfig: db.KerberosConfig{Realm: "EXAMPLE.COM", KDC: "kdc1.example.com, kdc2.example.com:88"}, wantKDCs: []string{"kdc1.example.com:88", "kdc2.example.com:88"}},
+	}
+	for _, test := range tests {
+		krb5Config, err := config.NewFromString(GetKerberosConfigString(test.kerberosConfig))
+		a.NoError(err)
+		a.Equal(test.kerberosConfig.Realm, krb5Config.LibDefaults.DefaultRealm)
+		a.Equal(len(test.wantKDCs) == 0, krb5Config.LibDefaults.DNSLookupKDC)
+		if len(test.wantKDCs) == 0 {
+			a.Empty(krb5Config.Realms)
+			continue
+		}
+		a.Len(krb5Config.Realms, 1)
+		a.Equal(test.wantKDCs, krb5Config.Realms[0].KDC)
+	}
+}
+
+func TestRegisterKerberosClient(t *testing.T) {
+	a := require.New(t)
+
+	_, err := RegisterKerberosClient("bytebase", db.KerberosConfig{Keytab: []byte{0x05, 0x02}})
+	a.ErrorContains(err, "realm must be set")
+	_, err = RegisterKerberosClient("bytebase", db.KerberosConfig{Realm: "EXAMPLE.COM"})
+	a.ErrorContains(err, "keytab must be set")
+	_, err = RegisterKerberosClient("bytebase", db.KerberosConfig{Realm: "EXAMPLE.COM", Keytab: []byte("invalid")})
+	a.ErrorContains(err, "failed to parse keytab")
+
+	_, err = GetKerberosInitToken("0", "postgres/db.example.com")
+	a.ErrorContains(err, "not found")
+}
+
+func TestWriteKerberosFiles(t *testing.T) {
+	a := require.New(t)
+
+	dir, env, err := WriteKerberosFiles(db.KerberosConfig{Realm: "EXAMPLE.COM", Keytab: []byte{0x05, 0x02}})
+	a.NoError(err)
+	defer os.RemoveAll(dir)
+	a.Equal([]string{
+		"KRB5_CONFIG=" + filepath.Join(dir, "krb5.conf"),
+		"KRB5_CLIENT_KTNAME=FILE:" + filepath.Join(dir, "client.keytab"),
+		"KRB5CCNAME=MEMORY:bytebase",
+	}, env)
+	keytab, err := os.ReadFile(filepath.Join(dir, "client.keytab"))
+	a.NoError(err)
+	a.Equal([]byte{0x05, 0x02}, keytab)
+}
@@ -64,6 +64,25 @@ func extractSensitiveField(dbType db.Type, statement string, currentDatabase str
 	}
 }
 
+// IsSensitiveFieldExtractionSupported returns true if the sensitive fields of the query statements can be extracted for
+// the engine.
+func IsSensitiveFieldExtractionSupported(dbType db.Type) bool {
+	switch dbType {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase, db.Postgres:
+		return true
+	}
+	return false
+}
+
+// ExtractSensitiveFields returns the fields of the query statement, a field is sensitive if it's derived from any
+// sensitive column of the schema info, including through the aliases, the subqueries and the CTEs.
+func ExtractSensitiveFields(dbType db.Type, statement string, currentDatabase string, schemaInfo *db.SensitiveSchemaInfo) ([]db.SensitiveField, error) {
+	if !IsSensitiveFieldExtractionSupported(dbType) {
+		return nil, errors.Errorf("extracting the sensitive fields is not supported for engine %q", dbType)
+	}
+	return extractSensitiveField(dbType, statement, currentDatabase, schemaInfo)
+}
+
 func isPostgreSQLSystemSchema(schema string) bool {
 	switch schema {
 	case "information_schema", "pg_catalog":
@@ -99,8 +118,10 @@ func (extractor *sensitiveFieldExtractor) extractPostgreSQLSensitiveField(statem
 	result := []db.SensitiveField{}
 	for _, field := range fieldList {
 		result = append(result, db.SensitiveField{
-			Name:      field.name,
-			Sensitive: field.sensitive,
+			Name:          field.name,
+			Sensitive:     field.sensitive,
+			MaskType:      field.maskType,
+			MaskCondition: field.maskCondition,
 		})
 	}
 	return result, nil
@@ -150,8 +171,8 @@ func pgMergeJoinField(node *pgquery.Node_JoinExpr, leftField []fieldInfo, rightF
 		// Natural Join will merge the same column name field.
 		for _, field := range leftField {
 			// Merge the sensitive attribute for the same column name field.
-			if rField, exists := rightFieldMap[field.name]; exists && rField.sensitive {
-				field.sensitive = true
+			if rField, exists := rightFieldMap[field.name]; exists {
+				field = mergeSensitiveField(field, rField)
 			}
 			result = append(result, field)
 		}
@@ -181,8 +202,8 @@ func pgMergeJoinField(node *pgquery.Node_JoinExpr, leftField []fieldInfo, rightF
 				_, existsInUsingMap := usingMap[field.name]
 				rField, existsInRightField := rightFieldMap[field.name]
 				// Merge the sensitive attribute for the column name field in USING.
-				if existsInUsingMap && existsInRightField && rField.sensitive {
-					field.sensitive = true
+				if existsInUsingMap && existsInRightField {
+					field = mergeSensitiveField(field, rField)
 				}
 				result = append(result, field)
 			}
@@ -224,9 +245,11 @@ func (extractor *sensitiveFieldExtractor) pgExtractRangeSubselect(node *pgquery.
 				columnName = columnNameList[i]
 			}
 			result = append(result, fieldInfo{
-				table:     fmt.Sprintf("public.%s", aliasName),
-				name:      columnName,
-				sensitive: item.sensitive,
+				table:         fmt.Sprintf("public.%s", aliasName),
+				name:          columnName,
+				sensitive:     item.sensitive,
+				maskType:      item.maskType,
+				maskCondition: item.maskCondition,
 			})
 		}
 		return result, nil
@@ -269,9 +292,11 @@ func (extractor *sensitiveFieldExtractor) pgExtractRangeVar(node *pgquery.Node_R
 	if node.RangeVar.Alias == nil {
 		for _, column := range tableSchema.ColumnList {
 			res = append(res, fieldInfo{
-				name:      column.Name,
-				table:     tableSchema.Name,
-				sensitive: column.Sensitive,
+				name:          column.Name,
+				table:         tableSchema.Name,
+				sensitive:     column.Sensitive,
+				maskType:      column.MaskType,
+				maskCondition: column.MaskCondition,
 			})
 		}
 	} else {
@@ -290,9 +315,11 @@ func (extractor *sensitiveFieldExtractor) pgExtractRangeVar(node *pgquery.Node_R
 				columnName = columnNameList[i]
 			}
 			res = append(res, fieldInfo{
-				name:      columnName,
-				table:     tableName,
-				sensitive: column.Sensitive,
+				name:          columnName,
+				table:         tableName,
+				sensitive:     column.Sensitive,
+				maskType:      column.MaskType,
+				maskCondition: column.MaskCondition,
 			})
 		}
 	}
@@ -355,8 +382,10 @@ func (extractor *sensitiveFieldExtractor) pgExtractRecursiveCTE(node *pgquery.No
 		cteInfo := db.TableSchema{Name: pgNormalizeTableName("public", node.CommonTableExpr.Ctename)}
 		for _, field := range initialField {
 			cteInfo.ColumnList = append(cteInfo.ColumnList, db.ColumnInfo{
-				Name:      field.name,
-				Sensitive: field.sensitive,
+				Name:          field.name,
+				Sensitive:     field.sensitive,
+				MaskType:      field.maskType,
+				MaskCondition: field.maskCondition,
 			})
 		}
 
@@ -385,9 +414,8 @@ func (extractor *sensitiveFieldExtractor) pgExtractRecursiveCTE(node *pgquery.No
 
 			changed := false
 			for i, field := range fieldList {
-				if field.sensitive && !cteInfo.ColumnList[i].Sensitive {
+				if mergeSensitiveColumn(&cteInfo.ColumnList[i], field) {
 					changed = true
-					cteInfo.ColumnList[i].Sensitive = true
 				}
 			}
 
@@ -430,8 +458,10 @@ func (extractor *sensitiveFieldExtractor) pgExtractNonRecursiveCTE(node *pgquery
 
 	for _, field := range fieldList {
 		result.ColumnList = append(result.ColumnList, db.ColumnInfo{
-			Name:      field.name,
-			Sensitive: field.sensitive,
+			Name:          field.name,
+			Sensitive:     field.sensitive,
+			MaskType:      field.maskType,
+			MaskCondition: field.maskCondition,
 		})
 	}
 
@@ -506,10 +536,13 @@ func (extractor *sensitiveFieldExtractor) pgExtractSelect(node *pgquery.Node_Sel
 		}
 		var result []fieldInfo
 		for i, field := range leftField {
+			merged := mergeSensitiveField(field, rightField[i])
 			result = append(result, fieldInfo{
-				name:      field.name,
-				table:     field.table,
-				sensitive: field.sensitive || rightField[i].sensitive,
+				name:          field.name,
+				table:         field.table,
+				sensitive:     merged.sensitive,
+				maskType:      merged.maskType,
+				maskCondition: merged.maskCondition,
 			})
 		}
 		return result, nil
@@ -568,9 +601,17 @@ func (extractor *sensitiveFieldExtractor) pgExtractSelect(node *pgquery.Node_Sel
 				if resTarget.ResTarget.Name != "" {
 					columnName = resTarget.ResTarget.Name
 				}
+				var maskType db.SensitiveDataMaskType
+				var maskCondition string
+				if field, ok := extractor.pgFindField(pgNormalizeColumnName(columnRef)); ok {
+					maskType = field.maskType
+					maskCondition = field.maskCondition
+				}
 				result = append(result, fieldInfo{
-					name:      columnName,
-					sensitive: sensitive,
+					name:          columnName,
+					sensitive:     sensitive,
+					maskType:      maskType,
+					maskCondition: maskCondition,
 				})
 			}
 		default:
@@ -750,6 +791,11 @@ func pgNormalizeColumnName(columnName *ast.ColumnNameDef) (string, string) {
 }
 
 func (extractor *sensitiveFieldExtractor) pgCheckFieldSensitive(tableName string, fieldName string) bool {
+	field, _ := extractor.pgFindField(tableName, fieldName)
+	return field.sensitive
+}
+
+func (extractor *sensitiveFieldExtractor) pgFindField(tableName string, fieldName string) (fieldInfo, bool) {
 	// One sub-query may have multi-outer schemas and the multi-outer schemas can use the same name, such as:
 	//
 	//  select (
@@ -768,7 +814,7 @@ func (extractor *sensitiveFieldExtractor) pgCheckFieldSensitive(tableName string
 		sameTable := (tableName == field.table || tableName == "")
 		sameField := (fieldName == field.name)
 		if sameTable && sameField {
-			return field.sensitive
+			return field, true
 		}
 	}
 
@@ -776,11 +822,11 @@ func (extractor *sensitiveFieldExtractor) pgCheckFieldSensitive(tableName string
 		sameTable := (tableName == field.table || tableName == "")
 		sameField := (fieldName == field.name)
 		if sameTable && sameField {
-			return field.sensitive
+			return field, true
 		}
 	}
 
-	return false
+	return fieldInfo{}, false
 }
 
 func (extractor *sensitiveFieldExtractor) pgExtractColumnRefFromExpressionNode(in *pgquery.Node) (bool, error) {
@@ -939,8 +985,10 @@ func (extractor *sensitiveFieldExtractor) extractMySQLSensitiveField(statement s
 	result := []db.SensitiveField{}
 	for _, field := range fieldList {
 		result = append(result, db.SensitiveField{
-			Name:      field.name,
-			Sensitive: field.sensitive,
+			Name:          field.name,
+			Sensitive:     field.sensitive,
+			MaskType:      field.maskType,
+			MaskCondition: field.maskCondition,
 		})
 	}
 	return result, nil
@@ -951,6 +999,39 @@ type fieldInfo struct {
 	table     string
 	database  string
 	sensitive bool
+	// maskType is the mask type of the sensitive column the field selects as is, empty means the default.
+	maskType db.SensitiveDataMaskType
+	// maskCondition is the condition of the sensitive column the field selects as is, empty means always masked.
+	maskCondition string
+}
+
+// mergeSensitiveField merges the sensitive attribute of the other field sharing the values into the field. The mask
+// type and condition are kept only if the sensitive fields agree on them.
+func mergeSensitiveField(field fieldInfo, other fieldInfo) fieldInfo {
+	switch {
+	case !other.sensitive:
+	case !field.sensitive:
+		field.sensitive, field.maskType, field.maskCondition = true, other.maskType, other.maskCondition
+	default:
+		if field.maskType != other.maskType {
+			field.maskType = ""
+		}
+		if field.maskCondition != other.maskCondition {
+			field.maskCondition = ""
+		}
+	}
+	return field
+}
+
+// mergeSensitiveColumn merges the sensitive attribute of the field into the column of the recursive CTE, and returns
+// true if the column changes.
+func mergeSensitiveColumn(column *db.ColumnInfo, field fieldInfo) bool {
+	merged := mergeSensitiveField(fieldInfo{sensitive: column.Sensitive, maskType: column.MaskType, maskCondition: column.MaskCondition}, field)
+	if merged.sensitive == column.Sensitive && merged.maskType == column.MaskType && merged.maskCondition == column.MaskCondition {
+		return false
+	}
+	column.Sensitive, column.MaskType, column.MaskCondition = merged.sensitive, merged.maskType, merged.maskCondition
+	return true
 }
 
 func (extractor *sensitiveFieldExtractor) extractNode(in tidbast.Node) ([]fieldInfo, error) {
@@ -1002,9 +1083,7 @@ func (extractor *sensitiveFieldExtractor) extractSetOpr(node *tidbast.SetOprStmt
 				return nil, errors.Errorf("The used SELECT statements have a different number of columns")
 			}
 			for index := 0; index < len(result); index++ {
-				if fieldList[index].sensitive {
-					result[index].sensitive = true
-				}
+				result[index] = mergeSensitiveField(result[index], fieldList[index])
 			}
 		}
 	}
@@ -1080,8 +1159,10 @@ func (extractor *sensitiveFieldExtractor) extractRecursiveCTE(node *tidbast.Comm
 		}
 		for _, field := range initialField {
 			cteInfo.ColumnList = append(cteInfo.ColumnList, db.ColumnInfo{
-				Name:      field.name,
-				Sensitive: field.sensitive,
+				Name:          field.name,
+				Sensitive:     field.sensitive,
+				MaskType:      field.maskType,
+				MaskCondition: field.maskCondition,
 			})
 		}
 
@@ -1115,9 +1196,8 @@ func (extractor *sensitiveFieldExtractor) extractRecursiveCTE(node *tidbast.Comm
 
 			changed := false
 			for i, field := range fieldList {
-				if field.sensitive && !cteInfo.ColumnList[i].Sensitive {
+				if mergeSensitiveColumn(&cteInfo.ColumnList[i], field) {
 					changed = true
-					cteInfo.ColumnList[i].Sensitive = true
 				}
 			}
 
@@ -1152,8 +1232,10 @@ func (extractor *sensitiveFieldExtractor) extractNonRecursiveCTE(node *tidbast.C
 	}
 	for _, field := range fieldList {
 		result.ColumnList = append(result.ColumnList, db.ColumnInfo{
-			Name:      field.name,
-			Sensitive: field.sensitive,
+			Name:          field.name,
+			Sensitive:     field.sensitive,
+			MaskType:      field.maskType,
+			MaskCondition: field.maskCondition,
 		})
 	}
 	return result, nil
@@ -1215,12 +1297,22 @@ func (extractor *sensitiveFieldExtractor) extractSelect(node *tidbast.SelectStmt
 				if err != nil {
 					return nil, err
 				}
+				var maskType db.SensitiveDataMaskType
+				var maskCondition string
+				if columnName, ok := field.Expr.(*tidbast.ColumnNameExpr); ok {
+					if field, ok := extractor.findField(columnName.Name.Schema.O, columnName.Name.Table.O, columnName.Name.Name.O); ok {
+						maskType = field.maskType
+						maskCondition = field.maskCondition
+					}
+				}
 				fieldName := extractFieldName(field)
 				result = append(result, fieldInfo{
-					database:  "",
-					table:     "",
-					name:      fieldName,
-					sensitive: sensitive,
+					database:      "",
+					table:         "",
+					name:          fieldName,
+					sensitive:     sensitive,
+					maskType:      maskType,
+					maskCondition: maskCondition,
 				})
 			}
 		}
@@ -1244,6 +1336,11 @@ func extractFieldName(in *tidbast.SelectField) string {
 }
 
 func (extractor *sensitiveFieldExtractor) checkFieldSensitive(databaseName string, tableName string, fieldName string) bool {
+	field, _ := extractor.findField(databaseName, tableName, fieldName)
+	return field.sensitive
+}
+
+func (extractor *sensitiveFieldExtractor) findField(databaseName string, tableName string, fieldName string) (fieldInfo, bool) {
 	// One sub-query may have multi-outer schemas and the multi-outer schemas can use the same name, such as:
 	//
 	//  select (
@@ -1263,7 +1360,7 @@ func (extractor *sensitiveFieldExtractor) checkFieldSensitive(databaseName strin
 		sameTable := (tableName == field.table || tableName == "")
 		sameField := (fieldName == field.name)
 		if sameDatabase && sameTable && sameField {
-			return field.sensitive
+			return field, true
 		}
 	}
 
@@ -1272,11 +1369,11 @@ func (extractor *sensitiveFieldExtractor) checkFieldSensitive(databaseName strin
 		sameTable := (tableName == field.table || tableName == "")
 		sameField := (fieldName == field.name)
 		if sameDatabase && sameTable && sameField {
-			return field.sensitive
+			return field, true
 		}
 	}
 
-	return false
+	return fieldInfo{}, false
 }
 
 func (extractor *sensitiveFieldExtractor) extractColumnFromExprNode(in tidbast.ExprNode) (sensitive bool, err error) {
@@ -1394,10 +1491,12 @@ func (extractor *sensitiveFieldExtractor) extractTableSource(node *tidbast.Table
 	if node.AsName.O != "" {
 		for _, field := range fieldList {
 			res = append(res, fieldInfo{
-				name:      field.name,
-				table:     node.AsName.O,
-				database:  field.database,
-				sensitive: field.sensitive,
+				name:          field.name,
+				table:         node.AsName.O,
+				database:      field.database,
+				sensitive:     field.sensitive,
+				maskType:      field.maskType,
+				maskCondition: field.maskCondition,
 			})
 		}
 	} else {
@@ -1448,10 +1547,12 @@ func (extractor *sensitiveFieldExtractor) extractTableName(node *tidbast.TableNa
 	var res []fieldInfo
 	for _, column := range tableSchema.ColumnList {
 		res = append(res, fieldInfo{
-			name:      column.Name,
-			table:     tableSchema.Name,
-			database:  databaseName,
-			sensitive: column.Sensitive,
+			name:          column.Name,
+			table:         tableSchema.Name,
+			database:      databaseName,
+			sensitive:     column.Sensitive,
+			maskType:      column.MaskType,
+			maskCondition: column.MaskCondition,
 		})
 	}
 	return res, nil
@@ -1489,8 +1590,8 @@ func mergeJoinField(node *tidbast.Join, leftField []fieldInfo, rightField []fiel
 		// Natural Join will merge the same column name field.
 		for _, field := range leftField {
 			// Merge the sensitive attribute for the same column name field.
-			if rField, exists := rightFieldMap[strings.ToLower(field.name)]; exists && rField.sensitive {
-				field.sensitive = true
+			if rField, exists := rightFieldMap[strings.ToLower(field.name)]; exists {
+				field = mergeSensitiveField(field, rField)
 			}
 			result = append(result, field)
 		}
@@ -1513,8 +1614,8 @@ func mergeJoinField(node *tidbast.Join, leftField []fieldInfo, rightField []fiel
 				_, existsInUsingMap := usingMap[strings.ToLower(field.name)]
 				rField, existsInRightField := rightFieldMap[strings.ToLower(field.name)]
 				// Merge the sensitive attribute for the column name field in USING.
-				if existsInUsingMap && existsInRightField && rField.sensitive {
-					field.sensitive = true
+				if existsInUsingMap && existsInRightField {
+					field = mergeSensitiveField(field, rField)
 				}
 				result = append(result, field)
 			}
@@ -0,0 +1,95 @@
+package util
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+var (
+	// mysqlSessionVariables are the session variables allowed to set for the MySQL family engines.
+	mysqlSessionVariables = map[string]bool{
+		"time_zone":        true,
+		"sql_mode":         true,
+		"optimizer_switch": true,
+	}
+	// postgresSessionVariables are the session variables allowed to set for the PostgreSQL family engines.
+	postgresSessionVariables = map[string]bool{
+		"search_path":               true,
+		"timezone":                  true,
+		"datestyle":                 true,
+		"enable_seqscan":            true,
+		"enable_indexscan":          true,
+		"enable_bitmapscan":         true,
+		"enable_hashjoin":           true,
+		"enable_mergejoin":          true,
+		"enable_nestloop":           true,
+		"enable_partitionwise_join": true,
+	}
+)
+
+// IsSessionVariableSupported returns true if the engine supports setting the session variables.
+func IsSessionVariableSupported(engine db.Type) bool {
+	return getAllowedSessionVariables(engine) != nil
+}
+
+// ValidateSessionVariables validates the session variables are allowed for the engine, and returns them with the
+// names normalized in lower case. The later ones override the earlier ones with the same name.
+func ValidateSessionVariables(engine db.Type, variables []db.SessionVariable) ([]db.SessionVariable, error) {
+	if len(variables) == 0 {
+		return nil, nil
+	}
+	allowed := getAllowedSessionVariables(engine)
+	if allowed == nil {
+		return nil, errors.Errorf("session variables are not supported for engine %q", engine)
+	}
+	var result []db.SessionVariable
+	index := make(map[string]int)
+	for _, variable := range variables {
+		name := strings.ToLower(strings.TrimSpace(variable.Name))
+		if !allowed[name] {
+			return nil, errors.Errorf("session variable %q is not allowed for engine %q", variable.Name, engine)
+		}
+		if i, ok := index[name]; ok {
+			result[i].Value = variable.Value
+			continue
+		}
+		index[name] = len(result)
+		result = append(result, db.SessionVariable{Name: name, Value: variable.Value})
+	}
+	return result, nil
+}
+
+// SetSessionVariables sets the validated session variables on the connection, they last for the connection.
+// The variable names are from the allowed ones, and the values are passed as the parameters.
+func SetSessionVariables(ctx context.Context, engine db.Type, conn *sql.Conn, variables []db.SessionVariable) error {
+	for _, variable := range variables {
+		var err error
+		switch engine {
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+			_, err = conn.ExecContext(ctx, "SET SESSION "+variable.Name+" = ?", variable.Value)
+		case db.Postgres, db.Redshift:
+			_, err = conn.ExecContext(ctx, "SELECT set_config($1, $2, false)", variable.Name, variable.Value)
+		default:
+			return errors.Errorf("session variables are not supported for engine %q", engine)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to set session variable %q", variable.Name)
+		}
+	}
+	return nil
+}
+
+func getAllowedSessionVariables(engine db.Type) map[string]bool {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		return mysqlSessionVariables
+	case db.Postgres, db.Redshift:
+		return postgresSessionVariables
+	}
+	return nil
+}
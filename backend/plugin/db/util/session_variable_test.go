@@ -0,0 +1,43 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestValidateSessionVariables(t *testing.T) {
+	variables, err := ValidateSessionVariables(db.MySQL, []db.SessionVariable{
+		{Name: "TIME_ZONE", Value: "+00:00"},
+		{Name: "sql_mode", Value: "ANSI_QUOTES"},
+		{Name: "time_zone", Value: "+08:00"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []db.SessionVariable{
+		{Name: "time_zone", Value: "+08:00"},
+		{Name: "sql_mode", Value: "ANSI_QUOTES"},
+	}, variables)
+
+	variables, err = ValidateSessionVariables(db.Postgres, []db.SessionVariable{{Name: "search_path", Value: "app, public"}})
+	require.NoError(t, err)
+	require.Equal(t, []db.SessionVariable{{Name: "search_path", Value: "app, public"}}, variables)
+
+	variables, err = ValidateSessionVariables(db.MongoDB, nil)
+	require.NoError(t, err)
+	require.Nil(t, variables)
+
+	for _, test := range []struct {
+		engine   db.Type
+		variable db.SessionVariable
+	}{
+		{db.MySQL, db.SessionVariable{Name: "search_path", Value: "public"}},
+		{db.MySQL, db.SessionVariable{Name: "time_zone = '+00:00'; DROP TABLE t; --", Value: ""}},
+		{db.Postgres, db.SessionVariable{Name: "default_transaction_read_only", Value: "off"}},
+		{db.MongoDB, db.SessionVariable{Name: "time_zone", Value: "+00:00"}},
+	} {
+		_, err := ValidateSessionVariables(test.engine, []db.SessionVariable{test.variable})
+		require.Error(t, err, test.variable.Name)
+	}
+}
@@ -0,0 +1,157 @@
+package util
+
+import (
+	"net"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/net/proxy"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+const (
+	defaultSSHPort    = "22"
+	defaultSOCKS5Port = "1080"
+)
+
+// SSHTunnel is the chain of SSH clients through the jump hosts to the SSH host.
+type SSHTunnel struct {
+	clientList []*ssh.Client
+}
+
+// NewSSHTunnel connects to the SSH host by hopping through the jump hosts in order.
+// The first hop is dialed through the SOCKS5 proxy if it's configured.
+func NewSSHTunnel(sshConfig db.SSHConfig, socks5Config db.SOCKS5Config) (*SSHTunnel, error) {
+	dialer, err := GetSOCKS5Dialer(socks5Config)
+	if err != nil {
+		return nil, err
+	}
+
+	var hopList []db.SSHConfig
+	hopList = append(hopList, sshConfig.JumpHostList...)
+	hopList = append(hopList, sshConfig)
+
+	tunnel := &SSHTunnel{}
+	for i, hop := range hopList {
+		client, err := tunnel.connect(dialer, hop)
+		if err != nil {
+			if i < len(hopList)-1 {
+				err = errors.Wrapf(err, "failed to connect to SSH jump host %d", i+1)
+			}
+			return nil, multierr.Append(err, tunnel.Close())
+		}
+		tunnel.clientList = append(tunnel.clientList, client)
+	}
+	return tunnel, nil
+}
+
+// connect connects to the hop through the last SSH client in the tunnel, or through the dialer for the first hop.
+func (tunnel *SSHTunnel) connect(dialer proxy.Dialer, hop db.SSHConfig) (*ssh.Client, error) {
+	clientConfig, closeAgent, err := getSSHClientConfig(hop)
+	if err != nil {
+		return nil, err
+	}
+	defer closeAgent()
+
+	port := hop.Port
+	if port == "" {
+		port = defaultSSHPort
+	}
+	addr := net.JoinHostPort(hop.Host, port)
+	var conn net.Conn
+	if len(tunnel.clientList) == 0 {
+		conn, err = dialer.Dial("tcp", addr)
+	} else {
+		conn, err = tunnel.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial %q", addr)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, clientConfig)
+	if err != nil {
+		return nil, multierr.Append(errors.Wrapf(err, "failed to establish SSH connection to %q", addr), conn.Close())
+	}
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// Dial dials the address from the SSH host.
+func (tunnel *SSHTunnel) Dial(network, addr string) (net.Conn, error) {
+	return tunnel.clientList[len(tunnel.clientList)-1].Dial(network, addr)
+}
+
+// Close closes the SSH clients from the SSH host back to the first jump host.
+func (tunnel *SSHTunnel) Close() error {
+	var err error
+	for i := len(tunnel.clientList) - 1; i >= 0; i-- {
+		err = multierr.Append(err, tunnel.clientList[i].Close())
+	}
+	tunnel.clientList = nil
+	return err
+}
+
+// getSSHClientConfig returns the SSH client config for the hop, and the function to close the ssh-agent connection after the handshake.
+func getSSHClientConfig(hop db.SSHConfig) (*ssh.ClientConfig, func(), error) {
+	clientConfig := &ssh.ClientConfig{
+		User:            hop.User,
+		Auth:            []ssh.AuthMethod{},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	}
+	closeAgent := func() {}
+	if hop.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(hop.PrivateKey))
+		if err != nil {
+			return nil, nil, err
+		}
+		clientConfig.Auth = append(clientConfig.Auth, ssh.PublicKeys(signer))
+	} else {
+		// Establish a connection to the local ssh-agent
+		conn, err := net.Dial("unix", os.Getenv("SSH_AUTH_SOCK"))
+		if err != nil {
+			return nil, nil, err
+		}
+		closeAgent = func() {
+			conn.Close()
+		}
+		// Create a new instance of the ssh agent
+		agentClient := agent.NewClient(conn)
+		// When the agentClient connection succeeded, add them as AuthMethod
+		if agentClient != nil {
+			clientConfig.Auth = append(clientConfig.Auth, ssh.PublicKeysCallback(agentClient.Signers))
+		}
+	}
+	// When there's a non empty password add the password AuthMethod.
+	if hop.Password != "" {
+		password := hop.Password
+		clientConfig.Auth = append(clientConfig.Auth, ssh.PasswordCallback(func() (string, error) {
+			return password, nil
+		}))
+	}
+	return clientConfig, closeAgent, nil
+}
+
+// GetSOCKS5Dialer returns the dialer through the SOCKS5 proxy, or the direct dialer if the proxy is not configured.
+func GetSOCKS5Dialer(socks5Config db.SOCKS5Config) (proxy.Dialer, error) {
+	if socks5Config.Host == "" {
+		return proxy.Direct, nil
+	}
+	port := socks5Config.Port
+	if port == "" {
+		port = defaultSOCKS5Port
+	}
+	var auth *proxy.Auth
+	if socks5Config.User != "" {
+		auth = &proxy.Auth{
+			User:     socks5Config.User,
+			Password: socks5Config.Password,
+		}
+	}
+	dialer, err := proxy.SOCKS5("tcp", net.JoinHostPort(socks5Config.Host, port), auth, proxy.Direct)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create SOCKS5 dialer")
+	}
+	return dialer, nil
+}
@@ -0,0 +1,157 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestSSHTunnelWithJumpHosts(t *testing.T) {
+	a := require.New(t)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	a.NoError(err)
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	a.NoError(err)
+	clientKey := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	authorizedKey, err := ssh.NewPublicKey(&privateKey.PublicKey)
+	a.NoError(err)
+
+	echoAddr := startEchoServer(t)
+	firstJumpAddr := startSSHServer(t, authorizedKey)
+	secondJumpAddr := startSSHServer(t, authorizedKey)
+	sshAddr := startSSHServer(t, authorizedKey)
+
+	newHop := func(addr string) db.SSHConfig {
+		host, port, err := net.SplitHostPort(addr)
+		a.NoError(err)
+		return db.SSHConfig{Host: host, Port: port, User: "bytebase", PrivateKey: clientKey}
+	}
+	sshConfig := newHop(sshAddr)
+	sshConfig.JumpHostList = []db.SSHConfig{newHop(firstJumpAddr), newHop(secondJumpAddr)}
+
+	tunnel, err := NewSSHTunnel(sshConfig, db.SOCKS5Config{})
+	a.NoError(err)
+	a.Len(tunnel.clientList, 3)
+
+	conn, err := tunnel.Dial("tcp", echoAddr)
+	a.NoError(err)
+	_, err = conn.Write([]byte("ping"))
+	a.NoError(err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	a.NoError(err)
+	a.Equal("ping", string(buf))
+	a.NoError(conn.Close())
+	a.NoError(tunnel.Close())
+
+	sshConfig.JumpHostList[1].User = "unknown"
+	_, err = NewSSHTunnel(sshConfig, db.SOCKS5Config{})
+	a.ErrorContains(err, "failed to connect to SSH jump host 2")
+}
+
+func startEchoServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// startSSHServer starts an SSH server which only supports the direct-tcpip channel for port forwarding.
+func startSSHServer(t *testing.T, authorizedKey ssh.PublicKey) string {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+	serverConfig := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "bytebase" && string(key.Marshal()) == string(authorizedKey.Marshal()) {
+				return &ssh.Permissions{}, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	serverConfig.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSHConn(conn, serverConfig)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveSSHConn(conn net.Conn, serverConfig *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, serverConfig)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		var payload struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+		if err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			target.Close()
+			continue
+		}
+		go ssh.DiscardRequests(requests)
+		go func() {
+			defer channel.Close()
+			defer target.Close()
+			go func() {
+				_, _ = io.Copy(target, channel)
+			}()
+			_, _ = io.Copy(channel, target)
+		}()
+	}
+}
@@ -69,31 +69,31 @@ func (p *IdentityProvider) ExchangeToken(ctx context.Context, redirectURL, code
 	return accessToken, nil
 }
 
-// UserInfo returns the parsed user information using the given OAuth2 token.
-func (p *IdentityProvider) UserInfo(token string) (*storepb.IdentityProviderUserInfo, error) {
+// UserInfo returns the parsed user information and the raw claims using the given OAuth2 token.
+func (p *IdentityProvider) UserInfo(token string) (*storepb.IdentityProviderUserInfo, map[string]any, error) {
 	client := &http.Client{}
 	req, err := http.NewRequest(http.MethodGet, p.config.UserInfoUrl, nil)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to new http request")
+		return nil, nil, errors.Wrap(err, "failed to new http request")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	resp, err := client.Do(req)
 	if err != nil {
 		log.Error("Failed to get user information", zap.String("token", token), zap.Error(err))
-		return nil, errors.Wrap(err, "failed to get user information")
+		return nil, nil, errors.Wrap(err, "failed to get user information")
 	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.Error("Failed to read response body", zap.String("token", token), zap.Error(err))
-		return nil, errors.Wrap(err, "failed to read response body")
+		return nil, nil, errors.Wrap(err, "failed to read response body")
 	}
 
 	var claims map[string]any
 	err = json.Unmarshal(body, &claims)
 	if err != nil {
 		log.Error("Failed to unmarshal response body", zap.String("token", token), zap.String("body", string(body)), zap.Error(err))
-		return nil, errors.Wrap(err, "failed to unmarshal response body")
+		return nil, nil, errors.Wrap(err, "failed to unmarshal response body")
 	}
 	log.Debug("User info", zap.Any("claims", claims))
 
@@ -103,7 +103,7 @@ func (p *IdentityProvider) UserInfo(token string) (*storepb.IdentityProviderUser
 	}
 	if userInfo.Identifier == "" {
 		log.Error("Missing identifier in response body", zap.String("token", token), zap.Any("claims", claims), zap.Error(err))
-		return nil, errors.Errorf("the field %q is not found in claims or has empty value", p.config.FieldMapping.Identifier)
+		return nil, nil, errors.Errorf("the field %q is not found in claims or has empty value", p.config.FieldMapping.Identifier)
 	}
 
 	// Best effort to map optional fields
@@ -120,5 +120,5 @@ func (p *IdentityProvider) UserInfo(token string) (*storepb.IdentityProviderUser
 			userInfo.Email = v
 		}
 	}
-	return userInfo, nil
+	return userInfo, claims, nil
 }
@@ -122,9 +122,10 @@ func TestIdentityProvider(t *testing.T) {
 	)
 	userInfo, err := json.Marshal(
 		map[string]any{
-			"sub":   testSubject,
-			"name":  testName,
-			"email": testEmail,
+			"sub":    testSubject,
+			"name":   testName,
+			"email":  testEmail,
+			"groups": []string{"dba"},
 		},
 	)
 	require.NoError(t, err)
@@ -151,8 +152,9 @@ func TestIdentityProvider(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, testAccessToken, oauthToken)
 
-	userInfoResult, err := oauth2.UserInfo(oauthToken)
+	userInfoResult, claims, err := oauth2.UserInfo(oauthToken)
 	require.NoError(t, err)
+	assert.Equal(t, []any{"dba"}, claims["groups"])
 
 	wantUserInfo := &storepb.IdentityProviderUserInfo{
 		Identifier:  testSubject,
@@ -75,21 +75,23 @@ func (p *IdentityProvider) ExchangeToken(ctx context.Context, redirectURL, code
 	return token, nil
 }
 
-// UserInfo returns the parsed user information using the given OAuth2 token.
+// UserInfo returns the parsed user information and the raw claims using the given OAuth2 token.
 // The nonce is used for request validation, which should be the same value as
 // it was sent to the issuer as part of the Authentication Request, see
 // https://openid.net/specs/openid-connect-core-1_0.html#AuthRequest.
-func (p *IdentityProvider) UserInfo(ctx context.Context, token *oauth2.Token, nonce string) (*storepb.IdentityProviderUserInfo, error) {
+// The claims of the ID Token are overridden by the ones of the userinfo
+// endpoint, as some issuers only put the groups in the ID Token.
+func (p *IdentityProvider) UserInfo(ctx context.Context, token *oauth2.Token, nonce string) (*storepb.IdentityProviderUserInfo, map[string]any, error) {
 	// Extract the ID Token from the access token, see http://openid.net/specs/openid-connect-core-1_0.html#TokenResponse.
 	rawIDToken, ok := token.Extra("id_token").(string)
 	if !ok {
-		return nil, errors.New(`missing "id_token" from the issuer's authorization response`)
+		return nil, nil, errors.New(`missing "id_token" from the issuer's authorization response`)
 	}
 
 	verifier := p.provider.Verifier(&oidc.Config{ClientID: p.config.ClientID})
 	idToken, err := verifier.Verify(ctx, rawIDToken)
 	if err != nil {
-		return nil, errors.Wrap(err, "verify raw ID Token")
+		return nil, nil, errors.Wrap(err, "verify raw ID Token")
 	}
 
 	// NOTE: Skip checking nonce if the expected nonce is empty. It is OK because
@@ -97,18 +99,25 @@ func (p *IdentityProvider) UserInfo(ctx context.Context, token *oauth2.Token, no
 	// and some IdP implementations are just behaving strangely that would return a
 	// random nonce when we send an empty nonce to them.
 	if nonce != "" && nonce != idToken.Nonce {
-		return nil, errors.Errorf("mismatched nonce, want %q but got %q", nonce, idToken.Nonce)
+		return nil, nil, errors.Errorf("mismatched nonce, want %q but got %q", nonce, idToken.Nonce)
 	}
 
 	rawUserInfo, err := p.provider.UserInfo(ctx, oauth2.StaticTokenSource(token))
 	if err != nil {
-		return nil, errors.Wrap(err, "fetch user info")
+		return nil, nil, errors.Wrap(err, "fetch user info")
 	}
 
-	var claims map[string]any
-	err = rawUserInfo.Claims(&claims)
+	claims := make(map[string]any)
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal ID Token claims")
+	}
+	var userInfoClaims map[string]any
+	err = rawUserInfo.Claims(&userInfoClaims)
 	if err != nil {
-		return nil, errors.Wrap(err, "unmarshal claims")
+		return nil, nil, errors.Wrap(err, "unmarshal claims")
+	}
+	for k, v := range userInfoClaims {
+		claims[k] = v
 	}
 	log.Debug("User info", zap.Any("claims", claims))
 
@@ -117,7 +126,7 @@ func (p *IdentityProvider) UserInfo(ctx context.Context, token *oauth2.Token, no
 		userInfo.Identifier = v
 	}
 	if userInfo.Identifier == "" {
-		return nil, errors.Errorf("the field %q is not found in claims or has empty value", p.config.FieldMapping.Identifier)
+		return nil, nil, errors.Errorf("the field %q is not found in claims or has empty value", p.config.FieldMapping.Identifier)
 	}
 
 	// Best effort to map optional fields
@@ -134,5 +143,5 @@ func (p *IdentityProvider) UserInfo(ctx context.Context, token *oauth2.Token, no
 			userInfo.Email = v
 		}
 	}
-	return userInfo, nil
+	return userInfo, claims, nil
 }
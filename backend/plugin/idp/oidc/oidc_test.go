@@ -163,6 +163,8 @@ func newMockServer(t *testing.T, clientID, code, accessToken, nonce string, user
 			"exp":   time.Now().Add(time.Hour).Unix(),
 			"iat":   time.Now().Unix(),
 			"nonce": nonce,
+			// Some issuers only put the groups in the ID Token.
+			"groups": []string{"dba"},
 		},
 	)
 	rawIDToken, err = token.SignedString(rs256)
@@ -211,8 +213,10 @@ func TestIdentityProvider(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, testAccessToken, oauthToken.AccessToken)
 
-	userInfo, err := oidc.UserInfo(ctx, oauthToken, testNonce)
+	userInfo, claims, err := oidc.UserInfo(ctx, oauthToken, testNonce)
 	require.NoError(t, err)
+	assert.Equal(t, []any{"dba"}, claims["groups"])
+	assert.Equal(t, testEmail, claims["email"])
 
 	wantUserInfo := &storepb.IdentityProviderUserInfo{
 		Identifier:  testSubject,
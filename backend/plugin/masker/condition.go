@@ -0,0 +1,77 @@
+package masker
+
+import (
+	"github.com/google/cel-go/cel"
+	"github.com/pkg/errors"
+)
+
+// conditionEnvOptions declares the variables of the mask conditions. The row is the unmasked values of the row
+// indexed by the column names in the result, and the requester is the principal querying with the email, name and
+// role.
+var conditionEnvOptions = []cel.EnvOption{
+	cel.Variable("row", cel.MapType(cel.StringType, cel.DynType)),
+	cel.Variable("requester", cel.MapType(cel.StringType, cel.StringType)),
+}
+
+// Condition decides whether to mask the sensitive values of a row.
+type Condition struct {
+	program cel.Program
+}
+
+// NewCondition compiles the mask condition, which is a CEL expression returning true if the value should be masked,
+// e.g. `row.country == "DE"` or `row.owner_email != requester.email`.
+func NewCondition(expression string) (*Condition, error) {
+	e, err := cel.NewEnv(conditionEnvOptions...)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := e.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Wrapf(issues.Err(), "invalid mask condition %q", expression)
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, errors.Errorf("mask condition %q must return a bool, got %s", expression, ast.OutputType())
+	}
+	program, err := e.Program(ast)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid mask condition %q", expression)
+	}
+	return &Condition{program: program}, nil
+}
+
+// ValidateCondition validates the mask condition.
+func ValidateCondition(expression string) error {
+	_, err := NewCondition(expression)
+	return err
+}
+
+// ShouldMask returns true if the value of the row should be masked for the requester. It fails safe to mask if the
+// condition can't be evaluated, e.g. the column it refers to isn't selected in the query.
+func (c *Condition) ShouldMask(row map[string]any, requester map[string]string) bool {
+	out, _, err := c.program.Eval(map[string]any{
+		"row":       row,
+		"requester": requester,
+	})
+	if err != nil {
+		return true
+	}
+	v, ok := out.Value().(bool)
+	return !ok || v
+}
+
+// ShouldMaskForRequester evaluates the mask condition for the requester regardless of the rows. The decided is false
+// if the result depends on the values of the rows, e.g. `row.owner != requester.email`.
+func (c *Condition) ShouldMaskForRequester(requester map[string]string) (mask bool, decided bool) {
+	out, _, err := c.program.Eval(map[string]any{
+		"row":       map[string]any{},
+		"requester": requester,
+	})
+	if err != nil {
+		return true, false
+	}
+	v, ok := out.Value().(bool)
+	if !ok {
+		return true, false
+	}
+	return v, true
+}
@@ -0,0 +1,54 @@
+package masker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCondition(t *testing.T) {
+	requester := map[string]string{"email": "alice@example.com", "name": "Alice", "role": "DEVELOPER"}
+	tests := []struct {
+		expression string
+		row        map[string]any
+		want       bool
+	}{
+		{`row.country == "DE"`, map[string]any{"country": "DE"}, true},
+		{`row.country == "DE"`, map[string]any{"country": "US"}, false},
+		{`row.owner != requester.email`, map[string]any{"owner": "alice@example.com"}, false},
+		{`requester.role != "DBA" && row.salary > 1000`, map[string]any{"salary": int64(2000)}, true},
+		{`requester.role != "DBA" && row.salary > 1000`, map[string]any{"salary": int64(10)}, false},
+		// The conditions which can't be evaluated fail safe to mask.
+		{`row.country == "DE"`, map[string]any{}, true},
+		{`row.salary > 1000`, map[string]any{"salary": nil}, true},
+	}
+	for _, test := range tests {
+		condition, err := NewCondition(test.expression)
+		require.NoError(t, err, test.expression)
+		require.Equal(t, test.want, condition.ShouldMask(test.row, requester), test.expression)
+	}
+
+	requesterTests := []struct {
+		expression string
+		role       string
+		mask       bool
+		decided    bool
+	}{
+		{`requester.role != "DBA"`, "DBA", false, true},
+		{`requester.role != "DBA"`, "DEVELOPER", true, true},
+		{`requester.role != "DBA" && row.salary > 1000`, "DBA", false, true},
+		{`requester.role != "DBA" && row.salary > 1000`, "DEVELOPER", true, false},
+		{`row.owner != requester.email`, "DBA", true, false},
+	}
+	for _, test := range requesterTests {
+		condition, err := NewCondition(test.expression)
+		require.NoError(t, err, test.expression)
+		mask, decided := condition.ShouldMaskForRequester(map[string]string{"email": "alice@example.com", "name": "Alice", "role": test.role})
+		require.Equal(t, test.decided, decided, test.expression)
+		require.Equal(t, test.mask, mask, test.expression)
+	}
+
+	for _, expression := range []string{`row.country ==`, `"DE"`, `unknown.country == "DE"`} {
+		require.Error(t, ValidateCondition(expression), expression)
+	}
+}
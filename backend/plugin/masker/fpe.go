@@ -0,0 +1,227 @@
+package masker
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// The format-preserving encryption of NIST SP 800-38G. The numeral strings are the slices of the numerals in
+// [0, radix), and the radix^minlen must be at least 1,000,000.
+const fpeMinDomainSize = 1000000
+
+// ff1 is the FF1 mode of the format-preserving encryption.
+type ff1 struct {
+	block cipher.Block
+	radix int
+	tweak []byte
+}
+
+func newFF1(key []byte, radix int, tweak []byte) (*ff1, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if radix < 2 || radix > 65536 {
+		return nil, errors.Errorf("invalid radix %d", radix)
+	}
+	return &ff1{block: block, radix: radix, tweak: tweak}, nil
+}
+
+func (f *ff1) encrypt(x []int) ([]int, error) {
+	n := len(x)
+	if !isFPEDomainLargeEnough(f.radix, n) {
+		return nil, errors.Errorf("the domain of %d numerals of radix %d is too small", n, f.radix)
+	}
+	u := n / 2
+	v := n - u
+	a, b := x[:u], x[u:]
+	t := len(f.tweak)
+
+	// b is the number of the bytes of NUM(B), and d is the number of the bytes of the pseudorandom output.
+	bBytes := (bitLength(f.radix, v) + 7) / 8
+	d := 4*((bBytes+3)/4) + 4
+
+	p := []byte{1, 2, 1, byte(f.radix >> 16), byte(f.radix >> 8), byte(f.radix), 10, byte(u)}
+	p = append(p, uint32Bytes(uint32(n))...)
+	p = append(p, uint32Bytes(uint32(t))...)
+
+	radix := big.NewInt(int64(f.radix))
+	for i := 0; i < 10; i++ {
+		q := append([]byte{}, f.tweak...)
+		q = append(q, make([]byte, mod(-t-bBytes-1, 16))...)
+		q = append(q, byte(i))
+		q = append(q, fixedBytes(num(b, radix), bBytes)...)
+
+		r := f.prf(append(append([]byte{}, p...), q...))
+		s := append([]byte{}, r...)
+		for j := 1; len(s) < d; j++ {
+			block := append([]byte{}, r...)
+			counter := uint32Bytes(uint32(j))
+			for k := range counter {
+				block[12+k] ^= counter[k]
+			}
+			f.block.Encrypt(block, block)
+			s = append(s, block...)
+		}
+		y := new(big.Int).SetBytes(s[:d])
+
+		m := u
+		if i%2 == 1 {
+			m = v
+		}
+		c := new(big.Int).Add(num(a, radix), y)
+		c.Mod(c, new(big.Int).Exp(radix, big.NewInt(int64(m)), nil))
+		a, b = b, str(c, radix, m)
+	}
+	return append(append([]int{}, a...), b...), nil
+}
+
+// prf is the CBC-MAC of the blocks with the zero IV.
+func (f *ff1) prf(x []byte) []byte {
+	y := make([]byte, aes.BlockSize)
+	for i := 0; i < len(x); i += aes.BlockSize {
+		for j := 0; j < aes.BlockSize; j++ {
+			y[j] ^= x[i+j]
+		}
+		f.block.Encrypt(y, y)
+	}
+	return y
+}
+
+// ff3 is the FF3-1 mode of the format-preserving encryption, with the 56-bit tweak.
+type ff3 struct {
+	block cipher.Block
+	radix int
+	// tweak is the 64-bit tweak of FF3, which is converted from the 56-bit tweak of FF3-1.
+	tweak [8]byte
+}
+
+func newFF3(key []byte, radix int, tweak [7]byte) (*ff3, error) {
+	// FF3 encrypts with the reversed key.
+	reversedKey := reverseBytes(key)
+	block, err := aes.NewCipher(reversedKey)
+	if err != nil {
+		return nil, err
+	}
+	if radix < 2 || radix > 65536 {
+		return nil, errors.Errorf("invalid radix %d", radix)
+	}
+	f := &ff3{block: block, radix: radix}
+	// TL is the first 28 bits followed by 4 zero bits, and TR is the last 24 bits followed by the middle 4 bits and
+	// 4 zero bits.
+	f.tweak = [8]byte{tweak[0], tweak[1], tweak[2], tweak[3] & 0xF0, tweak[4], tweak[5], tweak[6], tweak[3] << 4}
+	return f, nil
+}
+
+// maxLength returns the maximum number of the numerals, which is 2*floor(log_radix(2^96)).
+func (f *ff3) maxLength() int {
+	limit := new(big.Int).Lsh(big.NewInt(1), 96)
+	size := big.NewInt(int64(f.radix))
+	k := 0
+	for size.Cmp(limit) <= 0 {
+		size.Mul(size, big.NewInt(int64(f.radix)))
+		k++
+	}
+	return 2 * k
+}
+
+func (f *ff3) encrypt(x []int) ([]int, error) {
+	n := len(x)
+	if !isFPEDomainLargeEnough(f.radix, n) {
+		return nil, errors.Errorf("the domain of %d numerals of radix %d is too small", n, f.radix)
+	}
+	if n > f.maxLength() {
+		return nil, errors.Errorf("the %d numerals exceed the maximum length %d of radix %d", n, f.maxLength(), f.radix)
+	}
+	u := (n + 1) / 2
+	v := n - u
+	a, b := x[:u], x[u:]
+	tl, tr := f.tweak[:4], f.tweak[4:]
+
+	radix := big.NewInt(int64(f.radix))
+	for i := 0; i < 8; i++ {
+		m, w := u, tr
+		if i%2 == 1 {
+			m, w = v, tl
+		}
+		p := append([]byte{}, w...)
+		p[3] ^= byte(i)
+		p = append(p, fixedBytes(num(reverseInts(b), radix), 12)...)
+
+		s := reverseBytes(p)
+		f.block.Encrypt(s, s)
+		s = reverseBytes(s)
+		y := new(big.Int).SetBytes(s)
+
+		c := new(big.Int).Add(num(reverseInts(a), radix), y)
+		c.Mod(c, new(big.Int).Exp(radix, big.NewInt(int64(m)), nil))
+		a, b = b, reverseInts(str(c, radix, m))
+	}
+	return append(append([]int{}, a...), b...), nil
+}
+
+func isFPEDomainLargeEnough(radix int, n int) bool {
+	size := new(big.Int).Exp(big.NewInt(int64(radix)), big.NewInt(int64(n)), nil)
+	return size.Cmp(big.NewInt(fpeMinDomainSize)) >= 0
+}
+
+// num returns the number the numerals represent in the radix, the most significant numeral first.
+func num(x []int, radix *big.Int) *big.Int {
+	result := new(big.Int)
+	for _, v := range x {
+		result.Mul(result, radix)
+		result.Add(result, big.NewInt(int64(v)))
+	}
+	return result
+}
+
+// str returns the m numerals representing the number in the radix.
+func str(x *big.Int, radix *big.Int, m int) []int {
+	result := make([]int, m)
+	value := new(big.Int).Set(x)
+	rem := new(big.Int)
+	for i := m - 1; i >= 0; i-- {
+		value.DivMod(value, radix, rem)
+		result[i] = int(rem.Int64())
+	}
+	return result
+}
+
+// bitLength returns ceil(n * log2(radix)).
+func bitLength(radix int, n int) int {
+	size := new(big.Int).Exp(big.NewInt(int64(radix)), big.NewInt(int64(n)), nil)
+	size.Sub(size, big.NewInt(1))
+	return size.BitLen()
+}
+
+func fixedBytes(x *big.Int, length int) []byte {
+	result := make([]byte, length)
+	return x.FillBytes(result)
+}
+
+func uint32Bytes(x uint32) []byte {
+	return []byte{byte(x >> 24), byte(x >> 16), byte(x >> 8), byte(x)}
+}
+
+func mod(x, m int) int {
+	return ((x % m) + m) % m
+}
+
+func reverseBytes(x []byte) []byte {
+	result := make([]byte, len(x))
+	for i, v := range x {
+		result[len(x)-1-i] = v
+	}
+	return result
+}
+
+func reverseInts(x []int) []int {
+	result := make([]int, len(x))
+	for i, v := range x {
+		result[len(x)-1-i] = v
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package masker
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFF1(t *testing.T) {
+	// The samples of NIST SP 800-38G.
+	tests := []struct {
+		key        string
+		radix      int
+		tweak      string
+		plaintext  string
+		ciphertext string
+	}{
+		{key: "2B7E151628AED2A6ABF7158809CF4F3C", radix: 10, plaintext: "0123456789", ciphertext: "2433477484"},
+		{key: "2B7E151628AED2A6ABF7158809CF4F3C", radix: 10, tweak: "39383736353433323130", plaintext: "0123456789", ciphertext: "6124200773"},
+		{key: "2B7E151628AED2A6ABF7158809CF4F3C", radix: 36, tweak: "3737373770717273373737", plaintext: "0123456789abcdefghi", ciphertext: "a9tv40mll9kdu509eum"},
+		{key: "2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F7F036D6F04FC6A94", radix: 10, plaintext: "0123456789", ciphertext: "6657667009"},
+	}
+	for _, test := range tests {
+		key, err := hex.DecodeString(test.key)
+		require.NoError(t, err)
+		tweak, err := hex.DecodeString(test.tweak)
+		require.NoError(t, err)
+		f, err := newFF1(key, test.radix, tweak)
+		require.NoError(t, err)
+		ciphertext, err := f.encrypt(toNumerals(test.plaintext))
+		require.NoError(t, err)
+		require.Equal(t, test.ciphertext, fromNumerals(ciphertext), test.plaintext)
+	}
+
+	f, err := newFF1(make([]byte, 16), 10, nil)
+	require.NoError(t, err)
+	_, err = f.encrypt(toNumerals("12345"))
+	require.Error(t, err)
+}
+
+func TestFF3(t *testing.T) {
+	// The sample of FF3 in NIST SP 800-38G, with the 64-bit tweak D8E7920AFA330A73.
+	key, err := hex.DecodeString("EF4359D8D580AA4F7F036D6F04FC6A94")
+	require.NoError(t, err)
+	f, err := newFF3(key, 10, [7]byte{})
+	require.NoError(t, err)
+	copy(f.tweak[:], []byte{0xD8, 0xE7, 0x92, 0x0A, 0xFA, 0x33, 0x0A, 0x73})
+	ciphertext, err := f.encrypt(toNumerals("890121234567890000"))
+	require.NoError(t, err)
+	require.Equal(t, "750918814058654607", fromNumerals(ciphertext))
+
+	require.Equal(t, 56, f.maxLength())
+	_, err = f.encrypt(toNumerals("12345"))
+	require.Error(t, err)
+}
+
+func toNumerals(s string) []int {
+	var result []int
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			result = append(result, int(c-'0'))
+		default:
+			result = append(result, int(c-'a')+10)
+		}
+	}
+	return result
+}
+
+func fromNumerals(x []int) string {
+	var result []byte
+	for _, v := range x {
+		if v < 10 {
+			result = append(result, byte('0'+v))
+		} else {
+			result = append(result, byte('a'+v-10))
+		}
+	}
+	return string(result)
+}
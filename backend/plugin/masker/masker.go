@@ -0,0 +1,190 @@
+// Package masker provides the algorithms masking the sensitive values in the query results.
+package masker
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// defaultMaskedValue is the value of the masked data of the default algorithm.
+const defaultMaskedValue = "******"
+
+// Masker masks the sensitive values.
+type Masker interface {
+	// Mask returns the masked value. The NULLs are kept except for the default algorithm, which hides everything.
+	Mask(value any) any
+}
+
+// NewMasker creates the masker of the mask type. The masks of the salted hash, HMAC and format-preserving encryption
+// are deterministic with the same secret, so the masked values can still be joined across the queries and exports.
+// The keys of the algorithms are derived from the secret separately.
+func NewMasker(maskType db.SensitiveDataMaskType, secret string) (Masker, error) {
+	switch maskType {
+	case "", db.SensitiveDataMaskTypeDefault:
+		return defaultMasker{}, nil
+	case db.SensitiveDataMaskTypeSHA256:
+		return &sha256Masker{salt: deriveKey(secret, maskType)}, nil
+	case db.SensitiveDataMaskTypeHMAC:
+		return &hmacMasker{key: deriveKey(secret, maskType)}, nil
+	case db.SensitiveDataMaskTypeFF1, db.SensitiveDataMaskTypeFF3:
+		return newFPEMasker(maskType, deriveKey(secret, maskType))
+	}
+	return nil, errors.Errorf("unsupported mask type %q", maskType)
+}
+
+// deriveKey derives the 256-bit key of the mask type from the secret.
+func deriveKey(secret string, maskType db.SensitiveDataMaskType) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte("bb.masking." + string(maskType)))
+	return h.Sum(nil)
+}
+
+type defaultMasker struct{}
+
+func (defaultMasker) Mask(any) any {
+	return defaultMaskedValue
+}
+
+// sha256Masker masks the values with the hex of the SHA-256 of the salt and the value.
+type sha256Masker struct {
+	salt []byte
+}
+
+func (m *sha256Masker) Mask(value any) any {
+	if value == nil {
+		return nil
+	}
+	h := sha256.New()
+	h.Write(m.salt)
+	h.Write([]byte(toString(value)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hmacMasker masks the values with the hex of the HMAC-SHA256 of the value.
+type hmacMasker struct {
+	key []byte
+}
+
+func (m *hmacMasker) Mask(value any) any {
+	if value == nil {
+		return nil
+	}
+	h := hmac.New(sha256.New, m.key)
+	h.Write([]byte(toString(value)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fpeMasker masks the values with the format-preserving encryption. The digits and the letters are encrypted in
+// place separately, with the cases of the letters kept, and the other characters are kept as is. For example, the
+// masked phone number "+1 (555) 010-9999" is still a phone number.
+type fpeMasker struct {
+	digits  fpeCipher
+	letters fpeCipher
+	// key derives the numerals of the runs too short to encrypt.
+	key []byte
+}
+
+type fpeCipher interface {
+	encrypt(x []int) ([]int, error)
+}
+
+func newFPEMasker(maskType db.SensitiveDataMaskType, key []byte) (*fpeMasker, error) {
+	m := &fpeMasker{key: key}
+	var err error
+	switch maskType {
+	case db.SensitiveDataMaskTypeFF1:
+		if m.digits, err = newFF1(key, 10, nil); err != nil {
+			return nil, err
+		}
+		if m.letters, err = newFF1(key, 26, nil); err != nil {
+			return nil, err
+		}
+	case db.SensitiveDataMaskTypeFF3:
+		var tweak [7]byte
+		if m.digits, err = newFF3(key, 10, tweak); err != nil {
+			return nil, err
+		}
+		if m.letters, err = newFF3(key, 26, tweak); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *fpeMasker) Mask(value any) any {
+	if value == nil {
+		return nil
+	}
+	s := []rune(toString(value))
+	var digitIndexes, letterIndexes []int
+	var digits, letters []int
+	for i, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digitIndexes = append(digitIndexes, i)
+			digits = append(digits, int(c-'0'))
+		case c >= 'a' && c <= 'z':
+			letterIndexes = append(letterIndexes, i)
+			letters = append(letters, int(c-'a'))
+		case c >= 'A' && c <= 'Z':
+			letterIndexes = append(letterIndexes, i)
+			letters = append(letters, int(c-'A'))
+		}
+	}
+	for i, v := range m.encrypt(m.digits, 10, digits) {
+		s[digitIndexes[i]] = rune('0' + v)
+	}
+	for i, v := range m.encrypt(m.letters, 26, letters) {
+		if s[letterIndexes[i]] >= 'a' {
+			s[letterIndexes[i]] = rune('a' + v)
+		} else {
+			s[letterIndexes[i]] = rune('A' + v)
+		}
+	}
+	return string(s)
+}
+
+// encrypt encrypts the numerals, and derives them deterministically from the key if they are too short or too long
+// to encrypt.
+func (m *fpeMasker) encrypt(c fpeCipher, radix int, x []int) []int {
+	if len(x) == 0 {
+		return nil
+	}
+	if result, err := c.encrypt(x); err == nil {
+		return result
+	}
+	h := hmac.New(sha256.New, m.key)
+	h.Write([]byte(fmt.Sprint(radix, x)))
+	sum := h.Sum(nil)
+	result := make([]int, len(x))
+	for i := range result {
+		if i > 0 && i%len(sum) == 0 {
+			h.Reset()
+			h.Write(sum)
+			sum = h.Sum(nil)
+		}
+		result[i] = int(sum[i%len(sum)]) % radix
+	}
+	return result
+}
+
+func toString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return hex.EncodeToString(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,65 @@
+package masker
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestMasker(t *testing.T) {
+	m, err := NewMasker(db.SensitiveDataMaskTypeDefault, "secret")
+	require.NoError(t, err)
+	require.Equal(t, "******", m.Mask(nil))
+	require.Equal(t, "******", m.Mask(int64(1)))
+
+	for _, maskType := range []db.SensitiveDataMaskType{db.SensitiveDataMaskTypeSHA256, db.SensitiveDataMaskTypeHMAC} {
+		m, err := NewMasker(maskType, "secret")
+		require.NoError(t, err)
+		masked := m.Mask("alice@example.com")
+		require.Regexp(t, regexp.MustCompile(`^[0-9a-f]{64}$`), masked)
+		// The masks are deterministic, so the masked values can be joined.
+		require.Equal(t, masked, m.Mask("alice@example.com"))
+		require.Equal(t, m.Mask("42"), m.Mask(int64(42)))
+		require.NotEqual(t, masked, m.Mask("bob@example.com"))
+		require.Nil(t, m.Mask(nil))
+
+		other, err := NewMasker(maskType, "another secret")
+		require.NoError(t, err)
+		require.NotEqual(t, masked, other.Mask("alice@example.com"))
+	}
+	sha256Masker, err := NewMasker(db.SensitiveDataMaskTypeSHA256, "secret")
+	require.NoError(t, err)
+	hmacMasker, err := NewMasker(db.SensitiveDataMaskTypeHMAC, "secret")
+	require.NoError(t, err)
+	require.NotEqual(t, sha256Masker.Mask("alice"), hmacMasker.Mask("alice"))
+
+	_, err = NewMasker("UNKNOWN", "secret")
+	require.Error(t, err)
+}
+
+func TestFPEMasker(t *testing.T) {
+	for _, maskType := range []db.SensitiveDataMaskType{db.SensitiveDataMaskTypeFF1, db.SensitiveDataMaskTypeFF3} {
+		m, err := NewMasker(maskType, "secret")
+		require.NoError(t, err)
+
+		phone := "+1 (555) 010-9999"
+		masked := m.Mask(phone).(string)
+		require.Regexp(t, regexp.MustCompile(`^\+\d \(\d{3}\) \d{3}-\d{4}$`), masked)
+		require.NotEqual(t, phone, masked)
+		require.Equal(t, masked, m.Mask(phone))
+
+		masked = m.Mask("Alice Smith").(string)
+		require.Regexp(t, regexp.MustCompile(`^[A-Z][a-z]{4} [A-Z][a-z]{4}$`), masked)
+
+		// The runs too short to encrypt are still masked in the format.
+		require.Regexp(t, regexp.MustCompile(`^\d{3}$`), m.Mask("123"))
+		// The runs too long for FF3-1 too.
+		require.Regexp(t, regexp.MustCompile(`^\d{60}$`), m.Mask(strings.Repeat("7", 60)))
+		require.Equal(t, "@-", m.Mask("@-"))
+		require.Nil(t, m.Mask(nil))
+	}
+}
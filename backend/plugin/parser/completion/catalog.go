@@ -0,0 +1,267 @@
+package completion
+
+import (
+	"strings"
+
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+type table struct {
+	schema  string
+	name    string
+	view    bool
+	columns []*storepb.ColumnMetadata
+}
+
+// tableRef is a table referred in the statement.
+type tableRef struct {
+	alias string
+	// table is nil if the table isn't in the metadata.
+	table *table
+}
+
+// catalog is the metadata of the connected database.
+type catalog struct {
+	engine        parser.EngineType
+	databaseName  string
+	defaultSchema string
+	schemas       []string
+	tables        []*table
+	functions     []*Candidate
+}
+
+func newCatalog(engine parser.EngineType, metadata *storepb.DatabaseMetadata) *catalog {
+	c := &catalog{
+		engine:       engine,
+		databaseName: metadata.GetName(),
+	}
+	if !isMySQLDialect(engine) {
+		c.defaultSchema = "public"
+	}
+	for _, schema := range metadata.GetSchemas() {
+		if schema.Name != "" {
+			c.schemas = append(c.schemas, schema.Name)
+		}
+		for _, t := range schema.Tables {
+			c.tables = append(c.tables, &table{schema: schema.Name, name: t.Name, columns: t.Columns})
+		}
+		for _, view := range schema.Views {
+			c.tables = append(c.tables, &table{schema: schema.Name, name: view.Name, view: true})
+		}
+		for _, function := range schema.Functions {
+			c.functions = append(c.functions, &Candidate{Text: c.getDisplayName(schema.Name, function.Name), Type: CandidateTypeFunction, Detail: "function"})
+		}
+	}
+	for _, function := range getBuiltinFunctions(engine) {
+		c.functions = append(c.functions, &Candidate{Text: function, Type: CandidateTypeFunction, Detail: "built-in function"})
+	}
+	return c
+}
+
+// getDisplayName returns the name of the object qualified by the schema unless it's in the default schema.
+func (c *catalog) getDisplayName(schema, name string) string {
+	if schema == "" || schema == c.defaultSchema {
+		return name
+	}
+	return schema + "." + name
+}
+
+// findTable finds the table by the qualified name in the statement.
+func (c *catalog) findTable(parts []string) *table {
+	var schema, name string
+	switch {
+	case len(parts) == 1:
+		name = parts[0]
+		var found *table
+		for _, t := range c.tables {
+			if !strings.EqualFold(t.name, name) {
+				continue
+			}
+			// The unqualified name is resolved to the default schema first.
+			if t.schema == c.defaultSchema {
+				return t
+			}
+			if found == nil {
+				found = t
+			}
+		}
+		return found
+	case len(parts) == 2 && isMySQLDialect(c.engine):
+		// The MySQL dialect qualifies the tables by the database name.
+		if !strings.EqualFold(parts[0], c.databaseName) {
+			return nil
+		}
+		name = parts[1]
+	case len(parts) == 2:
+		schema, name = parts[0], parts[1]
+	case len(parts) == 3 && !isMySQLDialect(c.engine):
+		if !strings.EqualFold(parts[0], c.databaseName) {
+			return nil
+		}
+		schema, name = parts[1], parts[2]
+	default:
+		return nil
+	}
+	for _, t := range c.tables {
+		if strings.EqualFold(t.schema, schema) && strings.EqualFold(t.name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// getTableRefs returns the tables referred in the FROM, JOIN, UPDATE and INTO clauses of the statement.
+func (c *catalog) getTableRefs(tokens []*token) []*tableRef {
+	var refs []*tableRef
+	for i, t := range tokens {
+		if !t.isKeyword("FROM", "JOIN", "UPDATE", "INTO") {
+			continue
+		}
+		j := i + 1
+		for {
+			var ref *tableRef
+			ref, j = c.parseTableRef(tokens, j)
+			if ref == nil {
+				break
+			}
+			refs = append(refs, ref)
+			// Only the FROM clause has the comma separated table references.
+			if !t.isKeyword("FROM") || j >= len(tokens) || !tokens[j].isPunctuation(",") {
+				break
+			}
+			j++
+		}
+	}
+	return refs
+}
+
+// parseTableRef parses the table reference "[schema.]table [[AS] alias]" at the offset, and returns the offset after it.
+func (c *catalog) parseTableRef(tokens []*token, i int) (*tableRef, int) {
+	var parts []string
+	for i < len(tokens) && tokens[i].kind == tokenIdentifier && !tokens[i].isKeyword(reservedKeywords...) {
+		parts = append(parts, tokens[i].text)
+		i++
+		if i < len(tokens) && tokens[i].isPunctuation(".") {
+			i++
+			continue
+		}
+		break
+	}
+	if len(parts) == 0 {
+		return nil, i
+	}
+	ref := &tableRef{table: c.findTable(parts)}
+	if i < len(tokens) && tokens[i].isKeyword("AS") {
+		i++
+		if i < len(tokens) && tokens[i].kind == tokenIdentifier {
+			ref.alias = tokens[i].text
+			i++
+		}
+	} else if i < len(tokens) && tokens[i].kind == tokenIdentifier && !tokens[i].isKeyword(reservedKeywords...) {
+		ref.alias = tokens[i].text
+		i++
+	}
+	return ref, i
+}
+
+func (c *catalog) getTableCandidates(score int) []*Candidate {
+	var candidates []*Candidate
+	for _, t := range c.tables {
+		candidateType := CandidateTypeTable
+		if t.view {
+			candidateType = CandidateTypeView
+		}
+		candidates = append(candidates, &Candidate{Text: c.getDisplayName(t.schema, t.name), Type: candidateType, Parent: t.schema, score: score})
+	}
+	return candidates
+}
+
+func (c *catalog) getSchemaCandidates(score int) []*Candidate {
+	var candidates []*Candidate
+	for _, schema := range c.schemas {
+		candidates = append(candidates, &Candidate{Text: schema, Type: CandidateTypeSchema, score: score})
+	}
+	return candidates
+}
+
+func (c *catalog) getFunctionCandidates(score int) []*Candidate {
+	var candidates []*Candidate
+	for _, function := range c.functions {
+		candidate := *function
+		candidate.score = score
+		candidates = append(candidates, &candidate)
+	}
+	return candidates
+}
+
+func (c *catalog) getColumnCandidates(t *table, score int) []*Candidate {
+	var candidates []*Candidate
+	for _, column := range t.columns {
+		candidates = append(candidates, &Candidate{Text: column.Name, Type: CandidateTypeColumn, Parent: c.getDisplayName(t.schema, t.name), Detail: column.Type, score: score})
+	}
+	return candidates
+}
+
+// getScopeCandidates returns the columns of the tables referred in the statement and their aliases. If no table is
+// referred yet, it returns the columns of all the tables with the lower score.
+func (c *catalog) getScopeCandidates(refs []*tableRef, columnScore, aliasScore int) []*Candidate {
+	var candidates []*Candidate
+	for _, ref := range refs {
+		if ref.table == nil {
+			continue
+		}
+		candidates = append(candidates, c.getColumnCandidates(ref.table, columnScore)...)
+		if ref.alias != "" {
+			candidates = append(candidates, &Candidate{Text: ref.alias, Type: CandidateTypeAlias, Detail: c.getDisplayName(ref.table.schema, ref.table.name), score: aliasScore})
+		}
+	}
+	if len(candidates) == 0 {
+		for _, t := range c.tables {
+			candidates = append(candidates, c.getColumnCandidates(t, columnScore-60)...)
+		}
+	}
+	return candidates
+}
+
+// getQualifiedCandidates returns the candidates after the qualifier such as "t." and "s.t.".
+func (c *catalog) getQualifiedCandidates(qualifier []string, refs []*tableRef) []*Candidate {
+	var candidates []*Candidate
+	if len(qualifier) == 1 {
+		name := qualifier[0]
+		// The alias and the table referred in the statement take precedence over the tables not referred.
+		for _, ref := range refs {
+			if ref.table != nil && strings.EqualFold(ref.alias, name) {
+				candidates = append(candidates, c.getColumnCandidates(ref.table, 100)...)
+			}
+		}
+		if len(candidates) == 0 {
+			for _, ref := range refs {
+				if ref.table != nil && ref.alias == "" && strings.EqualFold(ref.table.name, name) {
+					candidates = append(candidates, c.getColumnCandidates(ref.table, 100)...)
+				}
+			}
+		}
+		if len(candidates) == 0 {
+			if t := c.findTable(qualifier); t != nil {
+				candidates = append(candidates, c.getColumnCandidates(t, 100)...)
+			}
+		}
+		for _, t := range c.tables {
+			isSchema := !isMySQLDialect(c.engine) && strings.EqualFold(t.schema, name)
+			isDatabase := isMySQLDialect(c.engine) && strings.EqualFold(c.databaseName, name)
+			if isSchema || isDatabase {
+				candidateType := CandidateTypeTable
+				if t.view {
+					candidateType = CandidateTypeView
+				}
+				candidates = append(candidates, &Candidate{Text: t.name, Type: candidateType, Parent: t.schema, score: 100})
+			}
+		}
+		return candidates
+	}
+	if t := c.findTable(qualifier); t != nil {
+		candidates = append(candidates, c.getColumnCandidates(t, 100)...)
+	}
+	return candidates
+}
@@ -0,0 +1,252 @@
+// Package completion provides the schema-aware auto completion for the SQL editor. It works on the statement being
+// typed, so it tokenizes the statement instead of parsing it and never fails on the incomplete statements.
+package completion
+
+import (
+	"sort"
+	"strings"
+
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// CandidateType is the type of the completion candidate.
+type CandidateType string
+
+const (
+	// CandidateTypeTable is the table candidate.
+	CandidateTypeTable CandidateType = "TABLE"
+	// CandidateTypeView is the view candidate.
+	CandidateTypeView CandidateType = "VIEW"
+	// CandidateTypeColumn is the column candidate.
+	CandidateTypeColumn CandidateType = "COLUMN"
+	// CandidateTypeAlias is the table alias declared in the statement.
+	CandidateTypeAlias CandidateType = "ALIAS"
+	// CandidateTypeSchema is the schema candidate.
+	CandidateTypeSchema CandidateType = "SCHEMA"
+	// CandidateTypeFunction is the function candidate.
+	CandidateTypeFunction CandidateType = "FUNCTION"
+)
+
+// maxCandidateCount is the maximum number of the candidates returned.
+const maxCandidateCount = 200
+
+// Candidate is a completion candidate.
+type Candidate struct {
+	Text string        `json:"text"`
+	Type CandidateType `json:"type"`
+	// Parent is the table of the column candidates, and the schema of the table candidates.
+	Parent string `json:"parent,omitempty"`
+	// Detail is the column type of the column candidates, and the aliased table of the alias candidates.
+	Detail string `json:"detail,omitempty"`
+
+	score int
+}
+
+type completionContext int
+
+const (
+	// contextOther is the position we don't know, such as after a complete table reference.
+	contextOther completionContext = iota
+	// contextTable is the position expecting a table, such as after FROM.
+	contextTable
+	// contextColumn is the position expecting an expression, such as after SELECT and WHERE.
+	contextColumn
+)
+
+// tableClauseKeywords are the keywords followed by the table references.
+var tableClauseKeywords = []string{"FROM", "JOIN", "UPDATE", "INTO", "TABLE"}
+
+// columnClauseKeywords are the keywords followed by the expressions.
+var columnClauseKeywords = []string{"SELECT", "WHERE", "ON", "BY", "HAVING", "SET", "AND", "OR", "NOT", "CASE", "WHEN", "THEN", "ELSE", "DISTINCT"}
+
+// reservedKeywords are the keywords that can't be the table names and aliases without quotes.
+var reservedKeywords = []string{
+	"SELECT", "FROM", "WHERE", "JOIN", "INNER", "LEFT", "RIGHT", "FULL", "CROSS", "OUTER", "NATURAL", "STRAIGHT_JOIN",
+	"LATERAL", "ON", "USING", "GROUP", "ORDER", "HAVING", "LIMIT", "OFFSET", "UNION", "EXCEPT", "INTERSECT", "WINDOW",
+	"FOR", "SET", "VALUES", "RETURNING", "AS", "AND", "OR", "WITH", "INTO", "FETCH",
+}
+
+// Complete returns the ranked candidates at the caret, which is the rune offset in the statement. The metadata is the
+// metadata of the connected database.
+func Complete(engine parser.EngineType, statement string, caretOffset int, metadata *storepb.DatabaseMetadata) []*Candidate {
+	var tokens []*token
+	for _, t := range tokenize(engine, statement) {
+		if (t.kind == tokenString || t.kind == tokenComment) && t.contains(caretOffset) {
+			// No completion in the strings and comments.
+			return nil
+		}
+		if t.kind != tokenComment {
+			tokens = append(tokens, t)
+		}
+	}
+	tokens = getCurrentStatementTokens(tokens, caretOffset)
+	c := newCatalog(engine, metadata)
+
+	// The prefix is the identifier being typed, and the tokens before it decide the context.
+	prefix := ""
+	before := tokens
+	for i, t := range tokens {
+		if t.start >= caretOffset {
+			before = tokens[:i]
+			break
+		}
+		if t.end >= caretOffset {
+			before = tokens[:i]
+			if t.kind == tokenIdentifier {
+				prefix = getTokenPrefix(t, caretOffset)
+			} else {
+				before = tokens[:i+1]
+			}
+			break
+		}
+	}
+
+	refs := c.getTableRefs(tokens)
+	var candidates []*Candidate
+	if qualifier := getQualifier(before); len(qualifier) > 0 {
+		candidates = c.getQualifiedCandidates(qualifier, refs)
+	} else {
+		switch getCompletionContext(before) {
+		case contextTable:
+			candidates = append(candidates, c.getTableCandidates(100)...)
+			candidates = append(candidates, c.getSchemaCandidates(80)...)
+		case contextColumn:
+			candidates = append(candidates, c.getScopeCandidates(refs, 100, 90)...)
+			candidates = append(candidates, c.getFunctionCandidates(70)...)
+			candidates = append(candidates, c.getTableCandidates(50)...)
+		default:
+			candidates = append(candidates, c.getScopeCandidates(refs, 80, 75)...)
+			candidates = append(candidates, c.getTableCandidates(70)...)
+			candidates = append(candidates, c.getFunctionCandidates(60)...)
+		}
+	}
+	return rankCandidates(candidates, prefix)
+}
+
+// CaretOffset converts the 1-based line and column of the caret into the rune offset in the statement.
+func CaretOffset(statement string, line, column int) int {
+	offset := 0
+	currentLine := 1
+	runes := []rune(statement)
+	for offset < len(runes) && currentLine < line {
+		if runes[offset] == '\n' {
+			currentLine++
+		}
+		offset++
+	}
+	for i := 1; i < column && offset < len(runes) && runes[offset] != '\n'; i++ {
+		offset++
+	}
+	return offset
+}
+
+// getCurrentStatementTokens returns the tokens of the statement containing the caret in the multiple statements.
+func getCurrentStatementTokens(tokens []*token, caretOffset int) []*token {
+	start := 0
+	for i, t := range tokens {
+		if !t.isPunctuation(";") {
+			continue
+		}
+		if t.end > caretOffset {
+			return tokens[start:i]
+		}
+		start = i + 1
+	}
+	return tokens[start:]
+}
+
+func getTokenPrefix(t *token, caretOffset int) string {
+	runes := []rune(t.text)
+	n := caretOffset - t.start
+	if t.quoted {
+		// Skip the opening quote.
+		n--
+	}
+	if n < 0 {
+		return ""
+	}
+	if n > len(runes) {
+		n = len(runes)
+	}
+	return string(runes[:n])
+}
+
+// getQualifier returns the qualifier before the caret, such as ["s", "t"] for "s.t.".
+func getQualifier(before []*token) []string {
+	var qualifier []string
+	for i := len(before) - 1; i >= 1; i -= 2 {
+		if !before[i].isPunctuation(".") || before[i-1].kind != tokenIdentifier {
+			break
+		}
+		qualifier = append([]string{before[i-1].text}, qualifier...)
+	}
+	return qualifier
+}
+
+func getCompletionContext(before []*token) completionContext {
+	depth := 0
+	for i := len(before) - 1; i >= 0; i-- {
+		t := before[i]
+		switch {
+		case t.isPunctuation(")"):
+			depth++
+			continue
+		case t.isPunctuation("("):
+			// The unmatched parenthesis is the one the caret is in, we keep looking for the clause outside it.
+			if depth > 0 {
+				depth--
+			}
+			continue
+		}
+		if depth > 0 {
+			continue
+		}
+		if t.isKeyword(tableClauseKeywords...) {
+			last := before[len(before)-1]
+			if i == len(before)-1 || last.isPunctuation(",") {
+				return contextTable
+			}
+			// The caret is after a complete table reference, such as "FROM t ".
+			return contextOther
+		}
+		if t.isKeyword(columnClauseKeywords...) {
+			return contextColumn
+		}
+	}
+	return contextOther
+}
+
+func rankCandidates(candidates []*Candidate, prefix string) []*Candidate {
+	lowerPrefix := strings.ToLower(prefix)
+	seen := make(map[string]bool)
+	var list []*Candidate
+	for _, candidate := range candidates {
+		key := string(candidate.Type) + "\x00" + candidate.Parent + "\x00" + candidate.Text
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if lowerPrefix != "" {
+			lowerText := strings.ToLower(candidate.Text)
+			switch {
+			case strings.HasPrefix(lowerText, lowerPrefix):
+				candidate.score += 20
+			case strings.Contains(lowerText, lowerPrefix):
+			default:
+				continue
+			}
+		}
+		list = append(list, candidate)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].score != list[j].score {
+			return list[i].score > list[j].score
+		}
+		return strings.ToLower(list[i].Text) < strings.ToLower(list[j].Text)
+	})
+	if len(list) > maxCandidateCount {
+		list = list[:maxCandidateCount]
+	}
+	return list
+}
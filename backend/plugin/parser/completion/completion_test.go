@@ -0,0 +1,155 @@
+package completion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func newTestMetadata(schema string) *storepb.DatabaseMetadata {
+	return &storepb.DatabaseMetadata{
+		Name: "shop",
+		Schemas: []*storepb.SchemaMetadata{
+			{
+				Name: schema,
+				Tables: []*storepb.TableMetadata{
+					{
+						Name: "users",
+						Columns: []*storepb.ColumnMetadata{
+							{Name: "id", Type: "int"},
+							{Name: "name", Type: "varchar(255)"},
+						},
+					},
+					{
+						Name: "orders",
+						Columns: []*storepb.ColumnMetadata{
+							{Name: "id", Type: "int"},
+							{Name: "user_id", Type: "int"},
+							{Name: "amount", Type: "decimal(10,2)"},
+						},
+					},
+				},
+				Views: []*storepb.ViewMetadata{
+					{Name: "user_orders"},
+				},
+			},
+		},
+	}
+}
+
+// complete completes at the "|" in the statement.
+func complete(engine parser.EngineType, statement string, metadata *storepb.DatabaseMetadata) []*Candidate {
+	caret := strings.Index(statement, "|")
+	return Complete(engine, strings.Replace(statement, "|", "", 1), caret, metadata)
+}
+
+func getCandidateTexts(candidates []*Candidate, candidateType CandidateType) []string {
+	var list []string
+	for _, candidate := range candidates {
+		if candidate.Type == candidateType {
+			list = append(list, candidate.Text)
+		}
+	}
+	return list
+}
+
+func TestCompleteTable(t *testing.T) {
+	metadata := newTestMetadata("")
+	candidates := complete(parser.MySQL, "SELECT * FROM |", metadata)
+	require.Equal(t, []string{"orders", "users"}, getCandidateTexts(candidates, CandidateTypeTable))
+	require.Equal(t, []string{"user_orders"}, getCandidateTexts(candidates, CandidateTypeView))
+	require.Empty(t, getCandidateTexts(candidates, CandidateTypeFunction))
+
+	// The prefix matches rank first.
+	candidates = complete(parser.MySQL, "SELECT * FROM orders o JOIN us|", metadata)
+	require.Equal(t, "user_orders", candidates[0].Text)
+	require.Equal(t, "users", candidates[1].Text)
+	require.Len(t, candidates, 2)
+
+	candidates = complete(parser.MySQL, "SELECT * FROM orders, |", metadata)
+	require.Equal(t, []string{"orders", "users"}, getCandidateTexts(candidates, CandidateTypeTable))
+
+	// The schemas are suggested for Postgres.
+	candidates = complete(parser.Postgres, "SELECT * FROM |", newTestMetadata("sales"))
+	require.Equal(t, []string{"sales.orders", "sales.users"}, getCandidateTexts(candidates, CandidateTypeTable))
+	require.Equal(t, []string{"sales"}, getCandidateTexts(candidates, CandidateTypeSchema))
+}
+
+func TestCompleteColumn(t *testing.T) {
+	metadata := newTestMetadata("")
+	candidates := complete(parser.MySQL, "SELECT | FROM orders o", metadata)
+	require.Equal(t, []string{"amount", "id", "user_id"}, getCandidateTexts(candidates, CandidateTypeColumn))
+	require.Equal(t, []string{"o"}, getCandidateTexts(candidates, CandidateTypeAlias))
+	require.Contains(t, getCandidateTexts(candidates, CandidateTypeFunction), "GROUP_CONCAT")
+	require.Equal(t, CandidateTypeColumn, candidates[0].Type)
+
+	// The columns of the aliased table.
+	candidates = complete(parser.MySQL, "SELECT u.| FROM orders o JOIN users AS u ON o.user_id = u.id", metadata)
+	require.Equal(t, []*Candidate{
+		{Text: "id", Type: CandidateTypeColumn, Parent: "users", Detail: "int", score: 100},
+		{Text: "name", Type: CandidateTypeColumn, Parent: "users", Detail: "varchar(255)", score: 100},
+	}, candidates)
+
+	candidates = complete(parser.MySQL, "SELECT * FROM orders o WHERE o.us|", metadata)
+	require.Equal(t, []string{"user_id"}, getCandidateTexts(candidates, CandidateTypeColumn))
+
+	// The table not referred yet.
+	candidates = complete(parser.MySQL, "SELECT users.|", metadata)
+	require.Equal(t, []string{"id", "name"}, getCandidateTexts(candidates, CandidateTypeColumn))
+
+	// The tables of the database.
+	candidates = complete(parser.MySQL, "SELECT * FROM shop.|", metadata)
+	require.Equal(t, []string{"orders", "users"}, getCandidateTexts(candidates, CandidateTypeTable))
+
+	// The columns of the current statement in the multiple statements.
+	candidates = complete(parser.MySQL, "SELECT * FROM users;\nSELECT * FROM orders WHERE |;\nSELECT 1", metadata)
+	require.Equal(t, []string{"amount", "id", "user_id"}, getCandidateTexts(candidates, CandidateTypeColumn))
+}
+
+func TestCompletePostgres(t *testing.T) {
+	metadata := newTestMetadata("public")
+	candidates := complete(parser.Postgres, `SELECT * FROM "orders" AS o WHERE o.|`, metadata)
+	require.Equal(t, []string{"amount", "id", "user_id"}, getCandidateTexts(candidates, CandidateTypeColumn))
+
+	candidates = complete(parser.Postgres, "SELECT * FROM public.users WHERE public.users.|", metadata)
+	require.Equal(t, []string{"id", "name"}, getCandidateTexts(candidates, CandidateTypeColumn))
+
+	candidates = complete(parser.Postgres, "SELECT * FROM public.|", metadata)
+	require.Equal(t, []string{"orders", "users"}, getCandidateTexts(candidates, CandidateTypeTable))
+
+	candidates = complete(parser.Postgres, "SELECT date_t|", metadata)
+	require.Equal(t, []string{"DATE_TRUNC"}, getCandidateTexts(candidates, CandidateTypeFunction))
+}
+
+func TestCompleteIncompleteStatement(t *testing.T) {
+	metadata := newTestMetadata("")
+	// The unterminated string and comment don't break the completion.
+	candidates := complete(parser.MySQL, "SELECT * FROM users u WHERE u.name = 'a' AND u.|", metadata)
+	require.Equal(t, []string{"id", "name"}, getCandidateTexts(candidates, CandidateTypeColumn))
+
+	candidates = complete(parser.MySQL, "SELECT * FROM users /* users */ WHERE |", metadata)
+	require.Equal(t, []string{"id", "name"}, getCandidateTexts(candidates, CandidateTypeColumn))
+
+	// No completion in the strings and comments.
+	require.Empty(t, complete(parser.MySQL, "SELECT * FROM users WHERE name = 'abc |", metadata))
+	require.Empty(t, complete(parser.MySQL, "SELECT 1 -- FROM |", metadata))
+	require.Empty(t, complete(parser.MySQL, "SELECT 1 /* FROM |", metadata))
+
+	candidates = complete(parser.MySQL, "SELECT COUNT(| FROM orders", metadata)
+	require.Equal(t, []string{"amount", "id", "user_id"}, getCandidateTexts(candidates, CandidateTypeColumn))
+
+	candidates = complete(parser.MySQL, "/* comment */|", metadata)
+	require.NotEmpty(t, candidates)
+}
+
+func TestCaretOffset(t *testing.T) {
+	statement := "SELECT *\nFROM 表 t\nWHERE"
+	require.Equal(t, 0, CaretOffset(statement, 1, 1))
+	require.Equal(t, 8, CaretOffset(statement, 1, 100))
+	require.Equal(t, 15, CaretOffset(statement, 2, 7))
+	require.Equal(t, 19, CaretOffset(statement, 3, 2))
+}
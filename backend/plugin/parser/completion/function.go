@@ -0,0 +1,29 @@
+package completion
+
+import (
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+)
+
+// commonFunctions are the built-in functions available in all the supported engines.
+var commonFunctions = []string{
+	"ABS", "AVG", "CAST", "CEIL", "COALESCE", "CONCAT", "COUNT", "FLOOR", "LENGTH", "LOWER", "MAX", "MIN", "NULLIF",
+	"REPLACE", "ROUND", "SUBSTRING", "SUM", "TRIM", "UPPER",
+}
+
+var mysqlFunctions = []string{
+	"CURDATE", "DATE_ADD", "DATE_FORMAT", "DATE_SUB", "DATEDIFF", "FROM_UNIXTIME", "GROUP_CONCAT", "IF", "IFNULL",
+	"JSON_EXTRACT", "JSON_OBJECT", "NOW", "STR_TO_DATE", "UNIX_TIMESTAMP",
+}
+
+var postgresFunctions = []string{
+	"AGE", "ARRAY_AGG", "CURRENT_DATE", "DATE_TRUNC", "EXTRACT", "GENERATE_SERIES", "JSONB_BUILD_OBJECT", "NOW",
+	"REGEXP_REPLACE", "STRING_AGG", "TO_CHAR", "TO_DATE", "TO_TIMESTAMP",
+}
+
+func getBuiltinFunctions(engine parser.EngineType) []string {
+	functions := append([]string{}, commonFunctions...)
+	if isMySQLDialect(engine) {
+		return append(functions, mysqlFunctions...)
+	}
+	return append(functions, postgresFunctions...)
+}
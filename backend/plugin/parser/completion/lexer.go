@@ -0,0 +1,148 @@
+package completion
+
+import (
+	"strings"
+	"unicode"
+
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+)
+
+type tokenKind int
+
+const (
+	tokenIdentifier tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenPunctuation
+	tokenComment
+)
+
+// token is a token of the statement, the offsets are the rune offsets in the statement.
+type token struct {
+	kind tokenKind
+	// text is the unquoted text for the identifiers.
+	text   string
+	quoted bool
+	// open is true if the token extends to the caret at its end, such as the unterminated strings and the line comments.
+	open  bool
+	start int
+	end   int
+}
+
+// contains returns true if the offset is inside the token.
+func (t *token) contains(offset int) bool {
+	return t.start < offset && (offset < t.end || (offset == t.end && t.open))
+}
+
+func (t *token) isPunctuation(text string) bool {
+	return t.kind == tokenPunctuation && t.text == text
+}
+
+// isKeyword returns true if the token is the unquoted keyword.
+func (t *token) isKeyword(keywords ...string) bool {
+	if t.kind != tokenIdentifier || t.quoted {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(t.text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize splits the statement into the tokens including the comments without parsing. It never fails, the
+// unterminated strings, quoted identifiers and comments extend to the end of the statement, which is common while the
+// user is typing.
+func tokenize(engine parser.EngineType, statement string) []*token {
+	runes := []rune(statement)
+	mysqlDialect := isMySQLDialect(engine)
+	var tokens []*token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#' && mysqlDialect:
+			start := i
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			tokens = append(tokens, &token{kind: tokenComment, open: true, start: start, end: i})
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			start := i
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			open := i >= len(runes)
+			if !open {
+				i += 2
+			}
+			tokens = append(tokens, &token{kind: tokenComment, open: open, start: start, end: i})
+		case r == '\'' || (r == '"' && mysqlDialect):
+			start := i
+			var closed bool
+			i, closed = scanQuoted(runes, i, mysqlDialect)
+			tokens = append(tokens, &token{kind: tokenString, text: string(runes[start:i]), open: !closed, start: start, end: i})
+		case r == '"' || (r == '`' && mysqlDialect):
+			start := i
+			var closed bool
+			i, closed = scanQuoted(runes, i, false)
+			text := string(runes[start+1 : i])
+			if closed {
+				text = string(runes[start+1 : i-1])
+			}
+			text = strings.ReplaceAll(text, string([]rune{r, r}), string(r))
+			tokens = append(tokens, &token{kind: tokenIdentifier, text: text, quoted: true, open: !closed, start: start, end: i})
+		case isIdentifierRune(r) && !unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && isIdentifierRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, &token{kind: tokenIdentifier, text: string(runes[start:i]), start: start, end: i})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || unicode.IsLetter(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, &token{kind: tokenNumber, text: string(runes[start:i]), start: start, end: i})
+		default:
+			tokens = append(tokens, &token{kind: tokenPunctuation, text: string(r), start: i, end: i + 1})
+			i++
+		}
+	}
+	return tokens
+}
+
+// scanQuoted returns the offset after the closing quote of the quoted text starting at the offset, the doubled quotes
+// are the escaped quotes. It returns the length of the runes if the quote isn't closed.
+func scanQuoted(runes []rune, start int, backslashEscape bool) (int, bool) {
+	quote := runes[start]
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case backslashEscape && runes[i] == '\\':
+			i++
+		case runes[i] == quote:
+			if i+1 < len(runes) && runes[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, true
+		}
+	}
+	return len(runes), false
+}
+
+func isIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$'
+}
+
+func isMySQLDialect(engine parser.EngineType) bool {
+	switch engine {
+	case parser.MySQL, parser.TiDB, parser.MariaDB, parser.OceanBase:
+		return true
+	default:
+		return false
+	}
+}
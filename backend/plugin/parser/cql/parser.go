@@ -0,0 +1,242 @@
+// Package cql splits and tokenizes the Cassandra Query Language (CQL) statements.
+package cql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TokenKind is the kind of a token.
+type TokenKind int
+
+const (
+	// Word is the keyword, the unquoted identifier or the number, e.g. SELECT, users and 86400.
+	Word TokenKind = iota
+	// QuotedIdentifier is the double-quoted identifier, e.g. "userId".
+	QuotedIdentifier
+	// String is the string literal quoted by single quotes or $$.
+	String
+	// Symbol is the single punctuation character, e.g. "(" and "=".
+	Symbol
+)
+
+// Token is a CQL token, the comments and the whitespaces are dropped.
+type Token struct {
+	Kind TokenKind
+	Text string
+	// Line is the 1-based line of the token in the whole statement.
+	Line int
+}
+
+// Statement is a single CQL statement.
+type Statement struct {
+	// Text is the statement text without the trailing semicolon.
+	Text string
+	// Line is the 1-based line of the first token.
+	Line   int
+	Tokens []Token
+}
+
+// SyntaxError is the error for the unterminated strings, identifiers and comments.
+type SyntaxError struct {
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// SplitStatements splits the statement into the statements separated by semicolons.
+// The statements between BEGIN BATCH and APPLY BATCH are kept as a single batch statement.
+func SplitStatements(statement string) ([]*Statement, error) {
+	var result []*Statement
+	var current *Statement
+	start := 0
+	line := 1
+
+	addToken := func(kind TokenKind, text string, pos, tokenLine int) {
+		if current == nil {
+			current = &Statement{Line: tokenLine}
+			start = pos
+		}
+		current.Tokens = append(current.Tokens, Token{Kind: kind, Text: text, Line: tokenLine})
+	}
+	finish := func(end int) {
+		if current == nil {
+			return
+		}
+		current.Text = strings.TrimSpace(statement[start:end])
+		result = append(result, current)
+		current = nil
+	}
+
+	runes := []rune(statement)
+	// offsets maps the rune index to the byte offset, so that we can slice the statement.
+	offsets := make([]int, len(runes)+1)
+	offset := 0
+	for i, r := range runes {
+		offsets[i] = offset
+		offset += len(string(r))
+	}
+	offsets[len(runes)] = offset
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			beginLine := line
+			i += 2
+			for ; i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/'); i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+			}
+			if i >= len(runes) {
+				return nil, &SyntaxError{Line: beginLine, Message: "unterminated comment"}
+			}
+			i += 2
+		case r == '\'' || r == '"':
+			beginLine, begin := line, i
+			i++
+			for {
+				if i >= len(runes) {
+					if r == '\'' {
+						return nil, &SyntaxError{Line: beginLine, Message: "unterminated string"}
+					}
+					return nil, &SyntaxError{Line: beginLine, Message: "unterminated quoted identifier"}
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				if runes[i] == r {
+					// The quote is escaped by doubling it.
+					if i+1 < len(runes) && runes[i+1] == r {
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				i++
+			}
+			kind := String
+			if r == '"' {
+				kind = QuotedIdentifier
+			}
+			addToken(kind, string(runes[begin:i]), offsets[begin], beginLine)
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '$':
+			beginLine, begin := line, i
+			i += 2
+			for ; i < len(runes) && !(runes[i] == '$' && i+1 < len(runes) && runes[i+1] == '$'); i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+			}
+			if i >= len(runes) {
+				return nil, &SyntaxError{Line: beginLine, Message: "unterminated string"}
+			}
+			i += 2
+			addToken(String, string(runes[begin:i]), offsets[begin], beginLine)
+		case isWordRune(r):
+			begin := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			addToken(Word, string(runes[begin:i]), offsets[begin], line)
+		case r == ';':
+			if current != nil && current.isBatch() && !current.isBatchApplied() {
+				addToken(Symbol, ";", offsets[i], line)
+				i++
+				continue
+			}
+			finish(offsets[i])
+			i++
+		default:
+			addToken(Symbol, string(r), offsets[i], line)
+			i++
+		}
+	}
+	finish(len(statement))
+	return result, nil
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Keyword returns the upper-case word at the position, empty if the token is not a word.
+func (s *Statement) Keyword(i int) string {
+	if i < 0 || i >= len(s.Tokens) || s.Tokens[i].Kind != Word {
+		return ""
+	}
+	return strings.ToUpper(s.Tokens[i].Text)
+}
+
+// Kind returns the statement kind from the leading keywords, e.g. "SELECT", "CREATE TABLE" and "ALTER KEYSPACE".
+func (s *Statement) Kind() string {
+	first := s.Keyword(0)
+	switch first {
+	case "CREATE", "ALTER", "DROP":
+		i := 1
+		// Skip the modifiers, e.g. CREATE OR REPLACE FUNCTION and CREATE CUSTOM INDEX.
+		for s.Keyword(i) == "OR" || s.Keyword(i) == "REPLACE" || s.Keyword(i) == "CUSTOM" {
+			i++
+		}
+		object := s.Keyword(i)
+		if object == "MATERIALIZED" && s.Keyword(i+1) == "VIEW" {
+			object = "MATERIALIZED VIEW"
+		}
+		if object == "" {
+			return first
+		}
+		return first + " " + object
+	case "BEGIN":
+		return "BATCH"
+	}
+	return first
+}
+
+// FindKeywords returns the index of the first occurrence of the consecutive keywords, e.g. "ALLOW", "FILTERING", returns -1 if not found.
+func (s *Statement) FindKeywords(keywords ...string) int {
+	if len(keywords) == 0 {
+		return -1
+	}
+	for i := 0; i+len(keywords) <= len(s.Tokens); i++ {
+		matched := true
+		for j, keyword := range keywords {
+			if s.Keyword(i+j) != keyword {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i
+		}
+	}
+	return -1
+}
+
+// isBatch returns true for BEGIN [UNLOGGED | COUNTER] BATCH.
+func (s *Statement) isBatch() bool {
+	if s.Keyword(0) != "BEGIN" {
+		return false
+	}
+	return s.Keyword(1) == "BATCH" || s.Keyword(2) == "BATCH"
+}
+
+// isBatchApplied returns true if the statement ends with APPLY BATCH.
+func (s *Statement) isBatchApplied() bool {
+	n := len(s.Tokens)
+	return n >= 3 && s.Keyword(n-2) == "APPLY" && s.Keyword(n-1) == "BATCH"
+}
@@ -0,0 +1,79 @@
+package cql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		statement string
+		textList  []string
+		kindList  []string
+		lineList  []int
+		wantErr   bool
+	}{
+		{
+			statement: `-- Create the table.
+CREATE TABLE IF NOT EXISTS users (id uuid PRIMARY KEY, name text)
+  WITH default_time_to_live = 86400
+  AND compaction = {'class': 'LeveledCompactionStrategy'};
+
+SELECT * FROM users WHERE name = 'it''s; fine' ALLOW FILTERING;
+/* multi-line
+   comment */ ALTER MATERIALIZED VIEW users_by_name WITH comment = $$a;b$$`,
+			textList: []string{
+				"CREATE TABLE IF NOT EXISTS users (id uuid PRIMARY KEY, name text)\n  WITH default_time_to_live = 86400\n  AND compaction = {'class': 'LeveledCompactionStrategy'}",
+				"SELECT * FROM users WHERE name = 'it''s; fine' ALLOW FILTERING",
+				"ALTER MATERIALIZED VIEW users_by_name WITH comment = $$a;b$$",
+			},
+			kindList: []string{"CREATE TABLE", "SELECT", "ALTER MATERIALIZED VIEW"},
+			lineList: []int{2, 6, 8},
+		},
+		{
+			statement: `BEGIN UNLOGGED BATCH
+  INSERT INTO users (id, name) VALUES (uuid(), 'a');
+  UPDATE users SET name = 'b' WHERE id = 5b6962dd-3f90-4c93-8f61-eabfa4a803e2;
+APPLY BATCH;
+DROP TABLE users;`,
+			textList: []string{
+				"BEGIN UNLOGGED BATCH\n  INSERT INTO users (id, name) VALUES (uuid(), 'a');\n  UPDATE users SET name = 'b' WHERE id = 5b6962dd-3f90-4c93-8f61-eabfa4a803e2;\nAPPLY BATCH",
+				"DROP TABLE users",
+			},
+			kindList: []string{"BATCH", "DROP TABLE"},
+			lineList: []int{1, 5},
+		},
+		{
+			statement: "SELECT * FROM users WHERE name = 'unterminated",
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		statementList, err := SplitStatements(test.statement)
+		if test.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		var textList, kindList []string
+		var lineList []int
+		for _, statement := range statementList {
+			textList = append(textList, statement.Text)
+			kindList = append(kindList, statement.Kind())
+			lineList = append(lineList, statement.Line)
+		}
+		require.Equal(t, test.textList, textList)
+		require.Equal(t, test.kindList, kindList)
+		require.Equal(t, test.lineList, lineList)
+	}
+}
+
+func TestFindKeywords(t *testing.T) {
+	statementList, err := SplitStatements(`SELECT * FROM users WHERE note = 'ALLOW FILTERING'; SELECT * FROM users WHERE age > 18 allow filtering;`)
+	require.NoError(t, err)
+	require.Len(t, statementList, 2)
+	require.Equal(t, -1, statementList[0].FindKeywords("ALLOW", "FILTERING"))
+	require.Equal(t, 8, statementList[1].FindKeywords("ALLOW", "FILTERING"))
+}
@@ -0,0 +1,293 @@
+// Package cypher splits and tokenizes the Neo4j Cypher statements.
+package cypher
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TokenKind is the kind of a token.
+type TokenKind int
+
+const (
+	// Word is the keyword, the unquoted identifier or the number, e.g. MATCH, Person and 42.
+	Word TokenKind = iota
+	// QuotedIdentifier is the identifier quoted by backticks, e.g. `Person Name`.
+	QuotedIdentifier
+	// String is the string literal quoted by single or double quotes.
+	String
+	// Symbol is the single punctuation character, e.g. "(" and ":".
+	Symbol
+)
+
+// Token is a Cypher token, the comments and the whitespaces are dropped.
+type Token struct {
+	Kind TokenKind
+	Text string
+	// Line is the 1-based line of the token in the whole statement.
+	Line int
+	// Offset is the byte offset of the token in the statement text.
+	Offset int
+}
+
+// Statement is a single Cypher statement.
+type Statement struct {
+	// Text is the statement text without the trailing semicolon.
+	Text string
+	// Line is the 1-based line of the first token.
+	Line   int
+	Tokens []Token
+}
+
+// SyntaxError is the error for the unterminated strings, identifiers and comments.
+type SyntaxError struct {
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// SplitStatements splits the statement into the statements separated by semicolons.
+func SplitStatements(statement string) ([]*Statement, error) {
+	var result []*Statement
+	var current *Statement
+	start := 0
+	line := 1
+
+	addToken := func(kind TokenKind, text string, pos, tokenLine int) {
+		if current == nil {
+			current = &Statement{Line: tokenLine}
+			start = pos
+		}
+		current.Tokens = append(current.Tokens, Token{Kind: kind, Text: text, Line: tokenLine, Offset: pos - start})
+	}
+	finish := func(end int) {
+		if current == nil {
+			return
+		}
+		current.Text = strings.TrimSpace(statement[start:end])
+		result = append(result, current)
+		current = nil
+	}
+
+	runes := []rune(statement)
+	// offsets maps the rune index to the byte offset, so that we can slice the statement.
+	offsets := make([]int, len(runes)+1)
+	offset := 0
+	for i, r := range runes {
+		offsets[i] = offset
+		offset += len(string(r))
+	}
+	offsets[len(runes)] = offset
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			beginLine := line
+			i += 2
+			for ; i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/'); i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+			}
+			if i >= len(runes) {
+				return nil, &SyntaxError{Line: beginLine, Message: "unterminated comment"}
+			}
+			i += 2
+		case r == '\'' || r == '"':
+			beginLine, begin := line, i
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, &SyntaxError{Line: beginLine, Message: "unterminated string"}
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				// The strings use the backslash escapes, e.g. 'It\'s'.
+				if runes[i] == '\\' && i+1 < len(runes) {
+					if runes[i+1] == '\n' {
+						line++
+					}
+					i += 2
+					continue
+				}
+				if runes[i] == r {
+					i++
+					break
+				}
+				i++
+			}
+			addToken(String, string(runes[begin:i]), offsets[begin], beginLine)
+		case r == '`':
+			beginLine, begin := line, i
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, &SyntaxError{Line: beginLine, Message: "unterminated quoted identifier"}
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				if runes[i] == '`' {
+					// The backtick is escaped by doubling it.
+					if i+1 < len(runes) && runes[i+1] == '`' {
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				i++
+			}
+			addToken(QuotedIdentifier, string(runes[begin:i]), offsets[begin], beginLine)
+		case isWordRune(r):
+			begin := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			addToken(Word, string(runes[begin:i]), offsets[begin], line)
+		case r == ';':
+			finish(offsets[i])
+			i++
+		default:
+			addToken(Symbol, string(r), offsets[i], line)
+			i++
+		}
+	}
+	finish(len(statement))
+	return result, nil
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Keyword returns the upper-case word at the position, empty if the token is not a word.
+func (s *Statement) Keyword(i int) string {
+	if i < 0 || i >= len(s.Tokens) || s.Tokens[i].Kind != Word {
+		return ""
+	}
+	return strings.ToUpper(s.Tokens[i].Text)
+}
+
+// Kind returns the statement kind, e.g. "MATCH", "CREATE INDEX" and "DROP CONSTRAINT".
+// The index types such as RANGE, TEXT and FULLTEXT are folded into "CREATE INDEX".
+func (s *Statement) Kind() string {
+	first := s.Keyword(0)
+	if first != "CREATE" && first != "DROP" {
+		return first
+	}
+	if i := s.objectKeywordIndex(); i >= 0 {
+		return first + " " + s.Keyword(i)
+	}
+	// CREATE is also the clause creating the nodes and relationships.
+	return first
+}
+
+// IsSchemaCommand returns true for the statements changing the constraints and the indexes.
+func (s *Statement) IsSchemaCommand() bool {
+	switch s.Kind() {
+	case "CREATE INDEX", "DROP INDEX", "CREATE CONSTRAINT", "DROP CONSTRAINT":
+		return true
+	}
+	return false
+}
+
+// ObjectName returns the name of the index or the constraint, the quotes are kept.
+// It returns empty for the unnamed ones, e.g. CREATE INDEX FOR (n:Person) ON (n.name).
+func (s *Statement) ObjectName() string {
+	i := s.objectKeywordIndex()
+	if i < 0 || i+1 >= len(s.Tokens) {
+		return ""
+	}
+	token := s.Tokens[i+1]
+	switch {
+	case token.Kind == QuotedIdentifier:
+		return token.Text
+	case token.Kind == Word:
+		switch strings.ToUpper(token.Text) {
+		case "IF", "FOR", "ON":
+			return ""
+		}
+		return token.Text
+	case token.Kind == Symbol && token.Text == "$":
+		// The name could be a parameter, e.g. DROP INDEX $name.
+		if i+2 < len(s.Tokens) {
+			return "$" + s.Tokens[i+2].Text
+		}
+	}
+	return ""
+}
+
+// ObjectNameEnd returns the byte offset in the text right after the name of the index or the constraint,
+// or right after the INDEX and CONSTRAINT keyword for the unnamed ones. It returns -1 for the other statements.
+func (s *Statement) ObjectNameEnd() int {
+	i := s.objectKeywordIndex()
+	if i < 0 {
+		return -1
+	}
+	name := s.ObjectName()
+	if name != "" {
+		// The parameter name takes two tokens.
+		if strings.HasPrefix(name, "$") && s.Tokens[i+1].Kind == Symbol {
+			i++
+		}
+		i++
+	}
+	return s.Tokens[i].Offset + len(s.Tokens[i].Text)
+}
+
+// FindKeywords returns the index of the first occurrence of the consecutive keywords, e.g. "IF", "NOT", "EXISTS", returns -1 if not found.
+func (s *Statement) FindKeywords(keywords ...string) int {
+	if len(keywords) == 0 {
+		return -1
+	}
+	for i := 0; i+len(keywords) <= len(s.Tokens); i++ {
+		matched := true
+		for j, keyword := range keywords {
+			if s.Keyword(i+j) != keyword {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i
+		}
+	}
+	return -1
+}
+
+// objectKeywordIndex returns the index of the INDEX or CONSTRAINT keyword for the schema commands, returns -1 otherwise.
+func (s *Statement) objectKeywordIndex() int {
+	first := s.Keyword(0)
+	if first != "CREATE" && first != "DROP" {
+		return -1
+	}
+	i := 1
+	if s.Keyword(i) == "OR" && s.Keyword(i+1) == "REPLACE" {
+		i += 2
+	}
+	switch s.Keyword(i) {
+	case "RANGE", "TEXT", "POINT", "LOOKUP", "FULLTEXT", "BTREE", "VECTOR":
+		if s.Keyword(i+1) == "INDEX" {
+			return i + 1
+		}
+	case "INDEX", "CONSTRAINT":
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,109 @@
+package cypher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		statement string
+		textList  []string
+		kindList  []string
+		lineList  []int
+		wantErr   bool
+	}{
+		{
+			statement: `// Create the constraint.
+CREATE CONSTRAINT person_id IF NOT EXISTS
+  FOR (p:Person) REQUIRE p.id IS UNIQUE;
+
+MATCH (p:Person {name: 'It\'s; fine'}) RETURN p.` + "`full name`" + `;
+/* multi-line
+   comment */ CREATE FULLTEXT INDEX titles FOR (n:Movie) ON EACH [n.title];
+CREATE (p:Person {id: 1})`,
+			textList: []string{
+				"CREATE CONSTRAINT person_id IF NOT EXISTS\n  FOR (p:Person) REQUIRE p.id IS UNIQUE",
+				"MATCH (p:Person {name: 'It\\'s; fine'}) RETURN p.`full name`",
+				"CREATE FULLTEXT INDEX titles FOR (n:Movie) ON EACH [n.title]",
+				"CREATE (p:Person {id: 1})",
+			},
+			kindList: []string{"CREATE CONSTRAINT", "MATCH", "CREATE INDEX", "CREATE"},
+			lineList: []int{2, 5, 7, 8},
+		},
+		{
+			statement: "MATCH (p:Person {name: 'unterminated}) RETURN p",
+			wantErr:   true,
+		},
+		{
+			statement: "MATCH (p:`Person) RETURN p",
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		statementList, err := SplitStatements(test.statement)
+		if test.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		var textList, kindList []string
+		var lineList []int
+		for _, statement := range statementList {
+			textList = append(textList, statement.Text)
+			kindList = append(kindList, statement.Kind())
+			lineList = append(lineList, statement.Line)
+		}
+		require.Equal(t, test.textList, textList)
+		require.Equal(t, test.kindList, kindList)
+		require.Equal(t, test.lineList, lineList)
+	}
+}
+
+func TestObjectName(t *testing.T) {
+	tests := []struct {
+		statement string
+		name      string
+		nameEnd   string
+	}{
+		{
+			statement: "CREATE INDEX person_name FOR (p:Person) ON (p.name)",
+			name:      "person_name",
+			nameEnd:   "CREATE INDEX person_name",
+		},
+		{
+			statement: "CREATE INDEX FOR (p:Person) ON (p.name)",
+			name:      "",
+			nameEnd:   "CREATE INDEX",
+		},
+		{
+			statement: "DROP CONSTRAINT `person id`",
+			name:      "`person id`",
+			nameEnd:   "DROP CONSTRAINT `person id`",
+		},
+		{
+			statement: "DROP INDEX $name IF EXISTS",
+			name:      "$name",
+			nameEnd:   "DROP INDEX $name",
+		},
+		{
+			statement: "MATCH (p:Person) RETURN p",
+			name:      "",
+		},
+	}
+
+	for _, test := range tests {
+		statementList, err := SplitStatements(test.statement)
+		require.NoError(t, err)
+		require.Len(t, statementList, 1)
+		statement := statementList[0]
+		require.Equal(t, test.name, statement.ObjectName())
+		if test.nameEnd == "" {
+			require.Equal(t, -1, statement.ObjectNameEnd())
+			continue
+		}
+		require.Equal(t, test.nameEnd, statement.Text[:statement.ObjectNameEnd()])
+	}
+}
@@ -0,0 +1,174 @@
+// Package elasticsearch parses the Elasticsearch requests written in the Kibana Dev Tools console syntax.
+package elasticsearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// requestLineRegexp matches the request line like "GET /index/_search", the method must start at the beginning of the line.
+	requestLineRegexp = regexp.MustCompile(`^(?i)(GET|POST|PUT|DELETE|HEAD|PATCH)\s+(\S+)\s*$`)
+
+	// ndjsonEndpoints are the endpoints whose body is newline-delimited JSON.
+	ndjsonEndpoints = map[string]bool{
+		"_bulk":             true,
+		"_msearch":          true,
+		"_msearch/template": true,
+	}
+)
+
+// Request is an Elasticsearch REST request.
+type Request struct {
+	// Method is the upper-cased HTTP method.
+	Method string
+	// Path is the request path with the query string, which always starts with "/".
+	Path string
+	// Body is the JSON body of the request, or the newline-delimited JSON for the bulk requests.
+	Body string
+	// Line is the 1-based line number of the request line.
+	Line int
+}
+
+// SyntaxError is the error for the malformed requests.
+type SyntaxError struct {
+	// Line is the 1-based line of the request line, or the unexpected line outside the requests.
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ParseRequests parses the statement into requests, for example:
+//
+//	PUT /my-index
+//	{"mappings": {"properties": {"title": {"type": "text"}}}}
+//
+//	POST /my-index/_delete_by_query
+//	{"query": {"term": {"status": "expired"}}}
+//
+// The blank lines and the comment lines starting with "#" or "//" are ignored.
+func ParseRequests(statement string) ([]*Request, error) {
+	var requestList []*Request
+	var current *Request
+	var bodyLines []string
+
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		body := strings.TrimSpace(strings.Join(bodyLines, "\n"))
+		if body != "" {
+			if err := validateBody(current, body); err != nil {
+				return err
+			}
+		}
+		current.Body = body
+		requestList = append(requestList, current)
+		current, bodyLines = nil, nil
+		return nil
+	}
+
+	for i, line := range strings.Split(statement, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if matches := requestLineRegexp.FindStringSubmatch(line); matches != nil {
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			path := matches[2]
+			if !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+			current = &Request{
+				Method: strings.ToUpper(matches[1]),
+				Path:   path,
+				Line:   i + 1,
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		// The JSON strings cannot span lines, so the lines starting with "#" or "//" are always the comments.
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		if current == nil && trimmed == "" {
+			continue
+		}
+		if current == nil {
+			return nil, &SyntaxError{Line: i + 1, Message: fmt.Sprintf("expect the request line like \"GET /index/_search\", but got %q", trimmed)}
+		}
+		bodyLines = append(bodyLines, line)
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return requestList, nil
+}
+
+func validateBody(request *Request, body string) error {
+	if ndjsonEndpoints[request.Endpoint()] {
+		for _, line := range strings.Split(body, "\n") {
+			if line = strings.TrimSpace(line); line != "" && !json.Valid([]byte(line)) {
+				return &SyntaxError{Line: request.Line, Message: fmt.Sprintf("invalid newline-delimited JSON body of %s %s", request.Method, request.Path)}
+			}
+		}
+		return nil
+	}
+	if !json.Valid([]byte(body)) {
+		return &SyntaxError{Line: request.Line, Message: fmt.Sprintf("invalid JSON body of %s %s", request.Method, request.Path)}
+	}
+	return nil
+}
+
+// pathSegments returns the path segments without the query string.
+func (r *Request) pathSegments() []string {
+	path := r.Path
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// Index returns the target index, data stream or alias of the request, empty if the request targets the cluster.
+func (r *Request) Index() string {
+	segments := r.pathSegments()
+	if len(segments) == 0 || strings.HasPrefix(segments[0], "_") {
+		return ""
+	}
+	return segments[0]
+}
+
+// Endpoint returns the API endpoint of the request from the first segment starting with "_", e.g. "_delete_by_query" and "_msearch/template".
+func (r *Request) Endpoint() string {
+	segments := r.pathSegments()
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "_") {
+			return strings.Join(segments[i:], "/")
+		}
+	}
+	return ""
+}
+
+// IsReadOnly returns true if the request doesn't change the data, mappings or settings.
+func (r *Request) IsReadOnly() bool {
+	switch r.Method {
+	case "GET", "HEAD":
+		return true
+	case "POST":
+		switch strings.SplitN(r.Endpoint(), "/", 2)[0] {
+		case "_search", "_msearch", "_count", "_mget", "_validate", "_explain", "_field_caps", "_termvectors", "_mtermvectors", "_render", "_sql", "_eql":
+			return true
+		}
+	}
+	return false
+}
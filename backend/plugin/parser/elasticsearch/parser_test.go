@@ -0,0 +1,97 @@
+package elasticsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequests(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      []*Request
+		wantErr   bool
+	}{
+		{
+			statement: `# Create the index.
+PUT my-index
+{
+  "mappings": {
+    "properties": {
+      "title": {"type": "text"}
+    }
+  }
+}
+
+get /my-index/_search?size=1
+
+POST /_bulk
+{"index": {"_index": "my-index"}}
+{"title": "hello"}
+`,
+			want: []*Request{
+				{Method: "PUT", Path: "/my-index", Body: "{\n  \"mappings\": {\n    \"properties\": {\n      \"title\": {\"type\": \"text\"}\n    }\n  }\n}", Line: 2},
+				{Method: "GET", Path: "/my-index/_search?size=1", Line: 11},
+				{Method: "POST", Path: "/_bulk", Body: "{\"index\": {\"_index\": \"my-index\"}}\n{\"title\": \"hello\"}", Line: 13},
+			},
+		},
+		{
+			statement: `{"query": {"match_all": {}}}`,
+			wantErr:   true,
+		},
+		{
+			statement: "POST /my-index/_delete_by_query\n{\"query\": ",
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseRequests(test.statement)
+		if test.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.want, got)
+	}
+}
+
+func TestRequest(t *testing.T) {
+	tests := []struct {
+		request  *Request
+		index    string
+		endpoint string
+		readOnly bool
+	}{
+		{
+			request:  &Request{Method: "POST", Path: "/my-index/_search?size=1"},
+			index:    "my-index",
+			endpoint: "_search",
+			readOnly: true,
+		},
+		{
+			request:  &Request{Method: "POST", Path: "/my-index/_delete_by_query"},
+			index:    "my-index",
+			endpoint: "_delete_by_query",
+			readOnly: false,
+		},
+		{
+			request:  &Request{Method: "GET", Path: "/_cat/indices"},
+			index:    "",
+			endpoint: "_cat/indices",
+			readOnly: true,
+		},
+		{
+			request:  &Request{Method: "PUT", Path: "/my-index"},
+			index:    "my-index",
+			endpoint: "",
+			readOnly: false,
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.index, test.request.Index())
+		require.Equal(t, test.endpoint, test.request.Endpoint())
+		require.Equal(t, test.readOnly, test.request.IsReadOnly())
+	}
+}
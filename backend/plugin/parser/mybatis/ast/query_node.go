@@ -31,6 +31,9 @@ type QueryNode struct {
 	ID string
 	// Type is the type of the query node.
 	Type QueryNodeType
+	// Line is the 1-based line of the first SQL text of the query node in the mapper xml, 0 if the query node
+	// has no SQL text.
+	Line int
 	// Children is the children of the query node.
 	Children []Node
 }
@@ -13,10 +13,9 @@ import (
 
 // Parser is the mybatis mapper xml parser.
 type Parser struct {
-	d           *xml.Decoder
-	buf         []rune
-	cursor      uint
-	currentLine uint
+	d      *xml.Decoder
+	buf    []rune
+	cursor uint
 }
 
 // NewParser creates a new mybatis mapper xml parser.
@@ -40,6 +39,8 @@ func (p *Parser) Parse() (ast.Node, error) {
 	nodeStack := []ast.Node{root}
 
 	for {
+		// The position of the decoder is the end of the last token, which is also the start of the next token.
+		line, _ := p.d.InputPos()
 		token, err := p.d.Token()
 		if err != nil {
 			if err == io.EOF {
@@ -71,11 +72,6 @@ func (p *Parser) Parse() (ast.Node, error) {
 			}
 			nodeStack = nodeStack[:len(nodeStack)-1]
 		case xml.CharData:
-			for _, b := range ele {
-				if b == '\n' {
-					p.currentLine++
-				}
-			}
 			trimmed := strings.TrimSpace(string(ele))
 			if len(trimmed) == 0 {
 				continue
@@ -88,16 +84,25 @@ func (p *Parser) Parse() (ast.Node, error) {
 				return nil, errors.Errorf("try to append data node to parent node, but node stack is empty")
 			}
 			nodeStack[len(nodeStack)-1].AddChild(dataNode)
-		case xml.Comment:
-			for _, b := range ele {
-				if b == '\n' {
-					p.currentLine++
-				}
+			// The query node starts at the line of its first data node, skipping the leading blank lines.
+			if queryNode := innermostQueryNode(nodeStack); queryNode != nil && queryNode.Line == 0 {
+				prefix := string(ele)[:strings.Index(string(ele), trimmed)]
+				queryNode.Line = line + strings.Count(prefix, "\n")
 			}
 		}
 	}
 }
 
+// innermostQueryNode returns the innermost query node in the node stack, returns nil if not found.
+func innermostQueryNode(nodeStack []ast.Node) *ast.QueryNode {
+	for i := len(nodeStack) - 1; i >= 0; i-- {
+		if queryNode, ok := nodeStack[i].(*ast.QueryNode); ok {
+			return queryNode
+		}
+	}
+	return nil
+}
+
 // newNodeByStartElement returns the node related to the startElement, for example, returns QueryNode for
 // start element which name is "select", "update", "insert", "delete". If the startElement is unacceptable,
 // returns an emptyNode instead.
@@ -9,6 +9,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
+
+	"github.com/bytebase/bytebase/backend/plugin/parser/mybatis/ast"
 )
 
 // TestData is the test data for mybatis parser. It contains the xml and the expected sql.
@@ -67,3 +69,35 @@ func TestParser(t *testing.T) {
 		runTest(t, filepath, false)
 	}
 }
+
+func TestParserQueryNodeLine(t *testing.T) {
+	stmt := `<mapper namespace="com.bytebase.test">
+    <select id="selectUser" parameterType="int" resultType="hashmap">
+
+        select * from user where id = #{id}
+    </select>
+    <!-- Multi-line
+         comment -->
+    <update id="updateUser" parameterType="com.bytebase.test.User">
+        <if test="name != null">
+            update user set name = #{name} where id = ${id}
+        </if>
+    </update>
+    <delete id="deleteUser" parameterType="int"></delete>
+</mapper>`
+	node, err := NewParser(stmt).Parse()
+	require.NoError(t, err)
+	root, ok := node.(*ast.RootNode)
+	require.True(t, ok)
+	require.Len(t, root.Children, 1)
+	mapper, ok := root.Children[0].(*ast.MapperNode)
+	require.True(t, ok)
+
+	var lines []int
+	for _, child := range mapper.Children {
+		if queryNode, ok := child.(*ast.QueryNode); ok {
+			lines = append(lines, queryNode.Line)
+		}
+	}
+	require.Equal(t, []int{4, 10, 0}, lines)
+}
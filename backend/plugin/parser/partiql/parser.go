@@ -0,0 +1,189 @@
+// Package partiql splits and tokenizes the PartiQL statements for Amazon DynamoDB.
+package partiql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TokenKind is the kind of a token.
+type TokenKind int
+
+const (
+	// Word is the keyword, the unquoted identifier or the number, e.g. SELECT, Music and 10.
+	Word TokenKind = iota
+	// QuotedIdentifier is the double-quoted identifier, e.g. "Music".
+	QuotedIdentifier
+	// Literal is the string literal quoted by single quotes or the Ion literal quoted by backticks.
+	Literal
+	// Symbol is the single punctuation character, e.g. "." and "=".
+	Symbol
+)
+
+// Token is a PartiQL token, the comments and the whitespaces are dropped.
+type Token struct {
+	Kind TokenKind
+	Text string
+	// Line is the 1-based line of the token in the whole statement.
+	Line int
+}
+
+// Statement is a single PartiQL statement.
+type Statement struct {
+	// Text is the statement text without the trailing semicolon.
+	Text string
+	// Line is the 1-based line of the first token.
+	Line   int
+	Tokens []Token
+}
+
+// SyntaxError is the error for the unterminated literals, identifiers and comments.
+type SyntaxError struct {
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// SplitStatements splits the statement into the statements separated by semicolons.
+func SplitStatements(statement string) ([]*Statement, error) {
+	var result []*Statement
+	var current *Statement
+	start, line := 0, 1
+
+	addToken := func(kind TokenKind, text string, pos, tokenLine int) {
+		if current == nil {
+			current = &Statement{Line: tokenLine}
+			start = pos
+		}
+		current.Tokens = append(current.Tokens, Token{Kind: kind, Text: text, Line: tokenLine})
+	}
+	finish := func(end int) {
+		if current == nil {
+			return
+		}
+		current.Text = strings.TrimSpace(statement[start:end])
+		result = append(result, current)
+		current = nil
+	}
+
+	for i := 0; i < len(statement); {
+		c := statement[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(statement[i:], "--"):
+			for i < len(statement) && statement[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(statement[i:], "/*"):
+			end := strings.Index(statement[i+2:], "*/")
+			if end < 0 {
+				return nil, &SyntaxError{Line: line, Message: "unterminated comment"}
+			}
+			line += strings.Count(statement[i:i+2+end], "\n")
+			i += 2 + end + 2
+		case c == '\'' || c == '"' || c == '`':
+			beginLine, begin := line, i
+			i++
+			for {
+				if i >= len(statement) {
+					return nil, &SyntaxError{Line: beginLine, Message: fmt.Sprintf("unterminated %c", c)}
+				}
+				if statement[i] == '\n' {
+					line++
+				}
+				if statement[i] == c {
+					// The quote is escaped by doubling it.
+					if c != '`' && i+1 < len(statement) && statement[i+1] == c {
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				i++
+			}
+			kind := Literal
+			if c == '"' {
+				kind = QuotedIdentifier
+			}
+			addToken(kind, statement[begin:i], begin, beginLine)
+		case c == ';':
+			finish(i)
+			i++
+		case isWordByte(c):
+			begin := i
+			for i < len(statement) && isWordByte(statement[i]) {
+				i++
+			}
+			addToken(Word, statement[begin:i], begin, line)
+		default:
+			addToken(Symbol, string(c), i, line)
+			i++
+		}
+	}
+	finish(len(statement))
+	return result, nil
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// Keyword returns the upper-case word at the position, empty if the token is not a word.
+func (s *Statement) Keyword(i int) string {
+	if i < 0 || i >= len(s.Tokens) || s.Tokens[i].Kind != Word {
+		return ""
+	}
+	return strings.ToUpper(s.Tokens[i].Text)
+}
+
+// Kind returns the first keyword of the statement, e.g. SELECT, INSERT, UPDATE and DELETE.
+func (s *Statement) Kind() string {
+	return s.Keyword(0)
+}
+
+// FindKeyword returns the index of the first occurrence of the keyword, returns -1 if not found.
+func (s *Statement) FindKeyword(keyword string) int {
+	for i := range s.Tokens {
+		if s.Keyword(i) == keyword {
+			return i
+		}
+	}
+	return -1
+}
+
+// Table returns the target table, e.g. "Music" for SELECT * FROM "Music"."GenreIndex", empty if not found.
+func (s *Statement) Table() string {
+	i := -1
+	switch s.Kind() {
+	case "SELECT", "DELETE":
+		if from := s.FindKeyword("FROM"); from >= 0 {
+			i = from + 1
+		}
+	case "INSERT":
+		if into := s.FindKeyword("INTO"); into >= 0 {
+			i = into + 1
+		}
+	case "UPDATE":
+		i = 1
+	}
+	if i < 0 || i >= len(s.Tokens) {
+		return ""
+	}
+	token := s.Tokens[i]
+	switch token.Kind {
+	case Word:
+		return token.Text
+	case QuotedIdentifier:
+		return strings.ReplaceAll(token.Text[1:len(token.Text)-1], `""`, `"`)
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package partiql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		statement string
+		textList  []string
+		kindList  []string
+		tableList []string
+		lineList  []int
+		wantErr   bool
+	}{
+		{
+			statement: `-- Find the songs.
+SELECT * FROM "Music"."GenreIndex" WHERE Genre = 'Rock; Roll';
+INSERT INTO Music VALUE {'Artist': 'Acme Band', 'SongTitle': 'PartiQL Rocks'};
+/* Update
+   the awards. */ UPDATE "Music" SET AwardsWon = 1 WHERE Artist = 'Acme Band'`,
+			textList: []string{
+				`SELECT * FROM "Music"."GenreIndex" WHERE Genre = 'Rock; Roll'`,
+				`INSERT INTO Music VALUE {'Artist': 'Acme Band', 'SongTitle': 'PartiQL Rocks'}`,
+				`UPDATE "Music" SET AwardsWon = 1 WHERE Artist = 'Acme Band'`,
+			},
+			kindList:  []string{"SELECT", "INSERT", "UPDATE"},
+			tableList: []string{"Music", "Music", "Music"},
+			lineList:  []int{2, 3, 5},
+		},
+		{
+			statement: `SELECT * FROM Music WHERE Artist = 'unterminated`,
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		statementList, err := SplitStatements(test.statement)
+		if test.wantErr {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		var textList, kindList, tableList []string
+		var lineList []int
+		for _, statement := range statementList {
+			textList = append(textList, statement.Text)
+			kindList = append(kindList, statement.Kind())
+			tableList = append(tableList, statement.Table())
+			lineList = append(lineList, statement.Line)
+		}
+		require.Equal(t, test.textList, textList)
+		require.Equal(t, test.kindList, kindList)
+		require.Equal(t, test.tableList, tableList)
+		require.Equal(t, test.lineList, lineList)
+	}
+}
@@ -4,5 +4,6 @@ package ast
 type CreateFunctionStmt struct {
 	ddl
 
-	Function *FunctionDef
+	Function  *FunctionDef
+	OrReplace bool
 }
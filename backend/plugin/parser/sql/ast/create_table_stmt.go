@@ -9,6 +9,5 @@ type CreateTableStmt struct {
 	ColumnList     []*ColumnDef
 	ConstraintList []*ConstraintDef
 
-	// TODO(rebelice): convert the partition definition.
-	PartitionDef Node
+	PartitionDef *PartitionDef
 }
@@ -0,0 +1,12 @@
+package ast
+
+// CreateViewStmt is the struct for create view and create materialized view statements.
+type CreateViewStmt struct {
+	ddl
+
+	// Name.Type is TableTypeView or TableTypeMaterializedView.
+	Name    *TableDef
+	Replace bool
+	// IfNotExists is only for the materialized views.
+	IfNotExists bool
+}
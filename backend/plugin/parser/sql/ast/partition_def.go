@@ -0,0 +1,22 @@
+package ast
+
+// PartitionStrategy is the strategy to partition the table.
+type PartitionStrategy string
+
+const (
+	// PartitionStrategyRange is the strategy partitioning the table by ranges of the partition key.
+	PartitionStrategyRange PartitionStrategy = "RANGE"
+	// PartitionStrategyList is the strategy partitioning the table by lists of the partition key values.
+	PartitionStrategyList PartitionStrategy = "LIST"
+	// PartitionStrategyHash is the strategy partitioning the table by the modulus of the partition key hash.
+	PartitionStrategyHash PartitionStrategy = "HASH"
+)
+
+// PartitionDef is the struct for the partition definition.
+type PartitionDef struct {
+	node
+
+	Strategy PartitionStrategy
+	// KeyList is the partition key column list, the expression keys are empty strings.
+	KeyList []string
+}
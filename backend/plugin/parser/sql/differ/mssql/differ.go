@@ -0,0 +1,26 @@
+// Package mssql provides the MSSQL differ plugin.
+package mssql
+
+import (
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/differ"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/sdl"
+)
+
+var (
+	_ differ.SchemaDiffer = (*SchemaDiffer)(nil)
+)
+
+func init() {
+	differ.Register(parser.MSSQL, &SchemaDiffer{})
+}
+
+// SchemaDiffer it the differ for MSSQL dialect.
+type SchemaDiffer struct {
+}
+
+// SchemaDiff returns the schema diff.
+func (*SchemaDiffer) SchemaDiff(oldStmt, newStmt string) (string, error) {
+	return sdl.SchemaDiff(parser.MSSQL, oldStmt, newStmt)
+}
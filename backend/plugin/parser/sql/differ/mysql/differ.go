@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"sort"
-	"strings"
 
 	"go.uber.org/zap"
 
@@ -72,7 +71,7 @@ type diffNode struct {
 }
 
 func (diff *diffNode) diffUnsupportedStatement(oldUnsupportedStmtList, newUnsupportedStmtList []string) error {
-	// We compare the CREATE TRIGGER/EVENT/FUNCTION/PROCEDURE statements based on strcmp.
+	// We compare the CREATE TRIGGER/EVENT/FUNCTION/PROCEDURE statements based on the normalized text.
 	oldUnsupportMap, err := buildUnsupportObjectMap(oldUnsupportedStmtList)
 	if err != nil {
 		return err
@@ -84,7 +83,7 @@ func (diff *diffNode) diffUnsupportedStatement(oldUnsupportedStmtList, newUnsupp
 	for tp, objs := range newUnsupportMap {
 		for newName, newStmt := range objs {
 			if oldStmt, ok := oldUnsupportMap[tp][newName]; ok {
-				if strings.Compare(oldStmt, newStmt) != 0 {
+				if normalizeUnsupportStmt(oldStmt) != normalizeUnsupportStmt(newStmt) {
 					// We should drop the old function and create the new function.
 					// https://dev.mysql.com/doc/refman/8.0/en/drop-procedure.html
 					// https://dev.mysql.com/doc/refman/5.7/en/drop-procedure.html
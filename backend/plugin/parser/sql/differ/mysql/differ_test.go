@@ -84,6 +84,31 @@ func TestExtractUnsupportObjNameAndType(t *testing.T) {
 	}
 }
 
+func TestNormalizeUnsupportStmt(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want string
+	}{
+		{
+			stmt: "CREATE DEFINER=`root`@`%` TRIGGER `ins_sum`\n\tBEFORE INSERT ON `account` FOR EACH ROW SET @sum = @sum + NEW.price ;;",
+			want: "CREATE DEFINER=`root`@`%` TRIGGER `ins_sum` BEFORE INSERT ON `account` FOR EACH ROW SET @sum = @sum + NEW.price",
+		},
+		{
+			stmt: "/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`%`*/ /*!50003 TRIGGER `ins_sum` BEFORE INSERT ON `account` FOR EACH ROW SET @sum = @sum + NEW.price */;;",
+			want: "CREATE DEFINER=`root`@`%` TRIGGER `ins_sum` BEFORE INSERT ON `account` FOR EACH ROW SET @sum = @sum + NEW.price",
+		},
+		{
+			stmt: "CREATE FUNCTION `hello` (s CHAR(20)) RETURNS CHAR(50)\nRETURN CONCAT('Hello,  \\'',s,'!');",
+			want: "CREATE FUNCTION `hello` (s CHAR(20)) RETURNS CHAR(50) RETURN CONCAT('Hello,  \\'',s,'!')",
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, normalizeUnsupportStmt(test.stmt))
+	}
+}
+
 type testCase struct {
 	old  string
 	new  string
@@ -66,3 +66,43 @@ func toString(node ast.Node) (string, error) {
 	}
 	return buf.String(), nil
 }
+
+// versionCommentRegexp matches the MySQL version-specific comments such as "/*!50003 CREATE*/" produced by mysqldump.
+var versionCommentRegexp = regexp.MustCompile(`(?s)/\*!\d*\s?(.*?)\*/`)
+
+// normalizeUnsupportStmt normalizes the CREATE TRIGGER/EVENT/FUNCTION/PROCEDURE statement for comparison.
+// It unwraps the version-specific comments, collapses the whitespaces outside the quoted strings and
+// identifiers, and trims the trailing delimiters, so that the statements differ only in formatting are equal.
+func normalizeUnsupportStmt(stmt string) string {
+	stmt = versionCommentRegexp.ReplaceAllString(stmt, "$1")
+	var buf strings.Builder
+	var quote rune
+	space, escape := false, false
+	for _, c := range strings.TrimSpace(stmt) {
+		if quote != 0 {
+			buf.WriteRune(c)
+			switch {
+			case escape:
+				escape = false
+			case c == '\\':
+				escape = true
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			space = true
+			continue
+		case '\'', '"', '`':
+			quote = c
+		}
+		if space {
+			buf.WriteRune(' ')
+			space = false
+		}
+		buf.WriteRune(c)
+	}
+	return strings.TrimRight(buf.String(), "; ")
+}
@@ -34,6 +34,30 @@ func TestFunction(t *testing.T) {
 				"CREATE DEFINER=`root`@`%` FUNCTION `AddOne`(v INT) RETURNS int\n" +
 				"BEGIN   DECLARE a INT;   SET a = v;   SET a = a * 1 + 1;   RETURN a; END ;;\n\n",
 		},
+		{
+			old: "DELIMITER ;;\n" +
+				"CREATE DEFINER=`root`@`%` FUNCTION `AddOne`(v INT) RETURNS int\n" +
+				"BEGIN   DECLARE a INT;   SET a = v;   SET a = a + 1;   RETURN a; END ;;\n" +
+				"DELIMITER ;\n",
+			new: "DELIMITER ;;\n" +
+				"CREATE DEFINER=`root`@`%` FUNCTION `AddOne`(v INT) RETURNS int\n" +
+				"BEGIN\n  DECLARE a INT;\n  SET a = v;\n  SET a = a + 1;\n  RETURN a;\nEND;;\n" +
+				"DELIMITER ;\n",
+			want: "",
+		},
+		{
+			old: "DELIMITER ;;\n" +
+				"CREATE DEFINER=`root`@`%` FUNCTION `Hello`(s CHAR(20)) RETURNS char(50)\n" +
+				"RETURN CONCAT('Hello,  ', s) ;;\n" +
+				"DELIMITER ;\n",
+			new: "DELIMITER ;;\n" +
+				"CREATE DEFINER=`root`@`%` FUNCTION `Hello`(s CHAR(20)) RETURNS char(50)\n" +
+				"RETURN CONCAT('Hello, ', s) ;;\n" +
+				"DELIMITER ;\n",
+			want: "DROP FUNCTION IF EXISTS `Hello`;\n\n" +
+				"CREATE DEFINER=`root`@`%` FUNCTION `Hello`(s CHAR(20)) RETURNS char(50)\n" +
+				"RETURN CONCAT('Hello, ', s) ;;\n\n",
+		},
 	}
 	testDiffWithoutDisableForeignKeyCheck(t, tests)
 }
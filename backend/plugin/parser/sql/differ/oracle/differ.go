@@ -0,0 +1,26 @@
+// Package oracle provides the Oracle differ plugin.
+package oracle
+
+import (
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/differ"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/sdl"
+)
+
+var (
+	_ differ.SchemaDiffer = (*SchemaDiffer)(nil)
+)
+
+func init() {
+	differ.Register(parser.Oracle, &SchemaDiffer{})
+}
+
+// SchemaDiffer it the differ for Oracle dialect.
+type SchemaDiffer struct {
+}
+
+// SchemaDiff returns the schema diff.
+func (*SchemaDiffer) SchemaDiff(oldStmt, newStmt string) (string, error) {
+	return sdl.SchemaDiff(parser.Oracle, oldStmt, newStmt)
+}
@@ -0,0 +1,76 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaDiff(t *testing.T) {
+	tests := []struct {
+		old  string
+		new  string
+		want string
+	}{
+		{
+			old: "CREATE TABLE t (a NUMBER);\n" +
+				"CREATE VIEW v1 AS SELECT a FROM t;\n" +
+				"CREATE SEQUENCE s1 START WITH 1 INCREMENT BY 1;\n",
+			new: "CREATE TABLE t (a NUMBER, b NUMBER);\n" +
+				"create view V1 as\n  select a\n  from t;\n" +
+				"CREATE SEQUENCE s1 START WITH 100 INCREMENT BY 1;\n",
+			want: "ALTER TABLE T ADD (b NUMBER);\n\n",
+		},
+		{
+			old: "CREATE TABLE t (a NUMBER);\n" +
+				"CREATE VIEW v1 AS SELECT a FROM t;\n" +
+				"CREATE MATERIALIZED VIEW mv1 AS SELECT a FROM t;\n" +
+				"CREATE SEQUENCE s1 START WITH 1 INCREMENT BY 1;\n" +
+				"CREATE SEQUENCE s2;\n",
+			new: "CREATE TABLE t (a NUMBER);\n" +
+				"CREATE VIEW v1 AS SELECT a, 'x' AS b FROM t;\n" +
+				"CREATE MATERIALIZED VIEW mv1 AS SELECT a FROM t WHERE a > 0;\n" +
+				"CREATE VIEW v2 AS SELECT a FROM v1;\n" +
+				"CREATE SEQUENCE s1 START WITH 1 INCREMENT BY 10 CACHE 20;\n",
+			want: "DROP MATERIALIZED VIEW MV1;\n\n" +
+				"DROP SEQUENCE S2;\n\n" +
+				"ALTER SEQUENCE S1 INCREMENT BY 10 CACHE 20;\n\n" +
+				"CREATE OR REPLACE VIEW v1 AS SELECT a, 'x' AS b FROM t;\n\n" +
+				"CREATE MATERIALIZED VIEW mv1 AS SELECT a FROM t WHERE a > 0;\n\n" +
+				"CREATE VIEW v2 AS SELECT a FROM v1;\n\n",
+		},
+		{
+			old: "CREATE OR REPLACE EDITIONABLE FUNCTION add_one (v NUMBER) RETURN NUMBER IS\n" +
+				"BEGIN\n  RETURN v + 1;\nEND;\n/\n" +
+				"CREATE OR REPLACE PROCEDURE p1 IS\nBEGIN\n  NULL;\nEND;\n/\n" +
+				"CREATE OR REPLACE TRIGGER trg1 BEFORE INSERT ON t FOR EACH ROW\n" +
+				"BEGIN\n  :NEW.a := add_one(:NEW.a);\nEND;\n/\n",
+			new: "-- The function adding one.\n" +
+				"CREATE FUNCTION add_one (v NUMBER) RETURN NUMBER IS\n" +
+				"BEGIN\n    RETURN v + 1; -- Add one.\nEND;\n/\n" +
+				"CREATE OR REPLACE TRIGGER trg1 BEFORE INSERT ON t FOR EACH ROW\n" +
+				"BEGIN\n  :NEW.a := add_one(:NEW.a) + 1;\nEND;\n/\n",
+			want: "DROP PROCEDURE P1;\n\n" +
+				"CREATE OR REPLACE TRIGGER trg1 BEFORE INSERT ON t FOR EACH ROW\n" +
+				"BEGIN\n  :NEW.a := add_one(:NEW.a) + 1;\nEND;\n/\n\n",
+		},
+		{
+			old: "CREATE TABLE t (a NUMBER);\n",
+			new: "CREATE TABLE t (a NUMBER);\n" +
+				"CREATE SEQUENCE s1;\n" +
+				"CREATE FUNCTION f1 RETURN NUMBER IS\nBEGIN\n  RETURN s1.NEXTVAL;\nEND;\n/\n" +
+				"CREATE VIEW v1 AS SELECT f1() AS a FROM dual;\n",
+			want: "CREATE SEQUENCE s1;\n\n" +
+				"CREATE FUNCTION f1 RETURN NUMBER IS\nBEGIN\n  RETURN s1.NEXTVAL;\nEND;\n/\n\n" +
+				"CREATE VIEW v1 AS SELECT f1() AS a FROM dual;\n\n",
+		},
+	}
+
+	a := require.New(t)
+	differ := &SchemaDiffer{}
+	for _, test := range tests {
+		got, err := differ.SchemaDiff(test.old, test.new)
+		a.NoError(err)
+		a.Equalf(test.want, got, "old: %s\nnew: %s\n", test.old, test.new)
+	}
+}
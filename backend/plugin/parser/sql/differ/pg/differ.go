@@ -6,6 +6,7 @@ import (
 	"io"
 	"sort"
 
+	pgquery "github.com/pganalyze/pg_query_go/v2"
 	"github.com/pkg/errors"
 
 	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
@@ -33,7 +34,9 @@ type diffNode struct {
 	dropForeignKeyList         []ast.Node
 	dropConstraintExceptFkList []ast.Node
 	dropTriggerList            []ast.Node
+	dropPolicyList             []ast.Node
 	dropIndexList              []ast.Node
+	dropViewList               []ast.Node
 	dropDefaultList            []ast.Node
 	dropSequenceOwnedByList    []ast.Node
 	dropColumnList             []ast.Node
@@ -57,10 +60,13 @@ type diffNode struct {
 	alterColumnList                []ast.Node
 	setSequenceOwnedByList         []ast.Node
 	setDefaultList                 []ast.Node
+	createViewList                 []ast.Node
 	createIndexList                []ast.Node
 	createTriggerList              []ast.Node
 	createConstraintExceptFkList   []ast.Node
 	createForeignKeyList           []ast.Node
+	createPolicyList               []ast.Node
+	alterRowSecurityList           []ast.Node
 }
 
 type schemaMap map[string]*schemaInfo
@@ -72,6 +78,7 @@ type extensionMap map[string]*extensionInfo
 type functionMap map[string]*functionInfo
 type triggerMap map[string]*triggerInfo
 type typeMap map[string]*typeInfo
+type viewMap map[string]*viewInfo
 
 type schemaInfo struct {
 	id           int
@@ -83,6 +90,7 @@ type schemaInfo struct {
 	extensionMap extensionMap
 	functionMap  functionMap
 	typeMap      typeMap
+	viewMap      viewMap
 }
 
 func newSchemaInfo(id int, createSchema *ast.CreateSchemaStmt) *schemaInfo {
@@ -96,6 +104,7 @@ func newSchemaInfo(id int, createSchema *ast.CreateSchemaStmt) *schemaInfo {
 		extensionMap: make(extensionMap),
 		functionMap:  make(functionMap),
 		typeMap:      make(typeMap),
+		viewMap:      make(viewMap),
 	}
 }
 
@@ -230,6 +239,22 @@ func newTypeInfo(id int, createType *ast.CreateTypeStmt) *typeInfo {
 	}
 }
 
+type viewInfo struct {
+	id          int
+	existsInNew bool
+	// recreate is true if the view exists in new but needs to be dropped and created again.
+	recreate   bool
+	createView *ast.CreateViewStmt
+}
+
+func newViewInfo(id int, createView *ast.CreateViewStmt) *viewInfo {
+	return &viewInfo{
+		id:          id,
+		existsInNew: false,
+		createView:  createView,
+	}
+}
+
 func (m schemaMap) addTable(id int, table *ast.CreateTableStmt) error {
 	schema, exists := m[table.Name.Schema]
 	if !exists {
@@ -373,6 +398,23 @@ func (m schemaMap) getTrigger(schemaName string, tableName string, triggerName s
 	return table.triggerMap[triggerName]
 }
 
+func (m schemaMap) addView(id int, view *ast.CreateViewStmt) error {
+	schema, exists := m[view.Name.Schema]
+	if !exists {
+		return errors.Errorf("failed to add view: schema %s not found", view.Name.Schema)
+	}
+	schema.viewMap[view.Name.Name] = newViewInfo(id, view)
+	return nil
+}
+
+func (m schemaMap) getView(schemaName string, viewName string) *viewInfo {
+	schema, exists := m[schemaName]
+	if !exists {
+		return nil
+	}
+	return schema.viewMap[viewName]
+}
+
 func (m schemaMap) addType(id int, createType *ast.CreateTypeStmt) error {
 	schema, exists := m[createType.Type.TypeName().Schema]
 	if !exists {
@@ -453,6 +495,10 @@ func mergeDefaultIntoColumn(nodeList []ast.Node) ([]ast.Node, error) {
 				// For pg_dump, this will never happen.
 				return nil, errors.Errorf("cannot find table %s", schemaTableName)
 			}
+			// The unconverted items, e.g. ENABLE ROW LEVEL SECURITY, are kept for the row level security differ.
+			if len(node.AlterItemList) == 0 {
+				retNodes = append(retNodes, node)
+			}
 			for _, alterItem := range node.AlterItemList {
 				switch item := alterItem.(type) {
 				case *ast.SetDefaultStmt:
@@ -547,11 +593,16 @@ func (*SchemaDiffer) SchemaDiff(oldStmt, newStmt string) (string, error) {
 			if err := oldSchemaMap.addType(i, stmt); err != nil {
 				return "", err
 			}
+		case *ast.CreateViewStmt:
+			if err := oldSchemaMap.addView(i, stmt); err != nil {
+				return "", err
+			}
 			// TODO(rebelice): add default back here
 		}
 	}
 
 	diff := &diffNode{}
+	var newViewList []*ast.CreateViewStmt
 	for _, node := range newNodes {
 		switch stmt := node.(type) {
 		case *ast.CreateTableStmt:
@@ -693,9 +744,19 @@ func (*SchemaDiffer) SchemaDiff(oldStmt, newStmt string) (string, error) {
 			if err := diff.modifyType(oldType.createType, stmt); err != nil {
 				return "", err
 			}
+		case *ast.CreateViewStmt:
+			newViewList = append(newViewList, stmt)
 		}
 	}
 
+	if err := diff.modifyViewList(oldSchemaMap, newViewList); err != nil {
+		return "", err
+	}
+
+	if err := diff.diffRowSecurity(oldNodes, newNodes, oldSchemaMap); err != nil {
+		return "", err
+	}
+
 	// Drop remaining old objects.
 	if err := diff.dropObject(oldSchemaMap); err != nil {
 		return "", err
@@ -802,6 +863,9 @@ func (diff *diffNode) dropObject(oldSchemaMap schemaMap) error {
 	// Drop the remaining old trigger.
 	diff.dropTriggerStmt(oldSchemaMap)
 
+	// Drop the remaining old view.
+	diff.dropViewStmt(oldSchemaMap)
+
 	// Drop the remaining old type.
 	diff.dropTypeStmt(oldSchemaMap)
 
@@ -1017,8 +1081,11 @@ func (diff *diffNode) modifyExtension(oldExtension *ast.CreateExtensionStmt, new
 }
 
 func (diff *diffNode) modifyFunction(oldFunction *ast.CreateFunctionStmt, newFunction *ast.CreateFunctionStmt) error {
-	// TODO(rebelice): not use Text(), it only works for pg_dump.
-	if oldFunction.Text() != newFunction.Text() {
+	equal, err := isEqualStatement(oldFunction, newFunction)
+	if err != nil {
+		return err
+	}
+	if !equal {
 		diff.dropFunctionList = append(diff.dropFunctionList, &ast.DropFunctionStmt{
 			FunctionList: []*ast.FunctionDef{oldFunction.Function},
 		})
@@ -1140,8 +1207,11 @@ func (diff *diffNode) modifyType(oldType *ast.CreateTypeStmt, newType *ast.Creat
 }
 
 func (diff *diffNode) modifyTrigger(oldTrigger *ast.CreateTriggerStmt, newTrigger *ast.CreateTriggerStmt) error {
-	// TODO(rebelice): not use Text(), it only works for pg_dump.
-	if oldTrigger.Text() != newTrigger.Text() {
+	equal, err := isEqualStatement(oldTrigger, newTrigger)
+	if err != nil {
+		return err
+	}
+	if !equal {
 		diff.dropTriggerList = append(diff.dropTriggerList, &ast.DropTriggerStmt{
 			Trigger: oldTrigger.Trigger,
 		})
@@ -1150,6 +1220,64 @@ func (diff *diffNode) modifyTrigger(oldTrigger *ast.CreateTriggerStmt, newTrigge
 	return nil
 }
 
+// modifyViewList compares the views in the order of the new schema.
+// A view only depends on the views before it in pg_dump, so the views after the first modified view are recreated as well.
+func (diff *diffNode) modifyViewList(oldSchemaMap schemaMap, newViewList []*ast.CreateViewStmt) error {
+	recreate := false
+	for _, newView := range newViewList {
+		oldView := oldSchemaMap.getView(newView.Name.Schema, newView.Name.Name)
+		// Add the view.
+		if oldView == nil {
+			diff.createViewList = append(diff.createViewList, newView)
+			continue
+		}
+		oldView.existsInNew = true
+		if !recreate {
+			equal, err := isEqualStatement(oldView.createView, newView)
+			if err != nil {
+				return err
+			}
+			if equal && oldView.createView.Name.Type == newView.Name.Type {
+				continue
+			}
+			recreate = true
+		}
+		// CREATE OR REPLACE VIEW can only append new columns, so we drop and create the view again.
+		oldView.recreate = true
+		diff.createViewList = append(diff.createViewList, newView)
+	}
+	return nil
+}
+
+// isEqualStatement compares the statements by the deparsed pg_query parse tree,
+// so that the differences in the formatting, letter case and comments are ignored.
+func isEqualStatement(oldNode ast.Node, newNode ast.Node) (bool, error) {
+	if oldNode.Text() == newNode.Text() {
+		return true, nil
+	}
+	oldNormalized, err := normalizeStatement(oldNode.Text())
+	if err != nil {
+		return false, err
+	}
+	newNormalized, err := normalizeStatement(newNode.Text())
+	if err != nil {
+		return false, err
+	}
+	return oldNormalized == newNormalized, nil
+}
+
+func normalizeStatement(statement string) (string, error) {
+	tree, err := pgquery.Parse(statement)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse statement %q", statement)
+	}
+	normalized, err := pgquery.Deparse(tree)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to deparse statement %q", statement)
+	}
+	return normalized, nil
+}
+
 func (diff *diffNode) modifyIndex(oldIndex *ast.CreateIndexStmt, newIndex *ast.CreateIndexStmt) error {
 	// TODO(rebelice): not use Text(), it only works for pg_dump.
 	if oldIndex.Text() != newIndex.Text() {
@@ -1369,9 +1497,15 @@ func (diff *diffNode) deparse() (string, error) {
 	if err := printStmtSlice(&buf, diff.dropTriggerList); err != nil {
 		return "", err
 	}
+	if err := printStmtSliceByText(&buf, diff.dropPolicyList); err != nil {
+		return "", err
+	}
 	if err := printStmtSlice(&buf, diff.dropIndexList); err != nil {
 		return "", err
 	}
+	if err := printStmtSlice(&buf, diff.dropViewList); err != nil {
+		return "", err
+	}
 	if err := printStmtSlice(&buf, diff.dropDefaultList); err != nil {
 		return "", err
 	}
@@ -1437,6 +1571,9 @@ func (diff *diffNode) deparse() (string, error) {
 	if err := printStmtSlice(&buf, diff.setDefaultList); err != nil {
 		return "", err
 	}
+	if err := printStmtSliceByText(&buf, diff.createViewList); err != nil {
+		return "", err
+	}
 	if err := printStmtSliceByText(&buf, diff.createIndexList); err != nil {
 		return "", err
 	}
@@ -1449,6 +1586,12 @@ func (diff *diffNode) deparse() (string, error) {
 	if err := printStmtSlice(&buf, diff.createForeignKeyList); err != nil {
 		return "", err
 	}
+	if err := printStmtSliceByText(&buf, diff.createPolicyList); err != nil {
+		return "", err
+	}
+	if err := printStmtSliceByText(&buf, diff.alterRowSecurityList); err != nil {
+		return "", err
+	}
 
 	return buf.String(), nil
 }
@@ -1629,6 +1772,31 @@ func (diff *diffNode) dropTriggerStmt(m schemaMap) {
 	}
 }
 
+func (diff *diffNode) dropViewStmt(m schemaMap) {
+	var viewList []*viewInfo
+	for _, schema := range m {
+		for _, view := range schema.viewMap {
+			if view.existsInNew && !view.recreate {
+				// no need to drop
+				continue
+			}
+			viewList = append(viewList, view)
+		}
+	}
+	if len(viewList) == 0 {
+		return
+	}
+	// Drop the views in the reverse order, so that the dependent views are dropped first.
+	sort.Slice(viewList, func(i, j int) bool {
+		return viewList[i].id > viewList[j].id
+	})
+
+	for _, view := range viewList {
+		diff.dropViewList = append(diff.dropViewList,
+			&ast.DropTableStmt{TableList: []*ast.TableDef{view.createView.Name}})
+	}
+}
+
 func dropIndex(m schemaMap) *ast.DropIndexStmt {
 	var indexList []*indexInfo
 	for _, schema := range m {
@@ -64,6 +64,10 @@ func TestComputeDiff(t *testing.T) {
 		"test_differ_merge.yaml",
 		// Sequence
 		"test_differ_sequence.yaml",
+		// View
+		"test_differ_view.yaml",
+		// Row level security
+		"test_differ_policy.yaml",
 	}
 	for _, test := range testFileList {
 		runDifferTest(t, test, false /* record */)
@@ -0,0 +1,205 @@
+package pg
+
+import (
+	"fmt"
+	"sort"
+
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
+)
+
+// rowSecurityMap is the row level security of the tables, keyed by the schema and the table name. The AST doesn't
+// convert the row level security statements yet, so they're parsed from the statement text with pg_query.
+type rowSecurityMap map[string]*rowSecurityInfo
+
+type rowSecurityInfo struct {
+	schema    string
+	table     string
+	enabled   bool
+	forced    bool
+	policyMap map[string]*policyInfo
+}
+
+type policyInfo struct {
+	existsInNew bool
+	// createPolicy is the CREATE POLICY statement text.
+	createPolicy string
+}
+
+// getRowSecurity returns the row level security of the table, creating it if absent.
+func (m rowSecurityMap) getRowSecurity(schema string, table string) *rowSecurityInfo {
+	if schema == "" {
+		schema = "public"
+	}
+	key := fmt.Sprintf("%s.%s", schema, table)
+	info, ok := m[key]
+	if !ok {
+		info = &rowSecurityInfo{schema: schema, table: table, policyMap: make(map[string]*policyInfo)}
+		m[key] = info
+	}
+	return info
+}
+
+// buildRowSecurityMap collects the CREATE POLICY and the ALTER TABLE ... ROW LEVEL SECURITY statements. The other
+// statements are skipped.
+func buildRowSecurityMap(nodeList []ast.Node) (rowSecurityMap, error) {
+	m := make(rowSecurityMap)
+	for _, node := range nodeList {
+		switch node := node.(type) {
+		case *ast.UnconvertedStmt:
+		case *ast.AlterTableStmt:
+			// The converted items are handled by the schema differ.
+			if len(node.AlterItemList) > 0 {
+				continue
+			}
+		default:
+			continue
+		}
+		tree, err := pgquery.Parse(node.Text())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse statement %q", node.Text())
+		}
+		for _, stmt := range tree.Stmts {
+			switch n := stmt.Stmt.Node.(type) {
+			case *pgquery.Node_CreatePolicyStmt:
+				info := m.getRowSecurity(n.CreatePolicyStmt.Table.Schemaname, n.CreatePolicyStmt.Table.Relname)
+				info.policyMap[n.CreatePolicyStmt.PolicyName] = &policyInfo{createPolicy: node.Text()}
+			case *pgquery.Node_AlterTableStmt:
+				info := m.getRowSecurity(n.AlterTableStmt.Relation.Schemaname, n.AlterTableStmt.Relation.Relname)
+				for _, cmd := range n.AlterTableStmt.Cmds {
+					alterCmd, ok := cmd.Node.(*pgquery.Node_AlterTableCmd)
+					if !ok {
+						continue
+					}
+					switch alterCmd.AlterTableCmd.Subtype {
+					case pgquery.AlterTableType_AT_EnableRowSecurity:
+						info.enabled = true
+					case pgquery.AlterTableType_AT_DisableRowSecurity:
+						info.enabled = false
+					case pgquery.AlterTableType_AT_ForceRowSecurity:
+						info.forced = true
+					case pgquery.AlterTableType_AT_NoForceRowSecurity:
+						info.forced = false
+					}
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+// diffRowSecurity computes the row level security changes of the tables existing in the new schema. The policies of
+// the dropped tables are dropped with the tables.
+func (diff *diffNode) diffRowSecurity(oldNodeList []ast.Node, newNodeList []ast.Node, oldSchemaMap schemaMap) error {
+	oldMap, err := buildRowSecurityMap(oldNodeList)
+	if err != nil {
+		return err
+	}
+	newMap, err := buildRowSecurityMap(newNodeList)
+	if err != nil {
+		return err
+	}
+
+	var keyList []string
+	for key := range newMap {
+		keyList = append(keyList, key)
+	}
+	sort.Strings(keyList)
+	for _, key := range keyList {
+		newInfo := newMap[key]
+		oldInfo, ok := oldMap[key]
+		if !ok {
+			oldInfo = &rowSecurityInfo{schema: newInfo.schema, table: newInfo.table, policyMap: make(map[string]*policyInfo)}
+		}
+		tableName := fmt.Sprintf(`"%s"."%s"`, newInfo.schema, newInfo.table)
+		if oldInfo.enabled != newInfo.enabled {
+			action := "DISABLE"
+			if newInfo.enabled {
+				action = "ENABLE"
+			}
+			diff.alterRowSecurityList = append(diff.alterRowSecurityList, newTextNode(fmt.Sprintf("ALTER TABLE %s %s ROW LEVEL SECURITY;", tableName, action)))
+		}
+		if oldInfo.forced != newInfo.forced {
+			action := "NO FORCE"
+			if newInfo.forced {
+				action = "FORCE"
+			}
+			diff.alterRowSecurityList = append(diff.alterRowSecurityList, newTextNode(fmt.Sprintf("ALTER TABLE %s %s ROW LEVEL SECURITY;", tableName, action)))
+		}
+
+		for _, name := range sortedPolicyNames(newInfo.policyMap) {
+			newPolicy := newInfo.policyMap[name]
+			oldPolicy, ok := oldInfo.policyMap[name]
+			if !ok {
+				diff.createPolicyList = append(diff.createPolicyList, newTextNode(newPolicy.createPolicy))
+				continue
+			}
+			oldPolicy.existsInNew = true
+			equal, err := isEqualPolicy(oldPolicy.createPolicy, newPolicy.createPolicy)
+			if err != nil {
+				return err
+			}
+			if !equal {
+				diff.dropPolicyList = append(diff.dropPolicyList, newTextNode(fmt.Sprintf(`DROP POLICY "%s" ON %s;`, name, tableName)))
+				diff.createPolicyList = append(diff.createPolicyList, newTextNode(newPolicy.createPolicy))
+			}
+		}
+	}
+
+	// Drop the old policies and disable the row level security of the tables left but not in the new schema.
+	keyList = nil
+	for key := range oldMap {
+		keyList = append(keyList, key)
+	}
+	sort.Strings(keyList)
+	for _, key := range keyList {
+		oldInfo := oldMap[key]
+		if table := oldSchemaMap.getTable(oldInfo.schema, oldInfo.table); table != nil && !table.existsInNew {
+			continue
+		}
+		tableName := fmt.Sprintf(`"%s"."%s"`, oldInfo.schema, oldInfo.table)
+		if _, ok := newMap[key]; !ok {
+			if oldInfo.enabled {
+				diff.alterRowSecurityList = append(diff.alterRowSecurityList, newTextNode(fmt.Sprintf("ALTER TABLE %s DISABLE ROW LEVEL SECURITY;", tableName)))
+			}
+			if oldInfo.forced {
+				diff.alterRowSecurityList = append(diff.alterRowSecurityList, newTextNode(fmt.Sprintf("ALTER TABLE %s NO FORCE ROW LEVEL SECURITY;", tableName)))
+			}
+		}
+		for _, name := range sortedPolicyNames(oldInfo.policyMap) {
+			if !oldInfo.policyMap[name].existsInNew {
+				diff.dropPolicyList = append(diff.dropPolicyList, newTextNode(fmt.Sprintf(`DROP POLICY "%s" ON %s;`, name, tableName)))
+			}
+		}
+	}
+	return nil
+}
+
+func isEqualPolicy(oldPolicy string, newPolicy string) (bool, error) {
+	oldNormalized, err := normalizeStatement(oldPolicy)
+	if err != nil {
+		return false, err
+	}
+	newNormalized, err := normalizeStatement(newPolicy)
+	if err != nil {
+		return false, err
+	}
+	return oldNormalized == newNormalized, nil
+}
+
+func sortedPolicyNames(policyMap map[string]*policyInfo) []string {
+	var nameList []string
+	for name := range policyMap {
+		nameList = append(nameList, name)
+	}
+	sort.Strings(nameList)
+	return nameList
+}
+
+func newTextNode(text string) ast.Node {
+	node := &ast.UnconvertedStmt{}
+	node.SetText(text)
+	return node
+}
@@ -722,7 +722,7 @@ func convert(node *pgquery.Node, statement parser.SingleSQL) (res ast.Node, err
 			return nil, err
 		}
 
-		return &ast.CreateFunctionStmt{Function: functionDef}, nil
+		return &ast.CreateFunctionStmt{Function: functionDef, OrReplace: in.CreateFunctionStmt.Replace}, nil
 	case *pgquery.Node_CreateTrigStmt:
 		createTriggerStmt := &ast.CreateTriggerStmt{
 			Trigger: &ast.TriggerDef{
@@ -781,6 +781,20 @@ func convert(node *pgquery.Node, statement parser.SingleSQL) (res ast.Node, err
 		if in.TransactionStmt.Kind == pgquery.TransactionStmtKind_TRANS_STMT_COMMIT {
 			return &ast.CommitStmt{}, nil
 		}
+	case *pgquery.Node_ViewStmt:
+		return &ast.CreateViewStmt{
+			Name:    convertRangeVarToTableName(in.ViewStmt.View, ast.TableTypeView),
+			Replace: in.ViewStmt.Replace,
+		}, nil
+	case *pgquery.Node_CreateTableAsStmt:
+		if in.CreateTableAsStmt.Relkind == pgquery.ObjectType_OBJECT_MATVIEW && in.CreateTableAsStmt.Into != nil {
+			return &ast.CreateViewStmt{
+				Name:        convertRangeVarToTableName(in.CreateTableAsStmt.Into.Rel, ast.TableTypeMaterializedView),
+				IfNotExists: in.CreateTableAsStmt.IfNotExists,
+			}, nil
+		}
+		// CREATE TABLE AS statements are not converted yet.
+		return &ast.UnconvertedStmt{}, nil
 	default:
 		return &ast.UnconvertedStmt{}, nil
 	}
@@ -1159,12 +1173,22 @@ func convertCreateStmt(in *pgquery.CreateStmt) (*ast.CreateTableStmt, error) {
 	}
 
 	if in.Partspec != nil {
-		// TODO(rebelice): convert the partition definition.
-		table.PartitionDef = &ast.UnconvertedStmt{}
+		table.PartitionDef = convertPartitionSpec(in.Partspec)
 	}
 	return table, nil
 }
 
+func convertPartitionSpec(in *pgquery.PartitionSpec) *ast.PartitionDef {
+	partition := &ast.PartitionDef{
+		Strategy: ast.PartitionStrategy(strings.ToUpper(in.Strategy)),
+	}
+	for _, param := range in.PartParams {
+		// The expression keys don't have the name.
+		partition.KeyList = append(partition.KeyList, param.GetPartitionElem().GetName())
+	}
+	return partition
+}
+
 func convertSelectStmt(in *pgquery.SelectStmt) (*ast.SelectStmt, error) {
 	selectStmt := &ast.SelectStmt{}
 
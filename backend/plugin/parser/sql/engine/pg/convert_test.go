@@ -96,7 +96,10 @@ func TestPGConvertCreateTableStmt(t *testing.T) {
 							},
 						},
 					},
-					PartitionDef: &ast.UnconvertedStmt{},
+					PartitionDef: &ast.PartitionDef{
+						Strategy: ast.PartitionStrategyRange,
+						KeyList:  []string{"a"},
+					},
 				},
 			},
 			statementList: []parser.SingleSQL{
@@ -288,7 +288,16 @@ func deparseDropTable(context parser.DeparseContext, in *ast.DropTableStmt, buf
 		return err
 	}
 
-	if _, err := buf.WriteString("DROP TABLE "); err != nil {
+	objectType := "TABLE"
+	if len(in.TableList) > 0 {
+		switch in.TableList[0].Type {
+		case ast.TableTypeView:
+			objectType = "VIEW"
+		case ast.TableTypeMaterializedView:
+			objectType = "MATERIALIZED VIEW"
+		}
+	}
+	if _, err := buf.WriteString("DROP " + objectType + " "); err != nil {
 		return err
 	}
 	if in.IfExists {
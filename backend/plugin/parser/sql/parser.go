@@ -32,6 +32,8 @@ const (
 	Redshift EngineType = "REDSHIFT"
 	// OceanBase is the engine type for OceanBase.
 	OceanBase EngineType = "OCEANBASE"
+	// Snowflake is the engine type for Snowflake.
+	Snowflake EngineType = "SNOWFLAKE"
 
 	// DeparseIndentString is the string for each indent level.
 	DeparseIndentString = "    "
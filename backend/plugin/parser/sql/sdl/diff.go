@@ -0,0 +1,425 @@
+package sdl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+)
+
+// createPrefixRegexp matches the CREATE keyword and the optional OR REPLACE or OR ALTER clause.
+var createPrefixRegexp = regexp.MustCompile(`(?i)^CREATE\s+(OR\s+(REPLACE|ALTER)\s+)?`)
+
+// diffNode defines different modification types as the safe change order.
+// The safe change order means we can change them with no dependency conflicts as this order.
+type diffNode struct {
+	engineType parser.EngineType
+
+	// Drop nodes
+	dropForeignKeyList []string
+	dropTriggerList    []string
+	dropViewList       []string
+	dropFunctionList   []string
+	dropIndexList      []string
+	dropConstraintList []string
+	dropColumnList     []string
+	dropTableList      []string
+	dropSequenceList   []string
+
+	// Create nodes
+	sequenceList      []string
+	createTableList   []string
+	columnList        []string
+	addConstraintList []string
+	createIndexList   []string
+	addForeignKeyList []string
+	functionList      []string
+	viewList          []string
+	createTriggerList []string
+}
+
+// SchemaDiff returns the statements changing the old schema to the new schema.
+//
+// It compares the tables, indexes, views, materialized views, functions, procedures, triggers and sequences.
+// The unnamed constraints except the primary keys are never dropped because we cannot refer to them by name.
+func SchemaDiff(engineType parser.EngineType, oldStmt, newStmt string) (string, error) {
+	oldObjectList, err := extractObjectList(engineType, oldStmt)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to extract objects from old statement %q", oldStmt)
+	}
+	newObjectList, err := extractObjectList(engineType, newStmt)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to extract objects from new statement %q", newStmt)
+	}
+
+	oldObjectMap := make(map[string]*object)
+	for _, obj := range oldObjectList {
+		oldObjectMap[obj.key()] = obj
+	}
+	newObjectMap := make(map[string]*object)
+	for _, obj := range newObjectList {
+		newObjectMap[obj.key()] = obj
+	}
+
+	diff := &diffNode{engineType: engineType}
+	// Drop the objects in the reverse order of the old schema, so that the dependents are dropped first.
+	for i := len(oldObjectList) - 1; i >= 0; i-- {
+		oldObject := oldObjectList[i]
+		newObject, ok := newObjectMap[oldObject.key()]
+		if !ok {
+			diff.dropObject(oldObject)
+			continue
+		}
+		// The indexes and materialized views cannot be replaced, so we drop and recreate them.
+		if (oldObject.tp == index || oldObject.tp == materializedView) && oldObject.normalized != newObject.normalized {
+			diff.dropObject(oldObject)
+		}
+	}
+	for _, newObject := range newObjectList {
+		oldObject, ok := oldObjectMap[newObject.key()]
+		if !ok {
+			diff.createObject(newObject)
+			continue
+		}
+		if oldObject.normalized == newObject.normalized {
+			continue
+		}
+		switch newObject.tp {
+		case index, materializedView:
+			diff.createObject(newObject)
+		case table:
+			diff.modifyTable(oldObject, newObject)
+		case sequence:
+			diff.alterSequence(newObject)
+		default:
+			prefix := "CREATE OR REPLACE "
+			if engineType == parser.MSSQL {
+				prefix = "CREATE OR ALTER "
+			}
+			stmt := createPrefixRegexp.ReplaceAllString(newObject.stmt, prefix)
+			diff.appendCreate(newObject, diff.terminate(stmt, newObject.isProgramUnit(engineType)))
+		}
+	}
+
+	return diff.deparse(), nil
+}
+
+func (diff *diffNode) dropObject(obj *object) {
+	switch obj.tp {
+	case table:
+		for _, constraint := range obj.constraints {
+			if constraint.constraintType() == "FOREIGN KEY" && constraint.name != "" {
+				diff.dropForeignKeyList = append(diff.dropForeignKeyList, diff.dropConstraint(obj.name, constraint))
+			}
+		}
+		diff.dropTableList = append(diff.dropTableList, diff.terminate(fmt.Sprintf("DROP TABLE %s", obj.name), false))
+	case index:
+		stmt := fmt.Sprintf("DROP INDEX %s", obj.name)
+		if diff.engineType == parser.MSSQL {
+			stmt = fmt.Sprintf("DROP INDEX %s ON %s", obj.name, obj.table)
+		}
+		diff.dropIndexList = append(diff.dropIndexList, diff.terminate(stmt, false))
+	default:
+		stmt := diff.terminate(fmt.Sprintf("DROP %s %s", obj.tp, obj.name), false)
+		switch obj.tp {
+		case trigger:
+			diff.dropTriggerList = append(diff.dropTriggerList, stmt)
+		case view, materializedView:
+			diff.dropViewList = append(diff.dropViewList, stmt)
+		case function, procedure:
+			diff.dropFunctionList = append(diff.dropFunctionList, stmt)
+		case sequence:
+			diff.dropSequenceList = append(diff.dropSequenceList, stmt)
+		}
+	}
+}
+
+func (diff *diffNode) createObject(obj *object) {
+	if obj.tp != table {
+		diff.appendCreate(obj, diff.terminate(obj.stmt, obj.isProgramUnit(diff.engineType)))
+		return
+	}
+	// We add the foreign keys after creating all tables to avoid the dependency conflicts.
+	var elementList []*element
+	elementList = append(elementList, obj.columns...)
+	for _, constraint := range obj.constraints {
+		if constraint.constraintType() == "FOREIGN KEY" {
+			diff.addForeignKeyList = append(diff.addForeignKeyList, diff.addConstraint(obj.name, constraint))
+			continue
+		}
+		elementList = append(elementList, constraint)
+	}
+	diff.createTableList = append(diff.createTableList, diff.terminate(formatTable(obj, elementList), false))
+}
+
+func (diff *diffNode) appendCreate(obj *object, stmt string) {
+	switch obj.tp {
+	case trigger:
+		diff.createTriggerList = append(diff.createTriggerList, stmt)
+	case view, materializedView:
+		diff.viewList = append(diff.viewList, stmt)
+	case function, procedure:
+		diff.functionList = append(diff.functionList, stmt)
+	case sequence:
+		diff.sequenceList = append(diff.sequenceList, stmt)
+	case index:
+		diff.createIndexList = append(diff.createIndexList, stmt)
+	}
+}
+
+func (diff *diffNode) modifyTable(oldTable, newTable *object) {
+	oldColumnMap := make(map[string]*element)
+	for _, column := range oldTable.columns {
+		oldColumnMap[column.name] = column
+	}
+	newColumnMap := make(map[string]*element)
+	for _, column := range newTable.columns {
+		newColumnMap[column.name] = column
+	}
+	for _, oldColumn := range oldTable.columns {
+		if _, ok := newColumnMap[oldColumn.name]; !ok {
+			// MSSQL cannot drop the column with the default constraint.
+			if oldDefault, _ := oldColumn.columnDefault(); oldDefault != "" && diff.engineType == parser.MSSQL {
+				diff.dropMSSQLDefault(oldTable.name, oldColumn)
+			}
+			diff.dropColumnList = append(diff.dropColumnList, diff.terminate(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", oldTable.name, oldColumn.name), false))
+		}
+	}
+	for _, newColumn := range newTable.columns {
+		oldColumn, ok := oldColumnMap[newColumn.name]
+		if !ok {
+			stmt := fmt.Sprintf("ALTER TABLE %s ADD (%s)", newTable.name, newColumn.text())
+			if diff.engineType == parser.MSSQL {
+				stmt = fmt.Sprintf("ALTER TABLE %s ADD %s", newTable.name, newColumn.text())
+			}
+			diff.columnList = append(diff.columnList, diff.terminate(stmt, false))
+			continue
+		}
+		if oldColumn.normalized != newColumn.normalized {
+			diff.modifyColumn(newTable.name, oldColumn, newColumn)
+		}
+	}
+
+	constraintKey := func(constraint *element) string {
+		if constraint.name != "" {
+			return constraint.name
+		}
+		return constraint.normalized
+	}
+	oldConstraintMap := make(map[string]*element)
+	for _, constraint := range oldTable.constraints {
+		oldConstraintMap[constraintKey(constraint)] = constraint
+	}
+	newConstraintMap := make(map[string]*element)
+	for _, constraint := range newTable.constraints {
+		newConstraintMap[constraintKey(constraint)] = constraint
+	}
+	for _, oldConstraint := range oldTable.constraints {
+		newConstraint, ok := newConstraintMap[constraintKey(oldConstraint)]
+		if ok && newConstraint.normalized == oldConstraint.normalized {
+			continue
+		}
+		if oldConstraint.name == "" && oldConstraint.constraintType() != "PRIMARY KEY" {
+			continue
+		}
+		stmt := diff.dropConstraint(oldTable.name, oldConstraint)
+		if oldConstraint.constraintType() == "FOREIGN KEY" {
+			diff.dropForeignKeyList = append(diff.dropForeignKeyList, stmt)
+		} else {
+			diff.dropConstraintList = append(diff.dropConstraintList, stmt)
+		}
+	}
+	for _, newConstraint := range newTable.constraints {
+		oldConstraint, ok := oldConstraintMap[constraintKey(newConstraint)]
+		if ok && newConstraint.normalized == oldConstraint.normalized {
+			continue
+		}
+		stmt := diff.addConstraint(newTable.name, newConstraint)
+		if newConstraint.constraintType() == "FOREIGN KEY" {
+			diff.addForeignKeyList = append(diff.addForeignKeyList, stmt)
+		} else {
+			diff.addConstraintList = append(diff.addConstraintList, stmt)
+		}
+	}
+}
+
+func (diff *diffNode) modifyColumn(tableName string, oldColumn, newColumn *element) {
+	if diff.engineType == parser.MSSQL {
+		diff.modifyMSSQLColumn(tableName, oldColumn, newColumn)
+		return
+	}
+
+	// Oracle reports an error if we modify the column to NOT NULL or NULL which it already is.
+	var tokens []string
+	for i := 0; i < len(newColumn.tokens); i++ {
+		if oldColumn.notNull() && newColumn.notNull() && strings.EqualFold(newColumn.tokens[i], "NOT") && i+1 < len(newColumn.tokens) && strings.EqualFold(newColumn.tokens[i+1], "NULL") {
+			i++
+			continue
+		}
+		tokens = append(tokens, newColumn.tokens[i])
+	}
+	if oldDefault, _ := oldColumn.columnDefault(); oldDefault != "" {
+		if newDefault, _ := newColumn.columnDefault(); newDefault == "" {
+			tokens = insertBeforeConstraint(tokens, "DEFAULT", "NULL")
+		}
+	}
+	if oldColumn.notNull() && !newColumn.notNull() {
+		tokens = append(tokens, "NULL")
+	}
+	diff.columnList = append(diff.columnList, diff.terminate(fmt.Sprintf("ALTER TABLE %s MODIFY (%s)", tableName, strings.Join(tokens, " ")), false))
+}
+
+// modifyMSSQLColumn modifies the MSSQL column. The ALTER COLUMN clause cannot change the DEFAULT and IDENTITY
+// properties, so we change the default constraint separately and leave the identity as it is.
+func (diff *diffNode) modifyMSSQLColumn(tableName string, oldColumn, newColumn *element) {
+	oldTokens, newTokens := withoutDefaultAndIdentity(oldColumn.tokens), withoutDefaultAndIdentity(newColumn.tokens)
+	oldDefault, oldNormalizedDefault := oldColumn.columnDefault()
+	newDefault, newNormalizedDefault := newColumn.columnDefault()
+	if oldNormalizedDefault != newNormalizedDefault && oldDefault != "" {
+		diff.dropMSSQLDefault(tableName, oldColumn)
+	}
+	if normalizeText(diff.engineType, strings.Join(oldTokens, " ")) != normalizeText(diff.engineType, strings.Join(newTokens, " ")) {
+		diff.columnList = append(diff.columnList, diff.terminate(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", tableName, strings.Join(newTokens, " ")), false))
+	}
+	if oldNormalizedDefault != newNormalizedDefault && newDefault != "" {
+		diff.columnList = append(diff.columnList, diff.terminate(fmt.Sprintf("ALTER TABLE %s ADD DEFAULT %s FOR %s", tableName, newDefault, newColumn.name), false))
+	}
+}
+
+// dropMSSQLDefault drops the default constraint of the MSSQL column, which is system named usually.
+func (diff *diffNode) dropMSSQLDefault(tableName string, column *element) {
+	query := fmt.Sprintf("SELECT name FROM sys.default_constraints WHERE parent_object_id = OBJECT_ID(N'%s') AND parent_column_id = COLUMNPROPERTY(OBJECT_ID(N'%s'), N'%s', 'ColumnId')", quoteString(tableName), quoteString(tableName), quoteString(unquoteIdentifier(column.name)))
+	diff.dropConstraintList = append(diff.dropConstraintList, dropMSSQLConstraint(tableName, query))
+}
+
+func (diff *diffNode) addConstraint(tableName string, constraint *element) string {
+	return diff.terminate(fmt.Sprintf("ALTER TABLE %s ADD %s", tableName, constraint.text()), false)
+}
+
+func (diff *diffNode) dropConstraint(tableName string, constraint *element) string {
+	if constraint.name != "" {
+		return diff.terminate(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", tableName, constraint.name), false)
+	}
+	// The unnamed primary key.
+	if diff.engineType == parser.MSSQL {
+		return dropMSSQLConstraint(tableName, fmt.Sprintf("SELECT name FROM sys.key_constraints WHERE parent_object_id = OBJECT_ID(N'%s') AND type = 'PK'", quoteString(tableName)))
+	}
+	return diff.terminate(fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY", tableName), false)
+}
+
+// alterSequence alters the sequence to the new options except the START WITH option which cannot be altered.
+func (diff *diffNode) alterSequence(obj *object) {
+	loc := objectRegexp.FindStringIndex(obj.normalized)
+	options := strings.TrimSpace(obj.normalized[loc[1]:])
+	if options == "" {
+		return
+	}
+	diff.sequenceList = append(diff.sequenceList, diff.terminate(fmt.Sprintf("ALTER SEQUENCE %s %s", obj.name, options), false))
+}
+
+// terminate appends the terminator to the statement, the program units are terminated by the batch separator.
+func (diff *diffNode) terminate(stmt string, programUnit bool) string {
+	if !programUnit {
+		return stmt + ";\n\n"
+	}
+	if diff.engineType == parser.MSSQL {
+		return stmt + "\nGO\n\n"
+	}
+	return stmt + "\n/\n\n"
+}
+
+func (diff *diffNode) deparse() string {
+	var buf strings.Builder
+	for _, list := range [][]string{
+		diff.dropForeignKeyList,
+		diff.dropTriggerList,
+		diff.dropViewList,
+		diff.dropFunctionList,
+		diff.dropIndexList,
+		diff.dropConstraintList,
+		diff.dropColumnList,
+		diff.dropTableList,
+		diff.dropSequenceList,
+		diff.sequenceList,
+		diff.createTableList,
+		diff.columnList,
+		diff.addConstraintList,
+		diff.createIndexList,
+		diff.addForeignKeyList,
+		diff.functionList,
+		diff.viewList,
+		diff.createTriggerList,
+	} {
+		for _, stmt := range list {
+			_, _ = buf.WriteString(stmt)
+		}
+	}
+	return buf.String()
+}
+
+// formatTable formats the CREATE TABLE statement with one element per line.
+func formatTable(obj *object, elementList []*element) string {
+	var lines []string
+	for _, e := range elementList {
+		lines = append(lines, "  "+e.text())
+	}
+	stmt := fmt.Sprintf("%s (\n%s\n)", obj.prefix, strings.Join(lines, ",\n"))
+	if obj.suffix != "" {
+		stmt += " " + obj.suffix
+	}
+	return stmt
+}
+
+// dropMSSQLConstraint returns the batch dropping the MSSQL constraint whose name is queried by the query,
+// such as the system named default constraints.
+func dropMSSQLConstraint(tableName string, query string) string {
+	return fmt.Sprintf("DECLARE @constraint_name sysname = (%s);\nIF @constraint_name IS NOT NULL EXEC(N'ALTER TABLE %s DROP CONSTRAINT ' + QUOTENAME(@constraint_name));\nGO\n\n", query, quoteString(tableName))
+}
+
+func withoutDefaultAndIdentity(tokens []string) []string {
+	var result []string
+	for i := 0; i < len(tokens); i++ {
+		switch {
+		case strings.EqualFold(tokens[i], "CONSTRAINT") && i+2 < len(tokens) && strings.EqualFold(tokens[i+2], "DEFAULT"):
+			// The named default constraint.
+			i += 3
+		case strings.EqualFold(tokens[i], "DEFAULT"):
+			i++
+		case strings.EqualFold(tokens[i], "IDENTITY"):
+			if i+1 < len(tokens) && strings.HasPrefix(tokens[i+1], "(") {
+				i++
+			}
+		case len(tokens[i]) > len("IDENTITY") && strings.EqualFold(tokens[i][:len("IDENTITY")+1], "IDENTITY("):
+		default:
+			result = append(result, tokens[i])
+		}
+	}
+	return result
+}
+
+// insertBeforeConstraint inserts the tokens before the inline constraints of the column.
+func insertBeforeConstraint(tokens []string, insert ...string) []string {
+	for i, token := range tokens {
+		switch strings.ToUpper(token) {
+		case "NOT", "NULL", "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "REFERENCES":
+			result := append([]string{}, tokens[:i]...)
+			result = append(result, insert...)
+			return append(result, tokens[i:]...)
+		}
+	}
+	return append(tokens, insert...)
+}
+
+func quoteString(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+func unquoteIdentifier(identifier string) string {
+	if len(identifier) >= 2 && (identifier[0] == '[' || identifier[0] == '"') {
+		return identifier[1 : len(identifier)-1]
+	}
+	return identifier
+}
@@ -0,0 +1,93 @@
+package sdl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+)
+
+func TestOracleTableDiff(t *testing.T) {
+	oldSchema := `CREATE TABLE "T1" ("ID" NUMBER(*,0) NOT NULL ENABLE, "NAME" VARCHAR2(20 BYTE) DEFAULT 'a', "OLD" NUMBER, CONSTRAINT "PK_T1" PRIMARY KEY ("ID") USING INDEX ENABLE);
+CREATE INDEX "IDX_T1_NAME" ON "T1" ("NAME");
+CREATE SEQUENCE "S1" START WITH 21 INCREMENT BY 1 CACHE 20;`
+	newSchema := `create table t1 (
+  id integer not null,
+  name varchar2(30) not null,
+  age number default 0,
+  constraint pk_t1 primary key (id),
+  constraint fk_t1_t2 foreign key (age) references t2 (id)
+);
+create table t2 (id integer, primary key (id));
+create index idx_t1_name on t1 (name, age);
+CREATE SEQUENCE s1 START WITH 1 INCREMENT BY 1 CACHE 20;`
+	want := "DROP INDEX IDX_T1_NAME;\n\n" +
+		"ALTER TABLE T1 DROP COLUMN OLD;\n\n" +
+		"create table t2 (\n  id integer,\n  primary key (id)\n);\n\n" +
+		"ALTER TABLE T1 MODIFY (name varchar2(30) DEFAULT NULL not null);\n\n" +
+		"ALTER TABLE T1 ADD (age number default 0);\n\n" +
+		"create index idx_t1_name on t1 (name, age);\n\n" +
+		"ALTER TABLE T1 ADD constraint fk_t1_t2 foreign key (age) references t2 (id);\n\n"
+
+	a := require.New(t)
+	got, err := SchemaDiff(parser.Oracle, oldSchema, newSchema)
+	a.NoError(err)
+	a.Equal(want, got)
+
+	// The dumped schema is identical to the declared one.
+	got, err = SchemaDiff(parser.Oracle, oldSchema, oldSchema)
+	a.NoError(err)
+	a.Equal("", got)
+}
+
+func TestMSSQLTableDiff(t *testing.T) {
+	oldSchema := `CREATE TABLE [dbo].[t1] (
+  [id] int IDENTITY(1,1) NOT NULL,
+  [name] nvarchar(20) NULL DEFAULT ((0)),
+  [old] int NULL DEFAULT (getdate()),
+  PRIMARY KEY ([id])
+);
+CREATE NONCLUSTERED INDEX [idx_name] ON [dbo].[t1] ([name] ASC);
+GO
+CREATE VIEW [dbo].[v1] AS SELECT id FROM t1
+GO
+`
+	newSchema := `CREATE TABLE t1 (
+  id INT IDENTITY(1,1) NOT NULL,
+  name NVARCHAR(40) DEFAULT 0,
+  CONSTRAINT pk_t1 PRIMARY KEY (id)
+);
+CREATE INDEX idx_name ON t1 (name);
+GO
+CREATE VIEW v1 AS
+  SELECT id, name FROM t1;
+GO
+CREATE PROCEDURE p1 AS
+BEGIN
+  SELECT 1;
+  SELECT 2;
+END
+GO
+`
+	want := "DECLARE @constraint_name sysname = (SELECT name FROM sys.default_constraints WHERE parent_object_id = OBJECT_ID(N'DBO.T1') AND parent_column_id = COLUMNPROPERTY(OBJECT_ID(N'DBO.T1'), N'OLD', 'ColumnId'));\n" +
+		"IF @constraint_name IS NOT NULL EXEC(N'ALTER TABLE DBO.T1 DROP CONSTRAINT ' + QUOTENAME(@constraint_name));\n" +
+		"GO\n\n" +
+		"DECLARE @constraint_name sysname = (SELECT name FROM sys.key_constraints WHERE parent_object_id = OBJECT_ID(N'DBO.T1') AND type = 'PK');\n" +
+		"IF @constraint_name IS NOT NULL EXEC(N'ALTER TABLE DBO.T1 DROP CONSTRAINT ' + QUOTENAME(@constraint_name));\n" +
+		"GO\n\n" +
+		"ALTER TABLE DBO.T1 DROP COLUMN OLD;\n\n" +
+		"ALTER TABLE DBO.T1 ALTER COLUMN name NVARCHAR(40);\n\n" +
+		"ALTER TABLE DBO.T1 ADD CONSTRAINT pk_t1 PRIMARY KEY (id);\n\n" +
+		"CREATE PROCEDURE p1 AS\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND\nGO\n\n" +
+		"CREATE OR ALTER VIEW v1 AS\n  SELECT id, name FROM t1;\nGO\n\n"
+
+	a := require.New(t)
+	got, err := SchemaDiff(parser.MSSQL, oldSchema, newSchema)
+	a.NoError(err)
+	a.Equal(want, got)
+
+	got, err = SchemaDiff(parser.MSSQL, newSchema, newSchema)
+	a.NoError(err)
+	a.Equal("", got)
+}
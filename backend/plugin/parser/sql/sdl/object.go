@@ -0,0 +1,491 @@
+// Package sdl extracts and compares the schema objects in the SDL (schema definition language) statements
+// for the engines without the AST parser, such as Oracle and MSSQL.
+package sdl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+)
+
+type objectType string
+
+const (
+	table            objectType = "TABLE"
+	index            objectType = "INDEX"
+	view             objectType = "VIEW"
+	materializedView objectType = "MATERIALIZED VIEW"
+	function         objectType = "FUNCTION"
+	procedure        objectType = "PROCEDURE"
+	trigger          objectType = "TRIGGER"
+	sequence         objectType = "SEQUENCE"
+)
+
+const identifierPattern = `(?:"[^"]*"|\[[^\]]*\]|[A-Z0-9_$#@]+)`
+
+var (
+	// objectRegexp matches the normalized CREATE statements of the objects we support.
+	objectRegexp = regexp.MustCompile(`^CREATE (?:OR REPLACE |OR ALTER )?(?:(?:NON)?EDITIONABLE )?(?:(?:NO )?FORCE )?(?:UNIQUE )?(?:BITMAP |CLUSTERED |NONCLUSTERED )?(TABLE|INDEX|MATERIALIZED VIEW|VIEW|FUNCTION|PROCEDURE|PROC|TRIGGER|SEQUENCE) (` + identifierPattern + `(?:\.` + identifierPattern + `)*)`)
+	// indexTableRegexp matches the table name of the normalized CREATE INDEX statements.
+	indexTableRegexp = regexp.MustCompile(` ON (` + identifierPattern + `(?:\.` + identifierPattern + `)*)`)
+	// normalizedPrefixRegexp matches the clauses of the normalized CREATE statements which don't affect the object.
+	normalizedPrefixRegexp = regexp.MustCompile(`^CREATE (OR REPLACE |OR ALTER )?(EDITIONABLE )?`)
+	// startWithRegexp matches the START WITH option and the MSSQL data type of the normalized sequences.
+	startWithRegexp = regexp.MustCompile(`( START WITH -?\d+| AS [A-Z]+)`)
+	// oracleIntegerRegexp matches the integer types which are the aliases of NUMBER(*,0) in Oracle.
+	oracleIntegerRegexp = regexp.MustCompile(`^(\S+) (INTEGER|INT|SMALLINT)\b`)
+	// oracleByteSemanticsRegexp matches the default byte length semantics of the character types in Oracle.
+	oracleByteSemanticsRegexp = regexp.MustCompile(`\((\d+) BYTE\)`)
+	// mssqlIndexOptionRegexp matches the default options of the normalized MSSQL indexes.
+	mssqlIndexOptionRegexp = regexp.MustCompile(`( NONCLUSTERED| ASC\b)`)
+	// oracleSimpleIdentifierRegexp and mssqlSimpleIdentifierRegexp match the identifiers which don't need quoting.
+	oracleSimpleIdentifierRegexp = regexp.MustCompile(`^[A-Z][A-Z0-9_$#]*$`)
+	mssqlSimpleIdentifierRegexp  = regexp.MustCompile(`^[A-Z_@#][A-Z0-9_@$#]*$`)
+)
+
+// object is the schema object defined by a CREATE statement.
+type object struct {
+	tp objectType
+	// name is the normalized object name.
+	name string
+	// table is the normalized table name of the index.
+	table string
+	// stmt is the original statement without the leading comments and the terminator.
+	stmt string
+	// normalized is the normalized statement for comparison.
+	normalized string
+
+	// The following fields are only for tables.
+	// prefix and suffix are the original text around the table elements.
+	prefix      string
+	suffix      string
+	columns     []*element
+	constraints []*element
+}
+
+// element is the column or constraint in the CREATE TABLE statement.
+type element struct {
+	// name is the normalized name, it's empty for unnamed constraints.
+	name string
+	// tokens are the original tokens of the element.
+	tokens     []string
+	normalized string
+}
+
+func (o *object) key() string {
+	return fmt.Sprintf("%s %s %s", o.tp, o.table, o.name)
+}
+
+func (e *element) text() string {
+	return strings.Join(e.tokens, " ")
+}
+
+// isProgramUnit returns true if the statement must be terminated by the batch separator.
+func (o *object) isProgramUnit(engineType parser.EngineType) bool {
+	switch o.tp {
+	case function, procedure, trigger:
+		return true
+	case view:
+		return engineType == parser.MSSQL
+	}
+	return false
+}
+
+// extractObjectList extracts the objects from the statement in the original order.
+// The statements which are not the CREATE statements of the supported objects are ignored.
+func extractObjectList(engineType parser.EngineType, statement string) ([]*object, error) {
+	list, err := parser.SplitBatchMultiSQL(engineType, statement)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to split statement")
+	}
+	var result []*object
+	for _, sql := range list {
+		obj, err := parseObject(engineType, sql.Text)
+		if err != nil {
+			return nil, err
+		}
+		if obj != nil {
+			result = append(result, obj)
+		}
+	}
+	return result, nil
+}
+
+// parseObject parses the CREATE statement of the object. It returns nil if the statement is not supported.
+func parseObject(engineType parser.EngineType, stmt string) (*object, error) {
+	stmt = trimLeadingComments(stmt)
+	normalized := normalizeText(engineType, stmt)
+	loc := objectRegexp.FindStringSubmatchIndex(normalized)
+	if loc == nil {
+		return nil, nil
+	}
+	obj := &object{
+		tp:   objectType(normalized[loc[2]:loc[3]]),
+		name: normalized[loc[4]:loc[5]],
+		stmt: stmt,
+	}
+	if obj.tp == "PROC" {
+		obj.tp = procedure
+	}
+	if obj.tp != index {
+		obj.name = normalizeObjectName(engineType, obj.name)
+	}
+	if !obj.isProgramUnit(engineType) {
+		obj.stmt = strings.TrimRight(obj.stmt, "; \n\t")
+	}
+	// We use the normalized names in the normalized statement, so that "dbo.t" and "t" are the same in MSSQL.
+	prefix := normalizedPrefixRegexp.ReplaceAllString(normalized[:loc[2]], "CREATE ")
+	rest := normalized[loc[5]:]
+	switch obj.tp {
+	case table:
+		if err := obj.parseTableElements(engineType); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case index:
+		tableLoc := indexTableRegexp.FindStringSubmatchIndex(rest)
+		if tableLoc == nil {
+			return nil, errors.Errorf("failed to find the table of index %q", stmt)
+		}
+		obj.table = normalizeObjectName(engineType, rest[tableLoc[2]:tableLoc[3]])
+		rest = rest[:tableLoc[2]] + obj.table + rest[tableLoc[3]:]
+	case sequence:
+		// The START WITH option is the current value of the dumped Oracle sequences, so we ignore it.
+		rest = startWithRegexp.ReplaceAllString(rest, "")
+	}
+	obj.normalized = fmt.Sprintf("%s%s %s%s", prefix, obj.tp, obj.name, rest)
+	if obj.tp == index && engineType == parser.MSSQL {
+		obj.normalized = mssqlIndexOptionRegexp.ReplaceAllString(obj.normalized, "")
+	}
+	return obj, nil
+}
+
+// parseTableElements parses the columns and constraints of the CREATE TABLE statement.
+func (o *object) parseTableElements(engineType parser.EngineType) error {
+	begin, end := -1, -1
+	walk(o.stmt, func(i int, c rune, depth int) bool {
+		if c == '(' && depth == 0 && begin < 0 {
+			begin = i
+		}
+		if c == ')' && depth == 0 && begin >= 0 {
+			end = i
+			return false
+		}
+		return true
+	})
+	if begin < 0 || end < 0 {
+		return errors.Errorf("failed to find the columns of table %q", o.stmt)
+	}
+	o.prefix = strings.TrimSpace(o.stmt[:begin])
+	o.suffix = strings.TrimSpace(o.stmt[end+1:])
+
+	var normalizedList []string
+	for _, text := range splitTopLevel(o.stmt[begin+1:end], ',') {
+		e := newElement(engineType, text)
+		if e == nil {
+			continue
+		}
+		normalizedList = append(normalizedList, e.normalized)
+		if isConstraint(e.normalized) {
+			if strings.HasPrefix(e.normalized, "CONSTRAINT ") {
+				e.name = splitTopLevel(e.normalized, ' ')[1]
+			}
+			o.constraints = append(o.constraints, e)
+			continue
+		}
+		e.name = splitTopLevel(e.normalized, ' ')[0]
+		o.columns = append(o.columns, e)
+	}
+	o.normalized = fmt.Sprintf("CREATE TABLE %s(%s)%s", o.name, strings.Join(normalizedList, ","), normalizeText(engineType, o.suffix))
+	return nil
+}
+
+// newElement returns the column or constraint, the tokens which don't affect the element are removed.
+func newElement(engineType parser.EngineType, text string) *element {
+	var tokens []string
+	var normalizedTokens []string
+	for _, token := range splitTopLevel(text, ' ') {
+		normalizedToken := normalizeText(engineType, token)
+		switch normalizedToken {
+		case "":
+			continue
+		case "ENABLE":
+			// The ENABLE is the default state of the Oracle constraints.
+			continue
+		case "INDEX":
+			// The USING INDEX clause of the Oracle constraints.
+			if len(normalizedTokens) > 0 && normalizedTokens[len(normalizedTokens)-1] == "USING" {
+				tokens, normalizedTokens = tokens[:len(tokens)-1], normalizedTokens[:len(normalizedTokens)-1]
+				continue
+			}
+		case "NULL":
+			// The columns are nullable by default.
+			if len(normalizedTokens) > 0 && normalizedTokens[len(normalizedTokens)-1] != "NOT" && normalizedTokens[len(normalizedTokens)-1] != "DEFAULT" {
+				continue
+			}
+		}
+		tokens = append(tokens, token)
+		normalizedTokens = append(normalizedTokens, normalizedToken)
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	e := &element{tokens: tokens}
+	e.normalized = normalizeText(engineType, e.text())
+	if !isConstraint(e.normalized) {
+		if engineType == parser.Oracle {
+			e.normalized = oracleIntegerRegexp.ReplaceAllString(e.normalized, "$1 NUMBER(*,0)")
+			e.normalized = oracleByteSemanticsRegexp.ReplaceAllString(e.normalized, "($1)")
+		}
+		e.normalized = stripDefaultParentheses(e.normalized)
+	}
+	return e
+}
+
+func isConstraint(normalized string) bool {
+	for _, prefix := range []string{"CONSTRAINT ", "PRIMARY KEY", "UNIQUE", "FOREIGN KEY", "CHECK"} {
+		if strings.HasPrefix(normalized, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// constraintType returns the type of the normalized constraint, such as "PRIMARY KEY" and "FOREIGN KEY".
+func (e *element) constraintType() string {
+	text := e.normalized
+	if e.name != "" {
+		text = strings.TrimPrefix(text, fmt.Sprintf("CONSTRAINT %s ", e.name))
+	}
+	for _, tp := range []string{"PRIMARY KEY", "UNIQUE", "FOREIGN KEY", "CHECK"} {
+		if strings.HasPrefix(text, tp) {
+			return tp
+		}
+	}
+	return ""
+}
+
+// columnDefault returns the original and normalized DEFAULT expressions of the column.
+func (e *element) columnDefault() (string, string) {
+	for i, token := range e.tokens {
+		if strings.EqualFold(token, "DEFAULT") && i+1 < len(e.tokens) {
+			return e.tokens[i+1], stripDefaultParentheses(normalizeText(parser.Standard, "DEFAULT "+e.tokens[i+1]))
+		}
+	}
+	return "", ""
+}
+
+// notNull returns true if the column is NOT NULL.
+func (e *element) notNull() bool {
+	return strings.Contains(" "+e.normalized+" ", " NOT NULL ")
+}
+
+// stripDefaultParentheses strips the redundant parentheses around the normalized DEFAULT expression, such as
+// "DEFAULT ((0))" dumped by MSSQL.
+func stripDefaultParentheses(normalized string) string {
+	i := strings.Index(normalized, "DEFAULT(")
+	if i < 0 {
+		return normalized
+	}
+	rest := normalized[i+len("DEFAULT"):]
+	for strings.HasPrefix(rest, "(") {
+		end := -1
+		walk(rest, func(j int, c rune, depth int) bool {
+			if c == ')' && depth == 0 {
+				end = j
+				return false
+			}
+			return true
+		})
+		if end < 0 || (end+1 < len(rest) && rest[end+1] != ' ') {
+			break
+		}
+		rest = rest[1:end] + rest[end+1:]
+	}
+	if !strings.HasPrefix(rest, " ") {
+		rest = " " + rest
+	}
+	return normalized[:i] + "DEFAULT" + rest
+}
+
+// normalizeObjectName normalizes the object name, the MSSQL objects belong to the dbo schema by default.
+func normalizeObjectName(engineType parser.EngineType, name string) string {
+	if engineType == parser.MSSQL && len(splitTopLevel(name, '.')) == 1 {
+		return "DBO." + name
+	}
+	return name
+}
+
+// normalizeText normalizes the text for comparison.
+// It removes the comments, collapses the whitespaces, upper-cases the text outside the quoted strings,
+// unquotes the identifiers which don't need quoting and trims the trailing terminator.
+func normalizeText(engineType parser.EngineType, text string) string {
+	var buf strings.Builder
+	runes := []rune(text)
+	space := false
+	var last rune
+	write := func(s string) {
+		if space && buf.Len() > 0 && !strings.ContainsRune("(,.", last) && !strings.ContainsRune("(),.", []rune(s)[0]) {
+			_, _ = buf.WriteRune(' ')
+		}
+		space = false
+		_, _ = buf.WriteString(s)
+		last = []rune(s)[len([]rune(s))-1]
+	}
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			space = true
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				i++
+			}
+			i++
+			space = true
+		case unicode.IsSpace(c):
+			space = true
+		case c == '\'' || c == '"' || (c == '[' && engineType == parser.MSSQL):
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			// The quote escaped by doubling is handled as two adjacent quoted texts.
+			j := i + 1
+			for j < len(runes) && runes[j] != closing {
+				j++
+			}
+			if j >= len(runes) {
+				j = len(runes) - 1
+			}
+			quoted := string(runes[i : j+1])
+			if c != '\'' {
+				quoted = normalizeIdentifier(engineType, string(runes[i+1:j]))
+			}
+			write(quoted)
+			i = j
+		default:
+			write(strings.ToUpper(string(c)))
+		}
+	}
+	return strings.TrimRight(buf.String(), "; ")
+}
+
+func normalizeIdentifier(engineType parser.EngineType, identifier string) string {
+	if engineType == parser.MSSQL {
+		// MSSQL uses the case-insensitive collation by default.
+		identifier = strings.ToUpper(identifier)
+		if mssqlSimpleIdentifierRegexp.MatchString(identifier) {
+			return identifier
+		}
+		return fmt.Sprintf("[%s]", identifier)
+	}
+	if oracleSimpleIdentifierRegexp.MatchString(identifier) {
+		return identifier
+	}
+	return fmt.Sprintf(`"%s"`, identifier)
+}
+
+// walk calls f for each rune outside the quoted texts and comments with the depth of parentheses until f returns false.
+func walk(text string, f func(i int, c rune, depth int) bool) {
+	depth := 0
+	var quote rune
+	comment := ""
+	runes := []rune(text)
+	offset := 0
+	for i, c := range runes {
+		pos := offset
+		offset += len(string(c))
+		switch {
+		case comment == "--":
+			if c == '\n' {
+				comment = ""
+			}
+			continue
+		case comment == "/*":
+			if c == '/' && i > 0 && runes[i-1] == '*' {
+				comment = ""
+			}
+			continue
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+			continue
+		case '[':
+			quote = ']'
+			continue
+		case '-', '/':
+			if i+1 < len(runes) && ((c == '-' && runes[i+1] == '-') || (c == '/' && runes[i+1] == '*')) {
+				comment = string([]rune{c, runes[i+1]})
+				continue
+			}
+		}
+		if c == ')' {
+			depth--
+		}
+		if !f(pos, c, depth) {
+			return
+		}
+		if c == '(' {
+			depth++
+		}
+	}
+}
+
+// splitTopLevel splits the text by the separator outside the parentheses, quoted texts and comments.
+// The separator ' ' matches all whitespaces.
+func splitTopLevel(text string, separator rune) []string {
+	var result []string
+	start := 0
+	walk(text, func(i int, c rune, depth int) bool {
+		if depth == 0 && (c == separator || (separator == ' ' && unicode.IsSpace(c))) {
+			if part := strings.TrimSpace(text[start:i]); part != "" {
+				result = append(result, part)
+			}
+			start = i + len(string(c))
+		}
+		return true
+	})
+	if part := strings.TrimSpace(text[start:]); part != "" {
+		result = append(result, part)
+	}
+	return result
+}
+
+// trimLeadingComments removes the leading comments and whitespaces of the statement.
+func trimLeadingComments(stmt string) string {
+	for {
+		stmt = strings.TrimSpace(stmt)
+		switch {
+		case strings.HasPrefix(stmt, "--"):
+			i := strings.Index(stmt, "\n")
+			if i < 0 {
+				return ""
+			}
+			stmt = stmt[i+1:]
+		case strings.HasPrefix(stmt, "/*"):
+			i := strings.Index(stmt, "*/")
+			if i < 0 {
+				return ""
+			}
+			stmt = stmt[i+2:]
+		default:
+			return stmt
+		}
+	}
+}
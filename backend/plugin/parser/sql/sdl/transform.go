@@ -0,0 +1,117 @@
+package sdl
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+)
+
+// Check checks the schema format and returns the line of the invalid statement.
+//
+// Accepted SDL Format:
+//  1. CREATE TABLE statements.
+//     i.  Column define without constraints.
+//     ii. Primary key, unique, check and foreign key constraints define in table-level.
+//     iii. The constraints except the primary key must be named.
+//  2. CREATE INDEX statements.
+//  3. CREATE VIEW, MATERIALIZED VIEW, FUNCTION, PROCEDURE, TRIGGER and SEQUENCE statements.
+func Check(engineType parser.EngineType, schema string) (int, error) {
+	list, err := parser.SplitBatchMultiSQL(engineType, schema)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to split SQL")
+	}
+	for _, sql := range list {
+		obj, err := parseObject(engineType, sql.Text)
+		if err != nil {
+			return sql.LastLine, err
+		}
+		if obj == nil {
+			return sql.LastLine, errors.Errorf("%q is invalid SDL statement", firstLine(sql.Text))
+		}
+		if obj.tp != table {
+			continue
+		}
+		for _, column := range obj.columns {
+			for _, token := range splitTopLevel(column.normalized, ' ') {
+				switch {
+				case token == "PRIMARY":
+					return sql.LastLine, errors.Errorf("The column-level primary key constraint is invalid SDL format. Please use table-level primary key, such as \"CREATE TABLE t(id INT, PRIMARY KEY (id));\"")
+				case token == "UNIQUE":
+					return sql.LastLine, errors.Errorf("The column-level unique key constraint is invalid SDL format. Please use table-level unique key, such as \"CREATE TABLE t(id INT, CONSTRAINT uk_t_id UNIQUE (id));\"")
+				case token == "CHECK" || strings.HasPrefix(token, "CHECK("):
+					return sql.LastLine, errors.Errorf("The column-level check constraint is invalid SDL format. Please use table-level check constraints, such as \"CREATE TABLE t(id INT, CONSTRAINT ck_t CHECK (id > 0));\"")
+				case token == "REFERENCES":
+					return sql.LastLine, errors.Errorf("The column-level foreign key constraint is invalid SDL format. Please use table-level foreign key constraints, such as \"CREATE TABLE t(id INT, CONSTRAINT fk_t_id FOREIGN KEY (id) REFERENCES t1(c1));\"")
+				}
+			}
+		}
+		for _, constraint := range obj.constraints {
+			if constraint.name == "" && constraint.constraintType() != "PRIMARY KEY" {
+				return sql.LastLine, errors.Errorf("The constraint name is required for SDL format")
+			}
+		}
+	}
+	return 0, nil
+}
+
+// Transform returns the transformed schema, the statements other than the supported CREATE statements are removed.
+func Transform(engineType parser.EngineType, schema string) (string, error) {
+	objectList, err := extractObjectList(engineType, schema)
+	if err != nil {
+		return "", err
+	}
+	return deparseObjectList(engineType, objectList), nil
+}
+
+// Normalize normalizes the schema format and orders the objects as the standard. The schema and standard should be SDL format.
+func Normalize(engineType parser.EngineType, schema string, standard string) (string, error) {
+	objectList, err := extractObjectList(engineType, schema)
+	if err != nil {
+		return "", err
+	}
+	standardObjectList, err := extractObjectList(engineType, standard)
+	if err != nil {
+		return "", err
+	}
+
+	objectMap := make(map[string]*object)
+	for _, obj := range objectList {
+		objectMap[obj.key()] = obj
+	}
+	var result []*object
+	for _, standardObject := range standardObjectList {
+		if obj, ok := objectMap[standardObject.key()]; ok {
+			result = append(result, obj)
+			delete(objectMap, standardObject.key())
+		}
+	}
+	// The remaining objects are not in the standard, we append them in the original order.
+	for _, obj := range objectList {
+		if _, ok := objectMap[obj.key()]; ok {
+			result = append(result, obj)
+		}
+	}
+	return deparseObjectList(engineType, result), nil
+}
+
+func deparseObjectList(engineType parser.EngineType, objectList []*object) string {
+	diff := &diffNode{engineType: engineType}
+	var buf strings.Builder
+	for _, obj := range objectList {
+		stmt := obj.stmt
+		if obj.tp == table {
+			stmt = formatTable(obj, append(append([]*element{}, obj.columns...), obj.constraints...))
+		}
+		_, _ = buf.WriteString(diff.terminate(stmt, obj.isProgramUnit(engineType)))
+	}
+	return buf.String()
+}
+
+func firstLine(text string) string {
+	if i := strings.Index(text, "\n"); i >= 0 {
+		return text[:i]
+	}
+	return text
+}
@@ -0,0 +1,75 @@
+package sdl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		engineType parser.EngineType
+		schema     string
+		line       int
+		err        string
+	}{
+		{
+			engineType: parser.Oracle,
+			schema:     "CREATE TABLE t (a NUMBER, CONSTRAINT pk_t PRIMARY KEY (a));\nCREATE INDEX idx_t_a ON t (a);\n",
+		},
+		{
+			engineType: parser.Oracle,
+			schema:     "CREATE TABLE t (a NUMBER PRIMARY KEY);\n",
+			line:       1,
+			err:        "The column-level primary key constraint is invalid SDL format. Please use table-level primary key, such as \"CREATE TABLE t(id INT, PRIMARY KEY (id));\"",
+		},
+		{
+			engineType: parser.Oracle,
+			schema:     "CREATE TABLE t (a NUMBER);\nINSERT INTO t VALUES (1);\n",
+			line:       2,
+			err:        "\"INSERT INTO t VALUES (1)\" is invalid SDL statement",
+		},
+		{
+			engineType: parser.MSSQL,
+			schema:     "CREATE TABLE t (a INT, UNIQUE (a));\n",
+			line:       1,
+			err:        "The constraint name is required for SDL format",
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		line, err := Check(test.engineType, test.schema)
+		if test.err == "" {
+			a.NoError(err)
+			continue
+		}
+		a.EqualError(err, test.err)
+		a.Equal(test.line, line)
+	}
+}
+
+func TestTransformAndNormalize(t *testing.T) {
+	schema := "CREATE TABLE t (a NUMBER, b NUMBER, CONSTRAINT pk_t PRIMARY KEY (a));\n" +
+		"CREATE INDEX idx_t_b ON t (b);\n" +
+		"CREATE OR REPLACE PROCEDURE p1 IS\nBEGIN\n  NULL;\nEND;\n/\n"
+	want := "CREATE TABLE t (\n  a NUMBER,\n  b NUMBER,\n  CONSTRAINT pk_t PRIMARY KEY (a)\n);\n\n" +
+		"CREATE INDEX idx_t_b ON t (b);\n\n" +
+		"CREATE OR REPLACE PROCEDURE p1 IS\nBEGIN\n  NULL;\nEND;\n/\n\n"
+
+	a := require.New(t)
+	got, err := Transform(parser.Oracle, schema)
+	a.NoError(err)
+	a.Equal(want, got)
+
+	standard := "CREATE OR REPLACE PROCEDURE p1 IS\nBEGIN\n  NULL;\nEND;\n/\n" +
+		"CREATE TABLE T (A NUMBER);\n"
+	want = "CREATE OR REPLACE PROCEDURE p1 IS\nBEGIN\n  NULL;\nEND;\n/\n\n" +
+		"CREATE TABLE t (\n  a NUMBER,\n  b NUMBER,\n  CONSTRAINT pk_t PRIMARY KEY (a)\n);\n\n" +
+		"CREATE INDEX idx_t_b ON t (b);\n\n"
+	got, err = Normalize(parser.Oracle, schema, standard)
+	a.NoError(err)
+	a.Equal(want, got)
+}
@@ -69,6 +69,60 @@ func TestOracleSplitMultiSQL(t *testing.T) {
 	}
 }
 
+func TestSplitBatchMultiSQL(t *testing.T) {
+	tests := []struct {
+		engineType EngineType
+		statement  string
+		want       []SingleSQL
+	}{
+		{
+			engineType: Oracle,
+			statement: "CREATE TABLE t (a NUMBER);\n" +
+				"CREATE OR REPLACE FUNCTION f RETURN NUMBER IS\n" +
+				"BEGIN\n" +
+				"  RETURN 1;\n" +
+				"END;\n" +
+				"/\n" +
+				"INSERT INTO t VALUES (1);\n" +
+				"BEGIN\n" +
+				"  UPDATE t SET a = 2;\n" +
+				"END;\n" +
+				"/\n",
+			want: []SingleSQL{
+				{Text: "CREATE TABLE t (a NUMBER)", LastLine: 1},
+				{Text: "CREATE OR REPLACE FUNCTION f RETURN NUMBER IS\nBEGIN\n  RETURN 1;\nEND;", LastLine: 5},
+				{Text: "INSERT INTO t VALUES (1)", LastLine: 7},
+				{Text: "BEGIN\n  UPDATE t SET a = 2;\nEND;", LastLine: 10},
+			},
+		},
+		{
+			engineType: MSSQL,
+			statement: "CREATE TABLE t (a INT);\n" +
+				"INSERT INTO t VALUES (1);\n" +
+				"GO\n" +
+				"CREATE PROCEDURE p AS\n" +
+				"BEGIN\n" +
+				"  SELECT a FROM t;\n" +
+				"  SELECT 1;\n" +
+				"END\n" +
+				"go\n" +
+				"SELECT 2;",
+			want: []SingleSQL{
+				{Text: "CREATE TABLE t (a INT);", LastLine: 1},
+				{Text: "INSERT INTO t VALUES (1);", LastLine: 2},
+				{Text: "CREATE PROCEDURE p AS\nBEGIN\n  SELECT a FROM t;\n  SELECT 1;\nEND", LastLine: 8},
+				{Text: "SELECT 2;", LastLine: 10},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		res, err := SplitBatchMultiSQL(test.engineType, test.statement)
+		require.NoError(t, err)
+		require.Equal(t, test.want, res, test.statement)
+	}
+}
+
 func TestPGSplitMultiSQL(t *testing.T) {
 	bigSQL := generateOneMBInsert()
 	tests := []testData{
@@ -0,0 +1,36 @@
+// Package mssql provides the MSSQL transformer plugin.
+package mssql
+
+import (
+	bbparser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/sdl"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/transform"
+)
+
+var (
+	_ transform.SchemaTransformer = (*SchemaTransformer)(nil)
+)
+
+func init() {
+	transform.Register(bbparser.MSSQL, &SchemaTransformer{})
+}
+
+// SchemaTransformer it the transformer for MSSQL dialect.
+type SchemaTransformer struct {
+}
+
+// Normalize normalizes the schema format. The schema and standard should be SDL format.
+func (*SchemaTransformer) Normalize(schema string, standard string) (string, error) {
+	return sdl.Normalize(bbparser.MSSQL, schema, standard)
+}
+
+// Check checks the schema format.
+func (*SchemaTransformer) Check(schema string) (int, error) {
+	return sdl.Check(bbparser.MSSQL, schema)
+}
+
+// Transform returns the transformed schema.
+func (*SchemaTransformer) Transform(schema string) (string, error) {
+	return sdl.Transform(bbparser.MSSQL, schema)
+}
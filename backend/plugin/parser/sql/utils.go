@@ -239,7 +239,8 @@ func SplitMultiSQL(engineType EngineType, statement string) ([]SingleSQL, error)
 	case Oracle, MSSQL:
 		t := newTokenizer(statement)
 		list, err = t.splitStandardMultiSQL()
-	case Postgres, Redshift:
+	// Snowflake uses the dollar-quoted string constants for the procedure bodies as PostgreSQL.
+	case Postgres, Redshift, Snowflake:
 		t := newTokenizer(statement)
 		list, err = t.splitPostgreSQLMultiSQL()
 	case MySQL, TiDB, MariaDB, OceanBase:
@@ -274,7 +275,7 @@ func SplitMultiSQLStream(engineType EngineType, src io.Reader, f func(string) er
 	case Oracle, MSSQL:
 		t := newStreamTokenizer(src, f)
 		list, err = t.splitStandardMultiSQL()
-	case Postgres, Redshift:
+	case Postgres, Redshift, Snowflake:
 		t := newStreamTokenizer(src, f)
 		list, err = t.splitPostgreSQLMultiSQL()
 	case MySQL, TiDB, MariaDB, OceanBase:
@@ -302,6 +303,77 @@ func SplitMultiSQLStream(engineType EngineType, src io.Reader, f func(string) er
 	return result, nil
 }
 
+var (
+	// oracleProgramUnitRegexp matches the beginning of the PL/SQL units and blocks.
+	oracleProgramUnitRegexp = regexp.MustCompile(`(?im)^[ \t]*(CREATE\s+(OR\s+REPLACE\s+)?((NON)?EDITIONABLE\s+)?(FUNCTION|PROCEDURE|TRIGGER|PACKAGE|TYPE)\s|DECLARE\b|BEGIN\b)`)
+	// mssqlProgramUnitRegexp matches the beginning of the statements which must be the only statement in the batch.
+	mssqlProgramUnitRegexp = regexp.MustCompile(`(?im)^[ \t]*((CREATE|ALTER|CREATE\s+OR\s+ALTER)\s+(PROCEDURE|PROC|FUNCTION|TRIGGER|VIEW)\s|DECLARE\b)`)
+)
+
+// SplitBatchMultiSQL splits the statement into a slice of the single SQL for the engines using the batch separator,
+// which is a line containing only "/" for Oracle and "GO" for MSSQL.
+// The program units, such as the PL/SQL blocks and the CREATE PROCEDURE statements, are kept as a whole up to
+// the batch separator, and the other statements are split as SplitMultiSQL does.
+func SplitBatchMultiSQL(engineType EngineType, statement string) ([]SingleSQL, error) {
+	var programUnitRegexp *regexp.Regexp
+	var isSeparator func(string) bool
+	switch engineType {
+	case Oracle:
+		programUnitRegexp = oracleProgramUnitRegexp
+		isSeparator = func(line string) bool { return strings.TrimSpace(line) == "/" }
+	case MSSQL:
+		programUnitRegexp = mssqlProgramUnitRegexp
+		isSeparator = func(line string) bool { return strings.EqualFold(strings.TrimSpace(line), "GO") }
+	default:
+		return nil, errors.Errorf("engine type is not supported: %s", engineType)
+	}
+
+	var result []SingleSQL
+	var block []string
+	// firstLine is the line number of the first line in the block.
+	firstLine := 1
+	flush := func() error {
+		text := strings.Join(block, "\n")
+		block = nil
+		unit := ""
+		if loc := programUnitRegexp.FindStringIndex(text); loc != nil {
+			text, unit = text[:loc[0]], text[loc[0]:]
+		}
+		if strings.TrimSpace(text) != "" {
+			list, err := SplitMultiSQL(engineType, text)
+			if err != nil {
+				return err
+			}
+			for _, sql := range list {
+				sql.LastLine += firstLine - 1
+				result = append(result, sql)
+			}
+		}
+		if strings.TrimSpace(unit) != "" {
+			result = append(result, SingleSQL{
+				Text:     strings.TrimSpace(unit),
+				LastLine: firstLine + strings.Count(text, "\n") + strings.Count(strings.TrimRight(unit, " \n\t"), "\n"),
+			})
+		}
+		return nil
+	}
+	lines := strings.Split(statement, "\n")
+	for i, line := range lines {
+		if isSeparator(line) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			firstLine = i + 2
+			continue
+		}
+		block = append(block, line)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // SetLineForCreateTableStmt sets the line for columns and table constraints in CREATE TABLE statements.
 func SetLineForCreateTableStmt(engineType EngineType, node *ast.CreateTableStmt) error {
 	switch engineType {
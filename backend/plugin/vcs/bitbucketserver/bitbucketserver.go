@@ -0,0 +1,871 @@
+// Package bitbucketserver is the plugin for self-hosted Bitbucket Server and Data Center.
+//
+// Bitbucket Data Center authenticates the REST API with the HTTP access tokens, a.k.a. the personal access tokens, of
+// the users instead of the OAuth applications, thus the token of the service account is set as the secret of the VCS
+// provider.
+package bitbucketserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/internal/oauth"
+)
+
+const (
+	// apiPageSize is the page size when making API requests.
+	apiPageSize = 100
+)
+
+func init() {
+	vcs.Register(vcs.BitbucketServer, newProvider)
+}
+
+var (
+	_ vcs.Provider             = (*Provider)(nil)
+	_ vcs.PullRequestCommenter = (*Provider)(nil)
+)
+
+// Provider is a Bitbucket Server VCS provider.
+type Provider struct {
+	client *http.Client
+}
+
+func newProvider(config vcs.ProviderConfig) vcs.Provider {
+	if config.Client == nil {
+		config.Client = &http.Client{}
+	}
+	return &Provider{
+		client: config.Client,
+	}
+}
+
+// APIURL returns the API URL path of a Bitbucket Server instance.
+func (*Provider) APIURL(instanceURL string) string {
+	return fmt.Sprintf("%s/rest/api/1.0", instanceURL)
+}
+
+// repositoryPath returns the API path of the repository, whose ID is in the form of "<project key>/<repository slug>".
+func repositoryPath(repositoryID string) string {
+	projectKey, slug, _ := strings.Cut(repositoryID, "/")
+	return fmt.Sprintf("projects/%s/repos/%s", projectKey, slug)
+}
+
+// ExchangeOAuthToken verifies the personal access token, which is the code if present or the client secret otherwise,
+// and returns it as the OAuth token. The token doesn't expire from Bytebase's point of view, it must be rotated in
+// the VCS provider settings.
+func (p *Provider) ExchangeOAuthToken(ctx context.Context, instanceURL string, oauthExchange *common.OAuthExchange) (*vcs.OAuthToken, error) {
+	token := oauthExchange.Code
+	if token == "" {
+		token = oauthExchange.ClientSecret
+	}
+	username, err := p.whoami(ctx, instanceURL, token)
+	if err != nil {
+		return nil, err
+	}
+	if username == "" {
+		return nil, errors.New("invalid personal access token, the request is authenticated as an anonymous user")
+	}
+	return &vcs.OAuthToken{
+		AccessToken: token,
+		CreatedAt:   time.Now().Unix(),
+	}, nil
+}
+
+// whoami returns the username of the token, or empty for the anonymous user.
+func (p *Provider) whoami(ctx context.Context, instanceURL, token string) (string, error) {
+	url := fmt.Sprintf("%s/plugins/servlet/applinks/whoami", instanceURL)
+	code, _, body, err := oauth.Get(ctx, p.client, url, &token, tokenRefresher())
+	if err != nil {
+		return "", errors.Wrapf(err, "GET %s", url)
+	}
+	if code == http.StatusUnauthorized {
+		return "", errors.Errorf("invalid personal access token, status code: %d, body: %s", code, body)
+	} else if code >= 300 {
+		return "", errors.Errorf("failed to read the current user from URL %s, status code: %d, body: %s", url, code, body)
+	}
+	return strings.TrimSpace(body), nil
+}
+
+// User represents a Bitbucket Server API response for a user.
+type User struct {
+	Name         string `json:"name"`
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+	Active       bool   `json:"active"`
+	Slug         string `json:"slug"`
+}
+
+// TryLogin tries to fetch the user info from the current OAuth context.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-system-maintenance/#api-api-latest-users-userslug-get
+func (p *Provider) TryLogin(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) (*vcs.UserInfo, error) {
+	username, err := p.whoami(ctx, instanceURL, oauthCtx.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	if username == "" {
+		return nil, errors.New("invalid personal access token, the request is authenticated as an anonymous user")
+	}
+
+	url := fmt.Sprintf("%s/users/%s", p.APIURL(instanceURL), url.PathEscape(username))
+	code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher())
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to read user info from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to read user info from URL %s, status code: %d, body: %s", url, code, body)
+	}
+
+	var user User
+	if err := json.Unmarshal([]byte(body), &user); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	state := vcs.StateActive
+	if !user.Active {
+		state = vcs.StateArchived
+	}
+	return &vcs.UserInfo{
+		PublicEmail: user.EmailAddress,
+		Name:        user.DisplayName,
+		State:       state,
+	}, nil
+}
+
+// Commit represents a Bitbucket Server API response for a commit.
+type Commit struct {
+	ID        string `json:"id"`
+	DisplayID string `json:"displayId"`
+	Author    User   `json:"author"`
+	// AuthorTimestamp is the timestamp in milliseconds.
+	AuthorTimestamp int64  `json:"authorTimestamp"`
+	Message         string `json:"message"`
+}
+
+// FetchCommitByID fetches the commit data by its ID from the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-commits-commitid-get
+func (p *Provider) FetchCommitByID(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, commitID string) (*vcs.Commit, error) {
+	url := fmt.Sprintf("%s/%s/commits/%s", p.APIURL(instanceURL), repositoryPath(repositoryID), commitID)
+	code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher())
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to fetch commit data from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to fetch commit data from URL %s, status code: %d, body: %s", url, code, body)
+	}
+
+	var commit Commit
+	if err := json.Unmarshal([]byte(body), &commit); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+
+	// Per Git convention, the message title and body are separated by two new line characters.
+	messages := strings.SplitN(commit.Message, "\n\n", 2)
+	return &vcs.Commit{
+		ID:          commit.ID,
+		Title:       messages[0],
+		Message:     commit.Message,
+		CreatedTs:   commit.AuthorTimestamp / 1000,
+		AuthorName:  commit.Author.DisplayName,
+		AuthorEmail: commit.Author.EmailAddress,
+	}, nil
+}
+
+// ChangePath represents a Bitbucket Server API response for the path of a change.
+type ChangePath struct {
+	ToString string `json:"toString"`
+}
+
+// Change represents a Bitbucket Server API response for a changed file.
+type Change struct {
+	Path ChangePath `json:"path"`
+	// The type of the change, possible values are "ADD", "MODIFY", "DELETE",
+	// "MOVE", "COPY".
+	Type string `json:"type"`
+}
+
+type changePage struct {
+	Values        []*Change `json:"values"`
+	IsLastPage    bool      `json:"isLastPage"`
+	NextPageStart int       `json:"nextPageStart"`
+}
+
+// listChanges lists all changes from the paginated API of the given URL, which must have query parameters already.
+func (p *Provider) listChanges(ctx context.Context, oauthCtx common.OauthContext, url string) ([]*Change, error) {
+	var changes []*Change
+	start := 0
+	for {
+		pageURL := fmt.Sprintf("%s&start=%d&limit=%d", url, start, apiPageSize)
+		code, _, body, err := oauth.Get(ctx, p.client, pageURL, &oauthCtx.AccessToken, tokenRefresher())
+		if err != nil {
+			return nil, errors.Wrapf(err, "GET %s", pageURL)
+		}
+		if code == http.StatusNotFound {
+			return nil, common.Errorf(common.NotFound, "failed to list changes from URL %s", pageURL)
+		} else if code >= 300 {
+			return nil, errors.Errorf("failed to list changes from URL %s, status code: %d, body: %s", pageURL, code, body)
+		}
+
+		var page changePage
+		if err := json.Unmarshal([]byte(body), &page); err != nil {
+			return nil, errors.Wrap(err, "unmarshal body")
+		}
+		changes = append(changes, page.Values...)
+		if page.IsLastPage {
+			break
+		}
+		start = page.NextPageStart
+	}
+	return changes, nil
+}
+
+// GetDiffFileList gets the diff files list between two commits.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-changes-get
+func (p *Provider) GetDiffFileList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, beforeCommit, afterCommit string) ([]vcs.FileDiff, error) {
+	url := fmt.Sprintf("%s/%s/changes?until=%s", p.APIURL(instanceURL), repositoryPath(repositoryID), afterCommit)
+	// The before commit is all zeros for a new branch, the changes are of the
+	// after commit alone in that case.
+	if strings.Trim(beforeCommit, "0") != "" {
+		url += fmt.Sprintf("&since=%s", beforeCommit)
+	}
+	changes, err := p.listChanges(ctx, oauthCtx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []vcs.FileDiff
+	for _, c := range changes {
+		diff := vcs.FileDiff{
+			Path: c.Path.ToString,
+		}
+		switch c.Type {
+		case "ADD":
+			diff.Type = vcs.FileDiffTypeAdded
+		case "MODIFY":
+			diff.Type = vcs.FileDiffTypeModified
+		case "DELETE":
+			diff.Type = vcs.FileDiffTypeRemoved
+		default:
+			// Skip because we don't care about file diff in other types
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// Link represents a Bitbucket Server API response for a link.
+type Link struct {
+	Href string `json:"href"`
+}
+
+// Links represents a Bitbucket Server API response for the links of a resource.
+type Links struct {
+	Self []Link `json:"self"`
+}
+
+// Project represents a Bitbucket Server API response for a project.
+type Project struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// Repository represents a Bitbucket Server API response for a repository.
+type Repository struct {
+	ID      int     `json:"id"`
+	Slug    string  `json:"slug"`
+	Name    string  `json:"name"`
+	Project Project `json:"project"`
+	Links   Links   `json:"links"`
+}
+
+// FetchAllRepositoryList fetches all repositories where the authenticated user
+// has admin permissions, which is required to create webhook in the repository.
+// The repositories are browsed by projects, i.e. the ID and the full path are in
+// the form of "<project key>/<repository slug>".
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-repos-get
+func (p *Provider) FetchAllRepositoryList(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) ([]*vcs.Repository, error) {
+	var repos []*vcs.Repository
+	start := 0
+	for {
+		url := fmt.Sprintf("%s/repos?permission=REPO_ADMIN&start=%d&limit=%d", p.APIURL(instanceURL), start, apiPageSize)
+		code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher())
+		if err != nil {
+			return nil, errors.Wrapf(err, "GET %s", url)
+		}
+		if code == http.StatusNotFound {
+			return nil, common.Errorf(common.NotFound, "failed to fetch repository list from URL %s", url)
+		} else if code >= 300 {
+			return nil, errors.Errorf("failed to fetch repository list from URL %s, status code: %d, body: %s", url, code, body)
+		}
+
+		var page struct {
+			Values        []*Repository `json:"values"`
+			IsLastPage    bool          `json:"isLastPage"`
+			NextPageStart int           `json:"nextPageStart"`
+		}
+		if err := json.Unmarshal([]byte(body), &page); err != nil {
+			return nil, errors.Wrap(err, "unmarshal body")
+		}
+		for _, r := range page.Values {
+			fullPath := fmt.Sprintf("%s/%s", r.Project.Key, r.Slug)
+			var webURL string
+			if len(r.Links.Self) > 0 {
+				webURL = strings.TrimSuffix(r.Links.Self[0].Href, "/browse")
+			}
+			repos = append(repos,
+				&vcs.Repository{
+					ID:       fullPath,
+					Name:     r.Name,
+					FullPath: fullPath,
+					WebURL:   webURL,
+				},
+			)
+		}
+
+		if page.IsLastPage {
+			break
+		}
+		start = page.NextPageStart
+	}
+	return repos, nil
+}
+
+// FetchRepositoryFileList fetches the all files from the given repository tree
+// recursively.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-files-path-get
+func (p *Provider) FetchRepositoryFileList(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, ref, filePath string) ([]*vcs.RepositoryTreeNode, error) {
+	filePath = strings.Trim(filePath, "/")
+
+	var allTreeNodes []*vcs.RepositoryTreeNode
+	start := 0
+	for {
+		url := fmt.Sprintf("%s/%s/files/%s?at=%s&start=%d&limit=%d", p.APIURL(instanceURL), repositoryPath(repositoryID), filePath, url.QueryEscape(ref), start, apiPageSize)
+		code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher())
+		if err != nil {
+			return nil, errors.Wrapf(err, "GET %s", url)
+		}
+		if code == http.StatusNotFound {
+			return nil, common.Errorf(common.NotFound, "failed to fetch repository file list from URL %s", url)
+		} else if code >= 300 {
+			return nil, errors.Errorf("failed to fetch repository file list from URL %s, status code: %d, body: %s", url, code, body)
+		}
+
+		var page struct {
+			// The file paths are relative to the requested path.
+			Values        []string `json:"values"`
+			IsLastPage    bool     `json:"isLastPage"`
+			NextPageStart int      `json:"nextPageStart"`
+		}
+		if err := json.Unmarshal([]byte(body), &page); err != nil {
+			return nil, errors.Wrap(err, "unmarshal body")
+		}
+		for _, v := range page.Values {
+			allTreeNodes = append(allTreeNodes,
+				&vcs.RepositoryTreeNode{
+					Path: path.Join(filePath, v),
+					Type: "blob",
+				},
+			)
+		}
+
+		if page.IsLastPage {
+			break
+		}
+		start = page.NextPageStart
+	}
+	return allTreeNodes, nil
+}
+
+// CreateFile creates a file at given path in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-browse-path-put
+func (p *Provider) CreateFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	_ = w.WriteField("content", fileCommitCreate.Content)
+	_ = w.WriteField("message", fileCommitCreate.CommitMessage)
+	_ = w.WriteField("branch", fileCommitCreate.Branch)
+	if fileCommitCreate.LastCommitID != "" {
+		_ = w.WriteField("sourceCommitId", fileCommitCreate.LastCommitID)
+	}
+	_ = w.Close()
+
+	url := fmt.Sprintf("%s/%s/browse/%s", p.APIURL(instanceURL), repositoryPath(repositoryID), strings.TrimPrefix(filePath, "/"))
+	code, _, resp, err := oauth.PutWithHeader(
+		ctx,
+		p.client,
+		url,
+		&oauthCtx.AccessToken,
+		&body,
+		tokenRefresher(),
+		map[string]string{
+			"Content-Type": w.FormDataContentType(),
+			// The multipart requests are rejected by the XSRF check without it.
+			"X-Atlassian-Token": "no-check",
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create/update file through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create/update file through URL %s, status code: %d, body: %s", url, code, resp)
+	}
+	return nil
+}
+
+// OverwriteFile overwrites an existing file at given path in the repository,
+// the last commit ID of the file is required.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-browse-path-put
+func (p *Provider) OverwriteFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	return p.CreateFile(ctx, oauthCtx, instanceURL, repositoryID, filePath, fileCommitCreate)
+}
+
+// ReadFileMeta reads the metadata of the given file in the repository, which
+// is the last commit changing the file.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-commits-get
+func (p *Provider) ReadFileMeta(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath, ref string) (*vcs.FileMeta, error) {
+	filePath = strings.TrimPrefix(filePath, "/")
+	url := fmt.Sprintf("%s/%s/commits?path=%s&until=%s&limit=1", p.APIURL(instanceURL), repositoryPath(repositoryID), url.QueryEscape(filePath), url.QueryEscape(ref))
+	code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher())
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to read file meta from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to read file meta from URL %s, status code: %d, body: %s", url, code, body)
+	}
+
+	var page struct {
+		Values []*Commit `json:"values"`
+	}
+	if err := json.Unmarshal([]byte(body), &page); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	if len(page.Values) == 0 {
+		return nil, common.Errorf(common.NotFound, "file %q not found at %q", filePath, ref)
+	}
+
+	return &vcs.FileMeta{
+		Name:         path.Base(filePath),
+		Path:         filePath,
+		LastCommitID: page.Values[0].ID,
+	}, nil
+}
+
+// ReadFileContent reads the content of the given file in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-raw-path-get
+func (p *Provider) ReadFileContent(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, filePath, ref string) (string, error) {
+	url := fmt.Sprintf("%s/%s/raw/%s?at=%s", p.APIURL(instanceURL), repositoryPath(repositoryID), strings.TrimPrefix(filePath, "/"), url.QueryEscape(ref))
+	code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher())
+	if err != nil {
+		return "", errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to read file from URL %s", url)
+	} else if code >= 300 {
+		return "", errors.Errorf("failed to read file from URL %s, status code: %d, body: %s", url, code, body)
+	}
+	return body, nil
+}
+
+// Branch represents a Bitbucket Server API response for a branch.
+type Branch struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+}
+
+// GetBranch gets the given branch in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-branches-get
+func (p *Provider) GetBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, branchName string) (*vcs.BranchInfo, error) {
+	url := fmt.Sprintf("%s/%s/branches?filterText=%s&limit=%d", p.APIURL(instanceURL), repositoryPath(repositoryID), url.QueryEscape(branchName), apiPageSize)
+	code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher())
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to get branch from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to get branch from URL %s, status code: %d, body: %s", url, code, body)
+	}
+
+	var page struct {
+		Values []*Branch `json:"values"`
+	}
+	if err := json.Unmarshal([]byte(body), &page); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	// The filter text matches the branches containing it.
+	for _, branch := range page.Values {
+		if branch.DisplayID == branchName {
+			return &vcs.BranchInfo{
+				Name:         branch.DisplayID,
+				LastCommitID: branch.LatestCommit,
+			}, nil
+		}
+	}
+	return nil, common.Errorf(common.NotFound, "branch %q not found", branchName)
+}
+
+type branchCreate struct {
+	Name       string `json:"name"`
+	StartPoint string `json:"startPoint"`
+}
+
+// CreateBranch creates the branch in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-branches-post
+func (p *Provider) CreateBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, branch *vcs.BranchInfo) error {
+	body, err := json.Marshal(
+		branchCreate{
+			Name:       branch.Name,
+			StartPoint: branch.LastCommitID,
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "marshal branch create")
+	}
+
+	url := fmt.Sprintf("%s/%s/branches", p.APIURL(instanceURL), repositoryPath(repositoryID))
+	code, _, resp, err := oauth.Post(ctx, p.client, url, &oauthCtx.AccessToken, bytes.NewReader(body), tokenRefresher())
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create branch from URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create branch from URL %s, status code: %d, body: %s", url, code, resp)
+	}
+	return nil
+}
+
+// PullRequestRef represents a Bitbucket Server API response for the source or
+// the target of a pull request.
+type PullRequestRef struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId,omitempty"`
+	LatestCommit string `json:"latestCommit,omitempty"`
+}
+
+// PullRequest represents a Bitbucket Server API response for a pull request.
+type PullRequest struct {
+	ID      int            `json:"id"`
+	Title   string         `json:"title"`
+	FromRef PullRequestRef `json:"fromRef"`
+	ToRef   PullRequestRef `json:"toRef"`
+	Links   Links          `json:"links"`
+}
+
+// ListPullRequestFile lists the changed files in the pull request.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-changes-get
+func (p *Provider) ListPullRequestFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID string) ([]*vcs.PullRequestFile, error) {
+	url := fmt.Sprintf("%s/%s/pull-requests/%s", p.APIURL(instanceURL), repositoryPath(repositoryID), pullRequestID)
+	code, _, body, err := oauth.Get(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher())
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s", url)
+	}
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to get pull request from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to get pull request from URL %s, status code: %d, body: %s", url, code, body)
+	}
+	var pullRequest PullRequest
+	if err := json.Unmarshal([]byte(body), &pullRequest); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+
+	changes, err := p.listChanges(ctx, oauthCtx, url+"/changes?withComments=false")
+	if err != nil {
+		return nil, err
+	}
+	var files []*vcs.PullRequestFile
+	for _, c := range changes {
+		files = append(files, &vcs.PullRequestFile{
+			Path:         c.Path.ToString,
+			LastCommitID: pullRequest.FromRef.LatestCommit,
+			IsDeleted:    c.Type == "DELETE",
+		})
+	}
+	return files, nil
+}
+
+// PullRequestCreate represents a Bitbucket Server API request for creating a pull request.
+type PullRequestCreate struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	FromRef     PullRequestRef `json:"fromRef"`
+	ToRef       PullRequestRef `json:"toRef"`
+}
+
+// CreatePullRequest creates the pull request in the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-post
+func (p *Provider) CreatePullRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, create *vcs.PullRequestCreate) (*vcs.PullRequest, error) {
+	payload, err := json.Marshal(
+		PullRequestCreate{
+			Title:       create.Title,
+			Description: create.Body,
+			FromRef:     PullRequestRef{ID: "refs/heads/" + create.Head},
+			ToRef:       PullRequestRef{ID: "refs/heads/" + create.Base},
+		},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal pull request create")
+	}
+
+	url := fmt.Sprintf("%s/%s/pull-requests", p.APIURL(instanceURL), repositoryPath(repositoryID))
+	code, _, body, err := oauth.Post(ctx, p.client, url, &oauthCtx.AccessToken, bytes.NewReader(payload), tokenRefresher())
+	if err != nil {
+		return nil, errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil, common.Errorf(common.NotFound, "failed to create pull request from URL %s", url)
+	} else if code >= 300 {
+		return nil, errors.Errorf("failed to create pull request from URL %s, status code: %d, body: %s", url, code, body)
+	}
+
+	var pullRequest PullRequest
+	if err := json.Unmarshal([]byte(body), &pullRequest); err != nil {
+		return nil, errors.Wrap(err, "unmarshal body")
+	}
+	var pullRequestURL string
+	if len(pullRequest.Links.Self) > 0 {
+		pullRequestURL = pullRequest.Links.Self[0].Href
+	}
+	return &vcs.PullRequest{
+		URL: pullRequestURL,
+	}, nil
+}
+
+// CreatePullRequestComment creates a comment on the pull request, the content is in markdown.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-pull-requests/#api-api-latest-projects-projectkey-repos-repositoryslug-pull-requests-pullrequestid-comments-post
+func (p *Provider) CreatePullRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID, content string) error {
+	payload, err := json.Marshal(map[string]string{"text": content})
+	if err != nil {
+		return errors.Wrap(err, "marshal pull request comment")
+	}
+
+	url := fmt.Sprintf("%s/%s/pull-requests/%s/comments", p.APIURL(instanceURL), repositoryPath(repositoryID), pullRequestID)
+	code, _, body, err := oauth.Post(ctx, p.client, url, &oauthCtx.AccessToken, bytes.NewReader(payload), tokenRefresher())
+	if err != nil {
+		return errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to create pull request comment through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to create pull request comment through URL %s, status code: %d, body: %s", url, code, body)
+	}
+	return nil
+}
+
+// UpsertEnvironmentVariable creates or updates the environment variable in the repository.
+//
+// WARNING: This is not supported in Bitbucket Server, the SQL review results
+// are commented on the pull requests instead of running in the CI.
+func (*Provider) UpsertEnvironmentVariable(context.Context, common.OauthContext, string, string, string, string) error {
+	return errors.New("not supported")
+}
+
+const (
+	// WebhookRefsChanged is the webhook event key for pushing to the repository.
+	WebhookRefsChanged = "repo:refs_changed"
+	// WebhookPullRequestOpened is the webhook event key for opening a pull request.
+	WebhookPullRequestOpened = "pr:opened"
+	// WebhookPullRequestFromRefUpdated is the webhook event key for pushing to the source branch of a pull request.
+	WebhookPullRequestFromRefUpdated = "pr:from_ref_updated"
+	// WebhookPing is the webhook event key for testing the connection in the webhook settings.
+	WebhookPing = "diagnostics:ping"
+)
+
+// WebhookConfiguration represents the Bitbucket Server API message for webhook configuration.
+type WebhookConfiguration struct {
+	Secret string `json:"secret"`
+}
+
+// WebhookCreateOrUpdate represents a Bitbucket Server API request for creating
+// or updating a webhook.
+type WebhookCreateOrUpdate struct {
+	Name          string               `json:"name"`
+	URL           string               `json:"url"`
+	Active        bool                 `json:"active"`
+	Events        []string             `json:"events"`
+	Configuration WebhookConfiguration `json:"configuration"`
+}
+
+// Webhook represents a Bitbucket Server API response for the webhook information.
+type Webhook struct {
+	ID int `json:"id"`
+}
+
+// CreateWebhook creates a webhook in the repository with given payload.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-webhooks-post
+func (p *Provider) CreateWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID string, payload []byte) (string, error) {
+	url := fmt.Sprintf("%s/%s/webhooks", p.APIURL(instanceURL), repositoryPath(repositoryID))
+	code, _, body, err := oauth.Post(ctx, p.client, url, &oauthCtx.AccessToken, bytes.NewReader(payload), tokenRefresher())
+	if err != nil {
+		return "", errors.Wrapf(err, "POST %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "failed to create webhook through URL %s", url)
+	} else if code >= 300 {
+		return "", errors.Errorf("failed to create webhook through URL %s, status code: %d, body: %s", url, code, body)
+	}
+
+	var webhook Webhook
+	if err = json.Unmarshal([]byte(body), &webhook); err != nil {
+		return "", errors.Wrap(err, "unmarshal body")
+	}
+	return strconv.Itoa(webhook.ID), nil
+}
+
+// PatchWebhook patches the webhook in the repository with given payload.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-webhooks-webhookid-put
+func (p *Provider) PatchWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string, payload []byte) error {
+	url := fmt.Sprintf("%s/%s/webhooks/%s", p.APIURL(instanceURL), repositoryPath(repositoryID), webhookID)
+	code, _, body, err := oauth.Put(ctx, p.client, url, &oauthCtx.AccessToken, bytes.NewReader(payload), tokenRefresher())
+	if err != nil {
+		return errors.Wrapf(err, "PUT %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return common.Errorf(common.NotFound, "failed to patch webhook through URL %s", url)
+	} else if code >= 300 {
+		return errors.Errorf("failed to patch webhook through URL %s, status code: %d, body: %s", url, code, body)
+	}
+	return nil
+}
+
+// DeleteWebhook deletes the webhook from the repository.
+//
+// Docs: https://developer.atlassian.com/server/bitbucket/rest/v811/api-group-repository/#api-api-latest-projects-projectkey-repos-repositoryslug-webhooks-webhookid-delete
+func (p *Provider) DeleteWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string) error {
+	url := fmt.Sprintf("%s/%s/webhooks/%s", p.APIURL(instanceURL), repositoryPath(repositoryID), webhookID)
+	code, _, body, err := oauth.Delete(ctx, p.client, url, &oauthCtx.AccessToken, tokenRefresher())
+	if err != nil {
+		return errors.Wrapf(err, "DELETE %s", url)
+	}
+
+	if code == http.StatusNotFound {
+		return nil // It is OK if the webhook has already gone
+	} else if code >= 300 {
+		return errors.Errorf("failed to delete webhook through URL %s, status code: %d, body: %s", url, code, body)
+	}
+	return nil
+}
+
+// WebhookProject is the API message for the project of a webhook repository.
+type WebhookProject struct {
+	Key string `json:"key"`
+}
+
+// WebhookRepository is the API message for the repository of a webhook event.
+type WebhookRepository struct {
+	Slug    string         `json:"slug"`
+	Name    string         `json:"name"`
+	Project WebhookProject `json:"project"`
+}
+
+// FullPath returns the full path of the repository, which is also the repository ID.
+func (r WebhookRepository) FullPath() string {
+	return fmt.Sprintf("%s/%s", r.Project.Key, r.Slug)
+}
+
+// WebhookRef is the API message for the ref of a webhook change.
+type WebhookRef struct {
+	ID        string `json:"id"`
+	DisplayID string `json:"displayId"`
+	// The type of the ref, possible values are "BRANCH", "TAG".
+	Type string `json:"type"`
+}
+
+// WebhookChange is the API message for a ref change of the webhook push event.
+type WebhookChange struct {
+	Ref      WebhookRef `json:"ref"`
+	RefID    string     `json:"refId"`
+	FromHash string     `json:"fromHash"`
+	ToHash   string     `json:"toHash"`
+	// The type of the change, possible values are "ADD", "UPDATE", "DELETE".
+	Type string `json:"type"`
+}
+
+// WebhookPushEvent is the API message for webhook push event, which doesn't
+// carry the commits.
+type WebhookPushEvent struct {
+	EventKey   string            `json:"eventKey"`
+	Actor      User              `json:"actor"`
+	Repository WebhookRepository `json:"repository"`
+	Changes    []WebhookChange   `json:"changes"`
+}
+
+// WebhookPullRequestRef is the API message for the source or the target of the
+// pull request in a webhook event.
+type WebhookPullRequestRef struct {
+	ID           string            `json:"id"`
+	DisplayID    string            `json:"displayId"`
+	LatestCommit string            `json:"latestCommit"`
+	Repository   WebhookRepository `json:"repository"`
+}
+
+// WebhookPullRequest is the API message for the pull request in a webhook event.
+type WebhookPullRequest struct {
+	ID      int                   `json:"id"`
+	Title   string                `json:"title"`
+	FromRef WebhookPullRequestRef `json:"fromRef"`
+	ToRef   WebhookPullRequestRef `json:"toRef"`
+}
+
+// WebhookPullRequestEvent is the API message for webhook pull request event.
+type WebhookPullRequestEvent struct {
+	EventKey    string             `json:"eventKey"`
+	Actor       User               `json:"actor"`
+	PullRequest WebhookPullRequest `json:"pullRequest"`
+}
+
+// tokenRefresher fails the retries, because the personal access tokens can't be refreshed.
+func tokenRefresher() oauth.TokenRefresher {
+	return func(context.Context, *http.Client, *string) error {
+		return errors.New("the personal access token is expired or revoked, update the Bitbucket Server VCS provider with a new token")
+	}
+}
@@ -0,0 +1,530 @@
+package bitbucketserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/plugin/vcs"
+)
+
+const bitbucketServerURL = "https://bitbucket.example.com"
+
+func TestProvider_ExchangeOAuthToken(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/plugins/servlet/applinks/whoami", r.URL.Path)
+		assert.Equal(t, "Bearer test_token", r.Header.Get("Authorization"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("bytebase")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ExchangeOAuthToken(ctx, bitbucketServerURL,
+		&common.OAuthExchange{
+			ClientID:     "bytebase",
+			ClientSecret: "test_token",
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "test_token", got.AccessToken)
+}
+
+func TestProvider_ExchangeOAuthToken_Anonymous(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	_, err := p.ExchangeOAuthToken(ctx, bitbucketServerURL,
+		&common.OAuthExchange{
+			ClientID:     "bytebase",
+			ClientSecret: "invalid_token",
+		},
+	)
+	assert.Error(t, err)
+}
+
+func TestProvider_TryLogin(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/plugins/servlet/applinks/whoami":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("jsmith")),
+			}, nil
+		case "/rest/api/1.0/users/jsmith":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "name": "jsmith",
+  "emailAddress": "jsmith@example.com",
+  "id": 101,
+  "displayName": "Jane Smith",
+  "active": true,
+  "slug": "jsmith",
+  "type": "NORMAL"
+}
+`)),
+			}, nil
+		}
+		t.Errorf("unexpected request %s", r.URL.Path)
+		return nil, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.TryLogin(ctx, common.OauthContext{}, bitbucketServerURL)
+	require.NoError(t, err)
+
+	want := &vcs.UserInfo{
+		PublicEmail: "jsmith@example.com",
+		Name:        "Jane Smith",
+		State:       vcs.StateActive,
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_FetchCommitByID(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/hello/commits/def0123abcdef4567abcdef8987abcdef6543abc", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "id": "def0123abcdef4567abcdef8987abcdef6543abc",
+  "displayId": "def0123abcd",
+  "author": {
+    "name": "jsmith",
+    "emailAddress": "jsmith@example.com",
+    "displayName": "Jane Smith"
+  },
+  "authorTimestamp": 1548720847608,
+  "message": "Create users table\n\nThe users table is for the accounts.",
+  "parents": [{"id": "abcdef0123abcdef4567abcdef8987abcdef6543"}]
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchCommitByID(ctx, common.OauthContext{}, bitbucketServerURL, "PRJ/hello", "def0123abcdef4567abcdef8987abcdef6543abc")
+	require.NoError(t, err)
+
+	want := &vcs.Commit{
+		ID:          "def0123abcdef4567abcdef8987abcdef6543abc",
+		Title:       "Create users table",
+		Message:     "Create users table\n\nThe users table is for the accounts.",
+		CreatedTs:   1548720847,
+		AuthorName:  "Jane Smith",
+		AuthorEmail: "jsmith@example.com",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_GetDiffFileList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/hello/changes", r.URL.Path)
+		assert.Equal(t, "a1b2c3", r.URL.Query().Get("since"))
+		assert.Equal(t, "d4e5f6", r.URL.Query().Get("until"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"path": {"toString": "prod/001__create_users.sql"}, "type": "ADD"},
+    {"path": {"toString": "prod/000__init.sql"}, "type": "MODIFY"},
+    {"path": {"toString": "README.md"}, "type": "DELETE"},
+    {"path": {"toString": "docs/README.md"}, "type": "MOVE"}
+  ],
+  "isLastPage": true
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.GetDiffFileList(ctx, common.OauthContext{}, bitbucketServerURL, "PRJ/hello", "a1b2c3", "d4e5f6")
+	require.NoError(t, err)
+
+	want := []vcs.FileDiff{
+		{
+			Path: "prod/001__create_users.sql",
+			Type: vcs.FileDiffTypeAdded,
+		},
+		{
+			Path: "prod/000__init.sql",
+			Type: vcs.FileDiffTypeModified,
+		},
+		{
+			Path: "README.md",
+			Type: vcs.FileDiffTypeRemoved,
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_FetchAllRepositoryList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/repos", r.URL.Path)
+		assert.Equal(t, "REPO_ADMIN", r.URL.Query().Get("permission"))
+		if r.URL.Query().Get("start") == "0" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {
+      "id": 1,
+      "slug": "hello",
+      "name": "Hello",
+      "project": {"key": "PRJ", "name": "Project"},
+      "links": {"self": [{"href": "https://bitbucket.example.com/projects/PRJ/repos/hello/browse"}]}
+    }
+  ],
+  "isLastPage": false,
+  "nextPageStart": 1
+}
+`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {
+      "id": 2,
+      "slug": "world",
+      "name": "World",
+      "project": {"key": "PRJ", "name": "Project"},
+      "links": {"self": [{"href": "https://bitbucket.example.com/projects/PRJ/repos/world/browse"}]}
+    }
+  ],
+  "isLastPage": true
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchAllRepositoryList(ctx, common.OauthContext{}, bitbucketServerURL)
+	require.NoError(t, err)
+
+	want := []*vcs.Repository{
+		{
+			ID:       "PRJ/hello",
+			Name:     "Hello",
+			FullPath: "PRJ/hello",
+			WebURL:   "https://bitbucket.example.com/projects/PRJ/repos/hello",
+		},
+		{
+			ID:       "PRJ/world",
+			Name:     "World",
+			FullPath: "PRJ/world",
+			WebURL:   "https://bitbucket.example.com/projects/PRJ/repos/world",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_FetchRepositoryFileList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/hello/files/prod", r.URL.Path)
+		assert.Equal(t, "main", r.URL.Query().Get("at"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": ["001__create_users.sql", "sub/002__create_orders.sql"],
+  "isLastPage": true
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchRepositoryFileList(ctx, common.OauthContext{}, bitbucketServerURL, "PRJ/hello", "main", "prod")
+	require.NoError(t, err)
+
+	want := []*vcs.RepositoryTreeNode{
+		{
+			Path: "prod/001__create_users.sql",
+			Type: "blob",
+		},
+		{
+			Path: "prod/sub/002__create_orders.sql",
+			Type: "blob",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_OverwriteFile(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/hello/browse/prod/001__create_users.sql", r.URL.Path)
+		assert.Equal(t, "no-check", r.Header.Get("X-Atlassian-Token"))
+
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "CREATE TABLE users (id INT);", r.FormValue("content"))
+		assert.Equal(t, "Update users table", r.FormValue("message"))
+		assert.Equal(t, "main", r.FormValue("branch"))
+		assert.Equal(t, "a1b2c3", r.FormValue("sourceCommitId"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("{}")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.OverwriteFile(
+		ctx,
+		common.OauthContext{},
+		bitbucketServerURL,
+		"PRJ/hello",
+		"prod/001__create_users.sql",
+		vcs.FileCommitCreate{
+			Branch:        "main",
+			Content:       "CREATE TABLE users (id INT);",
+			CommitMessage: "Update users table",
+			LastCommitID:  "a1b2c3",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestProvider_ReadFileMeta(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/hello/commits", r.URL.Path)
+		assert.Equal(t, "prod/001__create_users.sql", r.URL.Query().Get("path"))
+		assert.Equal(t, "main", r.URL.Query().Get("until"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [{"id": "a1b2c3", "displayId": "a1b2c3"}],
+  "isLastPage": false
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ReadFileMeta(ctx, common.OauthContext{}, bitbucketServerURL, "PRJ/hello", "prod/001__create_users.sql", "main")
+	require.NoError(t, err)
+
+	want := &vcs.FileMeta{
+		Name:         "001__create_users.sql",
+		Path:         "prod/001__create_users.sql",
+		LastCommitID: "a1b2c3",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_GetBranch(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/hello/branches", r.URL.Path)
+		assert.Equal(t, "main", r.URL.Query().Get("filterText"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"id": "refs/heads/maintenance", "displayId": "maintenance", "latestCommit": "d4e5f6"},
+    {"id": "refs/heads/main", "displayId": "main", "latestCommit": "a1b2c3"}
+  ],
+  "isLastPage": true
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.GetBranch(ctx, common.OauthContext{}, bitbucketServerURL, "PRJ/hello", "main")
+	require.NoError(t, err)
+
+	want := &vcs.BranchInfo{
+		Name:         "main",
+		LastCommitID: "a1b2c3",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_ListPullRequestFile(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PRJ/repos/hello/pull-requests/7":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "id": 7,
+  "title": "Create users table",
+  "fromRef": {"id": "refs/heads/feature", "displayId": "feature", "latestCommit": "d4e5f6"},
+  "toRef": {"id": "refs/heads/main", "displayId": "main", "latestCommit": "a1b2c3"}
+}
+`)),
+			}, nil
+		case "/rest/api/1.0/projects/PRJ/repos/hello/pull-requests/7/changes":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "values": [
+    {"path": {"toString": "prod/001__create_users.sql"}, "type": "ADD"},
+    {"path": {"toString": "prod/000__init.sql"}, "type": "DELETE"}
+  ],
+  "isLastPage": true
+}
+`)),
+			}, nil
+		}
+		t.Errorf("unexpected request %s", r.URL.Path)
+		return nil, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ListPullRequestFile(ctx, common.OauthContext{}, bitbucketServerURL, "PRJ/hello", "7")
+	require.NoError(t, err)
+
+	want := []*vcs.PullRequestFile{
+		{
+			Path:         "prod/001__create_users.sql",
+			LastCommitID: "d4e5f6",
+			IsDeleted:    false,
+		},
+		{
+			Path:         "prod/000__init.sql",
+			LastCommitID: "d4e5f6",
+			IsDeleted:    true,
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_CreatePullRequest(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/hello/pull-requests", r.URL.Path)
+
+		var create PullRequestCreate
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&create))
+		want := PullRequestCreate{
+			Title:       "Create users table",
+			Description: "Created by Bytebase",
+			FromRef:     PullRequestRef{ID: "refs/heads/feature"},
+			ToRef:       PullRequestRef{ID: "refs/heads/main"},
+		}
+		assert.Equal(t, want, create)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "id": 8,
+  "links": {"self": [{"href": "https://bitbucket.example.com/projects/PRJ/repos/hello/pull-requests/8"}]}
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.CreatePullRequest(ctx, common.OauthContext{}, bitbucketServerURL, "PRJ/hello",
+		&vcs.PullRequestCreate{
+			Title: "Create users table",
+			Body:  "Created by Bytebase",
+			Head:  "feature",
+			Base:  "main",
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "https://bitbucket.example.com/projects/PRJ/repos/hello/pull-requests/8", got.URL)
+}
+
+func TestProvider_CreatePullRequestComment(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/hello/pull-requests/7/comments", r.URL.Path)
+
+		var comment map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+		assert.Equal(t, "No problems found.", comment["text"])
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 1}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.(*Provider).CreatePullRequestComment(ctx, common.OauthContext{}, bitbucketServerURL, "PRJ/hello", "7", "No problems found.")
+	require.NoError(t, err)
+}
+
+func TestProvider_CreateWebhook(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/hello/webhooks", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 12, "name": "Bytebase", "active": true}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.CreateWebhook(ctx, common.OauthContext{}, bitbucketServerURL, "PRJ/hello", []byte(""))
+	require.NoError(t, err)
+	assert.Equal(t, "12", got)
+}
+
+func TestProvider_DeleteWebhook(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/rest/api/1.0/projects/PRJ/repos/hello/webhooks/12", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.DeleteWebhook(ctx, common.OauthContext{}, bitbucketServerURL, "PRJ/hello", "12")
+	require.NoError(t, err)
+}
+
+func newMockProvider(mockRoundTrip func(r *http.Request) (*http.Response, error)) vcs.Provider {
+	return newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: mockRoundTrip,
+				},
+			},
+		},
+	)
+}
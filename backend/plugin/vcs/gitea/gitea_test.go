@@ -0,0 +1,555 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/internal/oauth"
+)
+
+const giteaURL = "https://gitea.example.com"
+
+func TestProvider_ExchangeOAuthToken(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/login/oauth/access_token", r.URL.Path)
+
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "test_client_id", r.PostForm.Get("client_id"))
+		assert.Equal(t, "test_code", r.PostForm.Get("code"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "access_token": "eyJhbGciOiJSUzI1NiIsImtpZCI6In0.access",
+  "token_type": "bearer",
+  "expires_in": 3600,
+  "refresh_token": "eyJhbGciOiJSUzI1NiIsImtpZCI6In0.refresh"
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ExchangeOAuthToken(ctx, giteaURL,
+		&common.OAuthExchange{
+			ClientID:     "test_client_id",
+			ClientSecret: "test_client_secret",
+			Code:         "test_code",
+			RedirectURL:  "http://localhost:3000",
+		},
+	)
+	require.NoError(t, err)
+
+	// We use time.Now() to compute the values of CreatedAt and ExpiresTs so there
+	// is no point to assert.
+	got.CreatedAt = 0
+	got.ExpiresTs = 0
+
+	want := &vcs.OAuthToken{
+		AccessToken:  "eyJhbGciOiJSUzI1NiIsImtpZCI6In0.access",
+		RefreshToken: "eyJhbGciOiJSUzI1NiIsImtpZCI6In0.refresh",
+		ExpiresIn:    3600,
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_FetchCommitByID(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/hello/git/commits/7638417", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "sha": "7638417db6d59f3c431d3e1f261cc637155684cd",
+  "html_url": "https://gitea.example.com/octocat/hello/commit/7638417db6d59f3c431d3e1f261cc637155684cd",
+  "commit": {
+    "message": "Create users table",
+    "author": {
+      "name": "Octocat",
+      "email": "octocat@example.com",
+      "date": "2023-10-14T09:00:00Z"
+    }
+  }
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchCommitByID(ctx, common.OauthContext{}, giteaURL, "octocat/hello", "7638417")
+	require.NoError(t, err)
+
+	want := &vcs.Commit{
+		ID:          "7638417db6d59f3c431d3e1f261cc637155684cd",
+		AuthorName:  "Octocat",
+		AuthorEmail: "octocat@example.com",
+		CreatedTs:   1697274000,
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_GetDiffFileList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/hello/compare/a1b2c3...d4e5f6", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "total_commits": 2,
+  "commits": [
+    {
+      "sha": "b2c3d4",
+      "files": [
+        {"filename": "prod/001__create_users.sql", "status": "added"},
+        {"filename": "prod/000__init.sql", "status": "modified"}
+      ]
+    },
+    {
+      "sha": "d4e5f6",
+      "files": [
+        {"filename": "prod/001__create_users.sql", "status": "modified"},
+        {"filename": "README.md", "status": "removed"}
+      ]
+    }
+  ]
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.GetDiffFileList(ctx, common.OauthContext{}, giteaURL, "octocat/hello", "a1b2c3", "d4e5f6")
+	require.NoError(t, err)
+
+	want := []vcs.FileDiff{
+		{Path: "prod/001__create_users.sql", Type: vcs.FileDiffTypeAdded},
+		{Path: "prod/000__init.sql", Type: vcs.FileDiffTypeModified},
+		{Path: "README.md", Type: vcs.FileDiffTypeRemoved},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_FetchAllRepositoryList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/user/repos", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("page"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+[
+  {
+    "id": 1,
+    "name": "hello",
+    "full_name": "octocat/hello",
+    "html_url": "https://gitea.example.com/octocat/hello",
+    "permissions": {"admin": true, "push": true, "pull": true}
+  },
+  {
+    "id": 2,
+    "name": "readonly",
+    "full_name": "octocat/readonly",
+    "html_url": "https://gitea.example.com/octocat/readonly",
+    "permissions": {"admin": false, "push": false, "pull": true}
+  }
+]
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchAllRepositoryList(ctx, common.OauthContext{}, giteaURL)
+	require.NoError(t, err)
+
+	want := []*vcs.Repository{
+		{
+			ID:       "octocat/hello",
+			Name:     "hello",
+			FullPath: "octocat/hello",
+			WebURL:   "https://gitea.example.com/octocat/hello",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_FetchRepositoryFileList(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/hello/git/trees/main", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("recursive"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "tree": [
+    {"path": "README.md", "type": "blob", "sha": "a1"},
+    {"path": "prod", "type": "tree", "sha": "b2"},
+    {"path": "prod/001__create_users.sql", "type": "blob", "sha": "c3"}
+  ],
+  "truncated": false
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.FetchRepositoryFileList(ctx, common.OauthContext{}, giteaURL, "octocat/hello", "main", "prod")
+	require.NoError(t, err)
+
+	want := []*vcs.RepositoryTreeNode{
+		{
+			Path: "prod/001__create_users.sql",
+			Type: "blob",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_CreateFile(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/repos/octocat/hello/contents/prod/001__create_users.sql", r.URL.Path)
+
+		var commit FileCommit
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&commit))
+		want := FileCommit{
+			Content: "Q1JFQVRFIFRBQkxFIHVzZXJzIChpZCBJTlQpOw==",
+			Message: "Create users table",
+			Branch:  "main",
+			Author: FileCommitIdentity{
+				Name:  vcs.BytebaseAuthorName,
+				Email: vcs.BytebaseAuthorEmail,
+			},
+		}
+		assert.Equal(t, want, commit)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreateFile(
+		ctx,
+		common.OauthContext{},
+		giteaURL,
+		"octocat/hello",
+		"prod/001__create_users.sql",
+		vcs.FileCommitCreate{
+			Branch:        "main",
+			Content:       "CREATE TABLE users (id INT);",
+			CommitMessage: "Create users table",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestProvider_OverwriteFile(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v1/repos/octocat/hello/contents/prod/001__create_users.sql", r.URL.Path)
+
+		var commit FileCommit
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&commit))
+		assert.Equal(t, "3d21ec53a331a6f037a91c368710b99387d012c1", commit.SHA)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.OverwriteFile(
+		ctx,
+		common.OauthContext{},
+		giteaURL,
+		"octocat/hello",
+		"prod/001__create_users.sql",
+		vcs.FileCommitCreate{
+			Branch:        "main",
+			Content:       "CREATE TABLE users (id INT);",
+			CommitMessage: "Update users table",
+			SHA:           "3d21ec53a331a6f037a91c368710b99387d012c1",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestProvider_ReadFileMeta(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/hello/contents/prod/001__create_users.sql", r.URL.Path)
+		assert.Equal(t, "main", r.URL.Query().Get("ref"))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "name": "001__create_users.sql",
+  "path": "prod/001__create_users.sql",
+  "sha": "3d21ec53a331a6f037a91c368710b99387d012c1",
+  "last_commit_sha": "7638417db6d59f3c431d3e1f261cc637155684cd",
+  "type": "file",
+  "size": 28
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ReadFileMeta(ctx, common.OauthContext{}, giteaURL, "octocat/hello", "prod/001__create_users.sql", "main")
+	require.NoError(t, err)
+
+	want := &vcs.FileMeta{
+		Name:         "001__create_users.sql",
+		Path:         "prod/001__create_users.sql",
+		Size:         28,
+		LastCommitID: "7638417db6d59f3c431d3e1f261cc637155684cd",
+		SHA:          "3d21ec53a331a6f037a91c368710b99387d012c1",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_ReadFileContent(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/hello/raw/prod/001__create_users.sql", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("CREATE TABLE users (id INT);")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ReadFileContent(ctx, common.OauthContext{}, giteaURL, "octocat/hello", "prod/001__create_users.sql", "main")
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE users (id INT);", got)
+}
+
+func TestProvider_GetBranch(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "/api/v1/repos/octocat/hello/branches/main", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`
+{
+  "name": "main",
+  "commit": {
+    "id": "7638417db6d59f3c431d3e1f261cc637155684cd",
+    "message": "Create users table"
+  }
+}
+`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.GetBranch(ctx, common.OauthContext{}, giteaURL, "octocat/hello", "main")
+	require.NoError(t, err)
+
+	want := &vcs.BranchInfo{
+		Name:         "main",
+		LastCommitID: "7638417db6d59f3c431d3e1f261cc637155684cd",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_CreateBranch(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/repos/octocat/hello/branches", r.URL.Path)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"new_branch_name":"bytebase-schema","old_ref_name":"7638417db6d59f3c431d3e1f261cc637155684cd"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.CreateBranch(ctx, common.OauthContext{}, giteaURL, "octocat/hello",
+		&vcs.BranchInfo{
+			Name:         "bytebase-schema",
+			LastCommitID: "7638417db6d59f3c431d3e1f261cc637155684cd",
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestProvider_ListPullRequestFile(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/api/v1/repos/octocat/hello/pulls/7":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+{
+  "number": 7,
+  "html_url": "https://gitea.example.com/octocat/hello/pulls/7",
+  "head": {"ref": "feature", "sha": "d4e5f6"}
+}
+`)),
+			}, nil
+		case "/api/v1/repos/octocat/hello/pulls/7/files":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`
+[
+  {"filename": "prod/001__create_users.sql", "status": "added"},
+  {"filename": "prod/000__init.sql", "status": "deleted"}
+]
+`)),
+			}, nil
+		}
+		t.Errorf("unexpected request %s", r.URL.Path)
+		return nil, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.ListPullRequestFile(ctx, common.OauthContext{}, giteaURL, "octocat/hello", "7")
+	require.NoError(t, err)
+
+	want := []*vcs.PullRequestFile{
+		{
+			Path:         "prod/001__create_users.sql",
+			LastCommitID: "d4e5f6",
+			IsDeleted:    false,
+		},
+		{
+			Path:         "prod/000__init.sql",
+			LastCommitID: "d4e5f6",
+			IsDeleted:    true,
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestProvider_CreateWebhook(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/repos/octocat/hello/hooks", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(strings.NewReader(`{"id": 12, "type": "gitea", "active": true}`)),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	got, err := p.CreateWebhook(ctx, common.OauthContext{}, giteaURL, "octocat/hello", []byte(""))
+	require.NoError(t, err)
+	assert.Equal(t, "12", got)
+}
+
+func TestProvider_PatchWebhook(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/api/v1/repos/octocat/hello/hooks/12", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.PatchWebhook(ctx, common.OauthContext{}, giteaURL, "octocat/hello", "12", []byte(""))
+	require.NoError(t, err)
+}
+
+func TestProvider_DeleteWebhook(t *testing.T) {
+	p := newMockProvider(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/v1/repos/octocat/hello/hooks/12", r.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	},
+	)
+
+	ctx := context.Background()
+	err := p.DeleteWebhook(ctx, common.OauthContext{}, giteaURL, "octocat/hello", "12")
+	require.NoError(t, err)
+}
+
+func TestOAuth_RefreshToken(t *testing.T) {
+	ctx := context.Background()
+	client := &http.Client{
+		Transport: &common.MockRoundTripper{
+			MockRoundTrip: func(r *http.Request) (*http.Response, error) {
+				token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+				if token == "expired" {
+					return &http.Response{
+						StatusCode: http.StatusUnauthorized,
+						Body:       io.NopCloser(strings.NewReader(`{"message":"token is required"}`)),
+					}, nil
+				}
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body: io.NopCloser(strings.NewReader(`
+{
+  "access_token": "eyJhbGciOiJSUzI1NiIsImtpZCI6In0.refreshed",
+  "expires_in": 3600,
+  "refresh_token": "eyJhbGciOiJSUzI1NiIsImtpZCI6In0.refresh"
+}
+`)),
+				}, nil
+			},
+		},
+	}
+	token := "expired"
+
+	calledRefresher := false
+	refresher := func(_, _ string, _ int64) error {
+		calledRefresher = true
+		return nil
+	}
+
+	_, _, _, err := oauth.Get(
+		ctx,
+		client,
+		giteaURL+"/api/v1/user",
+		&token,
+		tokenRefresher(
+			giteaURL,
+			oauthContext{},
+			refresher,
+		),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "eyJhbGciOiJSUzI1NiIsImtpZCI6In0.refreshed", token)
+	assert.True(t, calledRefresher)
+}
+
+func newMockProvider(mockRoundTrip func(r *http.Request) (*http.Response, error)) vcs.Provider {
+	return newProvider(
+		vcs.ProviderConfig{
+			Client: &http.Client{
+				Transport: &common.MockRoundTripper{
+					MockRoundTrip: mockRoundTrip,
+				},
+			},
+		},
+	)
+}
@@ -201,6 +201,9 @@ type WebhookPushEvent struct {
 	Repository WebhookRepository `json:"repository"`
 	Sender     WebhookSender     `json:"sender"`
 	Commits    []WebhookCommit   `json:"commits"`
+	// HeadCommit is the commit the ref points to after the push, it's nil if the ref is deleted.
+	HeadCommit *WebhookCommit `json:"head_commit"`
+	Deleted    bool           `json:"deleted"`
 }
 
 // fetchUserInfoImpl fetches user information from the given resourceURI, which
@@ -21,3 +21,24 @@ const (
 func SetupSQLReviewCI(endpoint string) string {
 	return fmt.Sprintf(sqlReviewAction, endpoint, vcs.SQLReviewAPISecretName)
 }
+
+// CheckRunAnnotationLevel is the level of the check run annotation.
+type CheckRunAnnotationLevel string
+
+const (
+	// CheckRunAnnotationLevelFailure is the failure annotation level.
+	CheckRunAnnotationLevelFailure CheckRunAnnotationLevel = "failure"
+	// CheckRunAnnotationLevelWarning is the warning annotation level.
+	CheckRunAnnotationLevelWarning CheckRunAnnotationLevel = "warning"
+)
+
+// CheckRunAnnotation is the inline annotation on the file of the check run.
+// Docs: https://docs.github.com/en/rest/checks/runs#create-a-check-run
+type CheckRunAnnotation struct {
+	Path            string                  `json:"path"`
+	StartLine       int                     `json:"start_line"`
+	EndLine         int                     `json:"end_line"`
+	AnnotationLevel CheckRunAnnotationLevel `json:"annotation_level"`
+	Title           string                  `json:"title"`
+	Message         string                  `json:"message"`
+}
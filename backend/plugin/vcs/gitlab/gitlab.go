@@ -38,6 +38,8 @@ type WebhookType string
 const (
 	// WebhookPush is the webhook type for push.
 	WebhookPush WebhookType = "push"
+	// WebhookTagPush is the webhook type for tag push.
+	WebhookTagPush WebhookType = "tag_push"
 )
 
 // WebhookInfo represents a GitLab API response for the webhook information.
@@ -51,6 +53,8 @@ type WebhookCreate struct {
 	SecretToken string `json:"token"`
 	// This is set to true
 	PushEvents bool `json:"push_events"`
+	// This is set to true, the tag pushes build the releases for the repositories in the release mode.
+	TagPushEvents bool `json:"tag_push_events"`
 	// For now, there is no native dry run DDL support in mysql/postgres. One may wonder if we could wrap the DDL
 	// in a transaction and just not commit at the end, unfortunately there are side effects which are hard to control.
 	// See https://www.postgresql.org/message-id/CAMsr%2BYGiYQ7PYvYR2Voio37YdCpp79j5S%2BcmgVJMOLM2LnRQcA%40mail.gmail.com
@@ -94,6 +98,10 @@ type WebhookPushEvent struct {
 	AuthorName string          `json:"user_name"`
 	Project    WebhookProject  `json:"project"`
 	CommitList []WebhookCommit `json:"commits"`
+	// AuthorEmail and CheckoutSHA are used by the tag push event, the CheckoutSHA is the tagged commit and it's empty
+	// if the tag is deleted.
+	AuthorEmail string `json:"user_email"`
+	CheckoutSHA string `json:"checkout_sha"`
 }
 
 // Commit is the API message for commit.
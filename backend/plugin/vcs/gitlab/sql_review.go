@@ -67,3 +67,34 @@ func findSQLReviewCI(include []any) (map[string]any, bool) {
 
 	return nil, false
 }
+
+// CodeQualitySeverity is the severity of the code quality issue.
+type CodeQualitySeverity string
+
+const (
+	// CodeQualitySeverityCritical is the critical severity.
+	CodeQualitySeverityCritical CodeQualitySeverity = "critical"
+	// CodeQualitySeverityMinor is the minor severity.
+	CodeQualitySeverityMinor CodeQualitySeverity = "minor"
+)
+
+// CodeQualityIssue is the issue in the code quality report, which is shown inline in the merge request diff.
+// Docs: https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool
+type CodeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    CodeQualitySeverity `json:"severity"`
+	Location    CodeQualityLocation `json:"location"`
+}
+
+// CodeQualityLocation is the location of the code quality issue.
+type CodeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines CodeQualityLines `json:"lines"`
+}
+
+// CodeQualityLines is the lines of the code quality issue.
+type CodeQualityLines struct {
+	Begin int `json:"begin"`
+}
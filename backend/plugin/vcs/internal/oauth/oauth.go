@@ -79,6 +79,13 @@ func Put(ctx context.Context, client *http.Client, url string, token *string, bo
 	return retry(ctx, client, token, tokenRefresher, requester(ctx, client, http.MethodPut, url, token, body))
 }
 
+// PutWithHeader makes a HTTP PUT request to the given URL using the token and
+// additional header. It refreshes token and retries the request in the case of
+// the token has expired.
+func PutWithHeader(ctx context.Context, client *http.Client, url string, token *string, body io.Reader, tokenRefresher TokenRefresher, header map[string]string) (code int, _ http.Header, respBody string, err error) {
+	return retry(ctx, client, token, tokenRefresher, requesterWithHeader(ctx, client, http.MethodPut, url, token, body, header))
+}
+
 // Patch makes a HTTP PATCH request to the given URL using the token. It
 // refreshes token and retries the request in the case of the token has expired.
 func Patch(ctx context.Context, client *http.Client, url string, token *string, body io.Reader, tokenRefresher TokenRefresher) (code int, header http.Header, respBody string, err error) {
@@ -152,6 +159,11 @@ func getOAuthErrorDetails(code int, body []byte) error {
 	if bytes.Contains(body, []byte("OAuth2 access token expired.")) {
 		return &oauthError{}
 	}
+	// Special case for Gitea OAuth error, the expired access token is ignored and
+	// the request is rejected as unauthenticated.
+	if code == http.StatusUnauthorized && bytes.Contains(body, []byte(`"message":"token is required"`)) {
+		return &oauthError{}
+	}
 
 	var oe oauthError
 	if err := json.Unmarshal(body, &oe); err != nil {
@@ -24,6 +24,10 @@ const (
 	GitHub Type = "GITHUB"
 	// Bitbucket is the VCS type for Bitbucket Cloud (bitbucket.org).
 	Bitbucket Type = "BITBUCKET"
+	// BitbucketServer is the VCS type for self-hosted Bitbucket Server and Data Center.
+	BitbucketServer Type = "BITBUCKET_SERVER"
+	// Gitea is the VCS type for self-hosted Gitea, which covers its fork Forgejo as well.
+	Gitea Type = "GITEA"
 
 	// SQLReviewAPISecretName is the api secret name used in GitHub action or GitLab CI workflow.
 	SQLReviewAPISecretName = "SQL_REVIEW_API_SECRET"
@@ -313,6 +317,19 @@ type Provider interface {
 	DeleteWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, webhookID string) error
 }
 
+// PullRequestCommenter is the interface for the VCS providers that report the
+// SQL review results as pull request comments instead of in the CI.
+type PullRequestCommenter interface {
+	// CreatePullRequestComment creates a comment on the pull request.
+	//
+	// oauthCtx: OAuth context to create the comment
+	// instanceURL: VCS instance URL
+	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
+	// pullRequestID: the pull request id
+	// content: the comment content in markdown
+	CreatePullRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL, repositoryID, pullRequestID, content string) error
+}
+
 var (
 	providerMu sync.RWMutex
 	providers  = make(map[Type]providerFunc)
@@ -425,3 +442,32 @@ func isMultipleTimesAsteriskInTemplateValid(pathTemplate string, asteriskTimes i
 	}
 	return nil
 }
+
+// MatchPathPattern returns true if the file path matches the glob pattern, where "*" matches any characters except "/"
+// and "**" matches any number of path segments.
+func MatchPathPattern(pattern, filePath string) (bool, error) {
+	var regex strings.Builder
+	regex.WriteString("^")
+	for i := 0; i < len(pattern); {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			regex.WriteString("(.*/)?")
+			i += 3
+		case strings.HasPrefix(pattern[i:], "**"):
+			regex.WriteString(".*")
+			i += 2
+		case pattern[i] == '*':
+			regex.WriteString("[^/]*")
+			i++
+		default:
+			regex.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			i++
+		}
+	}
+	regex.WriteString("$")
+	re, err := regexp.Compile(regex.String())
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid path pattern %q", pattern)
+	}
+	return re.MatchString(filePath), nil
+}
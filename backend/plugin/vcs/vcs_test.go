@@ -46,3 +46,48 @@ func TestIsDoubleTimesAsteriskInTemplateValid(t *testing.T) {
 		}
 	}
 }
+
+func TestMatchPathPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		filePath string
+		want     bool
+	}{
+		{
+			pattern:  "services/payment/**",
+			filePath: "services/payment/db/prod/payment##0001##migrate##init.sql",
+			want:     true,
+		},
+		{
+			pattern:  "services/*/migrations/*.sql",
+			filePath: "services/order/migrations/order##0001##migrate##init.sql",
+			want:     true,
+		},
+		{
+			pattern:  "services/*/migrations/*.sql",
+			filePath: "services/order/v1/migrations/order##0001##migrate##init.sql",
+			want:     false,
+		},
+		{
+			pattern:  "**/order##*.sql",
+			filePath: "order##0001##migrate##init.sql",
+			want:     true,
+		},
+		{
+			pattern:  "services/payment/**",
+			filePath: "services/payments/payment##0001##migrate##init.sql",
+			want:     false,
+		},
+		{
+			pattern:  "services/payment.v1/*",
+			filePath: "services/paymentxv1/payment##0001##migrate##init.sql",
+			want:     false,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := MatchPathPattern(test.pattern, test.filePath)
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, got, test.pattern)
+	}
+}
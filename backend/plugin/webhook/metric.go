@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	deliveryTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "bytebase",
+			Subsystem: "webhook",
+			Name:      "delivery_total",
+			Help:      "The number of the attempts delivering the webhook events, by the webhook type and the result.",
+		},
+		[]string{"type", "result"},
+	)
+	deliveryLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "bytebase",
+			Subsystem: "webhook",
+			Name:      "delivery_duration_seconds",
+			Help:      "The latency of delivering the webhook events.",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2, 3, 5},
+		},
+		[]string{"type"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(deliveryTotal, deliveryLatency)
+}
+
+func observeDelivery(webhookType string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	deliveryTotal.WithLabelValues(webhookType, result).Inc()
+	deliveryLatency.WithLabelValues(webhookType).Observe(duration.Seconds())
+}
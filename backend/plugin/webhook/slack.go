@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/pkg/errors"
 )
@@ -24,11 +25,19 @@ type SlackWebhookElementButton struct {
 
 // SlackWebhookElement is the API message for Slack webhook element.
 type SlackWebhookElement struct {
-	Type   string                    `json:"type"`
-	Button SlackWebhookElementButton `json:"text,omitempty"`
-	URL    string                    `json:"url,omitempty"`
+	Type     string                    `json:"type"`
+	Button   SlackWebhookElementButton `json:"text,omitempty"`
+	URL      string                    `json:"url,omitempty"`
+	ActionID string                    `json:"action_id,omitempty"`
+	Value    string                    `json:"value,omitempty"`
+	Style    string                    `json:"style,omitempty"`
 }
 
+const (
+	// SlackActionApprove is the action ID of the approve button, the value is the issue ID.
+	SlackActionApprove = "bb.issue.approve"
+)
+
 // SlackWebhookBlock is the API message for Slack webhook block.
 type SlackWebhookBlock struct {
 	Type        string                     `json:"type"`
@@ -98,18 +107,32 @@ func (*SlackReceiver) post(context Context) error {
 		},
 	})
 
-	blockList = append(blockList, SlackWebhookBlock{
-		Type: "actions",
-		ElementList: []SlackWebhookElement{
-			{
-				Type: "button",
-				Button: SlackWebhookElementButton{
-					Type: "plain_text",
-					Text: "View in Bytebase",
-				},
-				URL: context.Link,
+	elementList := []SlackWebhookElement{
+		{
+			Type: "button",
+			Button: SlackWebhookElementButton{
+				Type: "plain_text",
+				Text: "View in Bytebase",
 			},
+			URL: context.Link,
 		},
+	}
+	// The button clicks are posted to the interactivity request URL of the Slack app.
+	if context.ApprovalActions && context.Issue != nil {
+		elementList = append(elementList, SlackWebhookElement{
+			Type: "button",
+			Button: SlackWebhookElementButton{
+				Type: "plain_text",
+				Text: "Approve",
+			},
+			ActionID: SlackActionApprove,
+			Value:    strconv.Itoa(context.Issue.ID),
+			Style:    "primary",
+		})
+	}
+	blockList = append(blockList, SlackWebhookBlock{
+		Type:        "actions",
+		ElementList: elementList,
 	})
 
 	post := SlackWebhook{
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultPayloadTemplateKey is the key of the payload template for the activity types without their own templates.
+const DefaultPayloadTemplateKey = "*"
+
+var payloadTemplateFuncs = template.FuncMap{
+	// json encodes the value as JSON, e.g. to quote the strings in the payload.
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	},
+}
+
+// ParsePayloadTemplate parses the Go template of the webhook payload, which is executed with the webhook Context.
+func ParsePayloadTemplate(payloadTemplate string) (*template.Template, error) {
+	return template.New("payload").Funcs(payloadTemplateFuncs).Parse(payloadTemplate)
+}
+
+// GetPayloadTemplate returns the payload template for the activity type, or the default payload template if the
+// activity type has no template. Empty means posting the message of the webhook type.
+func GetPayloadTemplate(payloadTemplates map[string]string, activityType string) string {
+	if v, ok := payloadTemplates[activityType]; ok {
+		return v
+	}
+	return payloadTemplates[DefaultPayloadTemplateKey]
+}
+
+// renderPayloadTemplate renders the payload template of the context, the result must be a JSON document.
+func renderPayloadTemplate(context Context) ([]byte, error) {
+	tmpl, err := ParsePayloadTemplate(context.PayloadTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse payload template")
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, context); err != nil {
+		return nil, errors.Wrap(err, "failed to execute payload template")
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, errors.Errorf("payload template result is not valid JSON: %s", buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+// postPayloadTemplate posts the rendered payload template instead of the message of the webhook type. Any 2xx status
+// is accepted as the receivers are not the messaging platforms.
+func postPayloadTemplate(context Context) error {
+	body, err := renderPayloadTemplate(context)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render webhook payload to %s", context.URL)
+	}
+	req, err := http.NewRequest("POST", context.URL, bytes.NewBuffer(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct webhook POST request to %s", context.URL)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{
+		Timeout: timeout,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to POST webhook to %s", context.URL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read POST webhook response from %s", context.URL)
+		}
+		return errors.Errorf("failed to POST webhook %s, status code: %d, response body: %s", context.URL, resp.StatusCode, b)
+	}
+	return nil
+}
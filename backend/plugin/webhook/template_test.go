@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPayloadTemplate(t *testing.T) {
+	a := require.New(t)
+	context := Context{
+		Level:        WebhookWarn,
+		ActivityType: "bb.issue.status.update",
+		Title:        `Issue "hello" canceled`,
+		Issue:        &Issue{ID: 101, Name: "hello"},
+	}
+
+	context.PayloadTemplate = `{"severity":{{json .Level}},"summary":{{json .Title}}{{if .Issue}},"issueId":{{.Issue.ID}}{{end}}}`
+	got, err := renderPayloadTemplate(context)
+	a.NoError(err)
+	a.JSONEq(`{"severity":"WARN","summary":"Issue \"hello\" canceled","issueId":101}`, string(got))
+
+	// The result must be a JSON document.
+	context.PayloadTemplate = `summary: {{.Title}}`
+	_, err = renderPayloadTemplate(context)
+	a.Error(err)
+
+	_, err = ParsePayloadTemplate(`{"summary":{{json .Title}`)
+	a.Error(err)
+}
+
+func TestGetPayloadTemplate(t *testing.T) {
+	a := require.New(t)
+	payloadTemplates := map[string]string{
+		"bb.issue.create":         `{"event":"create"}`,
+		DefaultPayloadTemplateKey: `{"event":"other"}`,
+	}
+	a.Equal(`{"event":"create"}`, GetPayloadTemplate(payloadTemplates, "bb.issue.create"))
+	a.Equal(`{"event":"other"}`, GetPayloadTemplate(payloadTemplates, "bb.issue.status.update"))
+	a.Equal("", GetPayloadTemplate(map[string]string{"bb.issue.create": `{}`}, "bb.issue.status.update"))
+	a.Equal("", GetPayloadTemplate(nil, "bb.issue.create"))
+}
@@ -77,6 +77,12 @@ type Context struct {
 	Issue        *Issue
 	Project      *Project
 	TaskResult   *TaskResult
+	// ApprovalActions adds the approve action for the receivers supporting the interactive messages, i.e.
+	// Slack with the Bytebase Slack app.
+	ApprovalActions bool
+	// PayloadTemplate is the Go template of the JSON payload posted instead of the message of the webhook type if not
+	// empty.
+	PayloadTemplate string
 }
 
 // Receiver is the webhook receiver.
@@ -158,5 +164,13 @@ func Post(webhookType string, context Context) error {
 	if !ok {
 		return errors.Errorf("webhook: no applicable receiver for webhook type: %v", webhookType)
 	}
-	return r.post(context)
+	startTime := time.Now()
+	var err error
+	if context.PayloadTemplate != "" {
+		err = postPayloadTemplate(context)
+	} else {
+		err = r.post(context)
+	}
+	observeDelivery(webhookType, time.Since(startTime), err)
+	return err
 }
@@ -0,0 +1,295 @@
+// Package alerting is a runner that opens the incidents on the alert receivers for the failed task runs, the schema
+// drifts and the unreachable instances, and resolves them after the conditions clear.
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosimple/slug"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/alert"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const alertingInterval = 1 * time.Minute
+
+// NewAlerter creates a new alerter.
+func NewAlerter(store *store.Store) *Alerter {
+	return &Alerter{
+		store: store,
+	}
+}
+
+// Alerter is the alerter. The incidents opened on each receiver are persisted, so that they are resolved after the
+// conditions clear, even if the conditions clear while Bytebase is down. The failed triggers and resolves are sent
+// again in the next round.
+type Alerter struct {
+	store *store.Store
+}
+
+// conditionAlert is the alert of an active condition.
+type conditionAlert struct {
+	condition api.AlertCondition
+	alert     *alert.Alert
+}
+
+// Run will run the alerter.
+func (a *Alerter) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(alertingInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Alerter started and will run every %s", alertingInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Alerter received context cancellation")
+			return
+		case <-ticker.C:
+			a.check(ctx)
+		}
+	}
+}
+
+func (a *Alerter) check(ctx context.Context) {
+	settingName := api.SettingWorkspaceAlerting
+	setting, err := a.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &settingName})
+	if err != nil {
+		log.Error("Failed to get alerting setting", zap.Error(err))
+		return
+	}
+	value := &api.SettingWorkspaceAlertingValue{}
+	if setting != nil && setting.Value != "" {
+		if value, err = api.ValidateAndGetAlertingSetting(setting.Value); err != nil {
+			log.Error("Invalid alerting setting", zap.Error(err))
+			return
+		}
+	}
+	incidents, err := a.store.ListAlertIncidents(ctx)
+	if err != nil {
+		log.Error("Failed to list alert incidents", zap.Error(err))
+		return
+	}
+	if len(value.ReceiverList) == 0 && len(incidents) == 0 {
+		return
+	}
+	alerts, err := a.collectAlerts(ctx)
+	if err != nil {
+		log.Error("Failed to collect the alerts", zap.Error(err))
+		return
+	}
+
+	incidentsByReceiver := make(map[string][]*store.AlertIncidentMessage)
+	for _, incident := range incidents {
+		incidentsByReceiver[incident.ReceiverID] = append(incidentsByReceiver[incident.ReceiverID], incident)
+	}
+	for _, receiver := range value.ReceiverList {
+		receiverIncidents := incidentsByReceiver[receiver.ID]
+		delete(incidentsByReceiver, receiver.ID)
+		// The incidents of the disabled receivers are resolved after the receivers are enabled again.
+		if receiver.Disabled {
+			continue
+		}
+		triggers, resolves := reconcile(receiver, receiverIncidents, alerts)
+		if len(triggers) == 0 && len(resolves) == 0 {
+			continue
+		}
+		sender, err := alert.NewSender(receiver.Config())
+		if err != nil {
+			log.Error("Failed to create the alert sender", zap.String("receiver", receiver.ID), zap.Error(err))
+			continue
+		}
+		for _, t := range triggers {
+			if err := sender.Trigger(ctx, t); err != nil {
+				log.Warn("Failed to trigger the alert, will retry later", zap.String("receiver", receiver.ID), zap.String("dedupKey", t.DedupKey), zap.Error(err))
+				continue
+			}
+			if err := a.store.CreateAlertIncident(ctx, receiver.ID, t.DedupKey); err != nil {
+				log.Error("Failed to create the alert incident", zap.String("receiver", receiver.ID), zap.String("dedupKey", t.DedupKey), zap.Error(err))
+			}
+		}
+		for _, dedupKey := range resolves {
+			if err := sender.Resolve(ctx, dedupKey); err != nil {
+				log.Warn("Failed to resolve the alert, will retry later", zap.String("receiver", receiver.ID), zap.String("dedupKey", dedupKey), zap.Error(err))
+				continue
+			}
+			if err := a.store.DeleteAlertIncident(ctx, receiver.ID, dedupKey); err != nil {
+				log.Error("Failed to delete the alert incident", zap.String("receiver", receiver.ID), zap.String("dedupKey", dedupKey), zap.Error(err))
+			}
+		}
+	}
+	// The incidents of the removed receivers can't be resolved without the keys.
+	for receiverID, receiverIncidents := range incidentsByReceiver {
+		for _, incident := range receiverIncidents {
+			if err := a.store.DeleteAlertIncident(ctx, receiverID, incident.DedupKey); err != nil {
+				log.Error("Failed to delete the alert incident", zap.String("receiver", receiverID), zap.String("dedupKey", incident.DedupKey), zap.Error(err))
+			}
+		}
+	}
+}
+
+// reconcile returns the alerts to trigger and the dedup keys to resolve on the receiver, by the open incidents of the
+// receiver and the alerts of the active conditions.
+func reconcile(receiver *api.AlertReceiver, incidents []*store.AlertIncidentMessage, alerts map[string]*conditionAlert) ([]*alert.Alert, []string) {
+	open := make(map[string]bool)
+	var resolves []string
+	for _, incident := range incidents {
+		open[incident.DedupKey] = true
+		if a, ok := alerts[incident.DedupKey]; !ok || !receiver.HasCondition(a.condition) {
+			resolves = append(resolves, incident.DedupKey)
+		}
+	}
+	var triggers []*alert.Alert
+	for dedupKey, a := range alerts {
+		if !open[dedupKey] && receiver.HasCondition(a.condition) {
+			triggers = append(triggers, a.alert)
+		}
+	}
+	sort.Slice(triggers, func(i, j int) bool {
+		return triggers[i].DedupKey < triggers[j].DedupKey
+	})
+	return triggers, resolves
+}
+
+// collectAlerts returns the alerts of the active conditions by the dedup keys.
+func (a *Alerter) collectAlerts(ctx context.Context) (map[string]*conditionAlert, error) {
+	generalSetting, err := a.store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	externalURL := strings.TrimSuffix(generalSetting.ExternalUrl, "/")
+	alerts := make(map[string]*conditionAlert)
+
+	issues, err := a.store.ListIssueV2(ctx, &store.FindIssueMessage{
+		StatusList: []api.IssueStatus{api.IssueOpen},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		if issue.PipelineUID == 0 {
+			continue
+		}
+		tasks, err := a.store.ListTasks(ctx, &api.TaskFind{
+			PipelineID:   &issue.PipelineUID,
+			StatusList:   &[]api.TaskStatus{api.TaskFailed},
+			StripPayload: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			instance, err := a.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID, ShowDeleted: true})
+			if err != nil {
+				return nil, err
+			}
+			source := "bytebase"
+			if instance != nil {
+				source = instance.Title
+			}
+			dedupKey := fmt.Sprintf("bytebase/task/%d/failed", task.ID)
+			alerts[dedupKey] = &conditionAlert{
+				condition: api.AlertConditionTaskRunFailed,
+				alert: &alert.Alert{
+					DedupKey: dedupKey,
+					Summary:  fmt.Sprintf("Task %q of issue #%d %q failed", task.Name, issue.UID, issue.Title),
+					Source:   source,
+					Severity: alert.SeverityError,
+					Link:     fmt.Sprintf("%s/issue/%s-%d?stage=%d", externalURL, slug.Make(issue.Title), issue.UID, task.StageID),
+					Details: map[string]string{
+						"project": issue.Project.Title,
+						"issue":   fmt.Sprintf("#%d %s", issue.UID, issue.Title),
+						"task":    task.Name,
+					},
+				},
+			}
+		}
+	}
+
+	normal := api.Normal
+	anomalies, err := a.store.ListAnomalyV2(ctx, &store.ListAnomalyMessage{
+		RowStatus: &normal,
+		Types:     []api.AnomalyType{api.AnomalyDatabaseSchemaDrift, api.AnomalyInstanceConnection},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, anomaly := range anomalies {
+		switch anomaly.Type {
+		case api.AnomalyDatabaseSchemaDrift:
+			if anomaly.DatabaseUID == nil {
+				continue
+			}
+			database, err := a.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: anomaly.DatabaseUID})
+			if err != nil {
+				return nil, err
+			}
+			if database == nil {
+				continue
+			}
+			details := map[string]string{
+				"project":     database.ProjectID,
+				"environment": database.EnvironmentID,
+				"instance":    database.InstanceID,
+				"database":    database.DatabaseName,
+			}
+			payload := &api.AnomalyDatabaseSchemaDriftPayload{}
+			if err := json.Unmarshal([]byte(anomaly.Payload), payload); err == nil && payload.Version != "" {
+				details["version"] = payload.Version
+			}
+			dedupKey := fmt.Sprintf("bytebase/database/%d/schema-drift", database.UID)
+			alerts[dedupKey] = &conditionAlert{
+				condition: api.AlertConditionSchemaDrift,
+				alert: &alert.Alert{
+					DedupKey: dedupKey,
+					Summary:  fmt.Sprintf("Schema drift detected on database %q of instance %q", database.DatabaseName, database.InstanceID),
+					Source:   database.InstanceID,
+					Severity: alert.SeverityWarning,
+					Link:     fmt.Sprintf("%s/db/%s-%d", externalURL, slug.Make(database.DatabaseName), database.UID),
+					Details:  details,
+				},
+			}
+		case api.AnomalyInstanceConnection:
+			if anomaly.DatabaseUID != nil {
+				continue
+			}
+			instance, err := a.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &anomaly.InstanceUID})
+			if err != nil {
+				return nil, err
+			}
+			if instance == nil {
+				continue
+			}
+			summary := fmt.Sprintf("Instance %q is unreachable", instance.Title)
+			payload := &api.AnomalyInstanceConnectionPayload{}
+			if err := json.Unmarshal([]byte(anomaly.Payload), payload); err == nil && payload.Detail != "" {
+				summary = fmt.Sprintf("%s: %s", summary, payload.Detail)
+			}
+			dedupKey := fmt.Sprintf("bytebase/instance/%d/unreachable", instance.UID)
+			alerts[dedupKey] = &conditionAlert{
+				condition: api.AlertConditionInstanceUnreachable,
+				alert: &alert.Alert{
+					DedupKey: dedupKey,
+					Summary:  summary,
+					Source:   instance.Title,
+					Severity: alert.SeverityCritical,
+					Link:     fmt.Sprintf("%s/instance/%s-%d", externalURL, slug.Make(instance.Title), instance.UID),
+					Details: map[string]string{
+						"environment": instance.EnvironmentID,
+						"instance":    instance.ResourceID,
+						"engine":      string(instance.Engine),
+					},
+				},
+			}
+		}
+	}
+	return alerts, nil
+}
@@ -0,0 +1,42 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/alert"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func TestReconcile(t *testing.T) {
+	a := require.New(t)
+	alerts := map[string]*conditionAlert{
+		"bytebase/task/101/failed":           {condition: api.AlertConditionTaskRunFailed, alert: &alert.Alert{DedupKey: "bytebase/task/101/failed"}},
+		"bytebase/database/102/schema-drift": {condition: api.AlertConditionSchemaDrift, alert: &alert.Alert{DedupKey: "bytebase/database/102/schema-drift"}},
+		"bytebase/instance/103/unreachable":  {condition: api.AlertConditionInstanceUnreachable, alert: &alert.Alert{DedupKey: "bytebase/instance/103/unreachable"}},
+	}
+	incidents := []*store.AlertIncidentMessage{
+		// The condition is still active.
+		{ReceiverID: "pagerduty", DedupKey: "bytebase/task/101/failed"},
+		// The condition has cleared.
+		{ReceiverID: "pagerduty", DedupKey: "bytebase/task/100/failed"},
+		// The condition is no longer alerted to the receiver.
+		{ReceiverID: "pagerduty", DedupKey: "bytebase/database/102/schema-drift"},
+	}
+
+	triggers, resolves := reconcile(&api.AlertReceiver{
+		ID:            "pagerduty",
+		ConditionList: []api.AlertCondition{api.AlertConditionTaskRunFailed, api.AlertConditionInstanceUnreachable},
+	}, incidents, alerts)
+	a.Len(triggers, 1)
+	a.Equal("bytebase/instance/103/unreachable", triggers[0].DedupKey)
+	a.Equal([]string{"bytebase/task/100/failed", "bytebase/database/102/schema-drift"}, resolves)
+
+	// All conditions are alerted to the receivers without the conditions.
+	triggers, resolves = reconcile(&api.AlertReceiver{ID: "opsgenie"}, nil, alerts)
+	a.Len(triggers, 3)
+	a.Equal("bytebase/database/102/schema-drift", triggers[0].DedupKey)
+	a.Empty(resolves)
+}
@@ -0,0 +1,185 @@
+package anomaly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/component/activity"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+var (
+	// createObjectReg matches the object type and name of the CREATE statements in the schema dumps.
+	createObjectReg = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:UNIQUE\s+)?(?:ALGORITHM\s*=\s*\S+\s+)?(?:DEFINER\s*=\s*\S+\s+)?(?:SQL\s+SECURITY\s+\w+\s+)?(MATERIALIZED\s+VIEW|TABLE|VIEW|INDEX|SEQUENCE|FUNCTION|PROCEDURE|TRIGGER|TYPE|EXTENSION|SCHEMA|EVENT)\s+(?:IF\s+NOT\s+EXISTS\s+)?([^\s(]+)`)
+	// alterTableReg matches the table name of the ALTER TABLE statements, e.g. the constraints and the defaults in the PostgreSQL dumps.
+	alterTableReg = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:ONLY\s+)?(?:IF\s+EXISTS\s+)?([^\s(]+)`)
+	// versionedCommentReg matches the MySQL versioned comments wrapping the statements, e.g. /*!50003 CREATE*/.
+	versionedCommentReg = regexp.MustCompile(`/\*!\d*\s?|\*/`)
+	whitespaceReg       = regexp.MustCompile(`\s+`)
+)
+
+// isSchemaDriftChanged returns true if the database has no active schema drift anomaly,
+// or the drift is different from the active anomaly, which means there are new out-of-band changes.
+func (s *Scanner) isSchemaDriftChanged(ctx context.Context, database *store.DatabaseMessage, payload *api.AnomalyDatabaseSchemaDriftPayload) (bool, error) {
+	status := api.Normal
+	list, err := s.store.ListAnomalyV2(ctx, &store.ListAnomalyMessage{
+		RowStatus:   &status,
+		DatabaseUID: &database.UID,
+		Types:       []api.AnomalyType{api.AnomalyDatabaseSchemaDrift},
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(list) == 0 {
+		return true, nil
+	}
+	var activePayload api.AnomalyDatabaseSchemaDriftPayload
+	if err := json.Unmarshal([]byte(list[0].Payload), &activePayload); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal anomaly payload")
+	}
+	return activePayload.Version != payload.Version || activePayload.Actual != payload.Actual, nil
+}
+
+// createSchemaDriftActivity creates the schema drift activity in the project of the database, and posts the project webhooks.
+func (s *Scanner) createSchemaDriftActivity(ctx context.Context, database *store.DatabaseMessage, payload *api.AnomalyDatabaseSchemaDriftPayload) error {
+	project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &database.ProjectID})
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return errors.Errorf("project %q not found", database.ProjectID)
+	}
+	bytes, err := json.Marshal(api.ActivityDatabaseSchemaDriftPayload{
+		DatabaseID:   database.UID,
+		DatabaseName: database.DatabaseName,
+		Version:      payload.Version,
+		Objects:      payload.Objects,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal activity payload")
+	}
+
+	comment := fmt.Sprintf("Database %q has drifted from the schema version %s.", database.DatabaseName, payload.Version)
+	if len(payload.Objects) > 0 {
+		var objects []string
+		for _, object := range payload.Objects {
+			objects = append(objects, fmt.Sprintf("%s %s %s", strings.ToLower(string(object.Action)), strings.ToLower(object.Type), object.Name))
+		}
+		comment += " Out-of-band changes: " + strings.Join(objects, ", ") + "."
+	}
+	if _, err := s.activityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: project.UID,
+		Type:        api.ActivityDatabaseSchemaDrift,
+		Level:       api.ActivityWarn,
+		Payload:     string(bytes),
+		Comment:     comment,
+	}, &activity.Metadata{Project: project}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// getSchemaDriftObjects compares the expected and the actual schema dumps by objects.
+// It returns nil if the engine is not supported, and the drift is only shown as the whole schema diff.
+func getSchemaDriftObjects(engine db.Type, expect, actual string) []*api.SchemaDriftObject {
+	var parserEngine parser.EngineType
+	switch engine {
+	case db.Postgres:
+		parserEngine = parser.Postgres
+	case db.MySQL, db.TiDB, db.OceanBase:
+		parserEngine = parser.MySQL
+	case db.Snowflake:
+		parserEngine = parser.Snowflake
+	default:
+		return nil
+	}
+	expectObjects, err := getSchemaObjects(parserEngine, expect)
+	if err != nil {
+		return nil
+	}
+	actualObjects, err := getSchemaObjects(parserEngine, actual)
+	if err != nil {
+		return nil
+	}
+
+	var objects []*api.SchemaDriftObject
+	for key, definition := range actualObjects {
+		expectDefinition, ok := expectObjects[key]
+		if !ok {
+			objects = append(objects, &api.SchemaDriftObject{Type: key.tp, Name: key.name, Action: api.SchemaDriftCreated})
+		} else if expectDefinition != definition {
+			objects = append(objects, &api.SchemaDriftObject{Type: key.tp, Name: key.name, Action: api.SchemaDriftAltered})
+		}
+	}
+	for key := range expectObjects {
+		if _, ok := actualObjects[key]; !ok {
+			objects = append(objects, &api.SchemaDriftObject{Type: key.tp, Name: key.name, Action: api.SchemaDriftDropped})
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].Type != objects[j].Type {
+			return objects[i].Type < objects[j].Type
+		}
+		return objects[i].Name < objects[j].Name
+	})
+	return objects
+}
+
+type schemaObjectKey struct {
+	tp   string
+	name string
+}
+
+// getSchemaObjects groups the statements of the schema dump by objects, and returns the normalized definitions.
+// The ALTER TABLE statements belong to the tables, and the statements setting up the session are ignored.
+func getSchemaObjects(engine parser.EngineType, schema string) (map[schemaObjectKey]string, error) {
+	list, err := parser.SplitMultiSQLAndNormalize(engine, schema)
+	if err != nil {
+		return nil, err
+	}
+	objects := make(map[schemaObjectKey]string)
+	for _, sql := range list {
+		text := normalizeStatement(sql.Text)
+		var key schemaObjectKey
+		if matches := createObjectReg.FindStringSubmatch(text); matches != nil {
+			key = schemaObjectKey{tp: strings.ToUpper(whitespaceReg.ReplaceAllString(matches[1], " ")), name: normalizeObjectName(matches[2])}
+		} else if matches := alterTableReg.FindStringSubmatch(text); matches != nil {
+			key = schemaObjectKey{tp: "TABLE", name: normalizeObjectName(matches[1])}
+		} else {
+			continue
+		}
+		if definition, ok := objects[key]; ok {
+			objects[key] = definition + "\n" + text
+		} else {
+			objects[key] = text
+		}
+	}
+	return objects, nil
+}
+
+// normalizeStatement removes the comment lines and the MySQL versioned comments, and collapses the whitespaces.
+func normalizeStatement(statement string) string {
+	var lines []string
+	for _, line := range strings.Split(statement, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	text := versionedCommentReg.ReplaceAllString(strings.Join(lines, "\n"), "")
+	return strings.TrimSpace(whitespaceReg.ReplaceAllString(text, " "))
+}
+
+func normalizeObjectName(name string) string {
+	return strings.NewReplacer("`", "", `"`, "", ";", "").Replace(name)
+}
@@ -0,0 +1,69 @@
+package anomaly
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestGetSchemaDriftObjects(t *testing.T) {
+	a := require.New(t)
+
+	tests := []struct {
+		engine db.Type
+		expect string
+		actual string
+		want   []*api.SchemaDriftObject
+	}{
+		{
+			engine: db.Postgres,
+			expect: `
+SET statement_timeout = 0;
+--
+-- Name: t1; Type: TABLE; Schema: public; Owner: -
+--
+CREATE TABLE public.t1 (
+    id integer NOT NULL
+);
+ALTER TABLE ONLY public.t1
+    ADD CONSTRAINT t1_pkey PRIMARY KEY (id);
+CREATE TABLE public.t2 (id integer);
+CREATE VIEW public.v1 AS SELECT 1;
+`,
+			actual: `
+SET statement_timeout = 0;
+CREATE TABLE public.t1 (
+    id integer NOT NULL
+);
+CREATE TABLE public.t2 (id integer);
+CREATE INDEX idx_t2_id ON public.t2 USING btree (id);
+`,
+			want: []*api.SchemaDriftObject{
+				{Type: "INDEX", Name: "idx_t2_id", Action: api.SchemaDriftCreated},
+				{Type: "TABLE", Name: "public.t1", Action: api.SchemaDriftAltered},
+				{Type: "VIEW", Name: "public.v1", Action: api.SchemaDriftDropped},
+			},
+		},
+		{
+			engine: db.MySQL,
+			expect: "SET character_set_client = utf8mb4;\nCREATE TABLE `t1` (\n  `id` int NOT NULL\n) ENGINE=InnoDB;\n",
+			actual: "SET character_set_client = utf8mb4;\nCREATE TABLE `t1` (\n  `id` int NOT NULL,\n  `name` varchar(255)\n) ENGINE=InnoDB;\n" +
+				"DELIMITER ;;\n/*!50003 CREATE*/ /*!50017 DEFINER=`root`@`%`*/ /*!50003 TRIGGER `trg` BEFORE INSERT ON `t1` FOR EACH ROW SET NEW.id = 1 */;;\nDELIMITER ;\n",
+			want: []*api.SchemaDriftObject{
+				{Type: "TABLE", Name: "t1", Action: api.SchemaDriftAltered},
+				{Type: "TRIGGER", Name: "trg", Action: api.SchemaDriftCreated},
+			},
+		},
+		{
+			engine: db.ClickHouse,
+			expect: "CREATE TABLE t1 (id Int32) ENGINE = Memory;",
+			actual: "CREATE TABLE t2 (id Int32) ENGINE = Memory;",
+		},
+	}
+	for _, test := range tests {
+		a.Equal(test.want, getSchemaDriftObjects(test.engine, test.expect, test.actual))
+	}
+}
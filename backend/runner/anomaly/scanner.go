@@ -4,6 +4,7 @@ package anomaly
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -14,32 +15,40 @@ import (
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	enterpriseAPI "github.com/bytebase/bytebase/backend/enterprise/api"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
 )
 
 const (
 	// The chosen interval is a balance between anomaly staleness tolerance and background load.
 	anomalyScanInterval = time.Duration(10) * time.Minute
+	// The certificate expiry anomaly fires within the period before the certificate expires, so that it can be renewed before the connections start failing.
+	certificateExpiryWarningPeriod = time.Duration(30*24) * time.Hour
 )
 
 // NewScanner creates a anomaly scanner.
-func NewScanner(store *store.Store, dbFactory *dbfactory.DBFactory, licenseService enterpriseAPI.LicenseService) *Scanner {
+func NewScanner(store *store.Store, dbFactory *dbfactory.DBFactory, activityManager *activity.Manager, licenseService enterpriseAPI.LicenseService, secret string) *Scanner {
 	return &Scanner{
-		store:          store,
-		dbFactory:      dbFactory,
-		licenseService: licenseService,
+		store:           store,
+		dbFactory:       dbFactory,
+		activityManager: activityManager,
+		licenseService:  licenseService,
+		secret:          secret,
 	}
 }
 
 // Scanner is the anomaly scanner.
 type Scanner struct {
-	store          *store.Store
-	dbFactory      *dbfactory.DBFactory
-	licenseService enterpriseAPI.LicenseService
+	store           *store.Store
+	dbFactory       *dbfactory.DBFactory
+	activityManager *activity.Manager
+	licenseService  enterpriseAPI.LicenseService
+	secret          string
 }
 
 // Run will run the anomaly scanner once.
@@ -121,6 +130,7 @@ func (s *Scanner) Run(ctx context.Context, wg *sync.WaitGroup) {
 						}()
 
 						s.checkInstanceAnomaly(ctx, instance)
+						s.checkInstanceCertificateAnomaly(ctx, instance)
 
 						databases, err := s.store.ListDatabases(ctx, &store.FindDatabaseMessage{EnvironmentID: &instance.EnvironmentID, InstanceID: &instance.ResourceID})
 						if err != nil {
@@ -136,6 +146,9 @@ func (s *Scanner) Run(ctx context.Context, wg *sync.WaitGroup) {
 							}
 							s.checkDatabaseAnomaly(ctx, instance, database)
 							s.checkBackupAnomaly(ctx, environment, instance, database, backupPlanPolicyMap)
+							if common.FeatureFlag(common.FeatureFlagDatabaseTLS) {
+								s.checkDatabaseCertificateAnomaly(ctx, instance, database)
+							}
 						}
 					}(environment, instance)
 
@@ -191,6 +204,146 @@ func (s *Scanner) checkInstanceAnomaly(ctx context.Context, instance *store.Inst
 	}
 }
 
+func (s *Scanner) checkInstanceCertificateAnomaly(ctx context.Context, instance *store.InstanceMessage) {
+	adminDataSource := utils.DataSourceFromInstanceWithType(instance, api.Admin)
+	if adminDataSource == nil {
+		return
+	}
+	cert, err := s.getExpiringCertificate(adminDataSource.ObfuscatedSslCa, adminDataSource.ObfuscatedSslCert)
+	if err != nil {
+		log.Error("Failed to check anomaly",
+			zap.String("instance", instance.ResourceID),
+			zap.String("type", string(api.AnomalyInstanceCertificateExpiry)),
+			zap.Error(err))
+		return
+	}
+
+	if cert != nil {
+		anomalyPayload := api.AnomalyInstanceCertificateExpiryPayload{
+			Subject:  cert.Subject.String(),
+			ExpireTs: cert.NotAfter.Unix(),
+		}
+		payload, err := json.Marshal(anomalyPayload)
+		if err != nil {
+			log.Error("Failed to marshal anomaly payload",
+				zap.String("instance", instance.ResourceID),
+				zap.String("type", string(api.AnomalyInstanceCertificateExpiry)),
+				zap.Error(err))
+		} else {
+			if _, err = s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
+				InstanceUID: instance.UID,
+				Type:        api.AnomalyInstanceCertificateExpiry,
+				Payload:     string(payload),
+			}); err != nil {
+				log.Error("Failed to create anomaly",
+					zap.String("instance", instance.ResourceID),
+					zap.String("type", string(api.AnomalyInstanceCertificateExpiry)),
+					zap.Error(err))
+			}
+		}
+		return
+	}
+
+	err = s.store.ArchiveAnomalyV2(ctx, &store.ArchiveAnomalyMessage{
+		InstanceUID: &instance.UID,
+		Type:        api.AnomalyInstanceCertificateExpiry,
+	})
+	if err != nil && common.ErrorCode(err) != common.NotFound {
+		log.Error("Failed to close anomaly",
+			zap.String("instance", instance.ResourceID),
+			zap.String("type", string(api.AnomalyInstanceCertificateExpiry)),
+			zap.Error(err))
+	}
+}
+
+func (s *Scanner) checkDatabaseCertificateAnomaly(ctx context.Context, instance *store.InstanceMessage, database *store.DatabaseMessage) {
+	databaseTLS, err := s.store.GetDatabaseTLS(ctx, &store.FindDatabaseTLSMessage{DatabaseUID: &database.UID})
+	if err != nil {
+		log.Error("Failed to retrieve database TLS configuration",
+			zap.String("instance", instance.ResourceID),
+			zap.String("database", database.DatabaseName),
+			zap.Error(err))
+		return
+	}
+
+	var cert *x509.Certificate
+	if databaseTLS != nil {
+		cert, err = s.getExpiringCertificate(databaseTLS.ObfuscatedSslCa, databaseTLS.ObfuscatedSslCert)
+		if err != nil {
+			log.Error("Failed to check anomaly",
+				zap.String("instance", instance.ResourceID),
+				zap.String("database", database.DatabaseName),
+				zap.String("type", string(api.AnomalyDatabaseCertificateExpiry)),
+				zap.Error(err))
+			return
+		}
+	}
+
+	if cert != nil {
+		anomalyPayload := api.AnomalyDatabaseCertificateExpiryPayload{
+			Subject:  cert.Subject.String(),
+			ExpireTs: cert.NotAfter.Unix(),
+		}
+		payload, err := json.Marshal(anomalyPayload)
+		if err != nil {
+			log.Error("Failed to marshal anomaly payload",
+				zap.String("instance", instance.ResourceID),
+				zap.String("database", database.DatabaseName),
+				zap.String("type", string(api.AnomalyDatabaseCertificateExpiry)),
+				zap.Error(err))
+		} else {
+			if _, err = s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
+				InstanceUID: instance.UID,
+				DatabaseUID: &database.UID,
+				Type:        api.AnomalyDatabaseCertificateExpiry,
+				Payload:     string(payload),
+			}); err != nil {
+				log.Error("Failed to create anomaly",
+					zap.String("instance", instance.ResourceID),
+					zap.String("database", database.DatabaseName),
+					zap.String("type", string(api.AnomalyDatabaseCertificateExpiry)),
+					zap.Error(err))
+			}
+		}
+		return
+	}
+
+	err = s.store.ArchiveAnomalyV2(ctx, &store.ArchiveAnomalyMessage{
+		DatabaseUID: &database.UID,
+		Type:        api.AnomalyDatabaseCertificateExpiry,
+	})
+	if err != nil && common.ErrorCode(err) != common.NotFound {
+		log.Error("Failed to close anomaly",
+			zap.String("instance", instance.ResourceID),
+			zap.String("database", database.DatabaseName),
+			zap.String("type", string(api.AnomalyDatabaseCertificateExpiry)),
+			zap.Error(err))
+	}
+}
+
+// getExpiringCertificate returns the certificate expiring first among the CA bundle and the client certificate,
+// or nil if it doesn't expire within the warning period.
+func (s *Scanner) getExpiringCertificate(obfuscatedSslCa, obfuscatedSslCert string) (*x509.Certificate, error) {
+	var earliest *x509.Certificate
+	for _, obfuscated := range []string{obfuscatedSslCa, obfuscatedSslCert} {
+		pemBundle, err := common.Unobfuscate(obfuscated, s.secret)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := db.GetEarliestExpiringCertificate(pemBundle)
+		if err != nil {
+			return nil, err
+		}
+		if cert != nil && (earliest == nil || cert.NotAfter.Before(earliest.NotAfter)) {
+			earliest = cert
+		}
+	}
+	if earliest == nil || earliest.NotAfter.After(time.Now().Add(certificateExpiryWarningPeriod)) {
+		return nil, nil
+	}
+	return earliest, nil
+}
+
 func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *store.InstanceMessage, database *store.DatabaseMessage) {
 	driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, database.DatabaseName)
 
@@ -279,6 +432,7 @@ func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *store.Inst
 					Version: list[0].Version,
 					Expect:  list[0].Schema,
 					Actual:  schemaBuf.String(),
+					Objects: getSchemaDriftObjects(instance.Engine, list[0].Schema, schemaBuf.String()),
 				}
 				payload, err := json.Marshal(anomalyPayload)
 				if err != nil {
@@ -288,6 +442,16 @@ func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *store.Inst
 						zap.String("type", string(api.AnomalyDatabaseSchemaDrift)),
 						zap.Error(err))
 				} else {
+					// Only alert on the new out-of-band changes, the anomaly is refreshed in every round.
+					changed, err := s.isSchemaDriftChanged(ctx, database, &anomalyPayload)
+					if err != nil {
+						log.Error("Failed to check anomaly",
+							zap.String("instance", instance.ResourceID),
+							zap.String("database", database.DatabaseName),
+							zap.String("type", string(api.AnomalyDatabaseSchemaDrift)),
+							zap.Error(err))
+						return
+					}
 					if _, err = s.store.UpsertActiveAnomalyV2(ctx, api.SystemBotID, &store.AnomalyMessage{
 						InstanceUID: instance.UID,
 						DatabaseUID: &database.UID,
@@ -299,6 +463,13 @@ func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *store.Inst
 							zap.String("database", database.DatabaseName),
 							zap.String("type", string(api.AnomalyDatabaseSchemaDrift)),
 							zap.Error(err))
+					} else if changed {
+						if err := s.createSchemaDriftActivity(ctx, database, &anomalyPayload); err != nil {
+							log.Error("Failed to create schema drift activity",
+								zap.String("instance", instance.ResourceID),
+								zap.String("database", database.DatabaseName),
+								zap.Error(err))
+						}
 					}
 				}
 			} else {
@@ -485,13 +656,17 @@ func (s *Scanner) checkBackupAnomaly(ctx context.Context, environment *store.Env
 
 func disableBackupAnomalyCheck(dbTp db.Type) bool {
 	m := map[db.Type]struct{}{
-		db.MongoDB:  {},
-		db.Spanner:  {},
-		db.Redis:    {},
-		db.Oracle:   {},
-		db.MSSQL:    {},
-		db.MariaDB:  {},
-		db.Redshift: {},
+		db.MongoDB:       {},
+		db.Spanner:       {},
+		db.Redis:         {},
+		db.Oracle:        {},
+		db.MSSQL:         {},
+		db.MariaDB:       {},
+		db.Redshift:      {},
+		db.Elasticsearch: {},
+		db.Cassandra:     {},
+		db.DynamoDB:      {},
+		db.Neo4j:         {},
 	}
 	_, ok := m[dbTp]
 	return ok
@@ -0,0 +1,155 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/autoapproval"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// autoApproveIfNeeded approves the pending steps of the issue by the SystemBot if an auto-approval rule matches, and
+// logs the decision. It returns the matched rule and the number of the approved steps. The issues whose auto-approval
+// has been revoked are never approved again.
+func (r *Runner) autoApproveIfNeeded(ctx context.Context, issue *store.IssueMessage, riskLevel int64, approval *storepb.IssuePayloadApproval) (*api.AutoApprovalRule, int, error) {
+	if !common.FeatureFlag(common.FeatureFlagAutoApproval) {
+		return nil, 0, nil
+	}
+	if len(approval.ApprovalTemplates) != 1 || utils.FindNextPendingStep(approval.ApprovalTemplates[0], approval.Approvers) == nil {
+		return nil, 0, nil
+	}
+	policy, err := r.store.GetAutoApprovalPolicy(ctx)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to get auto-approval policy")
+	}
+	if len(policy.RuleList) == 0 {
+		return nil, 0, nil
+	}
+	decisions, err := r.store.ListAutoApprovals(ctx, &store.FindAutoApprovalMessage{IssueUID: &issue.UID})
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to list auto-approvals")
+	}
+	for _, decision := range decisions {
+		if decision.RevokerID != nil {
+			return nil, 0, nil
+		}
+	}
+
+	inputs, err := getAutoApprovalInputs(ctx, r.store, issue, riskLevel)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "failed to get auto-approval inputs")
+	}
+	if len(inputs) == 0 {
+		return nil, 0, nil
+	}
+	rule, err := findAutoApprovalRule(policy, inputs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if rule == nil {
+		return nil, 0, nil
+	}
+
+	count := 0
+	for utils.FindNextPendingStep(approval.ApprovalTemplates[0], approval.Approvers) != nil {
+		approval.Approvers = append(approval.Approvers, &storepb.IssuePayloadApproval_Approver{
+			Status:      storepb.IssuePayloadApproval_Approver_APPROVED,
+			PrincipalId: api.SystemBotID,
+		})
+		count++
+	}
+	if _, err := r.store.CreateAutoApproval(ctx, &store.AutoApprovalMessage{
+		IssueUID:  issue.UID,
+		RiskLevel: riskLevel,
+		RuleID:    rule.ID,
+		Condition: rule.Condition,
+	}); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to log auto-approval")
+	}
+	return rule, count, nil
+}
+
+// findAutoApprovalRule returns the first rule whose condition is true for all inputs.
+func findAutoApprovalRule(policy *api.AutoApprovalPolicy, inputs []*autoapproval.Input) (*api.AutoApprovalRule, error) {
+	for _, rule := range policy.RuleList {
+		condition, err := autoapproval.NewCondition(rule.Condition)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid auto-approval rule %q", rule.ID)
+		}
+		approved := true
+		for _, input := range inputs {
+			if !condition.ShouldApprove(input) {
+				approved = false
+				break
+			}
+		}
+		if approved {
+			return rule, nil
+		}
+	}
+	return nil, nil
+}
+
+// getAutoApprovalInputs returns an input for each database changed by the tasks pending approval.
+func getAutoApprovalInputs(ctx context.Context, s *store.Store, issue *store.IssueMessage, riskLevel int64) ([]*autoapproval.Input, error) {
+	tasks, err := s.ListTasks(ctx, &api.TaskFind{
+		PipelineID: &issue.PipelineUID,
+		StatusList: &[]api.TaskStatus{api.TaskPendingApproval},
+	})
+	if err != nil {
+		return nil, err
+	}
+	requester := map[string]string{}
+	if issue.Creator != nil {
+		requester = map[string]string{
+			"email": issue.Creator.Email,
+			"name":  issue.Creator.Name,
+			"role":  string(issue.Creator.Role),
+		}
+	}
+
+	var inputs []*autoapproval.Input
+	for _, task := range tasks {
+		instance, err := s.GetInstanceV2(ctx, &store.FindInstanceMessage{
+			UID: &task.InstanceID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		input := &autoapproval.Input{
+			Requester:     requester,
+			EnvironmentID: instance.EnvironmentID,
+			ProjectID:     issue.Project.ResourceID,
+			IssueType:     string(issue.Type),
+			RiskLevel:     riskLevel,
+		}
+		if task.Type == api.TaskDatabaseCreate {
+			payload := &api.TaskDatabaseCreatePayload{}
+			if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+				return nil, err
+			}
+			input.DatabaseName = payload.DatabaseName
+		} else {
+			database, err := s.GetDatabaseV2(ctx, &store.FindDatabaseMessage{
+				UID: task.DatabaseID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if database == nil {
+				return nil, errors.Errorf("database %v not found", *task.DatabaseID)
+			}
+			input.DatabaseName = database.DatabaseName
+			input.DatabaseLabels = database.Labels
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs, nil
+}
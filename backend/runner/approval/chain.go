@@ -0,0 +1,413 @@
+package approval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/app/feishu"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+const (
+	chainRunnerInterval = 1 * time.Minute
+	// externalApprovalTimeout is the timeout of posting the pending step to the external approver.
+	externalApprovalTimeout = 10 * time.Second
+)
+
+// ChainRunner is the runner tracking the pending approval steps of the issues, it escalates the steps pending for
+// longer than the escalation timeouts, and sends the steps of the external approvers to them.
+type ChainRunner struct {
+	store           *store.Store
+	activityManager *activity.Manager
+	feishuProvider  *feishu.Provider
+	client          *http.Client
+	// mu serializes the checks and the callbacks of the external approvers.
+	mu sync.Mutex
+}
+
+// NewChainRunner creates a new approval chain runner.
+func NewChainRunner(store *store.Store, activityManager *activity.Manager, feishuProvider *feishu.Provider) *ChainRunner {
+	return &ChainRunner{
+		store:           store,
+		activityManager: activityManager,
+		feishuProvider:  feishuProvider,
+		client:          &http.Client{Timeout: externalApprovalTimeout},
+	}
+}
+
+// Run runs the approval chain runner.
+func (r *ChainRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(chainRunnerInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Approval chain runner started and will run every %v", chainRunnerInterval))
+	for {
+		select {
+		case <-ticker.C:
+			r.check(ctx, time.Now())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *ChainRunner) check(ctx context.Context, now time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = errors.Errorf("%v", r)
+			}
+			log.Error("Approval chain runner PANIC RECOVER", zap.Error(err), zap.Stack("panic-stack"))
+		}
+	}()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chain, err := utils.GetApprovalChainSetting(ctx, r.store)
+	if err != nil {
+		log.Error("Failed to get approval chain setting", zap.Error(err))
+		return
+	}
+	issues, err := r.store.ListIssueV2(ctx, &store.FindIssueMessage{
+		StatusList: []api.IssueStatus{api.IssueOpen},
+	})
+	if err != nil {
+		log.Error("Failed to list open issues", zap.Error(err))
+		return
+	}
+	openIssues := make(map[int]bool)
+	for _, issue := range issues {
+		openIssues[issue.UID] = true
+		if err := r.checkIssue(ctx, issue, chain, now); err != nil {
+			log.Error("Failed to check the pending approval step", zap.Int("issue", issue.UID), zap.Error(err))
+		}
+	}
+
+	// Cancel the Feishu approval instances of the issues which are no longer open.
+	requested := api.ApprovalStepExternalRequested
+	states, err := r.store.ListIssueApprovalSteps(ctx, &store.FindIssueApprovalStepMessage{ExternalStatus: &requested})
+	if err != nil {
+		log.Error("Failed to list the requested external approvals", zap.Error(err))
+		return
+	}
+	for _, state := range states {
+		if openIssues[state.IssueUID] {
+			continue
+		}
+		issue, err := r.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: &state.IssueUID})
+		if err != nil {
+			log.Error("Failed to get issue", zap.Int("issue", state.IssueUID), zap.Error(err))
+			continue
+		}
+		// The issue may be opened after it's listed.
+		if issue == nil || issue.Status == api.IssueOpen {
+			continue
+		}
+		if err := r.cancelFeishuApprovalIfNeeded(ctx, issue, state, chain, "Canceled because the issue is no longer open."); err != nil {
+			log.Error("Failed to cancel the Feishu approval", zap.Int("issue", issue.UID), zap.Error(err))
+			continue
+		}
+		// The step is sent again if the issue is reopened.
+		state.ExternalStatus = api.ApprovalStepExternalNone
+		state.ExternalToken = ""
+		if err := r.store.UpsertIssueApprovalStep(ctx, state); err != nil {
+			log.Error("Failed to update the approval step", zap.Int("issue", issue.UID), zap.Error(err))
+		}
+	}
+}
+
+func (r *ChainRunner) checkIssue(ctx context.Context, issue *store.IssueMessage, chain *api.SettingWorkspaceApprovalChainValue, now time.Time) error {
+	approval, err := getApproval(issue)
+	if err != nil {
+		return err
+	}
+	if approval == nil {
+		return nil
+	}
+	step := utils.FindNextPendingStep(approval.ApprovalTemplates[0], approval.Approvers)
+	if step == nil {
+		return nil
+	}
+	state, err := r.store.GetIssueApprovalStep(ctx, &store.FindIssueApprovalStepMessage{IssueUID: &issue.UID})
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		// The approval flow is found before the steps are tracked, the risk level is unknown.
+		state = &store.IssueApprovalStepMessage{IssueUID: issue.UID, Step: -1}
+	}
+	if index := len(approval.Approvers); state.Step != index {
+		// The step is approved in Bytebase, e.g. by the escalation approver.
+		if err := r.cancelFeishuApprovalIfNeeded(ctx, issue, state, chain, "Canceled because the approval step is no longer pending."); err != nil {
+			log.Error("Failed to cancel the Feishu approval", zap.Int("issue", issue.UID), zap.Error(err))
+		}
+		state.Step = index
+		state.StartedTs = now.Unix()
+		state.EscalatedTs = 0
+		state.ExternalStatus = api.ApprovalStepExternalNone
+		state.ExternalToken = ""
+		if err := r.store.UpsertIssueApprovalStep(ctx, state); err != nil {
+			return err
+		}
+	}
+
+	if id, ok := getExternalApproverID(step); ok && state.ExternalStatus == api.ApprovalStepExternalNone {
+		approver := chain.FindExternalApprover(id)
+		if approver == nil {
+			return errors.Errorf("external approver %q not found", id)
+		}
+		var token string
+		if approver.Type == api.ExternalApproverFeishu {
+			token, err = r.requestFeishuApproval(ctx, issue, state, approval, approver)
+		} else {
+			token, err = r.requestExternalApproval(ctx, issue, state, approver)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to request external approver %q", id)
+		}
+		state.ExternalStatus = api.ApprovalStepExternalRequested
+		state.ExternalToken = token
+		if err := r.store.UpsertIssueApprovalStep(ctx, state); err != nil {
+			return err
+		}
+	} else if ok && state.ExternalStatus == api.ApprovalStepExternalRequested && state.ExternalToken != "" {
+		if approver := chain.FindExternalApprover(id); approver != nil && approver.Type == api.ExternalApproverFeishu {
+			if err := r.syncFeishuApproval(ctx, issue, state, approval, approver); err != nil {
+				return errors.Wrapf(err, "failed to sync the Feishu approval of external approver %q", id)
+			}
+		}
+	}
+
+	if escalation := chain.FindEscalation(state.RiskLevel); escalation != nil && state.EscalatedTs == 0 && now.Unix()-state.StartedTs >= escalation.TimeoutSeconds {
+		state.EscalatedTs = now.Unix()
+		if err := r.store.UpsertIssueApprovalStep(ctx, state); err != nil {
+			return err
+		}
+		comment := fmt.Sprintf("Approval step %d has been pending for more than %s, %s can approve it now.", state.Step+1, time.Duration(escalation.TimeoutSeconds)*time.Second, escalation.Approver)
+		if err := r.createComment(ctx, issue, comment); err != nil {
+			log.Error("Failed to create the escalation activity", zap.Int("issue", issue.UID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// requestExternalApproval posts the pending step to the external approver, and returns the token of the callback.
+func (r *ChainRunner) requestExternalApproval(ctx context.Context, issue *store.IssueMessage, state *store.IssueApprovalStepMessage, approver *api.ExternalApprover) (string, error) {
+	setting, err := r.store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return "", err
+	}
+	if setting.ExternalUrl == "" {
+		return "", errors.Errorf("external URL is required for the callback of the external approver")
+	}
+	token, err := common.RandomString(32)
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(&api.ExternalApprovalRequest{
+		IssueID:     issue.UID,
+		IssueTitle:  issue.Title,
+		ProjectID:   issue.Project.ResourceID,
+		Step:        state.Step + 1,
+		RiskLevel:   state.RiskLevel,
+		CallbackURL: fmt.Sprintf("%s/hook/approval/%s", strings.TrimSuffix(setting.ExternalUrl, "/"), token),
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, approver.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", errors.Errorf("unexpected status %s", resp.Status)
+	}
+	return token, nil
+}
+
+// HandleExternalCallback approves or rejects the pending step of the issue by the callback of the external approver.
+func (r *ChainRunner) HandleExternalCallback(ctx context.Context, token string, callback *api.ExternalApprovalCallback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token == "" {
+		return common.Errorf(common.NotFound, "external approval not found")
+	}
+	state, err := r.store.GetIssueApprovalStep(ctx, &store.FindIssueApprovalStepMessage{ExternalToken: &token})
+	if err != nil {
+		return err
+	}
+	if state == nil || state.ExternalStatus != api.ApprovalStepExternalRequested {
+		return common.Errorf(common.NotFound, "external approval not found")
+	}
+	issue, err := r.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: &state.IssueUID})
+	if err != nil {
+		return err
+	}
+	if issue == nil || issue.Status != api.IssueOpen {
+		return common.Errorf(common.Conflict, "issue is no longer open")
+	}
+	approval, err := getApproval(issue)
+	if err != nil {
+		return err
+	}
+	if approval == nil || len(approval.Approvers) != state.Step {
+		return common.Errorf(common.Conflict, "approval step is no longer pending")
+	}
+	id, ok := getExternalApproverID(utils.FindNextPendingStep(approval.ApprovalTemplates[0], approval.Approvers))
+	if !ok {
+		return common.Errorf(common.Conflict, "approval step is no longer pending")
+	}
+	// The steps of the Feishu external approvers are synced from the Feishu approval instances.
+	chain, err := utils.GetApprovalChainSetting(ctx, r.store)
+	if err != nil {
+		return err
+	}
+	if approver := chain.FindExternalApprover(id); approver != nil && approver.Type == api.ExternalApproverFeishu {
+		return common.Errorf(common.NotFound, "external approval not found")
+	}
+
+	switch callback.Action {
+	case api.ExternalApprovalEventActionApprove:
+		return r.approveExternalStep(ctx, issue, state, approval, api.SystemBotID, callback.Comment)
+	case api.ExternalApprovalEventActionReject:
+		comment := fmt.Sprintf("Approval step %d has been rejected by the external approver.", state.Step+1)
+		if callback.Comment != "" {
+			comment = fmt.Sprintf("%s %s", comment, callback.Comment)
+		}
+		return r.rejectExternalStep(ctx, issue, state, comment)
+	default:
+		return common.Errorf(common.Invalid, "invalid action %q", callback.Action)
+	}
+}
+
+// approveExternalStep approves the pending step of the external approver as the principal.
+func (r *ChainRunner) approveExternalStep(ctx context.Context, issue *store.IssueMessage, state *store.IssueApprovalStepMessage, approval *storepb.IssuePayloadApproval, principalID int, comment string) error {
+	approval.Approvers = append(approval.Approvers, &storepb.IssuePayloadApproval_Approver{
+		Status:      storepb.IssuePayloadApproval_Approver_APPROVED,
+		PrincipalId: int32(principalID),
+	})
+	stepsSkipped, err := utils.SkipApprovalStepIfNeeded(ctx, r.store, issue.Project.UID, approval)
+	if err != nil {
+		return err
+	}
+	if err := updateIssuePayload(ctx, r.store, issue.UID, &storepb.IssuePayload{Approval: approval}); err != nil {
+		return err
+	}
+	// The token is only valid once.
+	state.ExternalToken = ""
+	if err := r.store.UpsertIssueApprovalStep(ctx, state); err != nil {
+		return err
+	}
+	if err := r.createApprovedActivities(ctx, issue, principalID, comment, 1+stepsSkipped); err != nil {
+		log.Error("Failed to create activity after the external approval", zap.Int("issue", issue.UID), zap.Error(err))
+	}
+	return nil
+}
+
+// rejectExternalStep marks the pending step of the external approver rejected. The rejected step is sent again after
+// the approval flow is found again.
+func (r *ChainRunner) rejectExternalStep(ctx context.Context, issue *store.IssueMessage, state *store.IssueApprovalStepMessage, comment string) error {
+	state.ExternalStatus = api.ApprovalStepExternalRejected
+	state.ExternalToken = ""
+	if err := r.store.UpsertIssueApprovalStep(ctx, state); err != nil {
+		return err
+	}
+	if err := r.createComment(ctx, issue, comment); err != nil {
+		log.Error("Failed to create activity after the external rejection", zap.Int("issue", issue.UID), zap.Error(err))
+	}
+	return nil
+}
+
+func (r *ChainRunner) createApprovedActivities(ctx context.Context, issue *store.IssueMessage, principalID int, comment string, count int) error {
+	activityPayload, err := protojson.Marshal(&storepb.ActivityIssueCommentCreatePayload{
+		Event: &storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_{
+			ApprovalEvent: &storepb.ActivityIssueCommentCreatePayload_ApprovalEvent{
+				Status: storepb.ActivityIssueCommentCreatePayload_ApprovalEvent_APPROVED,
+			},
+		},
+		IssueName: issue.Title,
+	})
+	if err != nil {
+		return err
+	}
+	for i := 0; i < count; i++ {
+		create := &api.ActivityCreate{
+			CreatorID:   principalID,
+			ContainerID: issue.UID,
+			Type:        api.ActivityIssueCommentCreate,
+			Level:       api.ActivityInfo,
+			Payload:     string(activityPayload),
+		}
+		// The comment of the external approver is on its own step.
+		if i == 0 {
+			create.Comment = comment
+		}
+		if _, err := r.activityManager.CreateActivity(ctx, create, &activity.Metadata{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ChainRunner) createComment(ctx context.Context, issue *store.IssueMessage, comment string) error {
+	activityPayload, err := protojson.Marshal(&storepb.ActivityIssueCommentCreatePayload{
+		IssueName: issue.Title,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.activityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: issue.UID,
+		Type:        api.ActivityIssueCommentCreate,
+		Level:       api.ActivityInfo,
+		Comment:     comment,
+		Payload:     string(activityPayload),
+	}, &activity.Metadata{})
+	return err
+}
+
+// getApproval returns the approval of the issue with the approval flow found, it's nil otherwise.
+func getApproval(issue *store.IssueMessage) (*storepb.IssuePayloadApproval, error) {
+	payload := &storepb.IssuePayload{}
+	if err := protojson.Unmarshal([]byte(issue.Payload), payload); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal issue payload")
+	}
+	approval := payload.Approval
+	if approval == nil || !approval.ApprovalFindingDone || approval.ApprovalFindingError != "" || len(approval.ApprovalTemplates) != 1 {
+		return nil, nil
+	}
+	return approval, nil
+}
+
+// getExternalApproverID returns the ID of the external approver of the step.
+func getExternalApproverID(step *storepb.ApprovalStep) (string, bool) {
+	if step == nil || len(step.Nodes) != 1 {
+		return "", false
+	}
+	return strings.CutPrefix(step.Nodes[0].GetRole(), api.ApprovalNodeExternalApproverPrefix)
+}
@@ -0,0 +1,182 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gosimple/slug"
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/app/feishu"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// requestFeishuApproval creates the Feishu approval instance of the pending step for the Feishu external approver, and
+// returns the instance code.
+func (r *ChainRunner) requestFeishuApproval(ctx context.Context, issue *store.IssueMessage, state *store.IssueApprovalStepMessage, approval *storepb.IssuePayloadApproval, approver *api.ExternalApprover) (string, error) {
+	setting, err := r.getFeishuSetting(ctx)
+	if err != nil {
+		return "", err
+	}
+	tokenCtx := feishu.TokenCtx{AppID: setting.AppID, AppSecret: setting.AppSecret}
+	users, err := r.feishuProvider.GetIDByEmail(ctx, tokenCtx, []string{issue.Creator.Email, approver.Approver})
+	if err != nil {
+		return "", err
+	}
+	approverID, ok := users[approver.Approver]
+	if !ok {
+		return "", errors.Errorf("failed to get user_id for the approver %s", approver.Approver)
+	}
+	requesterID, err := r.getFeishuRequesterID(ctx, tokenCtx, users, issue)
+	if err != nil {
+		return "", err
+	}
+
+	var taskList []feishu.Task
+	if issue.PipelineUID != 0 {
+		tasks, err := r.store.ListTasks(ctx, &api.TaskFind{PipelineID: &issue.PipelineUID})
+		if err != nil {
+			return "", err
+		}
+		for _, task := range tasks {
+			statement, err := utils.GetTaskStatement(task.Payload)
+			if err != nil {
+				return "", err
+			}
+			taskList = append(taskList, feishu.Task{
+				Name:      task.Name,
+				Status:    string(task.Status),
+				Statement: statement,
+			})
+		}
+	}
+	generalSetting, err := r.store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get workspace setting")
+	}
+	return r.feishuProvider.CreateExternalApproval(ctx, tokenCtx,
+		feishu.Content{
+			Issue:    fmt.Sprintf("#%d %s", issue.UID, issue.Title),
+			Stage:    fmt.Sprintf("Approval step %d of %d", state.Step+1, len(approval.ApprovalTemplates[0].GetFlow().GetSteps())),
+			Link:     fmt.Sprintf("%s/issue/%s-%d", generalSetting.ExternalUrl, slug.Make(issue.Title), issue.UID),
+			TaskList: taskList,
+		},
+		setting.ExternalApproval.ApprovalDefinitionID,
+		requesterID,
+		approverID,
+	)
+}
+
+// syncFeishuApproval approves or rejects the pending step if its Feishu approval instance is done.
+func (r *ChainRunner) syncFeishuApproval(ctx context.Context, issue *store.IssueMessage, state *store.IssueApprovalStepMessage, approval *storepb.IssuePayloadApproval, approver *api.ExternalApprover) error {
+	setting, err := r.getFeishuSetting(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := r.feishuProvider.GetExternalApprovalStatus(ctx, feishu.TokenCtx{AppID: setting.AppID, AppSecret: setting.AppSecret}, state.ExternalToken)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case feishu.ApprovalStatusApproved:
+		// The step is approved by the Bytebase user of the Feishu approver if there is one.
+		principalID := api.SystemBotID
+		user, err := r.store.GetUser(ctx, &store.FindUserMessage{Email: &approver.Approver})
+		if err != nil {
+			return err
+		}
+		if user != nil && !user.MemberDeleted {
+			principalID = user.ID
+		}
+		return r.approveExternalStep(ctx, issue, state, approval, principalID, fmt.Sprintf("Approved in Feishu by %s.", approver.Approver))
+	case feishu.ApprovalStatusRejected:
+		return r.rejectExternalStep(ctx, issue, state, fmt.Sprintf("Approval step %d has been rejected in Feishu by %s.", state.Step+1, approver.Approver))
+	case feishu.ApprovalStatusCanceled, feishu.ApprovalStatusDeleted:
+		return r.rejectExternalStep(ctx, issue, state, fmt.Sprintf("Approval step %d has been %s in Feishu.", state.Step+1, status))
+	}
+	return nil
+}
+
+// cancelFeishuApprovalIfNeeded cancels the Feishu approval instance of the step if the step is requested from a Feishu
+// external approver.
+func (r *ChainRunner) cancelFeishuApprovalIfNeeded(ctx context.Context, issue *store.IssueMessage, state *store.IssueApprovalStepMessage, chain *api.SettingWorkspaceApprovalChainValue, reason string) error {
+	if state.ExternalStatus != api.ApprovalStepExternalRequested || state.ExternalToken == "" {
+		return nil
+	}
+	approval, err := getApproval(issue)
+	if err != nil {
+		return err
+	}
+	if approval == nil {
+		return nil
+	}
+	steps := approval.ApprovalTemplates[0].GetFlow().GetSteps()
+	if state.Step < 0 || state.Step >= len(steps) {
+		return nil
+	}
+	id, ok := getExternalApproverID(steps[state.Step])
+	if !ok {
+		return nil
+	}
+	if approver := chain.FindExternalApprover(id); approver == nil || approver.Type != api.ExternalApproverFeishu {
+		return nil
+	}
+
+	setting, err := r.getFeishuSetting(ctx)
+	if err != nil {
+		return err
+	}
+	tokenCtx := feishu.TokenCtx{AppID: setting.AppID, AppSecret: setting.AppSecret}
+	users, err := r.feishuProvider.GetIDByEmail(ctx, tokenCtx, []string{issue.Creator.Email})
+	if err != nil {
+		return err
+	}
+	requesterID, err := r.getFeishuRequesterID(ctx, tokenCtx, users, issue)
+	if err != nil {
+		return err
+	}
+	if err := r.feishuProvider.CancelExternalApproval(ctx, tokenCtx, setting.ExternalApproval.ApprovalDefinitionID, state.ExternalToken, requesterID); err != nil {
+		return err
+	}
+	botID, err := r.feishuProvider.GetBotID(ctx, tokenCtx)
+	if err != nil {
+		return err
+	}
+	return r.feishuProvider.CreateExternalApprovalComment(ctx, tokenCtx, state.ExternalToken, botID, reason)
+}
+
+// getFeishuRequesterID returns the Feishu user ID of the issue creator, the application bot represents the creator if
+// the creator is not found.
+func (r *ChainRunner) getFeishuRequesterID(ctx context.Context, tokenCtx feishu.TokenCtx, users map[string]string, issue *store.IssueMessage) (string, error) {
+	if id, ok := users[issue.Creator.Email]; ok {
+		return id, nil
+	}
+	return r.feishuProvider.GetBotID(ctx, tokenCtx)
+}
+
+// getFeishuSetting returns the IM setting if the Feishu external approval is enabled.
+func (r *ChainRunner) getFeishuSetting(ctx context.Context) (*api.SettingAppIMValue, error) {
+	if r.feishuProvider == nil {
+		return nil, errors.Errorf("feishu is not available")
+	}
+	name := api.SettingAppIM
+	setting, err := r.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &name})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", name)
+	}
+	if setting == nil || setting.Value == "" {
+		return nil, errors.Errorf("IM setting is not configured")
+	}
+	value := new(api.SettingAppIMValue)
+	if err := json.Unmarshal([]byte(setting.Value), value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal IM setting")
+	}
+	if value.IMType != api.IMTypeFeishu || !value.ExternalApproval.Enabled || value.ExternalApproval.ApprovalDefinitionID == "" {
+		return nil, errors.Errorf("feishu external approval is not enabled")
+	}
+	return value, nil
+}
@@ -224,12 +224,26 @@ func (r *Runner) findApprovalTemplateForIssue(ctx context.Context, issue *store.
 	if err != nil {
 		return false, errors.Wrap(err, "failed to skip approval step if needed")
 	}
+	autoApprovalRule, stepsAutoApproved, err := r.autoApproveIfNeeded(ctx, issue, riskLevel, payload.Approval)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to auto-approve the issue")
+	}
 
 	if err := updateIssuePayload(ctx, r.store, issue.UID, payload); err != nil {
 		return false, errors.Wrap(err, "failed to update issue payload")
 	}
+	// The pending step is tracked by the chain runner from scratch for the escalations and the external approvers.
+	if common.FeatureFlag(common.FeatureFlagApprovalChain) {
+		if err := r.store.UpsertIssueApprovalStep(ctx, &store.IssueApprovalStepMessage{
+			IssueUID:  issue.UID,
+			RiskLevel: riskLevel,
+			Step:      -1,
+		}); err != nil {
+			return false, errors.Wrap(err, "failed to reset issue approval step")
+		}
+	}
 
-	if stepsSkipped > 0 {
+	if stepsSkipped+stepsAutoApproved > 0 {
 		// It's ok to fail to create activity.
 		if err := func() error {
 			activityPayload, err := protojson.Marshal(&storepb.ActivityIssueCommentCreatePayload{
@@ -244,7 +258,7 @@ func (r *Runner) findApprovalTemplateForIssue(ctx context.Context, issue *store.
 				return err
 			}
 
-			for i := 0; i < stepsSkipped; i++ {
+			for i := 0; i < stepsSkipped+stepsAutoApproved; i++ {
 				create := &api.ActivityCreate{
 					CreatorID:   api.SystemBotID,
 					ContainerID: issue.UID,
@@ -253,6 +267,9 @@ func (r *Runner) findApprovalTemplateForIssue(ctx context.Context, issue *store.
 					Comment:     "",
 					Payload:     string(activityPayload),
 				}
+				if i == stepsSkipped && autoApprovalRule != nil {
+					create.Comment = fmt.Sprintf("Auto-approved by rule %q.", autoApprovalRule.ID)
+				}
 				if _, err := r.activityManager.CreateActivity(ctx, create, &activity.Metadata{}); err != nil {
 					return err
 				}
@@ -356,17 +373,21 @@ func getReportResult(ctx context.Context, s *store.Store, task *store.TaskMessag
 }
 
 func getTaskRiskLevel(ctx context.Context, s *store.Store, issue *store.IssueMessage, task *store.TaskMessage, risks []*store.RiskMessage) (int64, bool, error) {
-	// Fall through to "DEFAULT" risk level if risks are empty.
-	if len(risks) == 0 {
-		return 0, true, nil
-	}
-
 	instance, err := s.GetInstanceV2(ctx, &store.FindInstanceMessage{
 		UID: &task.InstanceID,
 	})
 	if err != nil {
 		return 0, false, err
 	}
+	affectedRowsApprovalPolicy, err := getAffectedRowsApprovalPolicy(ctx, s, instance.EnvironmentID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// Fall through to "DEFAULT" risk level if risks are empty and the affected rows of the task are not limited.
+	if len(risks) == 0 && affectedRowsApprovalPolicy.MaxAffectedRows == 0 {
+		return 0, true, nil
+	}
 
 	var affectedRowsReportResult, statementTypeReportResult []api.TaskCheckResult
 	if api.IsTaskCheckReportSupported(instance.Engine) && api.IsTaskCheckReportNeededForTaskType(task.Type) {
@@ -492,9 +513,45 @@ func getTaskRiskLevel(ctx context.Context, s *store.Store, issue *store.IssueMes
 		}
 	}
 
+	// Escalate the risk level if the total estimated affected rows exceed the threshold of the environment.
+	if affectedRowsApprovalPolicy.MaxAffectedRows > 0 && getTotalAffectedRows(affectedRowsReportResult) > affectedRowsApprovalPolicy.MaxAffectedRows {
+		if affectedRowsApprovalPolicy.RiskLevel > maxRisk {
+			maxRisk = affectedRowsApprovalPolicy.RiskLevel
+		}
+	}
+
 	return maxRisk, true, nil
 }
 
+func getAffectedRowsApprovalPolicy(ctx context.Context, s *store.Store, environmentID string) (*api.AffectedRowsApprovalPolicy, error) {
+	environment, err := s.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{
+		ResourceID: &environmentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if environment == nil {
+		return nil, errors.Errorf("environment %q not found", environmentID)
+	}
+	return s.GetAffectedRowsApprovalPolicy(ctx, environment.UID)
+}
+
+// getTotalAffectedRows sums up the estimated affected rows of the statements, the failed estimations are skipped.
+func getTotalAffectedRows(affectedRowsReportResult []api.TaskCheckResult) int64 {
+	var total int64
+	for _, result := range affectedRowsReportResult {
+		if result.Code != common.Ok.Int() {
+			continue
+		}
+		affectedRows, err := strconv.ParseInt(result.Content, 10, 64)
+		if err != nil {
+			continue
+		}
+		total += affectedRows
+	}
+	return total
+}
+
 func updateIssuePayload(ctx context.Context, s *store.Store, issueID int, payload *storepb.IssuePayload) error {
 	payloadBytes, err := protojson.Marshal(payload)
 	if err != nil {
@@ -0,0 +1,202 @@
+// Package auditsink is a runner that streams the audit entries to the audit sinks configured in the workspace.
+package auditsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/auditsink"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	auditSinkStreamInterval = 5 * time.Second
+	// auditSinkBatchSize is the number of the entries sent to a sink in a request.
+	auditSinkBatchSize = 100
+	// auditSinkMaxBatchesPerTick limits the entries sent to a sink in a tick, so that a sink catching up doesn't
+	// block the others.
+	auditSinkMaxBatchesPerTick = 10
+	// auditSinkMaxBackoff is the maximum interval between the retries of a failing sink.
+	auditSinkMaxBackoff = 5 * time.Minute
+)
+
+// NewStreamer creates a new audit sink streamer.
+func NewStreamer(store *store.Store) *Streamer {
+	return &Streamer{
+		store:   store,
+		retries: make(map[string]*retry),
+	}
+}
+
+// Streamer is the audit sink streamer. The delivery progress of each sink is persisted as a cursor on the activity
+// ID, which only advances after the sink accepts a batch. The entries of a failing sink are therefore buffered in
+// the activity table and sent again with exponential backoff, in the same order.
+type Streamer struct {
+	store *store.Store
+	// retries is the backoff of the failing sinks by the sink ID.
+	retries map[string]*retry
+}
+
+type retry struct {
+	failures int
+	nextTime time.Time
+}
+
+// Run will run the audit sink streamer.
+func (s *Streamer) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(auditSinkStreamInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Audit sink streamer started and will run every %s", auditSinkStreamInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Audit sink streamer received context cancellation")
+			return
+		case <-ticker.C:
+			s.stream(ctx, time.Now())
+		}
+	}
+}
+
+func (s *Streamer) stream(ctx context.Context, now time.Time) {
+	settingName := api.SettingWorkspaceAuditSink
+	setting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &settingName})
+	if err != nil {
+		log.Error("Failed to get audit sink setting", zap.Error(err))
+		return
+	}
+	if setting == nil || setting.Value == "" {
+		return
+	}
+	value, err := api.ValidateAndGetAuditSinkSetting(setting.Value)
+	if err != nil {
+		log.Error("Invalid audit sink setting", zap.Error(err))
+		return
+	}
+	for _, sink := range value.SinkList {
+		if sink.Disabled {
+			continue
+		}
+		if r, ok := s.retries[sink.ID]; ok && now.Before(r.nextTime) {
+			continue
+		}
+		if err := s.streamSink(ctx, sink); err != nil {
+			r, ok := s.retries[sink.ID]
+			if !ok {
+				r = &retry{}
+				s.retries[sink.ID] = r
+			}
+			r.failures++
+			backoff := auditSinkMaxBackoff
+			if r.failures < 10 {
+				if b := auditSinkStreamInterval << (r.failures - 1); b < backoff {
+					backoff = b
+				}
+			}
+			r.nextTime = now.Add(backoff)
+			log.Warn("Failed to stream audit entries to sink, will retry later",
+				zap.String("sink", sink.ID),
+				zap.Int("failures", r.failures),
+				zap.Duration("backoff", backoff),
+				zap.Error(err))
+			continue
+		}
+		delete(s.retries, sink.ID)
+	}
+}
+
+// streamSink sends the entries after the cursor of the sink in batches, and advances the cursor after each batch is
+// accepted.
+func (s *Streamer) streamSink(ctx context.Context, sink *api.AuditSink) error {
+	cursor, err := s.store.GetAuditSinkCursor(ctx, sink.ID)
+	if err != nil {
+		return err
+	}
+	if cursor == nil {
+		// A new sink streams the entries from now on.
+		latest, err := s.latestActivityID(ctx)
+		if err != nil {
+			return err
+		}
+		return s.store.UpsertAuditSinkCursor(ctx, sink.ID, latest)
+	}
+
+	sender, err := auditsink.NewSender(sink.Config())
+	if err != nil {
+		return err
+	}
+	afterID := *cursor
+	for i := 0; i < auditSinkMaxBatchesPerTick; i++ {
+		limit := auditSinkBatchSize
+		order := api.ASC
+		activityList, err := s.store.FindActivity(ctx, &api.ActivityFind{
+			TypePrefixList: api.AuditActivityTypePrefixList,
+			AfterID:        &afterID,
+			Order:          &order,
+			Limit:          &limit,
+		})
+		if err != nil {
+			return err
+		}
+		if len(activityList) == 0 {
+			return nil
+		}
+		var entries []*auditsink.Entry
+		for _, activity := range activityList {
+			entries = append(entries, convertToEntry(activity))
+		}
+		if err := sender.Send(ctx, entries); err != nil {
+			return err
+		}
+		afterID = activityList[len(activityList)-1].ID
+		if err := s.store.UpsertAuditSinkCursor(ctx, sink.ID, afterID); err != nil {
+			return err
+		}
+		if len(activityList) < auditSinkBatchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *Streamer) latestActivityID(ctx context.Context) (int, error) {
+	limit := 1
+	order := api.DESC
+	activityList, err := s.store.FindActivity(ctx, &api.ActivityFind{
+		Order: &order,
+		Limit: &limit,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(activityList) == 0 {
+		return 0, nil
+	}
+	return activityList[0].ID, nil
+}
+
+func convertToEntry(activity *api.Activity) *auditsink.Entry {
+	entry := &auditsink.Entry{
+		ID:          activity.ID,
+		CreatedTs:   activity.CreatedTs,
+		Type:        string(activity.Type),
+		Level:       string(activity.Level),
+		ContainerID: activity.ContainerID,
+		Comment:     activity.Comment,
+	}
+	if activity.Creator != nil {
+		entry.CreatorEmail = activity.Creator.Email
+	}
+	if activity.Payload != "" && json.Valid([]byte(activity.Payload)) {
+		entry.Payload = json.RawMessage(activity.Payload)
+	}
+	return entry
+}
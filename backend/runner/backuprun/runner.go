@@ -296,7 +296,7 @@ func (r *Runner) downloadBinlogFilesForInstance(ctx context.Context, instance *s
 	}
 	defer driver.Close(ctx)
 
-	mysqlDriver, ok := driver.(*mysql.Driver)
+	mysqlDriver, ok := db.UnwrapDriver(driver).(*mysql.Driver)
 	if !ok {
 		log.Error("Failed to cast driver to mysql.Driver", zap.String("instance", instance.ResourceID))
 		return
@@ -347,8 +347,8 @@ func (r *Runner) startAutoBackups(ctx context.Context) {
 		if instance.Deleted {
 			continue
 		}
-		// backup for ClickHouse, Snowflake, MongoDB, Spanner, Redis, Oracle is not supported.
-		if instance.Engine == db.ClickHouse || instance.Engine == db.Snowflake || instance.Engine == db.MongoDB || instance.Engine == db.Spanner || instance.Engine == db.Redis || instance.Engine == db.Oracle {
+		// backup for ClickHouse, Snowflake, MongoDB, Spanner, Redis, Oracle, Elasticsearch, Cassandra, DynamoDB, Neo4j is not supported.
+		if instance.Engine == db.ClickHouse || instance.Engine == db.Snowflake || instance.Engine == db.MongoDB || instance.Engine == db.Spanner || instance.Engine == db.Redis || instance.Engine == db.Oracle || instance.Engine == db.Elasticsearch || instance.Engine == db.Cassandra || instance.Engine == db.DynamoDB || instance.Engine == db.Neo4j {
 			continue
 		}
 		environment, err := r.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{ResourceID: &instance.EnvironmentID})
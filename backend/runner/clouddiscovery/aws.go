@@ -0,0 +1,79 @@
+package clouddiscovery
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// listAWSInstances lists the RDS instances in the regions, including the Aurora cluster instances.
+func listAWSInstances(ctx context.Context, regions []string) ([]*cloudInstance, error) {
+	if len(regions) == 0 {
+		// Use the region of the default AWS config.
+		regions = []string{""}
+	}
+	var cloudInstances []*cloudInstance
+	for _, region := range regions {
+		var optFns []func(*awsconfig.LoadOptions) error
+		if region != "" {
+			optFns = append(optFns, awsconfig.WithRegion(region))
+		}
+		cfg, err := awsconfig.LoadDefaultConfig(ctx, optFns...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load AWS config")
+		}
+		paginator := rds.NewDescribeDBInstancesPaginator(rds.NewFromConfig(cfg), &rds.DescribeDBInstancesInput{})
+		for paginator.HasMorePages() {
+			output, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to describe RDS instances in region %q", cfg.Region)
+			}
+			for _, instance := range output.DBInstances {
+				engine := getAWSEngine(aws.ToString(instance.Engine))
+				// The endpoint is not available until the instance is created.
+				if engine == db.UnknownType || instance.Endpoint == nil {
+					continue
+				}
+				labels := make(map[string]string)
+				for _, tag := range instance.TagList {
+					labels[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+				cloudInstances = append(cloudInstances, &cloudInstance{
+					externalID: aws.ToString(instance.DBInstanceArn),
+					name:       aws.ToString(instance.DBInstanceIdentifier),
+					engine:     engine,
+					host:       aws.ToString(instance.Endpoint.Address),
+					port:       strconv.Itoa(int(instance.Endpoint.Port)),
+					region:     cfg.Region,
+					labels:     labels,
+				})
+			}
+		}
+	}
+	return cloudInstances, nil
+}
+
+// getAWSEngine returns the engine of the RDS engine name, such as aurora-postgresql and sqlserver-ee.
+func getAWSEngine(engine string) db.Type {
+	switch {
+	case strings.HasPrefix(engine, "aurora-postgresql"), strings.HasPrefix(engine, "postgres"):
+		return db.Postgres
+	case strings.HasPrefix(engine, "aurora"), strings.HasPrefix(engine, "mysql"):
+		return db.MySQL
+	case strings.HasPrefix(engine, "mariadb"):
+		return db.MariaDB
+	case strings.HasPrefix(engine, "sqlserver"):
+		return db.MSSQL
+	case strings.HasPrefix(engine, "oracle"):
+		return db.Oracle
+	default:
+		return db.UnknownType
+	}
+}
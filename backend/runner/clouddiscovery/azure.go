@@ -0,0 +1,111 @@
+package clouddiscovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+const azureManagementEndpoint = "https://management.azure.com"
+
+// azureResourceType is the Azure database resource type to list in the subscriptions.
+type azureResourceType struct {
+	provider   string
+	apiVersion string
+	engine     db.Type
+	port       string
+}
+
+var azureResourceTypes = []azureResourceType{
+	{provider: "Microsoft.DBforPostgreSQL/flexibleServers", apiVersion: "2022-12-01", engine: db.Postgres, port: "5432"},
+	{provider: "Microsoft.DBforMySQL/flexibleServers", apiVersion: "2021-05-01", engine: db.MySQL, port: "3306"},
+	{provider: "Microsoft.Sql/servers", apiVersion: "2021-11-01", engine: db.MSSQL, port: "1433"},
+}
+
+type azureServerList struct {
+	Value []struct {
+		ID         string            `json:"id"`
+		Name       string            `json:"name"`
+		Location   string            `json:"location"`
+		Tags       map[string]string `json:"tags"`
+		Properties struct {
+			FullyQualifiedDomainName string `json:"fullyQualifiedDomainName"`
+		} `json:"properties"`
+	} `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// listAzureInstances lists the Azure Database flexible servers and the Azure SQL servers in the subscriptions
+// with the default Azure credential.
+func listAzureInstances(ctx context.Context, subscriptions []string) ([]*cloudInstance, error) {
+	credential, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Azure credential")
+	}
+	token, err := credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{azureManagementEndpoint + "/.default"}})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Azure management token")
+	}
+
+	var cloudInstances []*cloudInstance
+	for _, subscription := range subscriptions {
+		for _, resourceType := range azureResourceTypes {
+			url := fmt.Sprintf("%s/subscriptions/%s/providers/%s?api-version=%s", azureManagementEndpoint, subscription, resourceType.provider, resourceType.apiVersion)
+			for url != "" {
+				serverList, err := getAzureServerList(ctx, url, token.Token)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to list %s in subscription %q", resourceType.provider, subscription)
+				}
+				for _, server := range serverList.Value {
+					if server.Properties.FullyQualifiedDomainName == "" {
+						continue
+					}
+					cloudInstances = append(cloudInstances, &cloudInstance{
+						externalID: server.ID,
+						name:       server.Name,
+						engine:     resourceType.engine,
+						host:       server.Properties.FullyQualifiedDomainName,
+						port:       resourceType.port,
+						region:     server.Location,
+						labels:     server.Tags,
+					})
+				}
+				url = serverList.NextLink
+			}
+		}
+	}
+	return cloudInstances, nil
+}
+
+func getAzureServerList(ctx context.Context, url, token string) (*azureServerList, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("status code %d: %s", resp.StatusCode, string(body))
+	}
+	serverList := &azureServerList{}
+	if err := json.Unmarshal(body, serverList); err != nil {
+		return nil, err
+	}
+	return serverList, nil
+}
@@ -0,0 +1,291 @@
+// Package clouddiscovery is a runner that discovers the database instances from the cloud providers.
+// The unregistered instances are proposed for onboarding, and the registered ones deleted upstream are flagged.
+package clouddiscovery
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	discoveryInterval = time.Hour
+	// discoveryTimeout is the timeout of listing the instances from all the cloud providers.
+	discoveryTimeout = 5 * time.Minute
+)
+
+// cloudInstance is the database instance listed from a cloud provider.
+type cloudInstance struct {
+	externalID string
+	name       string
+	engine     db.Type
+	host       string
+	port       string
+	region     string
+	labels     map[string]string
+}
+
+// NewDiscoverer creates a new cloud instance discoverer.
+func NewDiscoverer(store *store.Store) *Discoverer {
+	return &Discoverer{
+		store: store,
+	}
+}
+
+// Discoverer is the cloud instance discoverer.
+type Discoverer struct {
+	store *store.Store
+	// mu serializes the scheduled and the manually triggered discoveries.
+	mu sync.Mutex
+}
+
+// Run will run the cloud instance discoverer.
+func (d *Discoverer) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(discoveryInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Cloud instance discoverer started and will run every %s", discoveryInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Cloud instance discoverer received context cancellation")
+			return
+		case <-ticker.C:
+			log.Debug("Cloud instance discoverer received tick")
+			if err := d.Discover(ctx); err != nil {
+				log.Error("Failed to discover cloud instances", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Discover lists the instances from the configured cloud providers and reconciles them with the registered instances.
+// The providers failed to list are skipped, so that their instances are not flagged as deleted upstream.
+func (d *Discoverer) Discover(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr, ok := r.(error)
+			if !ok {
+				panicErr = errors.Errorf("%v", r)
+			}
+			log.Error("cloud instance discoverer PANIC RECOVER", zap.Error(panicErr), zap.Stack("panic-stack"))
+			err = panicErr
+		}
+	}()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	config, err := d.getConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return nil
+	}
+	environmentMap, err := d.getEnvironmentMap(ctx, config.EnvironmentMappings)
+	if err != nil {
+		return err
+	}
+	instances, err := d.store.ListInstancesV2(ctx, &store.FindInstanceMessage{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list instances")
+	}
+	registered := make(map[string]*store.InstanceMessage)
+	for _, instance := range instances {
+		if instance.Deleted {
+			continue
+		}
+		for _, dataSource := range instance.DataSources {
+			registered[endpointKey(dataSource.Host, dataSource.Port)] = instance
+			// The Cloud SQL instances connected through the connector are registered with the connection name.
+			if dataSource.GCPCloudSQLConnectionName != "" {
+				registered[dataSource.GCPCloudSQLConnectionName] = instance
+			}
+		}
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+	for _, provider := range config.Providers {
+		cloudInstances, err := listCloudInstances(listCtx, provider)
+		if err != nil {
+			log.Warn("Failed to list cloud instances", zap.String("provider", string(provider.Provider)), zap.Error(err))
+			continue
+		}
+		if err := d.reconcile(ctx, provider.Provider, cloudInstances, registered, config.EnvironmentMappings, environmentMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Discoverer) getConfig(ctx context.Context) (*api.SettingWorkspaceCloudDiscoveryValue, error) {
+	settingName := api.SettingWorkspaceCloudDiscovery
+	setting, err := d.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", settingName)
+	}
+	if setting == nil || setting.Value == "" {
+		return nil, nil
+	}
+	config, err := api.ValidateAndGetCloudDiscoverySetting(setting.Value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid setting %s", settingName)
+	}
+	return config, nil
+}
+
+// getEnvironmentMap returns the environment UIDs of the environment mapping rules by the resource ID.
+func (d *Discoverer) getEnvironmentMap(ctx context.Context, rules []*api.EnvironmentMappingRule) (map[string]int, error) {
+	environmentMap := make(map[string]int)
+	for _, rule := range rules {
+		if _, ok := environmentMap[rule.Environment]; ok {
+			continue
+		}
+		environmentID := rule.Environment
+		environment, err := d.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{ResourceID: &environmentID})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get environment %q", environmentID)
+		}
+		if environment == nil || environment.Deleted {
+			log.Warn("Environment in the environment mapping rule not found", zap.String("environment", environmentID))
+			continue
+		}
+		environmentMap[environmentID] = environment.UID
+	}
+	return environmentMap, nil
+}
+
+// reconcile upserts the listed instances of the provider, flags the registered ones no longer listed as deleted upstream,
+// and deletes the unregistered ones no longer listed.
+func (d *Discoverer) reconcile(ctx context.Context, provider api.CloudProvider, cloudInstances []*cloudInstance, registered map[string]*store.InstanceMessage, rules []*api.EnvironmentMappingRule, environmentMap map[string]int) error {
+	oldList, err := d.store.ListDiscoveredInstances(ctx, &store.FindDiscoveredInstanceMessage{Provider: &provider})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list discovered instances")
+	}
+	oldMap := make(map[string]*store.DiscoveredInstanceMessage)
+	for _, discoveredInstance := range oldList {
+		oldMap[discoveredInstance.ExternalID] = discoveredInstance
+	}
+
+	now := time.Now().Unix()
+	seen := make(map[string]bool)
+	for _, cloudInstance := range cloudInstances {
+		seen[cloudInstance.externalID] = true
+		upsert := &store.DiscoveredInstanceMessage{
+			Provider:   provider,
+			ExternalID: cloudInstance.externalID,
+			Name:       cloudInstance.name,
+			Engine:     cloudInstance.engine,
+			Host:       cloudInstance.host,
+			Port:       cloudInstance.port,
+			Region:     cloudInstance.region,
+			Labels:     cloudInstance.labels,
+			Status:     api.DiscoveredInstanceProposed,
+			LastSeenTs: now,
+		}
+		if rule := matchEnvironmentMappingRule(rules, cloudInstance); rule != nil {
+			if environmentUID, ok := environmentMap[rule.Environment]; ok {
+				upsert.EnvironmentUID = &environmentUID
+			}
+		}
+		old := oldMap[cloudInstance.externalID]
+		if instance := getRegistered(registered, cloudInstance); instance != nil {
+			upsert.InstanceUID = &instance.UID
+			upsert.Status = api.DiscoveredInstanceRegistered
+		} else if old != nil && old.InstanceUID != nil && isRegistered(registered, *old.InstanceUID) {
+			// Keep the instance linked on registering, whose host may differ from the listed one, e.g. the private IP.
+			upsert.InstanceUID = old.InstanceUID
+			upsert.Status = api.DiscoveredInstanceRegistered
+		} else if old != nil && old.Status == api.DiscoveredInstanceDismissed {
+			upsert.Status = api.DiscoveredInstanceDismissed
+		}
+		if _, err := d.store.UpsertDiscoveredInstance(ctx, upsert); err != nil {
+			return err
+		}
+	}
+
+	for _, old := range oldList {
+		if seen[old.ExternalID] {
+			continue
+		}
+		if old.InstanceUID != nil && isRegistered(registered, *old.InstanceUID) {
+			if old.Status == api.DiscoveredInstanceDeletedUpstream {
+				continue
+			}
+			status := api.DiscoveredInstanceDeletedUpstream
+			if _, err := d.store.UpdateDiscoveredInstance(ctx, &store.UpdateDiscoveredInstanceMessage{ID: old.ID, Status: &status}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := d.store.DeleteDiscoveredInstance(ctx, old.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func listCloudInstances(ctx context.Context, provider *api.CloudDiscoveryProvider) ([]*cloudInstance, error) {
+	switch provider.Provider {
+	case api.CloudProviderAWS:
+		return listAWSInstances(ctx, provider.Regions)
+	case api.CloudProviderGCP:
+		return listGCPInstances(ctx, provider.Projects)
+	case api.CloudProviderAzure:
+		return listAzureInstances(ctx, provider.Subscriptions)
+	default:
+		return nil, errors.Errorf("unsupported cloud provider %q", provider.Provider)
+	}
+}
+
+// matchEnvironmentMappingRule returns the first rule matching the instance, nil if none matches.
+func matchEnvironmentMappingRule(rules []*api.EnvironmentMappingRule, instance *cloudInstance) *api.EnvironmentMappingRule {
+	for _, rule := range rules {
+		if rule.LabelKey != "" {
+			value, ok := instance.labels[rule.LabelKey]
+			if !ok || (rule.LabelValue != "" && value != rule.LabelValue) {
+				continue
+			}
+		}
+		if rule.NamePattern != "" {
+			if matched, err := path.Match(rule.NamePattern, instance.name); err != nil || !matched {
+				continue
+			}
+		}
+		return rule
+	}
+	return nil
+}
+
+func getRegistered(registered map[string]*store.InstanceMessage, cloudInstance *cloudInstance) *store.InstanceMessage {
+	if instance, ok := registered[endpointKey(cloudInstance.host, cloudInstance.port)]; ok {
+		return instance
+	}
+	return registered[cloudInstance.externalID]
+}
+
+func isRegistered(registered map[string]*store.InstanceMessage, instanceUID int) bool {
+	for _, instance := range registered {
+		if instance.UID == instanceUID {
+			return true
+		}
+	}
+	return false
+}
+
+func endpointKey(host, port string) string {
+	return fmt.Sprintf("%s:%s", strings.ToLower(strings.TrimSuffix(host, ".")), port)
+}
@@ -0,0 +1,71 @@
+package clouddiscovery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestMatchEnvironmentMappingRule(t *testing.T) {
+	rules := []*api.EnvironmentMappingRule{
+		{LabelKey: "env", LabelValue: "production", Environment: "prod"},
+		{NamePattern: "staging-*", Environment: "staging"},
+		{LabelKey: "team", NamePattern: "*-test", Environment: "test"},
+	}
+	tests := []struct {
+		instance *cloudInstance
+		want     string
+	}{
+		{
+			instance: &cloudInstance{name: "orders", labels: map[string]string{"env": "production"}},
+			want:     "prod",
+		},
+		{
+			instance: &cloudInstance{name: "staging-orders", labels: map[string]string{"env": "staging"}},
+			want:     "staging",
+		},
+		{
+			instance: &cloudInstance{name: "orders-test", labels: map[string]string{"team": "payment"}},
+			want:     "test",
+		},
+		{
+			// The name matches but the label key is missing.
+			instance: &cloudInstance{name: "orders-test"},
+			want:     "",
+		},
+	}
+
+	for _, test := range tests {
+		got := ""
+		if rule := matchEnvironmentMappingRule(rules, test.instance); rule != nil {
+			got = rule.Environment
+		}
+		assert.Equal(t, test.want, got, test.instance.name)
+	}
+}
+
+func TestGetAWSEngine(t *testing.T) {
+	tests := map[string]db.Type{
+		"mysql":             db.MySQL,
+		"aurora-mysql":      db.MySQL,
+		"aurora":            db.MySQL,
+		"postgres":          db.Postgres,
+		"aurora-postgresql": db.Postgres,
+		"mariadb":           db.MariaDB,
+		"sqlserver-ee":      db.MSSQL,
+		"oracle-se2":        db.Oracle,
+		"neptune":           db.UnknownType,
+	}
+
+	for engine, want := range tests {
+		assert.Equal(t, want, getAWSEngine(engine), engine)
+	}
+}
+
+func TestEndpointKey(t *testing.T) {
+	assert.Equal(t, endpointKey("orders.abc.us-east-1.rds.amazonaws.com", "3306"), endpointKey("Orders.abc.us-east-1.rds.amazonaws.com.", "3306"))
+	assert.NotEqual(t, endpointKey("orders", "3306"), endpointKey("orders", "3307"))
+}
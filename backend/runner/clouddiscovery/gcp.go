@@ -0,0 +1,76 @@
+package clouddiscovery
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// listGCPInstances lists the Cloud SQL instances in the projects with the application default credentials.
+func listGCPInstances(ctx context.Context, projects []string) ([]*cloudInstance, error) {
+	service, err := sqladmin.NewService(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Cloud SQL Admin service")
+	}
+	var cloudInstances []*cloudInstance
+	for _, project := range projects {
+		if err := service.Instances.List(project).Pages(ctx, func(resp *sqladmin.InstancesListResponse) error {
+			for _, instance := range resp.Items {
+				engine, port := getGCPEngine(instance.DatabaseVersion)
+				host := getGCPHost(instance.IpAddresses)
+				if engine == db.UnknownType || host == "" {
+					continue
+				}
+				var labels map[string]string
+				if instance.Settings != nil {
+					labels = instance.Settings.UserLabels
+				}
+				cloudInstances = append(cloudInstances, &cloudInstance{
+					externalID: instance.ConnectionName,
+					name:       instance.Name,
+					engine:     engine,
+					host:       host,
+					port:       port,
+					region:     instance.Region,
+					labels:     labels,
+				})
+			}
+			return nil
+		}); err != nil {
+			return nil, errors.Wrapf(err, "failed to list Cloud SQL instances in project %q", project)
+		}
+	}
+	return cloudInstances, nil
+}
+
+// getGCPEngine returns the engine and the default port of the Cloud SQL database version, such as POSTGRES_14.
+func getGCPEngine(databaseVersion string) (db.Type, string) {
+	switch {
+	case strings.HasPrefix(databaseVersion, "MYSQL"):
+		return db.MySQL, "3306"
+	case strings.HasPrefix(databaseVersion, "POSTGRES"):
+		return db.Postgres, "5432"
+	case strings.HasPrefix(databaseVersion, "SQLSERVER"):
+		return db.MSSQL, "1433"
+	default:
+		return db.UnknownType, ""
+	}
+}
+
+// getGCPHost returns the public IP address of the instance, or the private one if the instance doesn't have a public IP.
+func getGCPHost(ipAddresses []*sqladmin.IpMapping) string {
+	host := ""
+	for _, ipAddress := range ipAddresses {
+		switch ipAddress.Type {
+		case "PRIMARY":
+			return ipAddress.IpAddress
+		case "PRIVATE":
+			host = ipAddress.IpAddress
+		}
+	}
+	return host
+}
@@ -0,0 +1,460 @@
+// Package databaseclone is a runner that copies the databases into the lower environments, masking the classified
+// columns during the copy.
+package databaseclone
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/masker"
+	"github.com/bytebase/bytebase/backend/runner/schemasync"
+	"github.com/bytebase/bytebase/backend/store"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+const (
+	databaseCloneInterval = 10 * time.Second
+	// insertBatchSize is the number of the rows inserted by a statement.
+	insertBatchSize = 500
+	// maxPlaceholderCount is the most placeholders in a statement allowed by both MySQL and Postgres.
+	maxPlaceholderCount = 65535
+)
+
+// NewCloner creates a new database cloner.
+func NewCloner(store *store.Store, dbFactory *dbfactory.DBFactory, schemaSyncer *schemasync.Syncer, maskingSecret string) *Cloner {
+	return &Cloner{
+		store:         store,
+		dbFactory:     dbFactory,
+		schemaSyncer:  schemaSyncer,
+		maskingSecret: maskingSecret,
+	}
+}
+
+// Cloner is the database cloner copying the pending clones one by one.
+type Cloner struct {
+	store        *store.Store
+	dbFactory    *dbfactory.DBFactory
+	schemaSyncer *schemasync.Syncer
+	// maskingSecret derives the keys of the deterministic masking algorithms, so the masked values are consistent
+	// with the SQL editor and can still be joined across the tables.
+	maskingSecret string
+}
+
+// Run will run the database cloner.
+func (c *Cloner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(databaseCloneInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Database cloner started and will run every %s", databaseCloneInterval.String()))
+	c.failInterrupted(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Database cloner received context cancellation")
+			return
+		case <-ticker.C:
+			log.Debug("Database cloner received tick")
+			c.runPending(ctx)
+		}
+	}
+}
+
+// IsSupported returns whether the engine is supported by the database cloner.
+func IsSupported(engine db.Type) bool {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase, db.Postgres:
+		return true
+	}
+	return false
+}
+
+// failInterrupted fails the clones left running by the last shutdown.
+func (c *Cloner) failInterrupted(ctx context.Context) {
+	clones, err := c.store.ListDatabaseClones(ctx, &store.FindDatabaseCloneMessage{
+		StatusList: []api.DatabaseCloneStatus{api.DatabaseCloneRunning},
+	})
+	if err != nil {
+		log.Error("Failed to list running database clones", zap.Error(err))
+		return
+	}
+	for _, clone := range clones {
+		message := "interrupted by the server restart"
+		if _, err := c.store.UpdateDatabaseClone(ctx, clone.UID, &store.UpdateDatabaseCloneMessage{
+			UpdaterID: api.SystemBotID,
+			OldStatus: api.DatabaseCloneRunning,
+			Status:    api.DatabaseCloneFailed,
+			Error:     &message,
+		}); err != nil {
+			log.Error("Failed to fail interrupted database clone", zap.Int("clone", clone.UID), zap.Error(err))
+		}
+	}
+}
+
+func (c *Cloner) runPending(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = errors.Errorf("%v", r)
+			}
+			log.Error("Database cloner PANIC RECOVER", zap.Error(err), zap.Stack("panic-stack"))
+		}
+	}()
+
+	clones, err := c.store.ListDatabaseClones(ctx, &store.FindDatabaseCloneMessage{
+		StatusList: []api.DatabaseCloneStatus{api.DatabaseClonePending},
+	})
+	if err != nil {
+		log.Error("Failed to list pending database clones", zap.Error(err))
+		return
+	}
+	// The latest clone is listed first, the clones are copied in the order they're created.
+	for i := len(clones) - 1; i >= 0; i-- {
+		claimed, err := c.store.UpdateDatabaseClone(ctx, clones[i].UID, &store.UpdateDatabaseCloneMessage{
+			UpdaterID: api.SystemBotID,
+			OldStatus: api.DatabaseClonePending,
+			Status:    api.DatabaseCloneRunning,
+		})
+		if err != nil {
+			log.Error("Failed to start database clone", zap.Int("clone", clones[i].UID), zap.Error(err))
+			continue
+		}
+		if claimed == nil {
+			continue
+		}
+		update := &store.UpdateDatabaseCloneMessage{
+			UpdaterID: api.SystemBotID,
+			OldStatus: api.DatabaseCloneRunning,
+			Status:    api.DatabaseCloneDone,
+		}
+		if err := c.clone(ctx, claimed); err != nil {
+			log.Debug("Failed to clone database", zap.Int("clone", claimed.UID), zap.Error(err))
+			message := err.Error()
+			update.Status, update.Error = api.DatabaseCloneFailed, &message
+		}
+		if _, err := c.store.UpdateDatabaseClone(ctx, claimed.UID, update); err != nil {
+			log.Error("Failed to finish database clone", zap.Int("clone", claimed.UID), zap.Error(err))
+		}
+	}
+}
+
+// clone copies the schema of the source database into the new target database, then streams the rows of the tables
+// through the maskers of the classified columns.
+func (c *Cloner) clone(ctx context.Context, clone *store.DatabaseCloneMessage) error {
+	source, err := c.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &clone.SourceDatabaseUID})
+	if err != nil {
+		return err
+	}
+	if source == nil {
+		return errors.Errorf("source database %d not found", clone.SourceDatabaseUID)
+	}
+	sourceInstance, err := c.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &source.InstanceID})
+	if err != nil {
+		return err
+	}
+	targetInstance, err := c.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &clone.TargetInstanceUID})
+	if err != nil {
+		return err
+	}
+	if sourceInstance == nil || targetInstance == nil {
+		return errors.Errorf("instance not found")
+	}
+	if !IsSupported(sourceInstance.Engine) || sourceInstance.Engine != targetInstance.Engine {
+		return errors.Errorf("cloning database from %q to %q is not supported", sourceInstance.Engine, targetInstance.Engine)
+	}
+	dbSchema, err := c.store.GetDBSchema(ctx, source.UID)
+	if err != nil {
+		return err
+	}
+	if dbSchema == nil {
+		return errors.Errorf("database schema %d not found", source.UID)
+	}
+	policy, err := c.store.GetSensitiveDataPolicy(ctx, source.UID)
+	if err != nil {
+		return err
+	}
+	var findings []*store.PIIFindingMessage
+	if common.FeatureFlag(common.FeatureFlagPIIScan) {
+		if findings, err = c.store.ListPIIFindings(ctx, &store.FindPIIFindingMessage{DatabaseUID: &source.UID}); err != nil {
+			return err
+		}
+	}
+	maskTypes := getColumnMaskTypes(policy, findings)
+
+	sourceDriver, err := c.dbFactory.GetAdminDatabaseDriver(ctx, sourceInstance, source.DatabaseName)
+	if err != nil {
+		return err
+	}
+	defer sourceDriver.Close(ctx)
+	var schema bytes.Buffer
+	if _, err := sourceDriver.Dump(ctx, &schema, true /* schemaOnly */); err != nil {
+		return errors.Wrapf(err, "failed to dump the schema of database %q", source.DatabaseName)
+	}
+
+	if err := c.createDatabase(ctx, targetInstance, clone.TargetDatabaseName); err != nil {
+		return err
+	}
+	targetDriver, err := c.dbFactory.GetAdminDatabaseDriver(ctx, targetInstance, clone.TargetDatabaseName)
+	if err != nil {
+		return err
+	}
+	defer targetDriver.Close(ctx)
+	if _, err := targetDriver.Execute(ctx, schema.String(), false /* createDatabase */); err != nil {
+		return errors.Wrapf(err, "failed to apply the schema to database %q", clone.TargetDatabaseName)
+	}
+
+	// The rows are inserted in one session without the foreign key checks, so the tables are copied in any order.
+	conn, err := targetDriver.GetDB().Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, getDisableForeignKeyCheckStatement(targetInstance.Engine)); err != nil {
+		return errors.Wrapf(err, "failed to disable the foreign key checks")
+	}
+	tableCount, rowCount := 0, int64(0)
+	for _, schema := range dbSchema.Metadata.Schemas {
+		for _, table := range schema.Tables {
+			maskers, err := c.getColumnMaskers(maskTypes, schema.Name, table)
+			if err != nil {
+				return err
+			}
+			count, err := copyTable(ctx, sourceDriver.GetDB(), conn, sourceInstance.Engine, schema.Name, table, maskers)
+			if err != nil {
+				return errors.Wrapf(err, "failed to copy table %q", table.Name)
+			}
+			tableCount, rowCount = tableCount+1, rowCount+count
+			if _, err := c.store.UpdateDatabaseClone(ctx, clone.UID, &store.UpdateDatabaseCloneMessage{
+				UpdaterID:  api.SystemBotID,
+				OldStatus:  api.DatabaseCloneRunning,
+				Status:     api.DatabaseCloneRunning,
+				TableCount: &tableCount,
+				RowCount:   &rowCount,
+			}); err != nil {
+				log.Debug("Failed to update database clone progress", zap.Int("clone", clone.UID), zap.Error(err))
+			}
+		}
+	}
+
+	database, err := c.store.UpsertDatabase(ctx, &store.DatabaseMessage{
+		ProjectID:            source.ProjectID,
+		EnvironmentID:        targetInstance.EnvironmentID,
+		InstanceID:           targetInstance.ResourceID,
+		DatabaseName:         clone.TargetDatabaseName,
+		SyncState:            api.OK,
+		SuccessfulSyncTimeTs: time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.schemaSyncer.SyncDatabaseSchema(ctx, database, true /* force */); err != nil {
+		log.Error("Failed to sync cloned database schema",
+			zap.String("instance", targetInstance.ResourceID),
+			zap.String("database", database.DatabaseName),
+			zap.Error(err))
+	}
+	return nil
+}
+
+func (c *Cloner) createDatabase(ctx context.Context, instance *store.InstanceMessage, databaseName string) error {
+	driver, err := c.dbFactory.GetAdminDatabaseDriver(ctx, instance, "")
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+	statement := fmt.Sprintf("CREATE DATABASE %s;", quoteIdentifier(instance.Engine, databaseName))
+	if _, err := driver.Execute(ctx, statement, true /* createDatabase */); err != nil {
+		return errors.Wrapf(err, "failed to create database %q", databaseName)
+	}
+	return nil
+}
+
+// columnKey is the key of a column in the mask types.
+type columnKey struct {
+	schema string
+	table  string
+	column string
+}
+
+// getColumnMaskTypes returns the mask types of the classified columns. The columns of the sensitive data policy are
+// masked with their types regardless of the conditions, and the PII findings not dismissed yet are masked with the
+// proposed types, so the unreviewed personal data doesn't leak into the clones.
+func getColumnMaskTypes(policy *api.SensitiveDataPolicy, findings []*store.PIIFindingMessage) map[columnKey]api.SensitiveDataMaskType {
+	maskTypes := make(map[columnKey]api.SensitiveDataMaskType)
+	for _, finding := range findings {
+		if finding.Status == api.PIIFindingDismissed {
+			continue
+		}
+		maskTypes[columnKey{schema: finding.SchemaName, table: finding.TableName, column: finding.ColumnName}] = finding.MaskType
+	}
+	for _, data := range policy.SensitiveDataList {
+		maskTypes[columnKey{schema: data.Schema, table: data.Table, column: data.Column}] = data.Type
+	}
+	return maskTypes
+}
+
+// getColumnMaskers returns the maskers of the table columns, nil for the columns copied as is.
+func (c *Cloner) getColumnMaskers(maskTypes map[columnKey]api.SensitiveDataMaskType, schemaName string, table *storepb.TableMetadata) ([]masker.Masker, error) {
+	maskers := make([]masker.Masker, len(table.Columns))
+	for i, column := range table.Columns {
+		maskType, ok := maskTypes[columnKey{schema: schemaName, table: table.Name, column: column.Name}]
+		if !ok {
+			continue
+		}
+		// The default mask isn't a valid value of the other types, these columns are cleared instead.
+		if (maskType == "" || maskType == api.SensitiveDataMaskTypeDefault) && !isTextType(column.Type) {
+			maskers[i] = nullMasker{}
+			continue
+		}
+		m, err := masker.NewMasker(db.SensitiveDataMaskType(maskType), c.maskingSecret)
+		if err != nil {
+			return nil, err
+		}
+		maskers[i] = m
+	}
+	return maskers, nil
+}
+
+// nullMasker masks the values to NULLs.
+type nullMasker struct{}
+
+func (nullMasker) Mask(any) any {
+	return nil
+}
+
+func isTextType(columnType string) bool {
+	columnType = strings.ToLower(columnType)
+	for _, keyword := range []string{"char", "text", "string", "clob"} {
+		if strings.Contains(columnType, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyTable streams the rows of the source table into the target table in batches, masking the values of the
+// columns with the maskers. It returns the number of the copied rows.
+func copyTable(ctx context.Context, sourceDB *sql.DB, target *sql.Conn, engine db.Type, schemaName string, table *storepb.TableMetadata, maskers []masker.Masker) (int64, error) {
+	if len(table.Columns) == 0 {
+		return 0, nil
+	}
+	var columnNames, quotedColumns []string
+	for _, column := range table.Columns {
+		columnNames = append(columnNames, column.Name)
+		quotedColumns = append(quotedColumns, quoteIdentifier(engine, column.Name))
+	}
+	rows, err := sourceDB.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s", strings.Join(quotedColumns, ", "), quoteTable(engine, schemaName, table.Name)))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	batchSize := insertBatchSize
+	if batchSize*len(columnNames) > maxPlaceholderCount {
+		batchSize = maxPlaceholderCount / len(columnNames)
+	}
+	var count int64
+	var batch [][]any
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		var args []any
+		for _, row := range batch {
+			args = append(args, row...)
+		}
+		if _, err := target.ExecContext(ctx, buildInsertStatement(engine, schemaName, table.Name, columnNames, len(batch)), args...); err != nil {
+			return err
+		}
+		count += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+	for rows.Next() {
+		values := make([]any, len(columnNames))
+		scanArgs := make([]any, len(columnNames))
+		for i := range values {
+			scanArgs[i] = &values[i]
+		}
+		if err := rows.Scan(scanArgs...); err != nil {
+			return count, err
+		}
+		batch = append(batch, maskRow(values, maskers))
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+	return count, flush()
+}
+
+// maskRow masks the values of the row in place with the maskers of the columns.
+func maskRow(values []any, maskers []masker.Masker) []any {
+	for i, m := range maskers {
+		if m != nil {
+			values[i] = m.Mask(values[i])
+		}
+	}
+	return values
+}
+
+// buildInsertStatement builds the statement inserting the rows of the columns with the placeholders.
+func buildInsertStatement(engine db.Type, schemaName, tableName string, columnNames []string, rowCount int) string {
+	var quotedColumns []string
+	for _, columnName := range columnNames {
+		quotedColumns = append(quotedColumns, quoteIdentifier(engine, columnName))
+	}
+	var valueList []string
+	for i := 0; i < rowCount; i++ {
+		var placeholders []string
+		for j := range columnNames {
+			if engine == db.Postgres {
+				placeholders = append(placeholders, fmt.Sprintf("$%d", i*len(columnNames)+j+1))
+			} else {
+				placeholders = append(placeholders, "?")
+			}
+		}
+		valueList = append(valueList, fmt.Sprintf("(%s)", strings.Join(placeholders, ", ")))
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", quoteTable(engine, schemaName, tableName), strings.Join(quotedColumns, ", "), strings.Join(valueList, ", "))
+}
+
+func getDisableForeignKeyCheckStatement(engine db.Type) string {
+	if engine == db.Postgres {
+		// The replica role skips the triggers, including the ones of the foreign keys.
+		return "SET session_replication_role = replica"
+	}
+	return "SET FOREIGN_KEY_CHECKS = 0"
+}
+
+func quoteTable(engine db.Type, schemaName, tableName string) string {
+	if engine == db.Postgres {
+		return fmt.Sprintf("%s.%s", quoteIdentifier(engine, schemaName), quoteIdentifier(engine, tableName))
+	}
+	return quoteIdentifier(engine, tableName)
+}
+
+func quoteIdentifier(engine db.Type, identifier string) string {
+	quote := "`"
+	if engine == db.Postgres {
+		quote = `"`
+	}
+	return quote + strings.ReplaceAll(identifier, quote, quote+quote) + quote
+}
@@ -0,0 +1,51 @@
+package databaseclone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/masker"
+	"github.com/bytebase/bytebase/backend/store"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func TestBuildInsertStatement(t *testing.T) {
+	a := require.New(t)
+	a.Equal("INSERT INTO `t` (`id`, `a``b`) VALUES (?, ?), (?, ?)", buildInsertStatement(db.MySQL, "", "t", []string{"id", "a`b"}, 2))
+	a.Equal(`INSERT INTO "public"."t" ("id", "name") VALUES ($1, $2), ($3, $4)`, buildInsertStatement(db.Postgres, "public", "t", []string{"id", "name"}, 2))
+}
+
+func TestGetColumnMaskers(t *testing.T) {
+	a := require.New(t)
+	maskTypes := getColumnMaskTypes(&api.SensitiveDataPolicy{
+		SensitiveDataList: []api.SensitiveData{
+			{Table: "user", Column: "email", Type: api.SensitiveDataMaskTypeSHA256},
+			{Table: "user", Column: "age", Type: api.SensitiveDataMaskTypeDefault},
+		},
+	}, []*store.PIIFindingMessage{
+		{TableName: "user", ColumnName: "email", MaskType: api.SensitiveDataMaskTypeDefault, Status: api.PIIFindingAccepted},
+		{TableName: "user", ColumnName: "phone", MaskType: api.SensitiveDataMaskTypeDefault, Status: api.PIIFindingProposed},
+		{TableName: "user", ColumnName: "name", MaskType: api.SensitiveDataMaskTypeDefault, Status: api.PIIFindingDismissed},
+	})
+	cloner := NewCloner(nil, nil, nil, "secret")
+	maskers, err := cloner.getColumnMaskers(maskTypes, "", &storepb.TableMetadata{
+		Name: "user",
+		Columns: []*storepb.ColumnMetadata{
+			{Name: "id", Type: "int"},
+			{Name: "name", Type: "varchar(255)"},
+			{Name: "email", Type: "varchar(255)"},
+			{Name: "phone", Type: "text"},
+			{Name: "age", Type: "int"},
+		},
+	})
+	a.NoError(err)
+
+	sha256, err := masker.NewMasker(db.SensitiveDataMaskTypeSHA256, "secret")
+	a.NoError(err)
+	got := maskRow([]any{int64(1), "alice", "alice@example.com", "555-0100", int64(30)}, maskers)
+	// The policy overrides the finding of the same column, and the default mask of a number clears it.
+	a.Equal([]any{int64(1), "alice", sha256.Mask("alice@example.com"), "******", nil}, got)
+}
@@ -0,0 +1,80 @@
+// Package databasegrant is a runner that revokes the temporary access to the databases after they expire.
+package databasegrant
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const databaseGrantExpireInterval = 1 * time.Minute
+
+// NewExpirer creates a new database grant expirer.
+func NewExpirer(store *store.Store, activityManager *activity.Manager) *Expirer {
+	return &Expirer{
+		store:           store,
+		activityManager: activityManager,
+	}
+}
+
+// Expirer is the database grant expirer revoking the grants after their durations.
+type Expirer struct {
+	store           *store.Store
+	activityManager *activity.Manager
+}
+
+// Run will run the database grant expirer.
+func (e *Expirer) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(databaseGrantExpireInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Database grant expirer started and will run every %s", databaseGrantExpireInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Database grant expirer received context cancellation")
+			return
+		case <-ticker.C:
+			log.Debug("Database grant expirer received tick")
+			e.expire(ctx, time.Now())
+		}
+	}
+}
+
+func (e *Expirer) expire(ctx context.Context, now time.Time) {
+	nowTs := now.Unix()
+	grants, err := e.store.ListDatabaseGrants(ctx, &store.FindDatabaseGrantMessage{
+		StatusList:     []api.DatabaseGrantStatus{api.DatabaseGrantActive},
+		ExpireTsBefore: &nowTs,
+	})
+	if err != nil {
+		log.Error("Failed to list expired database grants", zap.Error(err))
+		return
+	}
+	for _, grant := range grants {
+		expired, err := e.store.UpdateDatabaseGrant(ctx, grant.UID, &store.UpdateDatabaseGrantMessage{
+			UpdaterID: api.SystemBotID,
+			OldStatus: api.DatabaseGrantActive,
+			Status:    api.DatabaseGrantExpired,
+		})
+		if err != nil {
+			log.Error("Failed to expire database grant", zap.Int("grant", grant.UID), zap.Error(err))
+			continue
+		}
+		// The grant is revoked in the meantime.
+		if expired == nil {
+			continue
+		}
+		if err := e.activityManager.CreateDatabaseGrantActivity(ctx, expired, api.DatabaseGrantActive); err != nil {
+			log.Error("Failed to create database grant activity", zap.Int("grant", grant.UID), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/mail"
+	"github.com/bytebase/bytebase/backend/store"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// Attachment is the file attached to the workspace mail.
+type Attachment struct {
+	Filename    string
+	ContentType mail.ContentType
+	Content     []byte
+}
+
+// SendWorkspaceMail sends the HTML mail to the addresses with the workspace mail delivery setting.
+func SendWorkspaceMail(ctx context.Context, s *store.Store, to []string, subject, body string, attachments []*Attachment) error {
+	name := api.SettingWorkspaceMailDelivery
+	mailSetting, err := s.GetSettingV2(ctx, &store.FindSettingMessage{Name: &name})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get mail setting")
+	}
+	if mailSetting == nil {
+		return errors.Errorf("workspace mail delivery is not configured")
+	}
+	var storeValue storepb.SMTPMailDeliverySetting
+	if err := protojson.Unmarshal([]byte(mailSetting.Value), &storeValue); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal mail setting")
+	}
+	setting := convertStorepbToAPIMailDeliveryValue(&storeValue)
+
+	email := mail.NewEmailMsg()
+	email.SetFrom(fmt.Sprintf("Bytebase <%s>", setting.SMTPFrom)).
+		AddTo(to...).
+		SetSubject(subject).
+		SetBody(body)
+	for _, attachment := range attachments {
+		if _, err := email.Attach(bytes.NewReader(attachment.Content), attachment.Filename, attachment.ContentType); err != nil {
+			return errors.Wrapf(err, "failed to attach %q", attachment.Filename)
+		}
+	}
+	client := mail.NewSMTPClient(setting.SMTPServerHost, setting.SMTPServerPort)
+	client.SetAuthType(convertToMailSMTPAuthType(setting.SMTPAuthenticationType)).
+		SetAuthCredentials(setting.SMTPUsername, *setting.SMTPPassword).
+		SetEncryptionType(convertToMailSMTPEncryptionType(setting.SMTPEncryptionType))
+	if err := client.SendMail(email); err != nil {
+		return errors.Wrapf(err, "failed to send mail to %s", strings.Join(to, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+// Package partitionrun is a runner that maintains the rolling range partitions under the partition retention policies.
+package partitionrun
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+const (
+	partitionRunInterval = 1 * time.Hour
+
+	mysqlPartitionQuery = `
+		SELECT TABLE_NAME, PARTITION_NAME, PARTITION_METHOD, IFNULL(PARTITION_EXPRESSION, ''), IFNULL(PARTITION_DESCRIPTION, '')
+		FROM information_schema.PARTITIONS
+		WHERE TABLE_SCHEMA = ? AND PARTITION_NAME IS NOT NULL
+		ORDER BY TABLE_NAME, PARTITION_ORDINAL_POSITION`
+	// The range partitioned tables without any partition are included with the empty partition name.
+	pgPartitionQuery = `
+		SELECT n.nspname, p.relname, COALESCE(c.relname, '')
+		FROM pg_catalog.pg_partitioned_table pt
+		JOIN pg_catalog.pg_class p ON pt.partrelid = p.oid
+		JOIN pg_catalog.pg_namespace n ON p.relnamespace = n.oid
+		LEFT JOIN pg_catalog.pg_inherits i ON i.inhparent = p.oid
+		LEFT JOIN pg_catalog.pg_class c ON i.inhrelid = c.oid
+		WHERE pt.partstrat = 'r'
+		ORDER BY n.nspname, p.relname, c.relname`
+)
+
+// CreateIssueFunc creates an issue by the creator.
+type CreateIssueFunc func(ctx context.Context, issueCreate *api.IssueCreate, creatorID int) (*api.Issue, error)
+
+// NewRunner creates a new partition runner.
+func NewRunner(store *store.Store, dbFactory *dbfactory.DBFactory, createIssue CreateIssueFunc) *Runner {
+	return &Runner{
+		store:       store,
+		dbFactory:   dbFactory,
+		createIssue: createIssue,
+	}
+}
+
+// Runner is the partition runner generating the partition maintenance issues.
+// The DDL goes through the normal issues, so the changes are reviewed, approved and recorded as the others.
+type Runner struct {
+	store       *store.Store
+	dbFactory   *dbfactory.DBFactory
+	createIssue CreateIssueFunc
+}
+
+// Run will run the partition runner.
+func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(partitionRunInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Partition runner started and will run every %s", partitionRunInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Partition runner received context cancellation")
+			return
+		case <-ticker.C:
+			log.Debug("Partition runner received tick")
+			r.maintainPartitions(ctx)
+		}
+	}
+}
+
+func (r *Runner) maintainPartitions(ctx context.Context) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err, ok := rec.(error)
+			if !ok {
+				err = errors.Errorf("%v", rec)
+			}
+			log.Error("partition runner PANIC RECOVER", zap.Error(err), zap.Stack("panic-stack"))
+		}
+	}()
+
+	resourceType := api.PolicyResourceTypeDatabase
+	pType := api.PolicyTypePartitionRetention
+	policies, err := r.store.ListPoliciesV2(ctx, &store.FindPolicyMessage{
+		ResourceType: &resourceType,
+		Type:         &pType,
+	})
+	if err != nil {
+		log.Error("Failed to list partition retention policies", zap.Error(err))
+		return
+	}
+	for _, policy := range policies {
+		if !policy.Enforce {
+			continue
+		}
+		if err := r.maintainDatabasePartitions(ctx, policy); err != nil {
+			log.Warn("Failed to maintain database partitions",
+				zap.Int("databaseID", policy.ResourceUID),
+				zap.Error(err))
+		}
+	}
+}
+
+func (r *Runner) maintainDatabasePartitions(ctx context.Context, policy *store.PolicyMessage) error {
+	retentionPolicy, err := api.UnmarshalPartitionRetentionPolicy(policy.Payload)
+	if err != nil {
+		return err
+	}
+	if len(retentionPolicy.RuleList) == 0 {
+		return nil
+	}
+	database, err := r.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &policy.ResourceUID})
+	if err != nil {
+		return err
+	}
+	if database == nil || database.SyncState != api.OK {
+		return nil
+	}
+	instance, err := r.store.GetInstanceV2(ctx, &store.FindInstanceMessage{EnvironmentID: &database.EnvironmentID, ResourceID: &database.InstanceID})
+	if err != nil {
+		return err
+	}
+	if instance == nil || instance.Deleted {
+		return nil
+	}
+	if instance.Engine != db.MySQL && instance.Engine != db.Postgres {
+		return nil
+	}
+	project, err := r.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &database.ProjectID})
+	if err != nil {
+		return err
+	}
+	if project == nil || project.UID == api.DefaultProjectUID {
+		return nil
+	}
+
+	issueName := fmt.Sprintf("[%s] Maintain partitions", database.DatabaseName)
+	// Skip the database until the last maintenance issue is closed, otherwise the same DDL would be generated again.
+	creatorID := api.SystemBotID
+	openIssues, err := r.store.ListIssueV2(ctx, &store.FindIssueMessage{
+		ProjectUID: &project.UID,
+		CreatorID:  &creatorID,
+		StatusList: []api.IssueStatus{api.IssueOpen},
+	})
+	if err != nil {
+		return err
+	}
+	for _, issue := range openIssues {
+		if issue.Title == issueName {
+			return nil
+		}
+	}
+
+	tables, err := r.listRangePartitionTables(ctx, instance, database)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var statement string
+	for _, rule := range retentionPolicy.RuleList {
+		key := rule.Table
+		if instance.Engine == db.Postgres {
+			schema := rule.Schema
+			if schema == "" {
+				schema = "public"
+			}
+			key = fmt.Sprintf("%s.%s", schema, rule.Table)
+		}
+		table, ok := tables[key]
+		if !ok {
+			log.Debug("Skip the partition retention rule for the table not range partitioned",
+				zap.String("database", database.DatabaseName),
+				zap.String("table", key))
+			continue
+		}
+		tableStatement, err := utils.GeneratePartitionMaintenanceStatement(instance.Engine, rule, table, now)
+		if err != nil {
+			log.Warn("Failed to generate the partition maintenance statement",
+				zap.String("database", database.DatabaseName),
+				zap.String("table", key),
+				zap.Error(err))
+			continue
+		}
+		if tableStatement == "" {
+			continue
+		}
+		statement += fmt.Sprintf("-- Maintain the partitions of table %s.\n%s\n", key, tableStatement)
+	}
+	if statement == "" {
+		return nil
+	}
+
+	sheet, err := r.store.CreateSheet(ctx, &api.SheetCreate{
+		CreatorID:  api.SystemBotID,
+		ProjectID:  project.UID,
+		DatabaseID: &database.UID,
+		Name:       fmt.Sprintf("Sheet for %s", issueName),
+		Statement:  statement,
+		Visibility: api.ProjectSheet,
+		Source:     api.SheetFromBytebaseArtifact,
+		Type:       api.SheetForSQL,
+		Payload:    "{}",
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the partition maintenance sheet")
+	}
+	createContext, err := json.Marshal(&api.MigrationContext{
+		DetailList: []*api.MigrationDetail{
+			{
+				MigrationType: db.Migrate,
+				DatabaseID:    database.UID,
+				SheetID:       sheet.ID,
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the partition maintenance issue context")
+	}
+	if _, err := r.createIssue(ctx, &api.IssueCreate{
+		ProjectID:   project.UID,
+		Name:        issueName,
+		Type:        api.IssueDatabaseSchemaUpdate,
+		Description: "Creates the future partitions and expires the past partitions under the partition retention policy of the database.",
+		// The system bot lets the issue find the default assignee.
+		AssigneeID:            api.SystemBotID,
+		AssigneeNeedAttention: true,
+		CreateContext:         string(createContext),
+	}, api.SystemBotID); err != nil {
+		return errors.Wrap(err, "failed to create the partition maintenance issue")
+	}
+	return nil
+}
+
+// listRangePartitionTables returns the range partitioned tables keyed by the table name, or the schema qualified
+// table name for PostgreSQL.
+func (r *Runner) listRangePartitionTables(ctx context.Context, instance *store.InstanceMessage, database *store.DatabaseMessage) (map[string]*utils.RangePartitionTable, error) {
+	driver, err := r.dbFactory.GetAdminDatabaseDriver(ctx, instance, database.DatabaseName)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+
+	if instance.Engine == db.Postgres {
+		return queryPostgresPartitionTables(ctx, driver.GetDB())
+	}
+	return queryMySQLPartitionTables(ctx, driver.GetDB(), database.DatabaseName)
+}
+
+func queryMySQLPartitionTables(ctx context.Context, conn *sql.DB, databaseName string) (map[string]*utils.RangePartitionTable, error) {
+	rows, err := conn.QueryContext(ctx, mysqlPartitionQuery, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make(map[string]*utils.RangePartitionTable)
+	for rows.Next() {
+		var tableName, partitionName, method, expression, description string
+		if err := rows.Scan(&tableName, &partitionName, &method, &expression, &description); err != nil {
+			return nil, err
+		}
+		table, ok := tables[tableName]
+		if !ok {
+			table = &utils.RangePartitionTable{Method: method, Expression: expression}
+			tables[tableName] = table
+		}
+		table.PartitionList = append(table.PartitionList, partitionName)
+		if description == "MAXVALUE" {
+			table.MaxValuePartition = partitionName
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+func queryPostgresPartitionTables(ctx context.Context, conn *sql.DB) (map[string]*utils.RangePartitionTable, error) {
+	rows, err := conn.QueryContext(ctx, pgPartitionQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make(map[string]*utils.RangePartitionTable)
+	for rows.Next() {
+		var schemaName, tableName, partitionName string
+		if err := rows.Scan(&schemaName, &tableName, &partitionName); err != nil {
+			return nil, err
+		}
+		key := fmt.Sprintf("%s.%s", schemaName, tableName)
+		table, ok := tables[key]
+		if !ok {
+			table = &utils.RangePartitionTable{}
+			tables[key] = table
+		}
+		if partitionName != "" {
+			table.PartitionList = append(table.PartitionList, partitionName)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
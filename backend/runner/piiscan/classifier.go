@@ -0,0 +1,173 @@
+package piiscan
+
+import (
+	"regexp"
+	"strings"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+const (
+	// minMatchRatio is the ratio of the sampled values matching the classification to detect the column by the values.
+	minMatchRatio = 0.8
+	// minNameMatchRatio is the lower ratio for the columns whose names suggest the classification, as the values
+	// may be formatted loosely.
+	minNameMatchRatio = 0.5
+)
+
+// classifier detects a classification of the personal data by the column names and the values.
+type classifier struct {
+	classification api.PIIClassification
+	// maskType is the mask type proposed for the classification.
+	maskType     api.SensitiveDataMaskType
+	namePattern  *regexp.Regexp
+	valuePattern *regexp.Regexp
+	// validate validates the value matching the value pattern, nil means the pattern is enough.
+	validate func(value string) bool
+	// nameRequired is true if the values can't be told from the others without the name, such as the phone numbers
+	// from the numeric IDs.
+	nameRequired bool
+}
+
+// classifiers are ordered by the specificity, the former wins if several classifications match equally.
+// The emails are masked with HMAC so that they can still be joined, and the numbers are encrypted keeping the format.
+var classifiers = []*classifier{
+	{
+		classification: api.PIIClassificationSSN,
+		maskType:       api.SensitiveDataMaskTypeFF1,
+		namePattern:    regexp.MustCompile(`(?i)(^|_)ssn($|_)|social_?security`),
+		valuePattern:   regexp.MustCompile(`^\d{3}-\d{2}-\d{4}$`),
+		validate:       isValidSSN,
+	},
+	{
+		classification: api.PIIClassificationCreditCard,
+		maskType:       api.SensitiveDataMaskTypeFF1,
+		namePattern:    regexp.MustCompile(`(?i)credit_?card|card_?(number|num|no)|(^|_)(pan|cc_?num(ber)?)($|_)`),
+		valuePattern:   regexp.MustCompile(`^\d[\d -]{11,22}\d$`),
+		validate:       isValidCreditCard,
+	},
+	{
+		classification: api.PIIClassificationEmail,
+		maskType:       api.SensitiveDataMaskTypeHMAC,
+		namePattern:    regexp.MustCompile(`(?i)e_?mail`),
+		valuePattern:   regexp.MustCompile(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}$`),
+	},
+	{
+		classification: api.PIIClassificationPhone,
+		maskType:       api.SensitiveDataMaskTypeFF1,
+		namePattern:    regexp.MustCompile(`(?i)phone|mobile|(^|_)(tel|cell)($|_)`),
+		valuePattern:   regexp.MustCompile(`^\+?[\d ()\-.]{7,20}$`),
+		validate:       isValidPhone,
+		nameRequired:   true,
+	},
+}
+
+// classification is the classification detected in a column.
+type classification struct {
+	classifier  *classifier
+	nameMatched bool
+	sampleCount int
+	matchCount  int
+}
+
+// classify detects the classification of the column from its name and the sampled values, nil means not detected.
+// The columns are detected by the values mostly matching a classification, or by the names if the values don't
+// contradict. The empty values are not counted.
+func classify(columnName string, values []string) *classification {
+	var samples []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			samples = append(samples, value)
+		}
+	}
+
+	var best *classification
+	for _, c := range classifiers {
+		result := &classification{
+			classifier:  c,
+			nameMatched: c.namePattern.MatchString(columnName),
+			sampleCount: len(samples),
+		}
+		for _, sample := range samples {
+			if c.valuePattern.MatchString(sample) && (c.validate == nil || c.validate(sample)) {
+				result.matchCount++
+			}
+		}
+		if !result.detected() {
+			continue
+		}
+		if best == nil || result.score() > best.score() {
+			best = result
+		}
+	}
+	return best
+}
+
+func (c *classification) detected() bool {
+	if c.classifier.nameRequired && !c.nameMatched {
+		return false
+	}
+	if c.sampleCount == 0 {
+		return c.nameMatched
+	}
+	ratio := float64(c.matchCount) / float64(c.sampleCount)
+	if c.nameMatched {
+		return ratio >= minNameMatchRatio
+	}
+	return ratio >= minMatchRatio
+}
+
+// score ranks the detected classifications of a column by the ratio of the matched values and then the name.
+func (c *classification) score() float64 {
+	score := 0.0
+	if c.sampleCount > 0 {
+		score = float64(c.matchCount) / float64(c.sampleCount)
+	}
+	if c.nameMatched {
+		score += 0.5
+	}
+	return score
+}
+
+// isValidSSN rejects the numbers never issued, whose area is 000, 666 or 9xx, group is 00 or serial is 0000.
+func isValidSSN(value string) bool {
+	digits := extractDigits(value)
+	area, group, serial := digits[0:3], digits[3:5], digits[5:9]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+// isValidCreditCard validates the card numbers of 13 to 19 digits with the Luhn checksum.
+func isValidCreditCard(value string) bool {
+	digits := extractDigits(value)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// isValidPhone validates the phone numbers of 7 to 15 digits, the maximum of E.164.
+func isValidPhone(value string) bool {
+	digits := extractDigits(value)
+	return len(digits) >= 7 && len(digits) <= 15
+}
+
+func extractDigits(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
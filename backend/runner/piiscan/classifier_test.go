@@ -0,0 +1,46 @@
+package piiscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		columnName string
+		values     []string
+		want       api.PIIClassification
+	}{
+		{"contact", []string{"alice@example.com", "bob@mail.example.org", ""}, api.PIIClassificationEmail},
+		{"email", nil, api.PIIClassificationEmail},
+		{"note", []string{"078-05-1120", "219-09-9999", "457-55-5462"}, api.PIIClassificationSSN},
+		{"payment", []string{"4111 1111 1111 1111", "5500-0000-0000-0004", "340000000000009"}, api.PIIClassificationCreditCard},
+		{"mobile_phone", []string{"+1 (415) 555-2671", "020 7946 0958", "unknown"}, api.PIIClassificationPhone},
+		// The names suggesting the classifications are contradicted by the values.
+		{"email_verified", []string{"true", "false"}, ""},
+		// The numeric IDs are neither phone numbers nor card numbers.
+		{"id", []string{"4155552671", "4155552672", "4155552673"}, ""},
+		{"account", []string{"4111111111111112", "5500000000000005"}, ""},
+		// The never issued SSNs are rejected.
+		{"code", []string{"000-12-3456", "666-12-3456", "900-12-3456"}, ""},
+	}
+	for _, test := range tests {
+		result := classify(test.columnName, test.values)
+		if test.want == "" {
+			require.Nil(t, result, test.columnName)
+			continue
+		}
+		require.NotNil(t, result, test.columnName)
+		require.Equal(t, test.want, result.classifier.classification, test.columnName)
+	}
+}
+
+func TestIsValidCreditCard(t *testing.T) {
+	require.True(t, isValidCreditCard("4111-1111-1111-1111"))
+	require.True(t, isValidCreditCard("6011000990139424"))
+	require.False(t, isValidCreditCard("4111111111111112"))
+	require.False(t, isValidCreditCard("411111111111"))
+}
@@ -0,0 +1,250 @@
+// Package piiscan is a runner that samples the columns of the synced databases to discover the personal data, and
+// proposes the classifications and the masking for review.
+package piiscan
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/pg"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	piiScanInterval = 24 * time.Hour
+	// sampleRowCount is the number of the rows sampled from each table.
+	sampleRowCount = 100
+	// databaseScanTimeout bounds the sampling of a database, so a large one doesn't hold the scanner.
+	databaseScanTimeout = 5 * time.Minute
+)
+
+// NewScanner creates a new PII scanner.
+func NewScanner(store *store.Store, dbFactory *dbfactory.DBFactory) *Scanner {
+	return &Scanner{
+		store:     store,
+		dbFactory: dbFactory,
+	}
+}
+
+// Scanner is the PII scanner.
+type Scanner struct {
+	store     *store.Store
+	dbFactory *dbfactory.DBFactory
+}
+
+// Run will run the PII scanner.
+func (s *Scanner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(piiScanInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("PII scanner started and will run every %s", piiScanInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("PII scanner received context cancellation")
+			return
+		case <-ticker.C:
+			log.Debug("PII scanner received tick")
+			s.scan(ctx)
+		}
+	}
+}
+
+// IsSupported returns whether the engine is supported by the PII scanner.
+func IsSupported(engine db.Type) bool {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase, db.Postgres:
+		return true
+	}
+	return false
+}
+
+func (s *Scanner) scan(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = errors.Errorf("%v", r)
+			}
+			log.Error("PII scanner PANIC RECOVER", zap.Error(err), zap.Stack("panic-stack"))
+		}
+	}()
+
+	instances, err := s.store.ListInstancesV2(ctx, &store.FindInstanceMessage{})
+	if err != nil {
+		log.Error("Failed to list instances", zap.Error(err))
+		return
+	}
+
+	var instanceWG sync.WaitGroup
+	for _, instance := range instances {
+		if instance.Deleted || !IsSupported(instance.Engine) {
+			continue
+		}
+		instanceWG.Add(1)
+		go func(instance *store.InstanceMessage) {
+			defer instanceWG.Done()
+			databases, err := s.store.ListDatabases(ctx, &store.FindDatabaseMessage{InstanceID: &instance.ResourceID})
+			if err != nil {
+				log.Debug("Failed to list databases", zap.String("instance", instance.ResourceID), zap.Error(err))
+				return
+			}
+			// The databases of an instance are scanned one by one to limit the load on the instance.
+			for _, database := range databases {
+				if database.SyncState != api.OK {
+					continue
+				}
+				if err := s.ScanDatabase(ctx, instance, database); err != nil {
+					log.Debug("Failed to scan database for PII",
+						zap.String("instance", instance.ResourceID),
+						zap.String("database", database.DatabaseName),
+						zap.Int("databaseID", database.UID),
+						zap.Error(err))
+				}
+			}
+		}(instance)
+	}
+	instanceWG.Wait()
+}
+
+// ScanDatabase samples the columns of the database and replaces its PII findings.
+func (s *Scanner) ScanDatabase(ctx context.Context, instance *store.InstanceMessage, database *store.DatabaseMessage) error {
+	if !IsSupported(instance.Engine) {
+		return errors.Errorf("PII scan is not supported for engine %q", instance.Engine)
+	}
+	if instance.Engine == db.Postgres {
+		if _, exists := pg.ExcludedDatabaseList[database.DatabaseName]; exists {
+			return nil
+		}
+	}
+	dbSchema, err := s.store.GetDBSchema(ctx, database.UID)
+	if err != nil {
+		return err
+	}
+	if dbSchema == nil {
+		return errors.Errorf("database schema %d not found", database.UID)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, databaseScanTimeout)
+	defer cancel()
+	driver, err := s.dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, database.DatabaseName)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+
+	scannedTs := time.Now().Unix()
+	var findings []*store.PIIFindingMessage
+	for _, schema := range dbSchema.Metadata.Schemas {
+		for _, table := range schema.Tables {
+			var columnNames []string
+			for _, column := range table.Columns {
+				if isSampledType(column.Type) {
+					columnNames = append(columnNames, column.Name)
+				}
+			}
+			if len(columnNames) == 0 {
+				continue
+			}
+			samples, err := sampleTable(ctx, driver.GetDB(), instance.Engine, schema.Name, table.Name, columnNames)
+			if err != nil {
+				// The table may be dropped since the last sync, the other tables are still scanned.
+				log.Debug("Failed to sample table for PII",
+					zap.String("instance", instance.ResourceID),
+					zap.String("database", database.DatabaseName),
+					zap.String("schema", schema.Name),
+					zap.String("table", table.Name),
+					zap.Error(err))
+				continue
+			}
+			for i, columnName := range columnNames {
+				result := classify(columnName, samples[i])
+				if result == nil {
+					continue
+				}
+				findings = append(findings, &store.PIIFindingMessage{
+					DatabaseUID:    database.UID,
+					SchemaName:     schema.Name,
+					TableName:      table.Name,
+					ColumnName:     columnName,
+					Classification: result.classifier.classification,
+					MaskType:       result.classifier.maskType,
+					NameMatched:    result.nameMatched,
+					SampleCount:    result.sampleCount,
+					MatchCount:     result.matchCount,
+				})
+			}
+		}
+	}
+	return s.store.SetPIIFindings(ctx, database.UID, findings, scannedTs)
+}
+
+// isSampledType returns whether the values of the column type may hold the personal data as text or numbers.
+func isSampledType(columnType string) bool {
+	columnType = strings.ToLower(columnType)
+	for _, keyword := range []string{"blob", "binary", "bytea", "json", "xml", "bool", "bit", "date", "time", "interval", "geometry", "point", "enum", "set(", "[]"} {
+		if strings.Contains(columnType, keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// sampleTable returns the sampled values of the columns, indexed in the same order.
+func sampleTable(ctx context.Context, sqlDB *sql.DB, engine db.Type, schemaName, tableName string, columnNames []string) ([][]string, error) {
+	var query string
+	var quotedColumns []string
+	if engine == db.Postgres {
+		for _, columnName := range columnNames {
+			quotedColumns = append(quotedColumns, fmt.Sprintf("%s::text", quoteIdentifier(columnName, `"`)))
+		}
+		query = fmt.Sprintf("SELECT %s FROM %s.%s LIMIT %d", strings.Join(quotedColumns, ", "), quoteIdentifier(schemaName, `"`), quoteIdentifier(tableName, `"`), sampleRowCount)
+	} else {
+		for _, columnName := range columnNames {
+			quotedColumns = append(quotedColumns, quoteIdentifier(columnName, "`"))
+		}
+		query = fmt.Sprintf("SELECT %s FROM %s LIMIT %d", strings.Join(quotedColumns, ", "), quoteIdentifier(tableName, "`"), sampleRowCount)
+	}
+
+	rows, err := sqlDB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	samples := make([][]string, len(columnNames))
+	values := make([]sql.NullString, len(columnNames))
+	scanArgs := make([]any, len(columnNames))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, err
+		}
+		for i, value := range values {
+			if value.Valid {
+				samples[i] = append(samples[i], value.String)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+func quoteIdentifier(identifier, quote string) string {
+	return quote + strings.ReplaceAll(identifier, quote, quote+quote) + quote
+}
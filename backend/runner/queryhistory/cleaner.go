@@ -0,0 +1,82 @@
+// Package queryhistory is a runner that enforces the workspace retention of the SQL editor query histories.
+package queryhistory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const queryHistoryCleanInterval = 1 * time.Hour
+
+// NewCleaner creates a new query history cleaner.
+func NewCleaner(store *store.Store) *Cleaner {
+	return &Cleaner{
+		store: store,
+	}
+}
+
+// Cleaner is the query history cleaner deleting the query histories beyond the retention.
+type Cleaner struct {
+	store *store.Store
+}
+
+// Run will run the query history cleaner.
+func (c *Cleaner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(queryHistoryCleanInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Query history cleaner started and will run every %s", queryHistoryCleanInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Query history cleaner received context cancellation")
+			return
+		case <-ticker.C:
+			log.Debug("Query history cleaner received tick")
+			c.clean(ctx, time.Now())
+		}
+	}
+}
+
+func (c *Cleaner) clean(ctx context.Context, now time.Time) {
+	name := api.SettingWorkspaceQueryHistoryRetention
+	setting, err := c.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &name})
+	if err != nil {
+		log.Error("Failed to get query history retention setting", zap.Error(err))
+		return
+	}
+	// The query histories are kept forever without the setting.
+	if setting == nil {
+		return
+	}
+	value, err := api.ValidateAndGetQueryHistoryRetentionSetting(setting.Value)
+	if err != nil {
+		log.Error("Invalid query history retention setting", zap.Error(err))
+		return
+	}
+
+	if value.RetentionDays > 0 {
+		count, err := c.store.DeleteQueryHistoriesBefore(ctx, now.AddDate(0, 0, -value.RetentionDays).Unix())
+		if err != nil {
+			log.Error("Failed to delete expired query histories", zap.Error(err))
+		} else if count > 0 {
+			log.Debug("Deleted expired query histories", zap.Int64("count", count))
+		}
+	}
+	if value.MaxCountPerUser > 0 {
+		count, err := c.store.DeleteQueryHistoriesBeyondCountPerUser(ctx, value.MaxCountPerUser)
+		if err != nil {
+			log.Error("Failed to delete query histories beyond the count per user", zap.Error(err))
+		} else if count > 0 {
+			log.Debug("Deleted query histories beyond the count per user", zap.Int64("count", count))
+		}
+	}
+}
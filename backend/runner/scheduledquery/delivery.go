@@ -0,0 +1,185 @@
+package scheduledquery
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/mail"
+	runnermail "github.com/bytebase/bytebase/backend/runner/mail"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	deliveryTimeout = 10 * time.Second
+	// previewRowCount is the number of the rows in the mail body and the Slack message, the mail attaches all rows.
+	previewRowCount = 20
+	// previewCellLength is the maximum length of a cell in the Slack message.
+	previewCellLength = 40
+)
+
+// WebhookPayload is the payload POSTed to the WEBHOOK delivery.
+type WebhookPayload struct {
+	ScheduledQueryID int                       `json:"scheduledQueryId"`
+	Name             string                    `json:"name"`
+	SheetID          int                       `json:"sheetId"`
+	Result           *api.ScheduledQueryResult `json:"result"`
+}
+
+func (r *Runner) deliver(ctx context.Context, scheduledQuery *store.ScheduledQueryMessage, delivery *api.ScheduledQueryDelivery, result *api.ScheduledQueryResult) error {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	switch delivery.Type {
+	case api.ScheduledQueryDeliveryEmail:
+		var to []string
+		for _, address := range strings.Split(delivery.Target, ",") {
+			to = append(to, strings.TrimSpace(address))
+		}
+		csvContent, err := formatCSV(result)
+		if err != nil {
+			return err
+		}
+		return runnermail.SendWorkspaceMail(ctx, r.store, to, fmt.Sprintf("Scheduled query %q", scheduledQuery.Name), formatHTML(scheduledQuery.Name, result), []*runnermail.Attachment{
+			{
+				Filename:    "result.csv",
+				ContentType: mail.ContentTypeTextCSV,
+				Content:     csvContent,
+			},
+		})
+	case api.ScheduledQueryDeliverySlack:
+		return post(ctx, delivery.Target, map[string]string{"text": formatSlackText(scheduledQuery.Name, result)})
+	case api.ScheduledQueryDeliveryWebhook:
+		return post(ctx, delivery.Target, &WebhookPayload{
+			ScheduledQueryID: scheduledQuery.ID,
+			Name:             scheduledQuery.Name,
+			SheetID:          scheduledQuery.SheetUID,
+			Result:           result,
+		})
+	}
+	return errors.Errorf("unsupported delivery type %q", delivery.Type)
+}
+
+func post(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal POST request to %s", url)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct POST request to %s", url)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to POST to %s", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("failed to POST to %s, status code: %d, response body: %s", url, resp.StatusCode, b)
+	}
+	return nil
+}
+
+func formatCell(v any) string {
+	if v == nil {
+		return "NULL"
+	}
+	return fmt.Sprint(v)
+}
+
+func formatCSV(result *api.ScheduledQueryResult) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(result.ColumnNames); err != nil {
+		return nil, err
+	}
+	for _, row := range result.Rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			if v != nil {
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func formatSummary(result *api.ScheduledQueryResult) string {
+	summary := fmt.Sprintf("%d rows", len(result.Rows))
+	if len(result.Rows) == 1 {
+		summary = "1 row"
+	}
+	if result.Truncated {
+		summary += fmt.Sprintf(", truncated to the first %d", api.MaxScheduledQueryRowCount)
+	}
+	return summary
+}
+
+func formatHTML(name string, result *api.ScheduledQueryResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<h3>%s</h3><p>%s.", html.EscapeString(name), html.EscapeString(formatSummary(result)))
+	if len(result.Rows) > previewRowCount {
+		fmt.Fprintf(&b, " The first %d rows are shown, see the attachment for all rows.", previewRowCount)
+	}
+	b.WriteString("</p><table border=\"1\" cellspacing=\"0\" cellpadding=\"4\"><tr>")
+	for _, column := range result.ColumnNames {
+		fmt.Fprintf(&b, "<th>%s</th>", html.EscapeString(column))
+	}
+	b.WriteString("</tr>")
+	for i, row := range result.Rows {
+		if i >= previewRowCount {
+			break
+		}
+		b.WriteString("<tr>")
+		for _, v := range row {
+			fmt.Fprintf(&b, "<td>%s</td>", html.EscapeString(formatCell(v)))
+		}
+		b.WriteString("</tr>")
+	}
+	b.WriteString("</table>")
+	return b.String()
+}
+
+func formatSlackText(name string, result *api.ScheduledQueryResult) string {
+	var lines []string
+	lines = append(lines, strings.Join(result.ColumnNames, " | "))
+	for i, row := range result.Rows {
+		if i >= previewRowCount {
+			break
+		}
+		var cells []string
+		for _, v := range row {
+			cell := strings.ReplaceAll(formatCell(v), "\n", " ")
+			if runes := []rune(cell); len(runes) > previewCellLength {
+				cell = string(runes[:previewCellLength-3]) + "..."
+			}
+			cells = append(cells, cell)
+		}
+		lines = append(lines, strings.Join(cells, " | "))
+	}
+	text := fmt.Sprintf("*%s* (%s)", name, formatSummary(result))
+	if len(result.Rows) > previewRowCount {
+		text += fmt.Sprintf(", the first %d rows are shown", previewRowCount)
+	}
+	// The backticks in the cells would end the code block early.
+	return text + "\n```\n" + strings.ReplaceAll(strings.Join(lines, "\n"), "```", "'''") + "\n```"
+}
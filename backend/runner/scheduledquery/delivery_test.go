@@ -0,0 +1,49 @@
+package scheduledquery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestFormatResult(t *testing.T) {
+	result := &api.ScheduledQueryResult{
+		ColumnNames:     []string{"id", "email"},
+		ColumnTypeNames: []string{"INT", "TEXT"},
+		Rows: [][]any{
+			{int64(1), "******"},
+			{int64(2), nil},
+			{int64(3), "a,\"b\"<c>"},
+		},
+	}
+
+	csvContent, err := formatCSV(result)
+	require.NoError(t, err)
+	require.Equal(t, "id,email\n1,******\n2,\n3,\"a,\"\"b\"\"<c>\"\n", string(csvContent))
+
+	require.Equal(t,
+		"<h3>Daily &lt;users&gt;</h3><p>3 rows.</p><table border=\"1\" cellspacing=\"0\" cellpadding=\"4\"><tr><th>id</th><th>email</th></tr>"+
+			"<tr><td>1</td><td>******</td></tr><tr><td>2</td><td>NULL</td></tr><tr><td>3</td><td>a,&#34;b&#34;&lt;c&gt;</td></tr></table>",
+		formatHTML("Daily <users>", result),
+	)
+
+	require.Equal(t, "*Daily* (3 rows)\n```\nid | email\n1 | ******\n2 | NULL\n3 | a,\"b\"<c>\n```", formatSlackText("Daily", result))
+
+	result.Truncated = true
+	require.Equal(t, "3 rows, truncated to the first 1000", formatSummary(result))
+}
+
+func TestGetNextRunTs(t *testing.T) {
+	now := time.Date(2023, 10, 11, 10, 30, 0, 0, time.UTC)
+	nextRunTs, err := GetNextRunTs("0 9 * * *", "Asia/Shanghai", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2023, 10, 12, 1, 0, 0, 0, time.UTC).Unix(), nextRunTs)
+
+	_, err = GetNextRunTs("0 9 * * *", "Mars/Olympus", now)
+	require.Error(t, err)
+	_, err = GetNextRunTs("0 0 31 2 *", "UTC", now)
+	require.Error(t, err)
+}
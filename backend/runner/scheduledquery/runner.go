@@ -0,0 +1,225 @@
+// Package scheduledquery is a runner that runs the saved sheets on their cron schedules and delivers the masked results.
+package scheduledquery
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+const (
+	scheduledQueryRunInterval = 1 * time.Minute
+	// scheduledQueryTimeout is the timeout of running the query of a scheduled query, the deliveries have their own.
+	scheduledQueryTimeout = 5 * time.Minute
+)
+
+// CheckQueryFunc checks the principal can run the statement in the SQL editor, and returns the sensitive schema info
+// used to mask the result and the query limits of the principal.
+type CheckQueryFunc func(ctx context.Context, principalID int, role api.Role, instance *store.InstanceMessage, databaseName, statement string) (*db.SensitiveSchemaInfo, *utils.QueryLimit, error)
+
+// NewRunner creates a new scheduled query runner.
+func NewRunner(store *store.Store, dbFactory *dbfactory.DBFactory, checkQuery CheckQueryFunc) *Runner {
+	return &Runner{
+		store:      store,
+		dbFactory:  dbFactory,
+		checkQuery: checkQuery,
+	}
+}
+
+// Runner is the scheduled query runner.
+// The query runs as the creator of the scheduled query, so the access control and the masking are the same as the
+// creator running the sheet in the SQL editor at the time.
+type Runner struct {
+	store      *store.Store
+	dbFactory  *dbfactory.DBFactory
+	checkQuery CheckQueryFunc
+}
+
+// Run will run the scheduled query runner.
+func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(scheduledQueryRunInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Scheduled query runner started and will run every %s", scheduledQueryRunInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Scheduled query runner received context cancellation")
+			return
+		case <-ticker.C:
+			log.Debug("Scheduled query runner received tick")
+			r.runDueScheduledQueries(ctx)
+		}
+	}
+}
+
+func (r *Runner) runDueScheduledQueries(ctx context.Context) {
+	now := time.Now()
+	enabled := true
+	nowTs := now.Unix()
+	scheduledQueries, err := r.store.ListScheduledQueries(ctx, &store.FindScheduledQueryMessage{Enabled: &enabled, NextRunBefore: &nowTs})
+	if err != nil {
+		log.Error("Failed to list due scheduled queries", zap.Error(err))
+		return
+	}
+	for _, scheduledQuery := range scheduledQueries {
+		// Advance the next run before running, so a failing or slow query never runs twice for the same schedule.
+		nextRunTs, err := GetNextRunTs(scheduledQuery.Schedule, scheduledQuery.Timezone, now)
+		if err != nil {
+			log.Error("Failed to get the next run of the scheduled query", zap.Int("id", scheduledQuery.ID), zap.Error(err))
+			// Disable the scheduled query whose schedule never matches, otherwise it's due at every tick.
+			disabled := false
+			if _, err := r.store.UpdateScheduledQuery(ctx, &store.UpdateScheduledQueryMessage{ID: scheduledQuery.ID, UpdaterID: api.SystemBotID, Enabled: &disabled}); err != nil {
+				log.Error("Failed to disable the scheduled query", zap.Int("id", scheduledQuery.ID), zap.Error(err))
+			}
+			continue
+		}
+		if _, err := r.store.UpdateScheduledQuery(ctx, &store.UpdateScheduledQueryMessage{ID: scheduledQuery.ID, UpdaterID: scheduledQuery.UpdaterID, NextRunTs: &nextRunTs}); err != nil {
+			log.Error("Failed to update the next run of the scheduled query", zap.Int("id", scheduledQuery.ID), zap.Error(err))
+			continue
+		}
+		if _, err := r.Execute(ctx, scheduledQuery); err != nil {
+			log.Error("Failed to run the scheduled query", zap.Int("id", scheduledQuery.ID), zap.Error(err))
+		}
+	}
+}
+
+// Execute runs the scheduled query once, delivers the result and records the run. The failure of the query is
+// recorded in the run, and the returned error is the failure of recording the run.
+func (r *Runner) Execute(ctx context.Context, scheduledQuery *store.ScheduledQueryMessage) (*store.ScheduledQueryRunMessage, error) {
+	run := &store.ScheduledQueryRunMessage{
+		ScheduledQueryID: scheduledQuery.ID,
+		Status:           api.ScheduledQueryRunSuccess,
+	}
+	result, err := r.query(ctx, scheduledQuery)
+	if err != nil {
+		run.Status = api.ScheduledQueryRunFailed
+		run.Error = err.Error()
+	} else {
+		run.Result = result
+		for _, delivery := range scheduledQuery.DeliveryList {
+			deliveryResult := &api.ScheduledQueryDeliveryResult{
+				Type:   delivery.Type,
+				Target: delivery.Target,
+			}
+			if err := r.deliver(ctx, scheduledQuery, delivery, result); err != nil {
+				deliveryResult.Error = err.Error()
+			}
+			run.DeliveryResultList = append(run.DeliveryResultList, deliveryResult)
+		}
+	}
+	return r.store.CreateScheduledQueryRun(ctx, run)
+}
+
+func (r *Runner) query(ctx context.Context, scheduledQuery *store.ScheduledQueryMessage) (*api.ScheduledQueryResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, scheduledQueryTimeout)
+	defer cancel()
+
+	sheet, err := r.store.GetSheetV2(ctx, &api.SheetFind{ID: &scheduledQuery.SheetUID, LoadFull: true}, scheduledQuery.CreatorID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get sheet %d", scheduledQuery.SheetUID)
+	}
+	if sheet == nil {
+		return nil, errors.Errorf("sheet %d not found", scheduledQuery.SheetUID)
+	}
+	if sheet.DatabaseID == nil {
+		return nil, errors.Errorf("sheet %q isn't connected to a database", sheet.Name)
+	}
+	database, err := r.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: sheet.DatabaseID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get database %d", *sheet.DatabaseID)
+	}
+	if database == nil {
+		return nil, errors.Errorf("database %d not found", *sheet.DatabaseID)
+	}
+	instance, err := r.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get instance %q", database.InstanceID)
+	}
+	if instance == nil {
+		return nil, errors.Errorf("instance %q not found", database.InstanceID)
+	}
+	if !api.IsSQLCursorSupported(instance.Engine) {
+		return nil, errors.Errorf("scheduled queries don't support %s", instance.Engine)
+	}
+	creator, err := r.store.GetUserByID(ctx, scheduledQuery.CreatorID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get creator %d", scheduledQuery.CreatorID)
+	}
+	if creator == nil || creator.MemberDeleted {
+		return nil, errors.Errorf("creator %d of the scheduled query is no longer active", scheduledQuery.CreatorID)
+	}
+	sensitiveSchemaInfo, queryLimit, err := r.checkQuery(ctx, creator.ID, creator.Role, instance, database.DatabaseName, sheet.Statement)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancelQuery := queryLimit.WithTimeout(ctx)
+	defer cancelQuery()
+	rowLimit := queryLimit.ApplyRowLimit(api.MaxScheduledQueryRowCount)
+
+	driver, err := r.dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, database.DatabaseName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get database driver")
+	}
+	defer driver.Close(ctx)
+	conn, err := driver.GetDB().Conn(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get database connection")
+	}
+	defer conn.Close()
+
+	engine := instance.Engine
+	// The Redshift driver queries in the PostgreSQL dialect.
+	if engine == db.Redshift {
+		engine = db.Postgres
+	}
+	result := &api.ScheduledQueryResult{Rows: [][]any{}}
+	columnNames, columnTypeNames, _, err := util.QueryRows(ctx, engine, conn, sheet.Statement, &db.QueryContext{
+		ReadOnly:              true,
+		CurrentDatabase:       database.DatabaseName,
+		SensitiveDataMaskType: db.SensitiveDataMaskTypeDefault,
+		SensitiveSchemaInfo:   sensitiveSchemaInfo,
+	}, func(row []any) error {
+		if len(result.Rows) >= rowLimit {
+			result.Truncated = true
+			return util.ErrSkipRemainingRows
+		}
+		result.Rows = append(result.Rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.ColumnNames = columnNames
+	result.ColumnTypeNames = columnTypeNames
+	return result, nil
+}
+
+// GetNextRunTs returns the next run of the schedule in the timezone after now.
+func GetNextRunTs(schedule, timezone string, now time.Time) (int64, error) {
+	cronSchedule, err := utils.ParseCronSchedule(schedule)
+	if err != nil {
+		return 0, err
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid timezone %q", timezone)
+	}
+	next := cronSchedule.Next(now.In(location))
+	if next.IsZero() {
+		return 0, errors.Errorf("schedule %q never matches", schedule)
+	}
+	return next.Unix(), nil
+}
@@ -0,0 +1,32 @@
+package schemasync
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+var syncLatency = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "bytebase",
+		Subsystem: "schema_sync",
+		Name:      "duration_seconds",
+		Help:      "The latency of syncing the instances and the database schemas, by the engine, the scope, i.e. instance or database, and the result.",
+		Buckets:   []float64{.1, .5, 1, 5, 10, 30, 60, 300, 900},
+	},
+	[]string{"engine", "scope", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(syncLatency)
+}
+
+func observeSyncLatency(engine db.Type, scope string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	syncLatency.WithLabelValues(string(engine), scope, result).Observe(duration.Seconds())
+}
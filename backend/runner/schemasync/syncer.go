@@ -9,16 +9,19 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/testing/protocmp"
 
+	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
 	"github.com/bytebase/bytebase/backend/component/config"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	"github.com/bytebase/bytebase/backend/component/state"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/pg"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/utils"
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
@@ -164,7 +167,11 @@ func (s *Syncer) syncAllDatabases(ctx context.Context, instance *api.Instance) {
 }
 
 // SyncInstance syncs the schema for all databases in an instance.
-func (s *Syncer) SyncInstance(ctx context.Context, instance *store.InstanceMessage) ([]string, error) {
+func (s *Syncer) SyncInstance(ctx context.Context, instance *store.InstanceMessage) (_ []string, err error) {
+	startTime := time.Now()
+	defer func() {
+		observeSyncLatency(instance.Engine, "instance", time.Since(startTime), err)
+	}()
 	driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, "")
 	if err != nil {
 		return nil, err
@@ -251,7 +258,8 @@ func (s *Syncer) SyncInstance(ctx context.Context, instance *store.InstanceMessa
 }
 
 // SyncDatabaseSchema will sync the schema for a database.
-func (s *Syncer) SyncDatabaseSchema(ctx context.Context, database *store.DatabaseMessage, force bool) error {
+func (s *Syncer) SyncDatabaseSchema(ctx context.Context, database *store.DatabaseMessage, force bool) (err error) {
+	startTime := time.Now()
 	instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{EnvironmentID: &database.EnvironmentID, ResourceID: &database.InstanceID})
 	if err != nil {
 		return err
@@ -259,7 +267,15 @@ func (s *Syncer) SyncDatabaseSchema(ctx context.Context, database *store.Databas
 	if instance == nil {
 		return errors.Errorf("instance %q not found", database.InstanceID)
 	}
-	driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, database.DatabaseName)
+	defer func() {
+		observeSyncLatency(instance.Engine, "database", time.Since(startTime), err)
+	}()
+	// The forced sync happens right after the schema changes, so it reads from the admin data source to avoid the replication lag.
+	getDriver := s.dbFactory.GetSchemaSyncDatabaseDriver
+	if force {
+		getDriver = s.dbFactory.GetAdminDatabaseDriver
+	}
+	driver, err := getDriver(ctx, instance, database.DatabaseName)
 	if err != nil {
 		return err
 	}
@@ -303,18 +319,28 @@ func syncDBSchema(ctx context.Context, stores *store.Store, database *store.Data
 		return err
 	}
 	var oldDatabaseMetadata *storepb.DatabaseMetadata
+	var oldRowLevelSecurity []*db.TableRowLevelSecurity
 	if dbSchema != nil {
 		oldDatabaseMetadata = dbSchema.Metadata
+		oldRowLevelSecurity = dbSchema.RowLevelSecurity
+	}
+	var rowLevelSecurity []*db.TableRowLevelSecurity
+	if pgDriver, ok := db.UnwrapDriver(driver).(*pg.Driver); ok && common.FeatureFlag(common.FeatureFlagRowLevelSecurity) {
+		if rowLevelSecurity, err = pgDriver.SyncRowLevelSecurity(ctx); err != nil {
+			return err
+		}
 	}
+	// The row level security changes are dumped as well, e.g. CREATE POLICY.
+	rowLevelSecurityChanged := !cmp.Equal(oldRowLevelSecurity, rowLevelSecurity, cmpopts.EquateEmpty())
 
-	if !cmp.Equal(oldDatabaseMetadata, databaseMetadata, protocmp.Transform()) {
+	if rowLevelSecurityChanged || !cmp.Equal(oldDatabaseMetadata, databaseMetadata, protocmp.Transform()) {
 		var rawDump []byte
 		if dbSchema != nil {
 			rawDump = dbSchema.Schema
 		}
 		// Avoid updating dump everytime by dumping the schema only when the database metadata is changed.
 		// if oldDatabaseMetadata is nil and databaseMetadata is not, they are not equal resulting a sync.
-		if force || !equalDatabaseMetadata(oldDatabaseMetadata, databaseMetadata) {
+		if force || rowLevelSecurityChanged || !equalDatabaseMetadata(oldDatabaseMetadata, databaseMetadata) {
 			var schemaBuf bytes.Buffer
 			if _, err := driver.Dump(ctx, &schemaBuf, true /* schemaOnly */); err != nil {
 				return err
@@ -323,8 +349,9 @@ func syncDBSchema(ctx context.Context, stores *store.Store, database *store.Data
 		}
 
 		if err := stores.UpsertDBSchema(ctx, database.UID, &store.DBSchema{
-			Metadata: databaseMetadata,
-			Schema:   rawDump,
+			Metadata:         databaseMetadata,
+			Schema:           rawDump,
+			RowLevelSecurity: rowLevelSecurity,
 		}, api.SystemBotID); err != nil {
 			return err
 		}
@@ -0,0 +1,317 @@
+// Package ssogroupsync is a runner that maps the identity provider groups of the SSO users to the workspace roles and
+// the project memberships.
+package ssogroupsync
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	enterpriseAPI "github.com/bytebase/bytebase/backend/enterprise/api"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// ssoGroupSyncInterval is the interval of re-evaluating the SSO group mapping against the groups the users had on
+// their last SSO login, so the changes of the mapping take effect without the users signing in again.
+const ssoGroupSyncInterval = 10 * time.Minute
+
+// NewSyncer creates a new SSO group syncer.
+func NewSyncer(store *store.Store, licenseService enterpriseAPI.LicenseService) *Syncer {
+	return &Syncer{
+		store:          store,
+		licenseService: licenseService,
+	}
+}
+
+// Syncer is the SSO group syncer applying the SSO group mapping on the SSO logins and periodically afterwards.
+type Syncer struct {
+	store          *store.Store
+	licenseService enterpriseAPI.LicenseService
+}
+
+// Run will run the SSO group syncer.
+func (s *Syncer) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(ssoGroupSyncInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("SSO group syncer started and will run every %s", ssoGroupSyncInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("SSO group syncer received context cancellation")
+			return
+		case <-ticker.C:
+			log.Debug("SSO group syncer received tick")
+			s.sync(ctx)
+		}
+	}
+}
+
+// GetMapping returns the SSO group mapping of the identity provider, or nil if there's none.
+func (s *Syncer) GetMapping(ctx context.Context, idp string) (*api.SSOGroupMapping, error) {
+	value, err := s.getSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return value.GetMapping(idp), nil
+}
+
+// Apply applies the SSO group mapping of the identity provider to the user signing in with the groups, and records
+// the groups for the re-evaluation. It returns the user with the mapped workspace role.
+func (s *Syncer) Apply(ctx context.Context, user *store.UserMessage, idp string, groups []string) (*store.UserMessage, error) {
+	if !s.licenseService.IsFeatureEnabled(api.FeatureSSO) {
+		return user, nil
+	}
+	mapping, err := s.GetMapping(ctx, idp)
+	if err != nil {
+		return nil, err
+	}
+	membership, err := s.store.GetSSOGroupMembership(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	// The users of the identity providers without the mapping are left alone, unless they were granted the roles by
+	// the removed mapping.
+	if mapping == nil && membership == nil {
+		return user, nil
+	}
+	return s.apply(ctx, user, idp, groups, mapping, membership)
+}
+
+func (s *Syncer) sync(ctx context.Context) {
+	if !s.licenseService.IsFeatureEnabled(api.FeatureSSO) {
+		return
+	}
+	value, err := s.getSetting(ctx)
+	if err != nil {
+		log.Error("Failed to get SSO group mapping setting", zap.Error(err))
+		return
+	}
+	memberships, err := s.store.ListSSOGroupMemberships(ctx)
+	if err != nil {
+		log.Error("Failed to list SSO group memberships", zap.Error(err))
+		return
+	}
+	for _, membership := range memberships {
+		user, err := s.store.GetUserByID(ctx, membership.PrincipalID)
+		if err != nil {
+			log.Error("Failed to get user", zap.Int("user", membership.PrincipalID), zap.Error(err))
+			continue
+		}
+		if user == nil || user.MemberDeleted {
+			continue
+		}
+		if _, err := s.apply(ctx, user, membership.IdentityProvider, membership.GroupList, value.GetMapping(membership.IdentityProvider), membership); err != nil {
+			log.Error("Failed to apply SSO group mapping", zap.String("user", user.Email), zap.Error(err))
+		}
+	}
+}
+
+func (s *Syncer) apply(ctx context.Context, user *store.UserMessage, idp string, groups []string, mapping *api.SSOGroupMapping, membership *store.SSOGroupMembershipMessage) (*store.UserMessage, error) {
+	grant := mapping.Evaluate(groups)
+
+	if grant.WorkspaceRole != "" && grant.WorkspaceRole != user.Role {
+		lastOwner, err := s.isLastOwner(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if lastOwner {
+			log.Warn("Skip demoting the last workspace owner by SSO group mapping", zap.String("user", user.Email), zap.String("role", string(grant.WorkspaceRole)))
+		} else {
+			updated, err := s.store.UpdateUser(ctx, user.ID, &store.UpdateUserMessage{Role: &grant.WorkspaceRole}, api.SystemBotID)
+			if err != nil {
+				return nil, err
+			}
+			user = updated
+		}
+	}
+
+	previous := make(map[api.SSOProjectRole]bool)
+	if membership != nil {
+		for _, projectRole := range membership.GrantedProjectRoleList {
+			previous[*projectRole] = true
+		}
+	}
+	desired := make(map[api.SSOProjectRole]bool)
+	for _, projectRole := range grant.ProjectRoleList {
+		desired[*projectRole] = true
+	}
+	projectRoles := make(map[string][]api.Role)
+	for _, m := range []map[api.SSOProjectRole]bool{previous, desired} {
+		for projectRole := range m {
+			if !containsRole(projectRoles[projectRole.Project], projectRole.Role) {
+				projectRoles[projectRole.Project] = append(projectRoles[projectRole.Project], projectRole.Role)
+			}
+		}
+	}
+	var projects []string
+	for project := range projectRoles {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	var granted []*api.SSOProjectRole
+	for _, project := range projects {
+		projectGranted, err := s.applyProjectRoles(ctx, user, project, projectRoles[project], previous, desired)
+		if err != nil {
+			return nil, err
+		}
+		granted = append(granted, projectGranted...)
+	}
+
+	if err := s.store.UpsertSSOGroupMembership(ctx, &store.SSOGroupMembershipMessage{
+		PrincipalID:            user.ID,
+		IdentityProvider:       idp,
+		GroupList:              groups,
+		GrantedProjectRoleList: granted,
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// applyProjectRoles adds the desired roles of the project to the user and revokes the previously granted ones which
+// aren't desired any more. It returns the roles granted by the mapping, the ones the user already had aren't counted.
+func (s *Syncer) applyProjectRoles(ctx context.Context, user *store.UserMessage, projectID string, roles []api.Role, previous, desired map[api.SSOProjectRole]bool) ([]*api.SSOProjectRole, error) {
+	project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &projectID})
+	if err != nil {
+		return nil, err
+	}
+	if project == nil || project.Deleted {
+		log.Warn("Skip the project roles of the missing project in SSO group mapping", zap.String("project", projectID))
+		return nil, nil
+	}
+	policy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{UID: &project.UID})
+	if err != nil {
+		return nil, err
+	}
+	// The cached policy is shared, so it's copied before the changes.
+	newPolicy := &store.IAMPolicyMessage{}
+	bindings := make(map[api.Role]*store.PolicyBinding)
+	for _, binding := range policy.Bindings {
+		newBinding := &store.PolicyBinding{Role: binding.Role, Members: append([]*store.UserMessage{}, binding.Members...)}
+		newPolicy.Bindings = append(newPolicy.Bindings, newBinding)
+		bindings[binding.Role] = newBinding
+	}
+
+	var granted []*api.SSOProjectRole
+	changed := false
+	for _, role := range roles {
+		projectRole := api.SSOProjectRole{Project: projectID, Role: role}
+		binding := bindings[role]
+		has := binding != nil && containsMember(binding.Members, user.ID)
+		switch {
+		case desired[projectRole] && has:
+			if previous[projectRole] {
+				granted = append(granted, &projectRole)
+			}
+		case desired[projectRole]:
+			exists, err := s.roleExists(ctx, role)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				log.Warn("Skip the missing project role in SSO group mapping", zap.String("project", projectID), zap.String("role", string(role)))
+				continue
+			}
+			if binding == nil {
+				binding = &store.PolicyBinding{Role: role}
+				newPolicy.Bindings = append(newPolicy.Bindings, binding)
+				bindings[role] = binding
+			}
+			binding.Members = append(binding.Members, user)
+			granted = append(granted, &projectRole)
+			changed = true
+		case previous[projectRole] && has:
+			// The projects must have at least one owner.
+			if role == api.Owner && len(binding.Members) == 1 {
+				log.Warn("Skip revoking the last project owner by SSO group mapping", zap.String("project", projectID), zap.String("user", user.Email))
+				granted = append(granted, &projectRole)
+				continue
+			}
+			binding.Members = removeMember(binding.Members, user.ID)
+			changed = true
+		}
+	}
+	if changed {
+		if _, err := s.store.SetProjectIAMPolicy(ctx, newPolicy, api.SystemBotID, project.UID); err != nil {
+			return nil, err
+		}
+	}
+	return granted, nil
+}
+
+func (s *Syncer) roleExists(ctx context.Context, role api.Role) (bool, error) {
+	if role == api.Owner || role == api.Developer {
+		return true, nil
+	}
+	customRole, err := s.store.GetRole(ctx, string(role))
+	if err != nil {
+		return false, err
+	}
+	return customRole != nil, nil
+}
+
+// isLastOwner returns whether the user is the last active workspace owner, demoting whom locks everyone out.
+func (s *Syncer) isLastOwner(ctx context.Context, user *store.UserMessage) (bool, error) {
+	if user.Role != api.Owner {
+		return false, nil
+	}
+	owner := api.Owner
+	owners, err := s.store.ListUsers(ctx, &store.FindUserMessage{Role: &owner})
+	if err != nil {
+		return false, err
+	}
+	for _, o := range owners {
+		if o.ID != user.ID && o.Type == api.EndUser {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *Syncer) getSetting(ctx context.Context) (*api.SettingWorkspaceSSOGroupMappingValue, error) {
+	settingName := api.SettingWorkspaceSSOGroupMapping
+	setting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil || setting.Value == "" {
+		return nil, nil
+	}
+	return api.ValidateAndGetSSOGroupMappingSetting(setting.Value)
+}
+
+func containsRole(roles []api.Role, role api.Role) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func containsMember(members []*store.UserMessage, userID int) bool {
+	for _, member := range members {
+		if member.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func removeMember(members []*store.UserMessage, userID int) []*store.UserMessage {
+	var result []*store.UserMessage
+	for _, member := range members {
+		if member.ID != userID {
+			result = append(result, member)
+		}
+	}
+	return result
+}
@@ -91,6 +91,10 @@ func (e *GhostSyncExecutor) Run(ctx context.Context, _ *store.TaskCheckRunMessag
 		return nil, common.Wrapf(err, common.Internal, "failed to parse table name from statement, statement: %v", statement)
 	}
 
+	if payload.OnlineMigrationConfig.GetEngine() == api.OnlineMigrationEnginePtOSC {
+		return e.runPtOSCDryRun(ctx, database, adminDataSource, tableName, renderedStatement, payload.OnlineMigrationConfig)
+	}
+
 	config, err := utils.GetGhostConfig(task.ID, database, adminDataSource, e.secret, instanceUsers, tableName, renderedStatement, true, 20000000)
 	if err != nil {
 		return nil, err
@@ -125,3 +129,40 @@ func (e *GhostSyncExecutor) Run(ctx context.Context, _ *store.TaskCheckRunMessag
 		},
 	}, nil
 }
+
+func (e *GhostSyncExecutor) runPtOSCDryRun(ctx context.Context, database *store.DatabaseMessage, dataSource *store.DataSourceMessage, tableName, statement string, onlineMigrationConfig *api.OnlineMigrationConfig) ([]api.TaskCheckResult, error) {
+	config, err := utils.GetPtOSCConfig(database, dataSource, e.secret, tableName, statement, onlineMigrationConfig)
+	if err != nil {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.Internal.Int(),
+				Title:     "pt-osc dry run failed",
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+
+	if err := utils.RunPtOSC(ctx, config, true /* dryRun */, nil); err != nil {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.Internal.Int(),
+				Title:     "pt-osc dry run failed",
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+
+	return []api.TaskCheckResult{
+		{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "OK",
+			Content:   "pt-osc dry run succeeded",
+		},
+	}, nil
+}
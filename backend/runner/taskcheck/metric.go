@@ -0,0 +1,24 @@
+package taskcheck
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var queueDepth = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "bytebase",
+		Subsystem: "task_check",
+		Name:      "queue_depth",
+		Help:      "The number of the task check runs, i.e. the plan checks, waiting for or under execution.",
+	},
+	[]string{"state"},
+)
+
+func init() {
+	prometheus.MustRegister(queueDepth)
+}
+
+func observeQueueDepth(waiting, executing int) {
+	queueDepth.WithLabelValues("waiting").Set(float64(waiting))
+	queueDepth.WithLabelValues("executing").Set(float64(executing))
+}
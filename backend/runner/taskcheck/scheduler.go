@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/common/tracing"
 	"github.com/bytebase/bytebase/backend/component/state"
 	enterpriseAPI "github.com/bytebase/bytebase/backend/enterprise/api"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
@@ -73,6 +75,13 @@ func (s *Scheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 					log.Error("Failed to retrieve running tasks", zap.Error(err))
 					return
 				}
+				executing := 0
+				for _, taskCheckRun := range taskCheckRuns {
+					if _, ok := s.stateCfg.RunningTaskChecks.Load(taskCheckRun.ID); ok {
+						executing++
+					}
+				}
+				observeQueueDepth(len(taskCheckRuns)-executing, executing)
 				for _, taskCheckRun := range taskCheckRuns {
 					executor, ok := s.executors[taskCheckRun.Type]
 					if !ok {
@@ -129,7 +138,15 @@ func (s *Scheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							s.stateCfg.InstanceOutstandingConnections[task.InstanceID]--
 							s.stateCfg.Unlock()
 						}()
-						checkResultList, err := executor.Run(ctx, taskCheckRun, task)
+						checkCtx, span := tracing.Start(ctx, "task_check.run",
+							attribute.Int("bytebase.task_check_run.id", taskCheckRun.ID),
+							attribute.String("bytebase.task_check_run.type", string(taskCheckRun.Type)),
+							attribute.Int("bytebase.task.id", task.ID),
+							attribute.Int("bytebase.pipeline.id", task.PipelineID),
+							attribute.Int("bytebase.instance.id", task.InstanceID),
+						)
+						checkResultList, err := executor.Run(checkCtx, taskCheckRun, task)
+						tracing.End(span, err)
 
 						if err == nil {
 							bytes, err := json.Marshal(api.TaskCheckRunResultPayload{
@@ -309,6 +326,22 @@ func (s *Scheduler) getTaskCheck(ctx context.Context, task *store.TaskMessage, c
 		createList = append(createList, create...)
 	}
 
+	create, err = getStatementShadowDryRunTaskCheck(task, instance, creatorID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to schedule statement shadow dry run task check")
+	}
+	if create != nil {
+		createList = append(createList, create...)
+	}
+
+	create, err = getStatementDependencyTaskCheck(task, instance, creatorID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to schedule statement dependency task check")
+	}
+	if create != nil {
+		createList = append(createList, create...)
+	}
+
 	return createList, nil
 }
 
@@ -429,6 +462,45 @@ func getStatementAffectedRowsReportTaskCheck(task *store.TaskMessage, instance *
 	}, nil
 }
 
+func getStatementShadowDryRunTaskCheck(task *store.TaskMessage, instance *store.InstanceMessage, creatorID int) ([]*store.TaskCheckRunMessage, error) {
+	if !api.IsShadowDryRunSupported(instance.Engine) {
+		return nil, nil
+	}
+	if task.Type != api.TaskDatabaseSchemaUpdate && task.Type != api.TaskDatabaseDataUpdate {
+		return nil, nil
+	}
+	payload := &shadowDryRunTaskPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return nil, err
+	}
+	if payload.ShadowDryRun == nil {
+		return nil, nil
+	}
+	return []*store.TaskCheckRunMessage{
+		{
+			CreatorID: creatorID,
+			TaskID:    task.ID,
+			Type:      api.TaskCheckDatabaseStatementShadowDryRun,
+		},
+	}, nil
+}
+
+func getStatementDependencyTaskCheck(task *store.TaskMessage, instance *store.InstanceMessage, creatorID int) ([]*store.TaskCheckRunMessage, error) {
+	if !api.IsDependencyCheckSupported(instance.Engine) {
+		return nil, nil
+	}
+	if task.Type != api.TaskDatabaseSchemaUpdate && task.Type != api.TaskDatabaseSchemaUpdateGhostSync {
+		return nil, nil
+	}
+	return []*store.TaskCheckRunMessage{
+		{
+			CreatorID: creatorID,
+			TaskID:    task.ID,
+			Type:      api.TaskCheckDatabaseStatementDependency,
+		},
+	}, nil
+}
+
 // SchedulePipelineTaskCheck schedules the task checks for a pipeline.
 func (s *Scheduler) SchedulePipelineTaskCheck(ctx context.Context, pipelineID int) error {
 	var createList []*store.TaskCheckRunMessage
@@ -91,7 +91,15 @@ func (e *StatementAdvisorCompositeExecutor) Run(ctx context.Context, taskCheckRu
 		return nil, errors.Wrapf(err, "failed to get sheet statement %d", payload.SheetID)
 	}
 
-	policy, err := e.store.GetSQLReviewPolicy(ctx, environment.UID)
+	project, err := e.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &database.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, errors.Errorf("project %q not found", database.ProjectID)
+	}
+
+	policy, err := e.store.GetSQLReviewPolicyForProject(ctx, environment.UID, project.UID)
 	if err != nil {
 		if e, ok := err.(*common.Error); ok && e.Code == common.NotFound {
 			return []api.TaskCheckResult{
@@ -124,16 +132,28 @@ func (e *StatementAdvisorCompositeExecutor) Run(ctx context.Context, taskCheckRu
 	defer driver.Close(ctx)
 	connection := driver.GetDB()
 
+	unusedIndexList, err := utils.GetUnusedIndexList(ctx, e.store, database.UID)
+	if err != nil {
+		return nil, err
+	}
+	changeHistoryList, err := utils.GetChangeHistoryListForSQLReview(ctx, e.store, database, policy.RuleList)
+	if err != nil {
+		return nil, err
+	}
+
 	materials := utils.GetSecretMapFromDatabaseMessage(database)
 	// To avoid leaking the rendered statement, the error message should use the original statement and not the rendered statement.
 	renderedStatement := utils.RenderStatement(statement, materials)
 	adviceList, err := advisor.SQLReviewCheck(renderedStatement, policy.RuleList, advisor.SQLReviewCheckContext{
-		Charset:   dbSchema.Metadata.CharacterSet,
-		Collation: dbSchema.Metadata.Collation,
-		DbType:    dbType,
-		Catalog:   catalog,
-		Driver:    connection,
-		Context:   ctx,
+		Charset:           dbSchema.Metadata.CharacterSet,
+		Collation:         dbSchema.Metadata.Collation,
+		DbType:            dbType,
+		Catalog:           catalog,
+		Driver:            connection,
+		Context:           ctx,
+		UnusedIndexList:   unusedIndexList,
+		ChangeHistoryList: changeHistoryList,
+		TemplateStatement: statement,
 	})
 	if err != nil {
 		return nil, err
@@ -144,7 +164,10 @@ func (e *StatementAdvisorCompositeExecutor) Run(ctx context.Context, taskCheckRu
 		status := api.TaskCheckStatusSuccess
 		switch advice.Status {
 		case advisor.Success:
-			continue
+			// Keep the index usage context for the reviewers.
+			if advice.Code != advisor.IndexUnused {
+				continue
+			}
 		case advisor.Warn:
 			status = api.TaskCheckStatusWarn
 		case advisor.Error:
@@ -11,6 +11,7 @@ import (
 
 	tidbparser "github.com/pingcap/tidb/parser"
 	tidbast "github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
 	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/backend/common"
@@ -99,8 +100,8 @@ func (s *StatementAffectedRowsReportExecutor) Run(ctx context.Context, _ *store.
 	switch instance.Engine {
 	case db.Postgres:
 		return reportStatementAffectedRowsForPostgres(ctx, sqlDB, renderedStatement)
-	case db.MySQL:
-		return reportStatementAffectedRowsForMySQL(ctx, sqlDB, renderedStatement, dbSchema.Metadata.CharacterSet, dbSchema.Metadata.Collation)
+	case db.MySQL, db.TiDB:
+		return reportStatementAffectedRowsForMySQL(ctx, instance.Engine, sqlDB, renderedStatement, dbSchema.Metadata.CharacterSet, dbSchema.Metadata.Collation)
 	default:
 		return nil, errors.New("unsupported db type")
 	}
@@ -234,7 +235,7 @@ func getAffectedRowsCountForPostgres(res []any) (int64, error) {
 
 // MySQL
 
-func reportStatementAffectedRowsForMySQL(ctx context.Context, sqlDB *sql.DB, statement, charset, collation string) ([]api.TaskCheckResult, error) {
+func reportStatementAffectedRowsForMySQL(ctx context.Context, engine db.Type, sqlDB *sql.DB, statement, charset, collation string) ([]api.TaskCheckResult, error) {
 	singleSQLs, err := parser.SplitMultiSQL(parser.MySQL, statement)
 	if err != nil {
 		// nolint:nilerr
@@ -289,7 +290,7 @@ func reportStatementAffectedRowsForMySQL(ctx context.Context, sqlDB *sql.DB, sta
 			})
 			continue
 		}
-		affectedRows, err := getAffectedRowsForMysql(ctx, sqlDB, root[0])
+		affectedRows, err := getAffectedRowsForMysql(ctx, engine, sqlDB, root[0])
 		if err != nil {
 			result = append(result, api.TaskCheckResult{
 				Status:    api.TaskCheckStatusError,
@@ -312,18 +313,18 @@ func reportStatementAffectedRowsForMySQL(ctx context.Context, sqlDB *sql.DB, sta
 	return result, nil
 }
 
-func getAffectedRowsForMysql(ctx context.Context, sqlDB *sql.DB, node tidbast.StmtNode) (int64, error) {
+func getAffectedRowsForMysql(ctx context.Context, engine db.Type, sqlDB *sql.DB, node tidbast.StmtNode) (int64, error) {
 	switch node := node.(type) {
 	case *tidbast.InsertStmt:
-		return getInsertAffectedRowsForMysql(ctx, sqlDB, node)
+		return getInsertAffectedRowsForMysql(ctx, engine, sqlDB, node)
 	case *tidbast.UpdateStmt, *tidbast.DeleteStmt:
-		return getUpdateOrDeleteAffectedRowsForMysql(ctx, sqlDB, node)
+		return getUpdateOrDeleteAffectedRowsForMysql(ctx, engine, sqlDB, node)
 	default:
 		return 0, nil
 	}
 }
 
-func getInsertAffectedRowsForMysql(ctx context.Context, sqlDB *sql.DB, node *tidbast.InsertStmt) (int64, error) {
+func getInsertAffectedRowsForMysql(ctx context.Context, engine db.Type, sqlDB *sql.DB, node *tidbast.InsertStmt) (int64, error) {
 	if node.Select == nil {
 		return int64(len(node.Lists)), nil
 	}
@@ -331,25 +332,131 @@ func getInsertAffectedRowsForMysql(ctx context.Context, sqlDB *sql.DB, node *tid
 	if err != nil {
 		return 0, err
 	}
-	rowCount, err := getAffectedRowsCountForMysql(res)
+	rowCount, err := getAffectedRowsCountFromQueryPlan(engine, res)
 	if err != nil {
-		return 0, errors.Wrapf(err, "failed to get insert affected rows count for mysql, res %+v", res)
+		return 0, errors.Wrapf(err, "failed to get insert affected rows count for %s, res %+v", engine, res)
 	}
 	return rowCount, nil
 }
 
-func getUpdateOrDeleteAffectedRowsForMysql(ctx context.Context, sqlDB *sql.DB, node tidbast.StmtNode) (int64, error) {
+func getUpdateOrDeleteAffectedRowsForMysql(ctx context.Context, engine db.Type, sqlDB *sql.DB, node tidbast.StmtNode) (int64, error) {
 	res, err := query(ctx, sqlDB, fmt.Sprintf("EXPLAIN %s", node.Text()))
 	if err != nil {
 		return 0, err
 	}
-	rowCount, err := getAffectedRowsCountForMysql(res)
+	rowCount, err := getAffectedRowsCountFromQueryPlan(engine, res)
+	if err == nil {
+		return rowCount, nil
+	}
+	// The query plan may have no rows estimation, e.g. the impossible WHERE.
+	// Rewrite the single table statement to the COUNT query in this case.
+	countStatement, countErr := getCountStatementForMysql(node)
+	if countErr != nil {
+		return 0, errors.Wrapf(err, "failed to get update or delete affected rows count for %s, res %+v", engine, res)
+	}
+	countRes, err := query(ctx, sqlDB, countStatement)
+	if err != nil {
+		return 0, err
+	}
+	rowCount, err = getCountResult(countRes)
 	if err != nil {
-		return 0, errors.Wrapf(err, "failed to get update or delete affected rows count for mysql, res %+v", res)
+		return 0, errors.Wrapf(err, "failed to get update or delete affected rows count for %s, res %+v", engine, countRes)
 	}
 	return rowCount, nil
 }
 
+func getAffectedRowsCountFromQueryPlan(engine db.Type, res []any) (int64, error) {
+	if engine == db.TiDB {
+		return getAffectedRowsCountForTiDB(res)
+	}
+	return getAffectedRowsCountForMysql(res)
+}
+
+// getCountStatementForMysql rewrites the single table UPDATE or DELETE statement to the COUNT query.
+func getCountStatementForMysql(node tidbast.StmtNode) (string, error) {
+	var tableRefs *tidbast.TableRefsClause
+	var where tidbast.ExprNode
+	var limit *tidbast.Limit
+	switch node := node.(type) {
+	case *tidbast.UpdateStmt:
+		if node.MultipleTable || node.With != nil {
+			return "", errors.New("only support single table UPDATE statement")
+		}
+		tableRefs, where, limit = node.TableRefs, node.Where, node.Limit
+	case *tidbast.DeleteStmt:
+		if node.IsMultiTable || node.With != nil {
+			return "", errors.New("only support single table DELETE statement")
+		}
+		tableRefs, where, limit = node.TableRefs, node.Where, node.Limit
+	default:
+		return "", errors.Errorf("unsupported statement type %T", node)
+	}
+	if tableRefs == nil || tableRefs.TableRefs == nil {
+		return "", errors.New("missing table")
+	}
+	if tableRefs.TableRefs.Right != nil {
+		return "", errors.New("only support single table statement")
+	}
+
+	var buf strings.Builder
+	restoreCtx := format.NewRestoreCtx(format.DefaultRestoreFlags, &buf)
+	if limit != nil {
+		buf.WriteString("SELECT COUNT(*) FROM (SELECT 1 FROM ")
+	} else {
+		buf.WriteString("SELECT COUNT(*) FROM ")
+	}
+	if err := tableRefs.TableRefs.Restore(restoreCtx); err != nil {
+		return "", err
+	}
+	if where != nil {
+		buf.WriteString(" WHERE ")
+		if err := where.Restore(restoreCtx); err != nil {
+			return "", err
+		}
+	}
+	if limit != nil {
+		buf.WriteString(" ")
+		if err := limit.Restore(restoreCtx); err != nil {
+			return "", err
+		}
+		buf.WriteString(") AS t")
+	}
+	return buf.String(), nil
+}
+
+func getCountResult(res []any) (int64, error) {
+	// the res struct is []any{columnName, columnTable, rowDataList}
+	if len(res) != 3 {
+		return 0, errors.Errorf("expected 3 but got %d", len(res))
+	}
+	rowList, ok := res[2].([]any)
+	if !ok {
+		return 0, errors.Errorf("expected []any but got %t", res[2])
+	}
+	if len(rowList) != 1 {
+		return 0, errors.Errorf("expected one row but got %d", len(rowList))
+	}
+	row, ok := rowList[0].([]any)
+	if !ok {
+		return 0, errors.Errorf("expected []any but got %t", rowList[0])
+	}
+	if len(row) != 1 {
+		return 0, errors.Errorf("expected one column but got %d", len(row))
+	}
+	switch col := row[0].(type) {
+	case int64:
+		return col, nil
+	case string:
+		v, err := strconv.ParseInt(col, 10, 64)
+		if err != nil {
+			return 0, errors.Errorf("expected int64 but got string(%s)", col)
+		}
+		return v, nil
+	default:
+		return 0, errors.Errorf("expected int64 but got %t", row[0])
+	}
+}
+
 func getAffectedRowsCountForMysql(res []any) (int64, error) {
 	// the res struct is []any{columnName, columnTable, rowDataList}
 	if len(res) != 3 {
@@ -411,6 +518,55 @@ func getAffectedRowsCountForMysql(res []any) (int64, error) {
 	return 0, errors.Errorf("failed to extract rows from query plan")
 }
 
+func getAffectedRowsCountForTiDB(res []any) (int64, error) {
+	// the res struct is []any{columnName, columnTable, rowDataList}
+	if len(res) != 3 {
+		return 0, errors.Errorf("expected 3 but got %d", len(res))
+	}
+	rowList, ok := res[2].([]any)
+	if !ok {
+		return 0, errors.Errorf("expected []any but got %t", res[2])
+	}
+	if len(rowList) < 1 {
+		return 0, errors.Errorf("not found any data")
+	}
+
+	// TiDB EXPLAIN statement result has 5 columns.
+	// the column 1 is the data 'estRows'.
+	// the first not-N/A value of column 1 is the affected rows count.
+	//
+	// mysql> explain delete from t where a > 1;
+	// +-------------------------+---------+-----------+---------------+--------------------------------+
+	// | id                      | estRows | task      | access object | operator info                  |
+	// +-------------------------+---------+-----------+---------------+--------------------------------+
+	// | Delete_4                | N/A     | root      |               | N/A                            |
+	// | └─TableReader_8         | 3333.33 | root      |               | data:Selection_7               |
+	// |   └─Selection_7         | 3333.33 | cop[tikv] |               | gt(test.t.a, 1)                |
+	// |     └─TableFullScan_6   | 10000.00| cop[tikv] | table:t       | keep order:false, stats:pseudo |
+	// +-------------------------+---------+-----------+---------------+--------------------------------+
+
+	for _, rowAny := range rowList {
+		row, ok := rowAny.([]any)
+		if !ok {
+			return 0, errors.Errorf("expected []any but got %t", row)
+		}
+		if len(row) != 5 {
+			return 0, errors.Errorf("expected 5 but got %d", len(row))
+		}
+		col, ok := row[1].(string)
+		if !ok || col == "N/A" {
+			continue
+		}
+		v, err := strconv.ParseFloat(col, 64)
+		if err != nil {
+			return 0, errors.Errorf("expected float but got string(%s)", col)
+		}
+		return int64(v), nil
+	}
+
+	return 0, errors.Errorf("failed to extract rows from query plan")
+}
+
 // Query runs the EXPLAIN or SELECT statements for advisors.
 func query(ctx context.Context, connection *sql.DB, statement string) ([]any, error) {
 	tx, err := connection.BeginTx(ctx, &sql.TxOptions{})
@@ -3,6 +3,7 @@ package taskcheck
 import (
 	"testing"
 
+	tidbparser "github.com/pingcap/tidb/parser"
 	"github.com/stretchr/testify/require"
 )
 
@@ -64,3 +65,64 @@ func TestGetAffectedRowsCountForMysql(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAffectedRowsCountForTiDB(t *testing.T) {
+	res := []any{
+		[]string{"id", "estRows", "task", "access object", "operator info"},
+		[]string{"VARCHAR", "VARCHAR", "VARCHAR", "VARCHAR", "VARCHAR"},
+		[]any{
+			[]any{"Delete_4", "N/A", "root", "", "N/A"},
+			[]any{"└─TableReader_8", "3333.33", "root", "", "data:Selection_7"},
+			[]any{"  └─Selection_7", "3333.33", "cop[tikv]", "", "gt(test.t.a, 1)"},
+			[]any{"    └─TableFullScan_6", "10000.00", "cop[tikv]", "table:t", "keep order:false, stats:pseudo"},
+		},
+	}
+
+	a := require.New(t)
+	got, err := getAffectedRowsCountForTiDB(res)
+	a.NoError(err)
+	a.Equal(int64(3333), got)
+}
+
+func TestGetCountStatementForMysql(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      string
+		wantErr   bool
+	}{
+		{
+			statement: "DELETE FROM t WHERE a = 1 AND 1 = 0",
+			want:      "SELECT COUNT(*) FROM `t` WHERE `a`=1 AND 1=0",
+		},
+		{
+			statement: "UPDATE db.t SET a = 1",
+			want:      "SELECT COUNT(*) FROM `db`.`t`",
+		},
+		{
+			statement: "UPDATE t SET a = 1 WHERE b > 2 ORDER BY c LIMIT 10",
+			want:      "SELECT COUNT(*) FROM (SELECT 1 FROM `t` WHERE `b`>2 LIMIT 10) AS t",
+		},
+		{
+			statement: "DELETE t1 FROM t1 JOIN t2 ON t1.id = t2.id",
+			wantErr:   true,
+		},
+		{
+			statement: "UPDATE t1, t2 SET t1.a = t2.a WHERE t1.id = t2.id",
+			wantErr:   true,
+		},
+	}
+
+	a := require.New(t)
+	p := tidbparser.New()
+	for _, test := range tests {
+		node, err := p.ParseOneStmt(test.statement, "", "")
+		a.NoError(err)
+		got, err := getCountStatementForMysql(node)
+		if test.wantErr {
+			a.Error(err, test.statement)
+			continue
+		}
+		a.NoError(err)
+		a.Equal(test.want, got)
+	}
+}
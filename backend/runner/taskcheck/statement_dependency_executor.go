@@ -0,0 +1,139 @@
+package taskcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+// NewStatementDependencyExecutor creates a task check statement dependency executor.
+func NewStatementDependencyExecutor(store *store.Store) Executor {
+	return &StatementDependencyExecutor{
+		store: store,
+	}
+}
+
+// StatementDependencyExecutor is the task check statement dependency executor. It finds the views, functions and
+// foreign keys in the synced metadata referencing the objects dropped, renamed or altered by the statement, and fails
+// if any of them would break unless the check is overridden.
+type StatementDependencyExecutor struct {
+	store *store.Store
+}
+
+type dependencyTaskPayload struct {
+	SheetID            int                     `json:"sheetId,omitempty"`
+	DependencyOverride *api.DependencyOverride `json:"dependencyOverride,omitempty"`
+}
+
+// Run will run the task check statement dependency executor once.
+func (e *StatementDependencyExecutor) Run(ctx context.Context, _ *store.TaskCheckRunMessage, task *store.TaskMessage) ([]api.TaskCheckResult, error) {
+	payload := &dependencyTaskPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return nil, err
+	}
+	instance, err := e.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, errors.Errorf("instance %d not found", task.InstanceID)
+	}
+	if !api.IsDependencyCheckSupported(instance.Engine) {
+		return nil, nil
+	}
+	database, err := e.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: task.DatabaseID})
+	if err != nil {
+		return nil, err
+	}
+	if database == nil {
+		return nil, errors.Errorf("database %v not found", task.DatabaseID)
+	}
+	dbSchema, err := e.store.GetDBSchema(ctx, database.UID)
+	if err != nil {
+		return nil, err
+	}
+	if dbSchema == nil {
+		return nil, errors.Errorf("database schema %v not found", task.DatabaseID)
+	}
+	statement, err := e.store.GetSheetStatementByID(ctx, payload.SheetID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get sheet statement %d", payload.SheetID)
+	}
+	renderedStatement := utils.RenderStatement(statement, utils.GetSecretMapFromDatabaseMessage(database))
+
+	changedList, err := utils.ExtractChangedObjects(instance.Engine, renderedStatement)
+	if err != nil {
+		// nolint:nilerr
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.AdvisorNamespace,
+				Code:      advisor.StatementSyntaxError.Int(),
+				Title:     "Syntax error",
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+	dependentList := utils.FindDependentObjects(instance.Engine, dbSchema.Metadata, changedList)
+	if len(dependentList) == 0 {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusSuccess,
+				Namespace: api.BBNamespace,
+				Code:      common.Ok.Int(),
+				Title:     "OK",
+				Content:   "No views, functions or foreign keys reference the changed objects",
+			},
+		}, nil
+	}
+
+	// The override approves the statement of the sheet only.
+	var override *api.DependencyOverride
+	if payload.DependencyOverride != nil && payload.DependencyOverride.SheetID == payload.SheetID {
+		override = payload.DependencyOverride
+	}
+	var results []api.TaskCheckResult
+	for _, dependent := range dependentList {
+		result := api.TaskCheckResult{
+			Status:    api.TaskCheckStatusWarn,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     fmt.Sprintf("%s references %s", dependent, dependent.Changed),
+			Content:   fmt.Sprintf("The %s is changed by %q, please check %s after the change.", dependent.Changed, dependent.Changed.Action, dependent),
+		}
+		if dependent.Breaking {
+			result.Content = fmt.Sprintf("The %s would be broken or dropped by %q of %s.", dependent, dependent.Changed.Action, dependent.Changed)
+			if override == nil {
+				result.Status = api.TaskCheckStatusError
+				result.Code = common.TaskDependentObjectBroken.Int()
+			}
+		}
+		results = append(results, result)
+	}
+	if override != nil {
+		approver, err := e.store.GetUserByID(ctx, override.ApproverID)
+		if err != nil {
+			return nil, err
+		}
+		approverName := fmt.Sprintf("user %d", override.ApproverID)
+		if approver != nil {
+			approverName = approver.Name
+		}
+		results = append(results, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusWarn,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "Dependency check overridden",
+			Content:   fmt.Sprintf("Overridden by %s: %s", approverName, override.Reason),
+		})
+	}
+	return results, nil
+}
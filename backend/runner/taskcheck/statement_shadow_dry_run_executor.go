@@ -0,0 +1,226 @@
+package taskcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+const (
+	// shadowDryRunSlowestStatementCount is the number of the slowest statements reported.
+	shadowDryRunSlowestStatementCount = 5
+	shadowDryRunStatementMaxLength    = 100
+)
+
+// NewStatementShadowDryRunExecutor creates a task check statement shadow dry run executor.
+func NewStatementShadowDryRunExecutor(store *store.Store, dbFactory *dbfactory.DBFactory) Executor {
+	return &StatementShadowDryRunExecutor{
+		store:     store,
+		dbFactory: dbFactory,
+	}
+}
+
+// StatementShadowDryRunExecutor is the task check statement shadow dry run executor. It applies the statement to a
+// shadow database cloned from the target database, and reports the timing, the locks and the post-check results.
+type StatementShadowDryRunExecutor struct {
+	store     *store.Store
+	dbFactory *dbfactory.DBFactory
+}
+
+type shadowDryRunTaskPayload struct {
+	SheetID      int                     `json:"sheetId,omitempty"`
+	ShadowDryRun *api.ShadowDryRunConfig `json:"shadowDryRun,omitempty"`
+}
+
+// Run will run the task check statement shadow dry run executor once.
+func (e *StatementShadowDryRunExecutor) Run(ctx context.Context, _ *store.TaskCheckRunMessage, task *store.TaskMessage) ([]api.TaskCheckResult, error) {
+	payload := &shadowDryRunTaskPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return nil, err
+	}
+	if payload.ShadowDryRun == nil {
+		return nil, nil
+	}
+	instance, err := e.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, errors.Errorf("instance %d not found", task.InstanceID)
+	}
+	if !api.IsShadowDryRunSupported(instance.Engine) {
+		return nil, nil
+	}
+	database, err := e.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: task.DatabaseID})
+	if err != nil {
+		return nil, err
+	}
+	if database == nil {
+		return nil, errors.Errorf("database %v not found", task.DatabaseID)
+	}
+	statement, err := e.store.GetSheetStatementByID(ctx, payload.SheetID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get sheet statement %d", payload.SheetID)
+	}
+	materials := utils.GetSecretMapFromDatabaseMessage(database)
+	renderedStatement := utils.RenderStatement(statement, materials)
+
+	report, err := e.dryRun(ctx, task, instance, database, renderedStatement, payload.ShadowDryRun)
+	if err != nil {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusError,
+				Namespace: api.BBNamespace,
+				Code:      common.Internal.Int(),
+				Title:     "Shadow database dry run failed",
+				Content:   err.Error(),
+			},
+		}, nil
+	}
+	// To avoid leaking the secrets, the rendered statements are referred by the positions.
+	return convertShadowDryRunReport(report, len(materials) > 0), nil
+}
+
+func (e *StatementShadowDryRunExecutor) dryRun(ctx context.Context, task *store.TaskMessage, instance *store.InstanceMessage, database *store.DatabaseMessage, statement string, config *api.ShadowDryRunConfig) (*utils.ShadowDryRunReport, error) {
+	sourceDriver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, database.DatabaseName)
+	if err != nil {
+		return nil, err
+	}
+	defer sourceDriver.Close(ctx)
+	var schemaBuf bytes.Buffer
+	if _, err := sourceDriver.Dump(ctx, &schemaBuf, true /* schemaOnly */); err != nil {
+		return nil, errors.Wrap(err, "failed to dump the schema")
+	}
+
+	instanceDriver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, "")
+	if err != nil {
+		return nil, err
+	}
+	defer instanceDriver.Close(ctx)
+	shadowDatabaseName := fmt.Sprintf("bbshadow_%d_%d", task.ID, time.Now().Unix())
+	quotedName := fmt.Sprintf("`%s`", shadowDatabaseName)
+	if instance.Engine == db.Postgres {
+		quotedName = fmt.Sprintf(`"%s"`, shadowDatabaseName)
+	}
+	if _, err := instanceDriver.Execute(ctx, fmt.Sprintf("CREATE DATABASE %s;", quotedName), true /* createDatabase */); err != nil {
+		return nil, errors.Wrap(err, "failed to create the shadow database")
+	}
+	defer func() {
+		if _, err := instanceDriver.GetDB().ExecContext(ctx, fmt.Sprintf("DROP DATABASE IF EXISTS %s", quotedName)); err != nil {
+			log.Warn("Failed to drop the shadow database",
+				zap.String("instance", instance.ResourceID),
+				zap.String("database", shadowDatabaseName),
+				zap.Error(err))
+		}
+	}()
+
+	// The shadow driver is closed before the shadow database is dropped.
+	shadowDriver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, instance, shadowDatabaseName)
+	if err != nil {
+		return nil, err
+	}
+	defer shadowDriver.Close(ctx)
+	return utils.RunShadowDryRun(ctx, instance.Engine, sourceDriver, shadowDriver, schemaBuf.String(), statement, config.SampleRows)
+}
+
+func convertShadowDryRunReport(report *utils.ShadowDryRunReport, hideStatement bool) []api.TaskCheckResult {
+	positions := make(map[*utils.ShadowDryRunStatement]int)
+	for i, statement := range report.StatementList {
+		positions[statement] = i + 1
+	}
+	label := func(statement *utils.ShadowDryRunStatement) string {
+		if hideStatement {
+			return fmt.Sprintf("statement #%d", positions[statement])
+		}
+		text, _ := common.TruncateString(strings.TrimSpace(statement.Statement), shadowDryRunStatementMaxLength)
+		return fmt.Sprintf("%q", text)
+	}
+
+	var results []api.TaskCheckResult
+	for _, warning := range report.SampleWarnings {
+		results = append(results, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusWarn,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "Failed to sample the table",
+			Content:   warning,
+		})
+	}
+	if report.Error != "" {
+		failed := report.StatementList[len(report.StatementList)-1]
+		results = append(results, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusError,
+			Namespace: api.BBNamespace,
+			Code:      common.Internal.Int(),
+			Title:     "Shadow database dry run failed",
+			Content:   fmt.Sprintf("%s failed after %s: %s", label(failed), failed.Duration.Round(time.Millisecond), report.Error),
+		})
+		return results
+	}
+
+	results = append(results, api.TaskCheckResult{
+		Status:    api.TaskCheckStatusSuccess,
+		Namespace: api.BBNamespace,
+		Code:      common.Ok.Int(),
+		Title:     "Shadow database dry run succeeded",
+		Content:   fmt.Sprintf("Applied %d statements in %s with %d sampled rows.", len(report.StatementList), report.Duration.Round(time.Millisecond), report.SampledRows),
+	})
+	var timings []string
+	for _, statement := range report.SlowestStatements(shadowDryRunSlowestStatementCount) {
+		timings = append(timings, fmt.Sprintf("%s: %s", label(statement), statement.Duration.Round(time.Millisecond)))
+	}
+	if len(timings) > 0 {
+		results = append(results, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "Slowest statements",
+			Content:   strings.Join(timings, "\n"),
+		})
+	}
+	for _, statement := range report.StatementList {
+		if len(statement.ExclusiveLocks) == 0 {
+			continue
+		}
+		results = append(results, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusWarn,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "ACCESS EXCLUSIVE lock",
+			Content:   fmt.Sprintf("%s blocks the reads and writes of %s for %s.", label(statement), strings.Join(statement.ExclusiveLocks, ", "), statement.Duration.Round(time.Millisecond)),
+		})
+	}
+	if report.RowLockWaits > 0 {
+		results = append(results, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusWarn,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "Row lock waits",
+			Content:   fmt.Sprintf("%d InnoDB row lock waits took %d ms on the instance during the dry run.", report.RowLockWaits, report.RowLockTimeMs),
+		})
+	}
+	for _, problem := range report.PostCheckErrors {
+		results = append(results, api.TaskCheckResult{
+			Status:    api.TaskCheckStatusError,
+			Namespace: api.BBNamespace,
+			Code:      common.Internal.Int(),
+			Title:     "Shadow database post-check failed",
+			Content:   problem,
+		})
+	}
+	return results
+}
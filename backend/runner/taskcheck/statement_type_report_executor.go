@@ -271,6 +271,10 @@ func getStatementTypeFromAstNode(node ast.Node) string {
 		case ast.TableTypeBaseTable:
 			return "CREATE_TABLE"
 		}
+	case *ast.CreateViewStmt:
+		if node.Name.Type == ast.TableTypeView {
+			return "CREATE_VIEW"
+		}
 	case *ast.CreateSequenceStmt:
 		return "CREATE_SEQUENCE"
 	case *ast.CreateDatabaseStmt:
@@ -41,8 +41,12 @@ type DatabaseCreateExecutor struct {
 }
 
 var cannotCreateDatabase = map[db.Type]bool{
-	db.Redis:  true,
-	db.Oracle: true,
+	db.Redis:         true,
+	db.Oracle:        true,
+	db.Elasticsearch: true,
+	db.Cassandra:     true,
+	db.DynamoDB:      true,
+	db.Neo4j:         true,
 }
 
 // RunOnce will run the database create task executor once.
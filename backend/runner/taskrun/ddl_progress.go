@@ -0,0 +1,89 @@
+package taskrun
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	"github.com/bytebase/bytebase/backend/component/state"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/mysql"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+const ddlProgressInterval = 3 * time.Second
+
+// trackDDLProgress polls the progress of the DDL statements executed by the driver in a separate connection, and
+// stores it as the task progress. It returns the function to stop the tracking.
+func trackDDLProgress(ctx context.Context, dbFactory *dbfactory.DBFactory, stateCfg *state.State, task *store.TaskMessage, instance *store.InstanceMessage, databaseName string, driver db.Driver) func() {
+	var connID string
+	if instance.Engine == db.MySQL {
+		mysqlDriver, ok := db.UnwrapDriver(driver).(*mysql.Driver)
+		if !ok {
+			return func() {}
+		}
+		id, err := mysqlDriver.GetMigrationConnID(ctx)
+		if err != nil {
+			log.Warn("Failed to get the migration connection ID for the DDL progress", zap.Int("task", task.ID), zap.Error(err))
+			return func() {}
+		}
+		connID = id
+	}
+
+	childCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pollDriver, err := dbFactory.GetAdminDatabaseDriver(childCtx, instance, databaseName)
+		if err != nil {
+			log.Debug("Failed to get the driver for the DDL progress", zap.Int("task", task.ID), zap.Error(err))
+			return
+		}
+		defer pollDriver.Close(context.Background())
+
+		ticker := time.NewTicker(ddlProgressInterval)
+		defer ticker.Stop()
+		createdTs := time.Now().Unix()
+		for {
+			select {
+			case <-ticker.C:
+				progress, err := utils.GetDDLProgress(childCtx, instance.Engine, pollDriver.GetDB(), databaseName, connID)
+				if err != nil {
+					// The progress views or the performance_schema tables are usually unavailable, so there is no point to retry.
+					log.Debug("Stop polling the DDL progress", zap.Int("task", task.ID), zap.Error(err))
+					return
+				}
+				if progress == nil {
+					// The statement between the DDL statements reports no progress.
+					stateCfg.TaskProgress.Delete(task.ID)
+					continue
+				}
+				payload, err := json.Marshal(&api.DDLProgressPayload{Phase: progress.Phase})
+				if err != nil {
+					return
+				}
+				stateCfg.TaskProgress.Store(task.ID, api.Progress{
+					TotalUnit:     progress.Total,
+					CompletedUnit: progress.Completed,
+					CreatedTs:     createdTs,
+					UpdatedTs:     time.Now().Unix(),
+					Payload:       string(payload),
+				})
+			case <-childCtx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		// Wait for the polling to stop, so the stale progress won't be stored after the task finishes.
+		<-done
+	}
+}
@@ -5,16 +5,18 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gosimple/slug"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/common/tracing"
 	"github.com/bytebase/bytebase/backend/component/activity"
 	"github.com/bytebase/bytebase/backend/component/config"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
@@ -23,6 +25,7 @@ import (
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/plugin/db/mysql"
+	"github.com/bytebase/bytebase/backend/plugin/db/pg"
 	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
 
 	"github.com/bytebase/bytebase/backend/plugin/parser/sql/transform"
@@ -43,8 +46,23 @@ type Executor interface {
 	RunOnce(ctx context.Context, task *store.TaskMessage) (terminated bool, result *api.TaskRunResultPayload, err error)
 }
 
-// RunExecutorOnce wraps a TaskExecutor.RunOnce call with panic recovery.
+// RunExecutorOnce wraps a TaskExecutor.RunOnce call with panic recovery and the span of the task run.
 func RunExecutorOnce(ctx context.Context, exec Executor, task *store.TaskMessage) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	ctx, span := tracing.Start(ctx, "task.run",
+		attribute.Int("bytebase.task.id", task.ID),
+		attribute.String("bytebase.task.name", task.Name),
+		attribute.String("bytebase.task.type", string(task.Type)),
+		attribute.Int("bytebase.pipeline.id", task.PipelineID),
+		attribute.Int("bytebase.stage.id", task.StageID),
+		attribute.Int("bytebase.instance.id", task.InstanceID),
+		// The time since the task was last updated, e.g. waiting for the earlier tasks of the same database.
+		attribute.Int64("bytebase.task.pending_seconds", time.Now().Unix()-task.UpdatedTs),
+	)
+	// Registered first to end the span after recovering from the panic.
+	defer func() {
+		span.SetAttributes(attribute.Bool("bytebase.task.terminated", terminated))
+		tracing.End(span, err)
+	}()
 	defer func() {
 		if r := recover(); r != nil {
 			panicErr, ok := r.(error)
@@ -58,7 +76,9 @@ func RunExecutorOnce(ctx context.Context, exec Executor, task *store.TaskMessage
 		}
 	}()
 
-	return exec.RunOnce(ctx, task)
+	// The drivers opened by the task share one connection of the instance, e.g. the read-only driver opened in the
+	// middle of the migration.
+	return exec.RunOnce(dbfactory.WithConnectionScope(ctx), task)
 }
 
 func preMigration(ctx context.Context, stores *store.Store, profile config.Profile, task *store.TaskMessage, migrationType db.MigrationType, statement, schemaVersion string, vcsPushEvent *vcsPlugin.PushEvent) (*db.MigrationInfo, error) {
@@ -180,6 +200,48 @@ func executeMigration(ctx context.Context, stores *store.Store, dbFactory *dbfac
 		task = updatedTask
 	}
 
+	if task.Type == api.TaskDatabaseSchemaUpdate && instance.Engine == db.Postgres {
+		payload := &api.TaskDatabaseSchemaUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return "", "", errors.Wrap(err, "invalid database schema update payload")
+		}
+		if payload.ZeroDowntime != nil {
+			return executeZeroDowntimeMigration(ctx, stores, driver, mi, statement, payload.ZeroDowntime)
+		}
+	}
+
+	if task.Type == api.TaskDatabaseDataUpdate {
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return "", "", errors.Wrap(err, "invalid database data update payload")
+		}
+		if payload.Seed != nil {
+			// The seed data change is generated against the table contents of each database, so the databases in different environments converge to the dataset.
+			change, err := utils.GenerateSeedDataChange(ctx, driver, instance.Engine, payload.Seed)
+			if err != nil {
+				return "", "", errors.Wrapf(err, "failed to generate the seed data change for table %q", payload.Seed.Table)
+			}
+			statement = change.Statement
+		}
+		if payload.Chunked != nil {
+			return executeChunkedDMLMigration(ctx, stores, dbFactory, stateCfg, task, instance, database.DatabaseName, driver, mi, statement, payload.Chunked)
+		}
+	}
+
+	if (task.Type == api.TaskDatabaseSchemaUpdate || task.Type == api.TaskDatabaseSchemaUpdateSDL) && (instance.Engine == db.MySQL || instance.Engine == db.Postgres) {
+		// The long-running ALTER TABLE and CREATE INDEX statements report their phases and percent-complete.
+		stopTracking := trackDDLProgress(ctx, dbFactory, stateCfg, task, instance, database.DatabaseName, driver)
+		defer stopTracking()
+	}
+
+	resumablePayload := &resumableTaskPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), resumablePayload); err != nil {
+		return "", "", errors.Wrap(err, "invalid task payload")
+	}
+	if resumablePayload.Resumable {
+		return executeResumableMigration(ctx, stores, driver, task, instance.Engine, mi, statement, resumablePayload)
+	}
+
 	var executeBeforeCommitTx func(tx *sql.Tx) error
 	if task.Type == api.TaskDatabaseDataUpdate && instance.Engine == db.Oracle {
 		// getSetOracleTransactionIdFunc will update the task payload to set the Oracle transaction id, we need to re-retrieve the task to store to the RollbackGenerate.
@@ -225,6 +287,129 @@ func executeMigration(ctx context.Context, stores *store.Store, dbFactory *dbfac
 	return migrationID, schema, nil
 }
 
+// executeZeroDowntimeMigration executes the PostgreSQL migration in the zero-downtime steps, each step runs with the lock timeout and retries.
+func executeZeroDowntimeMigration(ctx context.Context, stores *store.Store, driver db.Driver, mi *db.MigrationInfo, statement string, config *api.ZeroDowntimeMigrationConfig) (migrationID string, schema string, err error) {
+	pgDriver, ok := db.UnwrapDriver(driver).(*pg.Driver)
+	if !ok {
+		return "", "", errors.New("failed to cast driver to postgres driver")
+	}
+	option := pg.ZeroDowntimeOption{
+		LockTimeout: time.Duration(config.LockTimeoutMs) * time.Millisecond,
+		MaxRetries:  config.MaxRetries,
+		BatchSize:   config.BatchSize,
+	}
+	execFunc := func(execStatement string) error {
+		if _, err := pgDriver.ExecuteZeroDowntimeMigration(ctx, execStatement, option); err != nil {
+			return err
+		}
+		return nil
+	}
+	return utils.ExecuteMigrationWithFunc(ctx, stores, driver, mi, statement, execFunc)
+}
+
+// executeChunkedDMLMigration executes the UPDATE and DELETE statements in the primary key range chunks, each chunk is committed on its own.
+// The execution is throttled by the replication lag, and could be paused and resumed between the chunks.
+func executeChunkedDMLMigration(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFactory, stateCfg *state.State, task *store.TaskMessage, instance *store.InstanceMessage, databaseName string, driver db.Driver, mi *db.MigrationInfo, statement string, config *api.ChunkedDMLConfig) (migrationID string, schema string, err error) {
+	option := utils.ChunkedDMLOption{
+		BatchSize:         config.BatchSize,
+		SleepInterval:     time.Duration(config.SleepMs) * time.Millisecond,
+		MaxReplicationLag: time.Duration(config.MaxReplicationLagSeconds) * time.Second,
+		IsPaused: func() bool {
+			_, ok := stateCfg.ChunkedDMLPaused.Load(task.ID)
+			return ok
+		},
+	}
+	if option.MaxReplicationLag > 0 {
+		option.GetReplicationLag = func(ctx context.Context) (time.Duration, error) {
+			if instance.Engine == db.Postgres {
+				return utils.GetReplicationLag(ctx, instance.Engine, driver.GetDB())
+			}
+			// The replication lag of MySQL is reported by the replica, so it's read from the read-only data source.
+			// There is no lag to throttle if the instance doesn't have any.
+			if !hasReadOnlyDataSource(instance) {
+				return 0, nil
+			}
+			replicaDriver, err := dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, databaseName)
+			if err != nil {
+				return 0, err
+			}
+			defer replicaDriver.Close(ctx)
+			return utils.GetReplicationLag(ctx, instance.Engine, replicaDriver.GetDB())
+		}
+	}
+	createdTs := time.Now().Unix()
+	option.OnProgress = func(completed, total int64, paused bool, comment string) {
+		payload, err := json.Marshal(&api.ChunkedDMLProgressPayload{Paused: paused, Comment: comment})
+		if err != nil {
+			return
+		}
+		stateCfg.TaskProgress.Store(task.ID, api.Progress{
+			TotalUnit:     total,
+			CompletedUnit: completed,
+			CreatedTs:     createdTs,
+			UpdatedTs:     time.Now().Unix(),
+			Payload:       string(payload),
+		})
+	}
+	defer stateCfg.ChunkedDMLPaused.Delete(task.ID)
+
+	execFunc := func(execStatement string) error {
+		if _, err := utils.ExecuteChunkedDML(ctx, instance.Engine, driver.GetDB(), execStatement, option); err != nil {
+			return err
+		}
+		return nil
+	}
+	return utils.ExecuteMigrationWithFunc(ctx, stores, driver, mi, statement, execFunc)
+}
+
+type resumableTaskPayload struct {
+	SheetID    int                      `json:"sheetId,omitempty"`
+	Resumable  bool                     `json:"resumable,omitempty"`
+	Checkpoint *api.StatementCheckpoint `json:"checkpoint,omitempty"`
+}
+
+// executeResumableMigration executes the statements one by one and records the checkpoint in the task payload after each of them,
+// so the retry of the failed task starts from the first statement not executed. The checkpoint of another sheet is ignored.
+func executeResumableMigration(ctx context.Context, stores *store.Store, driver db.Driver, task *store.TaskMessage, engine db.Type, mi *db.MigrationInfo, statement string, payload *resumableTaskPayload) (migrationID string, schema string, err error) {
+	option := utils.ResumableMigrationOption{
+		OnStatementDone: func(completed, total int) error {
+			_, err := stores.UpdateTaskV2(ctx, &api.TaskPatch{
+				ID:        task.ID,
+				UpdaterID: api.SystemBotID,
+				StatementCheckpoint: &api.StatementCheckpoint{
+					SheetID:        payload.SheetID,
+					CompletedCount: completed,
+					TotalCount:     total,
+				},
+			})
+			return err
+		},
+	}
+	if checkpoint := payload.Checkpoint; checkpoint != nil && checkpoint.SheetID == payload.SheetID {
+		option.CompletedCount = checkpoint.CompletedCount
+	}
+
+	execFunc := func(execStatement string) error {
+		if _, err := utils.ExecuteResumableMigration(ctx, engine, execStatement, option, func(ctx context.Context, statement string) error {
+			_, err := driver.Execute(ctx, statement, false /* createDatabase */)
+			return err
+		}); err != nil {
+			return err
+		}
+		return nil
+	}
+	return utils.ExecuteMigrationWithFunc(ctx, stores, driver, mi, statement, execFunc)
+}
+
+func hasReadOnlyDataSource(instance *store.InstanceMessage) bool {
+	for _, dataSource := range instance.DataSources {
+		if dataSource.Type == api.RO {
+			return true
+		}
+	}
+	return false
+}
+
 func getSetOracleTransactionIDFunc(ctx context.Context, task *store.TaskMessage, store *store.Store) func(tx *sql.Tx) error {
 	return func(tx *sql.Tx) error {
 		payload := &api.TaskDatabaseDataUpdatePayload{}
@@ -271,7 +456,7 @@ func getSetOracleTransactionIDFunc(ctx context.Context, task *store.TaskMessage,
 }
 
 func setThreadIDAndStartBinlogCoordinate(ctx context.Context, driver db.Driver, task *store.TaskMessage, store *store.Store) (*store.TaskMessage, error) {
-	mysqlDriver, ok := driver.(*mysql.Driver)
+	mysqlDriver, ok := db.UnwrapDriver(driver).(*mysql.Driver)
 	if !ok {
 		return nil, errors.Errorf("failed to cast driver to mysql.Driver")
 	}
@@ -350,7 +535,7 @@ func setMigrationIDAndEndBinlogCoordinate(ctx context.Context, driver db.Driver,
 	return updatedTask, nil
 }
 
-func postMigration(ctx context.Context, stores *store.Store, activityManager *activity.Manager, license enterpriseAPI.LicenseService, task *store.TaskMessage, vcsPushEvent *vcsPlugin.PushEvent, mi *db.MigrationInfo, migrationID string, schema string) (bool, *api.TaskRunResultPayload, error) {
+func postMigration(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFactory, activityManager *activity.Manager, license enterpriseAPI.LicenseService, task *store.TaskMessage, vcsPushEvent *vcsPlugin.PushEvent, mi *db.MigrationInfo, migrationID string, schema string) (bool, *api.TaskRunResultPayload, error) {
 	instance, err := stores.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
 	if err != nil {
 		return true, nil, err
@@ -407,17 +592,24 @@ func postMigration(ctx context.Context, stores *store.Store, activityManager *ac
 
 	if writebackBranch != "" {
 		// Transform the schema to standard style for SDL mode.
-		if instance.Engine == db.MySQL || instance.Engine == db.MariaDB {
-			standardSchema, err := transform.SchemaTransform(parser.MySQL, schema)
+		var engineType parser.EngineType
+		switch instance.Engine {
+		case db.MySQL, db.MariaDB:
+			engineType = parser.MySQL
+		case db.Oracle:
+			engineType = parser.Oracle
+		case db.MSSQL:
+			engineType = parser.MSSQL
+		}
+		if engineType != "" {
+			standardSchema, err := transform.SchemaTransform(engineType, schema)
 			if err != nil {
 				return true, nil, errors.Wrapf(err, "failed to transform to standard schema for database %q", database.DatabaseName)
 			}
 			schema = standardSchema
 		}
 
-		latestSchemaFile := filepath.Join(repo.BaseDirectory, repo.SchemaPathTemplate)
-		latestSchemaFile = strings.ReplaceAll(latestSchemaFile, "{{ENV_ID}}", mi.Environment)
-		latestSchemaFile = strings.ReplaceAll(latestSchemaFile, "{{DB_NAME}}", mi.Database)
+		latestSchemaFile := getWriteBackFilePath(repo, repo.SchemaPathTemplate, mi)
 
 		vcs, err := stores.GetVCSByID(ctx, repo.VCSID)
 		if err != nil {
@@ -438,9 +630,36 @@ func postMigration(ctx context.Context, stores *store.Store, activityManager *ac
 			bytebaseURL = fmt.Sprintf("%s/issue/%s-%d?stage=%d", setting.ExternalUrl, slug.Make(issue.Title), issue.UID, task.StageID)
 		}
 
-		commitID, err := writeBackLatestSchema(ctx, stores, repo, vcsPushEvent, mi, writebackBranch, latestSchemaFile, schema, bytebaseURL)
+		// Commit to a new branch and open a pull request instead of committing to the write-back branch directly.
+		commitBranch := writebackBranch
+		if repo.SchemaWriteBackPR {
+			commitBranch, err = createSchemaWriteBackBranch(ctx, stores, repo, task, writebackBranch)
+			if err != nil {
+				return true, nil, err
+			}
+		}
+		commitID, err := writeBackLatestSchema(ctx, stores, repo, vcsPushEvent, mi, commitBranch, latestSchemaFile, schema, bytebaseURL)
 		if err != nil {
-			return true, nil, err
+			return true, nil, errors.Wrapf(err, "failed to write back the latest schema after applying migration %s to %q", mi.Version, mi.Database)
+		}
+		if repo.MetadataPathTemplate != "" {
+			latestMetadataFile := getWriteBackFilePath(repo, repo.MetadataPathTemplate, mi)
+			metadata, err := getLatestMetadata(ctx, dbFactory, instance, database.DatabaseName)
+			if err != nil {
+				return true, nil, err
+			}
+			commitID, err = writeBackLatestMetadata(ctx, stores, repo, mi, commitBranch, latestMetadataFile, metadata, bytebaseURL)
+			if err != nil {
+				return true, nil, errors.Wrapf(err, "failed to write back the latest metadata after applying migration %s to %q", mi.Version, mi.Database)
+			}
+		}
+		comment := fmt.Sprintf("Committed the latest schema after applying migration version %s to %q.", mi.Version, mi.Database)
+		if repo.SchemaWriteBackPR {
+			pullRequestURL, err := createSchemaWriteBackPullRequest(ctx, stores, repo, mi, commitBranch, writebackBranch, bytebaseURL)
+			if err != nil {
+				return true, nil, err
+			}
+			comment = fmt.Sprintf("Opened pull request %s with the latest schema after applying migration version %s to %q.", pullRequestURL, mi.Version, mi.Database)
 		}
 
 		// Create file commit activity
@@ -449,7 +668,7 @@ func postMigration(ctx context.Context, stores *store.Store, activityManager *ac
 				TaskID:             task.ID,
 				VCSInstanceURL:     repo.VCS.InstanceURL,
 				RepositoryFullPath: repo.FullPath,
-				Branch:             repo.BranchFilter,
+				Branch:             commitBranch,
 				FilePath:           latestSchemaFile,
 				CommitID:           commitID,
 			})
@@ -467,11 +686,8 @@ func postMigration(ctx context.Context, stores *store.Store, activityManager *ac
 				ContainerID: task.PipelineID,
 				Type:        api.ActivityPipelineTaskFileCommit,
 				Level:       api.ActivityInfo,
-				Comment: fmt.Sprintf("Committed the latest schema after applying migration version %s to %q.",
-					mi.Version,
-					mi.Database,
-				),
-				Payload: string(payload),
+				Comment:     comment,
+				Payload:     string(payload),
 			}
 
 			if _, err := activityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{}); err != nil {
@@ -485,6 +701,44 @@ func postMigration(ctx context.Context, stores *store.Store, activityManager *ac
 		}
 	}
 
+	if repo != nil && repo.EnableRollbackPR && issue != nil {
+		setting, err := stores.GetWorkspaceGeneralSetting(ctx)
+		if err != nil {
+			return true, nil, errors.Wrapf(err, "failed to get workspace setting")
+		}
+		bytebaseURL := fmt.Sprintf("%s/issue/%s-%d?stage=%d", setting.ExternalUrl, slug.Make(issue.Title), issue.UID, task.StageID)
+		// The rollback has been applied, so the task shouldn't fail if the pull request can't be created.
+		pullRequestURL, err := createRollbackPullRequest(ctx, stores, repo, task, mi, bytebaseURL)
+		if err != nil {
+			log.Error("Failed to create the rollback pull request",
+				zap.Int("task_id", task.ID),
+				zap.String("repository", repo.WebURL),
+				zap.Error(err),
+			)
+		} else if pullRequestURL != "" {
+			activityPayload, err := json.Marshal(api.ActivityIssueCommentCreatePayload{
+				IssueName: issue.Title,
+			})
+			if err != nil {
+				return true, nil, errors.Wrap(err, "failed to marshal ActivityIssueCommentCreatePayload")
+			}
+			if _, err := activityManager.CreateActivity(ctx, &api.ActivityCreate{
+				CreatorID:   api.SystemBotID,
+				ContainerID: issue.UID,
+				Type:        api.ActivityIssueCommentCreate,
+				Level:       api.ActivityInfo,
+				Comment:     fmt.Sprintf("Opened pull request %s to add the rollback migration to the repository.", pullRequestURL),
+				Payload:     string(activityPayload),
+			}, &activity.Metadata{}); err != nil {
+				log.Error("Failed to create activity after opening the rollback pull request",
+					zap.Int("task_id", task.ID),
+					zap.String("pull_request", pullRequestURL),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
 	// Remove schema drift anomalies.
 	if err := stores.ArchiveAnomalyV2(ctx, &store.ArchiveAnomalyMessage{
 		DatabaseUID: task.DatabaseID,
@@ -569,46 +823,132 @@ func runMigration(ctx context.Context, store *store.Store, dbFactory *dbfactory.
 		return true, nil, err
 	}
 
+	hooks, assertions, err := getMigrationHooksAndAssertions(task)
+	if err != nil {
+		return true, nil, err
+	}
+	hookResults, err := runMigrationHooks(ctx, store, dbFactory, task, mi, hooks, api.MigrationHookPre)
+	if err != nil {
+		return true, nil, err
+	}
+
 	migrationID, schema, err := executeMigration(ctx, store, dbFactory, stateCfg, task, statement, mi)
 	if err != nil {
 		return true, nil, err
 	}
-	return postMigration(ctx, store, activityManager, license, task, vcsPushEvent, mi, migrationID, schema)
+	terminated, result, err = postMigration(ctx, store, dbFactory, activityManager, license, task, vcsPushEvent, mi, migrationID, schema)
+	if err != nil {
+		return terminated, result, err
+	}
+
+	// The migration is applied already, so the failed post hook is only reported in the result.
+	postHookResults, err := runMigrationHooks(ctx, store, dbFactory, task, mi, hooks, api.MigrationHookPost)
+	if err != nil {
+		log.Warn("Failed to run the post migration hooks", zap.Int("task_id", task.ID), zap.Error(err))
+	}
+	hookResults = append(hookResults, postHookResults...)
+	if result != nil && len(hookResults) > 0 {
+		result.HookResultList = hookResults
+	}
+
+	// The failed assertions fail the task to hold the rollout of the next stage. The retry skips the applied
+	// migration and runs the assertions again.
+	assertionResults, err := runMigrationAssertions(ctx, store, dbFactory, task, mi, assertions)
+	if err != nil {
+		return true, nil, err
+	}
+	if result != nil && len(assertionResults) > 0 {
+		result.AssertionResultList = assertionResults
+	}
+	return terminated, result, nil
 }
 
-// Writes back the latest schema to the repository after migration.
-// Returns the commit id on success.
-func writeBackLatestSchema(ctx context.Context, store *store.Store, repository *api.Repository, pushEvent *vcsPlugin.PushEvent, mi *db.MigrationInfo, writebackBranch, latestSchemaFile string, schema string, bytebaseURL string) (string, error) {
-	schemaFileMeta, err := vcsPlugin.Get(repository.VCS.Type, vcsPlugin.ProviderConfig{}).ReadFileMeta(
-		ctx,
-		common.OauthContext{
-			ClientID:     repository.VCS.ApplicationID,
-			ClientSecret: repository.VCS.Secret,
-			AccessToken:  repository.AccessToken,
-			RefreshToken: repository.RefreshToken,
-			Refresher:    utils.RefreshToken(ctx, store, repository.WebURL),
-		},
-		repository.VCS.InstanceURL,
-		repository.ExternalID,
-		latestSchemaFile,
-		writebackBranch,
-	)
+func getMigrationHooksAndAssertions(task *store.TaskMessage) ([]*api.MigrationHook, []*api.MigrationAssertion, error) {
+	if task.Type != api.TaskDatabaseSchemaUpdate && task.Type != api.TaskDatabaseDataUpdate {
+		return nil, nil, nil
+	}
+	payload := &struct {
+		Hooks      []*api.MigrationHook      `json:"hooks,omitempty"`
+		Assertions []*api.MigrationAssertion `json:"assertions,omitempty"`
+	}{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return nil, nil, errors.Wrap(err, "invalid task payload")
+	}
+	return payload.Hooks, payload.Assertions, nil
+}
 
-	createSchemaFile := false
-	verb := "Update"
+// runMigrationAssertions runs the assertions in the database of the task after the migration.
+func runMigrationAssertions(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFactory, task *store.TaskMessage, mi *db.MigrationInfo, assertions []*api.MigrationAssertion) ([]*api.MigrationAssertionResult, error) {
+	if len(assertions) == 0 {
+		return nil, nil
+	}
+	instance, err := stores.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
 	if err != nil {
-		if common.ErrorCode(err) == common.NotFound {
-			createSchemaFile = true
-			verb = "Create"
-		} else {
-			return "", errors.Wrap(err, "failed to fetch latest schema")
+		return nil, err
+	}
+	driver, err := dbFactory.GetAdminDatabaseDriver(ctx, instance, mi.Database)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect database %q for the migration assertions", mi.Database)
+	}
+	defer driver.Close(ctx)
+	sqlDB := driver.GetDB()
+	if sqlDB == nil {
+		return nil, errors.Errorf("migration assertions are not supported for %s", instance.Engine)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return utils.RunMigrationAssertions(ctx, assertions, func(ctx context.Context, statement string) ([][]string, error) {
+		return utils.QueryMigrationAssertionRows(ctx, conn, statement)
+	})
+}
+
+// runMigrationHooks runs the hooks of the stage, the SQL hooks are executed in the database of the task.
+func runMigrationHooks(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFactory, task *store.TaskMessage, mi *db.MigrationInfo, hooks []*api.MigrationHook, stage api.MigrationHookStage) ([]*api.MigrationHookResult, error) {
+	hasStage := false
+	for _, hook := range hooks {
+		if hook.Stage == stage {
+			hasStage = true
 		}
 	}
+	if !hasStage {
+		return nil, nil
+	}
+	instance, err := stores.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+	if err != nil {
+		return nil, err
+	}
+	driver, err := dbFactory.GetAdminDatabaseDriver(ctx, instance, mi.Database)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect database %q for the migration hooks", mi.Database)
+	}
+	defer driver.Close(ctx)
 
-	var commitMessage string
-	if mi.Type == db.Baseline {
-		commitMessage = fmt.Sprintf("[Bytebase] establish baseline for %q", mi.Database)
-	} else {
+	request := &utils.MigrationHookWebhookRequest{
+		Stage:       stage,
+		IssueID:     mi.IssueID,
+		TaskID:      task.ID,
+		TaskName:    task.Name,
+		Environment: mi.Environment,
+		Database:    mi.Database,
+		Version:     mi.Version,
+	}
+	return utils.RunMigrationHooks(ctx, hooks, stage, request, func(ctx context.Context, statement string) error {
+		_, err := driver.Execute(ctx, statement, false /* createDatabase */)
+		return err
+	})
+}
+
+// Writes back the latest schema to the repository after migration.
+// Returns the commit id on success.
+func writeBackLatestSchema(ctx context.Context, store *store.Store, repository *api.Repository, pushEvent *vcsPlugin.PushEvent, mi *db.MigrationInfo, writebackBranch, latestSchemaFile string, schema string, bytebaseURL string) (string, error) {
+	return writeBackFile(ctx, store, repository, writebackBranch, latestSchemaFile, schema, func(verb string) string {
+		if mi.Type == db.Baseline {
+			return fmt.Sprintf("[Bytebase] establish baseline for %q", mi.Database)
+		}
 		commitTitle := fmt.Sprintf("[Bytebase] %s latest schema for %q after migration %s", verb, mi.Database, mi.Version)
 		commitBody := "THIS COMMIT IS AUTO-GENERATED BY BYTEBASE"
 		if bytebaseURL != "" {
@@ -630,8 +970,52 @@ func writeBackLatestSchema(ctx context.Context, store *store.Store, repository *
 				)
 			}
 		}
-		commitMessage = fmt.Sprintf("%s\n\n%s", commitTitle, commitBody)
+		return fmt.Sprintf("%s\n\n%s", commitTitle, commitBody)
+	})
+}
+
+// writeBackLatestMetadata writes back the database metadata in JSON, e.g. for drawing the ER diagrams from the
+// repository.
+func writeBackLatestMetadata(ctx context.Context, store *store.Store, repository *api.Repository, mi *db.MigrationInfo, writebackBranch, latestMetadataFile string, metadata string, bytebaseURL string) (string, error) {
+	return writeBackFile(ctx, store, repository, writebackBranch, latestMetadataFile, metadata, func(verb string) string {
+		commitTitle := fmt.Sprintf("[Bytebase] %s latest metadata for %q after migration %s", verb, mi.Database, mi.Version)
+		commitBody := "THIS COMMIT IS AUTO-GENERATED BY BYTEBASE"
+		if bytebaseURL != "" {
+			commitBody += "\n\n" + bytebaseURL
+		}
+		return fmt.Sprintf("%s\n\n%s", commitTitle, commitBody)
+	})
+}
+
+// writeBackFile creates or overwrites the file on the branch, and returns the commit ID. The verb of the commit
+// message is "Create" or "Update".
+func writeBackFile(ctx context.Context, store *store.Store, repository *api.Repository, writebackBranch, filePath string, content string, getCommitMessage func(verb string) string) (string, error) {
+	fileMeta, err := vcsPlugin.Get(repository.VCS.Type, vcsPlugin.ProviderConfig{}).ReadFileMeta(
+		ctx,
+		common.OauthContext{
+			ClientID:     repository.VCS.ApplicationID,
+			ClientSecret: repository.VCS.Secret,
+			AccessToken:  repository.AccessToken,
+			RefreshToken: repository.RefreshToken,
+			Refresher:    utils.RefreshToken(ctx, store, repository.WebURL),
+		},
+		repository.VCS.InstanceURL,
+		repository.ExternalID,
+		filePath,
+		writebackBranch,
+	)
+
+	createFile := false
+	verb := "Update"
+	if err != nil {
+		if common.ErrorCode(err) == common.NotFound {
+			createFile = true
+			verb = "Create"
+		} else {
+			return "", errors.Wrapf(err, "failed to fetch file %s", filePath)
+		}
 	}
+	commitMessage := getCommitMessage(verb)
 
 	// Retrieve the latest AccessToken and RefreshToken as the previous VCS call may have
 	// updated the stored token pair. VCS will fetch and store the new token pair if the
@@ -644,16 +1028,16 @@ func writeBackLatestSchema(ctx context.Context, store *store.Store, repository *
 		return "", errors.Errorf("repository not found for schema write-back: %v", repository.ID)
 	}
 
-	schemaFileCommit := vcsPlugin.FileCommitCreate{
+	fileCommit := vcsPlugin.FileCommitCreate{
 		Branch:        writebackBranch,
 		CommitMessage: commitMessage,
-		Content:       schema,
+		Content:       content,
 		AuthorName:    vcsPlugin.BytebaseAuthorName,
 		AuthorEmail:   vcsPlugin.BytebaseAuthorEmail,
 	}
-	if createSchemaFile {
-		log.Debug("Create latest schema file",
-			zap.String("schema_file", latestSchemaFile),
+	if createFile {
+		log.Debug("Create write-back file",
+			zap.String("file", filePath),
 		)
 
 		err := vcsPlugin.Get(repo2.VCS.Type, vcsPlugin.ProviderConfig{}).CreateFile(
@@ -667,19 +1051,19 @@ func writeBackLatestSchema(ctx context.Context, store *store.Store, repository *
 			},
 			repo2.VCS.InstanceURL,
 			repo2.ExternalID,
-			latestSchemaFile,
-			schemaFileCommit,
+			filePath,
+			fileCommit,
 		)
 		if err != nil {
-			return "", errors.Wrapf(err, "failed to create file after applying migration %s to %q", mi.Version, mi.Database)
+			return "", errors.Wrapf(err, "failed to create file %s", filePath)
 		}
 	} else {
-		log.Debug("Update latest schema file",
-			zap.String("schema_file", latestSchemaFile),
+		log.Debug("Update write-back file",
+			zap.String("file", filePath),
 		)
 
-		schemaFileCommit.LastCommitID = schemaFileMeta.LastCommitID
-		schemaFileCommit.SHA = schemaFileMeta.SHA
+		fileCommit.LastCommitID = fileMeta.LastCommitID
+		fileCommit.SHA = fileMeta.SHA
 		err := vcsPlugin.Get(repo2.VCS.Type, vcsPlugin.ProviderConfig{}).OverwriteFile(
 			ctx,
 			common.OauthContext{
@@ -691,11 +1075,11 @@ func writeBackLatestSchema(ctx context.Context, store *store.Store, repository *
 			},
 			repo2.VCS.InstanceURL,
 			repo2.ExternalID,
-			latestSchemaFile,
-			schemaFileCommit,
+			filePath,
+			fileCommit,
 		)
 		if err != nil {
-			return "", errors.Wrapf(err, "failed to create file after applying migration %s to %q", mi.Version, mi.Database)
+			return "", errors.Wrapf(err, "failed to update file %s", filePath)
 		}
 	}
 
@@ -710,7 +1094,7 @@ func writeBackLatestSchema(ctx context.Context, store *store.Store, repository *
 		return "", errors.Errorf("repository not found after schema write-back: %v", repository.ID)
 	}
 	// VCS such as GitLab API doesn't return the commit on write, so we have to call ReadFileMeta again
-	schemaFileMeta, err = vcsPlugin.Get(repo2.VCS.Type, vcsPlugin.ProviderConfig{}).ReadFileMeta(
+	fileMeta, err = vcsPlugin.Get(repo2.VCS.Type, vcsPlugin.ProviderConfig{}).ReadFileMeta(
 		ctx,
 		common.OauthContext{
 			ClientID:     repo2.VCS.ApplicationID,
@@ -721,12 +1105,12 @@ func writeBackLatestSchema(ctx context.Context, store *store.Store, repository *
 		},
 		repo2.VCS.InstanceURL,
 		repo2.ExternalID,
-		latestSchemaFile,
+		filePath,
 		writebackBranch,
 	)
 
 	if err != nil {
-		return "", errors.Wrapf(err, "failed to fetch latest schema file %s after update", latestSchemaFile)
+		return "", errors.Wrapf(err, "failed to fetch file %s after update", filePath)
 	}
-	return schemaFileMeta.LastCommitID, nil
+	return fileMeta.LastCommitID, nil
 }
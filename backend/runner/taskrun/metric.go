@@ -0,0 +1,28 @@
+package taskrun
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// taskRunCanceled is the result of the task runs canceled while running.
+const taskRunCanceled = "CANCELED"
+
+var taskRunTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "bytebase",
+		Subsystem: "task",
+		Name:      "run_total",
+		Help:      "The number of the finished task runs, by the task type and the result, i.e. DONE, FAILED or CANCELED.",
+	},
+	[]string{"type", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(taskRunTotal)
+}
+
+func observeTaskRun(taskType api.TaskType, result string) {
+	taskRunTotal.WithLabelValues(string(taskType), result).Inc()
+}
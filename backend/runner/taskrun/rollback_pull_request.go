@@ -0,0 +1,130 @@
+package taskrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	vcsPlugin "github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+// createRollbackPullRequest opens a pull request adding the migration file of the finished rollback task, if the rolled
+// back change is from the repository, so that the repository keeps consistent with the migration history. Merging the
+// pull request doesn't apply the rollback again as its version has been applied.
+// Returns the pull request URL, or empty if no pull request is needed.
+func createRollbackPullRequest(ctx context.Context, stores *store.Store, repo *api.Repository, task *store.TaskMessage, mi *db.MigrationInfo, bytebaseURL string) (string, error) {
+	if !repo.EnableRollbackPR || task.Type != api.TaskDatabaseDataUpdate {
+		return "", nil
+	}
+	payload := &api.TaskDatabaseDataUpdatePayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return "", errors.Wrap(err, "invalid database data update payload")
+	}
+	if payload.RollbackFromTaskID == 0 {
+		return "", nil
+	}
+	rollbackFromTask, err := stores.GetTaskV2ByID(ctx, payload.RollbackFromTaskID)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the rolled back task %d", payload.RollbackFromTaskID)
+	}
+	if rollbackFromTask == nil {
+		return "", errors.Errorf("rolled back task %d not found", payload.RollbackFromTaskID)
+	}
+	rollbackFromPayload := &api.TaskDatabaseDataUpdatePayload{}
+	if err := json.Unmarshal([]byte(rollbackFromTask.Payload), rollbackFromPayload); err != nil {
+		return "", errors.Wrap(err, "invalid database data update payload of the rolled back task")
+	}
+	// The rolled back change isn't from the repository.
+	if rollbackFromPayload.VCSPushEvent == nil {
+		return "", nil
+	}
+
+	filePath, err := getRollbackMigrationFilePath(repo, mi, rollbackFromPayload.SchemaVersion)
+	if err != nil {
+		return "", err
+	}
+	baseBranch, err := vcsPlugin.Branch(rollbackFromPayload.VCSPushEvent.Ref)
+	if err != nil {
+		return "", err
+	}
+	statement, err := stores.GetSheetStatementByID(ctx, payload.SheetID)
+	if err != nil {
+		return "", err
+	}
+
+	oauthContext := common.OauthContext{
+		ClientID:     repo.VCS.ApplicationID,
+		ClientSecret: repo.VCS.Secret,
+		AccessToken:  repo.AccessToken,
+		RefreshToken: repo.RefreshToken,
+		Refresher:    utils.RefreshToken(ctx, stores, repo.WebURL),
+	}
+	provider := vcsPlugin.Get(repo.VCS.Type, vcsPlugin.ProviderConfig{})
+	branch, err := provider.GetBranch(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, baseBranch)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get branch %q", baseBranch)
+	}
+	rollbackBranch := &vcsPlugin.BranchInfo{
+		Name:         fmt.Sprintf("bytebase-rollback-%d-%d", task.ID, time.Now().Unix()),
+		LastCommitID: branch.LastCommitID,
+	}
+	if err := provider.CreateBranch(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, rollbackBranch); err != nil {
+		return "", errors.Wrapf(err, "failed to create branch %q", rollbackBranch.Name)
+	}
+
+	title := fmt.Sprintf("[Bytebase] Rollback migration %s for %q", rollbackFromPayload.SchemaVersion, mi.Database)
+	body := "THIS PULL REQUEST IS AUTO-GENERATED BY BYTEBASE\n\nThe rollback migration has been applied, merging this pull request doesn't apply it again."
+	if bytebaseURL != "" {
+		body += "\n\n" + bytebaseURL
+	}
+	if err := provider.CreateFile(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, filePath, vcsPlugin.FileCommitCreate{
+		Branch:        rollbackBranch.Name,
+		Content:       statement,
+		CommitMessage: fmt.Sprintf("%s\n\n%s", title, body),
+		AuthorName:    vcsPlugin.BytebaseAuthorName,
+		AuthorEmail:   vcsPlugin.BytebaseAuthorEmail,
+	}); err != nil {
+		return "", errors.Wrapf(err, "failed to create rollback migration file %q", filePath)
+	}
+
+	pullRequest, err := provider.CreatePullRequest(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, &vcsPlugin.PullRequestCreate{
+		Title:                 title,
+		Body:                  body,
+		Head:                  rollbackBranch.Name,
+		Base:                  baseBranch,
+		RemoveHeadAfterMerged: true,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create pull request for branch %q", rollbackBranch.Name)
+	}
+	return pullRequest.URL, nil
+}
+
+// getRollbackMigrationFilePath returns the path of the rollback migration file by the file path template of the
+// repository. The version is the one applied by the rollback task.
+func getRollbackMigrationFilePath(repo *api.Repository, mi *db.MigrationInfo, rollbackFromVersion string) (string, error) {
+	if strings.Contains(repo.FilePathTemplate, "*") {
+		return "", errors.Errorf("cannot generate the rollback migration file for the file path template %q with wildcards", repo.FilePathTemplate)
+	}
+	filePath := path.Join(repo.BaseDirectory, repo.FilePathTemplate)
+	for placeholder, value := range map[string]string{
+		"{{ENV_ID}}":      mi.Environment,
+		"{{DB_NAME}}":     mi.Database,
+		"{{VERSION}}":     mi.Version,
+		"{{TYPE}}":        "data",
+		"{{DESCRIPTION}}": fmt.Sprintf("rollback_%s", rollbackFromVersion),
+	} {
+		filePath = strings.ReplaceAll(filePath, placeholder, value)
+	}
+	return filePath, nil
+}
@@ -0,0 +1,31 @@
+package taskrun
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestGetRollbackMigrationFilePath(t *testing.T) {
+	mi := &db.MigrationInfo{
+		Environment: "prod",
+		Database:    "employee",
+		Version:     "20231014150405",
+	}
+
+	filePath, err := getRollbackMigrationFilePath(&api.Repository{
+		BaseDirectory:    "bytebase",
+		FilePathTemplate: "{{ENV_ID}}/{{DB_NAME}}##{{VERSION}}##{{TYPE}}##{{DESCRIPTION}}.sql",
+	}, mi, "0002")
+	require.NoError(t, err)
+	require.Equal(t, "bytebase/prod/employee##20231014150405##data##rollback_0002.sql", filePath)
+
+	_, err = getRollbackMigrationFilePath(&api.Repository{
+		BaseDirectory:    "bytebase",
+		FilePathTemplate: "**/{{DB_NAME}}##{{VERSION}}##{{TYPE}}##{{DESCRIPTION}}.sql",
+	}, mi, "0002")
+	require.Error(t, err)
+}
@@ -226,6 +226,7 @@ func (s *Scheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 						select {
 						case <-executorCtx.Done():
 							// task cancelled
+							observeTaskRun(task.Type, taskRunCanceled)
 							log.Debug("Task canceled",
 								zap.Int("id", task.ID),
 								zap.String("name", task.Name),
@@ -245,6 +246,7 @@ func (s *Scheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							return
 						}
 						if done && err != nil {
+							observeTaskRun(task.Type, string(api.TaskFailed))
 							log.Warn("Failed to run task",
 								zap.Int("id", task.ID),
 								zap.String("name", task.Name),
@@ -281,6 +283,7 @@ func (s *Scheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							return
 						}
 						if done && err == nil {
+							observeTaskRun(task.Type, string(api.TaskDone))
 							bytes, err := json.Marshal(*result)
 							if err != nil {
 								log.Error("Failed to marshal task run result",
@@ -685,23 +688,28 @@ func canUpdateTaskStatement(task *store.TaskMessage) *echo.HTTPError {
 // scheduleIfNeeded schedules the task if
 //  2. it has no blocking tasks.
 //  3. it has passed the earliest allowed time.
-func (s *Scheduler) scheduleIfNeeded(ctx context.Context, task *store.TaskMessage) error {
+//
+// It returns true if the task is scheduled.
+func (s *Scheduler) scheduleIfNeeded(ctx context.Context, task *store.TaskMessage) (bool, error) {
 	blocked, err := s.isTaskBlocked(ctx, task)
 	if err != nil {
-		return errors.Wrap(err, "failed to check if task is blocked")
+		return false, errors.Wrap(err, "failed to check if task is blocked")
 	}
 	if blocked {
-		return nil
+		return false, nil
 	}
 	if task.EarliestAllowedTs != 0 && time.Now().Before(time.Unix(task.EarliestAllowedTs, 0)) {
-		return nil
+		return false, nil
 	}
 
-	return s.PatchTaskStatus(ctx, task, &api.TaskStatusPatch{
+	if err := s.PatchTaskStatus(ctx, task, &api.TaskStatusPatch{
 		ID:        task.ID,
 		UpdaterID: api.SystemBotID,
 		Status:    api.TaskRunning,
-	})
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 func (s *Scheduler) isTaskBlocked(ctx context.Context, task *store.TaskMessage) (bool, error) {
@@ -745,6 +753,13 @@ func (s *Scheduler) scheduleAutoApprovedTasks(ctx context.Context) error {
 		if policy.Value != api.PipelineApprovalValueManualNever {
 			continue
 		}
+		manualRollout, err := utils.IsTaskManualRollout(task.Payload)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check the manual rollout of task %d", task.ID)
+		}
+		if manualRollout {
+			continue
+		}
 
 		issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{PipelineID: &task.PipelineID})
 		if err != nil {
@@ -789,10 +804,32 @@ func (s *Scheduler) schedulePendingTasks(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	// rolloutCapacities is the number of the tasks which can still be scheduled in the pipelines with the rollout strategy.
+	rolloutCapacities := make(map[int]int)
 	for _, task := range tasks {
-		if err := s.scheduleIfNeeded(ctx, task); err != nil {
+		strategy, err := utils.GetTaskRolloutStrategy(task.Payload)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the rollout strategy of task %d", task.ID)
+		}
+		if strategy != nil {
+			if _, ok := rolloutCapacities[task.PipelineID]; !ok {
+				pipelineTasks, err := s.store.ListTasks(ctx, &api.TaskFind{PipelineID: &task.PipelineID})
+				if err != nil {
+					return err
+				}
+				rolloutCapacities[task.PipelineID] = utils.GetRolloutCapacity(strategy, pipelineTasks)
+			}
+			if rolloutCapacities[task.PipelineID] <= 0 {
+				continue
+			}
+		}
+		scheduled, err := s.scheduleIfNeeded(ctx, task)
+		if err != nil {
 			return errors.Wrap(err, "failed to schedule task")
 		}
+		if scheduled && strategy != nil {
+			rolloutCapacities[task.PipelineID]--
+		}
 	}
 	return nil
 }
@@ -89,16 +89,23 @@ func (e *SchemaUpdateGhostCutoverExecutor) RunOnce(ctx context.Context, task *st
 		return true, nil, common.Wrapf(err, common.Internal, "failed to parse table name from statement, statement: %v", statement)
 	}
 
-	postponeFilename := utils.GetPostponeFlagFilename(syncTaskID, database.UID, database.DatabaseName, tableName)
+	var terminated bool
+	var result *api.TaskRunResultPayload
+	if payload.OnlineMigrationConfig.GetEngine() == api.OnlineMigrationEnginePtOSC {
+		// not using the rendered statement here because we want to avoid leaking the rendered statement
+		terminated, result, err = ptOSCCutover(ctx, e.store, e.dbFactory, e.activityManager, e.license, e.profile, task, statement, payload.SchemaVersion, payload.VCSPushEvent, tableName)
+	} else {
+		postponeFilename := utils.GetPostponeFlagFilename(syncTaskID, database.UID, database.DatabaseName, tableName)
+
+		value, ok := e.stateCfg.GhostTaskState.Load(syncTaskID)
+		if !ok {
+			return true, nil, errors.Errorf("failed to get gh-ost state from sync task")
+		}
+		sharedGhost := value.(sharedGhostState)
 
-	value, ok := e.stateCfg.GhostTaskState.Load(syncTaskID)
-	if !ok {
-		return true, nil, errors.Errorf("failed to get gh-ost state from sync task")
+		// not using the rendered statement here because we want to avoid leaking the rendered statement
+		terminated, result, err = cutover(ctx, e.store, e.dbFactory, e.activityManager, e.license, e.profile, task, statement, payload.SchemaVersion, payload.VCSPushEvent, postponeFilename, sharedGhost.migrationContext, sharedGhost.errCh)
 	}
-	sharedGhost := value.(sharedGhostState)
-
-	// not using the rendered statement here because we want to avoid leaking the rendered statement
-	terminated, result, err := cutover(ctx, e.store, e.dbFactory, e.activityManager, e.license, e.profile, task, statement, payload.SchemaVersion, payload.VCSPushEvent, postponeFilename, sharedGhost.migrationContext, sharedGhost.errCh)
 	if err := e.schemaSyncer.SyncDatabaseSchema(ctx, database, true /* force */); err != nil {
 		log.Error("failed to sync database schema",
 			zap.String("instanceName", instance.ResourceID),
@@ -151,7 +158,7 @@ func cutover(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFa
 		return true, nil, err
 	}
 
-	return postMigration(ctx, stores, activityManager, license, task, vcsPushEvent, mi, migrationID, schema)
+	return postMigration(ctx, stores, dbFactory, activityManager, license, task, vcsPushEvent, mi, migrationID, schema)
 }
 
 func waitForCutover(ctx context.Context, migrationContext *base.MigrationContext) bool {
@@ -47,6 +47,9 @@ func (exec *SchemaUpdateGhostSyncExecutor) RunOnce(ctx context.Context, task *st
 		return true, nil, err
 	}
 
+	if payload.OnlineMigrationConfig.GetEngine() == api.OnlineMigrationEnginePtOSC {
+		return exec.runPtOSCSync(ctx, task, statement, payload.OnlineMigrationConfig)
+	}
 	return exec.runGhostMigration(ctx, exec.store, task, statement)
 }
 
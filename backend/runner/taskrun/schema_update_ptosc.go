@@ -0,0 +1,112 @@
+package taskrun
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/component/activity"
+	"github.com/bytebase/bytebase/backend/component/config"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	enterpriseAPI "github.com/bytebase/bytebase/backend/enterprise/api"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	vcsPlugin "github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+// runPtOSCSync copies the rows to the new table with pt-osc.
+// The triggers keep the new table in sync after the copy, the tables are swapped by the cutover task.
+func (exec *SchemaUpdateGhostSyncExecutor) runPtOSCSync(ctx context.Context, task *store.TaskMessage, statement string, onlineMigrationConfig *api.OnlineMigrationConfig) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	statement = strings.TrimSpace(statement)
+
+	instance, err := exec.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+	if err != nil {
+		return true, nil, err
+	}
+	if instance == nil {
+		return true, nil, errors.Errorf("instance %d not found", task.InstanceID)
+	}
+	adminDataSource := utils.DataSourceFromInstanceWithType(instance, api.Admin)
+	if adminDataSource == nil {
+		return true, nil, common.Errorf(common.Internal, "admin data source not found for instance %d", instance.UID)
+	}
+
+	database, err := exec.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: task.DatabaseID})
+	if err != nil {
+		return true, nil, err
+	}
+	if database == nil {
+		return true, nil, errors.Errorf("database not found")
+	}
+
+	materials := utils.GetSecretMapFromDatabaseMessage(database)
+	// To avoid leaking the rendered statement, the error message should use the original statement and not the rendered statement.
+	renderedStatement := utils.RenderStatement(statement, materials)
+
+	tableName, err := utils.GetTableNameFromStatement(renderedStatement)
+	if err != nil {
+		return true, nil, common.Wrapf(err, common.Internal, "failed to parse table name from statement, statement: %v", statement)
+	}
+
+	ptOSCConfig, err := utils.GetPtOSCConfig(database, adminDataSource, exec.secret, tableName, renderedStatement, onlineMigrationConfig)
+	if err != nil {
+		return true, nil, err
+	}
+
+	createdTs := time.Now().Unix()
+	if err := utils.RunPtOSC(ctx, ptOSCConfig, false /* dryRun */, func(percentage int64) {
+		exec.stateCfg.TaskProgress.Store(task.ID, api.Progress{
+			TotalUnit:     100,
+			CompletedUnit: percentage,
+			CreatedTs:     createdTs,
+			UpdatedTs:     time.Now().Unix(),
+		})
+	}); err != nil {
+		if ctx.Err() != nil {
+			return true, nil, errors.New("task canceled")
+		}
+		return true, nil, err
+	}
+	return true, &api.TaskRunResultPayload{Detail: "sync done"}, nil
+}
+
+// ptOSCCutover swaps the original table with the new table synced by pt-osc and records the migration history.
+func ptOSCCutover(ctx context.Context, stores *store.Store, dbFactory *dbfactory.DBFactory, activityManager *activity.Manager, license enterpriseAPI.LicenseService, profile config.Profile, task *store.TaskMessage, statement, schemaVersion string, vcsPushEvent *vcsPlugin.PushEvent, tableName string) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	statement = strings.TrimSpace(statement)
+	instance, err := stores.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &task.InstanceID})
+	if err != nil {
+		return true, nil, err
+	}
+	database, err := stores.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: task.DatabaseID})
+	if err != nil {
+		return true, nil, err
+	}
+
+	mi, err := preMigration(ctx, stores, profile, task, db.Migrate, statement, schemaVersion, vcsPushEvent)
+	if err != nil {
+		return true, nil, err
+	}
+
+	driver, err := dbFactory.GetAdminDatabaseDriver(ctx, instance, database.DatabaseName)
+	if err != nil {
+		return true, nil, err
+	}
+	defer driver.Close(ctx)
+	execFunc := func(_ string) error {
+		if _, err := driver.Execute(ctx, utils.GetPtOSCCutoverStatement(database.DatabaseName, tableName), false /* createDatabase */); err != nil {
+			return errors.Wrap(err, "failed to swap the tables synced by pt-osc")
+		}
+		return nil
+	}
+	migrationID, schema, err := utils.ExecuteMigrationWithFunc(ctx, stores, driver, mi, statement, execFunc)
+	if err != nil {
+		return true, nil, err
+	}
+
+	return postMigration(ctx, stores, dbFactory, activityManager, license, task, vcsPushEvent, mi, migrationID, schema)
+}
@@ -0,0 +1,114 @@
+package taskrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	vcsPlugin "github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+// getWriteBackFilePath returns the path of the write-back file by the path template of the repository.
+func getWriteBackFilePath(repo *api.Repository, pathTemplate string, mi *db.MigrationInfo) string {
+	filePath := filepath.Join(repo.BaseDirectory, pathTemplate)
+	filePath = strings.ReplaceAll(filePath, "{{ENV_ID}}", mi.Environment)
+	return strings.ReplaceAll(filePath, "{{DB_NAME}}", mi.Database)
+}
+
+// getLatestMetadata syncs the database metadata after the migration and returns it in JSON. The metadata in the store
+// is synced after the task finishes, so it's not the latest yet.
+func getLatestMetadata(ctx context.Context, dbFactory *dbfactory.DBFactory, instance *store.InstanceMessage, databaseName string) (string, error) {
+	driver, err := dbFactory.GetAdminDatabaseDriver(ctx, instance, databaseName)
+	if err != nil {
+		return "", err
+	}
+	defer driver.Close(ctx)
+	metadata, err := driver.SyncDBSchema(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to sync metadata of database %q", databaseName)
+	}
+	content, err := protojson.Marshal(metadata)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal metadata of database %q", databaseName)
+	}
+	// The protojson output is unstable on purpose, indent it so that the file doesn't change if the metadata doesn't.
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, content, "", "  "); err != nil {
+		return "", errors.Wrapf(err, "failed to indent metadata of database %q", databaseName)
+	}
+	buf.WriteString("\n")
+	return buf.String(), nil
+}
+
+// createSchemaWriteBackBranch creates the branch from the write-back branch to commit the latest schema for the pull
+// request.
+func createSchemaWriteBackBranch(ctx context.Context, stores *store.Store, repo *api.Repository, task *store.TaskMessage, writebackBranch string) (string, error) {
+	oauthContext := common.OauthContext{
+		ClientID:     repo.VCS.ApplicationID,
+		ClientSecret: repo.VCS.Secret,
+		AccessToken:  repo.AccessToken,
+		RefreshToken: repo.RefreshToken,
+		Refresher:    utils.RefreshToken(ctx, stores, repo.WebURL),
+	}
+	provider := vcsPlugin.Get(repo.VCS.Type, vcsPlugin.ProviderConfig{})
+	branch, err := provider.GetBranch(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, writebackBranch)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get branch %q", writebackBranch)
+	}
+	schemaBranch := &vcsPlugin.BranchInfo{
+		Name:         fmt.Sprintf("bytebase-schema-%d-%d", task.ID, time.Now().Unix()),
+		LastCommitID: branch.LastCommitID,
+	}
+	if err := provider.CreateBranch(ctx, oauthContext, repo.VCS.InstanceURL, repo.ExternalID, schemaBranch); err != nil {
+		return "", errors.Wrapf(err, "failed to create branch %q", schemaBranch.Name)
+	}
+	return schemaBranch.Name, nil
+}
+
+// createSchemaWriteBackPullRequest opens the pull request merging the latest schema on the head branch into the
+// write-back branch, and returns the pull request URL.
+func createSchemaWriteBackPullRequest(ctx context.Context, stores *store.Store, repo *api.Repository, mi *db.MigrationInfo, headBranch, writebackBranch, bytebaseURL string) (string, error) {
+	// Retrieve the latest AccessToken and RefreshToken as the write-back may have updated the stored token pair.
+	latestRepo, err := stores.GetRepository(ctx, &api.RepositoryFind{ID: &repo.ID})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch repository for schema write-back")
+	}
+	if latestRepo == nil {
+		return "", errors.Errorf("repository not found for schema write-back: %v", repo.ID)
+	}
+	oauthContext := common.OauthContext{
+		ClientID:     latestRepo.VCS.ApplicationID,
+		ClientSecret: latestRepo.VCS.Secret,
+		AccessToken:  latestRepo.AccessToken,
+		RefreshToken: latestRepo.RefreshToken,
+		Refresher:    utils.RefreshToken(ctx, stores, latestRepo.WebURL),
+	}
+	body := "THIS PULL REQUEST IS AUTO-GENERATED BY BYTEBASE\n\nThe migration has been applied, this pull request updates the repository to reflect the live schema."
+	if bytebaseURL != "" {
+		body += "\n\n" + bytebaseURL
+	}
+	pullRequest, err := vcsPlugin.Get(latestRepo.VCS.Type, vcsPlugin.ProviderConfig{}).CreatePullRequest(ctx, oauthContext, latestRepo.VCS.InstanceURL, latestRepo.ExternalID, &vcsPlugin.PullRequestCreate{
+		Title:                 fmt.Sprintf("[Bytebase] Latest schema for %q after migration %s", mi.Database, mi.Version),
+		Body:                  body,
+		Head:                  headBranch,
+		Base:                  writebackBranch,
+		RemoveHeadAfterMerged: true,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create pull request for branch %q", headBranch)
+	}
+	return pullRequest.URL, nil
+}
@@ -0,0 +1,24 @@
+package taskrun
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestGetWriteBackFilePath(t *testing.T) {
+	repo := &api.Repository{
+		BaseDirectory:        "bytebase",
+		SchemaPathTemplate:   "{{ENV_ID}}/.{{DB_NAME}}##LATEST.sql",
+		MetadataPathTemplate: "{{ENV_ID}}/.{{DB_NAME}}##METADATA.json",
+	}
+	mi := &db.MigrationInfo{
+		Environment: "prod",
+		Database:    "employee",
+	}
+	require.Equal(t, "bytebase/prod/.employee##LATEST.sql", getWriteBackFilePath(repo, repo.SchemaPathTemplate, mi))
+	require.Equal(t, "bytebase/prod/.employee##METADATA.json", getWriteBackFilePath(repo, repo.MetadataPathTemplate, mi))
+}
@@ -0,0 +1,247 @@
+// Package unusedindexsync is a runner that collects the unused indexes from the engine statistics.
+package unusedindexsync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/pg"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	unusedIndexSyncInterval = 6 * time.Hour
+
+	// The sys.schema_unused_indexes view relies on the performance schema and excludes the primary keys.
+	// The statistics are reset when the MySQL server restarts.
+	mysqlUnusedIndexQuery = `
+		SELECT object_schema, object_name, index_name
+		FROM sys.schema_unused_indexes`
+	// The primary keys are excluded to be consistent with MySQL.
+	// The statistics are reset by pg_stat_reset().
+	pgUnusedIndexQuery = `
+		SELECT s.schemaname, s.relname, s.indexrelname
+		FROM pg_catalog.pg_stat_user_indexes s
+		JOIN pg_catalog.pg_index i ON s.indexrelid = i.indexrelid
+		WHERE s.idx_scan = 0 AND NOT i.indisprimary`
+)
+
+// NewSyncer creates a new unused index syncer.
+func NewSyncer(store *store.Store, dbFactory *dbfactory.DBFactory) *Syncer {
+	return &Syncer{
+		store:     store,
+		dbFactory: dbFactory,
+	}
+}
+
+// Syncer is the unused index syncer.
+type Syncer struct {
+	store     *store.Store
+	dbFactory *dbfactory.DBFactory
+}
+
+// Run will run the unused index syncer.
+func (s *Syncer) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(unusedIndexSyncInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Unused index syncer started and will run every %s", unusedIndexSyncInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Unused index syncer received context cancellation")
+			return
+		case <-ticker.C:
+			log.Debug("Unused index syncer received tick")
+			s.syncUnusedIndex(ctx)
+		}
+	}
+}
+
+func (s *Syncer) syncUnusedIndex(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = errors.Errorf("%v", r)
+			}
+			log.Error("unused index syncer PANIC RECOVER", zap.Error(err), zap.Stack("panic-stack"))
+		}
+	}()
+
+	instances, err := s.store.ListInstancesV2(ctx, &store.FindInstanceMessage{})
+	if err != nil {
+		log.Error("Failed to list instances", zap.Error(err))
+		return
+	}
+
+	var instanceWG sync.WaitGroup
+	for _, instance := range instances {
+		if instance.Deleted {
+			continue
+		}
+		if instance.Engine != db.MySQL && instance.Engine != db.Postgres {
+			continue
+		}
+		instanceWG.Add(1)
+		go func(instance *store.InstanceMessage) {
+			defer instanceWG.Done()
+			if err := s.syncInstanceUnusedIndex(ctx, instance); err != nil {
+				log.Debug("Failed to sync instance unused index",
+					zap.String("instance", instance.ResourceID),
+					zap.Error(err))
+			}
+		}(instance)
+	}
+	instanceWG.Wait()
+}
+
+func (s *Syncer) syncInstanceUnusedIndex(ctx context.Context, instance *store.InstanceMessage) error {
+	databases, err := s.store.ListDatabases(ctx, &store.FindDatabaseMessage{
+		InstanceID: &instance.ResourceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch instance.Engine {
+	case db.MySQL:
+		return s.syncMySQLUnusedIndex(ctx, instance, databases)
+	case db.Postgres:
+		return s.syncPostgreSQLUnusedIndex(ctx, instance, databases)
+	default:
+		return errors.Errorf("unsupported database engine: %s", instance.Engine)
+	}
+}
+
+func (s *Syncer) syncMySQLUnusedIndex(ctx context.Context, instance *store.InstanceMessage, databases []*store.DatabaseMessage) error {
+	driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+
+	unusedIndexMap, err := queryUnusedIndex(ctx, driver.GetDB(), mysqlUnusedIndexQuery)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, database := range databases {
+		if database.SyncState != api.OK {
+			continue
+		}
+		var unusedIndexes []*store.UnusedIndexMessage
+		for _, index := range unusedIndexMap[database.DatabaseName] {
+			unusedIndexes = append(unusedIndexes, &store.UnusedIndexMessage{
+				DatabaseUID: database.UID,
+				// MySQL doesn't have schema, the object schema is the database.
+				SchemaName:  "",
+				TableName:   index.TableName,
+				IndexName:   index.IndexName,
+				UnusedSince: now,
+			})
+		}
+		if err := s.store.SetUnusedIndexes(ctx, database.UID, unusedIndexes); err != nil {
+			log.Warn("Failed to set unused indexes",
+				zap.String("instance", instance.ResourceID),
+				zap.String("database", database.DatabaseName),
+				zap.Int("databaseID", database.UID),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (s *Syncer) syncPostgreSQLUnusedIndex(ctx context.Context, instance *store.InstanceMessage, databases []*store.DatabaseMessage) error {
+	now := time.Now()
+	for _, database := range databases {
+		if database.SyncState != api.OK {
+			continue
+		}
+		if _, exists := pg.ExcludedDatabaseList[database.DatabaseName]; exists {
+			continue
+		}
+		// The pg_stat_user_indexes view only contains the indexes of the connected database.
+		unusedIndexes, err := func() ([]*store.UnusedIndexMessage, error) {
+			driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, database.DatabaseName)
+			if err != nil {
+				return nil, err
+			}
+			defer driver.Close(ctx)
+
+			unusedIndexMap, err := queryUnusedIndex(ctx, driver.GetDB(), pgUnusedIndexQuery)
+			if err != nil {
+				return nil, err
+			}
+			var unusedIndexes []*store.UnusedIndexMessage
+			for schema, indexList := range unusedIndexMap {
+				for _, index := range indexList {
+					unusedIndexes = append(unusedIndexes, &store.UnusedIndexMessage{
+						DatabaseUID: database.UID,
+						SchemaName:  schema,
+						TableName:   index.TableName,
+						IndexName:   index.IndexName,
+						UnusedSince: now,
+					})
+				}
+			}
+			return unusedIndexes, nil
+		}()
+		if err != nil {
+			log.Debug("Failed to query unused indexes",
+				zap.String("instance", instance.ResourceID),
+				zap.String("database", database.DatabaseName),
+				zap.Int("databaseID", database.UID),
+				zap.Error(err))
+			continue
+		}
+		if err := s.store.SetUnusedIndexes(ctx, database.UID, unusedIndexes); err != nil {
+			log.Warn("Failed to set unused indexes",
+				zap.String("instance", instance.ResourceID),
+				zap.String("database", database.DatabaseName),
+				zap.Int("databaseID", database.UID),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+type unusedIndex struct {
+	TableName string
+	IndexName string
+}
+
+// queryUnusedIndex returns the unused indexes grouped by the schema.
+// The query must return the schema name, table name and index name.
+func queryUnusedIndex(ctx context.Context, conn *sql.DB, query string) (map[string][]*unusedIndex, error) {
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]*unusedIndex)
+	for rows.Next() {
+		var schema string
+		index := &unusedIndex{}
+		if err := rows.Scan(&schema, &index.TableName, &index.IndexName); err != nil {
+			return nil, err
+		}
+		result[schema] = append(result[schema], index)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
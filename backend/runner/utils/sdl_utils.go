@@ -40,6 +40,10 @@ func ComputeDatabaseSchemaDiff(ctx context.Context, instance *store.InstanceMess
 		engine = parser.Postgres
 	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 		engine = parser.MySQL
+	case db.Oracle:
+		engine = parser.Oracle
+	case db.MSSQL:
+		engine = parser.MSSQL
 	default:
 		return "", errors.Errorf("unsupported database engine %q", instance.Engine)
 	}
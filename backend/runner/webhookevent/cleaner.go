@@ -0,0 +1,68 @@
+// Package webhookevent is a runner that deletes the recorded VCS webhook deliveries beyond the retention.
+package webhookevent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	webhookEventCleanInterval = 1 * time.Hour
+	// doneRetention is how long the processed deliveries are kept for looking up.
+	doneRetention = 7 * 24 * time.Hour
+	// failedRetention is how long the failed deliveries are kept for replaying.
+	failedRetention = 30 * 24 * time.Hour
+)
+
+// NewCleaner creates a new webhook event cleaner.
+func NewCleaner(store *store.Store) *Cleaner {
+	return &Cleaner{
+		store: store,
+	}
+}
+
+// Cleaner is the webhook event cleaner deleting the webhook events beyond the retention.
+type Cleaner struct {
+	store *store.Store
+}
+
+// Run will run the webhook event cleaner.
+func (c *Cleaner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(webhookEventCleanInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	log.Debug(fmt.Sprintf("Webhook event cleaner started and will run every %s", webhookEventCleanInterval.String()))
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Webhook event cleaner received context cancellation")
+			return
+		case <-ticker.C:
+			log.Debug("Webhook event cleaner received tick")
+			c.clean(ctx, time.Now())
+		}
+	}
+}
+
+func (c *Cleaner) clean(ctx context.Context, now time.Time) {
+	// The processing events are never deleted, they are being handled or replayed.
+	for status, retention := range map[api.WebhookEventStatus]time.Duration{
+		api.WebhookEventDone:   doneRetention,
+		api.WebhookEventFailed: failedRetention,
+	} {
+		count, err := c.store.DeleteWebhookEventsBefore(ctx, status, now.Add(-retention).Unix())
+		if err != nil {
+			log.Error("Failed to delete expired webhook events", zap.String("status", string(status)), zap.Error(err))
+		} else if count > 0 {
+			log.Debug("Deleted expired webhook events", zap.String("status", string(status)), zap.Int64("count", count))
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/masker"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// The access report answers which principals can currently query the sensitive columns, and whether the values are
+// masked for them, for the periodic access reviews.
+func (s *Server) registerAccessReportRoutes(g *echo.Group) {
+	g.GET("/access-report/sensitive-column", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if !s.licenseService.IsFeatureEnabled(api.FeatureSensitiveData) {
+			return echo.NewHTTPError(http.StatusForbidden, api.FeatureSensitiveData.AccessErrorMessage())
+		}
+		find := &store.FindDatabaseMessage{}
+		if databaseIDStr := c.QueryParam("database"); databaseIDStr != "" {
+			databaseID, err := strconv.Atoi(databaseIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database ID is not a number: %s", databaseIDStr)).SetInternal(err)
+			}
+			find.UID = &databaseID
+		}
+		masking := api.ColumnAccessMasking(c.QueryParam("masking"))
+		switch masking {
+		case "", api.ColumnAccessMasked, api.ColumnAccessUnmasked, api.ColumnAccessConditional:
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid masking %q", masking))
+		}
+		format := c.QueryParam("format")
+		if format != "" && format != "json" && format != "csv" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format %q, must be json or csv", format))
+		}
+
+		databases, err := s.store.ListDatabases(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list databases").SetInternal(err)
+		}
+		users, err := s.store.ListUsers(ctx, &store.FindUserMessage{})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list users").SetInternal(err)
+		}
+		dlpPolicy, err := s.store.GetDLPPolicy(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the DLP policy").SetInternal(err)
+		}
+
+		accessList := []*api.SensitiveColumnAccess{}
+		for _, database := range databases {
+			list, err := s.listSensitiveColumnAccess(ctx, database, users, dlpPolicy)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list the sensitive column access of database %q", database.DatabaseName)).SetInternal(err)
+			}
+			for _, access := range list {
+				if masking == "" || access.Masking == masking {
+					accessList = append(accessList, access)
+				}
+			}
+		}
+
+		if format != "csv" {
+			return c.JSON(http.StatusOK, accessList)
+		}
+		content, err := exportSensitiveColumnAccessCSV(accessList)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to export the access report").SetInternal(err)
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "sensitive-column-access.csv"))
+		return c.Blob(http.StatusOK, "text/csv; charset=UTF-8", content)
+	})
+}
+
+// listSensitiveColumnAccess returns the access of the users who can query the database to its sensitive columns,
+// ordered by the column and the user. It follows the database access and the masking of the SQL editor.
+func (s *Server) listSensitiveColumnAccess(ctx context.Context, database *store.DatabaseMessage, users []*store.UserMessage, dlpPolicy *api.DLPPolicy) ([]*api.SensitiveColumnAccess, error) {
+	sensitiveDataPolicy, err := s.store.GetSensitiveDataPolicy(ctx, database.UID)
+	if err != nil {
+		return nil, err
+	}
+	findings, err := s.listPIIFindings(ctx, &store.FindPIIFindingMessage{DatabaseUID: &database.UID})
+	if err != nil {
+		return nil, err
+	}
+	levels, err := s.getDLPColumnLevels(ctx, dlpPolicy, database.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	columnSet := make(map[api.SensitiveData]bool)
+	sensitiveDataMap := make(map[api.SensitiveData]api.SensitiveData)
+	for _, data := range sensitiveDataPolicy.SensitiveDataList {
+		key := api.SensitiveData{Schema: data.Schema, Table: data.Table, Column: data.Column}
+		sensitiveDataMap[key] = data
+		columnSet[key] = true
+	}
+	classificationMap := make(map[api.SensitiveData][]api.PIIClassification)
+	for _, finding := range findings {
+		if finding.Status == api.PIIFindingDismissed {
+			continue
+		}
+		key := api.SensitiveData{Schema: finding.SchemaName, Table: finding.TableName, Column: finding.ColumnName}
+		classificationMap[key] = append(classificationMap[key], finding.Classification)
+		columnSet[key] = true
+	}
+	for key, level := range levels {
+		if level > 0 {
+			columnSet[key] = true
+		}
+	}
+	if len(columnSet) == 0 {
+		return nil, nil
+	}
+	var columnList []api.SensitiveData
+	for key := range columnSet {
+		columnList = append(columnList, key)
+	}
+	sort.Slice(columnList, func(i, j int) bool {
+		if columnList[i].Schema != columnList[j].Schema {
+			return columnList[i].Schema < columnList[j].Schema
+		}
+		if columnList[i].Table != columnList[j].Table {
+			return columnList[i].Table < columnList[j].Table
+		}
+		return columnList[i].Column < columnList[j].Column
+	})
+
+	type accessor struct {
+		user      *store.UserMessage
+		source    api.ColumnAccessSource
+		requester map[string]string
+		clearance int
+	}
+	var accessors []*accessor
+	for _, user := range users {
+		if user.ID == api.SystemBotID || user.MemberDeleted {
+			continue
+		}
+		source, err := s.getDatabaseAccessSource(ctx, user, database)
+		if err != nil {
+			return nil, err
+		}
+		if source == "" {
+			continue
+		}
+		accessors = append(accessors, &accessor{
+			user:      user,
+			source:    source,
+			requester: map[string]string{"email": user.Email, "name": user.Name, "role": string(user.Role)},
+			clearance: dlpPolicy.GetClearance(user.Email, user.Role),
+		})
+	}
+
+	var accessList []*api.SensitiveColumnAccess
+	for _, key := range columnList {
+		data, masked := sensitiveDataMap[key]
+		var condition *masker.Condition
+		if masked && data.Condition != "" {
+			// The invalid conditions are reported as masked, as they fail the queries.
+			condition, _ = masker.NewCondition(data.Condition)
+		}
+		for _, a := range accessors {
+			access := &api.SensitiveColumnAccess{
+				DatabaseID:         database.UID,
+				DatabaseName:       database.DatabaseName,
+				Schema:             key.Schema,
+				Table:              key.Table,
+				Column:             key.Column,
+				ClassificationList: classificationMap[key],
+				Level:              levels[key],
+				MaskType:           data.Type,
+				MaskCondition:      data.Condition,
+				PrincipalID:        a.user.ID,
+				PrincipalEmail:     a.user.Email,
+				PrincipalRole:      a.user.Role,
+				Source:             a.source,
+				Masking:            api.ColumnAccessUnmasked,
+				Exportable:         levels[key] <= a.clearance,
+			}
+			if masked {
+				access.Masking = api.ColumnAccessMasked
+				if condition != nil {
+					mask, decided := condition.ShouldMaskForRequester(a.requester)
+					switch {
+					case !decided:
+						access.Masking = api.ColumnAccessConditional
+					case !mask:
+						access.Masking = api.ColumnAccessUnmasked
+					}
+				}
+			}
+			accessList = append(accessList, access)
+		}
+	}
+	return accessList, nil
+}
+
+// getDatabaseAccessSource returns how the user can query the database, or empty if the user can't.
+func (s *Server) getDatabaseAccessSource(ctx context.Context, user *store.UserMessage, database *store.DatabaseMessage) (api.ColumnAccessSource, error) {
+	if user.Role == api.Owner || user.Role == api.DBA {
+		return api.ColumnAccessSourceWorkspaceRole, nil
+	}
+	granted, err := s.hasActiveDatabaseGrant(ctx, user.ID, database.UID, api.DatabaseGrantQuerier)
+	if err != nil {
+		return "", err
+	}
+	if granted {
+		return api.ColumnAccessSourceDatabaseGrant, nil
+	}
+	hasAccessRights, err := s.hasDatabaseAccessRights(ctx, user.ID, user.Role, database)
+	if err != nil {
+		return "", err
+	}
+	if !hasAccessRights {
+		return "", nil
+	}
+	return api.ColumnAccessSourceProjectRole, nil
+}
+
+func exportSensitiveColumnAccessCSV(accessList []*api.SensitiveColumnAccess) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"Database", "Schema", "Table", "Column", "Classifications", "Level", "Mask Type", "Mask Condition", "Principal", "Role", "Source", "Masking", "Exportable"}); err != nil {
+		return nil, err
+	}
+	for _, access := range accessList {
+		var classifications []string
+		for _, classification := range access.ClassificationList {
+			classifications = append(classifications, string(classification))
+		}
+		if err := w.Write([]string{
+			access.DatabaseName,
+			access.Schema,
+			access.Table,
+			access.Column,
+			strings.Join(classifications, ";"),
+			strconv.Itoa(access.Level),
+			string(access.MaskType),
+			access.MaskCondition,
+			access.PrincipalEmail,
+			string(access.PrincipalRole),
+			string(access.Source),
+			string(access.Masking),
+			strconv.FormatBool(access.Exportable),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
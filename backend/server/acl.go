@@ -116,16 +116,10 @@ func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.
 				if err != nil {
 					return nil, err
 				}
-				projectRoles := make(map[common.ProjectRole]bool)
-				for _, binding := range policy.Bindings {
-					for _, member := range binding.Members {
-						if member.ID == principalID {
-							projectRoles[common.ProjectRole(binding.Role)] = true
-							break
-						}
-					}
-				}
-				return projectRoles, nil
+				return s.getProjectRoles(ctx, principalID, policy)
+			}
+			customRolePermissionsFinder := func() (map[common.ProjectRole][]api.ProjectPermissionType, error) {
+				return s.getCustomRolePermissions(ctx)
 			}
 
 			sheetFinder := func(sheetID int) (*api.Sheet, error) {
@@ -166,7 +160,7 @@ func aclMiddleware(s *Server, pathPrefix string, ce *casbin.Enforcer, next echo.
 				}
 				c.Request().Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 
-				aclErr = enforceWorkspaceDeveloperIssueRouteACL(s.licenseService.GetEffectivePlan(), path, method, string(bodyBytes), c.QueryParams(), principalID, getRetrieveIssueProjectID(ctx, s.store), projectRolesFinder)
+				aclErr = enforceWorkspaceDeveloperIssueRouteACL(s.licenseService.GetEffectivePlan(), path, method, string(bodyBytes), c.QueryParams(), principalID, getRetrieveIssueProjectID(ctx, s.store), projectRolesFinder, customRolePermissionsFinder)
 			}
 			if aclErr != nil {
 				return aclErr
@@ -394,7 +388,7 @@ func enforceWorkspaceDeveloperSheetRouteACL(plan api.PlanType, path string, meth
 var issueStatusRegex = regexp.MustCompile(`^/issue/(?P<issueID>\d+)/status$`)
 var issueRouteRegex = regexp.MustCompile(`^/issue/(?P<issueID>\d+)$`)
 
-func enforceWorkspaceDeveloperIssueRouteACL(plan api.PlanType, path string, method string, body string, queryParams url.Values, principalID int, getIssueProjectID func(issueID int) (int, error), projectRolesFinder func(projectID int, principalID int) (map[common.ProjectRole]bool, error)) *echo.HTTPError {
+func enforceWorkspaceDeveloperIssueRouteACL(plan api.PlanType, path string, method string, body string, queryParams url.Values, principalID int, getIssueProjectID func(issueID int) (int, error), projectRolesFinder func(projectID int, principalID int) (map[common.ProjectRole]bool, error), customRolePermissionsFinder func() (map[common.ProjectRole][]api.ProjectPermissionType, error)) *echo.HTTPError {
 	switch method {
 	case http.MethodGet:
 		// For /issue route, require the caller principal to be the same as the user in the query.
@@ -457,7 +451,14 @@ func enforceWorkspaceDeveloperIssueRouteACL(plan api.PlanType, path string, meth
 				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
 			}
 			if !api.ProjectPermission(api.ProjectPermissionChangeDatabase, plan, projectRoles) {
-				return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("not allowed to create issues under the project %d", issueCreate.ProjectID))
+				// The custom roles can be composed to create issues too.
+				rolePermissions, err := customRolePermissionsFinder()
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process authorize request.").SetInternal(err)
+				}
+				if !api.CustomRolePermission(api.ProjectPermissionChangeDatabase, plan, projectRoles, rolePermissions) {
+					return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("not allowed to create issues under the project %d", issueCreate.ProjectID))
+				}
 			}
 		}
 	}
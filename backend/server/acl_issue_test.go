@@ -54,7 +54,7 @@ func TestWorkspaceDeveloperIssueRouteACL_RetrieveIssue(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
-			err := enforceWorkspaceDeveloperIssueRouteACL(tc.plan, tc.path, tc.method, tc.body, tc.queryParams, tc.principalID, testWorkspaceDeveloperIssueRouteMockGetIssueProjectID, getProjectRolesFinderForTest(testWorkspaceDeveloperIssueRouteHelper.projectMembers))
+			err := enforceWorkspaceDeveloperIssueRouteACL(tc.plan, tc.path, tc.method, tc.body, tc.queryParams, tc.principalID, testWorkspaceDeveloperIssueRouteMockGetIssueProjectID, getProjectRolesFinderForTest(testWorkspaceDeveloperIssueRouteHelper.projectMembers), testWorkspaceDeveloperIssueRouteMockCustomRolePermissions)
 			if err != nil {
 				if tc.errMsg == "" {
 					t.Errorf("expect no error, got %s", err.Message)
@@ -126,7 +126,7 @@ func TestWorkspaceDeveloperIssueRouteACL_OperateIssue(t *testing.T) {
 	for _, tc := range tests {
 		tc := tc
 		t.Run(tc.desc, func(t *testing.T) {
-			err := enforceWorkspaceDeveloperIssueRouteACL(tc.plan, tc.path, tc.method, tc.body, tc.queryParams, tc.principalID, testWorkspaceDeveloperIssueRouteMockGetIssueProjectID, getProjectRolesFinderForTest(testWorkspaceDeveloperIssueRouteHelper.projectMembers))
+			err := enforceWorkspaceDeveloperIssueRouteACL(tc.plan, tc.path, tc.method, tc.body, tc.queryParams, tc.principalID, testWorkspaceDeveloperIssueRouteMockGetIssueProjectID, getProjectRolesFinderForTest(testWorkspaceDeveloperIssueRouteHelper.projectMembers), testWorkspaceDeveloperIssueRouteMockCustomRolePermissions)
 			if err != nil {
 				if tc.errMsg == "" {
 					t.Errorf("expect no error, got %s", err.Message)
@@ -183,11 +183,31 @@ func TestWorkspaceDeveloperIssueRouteACL_CreateIssue(t *testing.T) {
 			principalID: 204,
 			errMsg:      "not allowed to create issues under the project 102",
 		},
+		{
+			desc:        "Create issue under project I am a member of with a custom role allowed to create issues",
+			plan:        api.ENTERPRISE,
+			path:        "/issue",
+			body:        `{"data":{"type":"issue","attributes":{"title":"test","description":"test","projectId":102}}}`,
+			queryParams: url.Values{},
+			method:      "POST",
+			principalID: 205,
+			errMsg:      "",
+		},
+		{
+			desc:        "Create issue with a custom role allowed to create issues without the custom role feature",
+			plan:        api.TEAM,
+			path:        "/issue",
+			body:        `{"data":{"type":"issue","attributes":{"title":"test","description":"test","projectId":102}}}`,
+			queryParams: url.Values{},
+			method:      "POST",
+			principalID: 205,
+			errMsg:      "not allowed to create issues under the project 102",
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.desc, func(t *testing.T) {
-			err := enforceWorkspaceDeveloperIssueRouteACL(tc.plan, tc.path, tc.method, tc.body, tc.queryParams, tc.principalID, testWorkspaceDeveloperIssueRouteMockGetIssueProjectID, getProjectRolesFinderForTest(testWorkspaceDeveloperIssueRouteHelper.projectMembers))
+			err := enforceWorkspaceDeveloperIssueRouteACL(tc.plan, tc.path, tc.method, tc.body, tc.queryParams, tc.principalID, testWorkspaceDeveloperIssueRouteMockGetIssueProjectID, getProjectRolesFinderForTest(testWorkspaceDeveloperIssueRouteHelper.projectMembers), testWorkspaceDeveloperIssueRouteMockCustomRolePermissions)
 			if err != nil {
 				if tc.errMsg == "" {
 					t.Errorf("expect no error, got %s", err.Message)
@@ -209,9 +229,10 @@ var testWorkspaceDeveloperIssueRouteHelper = struct {
 	projectMembers: map[int]map[common.ProjectRole][]int{
 		// Project 102 contains members 202 and 203.
 		102: {
-			common.ProjectOwner:              {202},
-			common.ProjectDeveloper:          {202, 203},
-			common.ProjectRole("CustomRole"): {204},
+			common.ProjectOwner:                {202},
+			common.ProjectDeveloper:            {202, 203},
+			common.ProjectRole("CustomRole"):   {204},
+			common.ProjectRole("IssueCreator"): {205},
 		},
 		// Project 103 contains member 204.
 		103: {
@@ -235,3 +256,10 @@ func testWorkspaceDeveloperIssueRouteMockGetIssueProjectID(issueID int) (int, er
 	}
 	return projectID, nil
 }
+
+func testWorkspaceDeveloperIssueRouteMockCustomRolePermissions() (map[common.ProjectRole][]api.ProjectPermissionType, error) {
+	return map[common.ProjectRole][]api.ProjectPermissionType{
+		common.ProjectRole("CustomRole"):   api.DefaultCustomRolePermissionList,
+		common.ProjectRole("IssueCreator"): {api.ProjectPermissionChangeDatabase},
+	}, nil
+}
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// adminSessionLimit is the number of the latest admin sessions returned.
+const adminSessionLimit = 100
+
+// adminSessionResponse is the content of the response entry of the admin session transcript.
+type adminSessionResponse struct {
+	SingleSQLResultList []api.SingleSQLResult `json:"singleSQLResultList"`
+	Error               string                `json:"error"`
+}
+
+func (s *Server) registerAdminSessionRoutes(g *echo.Group) {
+	g.POST("/sql/admin-session", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+
+		create := &api.AdminSessionCreate{}
+		if err := json.NewDecoder(c.Request().Body).Decode(create); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create admin session request").SetInternal(err)
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &create.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", create.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", create.InstanceID))
+		}
+		session, err := s.store.CreateAdminSession(ctx, &store.AdminSessionMessage{
+			CreatorID:    principalID,
+			InstanceUID:  instance.UID,
+			DatabaseName: create.DatabaseName,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create admin session").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIAdminSession(session))
+	})
+
+	g.GET("/sql/admin-session", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		limit := adminSessionLimit
+		find := &store.FindAdminSessionMessage{Limit: &limit}
+		if creatorID := c.QueryParam("creator"); creatorID != "" {
+			id, err := strconv.Atoi(creatorID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Creator ID is not a number: %s", creatorID)).SetInternal(err)
+			}
+			find.CreatorID = &id
+		}
+		if instanceID := c.QueryParam("instance"); instanceID != "" {
+			id, err := strconv.Atoi(instanceID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance ID is not a number: %s", instanceID)).SetInternal(err)
+			}
+			find.InstanceUID = &id
+		}
+		sessions, err := s.store.ListAdminSessions(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list admin sessions").SetInternal(err)
+		}
+		sessionList := []*api.AdminSession{}
+		for _, session := range sessions {
+			sessionList = append(sessionList, toAPIAdminSession(session))
+		}
+		return c.JSON(http.StatusOK, sessionList)
+	})
+
+	g.GET("/sql/admin-session/:adminSessionID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		session, err := s.getAdminSession(c)
+		if err != nil {
+			return err
+		}
+		entries, err := s.store.ListAdminSessionEntries(ctx, session.ID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list entries of admin session ID: %d", session.ID)).SetInternal(err)
+		}
+		transcript := &api.AdminSessionTranscript{
+			Session:   toAPIAdminSession(session),
+			EntryList: []*api.AdminSessionEntry{},
+			Verified:  true,
+		}
+		if err := store.VerifyAdminSessionTranscript(session, entries); err != nil {
+			transcript.Verified = false
+			transcript.VerifyError = err.Error()
+		}
+		for _, entry := range entries {
+			transcript.EntryList = append(transcript.EntryList, &api.AdminSessionEntry{
+				Seq:       entry.Seq,
+				CreatedTs: entry.CreatedTs,
+				Kind:      entry.Kind,
+				Content:   entry.Content,
+				Truncated: entry.Truncated,
+				PrevHash:  entry.PrevHash,
+				Hash:      entry.Hash,
+			})
+		}
+		return c.JSON(http.StatusOK, transcript)
+	})
+
+	g.POST("/sql/admin-session/:adminSessionID/end", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		session, err := s.getAdminSession(c)
+		if err != nil {
+			return err
+		}
+		if session.CreatorID != principalID {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the creator of the admin session can end it")
+		}
+		if err := s.store.EndAdminSession(ctx, session.ID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to end admin session ID: %d", session.ID)).SetInternal(err)
+		}
+		session, err = s.store.GetAdminSession(ctx, &store.FindAdminSessionMessage{ID: &session.ID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get admin session ID: %d", session.ID)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIAdminSession(session))
+	})
+}
+
+// getAdminSession gets the admin session in the path.
+func (s *Server) getAdminSession(c echo.Context) (*store.AdminSessionMessage, error) {
+	ctx := c.Request().Context()
+	id, err := strconv.Atoi(c.Param("adminSessionID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("adminSessionID"))).SetInternal(err)
+	}
+	session, err := s.store.GetAdminSession(ctx, &store.FindAdminSessionMessage{ID: &id})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get admin session ID: %d", id)).SetInternal(err)
+	}
+	if session == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Admin session not found with ID %d", id))
+	}
+	return session, nil
+}
+
+// getExecuteAdminSession returns the admin session the statement is recorded in. An implicit session is opened for the
+// statement if the session isn't given, which should be ended after the execution.
+func (s *Server) getExecuteAdminSession(ctx context.Context, principalID int, instance *store.InstanceMessage, exec *api.SQLExecute) (*store.AdminSessionMessage, error) {
+	if exec.AdminSessionID == 0 {
+		session, err := s.store.CreateAdminSession(ctx, &store.AdminSessionMessage{
+			CreatorID:    principalID,
+			InstanceUID:  instance.UID,
+			DatabaseName: exec.DatabaseName,
+		})
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to create admin session").SetInternal(err)
+		}
+		return session, nil
+	}
+	session, err := s.store.GetAdminSession(ctx, &store.FindAdminSessionMessage{ID: &exec.AdminSessionID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get admin session ID: %d", exec.AdminSessionID)).SetInternal(err)
+	}
+	if session == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Admin session not found with ID %d", exec.AdminSessionID))
+	}
+	if session.CreatorID != principalID {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "The admin session is opened by another user")
+	}
+	if session.EndedTs != 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Admin session ID %d has ended", session.ID))
+	}
+	if session.InstanceUID != instance.UID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Admin session ID %d is opened on another instance", session.ID))
+	}
+	return session, nil
+}
+
+// recordAdminSessionResponse records the results of the statement in the admin session.
+func (s *Server) recordAdminSessionResponse(ctx context.Context, session *store.AdminSessionMessage, singleSQLResults []api.SingleSQLResult, queryErr error) error {
+	response := &adminSessionResponse{SingleSQLResultList: singleSQLResults}
+	if queryErr != nil {
+		response.Error = queryErr.Error()
+	}
+	content, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	_, err = s.store.AppendAdminSessionEntry(ctx, session.ID, api.AdminSessionEntryResponse, string(content))
+	return err
+}
+
+func toAPIAdminSession(session *store.AdminSessionMessage) *api.AdminSession {
+	return &api.AdminSession{
+		ID:           session.ID,
+		CreatorID:    session.CreatorID,
+		CreatedTs:    session.CreatedTs,
+		InstanceID:   session.InstanceUID,
+		DatabaseName: session.DatabaseName,
+		EndedTs:      session.EndedTs,
+		EntryCount:   session.EntryCount,
+	}
+}
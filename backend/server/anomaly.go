@@ -26,7 +26,7 @@ func (s *Server) registerAnomalyRoutes(g *echo.Group) {
 				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter instance is not a number: %s", instanceIDStr)).SetInternal(err)
 			}
 			find.InstanceUID = &instanceID
-			find.Types = append(find.Types, api.AnomalyInstanceConnection, api.AnomalyInstanceMigrationSchema)
+			find.Types = append(find.Types, api.AnomalyInstanceConnection, api.AnomalyInstanceMigrationSchema, api.AnomalyInstanceCertificateExpiry)
 		}
 		if databaseIDStr := c.QueryParam("database"); databaseIDStr != "" {
 			databaseID, err := strconv.Atoi(databaseIDStr)
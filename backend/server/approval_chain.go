@@ -0,0 +1,38 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func (s *Server) registerApprovalChainWebhookRoutes(g *echo.Group) {
+	// The token in the callback URL is sent to the external approver with the pending step, so the callback doesn't
+	// need another credential.
+	g.POST("/approval/:token", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if s.ApprovalChainRunner == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "External approval is not available in readonly mode")
+		}
+		callback := &api.ExternalApprovalCallback{}
+		if err := json.NewDecoder(c.Request().Body).Decode(callback); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed external approval callback").SetInternal(err)
+		}
+		if err := s.ApprovalChainRunner.HandleExternalCallback(ctx, c.Param("token"), callback); err != nil {
+			switch common.ErrorCode(err) {
+			case common.NotFound:
+				return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			case common.Conflict:
+				return echo.NewHTTPError(http.StatusConflict, err.Error())
+			case common.Invalid:
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to handle external approval callback").SetInternal(err)
+		}
+		return c.String(http.StatusOK, "OK")
+	})
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/bytebase/bytebase/backend/component/activity"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func (s *Server) registerAutoApprovalRoutes(g *echo.Group) {
+	g.GET("/auto-approval", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		find := &store.FindAutoApprovalMessage{}
+		if issueIDStr := c.QueryParam("issue"); issueIDStr != "" {
+			issueID, err := strconv.Atoi(issueIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter issue is not a number: %s", issueIDStr)).SetInternal(err)
+			}
+			find.IssueUID = &issueID
+		}
+		autoApprovals, err := s.store.ListAutoApprovals(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list auto-approvals").SetInternal(err)
+		}
+		autoApprovalList := []*api.AutoApproval{}
+		for _, autoApproval := range autoApprovals {
+			autoApprovalList = append(autoApprovalList, toAPIAutoApproval(autoApproval))
+		}
+		return c.JSON(http.StatusOK, autoApprovalList)
+	})
+
+	// Revoking an auto-approval dismisses the approvals of the issue, and the issue requires the approvers again.
+	g.POST("/auto-approval/:autoApprovalID/revoke", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		id, err := strconv.Atoi(c.Param("autoApprovalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("autoApprovalID"))).SetInternal(err)
+		}
+		autoApprovals, err := s.store.ListAutoApprovals(ctx, &store.FindAutoApprovalMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get auto-approval %d", id)).SetInternal(err)
+		}
+		if len(autoApprovals) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Auto-approval %d not found", id))
+		}
+		autoApproval := autoApprovals[0]
+		if autoApproval.RevokerID != nil {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Auto-approval %d is already revoked", id))
+		}
+		issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: &autoApproval.IssueUID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue ID: %d", autoApproval.IssueUID)).SetInternal(err)
+		}
+		if issue == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", autoApproval.IssueUID))
+		}
+		if issue.Status != api.IssueOpen {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cannot revoke the auto-approval of issue %d which is %s", issue.UID, issue.Status))
+		}
+
+		if err := s.store.RevokeAutoApproval(ctx, autoApproval.UID, principalID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to revoke auto-approval %d", id)).SetInternal(err)
+		}
+		// Re-find the approval template, the issue with a revoked auto-approval isn't approved automatically again.
+		payloadBytes, err := protojson.Marshal(&storepb.IssuePayload{
+			Approval: &storepb.IssuePayloadApproval{
+				ApprovalFindingDone: false,
+			},
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal issue payload").SetInternal(err)
+		}
+		payloadStr := string(payloadBytes)
+		issue, err = s.store.UpdateIssueV2(ctx, issue.UID, &store.UpdateIssueMessage{
+			Payload: &payloadStr,
+		}, api.SystemBotID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update issue").SetInternal(err)
+		}
+		s.stateCfg.ApprovalFinding.Store(issue.UID, issue)
+
+		activityPayload, err := protojson.Marshal(&storepb.ActivityIssueCommentCreatePayload{
+			IssueName: issue.Title,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal activity payload").SetInternal(err)
+		}
+		if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+			CreatorID:   principalID,
+			ContainerID: issue.UID,
+			Type:        api.ActivityIssueCommentCreate,
+			Level:       api.ActivityInfo,
+			Comment:     fmt.Sprintf("Revoked the auto-approval by rule %q.", autoApproval.RuleID),
+			Payload:     string(activityPayload),
+		}, &activity.Metadata{}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
+		}
+
+		autoApprovals, err = s.store.ListAutoApprovals(ctx, &store.FindAutoApprovalMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get auto-approval %d", id)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIAutoApproval(autoApprovals[0]))
+	})
+}
+
+func toAPIAutoApproval(autoApproval *store.AutoApprovalMessage) *api.AutoApproval {
+	v := &api.AutoApproval{
+		ID:        autoApproval.UID,
+		CreatedTs: autoApproval.CreatedTs,
+		IssueID:   autoApproval.IssueUID,
+		RiskLevel: autoApproval.RiskLevel,
+		RuleID:    autoApproval.RuleID,
+		Condition: autoApproval.Condition,
+		RevokedTs: autoApproval.RevokedTs,
+	}
+	if autoApproval.RevokerID != nil {
+		v.RevokerID = *autoApproval.RevokerID
+	}
+	return v
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func (s *Server) registerCloudDiscoveryRoutes(g *echo.Group) {
+	g.GET("/cloud-discovery/instance", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if !common.FeatureFlag(common.FeatureFlagCloudDiscovery) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Cloud instance discovery is not supported yet")
+		}
+
+		find := &store.FindDiscoveredInstanceMessage{}
+		if status := c.QueryParam("status"); status != "" {
+			find.Status = (*api.DiscoveredInstanceStatus)(&status)
+		}
+		discoveredInstances, err := s.store.ListDiscoveredInstances(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list discovered instances").SetInternal(err)
+		}
+
+		discoveredInstanceList := []*api.DiscoveredInstance{}
+		for _, discoveredInstance := range discoveredInstances {
+			discoveredInstanceList = append(discoveredInstanceList, toAPIDiscoveredInstance(discoveredInstance))
+		}
+		return c.JSON(http.StatusOK, discoveredInstanceList)
+	})
+
+	g.PATCH("/cloud-discovery/instance/:discoveredInstanceID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("discoveredInstanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("discoveredInstanceID"))).SetInternal(err)
+		}
+		if !common.FeatureFlag(common.FeatureFlagCloudDiscovery) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Cloud instance discovery is not supported yet")
+		}
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read request body").SetInternal(err)
+		}
+		discoveredInstancePatch := &api.DiscoveredInstancePatch{}
+		if err := json.Unmarshal(body, discoveredInstancePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch discovered instance request").SetInternal(err)
+		}
+
+		discoveredInstance, err := s.store.GetDiscoveredInstance(ctx, &store.FindDiscoveredInstanceMessage{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get discovered instance ID: %d", id)).SetInternal(err)
+		}
+		if discoveredInstance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Discovered instance not found with ID %d", id))
+		}
+		update := &store.UpdateDiscoveredInstanceMessage{
+			ID:     id,
+			Status: discoveredInstancePatch.Status,
+		}
+		if v := discoveredInstancePatch.Status; v != nil {
+			switch {
+			case *v == api.DiscoveredInstanceDismissed && discoveredInstance.Status == api.DiscoveredInstanceProposed:
+			case *v == api.DiscoveredInstanceProposed && discoveredInstance.Status == api.DiscoveredInstanceDismissed:
+			default:
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cannot change the status of discovered instance from %s to %s", discoveredInstance.Status, *v))
+			}
+		}
+		if v := discoveredInstancePatch.InstanceID; v != nil {
+			if discoveredInstancePatch.Status != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Cannot change the status and link the instance at the same time")
+			}
+			instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: v})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %d", *v)).SetInternal(err)
+			}
+			if instance == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance not found with ID %d", *v))
+			}
+			registered := api.DiscoveredInstanceRegistered
+			update.Status = &registered
+			update.InstanceUID = &instance.UID
+		}
+
+		discoveredInstance, err = s.store.UpdateDiscoveredInstance(ctx, update)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update discovered instance ID: %d", id)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIDiscoveredInstance(discoveredInstance))
+	})
+
+	// Discover the instances right away, such as after changing the setting or registering a discovered instance.
+	g.POST("/cloud-discovery/sync", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if !common.FeatureFlag(common.FeatureFlagCloudDiscovery) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Cloud instance discovery is not supported yet")
+		}
+		if s.CloudDiscoverer == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Cloud instance discovery is not available in readonly mode")
+		}
+		if err := s.CloudDiscoverer.Discover(ctx); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to discover cloud instances").SetInternal(err)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+}
+
+func toAPIDiscoveredInstance(discoveredInstance *store.DiscoveredInstanceMessage) *api.DiscoveredInstance {
+	apiDiscoveredInstance := &api.DiscoveredInstance{
+		ID:         discoveredInstance.ID,
+		Provider:   discoveredInstance.Provider,
+		ExternalID: discoveredInstance.ExternalID,
+		Name:       discoveredInstance.Name,
+		Engine:     discoveredInstance.Engine,
+		Host:       discoveredInstance.Host,
+		Port:       discoveredInstance.Port,
+		Region:     discoveredInstance.Region,
+		Labels:     discoveredInstance.Labels,
+		Status:     discoveredInstance.Status,
+		LastSeenTs: discoveredInstance.LastSeenTs,
+	}
+	if v := discoveredInstance.EnvironmentUID; v != nil {
+		apiDiscoveredInstance.EnvironmentID = *v
+	}
+	if v := discoveredInstance.InstanceUID; v != nil {
+		apiDiscoveredInstance.InstanceID = *v
+	}
+	return apiDiscoveredInstance
+}
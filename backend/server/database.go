@@ -2,14 +2,18 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/encoding/protojson"
 
@@ -17,11 +21,16 @@ import (
 	"github.com/bytebase/bytebase/backend/common/log"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/pg"
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
 	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
 
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/differ"
 	"github.com/bytebase/bytebase/backend/plugin/parser/sql/edit"
 	"github.com/bytebase/bytebase/backend/plugin/parser/sql/transform"
+	runnerutils "github.com/bytebase/bytebase/backend/runner/utils"
 	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
 )
 
 func (s *Server) registerDatabaseRoutes(g *echo.Group) {
@@ -250,11 +259,14 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			if err != nil {
 				return err
 			}
-			// We only support MySQL now.
 			var engineType parser.EngineType
 			switch instance.Engine {
 			case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 				engineType = parser.MySQL
+			case db.Oracle:
+				engineType = parser.Oracle
+			case db.MSSQL:
+				engineType = parser.MSSQL
 			default:
 				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Not support SDL format for %s instance", instance.Engine))
 			}
@@ -277,6 +289,244 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		return nil
 	})
 
+	// The schema export renders the synced schema metadata as the ER diagram for the documentation pipelines.
+	g.GET("/database/:databaseID/schema/export", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		format := utils.SchemaExportFormat(c.QueryParam("format"))
+		if format == "" {
+			format = utils.SchemaExportFormatDBML
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		dbSchema, err := s.store.GetDBSchema(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get dbSchema for database %q", database.DatabaseName)).SetInternal(err)
+		}
+		if dbSchema == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Schema of database %q is not synced yet", database.DatabaseName))
+		}
+
+		diagram, err := utils.ExportSchemaDiagram(dbSchema.Metadata, format)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
+		if _, err := c.Response().Write([]byte(diagram)); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to write schema export response for database %q", database.DatabaseName)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// The schema import parses the schema designed in DBML as the desired schema and computes the migration statement.
+	g.POST("/database/:databaseID/schema/import", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		schemaImport := &api.DatabaseSchemaImport{}
+		if err := json.NewDecoder(c.Request().Body).Decode(schemaImport); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed schema import request").SetInternal(err)
+		}
+		if schemaImport.Format != "dbml" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported schema import format %q", schemaImport.Format))
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", database.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance %q not found", database.InstanceID))
+		}
+
+		metadata, err := utils.ParseDBML(instance.Engine, schemaImport.Content)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to parse DBML: %v", err)).SetInternal(err)
+		}
+		schema, err := utils.GenerateSDL(instance.Engine, metadata)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to generate schema for %s instance: %v", instance.Engine, err)).SetInternal(err)
+		}
+		statement, err := runnerutils.ComputeDatabaseSchemaDiff(ctx, instance, database.DatabaseName, s.dbFactory, schema)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compute the migration for database %q", database.DatabaseName)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, &api.DatabaseSchemaImportResult{
+			Schema:    schema,
+			Statement: statement,
+		})
+	})
+
+	// The schema conversion converts the MySQL schema to the schema of another engine for the engine migration projects.
+	g.POST("/database/:databaseID/schema/convert", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		schemaConvert := &api.DatabaseSchemaConvert{}
+		if err := json.NewDecoder(c.Request().Body).Decode(schemaConvert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed schema convert request").SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", database.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance %q not found", database.InstanceID))
+		}
+		if instance.Engine != db.MySQL && instance.Engine != db.MariaDB {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Schema conversion only supports MySQL databases, got %s", instance.Engine))
+		}
+
+		schema := schemaConvert.Schema
+		if schema == "" {
+			dbSchema, err := s.store.GetDBSchema(ctx, id)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get dbSchema for database %q", database.DatabaseName)).SetInternal(err)
+			}
+			if dbSchema == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Schema of database %q is not synced yet", database.DatabaseName))
+			}
+			schema = string(dbSchema.Schema)
+		}
+		convertedSchema, lossyMappingList, err := utils.ConvertMySQLSchema(schemaConvert.TargetEngine, schema)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to convert schema to %s: %v", schemaConvert.TargetEngine, err)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, &api.DatabaseSchemaConvertResult{
+			Schema:           convertedSchema,
+			LossyMappingList: lossyMappingList,
+		})
+	})
+
+	// The schema comparison diffs the schema with the same database in another environment, such as staging and prod,
+	// and computes the promotion migration for the database in the other environment.
+	g.POST("/database/:databaseID/schema/compare", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		schemaCompare := &api.DatabaseSchemaCompare{}
+		if err := json.NewDecoder(c.Request().Body).Decode(schemaCompare); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed schema compare request").SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		environment, err := s.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{UID: &schemaCompare.EnvironmentID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch environment ID: %v", schemaCompare.EnvironmentID)).SetInternal(err)
+		}
+		if environment == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Environment not found with ID %d", schemaCompare.EnvironmentID))
+		}
+		if environment.ResourceID == database.EnvironmentID {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q is already in environment %q", database.DatabaseName, environment.Title))
+		}
+		// The same logical database has the same name in the same project across the environments.
+		targetDatabaseList, err := s.store.ListDatabases(ctx, &store.FindDatabaseMessage{
+			ProjectID:     &database.ProjectID,
+			EnvironmentID: &environment.ResourceID,
+			DatabaseName:  &database.DatabaseName,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list databases in environment %q", environment.Title)).SetInternal(err)
+		}
+		if len(targetDatabaseList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database %q not found in environment %q", database.DatabaseName, environment.Title))
+		}
+		if len(targetDatabaseList) > 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Found %d databases named %q in environment %q", len(targetDatabaseList), database.DatabaseName, environment.Title))
+		}
+		targetDatabase := targetDatabaseList[0]
+
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", database.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance %q not found", database.InstanceID))
+		}
+		targetInstance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &targetDatabase.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", targetDatabase.InstanceID)).SetInternal(err)
+		}
+		if targetInstance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance %q not found", targetDatabase.InstanceID))
+		}
+		if instance.Engine != targetInstance.Engine {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cannot compare %s database with %s database", instance.Engine, targetInstance.Engine))
+		}
+		var engine parser.EngineType
+		switch instance.Engine {
+		case db.Postgres:
+			engine = parser.Postgres
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+			engine = parser.MySQL
+		case db.Oracle:
+			engine = parser.Oracle
+		case db.MSSQL:
+			engine = parser.MSSQL
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Schema comparison is not supported for engine %s", instance.Engine))
+		}
+
+		dbSchema, err := s.store.GetDBSchema(ctx, database.UID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get dbSchema for database %q", database.DatabaseName)).SetInternal(err)
+		}
+		targetDBSchema, err := s.store.GetDBSchema(ctx, targetDatabase.UID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get dbSchema for database %q", targetDatabase.DatabaseName)).SetInternal(err)
+		}
+		if dbSchema == nil || targetDBSchema == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Schema of database %q is not synced yet in both environments", database.DatabaseName))
+		}
+
+		statement, err := differ.SchemaDiff(engine, string(targetDBSchema.Schema), string(dbSchema.Schema))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compute promotion migration for database %q", database.DatabaseName)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, &api.DatabaseSchemaCompareResult{
+			TargetDatabaseID: targetDatabase.UID,
+			DiffList:         utils.CompareSchemaMetadata(dbSchema.Metadata, targetDBSchema.Metadata),
+			Statement:        statement,
+		})
+	})
+
 	g.POST("/database/:databaseID/backup", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		id, err := strconv.Atoi(c.Param("databaseID"))
@@ -447,6 +697,504 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		return nil
 	})
 
+	g.GET("/database/:databaseID/unused-index", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		if !common.FeatureFlag(common.FeatureFlagUnusedIndex) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Unused index report is not supported yet")
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		unusedIndexes, err := s.store.ListUnusedIndexes(ctx, &store.FindUnusedIndexMessage{DatabaseUID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list unused indexes for database ID: %d", id)).SetInternal(err)
+		}
+		unusedIndexList := []*api.UnusedIndex{}
+		for _, index := range unusedIndexes {
+			unusedIndexList = append(unusedIndexList, &api.UnusedIndex{
+				SchemaName:    index.SchemaName,
+				TableName:     index.TableName,
+				IndexName:     index.IndexName,
+				UnusedSinceTs: index.UnusedSince.Unix(),
+			})
+		}
+		return c.JSON(http.StatusOK, unusedIndexList)
+	})
+
+	g.GET("/database/:databaseID/row-level-security", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		if !common.FeatureFlag(common.FeatureFlagRowLevelSecurity) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Row level security is not supported yet")
+		}
+		// The row level security is synced with the schema, only for PostgreSQL for now.
+		rowLevelSecurityList := []*db.TableRowLevelSecurity{}
+		dbSchema, err := s.store.GetDBSchema(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get schema for database ID: %d", id)).SetInternal(err)
+		}
+		if dbSchema != nil && dbSchema.RowLevelSecurity != nil {
+			rowLevelSecurityList = dbSchema.RowLevelSecurity
+		}
+		return c.JSON(http.StatusOK, rowLevelSecurityList)
+	})
+
+	g.GET("/database/:databaseID/schema-drift/corrective-migration", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", database.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance %q not found", database.InstanceID))
+		}
+		var engine parser.EngineType
+		switch instance.Engine {
+		case db.Postgres:
+			engine = parser.Postgres
+		case db.MySQL, db.MariaDB:
+			engine = parser.MySQL
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Corrective migration is not supported for engine %s", instance.Engine))
+		}
+
+		normalRowStatus := api.Normal
+		anomalyList, err := s.store.ListAnomalyV2(ctx, &store.ListAnomalyMessage{
+			RowStatus:   &normalRowStatus,
+			DatabaseUID: &id,
+			Types:       []api.AnomalyType{api.AnomalyDatabaseSchemaDrift},
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list schema drift anomalies for database ID: %d", id)).SetInternal(err)
+		}
+		if len(anomalyList) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Schema drift not found for database %q", database.DatabaseName))
+		}
+		var payload api.AnomalyDatabaseSchemaDriftPayload
+		if err := json.Unmarshal([]byte(anomalyList[0].Payload), &payload); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmarshal schema drift payload").SetInternal(err)
+		}
+		// The corrective migration reverts the actual schema to the expected one.
+		statement, err := differ.SchemaDiff(engine, payload.Actual, payload.Expect)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to compute corrective migration for database %q", database.DatabaseName)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, &api.SchemaDriftCorrectiveMigration{Statement: statement})
+	})
+
+	g.POST("/database/:databaseID/zero-downtime-migration/preview", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", database.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance %q not found", database.InstanceID))
+		}
+		if instance.Engine != db.Postgres {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Zero-downtime migration is only supported for PostgreSQL, got %s", instance.Engine))
+		}
+
+		preview := &api.ZeroDowntimeMigrationPreview{}
+		if err := json.NewDecoder(c.Request().Body).Decode(preview); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed zero-downtime migration preview request").SetInternal(err)
+		}
+		// The engine version of PostgreSQL instances is synced from server_version_num, e.g. 140005.
+		serverVersionNum, err := strconv.Atoi(instance.EngineVersion)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid engine version %q of instance %q, sync the instance and try again", instance.EngineVersion, instance.ResourceID)).SetInternal(err)
+		}
+		steps, err := pg.TransformZeroDowntimeMigration(preview.Statement, serverVersionNum, preview.BatchSize)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to transform statement: %v", err)).SetInternal(err)
+		}
+		stepList := []*api.ZeroDowntimeMigrationStep{}
+		for _, step := range steps {
+			stepList = append(stepList, &api.ZeroDowntimeMigrationStep{
+				Statement: step.Statement,
+				Batched:   step.Batched,
+				Rewritten: step.Rewritten,
+			})
+		}
+		return c.JSON(http.StatusOK, stepList)
+	})
+
+	g.POST("/database/:databaseID/seed-data/preview", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", database.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance %q not found", database.InstanceID))
+		}
+
+		config := &api.SeedDataConfig{}
+		if err := json.NewDecoder(c.Request().Body).Decode(config); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed seed data preview request").SetInternal(err)
+		}
+		driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, database.DatabaseName)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to connect database %q", database.DatabaseName)).SetInternal(err)
+		}
+		defer driver.Close(ctx)
+		change, err := utils.GenerateSeedDataChange(ctx, driver, instance.Engine, config)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to generate the seed data change: %v", err)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, change)
+	})
+
+	// The change history import converts the history of Flyway or Liquibase into the change history, so the databases
+	// migrated to Bytebase keep the versions of the applied changes.
+	g.POST("/database/:databaseID/change-history/import", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		historyImport := &api.ChangeHistoryImport{}
+		if err := json.NewDecoder(c.Request().Body).Decode(historyImport); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed change history import request").SetInternal(err)
+		}
+		if historyImport.Source != db.Flyway && historyImport.Source != db.Liquibase {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported change history source %q", historyImport.Source))
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", database.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance %q not found", database.InstanceID))
+		}
+		switch instance.Engine {
+		case db.MySQL, db.TiDB, db.MariaDB, db.Postgres:
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Change history import is not supported for %s", instance.Engine))
+		}
+
+		// The imported history only precedes the changes made in Bytebase, and re-importing only appends the new changes.
+		historyList, err := s.store.ListInstanceChangeHistory(ctx, &store.FindInstanceChangeHistoryMessage{
+			InstanceID: &instance.UID,
+			DatabaseID: &database.UID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list change history for database %q", database.DatabaseName)).SetInternal(err)
+		}
+		existingVersions := make(map[string]bool)
+		for _, history := range historyList {
+			if history.Source != historyImport.Source {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q already has change history from %s, only the databases without the change history made in Bytebase can be imported", database.DatabaseName, history.Source))
+			}
+			existingVersions[history.Version] = true
+		}
+
+		table := historyImport.Table
+		if table == "" {
+			table = utils.DefaultChangeHistoryTable(historyImport.Source, instance.Engine)
+		}
+		driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, database.DatabaseName)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to connect database %q", database.DatabaseName)).SetInternal(err)
+		}
+		defer driver.Close(ctx)
+		changes, skippedList, err := utils.ReadChangeHistory(ctx, instance.Engine, driver.GetDB(), historyImport.Source, table)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		// The tools don't keep the schema of each change, so the schema of the last change is the current schema.
+		var schema strings.Builder
+		if _, err := driver.Dump(ctx, &schema, true /* schemaOnly */); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to dump schema for database %q", database.DatabaseName)).SetInternal(err)
+		}
+
+		creatorID := c.Get(getPrincipalIDContextKey()).(int)
+		var creates []*store.InstanceChangeHistoryMessage
+		for _, change := range changes {
+			storedVersion, err := util.ToStoredVersion(false, change.Version, "")
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to convert version %q", change.Version)).SetInternal(err)
+			}
+			if existingVersions[storedVersion] {
+				continue
+			}
+			creates = append(creates, &store.InstanceChangeHistoryMessage{
+				CreatorID:           creatorID,
+				CreatedTs:           change.ExecutedTs,
+				ReleaseVersion:      s.profile.Version,
+				Source:              change.Source,
+				Type:                change.Type,
+				Status:              change.Status,
+				Version:             storedVersion,
+				Description:         change.Description,
+				Statement:           change.Statement,
+				ExecutionDurationNs: change.ExecutionDurationNs,
+			})
+		}
+		if len(creates) > 0 {
+			creates[len(creates)-1].Schema = schema.String()
+			creates[len(creates)-1].SchemaPrev = schema.String()
+		}
+		if _, err := s.store.CreateImportedInstanceChangeHistory(ctx, &instance.UID, &database.UID, creates); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to import change history for database %q", database.DatabaseName)).SetInternal(err)
+		}
+		if skippedList == nil {
+			skippedList = []string{}
+		}
+		return c.JSON(http.StatusOK, &api.ChangeHistoryImportResult{
+			ImportedCount: len(creates),
+			SkippedList:   skippedList,
+		})
+	})
+
+	// The change history export renders the change history as the Liquibase changelog or the Flyway scripts for the
+	// auditors and the other deployment systems.
+	g.GET("/database/:databaseID/change-history/export", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		format := utils.ChangeHistoryExportFormat(c.QueryParam("format"))
+		if format == "" {
+			format = utils.ChangeHistoryExportFormatLiquibase
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", database.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance %q not found", database.InstanceID))
+		}
+		historyList, err := s.store.ListInstanceChangeHistory(ctx, &store.FindInstanceChangeHistoryMessage{
+			InstanceID: &instance.UID,
+			DatabaseID: &database.UID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list change history for database %q", database.DatabaseName)).SetInternal(err)
+		}
+
+		authors := make(map[int]string)
+		var entries []*utils.ChangeHistoryExportEntry
+		for _, history := range historyList {
+			author, ok := authors[history.CreatorID]
+			if !ok {
+				user, err := s.store.GetUserByID(ctx, history.CreatorID)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get user %d", history.CreatorID)).SetInternal(err)
+				}
+				author = fmt.Sprintf("user %d", history.CreatorID)
+				if user != nil {
+					author = user.Email
+				}
+				authors[history.CreatorID] = author
+			}
+			_, version, _, err := util.FromStoredVersion(history.Version)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to convert stored version %q", history.Version)).SetInternal(err)
+			}
+			entries = append(entries, &utils.ChangeHistoryExportEntry{
+				Sequence:    history.Sequence,
+				Type:        history.Type,
+				Status:      history.Status,
+				Version:     version,
+				Description: history.Description,
+				Statement:   history.Statement,
+				Author:      author,
+				CreatedTs:   history.CreatedTs,
+			})
+		}
+		content, err := utils.ExportChangeHistory(entries, format)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		contentType, filename := echo.MIMEApplicationXMLCharsetUTF8, fmt.Sprintf("%s.changelog.xml", database.DatabaseName)
+		if format == utils.ChangeHistoryExportFormatFlyway {
+			contentType, filename = "application/zip", fmt.Sprintf("%s.flyway.zip", database.DatabaseName)
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+		return c.Blob(http.StatusOK, contentType, content)
+	})
+
+	g.GET("/database/:databaseID/tls", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		if !common.FeatureFlag(common.FeatureFlagDatabaseTLS) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Database TLS configuration is not supported yet")
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		databaseTLS, err := s.store.GetDatabaseTLS(ctx, &store.FindDatabaseTLSMessage{DatabaseUID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get TLS configuration for database ID: %d", id)).SetInternal(err)
+		}
+		apiDatabaseTLS, err := s.toAPIDatabaseTLS(databaseTLS)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to convert TLS configuration for database ID: %d", id)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, apiDatabaseTLS)
+	})
+
+	g.PATCH("/database/:databaseID/tls", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		if !common.FeatureFlag(common.FeatureFlagDatabaseTLS) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Database TLS configuration is not supported yet")
+		}
+
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read request body").SetInternal(err)
+		}
+		databaseTLSPatch := &api.DatabaseTLSPatch{}
+		if err := json.Unmarshal(body, databaseTLSPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch database TLS request").SetInternal(err)
+		}
+
+		databaseTLS, err := s.store.GetDatabaseTLS(ctx, &store.FindDatabaseTLSMessage{DatabaseUID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get TLS configuration for database ID: %d", id)).SetInternal(err)
+		}
+		if databaseTLS == nil {
+			databaseTLS = &store.DatabaseTLSMessage{DatabaseUID: id}
+		}
+		tlsConfig := db.TLSConfig{}
+		for _, field := range []struct {
+			patch      *string
+			obfuscated *string
+			value      *string
+		}{
+			{databaseTLSPatch.SslCa, &databaseTLS.ObfuscatedSslCa, &tlsConfig.SslCA},
+			{databaseTLSPatch.SslCert, &databaseTLS.ObfuscatedSslCert, &tlsConfig.SslCert},
+			{databaseTLSPatch.SslKey, &databaseTLS.ObfuscatedSslKey, &tlsConfig.SslKey},
+		} {
+			if field.patch != nil {
+				*field.obfuscated = common.Obfuscate(*field.patch, s.secret)
+			}
+			value, err := common.Unobfuscate(*field.obfuscated, s.secret)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unobfuscate TLS configuration for database ID: %d", id)).SetInternal(err)
+			}
+			*field.value = value
+		}
+		if err := validateDatabaseTLSConfig(tlsConfig); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		if tlsConfig.SslCA == "" && tlsConfig.SslCert == "" {
+			// Fall back to the TLS configuration of the instance data sources.
+			if err := s.store.DeleteDatabaseTLS(ctx, id); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete TLS configuration for database ID: %d", id)).SetInternal(err)
+			}
+			databaseTLS = nil
+		} else {
+			databaseTLS, err = s.store.UpsertDatabaseTLS(ctx, databaseTLS, c.Get(getPrincipalIDContextKey()).(int))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to upsert TLS configuration for database ID: %d", id)).SetInternal(err)
+			}
+		}
+		apiDatabaseTLS, err := s.toAPIDatabaseTLS(databaseTLS)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to convert TLS configuration for database ID: %d", id)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, apiDatabaseTLS)
+	})
+
 	g.GET("/database/:databaseID/data-source/:dataSourceID", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		databaseID, err := strconv.Atoi(c.Param("databaseID"))
@@ -521,6 +1269,29 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, dataSourceCreate); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create data source request").SetInternal(err)
 		}
+		if err := validateExternalSecretReference(dataSourceCreate.Options.ExternalSecretReference); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err := validateKerberosKeytab(dataSourceCreate.Options.KerberosKeytab); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err := validateReadReplicaRouting(dataSourceCreate.Type, &dataSourceCreate.Options); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err := validateConnectionPool(dataSourceCreate.Type, &dataSourceCreate.Options); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if dataSourceCreate.Options.ExternalSecretReference != "" {
+			// The data sources without the host and port connect to the ones of the admin data source.
+			origin := utils.DataSourceFromInstanceWithType(instance, api.Admin)
+			host, port := dataSourceCreate.Host, dataSourceCreate.Port
+			if origin != nil && host == "" && port == "" {
+				host, port = origin.Host, origin.Port
+			}
+			if err := checkExternalSecretReference(c.Get(getRoleContextKey()).(api.Role), dataSourceCreate.Options.ExternalSecretReference, origin, host, port); err != nil {
+				return err
+			}
+		}
 		if !s.licenseService.IsFeatureEnabled(api.FeatureReadReplicaConnection) && dataSourceCreate.Type == api.RO {
 			if dataSourceCreate.Host != "" || dataSourceCreate.Port != "" {
 				return echo.NewHTTPError(http.StatusForbidden, api.FeatureReadReplicaConnection.AccessErrorMessage())
@@ -531,27 +1302,62 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		title := api.AdminDataSourceName
 		if dataSourceCreate.Type == api.RO {
 			title = api.ReadOnlyDataSourceName
+			// The additional read-only data sources are the read replicas distinguished by their names.
+			if utils.DataSourceFromInstanceWithType(instance, api.RO) != nil {
+				if !s.licenseService.IsFeatureEnabled(api.FeatureReadReplicaConnection) {
+					return echo.NewHTTPError(http.StatusForbidden, api.FeatureReadReplicaConnection.AccessErrorMessage())
+				}
+				title = strings.TrimSpace(dataSourceCreate.Name)
+				if title == "" {
+					return echo.NewHTTPError(http.StatusBadRequest, "Name is required for the additional read-only data source")
+				}
+				for _, ds := range instance.DataSources {
+					if ds.Title == title {
+						return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Data source %q already exists", title))
+					}
+				}
+			}
 		}
 		dataSourceMessage := &store.DataSourceMessage{
-			Title:                   title,
-			Type:                    dataSourceCreate.Type,
-			Username:                dataSourceCreate.Username,
-			ObfuscatedPassword:      common.Obfuscate(dataSourceCreate.Password, s.secret),
-			ObfuscatedSslCa:         common.Obfuscate(dataSourceCreate.SslCa, s.secret),
-			ObfuscatedSslCert:       common.Obfuscate(dataSourceCreate.SslCert, s.secret),
-			ObfuscatedSslKey:        common.Obfuscate(dataSourceCreate.SslKey, s.secret),
-			Host:                    dataSourceCreate.Host,
-			Port:                    dataSourceCreate.Port,
-			Database:                dataSourceCreate.Database,
-			SRV:                     dataSourceCreate.Options.SRV,
-			AuthenticationDatabase:  dataSourceCreate.Options.AuthenticationDatabase,
-			SID:                     dataSourceCreate.Options.SID,
-			ServiceName:             dataSourceCreate.Options.ServiceName,
-			SSHHost:                 dataSourceCreate.Options.SSHHost,
-			SSHPort:                 dataSourceCreate.Options.SSHPort,
-			SSHUser:                 dataSourceCreate.Options.SSHUser,
-			SSHObfuscatedPassword:   common.Obfuscate(dataSourceCreate.Options.SSHPassword, s.secret),
-			SSHObfuscatedPrivateKey: common.Obfuscate(dataSourceCreate.Options.SSHPrivateKey, s.secret),
+			Title:                        title,
+			Type:                         dataSourceCreate.Type,
+			Username:                     dataSourceCreate.Username,
+			ObfuscatedPassword:           common.Obfuscate(dataSourceCreate.Password, s.secret),
+			ObfuscatedSslCa:              common.Obfuscate(dataSourceCreate.SslCa, s.secret),
+			ObfuscatedSslCert:            common.Obfuscate(dataSourceCreate.SslCert, s.secret),
+			ObfuscatedSslKey:             common.Obfuscate(dataSourceCreate.SslKey, s.secret),
+			Host:                         dataSourceCreate.Host,
+			Port:                         dataSourceCreate.Port,
+			Database:                     dataSourceCreate.Database,
+			SRV:                          dataSourceCreate.Options.SRV,
+			AuthenticationDatabase:       dataSourceCreate.Options.AuthenticationDatabase,
+			SID:                          dataSourceCreate.Options.SID,
+			ServiceName:                  dataSourceCreate.Options.ServiceName,
+			SSHHost:                      dataSourceCreate.Options.SSHHost,
+			SSHPort:                      dataSourceCreate.Options.SSHPort,
+			SSHUser:                      dataSourceCreate.Options.SSHUser,
+			SSHObfuscatedPassword:        common.Obfuscate(dataSourceCreate.Options.SSHPassword, s.secret),
+			SSHObfuscatedPrivateKey:      common.Obfuscate(dataSourceCreate.Options.SSHPrivateKey, s.secret),
+			Proxy:                        s.convertToDataSourceProxy(dataSourceCreate.Options.SSHJumpHostList, dataSourceCreate.Options.SOCKS5Proxy, nil),
+			AuthenticationType:           dataSourceCreate.Options.AuthenticationType,
+			AWSRegion:                    dataSourceCreate.Options.AWSRegion,
+			AWSAccessKeyID:               dataSourceCreate.Options.AWSAccessKeyID,
+			AWSObfuscatedSecretAccessKey: common.Obfuscate(dataSourceCreate.Options.AWSSecretAccessKey, s.secret),
+			GCPCloudSQLConnectionName:    dataSourceCreate.Options.GCPCloudSQLConnectionName,
+			GCPCloudSQLUsePrivateIP:      dataSourceCreate.Options.GCPCloudSQLUsePrivateIP,
+			GCPObfuscatedCredentialsJSON: common.Obfuscate(dataSourceCreate.Options.GCPCredentialsJSON, s.secret),
+			AzureTenantID:                dataSourceCreate.Options.AzureTenantID,
+			AzureClientID:                dataSourceCreate.Options.AzureClientID,
+			AzureObfuscatedClientSecret:  common.Obfuscate(dataSourceCreate.Options.AzureClientSecret, s.secret),
+			KerberosRealm:                dataSourceCreate.Options.KerberosRealm,
+			KerberosKDC:                  dataSourceCreate.Options.KerberosKDC,
+			KerberosSPN:                  dataSourceCreate.Options.KerberosSPN,
+			KerberosObfuscatedKeytab:     common.Obfuscate(dataSourceCreate.Options.KerberosKeytab, s.secret),
+			ExternalSecretReference:      dataSourceCreate.Options.ExternalSecretReference,
+			ReadReplicaRoutingPolicy:     dataSourceCreate.Options.ReadReplicaRoutingPolicy,
+			ReadReplicaFallbackToPrimary: dataSourceCreate.Options.ReadReplicaFallbackToPrimary,
+			MaxConnections:               dataSourceCreate.Options.MaxConnections,
+			ConnectionIdleTimeoutSeconds: dataSourceCreate.Options.ConnectionIdleTimeoutSeconds,
 		}
 		if err := s.store.AddDataSourceToInstanceV2(ctx, instance.UID, creatorID, instance.EnvironmentID, instance.ResourceID, dataSourceMessage); err != nil {
 			return err
@@ -563,7 +1369,7 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		}
 		var composedDataSource *api.DataSource
 		for _, ds := range composedInstance.DataSourceList {
-			if ds.Type == dataSourceCreate.Type {
+			if ds.Name == title {
 				composedDataSource = ds
 				break
 			}
@@ -621,6 +1427,37 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, dataSourcePatch); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch data source request").SetInternal(err)
 		}
+		if dataSourcePatch.Options != nil {
+			if err := validateExternalSecretReference(dataSourcePatch.Options.ExternalSecretReference); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			if err := validateKerberosKeytab(dataSourcePatch.Options.KerberosKeytab); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			if err := validateReadReplicaRouting(dataSource.Type, dataSourcePatch.Options); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			if err := validateConnectionPool(dataSource.Type, dataSourcePatch.Options); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			if dataSourcePatch.Options.ReadReplicaRoutingPolicy != api.ReadReplicaRoutingUnspecified && !s.licenseService.IsFeatureEnabled(api.FeatureReadReplicaConnection) {
+				return echo.NewHTTPError(http.StatusForbidden, api.FeatureReadReplicaConnection.AccessErrorMessage())
+			}
+		}
+		// The stored external secret reference is checked as well, so that it can't be used with the patched host.
+		reference, host, port := dataSource.ExternalSecretReference, dataSource.Host, dataSource.Port
+		if dataSourcePatch.Options != nil {
+			reference = dataSourcePatch.Options.ExternalSecretReference
+		}
+		if dataSourcePatch.Host != nil {
+			host = *dataSourcePatch.Host
+		}
+		if dataSourcePatch.Port != nil {
+			port = *dataSourcePatch.Port
+		}
+		if err := checkExternalSecretReference(c.Get(getRoleContextKey()).(api.Role), reference, dataSource, host, port); err != nil {
+			return err
+		}
 		if dataSource.Type == api.RO && !s.licenseService.IsFeatureEnabled(api.FeatureReadReplicaConnection) {
 			if (dataSourcePatch.Host != nil && *dataSourcePatch.Host != "") || (dataSourcePatch.Port != nil && *dataSourcePatch.Port != "") {
 				return echo.NewHTTPError(http.StatusForbidden, api.FeatureReadReplicaConnection.AccessErrorMessage())
@@ -633,6 +1470,7 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			EnvironmentID: instance.EnvironmentID,
 			InstanceID:    instance.ResourceID,
 			Type:          dataSource.Type,
+			UID:           &dataSource.UID,
 			Username:      dataSourcePatch.Username,
 			Host:          dataSourcePatch.Host,
 			Port:          dataSourcePatch.Port,
@@ -664,6 +1502,52 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			updateMessage.AuthenticationDatabase = &dataSourcePatch.Options.AuthenticationDatabase
 			updateMessage.SID = &dataSourcePatch.Options.SID
 			updateMessage.ServiceName = &dataSourcePatch.Options.ServiceName
+			updateMessage.SSHHost = &dataSourcePatch.Options.SSHHost
+			updateMessage.SSHPort = &dataSourcePatch.Options.SSHPort
+			updateMessage.SSHUser = &dataSourcePatch.Options.SSHUser
+			if dataSourcePatch.Options.SSHPassword != "" {
+				obfuscated := common.Obfuscate(dataSourcePatch.Options.SSHPassword, s.secret)
+				updateMessage.SSHObfuscatedPassword = &obfuscated
+			}
+			if dataSourcePatch.Options.SSHPrivateKey != "" {
+				obfuscated := common.Obfuscate(dataSourcePatch.Options.SSHPrivateKey, s.secret)
+				updateMessage.SSHObfuscatedPrivateKey = &obfuscated
+			}
+			proxy := s.convertToDataSourceProxy(dataSourcePatch.Options.SSHJumpHostList, dataSourcePatch.Options.SOCKS5Proxy, &dataSource.Proxy)
+			updateMessage.Proxy = &proxy
+			updateMessage.AuthenticationType = &dataSourcePatch.Options.AuthenticationType
+			updateMessage.AWSRegion = &dataSourcePatch.Options.AWSRegion
+			updateMessage.AWSAccessKeyID = &dataSourcePatch.Options.AWSAccessKeyID
+			if dataSourcePatch.Options.AWSSecretAccessKey != "" {
+				obfuscated := common.Obfuscate(dataSourcePatch.Options.AWSSecretAccessKey, s.secret)
+				updateMessage.AWSObfuscatedSecretAccessKey = &obfuscated
+			}
+			updateMessage.GCPCloudSQLConnectionName = &dataSourcePatch.Options.GCPCloudSQLConnectionName
+			updateMessage.GCPCloudSQLUsePrivateIP = &dataSourcePatch.Options.GCPCloudSQLUsePrivateIP
+			if dataSourcePatch.Options.GCPCredentialsJSON != "" {
+				obfuscated := common.Obfuscate(dataSourcePatch.Options.GCPCredentialsJSON, s.secret)
+				updateMessage.GCPObfuscatedCredentialsJSON = &obfuscated
+			}
+			updateMessage.AzureTenantID = &dataSourcePatch.Options.AzureTenantID
+			updateMessage.AzureClientID = &dataSourcePatch.Options.AzureClientID
+			if dataSourcePatch.Options.AzureClientSecret != "" {
+				obfuscated := common.Obfuscate(dataSourcePatch.Options.AzureClientSecret, s.secret)
+				updateMessage.AzureObfuscatedClientSecret = &obfuscated
+			}
+			updateMessage.KerberosRealm = &dataSourcePatch.Options.KerberosRealm
+			updateMessage.KerberosKDC = &dataSourcePatch.Options.KerberosKDC
+			updateMessage.KerberosSPN = &dataSourcePatch.Options.KerberosSPN
+			if dataSourcePatch.Options.KerberosKeytab != "" {
+				obfuscated := common.Obfuscate(dataSourcePatch.Options.KerberosKeytab, s.secret)
+				updateMessage.KerberosObfuscatedKeytab = &obfuscated
+			}
+			updateMessage.ExternalSecretReference = &dataSourcePatch.Options.ExternalSecretReference
+			if dataSource.Type == api.Admin {
+				updateMessage.ReadReplicaRoutingPolicy = &dataSourcePatch.Options.ReadReplicaRoutingPolicy
+				updateMessage.ReadReplicaFallbackToPrimary = &dataSourcePatch.Options.ReadReplicaFallbackToPrimary
+				updateMessage.MaxConnections = &dataSourcePatch.Options.MaxConnections
+				updateMessage.ConnectionIdleTimeoutSeconds = &dataSourcePatch.Options.ConnectionIdleTimeoutSeconds
+			}
 		}
 		if err := s.store.UpdateDataSourceV2(ctx, updateMessage); err != nil {
 			return err
@@ -689,7 +1573,7 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		}
 		var composedDataSource *api.DataSource
 		for _, ds := range composedInstance.DataSourceList {
-			if ds.Type == dataSource.Type {
+			if ds.ID == dataSource.UID {
 				composedDataSource = ds
 				break
 			}
@@ -745,7 +1629,7 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		if dataSource.Type == api.Admin {
 			return echo.NewHTTPError(http.StatusBadRequest, "admin data source cannot be deleted")
 		}
-		if err := s.store.RemoveDataSourceV2(ctx, instance.UID, instance.EnvironmentID, instance.ResourceID, dataSource.Type); err != nil {
+		if err := s.store.RemoveDataSourceByUIDV2(ctx, instance.UID, instance.EnvironmentID, instance.ResourceID, dataSource.UID); err != nil {
 			return err
 		}
 
@@ -859,3 +1743,44 @@ func createTransferProjectActivity(ctx context.Context, stores *store.Store, dat
 	}
 	return nil
 }
+
+func (s *Server) toAPIDatabaseTLS(databaseTLS *store.DatabaseTLSMessage) (*api.DatabaseTLS, error) {
+	apiDatabaseTLS := &api.DatabaseTLS{}
+	if databaseTLS == nil {
+		return apiDatabaseTLS, nil
+	}
+	apiDatabaseTLS.HasSslCa = databaseTLS.ObfuscatedSslCa != ""
+	apiDatabaseTLS.HasSslCert = databaseTLS.ObfuscatedSslCert != ""
+	for _, obfuscated := range []string{databaseTLS.ObfuscatedSslCa, databaseTLS.ObfuscatedSslCert} {
+		pemBundle, err := common.Unobfuscate(obfuscated, s.secret)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := db.GetEarliestExpiringCertificate(pemBundle)
+		if err != nil {
+			return nil, err
+		}
+		if cert != nil && (apiDatabaseTLS.CertificateExpireTs == 0 || cert.NotAfter.Unix() < apiDatabaseTLS.CertificateExpireTs) {
+			apiDatabaseTLS.CertificateExpireTs = cert.NotAfter.Unix()
+		}
+	}
+	return apiDatabaseTLS, nil
+}
+
+// validateDatabaseTLSConfig validates the PEM of the CA bundle and the client certificate key pair.
+func validateDatabaseTLSConfig(tlsConfig db.TLSConfig) error {
+	if tlsConfig.SslCA != "" {
+		if ok := x509.NewCertPool().AppendCertsFromPEM([]byte(tlsConfig.SslCA)); !ok {
+			return errors.Errorf("invalid CA certificate")
+		}
+	}
+	if (tlsConfig.SslCert == "") != (tlsConfig.SslKey == "") {
+		return errors.Errorf("ssl-cert and ssl-key must be both set or unset")
+	}
+	if tlsConfig.SslCert != "" {
+		if _, err := tls.X509KeyPair([]byte(tlsConfig.SslCert), []byte(tlsConfig.SslKey)); err != nil {
+			return errors.Wrap(err, "invalid client certificate and key")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/runner/databaseclone"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func (s *Server) registerDatabaseCloneRoutes(g *echo.Group) {
+	g.POST("/database-clone", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if !s.licenseService.IsFeatureEnabled(api.FeatureSensitiveData) {
+			return echo.NewHTTPError(http.StatusForbidden, api.FeatureSensitiveData.AccessErrorMessage())
+		}
+		create := &api.DatabaseCloneCreate{}
+		if err := json.NewDecoder(c.Request().Body).Decode(create); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create database clone request").SetInternal(err)
+		}
+		if err := create.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		source, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &create.SourceDatabaseID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", create.SourceDatabaseID)).SetInternal(err)
+		}
+		if source == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", create.SourceDatabaseID))
+		}
+		sourceInstance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &source.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", source.InstanceID)).SetInternal(err)
+		}
+		targetInstance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &create.TargetInstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", create.TargetInstanceID)).SetInternal(err)
+		}
+		if sourceInstance == nil || targetInstance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, "Instance not found")
+		}
+		if !databaseclone.IsSupported(sourceInstance.Engine) || sourceInstance.Engine != targetInstance.Engine {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cloning database from %s to %s is not supported", sourceInstance.Engine, targetInstance.Engine))
+		}
+		environment, err := s.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{ResourceID: &targetInstance.EnvironmentID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch environment %q", targetInstance.EnvironmentID)).SetInternal(err)
+		}
+		if environment == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Environment %q not found", targetInstance.EnvironmentID))
+		}
+		// The clones are for the lower environments, the protected ones hold the production data already.
+		if environment.Protected {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cannot clone database into the protected environment %q", environment.Title))
+		}
+		existing, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{InstanceID: &targetInstance.ResourceID, DatabaseName: &create.TargetDatabaseName})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database %q", create.TargetDatabaseName)).SetInternal(err)
+		}
+		if existing != nil {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Database %q already exists in instance %q", create.TargetDatabaseName, targetInstance.Title))
+		}
+
+		clone, err := s.store.CreateDatabaseClone(ctx, &store.DatabaseCloneMessage{
+			SourceDatabaseUID:  source.UID,
+			TargetInstanceUID:  targetInstance.UID,
+			TargetDatabaseName: create.TargetDatabaseName,
+		}, principalID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create database clone").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIDatabaseClone(clone))
+	})
+
+	g.GET("/database-clone", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		find := &store.FindDatabaseCloneMessage{}
+		if status := c.QueryParam("status"); status != "" {
+			find.StatusList = []api.DatabaseCloneStatus{api.DatabaseCloneStatus(status)}
+		}
+		clones, err := s.store.ListDatabaseClones(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list database clones").SetInternal(err)
+		}
+		cloneList := []*api.DatabaseClone{}
+		for _, clone := range clones {
+			cloneList = append(cloneList, toAPIDatabaseClone(clone))
+		}
+		return c.JSON(http.StatusOK, cloneList)
+	})
+
+	g.GET("/database-clone/:cloneID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("cloneID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("cloneID"))).SetInternal(err)
+		}
+		clones, err := s.store.ListDatabaseClones(ctx, &store.FindDatabaseCloneMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get database clone %d", id)).SetInternal(err)
+		}
+		if len(clones) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database clone %d not found", id))
+		}
+		return c.JSON(http.StatusOK, toAPIDatabaseClone(clones[0]))
+	})
+}
+
+func toAPIDatabaseClone(clone *store.DatabaseCloneMessage) *api.DatabaseClone {
+	return &api.DatabaseClone{
+		ID:                 clone.UID,
+		CreatorID:          clone.CreatorID,
+		CreatedTs:          clone.CreatedTs,
+		UpdaterID:          clone.UpdaterID,
+		UpdatedTs:          clone.UpdatedTs,
+		SourceDatabaseID:   clone.SourceDatabaseUID,
+		TargetInstanceID:   clone.TargetInstanceUID,
+		TargetDatabaseName: clone.TargetDatabaseName,
+		Status:             clone.Status,
+		TableCount:         clone.TableCount,
+		RowCount:           clone.RowCount,
+		Error:              clone.Error,
+	}
+}
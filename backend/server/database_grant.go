@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func (s *Server) registerDatabaseGrantRoutes(g *echo.Group) {
+	g.POST("/database-grant", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+		create := &api.DatabaseGrantCreate{}
+		if err := json.NewDecoder(c.Request().Body).Decode(create); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create database grant request").SetInternal(err)
+		}
+		if err := create.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if role == api.Owner || role == api.DBA {
+			return echo.NewHTTPError(http.StatusBadRequest, "Workspace Owners and DBAs already have the access to all databases")
+		}
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &create.DatabaseID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", create.DatabaseID)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", create.DatabaseID))
+		}
+
+		grant, err := s.store.CreateDatabaseGrant(ctx, &store.DatabaseGrantMessage{
+			DatabaseUID:     database.UID,
+			Level:           create.Level,
+			DurationSeconds: create.DurationSeconds,
+			Justification:   create.Justification,
+		}, principalID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create database grant").SetInternal(err)
+		}
+		if err := s.ActivityManager.CreateDatabaseGrantActivity(ctx, grant, ""); err != nil {
+			log.Error("Failed to create database grant activity", zap.Int("grant", grant.UID), zap.Error(err))
+		}
+		return c.JSON(http.StatusOK, toAPIDatabaseGrant(grant))
+	})
+
+	g.GET("/database-grant", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+		find := &store.FindDatabaseGrantMessage{}
+		// Developers can only see their own grants.
+		if role != api.Owner && role != api.DBA {
+			find.CreatorID = &principalID
+		}
+		if databaseIDStr := c.QueryParam("database"); databaseIDStr != "" {
+			databaseID, err := strconv.Atoi(databaseIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter database is not a number: %s", databaseIDStr)).SetInternal(err)
+			}
+			find.DatabaseUID = &databaseID
+		}
+		if status := c.QueryParam("status"); status != "" {
+			find.StatusList = []api.DatabaseGrantStatus{api.DatabaseGrantStatus(status)}
+		}
+		grants, err := s.store.ListDatabaseGrants(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list database grants").SetInternal(err)
+		}
+		grantList := []*api.DatabaseGrant{}
+		for _, grant := range grants {
+			grantList = append(grantList, toAPIDatabaseGrant(grant))
+		}
+		return c.JSON(http.StatusOK, grantList)
+	})
+
+	g.POST("/database-grant/:grantID/approve", func(c echo.Context) error {
+		return s.updateDatabaseGrantStatus(c, api.DatabaseGrantPending, api.DatabaseGrantActive)
+	})
+
+	g.POST("/database-grant/:grantID/reject", func(c echo.Context) error {
+		return s.updateDatabaseGrantStatus(c, api.DatabaseGrantPending, api.DatabaseGrantRejected)
+	})
+
+	g.POST("/database-grant/:grantID/revoke", func(c echo.Context) error {
+		return s.updateDatabaseGrantStatus(c, api.DatabaseGrantActive, api.DatabaseGrantRevoked)
+	})
+}
+
+// updateDatabaseGrantStatus moves the database grant from the old status to the new one, the approved grant is revoked
+// automatically after its duration.
+func (s *Server) updateDatabaseGrantStatus(c echo.Context, oldStatus, newStatus api.DatabaseGrantStatus) error {
+	ctx := c.Request().Context()
+	principalID := c.Get(getPrincipalIDContextKey()).(int)
+	id, err := strconv.Atoi(c.Param("grantID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("grantID"))).SetInternal(err)
+	}
+	grants, err := s.store.ListDatabaseGrants(ctx, &store.FindDatabaseGrantMessage{UID: &id})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get database grant %d", id)).SetInternal(err)
+	}
+	if len(grants) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database grant %d not found", id))
+	}
+	if grants[0].Status != oldStatus {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database grant %d is %s", id, grants[0].Status))
+	}
+
+	update := &store.UpdateDatabaseGrantMessage{
+		UpdaterID: principalID,
+		OldStatus: oldStatus,
+		Status:    newStatus,
+	}
+	if newStatus == api.DatabaseGrantActive {
+		expireTs := time.Now().Unix() + grants[0].DurationSeconds
+		update.ExpireTs = &expireTs
+	}
+	grant, err := s.store.UpdateDatabaseGrant(ctx, id, update)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update database grant %d", id)).SetInternal(err)
+	}
+	if grant == nil {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Database grant %d has been updated in the meantime", id))
+	}
+	if err := s.ActivityManager.CreateDatabaseGrantActivity(ctx, grant, oldStatus); err != nil {
+		log.Error("Failed to create database grant activity", zap.Int("grant", grant.UID), zap.Error(err))
+	}
+	return c.JSON(http.StatusOK, toAPIDatabaseGrant(grant))
+}
+
+// checkAdminExecuteAccess checks whether the principal can execute the admin statements on the database. The users
+// other than the workspace Owners and DBAs need the temporary admin access to the database, and they are denied if the
+// database grants are not enabled.
+func (s *Server) checkAdminExecuteAccess(ctx context.Context, principalID int, role api.Role, database *store.DatabaseMessage) error {
+	if role == api.Owner || role == api.DBA {
+		return nil
+	}
+	if !common.FeatureFlag(common.FeatureFlagDatabaseGrant) {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("No admin access to database %q", database.DatabaseName))
+	}
+	granted, err := s.hasActiveDatabaseGrant(ctx, principalID, database.UID, api.DatabaseGrantAdmin)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check the database grants").SetInternal(err)
+	}
+	if !granted {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("No admin access to database %q", database.DatabaseName))
+	}
+	return nil
+}
+
+// hasActiveDatabaseGrant returns whether the principal is granted the level of the temporary access to the database.
+// The admin access implies the querier access.
+func (s *Server) hasActiveDatabaseGrant(ctx context.Context, principalID int, databaseUID int, level api.DatabaseGrantLevel) (bool, error) {
+	if !common.FeatureFlag(common.FeatureFlagDatabaseGrant) {
+		return false, nil
+	}
+	grants, err := s.store.ListDatabaseGrants(ctx, &store.FindDatabaseGrantMessage{
+		DatabaseUID: &databaseUID,
+		CreatorID:   &principalID,
+		StatusList:  []api.DatabaseGrantStatus{api.DatabaseGrantActive},
+	})
+	if err != nil {
+		return false, err
+	}
+	// The grants are revoked by the runner periodically, so the expiry is checked here as well.
+	now := time.Now().Unix()
+	for _, grant := range grants {
+		if grant.ExpireTs <= now {
+			continue
+		}
+		if grant.Level == api.DatabaseGrantAdmin || grant.Level == level {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func toAPIDatabaseGrant(grant *store.DatabaseGrantMessage) *api.DatabaseGrant {
+	return &api.DatabaseGrant{
+		ID:              grant.UID,
+		CreatorID:       grant.CreatorID,
+		CreatedTs:       grant.CreatedTs,
+		UpdaterID:       grant.UpdaterID,
+		UpdatedTs:       grant.UpdatedTs,
+		DatabaseID:      grant.DatabaseUID,
+		Level:           grant.Level,
+		Status:          grant.Status,
+		DurationSeconds: grant.DurationSeconds,
+		Justification:   grant.Justification,
+		ExpireTs:        grant.ExpireTs,
+	}
+}
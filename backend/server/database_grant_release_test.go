@@ -0,0 +1,31 @@
+//go:build release
+// +build release
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func TestCheckAdminExecuteAccessWithoutDatabaseGrant(t *testing.T) {
+	a := require.New(t)
+	// The database grants are never checked in the release build, so the server doesn't need a store.
+	s := &Server{}
+	database := &store.DatabaseMessage{UID: 101, DatabaseName: "employee"}
+
+	a.NoError(s.checkAdminExecuteAccess(context.Background(), 101, api.Owner, database))
+	a.NoError(s.checkAdminExecuteAccess(context.Background(), 102, api.DBA, database))
+
+	err := s.checkAdminExecuteAccess(context.Background(), 103, api.Developer, database)
+	httpErr, ok := err.(*echo.HTTPError)
+	a.True(ok, err)
+	a.Equal(http.StatusForbidden, httpErr.Code)
+}
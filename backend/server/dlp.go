@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// enforceDLP restricts the exported result to the clearance of the requester. The result is queried again with the
+// statement on the server, as the provenance of the result columns is inspected on the statement, so the client
+// can't export the rows different from the inspected ones. The columns derived from the columns classified above the
+// clearance, through the views, the aliases, the subqueries and the CTEs, are blocked or redacted by the policy.
+func (s *Server) enforceDLP(ctx context.Context, principalID int, role api.Role, instance *store.InstanceMessage, database *store.DatabaseMessage, policy *api.DLPPolicy, clearance int, export *api.SQLResultExport) error {
+	databaseList := []string{database.DatabaseName}
+	switch instance.Engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		if export.Statement == "" {
+			break
+		}
+		list, err := parser.ExtractDatabaseList(parser.MySQL, export.Statement)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to get database list: %s", export.Statement)).SetInternal(err)
+		}
+		databaseList = list
+	}
+	schemaInfo, restricted, err := s.getDLPSchemaInfo(ctx, instance, databaseList, database.DatabaseName, policy, clearance)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the classified columns").SetInternal(err)
+	}
+	if !restricted {
+		return nil
+	}
+	if export.Statement == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The statement is required to export the results of database %q with the classified columns", database.DatabaseName))
+	}
+	// The result is blocked if its provenance can't be inspected.
+	fields, err := util.ExtractSensitiveFields(instance.Engine, export.Statement, database.DatabaseName, schemaInfo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Failed to inspect the column provenance of the result: %v", err)).SetInternal(err)
+	}
+
+	sensitiveSchemaInfo, queryLimit, err := s.checkSQLEditorQuery(ctx, principalID, role, instance, database.DatabaseName, export.Statement)
+	if err != nil {
+		return err
+	}
+	result, err := s.queryRows(ctx, instance, database.DatabaseName, export.Statement, sensitiveSchemaInfo, queryLimit, len(export.Rows))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to query the result to export: %v", err)).SetInternal(err)
+	}
+	if len(fields) != len(result.columnNames) {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Failed to inspect the column provenance of the result, got %d fields for %d columns", len(fields), len(result.columnNames)))
+	}
+	var restrictedNames []string
+	for i, field := range fields {
+		if field.Sensitive {
+			restrictedNames = append(restrictedNames, result.columnNames[i])
+		}
+	}
+	if len(restrictedNames) > 0 && policy.Action == api.DLPActionBlock {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Columns %s are classified above your clearance", strings.Join(restrictedNames, ", ")))
+	}
+
+	sensitiveList := result.sensitiveList
+	if sensitiveList == nil {
+		sensitiveList = make([]bool, len(result.columnNames))
+	}
+	for i, field := range fields {
+		if !field.Sensitive {
+			continue
+		}
+		sensitiveList[i] = true
+		for _, row := range result.rows {
+			row[i] = nil
+		}
+	}
+	export.ColumnNames = result.columnNames
+	export.ColumnTypeNames = result.columnTypeNames
+	export.Rows = result.rows
+	export.SensitiveList = sensitiveList
+	return nil
+}
+
+// getDLPSchemaInfo returns the schema info of the databases, in which the columns classified above the clearance are
+// sensitive, and whether there is any such column. The views are included with the columns derived from the tables,
+// the views that can't be inspected are left out, and the statements querying them fail to be inspected.
+func (s *Server) getDLPSchemaInfo(ctx context.Context, instance *store.InstanceMessage, databaseList []string, currentDatabase string, policy *api.DLPPolicy, clearance int) (*db.SensitiveSchemaInfo, bool, error) {
+	type viewInfo struct {
+		databaseIndex int
+		name          string
+		definition    string
+	}
+	schemaInfo := &db.SensitiveSchemaInfo{DatabaseList: []db.DatabaseSchema{}}
+	restricted := false
+	var views []*viewInfo
+	for _, name := range databaseList {
+		databaseName := name
+		if databaseName == "" {
+			databaseName = currentDatabase
+		}
+		if databaseName == "" || isExcludeDatabase(instance.Engine, databaseName) {
+			continue
+		}
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{InstanceID: &instance.ResourceID, DatabaseName: &databaseName})
+		if err != nil {
+			return nil, false, err
+		}
+		if database == nil {
+			return nil, false, errors.Errorf("database %q not found", databaseName)
+		}
+		levels, err := s.getDLPColumnLevels(ctx, policy, database.UID)
+		if err != nil {
+			return nil, false, err
+		}
+		dbSchema, err := s.store.GetDBSchema(ctx, database.UID)
+		if err != nil {
+			return nil, false, err
+		}
+		if dbSchema == nil || dbSchema.Metadata == nil {
+			return nil, false, errors.Errorf("schema of database %q not found", databaseName)
+		}
+
+		databaseSchema := db.DatabaseSchema{Name: databaseName, TableList: []db.TableSchema{}}
+		for _, schema := range dbSchema.Metadata.Schemas {
+			for _, table := range schema.Tables {
+				tableSchema := db.TableSchema{Name: table.Name, ColumnList: []db.ColumnInfo{}}
+				if instance.Engine == db.Postgres {
+					tableSchema.Name = fmt.Sprintf("%s.%s", schema.Name, table.Name)
+				}
+				for _, column := range table.Columns {
+					sensitive := levels[api.SensitiveData{Schema: schema.Name, Table: table.Name, Column: column.Name}] > clearance
+					restricted = restricted || sensitive
+					tableSchema.ColumnList = append(tableSchema.ColumnList, db.ColumnInfo{Name: column.Name, Sensitive: sensitive})
+				}
+				databaseSchema.TableList = append(databaseSchema.TableList, tableSchema)
+			}
+			for _, view := range schema.Views {
+				name := view.Name
+				if instance.Engine == db.Postgres {
+					name = fmt.Sprintf("%s.%s", schema.Name, view.Name)
+				}
+				views = append(views, &viewInfo{databaseIndex: len(schemaInfo.DatabaseList), name: name, definition: view.Definition})
+			}
+		}
+		schemaInfo.DatabaseList = append(schemaInfo.DatabaseList, databaseSchema)
+	}
+	if !restricted {
+		return schemaInfo, false, nil
+	}
+
+	// The views may depend on the other views, so they're inspected until no more view can be.
+	for len(views) > 0 {
+		var pending []*viewInfo
+		for _, view := range views {
+			databaseName := schemaInfo.DatabaseList[view.databaseIndex].Name
+			fields, err := util.ExtractSensitiveFields(instance.Engine, view.definition, databaseName, schemaInfo)
+			if err != nil || len(fields) == 0 {
+				pending = append(pending, view)
+				continue
+			}
+			tableSchema := db.TableSchema{Name: view.name, ColumnList: []db.ColumnInfo{}}
+			for _, field := range fields {
+				tableSchema.ColumnList = append(tableSchema.ColumnList, db.ColumnInfo{Name: field.Name, Sensitive: field.Sensitive})
+			}
+			schemaInfo.DatabaseList[view.databaseIndex].TableList = append(schemaInfo.DatabaseList[view.databaseIndex].TableList, tableSchema)
+		}
+		if len(pending) == len(views) {
+			break
+		}
+		views = pending
+	}
+	return schemaInfo, true, nil
+}
+
+// getDLPColumnLevels returns the classification levels of the columns of the database, from the PII findings and the
+// columns classified in the policy.
+func (s *Server) getDLPColumnLevels(ctx context.Context, policy *api.DLPPolicy, databaseUID int) (map[api.SensitiveData]int, error) {
+	levels := make(map[api.SensitiveData]int)
+	findings, err := s.listPIIFindings(ctx, &store.FindPIIFindingMessage{DatabaseUID: &databaseUID})
+	if err != nil {
+		return nil, err
+	}
+	// The proposed findings are restricted as well before they're reviewed.
+	for _, finding := range findings {
+		if finding.Status == api.PIIFindingDismissed {
+			continue
+		}
+		key := api.SensitiveData{Schema: finding.SchemaName, Table: finding.TableName, Column: finding.ColumnName}
+		if level := policy.GetClassificationLevel(finding.Classification); level > levels[key] {
+			levels[key] = level
+		}
+	}
+	for _, column := range policy.ColumnList {
+		if column.DatabaseID != databaseUID {
+			continue
+		}
+		levels[api.SensitiveData{Schema: column.Schema, Table: column.Table, Column: column.Column}] = column.Level
+	}
+	return levels, nil
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func (s *Server) registerExportRequestRoutes(g *echo.Group) {
+	g.POST("/export-request", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+		create := &api.ExportRequestCreate{}
+		if err := json.NewDecoder(c.Request().Body).Decode(create); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create export request").SetInternal(err)
+		}
+		if err := create.Validate(); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if role == api.Owner || role == api.DBA {
+			return echo.NewHTTPError(http.StatusBadRequest, "Workspace Owners and DBAs don't need the export approval")
+		}
+		database, err := s.getExportableDatabase(ctx, principalID, role, create.DatabaseID)
+		if err != nil {
+			return err
+		}
+
+		request, err := s.store.CreateExportRequest(ctx, &store.ExportRequestMessage{
+			DatabaseUID:    database.UID,
+			RowCount:       create.RowCount,
+			ColumnNameList: create.ColumnNameList,
+			Justification:  create.Justification,
+		}, principalID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create export request").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIExportRequest(request))
+	})
+
+	g.GET("/export-request", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+		find := &store.FindExportRequestMessage{}
+		// Developers can only see their own requests.
+		if role != api.Owner && role != api.DBA {
+			find.CreatorID = &principalID
+		}
+		if status := c.QueryParam("status"); status != "" {
+			find.StatusList = []api.ExportRequestStatus{api.ExportRequestStatus(status)}
+		}
+		requests, err := s.store.ListExportRequests(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list export requests").SetInternal(err)
+		}
+		requestList := []*api.ExportRequest{}
+		for _, request := range requests {
+			requestList = append(requestList, toAPIExportRequest(request))
+		}
+		return c.JSON(http.StatusOK, requestList)
+	})
+
+	g.POST("/export-request/:requestID/approve", func(c echo.Context) error {
+		return s.updateExportRequestStatus(c, api.ExportRequestApproved)
+	})
+
+	g.POST("/export-request/:requestID/reject", func(c echo.Context) error {
+		return s.updateExportRequestStatus(c, api.ExportRequestRejected)
+	})
+}
+
+// updateExportRequestStatus approves or rejects the pending export request, the approved request expires after
+// api.ExportRequestValidDuration.
+func (s *Server) updateExportRequestStatus(c echo.Context, newStatus api.ExportRequestStatus) error {
+	ctx := c.Request().Context()
+	principalID := c.Get(getPrincipalIDContextKey()).(int)
+	id, err := strconv.Atoi(c.Param("requestID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("requestID"))).SetInternal(err)
+	}
+	update := &store.UpdateExportRequestMessage{
+		UpdaterID: principalID,
+		OldStatus: api.ExportRequestPending,
+		Status:    newStatus,
+	}
+	if newStatus == api.ExportRequestApproved {
+		expireTs := time.Now().Add(api.ExportRequestValidDuration).Unix()
+		update.ExpireTs = &expireTs
+	}
+	request, err := s.store.UpdateExportRequest(ctx, id, update)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update export request %d", id)).SetInternal(err)
+	}
+	if request == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Export request %d not found or not pending", id))
+	}
+	return c.JSON(http.StatusOK, toAPIExportRequest(request))
+}
+
+// getApprovedExportRequest returns the approved export request of the principal allowing the export.
+func (s *Server) getApprovedExportRequest(ctx context.Context, principalID int, export *api.SQLResultExport) (*store.ExportRequestMessage, error) {
+	if export.ExportRequestID == 0 {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "Exporting the result requires the export approval")
+	}
+	requests, err := s.store.ListExportRequests(ctx, &store.FindExportRequestMessage{UID: &export.ExportRequestID, CreatorID: &principalID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get export request %d", export.ExportRequestID)).SetInternal(err)
+	}
+	if len(requests) == 0 {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Export request %d not found", export.ExportRequestID))
+	}
+	request := requests[0]
+	if request.DatabaseUID != export.DatabaseID || !toAPIExportRequest(request).Allows(len(export.Rows), export.ColumnNames, time.Now()) {
+		return nil, echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Export request %d doesn't allow exporting the result", export.ExportRequestID))
+	}
+	return request, nil
+}
+
+func toAPIExportRequest(request *store.ExportRequestMessage) *api.ExportRequest {
+	return &api.ExportRequest{
+		ID:             request.UID,
+		CreatorID:      request.CreatorID,
+		CreatedTs:      request.CreatedTs,
+		UpdaterID:      request.UpdaterID,
+		UpdatedTs:      request.UpdatedTs,
+		DatabaseID:     request.DatabaseUID,
+		Status:         request.Status,
+		RowCount:       request.RowCount,
+		ColumnNameList: request.ColumnNameList,
+		Justification:  request.Justification,
+		ExpireTs:       request.ExpireTs,
+	}
+}
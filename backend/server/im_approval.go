@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gosimple/slug"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// imIssueStatus is the status of the issue looked up from the IM apps.
+type imIssueStatus struct {
+	issue *store.IssueMessage
+	link  string
+	// approval is the status of the approval flow, e.g. "Pending step 1 of 2".
+	approval string
+	// approvable is whether the approval flow has a pending step.
+	approvable bool
+	// tasks is the count of the tasks by status, e.g. "1 DONE, 2 PENDING_APPROVAL".
+	tasks string
+}
+
+// handleIMApprovalAction approves the pending approval step of the issue as the user mapped from the IM user. It
+// returns the message to the IM user. There is no rejected state of the approval steps, so the IM apps only approve.
+func (s *Server) handleIMApprovalAction(ctx context.Context, user *store.UserMessage, issueID int) (string, error) {
+	issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: &issueID})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get issue %d", issueID)
+	}
+	if issue == nil {
+		return fmt.Sprintf("Issue %d not found.", issueID), nil
+	}
+	if issue.Status != api.IssueOpen {
+		return fmt.Sprintf("Issue %q is no longer open.", issue.Title), nil
+	}
+
+	// Go through the approval API as the user, so that the permission checks and the activities are the same.
+	principalCtx := context.WithValue(ctx, common.PrincipalIDContextKey, user.ID)
+	if _, err := s.reviewService.ApproveReview(principalCtx, &v1pb.ApproveReviewRequest{
+		Name: fmt.Sprintf("projects/%s/reviews/%d", issue.Project.ResourceID, issue.UID),
+	}); err != nil {
+		switch status.Code(err) {
+		case codes.InvalidArgument, codes.FailedPrecondition, codes.PermissionDenied:
+			return fmt.Sprintf("Cannot approve issue %q: %s.", issue.Title, status.Convert(err).Message()), nil
+		}
+		return "", err
+	}
+	return fmt.Sprintf("Approved issue %q as %s.", issue.Title, user.Email), nil
+}
+
+// getIMIssueStatus returns the status of the issue, it's nil if the issue is not found.
+func (s *Server) getIMIssueStatus(ctx context.Context, issueID int) (*imIssueStatus, error) {
+	issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{UID: &issueID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get issue %d", issueID)
+	}
+	if issue == nil {
+		return nil, nil
+	}
+	generalSetting, err := s.store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get workspace setting")
+	}
+	issueStatus := &imIssueStatus{
+		issue: issue,
+		link:  fmt.Sprintf("%s/issue/%s-%d", generalSetting.ExternalUrl, slug.Make(issue.Title), issue.UID),
+	}
+	issueStatus.approval, issueStatus.approvable = getIMApprovalStatus(issue)
+	if issue.PipelineUID != 0 {
+		tasks, err := s.store.ListTasks(ctx, &api.TaskFind{PipelineID: &issue.PipelineUID})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list tasks of issue %d", issue.UID)
+		}
+		var statusList []api.TaskStatus
+		statusCount := make(map[api.TaskStatus]int)
+		for _, task := range tasks {
+			if statusCount[task.Status] == 0 {
+				statusList = append(statusList, task.Status)
+			}
+			statusCount[task.Status]++
+		}
+		var counts []string
+		for _, taskStatus := range statusList {
+			counts = append(counts, fmt.Sprintf("%d %s", statusCount[taskStatus], taskStatus))
+		}
+		issueStatus.tasks = strings.Join(counts, ", ")
+	}
+	return issueStatus, nil
+}
+
+// parseIMIssueID parses the issue ID of "<verb> <issue ID>", the "#" of the ID is optional. The verb is optional if
+// it's the default verb.
+func parseIMIssueID(text, verb, defaultVerb string) (int, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 2 && fields[0] == verb {
+		fields = fields[1:]
+	} else if verb != defaultVerb {
+		return 0, false
+	}
+	if len(fields) != 1 {
+		return 0, false
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(fields[0], "#"))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// getIMApproval returns the approval of the issue with the approval flow found, it's nil otherwise.
+func getIMApproval(issue *store.IssueMessage) (*storepb.IssuePayloadApproval, error) {
+	payload := &storepb.IssuePayload{}
+	if err := protojson.Unmarshal([]byte(issue.Payload), payload); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal issue payload")
+	}
+	approval := payload.Approval
+	if approval == nil || !approval.ApprovalFindingDone || approval.ApprovalFindingError != "" || len(approval.ApprovalTemplates) != 1 {
+		return nil, nil
+	}
+	return approval, nil
+}
+
+func getIMApprovalStatus(issue *store.IssueMessage) (string, bool) {
+	payload := &storepb.IssuePayload{}
+	if err := protojson.Unmarshal([]byte(issue.Payload), payload); err != nil {
+		return "Unknown", false
+	}
+	approval := payload.Approval
+	if approval == nil || !approval.ApprovalFindingDone {
+		return "Finding the approval flow", false
+	}
+	if approval.ApprovalFindingError != "" {
+		return "Failed to find the approval flow", false
+	}
+	if len(approval.ApprovalTemplates) != 1 {
+		return "Not required", false
+	}
+	if utils.FindNextPendingStep(approval.ApprovalTemplates[0], approval.Approvers) == nil {
+		return "Approved", false
+	}
+	return fmt.Sprintf("Pending step %d of %d", len(approval.Approvers)+1, len(approval.ApprovalTemplates[0].GetFlow().GetSteps())), issue.Status == api.IssueOpen
+}
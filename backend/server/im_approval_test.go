@@ -0,0 +1,39 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIMIssueID(t *testing.T) {
+	tests := []struct {
+		text string
+		verb string
+		id   int
+		ok   bool
+	}{
+		{text: "status 123", id: 123, ok: true},
+		{text: "  status   #123 ", id: 123, ok: true},
+		{text: "123", id: 123, ok: true},
+		{text: "#123", id: 123, ok: true},
+		{text: "", ok: false},
+		{text: "status", ok: false},
+		{text: "status abc", ok: false},
+		{text: "status 0", ok: false},
+		{text: "approve 123", ok: false},
+		{text: "approve 123", verb: "approve", id: 123, ok: true},
+		{text: "approve #123", verb: "approve", id: 123, ok: true},
+		{text: "123", verb: "approve", ok: false},
+		{text: "status 123", verb: "approve", ok: false},
+	}
+	for _, test := range tests {
+		verb := test.verb
+		if verb == "" {
+			verb = "status"
+		}
+		id, ok := parseIMIssueID(test.text, verb, "status")
+		require.Equal(t, test.ok, ok, test.text)
+		require.Equal(t, test.id, id, test.text)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,6 +15,8 @@ import (
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	"github.com/bytebase/bytebase/backend/component/externalsecret"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	metricAPI "github.com/bytebase/bytebase/backend/metric"
 	"github.com/bytebase/bytebase/backend/plugin/db"
@@ -23,6 +26,7 @@ import (
 	"github.com/bytebase/bytebase/backend/plugin/parser/sql/transform"
 	"github.com/bytebase/bytebase/backend/resources/postgres"
 	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
 )
 
 // pgConnectionInfo represents the embedded postgres instance connection info.
@@ -52,8 +56,14 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 		if !isValidResourceID(instanceCreate.ResourceID) {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid instance id %s", instanceCreate.ResourceID))
 		}
-		if instanceCreate.Engine != db.Postgres && instanceCreate.Engine != db.MongoDB && instanceCreate.Engine != db.Redshift && instanceCreate.Database != "" {
-			return echo.NewHTTPError(http.StatusBadRequest, "database parameter is only allowed for Postgres and MongoDB")
+		if instanceCreate.Engine != db.Postgres && instanceCreate.Engine != db.MongoDB && instanceCreate.Engine != db.Redshift && instanceCreate.Engine != db.Cassandra && instanceCreate.Engine != db.DynamoDB && instanceCreate.Engine != db.Neo4j && instanceCreate.Database != "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "database parameter is only allowed for Postgres, MongoDB, Redshift, Cassandra, DynamoDB and Neo4j")
+		}
+		if err := validateExternalSecretReference(instanceCreate.ExternalSecretReference); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err := validateKerberosKeytab(instanceCreate.KerberosKeytab); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
 		environment, err := s.store.GetEnvironmentByID(ctx, instanceCreate.EnvironmentID)
 		if err != nil {
@@ -70,25 +80,41 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 			ExternalLink: instanceCreate.ExternalLink,
 			DataSources: []*store.DataSourceMessage{
 				{
-					Title:                   api.AdminDataSourceName,
-					Type:                    api.Admin,
-					Username:                instanceCreate.Username,
-					ObfuscatedPassword:      common.Obfuscate(instanceCreate.Password, s.secret),
-					ObfuscatedSslCa:         common.Obfuscate(instanceCreate.SslCa, s.secret),
-					ObfuscatedSslCert:       common.Obfuscate(instanceCreate.SslCert, s.secret),
-					ObfuscatedSslKey:        common.Obfuscate(instanceCreate.SslKey, s.secret),
-					Host:                    instanceCreate.Host,
-					Port:                    instanceCreate.Port,
-					Database:                instanceCreate.Database,
-					SRV:                     instanceCreate.SRV,
-					AuthenticationDatabase:  instanceCreate.AuthenticationDatabase,
-					SID:                     instanceCreate.SID,
-					ServiceName:             instanceCreate.ServiceName,
-					SSHHost:                 instanceCreate.SSHHost,
-					SSHPort:                 instanceCreate.SSHPort,
-					SSHUser:                 instanceCreate.SSHUser,
-					SSHObfuscatedPassword:   common.Obfuscate(instanceCreate.SSHPassword, s.secret),
-					SSHObfuscatedPrivateKey: common.Obfuscate(instanceCreate.SSHPrivateKey, s.secret),
+					Title:                        api.AdminDataSourceName,
+					Type:                         api.Admin,
+					Username:                     instanceCreate.Username,
+					ObfuscatedPassword:           common.Obfuscate(instanceCreate.Password, s.secret),
+					ObfuscatedSslCa:              common.Obfuscate(instanceCreate.SslCa, s.secret),
+					ObfuscatedSslCert:            common.Obfuscate(instanceCreate.SslCert, s.secret),
+					ObfuscatedSslKey:             common.Obfuscate(instanceCreate.SslKey, s.secret),
+					Host:                         instanceCreate.Host,
+					Port:                         instanceCreate.Port,
+					Database:                     instanceCreate.Database,
+					SRV:                          instanceCreate.SRV,
+					AuthenticationDatabase:       instanceCreate.AuthenticationDatabase,
+					SID:                          instanceCreate.SID,
+					ServiceName:                  instanceCreate.ServiceName,
+					SSHHost:                      instanceCreate.SSHHost,
+					SSHPort:                      instanceCreate.SSHPort,
+					SSHUser:                      instanceCreate.SSHUser,
+					SSHObfuscatedPassword:        common.Obfuscate(instanceCreate.SSHPassword, s.secret),
+					SSHObfuscatedPrivateKey:      common.Obfuscate(instanceCreate.SSHPrivateKey, s.secret),
+					Proxy:                        s.convertToDataSourceProxy(instanceCreate.SSHJumpHostList, instanceCreate.SOCKS5Proxy, nil),
+					AuthenticationType:           instanceCreate.AuthenticationType,
+					AWSRegion:                    instanceCreate.AWSRegion,
+					AWSAccessKeyID:               instanceCreate.AWSAccessKeyID,
+					AWSObfuscatedSecretAccessKey: common.Obfuscate(instanceCreate.AWSSecretAccessKey, s.secret),
+					GCPCloudSQLConnectionName:    instanceCreate.GCPCloudSQLConnectionName,
+					GCPCloudSQLUsePrivateIP:      instanceCreate.GCPCloudSQLUsePrivateIP,
+					GCPObfuscatedCredentialsJSON: common.Obfuscate(instanceCreate.GCPCredentialsJSON, s.secret),
+					AzureTenantID:                instanceCreate.AzureTenantID,
+					AzureClientID:                instanceCreate.AzureClientID,
+					AzureObfuscatedClientSecret:  common.Obfuscate(instanceCreate.AzureClientSecret, s.secret),
+					KerberosRealm:                instanceCreate.KerberosRealm,
+					KerberosKDC:                  instanceCreate.KerberosKDC,
+					KerberosSPN:                  instanceCreate.KerberosSPN,
+					KerberosObfuscatedKeytab:     common.Obfuscate(instanceCreate.KerberosKeytab, s.secret),
+					ExternalSecretReference:      instanceCreate.ExternalSecretReference,
 				},
 			},
 		}, creator)
@@ -267,6 +293,71 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 		return nil
 	})
 
+	// Check the health of the read-only data sources, which also refreshes the health used by the read replica routing.
+	g.GET("/instance/:instanceID/read-replica-health", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", id)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance not found with ID %d", id))
+		}
+
+		healthList := []*api.ReadReplicaHealth{}
+		for dataSourceUID, health := range s.dbFactory.CheckReadReplicaHealth(ctx, instance) {
+			healthList = append(healthList, &api.ReadReplicaHealth{
+				DataSourceID: dataSourceUID,
+				Healthy:      health.Healthy,
+				LatencyMs:    health.Latency.Milliseconds(),
+				CheckedTs:    health.CheckedTs,
+				Error:        health.Error,
+			})
+		}
+		sort.Slice(healthList, func(i, j int) bool {
+			return healthList[i].DataSourceID < healthList[j].DataSourceID
+		})
+		return c.JSON(http.StatusOK, healthList)
+	})
+
+	g.GET("/instance/:instanceID/connection-pool", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", id)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance not found with ID %d", id))
+		}
+
+		stats := s.dbFactory.GetConnectionPoolStats(instance.ResourceID)
+		if adminDataSource := utils.DataSourceFromInstanceWithType(instance, api.Admin); adminDataSource != nil {
+			// The pool is created on the first connection, so take the limit from the data source.
+			stats.MaxConnections = adminDataSource.MaxConnections
+		}
+		return c.JSON(http.StatusOK, &api.ConnectionPoolStats{
+			MaxConnections: stats.MaxConnections,
+			InUse:          stats.InUse,
+			Idle:           stats.Idle,
+			Waiting:        stats.Waiting,
+			OpenCount:      stats.OpenCount,
+			ReuseCount:     stats.ReuseCount,
+			WaitCount:      stats.WaitCount,
+			WaitDurationMs: stats.WaitDuration.Milliseconds(),
+			TimeoutCount:   stats.TimeoutCount,
+		})
+	})
+
 	g.GET("/instance/:instanceID/user/:userID", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		instanceID, err := strconv.Atoi(c.Param("instanceID"))
@@ -381,6 +472,10 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 			switch instance.Engine {
 			case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 				engineType = parser.MySQL
+			case db.Oracle:
+				engineType = parser.Oracle
+			case db.MSSQL:
+				engineType = parser.MSSQL
 			default:
 				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Not support SDL format for %s instance", instance.Engine))
 			}
@@ -540,3 +635,110 @@ func (s *Server) disallowBytebaseStore(engine db.Type, host, port string) error
 	}
 	return nil
 }
+
+// validateExternalSecretReference validates the reference to the external secret if it's set.
+func validateExternalSecretReference(reference string) error {
+	if reference == "" {
+		return nil
+	}
+	_, err := externalsecret.ParseReference(reference)
+	return err
+}
+
+// checkExternalSecretReference checks whether the principal can connect to the host with the credential of the external
+// secret reference. Bytebase resolves the references with its own credentials, so they are only used with the host of
+// the existing data source, and only the workspace Owners and DBAs can set the references other than the stored one.
+func checkExternalSecretReference(role api.Role, reference string, origin *store.DataSourceMessage, host, port string) error {
+	if reference == "" {
+		return nil
+	}
+	if origin == nil || host != origin.Host || port != origin.Port {
+		return echo.NewHTTPError(http.StatusBadRequest, "External secret reference can only be used with the host of the existing data source")
+	}
+	if reference != origin.ExternalSecretReference && role != api.Owner && role != api.DBA {
+		return echo.NewHTTPError(http.StatusForbidden, "Only the workspace Owners and DBAs can set the external secret reference")
+	}
+	return nil
+}
+
+// validateKerberosKeytab validates the base64-encoded Kerberos keytab if it's set.
+func validateKerberosKeytab(keytab string) error {
+	if keytab == "" {
+		return nil
+	}
+	_, err := dbfactory.GetKerberosConfig("", "", "", keytab)
+	return err
+}
+
+// validateReadReplicaRouting validates the read replica routing options, which are used for the admin data source only.
+func validateReadReplicaRouting(dataSourceType api.DataSourceType, options *api.DataSourceOptions) error {
+	if options.ReadReplicaRoutingPolicy == api.ReadReplicaRoutingUnspecified && !options.ReadReplicaFallbackToPrimary {
+		return nil
+	}
+	if dataSourceType != api.Admin {
+		return errors.Errorf("read replica routing can only be configured on the admin data source")
+	}
+	switch options.ReadReplicaRoutingPolicy {
+	case api.ReadReplicaRoutingUnspecified, api.ReadReplicaRoutingRoundRobin, api.ReadReplicaRoutingNearest:
+	default:
+		return errors.Errorf("invalid read replica routing policy %q", options.ReadReplicaRoutingPolicy)
+	}
+	return nil
+}
+
+// validateConnectionPool validates the connection pool options, which are used for the admin data source only.
+func validateConnectionPool(dataSourceType api.DataSourceType, options *api.DataSourceOptions) error {
+	if options.MaxConnections == 0 && options.ConnectionIdleTimeoutSeconds == 0 {
+		return nil
+	}
+	if dataSourceType != api.Admin {
+		return errors.Errorf("connection pool can only be configured on the admin data source")
+	}
+	// A task may hold two connections to the instance at the same time, e.g. syncing the schema while migrating.
+	if options.MaxConnections < 0 || options.MaxConnections == 1 {
+		return errors.Errorf("max connections must be 0 for unlimited or at least 2, got %d", options.MaxConnections)
+	}
+	if options.ConnectionIdleTimeoutSeconds < 0 {
+		return errors.Errorf("connection idle timeout must not be negative, got %d", options.ConnectionIdleTimeoutSeconds)
+	}
+	return nil
+}
+
+// convertToDataSourceProxy obfuscates the proxy chain in the API message.
+// Since the secrets are never sent back to the client, a jump host or SOCKS5 proxy with empty secrets
+// keeps the secrets of the origin one at the same position if its address and user are unchanged.
+func (s *Server) convertToDataSourceProxy(jumpHostList []api.SSHJumpHost, socks5 *api.SOCKS5Proxy, origin *store.DataSourceProxy) store.DataSourceProxy {
+	var proxy store.DataSourceProxy
+	for i, jumpHost := range jumpHostList {
+		obfuscatedPassword := common.Obfuscate(jumpHost.Password, s.secret)
+		obfuscatedPrivateKey := common.Obfuscate(jumpHost.PrivateKey, s.secret)
+		if jumpHost.Password == "" && jumpHost.PrivateKey == "" && origin != nil && i < len(origin.SSHJumpHostList) {
+			if o := origin.SSHJumpHostList[i]; o.Host == jumpHost.Host && o.Port == jumpHost.Port && o.User == jumpHost.User {
+				obfuscatedPassword = o.ObfuscatedPassword
+				obfuscatedPrivateKey = o.ObfuscatedPrivateKey
+			}
+		}
+		proxy.SSHJumpHostList = append(proxy.SSHJumpHostList, &store.SSHJumpHost{
+			Host:                 jumpHost.Host,
+			Port:                 jumpHost.Port,
+			User:                 jumpHost.User,
+			ObfuscatedPassword:   obfuscatedPassword,
+			ObfuscatedPrivateKey: obfuscatedPrivateKey,
+		})
+	}
+	if socks5 != nil && socks5.Host != "" {
+		obfuscatedPassword := common.Obfuscate(socks5.Password, s.secret)
+		if socks5.Password == "" && origin != nil && origin.SOCKS5 != nil {
+			if o := origin.SOCKS5; o.Host == socks5.Host && o.Port == socks5.Port && o.User == socks5.User {
+				obfuscatedPassword = o.ObfuscatedPassword
+			}
+		}
+		proxy.SOCKS5 = &store.SOCKS5Proxy{
+			Host:               socks5.Host,
+			Port:               socks5.Port,
+			User:               socks5.User,
+			ObfuscatedPassword: obfuscatedPassword,
+		}
+	}
+	return proxy
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func TestCheckExternalSecretReference(t *testing.T) {
+	origin := &store.DataSourceMessage{Host: "10.0.0.1", Port: "5432", ExternalSecretReference: "vault://secret/data/prod#password"}
+	tests := []struct {
+		desc      string
+		role      api.Role
+		reference string
+		origin    *store.DataSourceMessage
+		host      string
+		port      string
+		code      int
+	}{
+		{"no reference", api.Developer, "", nil, "attacker.example.com", "5432", 0},
+		{"stored reference", api.Developer, origin.ExternalSecretReference, origin, "10.0.0.1", "5432", 0},
+		{"new reference by developer", api.Developer, "vault://secret/data/other#password", origin, "10.0.0.1", "5432", http.StatusForbidden},
+		{"new reference by DBA", api.DBA, "vault://secret/data/other#password", origin, "10.0.0.1", "5432", 0},
+		{"stored reference with another host", api.Developer, origin.ExternalSecretReference, origin, "attacker.example.com", "5432", http.StatusBadRequest},
+		{"new reference with another port by owner", api.Owner, "vault://secret/data/other#password", origin, "10.0.0.1", "6543", http.StatusBadRequest},
+		{"reference without data source", api.Owner, origin.ExternalSecretReference, nil, "10.0.0.1", "5432", http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		err := checkExternalSecretReference(test.role, test.reference, test.origin, test.host, test.port)
+		if test.code == 0 {
+			require.NoError(t, err, test.desc)
+			continue
+		}
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok, test.desc)
+		require.Equal(t, test.code, httpErr.Code, test.desc)
+	}
+}
@@ -813,6 +813,20 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 	if int64(databaseIDCount) > maximumTaskLimit {
 		return nil, echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Current plan can update up to %d databases, got %d.", maximumTaskLimit, databaseIDCount))
 	}
+	if c.RolloutStrategy != nil {
+		if project.TenantMode != api.TenantModeTenant {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Rollout strategy is only supported for the tenant mode project")
+		}
+		if issueCreate.Type == api.IssueDatabaseSchemaUpdateGhost {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Rollout strategy is not supported for the gh-ost issue")
+		}
+		if c.RolloutStrategy.CanaryPercentage < 0 || c.RolloutStrategy.CanaryPercentage > 100 {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Canary percentage should be between 0 and 100, got %d", c.RolloutStrategy.CanaryPercentage))
+		}
+		if c.RolloutStrategy.MaxParallelism < 0 || c.RolloutStrategy.StopOnFailureCount < 0 {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Max parallelism and stop on failure count should not be negative")
+		}
+	}
 
 	// aggregatedMatrix is the aggregated matrix by deployments.
 	// databaseToMigrationList is the mapping from database ID to migration detail.
@@ -827,6 +841,13 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 	for _, database := range databases {
 		databaseMap[database.UID] = database
 	}
+	if c.RolloutStrategy != nil {
+		for _, id := range c.RolloutStrategy.CanaryDatabaseIDList {
+			if _, ok := databaseMap[id]; !ok {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Canary database ID %d not found in project %d", id, issueCreate.ProjectID))
+			}
+		}
+	}
 
 	if databaseIDCount == 0 {
 		// Deploy to all tenant databases.
@@ -900,6 +921,10 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 				environmentID = database.EnvironmentID
 
 				schemaVersion := common.DefaultMigrationVersion()
+				onlineMigrationConfig, err := s.getOnlineMigrationConfig(ctx, database.EnvironmentID, c.OnlineMigrationConfig)
+				if err != nil {
+					return nil, err
+				}
 				migrationDetailList := databaseToMigrationList[database.UID]
 				for _, migrationDetail := range migrationDetailList {
 					instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{
@@ -909,7 +934,7 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 					if err != nil {
 						return nil, err
 					}
-					taskCreateList, taskIndexDAGList, err := createGhostTaskList(database, instance, c.VCSPushEvent, migrationDetail, schemaVersion)
+					taskCreateList, taskIndexDAGList, err := createGhostTaskList(database, instance, c.VCSPushEvent, migrationDetail, schemaVersion, onlineMigrationConfig)
 					if err != nil {
 						return nil, err
 					}
@@ -938,25 +963,21 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 	create := &api.PipelineCreate{
 		Name: "Change database pipeline",
 	}
-	for i, databaseList := range aggregatedMatrix {
-		// Skip the stage if the stage includes no database.
-		if len(databaseList) == 0 {
-			continue
-		}
+	appendStage := func(name string, databaseList []*store.DatabaseMessage) error {
 		var environmentID string
 		var taskCreateList []api.TaskCreate
 		var taskIndexDAGList []api.TaskIndexDAG
 		for _, database := range databaseList {
 			if environmentID != "" && environmentID != database.EnvironmentID {
-				return nil, echo.NewHTTPError(http.StatusInternalServerError, "all databases in a stage should have the same environment")
+				return echo.NewHTTPError(http.StatusInternalServerError, "all databases in a stage should have the same environment")
 			}
 			environmentID = database.EnvironmentID
 			instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{EnvironmentID: &database.EnvironmentID, ResourceID: &database.InstanceID})
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if instance == nil {
-				return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("instance not found for database %v", database.UID))
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("instance not found for database %v", database.UID))
 			}
 
 			migrationDetailList := databaseToMigrationList[database.UID]
@@ -967,9 +988,9 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 				taskIndexDAGList = append(taskIndexDAGList, api.TaskIndexDAG{FromIndex: len(taskCreateList) + i, ToIndex: len(taskCreateList) + i + 1})
 			}
 			for _, migrationDetail := range migrationDetailList {
-				taskCreate, err := getUpdateTask(database, instance, c.VCSPushEvent, migrationDetail, getOrDefaultSchemaVersion(migrationDetail))
+				taskCreate, err := getUpdateTask(database, instance, c.VCSPushEvent, migrationDetail, getOrDefaultSchemaVersion(migrationDetail), c.RolloutStrategy, c.ManualRollout)
 				if err != nil {
-					return nil, err
+					return err
 				}
 				taskCreateList = append(taskCreateList, taskCreate)
 			}
@@ -977,14 +998,33 @@ func (s *Server) getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx context.Con
 
 		environment, err := s.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{ResourceID: &environmentID})
 		if err != nil {
-			return nil, err
+			return err
 		}
 		create.StageList = append(create.StageList, api.StageCreate{
-			Name:             deploySchedule.Deployments[i].Name,
+			Name:             name,
 			EnvironmentID:    environment.UID,
 			TaskList:         taskCreateList,
 			TaskIndexDAGList: taskIndexDAGList,
 		})
+		return nil
+	}
+	for i, databaseList := range aggregatedMatrix {
+		// Skip the stage if the stage includes no database.
+		if len(databaseList) == 0 {
+			continue
+		}
+		name := deploySchedule.Deployments[i].Name
+		// The canaries are rolled out in a separate stage ahead, so the rest only starts after all canaries are done.
+		canaries, rest := utils.SplitCanaryDatabases(databaseList, c.RolloutStrategy)
+		if len(canaries) > 0 && len(rest) > 0 {
+			if err := appendStage(fmt.Sprintf("%s (canary)", name), canaries); err != nil {
+				return nil, err
+			}
+			databaseList = rest
+		}
+		if err := appendStage(name, databaseList); err != nil {
+			return nil, err
+		}
 	}
 	return create, nil
 }
@@ -996,7 +1036,7 @@ func getOrDefaultSchemaVersion(detail *api.MigrationDetail) string {
 	return common.DefaultMigrationVersion()
 }
 
-func getUpdateTask(database *store.DatabaseMessage, instance *store.InstanceMessage, vcsPushEvent *vcs.PushEvent, d *api.MigrationDetail, schemaVersion string) (api.TaskCreate, error) {
+func getUpdateTask(database *store.DatabaseMessage, instance *store.InstanceMessage, vcsPushEvent *vcs.PushEvent, d *api.MigrationDetail, schemaVersion string, rolloutStrategy *api.RolloutStrategy, manualRollout bool) (api.TaskCreate, error) {
 	var taskName string
 	var taskType api.TaskType
 
@@ -1006,7 +1046,9 @@ func getUpdateTask(database *store.DatabaseMessage, instance *store.InstanceMess
 		taskName = fmt.Sprintf("Establish baseline for database %q", database.DatabaseName)
 		taskType = api.TaskDatabaseSchemaBaseline
 		payload := api.TaskDatabaseSchemaBaselinePayload{
-			SchemaVersion: schemaVersion,
+			SchemaVersion:   schemaVersion,
+			RolloutStrategy: rolloutStrategy,
+			ManualRollout:   manualRollout,
 		}
 		bytes, err := json.Marshal(payload)
 		if err != nil {
@@ -1016,10 +1058,32 @@ func getUpdateTask(database *store.DatabaseMessage, instance *store.InstanceMess
 	case db.Migrate:
 		taskName = fmt.Sprintf("DDL(schema) for database %q", database.DatabaseName)
 		taskType = api.TaskDatabaseSchemaUpdate
+		if d.ZeroDowntime != nil && instance.Engine != db.Postgres {
+			return api.TaskCreate{}, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Zero-downtime migration is only supported for PostgreSQL, got %s", instance.Engine))
+		}
+		if err := validateShadowDryRunConfig(instance, d.ShadowDryRun); err != nil {
+			return api.TaskCreate{}, err
+		}
+		if err := validateResumable(instance, d); err != nil {
+			return api.TaskCreate{}, err
+		}
+		if err := utils.ValidateMigrationHooks(d.Hooks); err != nil {
+			return api.TaskCreate{}, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid migration hooks: %v", err)).SetInternal(err)
+		}
+		if err := validateMigrationAssertions(instance, d.Assertions); err != nil {
+			return api.TaskCreate{}, err
+		}
 		payload := api.TaskDatabaseSchemaUpdatePayload{
-			SheetID:       d.SheetID,
-			SchemaVersion: schemaVersion,
-			VCSPushEvent:  vcsPushEvent,
+			SheetID:         d.SheetID,
+			SchemaVersion:   schemaVersion,
+			VCSPushEvent:    vcsPushEvent,
+			ZeroDowntime:    d.ZeroDowntime,
+			ShadowDryRun:    d.ShadowDryRun,
+			Resumable:       d.Resumable,
+			Hooks:           d.Hooks,
+			Assertions:      d.Assertions,
+			RolloutStrategy: rolloutStrategy,
+			ManualRollout:   manualRollout,
 		}
 		bytes, err := json.Marshal(payload)
 		if err != nil {
@@ -1030,9 +1094,11 @@ func getUpdateTask(database *store.DatabaseMessage, instance *store.InstanceMess
 		taskName = fmt.Sprintf("SDL for database %q", database.DatabaseName)
 		taskType = api.TaskDatabaseSchemaUpdateSDL
 		payload := api.TaskDatabaseSchemaUpdateSDLPayload{
-			SheetID:       d.SheetID,
-			SchemaVersion: schemaVersion,
-			VCSPushEvent:  vcsPushEvent,
+			SheetID:         d.SheetID,
+			SchemaVersion:   schemaVersion,
+			VCSPushEvent:    vcsPushEvent,
+			RolloutStrategy: rolloutStrategy,
+			ManualRollout:   manualRollout,
 		}
 		bytes, err := json.Marshal(payload)
 		if err != nil {
@@ -1042,12 +1108,53 @@ func getUpdateTask(database *store.DatabaseMessage, instance *store.InstanceMess
 	case db.Data:
 		taskName = fmt.Sprintf("DML(data) for database %q", database.DatabaseName)
 		taskType = api.TaskDatabaseDataUpdate
+		if d.Chunked != nil {
+			switch instance.Engine {
+			case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase, db.Postgres:
+			default:
+				return api.TaskCreate{}, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Chunked DML is not supported for %s", instance.Engine))
+			}
+			if d.RollbackEnabled || d.Seed != nil {
+				return api.TaskCreate{}, echo.NewHTTPError(http.StatusBadRequest, "Chunked DML cannot be used with the rollback SQL or the seed data change")
+			}
+		}
+		if d.Seed != nil {
+			if d.Seed.Table == "" || len(d.Seed.KeyColumns) == 0 {
+				return api.TaskCreate{}, echo.NewHTTPError(http.StatusBadRequest, "The table and key columns are required for the seed data change")
+			}
+			if _, err := utils.ParseSeedDataset(d.Seed.Format, d.Seed.Dataset); err != nil {
+				return api.TaskCreate{}, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid seed dataset: %v", err)).SetInternal(err)
+			}
+		}
+		if d.ShadowDryRun != nil && d.Seed != nil {
+			return api.TaskCreate{}, echo.NewHTTPError(http.StatusBadRequest, "Shadow database dry run cannot be used with the seed data change")
+		}
+		if err := validateShadowDryRunConfig(instance, d.ShadowDryRun); err != nil {
+			return api.TaskCreate{}, err
+		}
+		if err := validateResumable(instance, d); err != nil {
+			return api.TaskCreate{}, err
+		}
+		if err := utils.ValidateMigrationHooks(d.Hooks); err != nil {
+			return api.TaskCreate{}, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid migration hooks: %v", err)).SetInternal(err)
+		}
+		if err := validateMigrationAssertions(instance, d.Assertions); err != nil {
+			return api.TaskCreate{}, err
+		}
 		payload := api.TaskDatabaseDataUpdatePayload{
 			SheetID:           d.SheetID,
 			SchemaVersion:     schemaVersion,
 			VCSPushEvent:      vcsPushEvent,
 			RollbackEnabled:   d.RollbackEnabled,
 			RollbackSQLStatus: api.RollbackSQLStatusPending,
+			Chunked:           d.Chunked,
+			Seed:              d.Seed,
+			ShadowDryRun:      d.ShadowDryRun,
+			Resumable:         d.Resumable,
+			Hooks:             d.Hooks,
+			Assertions:        d.Assertions,
+			RolloutStrategy:   rolloutStrategy,
+			ManualRollout:     manualRollout,
 		}
 		if d.RollbackDetail != nil {
 			payload.RollbackFromIssueID = d.RollbackDetail.IssueID
@@ -1073,6 +1180,47 @@ func getUpdateTask(database *store.DatabaseMessage, instance *store.InstanceMess
 	}, nil
 }
 
+// validateResumable rejects the resumable migration with the executions running the statements in other ways.
+// The rollback SQL of MySQL relies on the statements running in one connection.
+func validateResumable(instance *store.InstanceMessage, d *api.MigrationDetail) error {
+	if !d.Resumable {
+		return nil
+	}
+	if !api.IsResumableMigrationSupported(instance.Engine) {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Resumable migration is not supported for %s", instance.Engine))
+	}
+	if d.ZeroDowntime != nil || d.Chunked != nil || d.Seed != nil || d.RollbackEnabled {
+		return echo.NewHTTPError(http.StatusBadRequest, "Resumable migration cannot be used with the zero-downtime migration, the chunked DML, the seed data change or the rollback SQL")
+	}
+	return nil
+}
+
+func validateMigrationAssertions(instance *store.InstanceMessage, assertions []*api.MigrationAssertion) error {
+	if len(assertions) == 0 {
+		return nil
+	}
+	if !api.IsMigrationAssertionSupported(instance.Engine) {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Migration assertions are not supported for %s", instance.Engine))
+	}
+	if err := utils.ValidateMigrationAssertions(assertions); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid migration assertions: %v", err)).SetInternal(err)
+	}
+	return nil
+}
+
+func validateShadowDryRunConfig(instance *store.InstanceMessage, config *api.ShadowDryRunConfig) error {
+	if config == nil {
+		return nil
+	}
+	if !api.IsShadowDryRunSupported(instance.Engine) {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Shadow database dry run is not supported for %s", instance.Engine))
+	}
+	if config.SampleRows < 0 || config.SampleRows > api.MaxShadowDryRunSampleRows {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The sample rows of the shadow database dry run should be between 0 and %d", api.MaxShadowDryRunSampleRows))
+	}
+	return nil
+}
+
 // createDatabaseCreateTaskList returns the task list for create database.
 func (s *Server) createDatabaseCreateTaskList(ctx context.Context, c api.CreateDatabaseContext, instance *store.InstanceMessage, project *store.ProjectMessage) ([]api.TaskCreate, error) {
 	if err := checkCharacterSetCollationOwner(instance.Engine, c.CharacterSet, c.Collation, c.Owner); err != nil {
@@ -1324,21 +1472,66 @@ func getCreateDatabaseStatement(dbType db.Type, createDatabaseContext api.Create
 	return "", errors.Errorf("unsupported database type %s", dbType)
 }
 
+// getOnlineMigrationConfig returns the online migration config of the environment overridden by the issue.
+func (s *Server) getOnlineMigrationConfig(ctx context.Context, environmentID string, override *api.OnlineMigrationConfig) (*api.OnlineMigrationConfig, error) {
+	environment, err := s.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{ResourceID: &environmentID})
+	if err != nil {
+		return nil, err
+	}
+	if environment == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("environment %q not found", environmentID))
+	}
+	policy, err := s.store.GetOnlineMigrationPolicy(ctx, environment.UID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to get online migration policy for environment %q", environmentID)).SetInternal(err)
+	}
+	config := policy.OnlineMigrationConfig
+	if override != nil {
+		if override.Engine != "" {
+			config.Engine = override.Engine
+		}
+		if override.ChunkSize > 0 {
+			config.ChunkSize = override.ChunkSize
+		}
+		if override.MaxLagSeconds > 0 {
+			config.MaxLagSeconds = override.MaxLagSeconds
+		}
+		if override.MaxLoad != "" {
+			config.MaxLoad = override.MaxLoad
+		}
+		if override.CriticalLoad != "" {
+			config.CriticalLoad = override.CriticalLoad
+		}
+	}
+	switch config.GetEngine() {
+	case api.OnlineMigrationEngineGhost, api.OnlineMigrationEnginePtOSC:
+	default:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid online migration engine %q", config.Engine))
+	}
+	return &config, nil
+}
+
 // creates gh-ost TaskCreate list and dependency.
-func createGhostTaskList(database *store.DatabaseMessage, instance *store.InstanceMessage, vcsPushEvent *vcs.PushEvent, detail *api.MigrationDetail, schemaVersion string) ([]api.TaskCreate, []api.TaskIndexDAG, error) {
+// The sync and cutover tasks run pt-osc instead of gh-ost if the online migration engine is pt-osc.
+func createGhostTaskList(database *store.DatabaseMessage, instance *store.InstanceMessage, vcsPushEvent *vcs.PushEvent, detail *api.MigrationDetail, schemaVersion string, onlineMigrationConfig *api.OnlineMigrationConfig) ([]api.TaskCreate, []api.TaskIndexDAG, error) {
+	engineName := "gh-ost"
+	if onlineMigrationConfig.GetEngine() == api.OnlineMigrationEnginePtOSC {
+		engineName = "pt-osc"
+	}
 	var taskCreateList []api.TaskCreate
 	// task "sync"
 	payloadSync := api.TaskDatabaseSchemaUpdateGhostSyncPayload{
-		SheetID:       detail.SheetID,
-		SchemaVersion: schemaVersion,
-		VCSPushEvent:  vcsPushEvent,
+		SheetID:               detail.SheetID,
+		SchemaVersion:         schemaVersion,
+		VCSPushEvent:          vcsPushEvent,
+		OnlineMigrationConfig: onlineMigrationConfig,
 	}
 	bytesSync, err := json.Marshal(payloadSync)
 	if err != nil {
 		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to marshal database schema update gh-ost sync payload, error: %v", err))
 	}
 	taskCreateList = append(taskCreateList, api.TaskCreate{
-		Name:              fmt.Sprintf("Update schema gh-ost sync for database %q", database.DatabaseName),
+		Name:              fmt.Sprintf("Update schema %s sync for database %q", engineName, database.DatabaseName),
 		InstanceID:        instance.UID,
 		DatabaseID:        &database.UID,
 		Status:            api.TaskPendingApproval,
@@ -1354,7 +1547,7 @@ func createGhostTaskList(database *store.DatabaseMessage, instance *store.Instan
 		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("failed to marshal database schema update ghost cutover payload, error: %v", err))
 	}
 	taskCreateList = append(taskCreateList, api.TaskCreate{
-		Name:              fmt.Sprintf("Update schema gh-ost cutover for database %q", database.DatabaseName),
+		Name:              fmt.Sprintf("Update schema %s cutover for database %q", engineName, database.DatabaseName),
 		InstanceID:        instance.UID,
 		DatabaseID:        &database.UID,
 		Status:            api.TaskPendingApproval,
@@ -1430,6 +1623,11 @@ func (s *Server) setTaskProgressForIssue(issue *api.Issue) {
 		for _, task := range stage.TaskList {
 			if progress, ok := s.stateCfg.TaskProgress.Load(task.ID); ok {
 				task.Progress = progress.(api.Progress)
+				for _, taskRun := range task.TaskRunList {
+					if taskRun.Status == api.TaskRunRunning {
+						taskRun.Progress = task.Progress
+					}
+				}
 			}
 		}
 	}
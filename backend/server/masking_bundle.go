@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+func (s *Server) registerMaskingBundleRoutes(g *echo.Group) {
+	g.GET("/masking/bundle", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		format := utils.MaskingBundleFormat(c.QueryParam("format"))
+		if format == "" {
+			format = utils.MaskingBundleFormatYAML
+		}
+
+		bundle, err := s.exportMaskingBundle(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to export masking bundle").SetInternal(err)
+		}
+		data, err := utils.MarshalMaskingBundle(bundle, format)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		contentType := echo.MIMETextPlainCharsetUTF8
+		if format == utils.MaskingBundleFormatJSON {
+			contentType = echo.MIMEApplicationJSONCharsetUTF8
+		}
+		c.Response().Header().Set(echo.HeaderContentType, contentType)
+		if _, err := c.Response().Write(data); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to write masking bundle response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// The bundle is imported as a whole after it's validated, the sensitive data policies of the databases in the
+	// bundle are replaced, and the others are kept.
+	g.POST("/masking/bundle/import", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if !s.licenseService.IsFeatureEnabled(api.FeatureSensitiveData) {
+			return echo.NewHTTPError(http.StatusForbidden, api.FeatureSensitiveData.AccessErrorMessage())
+		}
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read request body").SetInternal(err)
+		}
+		bundle, err := utils.UnmarshalMaskingBundle(body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		result, err := s.importMaskingBundle(ctx, principalID, bundle)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to import masking bundle").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, result)
+	})
+}
+
+// exportMaskingBundle exports the enforced sensitive data policies and the dismissed PII findings, sorted so that
+// the bundles of the same configuration are identical.
+func (s *Server) exportMaskingBundle(ctx context.Context) (*api.MaskingBundle, error) {
+	bundle := &api.MaskingBundle{
+		Version:               api.MaskingBundleVersion,
+		SensitiveDataPolicies: []*api.MaskingBundleSensitiveDataPolicy{},
+		DismissedPIIFindings:  []*api.MaskingBundlePIIFinding{},
+	}
+	databases := make(map[int]*store.DatabaseMessage)
+	getDatabase := func(uid int) (*store.DatabaseMessage, error) {
+		if database, ok := databases[uid]; ok {
+			return database, nil
+		}
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &uid})
+		if err != nil {
+			return nil, err
+		}
+		databases[uid] = database
+		return database, nil
+	}
+
+	resourceType := api.PolicyResourceTypeDatabase
+	pType := api.PolicyTypeSensitiveData
+	policies, err := s.store.ListPoliciesV2(ctx, &store.FindPolicyMessage{
+		ResourceType: &resourceType,
+		Type:         &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if !policy.Enforce {
+			continue
+		}
+		database, err := getDatabase(policy.ResourceUID)
+		if err != nil {
+			return nil, err
+		}
+		if database == nil {
+			continue
+		}
+		sensitiveDataPolicy, err := api.UnmarshalSensitiveDataPolicy(policy.Payload)
+		if err != nil {
+			return nil, err
+		}
+		if len(sensitiveDataPolicy.SensitiveDataList) == 0 {
+			continue
+		}
+		sensitiveDataList := sensitiveDataPolicy.SensitiveDataList
+		sort.Slice(sensitiveDataList, func(i, j int) bool {
+			a, b := sensitiveDataList[i], sensitiveDataList[j]
+			return fmt.Sprintf("%s\x00%s\x00%s", a.Schema, a.Table, a.Column) < fmt.Sprintf("%s\x00%s\x00%s", b.Schema, b.Table, b.Column)
+		})
+		bundle.SensitiveDataPolicies = append(bundle.SensitiveDataPolicies, &api.MaskingBundleSensitiveDataPolicy{
+			Instance:          database.InstanceID,
+			Database:          database.DatabaseName,
+			SensitiveDataList: sensitiveDataList,
+		})
+	}
+	sort.Slice(bundle.SensitiveDataPolicies, func(i, j int) bool {
+		a, b := bundle.SensitiveDataPolicies[i], bundle.SensitiveDataPolicies[j]
+		return fmt.Sprintf("%s\x00%s", a.Instance, a.Database) < fmt.Sprintf("%s\x00%s", b.Instance, b.Database)
+	})
+
+	dismissed := api.PIIFindingDismissed
+	findings, err := s.listPIIFindings(ctx, &store.FindPIIFindingMessage{Status: &dismissed})
+	if err != nil {
+		return nil, err
+	}
+	for _, finding := range findings {
+		database, err := getDatabase(finding.DatabaseUID)
+		if err != nil {
+			return nil, err
+		}
+		if database == nil {
+			continue
+		}
+		bundle.DismissedPIIFindings = append(bundle.DismissedPIIFindings, &api.MaskingBundlePIIFinding{
+			Instance:       database.InstanceID,
+			Database:       database.DatabaseName,
+			Schema:         finding.SchemaName,
+			Table:          finding.TableName,
+			Column:         finding.ColumnName,
+			Classification: finding.Classification,
+		})
+	}
+	sort.Slice(bundle.DismissedPIIFindings, func(i, j int) bool {
+		a, b := bundle.DismissedPIIFindings[i], bundle.DismissedPIIFindings[j]
+		return fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s", a.Instance, a.Database, a.Schema, a.Table, a.Column) < fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s", b.Instance, b.Database, b.Schema, b.Table, b.Column)
+	})
+	return bundle, nil
+}
+
+// importMaskingBundle imports the validated masking bundle. The dismissed PII findings are applied to the proposed
+// findings of the same classifications, so the columns reviewed in the source workspace aren't proposed again.
+func (s *Server) importMaskingBundle(ctx context.Context, principalID int, bundle *api.MaskingBundle) (*api.MaskingBundleImportResult, error) {
+	result := &api.MaskingBundleImportResult{
+		SkippedDatabaseList: []string{},
+	}
+	for _, policy := range bundle.SensitiveDataPolicies {
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{InstanceID: &policy.Instance, DatabaseName: &policy.Database})
+		if err != nil {
+			return nil, err
+		}
+		if database == nil {
+			result.SkippedDatabaseList = append(result.SkippedDatabaseList, fmt.Sprintf("%s/%s", policy.Instance, policy.Database))
+			continue
+		}
+		if err := s.upsertSensitiveDataPolicy(ctx, principalID, database.UID, &api.SensitiveDataPolicy{SensitiveDataList: policy.SensitiveDataList}); err != nil {
+			return nil, err
+		}
+		result.UpdatedPolicyCount++
+	}
+
+	findingsByDatabase := make(map[string][]*store.PIIFindingMessage)
+	for _, dismissed := range bundle.DismissedPIIFindings {
+		key := fmt.Sprintf("%s/%s", dismissed.Instance, dismissed.Database)
+		findings, ok := findingsByDatabase[key]
+		if !ok {
+			database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{InstanceID: &dismissed.Instance, DatabaseName: &dismissed.Database})
+			if err != nil {
+				return nil, err
+			}
+			if database != nil {
+				if findings, err = s.listPIIFindings(ctx, &store.FindPIIFindingMessage{DatabaseUID: &database.UID}); err != nil {
+					return nil, err
+				}
+			}
+			findingsByDatabase[key] = findings
+		}
+		var matched *store.PIIFindingMessage
+		for _, finding := range findings {
+			if finding.SchemaName == dismissed.Schema && finding.TableName == dismissed.Table && finding.ColumnName == dismissed.Column && finding.Classification == dismissed.Classification {
+				matched = finding
+				break
+			}
+		}
+		switch {
+		case matched == nil || matched.Status == api.PIIFindingAccepted:
+			result.SkippedPIIFindingCount++
+		case matched.Status == api.PIIFindingProposed:
+			if _, err := s.store.UpdatePIIFindingStatus(ctx, matched.ID, api.PIIFindingDismissed); err != nil {
+				return nil, err
+			}
+			result.DismissedPIIFindingCount++
+		default:
+			result.DismissedPIIFindingCount++
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+func (s *Server) registerNotebookRoutes(g *echo.Group) {
+	g.POST("/sheet/:sheetID/notebook/run", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+		sheet, notebook, database, err := s.getNotebook(c)
+		if err != nil {
+			return err
+		}
+
+		run := &api.NotebookCellRun{}
+		if err := json.NewDecoder(c.Request().Body).Decode(run); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed run notebook cell request").SetInternal(err)
+		}
+		var cell *api.NotebookCell
+		for _, v := range notebook.CellList {
+			if v.ID == run.CellID {
+				cell = v
+				break
+			}
+		}
+		if cell == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Cell %q not found in the notebook", run.CellID))
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", database.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance %q not found", database.InstanceID))
+		}
+		if !api.IsSQLCursorSupported(instance.Engine) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Notebook is not supported for engine %q", instance.Engine))
+		}
+		statement, err := utils.RenderNotebookStatement(instance.Engine, cell.Statement, notebook.ParameterList, run.ParameterValues)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		sensitiveSchemaInfo, queryLimit, err := s.checkSQLEditorQuery(ctx, principalID, role, instance, database.DatabaseName, statement)
+		if err != nil {
+			return err
+		}
+
+		// The values used by the run are persisted including the defaults, so the result can be reproduced.
+		parameterValues := make(map[string]string)
+		for _, parameter := range notebook.ParameterList {
+			if v, ok := run.ParameterValues[parameter.Name]; ok {
+				parameterValues[parameter.Name] = v
+			} else {
+				parameterValues[parameter.Name] = parameter.DefaultValue
+			}
+		}
+		cellResult := &store.NotebookCellResultMessage{
+			CreatorID:       principalID,
+			SheetUID:        sheet.UID,
+			CellID:          cell.ID,
+			ParameterValues: parameterValues,
+			Rows:            [][]any{},
+		}
+		result, err := s.queryRows(ctx, instance, database.DatabaseName, statement, sensitiveSchemaInfo, queryLimit, api.MaxNotebookCellRowCount)
+		if err != nil {
+			cellResult.Error = err.Error()
+		} else {
+			cellResult.ColumnNames = result.columnNames
+			cellResult.ColumnTypeNames = result.columnTypeNames
+			cellResult.Rows = result.rows
+			cellResult.Truncated = result.truncated
+		}
+		cellResult, err = s.store.UpsertNotebookCellResult(ctx, cellResult)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to save the result of cell %q", cell.ID)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPINotebookCellResult(cellResult))
+	})
+
+	g.GET("/sheet/:sheetID/notebook/result", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+		sheet, notebook, database, err := s.getNotebook(c)
+		if err != nil {
+			return err
+		}
+		// The results are shared with everyone who can read the notebook, so they must be able to query the database.
+		hasAccessRights, err := s.hasDatabaseAccessRights(ctx, principalID, role, database)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check access control for database: %q", database.DatabaseName)).SetInternal(err)
+		}
+		if !hasAccessRights {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("No permission to access database %q", database.DatabaseName))
+		}
+
+		cellResults, err := s.store.ListNotebookCellResults(ctx, sheet.UID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list the cell results of sheet ID: %d", sheet.UID)).SetInternal(err)
+		}
+		// The results of the removed cells are kept until the cells with the same IDs run again, we skip them.
+		cells := make(map[string]bool)
+		for _, cell := range notebook.CellList {
+			cells[cell.ID] = true
+		}
+		cellResultList := []*api.NotebookCellResult{}
+		for _, cellResult := range cellResults {
+			if cells[cellResult.CellID] {
+				cellResultList = append(cellResultList, toAPINotebookCellResult(cellResult))
+			}
+		}
+		return c.JSON(http.StatusOK, cellResultList)
+	})
+}
+
+// getNotebook gets the notebook sheet in the path readable by the principal, and the database it's connected to.
+func (s *Server) getNotebook(c echo.Context) (*store.SheetMessage, *api.SheetNotebookPayload, *store.DatabaseMessage, error) {
+	ctx := c.Request().Context()
+	principalID := c.Get(getPrincipalIDContextKey()).(int)
+	role := c.Get(getRoleContextKey()).(api.Role)
+	id, err := strconv.Atoi(c.Param("sheetID"))
+	if err != nil {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("sheetID"))).SetInternal(err)
+	}
+	sheet, err := s.store.GetSheetV2(ctx, &api.SheetFind{ID: &id, LoadFull: true}, principalID)
+	if err != nil {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch sheet ID: %v", id)).SetInternal(err)
+	}
+	if sheet == nil {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("sheet ID not found: %d", id))
+	}
+	canRead, err := s.canReadSheet(ctx, principalID, role, sheet)
+	if err != nil {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check access control for sheet ID: %d", id)).SetInternal(err)
+	}
+	if !canRead {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("No permission to read sheet ID: %d", id))
+	}
+	if sheet.Type != api.SheetForNotebook {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Sheet ID %d is not a notebook", id))
+	}
+	notebook, err := utils.ParseNotebookPayload(sheet.Payload)
+	if err != nil {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to parse the notebook of sheet ID: %d", id)).SetInternal(err)
+	}
+	if sheet.DatabaseID == nil {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusBadRequest, "The notebook must be connected to a database to run")
+	}
+	database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: sheet.DatabaseID})
+	if err != nil {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", *sheet.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, nil, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", *sheet.DatabaseID))
+	}
+	return sheet, notebook, database, nil
+}
+
+// canReadSheet returns whether the principal can read the sheet under its visibility.
+func (s *Server) canReadSheet(ctx context.Context, principalID int, role api.Role, sheet *store.SheetMessage) (bool, error) {
+	if role == api.Owner || role == api.DBA || sheet.Creator.ID == principalID {
+		return true, nil
+	}
+	switch sheet.Visibility {
+	case api.PublicSheet:
+		return true, nil
+	case api.ProjectSheet:
+		projectPolicy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{ProjectID: &sheet.Project.ResourceID})
+		if err != nil {
+			return false, err
+		}
+		return isProjectMember(principalID, projectPolicy), nil
+	}
+	return false, nil
+}
+
+func toAPINotebookCellResult(cellResult *store.NotebookCellResultMessage) *api.NotebookCellResult {
+	return &api.NotebookCellResult{
+		SheetID:         cellResult.SheetUID,
+		CellID:          cellResult.CellID,
+		CreatorID:       cellResult.CreatorID,
+		CreatedTs:       cellResult.CreatedTs,
+		ParameterValues: cellResult.ParameterValues,
+		ColumnNames:     cellResult.ColumnNames,
+		ColumnTypeNames: cellResult.ColumnTypeNames,
+		Rows:            cellResult.Rows,
+		Truncated:       cellResult.Truncated,
+		Error:           cellResult.Error,
+	}
+}
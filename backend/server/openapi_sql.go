@@ -1,13 +1,17 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 
 	metricAPI "github.com/bytebase/bytebase/backend/metric"
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
@@ -15,6 +19,8 @@ import (
 	advisorDB "github.com/bytebase/bytebase/backend/plugin/advisor/db"
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/plugin/metric"
+	"github.com/bytebase/bytebase/backend/plugin/parser/mybatis"
+	mybatisast "github.com/bytebase/bytebase/backend/plugin/parser/mybatis/ast"
 	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
 
 	"github.com/bytebase/bytebase/backend/plugin/parser/sql/differ"
@@ -48,8 +54,15 @@ type sqlCheckRequestBody struct {
 	EnvironmentName string `json:"environmentName"`
 	Host            string `json:"host"`
 	Port            string `json:"port"`
+	// Format is the response format, could be empty or "SARIF".
+	Format string `json:"format"`
+	// FilePath is the path of the checked file, used as the location in the SARIF report.
+	FilePath string `json:"filePath"`
 }
 
+// sqlCheckResponseFormatSARIF is the SARIF response format for the sql check api.
+const sqlCheckResponseFormatSARIF = "SARIF"
+
 // sqlCheckController godoc
 // @Summary  Check the SQL statement.
 // @Description  Parse and check the SQL statement according to the SQL review policy.
@@ -62,6 +75,8 @@ type sqlCheckRequestBody struct {
 // @Param  host             body  string  false  "The instance host."
 // @Param  port             body  string  false  "The instance port."
 // @Param  databaseName     body  string  false  "The database name in the instance."
+// @Param  format           body  string  false  "The response format. Return the SARIF report if set."  Enums(SARIF)
+// @Param  filePath         body  string  false  "The file path of the statement, used as the location in the SARIF report."
 // @Success  200  {array}   advisor.Advice
 // @Failure  400  {object}  echo.HTTPError
 // @Failure  500  {object}  echo.HTTPError
@@ -85,43 +100,23 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Missing required SQL statement")
 	}
 
+	if request.Format != "" && request.Format != sqlCheckResponseFormatSARIF {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported format %s", request.Format))
+	}
+
 	ctx := c.Request().Context()
 	var databaseType string
 	var catalog catalog.Catalog
 	var driver db.Driver
 	var connection *sql.DB
+	var database *store.DatabaseMessage
 
 	if request.DatabaseName != "" && request.Host != "" && request.Port != "" {
-		instances, err := s.store.ListInstancesV2(ctx, &store.FindInstanceMessage{})
-		if err != nil {
-			return err
-		}
 		var instance *store.InstanceMessage
-		for _, v := range instances {
-			for _, d := range v.DataSources {
-				if d.Host == request.Host && d.Port == request.Port {
-					instance = v
-					break
-				}
-			}
-			if instance != nil {
-				break
-			}
-		}
-		if instance == nil {
-			return echo.NewHTTPError(http.StatusNotFound, "instance not found with host and port")
-		}
-		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{
-			EnvironmentID: &instance.EnvironmentID,
-			InstanceID:    &instance.ResourceID,
-			DatabaseName:  &request.DatabaseName,
-		})
+		instance, database, err = s.findSQLCheckDatabase(ctx, request.Host, request.Port, request.DatabaseName)
 		if err != nil {
 			return err
 		}
-		if database == nil {
-			return echo.NewHTTPError(http.StatusNotFound, "database not found")
-		}
 
 		dbType := instance.Engine
 		databaseType = string(dbType)
@@ -168,6 +163,7 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		request.Statement,
 		catalog,
 		connection,
+		database,
 	)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to run sql check").SetInternal(err)
@@ -182,9 +178,304 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		},
 	})
 
+	if request.Format == sqlCheckResponseFormatSARIF {
+		return c.JSON(http.StatusOK, advisor.ConvertToSARIF([]*advisor.SARIFFileAdvice{
+			{FilePath: request.FilePath, AdviceList: adviceList},
+		}))
+	}
 	return c.JSON(http.StatusOK, adviceList)
 }
 
+// findSQLCheckDatabase finds the database by the data source host and port of the instance and the database name.
+func (s *Server) findSQLCheckDatabase(ctx context.Context, host, port, databaseName string) (*store.InstanceMessage, *store.DatabaseMessage, error) {
+	instances, err := s.store.ListInstancesV2(ctx, &store.FindInstanceMessage{})
+	if err != nil {
+		return nil, nil, err
+	}
+	var instance *store.InstanceMessage
+	for _, v := range instances {
+		for _, d := range v.DataSources {
+			if d.Host == host && d.Port == port {
+				instance = v
+				break
+			}
+		}
+		if instance != nil {
+			break
+		}
+	}
+	if instance == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, "instance not found with host and port")
+	}
+	database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{
+		EnvironmentID: &instance.EnvironmentID,
+		InstanceID:    &instance.ResourceID,
+		DatabaseName:  &databaseName,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if database == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, "database not found")
+	}
+	return instance, database, nil
+}
+
+const (
+	// maxSQLBatchCheckFileCount is the maximum number of files in a batch sql check request.
+	maxSQLBatchCheckFileCount = 200
+	// sqlBatchCheckConcurrency is the maximum number of statements checked concurrently in a batch sql check request.
+	sqlBatchCheckConcurrency = 8
+)
+
+type sqlBatchCheckRequestBody struct {
+	DatabaseType    string `json:"databaseType"`
+	DatabaseName    string `json:"databaseName"`
+	EnvironmentName string `json:"environmentName"`
+	Host            string `json:"host"`
+	Port            string `json:"port"`
+	// Format is the response format, could be empty or "SARIF".
+	Format   string               `json:"format"`
+	FileList []*sqlBatchCheckFile `json:"fileList"`
+}
+
+type sqlBatchCheckFile struct {
+	// FilePath is the path of the checked file, the file is treated as the MyBatis mapper XML if it ends with ".xml".
+	FilePath  string `json:"filePath"`
+	Statement string `json:"statement"`
+}
+
+type sqlBatchCheckFileResult struct {
+	FilePath      string                          `json:"filePath"`
+	StatementList []*sqlBatchCheckStatementResult `json:"statementList"`
+	// Error is the error message if the file cannot be checked.
+	Error string `json:"error,omitempty"`
+}
+
+type sqlBatchCheckStatementResult struct {
+	// ID is the statement id in the MyBatis mapper XML, empty for SQL files.
+	ID string `json:"id,omitempty"`
+	// Line is the line of the statement in the MyBatis mapper XML, 0 for SQL files.
+	// The line of the advice is mapped to the line in the file.
+	Line       int              `json:"line,omitempty"`
+	Statement  string           `json:"statement"`
+	AdviceList []advisor.Advice `json:"adviceList"`
+}
+
+// sqlBatchCheckController godoc
+// @Summary  Check the SQL statements in a batch of files.
+// @Description  Parse and check the SQL statements of the files according to the SQL review policy, the MyBatis mapper XML files are supported.
+// @Accept  */*
+// @Tags  SQL review
+// @Produce  json
+// @Param  environmentName  body  string  true   "The environment name. Case sensitive."
+// @Param  fileList         body  array   true   "The files with the path and the statement."
+// @Param  databaseType     body  string  false  "The database type. Required if the port, host and database name is not specified."  Enums(MYSQL, POSTGRES, TIDB)
+// @Param  host             body  string  false  "The instance host."
+// @Param  port             body  string  false  "The instance port."
+// @Param  databaseName     body  string  false  "The database name in the instance."
+// @Param  format           body  string  false  "The response format. Return the SARIF report if set."  Enums(SARIF)
+// @Success  200  {array}   sqlBatchCheckFileResult
+// @Failure  400  {object}  echo.HTTPError
+// @Failure  500  {object}  echo.HTTPError
+// @Router  /sql/advise/batch  [post].
+func (s *Server) sqlBatchCheckController(c echo.Context) error {
+	request := &sqlBatchCheckRequestBody{}
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to read request body").SetInternal(err)
+	}
+	if err := json.Unmarshal(body, request); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Cannot format request body").SetInternal(err)
+	}
+
+	// EnvironmentName is the environment resource ID.
+	if request.EnvironmentName == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing required environment name")
+	}
+	if len(request.FileList) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing required file list")
+	}
+	if len(request.FileList) > maxSQLBatchCheckFileCount {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Too many files, the maximum is %d", maxSQLBatchCheckFileCount))
+	}
+	if request.Format != "" && request.Format != sqlCheckResponseFormatSARIF {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported format %s", request.Format))
+	}
+
+	ctx := c.Request().Context()
+	var databaseType string
+	var connection *sql.DB
+	var database *store.DatabaseMessage
+
+	if request.DatabaseName != "" && request.Host != "" && request.Port != "" {
+		var instance *store.InstanceMessage
+		instance, database, err = s.findSQLCheckDatabase(ctx, request.Host, request.Port, request.DatabaseName)
+		if err != nil {
+			return err
+		}
+		databaseType = string(instance.Engine)
+		driver, err := s.dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, database.DatabaseName)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get database driver").SetInternal(err)
+		}
+		defer driver.Close(ctx)
+		connection = driver.GetDB()
+	} else {
+		databaseType = request.DatabaseType
+		if databaseType == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Missing required database type")
+		}
+	}
+
+	advisorDBType, err := advisorDB.ConvertToAdvisorDBType(databaseType)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %s is not support", databaseType))
+	}
+
+	environment, err := s.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{ResourceID: &request.EnvironmentName})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to find environment %s", request.EnvironmentName)).SetInternal(err)
+	}
+	if environment == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid environment %s", request.EnvironmentName))
+	}
+
+	resultList := make([]*sqlBatchCheckFileResult, len(request.FileList))
+	for i, file := range request.FileList {
+		resultList[i] = &sqlBatchCheckFileResult{FilePath: file.FilePath}
+		statementList, err := extractSQLBatchCheckStatements(file)
+		if err != nil {
+			resultList[i].Error = err.Error()
+			continue
+		}
+		resultList[i].StatementList = statementList
+	}
+
+	// The walk-through changes the catalog, so each statement uses its own catalog.
+	errs := make([][]error, len(resultList))
+	semaphore := make(chan struct{}, sqlBatchCheckConcurrency)
+	var wg sync.WaitGroup
+	for i, result := range resultList {
+		errs[i] = make([]error, len(result.StatementList))
+		for j, statement := range result.StatementList {
+			wg.Add(1)
+			semaphore <- struct{}{}
+			go func(i, j int, statement *sqlBatchCheckStatementResult) {
+				defer func() {
+					<-semaphore
+					wg.Done()
+				}()
+				var checkCatalog catalog.Catalog = newCatalogService(advisorDBType)
+				if database != nil {
+					// TODO(rebelice): support SDL mode for open api.
+					databaseCatalog, err := s.store.NewCatalog(ctx, database.UID, db.Type(databaseType), advisor.SyntaxModeNormal)
+					if err != nil {
+						errs[i][j] = err
+						return
+					}
+					checkCatalog = databaseCatalog
+				}
+				_, statement.AdviceList, errs[i][j] = s.sqlCheck(
+					ctx,
+					advisorDBType,
+					"utf8mb4",
+					"utf8mb4_general_ci",
+					environment.UID,
+					statement.Statement,
+					checkCatalog,
+					connection,
+					database,
+				)
+				if statement.Line > 0 {
+					for k := range statement.AdviceList {
+						statement.AdviceList[k].Line = mapStatementLineToFileLine(statement.Line, statement.AdviceList[k].Line)
+					}
+				}
+			}(i, j, statement)
+		}
+	}
+	wg.Wait()
+	for i, result := range resultList {
+		for _, err := range errs[i] {
+			if err != nil {
+				result.Error = fmt.Sprintf("failed to run sql check: %v", err)
+				break
+			}
+		}
+	}
+
+	s.MetricReporter.Report(ctx, &metric.Metric{
+		Name:  metricAPI.SQLAdviseAPIMetricName,
+		Value: len(request.FileList),
+		Labels: map[string]any{
+			"database_type": databaseType,
+			"environment":   request.EnvironmentName,
+		},
+	})
+
+	if request.Format == sqlCheckResponseFormatSARIF {
+		var fileAdviceList []*advisor.SARIFFileAdvice
+		for _, result := range resultList {
+			fileAdvice := &advisor.SARIFFileAdvice{FilePath: result.FilePath}
+			for _, statement := range result.StatementList {
+				fileAdvice.AdviceList = append(fileAdvice.AdviceList, statement.AdviceList...)
+			}
+			fileAdviceList = append(fileAdviceList, fileAdvice)
+		}
+		return c.JSON(http.StatusOK, advisor.ConvertToSARIF(fileAdviceList))
+	}
+	return c.JSON(http.StatusOK, resultList)
+}
+
+// extractSQLBatchCheckStatements extracts the statements to check from the file.
+// Each query in the MyBatis mapper XML is a statement, and the SQL file is checked as a whole.
+func extractSQLBatchCheckStatements(file *sqlBatchCheckFile) ([]*sqlBatchCheckStatementResult, error) {
+	if file.Statement == "" {
+		return nil, errors.Errorf("missing required SQL statement")
+	}
+	if !strings.HasSuffix(strings.ToLower(file.FilePath), ".xml") {
+		return []*sqlBatchCheckStatementResult{{Statement: file.Statement}}, nil
+	}
+
+	root, err := mybatis.NewParser(file.Statement).Parse()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the MyBatis mapper XML")
+	}
+	var statementList []*sqlBatchCheckStatementResult
+	nodeList := []mybatisast.Node{root}
+	for len(nodeList) > 0 {
+		node := nodeList[0]
+		nodeList = nodeList[1:]
+		switch node := node.(type) {
+		case *mybatisast.RootNode:
+			nodeList = append(nodeList, node.Children...)
+		case *mybatisast.MapperNode:
+			nodeList = append(nodeList, node.Children...)
+		case *mybatisast.QueryNode:
+			var buf strings.Builder
+			if err := node.RestoreSQL(&buf); err != nil {
+				return nil, errors.Wrapf(err, "failed to restore the SQL of %q", node.ID)
+			}
+			statementList = append(statementList, &sqlBatchCheckStatementResult{
+				ID:        node.ID,
+				Line:      node.Line,
+				Statement: strings.TrimSpace(buf.String()),
+			})
+		}
+	}
+	return statementList, nil
+}
+
+// mapStatementLineToFileLine maps the line in the statement to the line in the file, the statement starts at the
+// statementLine of the file. The advice without line points to the start of the statement.
+func mapStatementLineToFileLine(statementLine, line int) int {
+	if line <= 0 {
+		return statementLine
+	}
+	return statementLine + line - 1
+}
+
 type schemaDiffRequestBody struct {
 	EngineType   parser.EngineType `json:"engineType"`
 	SourceSchema string            `json:"sourceSchema"`
@@ -220,6 +511,10 @@ func schemaDiff(c echo.Context) error {
 		engine = parser.Postgres
 	case parser.EngineType(db.MySQL), parser.EngineType(db.MariaDB):
 		engine = parser.MySQL
+	case parser.EngineType(db.Oracle):
+		engine = parser.Oracle
+	case parser.EngineType(db.MSSQL):
+		engine = parser.MSSQL
 	default:
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid database engine %s", request.EngineType))
 	}
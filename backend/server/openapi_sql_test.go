@@ -0,0 +1,56 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractSQLBatchCheckStatements(t *testing.T) {
+	tests := []struct {
+		file *sqlBatchCheckFile
+		want []*sqlBatchCheckStatementResult
+	}{
+		{
+			file: &sqlBatchCheckFile{
+				FilePath:  "migration/V1__init.sql",
+				Statement: "CREATE TABLE t(a int);\nINSERT INTO t VALUES (1);",
+			},
+			want: []*sqlBatchCheckStatementResult{
+				{Statement: "CREATE TABLE t(a int);\nINSERT INTO t VALUES (1);"},
+			},
+		},
+		{
+			file: &sqlBatchCheckFile{
+				FilePath: "mapper/UserMapper.XML",
+				Statement: `<mapper namespace="com.bytebase.test">
+    <select id="selectUser" parameterType="int" resultType="hashmap">
+        select * from user where id = #{id}
+    </select>
+    <delete id="deleteUser" parameterType="int">
+        delete from user where id = ${id}
+    </delete>
+</mapper>`,
+			},
+			want: []*sqlBatchCheckStatementResult{
+				{ID: "selectUser", Line: 3, Statement: "select * from user where id = ?;"},
+				{ID: "deleteUser", Line: 6, Statement: "delete from user where id = ?;"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := extractSQLBatchCheckStatements(test.file)
+		require.NoError(t, err)
+		require.Equal(t, test.want, got)
+	}
+
+	_, err := extractSQLBatchCheckStatements(&sqlBatchCheckFile{FilePath: "mapper/UserMapper.xml", Statement: "<mapper>"})
+	require.Error(t, err)
+}
+
+func TestMapStatementLineToFileLine(t *testing.T) {
+	require.Equal(t, 3, mapStatementLineToFileLine(3, 0))
+	require.Equal(t, 3, mapStatementLineToFileLine(3, 1))
+	require.Equal(t, 5, mapStatementLineToFileLine(3, 3))
+}
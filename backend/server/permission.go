@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// hasProjectPermission returns whether the principal is granted the permission in the project. Workspace Owners and
+// DBAs are granted all permissions, the others are granted the permissions of their project roles and of the custom
+// roles assigned to them at the workspace scope.
+func (s *Server) hasProjectPermission(ctx context.Context, principalID int, role api.Role, projectPolicy *store.IAMPolicyMessage, permission api.ProjectPermissionType) (bool, error) {
+	if role == api.Owner || role == api.DBA {
+		return true, nil
+	}
+	projectRoles, err := s.getProjectRoles(ctx, principalID, projectPolicy)
+	if err != nil {
+		return false, err
+	}
+	plan := s.licenseService.GetEffectivePlan()
+	if api.ProjectPermission(permission, plan, projectRoles) {
+		return true, nil
+	}
+	rolePermissions, err := s.getCustomRolePermissions(ctx)
+	if err != nil {
+		return false, err
+	}
+	return api.CustomRolePermission(permission, plan, projectRoles, rolePermissions), nil
+}
+
+// getProjectRoles returns the roles of the principal in the project, including the custom roles assigned to the
+// principal at the workspace scope.
+func (s *Server) getProjectRoles(ctx context.Context, principalID int, projectPolicy *store.IAMPolicyMessage) (map[common.ProjectRole]bool, error) {
+	projectRoles := make(map[common.ProjectRole]bool)
+	for _, binding := range projectPolicy.Bindings {
+		for _, member := range binding.Members {
+			if member.ID == principalID {
+				projectRoles[common.ProjectRole(binding.Role)] = true
+				break
+			}
+		}
+	}
+	if !common.FeatureFlag(common.FeatureFlagRoleMember) {
+		return projectRoles, nil
+	}
+	members, err := s.store.ListRoleMembers(ctx, &store.FindRoleMemberMessage{PrincipalID: &principalID})
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		projectRoles[common.ProjectRole(member.Role)] = true
+	}
+	return projectRoles, nil
+}
+
+// getCustomRolePermissions returns the permission lists of the custom roles.
+func (s *Server) getCustomRolePermissions(ctx context.Context) (map[common.ProjectRole][]api.ProjectPermissionType, error) {
+	roles, err := s.store.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rolePermissions := make(map[common.ProjectRole][]api.ProjectPermissionType)
+	for _, role := range roles {
+		rolePermissions[common.ProjectRole(role.ResourceID)] = role.PermissionList
+	}
+	return rolePermissions, nil
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/runner/piiscan"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func (s *Server) registerPIIRoutes(g *echo.Group) {
+	g.GET("/pii/finding", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		find := &store.FindPIIFindingMessage{}
+		if databaseIDStr := c.QueryParam("database"); databaseIDStr != "" {
+			databaseID, err := strconv.Atoi(databaseIDStr)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database ID is not a number: %s", databaseIDStr)).SetInternal(err)
+			}
+			find.DatabaseUID = &databaseID
+		}
+		if status := c.QueryParam("status"); status != "" {
+			find.Status = (*api.PIIFindingStatus)(&status)
+		}
+		findings, err := s.store.ListPIIFindings(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list PII findings").SetInternal(err)
+		}
+
+		findingList := []*api.PIIFinding{}
+		for _, finding := range findings {
+			findingList = append(findingList, toAPIPIIFinding(finding))
+		}
+		return c.JSON(http.StatusOK, findingList)
+	})
+
+	g.PATCH("/pii/finding/:findingID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		id, err := strconv.Atoi(c.Param("findingID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("findingID"))).SetInternal(err)
+		}
+		patch := &api.PIIFindingPatch{}
+		if err := json.NewDecoder(c.Request().Body).Decode(patch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch PII finding request").SetInternal(err)
+		}
+
+		finding, err := s.store.GetPIIFinding(ctx, &store.FindPIIFindingMessage{ID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get PII finding ID: %d", id)).SetInternal(err)
+		}
+		if finding == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("PII finding not found with ID %d", id))
+		}
+		// The accepted findings are in the sensitive data policy, which is changed through the policy instead.
+		switch {
+		case patch.Status == api.PIIFindingAccepted && finding.Status != api.PIIFindingAccepted:
+			if !s.licenseService.IsFeatureEnabled(api.FeatureSensitiveData) {
+				return echo.NewHTTPError(http.StatusForbidden, api.FeatureSensitiveData.AccessErrorMessage())
+			}
+			if err := s.addSensitiveData(ctx, principalID, finding); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to add PII finding ID %d to the sensitive data policy", id)).SetInternal(err)
+			}
+		case patch.Status == api.PIIFindingDismissed && finding.Status == api.PIIFindingProposed:
+		case patch.Status == api.PIIFindingProposed && finding.Status == api.PIIFindingDismissed:
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cannot change the status of PII finding from %s to %s", finding.Status, patch.Status))
+		}
+
+		finding, err = s.store.UpdatePIIFindingStatus(ctx, id, patch.Status)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update PII finding ID: %d", id)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIPIIFinding(finding))
+	})
+
+	// Scan the database right away, such as after the schema changes.
+	g.POST("/database/:databaseID/pii/scan", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		if s.PIIScanner == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "PII scan is not available in readonly mode")
+		}
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %d", id)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance %q", database.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance %q not found", database.InstanceID))
+		}
+		if !piiscan.IsSupported(instance.Engine) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("PII scan is not supported for engine %q", instance.Engine))
+		}
+		if err := s.PIIScanner.ScanDatabase(ctx, instance, database); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to scan database %q", database.DatabaseName)).SetInternal(err)
+		}
+
+		findings, err := s.store.ListPIIFindings(ctx, &store.FindPIIFindingMessage{DatabaseUID: &database.UID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list PII findings").SetInternal(err)
+		}
+		findingList := []*api.PIIFinding{}
+		for _, finding := range findings {
+			findingList = append(findingList, toAPIPIIFinding(finding))
+		}
+		return c.JSON(http.StatusOK, findingList)
+	})
+}
+
+// addSensitiveData adds the column of the PII finding with the proposed mask type to the sensitive data policy of the
+// database. The column already in the policy is kept as is.
+func (s *Server) addSensitiveData(ctx context.Context, principalID int, finding *store.PIIFindingMessage) error {
+	sensitiveDataPolicy, err := s.store.GetSensitiveDataPolicy(ctx, finding.DatabaseUID)
+	if err != nil {
+		return err
+	}
+	for _, data := range sensitiveDataPolicy.SensitiveDataList {
+		if data.Schema == finding.SchemaName && data.Table == finding.TableName && data.Column == finding.ColumnName {
+			return nil
+		}
+	}
+	sensitiveDataPolicy.SensitiveDataList = append(sensitiveDataPolicy.SensitiveDataList, api.SensitiveData{
+		Schema: finding.SchemaName,
+		Table:  finding.TableName,
+		Column: finding.ColumnName,
+		Type:   finding.MaskType,
+	})
+	return s.upsertSensitiveDataPolicy(ctx, principalID, finding.DatabaseUID, sensitiveDataPolicy)
+}
+
+// upsertSensitiveDataPolicy creates or replaces the sensitive data policy of the database.
+func (s *Server) upsertSensitiveDataPolicy(ctx context.Context, principalID int, databaseUID int, sensitiveDataPolicy *api.SensitiveDataPolicy) error {
+	payload, err := sensitiveDataPolicy.String()
+	if err != nil {
+		return err
+	}
+	resourceType := api.PolicyResourceTypeDatabase
+	pType := api.PolicyTypeSensitiveData
+	policy, err := s.store.GetPolicyV2(ctx, &store.FindPolicyMessage{
+		ResourceType: &resourceType,
+		ResourceUID:  &databaseUID,
+		Type:         &pType,
+	})
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		_, err = s.store.CreatePolicyV2(ctx, &store.PolicyMessage{
+			ResourceType:      resourceType,
+			ResourceUID:       databaseUID,
+			Type:              pType,
+			Payload:           payload,
+			InheritFromParent: true,
+			// Enforce cannot be false while creating a policy.
+			Enforce: true,
+		}, principalID)
+		return err
+	}
+	_, err = s.store.UpdatePolicyV2(ctx, &store.UpdatePolicyMessage{
+		UpdaterID:    principalID,
+		ResourceType: resourceType,
+		ResourceUID:  databaseUID,
+		Type:         pType,
+		Payload:      &payload,
+	})
+	return err
+}
+
+// listPIIFindings lists the PII findings, there are none before the PII scan is available.
+func (s *Server) listPIIFindings(ctx context.Context, find *store.FindPIIFindingMessage) ([]*store.PIIFindingMessage, error) {
+	if !common.FeatureFlag(common.FeatureFlagPIIScan) {
+		return nil, nil
+	}
+	return s.store.ListPIIFindings(ctx, find)
+}
+
+func toAPIPIIFinding(finding *store.PIIFindingMessage) *api.PIIFinding {
+	return &api.PIIFinding{
+		ID:             finding.ID,
+		DatabaseID:     finding.DatabaseUID,
+		SchemaName:     finding.SchemaName,
+		TableName:      finding.TableName,
+		ColumnName:     finding.ColumnName,
+		Classification: finding.Classification,
+		MaskType:       finding.MaskType,
+		NameMatched:    finding.NameMatched,
+		SampleCount:    finding.SampleCount,
+		MatchCount:     finding.MatchCount,
+		Status:         finding.Status,
+		LastScannedTs:  finding.LastScannedTs,
+	}
+}
@@ -125,6 +125,9 @@ func (s *Server) registerPolicyRoutes(g *echo.Group) {
 				return err
 			}
 		} else {
+			if pType == api.PolicyTypeSQLReview {
+				policyUpsert.Payload = inheritSQLReviewRuleVersion(policy.Payload, policyUpsert.Payload)
+			}
 			var enforce *bool
 			if policyUpsert.RowStatus != nil {
 				if *policyUpsert.RowStatus == string(api.Normal) {
@@ -420,7 +423,7 @@ func (s *Server) hasAccessToUpsertPolicy(pType api.PolicyType, policyUpsert *api
 		if !s.licenseService.IsFeatureEnabled(api.FeatureBackupPolicy) {
 			return errors.Errorf(api.FeatureBackupPolicy.AccessErrorMessage())
 		}
-	case api.PolicyTypeSQLReview:
+	case api.PolicyTypeSQLReview, api.PolicyTypeSQLReviewOverride:
 		return nil
 	case api.PolicyTypeEnvironmentTier:
 		if !s.licenseService.IsFeatureEnabled(api.FeatureEnvironmentTierPolicy) {
@@ -430,6 +433,18 @@ func (s *Server) hasAccessToUpsertPolicy(pType api.PolicyType, policyUpsert *api
 		if !s.licenseService.IsFeatureEnabled(api.FeatureSensitiveData) {
 			return errors.Errorf(api.FeatureSensitiveData.AccessErrorMessage())
 		}
+	case api.PolicyTypeAffectedRowsApproval, api.PolicyTypeAutoApproval:
+		if !s.licenseService.IsFeatureEnabled(api.FeatureCustomApproval) {
+			return errors.Errorf(api.FeatureCustomApproval.AccessErrorMessage())
+		}
+	case api.PolicyTypeOnlineMigration:
+		if !s.licenseService.IsFeatureEnabled(api.FeatureOnlineMigration) {
+			return errors.Errorf(api.FeatureOnlineMigration.AccessErrorMessage())
+		}
+	case api.PolicyTypeExport, api.PolicyTypeDLP:
+		if !s.licenseService.IsFeatureEnabled(api.FeatureAccessControl) {
+			return errors.Errorf(api.FeatureAccessControl.AccessErrorMessage())
+		}
 	}
 	return nil
 }
@@ -453,6 +468,7 @@ func mergeSQLReviewRule(payload string) string {
 			Level:   rule.Level,
 			Comment: rule.Comment,
 			Payload: rule.Payload,
+			Version: rule.Version,
 		})
 	}
 
@@ -464,6 +480,47 @@ func mergeSQLReviewRule(payload string) string {
 	return string(result)
 }
 
+// inheritSQLReviewRuleVersion keeps the versions of the existing rules and the policy if the payload doesn't specify them,
+// and the newly added rules are rolled out in the current version of the policy.
+func inheritSQLReviewRuleVersion(oldPayload string, payload *string) *string {
+	if payload == nil {
+		return nil
+	}
+	oldPolicy, err := api.UnmarshalSQLReviewPolicy(oldPayload)
+	if err != nil {
+		return payload
+	}
+	policy, err := api.UnmarshalSQLReviewPolicy(*payload)
+	if err != nil {
+		return payload
+	}
+
+	if policy.Version == 0 {
+		policy.Version = oldPolicy.Version
+	}
+	versionMap := make(map[string]int)
+	for _, rule := range oldPolicy.RuleList {
+		versionMap[fmt.Sprintf("%s/%s", rule.Type, rule.Engine)] = rule.Version
+	}
+	for _, rule := range policy.RuleList {
+		if rule.Version != 0 {
+			continue
+		}
+		if version, ok := versionMap[fmt.Sprintf("%s/%s", rule.Type, rule.Engine)]; ok {
+			rule.Version = version
+		} else {
+			rule.Version = policy.Version
+		}
+	}
+
+	result, err := json.Marshal(policy)
+	if err != nil {
+		return payload
+	}
+	s := string(result)
+	return &s
+}
+
 func splitSQLReviewRule(payload *string) *string {
 	if payload == nil {
 		return nil
@@ -485,6 +542,7 @@ func splitSQLReviewRule(payload *string) *string {
 					Engine:  db.MySQL,
 					Comment: rule.Comment,
 					Payload: rule.Payload,
+					Version: rule.Version,
 				})
 			}
 			if advisor.RuleExists(rule.Type, db.TiDB) {
@@ -494,6 +552,7 @@ func splitSQLReviewRule(payload *string) *string {
 					Engine:  db.TiDB,
 					Comment: rule.Comment,
 					Payload: rule.Payload,
+					Version: rule.Version,
 				})
 			}
 			if advisor.RuleExists(rule.Type, db.MariaDB) {
@@ -503,6 +562,7 @@ func splitSQLReviewRule(payload *string) *string {
 					Engine:  db.MariaDB,
 					Comment: rule.Comment,
 					Payload: rule.Payload,
+					Version: rule.Version,
 				})
 			}
 			if advisor.RuleExists(rule.Type, db.Postgres) {
@@ -512,6 +572,7 @@ func splitSQLReviewRule(payload *string) *string {
 					Engine:  db.Postgres,
 					Comment: rule.Comment,
 					Payload: rule.Payload,
+					Version: rule.Version,
 				})
 			}
 		}
@@ -22,6 +22,8 @@ import (
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	vcsPlugin "github.com/bytebase/bytebase/backend/plugin/vcs"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/bitbucket"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/bitbucketserver"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/gitea"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/github"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/gitlab"
 	"github.com/bytebase/bytebase/backend/store"
@@ -274,6 +276,17 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create linked repository request: %s", err.Error()))
 		}
 
+		if err := api.ValidateRepositoryMetadataPathTemplate(repositoryCreate.MetadataPathTemplate, repositoryCreate.SchemaPathTemplate, project.TenantMode); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create linked repository request: %s", err.Error()))
+		}
+
+		if (repositoryCreate.SchemaWriteBackPR || repositoryCreate.MetadataPathTemplate != "") && !common.FeatureFlag(common.FeatureFlagSchemaWriteBack) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create linked repository request: schema write-back pull requests and metadata path template are not supported yet")
+		}
+		if repositoryCreate.EnableRollbackPR && !common.FeatureFlag(common.FeatureFlagRollbackPR) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create linked repository request: rollback pull requests are not supported yet")
+		}
+
 		vcs, err := s.store.GetVCSByID(ctx, repositoryCreate.VCSID)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find VCS for creating repository: %d", repositoryCreate.VCSID)).SetInternal(err)
@@ -281,6 +294,19 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		if vcs == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("VCS not found with ID: %d", repositoryCreate.VCSID))
 		}
+		if err := validateReleaseTagFilter(repositoryCreate.ReleaseTagFilter, vcs.Type, project.TenantMode); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create linked repository request: %s", err.Error()))
+		}
+		routingRules, err := s.validateRoutingRules(ctx, repositoryCreate.RoutingRules, project.TenantMode, c.Get(getRoleContextKey()).(api.Role), repositoryCreate.CreatorID)
+		if err != nil {
+			return err
+		}
+		repositoryCreate.RoutingRules = routingRules
+		branchRules, err := s.validateBranchRules(ctx, repositoryCreate.BranchRules, project.TenantMode)
+		if err != nil {
+			return err
+		}
+		repositoryCreate.BranchRules = branchRules
 
 		// When the branch names doesn't contain wildcards, we should make sure the branch exists in the repo.
 		if !strings.Contains(repositoryCreate.BranchFilter, "*") {
@@ -393,13 +419,14 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "SQL review CI is already enabled")
 		}
 
-		pullRequest, err := s.setupVCSSQLReviewCI(ctx, repository)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create SQL review CI").SetInternal(err)
-		}
-
-		response := &api.SQLReviewCISetup{
-			PullRequestURL: pullRequest.URL,
+		response := &api.SQLReviewCISetup{}
+		// Bitbucket Server reviews the pull requests through the webhook, there is no CI to set up.
+		if repository.VCS.Type != vcsPlugin.BitbucketServer {
+			pullRequest, err := s.setupVCSSQLReviewCI(ctx, repository)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create SQL review CI").SetInternal(err)
+			}
+			response.PullRequestURL = pullRequest.URL
 		}
 
 		enabledCI := true
@@ -486,6 +513,29 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			}
 		}
 
+		if repoPatch.RoutingRules != nil {
+			routingRules, err := s.validateRoutingRules(ctx, *repoPatch.RoutingRules, project.TenantMode, c.Get(getRoleContextKey()).(api.Role), repoPatch.UpdaterID)
+			if err != nil {
+				return err
+			}
+			repoPatch.RoutingRules = &routingRules
+		}
+
+		if repoPatch.BranchRules != nil {
+			branchRules, err := s.validateBranchRules(ctx, *repoPatch.BranchRules, project.TenantMode)
+			if err != nil {
+				return err
+			}
+			repoPatch.BranchRules = &branchRules
+		}
+
+		if ((repoPatch.SchemaWriteBackPR != nil && *repoPatch.SchemaWriteBackPR) || (repoPatch.MetadataPathTemplate != nil && *repoPatch.MetadataPathTemplate != "")) && !common.FeatureFlag(common.FeatureFlagSchemaWriteBack) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch linked repository request: schema write-back pull requests and metadata path template are not supported yet")
+		}
+		if v := repoPatch.EnableRollbackPR; v != nil && *v && !common.FeatureFlag(common.FeatureFlagRollbackPR) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch linked repository request: rollback pull requests are not supported yet")
+		}
+
 		// Remove enclosing /
 		if repoPatch.BaseDirectory != nil {
 			baseDir := strings.Trim(*repoPatch.BaseDirectory, "/")
@@ -509,6 +559,18 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 
 		repo := repoList[0]
 		repoPatch.ID = &repo.ID
+		if repoPatch.SchemaPathTemplate != nil || repoPatch.MetadataPathTemplate != nil {
+			newSchemaPathTemplate, newMetadataPathTemplate := repo.SchemaPathTemplate, repo.MetadataPathTemplate
+			if repoPatch.SchemaPathTemplate != nil {
+				newSchemaPathTemplate = *repoPatch.SchemaPathTemplate
+			}
+			if repoPatch.MetadataPathTemplate != nil {
+				newMetadataPathTemplate = *repoPatch.MetadataPathTemplate
+			}
+			if err := api.ValidateRepositoryMetadataPathTemplate(newMetadataPathTemplate, newSchemaPathTemplate, project.TenantMode); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed patch linked repository request: %s", err.Error()))
+			}
+		}
 		newBranchFilter := repo.BranchFilter
 		if repoPatch.BranchFilter != nil {
 			newBranchFilter = *repoPatch.BranchFilter
@@ -524,6 +586,16 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		if vcs == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("VCS not found with ID: %d", repo.VCSID))
 		}
+		if repoPatch.ReleaseTagFilter != nil {
+			if err := validateReleaseTagFilter(*repoPatch.ReleaseTagFilter, vcs.Type, project.TenantMode); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed patch linked repository request: %s", err.Error()))
+			}
+			if *repoPatch.ReleaseTagFilter != "" && repo.ReleaseTagFilter == "" && vcs.Type == vcsPlugin.GitLab {
+				if err := enableGitLabTagPushEvents(ctx, vcs, repo); err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to enable tag push events for the webhook of repository %s", repo.Name)).SetInternal(err)
+				}
+			}
+		}
 
 		// When the branch names doesn't contain wildcards, we should make sure the branch exists in the repo.
 		if !strings.Contains(newBranchFilter, "*") {
@@ -929,6 +1001,10 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 				sheetSource = api.SheetFromGitHub
 			case vcsPlugin.Bitbucket:
 				sheetSource = api.SheetFromBitbucket
+			case vcsPlugin.Gitea:
+				sheetSource = api.SheetFromGitea
+			case vcsPlugin.BitbucketServer:
+				sheetSource = api.SheetFromBitbucketServer
 			}
 			vscSheetType := api.SheetForSQL
 			sheetFind := &api.SheetFind{
@@ -1283,6 +1359,7 @@ func createVCSWebhook(ctx context.Context, vcsType vcsPlugin.Type, webhookEndpoi
 			URL:                   fmt.Sprintf("%s/hook/gitlab/%s", externalURL, webhookEndpointID),
 			SecretToken:           secretToken,
 			PushEvents:            true,
+			TagPushEvents:         true,
 			EnableSSLVerification: false, // TODO(tianzhou): This is set to false, be lax to not enable_ssl_verification
 		}
 		webhookCreatePayload, err = json.Marshal(webhookCreate)
@@ -1314,6 +1391,40 @@ func createVCSWebhook(ctx context.Context, vcsType vcsPlugin.Type, webhookEndpoi
 		if err != nil {
 			return "", errors.Wrap(err, "failed to marshal request body for creating webhook")
 		}
+	case vcsPlugin.Gitea:
+		webhookPost := gitea.WebhookCreateOrUpdate{
+			Type: "gitea",
+			Config: gitea.WebhookConfig{
+				URL:         fmt.Sprintf("%s/hook/gitea/%s", externalURL, webhookEndpointID),
+				ContentType: "json",
+				Secret:      secretToken,
+			},
+			Events: []string{string(gitea.WebhookPush)},
+			Active: true,
+		}
+		webhookCreatePayload, err = json.Marshal(webhookPost)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to marshal request body for creating webhook")
+		}
+	case vcsPlugin.BitbucketServer:
+		webhookPost := bitbucketserver.WebhookCreateOrUpdate{
+			Name:   "Bytebase GitOps",
+			URL:    fmt.Sprintf("%s/hook/bitbucket-server/%s", externalURL, webhookEndpointID),
+			Active: true,
+			// The pull request events are for the SQL review, which is commented on the pull requests.
+			Events: []string{
+				bitbucketserver.WebhookRefsChanged,
+				bitbucketserver.WebhookPullRequestOpened,
+				bitbucketserver.WebhookPullRequestFromRefUpdated,
+			},
+			Configuration: bitbucketserver.WebhookConfiguration{
+				Secret: secretToken,
+			},
+		}
+		webhookCreatePayload, err = json.Marshal(webhookPost)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to marshal request body for creating webhook")
+		}
 	}
 	webhookID, err := vcsPlugin.Get(vcsType, vcsPlugin.ProviderConfig{}).CreateWebhook(
 		ctx,
@@ -1356,6 +1467,93 @@ func isBranchNotFound(ctx context.Context, vcs *api.VCS, store *store.Store, web
 	return false, err
 }
 
+// validateRoutingRules validates the routing rules of the repository and returns the normalized rules. The developers
+// can only route the files to the projects where they are the owner or developer.
+func (s *Server) validateRoutingRules(ctx context.Context, routingRules string, tenantMode api.ProjectTenantMode, role api.Role, principalID int) (string, error) {
+	ruleList, err := api.ParseRepositoryRoutingRules(routingRules)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Malformed routing rules").SetInternal(err)
+	}
+	if len(ruleList) == 0 {
+		return "[]", nil
+	}
+	if !common.FeatureFlag(common.FeatureFlagRoutingRule) {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Routing rules are not supported yet")
+	}
+	if tenantMode == api.TenantModeTenant {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Routing rules are not supported for the tenant mode project")
+	}
+	for _, rule := range ruleList {
+		if rule.PathPattern == "" {
+			return "", echo.NewHTTPError(http.StatusBadRequest, "Path pattern of the routing rule must be specified")
+		}
+		if strings.Contains(strings.ReplaceAll(rule.DatabaseTemplate, "{{DB_NAME}}", ""), "{{") {
+			return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database template %q of the routing rule can only contain {{DB_NAME}}", rule.DatabaseTemplate))
+		}
+		project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &rule.ProjectID})
+		if err != nil {
+			return "", echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project %q", rule.ProjectID)).SetInternal(err)
+		}
+		if project == nil || project.Deleted {
+			return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project %q of the routing rule %q not found", rule.ProjectID, rule.PathPattern))
+		}
+		if project.TenantMode == api.TenantModeTenant {
+			return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Routing the files to the tenant mode project %q is not supported", rule.ProjectID))
+		}
+		if role == api.Developer {
+			policy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{UID: &project.UID})
+			if err != nil {
+				return "", echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the policy of project %q", rule.ProjectID)).SetInternal(err)
+			}
+			if !isProjectOwnerOrDeveloper(principalID, policy) {
+				return "", echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Not allowed to route the files to project %q", rule.ProjectID))
+			}
+		}
+	}
+	normalized, err := json.Marshal(ruleList)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal routing rules").SetInternal(err)
+	}
+	return string(normalized), nil
+}
+
+// validateBranchRules validates the branch rules of the repository and returns the normalized rules.
+func (s *Server) validateBranchRules(ctx context.Context, branchRules string, tenantMode api.ProjectTenantMode) (string, error) {
+	ruleList, err := api.ParseRepositoryBranchRules(branchRules)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Malformed branch rules").SetInternal(err)
+	}
+	if len(ruleList) == 0 {
+		return "[]", nil
+	}
+	if !common.FeatureFlag(common.FeatureFlagBranchRule) {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Branch rules are not supported yet")
+	}
+	if tenantMode == api.TenantModeTenant {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Branch rules are not supported for the tenant mode project")
+	}
+	for _, rule := range ruleList {
+		if rule.Branch == "" {
+			return "", echo.NewHTTPError(http.StatusBadRequest, "Branch of the branch rule must be specified")
+		}
+		if _, err := filepath.Match(rule.Branch, ""); err != nil {
+			return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid branch %q of the branch rule", rule.Branch)).SetInternal(err)
+		}
+		environment, err := s.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{ResourceID: &rule.EnvironmentID})
+		if err != nil {
+			return "", echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch environment %q", rule.EnvironmentID)).SetInternal(err)
+		}
+		if environment == nil || environment.Deleted {
+			return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Environment %q of the branch rule %q not found", rule.EnvironmentID, rule.Branch))
+		}
+	}
+	normalized, err := json.Marshal(ruleList)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal branch rules").SetInternal(err)
+	}
+	return string(normalized), nil
+}
+
 // isProjectOwnerOrDeveloper returns whether a principal is a project owner or developer in the project.
 func isProjectOwnerOrDeveloper(principalID int, projectPolicy *store.IAMPolicyMessage) bool {
 	for _, binding := range projectPolicy.Bindings {
@@ -1371,6 +1569,21 @@ func isProjectOwnerOrDeveloper(principalID int, projectPolicy *store.IAMPolicyMe
 	return false
 }
 
+// isProjectOwner returns whether a principal has the Owner role in the project.
+func isProjectOwner(principalID int, projectPolicy *store.IAMPolicyMessage) bool {
+	for _, binding := range projectPolicy.Bindings {
+		if binding.Role != api.Owner {
+			continue
+		}
+		for _, member := range binding.Members {
+			if member.ID == principalID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // isProjectMember returns whether a principal is a project member in the project,
 // that is the principal has any role in the project.
 func isProjectMember(principalID int, projectPolicy *store.IAMPolicyMessage) bool {
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -10,6 +11,7 @@ import (
 	"github.com/google/jsonapi"
 	"github.com/gosimple/slug"
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/backend/common"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
@@ -66,6 +68,13 @@ func (s *Server) registerProjectWebhookRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, hookCreate); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create project webhook request").SetInternal(err)
 		}
+		if hookCreate.PayloadTemplates != "" && !common.FeatureFlag(common.FeatureFlagWebhookPayloadTemplate) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create project webhook request: payload templates are not supported yet")
+		}
+		payloadTemplates, err := parsePayloadTemplates(hookCreate.PayloadTemplates)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed create project webhook request: %s", err.Error()))
+		}
 		project, err := s.store.GetProjectByID(ctx, projectID)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch project ID: %v", projectID)).SetInternal(err)
@@ -75,10 +84,11 @@ func (s *Server) registerProjectWebhookRoutes(g *echo.Group) {
 		}
 
 		webhook, err := s.store.CreateProjectWebhookV2(ctx, c.Get(getPrincipalIDContextKey()).(int), projectID, project.ResourceID, &store.ProjectWebhookMessage{
-			Type:         hookCreate.Type,
-			Title:        hookCreate.Name,
-			URL:          hookCreate.URL,
-			ActivityList: hookCreate.ActivityList,
+			Type:             hookCreate.Type,
+			Title:            hookCreate.Name,
+			URL:              hookCreate.URL,
+			ActivityList:     hookCreate.ActivityList,
+			PayloadTemplates: payloadTemplates,
 		})
 		if err != nil {
 			if common.ErrorCode(err) == common.Conflict {
@@ -154,11 +164,22 @@ func (s *Server) registerProjectWebhookRoutes(g *echo.Group) {
 		if hookPatch.ActivityList != nil {
 			activityList = strings.Split(*hookPatch.ActivityList, ",")
 		}
+		var payloadTemplates map[string]string
+		if hookPatch.PayloadTemplates != nil {
+			if !common.FeatureFlag(common.FeatureFlagWebhookPayloadTemplate) {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed change project webhook: payload templates are not supported yet")
+			}
+			payloadTemplates, err = parsePayloadTemplates(*hookPatch.PayloadTemplates)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed change project webhook: %s", err.Error()))
+			}
+		}
 
 		webhook, err := s.store.UpdateProjectWebhookV2(ctx, c.Get(getPrincipalIDContextKey()).(int), project.ID, project.ResourceID, id, &store.UpdateProjectWebhookMessage{
-			Title:        hookPatch.Name,
-			URL:          hookPatch.URL,
-			ActivityList: activityList,
+			Title:            hookPatch.Name,
+			URL:              hookPatch.URL,
+			ActivityList:     activityList,
+			PayloadTemplates: payloadTemplates,
 		})
 		if err != nil {
 			if common.ErrorCode(err) == common.NotFound {
@@ -248,17 +269,18 @@ func (s *Server) registerProjectWebhookRoutes(g *echo.Group) {
 		err = webhookPlugin.Post(
 			webhook.Type,
 			webhookPlugin.Context{
-				URL:          webhook.URL,
-				Level:        webhookPlugin.WebhookInfo,
-				ActivityType: string(api.ActivityIssueCreate),
-				Title:        fmt.Sprintf("Test webhook %q", webhook.Title),
-				Description:  "This is a test",
-				Link:         fmt.Sprintf("%s/project/%s/webhook/%s", setting.ExternalUrl, getProjectSlug(project), api.ProjectWebhookSlug(webhook.Title, webhook.ID)),
-				CreatorID:    api.SystemBotID,
-				CreatorName:  "Bytebase",
-				CreatorEmail: "support@bytebase.com",
-				CreatedTs:    time.Now().Unix(),
-				Project:      &webhookPlugin.Project{Name: project.Title},
+				URL:             webhook.URL,
+				Level:           webhookPlugin.WebhookInfo,
+				ActivityType:    string(api.ActivityIssueCreate),
+				Title:           fmt.Sprintf("Test webhook %q", webhook.Title),
+				Description:     "This is a test",
+				Link:            fmt.Sprintf("%s/project/%s/webhook/%s", setting.ExternalUrl, getProjectSlug(project), api.ProjectWebhookSlug(webhook.Title, webhook.ID)),
+				CreatorID:       api.SystemBotID,
+				CreatorName:     "Bytebase",
+				CreatorEmail:    "support@bytebase.com",
+				CreatedTs:       time.Now().Unix(),
+				Project:         &webhookPlugin.Project{Name: project.Title},
+				PayloadTemplate: webhookPlugin.GetPayloadTemplate(webhook.PayloadTemplates, string(api.ActivityIssueCreate)),
 			},
 		)
 
@@ -274,6 +296,26 @@ func (s *Server) registerProjectWebhookRoutes(g *echo.Group) {
 	})
 }
 
+// parsePayloadTemplates parses the JSON encoded payload templates of the project webhook and validates the templates.
+func parsePayloadTemplates(payloadTemplates string) (map[string]string, error) {
+	templates := map[string]string{}
+	if payloadTemplates == "" {
+		return templates, nil
+	}
+	if err := json.Unmarshal([]byte(payloadTemplates), &templates); err != nil {
+		return nil, errors.Wrap(err, "payload templates should be a JSON object of the templates keyed by the activity type")
+	}
+	for activityType, payloadTemplate := range templates {
+		if activityType == "" {
+			return nil, errors.Errorf("activity type of the payload template must be specified")
+		}
+		if _, err := webhookPlugin.ParsePayloadTemplate(payloadTemplate); err != nil {
+			return nil, errors.Wrapf(err, "invalid payload template for %q", activityType)
+		}
+	}
+	return templates, nil
+}
+
 // getProjectSlug is the slug formatter for Project.
 func getProjectSlug(project *store.ProjectMessage) string {
 	return fmt.Sprintf("%s-%d", slug.Make(project.Title), project.UID)
@@ -0,0 +1,364 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	"github.com/bytebase/bytebase/backend/plugin/db/util"
+	"github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/gitlab"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+const releaseTagRefPrefix = "refs/tags/"
+
+func (s *Server) registerReleaseRoutes(g *echo.Group) {
+	g.GET("/project/:projectID/release", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		projectID, err := strconv.Atoi(c.Param("projectID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", c.Param("projectID"))).SetInternal(err)
+		}
+		find := &store.FindReleaseMessage{ProjectUID: &projectID}
+		if tag := c.QueryParam("tag"); tag != "" {
+			find.Tag = &tag
+		}
+		releases, err := s.store.ListReleases(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list releases for project ID: %d", projectID)).SetInternal(err)
+		}
+		var releaseList []*api.Release
+		for _, release := range releases {
+			releaseList = append(releaseList, toAPIRelease(release))
+		}
+		return c.JSON(http.StatusOK, releaseList)
+	})
+}
+
+func toAPIRelease(release *store.ReleaseMessage) *api.Release {
+	return &api.Release{
+		ID:        release.UID,
+		CreatorID: release.CreatorID,
+		CreatedTs: release.CreatedTs,
+		ProjectID: release.ProjectUID,
+		Tag:       release.Tag,
+		CommitID:  release.CommitID,
+		FileList:  release.FileList,
+		Checksum:  release.Checksum,
+	}
+}
+
+// isWebhookEventReleaseTag returns true if the push event ref is a tag matching the release tag filter.
+// The repository is not in the release mode if the filter is empty.
+func isWebhookEventReleaseTag(pushEventRef, releaseTagFilter string) (bool, error) {
+	if releaseTagFilter == "" || !strings.HasPrefix(pushEventRef, releaseTagRefPrefix) {
+		return false, nil
+	}
+	ok, err := filepath.Match(releaseTagFilter, strings.TrimPrefix(pushEventRef, releaseTagRefPrefix))
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to match release tag filter %q", releaseTagFilter)
+	}
+	return ok, nil
+}
+
+// validateReleaseTagFilter validates the release tag filter of the repository. The release mode is only supported for
+// the GitHub and GitLab repositories of the non-tenant projects.
+func validateReleaseTagFilter(releaseTagFilter string, vcsType vcs.Type, tenantMode api.ProjectTenantMode) error {
+	if releaseTagFilter == "" {
+		return nil
+	}
+	if !common.FeatureFlag(common.FeatureFlagVCSRelease) {
+		return errors.Errorf("release tag filter is not supported yet")
+	}
+	if vcsType != vcs.GitHub && vcsType != vcs.GitLab {
+		return errors.Errorf("release tag filter is not supported for %s", vcsType)
+	}
+	if tenantMode == api.TenantModeTenant {
+		return errors.Errorf("release tag filter is not supported for the tenant mode project")
+	}
+	if _, err := filepath.Match(releaseTagFilter, ""); err != nil {
+		return errors.Wrapf(err, "invalid release tag filter %q", releaseTagFilter)
+	}
+	return nil
+}
+
+// enableGitLabTagPushEvents enables the tag push events of the GitLab webhook, which are not enabled for the webhooks
+// created before the release mode.
+func enableGitLabTagPushEvents(ctx context.Context, repoVCS *api.VCS, repo *api.Repository) error {
+	payload, err := json.Marshal(gitlab.WebhookCreate{
+		URL:           fmt.Sprintf("%s/hook/gitlab/%s", repo.WebhookURLHost, repo.WebhookEndpointID),
+		SecretToken:   repo.WebhookSecretToken,
+		PushEvents:    true,
+		TagPushEvents: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request body for patching webhook")
+	}
+	return vcs.Get(vcs.GitLab, vcs.ProviderConfig{}).PatchWebhook(
+		ctx,
+		common.OauthContext{
+			ClientID:     repoVCS.ApplicationID,
+			ClientSecret: repoVCS.Secret,
+			AccessToken:  repo.AccessToken,
+			RefreshToken: repo.RefreshToken,
+			Refresher:    refreshTokenNoop(),
+		},
+		repoVCS.InstanceURL,
+		repo.ExternalID,
+		repo.ExternalWebhookID,
+		payload,
+	)
+}
+
+// releasePushEvent returns the push event for the tag pointing to the commit. The tag push events carry no commit
+// list, so the single commit is the one the files are read from.
+func releasePushEvent(baseVCSPushEvent vcs.PushEvent, commit vcs.Commit) vcs.PushEvent {
+	pushEvent := baseVCSPushEvent
+	if commit.Title == "" {
+		commit.Title = fmt.Sprintf("Release %s", strings.TrimPrefix(pushEvent.Ref, releaseTagRefPrefix))
+	}
+	pushEvent.After = commit.ID
+	pushEvent.CommitList = []vcs.Commit{commit}
+	return pushEvent
+}
+
+// releaseChecksum returns the hex encoded SHA-256 of the paths and the checksums of the files.
+func releaseChecksum(fileList []*api.ReleaseFile) string {
+	h := sha256.New()
+	for _, file := range fileList {
+		_, _ = fmt.Fprintf(h, "%s %s\n", file.Path, file.Checksum)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// processReleasePushEvent builds the releases from the tag push event and rolls out the migration files not yet
+// applied.
+func (s *Server) processReleasePushEvent(ctx context.Context, repositoryList []*api.Repository, baseVCSPushEvent vcs.PushEvent) ([]string, error) {
+	var createdMessageList []string
+	for _, repo := range repositoryList {
+		pushEvent := baseVCSPushEvent
+		pushEvent.VCSType = repo.VCS.Type
+		pushEvent.BaseDirectory = repo.BaseDirectory
+		messageList, err := s.createReleaseAndRollout(ctx, repo, pushEvent)
+		if err != nil {
+			return nil, err
+		}
+		createdMessageList = append(createdMessageList, messageList...)
+	}
+	return createdMessageList, nil
+}
+
+func (s *Server) createReleaseAndRollout(ctx context.Context, repo *api.Repository, pushEvent vcs.PushEvent) ([]string, error) {
+	tag := strings.TrimPrefix(pushEvent.Ref, releaseTagRefPrefix)
+	commitID := pushEvent.After
+	existing, err := s.store.GetRelease(ctx, &store.FindReleaseMessage{ProjectUID: &repo.ProjectID, Tag: &tag})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get release %q", tag)).SetInternal(err)
+	}
+	if existing != nil {
+		// The releases are immutable, moving or re-pushing the tag doesn't change the release.
+		log.Warn("Ignored the push event for the existing release",
+			zap.String("repoURL", repo.WebURL),
+			zap.String("tag", tag),
+			zap.String("releaseCommit", existing.CommitID),
+			zap.String("commit", commitID),
+		)
+		return nil, nil
+	}
+	if repo.Project.TenantMode == api.TenantModeTenant {
+		log.Warn("Ignored the release for the tenant mode project", zap.String("repoURL", repo.WebURL), zap.String("tag", tag))
+		return nil, nil
+	}
+
+	nodes, err := vcs.Get(repo.VCS.Type, vcs.ProviderConfig{}).FetchRepositoryFileList(
+		ctx,
+		common.OauthContext{
+			ClientID:     repo.VCS.ApplicationID,
+			ClientSecret: repo.VCS.Secret,
+			AccessToken:  repo.AccessToken,
+			RefreshToken: repo.RefreshToken,
+			Refresher:    utils.RefreshToken(ctx, s.store, repo.WebURL),
+		},
+		repo.VCS.InstanceURL,
+		repo.ExternalID,
+		commitID,
+		repo.BaseDirectory,
+	)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the file list of release %q", tag)).SetInternal(err)
+	}
+
+	var fileInfoList []fileInfo
+	for _, node := range nodes {
+		item := vcs.DistinctFileItem{
+			Commit:   pushEvent.CommitList[0],
+			FileName: node.Path,
+			ItemType: vcs.FileItemTypeAdded,
+		}
+		migrationInfo, fType, _, err := getFileInfo(item, []*api.Repository{repo})
+		if err != nil {
+			log.Debug("Ignored the release file", zap.String("file", node.Path), zap.Error(err))
+			continue
+		}
+		if fType != fileTypeMigration {
+			continue
+		}
+		routed, err := s.routeFileInfo(ctx, fileInfo{
+			item:          item,
+			migrationInfo: migrationInfo,
+			fType:         fType,
+			repository:    repo,
+		})
+		if err != nil {
+			log.Warn("Failed to route the ignored release file", zap.String("file", node.Path), zap.Error(err))
+			continue
+		}
+		fileInfoList = append(fileInfoList, routed)
+	}
+	fileInfoList = sortFilesBySchemaVersion(fileInfoList)
+
+	setting, err := s.store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get workspace setting").SetInternal(err)
+	}
+	fileList := []*api.ReleaseFile{}
+	var rolloutFileInfoList []fileInfo
+	for _, fileInfo := range fileInfoList {
+		content, err := s.readFileContent(ctx, pushEvent, repo, fileInfo.item.FileName)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to read file %q of release %q", fileInfo.item.FileName, tag)).SetInternal(err)
+		}
+		fileInfo.content = &content
+		checksum := sha256.Sum256([]byte(content))
+		file := &api.ReleaseFile{
+			Path:     fileInfo.item.FileName,
+			Database: fileInfo.migrationInfo.Database,
+			Version:  fileInfo.migrationInfo.Version,
+			Type:     fileInfo.migrationInfo.Type,
+			Checksum: hex.EncodeToString(checksum[:]),
+		}
+		applied, err := s.isMigrationFileApplied(ctx, fileInfo.repository, fileInfo.migrationInfo)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check whether file %q is applied", fileInfo.item.FileName)).SetInternal(err)
+		}
+		file.Applied = applied
+		if !applied {
+			adviceList, err := s.sqlAdviceForFile(ctx, fileInfo, setting.ExternalUrl)
+			if err != nil {
+				adviceList = []advisor.Advice{
+					{
+						Status:  advisor.Warn,
+						Code:    advisor.Internal,
+						Title:   "Failed to review the file",
+						Content: err.Error(),
+						Line:    1,
+					},
+				}
+			}
+			file.AdviceList = adviceList
+			rolloutFileInfoList = append(rolloutFileInfoList, fileInfo)
+		}
+		fileList = append(fileList, file)
+	}
+
+	creatorID := s.getIssueCreatorID(ctx, pushEvent.CommitList[0].AuthorEmail)
+	if _, err := s.store.CreateRelease(ctx, &store.ReleaseMessage{
+		ProjectUID: repo.ProjectID,
+		Tag:        tag,
+		CommitID:   commitID,
+		FileList:   fileList,
+		Checksum:   releaseChecksum(fileList),
+	}, creatorID); err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create release %q", tag)).SetInternal(err)
+	}
+
+	// The files may be routed to the other projects by the routing rules.
+	projectID2FileInfoList := make(map[int][]fileInfo)
+	for _, fileInfo := range rolloutFileInfoList {
+		projectID2FileInfoList[fileInfo.repository.ProjectID] = append(projectID2FileInfoList[fileInfo.repository.ProjectID], fileInfo)
+	}
+	var createdMessageList []string
+	for _, fileInfoListInProject := range projectID2FileInfoList {
+		for _, fileInfoListInDB := range groupFileInfoByDatabase(fileInfoListInProject) {
+			fileInfoListSorted := sortFilesBySchemaVersion(fileInfoListInDB)
+			createdMessage, created, activityCreateList, err := s.processFilesInProject(
+				ctx,
+				pushEvent,
+				fileInfoListSorted[0].repository,
+				fileInfoListSorted,
+			)
+			if err != nil {
+				return nil, err
+			}
+			if created {
+				createdMessageList = append(createdMessageList, createdMessage)
+			} else {
+				for _, activityCreate := range activityCreateList {
+					if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{}); err != nil {
+						log.Warn("Failed to create project activity for the ignored release files", zap.Error(err))
+					}
+				}
+			}
+		}
+	}
+	return createdMessageList, nil
+}
+
+// isMigrationFileApplied returns true if the migration version has been applied to all the databases of the file.
+func (s *Server) isMigrationFileApplied(ctx context.Context, repo *api.Repository, migrationInfo *db.MigrationInfo) (bool, error) {
+	databases, err := s.findProjectDatabases(ctx, repo.ProjectID, migrationInfo.Database, migrationInfo.Environment)
+	if err != nil {
+		// The rollout records the ignored file if no database matches.
+		return false, nil
+	}
+	storedVersion, err := util.ToStoredVersion(false, migrationInfo.Version, "")
+	if err != nil {
+		return false, err
+	}
+	for _, database := range databases {
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+		if err != nil {
+			return false, err
+		}
+		if instance == nil {
+			return false, errors.Errorf("instance %q not found", database.InstanceID)
+		}
+		histories, err := s.store.FindInstanceChangeHistoryList(ctx, &db.MigrationHistoryFind{
+			InstanceID: &instance.UID,
+			DatabaseID: &database.UID,
+			Version:    &storedVersion,
+		})
+		if err != nil {
+			return false, err
+		}
+		applied := false
+		for _, history := range histories {
+			if history.Status == db.Done {
+				applied = true
+				break
+			}
+		}
+		if !applied {
+			return false, nil
+		}
+	}
+	return true, nil
+}
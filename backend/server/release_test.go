@@ -0,0 +1,79 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/vcs"
+)
+
+func TestIsWebhookEventReleaseTag(t *testing.T) {
+	tests := []struct {
+		ref    string
+		filter string
+		want   bool
+	}{
+		{
+			ref:    "refs/tags/v1.0.0",
+			filter: "v*",
+			want:   true,
+		},
+		{
+			ref:    "refs/tags/release/1.0",
+			filter: "release/*",
+			want:   true,
+		},
+		{
+			ref:    "refs/tags/dev-1",
+			filter: "v*",
+			want:   false,
+		},
+		// The repository isn't in the release mode.
+		{
+			ref:    "refs/tags/v1.0.0",
+			filter: "",
+			want:   false,
+		},
+		// The branch push.
+		{
+			ref:    "refs/heads/v1",
+			filter: "v*",
+			want:   false,
+		},
+	}
+	for _, test := range tests {
+		got, err := isWebhookEventReleaseTag(test.ref, test.filter)
+		require.NoError(t, err)
+		assert.Equal(t, test.want, got, test.ref)
+	}
+}
+
+func TestValidateReleaseTagFilter(t *testing.T) {
+	assert.NoError(t, validateReleaseTagFilter("", vcs.Bitbucket, api.TenantModeTenant))
+	if !common.FeatureFlag(common.FeatureFlagVCSRelease) {
+		// The release tag filters are rejected until the schema reaches prod.
+		assert.Error(t, validateReleaseTagFilter("v*", vcs.GitHub, api.TenantModeDisabled))
+		return
+	}
+	assert.NoError(t, validateReleaseTagFilter("v*", vcs.GitHub, api.TenantModeDisabled))
+	assert.Error(t, validateReleaseTagFilter("v*", vcs.Bitbucket, api.TenantModeDisabled))
+	assert.Error(t, validateReleaseTagFilter("v*", vcs.GitLab, api.TenantModeTenant))
+	assert.Error(t, validateReleaseTagFilter("v[", vcs.GitLab, api.TenantModeDisabled))
+}
+
+func TestReleaseChecksum(t *testing.T) {
+	fileList := []*api.ReleaseFile{
+		{Path: "bytebase/db1##0001##migrate##create_t1.sql", Checksum: "a"},
+		{Path: "bytebase/db1##0002##migrate##create_t2.sql", Checksum: "b"},
+	}
+	checksum := releaseChecksum(fileList)
+	assert.Len(t, checksum, 64)
+	assert.Equal(t, checksum, releaseChecksum(fileList))
+
+	fileList[1].Checksum = "c"
+	assert.NotEqual(t, checksum, releaseChecksum(fileList))
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func (s *Server) registerRoleRoutes(g *echo.Group) {
+	g.GET("/role", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		roles, err := s.store.ListRoles(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list roles").SetInternal(err)
+		}
+		plan := s.licenseService.GetEffectivePlan()
+		roleList := []*api.CustomRole{}
+		for _, role := range roles {
+			roleList = append(roleList, toAPICustomRole(role, plan))
+		}
+		return c.JSON(http.StatusOK, roleList)
+	})
+
+	g.PATCH("/role/:roleID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if !s.licenseService.IsFeatureEnabled(api.FeatureCustomRole) {
+			return echo.NewHTTPError(http.StatusForbidden, api.FeatureCustomRole.AccessErrorMessage())
+		}
+		roleID := c.Param("roleID")
+		patch := &api.CustomRolePatch{}
+		if err := json.NewDecoder(c.Request().Body).Decode(patch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch role request").SetInternal(err)
+		}
+		if patch.PermissionList == nil {
+			patch.PermissionList = []api.ProjectPermissionType{}
+		}
+		if err := api.ValidateCustomRolePermissionList(patch.PermissionList); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if _, err := s.getCustomRole(c, roleID); err != nil {
+			return err
+		}
+
+		role, err := s.store.UpdateRole(ctx, &store.UpdateRoleMessage{
+			UpdaterID:      principalID,
+			ResourceID:     roleID,
+			PermissionList: &patch.PermissionList,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update role %q", roleID)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPICustomRole(role, s.licenseService.GetEffectivePlan()))
+	})
+}
+
+func (s *Server) registerRoleMemberRoutes(g *echo.Group) {
+	g.GET("/role/:roleID/member", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		roleID := c.Param("roleID")
+		if _, err := s.getCustomRole(c, roleID); err != nil {
+			return err
+		}
+		members, err := s.store.ListRoleMembers(ctx, &store.FindRoleMemberMessage{Role: &roleID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list the members of role %q", roleID)).SetInternal(err)
+		}
+		memberList := []*api.RoleMember{}
+		for _, member := range members {
+			memberList = append(memberList, toAPIRoleMember(member))
+		}
+		return c.JSON(http.StatusOK, memberList)
+	})
+
+	g.POST("/role/:roleID/member", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if !s.licenseService.IsFeatureEnabled(api.FeatureCustomRole) {
+			return echo.NewHTTPError(http.StatusForbidden, api.FeatureCustomRole.AccessErrorMessage())
+		}
+		roleID := c.Param("roleID")
+		create := &api.RoleMemberCreate{}
+		if err := json.NewDecoder(c.Request().Body).Decode(create); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create role member request").SetInternal(err)
+		}
+		if _, err := s.getCustomRole(c, roleID); err != nil {
+			return err
+		}
+		user, err := s.store.GetUserByID(ctx, create.PrincipalID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch user ID: %d", create.PrincipalID)).SetInternal(err)
+		}
+		if user == nil || user.MemberDeleted {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("User ID not found: %d", create.PrincipalID))
+		}
+		members, err := s.store.ListRoleMembers(ctx, &store.FindRoleMemberMessage{Role: &roleID, PrincipalID: &create.PrincipalID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list the members of role %q", roleID)).SetInternal(err)
+		}
+		if len(members) > 0 {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Role %q is already assigned to user %q", roleID, user.Email))
+		}
+
+		member, err := s.store.CreateRoleMember(ctx, &store.RoleMemberMessage{
+			Role:        roleID,
+			PrincipalID: create.PrincipalID,
+		}, principalID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to assign role %q", roleID)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIRoleMember(member))
+	})
+
+	g.DELETE("/role/:roleID/member/:principalID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		roleID := c.Param("roleID")
+		memberID, err := strconv.Atoi(c.Param("principalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Principal ID is not a number: %s", c.Param("principalID"))).SetInternal(err)
+		}
+		if err := s.store.DeleteRoleMember(ctx, roleID, memberID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unassign role %q", roleID)).SetInternal(err)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+}
+
+// getCustomRole returns the custom role, the built-in roles can't be changed or assigned at the workspace scope.
+func (s *Server) getCustomRole(c echo.Context, roleID string) (*store.RoleMessage, error) {
+	if roleID == string(common.ProjectOwner) || roleID == string(common.ProjectDeveloper) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Role %q is a built-in role", roleID))
+	}
+	role, err := s.store.GetRole(c.Request().Context(), roleID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch role %q", roleID)).SetInternal(err)
+	}
+	if role == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Role %q not found", roleID))
+	}
+	return role, nil
+}
+
+func toAPICustomRole(role *store.RoleMessage, plan api.PlanType) *api.CustomRole {
+	customRole := &api.CustomRole{
+		ResourceID:     role.ResourceID,
+		Name:           role.Name,
+		Description:    role.Description,
+		PermissionList: role.PermissionList,
+	}
+	if role.ResourceID == string(common.ProjectOwner) || role.ResourceID == string(common.ProjectDeveloper) {
+		customRole.BuiltIn = true
+		customRole.PermissionList = []api.ProjectPermissionType{}
+		for _, permission := range api.CustomRolePermissionList {
+			if api.ProjectPermission(permission, plan, map[common.ProjectRole]bool{common.ProjectRole(role.ResourceID): true}) {
+				customRole.PermissionList = append(customRole.PermissionList, permission)
+			}
+		}
+	}
+	return customRole
+}
+
+func toAPIRoleMember(member *store.RoleMemberMessage) *api.RoleMember {
+	return &api.RoleMember{
+		CreatorID:   member.CreatorID,
+		CreatedTs:   member.CreatedTs,
+		Role:        member.Role,
+		PrincipalID: member.PrincipalID,
+	}
+}
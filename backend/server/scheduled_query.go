@@ -0,0 +1,265 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/runner/scheduledquery"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// scheduledQueryRunLimit is the number of the latest runs returned.
+const scheduledQueryRunLimit = 50
+
+func (s *Server) registerScheduledQueryRoutes(g *echo.Group) {
+	g.GET("/scheduled-query", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+
+		find := &store.FindScheduledQueryMessage{}
+		// Workspace Owners and DBAs can see all scheduled queries, the others only see their own.
+		if role != api.Owner && role != api.DBA {
+			find.CreatorID = &principalID
+		}
+		if sheetID := c.QueryParam("sheet"); sheetID != "" {
+			id, err := strconv.Atoi(sheetID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Sheet ID is not a number: %s", sheetID)).SetInternal(err)
+			}
+			find.SheetUID = &id
+		}
+		scheduledQueries, err := s.store.ListScheduledQueries(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list scheduled queries").SetInternal(err)
+		}
+		scheduledQueryList := []*api.ScheduledQuery{}
+		for _, scheduledQuery := range scheduledQueries {
+			scheduledQueryList = append(scheduledQueryList, toAPIScheduledQuery(scheduledQuery))
+		}
+		return c.JSON(http.StatusOK, scheduledQueryList)
+	})
+
+	g.POST("/scheduled-query", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+
+		create := &api.ScheduledQueryCreate{}
+		if err := json.NewDecoder(c.Request().Body).Decode(create); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create scheduled query request").SetInternal(err)
+		}
+		if create.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Scheduled query name is required")
+		}
+		if create.Timezone == "" {
+			create.Timezone = "UTC"
+		}
+		if create.DeliveryList == nil {
+			create.DeliveryList = []*api.ScheduledQueryDelivery{}
+		}
+		if err := api.ValidateScheduledQueryDeliveryList(create.DeliveryList); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		nextRunTs, err := scheduledquery.GetNextRunTs(create.Schedule, create.Timezone, time.Now())
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		sheet, err := s.store.GetSheetV2(ctx, &api.SheetFind{ID: &create.SheetID}, principalID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get sheet ID: %d", create.SheetID)).SetInternal(err)
+		}
+		if sheet == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Sheet not found with ID %d", create.SheetID))
+		}
+		// The statement of the sheet is read at each run, so only the creator of the sheet can schedule it.
+		if sheet.Creator.ID != principalID {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the creator of the sheet can schedule it")
+		}
+		if sheet.DatabaseID == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "The sheet must be connected to a database to be scheduled")
+		}
+		// The cells of the notebooks are run one by one with the parameters prompted.
+		if sheet.Type == api.SheetForNotebook {
+			return echo.NewHTTPError(http.StatusBadRequest, "Notebook sheets cannot be scheduled")
+		}
+
+		scheduledQuery, err := s.store.CreateScheduledQuery(ctx, &store.ScheduledQueryMessage{
+			CreatorID:    principalID,
+			SheetUID:     sheet.UID,
+			Name:         create.Name,
+			Schedule:     create.Schedule,
+			Timezone:     create.Timezone,
+			DeliveryList: create.DeliveryList,
+			Enabled:      true,
+			NextRunTs:    nextRunTs,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create scheduled query").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIScheduledQuery(scheduledQuery))
+	})
+
+	g.PATCH("/scheduled-query/:scheduledQueryID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		scheduledQuery, err := s.getManagedScheduledQuery(c)
+		if err != nil {
+			return err
+		}
+
+		patch := &api.ScheduledQueryPatch{}
+		if err := json.NewDecoder(c.Request().Body).Decode(patch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch scheduled query request").SetInternal(err)
+		}
+		update := &store.UpdateScheduledQueryMessage{
+			ID:           scheduledQuery.ID,
+			UpdaterID:    principalID,
+			Name:         patch.Name,
+			Schedule:     patch.Schedule,
+			Timezone:     patch.Timezone,
+			DeliveryList: patch.DeliveryList,
+			Enabled:      patch.Enabled,
+		}
+		if v := patch.Name; v != nil && *v == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Scheduled query name is required")
+		}
+		if v := patch.DeliveryList; v != nil {
+			if *v == nil {
+				*v = []*api.ScheduledQueryDelivery{}
+			}
+			if err := api.ValidateScheduledQueryDeliveryList(*v); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+		}
+		// The next run is recalculated from now if the schedule changes or the scheduled query is enabled again,
+		// so the runs missed while disabled don't run at once.
+		if patch.Schedule != nil || patch.Timezone != nil || (patch.Enabled != nil && *patch.Enabled) {
+			schedule, timezone := scheduledQuery.Schedule, scheduledQuery.Timezone
+			if v := patch.Schedule; v != nil {
+				schedule = *v
+			}
+			if v := patch.Timezone; v != nil {
+				timezone = *v
+			}
+			nextRunTs, err := scheduledquery.GetNextRunTs(schedule, timezone, time.Now())
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			update.NextRunTs = &nextRunTs
+		}
+
+		scheduledQuery, err = s.store.UpdateScheduledQuery(ctx, update)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch scheduled query ID: %d", update.ID)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIScheduledQuery(scheduledQuery))
+	})
+
+	g.DELETE("/scheduled-query/:scheduledQueryID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		scheduledQuery, err := s.getManagedScheduledQuery(c)
+		if err != nil {
+			return err
+		}
+		if err := s.store.DeleteScheduledQuery(ctx, scheduledQuery.ID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete scheduled query ID: %d", scheduledQuery.ID)).SetInternal(err)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	g.GET("/scheduled-query/:scheduledQueryID/run", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		scheduledQuery, err := s.getManagedScheduledQuery(c)
+		if err != nil {
+			return err
+		}
+		runs, err := s.store.ListScheduledQueryRuns(ctx, &store.FindScheduledQueryRunMessage{ScheduledQueryID: scheduledQuery.ID, Limit: scheduledQueryRunLimit})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list runs of scheduled query ID: %d", scheduledQuery.ID)).SetInternal(err)
+		}
+		runList := []*api.ScheduledQueryRun{}
+		for _, run := range runs {
+			runList = append(runList, toAPIScheduledQueryRun(run))
+		}
+		return c.JSON(http.StatusOK, runList)
+	})
+
+	g.POST("/scheduled-query/:scheduledQueryID/run", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if s.ScheduledQueryRunner == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Scheduled queries cannot run in the readonly mode")
+		}
+		scheduledQuery, err := s.getManagedScheduledQuery(c)
+		if err != nil {
+			return err
+		}
+		run, err := s.ScheduledQueryRunner.Execute(ctx, scheduledQuery)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to run scheduled query ID: %d", scheduledQuery.ID)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIScheduledQueryRun(run))
+	})
+}
+
+// getManagedScheduledQuery gets the scheduled query in the path, which can be managed by its creator and the
+// workspace Owners and DBAs.
+func (s *Server) getManagedScheduledQuery(c echo.Context) (*store.ScheduledQueryMessage, error) {
+	ctx := c.Request().Context()
+	id, err := strconv.Atoi(c.Param("scheduledQueryID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("scheduledQueryID"))).SetInternal(err)
+	}
+	scheduledQuery, err := s.store.GetScheduledQuery(ctx, &store.FindScheduledQueryMessage{ID: &id})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get scheduled query ID: %d", id)).SetInternal(err)
+	}
+	if scheduledQuery == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Scheduled query not found with ID %d", id))
+	}
+	principalID := c.Get(getPrincipalIDContextKey()).(int)
+	role := c.Get(getRoleContextKey()).(api.Role)
+	if scheduledQuery.CreatorID != principalID && role != api.Owner && role != api.DBA {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "Only the creator, workspace Owners and DBAs can manage the scheduled query")
+	}
+	return scheduledQuery, nil
+}
+
+func toAPIScheduledQuery(scheduledQuery *store.ScheduledQueryMessage) *api.ScheduledQuery {
+	return &api.ScheduledQuery{
+		ID:           scheduledQuery.ID,
+		CreatorID:    scheduledQuery.CreatorID,
+		CreatedTs:    scheduledQuery.CreatedTs,
+		UpdaterID:    scheduledQuery.UpdaterID,
+		UpdatedTs:    scheduledQuery.UpdatedTs,
+		SheetID:      scheduledQuery.SheetUID,
+		Name:         scheduledQuery.Name,
+		Schedule:     scheduledQuery.Schedule,
+		Timezone:     scheduledQuery.Timezone,
+		DeliveryList: scheduledQuery.DeliveryList,
+		Enabled:      scheduledQuery.Enabled,
+		NextRunTs:    scheduledQuery.NextRunTs,
+	}
+}
+
+func toAPIScheduledQueryRun(run *store.ScheduledQueryRunMessage) *api.ScheduledQueryRun {
+	deliveryResultList := run.DeliveryResultList
+	if deliveryResultList == nil {
+		deliveryResultList = []*api.ScheduledQueryDeliveryResult{}
+	}
+	return &api.ScheduledQueryRun{
+		ID:                 run.ID,
+		CreatedTs:          run.CreatedTs,
+		ScheduledQueryID:   run.ScheduledQueryID,
+		Status:             run.Status,
+		Result:             run.Result,
+		Error:              run.Error,
+		DeliveryResultList: deliveryResultList,
+	}
+}
@@ -0,0 +1,583 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	scimContentType = "application/scim+json"
+	// scimMaxCount is the maximum number of the resources returned in a page.
+	scimMaxCount = 1000
+)
+
+// registerSCIMRoutes registers the SCIM 2.0 endpoint for the identity providers, e.g. Okta and Azure AD, to provision
+// the users and the groups. The SCIM users are the end users identified by the emails, and the SCIM groups are the
+// user groups.
+func (s *Server) registerSCIMRoutes(g *echo.Group) {
+	g.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return s.authenticateSCIM(c, next)
+		}
+	})
+
+	g.GET("/ServiceProviderConfig", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, scimContentType, []byte(fmt.Sprintf(`{
+	"schemas": [%q],
+	"patch": {"supported": true},
+	"bulk": {"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+	"filter": {"supported": true, "maxResults": %d},
+	"changePassword": {"supported": false},
+	"sort": {"supported": false},
+	"etag": {"supported": false},
+	"authenticationSchemes": [{"type": "oauthbearertoken", "name": "OAuth Bearer Token", "description": "Authentication with the SCIM token of the workspace."}]
+}`, api.SCIMServiceProviderConfigSchema, scimMaxCount)))
+	})
+
+	g.GET("/Users", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		filter, err := api.ParseSCIMFilter(c.QueryParam("filter"))
+		if err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidFilter", err.Error())
+		}
+		endUser := api.EndUser
+		find := &store.FindUserMessage{Type: &endUser, ShowDeleted: true}
+		if filter != nil {
+			switch filter.Attribute {
+			case "username", "emails", "emails.value":
+				email := strings.ToLower(filter.Value)
+				find.Email = &email
+			default:
+				return scimError(c, http.StatusBadRequest, "invalidFilter", fmt.Sprintf("unsupported filter attribute %q", filter.Attribute))
+			}
+		}
+		users, err := s.store.ListUsers(ctx, find)
+		if err != nil {
+			return scimInternalError(c, "Failed to list users", err)
+		}
+		var resources []any
+		for _, user := range users {
+			resources = append(resources, convertToSCIMUser(user))
+		}
+		return scimList(c, resources)
+	})
+
+	g.GET("/Users/:id", func(c echo.Context) error {
+		user, err := s.getSCIMUser(c.Request().Context(), c.Param("id"))
+		if err != nil {
+			return scimInternalError(c, "Failed to get user", err)
+		}
+		if user == nil {
+			return scimError(c, http.StatusNotFound, "", fmt.Sprintf("user %q not found", c.Param("id")))
+		}
+		return scimJSON(c, http.StatusOK, convertToSCIMUser(user))
+	})
+
+	g.POST("/Users", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		scimUser := &api.SCIMUser{}
+		if err := json.NewDecoder(c.Request().Body).Decode(scimUser); err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("malformed user: %v", err))
+		}
+		email, err := scimUser.Email()
+		if err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+		}
+		existing, err := s.store.GetUser(ctx, &store.FindUserMessage{Email: &email, ShowDeleted: true})
+		if err != nil {
+			return scimInternalError(c, "Failed to get user", err)
+		}
+		if existing != nil {
+			return scimError(c, http.StatusConflict, "uniqueness", fmt.Sprintf("user %q already exists", email))
+		}
+		// The provisioned users sign in with SSO, so the password is never used.
+		password, err := common.RandomString(20)
+		if err != nil {
+			return scimInternalError(c, "Failed to generate random password", err)
+		}
+		passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return scimInternalError(c, "Failed to generate password hash", err)
+		}
+		user, err := s.store.CreateUser(ctx, &store.UserMessage{
+			Email:        email,
+			Name:         scimUser.GetDisplayName(),
+			Type:         api.EndUser,
+			PasswordHash: string(passwordHash),
+		}, api.SystemBotID)
+		if err != nil {
+			return scimInternalError(c, "Failed to create user", err)
+		}
+		if !scimUser.IsActive() {
+			deleted := true
+			if user, err = s.store.UpdateUser(ctx, user.ID, &store.UpdateUserMessage{Delete: &deleted}, api.SystemBotID); err != nil {
+				return scimInternalError(c, "Failed to deactivate user", err)
+			}
+		}
+		return scimJSON(c, http.StatusCreated, convertToSCIMUser(user))
+	})
+
+	g.PUT("/Users/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		user, err := s.getSCIMUser(ctx, c.Param("id"))
+		if err != nil {
+			return scimInternalError(c, "Failed to get user", err)
+		}
+		if user == nil {
+			return scimError(c, http.StatusNotFound, "", fmt.Sprintf("user %q not found", c.Param("id")))
+		}
+		scimUser := &api.SCIMUser{}
+		if err := json.NewDecoder(c.Request().Body).Decode(scimUser); err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("malformed user: %v", err))
+		}
+		return s.updateSCIMUser(c, user, scimUser)
+	})
+
+	g.PATCH("/Users/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		user, err := s.getSCIMUser(ctx, c.Param("id"))
+		if err != nil {
+			return scimInternalError(c, "Failed to get user", err)
+		}
+		if user == nil {
+			return scimError(c, http.StatusNotFound, "", fmt.Sprintf("user %q not found", c.Param("id")))
+		}
+		request := &api.SCIMPatchRequest{}
+		if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("malformed patch: %v", err))
+		}
+		scimUser := convertToSCIMUser(user)
+		if err := api.ApplySCIMUserPatch(scimUser, request.Operations); err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+		}
+		return s.updateSCIMUser(c, user, scimUser)
+	})
+
+	// The users are deactivated rather than deleted, so that their activities are kept.
+	g.DELETE("/Users/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		user, err := s.getSCIMUser(ctx, c.Param("id"))
+		if err != nil {
+			return scimInternalError(c, "Failed to get user", err)
+		}
+		if user == nil {
+			return scimError(c, http.StatusNotFound, "", fmt.Sprintf("user %q not found", c.Param("id")))
+		}
+		if !user.MemberDeleted {
+			if err := s.checkSCIMDeactivation(ctx, user); err != nil {
+				if common.ErrorCode(err) == common.Invalid {
+					return scimError(c, http.StatusBadRequest, "mutability", err.Error())
+				}
+				return scimInternalError(c, "Failed to check user deactivation", err)
+			}
+			deleted := true
+			if _, err := s.store.UpdateUser(ctx, user.ID, &store.UpdateUserMessage{Delete: &deleted}, api.SystemBotID); err != nil {
+				return scimInternalError(c, "Failed to deactivate user", err)
+			}
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	g.GET("/Groups", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		filter, err := api.ParseSCIMFilter(c.QueryParam("filter"))
+		if err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidFilter", err.Error())
+		}
+		find := &store.FindUserGroupMessage{}
+		if filter != nil {
+			switch filter.Attribute {
+			case "displayname":
+				find.Title = &filter.Value
+			default:
+				return scimError(c, http.StatusBadRequest, "invalidFilter", fmt.Sprintf("unsupported filter attribute %q", filter.Attribute))
+			}
+		}
+		groups, err := s.store.ListUserGroups(ctx, find)
+		if err != nil {
+			return scimInternalError(c, "Failed to list user groups", err)
+		}
+		// The members are excluded from the list as Azure AD does, it gets the group for the members.
+		excludeMembers := strings.Contains(c.QueryParam("excludedAttributes"), "members")
+		var resources []any
+		for _, group := range groups {
+			scimGroup, err := s.convertToSCIMGroup(ctx, group)
+			if err != nil {
+				return scimInternalError(c, "Failed to convert user group", err)
+			}
+			if excludeMembers {
+				scimGroup.Members = nil
+			}
+			resources = append(resources, scimGroup)
+		}
+		return scimList(c, resources)
+	})
+
+	g.GET("/Groups/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		group, err := s.getSCIMGroup(ctx, c.Param("id"))
+		if err != nil {
+			return scimInternalError(c, "Failed to get user group", err)
+		}
+		if group == nil {
+			return scimError(c, http.StatusNotFound, "", fmt.Sprintf("group %q not found", c.Param("id")))
+		}
+		scimGroup, err := s.convertToSCIMGroup(ctx, group)
+		if err != nil {
+			return scimInternalError(c, "Failed to convert user group", err)
+		}
+		return scimJSON(c, http.StatusOK, scimGroup)
+	})
+
+	g.POST("/Groups", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		scimGroup := &api.SCIMGroup{}
+		if err := json.NewDecoder(c.Request().Body).Decode(scimGroup); err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("malformed group: %v", err))
+		}
+		if scimGroup.DisplayName == "" {
+			return scimError(c, http.StatusBadRequest, "invalidValue", "displayName is required")
+		}
+		existing, err := s.store.GetUserGroup(ctx, &store.FindUserGroupMessage{Title: &scimGroup.DisplayName})
+		if err != nil {
+			return scimInternalError(c, "Failed to get user group", err)
+		}
+		if existing != nil {
+			return scimError(c, http.StatusConflict, "uniqueness", fmt.Sprintf("group %q already exists", scimGroup.DisplayName))
+		}
+		memberIDs, err := s.getSCIMMemberIDs(ctx, scimGroup.Members)
+		if err != nil {
+			if common.ErrorCode(err) == common.Invalid {
+				return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+			}
+			return scimInternalError(c, "Failed to get group members", err)
+		}
+		group, err := s.store.CreateUserGroup(ctx, &store.UserGroupMessage{
+			Title:      scimGroup.DisplayName,
+			ExternalID: scimGroup.ExternalID,
+			MemberIDs:  memberIDs,
+		}, api.SystemBotID)
+		if err != nil {
+			return scimInternalError(c, "Failed to create user group", err)
+		}
+		if scimGroup, err = s.convertToSCIMGroup(ctx, group); err != nil {
+			return scimInternalError(c, "Failed to convert user group", err)
+		}
+		return scimJSON(c, http.StatusCreated, scimGroup)
+	})
+
+	g.PUT("/Groups/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		group, err := s.getSCIMGroup(ctx, c.Param("id"))
+		if err != nil {
+			return scimInternalError(c, "Failed to get user group", err)
+		}
+		if group == nil {
+			return scimError(c, http.StatusNotFound, "", fmt.Sprintf("group %q not found", c.Param("id")))
+		}
+		scimGroup := &api.SCIMGroup{}
+		if err := json.NewDecoder(c.Request().Body).Decode(scimGroup); err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("malformed group: %v", err))
+		}
+		return s.updateSCIMGroup(c, group, scimGroup)
+	})
+
+	g.PATCH("/Groups/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		group, err := s.getSCIMGroup(ctx, c.Param("id"))
+		if err != nil {
+			return scimInternalError(c, "Failed to get user group", err)
+		}
+		if group == nil {
+			return scimError(c, http.StatusNotFound, "", fmt.Sprintf("group %q not found", c.Param("id")))
+		}
+		request := &api.SCIMPatchRequest{}
+		if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("malformed patch: %v", err))
+		}
+		scimGroup, err := s.convertToSCIMGroup(ctx, group)
+		if err != nil {
+			return scimInternalError(c, "Failed to convert user group", err)
+		}
+		if err := api.ApplySCIMGroupPatch(scimGroup, request.Operations); err != nil {
+			return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+		}
+		return s.updateSCIMGroup(c, group, scimGroup)
+	})
+
+	g.DELETE("/Groups/:id", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		group, err := s.getSCIMGroup(ctx, c.Param("id"))
+		if err != nil {
+			return scimInternalError(c, "Failed to get user group", err)
+		}
+		if group == nil {
+			return scimError(c, http.StatusNotFound, "", fmt.Sprintf("group %q not found", c.Param("id")))
+		}
+		if err := s.store.DeleteUserGroup(ctx, group.ID); err != nil {
+			return scimInternalError(c, "Failed to delete user group", err)
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+}
+
+// authenticateSCIM authenticates the identity provider by the bearer token of the SCIM setting.
+func (s *Server) authenticateSCIM(c echo.Context, next echo.HandlerFunc) error {
+	ctx := c.Request().Context()
+	if !s.licenseService.IsFeatureEnabled(api.FeatureSSO) {
+		return scimError(c, http.StatusForbidden, "", api.FeatureSSO.AccessErrorMessage())
+	}
+	settingName := api.SettingWorkspaceSCIM
+	setting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &settingName})
+	if err != nil {
+		return scimInternalError(c, "Failed to get SCIM setting", err)
+	}
+	if setting == nil || setting.Value == "" {
+		return scimError(c, http.StatusUnauthorized, "", "SCIM provisioning is not enabled")
+	}
+	value, err := api.ValidateAndGetSCIMSetting(setting.Value)
+	if err != nil {
+		return scimInternalError(c, "Invalid SCIM setting", err)
+	}
+	if !value.Enabled {
+		return scimError(c, http.StatusUnauthorized, "", "SCIM provisioning is not enabled")
+	}
+	token := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(value.Token)) != 1 {
+		return scimError(c, http.StatusUnauthorized, "", "invalid SCIM token")
+	}
+	return next(c)
+}
+
+func (s *Server) getSCIMUser(ctx context.Context, id string) (*store.UserMessage, error) {
+	uid, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil
+	}
+	endUser := api.EndUser
+	return s.store.GetUser(ctx, &store.FindUserMessage{ID: &uid, Type: &endUser, ShowDeleted: true})
+}
+
+func (s *Server) getSCIMGroup(ctx context.Context, id string) (*store.UserGroupMessage, error) {
+	uid, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil
+	}
+	return s.store.GetUserGroup(ctx, &store.FindUserGroupMessage{ID: &uid})
+}
+
+// updateSCIMUser updates the user to the replaced or the patched SCIM user.
+func (s *Server) updateSCIMUser(c echo.Context, user *store.UserMessage, scimUser *api.SCIMUser) error {
+	ctx := c.Request().Context()
+	email, err := scimUser.Email()
+	if err != nil {
+		return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+	}
+	patch := &store.UpdateUserMessage{}
+	if email != user.Email {
+		existing, err := s.store.GetUser(ctx, &store.FindUserMessage{Email: &email, ShowDeleted: true})
+		if err != nil {
+			return scimInternalError(c, "Failed to get user", err)
+		}
+		if existing != nil {
+			return scimError(c, http.StatusConflict, "uniqueness", fmt.Sprintf("user %q already exists", email))
+		}
+		patch.Email = &email
+	}
+	if name := scimUser.GetDisplayName(); name != user.Name {
+		patch.Name = &name
+	}
+	if deleted := !scimUser.IsActive(); deleted != user.MemberDeleted {
+		if deleted {
+			if err := s.checkSCIMDeactivation(ctx, user); err != nil {
+				if common.ErrorCode(err) == common.Invalid {
+					return scimError(c, http.StatusBadRequest, "mutability", err.Error())
+				}
+				return scimInternalError(c, "Failed to check user deactivation", err)
+			}
+		}
+		patch.Delete = &deleted
+	}
+	if patch.Email != nil || patch.Name != nil || patch.Delete != nil {
+		if user, err = s.store.UpdateUser(ctx, user.ID, patch, api.SystemBotID); err != nil {
+			return scimInternalError(c, "Failed to update user", err)
+		}
+	}
+	return scimJSON(c, http.StatusOK, convertToSCIMUser(user))
+}
+
+// checkSCIMDeactivation refuses to deactivate the last active workspace owner, which locks everyone out.
+func (s *Server) checkSCIMDeactivation(ctx context.Context, user *store.UserMessage) error {
+	if user.Role != api.Owner {
+		return nil
+	}
+	owner := api.Owner
+	owners, err := s.store.ListUsers(ctx, &store.FindUserMessage{Role: &owner})
+	if err != nil {
+		return err
+	}
+	for _, o := range owners {
+		if o.ID != user.ID && o.Type == api.EndUser {
+			return nil
+		}
+	}
+	return common.Errorf(common.Invalid, "cannot deactivate the last workspace owner %q", user.Email)
+}
+
+// updateSCIMGroup updates the user group to the replaced or the patched SCIM group.
+func (s *Server) updateSCIMGroup(c echo.Context, group *store.UserGroupMessage, scimGroup *api.SCIMGroup) error {
+	ctx := c.Request().Context()
+	if scimGroup.DisplayName == "" {
+		return scimError(c, http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+	patch := &store.UpdateUserGroupMessage{}
+	if scimGroup.DisplayName != group.Title {
+		existing, err := s.store.GetUserGroup(ctx, &store.FindUserGroupMessage{Title: &scimGroup.DisplayName})
+		if err != nil {
+			return scimInternalError(c, "Failed to get user group", err)
+		}
+		if existing != nil {
+			return scimError(c, http.StatusConflict, "uniqueness", fmt.Sprintf("group %q already exists", scimGroup.DisplayName))
+		}
+		patch.Title = &scimGroup.DisplayName
+	}
+	if scimGroup.ExternalID != group.ExternalID {
+		patch.ExternalID = &scimGroup.ExternalID
+	}
+	memberIDs, err := s.getSCIMMemberIDs(ctx, scimGroup.Members)
+	if err != nil {
+		if common.ErrorCode(err) == common.Invalid {
+			return scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+		}
+		return scimInternalError(c, "Failed to get group members", err)
+	}
+	patch.MemberIDs = &memberIDs
+	group, err = s.store.UpdateUserGroup(ctx, group.ID, patch, api.SystemBotID)
+	if err != nil {
+		return scimInternalError(c, "Failed to update user group", err)
+	}
+	if scimGroup, err = s.convertToSCIMGroup(ctx, group); err != nil {
+		return scimInternalError(c, "Failed to convert user group", err)
+	}
+	return scimJSON(c, http.StatusOK, scimGroup)
+}
+
+// getSCIMMemberIDs returns the IDs of the users of the group members.
+func (s *Server) getSCIMMemberIDs(ctx context.Context, members []*api.SCIMMember) ([]int, error) {
+	var memberIDs []int
+	for _, member := range members {
+		user, err := s.getSCIMUser(ctx, member.Value)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, common.Errorf(common.Invalid, "member %q is not a provisioned user", member.Value)
+		}
+		memberIDs = append(memberIDs, user.ID)
+	}
+	return memberIDs, nil
+}
+
+func convertToSCIMUser(user *store.UserMessage) *api.SCIMUser {
+	active := !user.MemberDeleted
+	return &api.SCIMUser{
+		Schemas:     []string{api.SCIMUserSchema},
+		ID:          strconv.Itoa(user.ID),
+		UserName:    user.Email,
+		Name:        &api.SCIMName{Formatted: user.Name},
+		DisplayName: user.Name,
+		Emails:      []*api.SCIMEmail{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta:        &api.SCIMMeta{ResourceType: "User"},
+	}
+}
+
+func (s *Server) convertToSCIMGroup(ctx context.Context, group *store.UserGroupMessage) (*api.SCIMGroup, error) {
+	scimGroup := &api.SCIMGroup{
+		Schemas:     []string{api.SCIMGroupSchema},
+		ID:          strconv.Itoa(group.ID),
+		ExternalID:  group.ExternalID,
+		DisplayName: group.Title,
+		Meta: &api.SCIMMeta{
+			ResourceType: "Group",
+			Created:      time.Unix(group.CreatedTs, 0).UTC().Format(time.RFC3339),
+			LastModified: time.Unix(group.UpdatedTs, 0).UTC().Format(time.RFC3339),
+		},
+	}
+	for _, memberID := range group.MemberIDs {
+		user, err := s.store.GetUserByID(ctx, memberID)
+		if err != nil {
+			return nil, err
+		}
+		member := &api.SCIMMember{Value: strconv.Itoa(memberID)}
+		if user != nil {
+			member.Display = user.Email
+		}
+		scimGroup.Members = append(scimGroup.Members, member)
+	}
+	return scimGroup, nil
+}
+
+// scimList returns the page of the resources by the 1-based startIndex and the count.
+func scimList(c echo.Context, resources []any) error {
+	startIndex, err := strconv.Atoi(c.QueryParam("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(c.QueryParam("count"))
+	if err != nil || count < 0 || count > scimMaxCount {
+		count = scimMaxCount
+	}
+	page := []any{}
+	if startIndex <= len(resources) {
+		end := startIndex - 1 + count
+		if end > len(resources) {
+			end = len(resources)
+		}
+		page = resources[startIndex-1 : end]
+	}
+	return scimJSON(c, http.StatusOK, &api.SCIMListResponse{
+		Schemas:      []string{api.SCIMListResponseSchema},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	})
+}
+
+func scimJSON(c echo.Context, status int, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return scimInternalError(c, "Failed to marshal response", err)
+	}
+	return c.Blob(status, scimContentType, b)
+}
+
+func scimError(c echo.Context, status int, scimType, detail string) error {
+	return scimJSON(c, status, &api.SCIMError{
+		Schemas:  []string{api.SCIMErrorSchema},
+		Status:   strconv.Itoa(status),
+		SCIMType: scimType,
+		Detail:   detail,
+	})
+}
+
+func scimInternalError(c echo.Context, message string, err error) error {
+	log.Error(message, zap.String("path", c.Request().URL.Path), zap.Error(err))
+	return scimError(c, http.StatusInternalServerError, "", message)
+}
@@ -44,9 +44,13 @@ import (
 	v1 "github.com/bytebase/bytebase/backend/api/v1"
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/common/tracing"
 	"github.com/bytebase/bytebase/backend/component/activity"
 	"github.com/bytebase/bytebase/backend/component/config"
 	"github.com/bytebase/bytebase/backend/component/dbfactory"
+	"github.com/bytebase/bytebase/backend/component/externalsecret"
+	"github.com/bytebase/bytebase/backend/component/querycursor"
+	"github.com/bytebase/bytebase/backend/component/runningquery"
 	"github.com/bytebase/bytebase/backend/component/state"
 	enterpriseAPI "github.com/bytebase/bytebase/backend/enterprise/api"
 	enterpriseService "github.com/bytebase/bytebase/backend/enterprise/service"
@@ -57,23 +61,37 @@ import (
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
 	advisorDb "github.com/bytebase/bytebase/backend/plugin/advisor/db"
 	"github.com/bytebase/bytebase/backend/plugin/app/feishu"
+	"github.com/bytebase/bytebase/backend/plugin/app/slack"
+	"github.com/bytebase/bytebase/backend/plugin/app/teams"
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	metricPlugin "github.com/bytebase/bytebase/backend/plugin/metric"
 	bbs3 "github.com/bytebase/bytebase/backend/plugin/storage/s3"
 	"github.com/bytebase/bytebase/backend/resources/mongoutil"
 	"github.com/bytebase/bytebase/backend/resources/mysqlutil"
 	"github.com/bytebase/bytebase/backend/resources/postgres"
+	"github.com/bytebase/bytebase/backend/runner/alerting"
 	"github.com/bytebase/bytebase/backend/runner/anomaly"
 	"github.com/bytebase/bytebase/backend/runner/approval"
 	"github.com/bytebase/bytebase/backend/runner/apprun"
+	"github.com/bytebase/bytebase/backend/runner/auditsink"
 	"github.com/bytebase/bytebase/backend/runner/backuprun"
+	"github.com/bytebase/bytebase/backend/runner/clouddiscovery"
+	"github.com/bytebase/bytebase/backend/runner/databaseclone"
+	"github.com/bytebase/bytebase/backend/runner/databasegrant"
 	"github.com/bytebase/bytebase/backend/runner/mail"
 	"github.com/bytebase/bytebase/backend/runner/metricreport"
+	"github.com/bytebase/bytebase/backend/runner/partitionrun"
+	"github.com/bytebase/bytebase/backend/runner/piiscan"
+	"github.com/bytebase/bytebase/backend/runner/queryhistory"
 	"github.com/bytebase/bytebase/backend/runner/rollbackrun"
+	"github.com/bytebase/bytebase/backend/runner/scheduledquery"
 	"github.com/bytebase/bytebase/backend/runner/schemasync"
 	"github.com/bytebase/bytebase/backend/runner/slowquerysync"
+	"github.com/bytebase/bytebase/backend/runner/ssogroupsync"
 	"github.com/bytebase/bytebase/backend/runner/taskcheck"
 	"github.com/bytebase/bytebase/backend/runner/taskrun"
+	"github.com/bytebase/bytebase/backend/runner/unusedindexsync"
+	"github.com/bytebase/bytebase/backend/runner/webhookevent"
 	"github.com/bytebase/bytebase/backend/store"
 	_ "github.com/bytebase/bytebase/docs/openapi" // initial the swagger doc
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
@@ -102,6 +120,14 @@ import (
 	_ "github.com/bytebase/bytebase/backend/plugin/db/mssql"
 	// Register redshift driver.
 	_ "github.com/bytebase/bytebase/backend/plugin/db/redshift"
+	// Register elasticsearch driver.
+	_ "github.com/bytebase/bytebase/backend/plugin/db/elasticsearch"
+	// Register cassandra driver.
+	_ "github.com/bytebase/bytebase/backend/plugin/db/cassandra"
+	// Register dynamodb driver.
+	_ "github.com/bytebase/bytebase/backend/plugin/db/dynamodb"
+	// Register neo4j driver.
+	_ "github.com/bytebase/bytebase/backend/plugin/db/neo4j"
 	// Register pingcap parser driver.
 	_ "github.com/pingcap/tidb/types/parser_driver"
 	// Register fake advisor.
@@ -110,9 +136,27 @@ import (
 	_ "github.com/bytebase/bytebase/backend/plugin/advisor/mysql"
 	// Register postgresql advisor.
 	_ "github.com/bytebase/bytebase/backend/plugin/advisor/pg"
+	// Register mongodb advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/mongodb"
+	// Register redis advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/redis"
+	// Register elasticsearch advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/elasticsearch"
+	// Register cassandra advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/cassandra"
+	// Register dynamodb advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/dynamodb"
+	// Register neo4j advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/neo4j"
+	// Register snowflake advisor.
+	_ "github.com/bytebase/bytebase/backend/plugin/advisor/snowflake"
 
 	// Register mysql differ driver.
 	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/differ/mysql"
+	// Register mssql differ driver.
+	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/differ/mssql"
+	// Register oracle differ driver.
+	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/differ/oracle"
 	// Register postgres differ driver.
 	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/differ/pg"
 	// Register mysql edit driver.
@@ -121,8 +165,12 @@ import (
 	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/edit/pg"
 	// Register postgres parser driver.
 	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/engine/pg"
+	// Register mssql transform driver.
+	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/transform/mssql"
 	// Register mysql transform driver.
 	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/transform/mysql"
+	// Register oracle transform driver.
+	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/transform/oracle"
 )
 
 const (
@@ -132,26 +180,48 @@ const (
 	webhookAPIPrefix = "/hook"
 	// openAPIPrefix is the API prefix for Bytebase OpenAPI.
 	openAPIPrefix = "/v1"
+	// scimAPIPrefix is the API prefix for the SCIM provisioning.
+	scimAPIPrefix = "/scim/v2"
 )
 
 // Server is the Bytebase server.
 type Server struct {
 	// Asynchronous runners.
-	TaskScheduler      *taskrun.Scheduler
-	TaskCheckScheduler *taskcheck.Scheduler
-	MetricReporter     *metricreport.Reporter
-	SchemaSyncer       *schemasync.Syncer
-	SlowQuerySyncer    *slowquerysync.Syncer
-	MailSender         *mail.SlowQueryWeeklyMailSender
-	BackupRunner       *backuprun.Runner
-	AnomalyScanner     *anomaly.Scanner
-	ApplicationRunner  *apprun.Runner
-	RollbackRunner     *rollbackrun.Runner
-	ApprovalRunner     *approval.Runner
-	runnerWG           sync.WaitGroup
+	TaskScheduler        *taskrun.Scheduler
+	TaskCheckScheduler   *taskcheck.Scheduler
+	MetricReporter       *metricreport.Reporter
+	SchemaSyncer         *schemasync.Syncer
+	SlowQuerySyncer      *slowquerysync.Syncer
+	UnusedIndexSyncer    *unusedindexsync.Syncer
+	MailSender           *mail.SlowQueryWeeklyMailSender
+	BackupRunner         *backuprun.Runner
+	AnomalyScanner       *anomaly.Scanner
+	ApplicationRunner    *apprun.Runner
+	RollbackRunner       *rollbackrun.Runner
+	ApprovalRunner       *approval.Runner
+	ApprovalChainRunner  *approval.ChainRunner
+	PartitionRunner      *partitionrun.Runner
+	ScheduledQueryRunner *scheduledquery.Runner
+	QueryHistoryCleaner  *queryhistory.Cleaner
+	DatabaseGrantExpirer *databasegrant.Expirer
+	DatabaseCloner       *databaseclone.Cloner
+	AuditSinkStreamer    *auditsink.Streamer
+	Alerter              *alerting.Alerter
+	CloudDiscoverer      *clouddiscovery.Discoverer
+	PIIScanner           *piiscan.Scanner
+	SSOGroupSyncer       *ssogroupsync.Syncer
+	WebhookEventCleaner  *webhookevent.Cleaner
+	runnerWG             sync.WaitGroup
 
 	ActivityManager *activity.Manager
 
+	// externalSecretManager resolves and renews the data source credentials in the external secret managers.
+	externalSecretManager *externalsecret.Manager
+	// queryCursorManager keeps the materialized query results paged through by the SQL editor.
+	queryCursorManager *querycursor.Manager
+	// runningQueryManager tracks the running queries of the SQL editor to cancel them.
+	runningQueryManager *runningquery.Manager
+
 	licenseService enterpriseAPI.LicenseService
 
 	// SchemaVersion is the bytebase's schema version
@@ -166,6 +236,10 @@ type Server struct {
 	startedTs       int64
 	secret          string
 	errorRecordRing api.ErrorRecordRing
+	// maskingSecret derives the keys of the deterministic masking algorithms of the sensitive data.
+	maskingSecret string
+	// tracerProvider exports the traces if the OTLP endpoint is set.
+	tracerProvider *tracing.Provider
 
 	// MySQL utility binaries
 	mysqlBinDir string
@@ -176,6 +250,10 @@ type Server struct {
 
 	s3Client       *bbs3.Client
 	feishuProvider *feishu.Provider
+	slackProvider  *slack.Provider
+	teamsProvider  *teams.Provider
+	// reviewService approves the reviews from the Slack app as the approval API does.
+	reviewService *v1.ReviewService
 
 	// stateCfg is the shared in-momory state within the server.
 	stateCfg *state.State
@@ -235,6 +313,7 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	log.Info(fmt.Sprintf("backupBucket=%s", profile.BackupBucket))
 	log.Info(fmt.Sprintf("backupRegion=%s", profile.BackupRegion))
 	log.Info(fmt.Sprintf("backupCredentialFile=%s", profile.BackupCredentialFile))
+	log.Info(fmt.Sprintf("otlpEndpoint=%s", profile.OTLPEndpoint))
 	log.Info("-----Config END-------")
 
 	serverStarted := false
@@ -245,6 +324,12 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	}()
 
 	var err error
+	if profile.OTLPEndpoint != "" {
+		if s.tracerProvider, err = tracing.Init(profile.OTLPEndpoint, "bytebase", profile.Version); err != nil {
+			return nil, errors.Wrap(err, "failed to init tracing")
+		}
+	}
+
 	// Install mysqlutil
 	s.mysqlBinDir, err = mysqlutil.Install(profile.ResourceDir)
 	if err != nil {
@@ -328,19 +413,24 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		return nil, errors.Wrap(err, "failed to init config")
 	}
 	s.secret = config.secret
+	s.maskingSecret = config.maskingSecret
 
 	s.ActivityManager = activity.NewManager(storeInstance)
-	s.dbFactory = dbfactory.New(s.mysqlBinDir, s.mongoBinDir, s.pgBinDir, profile.DataDir, s.secret)
+	s.externalSecretManager = externalsecret.NewManager()
+	s.queryCursorManager = querycursor.NewManager(profile.DataDir)
+	s.runningQueryManager = runningquery.NewManager()
+	s.dbFactory = dbfactory.New(s.mysqlBinDir, s.mongoBinDir, s.pgBinDir, profile.DataDir, s.secret, s.store, s.externalSecretManager)
 	e := echo.New()
 	e.Debug = profile.Debug
 	e.HideBanner = true
 	e.HidePort = true
 	e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
 		Skipper: func(c echo.Context) bool {
-			// Skip grpc and webhook calls.
+			// Skip grpc, webhook and streaming calls.
 			return strings.HasPrefix(c.Request().URL.Path, "/bytebase.v1.") ||
 				strings.HasPrefix(c.Request().URL.Path, webhookAPIPrefix) ||
-				strings.HasPrefix(c.Request().URL.Path, "/api/sheet/")
+				strings.HasPrefix(c.Request().URL.Path, "/api/sheet/") ||
+				strings.HasSuffix(c.Request().URL.Path, "/stream")
 		},
 		Timeout: 30 * time.Second,
 	}))
@@ -406,15 +496,29 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	}
 
 	s.MetricReporter = metricreport.NewReporter(s.store, s.licenseService, &s.profile, false)
+	// The SSO group syncer applies the mapping on the SSO logins, which are allowed in the readonly mode.
+	s.SSOGroupSyncer = ssogroupsync.NewSyncer(storeInstance, s.licenseService)
 	if !profile.Readonly {
 		s.SchemaSyncer = schemasync.NewSyncer(storeInstance, s.dbFactory, s.stateCfg, profile)
 		s.SlowQuerySyncer = slowquerysync.NewSyncer(storeInstance, s.dbFactory, s.stateCfg, profile)
+		s.UnusedIndexSyncer = unusedindexsync.NewSyncer(storeInstance, s.dbFactory)
 		// TODO(p0ny): enable Feishu provider only when it is needed.
 		s.feishuProvider = feishu.NewProvider(profile.FeishuAPIURL)
 		s.ApplicationRunner = apprun.NewRunner(storeInstance, s.ActivityManager, s.feishuProvider, profile)
+		s.slackProvider = slack.NewProvider(slack.APIPath)
+		s.teamsProvider = teams.NewProvider()
 		s.BackupRunner = backuprun.NewRunner(storeInstance, s.dbFactory, s.s3Client, s.stateCfg, &profile)
 		s.RollbackRunner = rollbackrun.NewRunner(storeInstance, s.dbFactory, s.stateCfg)
 		s.ApprovalRunner = approval.NewRunner(storeInstance, s.dbFactory, s.stateCfg, s.ActivityManager, s.licenseService)
+		s.ApprovalChainRunner = approval.NewChainRunner(storeInstance, s.ActivityManager, s.feishuProvider)
+		s.PartitionRunner = partitionrun.NewRunner(storeInstance, s.dbFactory, s.createIssue)
+		s.ScheduledQueryRunner = scheduledquery.NewRunner(storeInstance, s.dbFactory, s.checkSQLEditorQuery)
+		s.QueryHistoryCleaner = queryhistory.NewCleaner(storeInstance)
+		s.WebhookEventCleaner = webhookevent.NewCleaner(storeInstance)
+		s.DatabaseGrantExpirer = databasegrant.NewExpirer(storeInstance, s.ActivityManager)
+		s.DatabaseCloner = databaseclone.NewCloner(storeInstance, s.dbFactory, s.SchemaSyncer, s.maskingSecret)
+		s.AuditSinkStreamer = auditsink.NewStreamer(storeInstance)
+		s.Alerter = alerting.NewAlerter(storeInstance)
 
 		s.MailSender = mail.NewSender(s.store, s.stateCfg)
 
@@ -449,9 +553,19 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		s.TaskCheckScheduler.Register(api.TaskCheckDatabaseStatementTypeReport, statementTypeReportExecutor)
 		statementAffectedRowsExecutor := taskcheck.NewStatementAffectedRowsReportExecutor(storeInstance, s.dbFactory)
 		s.TaskCheckScheduler.Register(api.TaskCheckDatabaseStatementAffectedRowsReport, statementAffectedRowsExecutor)
+		statementShadowDryRunExecutor := taskcheck.NewStatementShadowDryRunExecutor(storeInstance, s.dbFactory)
+		s.TaskCheckScheduler.Register(api.TaskCheckDatabaseStatementShadowDryRun, statementShadowDryRunExecutor)
+		statementDependencyExecutor := taskcheck.NewStatementDependencyExecutor(storeInstance)
+		s.TaskCheckScheduler.Register(api.TaskCheckDatabaseStatementDependency, statementDependencyExecutor)
 
 		// Anomaly scanner
-		s.AnomalyScanner = anomaly.NewScanner(storeInstance, s.dbFactory, s.licenseService)
+		s.AnomalyScanner = anomaly.NewScanner(storeInstance, s.dbFactory, s.ActivityManager, s.licenseService, s.secret)
+
+		// Cloud instance discoverer
+		s.CloudDiscoverer = clouddiscovery.NewDiscoverer(storeInstance)
+
+		// PII scanner
+		s.PIIScanner = piiscan.NewScanner(storeInstance, s.dbFactory)
 
 		// Metric reporter
 		s.initMetricReporter()
@@ -469,7 +583,7 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 			if s.profile.Mode == common.ReleaseModeProd && !s.profile.Debug {
 				return true
 			}
-			return !common.HasPrefixes(c.Path(), internalAPIPrefix, openAPIPrefix, webhookAPIPrefix)
+			return !common.HasPrefixes(c.Path(), internalAPIPrefix, openAPIPrefix, webhookAPIPrefix, scimAPIPrefix)
 		},
 		Format: `{"time":"${time_rfc3339}",` +
 			`"method":"${method}","uri":"${uri}",` +
@@ -481,6 +595,17 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 
 	webhookGroup := e.Group(webhookAPIPrefix)
 	s.registerWebhookRoutes(webhookGroup)
+	if common.FeatureFlag(common.FeatureFlagApprovalChain) {
+		s.registerApprovalChainWebhookRoutes(webhookGroup)
+	}
+	s.registerSlackRoutes(webhookGroup)
+	s.registerTeamsRoutes(webhookGroup)
+
+	if common.FeatureFlag(common.FeatureFlagSCIM) {
+		// The SCIM endpoint authenticates the identity providers by the SCIM token.
+		scimGroup := e.Group(scimAPIPrefix)
+		s.registerSCIMRoutes(scimGroup)
+	}
 
 	apiGroup := e.Group(internalAPIPrefix)
 	// API JWT authentication middleware.
@@ -514,6 +639,7 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	s.registerIssueRoutes(apiGroup)
 	s.registerIssueSubscriberRoutes(apiGroup)
 	s.registerTaskRoutes(apiGroup)
+	s.registerTaskLogStreamRoutes(apiGroup)
 	s.registerStageRoutes(apiGroup)
 	s.registerActivityRoutes(apiGroup)
 	s.registerInboxRoutes(apiGroup)
@@ -523,7 +649,50 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	s.registerPlanRoutes(apiGroup)
 	s.registerSheetRoutes(apiGroup)
 	s.registerSheetOrganizerRoutes(apiGroup)
+	if common.FeatureFlag(common.FeatureFlagNotebook) {
+		s.registerNotebookRoutes(apiGroup)
+	}
 	s.registerAnomalyRoutes(apiGroup)
+	s.registerCloudDiscoveryRoutes(apiGroup)
+	if common.FeatureFlag(common.FeatureFlagScheduledQuery) {
+		s.registerScheduledQueryRoutes(apiGroup)
+	}
+	if common.FeatureFlag(common.FeatureFlagSnippet) {
+		s.registerSnippetRoutes(apiGroup)
+	}
+	if common.FeatureFlag(common.FeatureFlagAdminSession) {
+		s.registerAdminSessionRoutes(apiGroup)
+	}
+	if common.FeatureFlag(common.FeatureFlagPIIScan) {
+		s.registerPIIRoutes(apiGroup)
+	}
+	s.registerMaskingBundleRoutes(apiGroup)
+	s.registerRoleRoutes(apiGroup)
+	if common.FeatureFlag(common.FeatureFlagRoleMember) {
+		s.registerRoleMemberRoutes(apiGroup)
+	}
+	if common.FeatureFlag(common.FeatureFlagAutoApproval) {
+		s.registerAutoApprovalRoutes(apiGroup)
+	}
+	if common.FeatureFlag(common.FeatureFlagDatabaseGrant) {
+		s.registerDatabaseGrantRoutes(apiGroup)
+	}
+	if common.FeatureFlag(common.FeatureFlagExportRequest) {
+		s.registerExportRequestRoutes(apiGroup)
+	}
+	if common.FeatureFlag(common.FeatureFlagDatabaseClone) {
+		s.registerDatabaseCloneRoutes(apiGroup)
+	}
+	s.registerAccessReportRoutes(apiGroup)
+	if common.FeatureFlag(common.FeatureFlagVCSRelease) {
+		s.registerReleaseRoutes(apiGroup)
+	}
+	if common.FeatureFlag(common.FeatureFlagWebhookEvent) {
+		s.registerWebhookEventRoutes(apiGroup)
+	}
+	if common.FeatureFlag(common.FeatureFlagSCIM) {
+		s.registerUserGroupRoutes(apiGroup)
+	}
 
 	// Register healthz endpoint.
 	e.GET("/healthz", func(c echo.Context) error {
@@ -534,9 +703,9 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	authProvider := auth.New(s.store, s.secret, s.licenseService, profile.Mode)
 	aclProvider := v1.NewACLInterceptor(s.store, s.secret, s.licenseService, profile.Mode)
 	s.grpcServer = grpc.NewServer(
-		grpc.ChainUnaryInterceptor(authProvider.AuthenticationInterceptor, aclProvider.ACLInterceptor),
+		grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor, authProvider.AuthenticationInterceptor, aclProvider.ACLInterceptor),
 	)
-	v1pb.RegisterAuthServiceServer(s.grpcServer, v1.NewAuthService(s.store, s.secret, s.licenseService, s.MetricReporter, &profile,
+	v1pb.RegisterAuthServiceServer(s.grpcServer, v1.NewAuthService(s.store, s.secret, s.licenseService, s.MetricReporter, s.SSOGroupSyncer, &profile,
 		func(ctx context.Context, user *store.UserMessage, firstEndUser bool) error {
 			if s.profile.TestOnlySkipOnboardingData {
 				return nil
@@ -573,7 +742,8 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	v1pb.RegisterSQLServiceServer(s.grpcServer, v1.NewSQLService())
 	v1pb.RegisterExternalVersionControlServiceServer(s.grpcServer, v1.NewExternalVersionControlService(s.store))
 	v1pb.RegisterRiskServiceServer(s.grpcServer, v1.NewRiskService(s.store, s.licenseService))
-	v1pb.RegisterReviewServiceServer(s.grpcServer, v1.NewReviewService(s.store, s.ActivityManager, s.stateCfg))
+	s.reviewService = v1.NewReviewService(s.store, s.ActivityManager, s.stateCfg)
+	v1pb.RegisterReviewServiceServer(s.grpcServer, s.reviewService)
 	v1pb.RegisterRoleServiceServer(s.grpcServer, v1.NewRoleService(s.store, s.licenseService))
 	reflection.Register(s.grpcServer)
 
@@ -583,7 +753,7 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
-	mux := runtime.NewServeMux(runtime.WithForwardResponseOption(auth.GatewayResponseModifier))
+	mux := runtime.NewServeMux(runtime.WithForwardResponseOption(auth.GatewayResponseModifier), runtime.WithIncomingHeaderMatcher(tracing.GatewayHeaderMatcher))
 	if err := v1pb.RegisterAuthServiceHandler(ctx, mux, grpcConn); err != nil {
 		return nil, err
 	}
@@ -667,6 +837,7 @@ func (s *Server) registerOpenAPIRoutes(e *echo.Echo, ce *casbin.Enforcer, prof c
 		return openAPIMetricMiddleware(s, next)
 	}
 	e.POST("/v1/sql/advise", s.sqlCheckController)
+	e.POST("/v1/sql/advise/batch", s.sqlBatchCheckController)
 	e.POST("/v1/sql/schema/diff", schemaDiff)
 	e.PATCH("/v1/instances/:instanceName/databases/:database", s.updateInstanceDatabase, jwtMiddlewareFunc, aclMiddlewareFunc, metricMiddlewareFunc)
 	e.POST("/v1/issues", s.createIssueByOpenAPI, jwtMiddlewareFunc, aclMiddlewareFunc, metricMiddlewareFunc)
@@ -692,6 +863,8 @@ type workspaceConfig struct {
 	secret string
 	// workspaceID used to initial the identify for a new workspace.
 	workspaceID string
+	// maskingSecret derives the keys of the deterministic masking algorithms.
+	maskingSecret string
 }
 
 func (s *Server) getInitSetting(ctx context.Context, datastore *store.Store) (*workspaceConfig, error) {
@@ -724,6 +897,21 @@ func (s *Server) getInitSetting(ctx context.Context, datastore *store.Store) (*w
 	}
 	conf.secret = authSetting.Value
 
+	// initial masking secret, the masked values can't be joined with the ones masked before if it changes.
+	value, err = common.RandomString(secretLength)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate random masking secret")
+	}
+	maskingSetting, _, err := datastore.CreateSettingIfNotExistV2(ctx, &store.SettingMessage{
+		Name:        api.SettingMaskingSecret,
+		Value:       value,
+		Description: "Random string used to derive the keys of the masking algorithms.",
+	}, api.SystemBotID)
+	if err != nil {
+		return nil, err
+	}
+	conf.maskingSecret = maskingSetting.Value
+
 	// initial workspace
 	workspaceSetting, _, err := datastore.CreateSettingIfNotExistV2(ctx, &store.SettingMessage{
 		Name:        api.SettingWorkspaceID,
@@ -847,6 +1035,10 @@ func (s *Server) Run(ctx context.Context, port int) error {
 		go s.SchemaSyncer.Run(ctx, &s.runnerWG)
 		s.runnerWG.Add(1)
 		go s.SlowQuerySyncer.Run(ctx, &s.runnerWG)
+		if common.FeatureFlag(common.FeatureFlagUnusedIndex) {
+			s.runnerWG.Add(1)
+			go s.UnusedIndexSyncer.Run(ctx, &s.runnerWG)
+		}
 		s.runnerWG.Add(1)
 		go s.MailSender.Run(ctx, &s.runnerWG)
 		s.runnerWG.Add(1)
@@ -859,10 +1051,65 @@ func (s *Server) Run(ctx context.Context, port int) error {
 		go s.RollbackRunner.Run(ctx, &s.runnerWG)
 		s.runnerWG.Add(1)
 		go s.ApprovalRunner.Run(ctx, &s.runnerWG)
+		if common.FeatureFlag(common.FeatureFlagApprovalChain) {
+			s.runnerWG.Add(1)
+			go s.ApprovalChainRunner.Run(ctx, &s.runnerWG)
+		}
+		s.runnerWG.Add(1)
+		go s.PartitionRunner.Run(ctx, &s.runnerWG)
+		if common.FeatureFlag(common.FeatureFlagScheduledQuery) {
+			s.runnerWG.Add(1)
+			go s.ScheduledQueryRunner.Run(ctx, &s.runnerWG)
+		}
+		if common.FeatureFlag(common.FeatureFlagQueryHistory) {
+			s.runnerWG.Add(1)
+			go s.QueryHistoryCleaner.Run(ctx, &s.runnerWG)
+		}
+		if common.FeatureFlag(common.FeatureFlagWebhookEvent) {
+			s.runnerWG.Add(1)
+			go s.WebhookEventCleaner.Run(ctx, &s.runnerWG)
+		}
+		if common.FeatureFlag(common.FeatureFlagDatabaseGrant) {
+			s.runnerWG.Add(1)
+			go s.DatabaseGrantExpirer.Run(ctx, &s.runnerWG)
+		}
+		if common.FeatureFlag(common.FeatureFlagDatabaseClone) {
+			s.runnerWG.Add(1)
+			go s.DatabaseCloner.Run(ctx, &s.runnerWG)
+		}
+		if common.FeatureFlag(common.FeatureFlagAuditSink) {
+			s.runnerWG.Add(1)
+			go s.AuditSinkStreamer.Run(ctx, &s.runnerWG)
+		}
+		if common.FeatureFlag(common.FeatureFlagAlerting) {
+			s.runnerWG.Add(1)
+			go s.Alerter.Run(ctx, &s.runnerWG)
+		}
+		s.runnerWG.Add(1)
+		go s.externalSecretManager.Run(ctx, &s.runnerWG)
+		if common.FeatureFlag(common.FeatureFlagCloudDiscovery) {
+			s.runnerWG.Add(1)
+			go s.CloudDiscoverer.Run(ctx, &s.runnerWG)
+		}
+		if common.FeatureFlag(common.FeatureFlagPIIScan) {
+			s.runnerWG.Add(1)
+			go s.PIIScanner.Run(ctx, &s.runnerWG)
+		}
+		if common.FeatureFlag(common.FeatureFlagSSOGroupSync) {
+			s.runnerWG.Add(1)
+			go s.SSOGroupSyncer.Run(ctx, &s.runnerWG)
+		}
 
 		s.runnerWG.Add(1)
 		go s.MetricReporter.Run(ctx, &s.runnerWG)
 	}
+	// The connections are pooled for the SQL editor in the readonly mode as well.
+	s.runnerWG.Add(1)
+	go s.dbFactory.Run(ctx, &s.runnerWG)
+	s.runnerWG.Add(1)
+	go s.queryCursorManager.Run(ctx, &s.runnerWG)
+	s.runnerWG.Add(1)
+	go s.runningQueryManager.Run(ctx, &s.runnerWG)
 
 	listen, err := net.Listen("tcp", fmt.Sprintf(":%d", port+1))
 	if err != nil {
@@ -923,6 +1170,11 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	if s.metaDB != nil {
 		s.metaDB.Close()
 	}
+
+	// Flush the ended spans.
+	if s.tracerProvider != nil {
+		s.tracerProvider.Shutdown(ctx)
+	}
 	log.Info("Bytebase stopped properly")
 
 	return nil
@@ -1238,7 +1490,7 @@ func (s *Server) backfillInstanceChangeHistory(ctx context.Context) {
 				for {
 					var history []*db.MigrationHistory
 					if instance.Engine == db.MySQL {
-						myDriver := driver.(*mysql.Driver)
+						myDriver := db.UnwrapDriver(driver).(*mysql.Driver)
 						history, err = myDriver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{
 							InstanceID: &instance.UID,
 							Limit:      &limit,
@@ -1248,7 +1500,7 @@ func (s *Server) backfillInstanceChangeHistory(ctx context.Context) {
 							return err
 						}
 					} else if instance.Engine == db.Postgres {
-						pgDriver := driver.(*pg.Driver)
+						pgDriver := db.UnwrapDriver(driver).(*pg.Driver)
 						history, err = pgDriver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{
 							InstanceID: &instance.UID,
 							Limit:      &limit,
@@ -1258,7 +1510,7 @@ func (s *Server) backfillInstanceChangeHistory(ctx context.Context) {
 							return err
 						}
 					} else if instance.Engine == db.ClickHouse {
-						cDriver := driver.(*clickhouse.Driver)
+						cDriver := db.UnwrapDriver(driver).(*clickhouse.Driver)
 						history, err = cDriver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{
 							InstanceID: &instance.UID,
 							Limit:      &limit,
@@ -26,6 +26,13 @@ var whitelistSettings = []api.SettingName{
 	api.SettingPluginOpenAIKey,
 	api.SettingPluginOpenAIEndpoint,
 	api.SettingWorkspaceMailDelivery,
+	api.SettingWorkspaceCloudDiscovery,
+	api.SettingWorkspaceQueryHistoryRetention,
+	api.SettingWorkspaceApprovalChain,
+	api.SettingWorkspaceAuditSink,
+	api.SettingWorkspaceAlerting,
+	api.SettingWorkspaceSCIM,
+	api.SettingWorkspaceSSOGroupMapping,
 }
 
 func (s *Server) registerSettingRoutes(g *echo.Group) {
@@ -53,6 +60,30 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 				}
 				setting.Value = string(bytes)
 			}
+			if setting.Name == api.SettingWorkspaceAuditSink {
+				// We don't want to return the sink tokens to the client.
+				value, err := api.RedactAuditSinkTokens(setting.Value)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to redact audit sink setting value").SetInternal(err)
+				}
+				setting.Value = value
+			}
+			if setting.Name == api.SettingWorkspaceAlerting {
+				// We don't want to return the receiver keys to the client.
+				value, err := api.RedactAlertReceiverKeys(setting.Value)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to redact alerting setting value").SetInternal(err)
+				}
+				setting.Value = value
+			}
+			if setting.Name == api.SettingWorkspaceSCIM {
+				// We don't want to return the SCIM token to the client.
+				value, err := api.RedactSCIMToken(setting.Value)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to redact SCIM setting value").SetInternal(err)
+				}
+				setting.Value = value
+			}
 			for _, whitelist := range whitelistSettings {
 				if setting.Name == whitelist {
 					filteredList = append(filteredList, setting)
@@ -106,6 +137,105 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 			settingPatch.Value = string(bytes)
 		}
 
+		if settingPatch.Name == api.SettingWorkspaceCloudDiscovery {
+			if _, err := api.ValidateAndGetCloudDiscoverySetting(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid cloud discovery setting: %v", err))
+			}
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceQueryHistoryRetention {
+			if _, err := api.ValidateAndGetQueryHistoryRetentionSetting(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid query history retention setting: %v", err))
+			}
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceApprovalChain {
+			if _, err := api.ValidateAndGetApprovalChainSetting(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid approval chain setting: %v", err))
+			}
+		}
+
+		if settingPatch.Name == api.SettingAppSlack {
+			if !s.licenseService.IsFeatureEnabled(api.FeatureIMApproval) {
+				return echo.NewHTTPError(http.StatusBadRequest, api.FeatureIMApproval.AccessErrorMessage())
+			}
+			if _, err := api.ValidateAndGetSlackSetting(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid Slack setting: %v", err))
+			}
+		}
+
+		if settingPatch.Name == api.SettingAppTeams {
+			if !s.licenseService.IsFeatureEnabled(api.FeatureIMApproval) {
+				return echo.NewHTTPError(http.StatusBadRequest, api.FeatureIMApproval.AccessErrorMessage())
+			}
+			if _, err := api.ValidateAndGetTeamsSetting(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid Teams setting: %v", err))
+			}
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceAuditSink {
+			settingName := api.SettingWorkspaceAuditSink
+			oldSetting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &settingName})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get audit sink setting").SetInternal(err)
+			}
+			oldValue := ""
+			if oldSetting != nil {
+				oldValue = oldSetting.Value
+			}
+			value, err := api.InheritAuditSinkTokens(oldValue, settingPatch.Value)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid audit sink setting: %v", err))
+			}
+			settingPatch.Value = value
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceAlerting {
+			settingName := api.SettingWorkspaceAlerting
+			oldSetting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &settingName})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get alerting setting").SetInternal(err)
+			}
+			oldValue := ""
+			if oldSetting != nil {
+				oldValue = oldSetting.Value
+			}
+			value, err := api.InheritAlertReceiverKeys(oldValue, settingPatch.Value)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid alerting setting: %v", err))
+			}
+			settingPatch.Value = value
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceSCIM {
+			if !s.licenseService.IsFeatureEnabled(api.FeatureSSO) {
+				return echo.NewHTTPError(http.StatusForbidden, api.FeatureSSO.AccessErrorMessage())
+			}
+			settingName := api.SettingWorkspaceSCIM
+			oldSetting, err := s.store.GetSettingV2(ctx, &store.FindSettingMessage{Name: &settingName})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get SCIM setting").SetInternal(err)
+			}
+			oldValue := ""
+			if oldSetting != nil {
+				oldValue = oldSetting.Value
+			}
+			value, err := api.InheritSCIMToken(oldValue, settingPatch.Value)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid SCIM setting: %v", err))
+			}
+			settingPatch.Value = value
+		}
+
+		if settingPatch.Name == api.SettingWorkspaceSSOGroupMapping {
+			if !s.licenseService.IsFeatureEnabled(api.FeatureSSO) {
+				return echo.NewHTTPError(http.StatusForbidden, api.FeatureSSO.AccessErrorMessage())
+			}
+			if _, err := api.ValidateAndGetSSOGroupMappingSetting(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid SSO group mapping setting: %v", err))
+			}
+		}
+
 		if settingPatch.Name == api.SettingAppIM {
 			var value api.SettingAppIMValue
 			if err := json.Unmarshal([]byte(settingPatch.Value), &value); err != nil {
@@ -238,6 +368,30 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 			}
 			setting.Value = string(bytes)
 		}
+		if setting.Name == api.SettingWorkspaceAuditSink {
+			// We don't want to return the sink tokens to the client.
+			value, err := api.RedactAuditSinkTokens(setting.Value)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to redact audit sink setting value").SetInternal(err)
+			}
+			setting.Value = value
+		}
+		if setting.Name == api.SettingWorkspaceAlerting {
+			// We don't want to return the receiver keys to the client.
+			value, err := api.RedactAlertReceiverKeys(setting.Value)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to redact alerting setting value").SetInternal(err)
+			}
+			setting.Value = value
+		}
+		if setting.Name == api.SettingWorkspaceSCIM {
+			// We don't want to return the SCIM token to the client.
+			value, err := api.RedactSCIMToken(setting.Value)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to redact SCIM setting value").SetInternal(err)
+			}
+			setting.Value = value
+		}
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		if err := jsonapi.MarshalPayload(c.Response().Writer, setting); err != nil {
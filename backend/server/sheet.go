@@ -14,6 +14,7 @@ import (
 	"github.com/bytebase/bytebase/backend/common"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
 )
 
 func (s *Server) registerSheetRoutes(g *echo.Group) {
@@ -26,7 +27,23 @@ func (s *Server) registerSheetRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, sheetCreate); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create sheet request").SetInternal(err)
 		}
-		sheetCreate.Type = api.SheetForSQL
+		switch sheetCreate.Type {
+		case "":
+			sheetCreate.Type = api.SheetForSQL
+		case api.SheetForSQL:
+		case api.SheetForNotebook:
+			if !common.FeatureFlag(common.FeatureFlagNotebook) {
+				return echo.NewHTTPError(http.StatusBadRequest, "Notebook sheets are not supported yet")
+			}
+			notebook, err := utils.ParseNotebookPayload(sheetCreate.Payload)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sheet request, %v", err))
+			}
+			// The statement of the notebook is the statements of the cells.
+			sheetCreate.Statement = utils.GetNotebookStatement(notebook)
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sheet request, invalid type %q", sheetCreate.Type))
+		}
 
 		if sheetCreate.Name == "" {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sheet request, missing name")
@@ -203,6 +220,23 @@ func (s *Server) registerSheetRoutes(g *echo.Group) {
 		if err := jsonapi.UnmarshalPayload(c.Request().Body, sheetPatch); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch sheet request").SetInternal(err)
 		}
+		if sheetPatch.Statement != nil || sheetPatch.Payload != nil {
+			sheet, err := s.store.GetSheetV2(ctx, &api.SheetFind{ID: &id}, currentPrincipalID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch sheet ID: %v", id)).SetInternal(err)
+			}
+			if sheet != nil && sheet.Type == api.SheetForNotebook {
+				if sheetPatch.Payload == nil {
+					return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch sheet request, the statement of the notebook is patched by its cells in the payload")
+				}
+				notebook, err := utils.ParseNotebookPayload(*sheetPatch.Payload)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed patch sheet request, %v", err))
+				}
+				statement := utils.GetNotebookStatement(notebook)
+				sheetPatch.Statement = &statement
+			}
+		}
 
 		sheet, err := s.store.PatchSheet(ctx, sheetPatch)
 		if err != nil {
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/app/slack"
+	"github.com/bytebase/bytebase/backend/plugin/webhook"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+// slackCommandUsage is the usage of the /bytebase slash command.
+const slackCommandUsage = "Usage: `/bytebase status <issue ID>` to look up the status of the issue."
+
+// registerSlackRoutes registers the request URLs of the Slack app, i.e. the interactivity request URL for the approve
+// button in the Slack webhook messages, and the request URL of the /bytebase slash command. The Slack
+// users are mapped to the Bytebase users by email.
+func (s *Server) registerSlackRoutes(g *echo.Group) {
+	g.POST("/slack/interaction", func(c echo.Context) error {
+		setting, body, err := s.verifySlackRequest(c)
+		if err != nil {
+			return err
+		}
+		payload, err := slack.ParseInteractionPayload(body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed Slack interaction").SetInternal(err)
+		}
+		// The "View in Bytebase" link button is also posted here.
+		if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+			return c.NoContent(http.StatusOK)
+		}
+		action := payload.Actions[0]
+		if action.ActionID != webhook.SlackActionApprove {
+			return c.NoContent(http.StatusOK)
+		}
+		// Slack expects the response in 3 seconds, so the result is posted to the response URL instead.
+		go func() {
+			ctx := context.Background()
+			text, err := s.handleSlackApprovalAction(ctx, setting, payload.User.ID, action)
+			if err != nil {
+				log.Error("Failed to handle Slack approval action", zap.String("action", action.ActionID), zap.String("issue", action.Value), zap.Error(err))
+				text = "Failed to handle the action, please try again in Bytebase."
+			}
+			if err := s.slackProvider.PostResponse(ctx, payload.ResponseURL, &slack.Message{
+				ResponseType: slack.ResponseTypeEphemeral,
+				Text:         text,
+			}); err != nil {
+				log.Error("Failed to post Slack response", zap.Error(err))
+			}
+		}()
+		return c.NoContent(http.StatusOK)
+	})
+
+	g.POST("/slack/command", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		setting, body, err := s.verifySlackRequest(c)
+		if err != nil {
+			return err
+		}
+		command, err := slack.ParseSlashCommand(body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed Slack slash command").SetInternal(err)
+		}
+		text, err := s.handleSlackCommand(ctx, setting, command)
+		if err != nil {
+			log.Error("Failed to handle Slack slash command", zap.String("text", command.Text), zap.Error(err))
+			text = "Failed to handle the command, please try again later."
+		}
+		return c.JSON(http.StatusOK, &slack.Message{
+			ResponseType: slack.ResponseTypeEphemeral,
+			Text:         text,
+		})
+	})
+}
+
+// verifySlackRequest returns the Slack app setting and the request body if the request is signed by the Slack app.
+func (s *Server) verifySlackRequest(c echo.Context) (*api.SettingAppSlackValue, []byte, error) {
+	ctx := c.Request().Context()
+	if s.slackProvider == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Slack app is not available in readonly mode")
+	}
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to read Slack request").SetInternal(err)
+	}
+	setting, err := utils.GetSlackSetting(ctx, s.store)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get Slack setting").SetInternal(err)
+	}
+	if setting == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, "Slack app is not configured")
+	}
+	if err := slack.VerifyRequest(setting.SigningSecret, c.Request().Header, body, time.Now()); err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusUnauthorized, "Invalid Slack request signature").SetInternal(err)
+	}
+	return setting, body, nil
+}
+
+// getSlackUser returns the Bytebase user with the email of the Slack user, it's nil if not found.
+func (s *Server) getSlackUser(ctx context.Context, setting *api.SettingAppSlackValue, slackUserID string) (*store.UserMessage, error) {
+	email, err := s.slackProvider.GetUserEmail(ctx, setting.BotToken, slackUserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.GetUser(ctx, &store.FindUserMessage{Email: &email})
+}
+
+// handleSlackApprovalAction approves the pending approval step of the issue as the Bytebase user of the
+// Slack user. It returns the message to the Slack user.
+func (s *Server) handleSlackApprovalAction(ctx context.Context, setting *api.SettingAppSlackValue, slackUserID string, action *slack.Action) (string, error) {
+	user, err := s.getSlackUser(ctx, setting, slackUserID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the user of the Slack user")
+	}
+	if user == nil {
+		return "Your Slack email doesn't match any Bytebase user.", nil
+	}
+	issueID, err := strconv.Atoi(action.Value)
+	if err != nil {
+		return "", errors.Errorf("invalid issue ID %q", action.Value)
+	}
+	return s.handleIMApprovalAction(ctx, user, issueID)
+}
+
+// handleSlackCommand handles the /bytebase slash command of the Slack user. It returns the message to the Slack user.
+func (s *Server) handleSlackCommand(ctx context.Context, setting *api.SettingAppSlackValue, command *slack.SlashCommand) (string, error) {
+	issueID, ok := parseIMIssueID(command.Text, "status", "status")
+	if !ok {
+		return slackCommandUsage, nil
+	}
+	user, err := s.getSlackUser(ctx, setting, command.UserID)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the user of the Slack user")
+	}
+	if user == nil {
+		return "Your Slack email doesn't match any Bytebase user.", nil
+	}
+	issueStatus, err := s.getIMIssueStatus(ctx, issueID)
+	if err != nil {
+		return "", err
+	}
+	if issueStatus == nil {
+		return fmt.Sprintf("Issue %d not found.", issueID), nil
+	}
+	lines := []string{
+		fmt.Sprintf("*<%s|%s>*", issueStatus.link, issueStatus.issue.Title),
+		fmt.Sprintf("*Project:* %s", issueStatus.issue.Project.Title),
+		fmt.Sprintf("*Status:* %s", issueStatus.issue.Status),
+		fmt.Sprintf("*Approval:* %s", issueStatus.approval),
+	}
+	if issueStatus.tasks != "" {
+		lines = append(lines, fmt.Sprintf("*Tasks:* %s", issueStatus.tasks))
+	}
+	return strings.Join(lines, "\n"), nil
+}
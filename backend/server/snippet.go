@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+func (s *Server) registerSnippetRoutes(g *echo.Group) {
+	g.GET("/snippet", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+
+		find := &store.FindSnippetMessage{
+			Query: c.QueryParam("query"),
+		}
+		// Workspace Owners and DBAs can see all snippets.
+		if role != api.Owner && role != api.DBA {
+			find.VisibleToPrincipalID = &principalID
+		}
+		if projectID := c.QueryParam("project"); projectID != "" {
+			id, err := strconv.Atoi(projectID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project ID is not a number: %s", projectID)).SetInternal(err)
+			}
+			find.ProjectUID = &id
+		}
+		snippets, err := s.store.ListSnippets(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list snippets").SetInternal(err)
+		}
+		snippetList := []*api.Snippet{}
+		for _, snippet := range snippets {
+			snippetList = append(snippetList, toAPISnippet(snippet))
+		}
+		return c.JSON(http.StatusOK, snippetList)
+	})
+
+	g.POST("/snippet", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+
+		create := &api.SnippetCreate{}
+		if err := json.NewDecoder(c.Request().Body).Decode(create); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed create snippet request").SetInternal(err)
+		}
+		if create.Name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Snippet name is required")
+		}
+		if create.VariableList == nil {
+			create.VariableList = []*api.SnippetVariable{}
+		}
+		if err := utils.ValidateSnippet(create.Content, create.VariableList); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		projectUID, err := s.checkSnippetSharing(ctx, principalID, role, create.Visibility, create.ProjectID)
+		if err != nil {
+			return err
+		}
+
+		snippet, err := s.store.CreateSnippet(ctx, &store.SnippetMessage{
+			CreatorID:    principalID,
+			ProjectUID:   projectUID,
+			Visibility:   create.Visibility,
+			Name:         create.Name,
+			Description:  create.Description,
+			Content:      create.Content,
+			VariableList: create.VariableList,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create snippet").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPISnippet(snippet))
+	})
+
+	g.PATCH("/snippet/:snippetID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+		snippet, err := s.getSnippet(c, true /* manage */)
+		if err != nil {
+			return err
+		}
+
+		patch := &api.SnippetPatch{}
+		if err := json.NewDecoder(c.Request().Body).Decode(patch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch snippet request").SetInternal(err)
+		}
+		if v := patch.Name; v != nil && *v == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Snippet name is required")
+		}
+		update := &store.UpdateSnippetMessage{
+			ID:           snippet.ID,
+			UpdaterID:    principalID,
+			Name:         patch.Name,
+			Description:  patch.Description,
+			Content:      patch.Content,
+			VariableList: patch.VariableList,
+		}
+		if patch.Content != nil || patch.VariableList != nil {
+			content, variableList := snippet.Content, snippet.VariableList
+			if v := patch.Content; v != nil {
+				content = *v
+			}
+			if v := patch.VariableList; v != nil {
+				if *v == nil {
+					*v = []*api.SnippetVariable{}
+				}
+				variableList = *v
+			}
+			if err := utils.ValidateSnippet(content, variableList); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+		}
+		// The visibility and the project are checked and updated together.
+		if patch.Visibility != nil || patch.ProjectID != nil {
+			visibility, projectUID := snippet.Visibility, snippet.ProjectUID
+			if v := patch.Visibility; v != nil {
+				visibility = *v
+			}
+			if v := patch.ProjectID; v != nil {
+				projectUID = v
+			}
+			projectUID, err = s.checkSnippetSharing(ctx, principalID, role, visibility, projectUID)
+			if err != nil {
+				return err
+			}
+			update.Visibility = &visibility
+			update.ProjectUID = projectUID
+		}
+
+		snippet, err = s.store.UpdateSnippet(ctx, update)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch snippet ID: %d", update.ID)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPISnippet(snippet))
+	})
+
+	g.DELETE("/snippet/:snippetID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		snippet, err := s.getSnippet(c, true /* manage */)
+		if err != nil {
+			return err
+		}
+		if err := s.store.DeleteSnippet(ctx, snippet.ID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete snippet ID: %d", snippet.ID)).SetInternal(err)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	g.POST("/snippet/:snippetID/insert", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		snippet, err := s.getSnippet(c, false /* manage */)
+		if err != nil {
+			return err
+		}
+		insert := &api.SnippetInsert{}
+		if err := json.NewDecoder(c.Request().Body).Decode(insert); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed insert snippet request").SetInternal(err)
+		}
+		if err := s.store.RecordSnippetUsage(ctx, snippet.ID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to record the usage of snippet ID: %d", snippet.ID)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, &api.SnippetInsertResult{
+			Content: utils.RenderSnippet(snippet.Content, snippet.VariableList, insert.VariableValues),
+		})
+	})
+}
+
+// getSnippet gets the snippet in the path visible to the principal. The snippet can be managed by its creator, the
+// workspace Owners and DBAs, and the Owners of its project.
+func (s *Server) getSnippet(c echo.Context, manage bool) (*store.SnippetMessage, error) {
+	ctx := c.Request().Context()
+	principalID := c.Get(getPrincipalIDContextKey()).(int)
+	role := c.Get(getRoleContextKey()).(api.Role)
+	id, err := strconv.Atoi(c.Param("snippetID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("snippetID"))).SetInternal(err)
+	}
+	find := &store.FindSnippetMessage{ID: &id}
+	if role != api.Owner && role != api.DBA {
+		find.VisibleToPrincipalID = &principalID
+	}
+	snippet, err := s.store.GetSnippet(ctx, find)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get snippet ID: %d", id)).SetInternal(err)
+	}
+	if snippet == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Snippet not found with ID %d", id))
+	}
+	if !manage || snippet.CreatorID == principalID || role == api.Owner || role == api.DBA {
+		return snippet, nil
+	}
+	if snippet.Visibility == api.SnippetVisibilityProject && snippet.ProjectUID != nil {
+		projectPolicy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{UID: snippet.ProjectUID})
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get the policy of project ID: %d", *snippet.ProjectUID)).SetInternal(err)
+		}
+		if isProjectOwner(principalID, projectPolicy) {
+			return snippet, nil
+		}
+	}
+	return nil, echo.NewHTTPError(http.StatusForbidden, "Only the creator, workspace Owners, DBAs and project Owners can manage the snippet")
+}
+
+// checkSnippetSharing checks the principal can share the snippet with the visibility, and returns the project of the
+// snippet. Only the members of the project can share the snippet in it.
+func (s *Server) checkSnippetSharing(ctx context.Context, principalID int, role api.Role, visibility api.SnippetVisibility, projectUID *int) (*int, error) {
+	switch visibility {
+	case api.SnippetVisibilityPrivate, api.SnippetVisibilityWorkspace:
+		return nil, nil
+	case api.SnippetVisibilityProject:
+	default:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid snippet visibility %q", visibility))
+	}
+	if projectUID == nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Project is required for the snippets shared in a project")
+	}
+	project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{UID: projectUID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get project ID: %d", *projectUID)).SetInternal(err)
+	}
+	if project == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Project not found with ID %d", *projectUID))
+	}
+	if role != api.Owner && role != api.DBA {
+		projectPolicy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{UID: &project.UID})
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get the policy of project ID: %d", project.UID)).SetInternal(err)
+		}
+		if !isProjectMember(principalID, projectPolicy) {
+			return nil, echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Only the members of project %q can share snippets in it", project.Title))
+		}
+	}
+	return &project.UID, nil
+}
+
+func toAPISnippet(snippet *store.SnippetMessage) *api.Snippet {
+	variableList := snippet.VariableList
+	if variableList == nil {
+		variableList = []*api.SnippetVariable{}
+	}
+	return &api.Snippet{
+		ID:           snippet.ID,
+		CreatorID:    snippet.CreatorID,
+		CreatedTs:    snippet.CreatedTs,
+		UpdaterID:    snippet.UpdaterID,
+		UpdatedTs:    snippet.UpdatedTs,
+		ProjectID:    snippet.ProjectUID,
+		Visibility:   snippet.Visibility,
+		Name:         snippet.Name,
+		Description:  snippet.Description,
+		Content:      snippet.Content,
+		VariableList: variableList,
+		UsageCount:   snippet.UsageCount,
+		LastUsedTs:   snippet.LastUsedTs,
+	}
+}
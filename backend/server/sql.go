@@ -6,10 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/jsonapi"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -17,6 +19,7 @@ import (
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
 	"github.com/bytebase/bytebase/backend/component/activity"
+	"github.com/bytebase/bytebase/backend/component/dbfactory"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	metricAPI "github.com/bytebase/bytebase/backend/metric"
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
@@ -25,10 +28,13 @@ import (
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/plugin/db/util"
 	"github.com/bytebase/bytebase/backend/plugin/metric"
+	"github.com/bytebase/bytebase/backend/plugin/parser/completion"
 	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
 
 	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
 	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 )
 
 func (s *Server) registerSQLRoutes(g *echo.Group) {
@@ -66,6 +72,32 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			}
 		}
 
+		username := connectionInfo.Username
+		if connectionInfo.ExternalSecretReference != "" {
+			var origin *store.DataSourceMessage
+			if connectionInfo.InstanceID != nil {
+				instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: connectionInfo.InstanceID})
+				if err != nil {
+					return err
+				}
+				if instance == nil {
+					return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("instance %d not found", *connectionInfo.InstanceID))
+				}
+				origin = utils.DataSourceFromInstanceWithType(instance, api.Admin)
+			}
+			if err := checkExternalSecretReference(c.Get(getRoleContextKey()).(api.Role), connectionInfo.ExternalSecretReference, origin, connectionInfo.Host, connectionInfo.Port); err != nil {
+				return err
+			}
+			credential, err := s.externalSecretManager.GetCredential(ctx, connectionInfo.ExternalSecretReference)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to get the credential from external secret %q: %v", connectionInfo.ExternalSecretReference, err)).SetInternal(err)
+			}
+			if credential.Username != "" {
+				username = credential.Username
+			}
+			password = credential.Password
+		}
+
 		var sshConfig db.SSHConfig
 		if connectionInfo.SSHHost != "" {
 			sshConfig.Host = connectionInfo.SSHHost
@@ -74,6 +106,61 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			sshConfig.Password = connectionInfo.SSHPassword
 			sshConfig.PrivateKey = connectionInfo.SSHPrivateKey
 		}
+		// Like the password, the secrets of the proxy chain and the AWS credential are not transferred back to client,
+		// so we fill in the existing secrets of the admin data source if the instanceID is passed.
+		var originProxy *store.DataSourceProxy
+		awsSecretAccessKey := connectionInfo.AWSSecretAccessKey
+		gcpCredentialsJSON := connectionInfo.GCPCredentialsJSON
+		azureClientSecret := connectionInfo.AzureClientSecret
+		kerberosKeytab := connectionInfo.KerberosKeytab
+		if connectionInfo.InstanceID != nil {
+			instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: connectionInfo.InstanceID})
+			if err != nil {
+				return err
+			}
+			if instance == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("instance %d not found", *connectionInfo.InstanceID))
+			}
+			for _, ds := range instance.DataSources {
+				if ds.Type == api.Admin {
+					originProxy = &ds.Proxy
+					if awsSecretAccessKey == "" && ds.AWSAccessKeyID == connectionInfo.AWSAccessKeyID {
+						awsSecretAccessKey, err = common.Unobfuscate(ds.AWSObfuscatedSecretAccessKey, s.secret)
+						if err != nil {
+							return err
+						}
+					}
+					if gcpCredentialsJSON == "" {
+						gcpCredentialsJSON, err = common.Unobfuscate(ds.GCPObfuscatedCredentialsJSON, s.secret)
+						if err != nil {
+							return err
+						}
+					}
+					if azureClientSecret == "" && ds.AzureClientID == connectionInfo.AzureClientID {
+						azureClientSecret, err = common.Unobfuscate(ds.AzureObfuscatedClientSecret, s.secret)
+						if err != nil {
+							return err
+						}
+					}
+					if kerberosKeytab == "" {
+						kerberosKeytab, err = common.Unobfuscate(ds.KerberosObfuscatedKeytab, s.secret)
+						if err != nil {
+							return err
+						}
+					}
+					break
+				}
+			}
+		}
+		kerberosConfig, err := dbfactory.GetKerberosConfig(connectionInfo.KerberosRealm, connectionInfo.KerberosKDC, connectionInfo.KerberosSPN, kerberosKeytab)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		jumpHostList, socks5Config, err := dbfactory.GetProxyConfig(s.convertToDataSourceProxy(connectionInfo.SSHJumpHostList, connectionInfo.SOCKS5Proxy, originProxy), s.secret)
+		if err != nil {
+			return err
+		}
+		sshConfig.JumpHostList = jumpHostList
 
 		var tlsConfig db.TLSConfig
 		supportTLS := connectionInfo.Engine == db.ClickHouse || connectionInfo.Engine == db.MySQL || connectionInfo.Engine == db.TiDB || connectionInfo.Engine == db.MariaDB || connectionInfo.Engine == db.OceanBase
@@ -127,7 +214,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			connectionInfo.Engine,
 			db.DriverConfig{},
 			db.ConnectionConfig{
-				Username:               connectionInfo.Username,
+				Username:               username,
 				Password:               password,
 				Host:                   connectionInfo.Host,
 				Port:                   connectionInfo.Port,
@@ -138,6 +225,24 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				SID:                    connectionInfo.SID,
 				ServiceName:            connectionInfo.ServiceName,
 				SSHConfig:              sshConfig,
+				SOCKS5Config:           socks5Config,
+				AuthenticationType:     connectionInfo.AuthenticationType,
+				AWSCredential: db.AWSCredential{
+					Region:          connectionInfo.AWSRegion,
+					AccessKeyID:     connectionInfo.AWSAccessKeyID,
+					SecretAccessKey: awsSecretAccessKey,
+				},
+				GCPCloudSQLConfig: db.GCPCloudSQLConfig{
+					ConnectionName:  connectionInfo.GCPCloudSQLConnectionName,
+					UsePrivateIP:    connectionInfo.GCPCloudSQLUsePrivateIP,
+					CredentialsJSON: gcpCredentialsJSON,
+				},
+				AzureCredential: db.AzureCredential{
+					TenantID:     connectionInfo.AzureTenantID,
+					ClientID:     connectionInfo.AzureClientID,
+					ClientSecret: azureClientSecret,
+				},
+				KerberosConfig: kerberosConfig,
 			},
 			db.ConnectionContext{},
 		)
@@ -148,7 +253,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			if connectionInfo.Port != "" {
 				hostPort += ":" + connectionInfo.Port
 			}
-			resultSet.Error = errors.Wrapf(err, "failed to connect %q for user %q", hostPort, connectionInfo.Username).Error()
+			resultSet.Error = errors.Wrapf(err, "failed to connect %q for user %q", hostPort, username).Error()
 		} else {
 			defer db.Close(ctx)
 			if err := db.Ping(ctx); err != nil {
@@ -250,70 +355,25 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		}
 		principalID := c.Get(getPrincipalIDContextKey()).(int)
 		role := c.Get(getRoleContextKey()).(api.Role)
-		var database *store.DatabaseMessage
-		if exec.DatabaseName != "" {
-			database, err = s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{EnvironmentID: &instance.EnvironmentID, InstanceID: &instance.ResourceID, DatabaseName: &exec.DatabaseName})
-			if err != nil {
-				return err
-			}
-			if database == nil {
-				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database %q not found", exec.DatabaseName))
-			}
-			// Database Access Control
-			hasAccessRights, err := s.hasDatabaseAccessRights(ctx, principalID, role, database)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check access control for database: %q", exec.DatabaseName)).SetInternal(err)
-			}
-			if !hasAccessRights {
-				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql execute request, no permission to access database %q", exec.DatabaseName))
-			}
+		database, err := s.checkSQLEditorDatabaseAccess(ctx, principalID, role, instance, exec.DatabaseName, exec.Statement)
+		if err != nil {
+			return err
 		}
-
-		// Database Access Control for MySQL dialect.
-		// MySQL dialect can query cross the database.
-		// We need special check.
-		if instance.Engine == db.MySQL || instance.Engine == db.TiDB || instance.Engine == db.MariaDB || instance.Engine == db.OceanBase {
-			databaseList, err := parser.ExtractDatabaseList(parser.MySQL, exec.Statement)
+		queryLimit, err := s.getQueryLimit(ctx, principalID, role, instance, database)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the query limit policy").SetInternal(err)
+		}
+		exec.Limit = queryLimit.ApplyRowLimit(exec.Limit)
+		if len(exec.SessionVariableList) > 0 {
+			canSet, err := s.canSetSessionVariables(ctx, principalID, role, database)
 			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to extract database list: %q", exec.Statement)).SetInternal(err)
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check the permission of setting session variables").SetInternal(err)
 			}
-
-			if exec.DatabaseName != "" {
-				// Disallow cross-database query if specify database.
-				for _, databaseName := range databaseList {
-					upperDatabaseName := strings.ToUpper(databaseName)
-					// We allow querying information schema.
-					if upperDatabaseName == "" || upperDatabaseName == "INFORMATION_SCHEMA" {
-						continue
-					}
-					if databaseName != exec.DatabaseName {
-						return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql execute request, specify database %q but access database %q", exec.DatabaseName, databaseName))
-					}
-				}
-			} else {
-				// Check database access rights.
-				for _, databaseName := range databaseList {
-					if databaseName == "" {
-						// We have already checked the current database access rights.
-						continue
-					}
-					accessDatabase, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{EnvironmentID: &instance.EnvironmentID, InstanceID: &instance.ResourceID, DatabaseName: &databaseName})
-					if err != nil {
-						if httpErr, ok := err.(*echo.HTTPError); ok && httpErr.Code == echo.ErrNotFound.Code {
-							// If database not found, skip.
-							continue
-						}
-						return err
-					}
-
-					hasAccessRights, err := s.hasDatabaseAccessRights(ctx, principalID, role, accessDatabase)
-					if err != nil {
-						return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check access control for database: %q", accessDatabase.DatabaseName)).SetInternal(err)
-					}
-					if !hasAccessRights {
-						return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql execute request, no permission to access database %q", accessDatabase.DatabaseName))
-					}
-				}
+			if !canSet {
+				return echo.NewHTTPError(http.StatusForbidden, "No permission to set session variables")
+			}
+			if exec.SessionVariableList, err = util.ValidateSessionVariables(instance.Engine, exec.SessionVariableList); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 			}
 		}
 
@@ -363,6 +423,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				exec.Statement,
 				catalog,
 				connection,
+				database,
 			)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to check SQL review policy").SetInternal(err)
@@ -378,6 +439,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 					DatabaseName:           exec.DatabaseName,
 					Error:                  "",
 					AdviceList:             adviceList,
+					SessionVariableList:    exec.SessionVariableList,
 				}); err != nil {
 					return err
 				}
@@ -402,12 +464,12 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get database list: %s", exec.Statement)).SetInternal(err)
 			}
 
-			sensitiveSchemaInfo, err = s.getSensitiveSchemaInfo(ctx, instance, databaseList, exec.DatabaseName)
+			sensitiveSchemaInfo, err = s.getSensitiveSchemaInfo(ctx, principalID, instance, databaseList, exec.DatabaseName)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get sensitive schema info: %s", exec.Statement)).SetInternal(err)
 			}
 		case db.Postgres:
-			sensitiveSchemaInfo, err = s.getSensitiveSchemaInfo(ctx, instance, []string{exec.DatabaseName}, exec.DatabaseName)
+			sensitiveSchemaInfo, err = s.getSensitiveSchemaInfo(ctx, principalID, instance, []string{exec.DatabaseName}, exec.DatabaseName)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get sensitive schema info: %s", exec.Statement)).SetInternal(err)
 			}
@@ -416,6 +478,8 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		start := time.Now().UnixNano()
 
 		singleSQLResults, queryErr := func() ([]api.SingleSQLResult, error) {
+			ctx, cancel := queryLimit.WithTimeout(ctx)
+			defer cancel()
 			driver, err := s.dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, exec.DatabaseName)
 			if err != nil {
 				return nil, err
@@ -423,7 +487,12 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			defer driver.Close(ctx)
 
 			// TODO(p0ny): refactor
-			if instance.Engine == db.MongoDB || instance.Engine == db.Spanner || instance.Engine == db.Redis {
+			if instance.Engine == db.MongoDB || instance.Engine == db.Spanner || instance.Engine == db.Redis || instance.Engine == db.Elasticsearch || instance.Engine == db.Cassandra || instance.Engine == db.DynamoDB || instance.Engine == db.Neo4j {
+				ctx, finish, err := s.runningQueryManager.Start(ctx, exec.QueryID, principalID, instance.UID, instance.Engine, nil, nil)
+				if err != nil {
+					return nil, err
+				}
+				defer finish()
 				data, err := driver.QueryConn(ctx, nil, exec.Statement, &db.QueryContext{
 					Limit:                 exec.Limit,
 					ReadOnly:              true,
@@ -455,6 +524,18 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				return nil, err
 			}
 			defer conn.Close()
+			ctx, finish, err := s.runningQueryManager.Start(ctx, exec.QueryID, principalID, instance.UID, instance.Engine, sqlDB, conn)
+			if err != nil {
+				return nil, err
+			}
+			defer finish()
+			if err := util.SetSessionVariables(ctx, instance.Engine, conn, exec.SessionVariableList); err != nil {
+				return nil, err
+			}
+
+			if exec.Cursor && api.IsSQLCursorSupported(instance.Engine) {
+				return s.queryWithCursor(ctx, conn, instance, exec, principalID, sensitiveSchemaInfo, queryLimit.MaxRowCount), nil
+			}
 
 			var singleSQLResults []api.SingleSQLResult
 
@@ -542,6 +623,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			DatabaseName:           exec.DatabaseName,
 			Error:                  errMessage,
 			AdviceList:             adviceList,
+			SessionVariableList:    exec.SessionVariableList,
 		}); err != nil {
 			return err
 		}
@@ -585,6 +667,482 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		return nil
 	})
 
+	g.POST("/sql/export", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		export := &api.SQLResultExport{}
+		decoder := json.NewDecoder(c.Request().Body)
+		// Keep the precision of the big integers.
+		decoder.UseNumber()
+		if err := decoder.Decode(export); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql export request").SetInternal(err)
+		}
+		format := utils.QueryResultExportFormat(export.Format)
+		if format != utils.QueryResultExportFormatParquet && format != utils.QueryResultExportFormatAvro {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported export format %q", export.Format))
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &export.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", export.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", export.InstanceID))
+		}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+		exportPolicy, err := s.store.GetExportPolicy(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get export policy").SetInternal(err)
+		}
+		dlpPolicy, err := s.store.GetDLPPolicy(ctx)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get DLP policy").SetInternal(err)
+		}
+		user, err := s.store.GetUserByID(ctx, principalID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch user ID: %d", principalID)).SetInternal(err)
+		}
+		if user == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("User ID not found: %d", principalID))
+		}
+		var database *store.DatabaseMessage
+		// The instance level results can only be exported by the workspace Owners and DBAs, as the permissions are
+		// granted in the projects of the databases.
+		if role != api.Owner && role != api.DBA {
+			if export.DatabaseID == 0 {
+				return echo.NewHTTPError(http.StatusForbidden, "Only the workspace Owners and DBAs can export the instance level results")
+			}
+			if database, err = s.getExportableDatabase(ctx, principalID, role, export.DatabaseID); err != nil {
+				return err
+			}
+		} else if export.DatabaseID != 0 {
+			if database, err = s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &export.DatabaseID}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", export.DatabaseID)).SetInternal(err)
+			}
+			if database == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", export.DatabaseID))
+			}
+		}
+		if database != nil && database.InstanceID != instance.ResourceID {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", export.DatabaseID))
+		}
+		// The DLP policy applies to everyone including the workspace Owners and DBAs.
+		if clearance := dlpPolicy.GetClearance(user.Email, role); clearance < dlpPolicy.GetMaxLevel() {
+			if database == nil {
+				return echo.NewHTTPError(http.StatusForbidden, "The instance level results can't be inspected by the DLP policy, export the results of a database instead")
+			}
+			if err := s.enforceDLP(ctx, principalID, role, instance, database, dlpPolicy, clearance, export); err != nil {
+				return err
+			}
+		}
+
+		var exportRequest *store.ExportRequestMessage
+		if common.FeatureFlag(common.FeatureFlagExportRequest) && role != api.Owner && role != api.DBA {
+			sensitive := false
+			for _, v := range export.SensitiveList {
+				sensitive = sensitive || v
+			}
+			// The workspace Owners and DBAs approve the export requests, so they don't need the approval themselves.
+			if exportPolicy.NeedApproval(len(export.Rows), sensitive) {
+				if exportRequest, err = s.getApprovedExportRequest(ctx, principalID, export); err != nil {
+					return err
+				}
+			}
+		}
+
+		result := &utils.QueryResultExport{
+			Engine:          instance.Engine,
+			ColumnNames:     export.ColumnNames,
+			ColumnTypeNames: export.ColumnTypeNames,
+			SensitiveList:   export.SensitiveList,
+			Rows:            export.Rows,
+		}
+		if err := utils.CheckQueryResultExport(result); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid query result: %v", err)).SetInternal(err)
+		}
+		exportID := uuid.NewString()
+		if exportPolicy.Watermark == api.ExportWatermarkMetadata || exportPolicy.Watermark == api.ExportWatermarkColumn {
+			result.Watermark = &utils.QueryResultWatermark{
+				ExportID:   exportID,
+				Requester:  user.Email,
+				ExportedTs: time.Now().Unix(),
+				Column:     exportPolicy.Watermark == api.ExportWatermarkColumn,
+			}
+		}
+		// Each approved request allows one export.
+		if exportRequest != nil {
+			used, err := s.store.UpdateExportRequest(ctx, exportRequest.UID, &store.UpdateExportRequestMessage{
+				UpdaterID: principalID,
+				OldStatus: api.ExportRequestApproved,
+				Status:    api.ExportRequestUsed,
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update export request %d", exportRequest.UID)).SetInternal(err)
+			}
+			if used == nil {
+				return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Export request %d has been used in the meantime", exportRequest.UID))
+			}
+		}
+		if err := s.createSQLEditorExportActivity(ctx, principalID, instance.UID, &api.ActivitySQLEditorExportPayload{
+			ExportID:        exportID,
+			InstanceID:      instance.UID,
+			DatabaseID:      export.DatabaseID,
+			Format:          export.Format,
+			RowCount:        len(export.Rows),
+			ColumnNameList:  export.ColumnNames,
+			ExportRequestID: export.ExportRequestID,
+		}); err != nil {
+			return err
+		}
+		// The file is streamed to the response, the error after this point can only be logged.
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEOctetStream)
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=result.%s", format))
+		c.Response().WriteHeader(http.StatusOK)
+		if err := utils.ExportQueryResult(c.Response(), result, format); err != nil {
+			log.Error("Failed to export query result", zap.Int("instance_id", instance.UID), zap.Error(err))
+		}
+		return nil
+	})
+
+	g.POST("/sql/explain", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		explain := &api.SQLExplain{}
+		if err := json.NewDecoder(c.Request().Body).Decode(explain); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql explain request").SetInternal(err)
+		}
+		if explain.InstanceID == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql explain request, missing instanceId")
+		}
+		if strings.TrimSpace(explain.Statement) == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql explain request, missing sql statement")
+		}
+
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &explain.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", explain.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", explain.InstanceID))
+		}
+		if !api.IsSQLExplainSupported(instance.Engine) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Explain is not supported for engine %q", instance.Engine))
+		}
+		role := c.Get(getRoleContextKey()).(api.Role)
+		if explain.Analyze {
+			if !api.IsSQLExplainAnalyzeSupported(instance.Engine) {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Explain with analyze is not supported for engine %q", instance.Engine))
+			}
+			// Analyze runs the statement, so it's only allowed for the users who can run the statements anyway.
+			if role != api.Owner && role != api.DBA {
+				return echo.NewHTTPError(http.StatusForbidden, "Only the workspace Owners and DBAs can explain with analyze")
+			}
+		}
+		parserEngine := convertToParserEngine(instance.Engine)
+		if !parser.ValidateSQLForEditor(parserEngine, explain.Statement) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql explain request, only support SELECT sql statement")
+		}
+		singleSQLs, err := parser.SplitMultiSQL(parserEngine, explain.Statement)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to split the statement").SetInternal(err)
+		}
+		if len(singleSQLs) != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql explain request, only support a single statement")
+		}
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(singleSQLs[0].Text)), "EXPLAIN") {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql explain request, the statement is an EXPLAIN statement already")
+		}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if _, err := s.checkSQLEditorDatabaseAccess(ctx, principalID, role, instance, explain.DatabaseName, explain.Statement); err != nil {
+			return err
+		}
+
+		statement, err := utils.GetExplainStatement(instance.Engine, singleSQLs[0].Text, explain.Analyze)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		raw, err := func() (string, error) {
+			driver, err := s.dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, explain.DatabaseName)
+			if err != nil {
+				return "", err
+			}
+			defer driver.Close(ctx)
+			// The read-only transaction prevents the analyzed statement from writing, and it's always rolled back.
+			tx, err := driver.GetDB().BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+			if err != nil {
+				return "", err
+			}
+			defer tx.Rollback()
+			var raw string
+			if err := tx.QueryRowContext(ctx, statement).Scan(&raw); err != nil {
+				return "", err
+			}
+			return raw, nil
+		}()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to explain the statement: %v", err)).SetInternal(err)
+		}
+		plan, err := utils.ParseQueryPlan(instance.Engine, raw, explain.Analyze)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to parse the query plan").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, plan)
+	})
+
+	g.POST("/sql/complete", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		request := &api.SQLCompletion{}
+		if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql complete request").SetInternal(err)
+		}
+		if request.InstanceID == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql complete request, missing instanceId")
+		}
+		if request.DatabaseName == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql complete request, missing databaseName")
+		}
+		instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &request.InstanceID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", request.InstanceID)).SetInternal(err)
+		}
+		if instance == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", request.InstanceID))
+		}
+		if !api.IsSQLCompletionSupported(instance.Engine) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Completion is not supported for engine %q", instance.Engine))
+		}
+		database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{EnvironmentID: &instance.EnvironmentID, InstanceID: &instance.ResourceID, DatabaseName: &request.DatabaseName})
+		if err != nil {
+			return err
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database %q not found", request.DatabaseName))
+		}
+		// The statement is incomplete while typing, so we only check the access to the metadata of the database.
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+		hasAccessRights, err := s.hasDatabaseAccessRights(ctx, principalID, role, database)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check access control for database: %q", request.DatabaseName)).SetInternal(err)
+		}
+		if !hasAccessRights {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("No permission to access database %q", request.DatabaseName))
+		}
+		dbSchema, err := s.store.GetDBSchema(ctx, database.UID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get the schema of database %q", request.DatabaseName)).SetInternal(err)
+		}
+		var metadata *storepb.DatabaseMetadata
+		if dbSchema != nil {
+			metadata = dbSchema.Metadata
+		}
+		caretOffset := completion.CaretOffset(request.Statement, request.CaretLine, request.CaretColumn)
+		return c.JSON(http.StatusOK, &api.SQLCompletionResult{
+			CandidateList: completion.Complete(convertToParserEngine(instance.Engine), request.Statement, caretOffset, metadata),
+		})
+	})
+
+	g.POST("/sql/diff", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		request := &api.SQLResultDiff{}
+		if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql diff request").SetInternal(err)
+		}
+		if request.Source == nil || request.Target == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql diff request, missing source or target")
+		}
+		if request.Statement == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql diff request, missing statement")
+		}
+
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+		var results []*queryRowsResult
+		for _, target := range []*api.SQLResultDiffDatabase{request.Source, request.Target} {
+			if target.DatabaseName == "" {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql diff request, missing databaseName")
+			}
+			instance, err := s.store.GetInstanceV2(ctx, &store.FindInstanceMessage{UID: &target.InstanceID})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", target.InstanceID)).SetInternal(err)
+			}
+			if instance == nil {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", target.InstanceID))
+			}
+			if !api.IsSQLCursorSupported(instance.Engine) {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Result diff is not supported for engine %q", instance.Engine))
+			}
+			// The results are masked as the principal queries them in the SQL editor, so the sensitive values are
+			// compared as the masked ones.
+			sensitiveSchemaInfo, queryLimit, err := s.checkSQLEditorQuery(ctx, principalID, role, instance, target.DatabaseName, request.Statement)
+			if err != nil {
+				return err
+			}
+			result, err := s.queryRows(ctx, instance, target.DatabaseName, request.Statement, sensitiveSchemaInfo, queryLimit, api.MaxSQLResultDiffRowCount)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to query database %q: %v", target.DatabaseName, err)).SetInternal(err)
+			}
+			// Diffing the partial results reports the rows beyond the limit as added or removed, so we reject them.
+			if result.truncated {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The result of database %q has more than %d rows, please narrow down the statement", target.DatabaseName, queryLimit.ApplyRowLimit(api.MaxSQLResultDiffRowCount)))
+			}
+			results = append(results, result)
+		}
+
+		diff, err := utils.DiffQueryResult(results[0].columnNames, results[1].columnNames, request.KeyColumnList, results[0].rows, results[1].rows)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return c.JSON(http.StatusOK, diff)
+	})
+
+	g.GET("/sql/history", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if !common.FeatureFlag(common.FeatureFlagQueryHistory) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Query history is not supported yet")
+		}
+		// The users search their own query histories.
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		find := &store.FindQueryHistoryMessage{
+			CreatorID: &principalID,
+			Query:     c.QueryParam("query"),
+		}
+		if v := c.QueryParam("instance"); v != "" {
+			instanceID, err := strconv.Atoi(v)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance ID is not a number: %s", v)).SetInternal(err)
+			}
+			find.InstanceUID = &instanceID
+		}
+		if v := c.QueryParam("database"); v != "" {
+			find.DatabaseName = &v
+		}
+		for _, param := range []struct {
+			name  string
+			value **int64
+		}{
+			{name: "from", value: &find.CreatedTsFrom},
+			{name: "to", value: &find.CreatedTsTo},
+		} {
+			if v := c.QueryParam(param.name); v != "" {
+				ts, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query parameter %s is not a timestamp: %s", param.name, v)).SetInternal(err)
+				}
+				*param.value = &ts
+			}
+		}
+		limit := api.MaxQueryHistoryPageSize
+		if v := c.QueryParam("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Limit is not a positive number: %s", v))
+			}
+			if n < limit {
+				limit = n
+			}
+		}
+		find.Limit = &limit
+		if v := c.QueryParam("offset"); v != "" {
+			offset, err := strconv.Atoi(v)
+			if err != nil || offset < 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Offset is not a non-negative number: %s", v))
+			}
+			find.Offset = &offset
+		}
+
+		queryHistories, err := s.store.ListQueryHistories(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list query histories").SetInternal(err)
+		}
+		queryHistoryList := []*api.QueryHistory{}
+		for _, queryHistory := range queryHistories {
+			queryHistoryList = append(queryHistoryList, &api.QueryHistory{
+				ID:           queryHistory.ID,
+				CreatorID:    queryHistory.CreatorID,
+				CreatedTs:    queryHistory.CreatedTs,
+				InstanceID:   queryHistory.InstanceUID,
+				DatabaseID:   queryHistory.DatabaseUID,
+				DatabaseName: queryHistory.DatabaseName,
+				Statement:    queryHistory.Statement,
+				DurationNs:   queryHistory.DurationNs,
+				Error:        queryHistory.Error,
+			})
+		}
+		return c.JSON(http.StatusOK, queryHistoryList)
+	})
+
+	g.GET("/sql/cursor/:cursorID", func(c echo.Context) error {
+		offset, err := strconv.Atoi(c.QueryParam("offset"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Offset is not a number: %s", c.QueryParam("offset"))).SetInternal(err)
+		}
+		limit, err := strconv.Atoi(c.QueryParam("limit"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Limit is not a number: %s", c.QueryParam("limit"))).SetInternal(err)
+		}
+		if limit <= 0 || limit > api.MaxSQLCursorPageSize {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Limit must be in (0, %d]", api.MaxSQLCursorPageSize))
+		}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		cursor, rows, err := s.queryCursorManager.Fetch(c.Param("cursorID"), principalID, offset, limit)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "Query cursor not found, the query may have expired, please run it again").SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, &api.SQLCursorPage{
+			Data:      []any{cursor.ColumnNames, cursor.ColumnTypeNames, rows, cursor.SensitiveList},
+			RowCount:  cursor.RowCount,
+			Truncated: cursor.Truncated,
+		})
+	})
+
+	g.POST("/sql/cursor/:cursorID/chart", func(c echo.Context) error {
+		request := &api.SQLResultChart{}
+		if err := json.NewDecoder(c.Request().Body).Decode(request); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql result chart request").SetInternal(err)
+		}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		// The zero limit fetches the columns of the cursor only.
+		cursor, _, err := s.queryCursorManager.Fetch(c.Param("cursorID"), principalID, 0, 0)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "Query cursor not found, the query may have expired, please run it again").SetInternal(err)
+		}
+		chart, err := utils.NewQueryResultChart(cursor.ColumnNames, cursor.SensitiveList, request)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		var chartErr error
+		if _, err := s.queryCursorManager.Scan(cursor.ID, principalID, func(row []any) error {
+			if err := chart.Add(row); err != nil {
+				chartErr = err
+				return util.ErrSkipRemainingRows
+			}
+			return nil
+		}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to read the query cursor").SetInternal(err)
+		}
+		if chartErr != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, chartErr.Error())
+		}
+		result := chart.Result()
+		result.Truncated = cursor.Truncated
+		return c.JSON(http.StatusOK, result)
+	})
+
+	g.DELETE("/sql/cursor/:cursorID", func(c echo.Context) error {
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if err := s.queryCursorManager.Close(c.Param("cursorID"), principalID); err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "Query cursor not found").SetInternal(err)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	g.POST("/sql/query/:queryID/cancel", func(c echo.Context) error {
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if err := s.runningQueryManager.Cancel(c.Param("queryID"), principalID); err != nil {
+			return echo.NewHTTPError(http.StatusNotFound, "Running query not found").SetInternal(err)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
 	g.POST("/sql/execute/admin", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		exec := &api.SQLExecute{}
@@ -614,6 +1172,21 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		}
 		// Admin API always executes with read-only off.
 		exec.Readonly = false
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if err := s.checkAdminExecuteAccess(ctx, principalID, c.Get(getRoleContextKey()).(api.Role), database); err != nil {
+			return err
+		}
+		var adminSession *store.AdminSessionMessage
+		if common.FeatureFlag(common.FeatureFlagAdminSession) {
+			adminSession, err = s.getExecuteAdminSession(ctx, principalID, instance, exec)
+			if err != nil {
+				return err
+			}
+			// The statement is recorded before the execution, so it can't be executed without being recorded.
+			if _, err := s.store.AppendAdminSessionEntry(ctx, adminSession.ID, api.AdminSessionEntryCommand, exec.Statement); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to record the statement in admin session ID: %d", adminSession.ID)).SetInternal(err)
+			}
+		}
 		start := time.Now().UnixNano()
 
 		singleSQLResults, queryErr := func() ([]api.SingleSQLResult, error) {
@@ -624,7 +1197,12 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			defer driver.Close(ctx)
 
 			// TODO(p0ny): refactor
-			if instance.Engine == db.MongoDB || instance.Engine == db.Spanner || instance.Engine == db.Redis {
+			if instance.Engine == db.MongoDB || instance.Engine == db.Spanner || instance.Engine == db.Redis || instance.Engine == db.Elasticsearch || instance.Engine == db.Cassandra || instance.Engine == db.DynamoDB || instance.Engine == db.Neo4j {
+				ctx, finish, err := s.runningQueryManager.Start(ctx, exec.QueryID, principalID, instance.UID, instance.Engine, nil, nil)
+				if err != nil {
+					return nil, err
+				}
+				defer finish()
 				data, err := driver.QueryConn(ctx, nil, exec.Statement, &db.QueryContext{
 					Limit:               exec.Limit,
 					ReadOnly:            false,
@@ -652,6 +1230,11 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				return nil, err
 			}
 			defer conn.Close()
+			ctx, finish, err := s.runningQueryManager.Start(ctx, exec.QueryID, principalID, instance.UID, instance.Engine, sqlDB, conn)
+			if err != nil {
+				return nil, err
+			}
+			defer finish()
 
 			var singleSQLResults []api.SingleSQLResult
 			// We split the query into multiple statements and execute them one by one for MySQL and PostgreSQL.
@@ -719,6 +1302,19 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			return singleSQLResults, nil
 		}()
 
+		adminSessionID := 0
+		if adminSession != nil {
+			adminSessionID = adminSession.ID
+			if err := s.recordAdminSessionResponse(ctx, adminSession, singleSQLResults, queryErr); err != nil {
+				log.Error("Failed to record the response in admin session", zap.Int("adminSessionID", adminSession.ID), zap.Error(err))
+			}
+			if exec.AdminSessionID == 0 {
+				if err := s.store.EndAdminSession(ctx, adminSession.ID); err != nil {
+					log.Error("Failed to end admin session", zap.Int("adminSessionID", adminSession.ID), zap.Error(err))
+				}
+			}
+		}
+
 		level := api.ActivityInfo
 		errMessage := ""
 		if err != nil {
@@ -743,6 +1339,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			DatabaseID:             databaseID,
 			DatabaseName:           exec.DatabaseName,
 			Error:                  errMessage,
+			AdminSessionID:         adminSessionID,
 		}); err != nil {
 			return err
 		}
@@ -802,10 +1399,55 @@ func convertToParserEngine(engine db.Type) parser.EngineType {
 		return parser.MSSQL
 	case db.OceanBase:
 		return parser.OceanBase
+	case db.Snowflake:
+		return parser.Snowflake
 	}
 	return parser.Standard
 }
 
+// getExportableDatabase returns the database if the principal has the permission to export its query results.
+func (s *Server) getExportableDatabase(ctx context.Context, principalID int, role api.Role, databaseID int) (*store.DatabaseMessage, error) {
+	database, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{UID: &databaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", databaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", databaseID))
+	}
+	projectPolicy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{ProjectID: &database.ProjectID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the policy of project %q", database.ProjectID)).SetInternal(err)
+	}
+	hasPermission, err := s.hasProjectPermission(ctx, principalID, role, projectPolicy, api.ProjectPermissionExportData)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check the export permission").SetInternal(err)
+	}
+	if !hasPermission {
+		return nil, echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Not allowed to export the results of database %q", database.DatabaseName))
+	}
+	return database, nil
+}
+
+func (s *Server) createSQLEditorExportActivity(ctx context.Context, principalID int, containerID int, payload *api.ActivitySQLEditorExportPayload) error {
+	activityBytes, err := json.Marshal(payload)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+	}
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   principalID,
+		Type:        api.ActivitySQLEditorExport,
+		ContainerID: containerID,
+		Level:       api.ActivityInfo,
+		Comment:     fmt.Sprintf("Exported %d rows of instance %d as %s, export ID %s.", payload.RowCount, payload.InstanceID, payload.Format, payload.ExportID),
+		Payload:     string(activityBytes),
+	}
+	// The export is refused if it can't be audited.
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create export activity").SetInternal(err)
+	}
+	return nil
+}
+
 func (s *Server) createSQLEditorQueryActivity(ctx context.Context, c echo.Context, level api.ActivityLevel, containerID int, payload api.ActivitySQLEditorQueryPayload) error {
 	activityBytes, err := json.Marshal(payload)
 	if err != nil {
@@ -835,6 +1477,25 @@ func (s *Server) createSQLEditorQueryActivity(ctx context.Context, c echo.Contex
 			zap.Error(err))
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create activity").SetInternal(err)
 	}
+
+	if !common.FeatureFlag(common.FeatureFlagQueryHistory) {
+		return nil
+	}
+	// The query history is for the users to search, failing to record it shouldn't fail the query.
+	if err := s.store.CreateQueryHistory(ctx, &store.QueryHistoryMessage{
+		CreatorID:    activityCreate.CreatorID,
+		InstanceUID:  payload.InstanceID,
+		DatabaseUID:  payload.DatabaseID,
+		DatabaseName: payload.DatabaseName,
+		Statement:    payload.Statement,
+		DurationNs:   payload.DurationNs,
+		Error:        payload.Error,
+	}); err != nil {
+		log.Warn("Failed to create query history after executing sql statement",
+			zap.String("database_name", payload.DatabaseName),
+			zap.Int("instance_id", payload.InstanceID),
+			zap.Error(err))
+	}
 	return nil
 }
 
@@ -847,6 +1508,7 @@ func (s *Server) sqlCheck(
 	statement string,
 	catalog catalog.Catalog,
 	driver *sql.DB,
+	database *store.DatabaseMessage,
 ) (advisor.Status, []advisor.Advice, error) {
 	var adviceList []advisor.Advice
 	policy, err := s.store.GetSQLReviewPolicy(ctx, environmentID)
@@ -856,14 +1518,40 @@ func (s *Server) sqlCheck(
 		}
 		return advisor.Error, nil, err
 	}
+	if database != nil {
+		project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &database.ProjectID})
+		if err != nil {
+			return advisor.Error, nil, err
+		}
+		if project != nil {
+			override, err := s.store.GetSQLReviewOverridePolicy(ctx, project.UID)
+			if err != nil {
+				return advisor.Error, nil, err
+			}
+			policy = policy.Override(override.PinnedVersion, override.RuleList)
+		}
+	}
+
+	var unusedIndexList []*advisor.UnusedIndex
+	var changeHistoryList []*advisor.ChangeHistory
+	if database != nil {
+		if unusedIndexList, err = utils.GetUnusedIndexList(ctx, s.store, database.UID); err != nil {
+			return advisor.Error, nil, err
+		}
+		if changeHistoryList, err = utils.GetChangeHistoryListForSQLReview(ctx, s.store, database, policy.RuleList); err != nil {
+			return advisor.Error, nil, err
+		}
+	}
 
 	res, err := advisor.SQLReviewCheck(statement, policy.RuleList, advisor.SQLReviewCheckContext{
-		Charset:   dbCharacterSet,
-		Collation: dbCollation,
-		DbType:    dbType,
-		Catalog:   catalog,
-		Driver:    driver,
-		Context:   ctx,
+		Charset:           dbCharacterSet,
+		Collation:         dbCollation,
+		DbType:            dbType,
+		Catalog:           catalog,
+		Driver:            driver,
+		Context:           ctx,
+		UnusedIndexList:   unusedIndexList,
+		ChangeHistoryList: changeHistoryList,
 	})
 	if err != nil {
 		return advisor.Error, nil, err
@@ -879,7 +1567,10 @@ func (s *Server) sqlCheck(
 		case advisor.Error:
 			adviceLevel = advisor.Error
 		case advisor.Success:
-			continue
+			// Keep the index usage context for the reviewers.
+			if advice.Code != advisor.IndexUnused {
+				continue
+			}
 		}
 
 		adviceList = append(adviceList, advice)
@@ -915,11 +1606,24 @@ func checkPostgreSQLIndexHit(statement string, plan string) []advisor.Advice {
 	return nil
 }
 
-func (s *Server) getSensitiveSchemaInfo(ctx context.Context, instance *store.InstanceMessage, databaseList []string, currentDatabase string) (*db.SensitiveSchemaInfo, error) {
-	type sensitiveDataMap map[api.SensitiveData]api.SensitiveDataMaskType
+func (s *Server) getSensitiveSchemaInfo(ctx context.Context, principalID int, instance *store.InstanceMessage, databaseList []string, currentDatabase string) (*db.SensitiveSchemaInfo, error) {
+	type sensitiveDataMap map[api.SensitiveData]api.SensitiveData
 	isEmpty := true
+	user, err := s.store.GetUserByID(ctx, principalID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.Errorf("principal %d not found", principalID)
+	}
 	result := &db.SensitiveSchemaInfo{
-		DatabaseList: []db.DatabaseSchema{},
+		DatabaseList:  []db.DatabaseSchema{},
+		MaskingSecret: s.maskingSecret,
+		Requester: &db.MaskingRequester{
+			Email: user.Email,
+			Name:  user.Name,
+			Role:  string(user.Role),
+		},
 	}
 	for _, name := range databaseList {
 		databaseName := name
@@ -957,7 +1661,7 @@ func (s *Server) getSensitiveSchemaInfo(ctx context.Context, instance *store.Ins
 				Schema: data.Schema,
 				Table:  data.Table,
 				Column: data.Column,
-			}] = data.Type
+			}] = data
 		}
 
 		dbSchema, err := s.store.GetDBSchema(ctx, database.UID)
@@ -979,14 +1683,16 @@ func (s *Server) getSensitiveSchemaInfo(ctx context.Context, instance *store.Ins
 					tableSchema.Name = fmt.Sprintf("%s.%s", schema.Name, table.Name)
 				}
 				for _, column := range table.Columns {
-					_, sensitive := columnMap[api.SensitiveData{
+					data, sensitive := columnMap[api.SensitiveData{
 						Schema: schema.Name,
 						Table:  table.Name,
 						Column: column.Name,
 					}]
 					tableSchema.ColumnList = append(tableSchema.ColumnList, db.ColumnInfo{
-						Name:      column.Name,
-						Sensitive: sensitive,
+						Name:          column.Name,
+						Sensitive:     sensitive,
+						MaskType:      db.SensitiveDataMaskType(data.Type),
+						MaskCondition: data.Condition,
 					})
 				}
 				databaseSchema.TableList = append(databaseSchema.TableList, tableSchema)
@@ -1035,11 +1741,185 @@ func isMySQLExcludeDatabase(database string) bool {
 	return true
 }
 
+// checkSQLEditorDatabaseAccess checks the principal can access the database and the databases referred in the statement
+// of the MySQL dialect, which can query across the databases. It returns the database if the database name isn't empty.
+func (s *Server) checkSQLEditorDatabaseAccess(ctx context.Context, principalID int, role api.Role, instance *store.InstanceMessage, databaseName, statement string) (*store.DatabaseMessage, error) {
+	var database *store.DatabaseMessage
+	if databaseName != "" {
+		var err error
+		database, err = s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{EnvironmentID: &instance.EnvironmentID, InstanceID: &instance.ResourceID, DatabaseName: &databaseName})
+		if err != nil {
+			return nil, err
+		}
+		if database == nil {
+			return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database %q not found", databaseName))
+		}
+		// Database Access Control
+		hasAccessRights, err := s.hasDatabaseAccessRights(ctx, principalID, role, database)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check access control for database: %q", databaseName)).SetInternal(err)
+		}
+		if !hasAccessRights {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql execute request, no permission to access database %q", databaseName))
+		}
+	}
+
+	// Database Access Control for MySQL dialect.
+	// MySQL dialect can query cross the database.
+	// We need special check.
+	if instance.Engine == db.MySQL || instance.Engine == db.TiDB || instance.Engine == db.MariaDB || instance.Engine == db.OceanBase {
+		databaseList, err := parser.ExtractDatabaseList(parser.MySQL, statement)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to extract database list: %q", statement)).SetInternal(err)
+		}
+
+		if databaseName != "" {
+			// Disallow cross-database query if specify database.
+			for _, accessDatabaseName := range databaseList {
+				upperDatabaseName := strings.ToUpper(accessDatabaseName)
+				// We allow querying information schema.
+				if upperDatabaseName == "" || upperDatabaseName == "INFORMATION_SCHEMA" {
+					continue
+				}
+				if accessDatabaseName != databaseName {
+					return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql execute request, specify database %q but access database %q", databaseName, accessDatabaseName))
+				}
+			}
+		} else {
+			// Check database access rights.
+			for _, accessDatabaseName := range databaseList {
+				if accessDatabaseName == "" {
+					// We have already checked the current database access rights.
+					continue
+				}
+				accessDatabase, err := s.store.GetDatabaseV2(ctx, &store.FindDatabaseMessage{EnvironmentID: &instance.EnvironmentID, InstanceID: &instance.ResourceID, DatabaseName: &accessDatabaseName})
+				if err != nil {
+					if httpErr, ok := err.(*echo.HTTPError); ok && httpErr.Code == echo.ErrNotFound.Code {
+						// If database not found, skip.
+						continue
+					}
+					return nil, err
+				}
+
+				hasAccessRights, err := s.hasDatabaseAccessRights(ctx, principalID, role, accessDatabase)
+				if err != nil {
+					return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check access control for database: %q", accessDatabase.DatabaseName)).SetInternal(err)
+				}
+				if !hasAccessRights {
+					return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql execute request, no permission to access database %q", accessDatabase.DatabaseName))
+				}
+			}
+		}
+	}
+	return database, nil
+}
+
+// checkSQLEditorQuery checks the principal can run the readonly statement in the SQL editor, and returns the sensitive
+// schema info to mask the result and the query limits, which are the same as /sql/execute.
+func (s *Server) checkSQLEditorQuery(ctx context.Context, principalID int, role api.Role, instance *store.InstanceMessage, databaseName, statement string) (*db.SensitiveSchemaInfo, *utils.QueryLimit, error) {
+	if !parser.ValidateSQLForEditor(convertToParserEngine(instance.Engine), statement) {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Only the SELECT statements are allowed")
+	}
+	database, err := s.checkSQLEditorDatabaseAccess(ctx, principalID, role, instance, databaseName, statement)
+	if err != nil {
+		return nil, nil, err
+	}
+	queryLimit, err := s.getQueryLimit(ctx, principalID, role, instance, database)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get the query limit policy").SetInternal(err)
+	}
+	var sensitiveSchemaInfo *db.SensitiveSchemaInfo
+	switch instance.Engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		databaseList, err := parser.ExtractDatabaseList(parser.MySQL, statement)
+		if err != nil {
+			return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get database list: %s", statement)).SetInternal(err)
+		}
+		if sensitiveSchemaInfo, err = s.getSensitiveSchemaInfo(ctx, principalID, instance, databaseList, databaseName); err != nil {
+			return nil, nil, err
+		}
+	case db.Postgres:
+		if sensitiveSchemaInfo, err = s.getSensitiveSchemaInfo(ctx, principalID, instance, []string{databaseName}, databaseName); err != nil {
+			return nil, nil, err
+		}
+	}
+	return sensitiveSchemaInfo, queryLimit, nil
+}
+
+// canSetSessionVariables returns whether the principal can set the session variables for querying the database, the
+// database is nil for the instance level queries. Only the workspace Owners and DBAs, and the project Owners and
+// Developers of the database can set them.
+func (s *Server) canSetSessionVariables(ctx context.Context, principalID int, role api.Role, database *store.DatabaseMessage) (bool, error) {
+	if role == api.Owner || role == api.DBA {
+		return true, nil
+	}
+	if database == nil {
+		return false, nil
+	}
+	projectPolicy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{ProjectID: &database.ProjectID})
+	if err != nil {
+		return false, err
+	}
+	return isProjectOwnerOrDeveloper(principalID, projectPolicy), nil
+}
+
+// getQueryLimit returns the limits of the query limit policy of the environment for the principal querying the
+// database, the database is nil for the instance level queries.
+func (s *Server) getQueryLimit(ctx context.Context, principalID int, role api.Role, instance *store.InstanceMessage, database *store.DatabaseMessage) (*utils.QueryLimit, error) {
+	environmentID := instance.EnvironmentID
+	if database != nil {
+		environmentID = database.EnvironmentID
+	}
+	environment, err := s.store.GetEnvironmentV2(ctx, &store.FindEnvironmentMessage{ResourceID: &environmentID})
+	if err != nil {
+		return nil, err
+	}
+	if environment == nil {
+		return nil, errors.Errorf("environment %q not found", environmentID)
+	}
+	policy, err := s.store.GetQueryLimitPolicy(ctx, environment.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectRoles map[api.Role]bool
+	// Workspace Owners and DBAs assume the project Owner role for all projects.
+	if role == api.Owner || role == api.DBA {
+		projectRoles = map[api.Role]bool{api.Owner: true}
+	}
+	if database != nil {
+		if projectRoles == nil {
+			projectRoles = make(map[api.Role]bool)
+		}
+		projectPolicy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{ProjectID: &database.ProjectID})
+		if err != nil {
+			return nil, err
+		}
+		for _, binding := range projectPolicy.Bindings {
+			for _, member := range binding.Members {
+				if member.ID == principalID {
+					projectRoles[binding.Role] = true
+					break
+				}
+			}
+		}
+	}
+	return utils.GetEffectiveQueryLimit(policy, projectRoles), nil
+}
+
 func (s *Server) hasDatabaseAccessRights(ctx context.Context, principalID int, role api.Role, database *store.DatabaseMessage) (bool, error) {
 	// Workspace Owners and DBAs always have database access rights.
 	if role == api.Owner || role == api.DBA {
 		return true, nil
 	}
+	// The temporary access granted to the principal overrides the project permissions and the access control policies.
+	granted, err := s.hasActiveDatabaseGrant(ctx, principalID, database.UID, api.DatabaseGrantQuerier)
+	if err != nil {
+		return false, err
+	}
+	if granted {
+		return true, nil
+	}
 
 	project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &database.ProjectID})
 	if err != nil {
@@ -1050,12 +1930,16 @@ func (s *Server) hasDatabaseAccessRights(ctx context.Context, principalID int, r
 		return false, err
 	}
 
-	// Only project owner or developer can access database.
+	// Only the project members with the query permission can access database.
 	projectPolicy, err := s.store.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{ProjectID: &project.ResourceID})
 	if err != nil {
 		return false, err
 	}
-	if !isProjectOwnerOrDeveloper(principalID, projectPolicy) {
+	hasPermission, err := s.hasProjectPermission(ctx, principalID, role, projectPolicy, api.ProjectPermissionQueryDatabase)
+	if err != nil {
+		return false, err
+	}
+	if !hasPermission {
 		return false, nil
 	}
 
@@ -1091,3 +1975,99 @@ func (s *Server) hasDatabaseAccessRights(ctx context.Context, principalID int, r
 	}
 	return hasAccessRights, nil
 }
+
+// queryRowsResult is the result of queryRows.
+type queryRowsResult struct {
+	columnNames     []string
+	columnTypeNames []string
+	rows            [][]any
+	sensitiveList   []bool
+	// truncated is true if the rows beyond the limit are dropped.
+	truncated bool
+}
+
+// queryRows runs the readonly statement and returns at most limit rows masked with the sensitive schema info, under
+// the query limits.
+func (s *Server) queryRows(ctx context.Context, instance *store.InstanceMessage, databaseName, statement string, sensitiveSchemaInfo *db.SensitiveSchemaInfo, queryLimit *utils.QueryLimit, limit int) (*queryRowsResult, error) {
+	ctx, cancel := queryLimit.WithTimeout(ctx)
+	defer cancel()
+	limit = queryLimit.ApplyRowLimit(limit)
+	driver, err := s.dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+	conn, err := driver.GetDB().Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	engine := instance.Engine
+	// The Redshift driver queries in the PostgreSQL dialect.
+	if engine == db.Redshift {
+		engine = db.Postgres
+	}
+	result := &queryRowsResult{rows: [][]any{}}
+	columnNames, columnTypeNames, sensitiveList, err := util.QueryRows(ctx, engine, conn, statement, &db.QueryContext{
+		ReadOnly:              true,
+		CurrentDatabase:       databaseName,
+		SensitiveDataMaskType: db.SensitiveDataMaskTypeDefault,
+		SensitiveSchemaInfo:   sensitiveSchemaInfo,
+	}, func(row []any) error {
+		if len(result.rows) >= limit {
+			result.truncated = true
+			return util.ErrSkipRemainingRows
+		}
+		result.rows = append(result.rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.columnNames = columnNames
+	result.columnTypeNames = columnTypeNames
+	result.sensitiveList = sensitiveList
+	return result, nil
+}
+
+// queryWithCursor materializes the whole result of the readonly query into a cursor, and returns the first page.
+// The statement isn't limited, the cursor manager caps the rows materialized to maxRowCount instead.
+func (s *Server) queryWithCursor(ctx context.Context, conn *sql.Conn, instance *store.InstanceMessage, exec *api.SQLExecute, principalID int, sensitiveSchemaInfo *db.SensitiveSchemaInfo, maxRowCount int) []api.SingleSQLResult {
+	engine := instance.Engine
+	// The Redshift driver queries in the PostgreSQL dialect.
+	if engine == db.Redshift {
+		engine = db.Postgres
+	}
+	queryContext := &db.QueryContext{
+		ReadOnly:              true,
+		CurrentDatabase:       exec.DatabaseName,
+		SensitiveDataMaskType: db.SensitiveDataMaskTypeDefault,
+		SensitiveSchemaInfo:   sensitiveSchemaInfo,
+	}
+	cursor, err := s.queryCursorManager.Create(principalID, maxRowCount, func(onRow func(row []any) error) ([]string, []string, []bool, error) {
+		return util.QueryRows(ctx, engine, conn, exec.Statement, queryContext, onRow)
+	})
+	if err != nil {
+		return []api.SingleSQLResult{{Error: err.Error()}}
+	}
+
+	pageSize := exec.Limit
+	if pageSize <= 0 || pageSize > api.MaxSQLCursorPageSize {
+		pageSize = api.MaxSQLCursorPageSize
+	}
+	_, rows, err := s.queryCursorManager.Fetch(cursor.ID, principalID, 0, pageSize)
+	if err != nil {
+		return []api.SingleSQLResult{{Error: err.Error()}}
+	}
+	data, err := json.Marshal([]any{cursor.ColumnNames, cursor.ColumnTypeNames, rows, cursor.SensitiveList})
+	if err != nil {
+		return []api.SingleSQLResult{{Error: err.Error()}}
+	}
+	return []api.SingleSQLResult{{
+		Data:      string(data),
+		CursorID:  cursor.ID,
+		RowCount:  cursor.RowCount,
+		Truncated: cursor.Truncated,
+	}}
+}
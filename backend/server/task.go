@@ -1,9 +1,11 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
@@ -13,6 +15,7 @@ import (
 
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
+	"github.com/bytebase/bytebase/backend/component/activity"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 	"github.com/bytebase/bytebase/backend/store"
 	"github.com/bytebase/bytebase/backend/utils"
@@ -235,6 +238,17 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 			}
 		}
 
+		if taskStatusPatch.Status == api.TaskPending && taskStatusPatch.RerunFromScratch {
+			// The empty checkpoint matches no sheet, so the resumable task executes all the statements again.
+			if _, err := s.store.UpdateTaskV2(ctx, &api.TaskPatch{
+				ID:                  task.ID,
+				UpdaterID:           currentPrincipalID,
+				StatementCheckpoint: &api.StatementCheckpoint{},
+			}); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to reset the checkpoint of task %q", task.Name)).SetInternal(err)
+			}
+		}
+
 		if taskStatusPatch.Status == api.TaskDone {
 			// the user marks the task as DONE, set Skipped to true and SkippedReason to Comment.
 			skipped := true
@@ -294,6 +308,144 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 		return nil
 	})
 
+	g.PATCH("/pipeline/:pipelineID/task/:taskID/chunked-dml", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+		chunkedDMLPatch := &api.ChunkedDMLPatch{}
+		if err := json.NewDecoder(c.Request().Body).Decode(chunkedDMLPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed chunked DML patch request").SetInternal(err)
+		}
+
+		task, err := s.store.GetTaskV2ByID(ctx, taskID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get task ID: %v", taskID)).SetInternal(err)
+		}
+		if task == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", taskID))
+		}
+		if task.Type != api.TaskDatabaseDataUpdate {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %q is not a data update task", task.Name))
+		}
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Invalid database data update payload").SetInternal(err)
+		}
+		if payload.Chunked == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %q is not executed in chunks", task.Name))
+		}
+		if task.Status != api.TaskRunning {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %q is not running", task.Name))
+		}
+
+		// The running task checks the flag before each chunk.
+		if chunkedDMLPatch.Paused {
+			s.stateCfg.ChunkedDMLPaused.Store(task.ID, true)
+		} else {
+			s.stateCfg.ChunkedDMLPaused.Delete(task.ID)
+		}
+
+		composedTask, err := s.store.GetTaskByID(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, composedTask); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal update task \"%v\" chunked DML response", task.Name)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/pipeline/:pipelineID/task/:taskID/dependency-override", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+		overridePatch := &api.DependencyOverridePatch{}
+		if err := json.NewDecoder(c.Request().Body).Decode(overridePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed dependency override request").SetInternal(err)
+		}
+		if overridePatch.Reason == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "The reason of the dependency override is required")
+		}
+
+		task, err := s.store.GetTaskV2ByID(ctx, taskID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get task ID: %v", taskID)).SetInternal(err)
+		}
+		if task == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task not found with ID %d", taskID))
+		}
+		if task.Type != api.TaskDatabaseSchemaUpdate && task.Type != api.TaskDatabaseSchemaUpdateGhostSync {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %q is not a schema update task", task.Name))
+		}
+		if task.Status != api.TaskPendingApproval && task.Status != api.TaskPending && task.Status != api.TaskFailed {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %q is %s", task.Name, task.Status))
+		}
+		issue, err := s.store.GetIssueV2(ctx, &store.FindIssueMessage{PipelineID: &task.PipelineID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue with pipeline ID %d", task.PipelineID)).SetInternal(err)
+		}
+		if issue == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue not found with pipeline ID %d", task.PipelineID))
+		}
+		payload := &struct {
+			SheetID int `json:"sheetId"`
+		}{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Invalid database schema update payload").SetInternal(err)
+		}
+
+		currentPrincipalID := c.Get(getPrincipalIDContextKey()).(int)
+		task, err = s.store.UpdateTaskV2(ctx, &api.TaskPatch{
+			ID:        task.ID,
+			UpdaterID: currentPrincipalID,
+			DependencyOverride: &api.DependencyOverride{
+				SheetID:    payload.SheetID,
+				ApproverID: currentPrincipalID,
+				Reason:     overridePatch.Reason,
+				CreatedTs:  time.Now().Unix(),
+			},
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to override the dependency check of task %q", task.Name)).SetInternal(err)
+		}
+		activityPayload, err := json.Marshal(api.ActivityIssueCommentCreatePayload{
+			IssueName: issue.Title,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+		}
+		activityCreate := &api.ActivityCreate{
+			CreatorID:   currentPrincipalID,
+			ContainerID: issue.UID,
+			Type:        api.ActivityIssueCommentCreate,
+			Level:       api.ActivityInfo,
+			Comment:     fmt.Sprintf("Overrode the dependency check of task %q: %s", task.Name, overridePatch.Reason),
+			Payload:     string(activityPayload),
+		}
+		if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &activity.Metadata{Issue: issue}); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after overriding the dependency check of task %q", task.Name)).SetInternal(err)
+		}
+		// Re-run the checks to downgrade the failed dependency check.
+		if err := s.TaskCheckScheduler.ScheduleCheck(ctx, task, currentPrincipalID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to run task check \"%v\"", task.Name)).SetInternal(err)
+		}
+
+		composedTask, err := s.store.GetTaskByID(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, composedTask); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal update task \"%v\" dependency override response", task.Name)).SetInternal(err)
+		}
+		return nil
+	})
+
 	g.POST("/pipeline/:pipelineID/task/:taskID/check", func(c echo.Context) error {
 		ctx := c.Request().Context()
 		taskID, err := strconv.Atoi(c.Param("taskID"))
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// taskLogStreamInterval is the interval to check the changes of the task runs and task check runs to stream.
+const taskLogStreamInterval = time.Second
+
+// taskLogStreamContentType is the newline delimited JSON, which the clients can decode line by line.
+const taskLogStreamContentType = "application/x-ndjson"
+
+// registerTaskLogStreamRoutes registers the routes streaming the task runs and task check runs as they change, so that
+// the CLIs and CI jobs can tail the rollout instead of polling the task.
+func (s *Server) registerTaskLogStreamRoutes(g *echo.Group) {
+	// The stream ends after the task is done, failed or canceled.
+	g.GET("/pipeline/:pipelineID/task/:taskID/run/stream", func(c echo.Context) error {
+		task, err := s.getPipelineTask(c)
+		if err != nil {
+			return err
+		}
+		return streamTaskLog(c, taskLogStreamInterval, func(ctx context.Context) ([]*api.TaskRunLogEntry, bool, error) {
+			taskRuns, err := s.store.ListTaskRun(ctx, &store.TaskRunFind{TaskID: &task.ID})
+			if err != nil {
+				return nil, false, err
+			}
+			var progress *api.Progress
+			if v, ok := s.stateCfg.TaskProgress.Load(task.ID); ok {
+				p := v.(api.Progress)
+				progress = &p
+			}
+			var entries []*api.TaskRunLogEntry
+			for _, taskRun := range taskRuns {
+				entry := &api.TaskRunLogTaskRun{
+					ID:        taskRun.ID,
+					TaskID:    taskRun.TaskID,
+					Name:      taskRun.Name,
+					Status:    taskRun.Status,
+					Code:      taskRun.Code,
+					Comment:   taskRun.Comment,
+					Result:    taskRun.Result,
+					UpdatedTs: taskRun.UpdatedTs,
+				}
+				if taskRun.Status == api.TaskRunRunning {
+					entry.Progress = progress
+				}
+				entries = append(entries, &api.TaskRunLogEntry{TaskRun: entry})
+			}
+			latestTask, err := s.store.GetTaskV2ByID(ctx, task.ID)
+			if err != nil {
+				return nil, false, err
+			}
+			if latestTask == nil {
+				return entries, true, nil
+			}
+			done := latestTask.Status == api.TaskDone || latestTask.Status == api.TaskFailed || latestTask.Status == api.TaskCanceled
+			return entries, done, nil
+		})
+	})
+
+	// The stream ends after no task check runs are running.
+	g.GET("/pipeline/:pipelineID/task/:taskID/check/stream", func(c echo.Context) error {
+		task, err := s.getPipelineTask(c)
+		if err != nil {
+			return err
+		}
+		return streamTaskLog(c, taskLogStreamInterval, func(ctx context.Context) ([]*api.TaskRunLogEntry, bool, error) {
+			taskCheckRuns, err := s.store.ListTaskCheckRuns(ctx, &store.TaskCheckRunFind{TaskID: &task.ID})
+			if err != nil {
+				return nil, false, err
+			}
+			var entries []*api.TaskRunLogEntry
+			done := true
+			for _, taskCheckRun := range taskCheckRuns {
+				if taskCheckRun.Status == api.TaskCheckRunRunning {
+					done = false
+				}
+				entries = append(entries, &api.TaskRunLogEntry{TaskCheckRun: &api.TaskRunLogTaskCheckRun{
+					ID:        taskCheckRun.ID,
+					TaskID:    taskCheckRun.TaskID,
+					Type:      taskCheckRun.Type,
+					Status:    taskCheckRun.Status,
+					Code:      taskCheckRun.Code,
+					Comment:   taskCheckRun.Comment,
+					Result:    taskCheckRun.Result,
+					UpdatedTs: taskCheckRun.UpdatedTs,
+				}})
+			}
+			return entries, done, nil
+		})
+	})
+}
+
+// getPipelineTask gets the task by the pipeline and task ID in the path.
+func (s *Server) getPipelineTask(c echo.Context) (*store.TaskMessage, error) {
+	pipelineID, err := strconv.Atoi(c.Param("pipelineID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Pipeline ID is not a number: %s", c.Param("pipelineID"))).SetInternal(err)
+	}
+	taskID, err := strconv.Atoi(c.Param("taskID"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+	}
+	task, err := s.store.GetTaskV2ByID(c.Request().Context(), taskID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch task %d", taskID)).SetInternal(err)
+	}
+	if task == nil || task.PipelineID != pipelineID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Task %d not found in pipeline %d", taskID, pipelineID))
+	}
+	return task, nil
+}
+
+// streamTaskLog writes the entries returned by list every interval until list returns done or the client disconnects.
+// Only the new and changed entries are written.
+func streamTaskLog(c echo.Context, interval time.Duration, list func(ctx context.Context) ([]*api.TaskRunLogEntry, bool, error)) error {
+	ctx := c.Request().Context()
+	c.Response().Header().Set(echo.HeaderContentType, taskLogStreamContentType)
+	c.Response().WriteHeader(http.StatusOK)
+
+	lastLines := make(map[string]string)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		entries, done, err := list(ctx)
+		if err != nil {
+			// The response has started, so the error can only be logged.
+			if ctx.Err() == nil {
+				log.Error("Failed to list the task log entries to stream", zap.Error(err))
+			}
+			return nil
+		}
+		for _, entry := range entries {
+			line, err := json.Marshal(entry)
+			if err != nil {
+				log.Error("Failed to marshal the task log entry", zap.Error(err))
+				return nil
+			}
+			key := taskRunLogEntryKey(entry)
+			if lastLines[key] == string(line) {
+				continue
+			}
+			lastLines[key] = string(line)
+			if _, err := c.Response().Write(append(line, '\n')); err != nil {
+				return nil
+			}
+		}
+		c.Response().Flush()
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func taskRunLogEntryKey(entry *api.TaskRunLogEntry) string {
+	if entry.TaskRun != nil {
+		return fmt.Sprintf("taskRun/%d", entry.TaskRun.ID)
+	}
+	return fmt.Sprintf("taskCheckRun/%d", entry.TaskCheckRun.ID)
+}
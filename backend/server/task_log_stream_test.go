@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestStreamTaskLog(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/pipeline/1/task/1/run/stream", nil), rec)
+
+	taskRun := func(id int, status api.TaskRunStatus) *api.TaskRunLogEntry {
+		return &api.TaskRunLogEntry{TaskRun: &api.TaskRunLogTaskRun{ID: id, TaskID: 1, Status: status}}
+	}
+	polls := [][]*api.TaskRunLogEntry{
+		{taskRun(1, api.TaskRunRunning)},
+		// Unchanged.
+		{taskRun(1, api.TaskRunRunning)},
+		{taskRun(1, api.TaskRunFailed), taskRun(2, api.TaskRunRunning)},
+		{taskRun(1, api.TaskRunFailed), taskRun(2, api.TaskRunDone)},
+	}
+	i := 0
+	err := streamTaskLog(c, time.Millisecond, func(context.Context) ([]*api.TaskRunLogEntry, bool, error) {
+		entries := polls[i]
+		i++
+		return entries, i == len(polls), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, len(polls), i)
+	require.Equal(t, taskLogStreamContentType, rec.Header().Get(echo.HeaderContentType))
+	require.Equal(t, []string{
+		`{"taskRun":{"id":1,"taskId":1,"name":"","status":"RUNNING","code":0,"comment":"","result":"","updatedTs":0}}`,
+		`{"taskRun":{"id":1,"taskId":1,"name":"","status":"FAILED","code":0,"comment":"","result":"","updatedTs":0}}`,
+		`{"taskRun":{"id":2,"taskId":1,"name":"","status":"RUNNING","code":0,"comment":"","result":"","updatedTs":0}}`,
+		`{"taskRun":{"id":2,"taskId":1,"name":"","status":"DONE","code":0,"comment":"","result":"","updatedTs":0}}`,
+	}, strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n"))
+}
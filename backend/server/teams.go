@@ -0,0 +1,201 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/app/teams"
+	"github.com/bytebase/bytebase/backend/store"
+	"github.com/bytebase/bytebase/backend/utils"
+)
+
+// teamsCommandUsage is the usage of the Teams bot and outgoing webhook.
+const teamsCommandUsage = "Usage: `status <issue ID>` to look up the status of the issue, `approve <issue ID>` to approve the pending approval step."
+
+var (
+	teamsMentionRegexp = regexp.MustCompile(`<at>[^<]*</at>`)
+	teamsTagRegexp     = regexp.MustCompile(`<[^>]*>`)
+)
+
+// registerTeamsRoutes registers the messaging endpoint of the Teams bot and the callback URL of the Teams outgoing
+// webhook. The issue status replies are adaptive cards, and the ones of the bot have the approve button.
+// The Teams users are mapped to the Bytebase users by email.
+func (s *Server) registerTeamsRoutes(g *echo.Group) {
+	g.POST("/teams/bot", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		setting, activity, err := s.readTeamsRequest(c)
+		if err != nil {
+			return err
+		}
+		if err := s.teamsProvider.VerifyBotRequest(ctx, setting.AppID, c.Request().Header, activity); err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid Teams bot request").SetInternal(err)
+		}
+		if activity.Type != "message" || activity.Conversation == nil {
+			return c.NoContent(http.StatusOK)
+		}
+		// The reply is sent through the Bot Framework, so the request doesn't wait for the approval.
+		go func() {
+			ctx := context.Background()
+			reply, err := s.handleTeamsMessage(ctx, setting, activity, true /* withActions */)
+			if err != nil {
+				log.Error("Failed to handle Teams bot message", zap.String("text", activity.Text), zap.Error(err))
+				reply = &teams.Activity{Type: "message", Text: "Failed to handle the message, please try again later."}
+			}
+			if err := s.teamsProvider.ReplyToActivity(ctx, getTeamsCredential(setting), activity, reply); err != nil {
+				log.Error("Failed to reply to Teams bot message", zap.Error(err))
+			}
+		}()
+		return c.NoContent(http.StatusOK)
+	})
+
+	// The outgoing webhooks are replied in the response within 5 seconds, and the buttons in the replies don't work.
+	g.POST("/teams/outgoing", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read Teams request").SetInternal(err)
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+		setting, activity, err := s.readTeamsRequest(c)
+		if err != nil {
+			return err
+		}
+		if setting.OutgoingWebhookToken == "" {
+			return echo.NewHTTPError(http.StatusNotFound, "Teams outgoing webhook is not configured")
+		}
+		if err := teams.VerifyOutgoingWebhook(setting.OutgoingWebhookToken, c.Request().Header, body); err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Invalid Teams outgoing webhook signature").SetInternal(err)
+		}
+		reply, err := s.handleTeamsMessage(ctx, setting, activity, false /* withActions */)
+		if err != nil {
+			log.Error("Failed to handle Teams outgoing webhook message", zap.String("text", activity.Text), zap.Error(err))
+			reply = &teams.Activity{Type: "message", Text: "Failed to handle the message, please try again later."}
+		}
+		return c.JSON(http.StatusOK, reply)
+	})
+}
+
+// readTeamsRequest returns the Teams app setting and the activity of the request, the request needs to be verified by
+// the caller.
+func (s *Server) readTeamsRequest(c echo.Context) (*api.SettingAppTeamsValue, *teams.Activity, error) {
+	ctx := c.Request().Context()
+	if s.teamsProvider == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Teams app is not available in readonly mode")
+	}
+	setting, err := utils.GetTeamsSetting(ctx, s.store)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to get Teams setting").SetInternal(err)
+	}
+	if setting == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, "Teams app is not configured")
+	}
+	activity := &teams.Activity{}
+	if err := json.NewDecoder(c.Request().Body).Decode(activity); err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Malformed Teams activity").SetInternal(err)
+	}
+	return setting, activity, nil
+}
+
+// handleTeamsMessage handles the text command or the clicked button of the message activity. It returns the reply to
+// the Teams user.
+func (s *Server) handleTeamsMessage(ctx context.Context, setting *api.SettingAppTeamsValue, activity *teams.Activity, withActions bool) (*teams.Activity, error) {
+	reply := func(text string) (*teams.Activity, error) {
+		return &teams.Activity{Type: "message", Text: text}, nil
+	}
+
+	var issueID int
+	var approve bool
+	if len(activity.Value) > 0 {
+		value := &teams.ActionValue{}
+		if err := json.Unmarshal(activity.Value, value); err != nil || value.Action != teams.ActionApprove {
+			return reply(teamsCommandUsage)
+		}
+		issueID, approve = value.IssueID, true
+	} else {
+		text := getTeamsMessageText(activity.Text)
+		if id, ok := parseIMIssueID(text, "approve", "status"); ok {
+			issueID, approve = id, true
+		} else if id, ok := parseIMIssueID(text, "status", "status"); ok {
+			issueID = id
+		} else {
+			return reply(teamsCommandUsage)
+		}
+	}
+
+	user, err := s.getTeamsUser(ctx, setting, activity.From)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the user of the Teams user")
+	}
+	if user == nil {
+		return reply("Your Teams email doesn't match any Bytebase user.")
+	}
+	if approve {
+		text, err := s.handleIMApprovalAction(ctx, user, issueID)
+		if err != nil {
+			return nil, err
+		}
+		return reply(text)
+	}
+
+	issueStatus, err := s.getIMIssueStatus(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if issueStatus == nil {
+		return reply(fmt.Sprintf("Issue %d not found.", issueID))
+	}
+	facts := []*teams.Fact{
+		{Title: "Project", Value: issueStatus.issue.Project.Title},
+		{Title: "Status", Value: string(issueStatus.issue.Status)},
+		{Title: "Approval", Value: issueStatus.approval},
+	}
+	if issueStatus.tasks != "" {
+		facts = append(facts, &teams.Fact{Title: "Tasks", Value: issueStatus.tasks})
+	}
+	return &teams.Activity{
+		Type:        "message",
+		Attachments: []*teams.Attachment{teams.NewIssueCard(issueStatus.issue.Title, issueStatus.link, issueStatus.issue.UID, facts, withActions && issueStatus.approvable)},
+	}, nil
+}
+
+// getTeamsUser returns the Bytebase user with the email of the Teams user, it's nil if not found.
+func (s *Server) getTeamsUser(ctx context.Context, setting *api.SettingAppTeamsValue, from *teams.ChannelAccount) (*store.UserMessage, error) {
+	if from == nil || from.AADObjectID == "" {
+		return nil, nil
+	}
+	email, err := s.teamsProvider.GetUserEmail(ctx, getTeamsCredential(setting), from.AADObjectID)
+	if err != nil {
+		return nil, err
+	}
+	if email == "" {
+		return nil, nil
+	}
+	return s.store.GetUser(ctx, &store.FindUserMessage{Email: &email})
+}
+
+func getTeamsCredential(setting *api.SettingAppTeamsValue) *teams.Credential {
+	return &teams.Credential{
+		TenantID:    setting.TenantID,
+		AppID:       setting.AppID,
+		AppPassword: setting.AppPassword,
+	}
+}
+
+// getTeamsMessageText returns the text of the message without the mentions of the bot and the HTML tags.
+func getTeamsMessageText(text string) string {
+	text = teamsMentionRegexp.ReplaceAllString(text, "")
+	text = teamsTagRegexp.ReplaceAllString(text, " ")
+	return strings.TrimSpace(strings.ReplaceAll(text, "&nbsp;", " "))
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTeamsMessageText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "<at>Bytebase</at> status 123", want: "status 123"},
+		{text: "<p><at>Bytebase</at>&nbsp;approve #123</p>\n", want: "approve #123"},
+		{text: "reject 123", want: "reject 123"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, getTeamsMessageText(test.text), test.text)
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func (s *Server) registerUserGroupRoutes(g *echo.Group) {
+	g.GET("/user-group", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		groups, err := s.store.ListUserGroups(ctx, &store.FindUserGroupMessage{})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list user groups").SetInternal(err)
+		}
+		groupList := []*api.UserGroup{}
+		for _, group := range groups {
+			userGroup := &api.UserGroup{
+				ID:         group.ID,
+				Title:      group.Title,
+				ExternalID: group.ExternalID,
+				MemberList: []string{},
+			}
+			for _, memberID := range group.MemberIDs {
+				user, err := s.store.GetUserByID(ctx, memberID)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get user group member").SetInternal(err)
+				}
+				if user != nil {
+					userGroup.MemberList = append(userGroup.MemberList, user.Email)
+				}
+			}
+			groupList = append(groupList, userGroup)
+		}
+		return c.JSON(http.StatusOK, groupList)
+	})
+}
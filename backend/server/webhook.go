@@ -14,6 +14,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -32,6 +33,8 @@ import (
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/plugin/vcs"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/bitbucket"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/bitbucketserver"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/gitea"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/github"
 	"github.com/bytebase/bytebase/backend/plugin/vcs/gitlab"
 	"github.com/bytebase/bytebase/backend/store"
@@ -50,22 +53,63 @@ const (
 )
 
 func (s *Server) registerWebhookRoutes(g *echo.Group) {
-	g.POST("/gitlab/:id", func(c echo.Context) error {
+	g.POST("/gitlab/:id", s.recordWebhookEvent(func(c echo.Context) error {
 		ctx := c.Request().Context()
 
 		body, err := io.ReadAll(c.Request().Body)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
 		}
+		validateToken := func(repo *api.Repository) (bool, error) {
+			return validateGitLabWebhookToken(c.Request().Header.Get("X-Gitlab-Token"), repo.WebhookSecretToken), nil
+		}
+		if err := s.verifyWebhookSignature(ctx, c.Param("id"), validateToken); err != nil {
+			return err
+		}
 		var pushEvent gitlab.WebhookPushEvent
 		if err := json.Unmarshal(body, &pushEvent); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
 		}
-		// This shouldn't happen as we only setup webhook to receive push event, just in case.
+		repositoryID := fmt.Sprintf("%v", pushEvent.Project.ID)
+		if pushEvent.ObjectKind == gitlab.WebhookTagPush {
+			// The checkout SHA is empty if the tag is deleted, the releases are immutable and kept.
+			if pushEvent.CheckoutSHA == "" {
+				return c.String(http.StatusOK, "OK")
+			}
+			filter := func(repo *api.Repository) (bool, error) {
+				if ok, err := validateToken(repo); err != nil || !ok {
+					return false, err
+				}
+
+				return isWebhookEventReleaseTag(pushEvent.Ref, repo.ReleaseTagFilter)
+			}
+			repositoryList, err := s.filterRepository(ctx, c.Param("id"), repositoryID, filter)
+			if err != nil {
+				return err
+			}
+			if len(repositoryList) == 0 {
+				log.Debug("Empty handle repo list. Ignore this tag push event.")
+				return c.String(http.StatusOK, "OK")
+			}
+
+			baseVCSPushEvent, err := pushEvent.ToVCS()
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to convert GitLab commits").SetInternal(err)
+			}
+			createdMessages, err := s.processReleasePushEvent(ctx, repositoryList, releasePushEvent(baseVCSPushEvent, vcs.Commit{
+				ID:          pushEvent.CheckoutSHA,
+				AuthorName:  pushEvent.AuthorName,
+				AuthorEmail: pushEvent.AuthorEmail,
+			}))
+			if err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, strings.Join(createdMessages, "\n"))
+		}
+		// This shouldn't happen as we only setup webhook to receive push and tag push events, just in case.
 		if pushEvent.ObjectKind != gitlab.WebhookPush {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want push", pushEvent.ObjectKind))
 		}
-		repositoryID := fmt.Sprintf("%v", pushEvent.Project.ID)
 
 		nonBytebaseCommitList := filterGitLabBytebaseCommit(pushEvent.CommitList)
 		if len(nonBytebaseCommitList) == 0 {
@@ -83,8 +127,8 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 		pushEvent.CommitList = nonBytebaseCommitList
 
 		filter := func(repo *api.Repository) (bool, error) {
-			if c.Request().Header.Get("X-Gitlab-Token") != repo.WebhookSecretToken {
-				return false, nil
+			if ok, err := validateToken(repo); err != nil || !ok {
+				return false, err
 			}
 
 			return s.isWebhookEventBranch(pushEvent.Ref, repo.BranchFilter)
@@ -108,9 +152,9 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 			return err
 		}
 		return c.String(http.StatusOK, strings.Join(createdMessages, "\n"))
-	})
+	}))
 
-	g.POST("/github/:id", func(c echo.Context) error {
+	g.POST("/github/:id", s.recordWebhookEvent(func(c echo.Context) error {
 		ctx := c.Request().Context()
 
 		// This shouldn't happen as we only setup webhook to receive push event, just in case.
@@ -129,11 +173,54 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
 		}
+		validateSignature := func(repo *api.Repository) (bool, error) {
+			ok, err := validateGitHubWebhookSignature256(c.Request().Header.Get("X-Hub-Signature-256"), repo.WebhookSecretToken, body)
+			if err != nil {
+				return false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate GitHub webhook signature").SetInternal(err)
+			}
+			return ok, nil
+		}
+		if err := s.verifyWebhookSignature(ctx, c.Param("id"), validateSignature); err != nil {
+			return err
+		}
 		var pushEvent github.WebhookPushEvent
 		if err := json.Unmarshal(body, &pushEvent); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
 		}
 		repositoryID := pushEvent.Repository.FullName
+		if strings.HasPrefix(pushEvent.Ref, releaseTagRefPrefix) {
+			// The releases are immutable and kept after the tag is deleted.
+			if pushEvent.Deleted || pushEvent.HeadCommit == nil {
+				return c.String(http.StatusOK, "OK")
+			}
+			filter := func(repo *api.Repository) (bool, error) {
+				if ok, err := validateSignature(repo); err != nil || !ok {
+					return false, err
+				}
+
+				return isWebhookEventReleaseTag(pushEvent.Ref, repo.ReleaseTagFilter)
+			}
+			repositoryList, err := s.filterRepository(ctx, c.Param("id"), repositoryID, filter)
+			if err != nil {
+				return err
+			}
+			if len(repositoryList) == 0 {
+				log.Debug("Empty handle repo list. Ignore this tag push event.")
+				return c.String(http.StatusOK, "OK")
+			}
+
+			// The after commit is the tag object for the annotated tags, use the head commit instead.
+			createdMessages, err := s.processReleasePushEvent(ctx, repositoryList, releasePushEvent(pushEvent.ToVCS(), vcs.Commit{
+				ID:          pushEvent.HeadCommit.ID,
+				URL:         pushEvent.HeadCommit.URL,
+				AuthorName:  pushEvent.HeadCommit.Author.Name,
+				AuthorEmail: pushEvent.HeadCommit.Author.Email,
+			}))
+			if err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, strings.Join(createdMessages, "\n"))
+		}
 
 		nonBytebaseCommitList := filterGitHubBytebaseCommit(pushEvent.Commits)
 		if len(nonBytebaseCommitList) == 0 {
@@ -151,12 +238,8 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 		pushEvent.Commits = nonBytebaseCommitList
 
 		filter := func(repo *api.Repository) (bool, error) {
-			ok, err := validateGitHubWebhookSignature256(c.Request().Header.Get("X-Hub-Signature-256"), repo.WebhookSecretToken, body)
-			if err != nil {
-				return false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate GitHub webhook signature").SetInternal(err)
-			}
-			if !ok {
-				return false, nil
+			if ok, err := validateSignature(repo); err != nil || !ok {
+				return false, err
 			}
 
 			return s.isWebhookEventBranch(pushEvent.Ref, repo.BranchFilter)
@@ -177,9 +260,9 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 			return err
 		}
 		return c.String(http.StatusOK, strings.Join(createdMessages, "\n"))
-	})
+	}))
 
-	g.POST("/bitbucket/:id", func(c echo.Context) error {
+	g.POST("/bitbucket/:id", s.recordWebhookEvent(func(c echo.Context) error {
 		ctx := c.Request().Context()
 
 		// This shouldn't happen as we only set up webhook to receive push event, just in case.
@@ -313,7 +396,126 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 			allCreatedMessages = append(allCreatedMessages, createdMessages...)
 		}
 		return c.String(http.StatusOK, strings.Join(allCreatedMessages, "\n"))
-	})
+	}))
+
+	// Forgejo sends the same headers as Gitea, alongside its own X-Forgejo-* ones.
+	g.POST("/gitea/:id", s.recordWebhookEvent(func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		// This shouldn't happen as we only set up webhook to receive push event, just in case.
+		eventType := gitea.WebhookType(c.Request().Header.Get("X-Gitea-Event"))
+		if eventType != gitea.WebhookPush {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want %s", eventType, gitea.WebhookPush))
+		}
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
+		}
+		validateSignature := func(repo *api.Repository) (bool, error) {
+			// The Gitea signature is the same HMAC-SHA256 hex digest as GitHub, without the "sha256=" prefix.
+			ok, err := validateGitHubWebhookSignature256(c.Request().Header.Get("X-Gitea-Signature"), repo.WebhookSecretToken, body)
+			if err != nil {
+				return false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate Gitea webhook signature").SetInternal(err)
+			}
+			return ok, nil
+		}
+		if err := s.verifyWebhookSignature(ctx, c.Param("id"), validateSignature); err != nil {
+			return err
+		}
+		var pushEvent gitea.WebhookPushEvent
+		if err := json.Unmarshal(body, &pushEvent); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
+		}
+		repositoryID := pushEvent.Repository.FullName
+
+		nonBytebaseCommitList := filterGiteaBytebaseCommit(pushEvent.Commits)
+		if len(nonBytebaseCommitList) == 0 {
+			var commitList []string
+			for _, commit := range pushEvent.Commits {
+				commitList = append(commitList, commit.ID)
+			}
+			log.Debug("all commits are created by Bytebase",
+				zap.String("repoURL", pushEvent.Repository.HTMLURL),
+				zap.String("repoName", pushEvent.Repository.FullName),
+				zap.String("commits", strings.Join(commitList, ", ")),
+			)
+			return c.String(http.StatusOK, "OK")
+		}
+		pushEvent.Commits = nonBytebaseCommitList
+
+		filter := func(repo *api.Repository) (bool, error) {
+			if ok, err := validateSignature(repo); err != nil || !ok {
+				return false, err
+			}
+
+			return s.isWebhookEventBranch(pushEvent.Ref, repo.BranchFilter)
+		}
+		repositoryList, err := s.filterRepository(ctx, c.Param("id"), repositoryID, filter)
+		if err != nil {
+			return err
+		}
+		if len(repositoryList) == 0 {
+			log.Debug("Empty handle repo list. Ignore this push event.")
+			return c.String(http.StatusOK, "OK")
+		}
+
+		createdMessages, err := s.processPushEvent(ctx, repositoryList, pushEvent.ToVCS())
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, strings.Join(createdMessages, "\n"))
+	}))
+
+	// Bitbucket Server doesn't run the SQL review in the CI, the pull request events are reviewed here and the results
+	// are commented on the pull requests.
+	g.POST("/bitbucket-server/:id", s.recordWebhookEvent(func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		eventKey := c.Request().Header.Get("X-Event-Key")
+		if eventKey == bitbucketserver.WebhookPing {
+			return c.String(http.StatusOK, "OK")
+		}
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
+		}
+		validateSignature := func(repo *api.Repository) (bool, error) {
+			ok, err := validateGitHubWebhookSignature256(c.Request().Header.Get("X-Hub-Signature"), repo.WebhookSecretToken, body)
+			if err != nil {
+				return false, echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate Bitbucket Server webhook signature").SetInternal(err)
+			}
+			return ok, nil
+		}
+		if err := s.verifyWebhookSignature(ctx, c.Param("id"), validateSignature); err != nil {
+			return err
+		}
+
+		switch eventKey {
+		case bitbucketserver.WebhookRefsChanged:
+			var pushEvent bitbucketserver.WebhookPushEvent
+			if err := json.Unmarshal(body, &pushEvent); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed push event").SetInternal(err)
+			}
+			createdMessages, err := s.processBitbucketServerPushEvent(ctx, c.Param("id"), &pushEvent, validateSignature)
+			if err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, strings.Join(createdMessages, "\n"))
+		case bitbucketserver.WebhookPullRequestOpened, bitbucketserver.WebhookPullRequestFromRefUpdated:
+			var pullRequestEvent bitbucketserver.WebhookPullRequestEvent
+			if err := json.Unmarshal(body, &pullRequestEvent); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed pull request event").SetInternal(err)
+			}
+			if err := s.reviewBitbucketServerPullRequest(ctx, c.Param("id"), &pullRequestEvent, validateSignature); err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, "OK")
+		default:
+			// This shouldn't happen as we only set up webhook to receive push and pull request events, just in case.
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event key %q", eventKey))
+		}
+	}))
 
 	// id is the webhookEndpointID in repository
 	// This endpoint is generated and injected into GitHub action & GitLab CI during the VCS setup.
@@ -399,53 +601,7 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list pull request file").SetInternal(err)
 		}
 
-		distinctFileList := []vcs.DistinctFileItem{}
-		for _, prFile := range prFiles {
-			if prFile.IsDeleted {
-				continue
-			}
-			distinctFileList = append(distinctFileList, vcs.DistinctFileItem{
-				FileName: prFile.Path,
-				Commit: vcs.Commit{
-					ID: prFile.LastCommitID,
-				},
-			})
-		}
-
-		sqlCheckAdvice := map[string][]advisor.Advice{}
-		var wg sync.WaitGroup
-
-		repoID2FileItemList := groupFileInfoByRepo(distinctFileList, repositoryList)
-		for _, fileInfoListInRepo := range repoID2FileItemList {
-			for _, file := range fileInfoListInRepo {
-				wg.Add(1)
-				go func(file fileInfo) {
-					defer wg.Done()
-					adviceList, err := s.sqlAdviceForFile(ctx, file, setting.ExternalUrl)
-					if err != nil {
-						log.Error(
-							"Failed to take SQL review for file",
-							zap.String("file", file.item.FileName),
-							zap.String("external_id", file.repository.ExternalID),
-							zap.Error(err),
-						)
-						sqlCheckAdvice[file.item.FileName] = []advisor.Advice{
-							{
-								Status:  advisor.Warn,
-								Code:    advisor.Internal,
-								Title:   "Failed to take SQL review",
-								Content: fmt.Sprintf("Failed to take SQL review for file %s with error %v", file.item.FileName, err),
-								Line:    1,
-							},
-						}
-					} else if adviceList != nil {
-						sqlCheckAdvice[file.item.FileName] = adviceList
-					}
-				}(file)
-			}
-		}
-
-		wg.Wait()
+		sqlCheckAdvice := s.sqlAdviceForPullRequestFiles(ctx, repositoryList, prFiles, setting.ExternalUrl)
 
 		response := &api.VCSSQLReviewResult{}
 		switch repo.VCS.Type {
@@ -467,6 +623,202 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 	})
 }
 
+func (s *Server) processBitbucketServerPushEvent(ctx context.Context, webhookEndpointID string, pushEvent *bitbucketserver.WebhookPushEvent, validateSignature func(*api.Repository) (bool, error)) ([]string, error) {
+	repositoryID := pushEvent.Repository.FullPath()
+	var allCreatedMessages []string
+	for _, change := range pushEvent.Changes {
+		if change.Ref.Type != "BRANCH" || change.Type == "DELETE" {
+			continue
+		}
+
+		filter := func(repo *api.Repository) (bool, error) {
+			ok, err := validateSignature(repo)
+			if err != nil || !ok {
+				return false, err
+			}
+			return s.isWebhookEventBranch(change.RefID, repo.BranchFilter)
+		}
+		repositoryList, err := s.filterRepository(ctx, webhookEndpointID, repositoryID, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(repositoryList) == 0 {
+			log.Debug("Empty handle repo list. Ignore this push event.")
+			continue
+		}
+
+		// The push event doesn't carry the commits, the head commit stands for the whole push.
+		repo := repositoryList[0]
+		oauthContext := common.OauthContext{
+			ClientID:     repo.VCS.ApplicationID,
+			ClientSecret: repo.VCS.Secret,
+			AccessToken:  repo.AccessToken,
+			RefreshToken: repo.RefreshToken,
+			Refresher:    utils.RefreshToken(ctx, s.store, repo.WebURL),
+		}
+		provider := vcs.Get(repo.VCS.Type, vcs.ProviderConfig{})
+		commit, err := provider.FetchCommitByID(ctx, oauthContext, repo.VCS.InstanceURL, repositoryID, change.ToHash)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch commit %q", change.ToHash)).SetInternal(err)
+		}
+		fileDiffList, err := provider.GetDiffFileList(ctx, oauthContext, repo.VCS.InstanceURL, repositoryID, change.FromHash, change.ToHash)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to get diff file list for commit %q", change.ToHash)).SetInternal(err)
+		}
+		for _, f := range fileDiffList {
+			switch f.Type {
+			case vcs.FileDiffTypeAdded:
+				commit.AddedList = append(commit.AddedList, f.Path)
+			case vcs.FileDiffTypeModified:
+				commit.ModifiedList = append(commit.ModifiedList, f.Path)
+			}
+		}
+		commit.URL = fmt.Sprintf("%s/commits/%s", repo.WebURL, commit.ID)
+
+		createdMessages, err := s.processPushEvent(
+			ctx,
+			repositoryList,
+			vcs.PushEvent{
+				VCSType:            vcs.BitbucketServer,
+				Ref:                change.RefID,
+				Before:             change.FromHash,
+				After:              change.ToHash,
+				RepositoryID:       repositoryID,
+				RepositoryURL:      repo.WebURL,
+				RepositoryFullPath: repositoryID,
+				AuthorName:         pushEvent.Actor.DisplayName,
+				CommitList:         []vcs.Commit{*commit},
+			},
+		)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to process push event for commit %q", change.ToHash)).SetInternal(err)
+		}
+		allCreatedMessages = append(allCreatedMessages, createdMessages...)
+	}
+	return allCreatedMessages, nil
+}
+
+func (s *Server) reviewBitbucketServerPullRequest(ctx context.Context, webhookEndpointID string, pullRequestEvent *bitbucketserver.WebhookPullRequestEvent, validateSignature func(*api.Repository) (bool, error)) error {
+	pullRequest := pullRequestEvent.PullRequest
+	repositoryID := pullRequest.ToRef.Repository.FullPath()
+	filter := func(repo *api.Repository) (bool, error) {
+		if !repo.EnableSQLReviewCI {
+			log.Debug("Skip repository as the SQL review CI is not enabled.",
+				zap.Int("repository_id", repo.ID),
+				zap.String("repository_external_id", repo.ExternalID),
+			)
+			return false, nil
+		}
+		ok, err := validateSignature(repo)
+		if err != nil || !ok {
+			return false, err
+		}
+		return s.isWebhookEventBranch(pullRequest.ToRef.ID, repo.BranchFilter)
+	}
+	repositoryList, err := s.filterRepository(ctx, webhookEndpointID, repositoryID, filter)
+	if err != nil {
+		return err
+	}
+	if len(repositoryList) == 0 {
+		log.Debug("Empty handle repo list. Ignore this pull request event.")
+		return nil
+	}
+
+	setting, err := s.store.GetWorkspaceGeneralSetting(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to find workspace setting").SetInternal(err)
+	}
+	repo := repositoryList[0]
+	oauthContext := common.OauthContext{
+		ClientID:     repo.VCS.ApplicationID,
+		ClientSecret: repo.VCS.Secret,
+		AccessToken:  repo.AccessToken,
+		RefreshToken: repo.RefreshToken,
+		Refresher:    utils.RefreshToken(ctx, s.store, repo.WebURL),
+	}
+	provider := vcs.Get(repo.VCS.Type, vcs.ProviderConfig{})
+	pullRequestID := strconv.Itoa(pullRequest.ID)
+	prFiles, err := provider.ListPullRequestFile(ctx, oauthContext, repo.VCS.InstanceURL, repositoryID, pullRequestID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list pull request file").SetInternal(err)
+	}
+
+	sqlCheckAdvice := s.sqlAdviceForPullRequestFiles(ctx, repositoryList, prFiles, setting.ExternalUrl)
+	response := convertSQLAdviceToBitbucketServerComment(sqlCheckAdvice)
+	log.Debug("SQL review finished",
+		zap.String("pull_request", pullRequestID),
+		zap.String("status", string(response.Status)),
+		zap.String("repository_id", repositoryID),
+		zap.String("vcs", string(repo.VCS.Type)),
+	)
+
+	commenter, ok := provider.(vcs.PullRequestCommenter)
+	if !ok {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("VCS %s doesn't support pull request comments", repo.VCS.Type))
+	}
+	if err := commenter.CreatePullRequestComment(ctx, oauthContext, repo.VCS.InstanceURL, repositoryID, pullRequestID, response.Content[0]); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to comment the SQL review result on the pull request").SetInternal(err)
+	}
+	return nil
+}
+
+// sqlAdviceForPullRequestFiles takes the SQL review for the files changed in the pull request, the advice list is keyed by the file path.
+func (s *Server) sqlAdviceForPullRequestFiles(ctx context.Context, repositoryList []*api.Repository, prFiles []*vcs.PullRequestFile, externalURL string) map[string][]advisor.Advice {
+	distinctFileList := []vcs.DistinctFileItem{}
+	for _, prFile := range prFiles {
+		if prFile.IsDeleted {
+			continue
+		}
+		distinctFileList = append(distinctFileList, vcs.DistinctFileItem{
+			FileName: prFile.Path,
+			Commit: vcs.Commit{
+				ID: prFile.LastCommitID,
+			},
+		})
+	}
+
+	sqlCheckAdvice := map[string][]advisor.Advice{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	projectID2FileInfoList := s.routeFileInfoByProject(ctx, groupFileInfoByRepo(distinctFileList, repositoryList))
+	for _, fileInfoListInProject := range projectID2FileInfoList {
+		for _, file := range fileInfoListInProject {
+			wg.Add(1)
+			go func(file fileInfo) {
+				defer wg.Done()
+				adviceList, err := s.sqlAdviceForFile(ctx, file, externalURL)
+				if err != nil {
+					log.Error(
+						"Failed to take SQL review for file",
+						zap.String("file", file.item.FileName),
+						zap.String("external_id", file.repository.ExternalID),
+						zap.Error(err),
+					)
+					mu.Lock()
+					defer mu.Unlock()
+					sqlCheckAdvice[file.item.FileName] = []advisor.Advice{
+						{
+							Status:  advisor.Warn,
+							Code:    advisor.Internal,
+							Title:   "Failed to take SQL review",
+							Content: fmt.Sprintf("Failed to take SQL review for file %s with error %v", file.item.FileName, err),
+							Line:    1,
+						},
+					}
+				} else if adviceList != nil {
+					mu.Lock()
+					defer mu.Unlock()
+					sqlCheckAdvice[file.item.FileName] = adviceList
+				}
+			}(file)
+		}
+	}
+
+	wg.Wait()
+	return sqlCheckAdvice
+}
+
 func (s *Server) sqlAdviceForFile(
 	ctx context.Context,
 	fileInfo fileInfo,
@@ -504,22 +856,27 @@ func (s *Server) sqlAdviceForFile(
 		return nil, errors.Errorf("Failed to list databse with error: %v", err)
 	}
 
-	fileContent, err := vcs.Get(fileInfo.repository.VCS.Type, vcs.ProviderConfig{}).ReadFileContent(
-		ctx,
-		common.OauthContext{
-			ClientID:     fileInfo.repository.VCS.ApplicationID,
-			ClientSecret: fileInfo.repository.VCS.Secret,
-			AccessToken:  fileInfo.repository.AccessToken,
-			RefreshToken: fileInfo.repository.RefreshToken,
-			Refresher:    utils.RefreshToken(ctx, s.store, fileInfo.repository.WebURL),
-		},
-		fileInfo.repository.VCS.InstanceURL,
-		fileInfo.repository.ExternalID,
-		fileInfo.item.FileName,
-		fileInfo.item.Commit.ID,
-	)
-	if err != nil {
-		return nil, errors.Errorf("Failed to read file cotent for %s with error: %v", fileInfo.item.FileName, err)
+	var fileContent string
+	if fileInfo.content != nil {
+		fileContent = *fileInfo.content
+	} else {
+		fileContent, err = vcs.Get(fileInfo.repository.VCS.Type, vcs.ProviderConfig{}).ReadFileContent(
+			ctx,
+			common.OauthContext{
+				ClientID:     fileInfo.repository.VCS.ApplicationID,
+				ClientSecret: fileInfo.repository.VCS.Secret,
+				AccessToken:  fileInfo.repository.AccessToken,
+				RefreshToken: fileInfo.repository.RefreshToken,
+				Refresher:    utils.RefreshToken(ctx, s.store, fileInfo.repository.WebURL),
+			},
+			fileInfo.repository.VCS.InstanceURL,
+			fileInfo.repository.ExternalID,
+			fileInfo.item.FileName,
+			fileInfo.item.Commit.ID,
+		)
+		if err != nil {
+			return nil, errors.Errorf("Failed to read file cotent for %s with error: %v", fileInfo.item.FileName, err)
+		}
 	}
 
 	// There may exist many databases that match the file name.
@@ -533,7 +890,14 @@ func (s *Server) sqlAdviceForFile(
 		if err != nil {
 			return nil, err
 		}
-		policy, err := s.store.GetSQLReviewPolicy(ctx, environment.UID)
+		project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &database.ProjectID})
+		if err != nil {
+			return nil, err
+		}
+		if project == nil {
+			return nil, errors.Errorf("project %q not found", database.ProjectID)
+		}
+		policy, err := s.store.GetSQLReviewPolicyForProject(ctx, environment.UID, project.UID)
 		if err != nil {
 			if e, ok := err.(*common.Error); ok && e.Code == common.NotFound {
 				log.Debug("Cannot found SQL review policy in environment", zap.String("Environment", database.EnvironmentID), zap.Error(err))
@@ -635,6 +999,29 @@ func (s *Server) filterRepository(ctx context.Context, webhookEndpointID string,
 	return filteredRepos, nil
 }
 
+// verifyWebhookSignature verifies the signature of the webhook request by the secret tokens of the repositories of the
+// webhook endpoint, and rejects the request if none of them matches. Otherwise, the forged requests are silently
+// ignored the same as the requests for the mismatched branches.
+func (s *Server) verifyWebhookSignature(ctx context.Context, webhookEndpointID string, validate repositoryFilter) error {
+	repos, err := s.store.FindRepository(ctx, &api.RepositoryFind{WebhookEndpointID: &webhookEndpointID})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to respond webhook event for endpoint: %v", webhookEndpointID)).SetInternal(err)
+	}
+	if len(repos) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Repository for webhook endpoint %s not found", webhookEndpointID))
+	}
+	for _, repo := range repos {
+		ok, err := validate(repo)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Invalid webhook signature for endpoint %s", webhookEndpointID))
+}
+
 func (*Server) isWebhookEventBranch(pushEventRef, branchFilter string) (bool, error) {
 	branch, err := parseBranchNameFromRefs(pushEventRef)
 	if err != nil {
@@ -683,10 +1070,60 @@ func parseBranchNameFromRefs(ref string) (string, error) {
 	return ref[len(expectedPrefix):], nil
 }
 
+// validateGitLabWebhookToken validates the secret token of the GitLab webhook request in constant time.
+func validateGitLabWebhookToken(token, secretToken string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(secretToken)) == 1
+}
+
+// matchBranchRule returns the first branch rule matching the pushed branch. It returns false if the branch matches no
+// rule, where the push should be ignored, and returns true with nil rule if there are no branch rules.
+func matchBranchRule(branchRules, ref string) (*api.RepositoryBranchRule, bool, error) {
+	ruleList, err := api.ParseRepositoryBranchRules(branchRules)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(ruleList) == 0 {
+		return nil, true, nil
+	}
+	branch, err := parseBranchNameFromRefs(ref)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, rule := range ruleList {
+		ok, err := filepath.Match(rule.Branch, branch)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "failed to match branch rule %q", rule.Branch)
+		}
+		if ok {
+			return rule, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
 func (s *Server) processPushEvent(ctx context.Context, repositoryList []*api.Repository, baseVCSPushEvent vcs.PushEvent) ([]string, error) {
 	if len(repositoryList) == 0 {
 		return nil, errors.Errorf("empty repository list")
 	}
+	// The repositories in the release mode roll out the releases built from the tags instead of the branch pushes.
+	var branchRepositoryList []*api.Repository
+	for _, repo := range repositoryList {
+		if repo.ReleaseTagFilter != "" {
+			log.Debug("Ignored the branch push event for the repository in the release mode", zap.String("repoURL", repo.WebURL))
+			continue
+		}
+		if _, ok, err := matchBranchRule(repo.BranchRules, baseVCSPushEvent.Ref); err != nil {
+			return nil, err
+		} else if !ok {
+			log.Debug("Ignored the branch push event matching no branch rule", zap.String("repoURL", repo.WebURL), zap.String("ref", baseVCSPushEvent.Ref))
+			continue
+		}
+		branchRepositoryList = append(branchRepositoryList, repo)
+	}
+	if len(branchRepositoryList) == 0 {
+		return nil, nil
+	}
+	repositoryList = branchRepositoryList
 
 	distinctFileList := baseVCSPushEvent.GetDistinctFileList()
 	if len(distinctFileList) == 0 {
@@ -713,12 +1150,12 @@ func (s *Server) processPushEvent(ctx context.Context, repositoryList []*api.Rep
 	}
 
 	var createdMessageList []string
-	repoID2FileItemList := groupFileInfoByRepo(filteredDistinctFileList, repositoryList)
-	for _, fileInfoListInRepo := range repoID2FileItemList {
+	projectID2FileInfoList := s.routeFileInfoByProject(ctx, groupFileInfoByRepo(filteredDistinctFileList, repositoryList))
+	for _, fileInfoListInProject := range projectID2FileInfoList {
 		// There are possibly multiple files in the push event.
 		// Each file corresponds to a (database name, schema version) pair.
 		// We want the migration statements are sorted by the file's schema version, and grouped by the database name.
-		dbID2FileInfoList := groupFileInfoByDatabase(fileInfoListInRepo)
+		dbID2FileInfoList := groupFileInfoByDatabase(fileInfoListInProject)
 		for _, fileInfoListInDB := range dbID2FileInfoList {
 			fileInfoListSorted := sortFilesBySchemaVersion(fileInfoListInDB)
 			repository := fileInfoListSorted[0].repository
@@ -796,6 +1233,8 @@ type fileInfo struct {
 	migrationInfo *db.MigrationInfo
 	fType         fileType
 	repository    *api.Repository
+	// content is the file content read when building the release, nil if not read yet.
+	content *string
 }
 
 func groupFileInfoByDatabase(fileInfoList []fileInfo) map[string][]fileInfo {
@@ -833,6 +1272,70 @@ func groupFileInfoByRepo(distinctFileList []vcs.DistinctFileItem, repositoryList
 	return repoID2FileItemList
 }
 
+// routeFileInfoByProject routes the files by the routing rules of their repositories, and groups them by the projects
+// routed to.
+func (s *Server) routeFileInfoByProject(ctx context.Context, repoID2FileItemList map[int][]fileInfo) map[int][]fileInfo {
+	projectID2FileInfoList := make(map[int][]fileInfo)
+	for _, fileInfoListInRepo := range repoID2FileItemList {
+		for _, fileInfo := range fileInfoListInRepo {
+			routed, err := s.routeFileInfo(ctx, fileInfo)
+			if err != nil {
+				log.Warn("Failed to route the ignored repository file",
+					zap.String("file", fileInfo.item.FileName),
+					zap.Error(err),
+				)
+				continue
+			}
+			projectID2FileInfoList[routed.repository.ProjectID] = append(projectID2FileInfoList[routed.repository.ProjectID], routed)
+		}
+	}
+	return projectID2FileInfoList
+}
+
+// routeFileInfo routes the file to the project of the first routing rule matching the file path relative to the base
+// directory. The file stays in the project of its repository if no rule matches.
+func (s *Server) routeFileInfo(ctx context.Context, fileInfo fileInfo) (fileInfo, error) {
+	ruleList, err := api.ParseRepositoryRoutingRules(fileInfo.repository.RoutingRules)
+	if err != nil {
+		return fileInfo, err
+	}
+	relativePath := strings.TrimPrefix(strings.TrimPrefix(fileInfo.item.FileName, fileInfo.repository.BaseDirectory), "/")
+	for _, rule := range ruleList {
+		ok, err := vcs.MatchPathPattern(rule.PathPattern, relativePath)
+		if err != nil {
+			return fileInfo, err
+		}
+		if !ok {
+			continue
+		}
+
+		project, err := s.store.GetProjectV2(ctx, &store.FindProjectMessage{ResourceID: &rule.ProjectID})
+		if err != nil {
+			return fileInfo, errors.Wrapf(err, "failed to get project %q", rule.ProjectID)
+		}
+		if project == nil || project.Deleted {
+			return fileInfo, errors.Errorf("project %q of the routing rule %q not found", rule.ProjectID, rule.PathPattern)
+		}
+		apiProject, err := s.store.GetProjectByID(ctx, project.UID)
+		if err != nil {
+			return fileInfo, errors.Wrapf(err, "failed to get project %q", rule.ProjectID)
+		}
+		// The routed repository is only used to process the file, it's never stored.
+		repository := *fileInfo.repository
+		repository.ProjectID = project.UID
+		repository.Project = apiProject
+		fileInfo.repository = &repository
+		if rule.DatabaseTemplate != "" {
+			migrationInfo := *fileInfo.migrationInfo
+			migrationInfo.Database = strings.ReplaceAll(rule.DatabaseTemplate, "{{DB_NAME}}", migrationInfo.Database)
+			migrationInfo.Namespace = migrationInfo.Database
+			fileInfo.migrationInfo = &migrationInfo
+		}
+		return fileInfo, nil
+	}
+	return fileInfo, nil
+}
+
 type fileType int
 
 const (
@@ -937,8 +1440,26 @@ func (s *Server) processFilesInProject(ctx context.Context, pushEvent vcs.PushEv
 	var createdIssueList []string
 	var fileNameList []string
 
+	// Each branch rule rolls out the push to its environment, so that the pushes to the branches build the separate
+	// pipelines.
+	branchRule, _, err := matchBranchRule(repo.BranchRules, pushEvent.Ref)
+	if err != nil {
+		return "", false, nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to match branch rules").SetInternal(err)
+	}
+	manualRollout := branchRule != nil && !branchRule.AutoRollout
+
 	creatorID := s.getIssueCreatorID(ctx, pushEvent.CommitList[0].AuthorEmail)
 	for _, fileInfo := range fileInfoList {
+		if branchRule != nil {
+			if fileInfo.migrationInfo.Environment != "" && fileInfo.migrationInfo.Environment != branchRule.EnvironmentID {
+				err := errors.Errorf("the environment %q in the file path conflicts with the environment %q of the branch rule %q", fileInfo.migrationInfo.Environment, branchRule.EnvironmentID, branchRule.Branch)
+				activityCreateList = append(activityCreateList, getIgnoredFileActivityCreate(repo.ProjectID, pushEvent, fileInfo.item.FileName, err))
+				continue
+			}
+			migrationInfo := *fileInfo.migrationInfo
+			migrationInfo.Environment = branchRule.EnvironmentID
+			fileInfo.migrationInfo = &migrationInfo
+		}
 		if fileInfo.fType == fileTypeSchema {
 			if repo.Project.SchemaChangeType == api.ProjectSchemaChangeTypeSDL {
 				// Create one issue per schema file for SDL project.
@@ -948,7 +1469,7 @@ func (s *Server) processFilesInProject(ctx context.Context, pushEvent vcs.PushEv
 					databaseName := fileInfo.migrationInfo.Database
 					issueName := fmt.Sprintf(issueNameTemplate, databaseName, "Alter schema")
 					issueDescription := fmt.Sprintf("Apply schema diff by file %s", strings.TrimPrefix(fileInfo.item.FileName, repo.BaseDirectory+"/"))
-					if err := s.createIssueFromMigrationDetailList(ctx, issueName, issueDescription, pushEvent, creatorID, repo.ProjectID, migrationDetailListForFile); err != nil {
+					if err := s.createIssueFromMigrationDetailList(ctx, issueName, issueDescription, pushEvent, creatorID, repo.ProjectID, migrationDetailListForFile, manualRollout); err != nil {
 						return "", false, activityCreateList, echo.NewHTTPError(http.StatusInternalServerError, "Failed to create issue").SetInternal(err)
 					}
 					createdIssueList = append(createdIssueList, issueName)
@@ -988,7 +1509,7 @@ func (s *Server) processFilesInProject(ctx context.Context, pushEvent vcs.PushEv
 	databaseName := fileInfoList[0].migrationInfo.Database
 	issueName := fmt.Sprintf(issueNameTemplate, databaseName, migrateType)
 	issueDescription := fmt.Sprintf("By VCS files:\n\n%s\n", strings.Join(fileNameList, "\n"))
-	if err := s.createIssueFromMigrationDetailList(ctx, issueName, issueDescription, pushEvent, creatorID, repo.ProjectID, migrationDetailList); err != nil {
+	if err := s.createIssueFromMigrationDetailList(ctx, issueName, issueDescription, pushEvent, creatorID, repo.ProjectID, migrationDetailList, manualRollout); err != nil {
 		return "", len(createdIssueList) != 0, activityCreateList, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create issue %s", issueName)).SetInternal(err)
 	}
 	createdIssueList = append(createdIssueList, issueName)
@@ -1007,11 +1528,12 @@ func sortFilesBySchemaVersion(fileInfoList []fileInfo) []fileInfo {
 	return ret
 }
 
-func (s *Server) createIssueFromMigrationDetailList(ctx context.Context, issueName, issueDescription string, pushEvent vcs.PushEvent, creatorID, projectID int, migrationDetailList []*api.MigrationDetail) error {
+func (s *Server) createIssueFromMigrationDetailList(ctx context.Context, issueName, issueDescription string, pushEvent vcs.PushEvent, creatorID, projectID int, migrationDetailList []*api.MigrationDetail, manualRollout bool) error {
 	createContext, err := json.Marshal(
 		&api.MigrationContext{
-			VCSPushEvent: &pushEvent,
-			DetailList:   migrationDetailList,
+			VCSPushEvent:  &pushEvent,
+			DetailList:    migrationDetailList,
+			ManualRollout: manualRollout,
 		},
 	)
 	if err != nil {
@@ -1209,6 +1731,14 @@ func (s *Server) readFileContent(ctx context.Context, pushEvent vcs.PushEvent, r
 	return content, nil
 }
 
+// readFileInfoContent returns the content already read for the file, or reads it from the given repository.
+func (s *Server) readFileInfoContent(ctx context.Context, pushEvent vcs.PushEvent, repo *api.Repository, fileInfo fileInfo) (string, error) {
+	if fileInfo.content != nil {
+		return *fileInfo.content, nil
+	}
+	return s.readFileContent(ctx, pushEvent, repo, fileInfo.item.FileName)
+}
+
 // prepareIssueFromSDLFile returns the migration info and a list of update
 // schema details derived from the given push event for SDL.
 func (s *Server) prepareIssueFromSDLFile(ctx context.Context, repo *api.Repository, pushEvent vcs.PushEvent, schemaInfo *db.MigrationInfo, file string) ([]*api.MigrationDetail, []*api.ActivityCreate) {
@@ -1271,7 +1801,7 @@ func (s *Server) prepareIssueFromSDLFile(ctx context.Context, repo *api.Reposito
 // prepareIssueFromFile returns a list of update schema details derived
 // from the given push event for DDL.
 func (s *Server) prepareIssueFromFile(ctx context.Context, repo *api.Repository, pushEvent vcs.PushEvent, fileInfo fileInfo) ([]*api.MigrationDetail, []*api.ActivityCreate) {
-	content, err := s.readFileContent(ctx, pushEvent, repo, fileInfo.item.FileName)
+	content, err := s.readFileInfoContent(ctx, pushEvent, repo, fileInfo)
 	if err != nil {
 		return nil, []*api.ActivityCreate{
 			getIgnoredFileActivityCreate(
@@ -1503,8 +2033,10 @@ func (s *Server) tryUpdateTasksFromModifiedFile(ctx context.Context, databases [
 // convertSQLAdviceToGitLabCIResult will convert SQL advice map to GitLab test output format.
 // GitLab test report: https://docs.gitlab.com/ee/ci/testing/unit_test_reports.html
 // junit XML format: https://llg.cubic.org/docs/junit/
+// The advice is also converted to the code quality report to annotate the merge request diff inline.
 func convertSQLAdviceToGitLabCIResult(adviceMap map[string][]advisor.Advice) *api.VCSSQLReviewResult {
 	testsuiteList := []string{}
+	codeQualityIssueList := []*gitlab.CodeQualityIssue{}
 	status := advisor.Success
 
 	fileList := []string{}
@@ -1551,6 +2083,21 @@ func convertSQLAdviceToGitLabCIResult(adviceMap map[string][]advisor.Advice) *ap
 			)
 
 			testcaseList = append(testcaseList, testcase)
+
+			severity := gitlab.CodeQualitySeverityMinor
+			if advice.Status == advisor.Error {
+				severity = gitlab.CodeQualitySeverityCritical
+			}
+			codeQualityIssueList = append(codeQualityIssueList, &gitlab.CodeQualityIssue{
+				Description: fmt.Sprintf("%s: %s", advice.Title, advice.Content),
+				CheckName:   fmt.Sprintf("%d", advice.Code),
+				Fingerprint: fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%d:%s", filePath, line, advice.Code, advice.Content)))),
+				Severity:    severity,
+				Location: gitlab.CodeQualityLocation{
+					Path:  filePath,
+					Lines: gitlab.CodeQualityLines{Begin: line},
+				},
+			})
 		}
 
 		if len(testcaseList) > 0 {
@@ -1569,13 +2116,16 @@ func convertSQLAdviceToGitLabCIResult(adviceMap map[string][]advisor.Advice) *ap
 				strings.Join(testsuiteList, "\n"),
 			),
 		},
+		CodeQualityIssues: codeQualityIssueList,
 	}
 }
 
 // convertSQLAdviceToGitHubActionResult will convert SQL advice map to GitHub action output format.
 // GitHub action output message: https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+// The advice is also converted to the check run annotations, so that the action can publish a check run to block the merge.
 func convertSQLAdviceToGitHubActionResult(adviceMap map[string][]advisor.Advice) *api.VCSSQLReviewResult {
 	messageList := []string{}
+	annotationList := []*github.CheckRunAnnotation{}
 	status := advisor.Success
 
 	fileList := []string{}
@@ -1597,8 +2147,10 @@ func convertSQLAdviceToGitHubActionResult(adviceMap map[string][]advisor.Advice)
 			}
 
 			prefix := ""
+			annotationLevel := github.CheckRunAnnotationLevelWarning
 			if advice.Status == advisor.Error {
 				prefix = "error"
+				annotationLevel = github.CheckRunAnnotationLevelFailure
 				status = advice.Status
 			} else {
 				prefix = "warning"
@@ -1620,11 +2172,77 @@ func convertSQLAdviceToGitHubActionResult(adviceMap map[string][]advisor.Advice)
 			)
 			// To indent the output message in action
 			messageList = append(messageList, strings.ReplaceAll(msg, "\n", "%0A"))
+			annotationList = append(annotationList, &github.CheckRunAnnotation{
+				Path:            filePath,
+				StartLine:       line,
+				EndLine:         line,
+				AnnotationLevel: annotationLevel,
+				Title:           fmt.Sprintf("%s (%d)", advice.Title, advice.Code),
+				Message:         fmt.Sprintf("%s\nDoc: %s#%d", advice.Content, sqlReviewDocs, advice.Code),
+			})
 		}
 	}
+	return &api.VCSSQLReviewResult{
+		Status:              status,
+		Content:             messageList,
+		CheckRunAnnotations: annotationList,
+	}
+}
+
+// convertSQLAdviceToBitbucketServerComment will convert SQL advice map to the markdown comment on the Bitbucket Server pull request.
+func convertSQLAdviceToBitbucketServerComment(adviceMap map[string][]advisor.Advice) *api.VCSSQLReviewResult {
+	messageList := []string{}
+	status := advisor.Success
+
+	fileList := []string{}
+	for filePath := range adviceMap {
+		fileList = append(fileList, filePath)
+	}
+	sort.Strings(fileList)
+
+	for _, filePath := range fileList {
+		adviceList := adviceMap[filePath]
+		for _, advice := range adviceList {
+			if advice.Code == 0 || advice.Status == advisor.Success {
+				continue
+			}
+
+			line := advice.Line
+			if line <= 0 {
+				line = 1
+			}
+
+			if advice.Status == advisor.Error {
+				status = advice.Status
+			} else if status != advisor.Error {
+				status = advice.Status
+			}
+
+			messageList = append(messageList, fmt.Sprintf(
+				"| %s | `%s#L%d` | [%s (%d)](%s#%d) | %s |",
+				advice.Status,
+				filePath,
+				line,
+				advice.Title,
+				advice.Code,
+				sqlReviewDocs,
+				advice.Code,
+				strings.ReplaceAll(advice.Content, "|", "\\|"),
+			))
+		}
+	}
+
+	content := "**Bytebase SQL Review**: no problems found."
+	if len(messageList) > 0 {
+		content = fmt.Sprintf(
+			"**Bytebase SQL Review**: %d problem(s) found.\n\n| Status | File | Rule | Message |\n| --- | --- | --- | --- |\n%s",
+			len(messageList),
+			strings.Join(messageList, "\n"),
+		)
+	}
 	return &api.VCSSQLReviewResult{
 		Status:  status,
-		Content: messageList,
+		Content: []string{content},
 	}
 }
 
@@ -1661,3 +2279,14 @@ func filterBitbucketBytebaseCommit(list []bitbucket.WebhookCommit) []bitbucket.W
 	}
 	return result
 }
+
+func filterGiteaBytebaseCommit(list []gitea.WebhookCommit) []gitea.WebhookCommit {
+	var result []gitea.WebhookCommit
+	for _, commit := range list {
+		if commit.Author.Name == vcs.BytebaseAuthorName && commit.Author.Email == vcs.BytebaseAuthorEmail {
+			continue
+		}
+		result = append(result, commit)
+	}
+	return result
+}
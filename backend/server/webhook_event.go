@@ -0,0 +1,212 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/common/log"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// webhookEventListLimit is the default number of the webhook events to list.
+const webhookEventListLimit = 100
+
+// webhookEventReplayContextKey is the request context key of the replayed webhook event ID. It's a context value instead
+// of a header, so the VCS deliveries cannot pretend to be replays.
+type webhookEventReplayContextKey struct{}
+
+// recordWebhookEvent persists the raw VCS webhook delivery and its result, so that the failed deliveries, e.g. due to
+// the transient errors, can be replayed instead of asking the users to push again.
+func (s *Server) recordWebhookEvent(next echo.HandlerFunc) echo.HandlerFunc {
+	if !common.FeatureFlag(common.FeatureFlagWebhookEvent) {
+		return next
+	}
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		eventUID, replay := ctx.Value(webhookEventReplayContextKey{}).(int)
+		if !replay {
+			event, err := s.store.CreateWebhookEvent(ctx, &store.WebhookEventMessage{
+				Path:   c.Request().URL.Path,
+				Header: redactWebhookEventHeader(c.Request().Header),
+				Body:   string(body),
+			})
+			if err != nil {
+				// Don't drop the delivery because it cannot be recorded.
+				log.Error("Failed to record webhook event", zap.String("path", c.Request().URL.Path), zap.Error(err))
+				return next(c)
+			}
+			eventUID = event.UID
+		}
+
+		handlerErr := next(c)
+		// The replays are signed again by the secret token of the webhook endpoint, so the deliveries failing the
+		// signature verification are dropped instead of being replayed as if they were signed.
+		var httpErr *echo.HTTPError
+		if !replay && errors.As(handlerErr, &httpErr) && httpErr.Code == http.StatusUnauthorized {
+			if err := s.store.DeleteWebhookEvent(context.Background(), eventUID); err != nil {
+				log.Error("Failed to delete webhook event", zap.Int("id", eventUID), zap.Error(err))
+			}
+			return handlerErr
+		}
+		update := &store.UpdateWebhookEventMessage{Status: api.WebhookEventDone}
+		if handlerErr != nil {
+			update.Status = api.WebhookEventFailed
+			update.Error = handlerErr.Error()
+		}
+		// The request context is canceled if the VCS closes the connection on its delivery timeout, which is exactly
+		// when the event needs to be recorded as failed.
+		if _, err := s.store.UpdateWebhookEvent(context.Background(), eventUID, update); err != nil {
+			log.Error("Failed to update webhook event", zap.Int("id", eventUID), zap.Error(err))
+		}
+		return handlerErr
+	}
+}
+
+func (s *Server) registerWebhookEventRoutes(g *echo.Group) {
+	g.GET("/webhook-event", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		find := &store.FindWebhookEventMessage{}
+		if status := c.QueryParam("status"); status != "" {
+			v := api.WebhookEventStatus(status)
+			find.Status = &v
+		}
+		limit := webhookEventListLimit
+		if v := c.QueryParam("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid limit %q", v))
+			}
+			limit = n
+		}
+		find.Limit = &limit
+		events, err := s.store.ListWebhookEvents(ctx, find)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list webhook events").SetInternal(err)
+		}
+		eventList := []*api.WebhookEvent{}
+		for _, event := range events {
+			eventList = append(eventList, toAPIWebhookEvent(event))
+		}
+		return c.JSON(http.StatusOK, eventList)
+	})
+
+	// The replay goes through the same signature verification as the delivery.
+	g.POST("/webhook-event/:eventID/replay", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("eventID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("eventID"))).SetInternal(err)
+		}
+		oldStatus := api.WebhookEventFailed
+		event, err := s.store.UpdateWebhookEvent(ctx, id, &store.UpdateWebhookEventMessage{
+			OldStatus: &oldStatus,
+			Status:    api.WebhookEventProcessing,
+			Replay:    true,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update webhook event %d", id)).SetInternal(err)
+		}
+		if event == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Webhook event %d not found or not failed", id))
+		}
+
+		req, err := http.NewRequestWithContext(context.WithValue(ctx, webhookEventReplayContextKey{}, event.UID), http.MethodPost, event.Path, strings.NewReader(event.Body))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to build the request of webhook event %d", id)).SetInternal(err)
+		}
+		req.Header = event.Header
+		if err := s.signWebhookEventReplay(ctx, req, []byte(event.Body)); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to sign the replay of webhook event %d", id)).SetInternal(err)
+		}
+		// Call the route handler directly, the result is recorded by recordWebhookEvent.
+		replayContext := s.e.NewContext(req, httptest.NewRecorder())
+		s.e.Router().Find(http.MethodPost, event.Path, replayContext)
+		_ = replayContext.Handler()(replayContext)
+
+		event, err = s.store.GetWebhookEvent(ctx, &store.FindWebhookEventMessage{UID: &id})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get webhook event %d", id)).SetInternal(err)
+		}
+		return c.JSON(http.StatusOK, toAPIWebhookEvent(event))
+	})
+}
+
+// redactWebhookEventHeader returns the header of the delivery to record, without the secret token and the signatures
+// by it.
+func redactWebhookEventHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for key := range redacted {
+		if key == "X-Gitlab-Token" || key == "X-Gitea-Signature" || strings.HasPrefix(key, "X-Hub-Signature") {
+			delete(redacted, key)
+		}
+	}
+	return redacted
+}
+
+// signWebhookEventReplay sets the secret token or the signature of the replayed delivery by the secret token of its
+// webhook endpoint, as they're not recorded. The repositories sharing a webhook endpoint share the secret token too.
+func (s *Server) signWebhookEventReplay(ctx context.Context, req *http.Request, body []byte) error {
+	// The path is /hook/{vcs}/{webhook_endpoint_id}.
+	vcsType, webhookEndpointID, ok := strings.Cut(strings.TrimPrefix(req.URL.Path, webhookAPIPrefix+"/"), "/")
+	if !ok {
+		return nil
+	}
+	repos, err := s.store.FindRepository(ctx, &api.RepositoryFind{WebhookEndpointID: &webhookEndpointID})
+	if err != nil {
+		return err
+	}
+	// The handler responds not found.
+	if len(repos) == 0 {
+		return nil
+	}
+	repo := repos[0]
+	m := hmac.New(sha256.New, []byte(repo.WebhookSecretToken))
+	if _, err := m.Write(body); err != nil {
+		return err
+	}
+	signature := hex.EncodeToString(m.Sum(nil))
+	switch vcsType {
+	case "gitlab":
+		req.Header.Set("X-Gitlab-Token", repo.WebhookSecretToken)
+	case "github":
+		req.Header.Set("X-Hub-Signature-256", "sha256="+signature)
+	case "gitea":
+		req.Header.Set("X-Gitea-Signature", signature)
+	case "bitbucket-server":
+		req.Header.Set("X-Hub-Signature", "sha256="+signature)
+	}
+	return nil
+}
+
+func toAPIWebhookEvent(event *store.WebhookEventMessage) *api.WebhookEvent {
+	return &api.WebhookEvent{
+		ID:           event.UID,
+		CreatedTs:    event.CreatedTs,
+		UpdatedTs:    event.UpdatedTs,
+		Path:         event.Path,
+		Status:       event.Status,
+		Error:        event.Error,
+		AttemptCount: event.AttemptCount,
+	}
+}
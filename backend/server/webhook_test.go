@@ -1,6 +1,7 @@
 package server
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,6 +11,8 @@ import (
 	"github.com/bytebase/bytebase/backend/plugin/advisor"
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/plugin/vcs"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/github"
+	"github.com/bytebase/bytebase/backend/plugin/vcs/gitlab"
 )
 
 // TODO(d): fix the double underscore "__".
@@ -48,6 +51,30 @@ func TestValidateGitHubWebhookSignature256(t *testing.T) {
 	})
 }
 
+func TestValidateGitLabWebhookToken(t *testing.T) {
+	assert.True(t, validateGitLabWebhookToken("bZovosSKsJ8QKCG9", "bZovosSKsJ8QKCG9"))
+	assert.False(t, validateGitLabWebhookToken("bZovosSKsJ8QKCG", "bZovosSKsJ8QKCG9"))
+	assert.False(t, validateGitLabWebhookToken("", "bZovosSKsJ8QKCG9"))
+}
+
+func TestRedactWebhookEventHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Gitlab-Token", "bZovosSKsJ8QKCG9")
+	header.Set("X-Hub-Signature", "sha256=e3b0c442")
+	header.Set("X-Hub-Signature-256", "sha256=e3b0c442")
+	header.Set("X-Gitea-Signature", "e3b0c442")
+	header.Set("X-GitHub-Event", "push")
+	header.Set("Content-Type", "application/json")
+
+	redacted := redactWebhookEventHeader(header)
+	assert.Equal(t, http.Header{
+		"X-Github-Event": []string{"push"},
+		"Content-Type":   []string{"application/json"},
+	}, redacted)
+	// The header of the request is kept for the handler.
+	assert.Equal(t, "bZovosSKsJ8QKCG9", header.Get("X-Gitlab-Token"))
+}
+
 func TestParseBranchNameFromGitHubRefs(t *testing.T) {
 	tests := []struct {
 		refs   string
@@ -85,6 +112,47 @@ func TestParseBranchNameFromGitHubRefs(t *testing.T) {
 	}
 }
 
+func TestMatchBranchRule(t *testing.T) {
+	branchRules := `[{"branch":"release/staging","environmentId":"staging","autoRollout":true},{"branch":"release/*","environmentId":"prod","autoRollout":false}]`
+	tests := []struct {
+		branchRules string
+		ref         string
+		want        *api.RepositoryBranchRule
+		ok          bool
+	}{
+		{
+			branchRules: branchRules,
+			ref:         "refs/heads/release/staging",
+			want:        &api.RepositoryBranchRule{Branch: "release/staging", EnvironmentID: "staging", AutoRollout: true},
+			ok:          true,
+		},
+		{
+			branchRules: branchRules,
+			ref:         "refs/heads/release/prod",
+			want:        &api.RepositoryBranchRule{Branch: "release/*", EnvironmentID: "prod"},
+			ok:          true,
+		},
+		// The branch matches no rule.
+		{
+			branchRules: branchRules,
+			ref:         "refs/heads/main",
+			ok:          false,
+		},
+		// No branch rules.
+		{
+			branchRules: "[]",
+			ref:         "refs/heads/main",
+			ok:          true,
+		},
+	}
+	for _, test := range tests {
+		rule, ok, err := matchBranchRule(test.branchRules, test.ref)
+		require.NoError(t, err)
+		assert.Equal(t, test.ok, ok, test.ref)
+		assert.Equal(t, test.want, rule, test.ref)
+	}
+}
+
 var mockSQLAdviceMap = map[string][]advisor.Advice{
 	"file1.sql": {
 		{
@@ -169,6 +237,27 @@ You can check the docs at https://www.bytebase.com/docs/reference/error-code/adv
 	assert.Equal(t, advisor.Error, res.Status)
 	assert.Equal(t, 1, len(res.Content))
 	assert.Equal(t, expect, res.Content[0])
+
+	require.Len(t, res.CodeQualityIssues, 4)
+	fingerprints := map[string]bool{}
+	for i, want := range []struct {
+		path     string
+		line     int
+		severity gitlab.CodeQualitySeverity
+	}{
+		{path: "file1.sql", line: 1, severity: gitlab.CodeQualitySeverityMinor},
+		{path: "file1.sql", line: 2, severity: gitlab.CodeQualitySeverityCritical},
+		{path: "file2.sql", line: 1, severity: gitlab.CodeQualitySeverityMinor},
+		{path: "file2.sql", line: 4, severity: gitlab.CodeQualitySeverityCritical},
+	} {
+		issue := res.CodeQualityIssues[i]
+		assert.Equal(t, want.path, issue.Location.Path)
+		assert.Equal(t, want.line, issue.Location.Lines.Begin)
+		assert.Equal(t, want.severity, issue.Severity)
+		fingerprints[issue.Fingerprint] = true
+	}
+	assert.Len(t, fingerprints, 4)
+	assert.Equal(t, "naming.index.idx: Index in table \"tech_book\" mismatches the naming convention, expect \"^$|^idx_tech_book_id_name$\" but found \"tech_book_id_name\"", res.CodeQualityIssues[1].Description)
 }
 
 func TestVCSSQLReview_ConvertSQLAdviceToGitHubActionResult(t *testing.T) {
@@ -182,6 +271,18 @@ func TestVCSSQLReview_ConvertSQLAdviceToGitHubActionResult(t *testing.T) {
 	assert.Equal(t, advisor.Error, res.Status)
 	assert.Equal(t, 4, len(res.Content))
 	assert.Equal(t, expect, res.Content)
+
+	require.Len(t, res.CheckRunAnnotations, 4)
+	assert.Equal(t, &github.CheckRunAnnotation{
+		Path:            "file1.sql",
+		StartLine:       2,
+		EndLine:         2,
+		AnnotationLevel: github.CheckRunAnnotationLevelFailure,
+		Title:           "naming.index.idx (303)",
+		Message:         "Index in table \"tech_book\" mismatches the naming convention, expect \"^$|^idx_tech_book_id_name$\" but found \"tech_book_id_name\"\nDoc: https://www.bytebase.com/docs/reference/error-code/advisor#303",
+	}, res.CheckRunAnnotations[1])
+	assert.Equal(t, github.CheckRunAnnotationLevelWarning, res.CheckRunAnnotations[2].AnnotationLevel)
+	assert.Equal(t, 1, res.CheckRunAnnotations[2].StartLine)
 }
 
 func TestGetFileInfo(t *testing.T) {
@@ -338,3 +439,21 @@ func TestGetFileInfo(t *testing.T) {
 		require.EqualError(t, err, "file change should be associated with exactly one project but found project-1, project-2")
 	})
 }
+
+func TestVCSSQLReview_ConvertSQLAdviceToBitbucketServerComment(t *testing.T) {
+	expect := "**Bytebase SQL Review**: 4 problem(s) found.\n\n" +
+		"| Status | File | Rule | Message |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| WARN | `file1.sql#L1` | [column.no-null (402)](https://www.bytebase.com/docs/reference/error-code/advisor#402) | Column \"id\" in \"public\".\"book\" cannot have NULL value |\n" +
+		"| ERROR | `file1.sql#L2` | [naming.index.idx (303)](https://www.bytebase.com/docs/reference/error-code/advisor#303) | Index in table \"tech_book\" mismatches the naming convention, expect \"^$\\|^idx_tech_book_id_name$\" but found \"tech_book_id_name\" |\n" +
+		"| WARN | `file2.sql#L1` | [naming.table (301)](https://www.bytebase.com/docs/reference/error-code/advisor#301) | \"techBook\" mismatches table naming convention, naming format should be \"^[a-z]+(_[a-z]+)*$\" |\n" +
+		"| ERROR | `file2.sql#L4` | [naming.index.uk (304)](https://www.bytebase.com/docs/reference/error-code/advisor#304) | Unique key in table \"tech_book\" mismatches the naming convention, expect \"^$\\|^uk_tech_book_id_name$\" but found \"tech_book_id_name\" |"
+	res := convertSQLAdviceToBitbucketServerComment(mockSQLAdviceMap)
+	assert.Equal(t, advisor.Error, res.Status)
+	assert.Equal(t, 1, len(res.Content))
+	assert.Equal(t, expect, res.Content[0])
+
+	res = convertSQLAdviceToBitbucketServerComment(map[string][]advisor.Advice{})
+	assert.Equal(t, advisor.Success, res.Status)
+	assert.Equal(t, []string{"**Bytebase SQL Review**: no problems found."}, res.Content)
+}
@@ -43,8 +43,15 @@ type sqlCheckRequestBody struct {
 	DatabaseType string                      `json:"databaseType"`
 	TemplateID   advisor.SQLReviewTemplateID `json:"templateId"`
 	Override     string                      `json:"override"`
+	// Format is the response format, could be empty or "SARIF".
+	Format string `json:"format"`
+	// FilePath is the path of the checked file, used as the location in the SARIF report.
+	FilePath string `json:"filePath"`
 }
 
+// sqlCheckResponseFormatSARIF is the SARIF response format for the sql check api.
+const sqlCheckResponseFormatSARIF = "SARIF"
+
 func (s *Server) registerAdvisorRoutes(g *echo.Group) {
 	g.POST("/advise", s.sqlCheckController)
 }
@@ -59,6 +66,8 @@ func (s *Server) registerAdvisorRoutes(g *echo.Group) {
 // @Param  databaseType  body  string  true   "The database type."  Enums(MYSQL, POSTGRES, TIDB)
 // @Param  templateId    body  string  false  "The SQL check template id. Required if the config is not specified." Enums(bb.sql-review.prod, bb.sql-review.dev)
 // @Param  override      body  string  false  "The SQL check config override string in YAML format. Check https://github.com/bytebase/bytebase/tree/main/plugin/advisor/config/sql-review.override.yaml for example. Required if the template is not specified."
+// @Param  format        body  string  false  "The response format. Return the SARIF report if set." Enums(SARIF)
+// @Param  filePath      body  string  false  "The file path of the statement, used as the location in the SARIF report."
 // @Success  200  {array}   advisor.Advice
 // @Failure  400  {object}  echo.HTTPError
 // @Failure  500  {object}  echo.HTTPError
@@ -81,6 +90,10 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Missing required template or override")
 	}
 
+	if request.Format != "" && request.Format != sqlCheckResponseFormatSARIF {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported format %s", request.Format))
+	}
+
 	advisorDBType, err := advisorDB.ConvertToAdvisorDBType(request.DatabaseType)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %s is not support", request.DatabaseType))
@@ -128,6 +141,11 @@ func (s *Server) sqlCheckController(c echo.Context) error {
 		},
 	})
 
+	if request.Format == sqlCheckResponseFormatSARIF {
+		return c.JSON(http.StatusOK, advisor.ConvertToSARIF([]*advisor.SARIFFileAdvice{
+			{FilePath: request.FilePath, AdviceList: adviceList},
+		}))
+	}
 	return c.JSON(http.StatusOK, adviceList)
 }
 
@@ -0,0 +1,358 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// AdminSessionMessage is the message for an admin mode session of the SQL editor.
+type AdminSessionMessage struct {
+	ID           int
+	CreatorID    int
+	CreatedTs    int64
+	InstanceUID  int
+	DatabaseName string
+	EndedTs      int64
+	EntryCount   int
+	LastHash     string
+}
+
+// FindAdminSessionMessage is the message to find admin sessions.
+type FindAdminSessionMessage struct {
+	ID          *int
+	CreatorID   *int
+	InstanceUID *int
+	Limit       *int
+	Offset      *int
+}
+
+// AdminSessionEntryMessage is the message for an entry of the admin session transcript.
+type AdminSessionEntryMessage struct {
+	SessionID int
+	Seq       int
+	CreatedTs int64
+	Kind      api.AdminSessionEntryKind
+	Content   string
+	Truncated bool
+	PrevHash  string
+	Hash      string
+}
+
+// adminSessionEntryHashContent is the content of the entry covered by the hash.
+type adminSessionEntryHashContent struct {
+	SessionID int                       `json:"sessionId"`
+	Seq       int                       `json:"seq"`
+	CreatedTs int64                     `json:"createdTs"`
+	Kind      api.AdminSessionEntryKind `json:"kind"`
+	Content   string                    `json:"content"`
+	Truncated bool                      `json:"truncated"`
+	PrevHash  string                    `json:"prevHash"`
+}
+
+// CreateAdminSession opens an admin session.
+func (s *Store) CreateAdminSession(ctx context.Context, create *AdminSessionMessage) (*AdminSessionMessage, error) {
+	var id int
+	if err := s.db.db.QueryRowContext(ctx, `
+		INSERT INTO admin_session (
+			creator_id,
+			instance_id,
+			database_name
+		) VALUES ($1, $2, $3)
+		RETURNING id`,
+		create.CreatorID,
+		create.InstanceUID,
+		create.DatabaseName,
+	).Scan(&id); err != nil {
+		return nil, errors.Wrapf(err, "failed to create admin session")
+	}
+	return s.GetAdminSession(ctx, &FindAdminSessionMessage{ID: &id})
+}
+
+// GetAdminSession gets an admin session.
+func (s *Store) GetAdminSession(ctx context.Context, find *FindAdminSessionMessage) (*AdminSessionMessage, error) {
+	sessions, err := s.ListAdminSessions(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+	if len(sessions) > 1 {
+		return nil, errors.Errorf("found %d admin sessions with filter %+v, expect 1", len(sessions), find)
+	}
+	return sessions[0], nil
+}
+
+// ListAdminSessions lists the admin sessions from the latest.
+func (s *Store) ListAdminSessions(ctx context.Context, find *FindAdminSessionMessage) ([]*AdminSessionMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceUID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			instance_id,
+			database_name,
+			ended_ts,
+			entry_count,
+			last_hash
+		FROM admin_session
+		WHERE %s
+		ORDER BY id DESC`, strings.Join(where, " AND "))
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+	if v := find.Offset; v != nil {
+		query += fmt.Sprintf(" OFFSET %d", *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query admin sessions")
+	}
+	defer rows.Close()
+
+	var sessions []*AdminSessionMessage
+	for rows.Next() {
+		session := &AdminSessionMessage{}
+		if err := rows.Scan(
+			&session.ID,
+			&session.CreatorID,
+			&session.CreatedTs,
+			&session.InstanceUID,
+			&session.DatabaseName,
+			&session.EndedTs,
+			&session.EntryCount,
+			&session.LastHash,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan admin session")
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return sessions, nil
+}
+
+// EndAdminSession ends an admin session, no entries can be appended to it afterwards.
+func (s *Store) EndAdminSession(ctx context.Context, id int) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		UPDATE admin_session
+		SET ended_ts = extract(epoch from now())
+		WHERE id = $1 AND ended_ts = 0`,
+		id,
+	); err != nil {
+		return errors.Wrapf(err, "failed to end admin session")
+	}
+	return nil
+}
+
+// AppendAdminSessionEntry appends an entry to the transcript of the open admin session. The entry is chained to the
+// previous one by the hash, and the session is locked so the entries are appended in order.
+func (s *Store) AppendAdminSessionEntry(ctx context.Context, sessionID int, kind api.AdminSessionEntryKind, content string) (*AdminSessionEntryMessage, error) {
+	entry := &AdminSessionEntryMessage{
+		SessionID: sessionID,
+		CreatedTs: time.Now().Unix(),
+		Kind:      kind,
+		Content:   content,
+	}
+	if len(entry.Content) > api.MaxAdminSessionEntryContentSize {
+		entry.Content = strings.ToValidUTF8(entry.Content[:api.MaxAdminSessionEntryContentSize], "")
+		entry.Truncated = true
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var entryCount int
+	var endedTs int64
+	if err := tx.QueryRowContext(ctx, `
+		SELECT entry_count, last_hash, ended_ts
+		FROM admin_session
+		WHERE id = $1
+		FOR UPDATE`,
+		sessionID,
+	).Scan(&entryCount, &entry.PrevHash, &endedTs); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.Errorf("admin session %d not found", sessionID)
+		}
+		return nil, errors.Wrapf(err, "failed to get admin session %d", sessionID)
+	}
+	if endedTs != 0 {
+		return nil, errors.Errorf("admin session %d has ended", sessionID)
+	}
+	entry.Seq = entryCount + 1
+	entry.Hash, err = computeAdminSessionEntryHash(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO admin_session_entry (
+			session_id,
+			seq,
+			created_ts,
+			kind,
+			content,
+			truncated,
+			prev_hash,
+			hash
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		entry.SessionID,
+		entry.Seq,
+		entry.CreatedTs,
+		entry.Kind,
+		entry.Content,
+		entry.Truncated,
+		entry.PrevHash,
+		entry.Hash,
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to create admin session entry")
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE admin_session
+		SET entry_count = $1, last_hash = $2
+		WHERE id = $3`,
+		entry.Seq,
+		entry.Hash,
+		sessionID,
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to update admin session %d", sessionID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return entry, nil
+}
+
+// ListAdminSessionEntries lists the entries of the admin session transcript in order.
+func (s *Store) ListAdminSessionEntries(ctx context.Context, sessionID int) ([]*AdminSessionEntryMessage, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			session_id,
+			seq,
+			created_ts,
+			kind,
+			content,
+			truncated,
+			prev_hash,
+			hash
+		FROM admin_session_entry
+		WHERE session_id = $1
+		ORDER BY seq`,
+		sessionID,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query admin session entries")
+	}
+	defer rows.Close()
+
+	var entries []*AdminSessionEntryMessage
+	for rows.Next() {
+		entry := &AdminSessionEntryMessage{}
+		if err := rows.Scan(
+			&entry.SessionID,
+			&entry.Seq,
+			&entry.CreatedTs,
+			&entry.Kind,
+			&entry.Content,
+			&entry.Truncated,
+			&entry.PrevHash,
+			&entry.Hash,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan admin session entry")
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return entries, nil
+}
+
+// VerifyAdminSessionTranscript verifies the hash chain of the entries of the admin session. It returns the error
+// describing the first broken entry, or nil if the transcript is intact.
+func VerifyAdminSessionTranscript(session *AdminSessionMessage, entries []*AdminSessionEntryMessage) error {
+	prevHash := ""
+	for i, entry := range entries {
+		if entry.SessionID != session.ID || entry.Seq != i+1 {
+			return errors.Errorf("entry %d is missing", i+1)
+		}
+		if entry.PrevHash != prevHash {
+			return errors.Errorf("entry %d isn't chained to the previous entry", entry.Seq)
+		}
+		hash, err := computeAdminSessionEntryHash(entry)
+		if err != nil {
+			return err
+		}
+		if hash != entry.Hash {
+			return errors.Errorf("entry %d has been modified", entry.Seq)
+		}
+		prevHash = entry.Hash
+	}
+	if len(entries) != session.EntryCount || prevHash != session.LastHash {
+		return errors.Errorf("the session has %d entries, but %d are found", session.EntryCount, len(entries))
+	}
+	return nil
+}
+
+func computeAdminSessionEntryHash(entry *AdminSessionEntryMessage) (string, error) {
+	content, err := json.Marshal(&adminSessionEntryHashContent{
+		SessionID: entry.SessionID,
+		Seq:       entry.Seq,
+		CreatedTs: entry.CreatedTs,
+		Kind:      entry.Kind,
+		Content:   entry.Content,
+		Truncated: entry.Truncated,
+		PrevHash:  entry.PrevHash,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal admin session entry")
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestVerifyAdminSessionTranscript(t *testing.T) {
+	session := &AdminSessionMessage{ID: 1}
+	var entries []*AdminSessionEntryMessage
+	for i, content := range []string{"DROP TABLE t;", `{"error":""}`} {
+		kind := api.AdminSessionEntryCommand
+		if i%2 == 1 {
+			kind = api.AdminSessionEntryResponse
+		}
+		entry := &AdminSessionEntryMessage{SessionID: 1, Seq: i + 1, CreatedTs: 1697270400, Kind: kind, Content: content, PrevHash: session.LastHash}
+		hash, err := computeAdminSessionEntryHash(entry)
+		require.NoError(t, err)
+		entry.Hash = hash
+		entries = append(entries, entry)
+		session.EntryCount, session.LastHash = entry.Seq, entry.Hash
+	}
+	require.NoError(t, VerifyAdminSessionTranscript(session, entries))
+
+	// The trailing entries are removed.
+	require.Error(t, VerifyAdminSessionTranscript(session, entries[:1]))
+	// An entry in the middle is removed.
+	require.Error(t, VerifyAdminSessionTranscript(session, entries[1:]))
+
+	// The content is modified.
+	modified := *entries[0]
+	modified.Content = "SELECT 1;"
+	require.Error(t, VerifyAdminSessionTranscript(session, []*AdminSessionEntryMessage{&modified, entries[1]}))
+
+	// The content is modified along with its hash, which breaks the chain.
+	hash, err := computeAdminSessionEntryHash(&modified)
+	require.NoError(t, err)
+	modified.Hash = hash
+	require.Error(t, VerifyAdminSessionTranscript(session, []*AdminSessionEntryMessage{&modified, entries[1]}))
+}
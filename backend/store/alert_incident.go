@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// AlertIncidentMessage is the message for an incident opened on an alert receiver.
+type AlertIncidentMessage struct {
+	ReceiverID string
+	DedupKey   string
+	CreatedTs  int64
+}
+
+// ListAlertIncidents lists the open incidents of all alert receivers.
+func (s *Store) ListAlertIncidents(ctx context.Context) ([]*AlertIncidentMessage, error) {
+	rows, err := s.db.db.QueryContext(ctx, `
+		SELECT
+			receiver_id,
+			dedup_key,
+			created_ts
+		FROM alert_incident
+		ORDER BY receiver_id, dedup_key`,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list alert incidents")
+	}
+	defer rows.Close()
+
+	var incidents []*AlertIncidentMessage
+	for rows.Next() {
+		var incident AlertIncidentMessage
+		if err := rows.Scan(
+			&incident.ReceiverID,
+			&incident.DedupKey,
+			&incident.CreatedTs,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan alert incident")
+		}
+		incidents = append(incidents, &incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to list alert incidents")
+	}
+	return incidents, nil
+}
+
+// CreateAlertIncident records the incident opened on the alert receiver.
+func (s *Store) CreateAlertIncident(ctx context.Context, receiverID, dedupKey string) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO alert_incident (
+			receiver_id,
+			dedup_key
+		) VALUES ($1, $2)
+		ON CONFLICT (receiver_id, dedup_key) DO NOTHING`,
+		receiverID,
+		dedupKey,
+	); err != nil {
+		return errors.Wrapf(err, "failed to create alert incident")
+	}
+	return nil
+}
+
+// DeleteAlertIncident deletes the incident after it's resolved on the alert receiver.
+func (s *Store) DeleteAlertIncident(ctx context.Context, receiverID, dedupKey string) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM alert_incident
+		WHERE receiver_id = $1 AND dedup_key = $2`,
+		receiverID,
+		dedupKey,
+	); err != nil {
+		return errors.Wrapf(err, "failed to delete alert incident")
+	}
+	return nil
+}
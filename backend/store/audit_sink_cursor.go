@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+)
+
+// GetAuditSinkCursor gets the ID of the last activity delivered to the audit sink, it returns nil if the sink hasn't
+// delivered any activity yet.
+func (s *Store) GetAuditSinkCursor(ctx context.Context, sinkID string) (*int, error) {
+	var activityID int
+	if err := s.db.db.QueryRowContext(ctx, `
+		SELECT activity_id
+		FROM audit_sink_cursor
+		WHERE sink_id = $1`,
+		sinkID,
+	).Scan(&activityID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get audit sink cursor")
+	}
+	return &activityID, nil
+}
+
+// UpsertAuditSinkCursor sets the ID of the last activity delivered to the audit sink.
+func (s *Store) UpsertAuditSinkCursor(ctx context.Context, sinkID string, activityID int) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO audit_sink_cursor (
+			sink_id,
+			activity_id
+		) VALUES ($1, $2)
+		ON CONFLICT (sink_id) DO UPDATE SET
+			updated_ts = extract(epoch from now()),
+			activity_id = EXCLUDED.activity_id`,
+		sinkID,
+		activityID,
+	); err != nil {
+		return errors.Wrapf(err, "failed to upsert audit sink cursor")
+	}
+	return nil
+}
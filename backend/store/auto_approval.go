@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AutoApprovalMessage is the message for an issue approved by an auto-approval rule.
+type AutoApprovalMessage struct {
+	IssueUID  int
+	RiskLevel int64
+	RuleID    string
+	Condition string
+
+	// Output only
+	UID       int
+	CreatedTs int64
+	// RevokerID is nil if the decision isn't revoked.
+	RevokerID *int
+	RevokedTs int64
+}
+
+// FindAutoApprovalMessage is the message to find auto-approvals.
+type FindAutoApprovalMessage struct {
+	UID      *int
+	IssueUID *int
+}
+
+// CreateAutoApproval logs the decision of an auto-approval rule.
+func (s *Store) CreateAutoApproval(ctx context.Context, create *AutoApprovalMessage) (*AutoApprovalMessage, error) {
+	autoApproval := &AutoApprovalMessage{
+		IssueUID:  create.IssueUID,
+		RiskLevel: create.RiskLevel,
+		RuleID:    create.RuleID,
+		Condition: create.Condition,
+	}
+	if err := s.db.db.QueryRowContext(ctx, `
+		INSERT INTO auto_approval (
+			issue_id,
+			risk_level,
+			rule_id,
+			condition
+		) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_ts`,
+		create.IssueUID,
+		create.RiskLevel,
+		create.RuleID,
+		create.Condition,
+	).Scan(&autoApproval.UID, &autoApproval.CreatedTs); err != nil {
+		return nil, errors.Wrapf(err, "failed to create auto-approval")
+	}
+	return autoApproval, nil
+}
+
+// ListAutoApprovals lists the auto-approvals, the latest first.
+func (s *Store) ListAutoApprovals(ctx context.Context, find *FindAutoApprovalMessage) ([]*AutoApprovalMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.UID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.IssueUID; v != nil {
+		where, args = append(where, fmt.Sprintf("issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+	rows, err := s.db.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			created_ts,
+			issue_id,
+			risk_level,
+			rule_id,
+			condition,
+			revoker_id,
+			revoked_ts
+		FROM auto_approval
+		WHERE %s
+		ORDER BY id DESC`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list auto-approvals")
+	}
+	defer rows.Close()
+
+	var autoApprovals []*AutoApprovalMessage
+	for rows.Next() {
+		var autoApproval AutoApprovalMessage
+		var revokerID sql.NullInt32
+		if err := rows.Scan(
+			&autoApproval.UID,
+			&autoApproval.CreatedTs,
+			&autoApproval.IssueUID,
+			&autoApproval.RiskLevel,
+			&autoApproval.RuleID,
+			&autoApproval.Condition,
+			&revokerID,
+			&autoApproval.RevokedTs,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan auto-approval")
+		}
+		if revokerID.Valid {
+			v := int(revokerID.Int32)
+			autoApproval.RevokerID = &v
+		}
+		autoApprovals = append(autoApprovals, &autoApproval)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan auto-approvals")
+	}
+	return autoApprovals, nil
+}
+
+// RevokeAutoApproval revokes the decision of an auto-approval rule.
+func (s *Store) RevokeAutoApproval(ctx context.Context, uid int, revokerID int) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		UPDATE auto_approval
+		SET revoker_id = $1, revoked_ts = extract(epoch from now())
+		WHERE id = $2`,
+		revokerID,
+		uid,
+	); err != nil {
+		return errors.Wrapf(err, "failed to revoke auto-approval")
+	}
+	return nil
+}
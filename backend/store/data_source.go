@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -9,6 +10,7 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 
 	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 )
 
@@ -35,6 +37,34 @@ type DataSourceMessage struct {
 	SSHUser                 string
 	SSHObfuscatedPassword   string
 	SSHObfuscatedPrivateKey string
+	// Proxy related.
+	Proxy DataSourceProxy
+	// Authentication related.
+	AuthenticationType           db.AuthenticationType
+	AWSRegion                    string
+	AWSAccessKeyID               string
+	AWSObfuscatedSecretAccessKey string
+	// GCP Cloud SQL connector related.
+	GCPCloudSQLConnectionName    string
+	GCPCloudSQLUsePrivateIP      bool
+	GCPObfuscatedCredentialsJSON string
+	// Azure AD related.
+	AzureTenantID               string
+	AzureClientID               string
+	AzureObfuscatedClientSecret string
+	// Kerberos related.
+	KerberosRealm            string
+	KerberosKDC              string
+	KerberosSPN              string
+	KerberosObfuscatedKeytab string
+	// ExternalSecretReference is the reference to the password in the external secret manager, such as vault://path#key.
+	ExternalSecretReference string
+	// Read replica routing related, used for the admin data source only.
+	ReadReplicaRoutingPolicy     api.ReadReplicaRoutingPolicy
+	ReadReplicaFallbackToPrimary bool
+	// Connection pool related, used for the admin data source only.
+	MaxConnections               int
+	ConnectionIdleTimeoutSeconds int
 	// (deprecated) Output only.
 	UID        int
 	DatabaseID int
@@ -48,6 +78,8 @@ type UpdateDataSourceMessage struct {
 	InstanceID    string
 
 	Type api.DataSourceType
+	// UID is used to update a particular read-only data source if set, because there could be multiple ones.
+	UID *int
 
 	Username           *string
 	ObfuscatedPassword *string
@@ -68,6 +100,90 @@ type UpdateDataSourceMessage struct {
 	SSHUser                 *string
 	SSHObfuscatedPassword   *string
 	SSHObfuscatedPrivateKey *string
+	// Proxy related.
+	Proxy *DataSourceProxy
+	// Authentication related.
+	AuthenticationType           *db.AuthenticationType
+	AWSRegion                    *string
+	AWSAccessKeyID               *string
+	AWSObfuscatedSecretAccessKey *string
+	// GCP Cloud SQL connector related.
+	GCPCloudSQLConnectionName    *string
+	GCPCloudSQLUsePrivateIP      *bool
+	GCPObfuscatedCredentialsJSON *string
+	// Azure AD related.
+	AzureTenantID               *string
+	AzureClientID               *string
+	AzureObfuscatedClientSecret *string
+	// Kerberos related.
+	KerberosRealm            *string
+	KerberosKDC              *string
+	KerberosSPN              *string
+	KerberosObfuscatedKeytab *string
+	// External secret related.
+	ExternalSecretReference *string
+	// Read replica routing related.
+	ReadReplicaRoutingPolicy     *api.ReadReplicaRoutingPolicy
+	ReadReplicaFallbackToPrimary *bool
+	// Connection pool related.
+	MaxConnections               *int
+	ConnectionIdleTimeoutSeconds *int
+}
+
+// extraDataSourceOptions are the data source options which storepb.DataSourceOptions doesn't have.
+// They're stored in the same options column.
+type extraDataSourceOptions struct {
+	Proxy                        *DataSourceProxy      `json:"proxy,omitempty"`
+	AuthenticationType           db.AuthenticationType `json:"authenticationType,omitempty"`
+	AWSRegion                    string                `json:"awsRegion,omitempty"`
+	AWSAccessKeyID               string                `json:"awsAccessKeyId,omitempty"`
+	AWSObfuscatedSecretAccessKey string                `json:"awsObfuscatedSecretAccessKey,omitempty"`
+	GCPCloudSQLConnectionName    string                `json:"gcpCloudSqlConnectionName,omitempty"`
+	GCPCloudSQLUsePrivateIP      bool                  `json:"gcpCloudSqlUsePrivateIp,omitempty"`
+	GCPObfuscatedCredentialsJSON string                `json:"gcpObfuscatedCredentialsJson,omitempty"`
+	AzureTenantID                string                `json:"azureTenantId,omitempty"`
+	AzureClientID                string                `json:"azureClientId,omitempty"`
+	AzureObfuscatedClientSecret  string                `json:"azureObfuscatedClientSecret,omitempty"`
+	ExternalSecretReference      string                `json:"externalSecretReference,omitempty"`
+	// Read replica routing related.
+	ReadReplicaRoutingPolicy     api.ReadReplicaRoutingPolicy `json:"readReplicaRoutingPolicy,omitempty"`
+	ReadReplicaFallbackToPrimary bool                         `json:"readReplicaFallbackToPrimary,omitempty"`
+	// Connection pool related.
+	MaxConnections               int `json:"maxConnections,omitempty"`
+	ConnectionIdleTimeoutSeconds int `json:"connectionIdleTimeoutSeconds,omitempty"`
+	// Kerberos related.
+	KerberosRealm            string `json:"kerberosRealm,omitempty"`
+	KerberosKDC              string `json:"kerberosKdc,omitempty"`
+	KerberosSPN              string `json:"kerberosSpn,omitempty"`
+	KerberosObfuscatedKeytab string `json:"kerberosObfuscatedKeytab,omitempty"`
+}
+
+// DataSourceProxy is the proxy chain to reach the data source.
+type DataSourceProxy struct {
+	SSHJumpHostList []*SSHJumpHost `json:"sshJumpHostList,omitempty"`
+	SOCKS5          *SOCKS5Proxy   `json:"socks5,omitempty"`
+}
+
+// IsEmpty returns true if no proxy is configured.
+func (p *DataSourceProxy) IsEmpty() bool {
+	return len(p.SSHJumpHostList) == 0 && p.SOCKS5 == nil
+}
+
+// SSHJumpHost is the SSH bastion hopped through before reaching the SSH host of the data source.
+type SSHJumpHost struct {
+	Host                 string `json:"host"`
+	Port                 string `json:"port"`
+	User                 string `json:"user"`
+	ObfuscatedPassword   string `json:"obfuscatedPassword"`
+	ObfuscatedPrivateKey string `json:"obfuscatedPrivateKey"`
+}
+
+// SOCKS5Proxy is the SOCKS5 proxy to dial the data source, or its first SSH hop, through.
+type SOCKS5Proxy struct {
+	Host               string `json:"host"`
+	Port               string `json:"port"`
+	User               string `json:"user"`
+	ObfuscatedPassword string `json:"obfuscatedPassword"`
 }
 
 func (*Store) listDataSourceV2(ctx context.Context, tx *Tx, instanceID string) ([]*DataSourceMessage, error) {
@@ -89,7 +205,8 @@ func (*Store) listDataSourceV2(ctx context.Context, tx *Tx, instanceID string) (
 			data_source.options
 		FROM data_source
 		LEFT JOIN instance ON instance.id = data_source.instance_id
-		WHERE instance.resource_id = $1`,
+		WHERE instance.resource_id = $1
+		ORDER BY data_source.id`,
 		instanceID,
 	)
 	if err != nil {
@@ -130,6 +247,32 @@ func (*Store) listDataSourceV2(ctx context.Context, tx *Tx, instanceID string) (
 		dataSourceMessage.SSHUser = dataSourceOptions.SshUser
 		dataSourceMessage.SSHObfuscatedPassword = dataSourceOptions.SshObfuscatedPassword
 		dataSourceMessage.SSHObfuscatedPrivateKey = dataSourceOptions.SshObfuscatedPrivateKey
+		var extraOptions extraDataSourceOptions
+		if err := json.Unmarshal(protoBytes, &extraOptions); err != nil {
+			return nil, err
+		}
+		if extraOptions.Proxy != nil {
+			dataSourceMessage.Proxy = *extraOptions.Proxy
+		}
+		dataSourceMessage.AuthenticationType = extraOptions.AuthenticationType
+		dataSourceMessage.AWSRegion = extraOptions.AWSRegion
+		dataSourceMessage.AWSAccessKeyID = extraOptions.AWSAccessKeyID
+		dataSourceMessage.AWSObfuscatedSecretAccessKey = extraOptions.AWSObfuscatedSecretAccessKey
+		dataSourceMessage.GCPCloudSQLConnectionName = extraOptions.GCPCloudSQLConnectionName
+		dataSourceMessage.GCPCloudSQLUsePrivateIP = extraOptions.GCPCloudSQLUsePrivateIP
+		dataSourceMessage.GCPObfuscatedCredentialsJSON = extraOptions.GCPObfuscatedCredentialsJSON
+		dataSourceMessage.AzureTenantID = extraOptions.AzureTenantID
+		dataSourceMessage.AzureClientID = extraOptions.AzureClientID
+		dataSourceMessage.AzureObfuscatedClientSecret = extraOptions.AzureObfuscatedClientSecret
+		dataSourceMessage.ExternalSecretReference = extraOptions.ExternalSecretReference
+		dataSourceMessage.ReadReplicaRoutingPolicy = extraOptions.ReadReplicaRoutingPolicy
+		dataSourceMessage.ReadReplicaFallbackToPrimary = extraOptions.ReadReplicaFallbackToPrimary
+		dataSourceMessage.MaxConnections = extraOptions.MaxConnections
+		dataSourceMessage.ConnectionIdleTimeoutSeconds = extraOptions.ConnectionIdleTimeoutSeconds
+		dataSourceMessage.KerberosRealm = extraOptions.KerberosRealm
+		dataSourceMessage.KerberosKDC = extraOptions.KerberosKDC
+		dataSourceMessage.KerberosSPN = extraOptions.KerberosSPN
+		dataSourceMessage.KerberosObfuscatedKeytab = extraOptions.KerberosObfuscatedKeytab
 
 		dataSourceMessages = append(dataSourceMessages, &dataSourceMessage)
 	}
@@ -203,6 +346,38 @@ func (s *Store) RemoveDataSourceV2(ctx context.Context, instanceUID int, environ
 	return nil
 }
 
+// RemoveDataSourceByUIDV2 removes a particular RO data source from an instance, because there could be multiple ones.
+func (s *Store) RemoveDataSourceByUIDV2(ctx context.Context, instanceUID int, environmentID, instanceID string, dataSourceUID int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.New("Failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM data_source WHERE data_source.instance_id = $1 AND data_source.id = $2 AND data_source.type = $3;
+	`, instanceUID, dataSourceUID, api.RO)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get rows affected")
+	}
+	if rowsAffected != 1 {
+		return errors.Errorf("remove %d data_sources with uid %d for instance uid %d, but expected 1", rowsAffected, dataSourceUID, instanceUID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	s.instanceCache.Delete(getInstanceCacheKey(environmentID, instanceID))
+	s.instanceIDCache.Delete(instanceUID)
+	return nil
+}
+
 // UpdateDataSourceV2 updates a data source and returns the instance.
 func (s *Store) UpdateDataSourceV2(ctx context.Context, patch *UpdateDataSourceMessage) error {
 	set, args := []string{"updater_id = $1"}, []any{fmt.Sprintf("%d", patch.UpdaterID)}
@@ -262,6 +437,70 @@ func (s *Store) UpdateDataSourceV2(ctx context.Context, patch *UpdateDataSourceM
 	if v := patch.SSHObfuscatedPrivateKey; v != nil {
 		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('sshObfuscatedPrivateKey', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
 	}
+	if v := patch.Proxy; v != nil {
+		proxyBytes, err := json.Marshal(v)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal data source proxy")
+		}
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('proxy', $%d::JSONB)", len(args)+1)), append(args, string(proxyBytes))
+	}
+	if v := patch.AuthenticationType; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('authenticationType', to_jsonb($%d::TEXT))", len(args)+1)), append(args, string(*v))
+	}
+	if v := patch.AWSRegion; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('awsRegion', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.AWSAccessKeyID; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('awsAccessKeyId', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.AWSObfuscatedSecretAccessKey; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('awsObfuscatedSecretAccessKey', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.GCPCloudSQLConnectionName; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('gcpCloudSqlConnectionName', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.GCPCloudSQLUsePrivateIP; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('gcpCloudSqlUsePrivateIp', to_jsonb($%d::BOOLEAN))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.GCPObfuscatedCredentialsJSON; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('gcpObfuscatedCredentialsJson', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.AzureTenantID; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('azureTenantId', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.AzureClientID; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('azureClientId', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.AzureObfuscatedClientSecret; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('azureObfuscatedClientSecret', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.ExternalSecretReference; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('externalSecretReference', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.ReadReplicaRoutingPolicy; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('readReplicaRoutingPolicy', to_jsonb($%d::TEXT))", len(args)+1)), append(args, string(*v))
+	}
+	if v := patch.ReadReplicaFallbackToPrimary; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('readReplicaFallbackToPrimary', to_jsonb($%d::BOOLEAN))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.MaxConnections; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('maxConnections', to_jsonb($%d::INTEGER))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.ConnectionIdleTimeoutSeconds; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('connectionIdleTimeoutSeconds', to_jsonb($%d::INTEGER))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.KerberosRealm; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('kerberosRealm', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.KerberosKDC; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('kerberosKdc', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.KerberosSPN; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('kerberosSpn', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
+	if v := patch.KerberosObfuscatedKeytab; v != nil {
+		optionSet, args = append(optionSet, fmt.Sprintf("jsonb_build_object('kerberosObfuscatedKeytab', to_jsonb($%d::TEXT))", len(args)+1)), append(args, *v)
+	}
 	if len(optionSet) != 0 {
 		set = append(set, fmt.Sprintf(`options = options || %s`, strings.Join(optionSet, "||")))
 	}
@@ -276,6 +515,9 @@ func (s *Store) UpdateDataSourceV2(ctx context.Context, patch *UpdateDataSourceM
 	query := `UPDATE data_source SET ` + strings.Join(set, ", ") +
 		` WHERE instance_id = ` + fmt.Sprintf("%d", patch.InstanceUID) +
 		` AND type = ` + fmt.Sprintf(`'%s'`, patch.Type)
+	if v := patch.UID; v != nil {
+		query += fmt.Sprintf(` AND id = %d`, *v)
+	}
 	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
@@ -315,6 +557,9 @@ func (*Store) addDataSourceToInstanceImplV2(ctx context.Context, tx *Tx, instanc
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal data source options")
 	}
+	if protoBytes, err = mergeExtraDataSourceOptions(protoBytes, dataSource); err != nil {
+		return err
+	}
 
 	if _, err := tx.ExecContext(ctx, `
 		INSERT INTO data_source (
@@ -346,6 +591,46 @@ func (*Store) addDataSourceToInstanceImplV2(ctx context.Context, tx *Tx, instanc
 	return nil
 }
 
+// mergeExtraDataSourceOptions merges the extra options of the data source into the marshaled storepb.DataSourceOptions.
+func mergeExtraDataSourceOptions(protoBytes []byte, dataSource *DataSourceMessage) ([]byte, error) {
+	extraOptions := extraDataSourceOptions{
+		AuthenticationType:           dataSource.AuthenticationType,
+		AWSRegion:                    dataSource.AWSRegion,
+		AWSAccessKeyID:               dataSource.AWSAccessKeyID,
+		AWSObfuscatedSecretAccessKey: dataSource.AWSObfuscatedSecretAccessKey,
+		GCPCloudSQLConnectionName:    dataSource.GCPCloudSQLConnectionName,
+		GCPCloudSQLUsePrivateIP:      dataSource.GCPCloudSQLUsePrivateIP,
+		GCPObfuscatedCredentialsJSON: dataSource.GCPObfuscatedCredentialsJSON,
+		AzureTenantID:                dataSource.AzureTenantID,
+		AzureClientID:                dataSource.AzureClientID,
+		AzureObfuscatedClientSecret:  dataSource.AzureObfuscatedClientSecret,
+		ExternalSecretReference:      dataSource.ExternalSecretReference,
+		ReadReplicaRoutingPolicy:     dataSource.ReadReplicaRoutingPolicy,
+		ReadReplicaFallbackToPrimary: dataSource.ReadReplicaFallbackToPrimary,
+		MaxConnections:               dataSource.MaxConnections,
+		ConnectionIdleTimeoutSeconds: dataSource.ConnectionIdleTimeoutSeconds,
+		KerberosRealm:                dataSource.KerberosRealm,
+		KerberosKDC:                  dataSource.KerberosKDC,
+		KerberosSPN:                  dataSource.KerberosSPN,
+		KerberosObfuscatedKeytab:     dataSource.KerberosObfuscatedKeytab,
+	}
+	if !dataSource.Proxy.IsEmpty() {
+		extraOptions.Proxy = &dataSource.Proxy
+	}
+	extraBytes, err := json.Marshal(&extraOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal extra data source options")
+	}
+	options := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(protoBytes, &options); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal data source options")
+	}
+	if err := json.Unmarshal(extraBytes, &options); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal extra data source options")
+	}
+	return json.Marshal(options)
+}
+
 // clearDataSourceImpl deletes dataSources by instance id and database id.
 func (*Store) clearDataSourceImpl(ctx context.Context, tx *Tx, instanceID, databaseID int) error {
 	if _, err := tx.ExecContext(ctx, `DELETE FROM data_source WHERE instance_id = $1 AND database_id = $2`, instanceID, databaseID); err != nil {
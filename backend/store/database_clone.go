@@ -0,0 +1,177 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// DatabaseCloneMessage is the message for a database clone.
+type DatabaseCloneMessage struct {
+	SourceDatabaseUID  int
+	TargetInstanceUID  int
+	TargetDatabaseName string
+	Status             api.DatabaseCloneStatus
+	TableCount         int
+	RowCount           int64
+	Error              string
+
+	// Output only
+	UID       int
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+}
+
+// FindDatabaseCloneMessage is the message to find database clones.
+type FindDatabaseCloneMessage struct {
+	UID        *int
+	StatusList []api.DatabaseCloneStatus
+}
+
+// UpdateDatabaseCloneMessage is the message to update the status and the progress of a database clone.
+type UpdateDatabaseCloneMessage struct {
+	UpdaterID int
+	// OldStatus is the status the clone is expected to be in, the clone isn't updated otherwise.
+	OldStatus  api.DatabaseCloneStatus
+	Status     api.DatabaseCloneStatus
+	TableCount *int
+	RowCount   *int64
+	Error      *string
+}
+
+const databaseCloneColumns = `
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			source_database_id,
+			target_instance_id,
+			target_database_name,
+			status,
+			table_count,
+			row_count,
+			error`
+
+// CreateDatabaseClone creates a pending database clone.
+func (s *Store) CreateDatabaseClone(ctx context.Context, create *DatabaseCloneMessage, creatorID int) (*DatabaseCloneMessage, error) {
+	clone, err := scanDatabaseClone(s.db.db.QueryRowContext(ctx, `
+		INSERT INTO database_clone (
+			creator_id,
+			updater_id,
+			source_database_id,
+			target_instance_id,
+			target_database_name,
+			status
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING`+databaseCloneColumns,
+		creatorID,
+		creatorID,
+		create.SourceDatabaseUID,
+		create.TargetInstanceUID,
+		create.TargetDatabaseName,
+		api.DatabaseClonePending,
+	))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create database clone")
+	}
+	return clone, nil
+}
+
+// ListDatabaseClones lists the database clones, the latest first.
+func (s *Store) ListDatabaseClones(ctx context.Context, find *FindDatabaseCloneMessage) ([]*DatabaseCloneMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.UID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.StatusList; v != nil {
+		list := []string{}
+		for _, status := range v {
+			list = append(list, fmt.Sprintf("$%d", len(args)+1))
+			args = append(args, status)
+		}
+		where = append(where, fmt.Sprintf("status IN (%s)", strings.Join(list, ",")))
+	}
+	rows, err := s.db.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT`+databaseCloneColumns+`
+		FROM database_clone
+		WHERE %s
+		ORDER BY id DESC`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list database clones")
+	}
+	defer rows.Close()
+
+	var clones []*DatabaseCloneMessage
+	for rows.Next() {
+		clone, err := scanDatabaseClone(rows)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to scan database clone")
+		}
+		clones = append(clones, clone)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan database clones")
+	}
+	return clones, nil
+}
+
+// UpdateDatabaseClone updates the status and the progress of a database clone. It returns nil if the clone isn't in
+// the old status, e.g. it's picked up by another runner in the meantime.
+func (s *Store) UpdateDatabaseClone(ctx context.Context, uid int, update *UpdateDatabaseCloneMessage) (*DatabaseCloneMessage, error) {
+	set, args := []string{"updater_id = $1", "status = $2"}, []any{update.UpdaterID, update.Status}
+	if v := update.TableCount; v != nil {
+		set, args = append(set, fmt.Sprintf("table_count = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := update.RowCount; v != nil {
+		set, args = append(set, fmt.Sprintf("row_count = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := update.Error; v != nil {
+		set, args = append(set, fmt.Sprintf("error = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, uid, update.OldStatus)
+	clone, err := scanDatabaseClone(s.db.db.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE database_clone
+		SET %s
+		WHERE id = $%d AND status = $%d
+		RETURNING`+databaseCloneColumns, strings.Join(set, ", "), len(args)-1, len(args)),
+		args...,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to update database clone")
+	}
+	return clone, nil
+}
+
+func scanDatabaseClone(row interface{ Scan(...any) error }) (*DatabaseCloneMessage, error) {
+	var clone DatabaseCloneMessage
+	if err := row.Scan(
+		&clone.UID,
+		&clone.CreatorID,
+		&clone.CreatedTs,
+		&clone.UpdaterID,
+		&clone.UpdatedTs,
+		&clone.SourceDatabaseUID,
+		&clone.TargetInstanceUID,
+		&clone.TargetDatabaseName,
+		&clone.Status,
+		&clone.TableCount,
+		&clone.RowCount,
+		&clone.Error,
+	); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
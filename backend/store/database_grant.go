@@ -0,0 +1,202 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// DatabaseGrantMessage is the message for the temporary elevated access to a database.
+type DatabaseGrantMessage struct {
+	DatabaseUID     int
+	Level           api.DatabaseGrantLevel
+	Status          api.DatabaseGrantStatus
+	DurationSeconds int64
+	Justification   string
+	ExpireTs        int64
+
+	// Output only
+	UID       int
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+}
+
+// FindDatabaseGrantMessage is the message to find database grants.
+type FindDatabaseGrantMessage struct {
+	UID         *int
+	DatabaseUID *int
+	CreatorID   *int
+	StatusList  []api.DatabaseGrantStatus
+	// ExpireTsBefore finds the grants expiring before the time.
+	ExpireTsBefore *int64
+}
+
+// UpdateDatabaseGrantMessage is the message to update the status of a database grant.
+type UpdateDatabaseGrantMessage struct {
+	UpdaterID int
+	// OldStatus is the status the grant is expected to be in, the grant isn't updated otherwise.
+	OldStatus api.DatabaseGrantStatus
+	Status    api.DatabaseGrantStatus
+	ExpireTs  *int64
+}
+
+// CreateDatabaseGrant creates a pending database grant.
+func (s *Store) CreateDatabaseGrant(ctx context.Context, create *DatabaseGrantMessage, creatorID int) (*DatabaseGrantMessage, error) {
+	grant := &DatabaseGrantMessage{
+		DatabaseUID:     create.DatabaseUID,
+		Level:           create.Level,
+		Status:          api.DatabaseGrantPending,
+		DurationSeconds: create.DurationSeconds,
+		Justification:   create.Justification,
+		CreatorID:       creatorID,
+		UpdaterID:       creatorID,
+	}
+	if err := s.db.db.QueryRowContext(ctx, `
+		INSERT INTO database_grant (
+			creator_id,
+			updater_id,
+			database_id,
+			level,
+			status,
+			duration_seconds,
+			justification
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_ts, updated_ts`,
+		creatorID,
+		creatorID,
+		create.DatabaseUID,
+		create.Level,
+		grant.Status,
+		create.DurationSeconds,
+		create.Justification,
+	).Scan(&grant.UID, &grant.CreatedTs, &grant.UpdatedTs); err != nil {
+		return nil, errors.Wrapf(err, "failed to create database grant")
+	}
+	return grant, nil
+}
+
+// ListDatabaseGrants lists the database grants, the latest first.
+func (s *Store) ListDatabaseGrants(ctx context.Context, find *FindDatabaseGrantMessage) ([]*DatabaseGrantMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.UID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseUID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.StatusList; v != nil {
+		list := []string{}
+		for _, status := range v {
+			list = append(list, fmt.Sprintf("$%d", len(args)+1))
+			args = append(args, status)
+		}
+		where = append(where, fmt.Sprintf("status IN (%s)", strings.Join(list, ",")))
+	}
+	if v := find.ExpireTsBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("expire_ts <= $%d", len(args)+1)), append(args, *v)
+	}
+	rows, err := s.db.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			level,
+			status,
+			duration_seconds,
+			justification,
+			expire_ts
+		FROM database_grant
+		WHERE %s
+		ORDER BY id DESC`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list database grants")
+	}
+	defer rows.Close()
+
+	var grants []*DatabaseGrantMessage
+	for rows.Next() {
+		var grant DatabaseGrantMessage
+		if err := rows.Scan(
+			&grant.UID,
+			&grant.CreatorID,
+			&grant.CreatedTs,
+			&grant.UpdaterID,
+			&grant.UpdatedTs,
+			&grant.DatabaseUID,
+			&grant.Level,
+			&grant.Status,
+			&grant.DurationSeconds,
+			&grant.Justification,
+			&grant.ExpireTs,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan database grant")
+		}
+		grants = append(grants, &grant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan database grants")
+	}
+	return grants, nil
+}
+
+// UpdateDatabaseGrant updates the status of a database grant. It returns nil if the grant isn't in the old status,
+// e.g. it's revoked by another user or has expired in the meantime.
+func (s *Store) UpdateDatabaseGrant(ctx context.Context, uid int, update *UpdateDatabaseGrantMessage) (*DatabaseGrantMessage, error) {
+	set, args := []string{"updater_id = $1", "status = $2"}, []any{update.UpdaterID, update.Status}
+	if v := update.ExpireTs; v != nil {
+		set, args = append(set, fmt.Sprintf("expire_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, uid, update.OldStatus)
+	var grant DatabaseGrantMessage
+	if err := s.db.db.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE database_grant
+		SET %s
+		WHERE id = $%d AND status = $%d
+		RETURNING
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			level,
+			status,
+			duration_seconds,
+			justification,
+			expire_ts`, strings.Join(set, ", "), len(args)-1, len(args)),
+		args...,
+	).Scan(
+		&grant.UID,
+		&grant.CreatorID,
+		&grant.CreatedTs,
+		&grant.UpdaterID,
+		&grant.UpdatedTs,
+		&grant.DatabaseUID,
+		&grant.Level,
+		&grant.Status,
+		&grant.DurationSeconds,
+		&grant.Justification,
+		&grant.ExpireTs,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to update database grant")
+	}
+	return &grant, nil
+}
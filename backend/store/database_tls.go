@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DatabaseTLSMessage is the message for the TLS configuration of a database.
+// It overrides the TLS configuration of the instance data sources when connecting to the database.
+type DatabaseTLSMessage struct {
+	DatabaseUID       int
+	ObfuscatedSslCa   string
+	ObfuscatedSslCert string
+	ObfuscatedSslKey  string
+	// Output only.
+	UpdatedTs int64
+}
+
+// FindDatabaseTLSMessage is the message to find the database TLS configurations.
+type FindDatabaseTLSMessage struct {
+	DatabaseUID  *int
+	InstanceUID  *int
+	DatabaseName *string
+}
+
+// GetDatabaseTLS gets the TLS configuration of a database.
+func (s *Store) GetDatabaseTLS(ctx context.Context, find *FindDatabaseTLSMessage) (*DatabaseTLSMessage, error) {
+	databaseTLSList, err := s.ListDatabaseTLS(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(databaseTLSList) == 0 {
+		return nil, nil
+	}
+	if len(databaseTLSList) > 1 {
+		return nil, errors.Errorf("found %d database TLS configurations with filter %+v, expect 1", len(databaseTLSList), find)
+	}
+	return databaseTLSList[0], nil
+}
+
+// ListDatabaseTLS lists the database TLS configurations.
+func (s *Store) ListDatabaseTLS(ctx context.Context, find *FindDatabaseTLSMessage) ([]*DatabaseTLSMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.DatabaseUID; v != nil {
+		where, args = append(where, fmt.Sprintf("db_tls.database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceUID; v != nil {
+		where, args = append(where, fmt.Sprintf("db.instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseName; v != nil {
+		where, args = append(where, fmt.Sprintf("db.name = $%d", len(args)+1)), append(args, *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			db_tls.database_id,
+			db_tls.ssl_ca,
+			db_tls.ssl_cert,
+			db_tls.ssl_key,
+			db_tls.updated_ts
+		FROM db_tls
+		LEFT JOIN db ON db_tls.database_id = db.id
+		WHERE %s
+		ORDER BY db_tls.database_id`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query database TLS configurations")
+	}
+	defer rows.Close()
+
+	var databaseTLSList []*DatabaseTLSMessage
+	for rows.Next() {
+		databaseTLS := &DatabaseTLSMessage{}
+		if err := rows.Scan(
+			&databaseTLS.DatabaseUID,
+			&databaseTLS.ObfuscatedSslCa,
+			&databaseTLS.ObfuscatedSslCert,
+			&databaseTLS.ObfuscatedSslKey,
+			&databaseTLS.UpdatedTs,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan database TLS configuration")
+		}
+		databaseTLSList = append(databaseTLSList, databaseTLS)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return databaseTLSList, nil
+}
+
+// UpsertDatabaseTLS upserts the TLS configuration of a database.
+func (s *Store) UpsertDatabaseTLS(ctx context.Context, upsert *DatabaseTLSMessage, updaterID int) (*DatabaseTLSMessage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	databaseTLS := &DatabaseTLSMessage{}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO db_tls (
+			creator_id,
+			updater_id,
+			database_id,
+			ssl_ca,
+			ssl_cert,
+			ssl_key
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (database_id) DO UPDATE SET
+			updater_id = excluded.updater_id,
+			ssl_ca = excluded.ssl_ca,
+			ssl_cert = excluded.ssl_cert,
+			ssl_key = excluded.ssl_key
+		RETURNING database_id, ssl_ca, ssl_cert, ssl_key, updated_ts`,
+		updaterID,
+		updaterID,
+		upsert.DatabaseUID,
+		upsert.ObfuscatedSslCa,
+		upsert.ObfuscatedSslCert,
+		upsert.ObfuscatedSslKey,
+	).Scan(
+		&databaseTLS.DatabaseUID,
+		&databaseTLS.ObfuscatedSslCa,
+		&databaseTLS.ObfuscatedSslCert,
+		&databaseTLS.ObfuscatedSslKey,
+		&databaseTLS.UpdatedTs,
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert database TLS configuration")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return databaseTLS, nil
+}
+
+// DeleteDatabaseTLS deletes the TLS configuration of a database, so that the one of the instance data sources is used.
+func (s *Store) DeleteDatabaseTLS(ctx context.Context, databaseUID int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM db_tls WHERE database_id = $1`, databaseUID); err != nil {
+		return errors.Wrapf(err, "failed to delete database TLS configuration")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrapf(err, "failed to commit transaction")
+	}
+	return nil
+}
@@ -3,11 +3,14 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"google.golang.org/protobuf/encoding/protojson"
 
+	"github.com/bytebase/bytebase/backend/common"
+	"github.com/bytebase/bytebase/backend/plugin/db"
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 )
 
@@ -15,6 +18,8 @@ import (
 type DBSchema struct {
 	Metadata *storepb.DatabaseMetadata
 	Schema   []byte
+	// RowLevelSecurity is the row level security of the PostgreSQL tables, which isn't in the metadata yet.
+	RowLevelSecurity []*db.TableRowLevelSecurity
 }
 
 // GetDBSchema gets the schema for a database.
@@ -34,18 +39,19 @@ func (s *Store) GetDBSchema(ctx context.Context, databaseID int) (*DBSchema, err
 	defer tx.Rollback()
 
 	dbSchema := &DBSchema{}
-	var metadata []byte
+	var metadata, rowLevelSecurity []byte
+	columns := []string{"metadata", "raw_dump"}
+	dest := []any{&metadata, &dbSchema.Schema}
+	if common.FeatureFlag(common.FeatureFlagRowLevelSecurity) {
+		columns, dest = append(columns, "row_level_security"), append(dest, &rowLevelSecurity)
+	}
 	if err := tx.QueryRowContext(ctx, `
 		SELECT
-			metadata,
-			raw_dump
+			`+strings.Join(columns, ", ")+`
 		FROM db_schema
 		WHERE `+strings.Join(where, " AND "),
 		args...,
-	).Scan(
-		&metadata,
-		&dbSchema.Schema,
-	); err != nil {
+	).Scan(dest...); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -61,6 +67,11 @@ func (s *Store) GetDBSchema(ctx context.Context, databaseID int) (*DBSchema, err
 		return nil, err
 	}
 	dbSchema.Metadata = &databaseSchema
+	if rowLevelSecurity != nil {
+		if err := json.Unmarshal(rowLevelSecurity, &dbSchema.RowLevelSecurity); err != nil {
+			return nil, err
+		}
+	}
 
 	s.dbSchemaCache.Store(databaseID, dbSchema)
 	return dbSchema, nil
@@ -72,35 +83,48 @@ func (s *Store) UpsertDBSchema(ctx context.Context, databaseID int, dbSchema *DB
 	if err != nil {
 		return err
 	}
+	args := []any{
+		updaterID,
+		updaterID,
+		databaseID,
+		metadataBytes,
+		// Convert to string because []byte{} is null which violates db schema constraints.
+		string(dbSchema.Schema),
+	}
+	columns := []string{"creator_id", "updater_id", "database_id", "metadata", "raw_dump"}
+	set := []string{"metadata = EXCLUDED.metadata", "raw_dump = EXCLUDED.raw_dump"}
+	if common.FeatureFlag(common.FeatureFlagRowLevelSecurity) {
+		rowLevelSecurity := dbSchema.RowLevelSecurity
+		if rowLevelSecurity == nil {
+			rowLevelSecurity = []*db.TableRowLevelSecurity{}
+		}
+		rowLevelSecurityBytes, err := json.Marshal(rowLevelSecurity)
+		if err != nil {
+			return err
+		}
+		args = append(args, rowLevelSecurityBytes)
+		columns = append(columns, "row_level_security")
+		set = append(set, "row_level_security = EXCLUDED.row_level_security")
+	}
+	var values []string
+	for i := range args {
+		values = append(values, fmt.Sprintf("$%d", i+1))
+	}
 
-	query := `
-		INSERT INTO db_schema (
-			creator_id,
-			updater_id,
-			database_id,
-			metadata,
-			raw_dump
-		)
-		VALUES ($1, $2, $3, $4, $5)
+	query := fmt.Sprintf(`
+		INSERT INTO db_schema (%s)
+		VALUES (%s)
 		ON CONFLICT(database_id) DO UPDATE SET
-			metadata = EXCLUDED.metadata,
-			raw_dump = EXCLUDED.raw_dump
+			%s
 		RETURNING metadata, raw_dump
-	`
+	`, strings.Join(columns, ", "), strings.Join(values, ", "), strings.Join(set, ", "))
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	if _, err := tx.ExecContext(ctx, query,
-		updaterID,
-		updaterID,
-		databaseID,
-		metadataBytes,
-		// Convert to string because []byte{} is null which violates db schema constraints.
-		string(dbSchema.Schema),
-	); err != nil {
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
 		return err
 	}
 	if err := tx.Commit(); err != nil {
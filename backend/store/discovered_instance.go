@@ -0,0 +1,283 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// DiscoveredInstanceMessage is the message for an instance discovered from a cloud provider.
+type DiscoveredInstanceMessage struct {
+	ID       int
+	Provider api.CloudProvider
+	// ExternalID is the ARN for AWS, the connection name for GCP and the resource ID for Azure.
+	ExternalID string
+	Name       string
+	Engine     db.Type
+	Host       string
+	Port       string
+	Region     string
+	Labels     map[string]string
+	// EnvironmentUID is the environment suggested by the environment mapping rules.
+	EnvironmentUID *int
+	// InstanceUID is the registered instance with the same host and port.
+	InstanceUID *int
+	Status      api.DiscoveredInstanceStatus
+	LastSeenTs  int64
+}
+
+// FindDiscoveredInstanceMessage is the message to find discovered instances.
+type FindDiscoveredInstanceMessage struct {
+	ID       *int
+	Provider *api.CloudProvider
+	Status   *api.DiscoveredInstanceStatus
+}
+
+// UpdateDiscoveredInstanceMessage is the message to update a discovered instance.
+type UpdateDiscoveredInstanceMessage struct {
+	ID          int
+	Status      *api.DiscoveredInstanceStatus
+	InstanceUID *int
+}
+
+// GetDiscoveredInstance gets a discovered instance.
+func (s *Store) GetDiscoveredInstance(ctx context.Context, find *FindDiscoveredInstanceMessage) (*DiscoveredInstanceMessage, error) {
+	discoveredInstances, err := s.ListDiscoveredInstances(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(discoveredInstances) == 0 {
+		return nil, nil
+	}
+	if len(discoveredInstances) > 1 {
+		return nil, errors.Errorf("found %d discovered instances with filter %+v, expect 1", len(discoveredInstances), find)
+	}
+	return discoveredInstances[0], nil
+}
+
+// ListDiscoveredInstances lists the discovered instances.
+func (s *Store) ListDiscoveredInstances(ctx context.Context, find *FindDiscoveredInstanceMessage) ([]*DiscoveredInstanceMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Provider; v != nil {
+		where, args = append(where, fmt.Sprintf("provider = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			provider,
+			external_id,
+			name,
+			engine,
+			host,
+			port,
+			region,
+			labels,
+			environment_id,
+			instance_id,
+			status,
+			last_seen_ts
+		FROM discovered_instance
+		WHERE %s
+		ORDER BY provider, name`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query discovered instances")
+	}
+	defer rows.Close()
+
+	var discoveredInstances []*DiscoveredInstanceMessage
+	for rows.Next() {
+		discoveredInstance, err := scanDiscoveredInstance(rows)
+		if err != nil {
+			return nil, err
+		}
+		discoveredInstances = append(discoveredInstances, discoveredInstance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return discoveredInstances, nil
+}
+
+// UpsertDiscoveredInstance upserts a discovered instance by the provider and the external ID.
+func (s *Store) UpsertDiscoveredInstance(ctx context.Context, upsert *DiscoveredInstanceMessage) (*DiscoveredInstanceMessage, error) {
+	labels, err := json.Marshal(upsert.Labels)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal labels")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		INSERT INTO discovered_instance (
+			provider,
+			external_id,
+			name,
+			engine,
+			host,
+			port,
+			region,
+			labels,
+			environment_id,
+			instance_id,
+			status,
+			last_seen_ts
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (provider, external_id) DO UPDATE SET
+			name = excluded.name,
+			engine = excluded.engine,
+			host = excluded.host,
+			port = excluded.port,
+			region = excluded.region,
+			labels = excluded.labels,
+			environment_id = excluded.environment_id,
+			instance_id = excluded.instance_id,
+			status = excluded.status,
+			last_seen_ts = excluded.last_seen_ts
+		RETURNING id, provider, external_id, name, engine, host, port, region, labels, environment_id, instance_id, status, last_seen_ts`,
+		upsert.Provider,
+		upsert.ExternalID,
+		upsert.Name,
+		upsert.Engine,
+		upsert.Host,
+		upsert.Port,
+		upsert.Region,
+		labels,
+		upsert.EnvironmentUID,
+		upsert.InstanceUID,
+		upsert.Status,
+		upsert.LastSeenTs,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert discovered instance")
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.Errorf("failed to upsert discovered instance %q", upsert.ExternalID)
+	}
+	discoveredInstance, err := scanDiscoveredInstance(rows)
+	if err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return discoveredInstance, nil
+}
+
+// UpdateDiscoveredInstance updates a discovered instance.
+func (s *Store) UpdateDiscoveredInstance(ctx context.Context, patch *UpdateDiscoveredInstanceMessage) (*DiscoveredInstanceMessage, error) {
+	set, args := []string{}, []any{}
+	if v := patch.Status; v != nil {
+		set, args = append(set, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.InstanceUID; v != nil {
+		set, args = append(set, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if len(set) == 0 {
+		return nil, errors.New("no update field provided")
+	}
+	args = append(args, patch.ID)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`UPDATE discovered_instance SET %s WHERE id = $%d`, strings.Join(set, ", "), len(args)), args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to update discovered instance")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return s.GetDiscoveredInstance(ctx, &FindDiscoveredInstanceMessage{ID: &patch.ID})
+}
+
+// DeleteDiscoveredInstance deletes a discovered instance.
+func (s *Store) DeleteDiscoveredInstance(ctx context.Context, id int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM discovered_instance WHERE id = $1`, id); err != nil {
+		return errors.Wrapf(err, "failed to delete discovered instance")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrapf(err, "failed to commit transaction")
+	}
+	return nil
+}
+
+func scanDiscoveredInstance(rows *sql.Rows) (*DiscoveredInstanceMessage, error) {
+	discoveredInstance := &DiscoveredInstanceMessage{}
+	var labels []byte
+	var environmentUID, instanceUID sql.NullInt32
+	if err := rows.Scan(
+		&discoveredInstance.ID,
+		&discoveredInstance.Provider,
+		&discoveredInstance.ExternalID,
+		&discoveredInstance.Name,
+		&discoveredInstance.Engine,
+		&discoveredInstance.Host,
+		&discoveredInstance.Port,
+		&discoveredInstance.Region,
+		&labels,
+		&environmentUID,
+		&instanceUID,
+		&discoveredInstance.Status,
+		&discoveredInstance.LastSeenTs,
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan discovered instance")
+	}
+	if err := json.Unmarshal(labels, &discoveredInstance.Labels); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal labels")
+	}
+	if environmentUID.Valid {
+		v := int(environmentUID.Int32)
+		discoveredInstance.EnvironmentUID = &v
+	}
+	if instanceUID.Valid {
+		v := int(instanceUID.Int32)
+		discoveredInstance.InstanceUID = &v
+	}
+	return discoveredInstance, nil
+}
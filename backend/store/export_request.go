@@ -0,0 +1,175 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// ExportRequestMessage is the message for the approval of exporting a query result.
+type ExportRequestMessage struct {
+	DatabaseUID    int
+	Status         api.ExportRequestStatus
+	RowCount       int
+	ColumnNameList []string
+	Justification  string
+	ExpireTs       int64
+
+	// Output only
+	UID       int
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+}
+
+// FindExportRequestMessage is the message to find export requests.
+type FindExportRequestMessage struct {
+	UID        *int
+	CreatorID  *int
+	StatusList []api.ExportRequestStatus
+}
+
+// UpdateExportRequestMessage is the message to update the status of an export request.
+type UpdateExportRequestMessage struct {
+	UpdaterID int
+	// OldStatus is the status the request is expected to be in, the request isn't updated otherwise.
+	OldStatus api.ExportRequestStatus
+	Status    api.ExportRequestStatus
+	ExpireTs  *int64
+}
+
+const exportRequestColumns = `
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			status,
+			row_count,
+			column_names,
+			justification,
+			expire_ts`
+
+// CreateExportRequest creates a pending export request.
+func (s *Store) CreateExportRequest(ctx context.Context, create *ExportRequestMessage, creatorID int) (*ExportRequestMessage, error) {
+	request, err := scanExportRequest(s.db.db.QueryRowContext(ctx, `
+		INSERT INTO export_request (
+			creator_id,
+			updater_id,
+			database_id,
+			status,
+			row_count,
+			column_names,
+			justification
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING`+exportRequestColumns,
+		creatorID,
+		creatorID,
+		create.DatabaseUID,
+		api.ExportRequestPending,
+		create.RowCount,
+		pq.Array(create.ColumnNameList),
+		create.Justification,
+	))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create export request")
+	}
+	return request, nil
+}
+
+// ListExportRequests lists the export requests, the latest first.
+func (s *Store) ListExportRequests(ctx context.Context, find *FindExportRequestMessage) ([]*ExportRequestMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.UID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.StatusList; v != nil {
+		list := []string{}
+		for _, status := range v {
+			list = append(list, fmt.Sprintf("$%d", len(args)+1))
+			args = append(args, status)
+		}
+		where = append(where, fmt.Sprintf("status IN (%s)", strings.Join(list, ",")))
+	}
+	rows, err := s.db.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT`+exportRequestColumns+`
+		FROM export_request
+		WHERE %s
+		ORDER BY id DESC`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list export requests")
+	}
+	defer rows.Close()
+
+	var requests []*ExportRequestMessage
+	for rows.Next() {
+		request, err := scanExportRequest(rows)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to scan export request")
+		}
+		requests = append(requests, request)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan export requests")
+	}
+	return requests, nil
+}
+
+// UpdateExportRequest updates the status of an export request. It returns nil if the request isn't in the old status,
+// e.g. it's used by another export in the meantime.
+func (s *Store) UpdateExportRequest(ctx context.Context, uid int, update *UpdateExportRequestMessage) (*ExportRequestMessage, error) {
+	set, args := []string{"updater_id = $1", "status = $2"}, []any{update.UpdaterID, update.Status}
+	if v := update.ExpireTs; v != nil {
+		set, args = append(set, fmt.Sprintf("expire_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, uid, update.OldStatus)
+	request, err := scanExportRequest(s.db.db.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE export_request
+		SET %s
+		WHERE id = $%d AND status = $%d
+		RETURNING`+exportRequestColumns, strings.Join(set, ", "), len(args)-1, len(args)),
+		args...,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to update export request")
+	}
+	return request, nil
+}
+
+func scanExportRequest(row interface{ Scan(...any) error }) (*ExportRequestMessage, error) {
+	var request ExportRequestMessage
+	var columnNames pq.StringArray
+	if err := row.Scan(
+		&request.UID,
+		&request.CreatorID,
+		&request.CreatedTs,
+		&request.UpdaterID,
+		&request.UpdatedTs,
+		&request.DatabaseUID,
+		&request.Status,
+		&request.RowCount,
+		&columnNames,
+		&request.Justification,
+		&request.ExpireTs,
+	); err != nil {
+		return nil, err
+	}
+	request.ColumnNameList = columnNames
+	return &request, nil
+}
@@ -90,13 +90,33 @@ func (s *Store) composeInstance(ctx context.Context, instance *InstanceMessage)
 			Host:       ds.Host,
 			Port:       ds.Port,
 			Options: api.DataSourceOptions{
-				SRV:                    ds.SRV,
-				AuthenticationDatabase: ds.AuthenticationDatabase,
-				SID:                    ds.SID,
-				ServiceName:            ds.ServiceName,
-				SSHHost:                ds.SSHHost,
-				SSHPort:                ds.SSHPort,
-				SSHUser:                ds.SSHUser,
+				SRV:                       ds.SRV,
+				AuthenticationDatabase:    ds.AuthenticationDatabase,
+				SID:                       ds.SID,
+				ServiceName:               ds.ServiceName,
+				SSHHost:                   ds.SSHHost,
+				SSHPort:                   ds.SSHPort,
+				SSHUser:                   ds.SSHUser,
+				SSHJumpHostList:           composeSSHJumpHostList(ds.Proxy.SSHJumpHostList),
+				SOCKS5Proxy:               composeSOCKS5Proxy(ds.Proxy.SOCKS5),
+				AuthenticationType:        ds.AuthenticationType,
+				AWSRegion:                 ds.AWSRegion,
+				AWSAccessKeyID:            ds.AWSAccessKeyID,
+				GCPCloudSQLConnectionName: ds.GCPCloudSQLConnectionName,
+				GCPCloudSQLUsePrivateIP:   ds.GCPCloudSQLUsePrivateIP,
+				AzureTenantID:             ds.AzureTenantID,
+				AzureClientID:             ds.AzureClientID,
+				ExternalSecretReference:   ds.ExternalSecretReference,
+				// Read replica routing related.
+				ReadReplicaRoutingPolicy:     ds.ReadReplicaRoutingPolicy,
+				ReadReplicaFallbackToPrimary: ds.ReadReplicaFallbackToPrimary,
+				// Connection pool related.
+				MaxConnections:               ds.MaxConnections,
+				ConnectionIdleTimeoutSeconds: ds.ConnectionIdleTimeoutSeconds,
+				// Kerberos related.
+				KerberosRealm: ds.KerberosRealm,
+				KerberosKDC:   ds.KerberosKDC,
+				KerberosSPN:   ds.KerberosSPN,
 			},
 			Database: ds.Database,
 		})
@@ -109,6 +129,31 @@ func (s *Store) composeInstance(ctx context.Context, instance *InstanceMessage)
 	return composedInstance, nil
 }
 
+// composeSSHJumpHostList composes the SSH jump hosts without the secrets.
+func composeSSHJumpHostList(jumpHostList []*SSHJumpHost) []api.SSHJumpHost {
+	var result []api.SSHJumpHost
+	for _, jumpHost := range jumpHostList {
+		result = append(result, api.SSHJumpHost{
+			Host: jumpHost.Host,
+			Port: jumpHost.Port,
+			User: jumpHost.User,
+		})
+	}
+	return result
+}
+
+// composeSOCKS5Proxy composes the SOCKS5 proxy without the password.
+func composeSOCKS5Proxy(socks5 *SOCKS5Proxy) *api.SOCKS5Proxy {
+	if socks5 == nil {
+		return nil
+	}
+	return &api.SOCKS5Proxy{
+		Host: socks5.Host,
+		Port: socks5.Port,
+		User: socks5.User,
+	}
+}
+
 // InstanceMessage is the mssage for instance.
 type InstanceMessage struct {
 	ResourceID   string
@@ -57,6 +57,9 @@ type UpdateInstanceChangeHistoryMessage struct {
 	Status              *db.MigrationStatus
 	ExecutionDurationNs *int64
 	Schema              *string
+
+	// RollbackStatement is merged into the payload.
+	RollbackStatement *string
 }
 
 // CreateInstanceChangeHistory creates instance change history in batch.
@@ -426,6 +429,9 @@ func (s *Store) UpdateInstanceChangeHistory(ctx context.Context, update *UpdateI
 	if v := update.Schema; v != nil {
 		set, args = append(set, fmt.Sprintf("schema = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := update.RollbackStatement; v != nil {
+		set, args = append(set, fmt.Sprintf("payload = payload || jsonb_build_object('rollbackStatement', $%d::TEXT)", len(args)+1)), append(args, *v)
+	}
 	if len(set) == 0 {
 		return nil
 	}
@@ -511,6 +517,39 @@ func (s *Store) CreatePendingInstanceChangeHistory(ctx context.Context, prevSche
 	return list[0].ID, nil
 }
 
+// CreateImportedInstanceChangeHistory creates the change history imported from other migration tools.
+// The change history is appended after the existing sequences of the database in the given order.
+func (s *Store) CreateImportedInstanceChangeHistory(ctx context.Context, instanceID *int, databaseID *int, creates []*InstanceChangeHistoryMessage) ([]*InstanceChangeHistoryMessage, error) {
+	if len(creates) == 0 {
+		return nil, nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	nextSequence, err := s.getNextInstanceChangeHistorySequence(ctx, tx, instanceID, databaseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, create := range creates {
+		create.InstanceID = instanceID
+		create.DatabaseID = databaseID
+		create.Sequence = nextSequence
+		nextSequence++
+	}
+	list, err := s.createInstanceChangeHistoryImpl(ctx, tx, creates...)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
 // ListInstanceHavingInstanceChangeHistory finds the instance id lists that have instance change history.
 func (s *Store) ListInstanceHavingInstanceChangeHistory(ctx context.Context) ([]int, error) {
 	query := `
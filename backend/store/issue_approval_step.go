@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// IssueApprovalStepMessage is the message for the state of the pending approval step of an issue.
+type IssueApprovalStepMessage struct {
+	IssueUID  int
+	RiskLevel int64
+	// Step is the index of the pending step in the approval flow, -1 before the approval flow is found.
+	Step           int
+	StartedTs      int64
+	EscalatedTs    int64
+	ExternalStatus api.ApprovalStepExternalStatus
+	ExternalToken  string
+}
+
+// FindIssueApprovalStepMessage is the message to find the issue approval step.
+type FindIssueApprovalStepMessage struct {
+	IssueUID       *int
+	ExternalToken  *string
+	ExternalStatus *api.ApprovalStepExternalStatus
+}
+
+// GetIssueApprovalStep gets the state of the pending approval step of an issue.
+func (s *Store) GetIssueApprovalStep(ctx context.Context, find *FindIssueApprovalStepMessage) (*IssueApprovalStepMessage, error) {
+	steps, err := s.ListIssueApprovalSteps(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return nil, nil
+	}
+	if len(steps) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: errors.Errorf("found %d issue approval steps with filter %+v, expect 1", len(steps), find)}
+	}
+	return steps[0], nil
+}
+
+// ListIssueApprovalSteps lists the states of the pending approval steps of the issues.
+func (s *Store) ListIssueApprovalSteps(ctx context.Context, find *FindIssueApprovalStepMessage) ([]*IssueApprovalStepMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.IssueUID; v != nil {
+		where, args = append(where, fmt.Sprintf("issue_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ExternalToken; v != nil {
+		where, args = append(where, fmt.Sprintf("external_token = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ExternalStatus; v != nil {
+		where, args = append(where, fmt.Sprintf("external_status = $%d", len(args)+1)), append(args, *v)
+	}
+	rows, err := s.db.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			issue_id,
+			risk_level,
+			step,
+			started_ts,
+			escalated_ts,
+			external_status,
+			external_token
+		FROM issue_approval_step
+		WHERE %s
+		ORDER BY issue_id`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list issue approval steps")
+	}
+	defer rows.Close()
+
+	var steps []*IssueApprovalStepMessage
+	for rows.Next() {
+		var step IssueApprovalStepMessage
+		if err := rows.Scan(
+			&step.IssueUID,
+			&step.RiskLevel,
+			&step.Step,
+			&step.StartedTs,
+			&step.EscalatedTs,
+			&step.ExternalStatus,
+			&step.ExternalToken,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan issue approval step")
+		}
+		steps = append(steps, &step)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to list issue approval steps")
+	}
+	return steps, nil
+}
+
+// UpsertIssueApprovalStep creates or replaces the state of the pending approval step of an issue.
+func (s *Store) UpsertIssueApprovalStep(ctx context.Context, upsert *IssueApprovalStepMessage) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO issue_approval_step (
+			issue_id,
+			risk_level,
+			step,
+			started_ts,
+			escalated_ts,
+			external_status,
+			external_token
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (issue_id) DO UPDATE SET
+			risk_level = EXCLUDED.risk_level,
+			step = EXCLUDED.step,
+			started_ts = EXCLUDED.started_ts,
+			escalated_ts = EXCLUDED.escalated_ts,
+			external_status = EXCLUDED.external_status,
+			external_token = EXCLUDED.external_token`,
+		upsert.IssueUID,
+		upsert.RiskLevel,
+		upsert.Step,
+		upsert.StartedTs,
+		upsert.EscalatedTs,
+		upsert.ExternalStatus,
+		upsert.ExternalToken,
+	); err != nil {
+		return errors.Wrapf(err, "failed to upsert issue approval step")
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// NotebookCellResultMessage is the message for the result of the latest run of a notebook cell.
+type NotebookCellResultMessage struct {
+	CreatorID       int
+	CreatedTs       int64
+	SheetUID        int
+	CellID          string
+	ParameterValues map[string]string
+	ColumnNames     []string
+	ColumnTypeNames []string
+	Rows            [][]any
+	Truncated       bool
+	Error           string
+}
+
+// notebookCellResultPayload is the result column of the notebook_cell_result table.
+type notebookCellResultPayload struct {
+	ColumnNames     []string `json:"columnNames"`
+	ColumnTypeNames []string `json:"columnTypeNames"`
+	Rows            [][]any  `json:"rows"`
+	Truncated       bool     `json:"truncated"`
+}
+
+// UpsertNotebookCellResult replaces the result of the notebook cell.
+func (s *Store) UpsertNotebookCellResult(ctx context.Context, upsert *NotebookCellResultMessage) (*NotebookCellResultMessage, error) {
+	parameterValues, err := json.Marshal(upsert.ParameterValues)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal parameter values")
+	}
+	result, err := json.Marshal(&notebookCellResultPayload{
+		ColumnNames:     upsert.ColumnNames,
+		ColumnTypeNames: upsert.ColumnTypeNames,
+		Rows:            upsert.Rows,
+		Truncated:       upsert.Truncated,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal result")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	cellResult := *upsert
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO notebook_cell_result (
+			creator_id,
+			sheet_id,
+			cell_id,
+			parameter_values,
+			result,
+			error
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (sheet_id, cell_id) DO UPDATE SET
+			creator_id = EXCLUDED.creator_id,
+			created_ts = extract(epoch from now()),
+			parameter_values = EXCLUDED.parameter_values,
+			result = EXCLUDED.result,
+			error = EXCLUDED.error
+		RETURNING created_ts`,
+		upsert.CreatorID,
+		upsert.SheetUID,
+		upsert.CellID,
+		parameterValues,
+		result,
+		upsert.Error,
+	).Scan(&cellResult.CreatedTs); err != nil {
+		return nil, errors.Wrapf(err, "failed to upsert notebook cell result")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return &cellResult, nil
+}
+
+// ListNotebookCellResults lists the results of the cells of the notebook sheet.
+func (s *Store) ListNotebookCellResults(ctx context.Context, sheetUID int) ([]*NotebookCellResultMessage, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			creator_id,
+			created_ts,
+			sheet_id,
+			cell_id,
+			parameter_values,
+			result,
+			error
+		FROM notebook_cell_result
+		WHERE sheet_id = $1
+		ORDER BY id`,
+		sheetUID,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query notebook cell results")
+	}
+	defer rows.Close()
+
+	var cellResults []*NotebookCellResultMessage
+	for rows.Next() {
+		cellResult := &NotebookCellResultMessage{}
+		var parameterValues, result []byte
+		if err := rows.Scan(
+			&cellResult.CreatorID,
+			&cellResult.CreatedTs,
+			&cellResult.SheetUID,
+			&cellResult.CellID,
+			&parameterValues,
+			&result,
+			&cellResult.Error,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan notebook cell result")
+		}
+		if err := json.Unmarshal(parameterValues, &cellResult.ParameterValues); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal parameter values")
+		}
+		var payload notebookCellResultPayload
+		if err := json.Unmarshal(result, &payload); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal result")
+		}
+		cellResult.ColumnNames = payload.ColumnNames
+		cellResult.ColumnTypeNames = payload.ColumnTypeNames
+		cellResult.Rows = payload.Rows
+		cellResult.Truncated = payload.Truncated
+		cellResults = append(cellResults, cellResult)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return cellResults, nil
+}
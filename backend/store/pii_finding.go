@@ -0,0 +1,197 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// PIIFindingMessage is the message for a column detected to contain the personal data.
+type PIIFindingMessage struct {
+	ID          int
+	DatabaseUID int
+	// SchemaName is empty for the engines without schema, such as MySQL.
+	SchemaName     string
+	TableName      string
+	ColumnName     string
+	Classification api.PIIClassification
+	MaskType       api.SensitiveDataMaskType
+	NameMatched    bool
+	SampleCount    int
+	MatchCount     int
+	Status         api.PIIFindingStatus
+	LastScannedTs  int64
+}
+
+// FindPIIFindingMessage is the message to find PII findings.
+type FindPIIFindingMessage struct {
+	ID          *int
+	DatabaseUID *int
+	Status      *api.PIIFindingStatus
+}
+
+// GetPIIFinding gets a PII finding.
+func (s *Store) GetPIIFinding(ctx context.Context, find *FindPIIFindingMessage) (*PIIFindingMessage, error) {
+	findings, err := s.ListPIIFindings(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(findings) == 0 {
+		return nil, nil
+	}
+	if len(findings) > 1 {
+		return nil, errors.Errorf("found %d PII findings with filter %+v, expect 1", len(findings), find)
+	}
+	return findings[0], nil
+}
+
+// ListPIIFindings lists the PII findings.
+func (s *Store) ListPIIFindings(ctx context.Context, find *FindPIIFindingMessage) ([]*PIIFindingMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseUID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			database_id,
+			schema_name,
+			table_name,
+			column_name,
+			classification,
+			mask_type,
+			name_matched,
+			sample_count,
+			match_count,
+			status,
+			last_scanned_ts
+		FROM pii_finding
+		WHERE %s
+		ORDER BY database_id, schema_name, table_name, column_name`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query PII findings")
+	}
+	defer rows.Close()
+
+	var findings []*PIIFindingMessage
+	for rows.Next() {
+		finding := &PIIFindingMessage{}
+		if err := rows.Scan(
+			&finding.ID,
+			&finding.DatabaseUID,
+			&finding.SchemaName,
+			&finding.TableName,
+			&finding.ColumnName,
+			&finding.Classification,
+			&finding.MaskType,
+			&finding.NameMatched,
+			&finding.SampleCount,
+			&finding.MatchCount,
+			&finding.Status,
+			&finding.LastScannedTs,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan PII finding")
+		}
+		findings = append(findings, finding)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return findings, nil
+}
+
+// SetPIIFindings replaces the PII findings of the database scanned at the time.
+// The reviewed status of the findings is kept unless the classification changes, so a dismissed column isn't proposed
+// again. The proposed findings not detected any more are removed, the reviewed ones are kept for the record.
+func (s *Store) SetPIIFindings(ctx context.Context, databaseUID int, findings []*PIIFindingMessage, scannedTs int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	for _, finding := range findings {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO pii_finding (
+				database_id,
+				schema_name,
+				table_name,
+				column_name,
+				classification,
+				mask_type,
+				name_matched,
+				sample_count,
+				match_count,
+				status,
+				last_scanned_ts
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (database_id, schema_name, table_name, column_name) DO UPDATE SET
+				status = CASE WHEN pii_finding.classification = excluded.classification THEN pii_finding.status ELSE excluded.status END,
+				classification = excluded.classification,
+				mask_type = excluded.mask_type,
+				name_matched = excluded.name_matched,
+				sample_count = excluded.sample_count,
+				match_count = excluded.match_count,
+				last_scanned_ts = excluded.last_scanned_ts`,
+			databaseUID,
+			finding.SchemaName,
+			finding.TableName,
+			finding.ColumnName,
+			finding.Classification,
+			finding.MaskType,
+			finding.NameMatched,
+			finding.SampleCount,
+			finding.MatchCount,
+			api.PIIFindingProposed,
+			scannedTs,
+		); err != nil {
+			return errors.Wrapf(err, "failed to upsert PII finding of column %q", finding.ColumnName)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM pii_finding
+		WHERE database_id = $1 AND status = $2 AND last_scanned_ts < $3`,
+		databaseUID,
+		api.PIIFindingProposed,
+		scannedTs,
+	); err != nil {
+		return errors.Wrapf(err, "failed to delete stale PII findings")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrapf(err, "failed to commit transaction")
+	}
+	return nil
+}
+
+// UpdatePIIFindingStatus updates the status of a PII finding.
+func (s *Store) UpdatePIIFindingStatus(ctx context.Context, id int, status api.PIIFindingStatus) (*PIIFindingMessage, error) {
+	if _, err := s.db.db.ExecContext(ctx, `UPDATE pii_finding SET status = $1 WHERE id = $2`, status, id); err != nil {
+		return nil, errors.Wrapf(err, "failed to update PII finding")
+	}
+	return s.GetPIIFinding(ctx, &FindPIIFindingMessage{ID: &id})
+}
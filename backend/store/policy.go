@@ -75,6 +75,37 @@ func (s *Store) GetSQLReviewPolicy(ctx context.Context, environmentID int) (*adv
 	return api.UnmarshalSQLReviewPolicy(policy.Payload)
 }
 
+// GetSQLReviewPolicyForProject will get the SQL review policy for an environment with the overrides of the project.
+func (s *Store) GetSQLReviewPolicyForProject(ctx context.Context, environmentID int, projectID int) (*advisor.SQLReviewPolicy, error) {
+	policy, err := s.GetSQLReviewPolicy(ctx, environmentID)
+	if err != nil {
+		return nil, err
+	}
+	override, err := s.GetSQLReviewOverridePolicy(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return policy.Override(override.PinnedVersion, override.RuleList), nil
+}
+
+// GetSQLReviewOverridePolicy will get the SQL review override policy for a project.
+func (s *Store) GetSQLReviewOverridePolicy(ctx context.Context, projectID int) (*api.SQLReviewOverridePolicy, error) {
+	resourceType := api.PolicyResourceTypeProject
+	pType := api.PolicyTypeSQLReviewOverride
+	policy, err := s.GetPolicyV2(ctx, &FindPolicyMessage{
+		ResourceType: &resourceType,
+		ResourceUID:  &projectID,
+		Type:         &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil || !policy.Enforce {
+		return &api.SQLReviewOverridePolicy{}, nil
+	}
+	return api.UnmarshalSQLReviewOverridePolicy(policy.Payload)
+}
+
 // GetSensitiveDataPolicy will get the sensitive data policy for database ID.
 func (s *Store) GetSensitiveDataPolicy(ctx context.Context, databaseID int) (*api.SensitiveDataPolicy, error) {
 	resourceType := api.PolicyResourceTypeDatabase
@@ -138,6 +169,117 @@ func (s *Store) GetSlowQueryPolicy(ctx context.Context, resourceType api.PolicyR
 	return api.UnmarshalSlowQueryPolicy(policy.Payload)
 }
 
+// GetAffectedRowsApprovalPolicy will get the affected rows approval policy for an environment.
+func (s *Store) GetAffectedRowsApprovalPolicy(ctx context.Context, environmentID int) (*api.AffectedRowsApprovalPolicy, error) {
+	resourceType := api.PolicyResourceTypeEnvironment
+	pType := api.PolicyTypeAffectedRowsApproval
+	policy, err := s.GetPolicyV2(ctx, &FindPolicyMessage{
+		ResourceType: &resourceType,
+		ResourceUID:  &environmentID,
+		Type:         &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return &api.AffectedRowsApprovalPolicy{}, nil
+	}
+	return api.UnmarshalAffectedRowsApprovalPolicy(policy.Payload)
+}
+
+// GetOnlineMigrationPolicy will get the online migration policy for an environment.
+func (s *Store) GetOnlineMigrationPolicy(ctx context.Context, environmentID int) (*api.OnlineMigrationPolicy, error) {
+	resourceType := api.PolicyResourceTypeEnvironment
+	pType := api.PolicyTypeOnlineMigration
+	policy, err := s.GetPolicyV2(ctx, &FindPolicyMessage{
+		ResourceType: &resourceType,
+		ResourceUID:  &environmentID,
+		Type:         &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return &api.OnlineMigrationPolicy{}, nil
+	}
+	return api.UnmarshalOnlineMigrationPolicy(policy.Payload)
+}
+
+// GetQueryLimitPolicy will get the query limit policy for an environment.
+func (s *Store) GetQueryLimitPolicy(ctx context.Context, environmentID int) (*api.QueryLimitPolicy, error) {
+	resourceType := api.PolicyResourceTypeEnvironment
+	pType := api.PolicyTypeQueryLimit
+	policy, err := s.GetPolicyV2(ctx, &FindPolicyMessage{
+		ResourceType: &resourceType,
+		ResourceUID:  &environmentID,
+		Type:         &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		return &api.QueryLimitPolicy{}, nil
+	}
+	return api.UnmarshalQueryLimitPolicy(policy.Payload)
+}
+
+// GetAutoApprovalPolicy will get the workspace auto-approval policy.
+func (s *Store) GetAutoApprovalPolicy(ctx context.Context) (*api.AutoApprovalPolicy, error) {
+	resourceType := api.PolicyResourceTypeWorkspace
+	resourceUID := 0
+	pType := api.PolicyTypeAutoApproval
+	policy, err := s.GetPolicyV2(ctx, &FindPolicyMessage{
+		ResourceType: &resourceType,
+		ResourceUID:  &resourceUID,
+		Type:         &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil || !policy.Enforce {
+		return &api.AutoApprovalPolicy{}, nil
+	}
+	return api.UnmarshalAutoApprovalPolicy(policy.Payload)
+}
+
+// GetExportPolicy will get the workspace export policy.
+func (s *Store) GetExportPolicy(ctx context.Context) (*api.ExportPolicy, error) {
+	resourceType := api.PolicyResourceTypeWorkspace
+	resourceUID := 0
+	pType := api.PolicyTypeExport
+	policy, err := s.GetPolicyV2(ctx, &FindPolicyMessage{
+		ResourceType: &resourceType,
+		ResourceUID:  &resourceUID,
+		Type:         &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil || !policy.Enforce {
+		return &api.ExportPolicy{Watermark: api.ExportWatermarkNone}, nil
+	}
+	return api.UnmarshalExportPolicy(policy.Payload)
+}
+
+// GetDLPPolicy will get the workspace DLP policy.
+func (s *Store) GetDLPPolicy(ctx context.Context) (*api.DLPPolicy, error) {
+	resourceType := api.PolicyResourceTypeWorkspace
+	resourceUID := 0
+	pType := api.PolicyTypeDLP
+	policy, err := s.GetPolicyV2(ctx, &FindPolicyMessage{
+		ResourceType: &resourceType,
+		ResourceUID:  &resourceUID,
+		Type:         &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil || !policy.Enforce {
+		return &api.DLPPolicy{Action: api.DLPActionBlock}, nil
+	}
+	return api.UnmarshalDLPPolicy(policy.Payload)
+}
+
 // PolicyMessage is the mssage for policy.
 type PolicyMessage struct {
 	ResourceUID       int
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -23,6 +24,8 @@ type ProjectWebhookMessage struct {
 	URL string
 	// ActivityList is the list of activities that the webhook is interested in.
 	ActivityList []string
+	// PayloadTemplates is the Go templates of the JSON payloads keyed by the activity type.
+	PayloadTemplates map[string]string
 	// Output only fields.
 	//
 	// ID is the unique identifier of the project webhook.
@@ -32,7 +35,7 @@ type ProjectWebhookMessage struct {
 
 // ToAPIProjectWebhook converts a ProjectWebhookMessage to an api.ProjectWebhook.
 func (p *ProjectWebhookMessage) ToAPIProjectWebhook() *api.ProjectWebhook {
-	return &api.ProjectWebhook{
+	webhook := &api.ProjectWebhook{
 		ID:           p.ID,
 		ProjectID:    p.ProjectID,
 		Type:         p.Type,
@@ -40,6 +43,12 @@ func (p *ProjectWebhookMessage) ToAPIProjectWebhook() *api.ProjectWebhook {
 		ActivityList: p.ActivityList,
 		Name:         p.Title,
 	}
+	if len(p.PayloadTemplates) > 0 {
+		// Marshaling the string map never fails.
+		payloadTemplates, _ := json.Marshal(p.PayloadTemplates)
+		webhook.PayloadTemplates = string(payloadTemplates)
+	}
+	return webhook
 }
 
 // UpdateProjectWebhookMessage is the message for updating project webhooks.
@@ -50,6 +59,8 @@ type UpdateProjectWebhookMessage struct {
 	URL *string
 	// ActivityList is the list of activities that the webhook is interested in.
 	ActivityList []string
+	// PayloadTemplates replaces the payload templates if not nil.
+	PayloadTemplates map[string]string
 }
 
 // FindProjectWebhookMessage is the message for finding project webhooks,
@@ -63,43 +74,35 @@ type FindProjectWebhookMessage struct {
 
 // CreateProjectWebhookV2 creates an instance of ProjectWebhook.
 func (s *Store) CreateProjectWebhookV2(ctx context.Context, principalUID int, projectUID int, projectResourceID string, create *ProjectWebhookMessage) (*ProjectWebhookMessage, error) {
-	query := `
-		INSERT INTO project_webhook (
-			creator_id,
-			updater_id,
-			project_id,
-			type,
-			name,
-			url,
-			activity_list
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, project_id, type, name, url, activity_list
-	`
+	columns := []string{"creator_id", "updater_id", "project_id", "type", "name", "url", "activity_list"}
+	args := []any{principalUID, principalUID, projectUID, create.Type, create.Title, create.URL, create.ActivityList}
+	if common.FeatureFlag(common.FeatureFlagWebhookPayloadTemplate) {
+		createPayloadTemplates, err := marshalPayloadTemplates(create.PayloadTemplates)
+		if err != nil {
+			return nil, err
+		}
+		columns, args = append(columns, "payload_templates"), append(args, createPayloadTemplates)
+	}
+	var values []string
+	for i := range columns {
+		values = append(values, fmt.Sprintf("$%d", i+1))
+	}
 	var projectWebhook ProjectWebhookMessage
 	var txtArray pgtype.TextArray
+	var payloadTemplates []byte
+	returning, dest := getProjectWebhookReturning(&projectWebhook, &txtArray, &payloadTemplates)
+	query := fmt.Sprintf(`
+		INSERT INTO project_webhook (%s)
+		VALUES (%s)
+		RETURNING %s
+	`, strings.Join(columns, ", "), strings.Join(values, ", "), strings.Join(returning, ", "))
 
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to begin transaction")
 	}
 
-	if err := tx.QueryRowContext(ctx, query,
-		principalUID,
-		principalUID,
-		projectUID,
-		create.Type,
-		create.Title,
-		create.URL,
-		create.ActivityList,
-	).Scan(
-		&projectWebhook.ID,
-		&projectWebhook.ProjectID,
-		&projectWebhook.Type,
-		&projectWebhook.Title,
-		&projectWebhook.URL,
-		&txtArray,
-	); err != nil {
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(dest...); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
 		}
@@ -108,6 +111,11 @@ func (s *Store) CreateProjectWebhookV2(ctx context.Context, principalUID int, pr
 	if err := txtArray.AssignTo(&projectWebhook.ActivityList); err != nil {
 		return nil, err
 	}
+	if payloadTemplates != nil {
+		if err := json.Unmarshal(payloadTemplates, &projectWebhook.PayloadTemplates); err != nil {
+			return nil, err
+		}
+	}
 	if err := tx.Commit(); err != nil {
 		return nil, errors.Wrapf(err, "failed to commit transaction")
 	}
@@ -178,27 +186,29 @@ func (s *Store) UpdateProjectWebhookV2(ctx context.Context, principalUID int, pr
 	if v := update.ActivityList; v != nil {
 		set, args = append(set, fmt.Sprintf("activity_list = $%d", len(args)+1)), append(args, v)
 	}
+	if v := update.PayloadTemplates; v != nil && common.FeatureFlag(common.FeatureFlagWebhookPayloadTemplate) {
+		payloadTemplates, err := marshalPayloadTemplates(v)
+		if err != nil {
+			return nil, err
+		}
+		set, args = append(set, fmt.Sprintf("payload_templates = $%d", len(args)+1)), append(args, payloadTemplates)
+	}
 
 	args = append(args, projectWebhookID)
 
 	var projectWebhook ProjectWebhookMessage
 	var txtArray pgtype.TextArray
+	var payloadTemplates []byte
+	returning, dest := getProjectWebhookReturning(&projectWebhook, &txtArray, &payloadTemplates)
 	// Execute update query with RETURNING.
 	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`
 	UPDATE project_webhook
 	SET `+strings.Join(set, ", ")+`
 	WHERE id = $%d
-	RETURNING id, project_id, type, name, url, activity_list
+	RETURNING `+strings.Join(returning, ", ")+`
 `, len(args)),
 		args...,
-	).Scan(
-		&projectWebhook.ID,
-		&projectWebhook.ProjectID,
-		&projectWebhook.Type,
-		&projectWebhook.Title,
-		&projectWebhook.URL,
-		&txtArray,
-	); err != nil {
+	).Scan(dest...); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("project hook ID not found: %d", projectWebhookID)}
 		}
@@ -207,6 +217,11 @@ func (s *Store) UpdateProjectWebhookV2(ctx context.Context, principalUID int, pr
 	if err := txtArray.AssignTo(&projectWebhook.ActivityList); err != nil {
 		return nil, err
 	}
+	if payloadTemplates != nil {
+		if err := json.Unmarshal(payloadTemplates, &projectWebhook.PayloadTemplates); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, errors.Wrapf(err, "failed to commit transaction")
@@ -250,13 +265,12 @@ func (*Store) findProjectWebhookImplV2(ctx context.Context, tx *Tx, find *FindPr
 		where, args = append(where, fmt.Sprintf("url = $%d", len(args)+1)), append(args, *v)
 	}
 
+	columns := []string{"id", "type", "name", "url", "activity_list"}
+	if common.FeatureFlag(common.FeatureFlagWebhookPayloadTemplate) {
+		columns = append(columns, "payload_templates")
+	}
 	rows, err := tx.QueryContext(ctx, `
-		SELECT
-			id,
-			type,
-			name,
-			url,
-			activity_list
+		SELECT `+strings.Join(columns, ", ")+`
 		FROM project_webhook
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -270,20 +284,30 @@ func (*Store) findProjectWebhookImplV2(ctx context.Context, tx *Tx, find *FindPr
 	for rows.Next() {
 		var projectWebhook ProjectWebhookMessage
 		var txtArray pgtype.TextArray
+		var payloadTemplates []byte
 
-		if err := rows.Scan(
+		dest := []any{
 			&projectWebhook.ID,
 			&projectWebhook.Type,
 			&projectWebhook.Title,
 			&projectWebhook.URL,
 			&txtArray,
-		); err != nil {
+		}
+		if common.FeatureFlag(common.FeatureFlagWebhookPayloadTemplate) {
+			dest = append(dest, &payloadTemplates)
+		}
+		if err := rows.Scan(dest...); err != nil {
 			return nil, err
 		}
 
 		if err := txtArray.AssignTo(&projectWebhook.ActivityList); err != nil {
 			return nil, err
 		}
+		if payloadTemplates != nil {
+			if err := json.Unmarshal(payloadTemplates, &projectWebhook.PayloadTemplates); err != nil {
+				return nil, err
+			}
+		}
 
 		if v := find.ActivityType; v != nil {
 			for _, activity := range projectWebhook.ActivityList {
@@ -302,3 +326,32 @@ func (*Store) findProjectWebhookImplV2(ctx context.Context, tx *Tx, find *FindPr
 
 	return projectWebhooks, nil
 }
+
+// getProjectWebhookReturning returns the columns returned by the project webhook mutations and the fields to scan them
+// into. The payload templates are left out unless the feature is enabled as its schema hasn't been applied to prod yet.
+func getProjectWebhookReturning(projectWebhook *ProjectWebhookMessage, txtArray *pgtype.TextArray, payloadTemplates *[]byte) ([]string, []any) {
+	columns := []string{"id", "project_id", "type", "name", "url", "activity_list"}
+	dest := []any{
+		&projectWebhook.ID,
+		&projectWebhook.ProjectID,
+		&projectWebhook.Type,
+		&projectWebhook.Title,
+		&projectWebhook.URL,
+		txtArray,
+	}
+	if common.FeatureFlag(common.FeatureFlagWebhookPayloadTemplate) {
+		columns, dest = append(columns, "payload_templates"), append(dest, payloadTemplates)
+	}
+	return columns, dest
+}
+
+func marshalPayloadTemplates(payloadTemplates map[string]string) (string, error) {
+	if payloadTemplates == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(payloadTemplates)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal payload templates")
+	}
+	return string(b), nil
+}
@@ -0,0 +1,185 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// QueryHistoryMessage is the message for a statement executed in the SQL editor.
+type QueryHistoryMessage struct {
+	ID           int
+	CreatorID    int
+	CreatedTs    int64
+	InstanceUID  int
+	DatabaseUID  int
+	DatabaseName string
+	Statement    string
+	DurationNs   int64
+	Error        string
+}
+
+// FindQueryHistoryMessage is the message to find the query histories.
+type FindQueryHistoryMessage struct {
+	CreatorID    *int
+	InstanceUID  *int
+	DatabaseName *string
+	// Query is the full-text search of the statements, each word matches the words in the statement with the prefix.
+	Query         string
+	CreatedTsFrom *int64
+	CreatedTsTo   *int64
+	Limit         *int
+	Offset        *int
+}
+
+// queryHistoryWordRegexp matches the words in the full-text search, which are the same as the ones in the statements
+// tokenized by the simple configuration.
+var queryHistoryWordRegexp = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// CreateQueryHistory creates a query history.
+func (s *Store) CreateQueryHistory(ctx context.Context, create *QueryHistoryMessage) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO query_history (
+			creator_id,
+			instance_id,
+			database_id,
+			database_name,
+			statement,
+			duration_ns,
+			error
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		create.CreatorID,
+		create.InstanceUID,
+		create.DatabaseUID,
+		create.DatabaseName,
+		create.Statement,
+		create.DurationNs,
+		create.Error,
+	); err != nil {
+		return errors.Wrapf(err, "failed to create query history")
+	}
+	return nil
+}
+
+// ListQueryHistories lists the query histories from the latest.
+func (s *Store) ListQueryHistories(ctx context.Context, find *FindQueryHistoryMessage) ([]*QueryHistoryMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceUID; v != nil {
+		where, args = append(where, fmt.Sprintf("instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseName; v != nil {
+		where, args = append(where, fmt.Sprintf("database_name = $%d", len(args)+1)), append(args, *v)
+	}
+	if tsQuery := getQueryHistoryTSQuery(find.Query); tsQuery != "" {
+		where, args = append(where, fmt.Sprintf("to_tsvector('simple', statement) @@ to_tsquery('simple', $%d)", len(args)+1)), append(args, tsQuery)
+	}
+	if v := find.CreatedTsFrom; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts >= $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatedTsTo; v != nil {
+		where, args = append(where, fmt.Sprintf("created_ts < $%d", len(args)+1)), append(args, *v)
+	}
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			instance_id,
+			database_id,
+			database_name,
+			statement,
+			duration_ns,
+			error
+		FROM query_history
+		WHERE %s
+		ORDER BY id DESC`, strings.Join(where, " AND "))
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+	if v := find.Offset; v != nil {
+		query += fmt.Sprintf(" OFFSET %d", *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query query histories")
+	}
+	defer rows.Close()
+
+	var queryHistories []*QueryHistoryMessage
+	for rows.Next() {
+		queryHistory := &QueryHistoryMessage{}
+		if err := rows.Scan(
+			&queryHistory.ID,
+			&queryHistory.CreatorID,
+			&queryHistory.CreatedTs,
+			&queryHistory.InstanceUID,
+			&queryHistory.DatabaseUID,
+			&queryHistory.DatabaseName,
+			&queryHistory.Statement,
+			&queryHistory.DurationNs,
+			&queryHistory.Error,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan query history")
+		}
+		queryHistories = append(queryHistories, queryHistory)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return queryHistories, nil
+}
+
+// DeleteQueryHistoriesBefore deletes the query histories created before the timestamp, and returns the number deleted.
+func (s *Store) DeleteQueryHistoriesBefore(ctx context.Context, createdTs int64) (int64, error) {
+	result, err := s.db.db.ExecContext(ctx, `DELETE FROM query_history WHERE created_ts < $1`, createdTs)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to delete query histories")
+	}
+	return result.RowsAffected()
+}
+
+// DeleteQueryHistoriesBeyondCountPerUser deletes the query histories of each user except the latest count ones, and
+// returns the number deleted.
+func (s *Store) DeleteQueryHistoriesBeyondCountPerUser(ctx context.Context, count int) (int64, error) {
+	result, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM query_history
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id, row_number() OVER (PARTITION BY creator_id ORDER BY id DESC) AS rank
+				FROM query_history
+			) ranked
+			WHERE rank > $1
+		)`, count)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to delete query histories")
+	}
+	return result.RowsAffected()
+}
+
+// getQueryHistoryTSQuery converts the full-text search into the tsquery, which matches the statements having all the
+// words as the prefixes of their words. The words are sanitized, so the search can't inject the tsquery operators.
+func getQueryHistoryTSQuery(query string) string {
+	var terms []string
+	for _, word := range queryHistoryWordRegexp.FindAllString(strings.ToLower(query), -1) {
+		terms = append(terms, word+":*")
+	}
+	return strings.Join(terms, " & ")
+}
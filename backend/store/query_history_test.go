@@ -0,0 +1,25 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetQueryHistoryTSQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "", want: ""},
+		{query: "  ", want: ""},
+		{query: "user_orders", want: "user:* & orders:*"},
+		{query: "SELECT * FROM Orders", want: "select:* & from:* & orders:*"},
+		// The tsquery operators are dropped.
+		{query: "a | !b & (c:*)", want: "a:* & b:* & c:*"},
+		{query: "用户 订单", want: "用户:* & 订单:*"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, getQueryHistoryTSQuery(test.query), test.query)
+	}
+}
@@ -22,23 +22,29 @@ type repositoryRaw struct {
 	ProjectID int
 
 	// Domain specific fields
-	Name               string
-	FullPath           string
-	WebURL             string
-	BranchFilter       string
-	BaseDirectory      string
-	FilePathTemplate   string
-	SchemaPathTemplate string
-	SheetPathTemplate  string
-	EnableSQLReviewCI  bool
-	ExternalID         string
-	ExternalWebhookID  string
-	WebhookURLHost     string
-	WebhookEndpointID  string
-	WebhookSecretToken string
-	AccessToken        string
-	ExpiresTs          int64
-	RefreshToken       string
+	Name                 string
+	FullPath             string
+	WebURL               string
+	BranchFilter         string
+	BaseDirectory        string
+	FilePathTemplate     string
+	SchemaPathTemplate   string
+	SheetPathTemplate    string
+	EnableSQLReviewCI    bool
+	ReleaseTagFilter     string
+	RoutingRules         string
+	EnableRollbackPR     bool
+	SchemaWriteBackPR    bool
+	MetadataPathTemplate string
+	BranchRules          string
+	ExternalID           string
+	ExternalWebhookID    string
+	WebhookURLHost       string
+	WebhookEndpointID    string
+	WebhookSecretToken   string
+	AccessToken          string
+	ExpiresTs            int64
+	RefreshToken         string
 }
 
 // toRepository creates an instance of Repository based on the repositoryRaw.
@@ -50,23 +56,29 @@ func (raw *repositoryRaw) toRepository() *api.Repository {
 		VCSID:     raw.VCSID,
 		ProjectID: raw.ProjectID,
 
-		Name:               raw.Name,
-		FullPath:           raw.FullPath,
-		WebURL:             raw.WebURL,
-		BranchFilter:       raw.BranchFilter,
-		BaseDirectory:      raw.BaseDirectory,
-		FilePathTemplate:   raw.FilePathTemplate,
-		SchemaPathTemplate: raw.SchemaPathTemplate,
-		SheetPathTemplate:  raw.SheetPathTemplate,
-		EnableSQLReviewCI:  raw.EnableSQLReviewCI,
-		ExternalID:         raw.ExternalID,
-		ExternalWebhookID:  raw.ExternalWebhookID,
-		WebhookURLHost:     raw.WebhookURLHost,
-		WebhookEndpointID:  raw.WebhookEndpointID,
-		WebhookSecretToken: raw.WebhookSecretToken,
-		AccessToken:        raw.AccessToken,
-		ExpiresTs:          raw.ExpiresTs,
-		RefreshToken:       raw.RefreshToken,
+		Name:                 raw.Name,
+		FullPath:             raw.FullPath,
+		WebURL:               raw.WebURL,
+		BranchFilter:         raw.BranchFilter,
+		BaseDirectory:        raw.BaseDirectory,
+		FilePathTemplate:     raw.FilePathTemplate,
+		SchemaPathTemplate:   raw.SchemaPathTemplate,
+		SheetPathTemplate:    raw.SheetPathTemplate,
+		EnableSQLReviewCI:    raw.EnableSQLReviewCI,
+		ReleaseTagFilter:     raw.ReleaseTagFilter,
+		RoutingRules:         raw.RoutingRules,
+		EnableRollbackPR:     raw.EnableRollbackPR,
+		SchemaWriteBackPR:    raw.SchemaWriteBackPR,
+		MetadataPathTemplate: raw.MetadataPathTemplate,
+		BranchRules:          raw.BranchRules,
+		ExternalID:           raw.ExternalID,
+		ExternalWebhookID:    raw.ExternalWebhookID,
+		WebhookURLHost:       raw.WebhookURLHost,
+		WebhookEndpointID:    raw.WebhookEndpointID,
+		WebhookSecretToken:   raw.WebhookSecretToken,
+		AccessToken:          raw.AccessToken,
+		ExpiresTs:            raw.ExpiresTs,
+		RefreshToken:         raw.RefreshToken,
 	}
 }
 
@@ -265,36 +277,38 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *Tx, create *api.Re
 	s.projectCache.Delete(create.ProjectResourceID)
 	s.projectIDCache.Delete(create.ProjectID)
 
-	var repository repositoryRaw
-	// Insert row into database.
-	query := `
-		INSERT INTO repository (
-			creator_id,
-			updater_id,
-			vcs_id,
-			project_id,
-			name,
-			full_path,
-			web_url,
-			branch_filter,
-			base_directory,
-			file_path_template,
-			schema_path_template,
-			sheet_path_template,
-			enable_sql_review_ci,
-			external_id,
-			external_webhook_id,
-			webhook_url_host,
-			webhook_endpoint_id,
-			webhook_secret_token,
-			access_token,
-			expires_ts,
-			refresh_token
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
-		RETURNING id, vcs_id, project_id, name, full_path, web_url, branch_filter, base_directory, file_path_template, schema_path_template, sheet_path_template, enable_sql_review_ci, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
-	`
-	if err := tx.QueryRowContext(ctx, query,
+	routingRules := create.RoutingRules
+	if routingRules == "" {
+		routingRules = "[]"
+	}
+	branchRules := create.BranchRules
+	if branchRules == "" {
+		branchRules = "[]"
+	}
+	columns := []string{
+		"creator_id",
+		"updater_id",
+		"vcs_id",
+		"project_id",
+		"name",
+		"full_path",
+		"web_url",
+		"branch_filter",
+		"base_directory",
+		"file_path_template",
+		"schema_path_template",
+		"sheet_path_template",
+		"enable_sql_review_ci",
+		"external_id",
+		"external_webhook_id",
+		"webhook_url_host",
+		"webhook_endpoint_id",
+		"webhook_secret_token",
+		"access_token",
+		"expires_ts",
+		"refresh_token",
+	}
+	args := []any{
 		create.CreatorID,
 		create.CreatorID,
 		create.VCSID,
@@ -316,28 +330,37 @@ func (s *Store) createRepositoryImpl(ctx context.Context, tx *Tx, create *api.Re
 		create.AccessToken,
 		create.ExpiresTs,
 		create.RefreshToken,
-	).Scan(
-		&repository.ID,
-		&repository.VCSID,
-		&repository.ProjectID,
-		&repository.Name,
-		&repository.FullPath,
-		&repository.WebURL,
-		&repository.BranchFilter,
-		&repository.BaseDirectory,
-		&repository.FilePathTemplate,
-		&repository.SchemaPathTemplate,
-		&repository.SheetPathTemplate,
-		&repository.EnableSQLReviewCI,
-		&repository.ExternalID,
-		&repository.ExternalWebhookID,
-		&repository.WebhookURLHost,
-		&repository.WebhookEndpointID,
-		&repository.WebhookSecretToken,
-		&repository.AccessToken,
-		&repository.ExpiresTs,
-		&repository.RefreshToken,
-	); err != nil {
+	}
+	if common.FeatureFlag(common.FeatureFlagVCSRelease) {
+		columns, args = append(columns, "release_tag_filter"), append(args, create.ReleaseTagFilter)
+	}
+	if common.FeatureFlag(common.FeatureFlagRoutingRule) {
+		columns, args = append(columns, "routing_rules"), append(args, routingRules)
+	}
+	if common.FeatureFlag(common.FeatureFlagRollbackPR) {
+		columns, args = append(columns, "enable_rollback_pr"), append(args, create.EnableRollbackPR)
+	}
+	if common.FeatureFlag(common.FeatureFlagSchemaWriteBack) {
+		columns = append(columns, "schema_write_back_pr", "metadata_path_template")
+		args = append(args, create.SchemaWriteBackPR, create.MetadataPathTemplate)
+	}
+	if common.FeatureFlag(common.FeatureFlagBranchRule) {
+		columns, args = append(columns, "branch_rules"), append(args, branchRules)
+	}
+	var values []string
+	for i := range args {
+		values = append(values, fmt.Sprintf("$%d", i+1))
+	}
+
+	var repository repositoryRaw
+	returning, dest := getRepositoryColumns(&repository)
+	// Insert row into database.
+	query := fmt.Sprintf(`
+		INSERT INTO repository (%s)
+		VALUES (%s)
+		RETURNING %s
+	`, strings.Join(columns, ", "), strings.Join(values, ", "), strings.Join(returning, ", "))
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(dest...); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
 		}
@@ -365,28 +388,9 @@ func (*Store) findRepositoryImpl(ctx context.Context, tx *Tx, find *api.Reposito
 		where, args = append(where, fmt.Sprintf("web_url = $%d", len(args)+1)), append(args, *v)
 	}
 
+	columns, _ := getRepositoryColumns(&repositoryRaw{})
 	rows, err := tx.QueryContext(ctx, `
-		SELECT
-			id,
-			vcs_id,
-			project_id,
-			name,
-			full_path,
-			web_url,
-			branch_filter,
-			base_directory,
-			file_path_template,
-			schema_path_template,
-			sheet_path_template,
-			enable_sql_review_ci,
-			external_id,
-			external_webhook_id,
-			webhook_url_host,
-			webhook_endpoint_id,
-			webhook_secret_token,
-			access_token,
-			expires_ts,
-			refresh_token
+		SELECT `+strings.Join(columns, ", ")+`
 		FROM repository
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -400,28 +404,8 @@ func (*Store) findRepositoryImpl(ctx context.Context, tx *Tx, find *api.Reposito
 	var repoRawList []*repositoryRaw
 	for rows.Next() {
 		var repository repositoryRaw
-		if err := rows.Scan(
-			&repository.ID,
-			&repository.VCSID,
-			&repository.ProjectID,
-			&repository.Name,
-			&repository.FullPath,
-			&repository.WebURL,
-			&repository.BranchFilter,
-			&repository.BaseDirectory,
-			&repository.FilePathTemplate,
-			&repository.SchemaPathTemplate,
-			&repository.SheetPathTemplate,
-			&repository.EnableSQLReviewCI,
-			&repository.ExternalID,
-			&repository.ExternalWebhookID,
-			&repository.WebhookURLHost,
-			&repository.WebhookEndpointID,
-			&repository.WebhookSecretToken,
-			&repository.AccessToken,
-			&repository.ExpiresTs,
-			&repository.RefreshToken,
-		); err != nil {
+		_, dest := getRepositoryColumns(&repository)
+		if err := rows.Scan(dest...); err != nil {
 			return nil, err
 		}
 
@@ -475,17 +459,70 @@ func (*Store) patchRepositoryImpl(ctx context.Context, tx *Tx, patch *api.Reposi
 	if v := patch.EnableSQLReviewCI; v != nil {
 		set, args = append(set, fmt.Sprintf("enable_sql_review_ci = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.ReleaseTagFilter; v != nil && common.FeatureFlag(common.FeatureFlagVCSRelease) {
+		set, args = append(set, fmt.Sprintf("release_tag_filter = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.RoutingRules; v != nil && common.FeatureFlag(common.FeatureFlagRoutingRule) {
+		set, args = append(set, fmt.Sprintf("routing_rules = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.EnableRollbackPR; v != nil && common.FeatureFlag(common.FeatureFlagRollbackPR) {
+		set, args = append(set, fmt.Sprintf("enable_rollback_pr = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SchemaWriteBackPR; v != nil && common.FeatureFlag(common.FeatureFlagSchemaWriteBack) {
+		set, args = append(set, fmt.Sprintf("schema_write_back_pr = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.MetadataPathTemplate; v != nil && common.FeatureFlag(common.FeatureFlagSchemaWriteBack) {
+		set, args = append(set, fmt.Sprintf("metadata_path_template = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.BranchRules; v != nil && common.FeatureFlag(common.FeatureFlagBranchRule) {
+		set, args = append(set, fmt.Sprintf("branch_rules = $%d", len(args)+1)), append(args, *v)
+	}
 
 	var repository repositoryRaw
+	returning, dest := getRepositoryColumns(&repository)
 	// Execute update query with RETURNING.
 	if err := tx.QueryRowContext(ctx, `
 		UPDATE repository
 		SET `+strings.Join(set, ", ")+`
 		WHERE `+strings.Join(where, " AND ")+`
-		RETURNING id, vcs_id, project_id, name, full_path, web_url, branch_filter, base_directory, file_path_template, schema_path_template, sheet_path_template, enable_sql_review_ci, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token
+		RETURNING `+strings.Join(returning, ", ")+`
 		`,
 		args...,
-	).Scan(
+	).Scan(dest...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("repository ID not found: %d", patch.ID)}
+		}
+		return nil, err
+	}
+	return &repository, nil
+}
+
+// getRepositoryColumns returns the columns of the repository and the fields to scan them into. The columns of the
+// features whose schema hasn't been applied to prod yet are left out unless the features are enabled.
+func getRepositoryColumns(repository *repositoryRaw) ([]string, []any) {
+	columns := []string{
+		"id",
+		"vcs_id",
+		"project_id",
+		"name",
+		"full_path",
+		"web_url",
+		"branch_filter",
+		"base_directory",
+		"file_path_template",
+		"schema_path_template",
+		"sheet_path_template",
+		"enable_sql_review_ci",
+		"external_id",
+		"external_webhook_id",
+		"webhook_url_host",
+		"webhook_endpoint_id",
+		"webhook_secret_token",
+		"access_token",
+		"expires_ts",
+		"refresh_token",
+	}
+	dest := []any{
 		&repository.ID,
 		&repository.VCSID,
 		&repository.ProjectID,
@@ -506,13 +543,24 @@ func (*Store) patchRepositoryImpl(ctx context.Context, tx *Tx, patch *api.Reposi
 		&repository.AccessToken,
 		&repository.ExpiresTs,
 		&repository.RefreshToken,
-	); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("repository ID not found: %d", patch.ID)}
-		}
-		return nil, err
 	}
-	return &repository, nil
+	if common.FeatureFlag(common.FeatureFlagVCSRelease) {
+		columns, dest = append(columns, "release_tag_filter"), append(dest, &repository.ReleaseTagFilter)
+	}
+	if common.FeatureFlag(common.FeatureFlagRoutingRule) {
+		columns, dest = append(columns, "routing_rules"), append(dest, &repository.RoutingRules)
+	}
+	if common.FeatureFlag(common.FeatureFlagRollbackPR) {
+		columns, dest = append(columns, "enable_rollback_pr"), append(dest, &repository.EnableRollbackPR)
+	}
+	if common.FeatureFlag(common.FeatureFlagSchemaWriteBack) {
+		columns = append(columns, "schema_write_back_pr", "metadata_path_template")
+		dest = append(dest, &repository.SchemaWriteBackPR, &repository.MetadataPathTemplate)
+	}
+	if common.FeatureFlag(common.FeatureFlagBranchRule) {
+		columns, dest = append(columns, "branch_rules"), append(dest, &repository.BranchRules)
+	}
+	return columns, dest
 }
 
 // deleteRepositoryImpl permanently deletes a repository by ID.
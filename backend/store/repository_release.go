@@ -0,0 +1,139 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// ReleaseMessage is the message for an immutable release built from a git tag.
+type ReleaseMessage struct {
+	ProjectUID int
+	Tag        string
+	CommitID   string
+	FileList   []*api.ReleaseFile
+	Checksum   string
+
+	// Output only
+	UID       int
+	CreatorID int
+	CreatedTs int64
+}
+
+// FindReleaseMessage is the message to find releases.
+type FindReleaseMessage struct {
+	ProjectUID *int
+	Tag        *string
+}
+
+const releaseColumns = `
+			id,
+			creator_id,
+			created_ts,
+			project_id,
+			tag,
+			commit_id,
+			files,
+			checksum`
+
+// CreateRelease creates a release. The release is immutable once created.
+func (s *Store) CreateRelease(ctx context.Context, create *ReleaseMessage, creatorID int) (*ReleaseMessage, error) {
+	files, err := json.Marshal(create.FileList)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal release files")
+	}
+	release, err := scanRelease(s.db.db.QueryRowContext(ctx, `
+		INSERT INTO repository_release (
+			creator_id,
+			project_id,
+			tag,
+			commit_id,
+			files,
+			checksum
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING`+releaseColumns,
+		creatorID,
+		create.ProjectUID,
+		create.Tag,
+		create.CommitID,
+		files,
+		create.Checksum,
+	))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create release")
+	}
+	return release, nil
+}
+
+// GetRelease gets a release, returns nil if not found.
+func (s *Store) GetRelease(ctx context.Context, find *FindReleaseMessage) (*ReleaseMessage, error) {
+	releases, err := s.ListReleases(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, nil
+	}
+	return releases[0], nil
+}
+
+// ListReleases lists the releases, the latest first.
+func (s *Store) ListReleases(ctx context.Context, find *FindReleaseMessage) ([]*ReleaseMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.ProjectUID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Tag; v != nil {
+		where, args = append(where, fmt.Sprintf("tag = $%d", len(args)+1)), append(args, *v)
+	}
+	rows, err := s.db.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT`+releaseColumns+`
+		FROM repository_release
+		WHERE %s
+		ORDER BY id DESC`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list releases")
+	}
+	defer rows.Close()
+
+	var releases []*ReleaseMessage
+	for rows.Next() {
+		release, err := scanRelease(rows)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to scan release")
+		}
+		releases = append(releases, release)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan releases")
+	}
+	return releases, nil
+}
+
+func scanRelease(row interface{ Scan(...any) error }) (*ReleaseMessage, error) {
+	var release ReleaseMessage
+	var files []byte
+	if err := row.Scan(
+		&release.UID,
+		&release.CreatorID,
+		&release.CreatedTs,
+		&release.ProjectUID,
+		&release.Tag,
+		&release.CommitID,
+		&files,
+		&release.Checksum,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(files, &release.FileList); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal release files")
+	}
+	return &release, nil
+}
@@ -3,9 +3,12 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/pkg/errors"
+
 	api "github.com/bytebase/bytebase/backend/legacyapi"
 )
 
@@ -14,30 +17,47 @@ type RoleMessage struct {
 	ResourceID  string
 	Name        string
 	Description string
+	// PermissionList is the permissions the role is composed from.
+	PermissionList []api.ProjectPermissionType
 
 	// Output only
 	CreatorID int
 }
 
+// rolePermissions is the permissions column of the role.
+type rolePermissions struct {
+	// PermissionList is nil for the roles created before the roles had permissions.
+	PermissionList []api.ProjectPermissionType `json:"permissionList"`
+}
+
 // UpdateRoleMessage is the message for updating roles.
 type UpdateRoleMessage struct {
 	UpdaterID  int
 	ResourceID string
 
-	Name        *string
-	Description *string
+	Name           *string
+	Description    *string
+	PermissionList *[]api.ProjectPermissionType
 }
 
 // CreateRole creates a new role.
 func (s *Store) CreateRole(ctx context.Context, create *RoleMessage, creatorID int) (*RoleMessage, error) {
+	if create.PermissionList == nil {
+		create.PermissionList = api.DefaultCustomRolePermissionList
+	}
+	permissions, err := marshalRolePermissions(create.PermissionList)
+	if err != nil {
+		return nil, err
+	}
 	query := `
 		INSERT INTO
-			role (creator_id, updater_id, resource_id, name, description)
-		VALUES ($1, $2, $3, $4, $5)
+			role (creator_id, updater_id, resource_id, name, description, permissions)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
-	if _, err := s.db.db.ExecContext(ctx, query, creatorID, creatorID, create.ResourceID, create.Name, create.Description); err != nil {
+	if _, err := s.db.db.ExecContext(ctx, query, creatorID, creatorID, create.ResourceID, create.Name, create.Description, permissions); err != nil {
 		return nil, err
 	}
+	create.CreatorID = creatorID
 	return create, nil
 }
 
@@ -45,18 +65,24 @@ func (s *Store) CreateRole(ctx context.Context, create *RoleMessage, creatorID i
 func (s *Store) GetRole(ctx context.Context, resourceID string) (*RoleMessage, error) {
 	query := `
 		SELECT
-			creator_id, name, description
+			creator_id, name, description, permissions
 		FROM role
 		WHERE resource_id = $1
 	`
 	var role RoleMessage
-	if err := s.db.db.QueryRowContext(ctx, query, resourceID).Scan(&role.CreatorID, &role.Name, &role.Description); err != nil {
+	var permissions []byte
+	if err := s.db.db.QueryRowContext(ctx, query, resourceID).Scan(&role.CreatorID, &role.Name, &role.Description, &permissions); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
 	role.ResourceID = resourceID
+	permissionList, err := unmarshalRolePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+	role.PermissionList = permissionList
 	return &role, nil
 }
 
@@ -64,7 +90,7 @@ func (s *Store) GetRole(ctx context.Context, resourceID string) (*RoleMessage, e
 func (s *Store) ListRoles(ctx context.Context) ([]*RoleMessage, error) {
 	query := `
 		SELECT
-			creator_id, resource_id, name, description
+			creator_id, resource_id, name, description, permissions
 		FROM role
 	`
 	rows, err := s.db.db.QueryContext(ctx, query)
@@ -91,7 +117,11 @@ func (s *Store) ListRoles(ctx context.Context) ([]*RoleMessage, error) {
 
 	for rows.Next() {
 		var role RoleMessage
-		if err := rows.Scan(&role.CreatorID, &role.ResourceID, &role.Name, &role.Description); err != nil {
+		var permissions []byte
+		if err := rows.Scan(&role.CreatorID, &role.ResourceID, &role.Name, &role.Description, &permissions); err != nil {
+			return nil, err
+		}
+		if role.PermissionList, err = unmarshalRolePermissions(permissions); err != nil {
 			return nil, err
 		}
 		roles = append(roles, &role)
@@ -113,21 +143,34 @@ func (s *Store) UpdateRole(ctx context.Context, patch *UpdateRoleMessage) (*Role
 	if v := patch.Description; v != nil {
 		set, args = append(set, fmt.Sprintf("description = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.PermissionList; v != nil {
+		permissions, err := marshalRolePermissions(*v)
+		if err != nil {
+			return nil, err
+		}
+		set, args = append(set, fmt.Sprintf("permissions = $%d", len(args)+1)), append(args, permissions)
+	}
 	args = append(args, patch.ResourceID)
 
 	query := fmt.Sprintf(`
 		UPDATE role
 		SET `+strings.Join(set, ", ")+`
 		WHERE resource_id = $%d
-		RETURNING creator_id, name, description
+		RETURNING creator_id, name, description, permissions
 	`, len(args))
 
 	role := RoleMessage{
 		ResourceID: patch.ResourceID,
 	}
-	if err := s.db.db.QueryRowContext(ctx, query, args...).Scan(&role.CreatorID, &role.Name, &role.Description); err != nil {
+	var permissions []byte
+	if err := s.db.db.QueryRowContext(ctx, query, args...).Scan(&role.CreatorID, &role.Name, &role.Description, &permissions); err != nil {
 		return nil, err
 	}
+	permissionList, err := unmarshalRolePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+	role.PermissionList = permissionList
 
 	return &role, nil
 }
@@ -143,3 +186,24 @@ func (s *Store) DeleteRole(ctx context.Context, resourceID string) error {
 	}
 	return nil
 }
+
+func marshalRolePermissions(permissionList []api.ProjectPermissionType) ([]byte, error) {
+	permissions, err := json.Marshal(&rolePermissions{PermissionList: permissionList})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal role permissions")
+	}
+	return permissions, nil
+}
+
+// unmarshalRolePermissions returns the permission list of the role, the roles created before the roles had
+// permissions have the default permission list.
+func unmarshalRolePermissions(permissions []byte) ([]api.ProjectPermissionType, error) {
+	var p rolePermissions
+	if err := json.Unmarshal(permissions, &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal role permissions")
+	}
+	if p.PermissionList == nil {
+		return api.DefaultCustomRolePermissionList, nil
+	}
+	return p.PermissionList, nil
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RoleMemberMessage is the message for a custom role assigned to a principal at the workspace scope.
+type RoleMemberMessage struct {
+	Role        string
+	PrincipalID int
+
+	// Output only
+	CreatorID int
+	CreatedTs int64
+}
+
+// FindRoleMemberMessage is the message to find role members.
+type FindRoleMemberMessage struct {
+	Role        *string
+	PrincipalID *int
+}
+
+// CreateRoleMember assigns a custom role to a principal at the workspace scope.
+func (s *Store) CreateRoleMember(ctx context.Context, create *RoleMemberMessage, creatorID int) (*RoleMemberMessage, error) {
+	member := &RoleMemberMessage{
+		Role:        create.Role,
+		PrincipalID: create.PrincipalID,
+		CreatorID:   creatorID,
+	}
+	if err := s.db.db.QueryRowContext(ctx, `
+		INSERT INTO role_member (
+			creator_id,
+			role,
+			principal_id
+		) VALUES ($1, $2, $3)
+		RETURNING created_ts`,
+		creatorID,
+		create.Role,
+		create.PrincipalID,
+	).Scan(&member.CreatedTs); err != nil {
+		return nil, errors.Wrapf(err, "failed to create role member")
+	}
+	return member, nil
+}
+
+// ListRoleMembers lists the role members.
+func (s *Store) ListRoleMembers(ctx context.Context, find *FindRoleMemberMessage) ([]*RoleMemberMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.Role; v != nil {
+		where, args = append(where, fmt.Sprintf("role = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.PrincipalID; v != nil {
+		where, args = append(where, fmt.Sprintf("principal_id = $%d", len(args)+1)), append(args, *v)
+	}
+	rows, err := s.db.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			creator_id,
+			created_ts,
+			role,
+			principal_id
+		FROM role_member
+		WHERE %s
+		ORDER BY id`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list role members")
+	}
+	defer rows.Close()
+
+	var members []*RoleMemberMessage
+	for rows.Next() {
+		var member RoleMemberMessage
+		if err := rows.Scan(
+			&member.CreatorID,
+			&member.CreatedTs,
+			&member.Role,
+			&member.PrincipalID,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan role member")
+		}
+		members = append(members, &member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan role members")
+	}
+	return members, nil
+}
+
+// DeleteRoleMember unassigns a custom role from a principal at the workspace scope.
+func (s *Store) DeleteRoleMember(ctx context.Context, role string, principalID int) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM role_member
+		WHERE role = $1 AND principal_id = $2`,
+		role,
+		principalID,
+	); err != nil {
+		return errors.Wrapf(err, "failed to delete role member")
+	}
+	return nil
+}
@@ -0,0 +1,389 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// ScheduledQueryMessage is the message for a sheet run on a cron schedule.
+type ScheduledQueryMessage struct {
+	ID        int
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+	SheetUID  int
+	Name      string
+	// Schedule is the cron expression with 5 fields evaluated in the Timezone.
+	Schedule     string
+	Timezone     string
+	DeliveryList []*api.ScheduledQueryDelivery
+	Enabled      bool
+	NextRunTs    int64
+}
+
+// FindScheduledQueryMessage is the message to find scheduled queries.
+type FindScheduledQueryMessage struct {
+	ID        *int
+	SheetUID  *int
+	CreatorID *int
+	Enabled   *bool
+	// NextRunBefore finds the scheduled queries due at the timestamp.
+	NextRunBefore *int64
+}
+
+// UpdateScheduledQueryMessage is the message to update a scheduled query.
+type UpdateScheduledQueryMessage struct {
+	ID        int
+	UpdaterID int
+
+	Name         *string
+	Schedule     *string
+	Timezone     *string
+	DeliveryList *[]*api.ScheduledQueryDelivery
+	Enabled      *bool
+	NextRunTs    *int64
+}
+
+// ScheduledQueryRunMessage is the message for a run of a scheduled query.
+type ScheduledQueryRunMessage struct {
+	ID                 int
+	CreatedTs          int64
+	ScheduledQueryID   int
+	Status             api.ScheduledQueryRunStatus
+	Result             *api.ScheduledQueryResult
+	Error              string
+	DeliveryResultList []*api.ScheduledQueryDeliveryResult
+}
+
+// FindScheduledQueryRunMessage is the message to find scheduled query runs.
+type FindScheduledQueryRunMessage struct {
+	ScheduledQueryID int
+	// Limit is the number of the latest runs returned.
+	Limit int
+}
+
+// GetScheduledQuery gets a scheduled query.
+func (s *Store) GetScheduledQuery(ctx context.Context, find *FindScheduledQueryMessage) (*ScheduledQueryMessage, error) {
+	scheduledQueries, err := s.ListScheduledQueries(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(scheduledQueries) == 0 {
+		return nil, nil
+	}
+	if len(scheduledQueries) > 1 {
+		return nil, errors.Errorf("found %d scheduled queries with filter %+v, expect 1", len(scheduledQueries), find)
+	}
+	return scheduledQueries[0], nil
+}
+
+// ListScheduledQueries lists the scheduled queries.
+func (s *Store) ListScheduledQueries(ctx context.Context, find *FindScheduledQueryMessage) ([]*ScheduledQueryMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.SheetUID; v != nil {
+		where, args = append(where, fmt.Sprintf("sheet_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.CreatorID; v != nil {
+		where, args = append(where, fmt.Sprintf("creator_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Enabled; v != nil {
+		where, args = append(where, fmt.Sprintf("enabled = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.NextRunBefore; v != nil {
+		where, args = append(where, fmt.Sprintf("next_run_ts <= $%d", len(args)+1)), append(args, *v)
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			sheet_id,
+			name,
+			schedule,
+			timezone,
+			delivery_list,
+			enabled,
+			next_run_ts
+		FROM scheduled_query
+		WHERE %s
+		ORDER BY id`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query scheduled queries")
+	}
+	defer rows.Close()
+
+	var scheduledQueries []*ScheduledQueryMessage
+	for rows.Next() {
+		scheduledQuery, err := scanScheduledQuery(rows)
+		if err != nil {
+			return nil, err
+		}
+		scheduledQueries = append(scheduledQueries, scheduledQuery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return scheduledQueries, nil
+}
+
+// CreateScheduledQuery creates a scheduled query.
+func (s *Store) CreateScheduledQuery(ctx context.Context, create *ScheduledQueryMessage) (*ScheduledQueryMessage, error) {
+	deliveryList, err := json.Marshal(create.DeliveryList)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal delivery list")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var id int
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO scheduled_query (
+			creator_id,
+			updater_id,
+			sheet_id,
+			name,
+			schedule,
+			timezone,
+			delivery_list,
+			enabled,
+			next_run_ts
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`,
+		create.CreatorID,
+		create.CreatorID,
+		create.SheetUID,
+		create.Name,
+		create.Schedule,
+		create.Timezone,
+		deliveryList,
+		create.Enabled,
+		create.NextRunTs,
+	).Scan(&id); err != nil {
+		return nil, errors.Wrapf(err, "failed to create scheduled query")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return s.GetScheduledQuery(ctx, &FindScheduledQueryMessage{ID: &id})
+}
+
+// UpdateScheduledQuery updates a scheduled query.
+func (s *Store) UpdateScheduledQuery(ctx context.Context, patch *UpdateScheduledQueryMessage) (*ScheduledQueryMessage, error) {
+	set, args := []string{"updater_id = $1"}, []any{patch.UpdaterID}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Schedule; v != nil {
+		set, args = append(set, fmt.Sprintf("schedule = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Timezone; v != nil {
+		set, args = append(set, fmt.Sprintf("timezone = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.DeliveryList; v != nil {
+		deliveryList, err := json.Marshal(*v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal delivery list")
+		}
+		set, args = append(set, fmt.Sprintf("delivery_list = $%d", len(args)+1)), append(args, deliveryList)
+	}
+	if v := patch.Enabled; v != nil {
+		set, args = append(set, fmt.Sprintf("enabled = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.NextRunTs; v != nil {
+		set, args = append(set, fmt.Sprintf("next_run_ts = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, patch.ID)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`UPDATE scheduled_query SET %s WHERE id = $%d`, strings.Join(set, ", "), len(args)), args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to update scheduled query")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return s.GetScheduledQuery(ctx, &FindScheduledQueryMessage{ID: &patch.ID})
+}
+
+// DeleteScheduledQuery deletes a scheduled query and its runs.
+func (s *Store) DeleteScheduledQuery(ctx context.Context, id int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM scheduled_query WHERE id = $1`, id); err != nil {
+		return errors.Wrapf(err, "failed to delete scheduled query")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrapf(err, "failed to commit transaction")
+	}
+	return nil
+}
+
+// CreateScheduledQueryRun creates a run of a scheduled query.
+func (s *Store) CreateScheduledQueryRun(ctx context.Context, create *ScheduledQueryRunMessage) (*ScheduledQueryRunMessage, error) {
+	result, err := json.Marshal(create.Result)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal result")
+	}
+	deliveryResultList, err := json.Marshal(create.DeliveryResultList)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal delivery result list")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	run := *create
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO scheduled_query_run (
+			scheduled_query_id,
+			status,
+			result,
+			error,
+			delivery_result_list
+		) VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_ts`,
+		create.ScheduledQueryID,
+		create.Status,
+		result,
+		create.Error,
+		deliveryResultList,
+	).Scan(&run.ID, &run.CreatedTs); err != nil {
+		return nil, errors.Wrapf(err, "failed to create scheduled query run")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return &run, nil
+}
+
+// ListScheduledQueryRuns lists the latest runs of a scheduled query.
+func (s *Store) ListScheduledQueryRuns(ctx context.Context, find *FindScheduledQueryRunMessage) ([]*ScheduledQueryRunMessage, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT
+			id,
+			created_ts,
+			scheduled_query_id,
+			status,
+			result,
+			error,
+			delivery_result_list
+		FROM scheduled_query_run
+		WHERE scheduled_query_id = $1
+		ORDER BY id DESC`
+	if find.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", find.Limit)
+	}
+	rows, err := tx.QueryContext(ctx, query, find.ScheduledQueryID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query scheduled query runs")
+	}
+	defer rows.Close()
+
+	var runs []*ScheduledQueryRunMessage
+	for rows.Next() {
+		run := &ScheduledQueryRunMessage{}
+		var result, deliveryResultList []byte
+		if err := rows.Scan(
+			&run.ID,
+			&run.CreatedTs,
+			&run.ScheduledQueryID,
+			&run.Status,
+			&result,
+			&run.Error,
+			&deliveryResultList,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan scheduled query run")
+		}
+		if err := json.Unmarshal(result, &run.Result); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal result")
+		}
+		if err := json.Unmarshal(deliveryResultList, &run.DeliveryResultList); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal delivery result list")
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return runs, nil
+}
+
+func scanScheduledQuery(rows *sql.Rows) (*ScheduledQueryMessage, error) {
+	scheduledQuery := &ScheduledQueryMessage{}
+	var deliveryList []byte
+	if err := rows.Scan(
+		&scheduledQuery.ID,
+		&scheduledQuery.CreatorID,
+		&scheduledQuery.CreatedTs,
+		&scheduledQuery.UpdaterID,
+		&scheduledQuery.UpdatedTs,
+		&scheduledQuery.SheetUID,
+		&scheduledQuery.Name,
+		&scheduledQuery.Schedule,
+		&scheduledQuery.Timezone,
+		&deliveryList,
+		&scheduledQuery.Enabled,
+		&scheduledQuery.NextRunTs,
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan scheduled query")
+	}
+	if err := json.Unmarshal(deliveryList, &scheduledQuery.DeliveryList); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal delivery list")
+	}
+	return scheduledQuery, nil
+}
@@ -0,0 +1,277 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// SnippetMessage is the message for a snippet.
+type SnippetMessage struct {
+	ID        int
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+	// ProjectUID is only set for the PROJECT visibility.
+	ProjectUID   *int
+	Visibility   api.SnippetVisibility
+	Name         string
+	Description  string
+	Content      string
+	VariableList []*api.SnippetVariable
+	UsageCount   int
+	LastUsedTs   int64
+}
+
+// FindSnippetMessage is the message to find snippets.
+type FindSnippetMessage struct {
+	ID         *int
+	ProjectUID *int
+	// VisibleToPrincipalID finds the snippets visible to the principal, which are the ones created by the principal,
+	// the workspace ones and the ones in the projects the principal is a member of.
+	VisibleToPrincipalID *int
+	// Query matches the name or the description case-insensitively.
+	Query string
+}
+
+// UpdateSnippetMessage is the message to update a snippet.
+type UpdateSnippetMessage struct {
+	ID        int
+	UpdaterID int
+
+	// The ProjectUID is updated along with the Visibility, the nil ProjectUID clears the project.
+	Visibility   *api.SnippetVisibility
+	ProjectUID   *int
+	Name         *string
+	Description  *string
+	Content      *string
+	VariableList *[]*api.SnippetVariable
+}
+
+// GetSnippet gets a snippet.
+func (s *Store) GetSnippet(ctx context.Context, find *FindSnippetMessage) (*SnippetMessage, error) {
+	snippets, err := s.ListSnippets(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(snippets) == 0 {
+		return nil, nil
+	}
+	if len(snippets) > 1 {
+		return nil, errors.Errorf("found %d snippets with filter %+v, expect 1", len(snippets), find)
+	}
+	return snippets[0], nil
+}
+
+// ListSnippets lists the snippets from the most used.
+func (s *Store) ListSnippets(ctx context.Context, find *FindSnippetMessage) ([]*SnippetMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.ProjectUID; v != nil {
+		where, args = append(where, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.VisibleToPrincipalID; v != nil {
+		where, args = append(where, fmt.Sprintf(`(
+			creator_id = $%d
+			OR visibility = '%s'
+			OR (visibility = '%s' AND project_id IN (SELECT project_id FROM project_member WHERE principal_id = $%d))
+		)`, len(args)+1, api.SnippetVisibilityWorkspace, api.SnippetVisibilityProject, len(args)+1)), append(args, *v)
+	}
+	if v := find.Query; v != "" {
+		where, args = append(where, fmt.Sprintf("(name ILIKE $%d OR description ILIKE $%d)", len(args)+1, len(args)+1)), append(args, "%"+v+"%")
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			project_id,
+			visibility,
+			name,
+			description,
+			content,
+			variable_list,
+			usage_count,
+			last_used_ts
+		FROM snippet
+		WHERE %s
+		ORDER BY usage_count DESC, id`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query snippets")
+	}
+	defer rows.Close()
+
+	var snippets []*SnippetMessage
+	for rows.Next() {
+		snippet, err := scanSnippet(rows)
+		if err != nil {
+			return nil, err
+		}
+		snippets = append(snippets, snippet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return snippets, nil
+}
+
+// CreateSnippet creates a snippet.
+func (s *Store) CreateSnippet(ctx context.Context, create *SnippetMessage) (*SnippetMessage, error) {
+	variableList, err := json.Marshal(create.VariableList)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal variable list")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	var id int
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO snippet (
+			creator_id,
+			updater_id,
+			project_id,
+			visibility,
+			name,
+			description,
+			content,
+			variable_list
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		create.CreatorID,
+		create.CreatorID,
+		create.ProjectUID,
+		create.Visibility,
+		create.Name,
+		create.Description,
+		create.Content,
+		variableList,
+	).Scan(&id); err != nil {
+		return nil, errors.Wrapf(err, "failed to create snippet")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return s.GetSnippet(ctx, &FindSnippetMessage{ID: &id})
+}
+
+// UpdateSnippet updates a snippet.
+func (s *Store) UpdateSnippet(ctx context.Context, patch *UpdateSnippetMessage) (*SnippetMessage, error) {
+	set, args := []string{"updater_id = $1", "updated_ts = extract(epoch from now())"}, []any{patch.UpdaterID}
+	if v := patch.Visibility; v != nil {
+		set, args = append(set, fmt.Sprintf("visibility = $%d", len(args)+1)), append(args, *v)
+		set, args = append(set, fmt.Sprintf("project_id = $%d", len(args)+1)), append(args, patch.ProjectUID)
+	}
+	if v := patch.Name; v != nil {
+		set, args = append(set, fmt.Sprintf("name = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Description; v != nil {
+		set, args = append(set, fmt.Sprintf("description = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.Content; v != nil {
+		set, args = append(set, fmt.Sprintf("content = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.VariableList; v != nil {
+		variableList, err := json.Marshal(*v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal variable list")
+		}
+		set, args = append(set, fmt.Sprintf("variable_list = $%d", len(args)+1)), append(args, variableList)
+	}
+	args = append(args, patch.ID)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`UPDATE snippet SET %s WHERE id = $%d`, strings.Join(set, ", "), len(args)), args...); err != nil {
+		return nil, errors.Wrapf(err, "failed to update snippet")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return s.GetSnippet(ctx, &FindSnippetMessage{ID: &patch.ID})
+}
+
+// RecordSnippetUsage increments the usage count of a snippet.
+func (s *Store) RecordSnippetUsage(ctx context.Context, id int) error {
+	if _, err := s.db.db.ExecContext(ctx, `
+		UPDATE snippet
+		SET usage_count = usage_count + 1, last_used_ts = extract(epoch from now())
+		WHERE id = $1`,
+		id,
+	); err != nil {
+		return errors.Wrapf(err, "failed to record snippet usage")
+	}
+	return nil
+}
+
+// DeleteSnippet deletes a snippet.
+func (s *Store) DeleteSnippet(ctx context.Context, id int) error {
+	if _, err := s.db.db.ExecContext(ctx, `DELETE FROM snippet WHERE id = $1`, id); err != nil {
+		return errors.Wrapf(err, "failed to delete snippet")
+	}
+	return nil
+}
+
+func scanSnippet(rows *sql.Rows) (*SnippetMessage, error) {
+	snippet := &SnippetMessage{}
+	var projectUID sql.NullInt32
+	var variableList []byte
+	if err := rows.Scan(
+		&snippet.ID,
+		&snippet.CreatorID,
+		&snippet.CreatedTs,
+		&snippet.UpdaterID,
+		&snippet.UpdatedTs,
+		&projectUID,
+		&snippet.Visibility,
+		&snippet.Name,
+		&snippet.Description,
+		&snippet.Content,
+		&variableList,
+		&snippet.UsageCount,
+		&snippet.LastUsedTs,
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan snippet")
+	}
+	if projectUID.Valid {
+		v := int(projectUID.Int32)
+		snippet.ProjectUID = &v
+	}
+	if err := json.Unmarshal(variableList, &snippet.VariableList); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal variable list")
+	}
+	return snippet, nil
+}
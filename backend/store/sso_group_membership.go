@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// SSOGroupMembershipMessage is the identity provider groups of a user and the project roles granted by them.
+type SSOGroupMembershipMessage struct {
+	PrincipalID int
+	// IdentityProvider is the resource ID of the identity provider the user last signed in with.
+	IdentityProvider string
+	GroupList        []string
+	// GrantedProjectRoleList is the project roles added by the SSO group mapping, the ones not granted any more are
+	// revoked. The project roles the users had before are never revoked.
+	GrantedProjectRoleList []*api.SSOProjectRole
+
+	// Output only
+	UpdatedTs int64
+}
+
+type ssoGroupMembershipPayload struct {
+	GrantedProjectRoleList []*api.SSOProjectRole `json:"grantedProjectRoleList"`
+}
+
+// GetSSOGroupMembership gets the SSO group membership of the user, or nil if the user never signed in with a mapped
+// identity provider.
+func (s *Store) GetSSOGroupMembership(ctx context.Context, principalID int) (*SSOGroupMembershipMessage, error) {
+	memberships, err := s.listSSOGroupMembershipsImpl(ctx, &principalID)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberships) == 0 {
+		return nil, nil
+	}
+	return memberships[0], nil
+}
+
+// ListSSOGroupMemberships lists the SSO group memberships of all users.
+func (s *Store) ListSSOGroupMemberships(ctx context.Context) ([]*SSOGroupMembershipMessage, error) {
+	return s.listSSOGroupMembershipsImpl(ctx, nil)
+}
+
+func (s *Store) listSSOGroupMembershipsImpl(ctx context.Context, principalID *int) ([]*SSOGroupMembershipMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := principalID; v != nil {
+		where, args = append(where, fmt.Sprintf("principal_id = $%d", len(args)+1)), append(args, *v)
+	}
+	rows, err := s.db.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			principal_id,
+			updated_ts,
+			idp,
+			group_list,
+			payload
+		FROM sso_group_membership
+		WHERE %s
+		ORDER BY principal_id`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list SSO group memberships")
+	}
+	defer rows.Close()
+
+	var memberships []*SSOGroupMembershipMessage
+	for rows.Next() {
+		var membership SSOGroupMembershipMessage
+		var payload []byte
+		if err := rows.Scan(
+			&membership.PrincipalID,
+			&membership.UpdatedTs,
+			&membership.IdentityProvider,
+			pq.Array(&membership.GroupList),
+			&payload,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan SSO group membership")
+		}
+		var p ssoGroupMembershipPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal SSO group membership payload")
+		}
+		membership.GrantedProjectRoleList = p.GrantedProjectRoleList
+		memberships = append(memberships, &membership)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to list SSO group memberships")
+	}
+	return memberships, nil
+}
+
+// UpsertSSOGroupMembership creates or replaces the SSO group membership of the user.
+func (s *Store) UpsertSSOGroupMembership(ctx context.Context, upsert *SSOGroupMembershipMessage) error {
+	payload, err := json.Marshal(&ssoGroupMembershipPayload{GrantedProjectRoleList: upsert.GrantedProjectRoleList})
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal SSO group membership payload")
+	}
+	groupList := upsert.GroupList
+	if groupList == nil {
+		groupList = []string{}
+	}
+	if _, err := s.db.db.ExecContext(ctx, `
+		INSERT INTO sso_group_membership (
+			principal_id,
+			idp,
+			group_list,
+			payload
+		) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (principal_id) DO UPDATE SET
+			idp = EXCLUDED.idp,
+			group_list = EXCLUDED.group_list,
+			payload = EXCLUDED.payload`,
+		upsert.PrincipalID,
+		upsert.IdentityProvider,
+		pq.Array(groupList),
+		payload,
+	); err != nil {
+		return errors.Wrapf(err, "failed to upsert SSO group membership")
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -438,8 +439,8 @@ func (s *Store) UpdateTaskV2(ctx context.Context, patch *api.TaskPatch) (*TaskMe
 	if (patch.SchemaVersion != nil || patch.SheetID != nil) && patch.Payload != nil {
 		return nil, errors.Errorf("cannot set both sheetID/schemaVersion and payload for TaskPatch")
 	}
-	if (patch.RollbackEnabled != nil || patch.RollbackSQLStatus != nil || patch.RollbackStatement != nil || patch.RollbackError != nil) && patch.Payload != nil {
-		return nil, errors.Errorf("cannot set both rollbackEnabled/rollbackSQLStatus/rollbackStatement/rollbackError payload for TaskPatch")
+	if (patch.RollbackEnabled != nil || patch.RollbackSQLStatus != nil || patch.RollbackStatement != nil || patch.RollbackError != nil || patch.DependencyOverride != nil || patch.StatementCheckpoint != nil) && patch.Payload != nil {
+		return nil, errors.Errorf("cannot set both rollbackEnabled/rollbackSQLStatus/rollbackStatement/rollbackError/dependencyOverride/statementCheckpoint payload for TaskPatch")
 	}
 	var payloadSet []string
 	if v := patch.SheetID; v != nil {
@@ -460,6 +461,20 @@ func (s *Store) UpdateTaskV2(ctx context.Context, patch *api.TaskPatch) (*TaskMe
 	if v := patch.RollbackError; v != nil {
 		payloadSet, args = append(payloadSet, fmt.Sprintf(`jsonb_build_object('rollbackError', to_jsonb($%d::TEXT))`, len(args)+1)), append(args, *v)
 	}
+	if v := patch.DependencyOverride; v != nil {
+		override, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		payloadSet, args = append(payloadSet, fmt.Sprintf(`jsonb_build_object('dependencyOverride', $%d::JSONB)`, len(args)+1)), append(args, string(override))
+	}
+	if v := patch.StatementCheckpoint; v != nil {
+		checkpoint, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		payloadSet, args = append(payloadSet, fmt.Sprintf(`jsonb_build_object('checkpoint', $%d::JSONB)`, len(args)+1)), append(args, string(checkpoint))
+	}
 	if len(payloadSet) != 0 {
 		set = append(set, fmt.Sprintf(`payload = payload || %s`, strings.Join(payloadSet, "||")))
 	}
@@ -0,0 +1,166 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// UnusedIndexMessage is the message for an index observed unused from the engine statistics.
+type UnusedIndexMessage struct {
+	DatabaseUID int
+	// SchemaName is empty for the engines without schema, such as MySQL.
+	SchemaName string
+	TableName  string
+	IndexName  string
+	// UnusedSince is the time when the index was first observed unused.
+	UnusedSince time.Time
+}
+
+// FindUnusedIndexMessage is the message to find unused indexes.
+type FindUnusedIndexMessage struct {
+	DatabaseUID *int
+	InstanceUID *int
+}
+
+// ListUnusedIndexes lists the unused indexes.
+func (s *Store) ListUnusedIndexes(ctx context.Context, find *FindUnusedIndexMessage) ([]*UnusedIndexMessage, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	unusedIndexes, err := s.listUnusedIndexImpl(ctx, tx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrapf(err, "failed to commit transaction")
+	}
+	return unusedIndexes, nil
+}
+
+// SetUnusedIndexes replaces the unused indexes of the database.
+// The unused since time of the indexes which are already recorded is kept, so that we can tell how long an index has been unused.
+func (s *Store) SetUnusedIndexes(ctx context.Context, databaseUID int, unusedIndexes []*UnusedIndexMessage) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	oldIndexes, err := s.listUnusedIndexImpl(ctx, tx, &FindUnusedIndexMessage{DatabaseUID: &databaseUID})
+	if err != nil {
+		return err
+	}
+	oldIndexMap := make(map[string]bool)
+	for _, index := range oldIndexes {
+		oldIndexMap[index.key()] = true
+	}
+	newIndexMap := make(map[string]bool)
+	for _, index := range unusedIndexes {
+		newIndexMap[index.key()] = true
+	}
+
+	for _, index := range oldIndexes {
+		if newIndexMap[index.key()] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM unused_index
+			WHERE database_id = $1 AND schema_name = $2 AND table_name = $3 AND index_name = $4`,
+			databaseUID,
+			index.SchemaName,
+			index.TableName,
+			index.IndexName,
+		); err != nil {
+			return errors.Wrapf(err, "failed to delete unused index %q", index.IndexName)
+		}
+	}
+	for _, index := range unusedIndexes {
+		if oldIndexMap[index.key()] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO unused_index (
+				database_id,
+				schema_name,
+				table_name,
+				index_name,
+				unused_since_ts
+			) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (database_id, schema_name, table_name, index_name) DO NOTHING`,
+			databaseUID,
+			index.SchemaName,
+			index.TableName,
+			index.IndexName,
+			index.UnusedSince.Unix(),
+		); err != nil {
+			return errors.Wrapf(err, "failed to create unused index %q", index.IndexName)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrapf(err, "failed to commit transaction")
+	}
+	return nil
+}
+
+func (index *UnusedIndexMessage) key() string {
+	return fmt.Sprintf("%s.%s.%s", index.SchemaName, index.TableName, index.IndexName)
+}
+
+func (*Store) listUnusedIndexImpl(ctx context.Context, tx *Tx, find *FindUnusedIndexMessage) ([]*UnusedIndexMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.DatabaseUID; v != nil {
+		where, args = append(where, fmt.Sprintf("unused_index.database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.InstanceUID; v != nil {
+		where, args = append(where, fmt.Sprintf("db.instance_id = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			unused_index.database_id,
+			unused_index.schema_name,
+			unused_index.table_name,
+			unused_index.index_name,
+			unused_index.unused_since_ts
+		FROM unused_index
+		LEFT JOIN db ON unused_index.database_id = db.id
+		WHERE %s
+		ORDER BY unused_index.database_id, unused_index.schema_name, unused_index.table_name, unused_index.index_name`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query unused indexes")
+	}
+	defer rows.Close()
+
+	var unusedIndexes []*UnusedIndexMessage
+	for rows.Next() {
+		index := &UnusedIndexMessage{}
+		var unusedSince int64
+		if err := rows.Scan(
+			&index.DatabaseUID,
+			&index.SchemaName,
+			&index.TableName,
+			&index.IndexName,
+			&unusedSince,
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan unused index")
+		}
+		index.UnusedSince = time.Unix(unusedSince, 0)
+		unusedIndexes = append(unusedIndexes, index)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to iterate unused indexes")
+	}
+	return unusedIndexes, nil
+}
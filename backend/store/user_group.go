@@ -0,0 +1,247 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+// UserGroupMessage is the message for a group of users.
+type UserGroupMessage struct {
+	ID    int
+	Title string
+	// ExternalID is the ID of the group in the identity provider provisioning it.
+	ExternalID string
+	MemberIDs  []int
+
+	// Output only
+	CreatedTs int64
+	UpdatedTs int64
+}
+
+// FindUserGroupMessage is the message to find user groups.
+type FindUserGroupMessage struct {
+	ID       *int
+	Title    *string
+	MemberID *int
+}
+
+// UpdateUserGroupMessage is the message to update a user group.
+type UpdateUserGroupMessage struct {
+	Title      *string
+	ExternalID *string
+	// MemberIDs replaces the members of the group if it's not nil.
+	MemberIDs *[]int
+}
+
+// GetUserGroup gets a user group.
+func (s *Store) GetUserGroup(ctx context.Context, find *FindUserGroupMessage) (*UserGroupMessage, error) {
+	groups, err := s.ListUserGroups(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	if len(groups) > 1 {
+		return nil, errors.Errorf("found %d user groups with filter %+v, expect 1", len(groups), find)
+	}
+	return groups[0], nil
+}
+
+// ListUserGroups lists the user groups.
+func (s *Store) ListUserGroups(ctx context.Context, find *FindUserGroupMessage) ([]*UserGroupMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("user_group.id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Title; v != nil {
+		where, args = append(where, fmt.Sprintf("user_group.title = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.MemberID; v != nil {
+		where, args = append(where, fmt.Sprintf("user_group.id IN (SELECT group_id FROM user_group_member WHERE principal_id = $%d)", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := s.db.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			user_group.id,
+			user_group.created_ts,
+			user_group.updated_ts,
+			user_group.title,
+			user_group.external_id,
+			ARRAY_AGG (
+				user_group_member.principal_id ORDER BY user_group_member.principal_id
+			) members
+		FROM user_group
+		LEFT JOIN user_group_member ON user_group.id = user_group_member.group_id
+		WHERE %s
+		GROUP BY user_group.id
+		ORDER BY user_group.id`, strings.Join(where, " AND ")),
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list user groups")
+	}
+	defer rows.Close()
+
+	var groups []*UserGroupMessage
+	for rows.Next() {
+		var group UserGroupMessage
+		var members []sql.NullInt32
+		if err := rows.Scan(
+			&group.ID,
+			&group.CreatedTs,
+			&group.UpdatedTs,
+			&group.Title,
+			&group.ExternalID,
+			pq.Array(&members),
+		); err != nil {
+			return nil, errors.Wrapf(err, "failed to scan user group")
+		}
+		for _, member := range members {
+			if member.Valid {
+				group.MemberIDs = append(group.MemberIDs, int(member.Int32))
+			}
+		}
+		groups = append(groups, &group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to list user groups")
+	}
+	return groups, nil
+}
+
+// CreateUserGroup creates a user group with the members.
+func (s *Store) CreateUserGroup(ctx context.Context, create *UserGroupMessage, creatorID int) (*UserGroupMessage, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	group := &UserGroupMessage{
+		Title:      create.Title,
+		ExternalID: create.ExternalID,
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO user_group (
+			creator_id,
+			updater_id,
+			title,
+			external_id
+		) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_ts, updated_ts`,
+		creatorID,
+		creatorID,
+		create.Title,
+		create.ExternalID,
+	).Scan(&group.ID, &group.CreatedTs, &group.UpdatedTs); err != nil {
+		return nil, errors.Wrapf(err, "failed to create user group")
+	}
+	if err := addUserGroupMembersImpl(ctx, tx, group.ID, create.MemberIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	group.MemberIDs = create.MemberIDs
+	return group, nil
+}
+
+// UpdateUserGroup updates a user group.
+func (s *Store) UpdateUserGroup(ctx context.Context, id int, patch *UpdateUserGroupMessage, updaterID int) (*UserGroupMessage, error) {
+	set, args := []string{"updater_id = $1"}, []any{updaterID}
+	if v := patch.Title; v != nil {
+		set, args = append(set, fmt.Sprintf("title = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.ExternalID; v != nil {
+		set, args = append(set, fmt.Sprintf("external_id = $%d", len(args)+1)), append(args, *v)
+	}
+	args = append(args, id)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE user_group
+		SET %s
+		WHERE id = $%d`, strings.Join(set, ", "), len(args)),
+		args...,
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to update user group")
+	}
+	if v := patch.MemberIDs; v != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_group_member WHERE group_id = $1`, id); err != nil {
+			return nil, errors.Wrapf(err, "failed to delete user group members")
+		}
+		if err := addUserGroupMembersImpl(ctx, tx, id, *v); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetUserGroup(ctx, &FindUserGroupMessage{ID: &id})
+}
+
+// AddUserGroupMembers adds the members to the user group, the existing ones are skipped.
+func (s *Store) AddUserGroupMembers(ctx context.Context, id int, memberIDs []int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := addUserGroupMembersImpl(ctx, tx, id, memberIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveUserGroupMembers removes the members from the user group.
+func (s *Store) RemoveUserGroupMembers(ctx context.Context, id int, memberIDs []int) error {
+	if len(memberIDs) == 0 {
+		return nil
+	}
+	if _, err := s.db.db.ExecContext(ctx, `
+		DELETE FROM user_group_member
+		WHERE group_id = $1 AND principal_id = ANY($2)`,
+		id,
+		pq.Array(memberIDs),
+	); err != nil {
+		return errors.Wrapf(err, "failed to remove user group members")
+	}
+	return nil
+}
+
+// DeleteUserGroup deletes a user group with its members.
+func (s *Store) DeleteUserGroup(ctx context.Context, id int) error {
+	if _, err := s.db.db.ExecContext(ctx, `DELETE FROM user_group WHERE id = $1`, id); err != nil {
+		return errors.Wrapf(err, "failed to delete user group")
+	}
+	return nil
+}
+
+func addUserGroupMembersImpl(ctx context.Context, tx *Tx, id int, memberIDs []int) error {
+	for _, memberID := range memberIDs {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO user_group_member (
+				group_id,
+				principal_id
+			) VALUES ($1, $2)
+			ON CONFLICT (group_id, principal_id) DO NOTHING`,
+			id,
+			memberID,
+		); err != nil {
+			return errors.Wrapf(err, "failed to add user group member")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// WebhookEventMessage is the message for a raw VCS webhook delivery.
+type WebhookEventMessage struct {
+	Path   string
+	Header http.Header
+	Body   string
+
+	// Output only
+	UID          int
+	CreatedTs    int64
+	UpdatedTs    int64
+	Status       api.WebhookEventStatus
+	Error        string
+	AttemptCount int
+}
+
+// FindWebhookEventMessage is the message to find webhook events.
+type FindWebhookEventMessage struct {
+	UID    *int
+	Status *api.WebhookEventStatus
+	Limit  *int
+}
+
+// UpdateWebhookEventMessage is the message to update the status of a webhook event.
+type UpdateWebhookEventMessage struct {
+	// OldStatus is the status the event is expected to be in if set, the event isn't updated otherwise.
+	OldStatus *api.WebhookEventStatus
+	Status    api.WebhookEventStatus
+	Error     string
+	// Replay increases the attempt count.
+	Replay bool
+}
+
+const webhookEventColumns = `
+			id,
+			created_ts,
+			updated_ts,
+			path,
+			header,
+			body,
+			status,
+			error,
+			attempt_count`
+
+// CreateWebhookEvent creates a webhook event in the processing status.
+func (s *Store) CreateWebhookEvent(ctx context.Context, create *WebhookEventMessage) (*WebhookEventMessage, error) {
+	header, err := json.Marshal(create.Header)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal webhook event header")
+	}
+	event, err := scanWebhookEvent(s.db.db.QueryRowContext(ctx, `
+		INSERT INTO webhook_event (
+			path,
+			header,
+			body,
+			status
+		) VALUES ($1, $2, $3, $4)
+		RETURNING`+webhookEventColumns,
+		create.Path,
+		header,
+		create.Body,
+		api.WebhookEventProcessing,
+	))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create webhook event")
+	}
+	return event, nil
+}
+
+// GetWebhookEvent gets a webhook event, returns nil if not found.
+func (s *Store) GetWebhookEvent(ctx context.Context, find *FindWebhookEventMessage) (*WebhookEventMessage, error) {
+	events, err := s.ListWebhookEvents(ctx, find)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+	return events[0], nil
+}
+
+// ListWebhookEvents lists the webhook events, the latest first.
+func (s *Store) ListWebhookEvents(ctx context.Context, find *FindWebhookEventMessage) ([]*WebhookEventMessage, error) {
+	where, args := []string{"TRUE"}, []any{}
+	if v := find.UID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+	query := fmt.Sprintf(`
+		SELECT`+webhookEventColumns+`
+		FROM webhook_event
+		WHERE %s
+		ORDER BY id DESC`, strings.Join(where, " AND "))
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+	rows, err := s.db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list webhook events")
+	}
+	defer rows.Close()
+
+	var events []*WebhookEventMessage
+	for rows.Next() {
+		event, err := scanWebhookEvent(rows)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to scan webhook event")
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan webhook events")
+	}
+	return events, nil
+}
+
+// UpdateWebhookEvent updates the status of a webhook event. It returns nil if the event isn't in the old status, e.g.
+// it's being replayed in the meantime.
+func (s *Store) UpdateWebhookEvent(ctx context.Context, uid int, update *UpdateWebhookEventMessage) (*WebhookEventMessage, error) {
+	set, args := []string{"status = $1", "error = $2"}, []any{update.Status, update.Error}
+	if update.Replay {
+		set = append(set, "attempt_count = attempt_count + 1")
+	}
+	where := []string{fmt.Sprintf("id = $%d", len(args)+1)}
+	args = append(args, uid)
+	if v := update.OldStatus; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+	event, err := scanWebhookEvent(s.db.db.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE webhook_event
+		SET %s
+		WHERE %s
+		RETURNING`+webhookEventColumns, strings.Join(set, ", "), strings.Join(where, " AND ")),
+		args...,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to update webhook event")
+	}
+	return event, nil
+}
+
+// DeleteWebhookEvent deletes a webhook event.
+func (s *Store) DeleteWebhookEvent(ctx context.Context, uid int) error {
+	if _, err := s.db.db.ExecContext(ctx, `DELETE FROM webhook_event WHERE id = $1`, uid); err != nil {
+		return errors.Wrapf(err, "failed to delete webhook event")
+	}
+	return nil
+}
+
+// DeleteWebhookEventsBefore deletes the webhook events in the status last updated before the timestamp, and returns the
+// number deleted.
+func (s *Store) DeleteWebhookEventsBefore(ctx context.Context, status api.WebhookEventStatus, updatedTs int64) (int64, error) {
+	result, err := s.db.db.ExecContext(ctx, `DELETE FROM webhook_event WHERE status = $1 AND updated_ts < $2`, status, updatedTs)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to delete webhook events")
+	}
+	return result.RowsAffected()
+}
+
+func scanWebhookEvent(row interface{ Scan(...any) error }) (*WebhookEventMessage, error) {
+	var event WebhookEventMessage
+	var header []byte
+	if err := row.Scan(
+		&event.UID,
+		&event.CreatedTs,
+		&event.UpdatedTs,
+		&event.Path,
+		&header,
+		&event.Body,
+		&event.Status,
+		&event.Error,
+		&event.AttemptCount,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(header, &event.Header); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal webhook event header")
+	}
+	return &event, nil
+}
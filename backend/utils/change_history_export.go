@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// ChangeHistoryExportFormat is the format of the exported change history.
+type ChangeHistoryExportFormat string
+
+const (
+	// ChangeHistoryExportFormatLiquibase is the Liquibase XML changelog.
+	ChangeHistoryExportFormatLiquibase ChangeHistoryExportFormat = "liquibase"
+	// ChangeHistoryExportFormatFlyway is the zip archive of the Flyway versioned scripts.
+	ChangeHistoryExportFormatFlyway ChangeHistoryExportFormat = "flyway"
+)
+
+// ChangeHistoryExportEntry is a change in the exported change history.
+type ChangeHistoryExportEntry struct {
+	Sequence    int64
+	Type        db.MigrationType
+	Status      db.MigrationStatus
+	Version     string
+	Description string
+	Statement   string
+	Author      string
+	CreatedTs   int64
+}
+
+var (
+	flywayVersionRegexp     = regexp.MustCompile(`^[0-9]+([._][0-9]+)*$`)
+	flywayDescriptionRegexp = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+const flywayDescriptionMaxLength = 64
+
+// ExportChangeHistory renders the done changes of the change history in the given format.
+// The baselines are exported as the tags in Liquibase, and they are skipped in Flyway as they have no statements.
+func ExportChangeHistory(entries []*ChangeHistoryExportEntry, format ChangeHistoryExportFormat) ([]byte, error) {
+	var doneList []*ChangeHistoryExportEntry
+	for _, entry := range entries {
+		if entry.Status == db.Done {
+			doneList = append(doneList, entry)
+		}
+	}
+	sort.SliceStable(doneList, func(i, j int) bool {
+		return doneList[i].Sequence < doneList[j].Sequence
+	})
+
+	switch format {
+	case ChangeHistoryExportFormatLiquibase:
+		return exportLiquibaseChangelog(doneList)
+	case ChangeHistoryExportFormatFlyway:
+		return exportFlywayScripts(doneList)
+	default:
+		return nil, errors.Errorf("unsupported change history export format %q", format)
+	}
+}
+
+type liquibaseChangelog struct {
+	XMLName        xml.Name              `xml:"databaseChangeLog"`
+	Xmlns          string                `xml:"xmlns,attr"`
+	XmlnsXsi       string                `xml:"xmlns:xsi,attr"`
+	SchemaLocation string                `xml:"xsi:schemaLocation,attr"`
+	ChangeSets     []*liquibaseChangeSet `xml:"changeSet"`
+}
+
+type liquibaseChangeSet struct {
+	ID          string                `xml:"id,attr"`
+	Author      string                `xml:"author,attr"`
+	Comment     string                `xml:"comment,omitempty"`
+	TagDatabase *liquibaseTagDatabase `xml:"tagDatabase"`
+	SQL         *liquibaseSQL         `xml:"sql"`
+}
+
+type liquibaseTagDatabase struct {
+	Tag string `xml:"tag,attr"`
+}
+
+type liquibaseSQL struct {
+	SplitStatements bool   `xml:"splitStatements,attr"`
+	StripComments   bool   `xml:"stripComments,attr"`
+	Statement       string `xml:",cdata"`
+}
+
+func exportLiquibaseChangelog(entries []*ChangeHistoryExportEntry) ([]byte, error) {
+	changelog := &liquibaseChangelog{
+		Xmlns:          "http://www.liquibase.org/xml/ns/dbchangelog",
+		XmlnsXsi:       "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: "http://www.liquibase.org/xml/ns/dbchangelog http://www.liquibase.org/xml/ns/dbchangelog/dbchangelog-latest.xsd",
+	}
+	for _, entry := range entries {
+		changeSet := &liquibaseChangeSet{
+			ID:      entry.Version,
+			Author:  entry.Author,
+			Comment: getChangeHistoryExportComment(entry),
+		}
+		if entry.Type == db.Baseline {
+			changeSet.TagDatabase = &liquibaseTagDatabase{Tag: entry.Version}
+		} else {
+			// The statement is executed as a whole as it was in Bytebase.
+			changeSet.SQL = &liquibaseSQL{Statement: entry.Statement}
+		}
+		changelog.ChangeSets = append(changelog.ChangeSets, changeSet)
+	}
+
+	content, err := xml.MarshalIndent(changelog, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(content, '\n')...), nil
+}
+
+// exportFlywayScripts keeps the versions if all of them are valid Flyway versions,
+// otherwise the changes are versioned by the sequences to keep the order.
+func exportFlywayScripts(entries []*ChangeHistoryExportEntry) ([]byte, error) {
+	var migrationList []*ChangeHistoryExportEntry
+	useSequence := false
+	for _, entry := range entries {
+		if entry.Type == db.Baseline {
+			continue
+		}
+		if !flywayVersionRegexp.MatchString(entry.Version) {
+			useSequence = true
+		}
+		migrationList = append(migrationList, entry)
+	}
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, entry := range migrationList {
+		version := entry.Version
+		if useSequence {
+			version = fmt.Sprintf("%d", entry.Sequence)
+		}
+		file, err := writer.Create(fmt.Sprintf("V%s__%s.sql", version, getFlywayDescription(entry)))
+		if err != nil {
+			return nil, err
+		}
+		var content strings.Builder
+		_, _ = fmt.Fprintf(&content, "-- Bytebase version: %s\n", entry.Version)
+		_, _ = fmt.Fprintf(&content, "-- %s\n\n", getChangeHistoryExportComment(entry))
+		_, _ = content.WriteString(entry.Statement)
+		if !strings.HasSuffix(entry.Statement, "\n") {
+			_, _ = content.WriteString("\n")
+		}
+		if _, err := file.Write([]byte(content.String())); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func getChangeHistoryExportComment(entry *ChangeHistoryExportEntry) string {
+	comment := fmt.Sprintf("%s by %s at %s", entry.Type, entry.Author, time.Unix(entry.CreatedTs, 0).UTC().Format(time.RFC3339))
+	if entry.Description != "" {
+		comment = fmt.Sprintf("%s: %s", comment, strings.ReplaceAll(entry.Description, "\n", " "))
+	}
+	return comment
+}
+
+func getFlywayDescription(entry *ChangeHistoryExportEntry) string {
+	description := strings.Trim(flywayDescriptionRegexp.ReplaceAllString(entry.Description, "_"), "_")
+	if len(description) > flywayDescriptionMaxLength {
+		description = strings.TrimRight(description[:flywayDescriptionMaxLength], "_")
+	}
+	if description == "" {
+		return strings.ToLower(string(entry.Type))
+	}
+	return description
+}
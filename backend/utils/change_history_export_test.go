@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestExportChangeHistory(t *testing.T) {
+	entries := []*ChangeHistoryExportEntry{
+		{Sequence: 3, Type: db.Migrate, Status: db.Done, Version: "2", Description: "Add email column", Statement: "ALTER TABLE users ADD COLUMN email TEXT;", Author: "alice", CreatedTs: 1697241600},
+		{Sequence: 1, Type: db.Baseline, Status: db.Done, Version: "1", Author: "alice", CreatedTs: 1697155200},
+		{Sequence: 2, Type: db.Migrate, Status: db.Failed, Version: "1.1", Statement: "SELECT 1;", Author: "bob", CreatedTs: 1697200000},
+	}
+
+	content, err := ExportChangeHistory(entries, ChangeHistoryExportFormatLiquibase)
+	require.NoError(t, err)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<databaseChangeLog xmlns="http://www.liquibase.org/xml/ns/dbchangelog" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.liquibase.org/xml/ns/dbchangelog http://www.liquibase.org/xml/ns/dbchangelog/dbchangelog-latest.xsd">
+  <changeSet id="1" author="alice">
+    <comment>BASELINE by alice at 2023-10-13T00:00:00Z</comment>
+    <tagDatabase tag="1"></tagDatabase>
+  </changeSet>
+  <changeSet id="2" author="alice">
+    <comment>MIGRATE by alice at 2023-10-14T00:00:00Z: Add email column</comment>
+    <sql splitStatements="false" stripComments="false"><![CDATA[ALTER TABLE users ADD COLUMN email TEXT;]]></sql>
+  </changeSet>
+</databaseChangeLog>
+`, string(content))
+
+	content, err = ExportChangeHistory(entries, ChangeHistoryExportFormatFlyway)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"V2__Add_email_column.sql": "-- Bytebase version: 2\n-- MIGRATE by alice at 2023-10-14T00:00:00Z: Add email column\n\nALTER TABLE users ADD COLUMN email TEXT;\n",
+	}, readZipFiles(t, content))
+
+	// The changes are versioned by the sequences if any version isn't a valid Flyway version.
+	entries = append(entries, &ChangeHistoryExportEntry{Sequence: 4, Type: db.Data, Status: db.Done, Version: "20231014-dml", Statement: "DELETE FROM users;\n", Author: "bob", CreatedTs: 1697241600})
+	content, err = ExportChangeHistory(entries, ChangeHistoryExportFormatFlyway)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"V3__Add_email_column.sql": "-- Bytebase version: 2\n-- MIGRATE by alice at 2023-10-14T00:00:00Z: Add email column\n\nALTER TABLE users ADD COLUMN email TEXT;\n",
+		"V4__data.sql":             "-- Bytebase version: 20231014-dml\n-- DATA by bob at 2023-10-14T00:00:00Z\n\nDELETE FROM users;\n",
+	}, readZipFiles(t, content))
+
+	_, err = ExportChangeHistory(entries, "yaml")
+	require.Error(t, err)
+}
+
+func readZipFiles(t *testing.T, content []byte) map[string]string {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		files[file.Name] = string(data)
+	}
+	return files
+}
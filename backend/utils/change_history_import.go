@@ -0,0 +1,277 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// ImportedChange is a change converted from the history table of Flyway or Liquibase.
+type ImportedChange struct {
+	Source      db.MigrationSource
+	Type        db.MigrationType
+	Status      db.MigrationStatus
+	Version     string
+	Description string
+	// Statement records the script of the change, the tools don't keep the statements.
+	Statement           string
+	ExecutedTs          int64
+	ExecutionDurationNs int64
+}
+
+// FlywayHistoryRow is a row of the flyway_schema_history table.
+type FlywayHistoryRow struct {
+	InstalledRank int64
+	// Version is empty for the repeatable migrations.
+	Version     string
+	Description string
+	Type        string
+	Script      string
+	Checksum    sql.NullInt64
+	InstalledBy string
+	InstalledTs int64
+	// ExecutionTimeMs is the execution time in milliseconds.
+	ExecutionTimeMs int64
+	Success         bool
+}
+
+// LiquibaseHistoryRow is a row of the DATABASECHANGELOG table.
+type LiquibaseHistoryRow struct {
+	ID            string
+	Author        string
+	Filename      string
+	ExecutedTs    int64
+	OrderExecuted int64
+	ExecType      string
+	MD5Sum        string
+	Description   string
+	Comments      string
+	Tag           string
+}
+
+// DefaultChangeHistoryTable returns the default history table of the tool.
+// Liquibase creates the table without quoting, which is folded to the lower case in PostgreSQL.
+func DefaultChangeHistoryTable(source db.MigrationSource, engine db.Type) string {
+	if source == db.Flyway {
+		return "flyway_schema_history"
+	}
+	if engine == db.Postgres {
+		return "databasechangelog"
+	}
+	return "DATABASECHANGELOG"
+}
+
+// ReadChangeHistory reads and converts the history table of Flyway or Liquibase in the database.
+// The table could be qualified by the schema in PostgreSQL, e.g. "flyway.flyway_schema_history".
+// The skipped rows are returned with the reasons.
+func ReadChangeHistory(ctx context.Context, engine db.Type, conn *sql.DB, source db.MigrationSource, table string) ([]*ImportedChange, []string, error) {
+	var quotedTable string
+	switch engine {
+	case db.Postgres:
+		var parts []string
+		for _, part := range strings.Split(table, ".") {
+			parts = append(parts, quotePostgresIdentifier(part))
+		}
+		quotedTable = strings.Join(parts, ".")
+	case db.MySQL, db.TiDB, db.MariaDB:
+		quotedTable = quoteMySQLIdentifier(table)
+	default:
+		return nil, nil, errors.Errorf("importing the change history is not supported for engine %q", engine)
+	}
+	epoch := func(column string) string {
+		if engine == db.Postgres {
+			return fmt.Sprintf("CAST(EXTRACT(EPOCH FROM %s) AS BIGINT)", column)
+		}
+		return fmt.Sprintf("UNIX_TIMESTAMP(%s)", column)
+	}
+
+	switch source {
+	case db.Flyway:
+		rows, err := readFlywayHistory(ctx, conn, quotedTable, epoch("installed_on"))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read the Flyway history table %q", table)
+		}
+		changes, skipped := ConvertFlywayHistory(rows)
+		return changes, skipped, nil
+	case db.Liquibase:
+		rows, err := readLiquibaseHistory(ctx, conn, quotedTable, epoch("DATEEXECUTED"))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read the Liquibase history table %q", table)
+		}
+		changes, skipped := ConvertLiquibaseHistory(rows)
+		return changes, skipped, nil
+	default:
+		return nil, nil, errors.Errorf("unsupported change history source %q", source)
+	}
+}
+
+func readFlywayHistory(ctx context.Context, conn *sql.DB, table, installedOn string) ([]*FlywayHistoryRow, error) {
+	query := fmt.Sprintf(`
+		SELECT installed_rank, version, description, type, script, checksum, installed_by, %s, execution_time, success
+		FROM %s
+		ORDER BY installed_rank`, installedOn, table)
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*FlywayHistoryRow
+	for rows.Next() {
+		row := &FlywayHistoryRow{}
+		var version sql.NullString
+		if err := rows.Scan(&row.InstalledRank, &version, &row.Description, &row.Type, &row.Script, &row.Checksum, &row.InstalledBy, &row.InstalledTs, &row.ExecutionTimeMs, &row.Success); err != nil {
+			return nil, err
+		}
+		row.Version = version.String
+		list = append(list, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func readLiquibaseHistory(ctx context.Context, conn *sql.DB, table, dateExecuted string) ([]*LiquibaseHistoryRow, error) {
+	query := fmt.Sprintf(`
+		SELECT ID, AUTHOR, FILENAME, %s, ORDEREXECUTED, EXECTYPE, MD5SUM, DESCRIPTION, COMMENTS, TAG
+		FROM %s
+		ORDER BY ORDEREXECUTED`, dateExecuted, table)
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*LiquibaseHistoryRow
+	for rows.Next() {
+		row := &LiquibaseHistoryRow{}
+		var md5Sum, description, comments, tag sql.NullString
+		if err := rows.Scan(&row.ID, &row.Author, &row.Filename, &row.ExecutedTs, &row.OrderExecuted, &row.ExecType, &md5Sum, &description, &comments, &tag); err != nil {
+			return nil, err
+		}
+		row.MD5Sum, row.Description, row.Comments, row.Tag = md5Sum.String, description.String, comments.String, tag.String
+		list = append(list, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ConvertFlywayHistory converts the Flyway history in the installed order.
+// The versioned migrations keep the Flyway versions, and the repeatable migrations are versioned as "R.<installed_rank>".
+// If a version is installed more than once, e.g. failed and then repaired, the last installation wins.
+func ConvertFlywayHistory(rows []*FlywayHistoryRow) ([]*ImportedChange, []string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].InstalledRank < rows[j].InstalledRank
+	})
+
+	var changes []*ImportedChange
+	var skipped []string
+	versionIndex := make(map[string]int)
+	for _, row := range rows {
+		var migrationType db.MigrationType
+		switch row.Type {
+		case "SQL", "JDBC", "SPRING_JDBC", "SCRIPT", "CUSTOM":
+			migrationType = db.Migrate
+		case "BASELINE", "SQL_BASELINE", "JDBC_BASELINE":
+			migrationType = db.Baseline
+		case "SCHEMA":
+			// The marker of the schemas created by Flyway, which changes nothing else.
+			continue
+		default:
+			// The undo migrations and the deletion markers don't leave the changes.
+			skipped = append(skipped, fmt.Sprintf("installed rank %d: %s %q is not a forward migration", row.InstalledRank, row.Type, row.Script))
+			continue
+		}
+		version := row.Version
+		if version == "" {
+			version = fmt.Sprintf("R.%d", row.InstalledRank)
+		}
+		status := db.Done
+		if !row.Success {
+			status = db.Failed
+		}
+		statement := fmt.Sprintf("-- Imported from Flyway: %s", row.Script)
+		if row.Checksum.Valid {
+			statement += fmt.Sprintf(", checksum %d", row.Checksum.Int64)
+		}
+		if row.InstalledBy != "" {
+			statement += fmt.Sprintf(", installed by %s", row.InstalledBy)
+		}
+		change := &ImportedChange{
+			Source:              db.Flyway,
+			Type:                migrationType,
+			Status:              status,
+			Version:             version,
+			Description:         row.Description,
+			Statement:           statement,
+			ExecutedTs:          row.InstalledTs,
+			ExecutionDurationNs: row.ExecutionTimeMs * 1000 * 1000,
+		}
+		if i, ok := versionIndex[version]; ok {
+			changes[i] = change
+			continue
+		}
+		versionIndex[version] = len(changes)
+		changes = append(changes, change)
+	}
+	return changes, skipped
+}
+
+// ConvertLiquibaseHistory converts the Liquibase history in the executed order.
+// The changesets have no versions, so they are versioned by the zero-padded ORDEREXECUTED to keep the order.
+func ConvertLiquibaseHistory(rows []*LiquibaseHistoryRow) ([]*ImportedChange, []string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].OrderExecuted < rows[j].OrderExecuted
+	})
+
+	var changes []*ImportedChange
+	var skipped []string
+	for _, row := range rows {
+		changeSet := fmt.Sprintf("%s::%s::%s", row.Filename, row.ID, row.Author)
+		migrationType, status := db.Migrate, db.Done
+		switch row.ExecType {
+		case "EXECUTED", "RERAN":
+		case "FAILED":
+			status = db.Failed
+		case "MARK_RAN":
+			// The change set is marked as applied without running, like a baseline.
+			migrationType = db.Baseline
+		default:
+			skipped = append(skipped, fmt.Sprintf("change set %s is %s", changeSet, row.ExecType))
+			continue
+		}
+		description := changeSet
+		if row.Description != "" {
+			description += ": " + row.Description
+		}
+		if row.Tag != "" {
+			description += fmt.Sprintf(" (tag %s)", row.Tag)
+		}
+		statement := fmt.Sprintf("-- Imported from Liquibase: %s", changeSet)
+		if row.MD5Sum != "" {
+			statement += fmt.Sprintf(", checksum %s", row.MD5Sum)
+		}
+		if row.Comments != "" {
+			statement += "\n-- " + strings.ReplaceAll(row.Comments, "\n", "\n-- ")
+		}
+		changes = append(changes, &ImportedChange{
+			Source:      db.Liquibase,
+			Type:        migrationType,
+			Status:      status,
+			Version:     fmt.Sprintf("%05d", row.OrderExecuted),
+			Description: description,
+			Statement:   statement,
+			ExecutedTs:  row.ExecutedTs,
+		})
+	}
+	return changes, skipped
+}
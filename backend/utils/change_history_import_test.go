@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestConvertFlywayHistory(t *testing.T) {
+	rows := []*FlywayHistoryRow{
+		{InstalledRank: 4, Version: "2", Description: "add email", Type: "SQL", Script: "V2__add_email.sql", Checksum: sql.NullInt64{Int64: 42, Valid: true}, InstalledTs: 400, ExecutionTimeMs: 12, Success: false},
+		{InstalledRank: 1, Type: "SCHEMA", Script: "<< Flyway Schema Creation >>", Success: true},
+		{InstalledRank: 2, Version: "1", Description: "<< Flyway Baseline >>", Type: "BASELINE", Script: "<< Flyway Baseline >>", InstalledTs: 200, Success: true},
+		{InstalledRank: 3, Description: "views", Type: "SQL", Script: "R__views.sql", InstalledTs: 300, Success: true},
+		{InstalledRank: 5, Version: "2", Description: "add email", Type: "SQL", Script: "V2__add_email.sql", InstalledBy: "ci", InstalledTs: 500, ExecutionTimeMs: 3, Success: true},
+		{InstalledRank: 6, Version: "2", Description: "add email", Type: "UNDO_SQL", Script: "U2__add_email.sql", InstalledTs: 600, Success: true},
+	}
+
+	changes, skipped := ConvertFlywayHistory(rows)
+	require.Equal(t, []*ImportedChange{
+		{Source: db.Flyway, Type: db.Baseline, Status: db.Done, Version: "1", Description: "<< Flyway Baseline >>", Statement: "-- Imported from Flyway: << Flyway Baseline >>", ExecutedTs: 200},
+		{Source: db.Flyway, Type: db.Migrate, Status: db.Done, Version: "R.3", Description: "views", Statement: "-- Imported from Flyway: R__views.sql", ExecutedTs: 300},
+		{Source: db.Flyway, Type: db.Migrate, Status: db.Done, Version: "2", Description: "add email", Statement: "-- Imported from Flyway: V2__add_email.sql, installed by ci", ExecutedTs: 500, ExecutionDurationNs: 3000000},
+	}, changes)
+	require.Equal(t, []string{`installed rank 6: UNDO_SQL "U2__add_email.sql" is not a forward migration`}, skipped)
+}
+
+func TestConvertLiquibaseHistory(t *testing.T) {
+	rows := []*LiquibaseHistoryRow{
+		{ID: "2", Author: "bob", Filename: "changelog.xml", ExecutedTs: 200, OrderExecuted: 2, ExecType: "FAILED", Description: "addColumn"},
+		{ID: "1", Author: "alice", Filename: "changelog.xml", ExecutedTs: 100, OrderExecuted: 1, ExecType: "EXECUTED", MD5Sum: "8:abc", Description: "createTable tableName=users", Tag: "v1"},
+		{ID: "3", Author: "bob", Filename: "changelog.xml", ExecutedTs: 300, OrderExecuted: 3, ExecType: "MARK_RAN", Comments: "already applied"},
+		{ID: "4", Author: "bob", Filename: "changelog.xml", ExecutedTs: 400, OrderExecuted: 4, ExecType: "SKIPPED"},
+	}
+
+	changes, skipped := ConvertLiquibaseHistory(rows)
+	require.Equal(t, []*ImportedChange{
+		{Source: db.Liquibase, Type: db.Migrate, Status: db.Done, Version: "00001", Description: "changelog.xml::1::alice: createTable tableName=users (tag v1)", Statement: "-- Imported from Liquibase: changelog.xml::1::alice, checksum 8:abc", ExecutedTs: 100},
+		{Source: db.Liquibase, Type: db.Migrate, Status: db.Failed, Version: "00002", Description: "changelog.xml::2::bob: addColumn", Statement: "-- Imported from Liquibase: changelog.xml::2::bob", ExecutedTs: 200},
+		{Source: db.Liquibase, Type: db.Baseline, Status: db.Done, Version: "00003", Description: "changelog.xml::3::bob", Statement: "-- Imported from Liquibase: changelog.xml::3::bob\n-- already applied", ExecutedTs: 300},
+	}, changes)
+	require.Equal(t, []string{"change set changelog.xml::4::bob is SKIPPED"}, skipped)
+}
+
+func TestDefaultChangeHistoryTable(t *testing.T) {
+	require.Equal(t, "flyway_schema_history", DefaultChangeHistoryTable(db.Flyway, db.Postgres))
+	require.Equal(t, "databasechangelog", DefaultChangeHistoryTable(db.Liquibase, db.Postgres))
+	require.Equal(t, "DATABASECHANGELOG", DefaultChangeHistoryTable(db.Liquibase, db.MySQL))
+}
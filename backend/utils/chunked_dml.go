@@ -0,0 +1,451 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+)
+
+const (
+	defaultChunkedDMLBatchSize = 1000
+	// chunkedDMLPollInterval is the interval of checking whether the paused or throttled execution could continue.
+	chunkedDMLPollInterval = 1 * time.Second
+)
+
+// ChunkedDMLOption is the option of the chunked DML execution.
+type ChunkedDMLOption struct {
+	// BatchSize is the size of the primary key range of each chunk, defaults to 1000.
+	BatchSize int64
+	// SleepInterval is the interval between the chunks.
+	SleepInterval time.Duration
+	// MaxReplicationLag throttles the chunks while the replication lag exceeds it, zero disables the throttling.
+	MaxReplicationLag time.Duration
+	// GetReplicationLag returns the current replication lag.
+	GetReplicationLag func(ctx context.Context) (time.Duration, error)
+	// IsPaused returns true if the execution is paused, it's checked before each chunk.
+	IsPaused func() bool
+	// OnProgress reports the completed and total chunks, the comment is the reason if the execution is postponed.
+	OnProgress func(completed, total int64, paused bool, comment string)
+}
+
+// ChunkedDMLStatement is an UPDATE or DELETE statement executed in the primary key range chunks.
+type ChunkedDMLStatement struct {
+	Schema string
+	Table  string
+	// buildChunkQuery returns the statement with the range condition on the primary key column,
+	// the lower (inclusive) and upper (exclusive) bounds are the query parameters.
+	buildChunkQuery func(primaryKey string) (string, error)
+}
+
+type dmlChunk struct {
+	query string
+	lower int64
+	upper int64
+}
+
+// ParseChunkedDML parses the statement into the single table UPDATE and DELETE statements to be executed in chunks.
+func ParseChunkedDML(engine db.Type, statement string) ([]*ChunkedDMLStatement, error) {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		return parseMySQLChunkedDML(statement)
+	case db.Postgres:
+		return parsePostgresChunkedDML(statement)
+	default:
+		return nil, errors.Errorf("chunked DML is not supported for engine %q", engine)
+	}
+}
+
+func parseMySQLChunkedDML(statement string) ([]*ChunkedDMLStatement, error) {
+	singleSQLs, err := parser.SplitMultiSQL(parser.MySQL, statement)
+	if err != nil {
+		return nil, err
+	}
+	p := tidbparser.New()
+	var result []*ChunkedDMLStatement
+	for _, singleSQL := range singleSQLs {
+		if singleSQL.Empty {
+			continue
+		}
+		node, err := p.ParseOneStmt(singleSQL.Text, "", "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse statement at line %d", singleSQL.LastLine)
+		}
+		var tableRefs *tidbast.TableRefsClause
+		var where *tidbast.ExprNode
+		switch node := node.(type) {
+		case *tidbast.UpdateStmt:
+			if node.MultipleTable || node.With != nil || node.Order != nil || node.Limit != nil {
+				return nil, errors.Errorf("chunked DML only supports the single table UPDATE without WITH, ORDER BY and LIMIT, got %q", singleSQL.Text)
+			}
+			tableRefs, where = node.TableRefs, &node.Where
+		case *tidbast.DeleteStmt:
+			if node.IsMultiTable || node.With != nil || node.Order != nil || node.Limit != nil {
+				return nil, errors.Errorf("chunked DML only supports the single table DELETE without WITH, ORDER BY and LIMIT, got %q", singleSQL.Text)
+			}
+			tableRefs, where = node.TableRefs, &node.Where
+		default:
+			return nil, errors.Errorf("chunked DML only supports UPDATE and DELETE statements, got %q", singleSQL.Text)
+		}
+		if tableRefs == nil || tableRefs.TableRefs == nil || tableRefs.TableRefs.Right != nil {
+			return nil, errors.Errorf("chunked DML only supports the single table statement, got %q", singleSQL.Text)
+		}
+		tableSource, ok := tableRefs.TableRefs.Left.(*tidbast.TableSource)
+		if !ok {
+			return nil, errors.Errorf("chunked DML only supports the single table statement, got %q", singleSQL.Text)
+		}
+		tableName, ok := tableSource.Source.(*tidbast.TableName)
+		if !ok {
+			return nil, errors.Errorf("chunked DML only supports the single table statement, got %q", singleSQL.Text)
+		}
+
+		originalWhere := *where
+		result = append(result, &ChunkedDMLStatement{
+			Schema: tableName.Schema.O,
+			Table:  tableName.Name.O,
+			buildChunkQuery: func(primaryKey string) (string, error) {
+				column := quoteMySQLIdentifier(primaryKey)
+				rangeNode, err := p.ParseOneStmt(fmt.Sprintf("SELECT 1 WHERE %s >= ? AND %s < ?", column, column), "", "")
+				if err != nil {
+					return "", err
+				}
+				rangeExpr := rangeNode.(*tidbast.SelectStmt).Where
+				if originalWhere == nil {
+					*where = rangeExpr
+				} else {
+					*where = &tidbast.BinaryOperationExpr{
+						Op: opcode.LogicAnd,
+						L:  &tidbast.ParenthesesExpr{Expr: originalWhere},
+						R:  rangeExpr,
+					}
+				}
+				defer func() {
+					*where = originalWhere
+				}()
+				var buf strings.Builder
+				if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &buf)); err != nil {
+					return "", err
+				}
+				return buf.String(), nil
+			},
+		})
+	}
+	return result, nil
+}
+
+func parsePostgresChunkedDML(statement string) ([]*ChunkedDMLStatement, error) {
+	singleSQLs, err := parser.SplitMultiSQL(parser.Postgres, statement)
+	if err != nil {
+		return nil, err
+	}
+	var result []*ChunkedDMLStatement
+	for _, singleSQL := range singleSQLs {
+		if singleSQL.Empty {
+			continue
+		}
+		tree, err := pgquery.Parse(singleSQL.Text)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse statement at line %d", singleSQL.LastLine)
+		}
+		if len(tree.Stmts) != 1 {
+			return nil, errors.Errorf("expecting one statement, got %d", len(tree.Stmts))
+		}
+		var relation *pgquery.RangeVar
+		var where **pgquery.Node
+		switch node := tree.Stmts[0].Stmt.Node.(type) {
+		case *pgquery.Node_UpdateStmt:
+			if node.UpdateStmt.WithClause != nil {
+				return nil, errors.Errorf("chunked DML only supports the UPDATE without WITH, got %q", singleSQL.Text)
+			}
+			relation, where = node.UpdateStmt.Relation, &node.UpdateStmt.WhereClause
+		case *pgquery.Node_DeleteStmt:
+			if node.DeleteStmt.WithClause != nil {
+				return nil, errors.Errorf("chunked DML only supports the DELETE without WITH, got %q", singleSQL.Text)
+			}
+			relation, where = node.DeleteStmt.Relation, &node.DeleteStmt.WhereClause
+		default:
+			return nil, errors.Errorf("chunked DML only supports UPDATE and DELETE statements, got %q", singleSQL.Text)
+		}
+		// The range condition refers to the target table by the alias if any, because the FROM or USING clause could join other tables.
+		qualifier := relation.Relname
+		if relation.Alias != nil && relation.Alias.Aliasname != "" {
+			qualifier = relation.Alias.Aliasname
+		}
+
+		originalWhere := *where
+		result = append(result, &ChunkedDMLStatement{
+			Schema: relation.Schemaname,
+			Table:  relation.Relname,
+			buildChunkQuery: func(primaryKey string) (string, error) {
+				column := func() *pgquery.Node {
+					return pgquery.MakeColumnRefNode([]*pgquery.Node{pgquery.MakeStrNode(qualifier), pgquery.MakeStrNode(primaryKey)}, -1)
+				}
+				args := []*pgquery.Node{
+					pgquery.MakeAExprNode(pgquery.A_Expr_Kind_AEXPR_OP, []*pgquery.Node{pgquery.MakeStrNode(">=")}, column(), pgquery.MakeParamRefNode(1, -1), -1),
+					pgquery.MakeAExprNode(pgquery.A_Expr_Kind_AEXPR_OP, []*pgquery.Node{pgquery.MakeStrNode("<")}, column(), pgquery.MakeParamRefNode(2, -1), -1),
+				}
+				if originalWhere != nil {
+					args = append([]*pgquery.Node{originalWhere}, args...)
+				}
+				*where = pgquery.MakeBoolExprNode(pgquery.BoolExprType_AND_EXPR, args, -1)
+				defer func() {
+					*where = originalWhere
+				}()
+				return pgquery.Deparse(tree)
+			},
+		})
+	}
+	return result, nil
+}
+
+// ExecuteChunkedDML executes the UPDATE and DELETE statements in the primary key range chunks, each chunk is committed on its own.
+// The tables must have the single-column integer primary key. It returns the total affected rows.
+func ExecuteChunkedDML(ctx context.Context, engine db.Type, sqlDB *sql.DB, statement string, option ChunkedDMLOption) (int64, error) {
+	if option.BatchSize <= 0 {
+		option.BatchSize = defaultChunkedDMLBatchSize
+	}
+	stmts, err := ParseChunkedDML(engine, statement)
+	if err != nil {
+		return 0, err
+	}
+
+	// Plan all the chunks ahead to report the progress.
+	var chunks []*dmlChunk
+	for _, stmt := range stmts {
+		primaryKey, err := getChunkPrimaryKey(ctx, engine, sqlDB, stmt.Schema, stmt.Table)
+		if err != nil {
+			return 0, err
+		}
+		query, err := stmt.buildChunkQuery(primaryKey)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to build the chunk statement for table %q", stmt.Table)
+		}
+		minValue, maxValue, err := getChunkPrimaryKeyRange(ctx, engine, sqlDB, stmt.Schema, stmt.Table, primaryKey)
+		if err != nil {
+			return 0, err
+		}
+		if minValue.Valid {
+			chunks = append(chunks, splitDMLChunks(query, minValue.Int64, maxValue.Int64, option.BatchSize)...)
+		}
+	}
+
+	report := func(completed int, paused bool, comment string) {
+		if option.OnProgress != nil {
+			option.OnProgress(int64(completed), int64(len(chunks)), paused, comment)
+		}
+	}
+	var affectedRows int64
+	for i, chunk := range chunks {
+		for option.IsPaused != nil && option.IsPaused() {
+			report(i, true /* paused */, "")
+			if err := sleepWithContext(ctx, chunkedDMLPollInterval); err != nil {
+				return affectedRows, err
+			}
+		}
+		if option.MaxReplicationLag > 0 && option.GetReplicationLag != nil {
+			for {
+				lag, err := option.GetReplicationLag(ctx)
+				if err != nil {
+					return affectedRows, errors.Wrapf(err, "failed to get the replication lag")
+				}
+				if lag <= option.MaxReplicationLag {
+					break
+				}
+				report(i, false /* paused */, fmt.Sprintf("postponing due to the replication lag %v", lag.Round(time.Second)))
+				if err := sleepWithContext(ctx, chunkedDMLPollInterval); err != nil {
+					return affectedRows, err
+				}
+			}
+		}
+		res, err := sqlDB.ExecContext(ctx, chunk.query, chunk.lower, chunk.upper)
+		if err != nil {
+			return affectedRows, errors.Wrapf(err, "failed to execute the chunk [%d, %d) of %q", chunk.lower, chunk.upper, chunk.query)
+		}
+		if rows, err := res.RowsAffected(); err == nil {
+			affectedRows += rows
+		}
+		report(i+1, false /* paused */, "")
+		if option.SleepInterval > 0 && i+1 < len(chunks) {
+			if err := sleepWithContext(ctx, option.SleepInterval); err != nil {
+				return affectedRows, err
+			}
+		}
+	}
+	return affectedRows, nil
+}
+
+// splitDMLChunks splits the primary key range [minValue, maxValue] into the chunks of the batch size.
+func splitDMLChunks(query string, minValue, maxValue, batchSize int64) []*dmlChunk {
+	var chunks []*dmlChunk
+	for lower := minValue; lower <= maxValue; lower += batchSize {
+		upper := lower + batchSize
+		if upper > maxValue {
+			upper = maxValue + 1
+		}
+		chunks = append(chunks, &dmlChunk{query: query, lower: lower, upper: upper})
+		if upper > maxValue {
+			break
+		}
+	}
+	return chunks
+}
+
+func getChunkPrimaryKey(ctx context.Context, engine db.Type, sqlDB *sql.DB, schema, table string) (string, error) {
+	var query string
+	var args []any
+	var integerTypes map[string]bool
+	if engine == db.Postgres {
+		query = `
+			SELECT a.attname, format_type(a.atttypid, a.atttypmod)
+			FROM pg_index i JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+			WHERE i.indrelid = $1::regclass AND i.indisprimary`
+		args = []any{quoteChunkTable(engine, schema, table)}
+		integerTypes = map[string]bool{"smallint": true, "integer": true, "bigint": true}
+	} else {
+		schemaCondition := "k.TABLE_SCHEMA = DATABASE()"
+		if schema != "" {
+			schemaCondition = "k.TABLE_SCHEMA = ?"
+			args = append(args, schema)
+		}
+		query = fmt.Sprintf(`
+			SELECT k.COLUMN_NAME, c.DATA_TYPE
+			FROM information_schema.KEY_COLUMN_USAGE k JOIN information_schema.COLUMNS c
+				ON c.TABLE_SCHEMA = k.TABLE_SCHEMA AND c.TABLE_NAME = k.TABLE_NAME AND c.COLUMN_NAME = k.COLUMN_NAME
+			WHERE k.CONSTRAINT_NAME = 'PRIMARY' AND %s AND k.TABLE_NAME = ?`, schemaCondition)
+		args = append(args, table)
+		integerTypes = map[string]bool{"tinyint": true, "smallint": true, "mediumint": true, "int": true, "bigint": true}
+	}
+
+	rows, err := sqlDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the primary key of table %q", table)
+	}
+	defer rows.Close()
+	var columns, types []string
+	for rows.Next() {
+		var column, columnType string
+		if err := rows.Scan(&column, &columnType); err != nil {
+			return "", err
+		}
+		columns = append(columns, column)
+		types = append(types, strings.ToLower(columnType))
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if len(columns) != 1 || !integerTypes[types[0]] {
+		return "", errors.Errorf("chunked DML requires table %q to have the single-column integer primary key", table)
+	}
+	return columns[0], nil
+}
+
+func getChunkPrimaryKeyRange(ctx context.Context, engine db.Type, sqlDB *sql.DB, schema, table, primaryKey string) (sql.NullInt64, sql.NullInt64, error) {
+	column := quoteMySQLIdentifier(primaryKey)
+	if engine == db.Postgres {
+		column = quotePostgresIdentifier(primaryKey)
+	}
+	var minValue, maxValue sql.NullInt64
+	query := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", column, column, quoteChunkTable(engine, schema, table))
+	if err := sqlDB.QueryRowContext(ctx, query).Scan(&minValue, &maxValue); err != nil {
+		return minValue, maxValue, errors.Wrapf(err, "failed to get the primary key range of table %q", table)
+	}
+	return minValue, maxValue, nil
+}
+
+func quoteChunkTable(engine db.Type, schema, table string) string {
+	quote := quoteMySQLIdentifier
+	if engine == db.Postgres {
+		quote = quotePostgresIdentifier
+	}
+	if schema == "" {
+		return quote(table)
+	}
+	return fmt.Sprintf("%s.%s", quote(schema), quote(table))
+}
+
+func quoteMySQLIdentifier(name string) string {
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+}
+
+func quotePostgresIdentifier(name string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+}
+
+// GetReplicationLag returns the replication lag of the database.
+// For PostgreSQL, it's the maximum replay lag of the standbys reported by the primary.
+// For MySQL, the sqlDB should connect to the replica, and it's the seconds behind the source.
+func GetReplicationLag(ctx context.Context, engine db.Type, sqlDB *sql.DB) (time.Duration, error) {
+	if engine == db.Postgres {
+		var seconds float64
+		if err := sqlDB.QueryRowContext(ctx, "SELECT COALESCE(EXTRACT(EPOCH FROM MAX(replay_lag)), 0) FROM pg_stat_replication").Scan(&seconds); err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	// SHOW REPLICA STATUS is introduced in MySQL 8.0.22, the older versions only support SHOW SLAVE STATUS.
+	rows, err := sqlDB.QueryContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		if rows, err = sqlDB.QueryContext(ctx, "SHOW SLAVE STATUS"); err != nil {
+			return 0, err
+		}
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	var lag time.Duration
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return 0, err
+		}
+		for i, column := range columns {
+			if column != "Seconds_Behind_Source" && column != "Seconds_Behind_Master" {
+				continue
+			}
+			// The NULL means the replication is not running, which is not throttled.
+			if !values[i].Valid {
+				continue
+			}
+			var seconds int64
+			if _, err := fmt.Sscanf(values[i].String, "%d", &seconds); err != nil {
+				return 0, errors.Wrapf(err, "invalid %s %q", column, values[i].String)
+			}
+			if d := time.Duration(seconds) * time.Second; d > lag {
+				lag = d
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	return lag, nil
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
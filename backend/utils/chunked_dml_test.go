@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"testing"
+
+	// Register the parser driver for the param markers.
+	_ "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestParseChunkedDML(t *testing.T) {
+	tests := []struct {
+		engine    db.Type
+		statement string
+		want      []string
+		err       string
+	}{
+		{
+			engine: db.MySQL,
+			statement: "UPDATE orders SET status = 'archived' WHERE created_at < '2020-01-01' OR status = 'done';\n" +
+				"DELETE FROM shop.logs;",
+			want: []string{
+				"UPDATE `orders` SET `status`=_UTF8MB4'archived' WHERE (`created_at`<_UTF8MB4'2020-01-01' OR `status`=_UTF8MB4'done') AND `id`>=? AND `id`<?",
+				"DELETE FROM `shop`.`logs` WHERE `id`>=? AND `id`<?",
+			},
+		},
+		{
+			engine: db.Postgres,
+			statement: `UPDATE public.orders o SET status = 'archived' FROM users u WHERE o.user_id = u.id AND u.deleted;
+DELETE FROM logs;`,
+			want: []string{
+				`UPDATE public.orders o SET status = 'archived' FROM users u WHERE (o.user_id = u.id AND u.deleted) AND o.id >= $1 AND o.id < $2`,
+				`DELETE FROM logs WHERE logs.id >= $1 AND logs.id < $2`,
+			},
+		},
+		{
+			engine:    db.MySQL,
+			statement: "DELETE FROM logs ORDER BY id LIMIT 10;",
+			err:       `chunked DML only supports the single table DELETE without WITH, ORDER BY and LIMIT, got "DELETE FROM logs ORDER BY id LIMIT 10;"`,
+		},
+		{
+			engine:    db.Postgres,
+			statement: "INSERT INTO logs VALUES (1);",
+			err:       `chunked DML only supports UPDATE and DELETE statements, got "INSERT INTO logs VALUES (1);"`,
+		},
+		{
+			engine:    db.Oracle,
+			statement: "DELETE FROM logs;",
+			err:       `chunked DML is not supported for engine "ORACLE"`,
+		},
+	}
+
+	for _, test := range tests {
+		stmts, err := ParseChunkedDML(test.engine, test.statement)
+		if test.err != "" {
+			require.EqualError(t, err, test.err)
+			continue
+		}
+		require.NoError(t, err)
+		var queries []string
+		for _, stmt := range stmts {
+			query, err := stmt.buildChunkQuery("id")
+			require.NoError(t, err)
+			queries = append(queries, query)
+		}
+		require.Equal(t, test.want, queries)
+	}
+}
+
+func TestSplitDMLChunks(t *testing.T) {
+	tests := []struct {
+		minValue  int64
+		maxValue  int64
+		batchSize int64
+		want      [][2]int64
+	}{
+		{
+			minValue:  1,
+			maxValue:  25,
+			batchSize: 10,
+			want:      [][2]int64{{1, 11}, {11, 21}, {21, 26}},
+		},
+		{
+			minValue:  5,
+			maxValue:  5,
+			batchSize: 10,
+			want:      [][2]int64{{5, 6}},
+		},
+		{
+			minValue:  -10,
+			maxValue:  9,
+			batchSize: 10,
+			want:      [][2]int64{{-10, 0}, {0, 10}},
+		},
+	}
+
+	for _, test := range tests {
+		var ranges [][2]int64
+		for _, chunk := range splitDMLChunks("", test.minValue, test.maxValue, test.batchSize) {
+			ranges = append(ranges, [2]int64{chunk.lower, chunk.upper})
+		}
+		require.Equal(t, test.want, ranges)
+	}
+}
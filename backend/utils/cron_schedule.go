@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CronSchedule is the parsed cron expression with the 5 fields: minute, hour, day of month, month and day of week.
+type CronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	// dayOfMonthAny and dayOfWeekAny are true if the field is "*", the day matches either of the day fields unless
+	// one of them is "*", which follows the cron convention.
+	dayOfMonthAny, dayOfWeekAny bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	// Both 0 and 7 are Sunday.
+	{name: "day of week", min: 0, max: 7},
+}
+
+// cronSearchLimit is the limit of the search for the next run time, so the schedules never matching such as
+// "0 0 31 2 *" don't loop forever.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// ParseCronSchedule parses the cron expression, such as "0 9 * * 1" for 9:00 every Monday. Each field supports "*",
+// the numbers, the ranges "a-b", the steps "*/n" and "a-b/n", and the lists separated by commas.
+func ParseCronSchedule(expression string) (*CronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, errors.Errorf("cron expression %q must have %d fields", expression, len(cronFields))
+	}
+	var bits [5]uint64
+	for i, field := range fields {
+		v, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = v
+	}
+	// Sunday is either 0 or 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &CronSchedule{
+		minute:        bits[0],
+		hour:          bits[1],
+		dayOfMonth:    bits[2],
+		month:         bits[3],
+		dayOfWeek:     bits[4],
+		dayOfMonthAny: fields[2] == "*",
+		dayOfWeekAny:  fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			v, err := strconv.Atoi(part[i+1:])
+			if err != nil || v <= 0 {
+				return 0, errors.Errorf("invalid step %q in the %s field", part[i+1:], f.name)
+			}
+			rangePart, step = part[:i], v
+		}
+		start, end := f.min, f.max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			v, err := strconv.Atoi(bounds[0])
+			if err != nil {
+				return 0, errors.Errorf("invalid value %q in the %s field", bounds[0], f.name)
+			}
+			start, end = v, v
+			if len(bounds) == 2 {
+				v, err := strconv.Atoi(bounds[1])
+				if err != nil {
+					return 0, errors.Errorf("invalid value %q in the %s field", bounds[1], f.name)
+				}
+				end = v
+			} else if step > 1 {
+				// "a/n" means from a to the max.
+				end = f.max
+			}
+		}
+		if start < f.min || end > f.max || start > end {
+			return 0, errors.Errorf("%q is out of the range [%d, %d] of the %s field", part, f.min, f.max, f.name)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time matching the schedule after the time, in the location of the time. It returns the zero
+// time if no time matches in the next 5 years.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) matchDay(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.dayOfMonthAny || s.dayOfWeekAny {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// Wednesday.
+	now := time.Date(2023, 10, 11, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		expression string
+		want       time.Time
+	}{
+		{expression: "* * * * *", want: time.Date(2023, 10, 11, 10, 31, 0, 0, time.UTC)},
+		{expression: "0 9 * * 1", want: time.Date(2023, 10, 16, 9, 0, 0, 0, time.UTC)},
+		{expression: "*/15 * * * *", want: time.Date(2023, 10, 11, 10, 45, 0, 0, time.UTC)},
+		{expression: "0 8-18/2 * * 1-5", want: time.Date(2023, 10, 11, 12, 0, 0, 0, time.UTC)},
+		{expression: "30 10 1,15 * *", want: time.Date(2023, 10, 15, 10, 30, 0, 0, time.UTC)},
+		{expression: "0 0 1 1 *", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// Sunday is either 0 or 7.
+		{expression: "0 0 * * 7", want: time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC)},
+		// The day matches either of the day fields if both are restricted.
+		{expression: "0 0 13 * 5", want: time.Date(2023, 10, 13, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 12 * 1", want: time.Date(2023, 10, 12, 0, 0, 0, 0, time.UTC)},
+		// Never matches.
+		{expression: "0 0 31 2 *", want: time.Time{}},
+	}
+	for _, test := range tests {
+		schedule, err := ParseCronSchedule(test.expression)
+		require.NoError(t, err, test.expression)
+		require.Equal(t, test.want, schedule.Next(now), test.expression)
+	}
+
+	// The schedule is evaluated in the location of the time.
+	location, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	schedule, err := ParseCronSchedule("0 9 * * *")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2023, 10, 12, 1, 0, 0, 0, time.UTC), schedule.Next(now.In(location)).UTC())
+}
+
+func TestParseCronScheduleError(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		_, err := ParseCronSchedule(expression)
+		require.Error(t, err, expression)
+	}
+}
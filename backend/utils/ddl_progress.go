@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+const (
+	// The stage events are only recorded if the stage/innodb/alter% instruments and the events_stages_current consumer
+	// are enabled in the performance_schema.
+	mysqlDDLProgressQuery = `
+		SELECT stage.EVENT_NAME, IFNULL(stage.WORK_COMPLETED, 0), IFNULL(stage.WORK_ESTIMATED, 0)
+		FROM performance_schema.events_stages_current stage
+		JOIN performance_schema.threads thread ON stage.THREAD_ID = thread.THREAD_ID
+		WHERE thread.PROCESSLIST_ID = ?`
+	// The progress views are available since PostgreSQL 12, the CLUSTER view also reports VACUUM FULL.
+	pgDDLProgressQuery = `
+		SELECT 'CREATE INDEX', phase,
+			CASE WHEN blocks_total > 0 THEN blocks_done ELSE tuples_done END,
+			CASE WHEN blocks_total > 0 THEN blocks_total ELSE tuples_total END
+		FROM pg_catalog.pg_stat_progress_create_index
+		WHERE datname = $1 AND pid <> pg_backend_pid()
+		UNION ALL
+		SELECT command, phase, heap_blks_scanned, heap_blks_total
+		FROM pg_catalog.pg_stat_progress_cluster
+		WHERE datname = $1 AND pid <> pg_backend_pid()
+		LIMIT 1`
+)
+
+// DDLProgress is the progress of the in-flight DDL statement.
+type DDLProgress struct {
+	// Phase is the current phase of the statement, e.g. "alter table (read PK and internal sort)".
+	Phase     string
+	Completed int64
+	Total     int64
+}
+
+// GetDDLProgress returns the progress of the in-flight ALTER TABLE or CREATE INDEX statement, or nil if there is none.
+// For MySQL, the statement is the one running in the connection connID. For PostgreSQL, it's any statement running in
+// the database, since the migration is not pinned to a connection.
+func GetDDLProgress(ctx context.Context, engine db.Type, conn *sql.DB, databaseName, connID string) (*DDLProgress, error) {
+	var query string
+	var args []any
+	switch engine {
+	case db.MySQL:
+		query, args = mysqlDDLProgressQuery, []any{connID}
+	case db.Postgres:
+		query, args = pgDDLProgressQuery, []any{databaseName}
+	default:
+		return nil, errors.Errorf("DDL progress is not supported for engine %q", engine)
+	}
+
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var progress *DDLProgress
+	for rows.Next() {
+		if engine == db.MySQL {
+			var eventName string
+			p := &DDLProgress{}
+			if err := rows.Scan(&eventName, &p.Completed, &p.Total); err != nil {
+				return nil, err
+			}
+			p.Phase = mysqlStagePhase(eventName)
+			progress = p
+			continue
+		}
+		var command, phase string
+		p := &DDLProgress{}
+		if err := rows.Scan(&command, &phase, &p.Completed, &p.Total); err != nil {
+			return nil, err
+		}
+		p.Phase = fmt.Sprintf("%s: %s", command, phase)
+		progress = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// mysqlStagePhase returns the stage of the stage event name, e.g. "alter table (merge sort)" for
+// "stage/innodb/alter table (merge sort)".
+func mysqlStagePhase(eventName string) string {
+	for _, prefix := range []string{"stage/innodb/", "stage/sql/"} {
+		if strings.HasPrefix(eventName, prefix) {
+			return strings.TrimPrefix(eventName, prefix)
+		}
+	}
+	return eventName
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMySQLStagePhase(t *testing.T) {
+	tests := []struct {
+		eventName string
+		want      string
+	}{
+		{
+			eventName: "stage/innodb/alter table (read PK and internal sort)",
+			want:      "alter table (read PK and internal sort)",
+		},
+		{
+			eventName: "stage/sql/copy to tmp table",
+			want:      "copy to tmp table",
+		},
+		{
+			eventName: "stage/other/unknown",
+			want:      "stage/other/unknown",
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, mysqlStagePhase(test.eventName))
+	}
+}
@@ -0,0 +1,392 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/ast"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// SchemaObjectType is the type of the schema object in the dependency analysis.
+type SchemaObjectType string
+
+const (
+	// SchemaObjectTable is the table.
+	SchemaObjectTable SchemaObjectType = "table"
+	// SchemaObjectView is the view.
+	SchemaObjectView SchemaObjectType = "view"
+	// SchemaObjectColumn is the column of a table.
+	SchemaObjectColumn SchemaObjectType = "column"
+	// SchemaObjectFunction is the function, including the trigger functions.
+	SchemaObjectFunction SchemaObjectType = "function"
+	// SchemaObjectForeignKey is the foreign key of a table.
+	SchemaObjectForeignKey SchemaObjectType = "foreign key"
+)
+
+// SchemaChangeAction is the action of the DDL statement to the changed object.
+type SchemaChangeAction string
+
+const (
+	// SchemaChangeDrop drops the object.
+	SchemaChangeDrop SchemaChangeAction = "drop"
+	// SchemaChangeRename renames the object.
+	SchemaChangeRename SchemaChangeAction = "rename"
+	// SchemaChangeAlterType changes the type of the column.
+	SchemaChangeAlterType SchemaChangeAction = "alter type"
+)
+
+// ChangedObject is the object changed by a DDL statement.
+type ChangedObject struct {
+	Action SchemaChangeAction
+	Type   SchemaObjectType
+	Schema string
+	// Table is the table or view. It's also the table of the column and foreign key.
+	Table string
+	// Name is the name of the column, foreign key or function.
+	Name string
+}
+
+func (o *ChangedObject) String() string {
+	switch o.Type {
+	case SchemaObjectColumn:
+		return fmt.Sprintf("column %s", qualifiedObjectName(o.Schema, o.Table, o.Name))
+	case SchemaObjectForeignKey:
+		return fmt.Sprintf("foreign key %s on %s", o.Name, qualifiedObjectName(o.Schema, o.Table))
+	case SchemaObjectFunction:
+		return fmt.Sprintf("function %s", qualifiedObjectName(o.Schema, o.Name))
+	default:
+		return fmt.Sprintf("%s %s", o.Type, qualifiedObjectName(o.Schema, o.Table))
+	}
+}
+
+// DependentObject is the object referencing a changed object.
+// Triggers are not synced in the database metadata, so they are covered by their trigger functions.
+type DependentObject struct {
+	// Type is one of the view, function and foreign key.
+	Type   SchemaObjectType
+	Schema string
+	// Table is the table of the foreign key.
+	Table string
+	Name  string
+	// Changed is the referenced object.
+	Changed *ChangedObject
+	// Breaking is true if the statement would fail, or the dependent object would fail or be dropped after the change.
+	Breaking bool
+}
+
+func (o *DependentObject) String() string {
+	if o.Type == SchemaObjectForeignKey {
+		return fmt.Sprintf("foreign key %s on %s", o.Name, qualifiedObjectName(o.Schema, o.Table))
+	}
+	return fmt.Sprintf("%s %s", o.Type, qualifiedObjectName(o.Schema, o.Name))
+}
+
+func qualifiedObjectName(names ...string) string {
+	var parts []string
+	for _, name := range names {
+		if name != "" {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// ExtractChangedObjects extracts the tables, views, columns, functions and foreign keys dropped, renamed or
+// altered by the DDL statement.
+func ExtractChangedObjects(engine db.Type, statement string) ([]*ChangedObject, error) {
+	switch engine {
+	case db.Postgres:
+		return extractPostgresChangedObjects(statement)
+	case db.MySQL, db.TiDB, db.MariaDB:
+		return extractMySQLChangedObjects(statement)
+	default:
+		return nil, errors.Errorf("dependent object analysis is not supported for engine %q", engine)
+	}
+}
+
+func extractPostgresChangedObjects(statement string) ([]*ChangedObject, error) {
+	nodes, err := parser.Parse(parser.Postgres, parser.ParseContext{}, statement)
+	if err != nil {
+		return nil, err
+	}
+	tableType := func(table *ast.TableDef) SchemaObjectType {
+		if table.Type == ast.TableTypeView || table.Type == ast.TableTypeMaterializedView {
+			return SchemaObjectView
+		}
+		return SchemaObjectTable
+	}
+	schemaName := func(schema string) string {
+		if schema == "" {
+			return "public"
+		}
+		return schema
+	}
+
+	var changedList []*ChangedObject
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *ast.DropTableStmt:
+			for _, table := range node.TableList {
+				changedList = append(changedList, &ChangedObject{Action: SchemaChangeDrop, Type: tableType(table), Schema: schemaName(table.Schema), Table: table.Name})
+			}
+		case *ast.DropFunctionStmt:
+			for _, function := range node.FunctionList {
+				changedList = append(changedList, &ChangedObject{Action: SchemaChangeDrop, Type: SchemaObjectFunction, Schema: schemaName(function.Schema), Name: function.Name})
+			}
+		case *ast.AlterTableStmt:
+			schema, table := schemaName(node.Table.Schema), node.Table.Name
+			for _, item := range node.AlterItemList {
+				switch item := item.(type) {
+				case *ast.DropColumnStmt:
+					changedList = append(changedList, &ChangedObject{Action: SchemaChangeDrop, Type: SchemaObjectColumn, Schema: schema, Table: table, Name: item.ColumnName})
+				case *ast.RenameColumnStmt:
+					changedList = append(changedList, &ChangedObject{Action: SchemaChangeRename, Type: SchemaObjectColumn, Schema: schema, Table: table, Name: item.ColumnName})
+				case *ast.AlterColumnTypeStmt:
+					changedList = append(changedList, &ChangedObject{Action: SchemaChangeAlterType, Type: SchemaObjectColumn, Schema: schema, Table: table, Name: item.ColumnName})
+				case *ast.RenameTableStmt:
+					changedList = append(changedList, &ChangedObject{Action: SchemaChangeRename, Type: tableType(node.Table), Schema: schema, Table: table})
+				case *ast.DropConstraintStmt:
+					// The constraint may not be a foreign key, which never matches the foreign keys in the metadata.
+					changedList = append(changedList, &ChangedObject{Action: SchemaChangeDrop, Type: SchemaObjectForeignKey, Schema: schema, Table: table, Name: item.ConstraintName})
+				}
+			}
+		}
+	}
+	return changedList, nil
+}
+
+func extractMySQLChangedObjects(statement string) ([]*ChangedObject, error) {
+	// The TiDB parser cannot parse some MySQL statements, e.g. CREATE PROCEDURE, which change no tables.
+	_, supportStmt, err := parser.ExtractTiDBUnsupportStmts(statement)
+	if err != nil {
+		return nil, err
+	}
+	p := tidbparser.New()
+	p.EnableWindowFunc(true)
+	nodes, _, err := p.Parse(supportStmt, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var changedList []*ChangedObject
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *tidbast.DropTableStmt:
+			objectType := SchemaObjectTable
+			if node.IsView {
+				objectType = SchemaObjectView
+			}
+			for _, table := range node.Tables {
+				changedList = append(changedList, &ChangedObject{Action: SchemaChangeDrop, Type: objectType, Table: table.Name.O})
+			}
+		case *tidbast.RenameTableStmt:
+			for _, tableToTable := range node.TableToTables {
+				changedList = append(changedList, &ChangedObject{Action: SchemaChangeRename, Type: SchemaObjectTable, Table: tableToTable.OldTable.Name.O})
+			}
+		case *tidbast.AlterTableStmt:
+			table := node.Table.Name.O
+			for _, spec := range node.Specs {
+				switch spec.Tp {
+				case tidbast.AlterTableDropColumn:
+					changedList = append(changedList, &ChangedObject{Action: SchemaChangeDrop, Type: SchemaObjectColumn, Table: table, Name: spec.OldColumnName.Name.O})
+				case tidbast.AlterTableRenameColumn:
+					changedList = append(changedList, &ChangedObject{Action: SchemaChangeRename, Type: SchemaObjectColumn, Table: table, Name: spec.OldColumnName.Name.O})
+				case tidbast.AlterTableModifyColumn:
+					changedList = append(changedList, &ChangedObject{Action: SchemaChangeAlterType, Type: SchemaObjectColumn, Table: table, Name: spec.NewColumns[0].Name.Name.O})
+				case tidbast.AlterTableChangeColumn:
+					oldName, newName := spec.OldColumnName.Name.O, spec.NewColumns[0].Name.Name.O
+					if !strings.EqualFold(oldName, newName) {
+						changedList = append(changedList, &ChangedObject{Action: SchemaChangeRename, Type: SchemaObjectColumn, Table: table, Name: oldName})
+					}
+					changedList = append(changedList, &ChangedObject{Action: SchemaChangeAlterType, Type: SchemaObjectColumn, Table: table, Name: oldName})
+				case tidbast.AlterTableRenameTable:
+					changedList = append(changedList, &ChangedObject{Action: SchemaChangeRename, Type: SchemaObjectTable, Table: table})
+				case tidbast.AlterTableDropForeignKey:
+					changedList = append(changedList, &ChangedObject{Action: SchemaChangeDrop, Type: SchemaObjectForeignKey, Table: table, Name: spec.Name})
+				}
+			}
+		}
+	}
+	return changedList, nil
+}
+
+// FindDependentObjects finds the views, functions and foreign keys in the database metadata referencing the changed
+// objects. The dependent objects dropped by the same statement are skipped.
+// The views are matched by the dependent columns if synced, otherwise the views and the functions are matched by the
+// identifiers in the definitions, which may report the false positives.
+func FindDependentObjects(engine db.Type, metadata *storepb.DatabaseMetadata, changedList []*ChangedObject) []*DependentObject {
+	sameName := func(a, b string) bool {
+		if engine == db.Postgres {
+			return a == b
+		}
+		// The MySQL metadata has only one schema, and the identifiers are case-insensitive on most platforms.
+		return strings.EqualFold(a, b)
+	}
+	sameSchema := func(a, b string) bool {
+		return engine != db.Postgres || a == b
+	}
+	isDropped := func(objectType SchemaObjectType, schema, table, name string) bool {
+		for _, changed := range changedList {
+			if changed.Action != SchemaChangeDrop || !sameSchema(changed.Schema, schema) {
+				continue
+			}
+			switch {
+			case changed.Type == objectType && objectType == SchemaObjectForeignKey:
+				if sameName(changed.Table, table) && sameName(changed.Name, name) {
+					return true
+				}
+			case changed.Type == objectType && objectType == SchemaObjectFunction:
+				if sameName(changed.Name, name) {
+					return true
+				}
+			case changed.Type == SchemaObjectTable || changed.Type == SchemaObjectView:
+				// The foreign keys are dropped with the table.
+				if objectType == SchemaObjectForeignKey && sameName(changed.Table, table) {
+					return true
+				}
+				if objectType == SchemaObjectView && changed.Type == SchemaObjectView && sameName(changed.Table, name) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	var dependentList []*DependentObject
+	for _, changed := range changedList {
+		if changed.Type == SchemaObjectFunction || changed.Type == SchemaObjectForeignKey {
+			continue
+		}
+		for _, schema := range metadata.GetSchemas() {
+			for _, view := range schema.Views {
+				if changed.Type == SchemaObjectView && sameSchema(changed.Schema, schema.Name) && sameName(changed.Table, view.Name) {
+					continue
+				}
+				if isDropped(SchemaObjectView, schema.Name, "", view.Name) || !viewReferences(engine, view, changed, sameName, sameSchema) {
+					continue
+				}
+				dependentList = append(dependentList, &DependentObject{
+					Type:     SchemaObjectView,
+					Schema:   schema.Name,
+					Name:     view.Name,
+					Changed:  changed,
+					Breaking: isBreakingForDependent(engine, SchemaObjectView, changed.Action),
+				})
+			}
+			for _, function := range schema.Functions {
+				if isDropped(SchemaObjectFunction, schema.Name, "", function.Name) || !definitionReferences(function.Definition, changed) {
+					continue
+				}
+				dependentList = append(dependentList, &DependentObject{
+					Type:     SchemaObjectFunction,
+					Schema:   schema.Name,
+					Name:     function.Name,
+					Changed:  changed,
+					Breaking: isBreakingForDependent(engine, SchemaObjectFunction, changed.Action),
+				})
+			}
+			if changed.Type == SchemaObjectView {
+				continue
+			}
+			for _, table := range schema.Tables {
+				for _, foreignKey := range table.ForeignKeys {
+					if isDropped(SchemaObjectForeignKey, schema.Name, table.Name, foreignKey.Name) || !foreignKeyReferences(schema.Name, table.Name, foreignKey, changed, sameName, sameSchema) {
+						continue
+					}
+					dependentList = append(dependentList, &DependentObject{
+						Type:     SchemaObjectForeignKey,
+						Schema:   schema.Name,
+						Table:    table.Name,
+						Name:     foreignKey.Name,
+						Changed:  changed,
+						Breaking: isBreakingForDependent(engine, SchemaObjectForeignKey, changed.Action),
+					})
+				}
+			}
+		}
+	}
+	return dependentList
+}
+
+func viewReferences(engine db.Type, view *storepb.ViewMetadata, changed *ChangedObject, sameName func(a, b string) bool, sameSchema func(a, b string) bool) bool {
+	if engine == db.Postgres && len(view.DependentColumns) > 0 {
+		for _, column := range view.DependentColumns {
+			if !sameSchema(column.Schema, changed.Schema) || !sameName(column.Table, changed.Table) {
+				continue
+			}
+			if changed.Type != SchemaObjectColumn || sameName(column.Column, changed.Name) {
+				return true
+			}
+		}
+		return false
+	}
+	return definitionReferences(view.Definition, changed)
+}
+
+func definitionReferences(definition string, changed *ChangedObject) bool {
+	if !containsIdentifier(definition, changed.Table) {
+		return false
+	}
+	return changed.Type != SchemaObjectColumn || containsIdentifier(definition, changed.Name)
+}
+
+func foreignKeyReferences(schema, table string, foreignKey *storepb.ForeignKeyMetadata, changed *ChangedObject, sameName func(a, b string) bool, sameSchema func(a, b string) bool) bool {
+	containsColumn := func(columns []string) bool {
+		for _, column := range columns {
+			if sameName(column, changed.Name) {
+				return true
+			}
+		}
+		return false
+	}
+	referenced := sameSchema(foreignKey.ReferencedSchema, changed.Schema) && sameName(foreignKey.ReferencedTable, changed.Table)
+	if changed.Type != SchemaObjectColumn {
+		// The self-referencing foreign keys are dropped or renamed with the table.
+		return referenced && !(sameSchema(schema, changed.Schema) && sameName(table, changed.Table))
+	}
+	if referenced && containsColumn(foreignKey.ReferencedColumns) {
+		return true
+	}
+	return sameSchema(schema, changed.Schema) && sameName(table, changed.Table) && containsColumn(foreignKey.Columns)
+}
+
+// isBreakingForDependent returns true if the change action breaks the dependent object.
+// PostgreSQL views and all foreign keys refer to the objects by the identities, which survive the renames. MySQL
+// views store the names in the definitions instead. PostgreSQL rejects to alter the type of the columns used by the
+// views, and the type changes of the columns referenced by the functions and the foreign keys are usually compatible.
+func isBreakingForDependent(engine db.Type, dependentType SchemaObjectType, action SchemaChangeAction) bool {
+	switch action {
+	case SchemaChangeDrop:
+		return true
+	case SchemaChangeRename:
+		switch dependentType {
+		case SchemaObjectView:
+			return engine != db.Postgres
+		case SchemaObjectFunction:
+			return true
+		default:
+			return false
+		}
+	case SchemaChangeAlterType:
+		return dependentType == SchemaObjectView && engine == db.Postgres
+	default:
+		return false
+	}
+}
+
+// containsIdentifier returns true if the identifier appears in the text as a whole word, quoted or not.
+func containsIdentifier(text, identifier string) bool {
+	if identifier == "" {
+		return false
+	}
+	re := regexp.MustCompile(`(?i)(^|[^\w$])` + regexp.QuoteMeta(identifier) + `([^\w$]|$)`)
+	return re.MatchString(text)
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func TestExtractChangedObjects(t *testing.T) {
+	tests := []struct {
+		engine    db.Type
+		statement string
+		want      []*ChangedObject
+	}{
+		{
+			engine:    db.Postgres,
+			statement: "DROP TABLE t1; ALTER TABLE s.t2 DROP COLUMN c1; ALTER TABLE t3 RENAME COLUMN c2 TO c3; DROP VIEW v1;",
+			want: []*ChangedObject{
+				{Action: SchemaChangeDrop, Type: SchemaObjectTable, Schema: "public", Table: "t1"},
+				{Action: SchemaChangeDrop, Type: SchemaObjectColumn, Schema: "s", Table: "t2", Name: "c1"},
+				{Action: SchemaChangeRename, Type: SchemaObjectColumn, Schema: "public", Table: "t3", Name: "c2"},
+				{Action: SchemaChangeDrop, Type: SchemaObjectView, Schema: "public", Table: "v1"},
+			},
+		},
+		{
+			engine:    db.Postgres,
+			statement: "CREATE TABLE t1 (id INT); ALTER TABLE t1 ADD COLUMN c1 INT;",
+		},
+		{
+			engine:    db.MySQL,
+			statement: "ALTER TABLE t1 CHANGE COLUMN c1 c2 BIGINT, DROP FOREIGN KEY fk1; RENAME TABLE t2 TO t3;",
+			want: []*ChangedObject{
+				{Action: SchemaChangeRename, Type: SchemaObjectColumn, Table: "t1", Name: "c1"},
+				{Action: SchemaChangeAlterType, Type: SchemaObjectColumn, Table: "t1", Name: "c1"},
+				{Action: SchemaChangeDrop, Type: SchemaObjectForeignKey, Table: "t1", Name: "fk1"},
+				{Action: SchemaChangeRename, Type: SchemaObjectTable, Table: "t2"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		changedList, err := ExtractChangedObjects(test.engine, test.statement)
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.want, changedList, test.statement)
+	}
+}
+
+func TestFindDependentObjects(t *testing.T) {
+	metadata := &storepb.DatabaseMetadata{
+		Schemas: []*storepb.SchemaMetadata{
+			{
+				Name: "public",
+				Tables: []*storepb.TableMetadata{
+					{Name: "users"},
+					{
+						Name: "orders",
+						ForeignKeys: []*storepb.ForeignKeyMetadata{
+							{Name: "fk_orders_user", Columns: []string{"user_id"}, ReferencedSchema: "public", ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+						},
+					},
+				},
+				Views: []*storepb.ViewMetadata{
+					{
+						Name:             "active_users",
+						DependentColumns: []*storepb.DependentColumn{{Schema: "public", Table: "users", Column: "email"}},
+					},
+				},
+				Functions: []*storepb.FunctionMetadata{
+					{Name: "count_users", Definition: "BEGIN RETURN (SELECT count(*) FROM users); END"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		statement string
+		want      []string
+		breaking  []bool
+	}{
+		{
+			statement: "DROP TABLE users;",
+			want:      []string{"view public.active_users", "function public.count_users", "foreign key fk_orders_user on public.orders"},
+			breaking:  []bool{true, true, true},
+		},
+		{
+			// The view follows the renamed table in PostgreSQL.
+			statement: "ALTER TABLE users RENAME TO members;",
+			want:      []string{"view public.active_users", "function public.count_users", "foreign key fk_orders_user on public.orders"},
+			breaking:  []bool{false, true, false},
+		},
+		{
+			statement: "ALTER TABLE users ALTER COLUMN email TYPE TEXT;",
+			want:      []string{"view public.active_users"},
+			breaking:  []bool{true},
+		},
+		{
+			statement: "DROP VIEW active_users; ALTER TABLE users DROP COLUMN email;",
+		},
+		{
+			statement: "ALTER TABLE orders DROP CONSTRAINT fk_orders_user; DROP FUNCTION count_users; ALTER TABLE users DROP COLUMN id;",
+		},
+	}
+
+	for _, test := range tests {
+		changedList, err := ExtractChangedObjects(db.Postgres, test.statement)
+		require.NoError(t, err, test.statement)
+		var got []string
+		var breaking []bool
+		for _, dependent := range FindDependentObjects(db.Postgres, metadata, changedList) {
+			got = append(got, dependent.String())
+			breaking = append(breaking, dependent.Breaking)
+		}
+		require.Equal(t, test.want, got, test.statement)
+		require.Equal(t, test.breaking, breaking, test.statement)
+	}
+}
+
+func TestContainsIdentifier(t *testing.T) {
+	require.True(t, containsIdentifier("SELECT * FROM `users`", "users"))
+	require.True(t, containsIdentifier(`SELECT "Users".id FROM "Users"`, "users"))
+	require.False(t, containsIdentifier("SELECT * FROM users_archive", "users"))
+	require.False(t, containsIdentifier("SELECT 1", ""))
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// MaskingBundleFormat is the format of the exported masking bundle.
+type MaskingBundleFormat string
+
+const (
+	// MaskingBundleFormatYAML is the YAML format.
+	MaskingBundleFormatYAML MaskingBundleFormat = "yaml"
+	// MaskingBundleFormatJSON is the JSON format.
+	MaskingBundleFormatJSON MaskingBundleFormat = "json"
+)
+
+// MarshalMaskingBundle marshals the masking bundle in the format. The YAML has the same field names as the JSON.
+func MarshalMaskingBundle(bundle *api.MaskingBundle, format MaskingBundleFormat) ([]byte, error) {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal masking bundle")
+	}
+	switch format {
+	case MaskingBundleFormatJSON:
+		return data, nil
+	case MaskingBundleFormatYAML:
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal masking bundle")
+		}
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(value); err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal masking bundle as YAML")
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal masking bundle as YAML")
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errors.Errorf("unsupported masking bundle format %q", format)
+	}
+}
+
+// UnmarshalMaskingBundle unmarshals and validates the masking bundle in YAML or JSON, as JSON is a subset of YAML.
+func UnmarshalMaskingBundle(data []byte) (*api.MaskingBundle, error) {
+	var value any
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal masking bundle")
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal masking bundle")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	bundle := &api.MaskingBundle{}
+	if err := decoder.Decode(bundle); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal masking bundle")
+	}
+
+	if bundle.Version != api.MaskingBundleVersion {
+		return nil, errors.Errorf("unsupported masking bundle version %d, expect %d", bundle.Version, api.MaskingBundleVersion)
+	}
+	databases := make(map[string]bool)
+	for _, policy := range bundle.SensitiveDataPolicies {
+		if policy.Instance == "" || policy.Database == "" {
+			return nil, errors.Errorf("sensitive data policy must have the instance and the database")
+		}
+		key := fmt.Sprintf("%s/%s", policy.Instance, policy.Database)
+		if databases[key] {
+			return nil, errors.Errorf("duplicate sensitive data policy for database %q", key)
+		}
+		databases[key] = true
+		payload, err := (&api.SensitiveDataPolicy{SensitiveDataList: policy.SensitiveDataList}).String()
+		if err != nil {
+			return nil, err
+		}
+		if err := api.ValidatePolicy(api.PolicyResourceTypeDatabase, api.PolicyTypeSensitiveData, &payload); err != nil {
+			return nil, errors.Wrapf(err, "invalid sensitive data policy for database %q", key)
+		}
+	}
+	for _, finding := range bundle.DismissedPIIFindings {
+		if finding.Instance == "" || finding.Database == "" || finding.Table == "" || finding.Column == "" {
+			return nil, errors.Errorf("dismissed PII finding must have the instance, the database, the table and the column")
+		}
+		switch finding.Classification {
+		case api.PIIClassificationEmail, api.PIIClassificationPhone, api.PIIClassificationSSN, api.PIIClassificationCreditCard:
+		default:
+			return nil, errors.Errorf("invalid PII classification %q", finding.Classification)
+		}
+	}
+	return bundle, nil
+}
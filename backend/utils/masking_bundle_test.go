@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestMaskingBundle(t *testing.T) {
+	bundle := &api.MaskingBundle{
+		Version: api.MaskingBundleVersion,
+		SensitiveDataPolicies: []*api.MaskingBundleSensitiveDataPolicy{
+			{
+				Instance: "prod-mysql",
+				Database: "hr",
+				SensitiveDataList: []api.SensitiveData{
+					{Table: "employee", Column: "salary", Type: api.SensitiveDataMaskTypeDefault, Condition: `row.country == "DE"`},
+					{Table: "employee", Column: "email", Type: api.SensitiveDataMaskTypeHMAC},
+				},
+			},
+		},
+		DismissedPIIFindings: []*api.MaskingBundlePIIFinding{
+			{Instance: "prod-mysql", Database: "hr", Table: "employee", Column: "phone_extension", Classification: api.PIIClassificationPhone},
+		},
+	}
+	for _, format := range []MaskingBundleFormat{MaskingBundleFormatYAML, MaskingBundleFormatJSON} {
+		data, err := MarshalMaskingBundle(bundle, format)
+		require.NoError(t, err)
+		got, err := UnmarshalMaskingBundle(data)
+		require.NoError(t, err)
+		require.Equal(t, bundle, got, format)
+	}
+	data, err := MarshalMaskingBundle(bundle, MaskingBundleFormatYAML)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "maskType: HMAC")
+
+	for _, data := range []string{
+		`version: 2`,
+		`{"version": 1, "unknown": true}`,
+		`{"version": 1, "sensitiveDataPolicies": [{"instance": "i", "sensitiveDataList": []}]}`,
+		`{"version": 1, "sensitiveDataPolicies": [{"instance": "i", "database": "d", "sensitiveDataList": []}, {"instance": "i", "database": "d", "sensitiveDataList": []}]}`,
+		`{"version": 1, "sensitiveDataPolicies": [{"instance": "i", "database": "d", "sensitiveDataList": [{"table": "t", "column": "c", "maskType": "ROT13"}]}]}`,
+		`{"version": 1, "sensitiveDataPolicies": [{"instance": "i", "database": "d", "sensitiveDataList": [{"table": "t", "column": "c", "maskType": "DEFAULT", "condition": "row.a =="}]}]}`,
+		`{"version": 1, "dismissedPiiFindings": [{"instance": "i", "database": "d", "table": "t", "column": "c", "classification": "NAME"}]}`,
+	} {
+		_, err := UnmarshalMaskingBundle([]byte(data))
+		require.Error(t, err, data)
+	}
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// migrationAssertionMaxRows is the maximum number of the rows read for an assertion.
+const migrationAssertionMaxRows = 100
+
+// ValidateMigrationAssertions validates the assertions declared in the issue.
+func ValidateMigrationAssertions(assertions []*api.MigrationAssertion) error {
+	if len(assertions) > api.MaxMigrationAssertionCount {
+		return errors.Errorf("a task can have at most %d assertions", api.MaxMigrationAssertionCount)
+	}
+	names := make(map[string]bool)
+	for i, assertion := range assertions {
+		if assertion.Name == "" {
+			return errors.Errorf("assertion %d has empty name", i+1)
+		}
+		if names[assertion.Name] {
+			return errors.Errorf("duplicate assertion name %q", assertion.Name)
+		}
+		names[assertion.Name] = true
+		if strings.TrimSpace(assertion.Statement) == "" {
+			return errors.Errorf("assertion %q has empty statement", assertion.Name)
+		}
+		switch assertion.Expect {
+		case api.MigrationAssertionExpectEmpty, api.MigrationAssertionExpectNotEmpty, api.MigrationAssertionExpectValue:
+		default:
+			return errors.Errorf("assertion %q has invalid expect %q", assertion.Name, assertion.Expect)
+		}
+	}
+	return nil
+}
+
+// RunMigrationAssertions runs all the assertions, so that the results show every failed assertion.
+// The query function returns the rows of the assertion statement, and it returns an error if any assertion fails.
+func RunMigrationAssertions(ctx context.Context, assertions []*api.MigrationAssertion, query func(ctx context.Context, statement string) ([][]string, error)) ([]*api.MigrationAssertionResult, error) {
+	var results []*api.MigrationAssertionResult
+	var failedList []string
+	for _, assertion := range assertions {
+		result := &api.MigrationAssertionResult{Name: assertion.Name}
+		rows, err := query(ctx, assertion.Statement)
+		if err == nil {
+			err = checkMigrationAssertion(assertion, rows)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			failedList = append(failedList, fmt.Sprintf("%q %s", assertion.Name, result.Error))
+		}
+		results = append(results, result)
+	}
+	if len(failedList) > 0 {
+		return results, errors.Errorf("%d of %d assertions failed: %s", len(failedList), len(assertions), strings.Join(failedList, "; "))
+	}
+	return results, nil
+}
+
+func checkMigrationAssertion(assertion *api.MigrationAssertion, rows [][]string) error {
+	switch assertion.Expect {
+	case api.MigrationAssertionExpectEmpty:
+		if len(rows) > 0 {
+			return errors.Errorf("expected no rows, got %s", formatMigrationAssertionRowCount(len(rows)))
+		}
+	case api.MigrationAssertionExpectNotEmpty:
+		if len(rows) == 0 {
+			return errors.New("expected at least one row, got no rows")
+		}
+	case api.MigrationAssertionExpectValue:
+		if len(rows) != 1 || len(rows[0]) != 1 {
+			return errors.Errorf("expected a single value, got %s", formatMigrationAssertionRowCount(len(rows)))
+		}
+		if rows[0][0] != assertion.Value {
+			return errors.Errorf("expected %q, got %q", assertion.Value, rows[0][0])
+		}
+	default:
+		return errors.Errorf("unsupported expect %q", assertion.Expect)
+	}
+	return nil
+}
+
+func formatMigrationAssertionRowCount(count int) string {
+	if count >= migrationAssertionMaxRows {
+		return fmt.Sprintf("at least %d rows", migrationAssertionMaxRows)
+	}
+	if count == 1 {
+		return "1 row"
+	}
+	return fmt.Sprintf("%d rows", count)
+}
+
+// QueryMigrationAssertionRows runs the assertion statement in a read-only transaction, and returns at most
+// migrationAssertionMaxRows rows. NULL is returned as "NULL".
+func QueryMigrationAssertionRows(ctx context.Context, conn *sql.Conn, statement string) ([][]string, error) {
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var result [][]string
+	for rows.Next() && len(result) < migrationAssertionMaxRows {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		var row []string
+		for _, value := range values {
+			if value.Valid {
+				row = append(row, value.String)
+			} else {
+				row = append(row, "NULL")
+			}
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestRunMigrationAssertions(t *testing.T) {
+	ctx := context.Background()
+	assertions := []*api.MigrationAssertion{
+		{Name: "no null email", Statement: "SELECT id FROM users WHERE email IS NULL", Expect: api.MigrationAssertionExpectEmpty},
+		{Name: "row count", Statement: "SELECT (SELECT COUNT(*) FROM users) - (SELECT COUNT(*) FROM users_old)", Expect: api.MigrationAssertionExpectValue, Value: "0"},
+		{Name: "admin exists", Statement: "SELECT id FROM users WHERE role = 'admin'", Expect: api.MigrationAssertionExpectNotEmpty},
+	}
+	require.NoError(t, ValidateMigrationAssertions(assertions))
+
+	rowsMap := map[string][][]string{
+		assertions[0].Statement: nil,
+		assertions[1].Statement: {{"0"}},
+		assertions[2].Statement: {{"1"}},
+	}
+	query := func(_ context.Context, statement string) ([][]string, error) {
+		rows, ok := rowsMap[statement]
+		if !ok {
+			return nil, errors.New("unknown statement")
+		}
+		return rows, nil
+	}
+	results, err := RunMigrationAssertions(ctx, assertions, query)
+	require.NoError(t, err)
+	require.Equal(t, []*api.MigrationAssertionResult{{Name: "no null email"}, {Name: "row count"}, {Name: "admin exists"}}, results)
+
+	// All the assertions run even if some of them fail.
+	rowsMap[assertions[0].Statement] = [][]string{{"3"}, {"7"}}
+	rowsMap[assertions[1].Statement] = [][]string{{"NULL"}}
+	results, err = RunMigrationAssertions(ctx, assertions, query)
+	require.EqualError(t, err, `2 of 3 assertions failed: "no null email" expected no rows, got 2 rows; "row count" expected "0", got "NULL"`)
+	require.Equal(t, []*api.MigrationAssertionResult{
+		{Name: "no null email", Error: "expected no rows, got 2 rows"},
+		{Name: "row count", Error: `expected "0", got "NULL"`},
+		{Name: "admin exists"},
+	}, results)
+
+	require.Error(t, ValidateMigrationAssertions([]*api.MigrationAssertion{{Name: "a", Statement: "SELECT 1", Expect: "MATCH"}}))
+	require.Error(t, ValidateMigrationAssertions([]*api.MigrationAssertion{
+		{Name: "a", Statement: "SELECT 1", Expect: api.MigrationAssertionExpectNotEmpty},
+		{Name: "a", Statement: "SELECT 2", Expect: api.MigrationAssertionExpectNotEmpty},
+	}))
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+const migrationHookWebhookTimeout = 10 * time.Second
+
+// MigrationHookWebhookRequest is the request POSTed to the webhook hooks.
+type MigrationHookWebhookRequest struct {
+	Stage       api.MigrationHookStage `json:"stage"`
+	IssueID     string                 `json:"issueId"`
+	TaskID      int                    `json:"taskId"`
+	TaskName    string                 `json:"taskName"`
+	Environment string                 `json:"environment"`
+	Database    string                 `json:"database"`
+	Version     string                 `json:"version"`
+}
+
+// ValidateMigrationHooks validates the hooks declared in the issue.
+func ValidateMigrationHooks(hooks []*api.MigrationHook) error {
+	if len(hooks) > api.MaxMigrationHookCount {
+		return errors.Errorf("a task can have at most %d hooks", api.MaxMigrationHookCount)
+	}
+	for i, hook := range hooks {
+		if hook.Stage != api.MigrationHookPre && hook.Stage != api.MigrationHookPost {
+			return errors.Errorf("hook %d has invalid stage %q", i+1, hook.Stage)
+		}
+		switch hook.Type {
+		case api.MigrationHookSQL:
+			if strings.TrimSpace(hook.Statement) == "" {
+				return errors.Errorf("hook %d has empty statement", i+1)
+			}
+		case api.MigrationHookWebhook:
+			u, err := url.Parse(hook.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return errors.Errorf("hook %d has invalid URL %q", i+1, hook.URL)
+			}
+		default:
+			return errors.Errorf("hook %d has invalid type %q", i+1, hook.Type)
+		}
+	}
+	return nil
+}
+
+// RunMigrationHooks runs the hooks of the stage in the declared order, and stops at the first failed hook.
+// The SQL hooks are executed by the execute function, and the webhook hooks are POSTed with the request.
+func RunMigrationHooks(ctx context.Context, hooks []*api.MigrationHook, stage api.MigrationHookStage, request *MigrationHookWebhookRequest, execute func(ctx context.Context, statement string) error) ([]*api.MigrationHookResult, error) {
+	var results []*api.MigrationHookResult
+	for i, hook := range hooks {
+		if hook.Stage != stage {
+			continue
+		}
+		start := time.Now()
+		var err error
+		switch hook.Type {
+		case api.MigrationHookSQL:
+			err = execute(ctx, hook.Statement)
+		case api.MigrationHookWebhook:
+			err = postMigrationHookWebhook(ctx, hook.URL, request)
+		default:
+			err = errors.Errorf("unsupported hook type %q", hook.Type)
+		}
+		result := &api.MigrationHookResult{
+			Stage:      hook.Stage,
+			Type:       hook.Type,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		results = append(results, result)
+		if err != nil {
+			result.Error = err.Error()
+			return results, errors.Wrapf(err, "%s hook %d (%s) failed", strings.ToLower(string(stage)), i+1, hook.Type)
+		}
+	}
+	return results, nil
+}
+
+func postMigrationHookWebhook(ctx context.Context, hookURL string, request *MigrationHookWebhookRequest) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal hook request to %s", hookURL)
+	}
+	ctx, cancel := context.WithTimeout(ctx, migrationHookWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hookURL, bytes.NewBuffer(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to construct hook request to %s", hookURL)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to POST hook to %s", hookURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("failed to POST hook to %s, status code: %d, response body: %s", hookURL, resp.StatusCode, b)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestValidateMigrationHooks(t *testing.T) {
+	require.NoError(t, ValidateMigrationHooks([]*api.MigrationHook{
+		{Stage: api.MigrationHookPre, Type: api.MigrationHookSQL, Statement: "SET session_replication_role = replica;"},
+		{Stage: api.MigrationHookPost, Type: api.MigrationHookWebhook, URL: "https://example.com/hook"},
+	}))
+	require.Error(t, ValidateMigrationHooks([]*api.MigrationHook{{Stage: "DURING", Type: api.MigrationHookSQL, Statement: "SELECT 1;"}}))
+	require.Error(t, ValidateMigrationHooks([]*api.MigrationHook{{Stage: api.MigrationHookPre, Type: api.MigrationHookSQL, Statement: " "}}))
+	require.Error(t, ValidateMigrationHooks([]*api.MigrationHook{{Stage: api.MigrationHookPre, Type: api.MigrationHookWebhook, URL: "ftp://example.com"}}))
+}
+
+func TestRunMigrationHooks(t *testing.T) {
+	var requests []*MigrationHookWebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &MigrationHookWebhookRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		requests = append(requests, request)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	hooks := []*api.MigrationHook{
+		{Stage: api.MigrationHookPre, Type: api.MigrationHookSQL, Statement: "ALTER TABLE t DISABLE TRIGGER ALL;"},
+		{Stage: api.MigrationHookPost, Type: api.MigrationHookWebhook, URL: server.URL + "/ok"},
+		{Stage: api.MigrationHookPre, Type: api.MigrationHookWebhook, URL: server.URL + "/ok"},
+		{Stage: api.MigrationHookPost, Type: api.MigrationHookWebhook, URL: server.URL + "/fail"},
+		{Stage: api.MigrationHookPost, Type: api.MigrationHookSQL, Statement: "ALTER TABLE t ENABLE TRIGGER ALL;"},
+	}
+	var executed []string
+	execute := func(_ context.Context, statement string) error {
+		executed = append(executed, statement)
+		return nil
+	}
+
+	results, err := RunMigrationHooks(context.Background(), hooks, api.MigrationHookPre, &MigrationHookWebhookRequest{Stage: api.MigrationHookPre, TaskID: 1}, execute)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, []string{"ALTER TABLE t DISABLE TRIGGER ALL;"}, executed)
+	require.Equal(t, []*MigrationHookWebhookRequest{{Stage: api.MigrationHookPre, TaskID: 1}}, requests)
+
+	// The hooks after the failed one are not run.
+	results, err = RunMigrationHooks(context.Background(), hooks, api.MigrationHookPost, &MigrationHookWebhookRequest{Stage: api.MigrationHookPost, TaskID: 1}, execute)
+	require.Error(t, err)
+	require.Len(t, results, 2)
+	require.Empty(t, results[0].Error)
+	require.NotEmpty(t, results[1].Error)
+	require.Len(t, executed, 1)
+
+	_, err = RunMigrationHooks(context.Background(), hooks[:1], api.MigrationHookPre, nil, func(context.Context, string) error {
+		return errors.New("permission denied")
+	})
+	require.EqualError(t, err, "pre hook 1 (SQL) failed: permission denied")
+}
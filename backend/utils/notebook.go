@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+var (
+	notebookParameterNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// notebookPlaceholderRegexp matches the "{{name}}" placeholders, the spaces inside the braces are allowed.
+	notebookPlaceholderRegexp = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+)
+
+// ParseNotebookPayload parses and validates the payload of the notebook sheet.
+func ParseNotebookPayload(payload string) (*api.SheetNotebookPayload, error) {
+	notebook := &api.SheetNotebookPayload{}
+	if payload != "" {
+		if err := json.Unmarshal([]byte(payload), notebook); err != nil {
+			return nil, errors.Wrapf(err, "invalid notebook payload")
+		}
+	}
+
+	parameters := make(map[string]bool)
+	for _, parameter := range notebook.ParameterList {
+		if !notebookParameterNameRegexp.MatchString(parameter.Name) {
+			return nil, errors.Errorf("invalid parameter name %q", parameter.Name)
+		}
+		if parameters[parameter.Name] {
+			return nil, errors.Errorf("duplicate parameter %q", parameter.Name)
+		}
+		parameters[parameter.Name] = true
+		switch parameter.Type {
+		case api.NotebookParameterText:
+		case api.NotebookParameterNumber:
+			if parameter.DefaultValue != "" {
+				if _, err := strconv.ParseFloat(parameter.DefaultValue, 64); err != nil {
+					return nil, errors.Errorf("default value %q of the number parameter %q is not a number", parameter.DefaultValue, parameter.Name)
+				}
+			}
+		default:
+			return nil, errors.Errorf("invalid type %q of parameter %q", parameter.Type, parameter.Name)
+		}
+	}
+
+	cells := make(map[string]bool)
+	for _, cell := range notebook.CellList {
+		if cell.ID == "" {
+			return nil, errors.Errorf("cell ID is required")
+		}
+		if cells[cell.ID] {
+			return nil, errors.Errorf("duplicate cell ID %q", cell.ID)
+		}
+		cells[cell.ID] = true
+		for _, match := range notebookPlaceholderRegexp.FindAllStringSubmatch(cell.Statement, -1) {
+			if !parameters[match[1]] {
+				return nil, errors.Errorf("cell %q refers the undefined parameter %q", cell.ID, match[1])
+			}
+		}
+	}
+	return notebook, nil
+}
+
+// GetNotebookStatement returns the statements of the cells joined, which is the statement of the notebook sheet for
+// the search and the size.
+func GetNotebookStatement(notebook *api.SheetNotebookPayload) string {
+	var statements []string
+	for _, cell := range notebook.CellList {
+		statements = append(statements, strings.TrimSpace(cell.Statement))
+	}
+	return strings.Join(statements, "\n\n")
+}
+
+// RenderNotebookStatement replaces the "{{name}}" placeholders in the statement of the cell with the literals of the
+// parameter values. The text values are quoted and escaped for the engine, so they can't break out of the literals.
+// The placeholders are replaced everywhere, including the ones in the string literals and comments.
+func RenderNotebookStatement(engine db.Type, statement string, parameterList []*api.NotebookParameter, values map[string]string) (string, error) {
+	parameters := make(map[string]*api.NotebookParameter)
+	for _, parameter := range parameterList {
+		parameters[parameter.Name] = parameter
+	}
+	var renderErr error
+	rendered := notebookPlaceholderRegexp.ReplaceAllStringFunc(statement, func(placeholder string) string {
+		name := notebookPlaceholderRegexp.FindStringSubmatch(placeholder)[1]
+		parameter, ok := parameters[name]
+		if !ok {
+			if renderErr == nil {
+				renderErr = errors.Errorf("undefined parameter %q", name)
+			}
+			return placeholder
+		}
+		value, ok := values[name]
+		if !ok {
+			value = parameter.DefaultValue
+		}
+		switch parameter.Type {
+		case api.NotebookParameterNumber:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				if renderErr == nil {
+					renderErr = errors.Errorf("value %q of the number parameter %q is not a number", value, name)
+				}
+				return placeholder
+			}
+			return value
+		default:
+			return quoteNotebookText(engine, value)
+		}
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+	return rendered, nil
+}
+
+func quoteNotebookText(engine db.Type, value string) string {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		// The backslash is the escape character in the MySQL string literals by default.
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
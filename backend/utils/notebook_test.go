@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestParseNotebookPayload(t *testing.T) {
+	notebook, err := ParseNotebookPayload(`{
+		"cellList": [
+			{"id": "c1", "title": "Orders", "statement": "SELECT * FROM orders WHERE user_id = {{ user_id }};"},
+			{"id": "c2", "statement": "SELECT * FROM users WHERE email = {{email}}"}
+		],
+		"parameterList": [
+			{"name": "user_id", "type": "NUMBER", "defaultValue": "1"},
+			{"name": "email", "type": "TEXT"}
+		]
+	}`)
+	require.NoError(t, err)
+	require.Len(t, notebook.CellList, 2)
+	require.Equal(t, "SELECT * FROM orders WHERE user_id = {{ user_id }};\n\nSELECT * FROM users WHERE email = {{email}}", GetNotebookStatement(notebook))
+
+	for _, payload := range []string{
+		`{"cellList": [{"id": "c1"}, {"id": "c1"}]}`,
+		`{"cellList": [{"statement": "SELECT 1"}]}`,
+		`{"cellList": [{"id": "c1", "statement": "SELECT {{x}}"}]}`,
+		`{"parameterList": [{"name": "1x", "type": "TEXT"}]}`,
+		`{"parameterList": [{"name": "x", "type": "TEXT"}, {"name": "x", "type": "TEXT"}]}`,
+		`{"parameterList": [{"name": "x", "type": "DATE"}]}`,
+		`{"parameterList": [{"name": "x", "type": "NUMBER", "defaultValue": "one"}]}`,
+		`[]`,
+	} {
+		_, err := ParseNotebookPayload(payload)
+		require.Error(t, err, payload)
+	}
+}
+
+func TestRenderNotebookStatement(t *testing.T) {
+	parameterList := []*api.NotebookParameter{
+		{Name: "user_id", Type: api.NotebookParameterNumber, DefaultValue: "1"},
+		{Name: "email", Type: api.NotebookParameterText},
+	}
+	statement := "SELECT * FROM users WHERE id = {{ user_id }} AND email = {{email}}"
+
+	rendered, err := RenderNotebookStatement(db.Postgres, statement, parameterList, map[string]string{"email": `o'neil\`})
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM users WHERE id = 1 AND email = 'o''neil\'`, rendered)
+
+	rendered, err = RenderNotebookStatement(db.MySQL, statement, parameterList, map[string]string{"user_id": "2.5", "email": `o'neil\`})
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM users WHERE id = 2.5 AND email = 'o''neil\\'`, rendered)
+
+	_, err = RenderNotebookStatement(db.Postgres, statement, parameterList, map[string]string{"user_id": "1 OR 1=1"})
+	require.Error(t, err)
+	_, err = RenderNotebookStatement(db.Postgres, "SELECT {{missing}}", parameterList, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// RangePartitionTable is the range partitioned table maintained by the partition retention rule.
+type RangePartitionTable struct {
+	// Method and Expression are the MySQL partition method and expression, e.g. "RANGE COLUMNS" and "`created_at`".
+	Method     string
+	Expression string
+	// PartitionList is the names of the existing partitions, they are the partition tables for PostgreSQL.
+	PartitionList []string
+	// MaxValuePartition is the MySQL partition with the MAXVALUE upper bound, the new partitions are reorganized from it.
+	MaxValuePartition string
+}
+
+// GeneratePartitionMaintenanceStatement generates the statement to create the future partitions and expire the past
+// partitions of the table under the retention rule at the time now. It returns the empty statement if the partitions
+// are up to date.
+//
+// The partitions are named as pYYYYMMDD or pYYYYMM after the start of the period, and the PostgreSQL partition tables
+// are prefixed with the table name, e.g. events_p20230102. The partitions not following the naming convention are
+// left intact. The missing partitions are only created after the latest one, since the MySQL range partitions could
+// only be appended.
+func GeneratePartitionMaintenanceStatement(engine db.Type, rule *api.PartitionRetentionRule, table *RangePartitionTable, now time.Time) (string, error) {
+	if engine != db.MySQL && engine != db.Postgres {
+		return "", errors.Errorf("partition retention is not supported for engine %q", engine)
+	}
+	prefix := "p"
+	if engine == db.Postgres {
+		prefix = rule.Table + "_p"
+	}
+
+	var existing []time.Time
+	existingName := make(map[time.Time]string)
+	for _, name := range table.PartitionList {
+		start, ok := parsePartitionName(rule.Interval, prefix, name, now.Location())
+		if !ok {
+			continue
+		}
+		existing = append(existing, start)
+		existingName[start] = name
+	}
+	sort.Slice(existing, func(i, j int) bool {
+		return existing[i].Before(existing[j])
+	})
+
+	current := partitionPeriodStart(rule.Interval, now)
+	var creates []time.Time
+	for i := 0; i <= rule.PrecreateCount; i++ {
+		start := addPartitionPeriods(rule.Interval, current, i)
+		if _, ok := existingName[start]; ok {
+			continue
+		}
+		if len(existing) > 0 && !start.After(existing[len(existing)-1]) {
+			continue
+		}
+		creates = append(creates, start)
+	}
+	var expires []string
+	if rule.RetentionCount > 0 {
+		threshold := addPartitionPeriods(rule.Interval, current, -rule.RetentionCount)
+		for _, start := range existing {
+			if start.Before(threshold) {
+				expires = append(expires, existingName[start])
+			}
+		}
+	}
+	// Keep at least one partition for MySQL, since the last partition cannot be dropped.
+	if engine == db.MySQL && len(creates) == 0 && table.MaxValuePartition == "" && len(expires) > 0 && len(expires) == len(table.PartitionList) {
+		expires = expires[:len(expires)-1]
+	}
+
+	if engine == db.MySQL {
+		return generateMySQLPartitionStatement(rule, table, prefix, creates, expires)
+	}
+	return generatePostgresPartitionStatement(rule, prefix, creates, expires), nil
+}
+
+func generateMySQLPartitionStatement(rule *api.PartitionRetentionRule, table *RangePartitionTable, prefix string, creates []time.Time, expires []string) (string, error) {
+	var buf strings.Builder
+	tableName := quoteMySQLIdentifier(rule.Table)
+	if len(creates) > 0 {
+		var partitions []string
+		for _, start := range creates {
+			bound, err := mysqlPartitionBound(table, addPartitionPeriods(rule.Interval, start, 1))
+			if err != nil {
+				return "", err
+			}
+			partitions = append(partitions, fmt.Sprintf("PARTITION %s VALUES LESS THAN (%s)", quoteMySQLIdentifier(partitionName(rule.Interval, prefix, start)), bound))
+		}
+		if table.MaxValuePartition != "" {
+			maxValuePartition := quoteMySQLIdentifier(table.MaxValuePartition)
+			partitions = append(partitions, fmt.Sprintf("PARTITION %s VALUES LESS THAN MAXVALUE", maxValuePartition))
+			_, _ = fmt.Fprintf(&buf, "ALTER TABLE %s REORGANIZE PARTITION %s INTO (\n  %s\n);\n", tableName, maxValuePartition, strings.Join(partitions, ",\n  "))
+		} else {
+			_, _ = fmt.Fprintf(&buf, "ALTER TABLE %s ADD PARTITION (\n  %s\n);\n", tableName, strings.Join(partitions, ",\n  "))
+		}
+	}
+	for _, name := range expires {
+		partition := quoteMySQLIdentifier(name)
+		if rule.ExpireAction == api.PartitionExpireActionArchive {
+			// The partition is exchanged with an empty non-partitioned copy of the table before dropping.
+			archive := quoteMySQLIdentifier(fmt.Sprintf("%s_%s", rule.Table, name))
+			_, _ = fmt.Fprintf(&buf, "CREATE TABLE %s LIKE %s;\n", archive, tableName)
+			_, _ = fmt.Fprintf(&buf, "ALTER TABLE %s REMOVE PARTITIONING;\n", archive)
+			_, _ = fmt.Fprintf(&buf, "ALTER TABLE %s EXCHANGE PARTITION %s WITH TABLE %s;\n", tableName, partition, archive)
+		}
+		_, _ = fmt.Fprintf(&buf, "ALTER TABLE %s DROP PARTITION %s;\n", tableName, partition)
+	}
+	return buf.String(), nil
+}
+
+func generatePostgresPartitionStatement(rule *api.PartitionRetentionRule, prefix string, creates []time.Time, expires []string) string {
+	schema := rule.Schema
+	if schema == "" {
+		schema = "public"
+	}
+	qualify := func(name string) string {
+		return fmt.Sprintf("%s.%s", quotePostgresIdentifier(schema), quotePostgresIdentifier(name))
+	}
+	var buf strings.Builder
+	tableName := qualify(rule.Table)
+	for _, start := range creates {
+		_, _ = fmt.Fprintf(&buf, "CREATE TABLE %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s');\n",
+			qualify(partitionName(rule.Interval, prefix, start)),
+			tableName,
+			start.Format("2006-01-02"),
+			addPartitionPeriods(rule.Interval, start, 1).Format("2006-01-02"),
+		)
+	}
+	for _, name := range expires {
+		if rule.ExpireAction == api.PartitionExpireActionArchive {
+			// The detached partition is kept as a standalone table.
+			_, _ = fmt.Fprintf(&buf, "ALTER TABLE %s DETACH PARTITION %s;\n", tableName, qualify(name))
+			continue
+		}
+		_, _ = fmt.Fprintf(&buf, "DROP TABLE %s;\n", qualify(name))
+	}
+	return buf.String()
+}
+
+// mysqlPartitionBound returns the VALUES LESS THAN bound of the partition ending at the time end.
+func mysqlPartitionBound(table *RangePartitionTable, end time.Time) (string, error) {
+	date := fmt.Sprintf("'%s'", end.Format("2006-01-02"))
+	if strings.EqualFold(table.Method, "RANGE COLUMNS") {
+		return date, nil
+	}
+	if strings.EqualFold(table.Method, "RANGE") {
+		expression := strings.ToLower(table.Expression)
+		switch {
+		case strings.HasPrefix(expression, "to_days("):
+			return fmt.Sprintf("TO_DAYS(%s)", date), nil
+		case strings.HasPrefix(expression, "unix_timestamp("):
+			return fmt.Sprintf("UNIX_TIMESTAMP(%s)", date), nil
+		}
+	}
+	return "", errors.Errorf("unsupported partition %s (%s), the table should be partitioned by RANGE COLUMNS, RANGE TO_DAYS or RANGE UNIX_TIMESTAMP", table.Method, table.Expression)
+}
+
+func partitionLayout(interval api.PartitionInterval) string {
+	if interval == api.PartitionIntervalMonth {
+		return "200601"
+	}
+	return "20060102"
+}
+
+func partitionName(interval api.PartitionInterval, prefix string, start time.Time) string {
+	return prefix + start.Format(partitionLayout(interval))
+}
+
+func parsePartitionName(interval api.PartitionInterval, prefix, name string, location *time.Location) (time.Time, bool) {
+	if !strings.HasPrefix(name, prefix) {
+		return time.Time{}, false
+	}
+	suffix := strings.TrimPrefix(name, prefix)
+	layout := partitionLayout(interval)
+	if len(suffix) != len(layout) {
+		return time.Time{}, false
+	}
+	start, err := time.ParseInLocation(layout, suffix, location)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+func partitionPeriodStart(interval api.PartitionInterval, t time.Time) time.Time {
+	if interval == api.PartitionIntervalMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func addPartitionPeriods(interval api.PartitionInterval, start time.Time, n int) time.Time {
+	if interval == api.PartitionIntervalMonth {
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, n)
+}
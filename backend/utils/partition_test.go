@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestGeneratePartitionMaintenanceStatement(t *testing.T) {
+	now := time.Date(2023, 3, 15, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		engine db.Type
+		rule   *api.PartitionRetentionRule
+		table  *RangePartitionTable
+		want   string
+		err    string
+	}{
+		{
+			engine: db.MySQL,
+			rule: &api.PartitionRetentionRule{
+				Table:          "events",
+				Interval:       api.PartitionIntervalDay,
+				PrecreateCount: 2,
+				RetentionCount: 2,
+				ExpireAction:   api.PartitionExpireActionDrop,
+			},
+			table: &RangePartitionTable{
+				Method:        "RANGE COLUMNS",
+				Expression:    "`created_at`",
+				PartitionList: []string{"p20230311", "p20230312", "p20230313", "p20230314", "p20230315"},
+			},
+			want: "ALTER TABLE `events` ADD PARTITION (\n" +
+				"  PARTITION `p20230316` VALUES LESS THAN ('2023-03-17'),\n" +
+				"  PARTITION `p20230317` VALUES LESS THAN ('2023-03-18')\n" +
+				");\n" +
+				"ALTER TABLE `events` DROP PARTITION `p20230311`;\n" +
+				"ALTER TABLE `events` DROP PARTITION `p20230312`;\n",
+		},
+		{
+			engine: db.MySQL,
+			rule: &api.PartitionRetentionRule{
+				Table:          "orders",
+				Interval:       api.PartitionIntervalMonth,
+				PrecreateCount: 1,
+				RetentionCount: 1,
+				ExpireAction:   api.PartitionExpireActionArchive,
+			},
+			table: &RangePartitionTable{
+				Method:            "RANGE",
+				Expression:        "to_days(`created_at`)",
+				PartitionList:     []string{"p202301", "p202302", "p202303", "pmax"},
+				MaxValuePartition: "pmax",
+			},
+			want: "ALTER TABLE `orders` REORGANIZE PARTITION `pmax` INTO (\n" +
+				"  PARTITION `p202304` VALUES LESS THAN (TO_DAYS('2023-05-01')),\n" +
+				"  PARTITION `pmax` VALUES LESS THAN MAXVALUE\n" +
+				");\n" +
+				"CREATE TABLE `orders_p202301` LIKE `orders`;\n" +
+				"ALTER TABLE `orders_p202301` REMOVE PARTITIONING;\n" +
+				"ALTER TABLE `orders` EXCHANGE PARTITION `p202301` WITH TABLE `orders_p202301`;\n" +
+				"ALTER TABLE `orders` DROP PARTITION `p202301`;\n",
+		},
+		{
+			engine: db.MySQL,
+			rule: &api.PartitionRetentionRule{
+				Table:          "events",
+				Interval:       api.PartitionIntervalDay,
+				PrecreateCount: 1,
+				ExpireAction:   api.PartitionExpireActionDrop,
+			},
+			table: &RangePartitionTable{
+				Method:        "RANGE COLUMNS",
+				Expression:    "`created_at`",
+				PartitionList: []string{"p20230315", "p20230316"},
+			},
+			want: "",
+		},
+		{
+			engine: db.MySQL,
+			rule: &api.PartitionRetentionRule{
+				Table:          "events",
+				Interval:       api.PartitionIntervalDay,
+				PrecreateCount: 1,
+				ExpireAction:   api.PartitionExpireActionDrop,
+			},
+			table: &RangePartitionTable{
+				Method:     "LIST",
+				Expression: "`region`",
+			},
+			err: "unsupported partition LIST (`region`), the table should be partitioned by RANGE COLUMNS, RANGE TO_DAYS or RANGE UNIX_TIMESTAMP",
+		},
+		{
+			engine: db.Postgres,
+			rule: &api.PartitionRetentionRule{
+				Table:          "events",
+				Interval:       api.PartitionIntervalDay,
+				PrecreateCount: 1,
+				RetentionCount: 1,
+				ExpireAction:   api.PartitionExpireActionArchive,
+			},
+			table: &RangePartitionTable{
+				PartitionList: []string{"events_p20230313", "events_p20230314", "events_default"},
+			},
+			want: `CREATE TABLE "public"."events_p20230315" PARTITION OF "public"."events" FOR VALUES FROM ('2023-03-15') TO ('2023-03-16');
+CREATE TABLE "public"."events_p20230316" PARTITION OF "public"."events" FOR VALUES FROM ('2023-03-16') TO ('2023-03-17');
+ALTER TABLE "public"."events" DETACH PARTITION "public"."events_p20230313";
+`,
+		},
+		{
+			engine: db.Postgres,
+			rule: &api.PartitionRetentionRule{
+				Schema:         "log",
+				Table:          "audit",
+				Interval:       api.PartitionIntervalMonth,
+				RetentionCount: 12,
+				ExpireAction:   api.PartitionExpireActionDrop,
+			},
+			table: &RangePartitionTable{
+				PartitionList: []string{"audit_p202201", "audit_p202203", "audit_p202303"},
+			},
+			want: `DROP TABLE "log"."audit_p202201";
+`,
+		},
+		{
+			engine: db.Oracle,
+			rule:   &api.PartitionRetentionRule{Table: "events", Interval: api.PartitionIntervalDay},
+			table:  &RangePartitionTable{},
+			err:    `partition retention is not supported for engine "ORACLE"`,
+		},
+	}
+
+	for _, test := range tests {
+		statement, err := GeneratePartitionMaintenanceStatement(test.engine, test.rule, test.table, now)
+		if test.err != "" {
+			require.EqualError(t, err, test.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.want, statement)
+	}
+}
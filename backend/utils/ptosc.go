@@ -0,0 +1,224 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	ghostsql "github.com/github/gh-ost/go/sql"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/common"
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+const (
+	// ptOSCBinary is the pt-online-schema-change binary looked up in PATH.
+	ptOSCBinary = "pt-online-schema-change"
+	// ptOSCOutputTailSize is the number of the output lines kept for the error message.
+	ptOSCOutputTailSize = 20
+)
+
+var (
+	ptOSCProgressReg = regexp.MustCompile(`^Copying .+:\s+(\d+)%`)
+	ptOSCCopiedReg   = regexp.MustCompile(`^Copied rows OK`)
+)
+
+// PtOSCConfig is the configuration for pt-online-schema-change.
+type PtOSCConfig struct {
+	// connection
+	host     string
+	port     string
+	user     string
+	password string
+	database string
+	table    string
+
+	// alter is the alter specification without the ALTER TABLE clause.
+	alter string
+	// throttling
+	chunkSize     int64
+	maxLagSeconds int64
+	maxLoad       string
+	criticalLoad  string
+}
+
+// GetPtOSCConfig returns a pt-online-schema-change configuration for migration.
+func GetPtOSCConfig(database *store.DatabaseMessage, dataSource *store.DataSourceMessage, secret string, tableName string, statement string, config *api.OnlineMigrationConfig) (PtOSCConfig, error) {
+	alter, err := GetPtOSCAlterSpecification(statement)
+	if err != nil {
+		return PtOSCConfig{}, err
+	}
+	password, err := common.Unobfuscate(dataSource.ObfuscatedPassword, secret)
+	if err != nil {
+		return PtOSCConfig{}, err
+	}
+	ptOSCConfig := PtOSCConfig{
+		host:     dataSource.Host,
+		port:     dataSource.Port,
+		user:     dataSource.Username,
+		password: password,
+		database: database.DatabaseName,
+		table:    tableName,
+		alter:    alter,
+	}
+	if config != nil {
+		ptOSCConfig.chunkSize = config.ChunkSize
+		ptOSCConfig.maxLagSeconds = config.MaxLagSeconds
+		ptOSCConfig.maxLoad = config.MaxLoad
+		ptOSCConfig.criticalLoad = config.CriticalLoad
+	}
+	return ptOSCConfig, nil
+}
+
+// GetPtOSCAlterSpecification returns the alter specification of the ALTER TABLE statement which is passed to pt-osc --alter.
+func GetPtOSCAlterSpecification(statement string) (string, error) {
+	statement = strings.TrimSuffix(strings.Join(strings.Fields(statement), " "), ";")
+	parser := ghostsql.NewParserFromAlterStatement(statement)
+	if !parser.HasExplicitTable() || parser.GetAlterStatementOptions() == "" {
+		return "", errors.Errorf("failed to parse alter specification from statement, statement: %v", statement)
+	}
+	return strings.TrimSpace(parser.GetAlterStatementOptions()), nil
+}
+
+// getPtOSCArgs returns the pt-online-schema-change arguments.
+// The sync leaves the new table and the triggers in place, so that the cutover can swap the tables later.
+func (c PtOSCConfig) getPtOSCArgs(defaultsFile string, dryRun bool) []string {
+	args := []string{
+		"--alter", c.alter,
+		"--new-table-name", "_%T_new",
+		"--progress", "percentage,1",
+		"--print",
+	}
+	if dryRun {
+		args = append(args, "--dry-run")
+	} else {
+		args = append(args, "--execute", "--no-swap-tables", "--no-drop-new-table", "--no-drop-triggers")
+	}
+	if c.chunkSize > 0 {
+		args = append(args, "--chunk-size", strconv.FormatInt(c.chunkSize, 10))
+	}
+	if c.maxLagSeconds > 0 {
+		args = append(args, "--max-lag", strconv.FormatInt(c.maxLagSeconds, 10))
+	}
+	if c.maxLoad != "" {
+		args = append(args, "--max-load", c.maxLoad)
+	}
+	if c.criticalLoad != "" {
+		args = append(args, "--critical-load", c.criticalLoad)
+	}
+	// The credentials are read from the defaults file to avoid exposing the password in the process list.
+	return append(args, fmt.Sprintf("F=%s,D=%s,t=%s", defaultsFile, c.database, c.table))
+}
+
+func (c PtOSCConfig) writeDefaultsFile() (string, error) {
+	f, err := os.CreateTemp("", "pt-osc.*.cnf")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create pt-osc defaults file")
+	}
+	defer f.Close()
+	port := c.port
+	if port == "" {
+		port = "3306"
+	}
+	content := fmt.Sprintf("[client]\nhost=%s\nport=%s\nuser=%s\npassword=%s\n", c.host, port, c.user, c.password)
+	if _, err := f.WriteString(content); err != nil {
+		_ = os.Remove(f.Name())
+		return "", errors.Wrap(err, "failed to write pt-osc defaults file")
+	}
+	return f.Name(), nil
+}
+
+// RunPtOSC runs pt-online-schema-change, onProgress receives the copy progress in percentage.
+// With dryRun, pt-osc creates and alters the new table without copying the rows to validate the statement.
+func RunPtOSC(ctx context.Context, config PtOSCConfig, dryRun bool, onProgress func(percentage int64)) error {
+	binary, err := exec.LookPath(ptOSCBinary)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find %s in PATH", ptOSCBinary)
+	}
+	defaultsFile, err := config.writeDefaultsFile()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(defaultsFile)
+
+	cmd := exec.CommandContext(ctx, binary, config.getPtOSCArgs(defaultsFile, dryRun)...)
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Start(); err != nil {
+		return errors.Wrapf(err, "failed to start %s", ptOSCBinary)
+	}
+
+	scanDone := make(chan []string, 1)
+	go func() {
+		scanDone <- scanPtOSCOutput(reader, onProgress)
+	}()
+	err = cmd.Wait()
+	_ = writer.Close()
+	tail := <-scanDone
+	if err != nil {
+		return errors.Wrapf(err, "failed to run %s, output:\n%s", ptOSCBinary, strings.Join(tail, "\n"))
+	}
+	return nil
+}
+
+// scanPtOSCOutput reports the progress parsed from the pt-osc output and returns the last output lines.
+func scanPtOSCOutput(r io.Reader, onProgress func(percentage int64)) []string {
+	var tail []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if percentage, ok := parsePtOSCProgress(line); ok && onProgress != nil {
+			onProgress(percentage)
+		}
+		tail = append(tail, line)
+		if len(tail) > ptOSCOutputTailSize {
+			tail = tail[1:]
+		}
+	}
+	// Drain the pipe so that pt-osc never blocks on writing if the scanner stops early.
+	_, _ = io.Copy(io.Discard, r)
+	return tail
+}
+
+func parsePtOSCProgress(line string) (int64, bool) {
+	if ptOSCCopiedReg.MatchString(line) {
+		return 100, true
+	}
+	matches := ptOSCProgressReg.FindStringSubmatch(line)
+	if len(matches) != 2 {
+		return 0, false
+	}
+	percentage, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return percentage, true
+}
+
+// GetPtOSCCutoverStatement returns the statement swapping the original table with the new table synced by pt-osc.
+// The triggers left by the sync are dropped together with the old table.
+func GetPtOSCCutoverStatement(databaseName, tableName string) string {
+	quote := func(name string) string {
+		return fmt.Sprintf("`%s`.`%s`", strings.ReplaceAll(databaseName, "`", "``"), strings.ReplaceAll(name, "`", "``"))
+	}
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "RENAME TABLE %s TO %s, %s TO %s;\n", quote(tableName), quote("_"+tableName+"_old"), quote("_"+tableName+"_new"), quote(tableName))
+	for _, action := range []string{"del", "upd", "ins"} {
+		_, _ = fmt.Fprintf(&buf, "DROP TRIGGER IF EXISTS %s;\n", quote(fmt.Sprintf("pt_osc_%s_%s_%s", databaseName, tableName, action)))
+	}
+	_, _ = fmt.Fprintf(&buf, "DROP TABLE IF EXISTS %s;\n", quote("_"+tableName+"_old"))
+	return buf.String()
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPtOSCAlterSpecification(t *testing.T) {
+	a := require.New(t)
+
+	tests := []struct {
+		statement string
+		want      string
+		wantErr   bool
+	}{
+		{
+			statement: "ALTER TABLE `t1` ADD COLUMN `c1` INT NOT NULL DEFAULT 0;",
+			want:      "ADD COLUMN `c1` INT NOT NULL DEFAULT 0",
+		},
+		{
+			statement: "ALTER TABLE t1\n  ADD INDEX idx_c1 (c1),\n  DROP COLUMN c2",
+			want:      "ADD INDEX idx_c1 (c1), DROP COLUMN c2",
+		},
+		{
+			statement: "CREATE TABLE t1 (id INT)",
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := GetPtOSCAlterSpecification(test.statement)
+		if test.wantErr {
+			a.Error(err)
+			continue
+		}
+		a.NoError(err)
+		a.Equal(test.want, got)
+	}
+}
+
+func TestGetPtOSCArgs(t *testing.T) {
+	a := require.New(t)
+
+	config := PtOSCConfig{
+		database:      "db",
+		table:         "t1",
+		alter:         "ADD COLUMN c1 INT",
+		chunkSize:     500,
+		maxLagSeconds: 2,
+		maxLoad:       "Threads_running=25",
+	}
+	a.Equal([]string{
+		"--alter", "ADD COLUMN c1 INT",
+		"--new-table-name", "_%T_new",
+		"--progress", "percentage,1",
+		"--print",
+		"--execute", "--no-swap-tables", "--no-drop-new-table", "--no-drop-triggers",
+		"--chunk-size", "500",
+		"--max-lag", "2",
+		"--max-load", "Threads_running=25",
+		"F=/tmp/pt-osc.cnf,D=db,t=t1",
+	}, config.getPtOSCArgs("/tmp/pt-osc.cnf", false))
+	a.Equal([]string{
+		"--alter", "ADD COLUMN c1 INT",
+		"--new-table-name", "_%T_new",
+		"--progress", "percentage,1",
+		"--print",
+		"--dry-run",
+		"--chunk-size", "500",
+		"--max-lag", "2",
+		"--max-load", "Threads_running=25",
+		"F=/tmp/pt-osc.cnf,D=db,t=t1",
+	}, config.getPtOSCArgs("/tmp/pt-osc.cnf", true))
+}
+
+func TestParsePtOSCProgress(t *testing.T) {
+	a := require.New(t)
+
+	tests := []struct {
+		line string
+		want int64
+		ok   bool
+	}{
+		{line: "Copying `db`.`t1`:  45% 00:12 remain", want: 45, ok: true},
+		{line: "Copied rows OK.", want: 100, ok: true},
+		{line: "Created new table db._t1_new OK.", ok: false},
+	}
+	for _, test := range tests {
+		got, ok := parsePtOSCProgress(test.line)
+		a.Equal(test.ok, ok, test.line)
+		a.Equal(test.want, got, test.line)
+	}
+}
+
+func TestGetPtOSCCutoverStatement(t *testing.T) {
+	a := require.New(t)
+
+	want := "RENAME TABLE `db`.`t1` TO `db`.`_t1_old`, `db`.`_t1_new` TO `db`.`t1`;\n" +
+		"DROP TRIGGER IF EXISTS `db`.`pt_osc_db_t1_del`;\n" +
+		"DROP TRIGGER IF EXISTS `db`.`pt_osc_db_t1_upd`;\n" +
+		"DROP TRIGGER IF EXISTS `db`.`pt_osc_db_t1_ins`;\n" +
+		"DROP TABLE IF EXISTS `db`.`_t1_old`;\n"
+	a.Equal(want, GetPtOSCCutoverStatement("db", "t1"))
+}
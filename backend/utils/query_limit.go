@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// QueryLimit is the effective limits of a query, zero means no limit.
+type QueryLimit struct {
+	MaxExecutionSeconds int
+	MaxRowCount         int
+}
+
+// GetEffectiveQueryLimit returns the limits of the query limit policy for the principal having the project roles.
+// The principal having several roles gets the most permissive limits of their rules, and the one having no role with
+// a rule isn't limited. The nil roles mean the query isn't in the context of a project, such as the instance level
+// queries, so the most restrictive limits of all rules apply.
+func GetEffectiveQueryLimit(policy *api.QueryLimitPolicy, projectRoles map[api.Role]bool) *QueryLimit {
+	limit := &QueryLimit{}
+	if policy == nil {
+		return limit
+	}
+	if projectRoles == nil {
+		for _, rule := range policy.RuleList {
+			limit.MaxExecutionSeconds = minQueryLimit(limit.MaxExecutionSeconds, rule.MaxExecutionSeconds)
+			limit.MaxRowCount = minQueryLimit(limit.MaxRowCount, rule.MaxRowCount)
+		}
+		return limit
+	}
+
+	matched := false
+	for _, rule := range policy.RuleList {
+		if !projectRoles[rule.Role] {
+			continue
+		}
+		if !matched {
+			limit.MaxExecutionSeconds, limit.MaxRowCount = rule.MaxExecutionSeconds, rule.MaxRowCount
+			matched = true
+			continue
+		}
+		limit.MaxExecutionSeconds = maxQueryLimit(limit.MaxExecutionSeconds, rule.MaxExecutionSeconds)
+		limit.MaxRowCount = maxQueryLimit(limit.MaxRowCount, rule.MaxRowCount)
+	}
+	return limit
+}
+
+// ApplyRowLimit returns the row limit requested capped by the limit, zero means no limit for both.
+func (l *QueryLimit) ApplyRowLimit(requested int) int {
+	return minQueryLimit(requested, l.MaxRowCount)
+}
+
+// WithTimeout returns the context canceled after the max execution time.
+func (l *QueryLimit) WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if l.MaxExecutionSeconds <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(l.MaxExecutionSeconds)*time.Second)
+}
+
+// minQueryLimit returns the more restrictive limit, zero means no limit.
+func minQueryLimit(a, b int) int {
+	if a <= 0 {
+		return b
+	}
+	if b <= 0 || a < b {
+		return a
+	}
+	return b
+}
+
+// maxQueryLimit returns the more permissive limit, zero means no limit.
+func maxQueryLimit(a, b int) int {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	if a > b {
+		return a
+	}
+	return b
+}
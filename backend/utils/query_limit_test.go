@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestGetEffectiveQueryLimit(t *testing.T) {
+	policy := &api.QueryLimitPolicy{
+		RuleList: []*api.QueryLimitRule{
+			{Role: api.Owner, MaxExecutionSeconds: 0, MaxRowCount: 100000},
+			{Role: api.Developer, MaxExecutionSeconds: 60, MaxRowCount: 10000},
+			{Role: "contractor", MaxExecutionSeconds: 10, MaxRowCount: 500},
+		},
+	}
+
+	tests := []struct {
+		projectRoles map[api.Role]bool
+		want         *QueryLimit
+	}{
+		{
+			projectRoles: map[api.Role]bool{"contractor": true},
+			want:         &QueryLimit{MaxExecutionSeconds: 10, MaxRowCount: 500},
+		},
+		{
+			projectRoles: map[api.Role]bool{"contractor": true, api.Developer: true},
+			want:         &QueryLimit{MaxExecutionSeconds: 60, MaxRowCount: 10000},
+		},
+		{
+			projectRoles: map[api.Role]bool{api.Owner: true, api.Developer: true},
+			want:         &QueryLimit{MaxExecutionSeconds: 0, MaxRowCount: 100000},
+		},
+		{
+			projectRoles: map[api.Role]bool{"releaser": true},
+			want:         &QueryLimit{},
+		},
+		{
+			projectRoles: nil,
+			want:         &QueryLimit{MaxExecutionSeconds: 10, MaxRowCount: 500},
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, GetEffectiveQueryLimit(policy, test.projectRoles), test.projectRoles)
+	}
+
+	limit := &QueryLimit{MaxRowCount: 500}
+	require.Equal(t, 500, limit.ApplyRowLimit(0))
+	require.Equal(t, 100, limit.ApplyRowLimit(100))
+	require.Equal(t, 500, limit.ApplyRowLimit(1000))
+	require.Equal(t, 1000, (&QueryLimit{}).ApplyRowLimit(1000))
+}
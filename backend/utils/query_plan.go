@@ -0,0 +1,327 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// GetExplainStatement returns the statement explaining the statement in the JSON format.
+func GetExplainStatement(engine db.Type, statement string, analyze bool) (string, error) {
+	statement = strings.TrimRight(strings.TrimSpace(statement), "; \t\n")
+	switch engine {
+	case db.Postgres:
+		if analyze {
+			return fmt.Sprintf("EXPLAIN (ANALYZE, FORMAT JSON) %s", statement), nil
+		}
+		return fmt.Sprintf("EXPLAIN (FORMAT JSON) %s", statement), nil
+	case db.MySQL, db.MariaDB:
+		if analyze {
+			return "", errors.Errorf("explain with analyze is not supported for engine %q", engine)
+		}
+		return fmt.Sprintf("EXPLAIN FORMAT=JSON %s", statement), nil
+	default:
+		return "", errors.Errorf("explain is not supported for engine %q", engine)
+	}
+}
+
+// ParseQueryPlan normalizes the JSON plan returned by the EXPLAIN statement into the plan tree.
+func ParseQueryPlan(engine db.Type, raw string, analyzed bool) (*api.QueryPlan, error) {
+	plan := &api.QueryPlan{
+		Analyzed: analyzed,
+		Raw:      raw,
+	}
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	switch engine {
+	case db.Postgres:
+		var list []map[string]any
+		if err := decoder.Decode(&list); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal the Postgres plan")
+		}
+		if len(list) == 0 {
+			return nil, errors.New("the Postgres plan is empty")
+		}
+		root, ok := list[0]["Plan"].(map[string]any)
+		if !ok {
+			return nil, errors.New("the Postgres plan has no root node")
+		}
+		plan.Root = parsePostgresPlanNode(root)
+		if v, ok := getPlanNumber(list[0], "Planning Time"); ok {
+			plan.PlanningTimeMs = &v
+		}
+		if v, ok := getPlanNumber(list[0], "Execution Time"); ok {
+			plan.ExecutionTimeMs = &v
+		}
+	case db.MySQL, db.MariaDB:
+		var object map[string]any
+		if err := decoder.Decode(&object); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal the MySQL plan")
+		}
+		queryBlock, ok := object["query_block"].(map[string]any)
+		if !ok {
+			return nil, errors.New("the MySQL plan has no query block")
+		}
+		plan.Root = parseMySQLQueryBlock(queryBlock)
+	default:
+		return nil, errors.Errorf("explain is not supported for engine %q", engine)
+	}
+	return plan, nil
+}
+
+// postgresPlanDetailKeys are the node properties shown as the detail of the node.
+var postgresPlanDetailKeys = []string{"Join Type", "Hash Cond", "Merge Cond", "Index Cond", "Recheck Cond", "Filter", "Sort Key", "Group Key"}
+
+func parsePostgresPlanNode(object map[string]any) *api.QueryPlanNode {
+	node := &api.QueryPlanNode{
+		Operation: getPlanString(object, "Node Type"),
+		Index:     getPlanString(object, "Index Name"),
+	}
+	if relation := getPlanString(object, "Relation Name"); relation != "" {
+		if schema := getPlanString(object, "Schema"); schema != "" {
+			relation = schema + "." + relation
+		}
+		node.Relation = relation
+	} else if cte := getPlanString(object, "CTE Name"); cte != "" {
+		node.Relation = cte
+	} else {
+		node.Relation = getPlanString(object, "Function Name")
+	}
+	var details []string
+	for _, key := range postgresPlanDetailKeys {
+		if value := getPlanString(object, key); value != "" {
+			details = append(details, fmt.Sprintf("%s: %s", key, value))
+		}
+	}
+	node.Detail = strings.Join(details, "; ")
+	node.StartupCost, _ = getPlanNumber(object, "Startup Cost")
+	node.TotalCost, _ = getPlanNumber(object, "Total Cost")
+	node.EstimatedRows, _ = getPlanNumber(object, "Plan Rows")
+	if loops, ok := getPlanNumber(object, "Actual Loops"); ok {
+		// Postgres reports the average rows and time per loop.
+		rows, _ := getPlanNumber(object, "Actual Rows")
+		rows *= loops
+		duration, _ := getPlanNumber(object, "Actual Total Time")
+		duration *= loops
+		node.Loops = &loops
+		node.ActualRows = &rows
+		node.ActualTimeMs = &duration
+	}
+	if children, ok := object["Plans"].([]any); ok {
+		for _, child := range children {
+			if child, ok := child.(map[string]any); ok {
+				node.Children = append(node.Children, parsePostgresPlanNode(child))
+			}
+		}
+	}
+	return node
+}
+
+// mysqlPlanOperationKeys are the keys of the MySQL plan nested in the query blocks and the operations in order.
+var mysqlPlanOperationKeys = []string{"union_result", "windowing", "duplicates_removal", "grouping_operation", "ordering_operation", "nested_loop", "table"}
+
+// mysqlPlanSubqueryKeys are the keys of the subquery lists of the MySQL plan.
+var mysqlPlanSubqueryKeys = []string{"select_list_subqueries", "attached_subqueries", "optimized_away_subqueries", "order_by_subqueries", "group_by_subqueries", "having_subqueries"}
+
+var mysqlAccessTypeOperations = map[string]string{
+	"ALL":    "Full Table Scan",
+	"index":  "Full Index Scan",
+	"range":  "Index Range Scan",
+	"ref":    "Non-Unique Key Lookup",
+	"eq_ref": "Unique Key Lookup",
+	"const":  "Constant Lookup",
+	"system": "System Table Lookup",
+}
+
+func parseMySQLQueryBlock(object map[string]any) *api.QueryPlanNode {
+	node := &api.QueryPlanNode{
+		Operation: "Query Block",
+		Detail:    getPlanString(object, "message"),
+	}
+	if id := getPlanString(object, "select_id"); id != "" {
+		node.Operation = fmt.Sprintf("Query Block #%s", id)
+	}
+	node.Children = parseMySQLPlanChildren(object)
+	if costInfo, ok := object["cost_info"].(map[string]any); ok {
+		node.TotalCost, _ = getPlanNumber(costInfo, "query_cost")
+	}
+	fillMySQLPlanNodeFromChildren(node)
+	return node
+}
+
+func parseMySQLPlanChildren(object map[string]any) []*api.QueryPlanNode {
+	var children []*api.QueryPlanNode
+	for _, key := range mysqlPlanOperationKeys {
+		value, ok := object[key]
+		if !ok {
+			continue
+		}
+		switch key {
+		case "table":
+			if table, ok := value.(map[string]any); ok {
+				children = append(children, parseMySQLTable(table))
+			}
+		case "nested_loop":
+			node := &api.QueryPlanNode{Operation: "Nested Loop"}
+			if list, ok := value.([]any); ok {
+				for _, item := range list {
+					if item, ok := item.(map[string]any); ok {
+						node.Children = append(node.Children, parseMySQLPlanChildren(item)...)
+					}
+				}
+			}
+			fillMySQLPlanNodeFromChildren(node)
+			children = append(children, node)
+		case "union_result":
+			operation, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			node := &api.QueryPlanNode{Operation: "Union"}
+			if getPlanString(operation, "using_temporary_table") == "true" {
+				node.Detail = "Using temporary table"
+			}
+			if list, ok := operation["query_specifications"].([]any); ok {
+				for _, item := range list {
+					if item, ok := item.(map[string]any); ok {
+						if queryBlock, ok := item["query_block"].(map[string]any); ok {
+							node.Children = append(node.Children, parseMySQLQueryBlock(queryBlock))
+						}
+					}
+				}
+			}
+			children = append(children, node)
+		default:
+			operation, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			node := parseMySQLOperation(key, operation)
+			children = append(children, node)
+		}
+	}
+	for _, key := range mysqlPlanSubqueryKeys {
+		list, ok := object[key].([]any)
+		if !ok {
+			continue
+		}
+		for _, item := range list {
+			if item, ok := item.(map[string]any); ok {
+				if queryBlock, ok := item["query_block"].(map[string]any); ok {
+					children = append(children, parseMySQLQueryBlock(queryBlock))
+				}
+			}
+		}
+	}
+	return children
+}
+
+func parseMySQLOperation(key string, object map[string]any) *api.QueryPlanNode {
+	node := &api.QueryPlanNode{}
+	switch key {
+	case "ordering_operation":
+		node.Operation = "Order"
+	case "grouping_operation":
+		node.Operation = "Group"
+	case "duplicates_removal":
+		node.Operation = "Distinct"
+	case "windowing":
+		node.Operation = "Window"
+	}
+	var details []string
+	if getPlanString(object, "using_filesort") == "true" {
+		details = append(details, "Using filesort")
+	}
+	if getPlanString(object, "using_temporary_table") == "true" {
+		details = append(details, "Using temporary table")
+	}
+	node.Detail = strings.Join(details, "; ")
+	node.Children = parseMySQLPlanChildren(object)
+	fillMySQLPlanNodeFromChildren(node)
+	return node
+}
+
+func parseMySQLTable(object map[string]any) *api.QueryPlanNode {
+	accessType := getPlanString(object, "access_type")
+	operation, ok := mysqlAccessTypeOperations[accessType]
+	if !ok {
+		operation = "Table Access"
+		if accessType != "" {
+			operation = fmt.Sprintf("Table Access (%s)", accessType)
+		}
+	}
+	node := &api.QueryPlanNode{
+		Operation: operation,
+		Relation:  getPlanString(object, "table_name"),
+		Index:     getPlanString(object, "key"),
+		Detail:    getPlanString(object, "attached_condition"),
+	}
+	node.EstimatedRows, _ = getPlanNumber(object, "rows_produced_per_join")
+	if costInfo, ok := object["cost_info"].(map[string]any); ok {
+		// The prefix cost is the cost of joining the table and the tables before it.
+		node.TotalCost, _ = getPlanNumber(costInfo, "prefix_cost")
+	}
+	if subquery, ok := object["materialized_from_subquery"].(map[string]any); ok {
+		if queryBlock, ok := subquery["query_block"].(map[string]any); ok {
+			node.Children = append(node.Children, parseMySQLQueryBlock(queryBlock))
+		}
+	}
+	node.Children = append(node.Children, parseMySQLPlanChildren(map[string]any{
+		"attached_subqueries": object["attached_subqueries"],
+	})...)
+	return node
+}
+
+// fillMySQLPlanNodeFromChildren fills the cost and rows of the operations that MySQL doesn't report from the last
+// child, since the MySQL costs accumulate along the join order.
+func fillMySQLPlanNodeFromChildren(node *api.QueryPlanNode) {
+	if len(node.Children) == 0 {
+		return
+	}
+	last := node.Children[len(node.Children)-1]
+	if node.TotalCost == 0 {
+		node.TotalCost = last.TotalCost
+	}
+	if node.EstimatedRows == 0 {
+		node.EstimatedRows = last.EstimatedRows
+	}
+}
+
+func getPlanString(object map[string]any, key string) string {
+	switch v := object[key].(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		var list []string
+		for _, item := range v {
+			list = append(list, fmt.Sprint(item))
+		}
+		return strings.Join(list, ", ")
+	default:
+		return ""
+	}
+}
+
+func getPlanNumber(object map[string]any, key string) (float64, bool) {
+	switch v := object[key].(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		// MySQL reports the costs as strings.
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
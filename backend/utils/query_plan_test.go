@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestGetExplainStatement(t *testing.T) {
+	statement, err := GetExplainStatement(db.Postgres, "SELECT * FROM t;\n", true)
+	require.NoError(t, err)
+	require.Equal(t, "EXPLAIN (ANALYZE, FORMAT JSON) SELECT * FROM t", statement)
+
+	statement, err = GetExplainStatement(db.MySQL, "SELECT * FROM t", false)
+	require.NoError(t, err)
+	require.Equal(t, "EXPLAIN FORMAT=JSON SELECT * FROM t", statement)
+
+	_, err = GetExplainStatement(db.MySQL, "SELECT * FROM t", true)
+	require.Error(t, err)
+	_, err = GetExplainStatement(db.Oracle, "SELECT * FROM t", false)
+	require.Error(t, err)
+}
+
+func TestParsePostgresQueryPlan(t *testing.T) {
+	raw := `[
+  {
+    "Plan": {
+      "Node Type": "Hash Join",
+      "Join Type": "Inner",
+      "Startup Cost": 1.09,
+      "Total Cost": 2.26,
+      "Plan Rows": 4,
+      "Actual Total Time": 0.05,
+      "Actual Rows": 4,
+      "Actual Loops": 1,
+      "Hash Cond": "(o.user_id = u.id)",
+      "Plans": [
+        {
+          "Node Type": "Seq Scan",
+          "Relation Name": "orders",
+          "Schema": "public",
+          "Startup Cost": 0.00,
+          "Total Cost": 1.04,
+          "Plan Rows": 4,
+          "Actual Total Time": 0.01,
+          "Actual Rows": 4,
+          "Actual Loops": 1
+        },
+        {
+          "Node Type": "Index Scan",
+          "Relation Name": "users",
+          "Schema": "public",
+          "Index Name": "users_pkey",
+          "Startup Cost": 0.15,
+          "Total Cost": 1.05,
+          "Plan Rows": 1,
+          "Actual Total Time": 0.002,
+          "Actual Rows": 1,
+          "Actual Loops": 4,
+          "Filter": "(active)"
+        }
+      ]
+    },
+    "Planning Time": 0.2,
+    "Execution Time": 0.1
+  }
+]`
+	plan, err := ParseQueryPlan(db.Postgres, raw, true)
+	require.NoError(t, err)
+	require.True(t, plan.Analyzed)
+	require.Equal(t, 0.2, *plan.PlanningTimeMs)
+	require.Equal(t, 0.1, *plan.ExecutionTimeMs)
+
+	root := plan.Root
+	require.Equal(t, "Hash Join", root.Operation)
+	require.Equal(t, "Join Type: Inner; Hash Cond: (o.user_id = u.id)", root.Detail)
+	require.Equal(t, 1.09, root.StartupCost)
+	require.Equal(t, 2.26, root.TotalCost)
+	require.Equal(t, float64(4), root.EstimatedRows)
+	require.Len(t, root.Children, 2)
+
+	require.Equal(t, "public.orders", root.Children[0].Relation)
+	index := root.Children[1]
+	require.Equal(t, "Index Scan", index.Operation)
+	require.Equal(t, "users_pkey", index.Index)
+	require.Equal(t, "Filter: (active)", index.Detail)
+	// The rows and time are the totals of the loops.
+	require.Equal(t, float64(4), *index.ActualRows)
+	require.Equal(t, 0.008, *index.ActualTimeMs)
+	require.Equal(t, float64(4), *index.Loops)
+
+	plan, err = ParseQueryPlan(db.Postgres, `[{"Plan": {"Node Type": "Result", "Startup Cost": 0.00, "Total Cost": 0.01, "Plan Rows": 1}}]`, false)
+	require.NoError(t, err)
+	require.Equal(t, &api.QueryPlanNode{Operation: "Result", TotalCost: 0.01, EstimatedRows: 1}, plan.Root)
+	require.Nil(t, plan.ExecutionTimeMs)
+
+	_, err = ParseQueryPlan(db.Postgres, `[]`, false)
+	require.Error(t, err)
+}
+
+func TestParseMySQLQueryPlan(t *testing.T) {
+	raw := `{
+  "query_block": {
+    "select_id": 1,
+    "cost_info": {"query_cost": "3.20"},
+    "ordering_operation": {
+      "using_filesort": true,
+      "nested_loop": [
+        {
+          "table": {
+            "table_name": "o",
+            "access_type": "ALL",
+            "rows_examined_per_scan": 4,
+            "rows_produced_per_join": 4,
+            "cost_info": {"read_cost": "0.25", "eval_cost": "0.40", "prefix_cost": "0.65"},
+            "attached_condition": "(o.user_id is not null)"
+          }
+        },
+        {
+          "table": {
+            "table_name": "u",
+            "access_type": "eq_ref",
+            "key": "PRIMARY",
+            "rows_examined_per_scan": 1,
+            "rows_produced_per_join": 4,
+            "cost_info": {"read_cost": "1.00", "eval_cost": "0.40", "prefix_cost": "2.05"},
+            "attached_subqueries": [
+              {
+                "dependent": true,
+                "query_block": {
+                  "select_id": 2,
+                  "message": "No tables used"
+                }
+              }
+            ]
+          }
+        }
+      ]
+    }
+  }
+}`
+	plan, err := ParseQueryPlan(db.MySQL, raw, false)
+	require.NoError(t, err)
+	require.False(t, plan.Analyzed)
+
+	root := plan.Root
+	require.Equal(t, "Query Block #1", root.Operation)
+	require.Equal(t, 3.2, root.TotalCost)
+	require.Equal(t, float64(4), root.EstimatedRows)
+	require.Len(t, root.Children, 1)
+
+	order := root.Children[0]
+	require.Equal(t, "Order", order.Operation)
+	require.Equal(t, "Using filesort", order.Detail)
+	require.Len(t, order.Children, 1)
+
+	loop := order.Children[0]
+	require.Equal(t, "Nested Loop", loop.Operation)
+	require.Equal(t, 2.05, loop.TotalCost)
+	require.Len(t, loop.Children, 2)
+	require.Equal(t, &api.QueryPlanNode{
+		Operation:     "Full Table Scan",
+		Relation:      "o",
+		Detail:        "(o.user_id is not null)",
+		TotalCost:     0.65,
+		EstimatedRows: 4,
+	}, loop.Children[0])
+
+	lookup := loop.Children[1]
+	require.Equal(t, "Unique Key Lookup", lookup.Operation)
+	require.Equal(t, "PRIMARY", lookup.Index)
+	require.Len(t, lookup.Children, 1)
+	require.Equal(t, "Query Block #2", lookup.Children[0].Operation)
+	require.Equal(t, "No tables used", lookup.Children[0].Detail)
+
+	_, err = ParseQueryPlan(db.MySQL, `{}`, false)
+	require.Error(t, err)
+}
@@ -0,0 +1,265 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// queryResultChartTimeLayouts are the layouts of the time values in the query results of the engines.
+var queryResultChartTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// QueryResultChart aggregates the rows of a query result into the chart series, the rows are added one by one so the
+// result isn't held in memory.
+type QueryResultChart struct {
+	request       *api.SQLResultChart
+	categoryIndex int
+	seriesIndex   int
+	valueIndex    int
+	categoryList  []string
+	categorySeen  map[string]bool
+	seriesList    []string
+	accumulators  map[string]map[string]*chartAccumulator
+	rowCount      int
+	defaultSeries string
+}
+
+type chartAccumulator struct {
+	count int
+	sum   float64
+	min   float64
+	max   float64
+}
+
+// NewQueryResultChart validates the chart request against the columns of the result. The sensitive columns are
+// masked, so they can only be counted.
+func NewQueryResultChart(columnNames []string, sensitiveList []bool, request *api.SQLResultChart) (*QueryResultChart, error) {
+	columnIndex := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		for i, columnName := range columnNames {
+			if columnName == name {
+				return i, nil
+			}
+		}
+		return -1, errors.Errorf("column %q not found in the result", name)
+	}
+	chart := &QueryResultChart{
+		request:      request,
+		categorySeen: make(map[string]bool),
+		accumulators: make(map[string]map[string]*chartAccumulator),
+	}
+	var err error
+	if request.CategoryColumn == "" {
+		return nil, errors.Errorf("category column is required")
+	}
+	if chart.categoryIndex, err = columnIndex(request.CategoryColumn); err != nil {
+		return nil, err
+	}
+	if chart.seriesIndex, err = columnIndex(request.SeriesColumn); err != nil {
+		return nil, err
+	}
+	if chart.valueIndex, err = columnIndex(request.ValueColumn); err != nil {
+		return nil, err
+	}
+
+	switch request.TimeBucket {
+	case api.SQLResultChartTimeBucketNone, api.SQLResultChartTimeBucketMinute, api.SQLResultChartTimeBucketHour, api.SQLResultChartTimeBucketDay, api.SQLResultChartTimeBucketWeek, api.SQLResultChartTimeBucketMonth, api.SQLResultChartTimeBucketYear:
+	default:
+		return nil, errors.Errorf("invalid time bucket %q", request.TimeBucket)
+	}
+	switch request.Aggregation {
+	case api.SQLResultChartAggregationCount:
+		chart.defaultSeries = string(request.Aggregation)
+	case api.SQLResultChartAggregationSum, api.SQLResultChartAggregationAvg, api.SQLResultChartAggregationMin, api.SQLResultChartAggregationMax:
+		if chart.valueIndex < 0 {
+			return nil, errors.Errorf("value column is required by the aggregation %q", request.Aggregation)
+		}
+		if chart.valueIndex < len(sensitiveList) && sensitiveList[chart.valueIndex] {
+			return nil, errors.Errorf("the sensitive column %q can only be counted", request.ValueColumn)
+		}
+		chart.defaultSeries = fmt.Sprintf("%s(%s)", request.Aggregation, request.ValueColumn)
+	default:
+		return nil, errors.Errorf("invalid aggregation %q", request.Aggregation)
+	}
+	return chart, nil
+}
+
+// Add aggregates a row, the rows with the NULL time are skipped with the time bucket.
+func (c *QueryResultChart) Add(row []any) error {
+	c.rowCount++
+	category, ok, err := c.getCategory(row[c.categoryIndex])
+	if err != nil || !ok {
+		return err
+	}
+	if !c.categorySeen[category] {
+		if len(c.categoryList) >= api.MaxSQLResultChartCategoryCount {
+			return errors.Errorf("the chart has more than %d categories, please use a time bucket or narrow down the result", api.MaxSQLResultChartCategoryCount)
+		}
+		c.categorySeen[category] = true
+		c.categoryList = append(c.categoryList, category)
+	}
+	series := c.defaultSeries
+	if c.seriesIndex >= 0 {
+		series = formatChartValue(row[c.seriesIndex])
+	}
+	var value float64
+	if c.request.Aggregation != api.SQLResultChartAggregationCount {
+		v, ok, err := parseChartNumber(row[c.valueIndex])
+		if err != nil {
+			return errors.Wrapf(err, "invalid value of column %q", c.request.ValueColumn)
+		}
+		// The NULL values are ignored by the aggregations but COUNT.
+		if !ok {
+			return nil
+		}
+		value = v
+	}
+	accumulators, ok := c.accumulators[series]
+	if !ok {
+		if len(c.seriesList) >= api.MaxSQLResultChartSeriesCount {
+			return errors.Errorf("the chart has more than %d series, please narrow down the result", api.MaxSQLResultChartSeriesCount)
+		}
+		accumulators = make(map[string]*chartAccumulator)
+		c.accumulators[series] = accumulators
+		c.seriesList = append(c.seriesList, series)
+	}
+	accumulator, ok := accumulators[category]
+	if !ok {
+		accumulator = &chartAccumulator{min: value, max: value}
+		accumulators[category] = accumulator
+	}
+	accumulator.count++
+	accumulator.sum += value
+	if value < accumulator.min {
+		accumulator.min = value
+	}
+	if value > accumulator.max {
+		accumulator.max = value
+	}
+	return nil
+}
+
+// Result returns the chart series of the rows added.
+func (c *QueryResultChart) Result() *api.SQLResultChartResult {
+	categoryList := append([]string{}, c.categoryList...)
+	// The time buckets are formatted to sort in the time order.
+	if c.request.TimeBucket != api.SQLResultChartTimeBucketNone {
+		sort.Strings(categoryList)
+	}
+	result := &api.SQLResultChartResult{
+		CategoryList: categoryList,
+		SeriesList:   []*api.SQLResultChartSeries{},
+		RowCount:     c.rowCount,
+	}
+	for _, series := range c.seriesList {
+		chartSeries := &api.SQLResultChartSeries{Name: series}
+		for _, category := range categoryList {
+			accumulator, ok := c.accumulators[series][category]
+			if !ok {
+				chartSeries.Data = append(chartSeries.Data, nil)
+				continue
+			}
+			var value float64
+			switch c.request.Aggregation {
+			case api.SQLResultChartAggregationCount:
+				value = float64(accumulator.count)
+			case api.SQLResultChartAggregationSum:
+				value = accumulator.sum
+			case api.SQLResultChartAggregationAvg:
+				value = accumulator.sum / float64(accumulator.count)
+			case api.SQLResultChartAggregationMin:
+				value = accumulator.min
+			case api.SQLResultChartAggregationMax:
+				value = accumulator.max
+			}
+			chartSeries.Data = append(chartSeries.Data, &value)
+		}
+		result.SeriesList = append(result.SeriesList, chartSeries)
+	}
+	return result
+}
+
+func (c *QueryResultChart) getCategory(v any) (string, bool, error) {
+	if c.request.TimeBucket == api.SQLResultChartTimeBucketNone {
+		return formatChartValue(v), true, nil
+	}
+	if v == nil {
+		return "", false, nil
+	}
+	s := formatChartValue(v)
+	var t time.Time
+	var err error
+	for _, layout := range queryResultChartTimeLayouts {
+		if t, err = time.Parse(layout, s); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return "", false, errors.Errorf("invalid time %q of column %q", s, c.request.CategoryColumn)
+	}
+	switch c.request.TimeBucket {
+	case api.SQLResultChartTimeBucketMinute:
+		return t.Format("2006-01-02 15:04"), true, nil
+	case api.SQLResultChartTimeBucketHour:
+		return t.Format("2006-01-02 15:00"), true, nil
+	case api.SQLResultChartTimeBucketDay:
+		return t.Format("2006-01-02"), true, nil
+	case api.SQLResultChartTimeBucketWeek:
+		return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7)).Format("2006-01-02"), true, nil
+	case api.SQLResultChartTimeBucketMonth:
+		return t.Format("2006-01"), true, nil
+	default:
+		return t.Format("2006"), true, nil
+	}
+}
+
+func formatChartValue(v any) string {
+	if v == nil {
+		return "NULL"
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// parseChartNumber parses the number value, the decimals may be in the strings. It returns false for NULL.
+func parseChartNumber(v any) (float64, bool, error) {
+	switch v := v.(type) {
+	case nil:
+		return 0, false, nil
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil, err
+	case float64:
+		return v, true, nil
+	case int:
+		return float64(v), true, nil
+	case int64:
+		return float64(v), true, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, false, errors.Errorf("%q is not a number", v)
+		}
+		return f, true, nil
+	}
+	return 0, false, errors.Errorf("%v is not a number", v)
+}
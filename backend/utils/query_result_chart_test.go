@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestQueryResultChart(t *testing.T) {
+	columnNames := []string{"created_at", "region", "amount", "email"}
+	sensitiveList := []bool{false, false, false, true}
+	rows := [][]any{
+		{"2023-10-02 08:00:00", "us", json.Number("10.5"), "******"},
+		{"2023-10-03T09:30:00Z", "eu", "4", "******"},
+		{"2023-10-09 10:00:00", "us", json.Number("1"), "******"},
+		{"2023-10-01", "us", nil, "******"},
+		{nil, "eu", json.Number("100"), "******"},
+	}
+	run := func(request *api.SQLResultChart) *api.SQLResultChartResult {
+		chart, err := NewQueryResultChart(columnNames, sensitiveList, request)
+		require.NoError(t, err)
+		for _, row := range rows {
+			require.NoError(t, chart.Add(row))
+		}
+		return chart.Result()
+	}
+	number := func(v float64) *float64 {
+		return &v
+	}
+
+	// The weeks start on Monday, and the NULL time is skipped.
+	result := run(&api.SQLResultChart{
+		CategoryColumn: "created_at",
+		TimeBucket:     api.SQLResultChartTimeBucketWeek,
+		SeriesColumn:   "region",
+		ValueColumn:    "amount",
+		Aggregation:    api.SQLResultChartAggregationSum,
+	})
+	require.Equal(t, &api.SQLResultChartResult{
+		CategoryList: []string{"2023-09-25", "2023-10-02", "2023-10-09"},
+		SeriesList: []*api.SQLResultChartSeries{
+			{Name: "us", Data: []*float64{nil, number(10.5), number(1)}},
+			{Name: "eu", Data: []*float64{nil, number(4), nil}},
+		},
+		RowCount: 5,
+	}, result)
+
+	result = run(&api.SQLResultChart{
+		CategoryColumn: "region",
+		Aggregation:    api.SQLResultChartAggregationCount,
+	})
+	require.Equal(t, []string{"us", "eu"}, result.CategoryList)
+	require.Equal(t, []*api.SQLResultChartSeries{{Name: "COUNT", Data: []*float64{number(3), number(2)}}}, result.SeriesList)
+
+	result = run(&api.SQLResultChart{
+		CategoryColumn: "region",
+		ValueColumn:    "amount",
+		Aggregation:    api.SQLResultChartAggregationAvg,
+	})
+	require.Equal(t, []*api.SQLResultChartSeries{{Name: "AVG(amount)", Data: []*float64{number(5.75), number(52)}}}, result.SeriesList)
+
+	for _, request := range []*api.SQLResultChart{
+		{Aggregation: api.SQLResultChartAggregationCount},
+		{CategoryColumn: "missing", Aggregation: api.SQLResultChartAggregationCount},
+		{CategoryColumn: "region", Aggregation: api.SQLResultChartAggregationSum},
+		{CategoryColumn: "region", ValueColumn: "email", Aggregation: api.SQLResultChartAggregationMax},
+		{CategoryColumn: "region", Aggregation: "MEDIAN"},
+		{CategoryColumn: "region", TimeBucket: "QUARTER", Aggregation: api.SQLResultChartAggregationCount},
+	} {
+		_, err := NewQueryResultChart(columnNames, sensitiveList, request)
+		require.Error(t, err, request)
+	}
+
+	chart, err := NewQueryResultChart(columnNames, sensitiveList, &api.SQLResultChart{
+		CategoryColumn: "region",
+		TimeBucket:     api.SQLResultChartTimeBucketDay,
+		Aggregation:    api.SQLResultChartAggregationCount,
+	})
+	require.NoError(t, err)
+	require.Error(t, chart.Add(rows[0]))
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// DiffQueryResult diffs the rows of the source and target results of the same statement. With the key columns, the
+// rows are matched by the keys, which must be unique in each result. Without them, the results are compared as the
+// multisets of the whole rows, so the duplicate rows are counted.
+// The values are compared by their text, so the results of the engines scanning the same value into different types,
+// such as 1 and "1", are the same.
+func DiffQueryResult(sourceColumnNames, targetColumnNames []string, keyColumnList []string, sourceRows, targetRows [][]any) (*api.SQLResultDiffResult, error) {
+	if strings.Join(sourceColumnNames, "\x00") != strings.Join(targetColumnNames, "\x00") {
+		return nil, errors.Errorf("the source columns (%s) are different from the target columns (%s)", strings.Join(sourceColumnNames, ", "), strings.Join(targetColumnNames, ", "))
+	}
+	var keyIndexes []int
+	for _, keyColumn := range keyColumnList {
+		index := -1
+		for i, columnName := range sourceColumnNames {
+			if columnName == keyColumn {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, errors.Errorf("key column %q not found in the result", keyColumn)
+		}
+		keyIndexes = append(keyIndexes, index)
+	}
+
+	result := &api.SQLResultDiffResult{
+		ColumnNames:    sourceColumnNames,
+		SourceRowCount: len(sourceRows),
+		TargetRowCount: len(targetRows),
+		AddedRows:      [][]any{},
+		RemovedRows:    [][]any{},
+		ChangedRows:    []*api.SQLResultDiffChangedRow{},
+	}
+	if len(keyIndexes) == 0 {
+		diffRowMultisets(result, sourceRows, targetRows)
+		return result, nil
+	}
+
+	sourceRowMap := make(map[string][]any)
+	for _, row := range sourceRows {
+		key := getRowKey(row, keyIndexes)
+		if _, ok := sourceRowMap[key]; ok {
+			return nil, errors.Errorf("key (%s) isn't unique in the source result", strings.Join(keyColumnList, ", "))
+		}
+		sourceRowMap[key] = row
+	}
+	targetKeys := make(map[string]bool)
+	for _, row := range targetRows {
+		key := getRowKey(row, keyIndexes)
+		if targetKeys[key] {
+			return nil, errors.Errorf("key (%s) isn't unique in the target result", strings.Join(keyColumnList, ", "))
+		}
+		targetKeys[key] = true
+
+		sourceRow, ok := sourceRowMap[key]
+		if !ok {
+			result.AddedRows = append(result.AddedRows, row)
+			continue
+		}
+		var changedColumnList []string
+		for i, columnName := range sourceColumnNames {
+			if formatDiffValue(sourceRow[i]) != formatDiffValue(row[i]) {
+				changedColumnList = append(changedColumnList, columnName)
+			}
+		}
+		if len(changedColumnList) == 0 {
+			result.SameRowCount++
+			continue
+		}
+		result.ChangedRows = append(result.ChangedRows, &api.SQLResultDiffChangedRow{
+			Source:            sourceRow,
+			Target:            row,
+			ChangedColumnList: changedColumnList,
+		})
+	}
+	for _, row := range sourceRows {
+		if !targetKeys[getRowKey(row, keyIndexes)] {
+			result.RemovedRows = append(result.RemovedRows, row)
+		}
+	}
+	return result, nil
+}
+
+func diffRowMultisets(result *api.SQLResultDiffResult, sourceRows, targetRows [][]any) {
+	var allIndexes []int
+	if len(sourceRows) > 0 {
+		for i := range sourceRows[0] {
+			allIndexes = append(allIndexes, i)
+		}
+	}
+	// The count of each row in the source result not matched by the target rows yet.
+	unmatched := make(map[string]int)
+	for _, row := range sourceRows {
+		unmatched[getRowKey(row, allIndexes)]++
+	}
+	for _, row := range targetRows {
+		key := getRowKey(row, allIndexes)
+		if unmatched[key] > 0 {
+			unmatched[key]--
+			result.SameRowCount++
+			continue
+		}
+		result.AddedRows = append(result.AddedRows, row)
+	}
+	// The source rows beyond the matched ones are removed, we keep the last ones in the order of the result.
+	for i := len(sourceRows) - 1; i >= 0; i-- {
+		key := getRowKey(sourceRows[i], allIndexes)
+		if unmatched[key] > 0 {
+			unmatched[key]--
+			result.RemovedRows = append(result.RemovedRows, sourceRows[i])
+		}
+	}
+	for i, j := 0, len(result.RemovedRows)-1; i < j; i, j = i+1, j-1 {
+		result.RemovedRows[i], result.RemovedRows[j] = result.RemovedRows[j], result.RemovedRows[i]
+	}
+}
+
+func getRowKey(row []any, indexes []int) string {
+	var parts []string
+	for _, i := range indexes {
+		parts = append(parts, formatDiffValue(row[i]))
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// formatDiffValue formats the value into the text compared, NULL is different from any text.
+func formatDiffValue(v any) string {
+	if v == nil {
+		return "\x01"
+	}
+	return "\x02" + fmt.Sprint(v)
+}
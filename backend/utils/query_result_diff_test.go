@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestDiffQueryResult(t *testing.T) {
+	columnNames := []string{"id", "name"}
+	sourceRows := [][]any{
+		{int64(1), "alice"},
+		{int64(2), "bob"},
+		{int64(3), nil},
+		{int64(4), "dave"},
+	}
+	targetRows := [][]any{
+		// The values are compared by their text.
+		{"1", "alice"},
+		{int64(2), "bobby"},
+		{int64(3), "NULL"},
+		{int64(5), "eve"},
+	}
+
+	result, err := DiffQueryResult(columnNames, columnNames, []string{"id"}, sourceRows, targetRows)
+	require.NoError(t, err)
+	require.Equal(t, &api.SQLResultDiffResult{
+		ColumnNames:    columnNames,
+		SourceRowCount: 4,
+		TargetRowCount: 4,
+		SameRowCount:   1,
+		AddedRows:      [][]any{{int64(5), "eve"}},
+		RemovedRows:    [][]any{{int64(4), "dave"}},
+		ChangedRows: []*api.SQLResultDiffChangedRow{
+			{Source: []any{int64(2), "bob"}, Target: []any{int64(2), "bobby"}, ChangedColumnList: []string{"name"}},
+			{Source: []any{int64(3), nil}, Target: []any{int64(3), "NULL"}, ChangedColumnList: []string{"name"}},
+		},
+	}, result)
+
+	// Without the key columns, the duplicate rows are counted.
+	result, err = DiffQueryResult(columnNames, columnNames, nil,
+		[][]any{{int64(1), "a"}, {int64(1), "a"}, {int64(2), "b"}},
+		[][]any{{int64(1), "a"}, {int64(3), "c"}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.SameRowCount)
+	require.Equal(t, [][]any{{int64(3), "c"}}, result.AddedRows)
+	require.Equal(t, [][]any{{int64(1), "a"}, {int64(2), "b"}}, result.RemovedRows)
+	require.Empty(t, result.ChangedRows)
+}
+
+func TestDiffQueryResultError(t *testing.T) {
+	columnNames := []string{"id", "name"}
+	_, err := DiffQueryResult(columnNames, []string{"id"}, nil, nil, nil)
+	require.Error(t, err)
+	_, err = DiffQueryResult(columnNames, columnNames, []string{"email"}, nil, nil)
+	require.Error(t, err)
+	_, err = DiffQueryResult(columnNames, columnNames, []string{"id"}, [][]any{{int64(1), "a"}, {int64(1), "b"}}, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,520 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	"github.com/apache/arrow/go/v10/parquet"
+	"github.com/apache/arrow/go/v10/parquet/compress"
+	"github.com/apache/arrow/go/v10/parquet/pqarrow"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// QueryResultExportFormat is the format of the exported query result.
+type QueryResultExportFormat string
+
+const (
+	// QueryResultExportFormatParquet is the Apache Parquet file.
+	QueryResultExportFormatParquet QueryResultExportFormat = "parquet"
+	// QueryResultExportFormatAvro is the Apache Avro object container file.
+	QueryResultExportFormatAvro QueryResultExportFormat = "avro"
+)
+
+// queryResultExportBatchSize is the number of the rows in a Parquet row group or an Avro block.
+// The rows are written batch by batch, so the writers don't hold the whole file in memory.
+const queryResultExportBatchSize = 10000
+
+// queryResultColumnType is the type of the exported column.
+type queryResultColumnType string
+
+const (
+	queryResultColumnLong    queryResultColumnType = "long"
+	queryResultColumnDouble  queryResultColumnType = "double"
+	queryResultColumnBoolean queryResultColumnType = "boolean"
+	queryResultColumnString  queryResultColumnType = "string"
+)
+
+// QueryResultExport is the query result to export.
+type QueryResultExport struct {
+	Engine          db.Type
+	ColumnNames     []string
+	ColumnTypeNames []string
+	// SensitiveList masks the columns, which are always exported as the strings.
+	SensitiveList []bool
+	// Rows are the values decoded from the query result JSON with json.Decoder.UseNumber.
+	Rows [][]any
+	// Watermark identifies the export in the file metadata, and optionally in an extra column, if it's set.
+	Watermark *QueryResultWatermark
+}
+
+// QueryResultWatermarkColumn is the name of the watermark column appended to the exported rows.
+const QueryResultWatermarkColumn = "_bb_watermark"
+
+// QueryResultWatermark identifies the export, so that a leaked file can be traced back to the requester.
+type QueryResultWatermark struct {
+	ExportID   string
+	Requester  string
+	ExportedTs int64
+	// Column appends the watermark column to the rows besides the file metadata.
+	Column bool
+}
+
+// metadata returns the key-value metadata of the watermark written to the file.
+func (w *QueryResultWatermark) metadata() ([]string, []string) {
+	return []string{"bytebase.export.id", "bytebase.export.requester", "bytebase.export.timestamp"},
+		[]string{w.ExportID, w.Requester, time.Unix(w.ExportedTs, 0).UTC().Format(time.RFC3339)}
+}
+
+// value returns the value of the watermark column.
+func (w *QueryResultWatermark) value() string {
+	return fmt.Sprintf("export %s by %s at %s", w.ExportID, w.Requester, time.Unix(w.ExportedTs, 0).UTC().Format(time.RFC3339))
+}
+
+var (
+	clickHouseTypeWrapperRegexp = regexp.MustCompile(`^(NULLABLE|LOWCARDINALITY)\((.*)\)$`)
+	avroNameInvalidCharRegexp   = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// getQueryResultColumnType maps the database type name of the column to the exported type. The DECIMAL and the
+// NUMERIC types are exported as the strings to keep the precision.
+func getQueryResultColumnType(engine db.Type, typeName string) queryResultColumnType {
+	typeName = strings.TrimSpace(strings.ToUpper(typeName))
+	switch engine {
+	case db.ClickHouse:
+		for {
+			matches := clickHouseTypeWrapperRegexp.FindStringSubmatch(typeName)
+			if matches == nil {
+				break
+			}
+			typeName = matches[2]
+		}
+		switch typeName {
+		case "INT8", "INT16", "INT32", "INT64", "UINT8", "UINT16", "UINT32":
+			return queryResultColumnLong
+		case "FLOAT32", "FLOAT64":
+			return queryResultColumnDouble
+		case "BOOL", "BOOLEAN":
+			return queryResultColumnBoolean
+		}
+		return queryResultColumnString
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		// The driver reports the unsigned types as "UNSIGNED BIGINT" and so on.
+		if typeName == "UNSIGNED BIGINT" {
+			return queryResultColumnString
+		}
+		typeName = strings.TrimPrefix(typeName, "UNSIGNED ")
+	case db.Oracle:
+		// NUMBER covers both the integers and the decimals.
+		switch typeName {
+		case "BINARY_FLOAT", "BINARY_DOUBLE":
+			return queryResultColumnDouble
+		}
+		return queryResultColumnString
+	}
+	switch typeName {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "INT2", "INT4", "INT8", "SERIAL", "SMALLSERIAL", "BIGSERIAL", "YEAR":
+		return queryResultColumnLong
+	case "FLOAT", "DOUBLE", "REAL", "FLOAT4", "FLOAT8", "DOUBLE PRECISION":
+		return queryResultColumnDouble
+	case "BOOL", "BOOLEAN":
+		return queryResultColumnBoolean
+	}
+	return queryResultColumnString
+}
+
+type queryResultColumn struct {
+	name string
+	typ  queryResultColumnType
+}
+
+func getQueryResultColumns(result *QueryResultExport) ([]*queryResultColumn, error) {
+	if len(result.ColumnTypeNames) != len(result.ColumnNames) {
+		return nil, errors.Errorf("expected %d column types, got %d", len(result.ColumnNames), len(result.ColumnTypeNames))
+	}
+	var columns []*queryResultColumn
+	names := make(map[string]bool)
+	for i, name := range result.ColumnNames {
+		// The duplicate column names such as "SELECT 1, 1" are renamed as they are not allowed in the schemas.
+		uniqueName := name
+		for suffix := 2; names[uniqueName]; suffix++ {
+			uniqueName = fmt.Sprintf("%s_%d", name, suffix)
+		}
+		names[uniqueName] = true
+		typ := getQueryResultColumnType(result.Engine, result.ColumnTypeNames[i])
+		if i < len(result.SensitiveList) && result.SensitiveList[i] {
+			typ = queryResultColumnString
+		}
+		columns = append(columns, &queryResultColumn{name: uniqueName, typ: typ})
+	}
+	return columns, nil
+}
+
+// CheckQueryResultExport checks the values can be converted to the exported types. The callers streaming the file
+// check the result first, so that the export only fails for the IO errors in the middle of the stream.
+func CheckQueryResultExport(result *QueryResultExport) error {
+	columns, err := getQueryResultColumns(result)
+	if err != nil {
+		return err
+	}
+	return checkQueryResultRows(columns, result.Rows)
+}
+
+// ExportQueryResult writes the query result to the writer in the format.
+func ExportQueryResult(w io.Writer, result *QueryResultExport, format QueryResultExportFormat) error {
+	columns, err := getQueryResultColumns(result)
+	if err != nil {
+		return err
+	}
+	if err := checkQueryResultRows(columns, result.Rows); err != nil {
+		return err
+	}
+	rows := result.Rows
+	if v := result.Watermark; v != nil && v.Column {
+		columns = append(columns, &queryResultColumn{name: QueryResultWatermarkColumn, typ: queryResultColumnString})
+		value := v.value()
+		rows = make([][]any, 0, len(result.Rows))
+		for _, row := range result.Rows {
+			watermarked := make([]any, 0, len(row)+1)
+			watermarked = append(watermarked, row...)
+			rows = append(rows, append(watermarked, value))
+		}
+	}
+	switch format {
+	case QueryResultExportFormatParquet:
+		return exportParquet(w, columns, rows, result.Watermark)
+	case QueryResultExportFormatAvro:
+		return exportAvro(w, columns, rows, result.Watermark)
+	default:
+		return errors.Errorf("unsupported query result export format %q", format)
+	}
+}
+
+func checkQueryResultRows(columns []*queryResultColumn, rows [][]any) error {
+	for i, row := range rows {
+		if err := checkQueryResultRow(columns, row); err != nil {
+			return errors.Wrapf(err, "row %d", i+1)
+		}
+	}
+	return nil
+}
+
+func checkQueryResultRow(columns []*queryResultColumn, row []any) error {
+	if len(row) != len(columns) {
+		return errors.Errorf("got %d values, expected %d", len(row), len(columns))
+	}
+	for j, column := range columns {
+		if row[j] == nil {
+			continue
+		}
+		var err error
+		switch column.typ {
+		case queryResultColumnLong:
+			_, err = convertToInt64(row[j])
+		case queryResultColumnDouble:
+			_, err = convertToFloat64(row[j])
+		case queryResultColumnBoolean:
+			_, err = convertToBool(row[j])
+		}
+		if err != nil {
+			return errors.Wrapf(err, "column %q", column.name)
+		}
+	}
+	return nil
+}
+
+func exportParquet(w io.Writer, columns []*queryResultColumn, rows [][]any, watermark *QueryResultWatermark) error {
+	var fields []arrow.Field
+	for _, column := range columns {
+		var typ arrow.DataType
+		switch column.typ {
+		case queryResultColumnLong:
+			typ = arrow.PrimitiveTypes.Int64
+		case queryResultColumnDouble:
+			typ = arrow.PrimitiveTypes.Float64
+		case queryResultColumnBoolean:
+			typ = arrow.FixedWidthTypes.Boolean
+		default:
+			typ = arrow.BinaryTypes.String
+		}
+		fields = append(fields, arrow.Field{Name: column.name, Type: typ, Nullable: true})
+	}
+	var metadata *arrow.Metadata
+	if watermark != nil {
+		m := arrow.NewMetadata(watermark.metadata())
+		metadata = &m
+	}
+	schema := arrow.NewSchema(fields, metadata)
+	props := parquet.NewWriterProperties(
+		parquet.WithCompression(compress.Codecs.Snappy),
+		parquet.WithMaxRowGroupLength(queryResultExportBatchSize),
+	)
+	writer, err := pqarrow.NewFileWriter(schema, w, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return errors.Wrap(err, "failed to create parquet writer")
+	}
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+
+	for start := 0; start < len(rows); start += queryResultExportBatchSize {
+		end := start + queryResultExportBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		for i := start; i < end; i++ {
+			if err := appendParquetRow(builder, columns, rows[i]); err != nil {
+				return errors.Wrapf(err, "row %d", i+1)
+			}
+		}
+		record := builder.NewRecord()
+		err := writer.Write(record)
+		record.Release()
+		if err != nil {
+			return errors.Wrap(err, "failed to write parquet row group")
+		}
+	}
+	return writer.Close()
+}
+
+func appendParquetRow(builder *array.RecordBuilder, columns []*queryResultColumn, row []any) error {
+	for j, column := range columns {
+		field := builder.Field(j)
+		if row[j] == nil {
+			field.AppendNull()
+			continue
+		}
+		switch column.typ {
+		case queryResultColumnLong:
+			v, err := convertToInt64(row[j])
+			if err != nil {
+				return errors.Wrapf(err, "column %q", column.name)
+			}
+			field.(*array.Int64Builder).Append(v)
+		case queryResultColumnDouble:
+			v, err := convertToFloat64(row[j])
+			if err != nil {
+				return errors.Wrapf(err, "column %q", column.name)
+			}
+			field.(*array.Float64Builder).Append(v)
+		case queryResultColumnBoolean:
+			v, err := convertToBool(row[j])
+			if err != nil {
+				return errors.Wrapf(err, "column %q", column.name)
+			}
+			field.(*array.BooleanBuilder).Append(v)
+		default:
+			field.(*array.StringBuilder).Append(convertToString(row[j]))
+		}
+	}
+	return nil
+}
+
+// exportAvro writes the Avro object container file without compression.
+// See https://avro.apache.org/docs/1.11.1/specification/#object-container-files.
+func exportAvro(w io.Writer, columns []*queryResultColumn, rows [][]any, watermark *QueryResultWatermark) error {
+	type avroField struct {
+		Name string   `json:"name"`
+		Type []string `json:"type"`
+		Doc  string   `json:"doc,omitempty"`
+	}
+	schema := struct {
+		Type   string      `json:"type"`
+		Name   string      `json:"name"`
+		Fields []avroField `json:"fields"`
+	}{
+		Type: "record",
+		Name: "QueryResult",
+	}
+	names := make(map[string]bool)
+	for _, column := range columns {
+		// The Avro names only contain [A-Za-z0-9_] and don't start with a digit.
+		name := avroNameInvalidCharRegexp.ReplaceAllString(column.name, "_")
+		if name == "" || (name[0] >= '0' && name[0] <= '9') {
+			name = "_" + name
+		}
+		uniqueName := name
+		for suffix := 2; names[uniqueName]; suffix++ {
+			uniqueName = fmt.Sprintf("%s_%d", name, suffix)
+		}
+		names[uniqueName] = true
+		field := avroField{Name: uniqueName, Type: []string{"null", string(column.typ)}}
+		if uniqueName != column.name {
+			field.Doc = column.name
+		}
+		schema.Fields = append(schema.Fields, field)
+	}
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+
+	var sync [16]byte
+	if _, err := rand.Read(sync[:]); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	var header bytes.Buffer
+	keys, values := []string{"avro.schema", "avro.codec"}, []string{string(schemaJSON), "null"}
+	if watermark != nil {
+		watermarkKeys, watermarkValues := watermark.metadata()
+		keys, values = append(keys, watermarkKeys...), append(values, watermarkValues...)
+	}
+	header.WriteString("Obj\x01")
+	writeAvroLong(&header, int64(len(keys)))
+	for i := range keys {
+		writeAvroBytes(&header, []byte(keys[i]))
+		writeAvroBytes(&header, []byte(values[i]))
+	}
+	writeAvroLong(&header, 0)
+	header.Write(sync[:])
+	if _, err := bw.Write(header.Bytes()); err != nil {
+		return err
+	}
+
+	var block, blockHeader bytes.Buffer
+	for start := 0; start < len(rows); start += queryResultExportBatchSize {
+		end := start + queryResultExportBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		block.Reset()
+		for i := start; i < end; i++ {
+			if err := writeAvroRow(&block, columns, rows[i]); err != nil {
+				return errors.Wrapf(err, "row %d", i+1)
+			}
+		}
+		blockHeader.Reset()
+		writeAvroLong(&blockHeader, int64(end-start))
+		writeAvroLong(&blockHeader, int64(block.Len()))
+		for _, b := range [][]byte{blockHeader.Bytes(), block.Bytes(), sync[:]} {
+			if _, err := bw.Write(b); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+func writeAvroRow(buf *bytes.Buffer, columns []*queryResultColumn, row []any) error {
+	for j, column := range columns {
+		// The fields are the unions of null and the column type.
+		if row[j] == nil {
+			writeAvroLong(buf, 0)
+			continue
+		}
+		writeAvroLong(buf, 1)
+		switch column.typ {
+		case queryResultColumnLong:
+			v, err := convertToInt64(row[j])
+			if err != nil {
+				return errors.Wrapf(err, "column %q", column.name)
+			}
+			writeAvroLong(buf, v)
+		case queryResultColumnDouble:
+			v, err := convertToFloat64(row[j])
+			if err != nil {
+				return errors.Wrapf(err, "column %q", column.name)
+			}
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+			buf.Write(b[:])
+		case queryResultColumnBoolean:
+			v, err := convertToBool(row[j])
+			if err != nil {
+				return errors.Wrapf(err, "column %q", column.name)
+			}
+			if v {
+				buf.WriteByte(1)
+			} else {
+				buf.WriteByte(0)
+			}
+		default:
+			writeAvroBytes(buf, []byte(convertToString(row[j])))
+		}
+	}
+	return nil
+}
+
+// writeAvroLong writes the zig-zag encoded variable-length long.
+func writeAvroLong(buf *bytes.Buffer, v int64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(b[:], v)
+	buf.Write(b[:n])
+}
+
+func writeAvroBytes(buf *bytes.Buffer, b []byte) {
+	writeAvroLong(buf, int64(len(b)))
+	buf.Write(b)
+}
+
+func convertToInt64(v any) (int64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.Int64()
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case int64:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, errors.Errorf("%v is not an integer", v)
+		}
+		return int64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 0, errors.Errorf("unexpected value %v for integer", v)
+}
+
+func convertToFloat64(v any) (float64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(v, 64)
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	}
+	return 0, errors.Errorf("unexpected value %v for float", v)
+}
+
+func convertToBool(v any) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case json.Number:
+		return v.String() != "0", nil
+	case string:
+		return strconv.ParseBool(v)
+	}
+	return false, errors.Errorf("unexpected value %v for boolean", v)
+}
+
+func convertToString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	"github.com/apache/arrow/go/v10/parquet/file"
+	"github.com/apache/arrow/go/v10/parquet/pqarrow"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestGetQueryResultColumnType(t *testing.T) {
+	tests := []struct {
+		engine   db.Type
+		typeName string
+		want     queryResultColumnType
+	}{
+		{db.Postgres, "INT8", queryResultColumnLong},
+		{db.Postgres, "FLOAT4", queryResultColumnDouble},
+		{db.Postgres, "BOOL", queryResultColumnBoolean},
+		{db.Postgres, "NUMERIC", queryResultColumnString},
+		{db.MySQL, "UNSIGNED INT", queryResultColumnLong},
+		{db.MySQL, "UNSIGNED BIGINT", queryResultColumnString},
+		{db.MySQL, "DECIMAL", queryResultColumnString},
+		{db.ClickHouse, "Nullable(UInt32)", queryResultColumnLong},
+		{db.ClickHouse, "UInt64", queryResultColumnString},
+		{db.Oracle, "NUMBER", queryResultColumnString},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, getQueryResultColumnType(test.engine, test.typeName), "%s %s", test.engine, test.typeName)
+	}
+}
+
+func TestExportQueryResult(t *testing.T) {
+	var rows [][]any
+	decoder := json.NewDecoder(bytes.NewBufferString(`[[1, "alice", 9007199254740993, true, 1.5], [2, "******", null, false, null]]`))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&rows))
+	result := &QueryResultExport{
+		Engine:          db.Postgres,
+		ColumnNames:     []string{"id", "name", "id", "active", "score"},
+		ColumnTypeNames: []string{"INT4", "TEXT", "INT8", "BOOL", "FLOAT8"},
+		SensitiveList:   []bool{false, true, false, false, false},
+		Rows:            rows,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ExportQueryResult(&buf, result, QueryResultExportFormatParquet))
+	table, err := pqarrow.ReadTable(context.Background(), bytes.NewReader(buf.Bytes()), nil, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	require.NoError(t, err)
+	defer table.Release()
+	require.Equal(t, int64(2), table.NumRows())
+	var names []string
+	var types []arrow.DataType
+	for _, field := range table.Schema().Fields() {
+		names = append(names, field.Name)
+		types = append(types, field.Type)
+	}
+	require.Equal(t, []string{"id", "name", "id_2", "active", "score"}, names)
+	require.Equal(t, []arrow.DataType{arrow.PrimitiveTypes.Int64, arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int64, arrow.FixedWidthTypes.Boolean, arrow.PrimitiveTypes.Float64}, types)
+	bigints := table.Column(2).Data().Chunk(0).(*array.Int64)
+	require.Equal(t, int64(9007199254740993), bigints.Value(0))
+	require.True(t, bigints.IsNull(1))
+
+	buf.Reset()
+	require.NoError(t, ExportQueryResult(&buf, result, QueryResultExportFormatAvro))
+	content := buf.Bytes()
+	require.Equal(t, []byte("Obj\x01"), content[:4])
+	reader := bytes.NewReader(content[4:])
+	readLong := func() int64 {
+		v, err := binary.ReadVarint(reader)
+		require.NoError(t, err)
+		return v
+	}
+	readBytes := func() string {
+		b := make([]byte, readLong())
+		_, err := reader.Read(b)
+		require.NoError(t, err)
+		return string(b)
+	}
+	require.Equal(t, int64(2), readLong())
+	require.Equal(t, "avro.schema", readBytes())
+	require.Equal(t, `{"type":"record","name":"QueryResult","fields":[{"name":"id","type":["null","long"]},{"name":"name","type":["null","string"]},{"name":"id_2","type":["null","long"]},{"name":"active","type":["null","boolean"]},{"name":"score","type":["null","double"]}]}`, readBytes())
+	require.Equal(t, "avro.codec", readBytes())
+	require.Equal(t, "null", readBytes())
+	require.Equal(t, int64(0), readLong())
+	sync := make([]byte, 16)
+	_, err = reader.Read(sync)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), readLong())
+	blockSize := readLong()
+	// The first row starts with the id 1 in the non-null branch of the union.
+	require.Equal(t, int64(1), readLong())
+	require.Equal(t, int64(1), readLong())
+	require.Equal(t, int64(reader.Len()), blockSize-2+16)
+	require.Equal(t, sync, content[len(content)-16:])
+
+	result.Rows = [][]any{{"x", "alice", nil, true, 1.5}}
+	require.Error(t, ExportQueryResult(&buf, result, QueryResultExportFormatAvro))
+	require.Error(t, ExportQueryResult(&buf, result, "orc"))
+}
+
+func TestExportQueryResultWatermark(t *testing.T) {
+	result := &QueryResultExport{
+		Engine:          db.Postgres,
+		ColumnNames:     []string{"id"},
+		ColumnTypeNames: []string{"INT4"},
+		Rows:            [][]any{{json.Number("1")}, {json.Number("2")}},
+		Watermark: &QueryResultWatermark{
+			ExportID:   "e1",
+			Requester:  "alice@example.com",
+			ExportedTs: 1697000000,
+			Column:     true,
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ExportQueryResult(&buf, result, QueryResultExportFormatParquet))
+	table, err := pqarrow.ReadTable(context.Background(), bytes.NewReader(buf.Bytes()), nil, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	require.NoError(t, err)
+	defer table.Release()
+	require.Equal(t, int64(2), table.NumCols())
+	require.Equal(t, QueryResultWatermarkColumn, table.Schema().Field(1).Name)
+	watermarks := table.Column(1).Data().Chunk(0).(*array.String)
+	require.Equal(t, "export e1 by alice@example.com at 2023-10-11T04:53:20Z", watermarks.Value(1))
+	reader, err := file.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, "e1", *reader.MetaData().KeyValueMetadata().FindValue("bytebase.export.id"))
+	// The rows of the caller are not changed.
+	require.Len(t, result.Rows[0], 1)
+
+	buf.Reset()
+	result.Watermark.Column = false
+	require.NoError(t, ExportQueryResult(&buf, result, QueryResultExportFormatAvro))
+	require.Contains(t, buf.String(), "bytebase.export.requester")
+	require.NotContains(t, buf.String(), QueryResultWatermarkColumn)
+}
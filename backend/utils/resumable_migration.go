@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+)
+
+// ResumableMigrationOption is the option of the resumable migration.
+type ResumableMigrationOption struct {
+	// CompletedCount is the number of the leading statements executed by the previous attempts, which are skipped.
+	CompletedCount int
+	// OnStatementDone is called after each statement is executed with the number of the completed statements.
+	// The migration fails if it fails to record the checkpoint.
+	OnStatementDone func(completed, total int) error
+}
+
+// SplitResumableStatements splits the migration into the statements executed one by one.
+func SplitResumableStatements(engine db.Type, statement string) ([]string, error) {
+	var parserEngine parser.EngineType
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB:
+		parserEngine = parser.MySQL
+	case db.Postgres:
+		parserEngine = parser.Postgres
+	default:
+		return nil, errors.Errorf("resumable migration is not supported for engine %q", engine)
+	}
+	singleSQLs, err := parser.SplitMultiSQL(parserEngine, statement)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	for _, singleSQL := range singleSQLs {
+		list = append(list, strings.TrimSpace(singleSQL.Text))
+	}
+	return list, nil
+}
+
+// ExecuteResumableMigration executes the statements one by one from the first statement not executed by the previous
+// attempts. Each statement is committed on its own, so a failure doesn't roll back the statements executed before it.
+// It returns the number of the completed statements.
+func ExecuteResumableMigration(ctx context.Context, engine db.Type, statement string, option ResumableMigrationOption, execute func(ctx context.Context, statement string) error) (int, error) {
+	list, err := SplitResumableStatements(engine, statement)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to split the statements")
+	}
+	completed := option.CompletedCount
+	if completed < 0 || completed > len(list) {
+		return 0, errors.Errorf("invalid checkpoint, %d statements completed out of %d", completed, len(list))
+	}
+	for ; completed < len(list); completed++ {
+		if err := ctx.Err(); err != nil {
+			return completed, err
+		}
+		if err := execute(ctx, list[completed]); err != nil {
+			return completed, errors.Wrapf(err, "failed to execute statement %d of %d", completed+1, len(list))
+		}
+		if option.OnStatementDone != nil {
+			if err := option.OnStatementDone(completed+1, len(list)); err != nil {
+				return completed + 1, errors.Wrap(err, "failed to record the checkpoint")
+			}
+		}
+	}
+	return completed, nil
+}
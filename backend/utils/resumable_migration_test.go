@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestExecuteResumableMigration(t *testing.T) {
+	ctx := context.Background()
+	statement := "CREATE TABLE t1 (id INT);\nINSERT INTO t1 VALUES (1);\nCREATE TABLE t2 (id INT);"
+
+	var executed []string
+	var checkpoints []int
+	option := ResumableMigrationOption{
+		OnStatementDone: func(completed, total int) error {
+			require.Equal(t, 3, total)
+			checkpoints = append(checkpoints, completed)
+			return nil
+		},
+	}
+	completed, err := ExecuteResumableMigration(ctx, db.MySQL, statement, option, func(_ context.Context, statement string) error {
+		if statement == "CREATE TABLE t2 (id INT);" {
+			return errors.New("lock wait timeout")
+		}
+		executed = append(executed, statement)
+		return nil
+	})
+	require.EqualError(t, err, "failed to execute statement 3 of 3: lock wait timeout")
+	require.Equal(t, 2, completed)
+	require.Equal(t, []int{1, 2}, checkpoints)
+	require.Equal(t, []string{"CREATE TABLE t1 (id INT);", "INSERT INTO t1 VALUES (1);"}, executed)
+
+	// The retry starts from the first statement not executed.
+	executed = nil
+	option.CompletedCount = completed
+	completed, err = ExecuteResumableMigration(ctx, db.Postgres, statement, option, func(_ context.Context, statement string) error {
+		executed = append(executed, statement)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, completed)
+	require.Equal(t, []string{"CREATE TABLE t2 (id INT);"}, executed)
+
+	option.CompletedCount = 4
+	_, err = ExecuteResumableMigration(ctx, db.MySQL, statement, option, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+	"github.com/bytebase/bytebase/backend/plugin/parser/sql/differ"
+)
+
+// ddlRollbackCaveat notes the data which the rollback statement can't restore.
+type ddlRollbackCaveat struct {
+	reg  *regexp.Regexp
+	note string
+}
+
+var ddlRollbackCaveats = []ddlRollbackCaveat{
+	{
+		reg:  regexp.MustCompile(`(?is)^CREATE\s+TABLE\b`),
+		note: "The table is recreated empty, the rows dropped by the change can only be restored from the backups.",
+	},
+	{
+		reg:  regexp.MustCompile(`(?is)^DROP\s+TABLE\b`),
+		note: "The table created by the change is dropped with the rows written since then.",
+	},
+	{
+		reg:  regexp.MustCompile(`(?is)\bADD\s+COLUMN\b`),
+		note: "The column is re-added with the default values, the values dropped by the change can only be restored from the backups.",
+	},
+	{
+		reg:  regexp.MustCompile(`(?is)\bDROP\s+COLUMN\b`),
+		note: "The column added by the change is dropped with the values written since then.",
+	},
+	{
+		reg:  regexp.MustCompile(`(?is)\bMODIFY\s+COLUMN\b|\bALTER\s+COLUMN\s+\S+\s+(?:SET\s+DATA\s+)?TYPE\b`),
+		note: "The column type is restored, the values converted by the change may not be restored exactly.",
+	},
+}
+
+// GenerateDDLRollbackStatement generates the statement reverting the schema after the DDL change to the schema before the change.
+// The statements which can't restore the data are annotated with the caveats.
+// It returns an empty statement if the engine is not supported or the change doesn't alter the schema.
+func GenerateDDLRollbackStatement(engine db.Type, prevSchema, schema string) (string, error) {
+	var parserEngine parser.EngineType
+	switch engine {
+	case db.Postgres:
+		parserEngine = parser.Postgres
+	case db.MySQL, db.MariaDB:
+		parserEngine = parser.MySQL
+	default:
+		return "", nil
+	}
+	if prevSchema == schema {
+		return "", nil
+	}
+
+	statement, err := differ.SchemaDiff(parserEngine, schema, prevSchema)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to compute the schema diff")
+	}
+	list, err := parser.SplitMultiSQL(parserEngine, statement)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to split the rollback statement")
+	}
+
+	var buf strings.Builder
+	for _, sql := range list {
+		text := strings.TrimSpace(sql.Text)
+		if text == "" {
+			continue
+		}
+		for _, caveat := range ddlRollbackCaveats {
+			if caveat.reg.MatchString(text) {
+				_, _ = buf.WriteString("-- Caveat: " + caveat.note + "\n")
+			}
+		}
+		_, _ = buf.WriteString(text + "\n")
+	}
+	return buf.String(), nil
+}
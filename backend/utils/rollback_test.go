@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+
+	// Register the schema differs.
+	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/differ/mysql"
+	_ "github.com/bytebase/bytebase/backend/plugin/parser/sql/differ/pg"
+)
+
+func TestGenerateDDLRollbackStatement(t *testing.T) {
+	a := require.New(t)
+
+	tests := []struct {
+		engine     db.Type
+		prevSchema string
+		schema     string
+		want       string
+	}{
+		{
+			engine:     db.MySQL,
+			prevSchema: "CREATE TABLE `t1` (\n  `id` int NOT NULL,\n  `name` varchar(10),\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB;\nCREATE TABLE `t2` (`id` int);\n",
+			schema:     "CREATE TABLE `t1` (\n  `id` bigint NOT NULL,\n  `age` int,\n  PRIMARY KEY (`id`),\n  KEY `idx_age` (`age`)\n) ENGINE=InnoDB;\nCREATE TABLE `t3` (`id` int);\n",
+			want: "SET FOREIGN_KEY_CHECKS=0;\n" +
+				"DROP INDEX `idx_age` ON `t1`;\n" +
+				"-- Caveat: The table created by the change is dropped with the rows written since then.\n" +
+				"DROP TABLE IF EXISTS `t3`;\n" +
+				"-- Caveat: The table is recreated empty, the rows dropped by the change can only be restored from the backups.\n" +
+				"CREATE TABLE IF NOT EXISTS `t2` (\n  `id` INT\n);\n" +
+				"-- Caveat: The column is re-added with the default values, the values dropped by the change can only be restored from the backups.\n" +
+				"-- Caveat: The column type is restored, the values converted by the change may not be restored exactly.\n" +
+				"ALTER TABLE `t1` MODIFY COLUMN `id` INT NOT NULL, ADD COLUMN `name` VARCHAR(10) AFTER `id`;\n" +
+				"-- Caveat: The column added by the change is dropped with the values written since then.\n" +
+				"ALTER TABLE `t1` DROP COLUMN `age`;\n" +
+				"SET FOREIGN_KEY_CHECKS=1;\n",
+		},
+		{
+			engine:     db.Postgres,
+			prevSchema: "CREATE TABLE public.t1 (\n    id integer NOT NULL\n);\n",
+			schema:     "CREATE TABLE public.t1 (\n    id bigint NOT NULL\n);\nCREATE INDEX idx_id ON public.t1 USING btree (id);\n",
+			want: "DROP INDEX \"public\".\"idx_id\";\n" +
+				"-- Caveat: The column type is restored, the values converted by the change may not be restored exactly.\n" +
+				"ALTER TABLE \"public\".\"t1\"\n    ALTER COLUMN \"id\" SET DATA TYPE integer;\n",
+		},
+		{
+			engine:     db.Postgres,
+			prevSchema: "CREATE TABLE public.t1 (id integer);\n",
+			schema:     "CREATE TABLE public.t1 (id integer);\n",
+		},
+		{
+			engine:     db.Snowflake,
+			prevSchema: "CREATE TABLE t1 (id integer);\n",
+			schema:     "CREATE TABLE t2 (id integer);\n",
+		},
+	}
+	for _, test := range tests {
+		statement, err := GenerateDDLRollbackStatement(test.engine, test.prevSchema, test.schema)
+		a.NoError(err)
+		a.Equal(test.want, statement)
+	}
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"encoding/json"
+	"math"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+// SplitCanaryDatabases splits the databases of a deployment into the canaries rolled out first and the rest.
+// The explicit canary databases are always canaries, and then the leading databases are picked until the canaries
+// reach the canary percentage, rounding up.
+func SplitCanaryDatabases(databases []*store.DatabaseMessage, strategy *api.RolloutStrategy) ([]*store.DatabaseMessage, []*store.DatabaseMessage) {
+	if strategy == nil {
+		return nil, databases
+	}
+	explicit := make(map[int]bool)
+	for _, id := range strategy.CanaryDatabaseIDList {
+		explicit[id] = true
+	}
+	canaryCount := int(math.Ceil(float64(len(databases)*strategy.CanaryPercentage) / 100))
+
+	isCanary := make(map[int]bool)
+	for _, database := range databases {
+		if explicit[database.UID] {
+			isCanary[database.UID] = true
+		}
+	}
+	for _, database := range databases {
+		if len(isCanary) >= canaryCount {
+			break
+		}
+		isCanary[database.UID] = true
+	}
+
+	var canaries, rest []*store.DatabaseMessage
+	for _, database := range databases {
+		if isCanary[database.UID] {
+			canaries = append(canaries, database)
+		} else {
+			rest = append(rest, database)
+		}
+	}
+	return canaries, rest
+}
+
+// GetTaskRolloutStrategy gets the rollout strategy of a task, nil if the task isn't throttled.
+func GetTaskRolloutStrategy(taskPayload string) (*api.RolloutStrategy, error) {
+	var payload struct {
+		RolloutStrategy *api.RolloutStrategy `json:"rolloutStrategy,omitempty"`
+	}
+	if err := json.Unmarshal([]byte(taskPayload), &payload); err != nil {
+		return nil, err
+	}
+	return payload.RolloutStrategy, nil
+}
+
+// IsTaskManualRollout returns whether the task waits for the manual rollout regardless of the approval policy.
+func IsTaskManualRollout(taskPayload string) (bool, error) {
+	var payload struct {
+		ManualRollout bool `json:"manualRollout,omitempty"`
+	}
+	if err := json.Unmarshal([]byte(taskPayload), &payload); err != nil {
+		return false, err
+	}
+	return payload.ManualRollout, nil
+}
+
+// GetRolloutCapacity returns the number of the tasks which can start running in the pipeline under the rollout strategy.
+// It's zero if the pipeline reaches the maximum parallelism or stops on the failures.
+func GetRolloutCapacity(strategy *api.RolloutStrategy, pipelineTasks []*store.TaskMessage) int {
+	if strategy == nil {
+		return math.MaxInt
+	}
+	running, failed := 0, 0
+	for _, task := range pipelineTasks {
+		switch task.Status {
+		case api.TaskRunning:
+			running++
+		case api.TaskFailed:
+			failed++
+		}
+	}
+	if strategy.StopOnFailureCount > 0 && failed >= strategy.StopOnFailureCount {
+		return 0
+	}
+	if strategy.MaxParallelism <= 0 {
+		return math.MaxInt
+	}
+	if running >= strategy.MaxParallelism {
+		return 0
+	}
+	return strategy.MaxParallelism - running
+}
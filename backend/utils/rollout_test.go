@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/store"
+)
+
+func TestSplitCanaryDatabases(t *testing.T) {
+	var databases []*store.DatabaseMessage
+	for i := 1; i <= 10; i++ {
+		databases = append(databases, &store.DatabaseMessage{UID: i})
+	}
+	uids := func(databases []*store.DatabaseMessage) []int {
+		var result []int
+		for _, database := range databases {
+			result = append(result, database.UID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		strategy *api.RolloutStrategy
+		canaries []int
+		rest     []int
+	}{
+		{
+			strategy: nil,
+			canaries: nil,
+			rest:     []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+		{
+			strategy: &api.RolloutStrategy{CanaryPercentage: 15},
+			canaries: []int{1, 2},
+			rest:     []int{3, 4, 5, 6, 7, 8, 9, 10},
+		},
+		{
+			strategy: &api.RolloutStrategy{CanaryPercentage: 20, CanaryDatabaseIDList: []int{7}},
+			canaries: []int{1, 7},
+			rest:     []int{2, 3, 4, 5, 6, 8, 9, 10},
+		},
+		{
+			strategy: &api.RolloutStrategy{CanaryDatabaseIDList: []int{4, 9, 42}},
+			canaries: []int{4, 9},
+			rest:     []int{1, 2, 3, 5, 6, 7, 8, 10},
+		},
+		{
+			strategy: &api.RolloutStrategy{CanaryPercentage: 100},
+			canaries: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			rest:     nil,
+		},
+	}
+
+	for _, test := range tests {
+		canaries, rest := SplitCanaryDatabases(databases, test.strategy)
+		require.Equal(t, test.canaries, uids(canaries))
+		require.Equal(t, test.rest, uids(rest))
+	}
+}
+
+func TestGetTaskRolloutStrategy(t *testing.T) {
+	strategy, err := GetTaskRolloutStrategy(`{"sheetId":1,"rolloutStrategy":{"maxParallelism":3,"stopOnFailureCount":2}}`)
+	require.NoError(t, err)
+	require.Equal(t, &api.RolloutStrategy{MaxParallelism: 3, StopOnFailureCount: 2}, strategy)
+
+	strategy, err = GetTaskRolloutStrategy(`{"sheetId":1}`)
+	require.NoError(t, err)
+	require.Nil(t, strategy)
+}
+
+func TestIsTaskManualRollout(t *testing.T) {
+	manualRollout, err := IsTaskManualRollout(`{"sheetId":1,"manualRollout":true}`)
+	require.NoError(t, err)
+	require.True(t, manualRollout)
+
+	manualRollout, err = IsTaskManualRollout(`{"sheetId":1}`)
+	require.NoError(t, err)
+	require.False(t, manualRollout)
+}
+
+func TestGetRolloutCapacity(t *testing.T) {
+	tasks := func(statusList ...api.TaskStatus) []*store.TaskMessage {
+		var result []*store.TaskMessage
+		for _, status := range statusList {
+			result = append(result, &store.TaskMessage{Status: status})
+		}
+		return result
+	}
+
+	tests := []struct {
+		strategy *api.RolloutStrategy
+		tasks    []*store.TaskMessage
+		want     int
+	}{
+		{
+			strategy: nil,
+			tasks:    tasks(api.TaskRunning, api.TaskFailed),
+			want:     math.MaxInt,
+		},
+		{
+			strategy: &api.RolloutStrategy{MaxParallelism: 3},
+			tasks:    tasks(api.TaskRunning, api.TaskPending, api.TaskPending, api.TaskDone),
+			want:     2,
+		},
+		{
+			strategy: &api.RolloutStrategy{MaxParallelism: 2},
+			tasks:    tasks(api.TaskRunning, api.TaskRunning, api.TaskPending),
+			want:     0,
+		},
+		{
+			strategy: &api.RolloutStrategy{StopOnFailureCount: 2},
+			tasks:    tasks(api.TaskFailed, api.TaskPending),
+			want:     math.MaxInt,
+		},
+		{
+			strategy: &api.RolloutStrategy{MaxParallelism: 5, StopOnFailureCount: 2},
+			tasks:    tasks(api.TaskFailed, api.TaskFailed, api.TaskPending),
+			want:     0,
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, GetRolloutCapacity(test.strategy, test.tasks))
+	}
+}
@@ -0,0 +1,217 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// schemaObject is the schema object with its definition for comparison.
+type schemaObject struct {
+	name       string
+	definition string
+}
+
+type schemaComparer struct {
+	diffList []*api.SchemaObjectDiff
+}
+
+// CompareSchemaMetadata compares the schema metadata of the source and target databases, and returns the object-level
+// differences to promote the source schema to the target database. The objects only in the source database are created,
+// the objects only in the target database are dropped, and the changed objects are altered. The columns, indexes and
+// foreign keys are compared only if the table exists in both databases.
+func CompareSchemaMetadata(source, target *storepb.DatabaseMetadata) []*api.SchemaObjectDiff {
+	c := &schemaComparer{diffList: []*api.SchemaObjectDiff{}}
+	sourceSchemas, targetSchemas := make(map[string]*storepb.SchemaMetadata), make(map[string]*storepb.SchemaMetadata)
+	for _, schema := range source.GetSchemas() {
+		sourceSchemas[schema.Name] = schema
+	}
+	for _, schema := range target.GetSchemas() {
+		targetSchemas[schema.Name] = schema
+	}
+
+	for _, sourceSchema := range source.GetSchemas() {
+		targetSchema, ok := targetSchemas[sourceSchema.Name]
+		if !ok {
+			c.add(api.SchemaObjectSchema, api.SchemaObjectDiffCreate, sourceSchema.Name, "", sourceSchema.Name, sourceSchema.Name, "")
+			continue
+		}
+		c.compareSchema(sourceSchema, targetSchema)
+	}
+	for _, targetSchema := range target.GetSchemas() {
+		if _, ok := sourceSchemas[targetSchema.Name]; !ok {
+			c.add(api.SchemaObjectSchema, api.SchemaObjectDiffDrop, targetSchema.Name, "", targetSchema.Name, "", targetSchema.Name)
+		}
+	}
+	return c.diffList
+}
+
+func (c *schemaComparer) add(tp api.SchemaObjectType, action api.SchemaObjectDiffAction, schema, table, name, source, target string) {
+	c.diffList = append(c.diffList, &api.SchemaObjectDiff{
+		Type:   tp,
+		Action: action,
+		Schema: schema,
+		Table:  table,
+		Name:   name,
+		Source: source,
+		Target: target,
+	})
+}
+
+// compareObjects compares the objects by name, the differences follow the order of the source objects and then the target objects.
+func (c *schemaComparer) compareObjects(tp api.SchemaObjectType, schema, table string, source, target []*schemaObject) {
+	sourceMap, targetMap := make(map[string]*schemaObject), make(map[string]*schemaObject)
+	for _, object := range source {
+		sourceMap[object.name] = object
+	}
+	for _, object := range target {
+		targetMap[object.name] = object
+	}
+	for _, sourceObject := range source {
+		targetObject, ok := targetMap[sourceObject.name]
+		if !ok {
+			c.add(tp, api.SchemaObjectDiffCreate, schema, table, sourceObject.name, sourceObject.definition, "")
+			continue
+		}
+		if sourceObject.definition != targetObject.definition {
+			c.add(tp, api.SchemaObjectDiffAlter, schema, table, sourceObject.name, sourceObject.definition, targetObject.definition)
+		}
+	}
+	for _, targetObject := range target {
+		if _, ok := sourceMap[targetObject.name]; !ok {
+			c.add(tp, api.SchemaObjectDiffDrop, schema, table, targetObject.name, "", targetObject.definition)
+		}
+	}
+}
+
+func (c *schemaComparer) compareSchema(source, target *storepb.SchemaMetadata) {
+	var sourceTables, targetTables []*schemaObject
+	for _, table := range source.Tables {
+		sourceTables = append(sourceTables, &schemaObject{name: table.Name, definition: tableDefinition(table)})
+	}
+	for _, table := range target.Tables {
+		targetTables = append(targetTables, &schemaObject{name: table.Name, definition: tableDefinition(table)})
+	}
+	c.compareObjects(api.SchemaObjectTable, source.Name, "", sourceTables, targetTables)
+
+	targetTableMap := make(map[string]*storepb.TableMetadata)
+	for _, table := range target.Tables {
+		targetTableMap[table.Name] = table
+	}
+	for _, sourceTable := range source.Tables {
+		if targetTable, ok := targetTableMap[sourceTable.Name]; ok {
+			c.compareTable(source.Name, sourceTable, targetTable)
+		}
+	}
+
+	var sourceViews, targetViews []*schemaObject
+	for _, view := range source.Views {
+		sourceViews = append(sourceViews, &schemaObject{name: view.Name, definition: strings.TrimSpace(view.Definition)})
+	}
+	for _, view := range target.Views {
+		targetViews = append(targetViews, &schemaObject{name: view.Name, definition: strings.TrimSpace(view.Definition)})
+	}
+	c.compareObjects(api.SchemaObjectView, source.Name, "", sourceViews, targetViews)
+
+	var sourceFunctions, targetFunctions []*schemaObject
+	for _, function := range source.Functions {
+		sourceFunctions = append(sourceFunctions, &schemaObject{name: function.Name, definition: strings.TrimSpace(function.Definition)})
+	}
+	for _, function := range target.Functions {
+		targetFunctions = append(targetFunctions, &schemaObject{name: function.Name, definition: strings.TrimSpace(function.Definition)})
+	}
+	c.compareObjects(api.SchemaObjectFunction, source.Name, "", sourceFunctions, targetFunctions)
+}
+
+func (c *schemaComparer) compareTable(schema string, source, target *storepb.TableMetadata) {
+	columnObjects := func(table *storepb.TableMetadata) []*schemaObject {
+		var objects []*schemaObject
+		for _, column := range table.Columns {
+			objects = append(objects, &schemaObject{name: column.Name, definition: columnDefinition(column)})
+		}
+		return objects
+	}
+	c.compareObjects(api.SchemaObjectColumn, schema, source.Name, columnObjects(source), columnObjects(target))
+
+	indexObjects := func(table *storepb.TableMetadata) []*schemaObject {
+		var objects []*schemaObject
+		for _, index := range table.Indexes {
+			objects = append(objects, &schemaObject{name: index.Name, definition: indexDefinition(index)})
+		}
+		return objects
+	}
+	c.compareObjects(api.SchemaObjectIndex, schema, source.Name, indexObjects(source), indexObjects(target))
+
+	foreignKeyObjects := func(table *storepb.TableMetadata) []*schemaObject {
+		var objects []*schemaObject
+		for _, fk := range table.ForeignKeys {
+			objects = append(objects, &schemaObject{name: fk.Name, definition: foreignKeyDefinition(fk)})
+		}
+		return objects
+	}
+	c.compareObjects(api.SchemaObjectForeignKey, schema, source.Name, foreignKeyObjects(source), foreignKeyObjects(target))
+}
+
+// tableDefinition returns the table-level definition, the columns, indexes and foreign keys are compared separately.
+func tableDefinition(table *storepb.TableMetadata) string {
+	var parts []string
+	if table.Engine != "" {
+		parts = append(parts, "ENGINE "+table.Engine)
+	}
+	if table.Collation != "" {
+		parts = append(parts, "COLLATE "+table.Collation)
+	}
+	if table.Comment != "" {
+		parts = append(parts, "COMMENT "+quoteSQLString(table.Comment))
+	}
+	return strings.Join(parts, " ")
+}
+
+func columnDefinition(column *storepb.ColumnMetadata) string {
+	definition := column.Type
+	if !column.Nullable {
+		definition += " NOT NULL"
+	}
+	if column.Default != nil {
+		definition += " DEFAULT " + column.Default.Value
+	}
+	if column.Collation != "" {
+		definition += " COLLATE " + column.Collation
+	}
+	if column.Comment != "" {
+		definition += " COMMENT " + quoteSQLString(column.Comment)
+	}
+	return definition
+}
+
+func indexDefinition(index *storepb.IndexMetadata) string {
+	kind := "INDEX"
+	switch {
+	case index.Primary:
+		kind = "PRIMARY KEY"
+	case index.Unique:
+		kind = "UNIQUE INDEX"
+	}
+	definition := fmt.Sprintf("%s (%s)", kind, strings.Join(index.Expressions, ", "))
+	if index.Type != "" {
+		definition += " USING " + index.Type
+	}
+	return definition
+}
+
+func foreignKeyDefinition(fk *storepb.ForeignKeyMetadata) string {
+	referencedTable := fk.ReferencedTable
+	if fk.ReferencedSchema != "" {
+		referencedTable = fk.ReferencedSchema + "." + referencedTable
+	}
+	definition := fmt.Sprintf("(%s) REFERENCES %s (%s)", strings.Join(fk.Columns, ", "), referencedTable, strings.Join(fk.ReferencedColumns, ", "))
+	if fk.OnDelete != "" {
+		definition += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" {
+		definition += " ON UPDATE " + fk.OnUpdate
+	}
+	return definition
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func TestCompareSchemaMetadata(t *testing.T) {
+	source := &storepb.DatabaseMetadata{
+		Schemas: []*storepb.SchemaMetadata{
+			{
+				Name: "public",
+				Tables: []*storepb.TableMetadata{
+					{
+						Name: "user",
+						Columns: []*storepb.ColumnMetadata{
+							{Name: "id", Type: "integer"},
+							{Name: "email", Type: "varchar(255)", Default: wrapperspb.String("''")},
+							{Name: "created_at", Type: "timestamp", Nullable: true},
+						},
+						Indexes: []*storepb.IndexMetadata{
+							{Name: "user_pkey", Expressions: []string{"id"}, Primary: true, Unique: true},
+							{Name: "idx_email", Expressions: []string{"email"}, Unique: true},
+						},
+					},
+					{Name: "audit", Columns: []*storepb.ColumnMetadata{{Name: "id", Type: "bigint"}}},
+				},
+				Views: []*storepb.ViewMetadata{{Name: "active_user", Definition: " SELECT * FROM user;"}},
+			},
+			{Name: "report"},
+		},
+	}
+	target := &storepb.DatabaseMetadata{
+		Schemas: []*storepb.SchemaMetadata{
+			{
+				Name: "public",
+				Tables: []*storepb.TableMetadata{
+					{
+						Name:    "user",
+						Comment: "users",
+						Columns: []*storepb.ColumnMetadata{
+							{Name: "id", Type: "integer"},
+							{Name: "email", Type: "varchar(64)", Default: wrapperspb.String("''")},
+							{Name: "legacy", Type: "text", Nullable: true},
+						},
+						Indexes: []*storepb.IndexMetadata{
+							{Name: "user_pkey", Expressions: []string{"id"}, Primary: true, Unique: true},
+						},
+					},
+				},
+				Views: []*storepb.ViewMetadata{{Name: "active_user", Definition: "SELECT * FROM user;"}},
+			},
+			{Name: "archive"},
+		},
+	}
+
+	require.Equal(t, []*api.SchemaObjectDiff{
+		{Type: api.SchemaObjectTable, Action: api.SchemaObjectDiffAlter, Schema: "public", Name: "user", Source: "", Target: "COMMENT 'users'"},
+		{Type: api.SchemaObjectTable, Action: api.SchemaObjectDiffCreate, Schema: "public", Name: "audit"},
+		{Type: api.SchemaObjectColumn, Action: api.SchemaObjectDiffAlter, Schema: "public", Table: "user", Name: "email", Source: "varchar(255) NOT NULL DEFAULT ''", Target: "varchar(64) NOT NULL DEFAULT ''"},
+		{Type: api.SchemaObjectColumn, Action: api.SchemaObjectDiffCreate, Schema: "public", Table: "user", Name: "created_at", Source: "timestamp"},
+		{Type: api.SchemaObjectColumn, Action: api.SchemaObjectDiffDrop, Schema: "public", Table: "user", Name: "legacy", Target: "text"},
+		{Type: api.SchemaObjectIndex, Action: api.SchemaObjectDiffCreate, Schema: "public", Table: "user", Name: "idx_email", Source: "UNIQUE INDEX (email)"},
+		{Type: api.SchemaObjectSchema, Action: api.SchemaObjectDiffCreate, Schema: "report", Name: "report", Source: "report"},
+		{Type: api.SchemaObjectSchema, Action: api.SchemaObjectDiffDrop, Schema: "archive", Name: "archive", Target: "archive"},
+	}, CompareSchemaMetadata(source, target))
+
+	require.Empty(t, CompareSchemaMetadata(source, source))
+}
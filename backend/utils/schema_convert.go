@@ -0,0 +1,703 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	tidbparser "github.com/pingcap/tidb/parser"
+	tidbast "github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+var (
+	// The TiDB parser doesn't support the spatial types and indexes, they are rewritten before parsing.
+	spatialColumnReg = regexp.MustCompile("(?i)([(,]\\s*)(`(?:[^`]|``)+`|\\w+)\\s+(geometrycollection|geomcollection|multilinestring|multipolygon|multipoint|linestring|polygon|geometry|point)\\b")
+	// The SRID attribute of the spatial columns is usually in the version comment, such as "/*!80003 SRID 4326 */".
+	sridReg          = regexp.MustCompile(`(?i)/\*!\d+\s+SRID\s+(\d+)\s*\*/|\bSRID\s+(\d+)`)
+	spatialIndexReg  = regexp.MustCompile("(?i),\\s*SPATIAL\\s+(?:KEY|INDEX)\\s*(`(?:[^`]|``)+`|\\w+)?\\s*\\([^)]*\\)")
+	storedProgramReg = regexp.MustCompile(`(?i)\b(PROCEDURE|FUNCTION|TRIGGER|EVENT)\b`)
+
+	// tidbCharsets is the character sets supported by TiDB.
+	tidbCharsets = map[string]bool{"utf8mb4": true, "utf8": true, "ascii": true, "latin1": true, "binary": true, "gbk": true}
+	// tidbCollations is the collations supported by TiDB with the new collation framework.
+	tidbCollations = map[string]bool{
+		"utf8mb4_bin": true, "utf8mb4_general_ci": true, "utf8mb4_unicode_ci": true, "utf8mb4_0900_bin": true, "utf8mb4_0900_ai_ci": true,
+		"utf8_bin": true, "utf8_general_ci": true, "utf8_unicode_ci": true,
+		"ascii_bin": true, "latin1_bin": true, "binary": true, "gbk_bin": true, "gbk_chinese_ci": true,
+	}
+	// postgresSpatialTypes maps the MySQL spatial types to the PostGIS geometry subtypes.
+	postgresSpatialTypes = map[string]string{
+		"geometry":           "",
+		"point":              "Point",
+		"linestring":         "LineString",
+		"polygon":            "Polygon",
+		"multipoint":         "MultiPoint",
+		"multilinestring":    "MultiLineString",
+		"multipolygon":       "MultiPolygon",
+		"geometrycollection": "GeometryCollection",
+		"geomcollection":     "GeometryCollection",
+	}
+)
+
+// spatialColumn is the spatial column rewritten to the longblob column before parsing.
+type spatialColumn struct {
+	tp   string
+	srid string
+}
+
+type mysqlSchemaConverter struct {
+	target   db.Type
+	mappings []*api.SchemaLossyMapping
+	// indexNames is the set of the converted index names, the index names are unique in the schema in PostgreSQL.
+	indexNames map[string]bool
+	viewList   []string
+	viewMap    map[string]string
+}
+
+// ConvertMySQLSchema converts the MySQL schema to the schema of the target engine, only PostgreSQL and TiDB are supported now.
+// The definitions that cannot be converted without loss are returned as the lossy mappings, so that they can be reviewed
+// before the engine migration. The statements other than CREATE TABLE and CREATE VIEW are skipped and reported as well.
+func ConvertMySQLSchema(target db.Type, schema string) (string, []*api.SchemaLossyMapping, error) {
+	if target != db.Postgres && target != db.TiDB {
+		return "", nil, errors.Errorf("converting MySQL schema to %q is not supported", target)
+	}
+	singleSQLs, err := parser.SplitMultiSQL(parser.MySQL, schema)
+	if err != nil {
+		return "", nil, err
+	}
+	c := &mysqlSchemaConverter{
+		target:     target,
+		mappings:   []*api.SchemaLossyMapping{},
+		indexNames: make(map[string]bool),
+		viewMap:    make(map[string]string),
+	}
+	p := tidbparser.New()
+	var buf strings.Builder
+	for _, singleSQL := range singleSQLs {
+		if singleSQL.Empty {
+			continue
+		}
+		// The DELIMITER commands of the dump are handled by the splitter.
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(singleSQL.Text)), "DELIMITER") {
+			continue
+		}
+		text, spatialColumns, spatialIndexes := rewriteSpatialDefinitions(singleSQL.Text)
+		node, err := p.ParseOneStmt(text, "", "")
+		if err != nil {
+			if storedProgramReg.MatchString(singleSQL.Text) {
+				c.report("", "", abbreviateStatement(singleSQL.Text), "", fmt.Sprintf("The stored programs are not converted, rewrite them for %s manually.", c.targetName()))
+				continue
+			}
+			c.report("", "", abbreviateStatement(singleSQL.Text), "", fmt.Sprintf("Failed to parse the statement at line %d: %v.", singleSQL.LastLine, err))
+			continue
+		}
+		switch node := node.(type) {
+		case *tidbast.CreateTableStmt:
+			if node.Select != nil || node.ReferTable != nil {
+				c.report(node.Table.Name.O, "", abbreviateStatement(singleSQL.Text), "", "CREATE TABLE ... SELECT and CREATE TABLE ... LIKE are not converted.")
+				continue
+			}
+			for _, name := range spatialIndexes {
+				c.reportSpatialIndex(node.Table.Name.O, name)
+			}
+			var statement string
+			if target == db.Postgres {
+				statement, err = c.convertPostgresTable(node, spatialColumns)
+			} else {
+				statement, err = c.convertTiDBTable(node, spatialColumns)
+			}
+			if err != nil {
+				return "", nil, errors.Wrapf(err, "failed to convert table %q", node.Table.Name.O)
+			}
+			_, _ = buf.WriteString(statement)
+		case *tidbast.CreateViewStmt:
+			c.convertView(node)
+		case *tidbast.SetStmt, *tidbast.UseStmt, *tidbast.DropTableStmt:
+			// The session settings and the cleanups of the dump are not part of the schema.
+		default:
+			c.report("", "", abbreviateStatement(singleSQL.Text), "", "Only CREATE TABLE and CREATE VIEW statements are converted.")
+		}
+	}
+	// The dump creates the placeholder views before the real ones, so the last definition of the view wins.
+	for _, name := range c.viewList {
+		_, _ = buf.WriteString(c.viewMap[name])
+	}
+	return buf.String(), c.mappings, nil
+}
+
+func (c *mysqlSchemaConverter) report(table, column, source, target, message string) {
+	c.mappings = append(c.mappings, &api.SchemaLossyMapping{
+		Table:   table,
+		Column:  column,
+		Source:  source,
+		Target:  target,
+		Message: message,
+	})
+}
+
+func (c *mysqlSchemaConverter) targetName() string {
+	if c.target == db.Postgres {
+		return "PostgreSQL"
+	}
+	return "TiDB"
+}
+
+func (c *mysqlSchemaConverter) reportSpatialIndex(table, name string) {
+	if c.target == db.Postgres {
+		c.report(table, name, "SPATIAL INDEX", "", "The SPATIAL index is dropped, create a GiST index with PostGIS instead.")
+		return
+	}
+	c.report(table, name, "SPATIAL INDEX", "", "TiDB doesn't support SPATIAL indexes, the index is dropped.")
+}
+
+func (c *mysqlSchemaConverter) convertView(node *tidbast.CreateViewStmt) {
+	name := node.ViewName.Name.O
+	if c.target == db.Postgres {
+		if _, ok := c.viewMap[name]; !ok {
+			c.viewList = append(c.viewList, name)
+			c.report(name, "", "VIEW", "", "The views are not converted, rewrite the view definition for PostgreSQL manually.")
+		}
+		c.viewMap[name] = ""
+		return
+	}
+	var buf strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags|format.RestoreStringWithoutCharset, &buf)); err != nil {
+		c.report(name, "", "VIEW", "", fmt.Sprintf("Failed to restore the view definition: %v.", err))
+		return
+	}
+	if _, ok := c.viewMap[name]; !ok {
+		c.viewList = append(c.viewList, name)
+	}
+	c.viewMap[name] = buf.String() + ";\n\n"
+}
+
+// rewriteSpatialDefinitions rewrites the spatial columns to the longblob columns and removes the SPATIAL indexes,
+// it returns the rewritten statement, the spatial columns and the names of the removed indexes.
+func rewriteSpatialDefinitions(statement string) (string, map[string]*spatialColumn, []string) {
+	columns := make(map[string]*spatialColumn)
+	var buf strings.Builder
+	last := 0
+	for _, match := range spatialColumnReg.FindAllStringSubmatchIndex(statement, -1) {
+		column := &spatialColumn{tp: strings.ToLower(statement[match[6]:match[7]])}
+		// The SRID attribute belongs to the column if it's before the next column definition.
+		rest := statement[match[1]:]
+		if i := strings.IndexAny(rest, ",\n"); i >= 0 {
+			rest = rest[:i]
+		}
+		if srid := sridReg.FindStringSubmatch(rest); srid != nil {
+			column.srid = srid[1] + srid[2]
+		}
+		columns[unquoteMySQLIdentifier(statement[match[4]:match[5]])] = column
+		_, _ = buf.WriteString(statement[last:match[0]])
+		_, _ = fmt.Fprintf(&buf, "%s%s longblob", statement[match[2]:match[3]], statement[match[4]:match[5]])
+		last = match[1]
+	}
+	_, _ = buf.WriteString(statement[last:])
+	statement = buf.String()
+	if len(columns) > 0 {
+		statement = sridReg.ReplaceAllString(statement, "")
+	}
+
+	var indexes []string
+	statement = spatialIndexReg.ReplaceAllStringFunc(statement, func(s string) string {
+		match := spatialIndexReg.FindStringSubmatch(s)
+		indexes = append(indexes, unquoteMySQLIdentifier(match[1]))
+		return ""
+	})
+	return statement, columns, indexes
+}
+
+func unquoteMySQLIdentifier(name string) string {
+	if len(name) >= 2 && strings.HasPrefix(name, "`") && strings.HasSuffix(name, "`") {
+		return strings.ReplaceAll(name[1:len(name)-1], "``", "`")
+	}
+	return name
+}
+
+func abbreviateStatement(statement string) string {
+	statement = strings.TrimSpace(statement)
+	if i := strings.IndexByte(statement, '\n'); i >= 0 {
+		statement = statement[:i]
+	}
+	if utf8.RuneCountInString(statement) > 100 {
+		statement = string([]rune(statement)[:100]) + "..."
+	}
+	return statement
+}
+
+func restoreMySQLNode(node tidbast.Node, flags format.RestoreFlags) (string, error) {
+	var buf strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(flags|format.RestoreStringSingleQuotes|format.RestoreKeyWordUppercase|format.RestoreStringWithoutCharset, &buf)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// mysqlColumnType returns the column type in the MySQL format, such as "int(11) unsigned".
+func mysqlColumnType(tp *types.FieldType) string {
+	if tp.GetType() == mysql.TypeYear {
+		return "year"
+	}
+	return tp.InfoSchemaStr()
+}
+
+func (c *mysqlSchemaConverter) convertPostgresTable(node *tidbast.CreateTableStmt, spatialColumns map[string]*spatialColumn) (string, error) {
+	tableName := node.Table.Name.O
+	table := &storepb.TableMetadata{Name: tableName}
+	var comments []string
+	var primaryKeys []string
+	var indexes []*storepb.IndexMetadata
+
+	for _, column := range node.Cols {
+		columnName := column.Name.Name.O
+		autoIncrement := false
+		for _, option := range column.Options {
+			if option.Tp == tidbast.ColumnOptionAutoIncrement {
+				autoIncrement = true
+			}
+		}
+		var columnType string
+		if spatial, ok := spatialColumns[columnName]; ok {
+			columnType = "geometry"
+			if subtype := postgresSpatialTypes[spatial.tp]; subtype != "" || spatial.srid != "" {
+				if subtype == "" {
+					subtype = "Geometry"
+				}
+				if spatial.srid != "" {
+					subtype += ", " + spatial.srid
+				}
+				columnType = fmt.Sprintf("geometry(%s)", subtype)
+			}
+			c.report(tableName, columnName, spatial.tp, columnType, "The spatial types require the PostGIS extension.")
+		} else {
+			columnType = c.postgresColumnType(tableName, columnName, column.Tp, autoIncrement)
+		}
+		if column.Tp.GetCharset() != "" && column.Tp.GetCharset() != "binary" {
+			c.report(tableName, columnName, "CHARACTER SET "+column.Tp.GetCharset(), "", "The column character set is dropped, the database encoding is used.")
+		}
+
+		result := &storepb.ColumnMetadata{
+			Name:     columnName,
+			Type:     columnType,
+			Nullable: true,
+		}
+		for _, option := range column.Options {
+			switch option.Tp {
+			case tidbast.ColumnOptionNotNull:
+				result.Nullable = false
+			case tidbast.ColumnOptionPrimaryKey:
+				result.Nullable = false
+				primaryKeys = append(primaryKeys, columnName)
+			case tidbast.ColumnOptionUniqKey:
+				indexes = append(indexes, &storepb.IndexMetadata{Name: columnName, Expressions: []string{columnName}, Unique: true})
+			case tidbast.ColumnOptionDefaultValue:
+				if autoIncrement {
+					continue
+				}
+				value, err := c.postgresDefault(tableName, columnName, option.Expr)
+				if err != nil {
+					return "", err
+				}
+				if value != "" {
+					result.Default = wrapperspb.String(value)
+				}
+			case tidbast.ColumnOptionOnUpdate:
+				expr, err := restoreMySQLNode(option.Expr, format.RestoreNameBackQuotes)
+				if err != nil {
+					return "", err
+				}
+				c.report(tableName, columnName, "ON UPDATE "+expr, "", "ON UPDATE is dropped, use a trigger to update the column instead.")
+			case tidbast.ColumnOptionComment:
+				if value, ok := option.Expr.(*driver.ValueExpr); ok && value.GetString() != "" {
+					comments = append(comments, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;\n", quotePostgresIdentifier(tableName), quotePostgresIdentifier(columnName), quoteSQLString(value.GetString())))
+				}
+			case tidbast.ColumnOptionCollate:
+				c.report(tableName, columnName, "COLLATE "+option.StrValue, "", "The column collation is dropped, the database collation is used.")
+			case tidbast.ColumnOptionGenerated:
+				expr, err := restoreMySQLNode(option.Expr, format.RestoreNameBackQuotes)
+				if err != nil {
+					return "", err
+				}
+				c.report(tableName, columnName, fmt.Sprintf("GENERATED ALWAYS AS (%s)", expr), "", "The generated column is converted to a regular column.")
+			case tidbast.ColumnOptionReference, tidbast.ColumnOptionCheck:
+				c.report(tableName, columnName, "REFERENCES / CHECK", "", "The inline column constraints are not converted.")
+			}
+		}
+		table.Columns = append(table.Columns, result)
+	}
+
+	for _, constraint := range node.Constraints {
+		switch constraint.Tp {
+		case tidbast.ConstraintPrimaryKey:
+			primaryKeys = append(primaryKeys, c.postgresIndexColumns(tableName, "PRIMARY", constraint.Keys)...)
+		case tidbast.ConstraintKey, tidbast.ConstraintIndex, tidbast.ConstraintUniq, tidbast.ConstraintUniqKey, tidbast.ConstraintUniqIndex:
+			name := constraint.Name
+			if name == "" && len(constraint.Keys) > 0 && constraint.Keys[0].Column != nil {
+				// MySQL names the index after the first column.
+				name = constraint.Keys[0].Column.Name.O
+			}
+			index := &storepb.IndexMetadata{
+				Name:   name,
+				Unique: constraint.Tp == tidbast.ConstraintUniq || constraint.Tp == tidbast.ConstraintUniqKey || constraint.Tp == tidbast.ConstraintUniqIndex,
+			}
+			for _, key := range constraint.Keys {
+				if key.Expr != nil {
+					expr, err := restoreMySQLNode(key.Expr, format.RestoreNameDoubleQuotes)
+					if err != nil {
+						return "", err
+					}
+					c.report(tableName, name, expr, expr, "The index expression is kept as it is, review it for PostgreSQL.")
+					index.Expressions = append(index.Expressions, expr)
+					continue
+				}
+				index.Expressions = append(index.Expressions, c.postgresIndexColumns(tableName, name, []*tidbast.IndexPartSpecification{key})...)
+			}
+			if constraint.Option != nil {
+				if constraint.Option.Visibility == tidbast.IndexVisibilityInvisible {
+					c.report(tableName, name, "INVISIBLE", "", "PostgreSQL doesn't support invisible indexes, the index is visible.")
+				}
+				index.Comment = constraint.Option.Comment
+			}
+			indexes = append(indexes, index)
+		case tidbast.ConstraintFulltext:
+			c.report(tableName, constraint.Name, "FULLTEXT INDEX", "", "The FULLTEXT index is dropped, create a GIN index on to_tsvector() instead.")
+		case tidbast.ConstraintForeignKey:
+			fk := &storepb.ForeignKeyMetadata{
+				Name:            constraint.Name,
+				ReferencedTable: constraint.Refer.Table.Name.O,
+			}
+			for _, key := range constraint.Keys {
+				fk.Columns = append(fk.Columns, key.Column.Name.O)
+			}
+			for _, key := range constraint.Refer.IndexPartSpecifications {
+				fk.ReferencedColumns = append(fk.ReferencedColumns, key.Column.Name.O)
+			}
+			if constraint.Refer.Table.Schema.O != "" {
+				c.report(tableName, constraint.Name, "REFERENCES "+constraint.Refer.Table.Schema.O+"."+fk.ReferencedTable, "REFERENCES "+fk.ReferencedTable, "The cross-database reference is converted to the reference in the same schema.")
+			}
+			if constraint.Refer.OnDelete != nil && constraint.Refer.OnDelete.ReferOpt != tidbast.ReferOptionNoOption {
+				fk.OnDelete = constraint.Refer.OnDelete.ReferOpt.String()
+			}
+			if constraint.Refer.OnUpdate != nil && constraint.Refer.OnUpdate.ReferOpt != tidbast.ReferOptionNoOption {
+				fk.OnUpdate = constraint.Refer.OnUpdate.ReferOpt.String()
+			}
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		case tidbast.ConstraintCheck:
+			c.report(tableName, constraint.Name, "CHECK", "", "The CHECK constraints are not converted, add them for PostgreSQL manually.")
+		}
+	}
+	if len(primaryKeys) > 0 {
+		table.Indexes = append(table.Indexes, &storepb.IndexMetadata{Name: "PRIMARY", Expressions: primaryKeys, Primary: true, Unique: true})
+	}
+	for _, index := range indexes {
+		name := index.Name
+		if c.indexNames[name] {
+			name = fmt.Sprintf("%s_%s", tableName, index.Name)
+			c.report(tableName, index.Name, index.Name, name, "The index names are unique in the schema in PostgreSQL, the index is renamed.")
+		}
+		c.indexNames[name] = true
+		index.Name = name
+		if index.Comment != "" {
+			comments = append(comments, fmt.Sprintf("COMMENT ON INDEX %s IS %s;\n", quotePostgresIdentifier(name), quoteSQLString(index.Comment)))
+		}
+		table.Indexes = append(table.Indexes, index)
+	}
+
+	for _, option := range node.Options {
+		switch option.Tp {
+		case tidbast.TableOptionComment:
+			if option.StrValue != "" {
+				comments = append([]string{fmt.Sprintf("COMMENT ON TABLE %s IS %s;\n", quotePostgresIdentifier(tableName), quoteSQLString(option.StrValue))}, comments...)
+			}
+		case tidbast.TableOptionAutoIncrement:
+			c.report(tableName, "", fmt.Sprintf("AUTO_INCREMENT=%d", option.UintValue), "", "The AUTO_INCREMENT start value is dropped, reset the sequences after migrating the data.")
+		}
+	}
+	if node.Partition != nil {
+		c.report(tableName, "", "PARTITION BY", "", "The partitions are not converted, use the declarative partitioning of PostgreSQL instead.")
+	}
+
+	sdl, err := GenerateSDL(db.Postgres, &storepb.DatabaseMetadata{
+		Schemas: []*storepb.SchemaMetadata{{Name: "public", Tables: []*storepb.TableMetadata{table}}},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(comments) > 0 {
+		sdl += strings.Join(comments, "") + "\n"
+	}
+	return sdl, nil
+}
+
+// postgresIndexColumns returns the columns of the index, the prefix lengths are dropped.
+func (c *mysqlSchemaConverter) postgresIndexColumns(table, index string, keys []*tidbast.IndexPartSpecification) []string {
+	var columns []string
+	for _, key := range keys {
+		if key.Column == nil {
+			continue
+		}
+		column := key.Column.Name.O
+		if key.Length > 0 {
+			c.report(table, index, fmt.Sprintf("%s(%d)", column, key.Length), column, "PostgreSQL doesn't support prefix indexes, the whole column is indexed.")
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// postgresColumnType returns the PostgreSQL type of the MySQL column type, the lossy mappings are reported.
+func (c *mysqlSchemaConverter) postgresColumnType(table, column string, tp *types.FieldType, autoIncrement bool) string {
+	source := mysqlColumnType(tp)
+	unsigned := mysql.HasUnsignedFlag(tp.GetFlag())
+	binary := tp.GetCharset() == "binary"
+	// nonNegative keeps the unsigned constraint of the types without the wider type.
+	nonNegative := func(t string) string {
+		if unsigned {
+			return fmt.Sprintf("%s CHECK (%s >= 0)", t, quotePostgresIdentifier(column))
+		}
+		return t
+	}
+	withPrecision := func(t string) string {
+		if tp.GetDecimal() > 0 {
+			return fmt.Sprintf("%s(%d)", t, tp.GetDecimal())
+		}
+		return t
+	}
+
+	var target string
+	switch tp.GetType() {
+	case mysql.TypeTiny:
+		target = "smallint"
+	case mysql.TypeShort:
+		target = "smallint"
+		if unsigned {
+			target = "integer"
+		}
+	case mysql.TypeInt24:
+		target = "integer"
+	case mysql.TypeLong:
+		target = "integer"
+		if unsigned {
+			target = "bigint"
+		}
+	case mysql.TypeLonglong:
+		target = "bigint"
+		if unsigned {
+			target = "numeric(20)"
+		}
+	case mysql.TypeFloat:
+		target = nonNegative("real")
+	case mysql.TypeDouble:
+		target = nonNegative("double precision")
+	case mysql.TypeNewDecimal:
+		target = "numeric"
+		if tp.GetFlen() > 0 {
+			if tp.GetDecimal() > 0 {
+				target = fmt.Sprintf("numeric(%d,%d)", tp.GetFlen(), tp.GetDecimal())
+			} else {
+				target = fmt.Sprintf("numeric(%d)", tp.GetFlen())
+			}
+		}
+		target = nonNegative(target)
+	case mysql.TypeYear:
+		target = "smallint"
+		c.report(table, column, source, target, "YEAR is converted to smallint without the range check.")
+	case mysql.TypeDate:
+		target = "date"
+	case mysql.TypeDatetime:
+		target = withPrecision("timestamp")
+	case mysql.TypeTimestamp:
+		target = withPrecision("timestamptz")
+	case mysql.TypeDuration:
+		target = withPrecision("time")
+		c.report(table, column, source, target, "The TIME values beyond 24 hours or negative are not supported, use interval for durations.")
+	case mysql.TypeString:
+		if binary {
+			target = "bytea"
+			c.report(table, column, source, target, "BINARY is converted to bytea without the fixed length.")
+		} else if tp.GetFlen() > 0 {
+			target = fmt.Sprintf("char(%d)", tp.GetFlen())
+		} else {
+			target = "char(1)"
+		}
+	case mysql.TypeVarchar, mysql.TypeVarString:
+		if binary {
+			target = "bytea"
+		} else {
+			target = fmt.Sprintf("varchar(%d)", tp.GetFlen())
+		}
+	case mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		target = "text"
+		if binary {
+			target = "bytea"
+		}
+	case mysql.TypeJSON:
+		target = "jsonb"
+	case mysql.TypeEnum:
+		length := 1
+		var values []string
+		for _, elem := range tp.GetElems() {
+			if n := utf8.RuneCountInString(elem); n > length {
+				length = n
+			}
+			values = append(values, quoteSQLString(elem))
+		}
+		target = fmt.Sprintf("varchar(%d) CHECK (%s IN (%s))", length, quotePostgresIdentifier(column), strings.Join(values, ", "))
+		c.report(table, column, source, target, "ENUM is converted to varchar with a CHECK constraint, the values are sorted as strings instead of by the index.")
+	case mysql.TypeSet:
+		target = "text"
+		c.report(table, column, source, target, "SET is converted to text, the values are not validated.")
+	case mysql.TypeBit:
+		flen := tp.GetFlen()
+		if flen <= 0 {
+			flen = 1
+		}
+		target = fmt.Sprintf("bit(%d)", flen)
+	default:
+		target = source
+		c.report(table, column, source, target, "The type is kept as it is, review it for PostgreSQL.")
+	}
+
+	if autoIncrement {
+		switch target {
+		case "smallint":
+			target = "smallserial"
+		case "integer":
+			target = "serial"
+		case "bigint":
+			target = "bigserial"
+		case "numeric(20)":
+			target = "bigserial"
+			c.report(table, column, source+" AUTO_INCREMENT", target, "The values above 9223372036854775807 are out of the range of bigserial.")
+		default:
+			c.report(table, column, source+" AUTO_INCREMENT", target, "AUTO_INCREMENT is only converted for the integer types.")
+		}
+	}
+	return target
+}
+
+// postgresDefault returns the PostgreSQL default value of the MySQL default expression, the empty string means no default.
+func (c *mysqlSchemaConverter) postgresDefault(table, column string, expr tidbast.ExprNode) (string, error) {
+	switch expr := expr.(type) {
+	case *driver.ValueExpr:
+		value, err := restoreMySQLNode(expr, 0)
+		if err != nil {
+			return "", err
+		}
+		if value == "NULL" {
+			return "", nil
+		}
+		if strings.HasPrefix(value, "'0000-00-00") {
+			c.report(table, column, "DEFAULT "+value, "", "PostgreSQL doesn't support the zero dates, the default is dropped.")
+			return "", nil
+		}
+		return value, nil
+	case *tidbast.FuncCallExpr:
+		switch expr.FnName.L {
+		case "current_timestamp", "now", "localtime", "localtimestamp":
+			if len(expr.Args) == 0 {
+				return "CURRENT_TIMESTAMP", nil
+			}
+			precision, err := restoreMySQLNode(expr.Args[0], 0)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("CURRENT_TIMESTAMP(%s)", precision), nil
+		}
+	}
+	value, err := restoreMySQLNode(expr, format.RestoreNameDoubleQuotes)
+	if err != nil {
+		return "", err
+	}
+	c.report(table, column, "DEFAULT "+value, "DEFAULT "+value, "The default expression is kept as it is, review it for PostgreSQL.")
+	return value, nil
+}
+
+func (c *mysqlSchemaConverter) convertTiDBTable(node *tidbast.CreateTableStmt, spatialColumns map[string]*spatialColumn) (string, error) {
+	tableName := node.Table.Name.O
+	for _, column := range node.Cols {
+		columnName := column.Name.Name.O
+		if spatial, ok := spatialColumns[columnName]; ok {
+			c.report(tableName, columnName, spatial.tp, "longblob", "TiDB doesn't support the spatial types, the column is converted to longblob.")
+		}
+		c.normalizeTiDBCharset(tableName, columnName, column.Tp)
+		var options []*tidbast.ColumnOption
+		for _, option := range column.Options {
+			switch option.Tp {
+			case tidbast.ColumnOptionAutoIncrement:
+				c.report(tableName, columnName, "AUTO_INCREMENT", "AUTO_INCREMENT", "TiDB allocates the AUTO_INCREMENT values in batches on each server, the values are unique but not consecutive.")
+			case tidbast.ColumnOptionCollate:
+				if !tidbCollations[strings.ToLower(option.StrValue)] {
+					c.report(tableName, columnName, "COLLATE "+option.StrValue, "", "TiDB doesn't support the collation, the default collation of the character set is used.")
+					continue
+				}
+			}
+			options = append(options, option)
+		}
+		column.Options = options
+	}
+
+	var constraints []*tidbast.Constraint
+	for _, constraint := range node.Constraints {
+		switch constraint.Tp {
+		case tidbast.ConstraintFulltext:
+			c.report(tableName, constraint.Name, "FULLTEXT INDEX", "", "TiDB doesn't support FULLTEXT indexes, the index is dropped.")
+			continue
+		case tidbast.ConstraintForeignKey:
+			c.report(tableName, constraint.Name, "FOREIGN KEY", "FOREIGN KEY", "TiDB enforces the foreign keys since v6.6.0, they are only parsed in the earlier versions.")
+		case tidbast.ConstraintCheck:
+			c.report(tableName, constraint.Name, "CHECK", "CHECK", "TiDB enforces the CHECK constraints since v7.2.0, they are only parsed in the earlier versions.")
+		}
+		constraints = append(constraints, constraint)
+	}
+	node.Constraints = constraints
+
+	var options []*tidbast.TableOption
+	for _, option := range node.Options {
+		switch option.Tp {
+		case tidbast.TableOptionCharset:
+			if !tidbCharsets[strings.ToLower(option.StrValue)] {
+				c.report(tableName, "", "CHARSET="+option.StrValue, "CHARSET=utf8mb4", "TiDB doesn't support the character set, utf8mb4 is used.")
+				option.StrValue = "utf8mb4"
+			}
+		case tidbast.TableOptionCollate:
+			if !tidbCollations[strings.ToLower(option.StrValue)] {
+				c.report(tableName, "", "COLLATE="+option.StrValue, "", "TiDB doesn't support the collation, the default collation of the character set is used.")
+				continue
+			}
+		}
+		options = append(options, option)
+	}
+	node.Options = options
+
+	statement, err := restoreMySQLNode(node, format.RestoreNameBackQuotes)
+	if err != nil {
+		return "", err
+	}
+	return statement + ";\n\n", nil
+}
+
+// normalizeTiDBCharset replaces the character set and the collation of the column unsupported by TiDB.
+func (c *mysqlSchemaConverter) normalizeTiDBCharset(table, column string, tp *types.FieldType) {
+	if charset := tp.GetCharset(); charset != "" && !tidbCharsets[strings.ToLower(charset)] {
+		c.report(table, column, "CHARACTER SET "+charset, "CHARACTER SET utf8mb4", "TiDB doesn't support the character set, utf8mb4 is used.")
+		tp.SetCharset("utf8mb4")
+		tp.SetCollate("")
+	}
+	if collate := tp.GetCollate(); collate != "" && !tidbCollations[strings.ToLower(collate)] {
+		c.report(table, column, "COLLATE "+collate, "", "TiDB doesn't support the collation, the default collation of the character set is used.")
+		tp.SetCollate("")
+	}
+}
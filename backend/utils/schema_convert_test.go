@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+const testConvertMySQLSchema = "SET character_set_client = utf8mb4;\n" +
+	"CREATE TABLE `user` (\n" +
+	"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+	"  `status` enum('active','banned') NOT NULL DEFAULT 'active' COMMENT 'user status',\n" +
+	"  `location` point NOT NULL /*!80003 SRID 4326 */,\n" +
+	"  `updated_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  KEY `idx_status` (`status`(3)),\n" +
+	"  SPATIAL KEY `idx_location` (`location`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='users';\n" +
+	"CREATE TABLE `post` (\n" +
+	"  `id` int NOT NULL AUTO_INCREMENT,\n" +
+	"  `user_id` bigint unsigned NOT NULL,\n" +
+	"  `score` decimal(5,2) unsigned DEFAULT '0.00',\n" +
+	"  `title` varchar(20) COLLATE latin1_swedish_ci,\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  KEY `idx_status` (`title`),\n" +
+	"  FULLTEXT KEY `idx_title` (`title`),\n" +
+	"  CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE\n" +
+	");\n" +
+	"DELIMITER ;;\n" +
+	"CREATE PROCEDURE p() BEGIN SELECT 1; END;;\n" +
+	"DELIMITER ;\n"
+
+func TestConvertMySQLSchemaToPostgres(t *testing.T) {
+	schema, mappings, err := ConvertMySQLSchema(db.Postgres, testConvertMySQLSchema)
+	require.NoError(t, err)
+	require.Equal(t, `CREATE TABLE "user" (
+  "id" bigserial NOT NULL,
+  "status" varchar(6) CHECK ("status" IN ('active', 'banned')) NOT NULL DEFAULT 'active',
+  "location" geometry(Point, 4326) NOT NULL,
+  "updated_at" timestamp(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX "idx_status" ON "user" ("status");
+
+COMMENT ON TABLE "user" IS 'users';
+COMMENT ON COLUMN "user"."status" IS 'user status';
+
+CREATE TABLE "post" (
+  "id" serial NOT NULL,
+  "user_id" numeric(20) NOT NULL,
+  "score" numeric(5,2) CHECK ("score" >= 0) DEFAULT '0.00',
+  "title" varchar(20),
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_user" FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "post_idx_status" ON "post" ("title");
+
+`, schema)
+
+	var sources []string
+	for _, mapping := range mappings {
+		sources = append(sources, mapping.Table+"."+mapping.Column+": "+mapping.Source)
+	}
+	require.Equal(t, []string{
+		"user.idx_location: SPATIAL INDEX",
+		"user.id: bigint(20) unsigned AUTO_INCREMENT",
+		"user.status: enum('active','banned')",
+		"user.location: point",
+		"user.updated_at: ON UPDATE CURRENT_TIMESTAMP(3)",
+		"user.idx_status: status(3)",
+		"post.title: COLLATE latin1_swedish_ci",
+		"post.idx_title: FULLTEXT INDEX",
+		"post.idx_status: idx_status",
+		".: CREATE PROCEDURE p() BEGIN SELECT 1; END;;",
+	}, sources)
+}
+
+func TestConvertMySQLSchemaToTiDB(t *testing.T) {
+	schema, mappings, err := ConvertMySQLSchema(db.TiDB, testConvertMySQLSchema)
+	require.NoError(t, err)
+	require.Equal(t, "CREATE TABLE `user` (`id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,`status` ENUM('active','banned') NOT NULL DEFAULT 'active' COMMENT 'user status',`location` LONGBLOB NOT NULL,`updated_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),PRIMARY KEY(`id`),INDEX `idx_status`(`status`(3))) ENGINE = InnoDB DEFAULT CHARACTER SET = UTF8MB4 COMMENT = 'users';\n\n"+
+		"CREATE TABLE `post` (`id` INT NOT NULL AUTO_INCREMENT,`user_id` BIGINT UNSIGNED NOT NULL,`score` DECIMAL(5,2) UNSIGNED DEFAULT '0.00',`title` VARCHAR(20),PRIMARY KEY(`id`),INDEX `idx_status`(`title`),CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `user`(`id`) ON DELETE CASCADE);\n\n", schema)
+	require.Contains(t, mappings, &api.SchemaLossyMapping{
+		Table:   "user",
+		Column:  "location",
+		Source:  "point",
+		Target:  "longblob",
+		Message: "TiDB doesn't support the spatial types, the column is converted to longblob.",
+	})
+	require.Contains(t, mappings, &api.SchemaLossyMapping{
+		Table:   "post",
+		Column:  "title",
+		Source:  "COLLATE latin1_swedish_ci",
+		Message: "TiDB doesn't support the collation, the default collation of the character set is used.",
+	})
+
+	_, _, err = ConvertMySQLSchema(db.Oracle, testConvertMySQLSchema)
+	require.EqualError(t, err, `converting MySQL schema to "ORACLE" is not supported`)
+}
@@ -0,0 +1,374 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// SchemaExportFormat is the format of the exported schema diagram.
+type SchemaExportFormat string
+
+const (
+	// SchemaExportFormatDBML is the DBML (database markup language) format.
+	SchemaExportFormatDBML SchemaExportFormat = "dbml"
+	// SchemaExportFormatMermaid is the Mermaid ER diagram format.
+	SchemaExportFormatMermaid SchemaExportFormat = "mermaid"
+	// SchemaExportFormatPlantUML is the PlantUML ER diagram format.
+	SchemaExportFormatPlantUML SchemaExportFormat = "plantuml"
+)
+
+// exportRelationship is the relationship between two tables, it comes from a foreign key or is inferred from the column name.
+type exportRelationship struct {
+	name              string
+	schema            string
+	table             string
+	columns           []string
+	referencedSchema  string
+	referencedTable   string
+	referencedColumns []string
+	inferred          bool
+}
+
+var nonIdentifierCharRegexp = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// ExportSchemaDiagram renders the database metadata as the schema diagram in the given format.
+// Besides the foreign keys, the relationships are inferred from the naming convention that the column "<table>_id"
+// refers to the "id" column of the table named "<table>" or its plural form.
+func ExportSchemaDiagram(metadata *storepb.DatabaseMetadata, format SchemaExportFormat) (string, error) {
+	relationships := getExportRelationships(metadata)
+	switch format {
+	case SchemaExportFormatDBML:
+		return exportDBML(metadata, relationships), nil
+	case SchemaExportFormatMermaid:
+		return exportMermaid(metadata, relationships), nil
+	case SchemaExportFormatPlantUML:
+		return exportPlantUML(metadata, relationships), nil
+	default:
+		return "", errors.Errorf("unsupported schema export format %q", format)
+	}
+}
+
+func getExportRelationships(metadata *storepb.DatabaseMetadata) []*exportRelationship {
+	var result []*exportRelationship
+	for _, schema := range metadata.Schemas {
+		for _, table := range schema.Tables {
+			// referencingColumns are the columns in the foreign keys, we don't infer relationships for them.
+			referencingColumns := make(map[string]bool)
+			for _, fk := range table.ForeignKeys {
+				for _, column := range fk.Columns {
+					referencingColumns[column] = true
+				}
+				referencedSchema := fk.ReferencedSchema
+				if referencedSchema == "" {
+					referencedSchema = schema.Name
+				}
+				result = append(result, &exportRelationship{
+					name:              fk.Name,
+					schema:            schema.Name,
+					table:             table.Name,
+					columns:           fk.Columns,
+					referencedSchema:  referencedSchema,
+					referencedTable:   fk.ReferencedTable,
+					referencedColumns: fk.ReferencedColumns,
+				})
+			}
+			for _, column := range table.Columns {
+				if referencingColumns[column.Name] {
+					continue
+				}
+				referencedSchema, referencedTable, referencedColumn, ok := inferReferencedTable(metadata, schema, table, column.Name)
+				if !ok {
+					continue
+				}
+				result = append(result, &exportRelationship{
+					schema:            schema.Name,
+					table:             table.Name,
+					columns:           []string{column.Name},
+					referencedSchema:  referencedSchema,
+					referencedTable:   referencedTable,
+					referencedColumns: []string{referencedColumn},
+					inferred:          true,
+				})
+			}
+		}
+	}
+	return result
+}
+
+// inferReferencedTable finds the table referenced by the column named "<table>_id", the tables in the same schema are preferred.
+func inferReferencedTable(metadata *storepb.DatabaseMetadata, schema *storepb.SchemaMetadata, table *storepb.TableMetadata, columnName string) (string, string, string, bool) {
+	lowerName := strings.ToLower(columnName)
+	if !strings.HasSuffix(lowerName, "_id") || len(lowerName) == len("_id") {
+		return "", "", "", false
+	}
+	prefix := strings.TrimSuffix(lowerName, "_id")
+	candidates := map[string]bool{
+		prefix:        true,
+		prefix + "s":  true,
+		prefix + "es": true,
+	}
+	if strings.HasSuffix(prefix, "y") {
+		candidates[strings.TrimSuffix(prefix, "y")+"ies"] = true
+	}
+
+	schemaList := []*storepb.SchemaMetadata{schema}
+	for _, s := range metadata.Schemas {
+		if s != schema {
+			schemaList = append(schemaList, s)
+		}
+	}
+	for _, s := range schemaList {
+		for _, t := range s.Tables {
+			if t == table || !candidates[strings.ToLower(t.Name)] {
+				continue
+			}
+			for _, column := range t.Columns {
+				if strings.ToLower(column.Name) == "id" {
+					return s.Name, t.Name, column.Name, true
+				}
+			}
+		}
+	}
+	return "", "", "", false
+}
+
+func getPrimaryKeyColumns(table *storepb.TableMetadata) map[string]bool {
+	result := make(map[string]bool)
+	for _, index := range table.Indexes {
+		if !index.Primary {
+			continue
+		}
+		for _, expression := range index.Expressions {
+			result[expression] = true
+		}
+	}
+	return result
+}
+
+func getReferencingColumns(schema *storepb.SchemaMetadata, table *storepb.TableMetadata, relationships []*exportRelationship) map[string]bool {
+	result := make(map[string]bool)
+	for _, relationship := range relationships {
+		if relationship.schema != schema.Name || relationship.table != table.Name {
+			continue
+		}
+		for _, column := range relationship.columns {
+			result[column] = true
+		}
+	}
+	return result
+}
+
+func exportDBML(metadata *storepb.DatabaseMetadata, relationships []*exportRelationship) string {
+	quote := func(name string) string {
+		return fmt.Sprintf("%q", name)
+	}
+	tableName := func(schema, table string) string {
+		if schema == "" {
+			return quote(table)
+		}
+		return fmt.Sprintf("%s.%s", quote(schema), quote(table))
+	}
+	columnList := func(columns []string) string {
+		if len(columns) == 1 {
+			return quote(columns[0])
+		}
+		var quoted []string
+		for _, column := range columns {
+			quoted = append(quoted, quote(column))
+		}
+		return fmt.Sprintf("(%s)", strings.Join(quoted, ", "))
+	}
+	escape := func(text string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(text, `\`, `\\`), "'", `\'`)
+	}
+
+	var buf strings.Builder
+	_, _ = fmt.Fprintf(&buf, "Project %s {\n  Note: 'Exported from Bytebase'\n}\n", quote(metadata.Name))
+	for _, schema := range metadata.Schemas {
+		for _, table := range schema.Tables {
+			primaryKeyColumns := getPrimaryKeyColumns(table)
+			_, _ = fmt.Fprintf(&buf, "\nTable %s {\n", tableName(schema.Name, table.Name))
+			for _, column := range table.Columns {
+				var settings []string
+				if primaryKeyColumns[column.Name] {
+					settings = append(settings, "pk")
+				}
+				if !column.Nullable {
+					settings = append(settings, "not null")
+				}
+				if column.Default != nil {
+					settings = append(settings, fmt.Sprintf("default: `%s`", strings.ReplaceAll(column.Default.Value, "`", "'")))
+				}
+				if column.Comment != "" {
+					settings = append(settings, fmt.Sprintf("note: '%s'", escape(column.Comment)))
+				}
+				_, _ = fmt.Fprintf(&buf, "  %s %s", quote(column.Name), quote(column.Type))
+				if len(settings) > 0 {
+					_, _ = fmt.Fprintf(&buf, " [%s]", strings.Join(settings, ", "))
+				}
+				_, _ = buf.WriteString("\n")
+			}
+			if table.Comment != "" {
+				_, _ = fmt.Fprintf(&buf, "  Note: '%s'\n", escape(table.Comment))
+			}
+			_, _ = buf.WriteString("}\n")
+		}
+	}
+	if len(relationships) > 0 {
+		_, _ = buf.WriteString("\n")
+	}
+	for _, relationship := range relationships {
+		if relationship.inferred {
+			_, _ = buf.WriteString("// Inferred from the column name.\n")
+		}
+		name := ""
+		if relationship.name != "" {
+			name = " " + quote(relationship.name)
+		}
+		_, _ = fmt.Fprintf(&buf, "Ref%s: %s.%s > %s.%s\n",
+			name,
+			tableName(relationship.schema, relationship.table),
+			columnList(relationship.columns),
+			tableName(relationship.referencedSchema, relationship.referencedTable),
+			columnList(relationship.referencedColumns),
+		)
+	}
+	return buf.String()
+}
+
+func exportMermaid(metadata *storepb.DatabaseMetadata, relationships []*exportRelationship) string {
+	entityName := func(schema, table string) string {
+		if schema == "" {
+			return fmt.Sprintf("%q", table)
+		}
+		return fmt.Sprintf("%q", schema+"."+table)
+	}
+	var buf strings.Builder
+	_, _ = buf.WriteString("erDiagram\n")
+	for _, schema := range metadata.Schemas {
+		for _, table := range schema.Tables {
+			primaryKeyColumns := getPrimaryKeyColumns(table)
+			referencingColumns := getReferencingColumns(schema, table, relationships)
+			_, _ = fmt.Fprintf(&buf, "  %s {\n", entityName(schema.Name, table.Name))
+			for _, column := range table.Columns {
+				// The attribute type and name in Mermaid must be words.
+				columnType := exportIdentifier(column.Type)
+				if columnType == "" {
+					columnType = "unknown"
+				}
+				_, _ = fmt.Fprintf(&buf, "    %s %s", columnType, exportIdentifier(column.Name))
+				var keys []string
+				if primaryKeyColumns[column.Name] {
+					keys = append(keys, "PK")
+				}
+				if referencingColumns[column.Name] {
+					keys = append(keys, "FK")
+				}
+				if len(keys) > 0 {
+					_, _ = fmt.Fprintf(&buf, " %s", strings.Join(keys, ", "))
+				}
+				if column.Comment != "" {
+					_, _ = fmt.Fprintf(&buf, " %q", strings.ReplaceAll(column.Comment, `"`, "'"))
+				}
+				_, _ = buf.WriteString("\n")
+			}
+			_, _ = buf.WriteString("  }\n")
+		}
+	}
+	for _, relationship := range relationships {
+		// The solid line is for the foreign key, and the dashed line is for the inferred relationship.
+		line := "--"
+		label := relationship.name
+		if relationship.inferred {
+			line = ".."
+			label = "inferred"
+		}
+		_, _ = fmt.Fprintf(&buf, "  %s ||%so{ %s : %q\n",
+			entityName(relationship.referencedSchema, relationship.referencedTable),
+			line,
+			entityName(relationship.schema, relationship.table),
+			label,
+		)
+	}
+	return buf.String()
+}
+
+func exportPlantUML(metadata *storepb.DatabaseMetadata, relationships []*exportRelationship) string {
+	alias := func(schema, table string) string {
+		if schema == "" {
+			return exportIdentifier("t_" + table)
+		}
+		return exportIdentifier("t_" + schema + "_" + table)
+	}
+	displayName := func(schema, table string) string {
+		if schema == "" {
+			return table
+		}
+		return schema + "." + table
+	}
+
+	var buf strings.Builder
+	_, _ = buf.WriteString("@startuml\n")
+	_, _ = buf.WriteString("hide circle\nskinparam linetype ortho\n")
+	for _, schema := range metadata.Schemas {
+		for _, table := range schema.Tables {
+			primaryKeyColumns := getPrimaryKeyColumns(table)
+			referencingColumns := getReferencingColumns(schema, table, relationships)
+			_, _ = fmt.Fprintf(&buf, "\nentity %q as %s {\n", displayName(schema.Name, table.Name), alias(schema.Name, table.Name))
+			var keyLines, lines []string
+			for _, column := range table.Columns {
+				// The asterisk marks the mandatory column.
+				line := "  "
+				if !column.Nullable {
+					line = "  * "
+				}
+				line += fmt.Sprintf("%s : %s", column.Name, column.Type)
+				if referencingColumns[column.Name] {
+					line += " <<FK>>"
+				}
+				if primaryKeyColumns[column.Name] {
+					keyLines = append(keyLines, line)
+				} else {
+					lines = append(lines, line)
+				}
+			}
+			// The primary key columns are above the separator.
+			for _, line := range keyLines {
+				_, _ = buf.WriteString(line + "\n")
+			}
+			_, _ = buf.WriteString("  --\n")
+			for _, line := range lines {
+				_, _ = buf.WriteString(line + "\n")
+			}
+			_, _ = buf.WriteString("}\n")
+		}
+	}
+	if len(relationships) > 0 {
+		_, _ = buf.WriteString("\n")
+	}
+	for _, relationship := range relationships {
+		line := "--"
+		label := relationship.name
+		if relationship.inferred {
+			line = ".."
+			label = "inferred"
+		}
+		_, _ = fmt.Fprintf(&buf, "%s ||%so{ %s", alias(relationship.referencedSchema, relationship.referencedTable), line, alias(relationship.schema, relationship.table))
+		if label != "" {
+			_, _ = fmt.Fprintf(&buf, " : %s", label)
+		}
+		_, _ = buf.WriteString("\n")
+	}
+	_, _ = buf.WriteString("@enduml\n")
+	return buf.String()
+}
+
+// exportIdentifier replaces the characters other than the letters, digits and underscores with underscores.
+func exportIdentifier(text string) string {
+	return strings.Trim(nonIdentifierCharRegexp.ReplaceAllString(text, "_"), "_")
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func TestExportSchemaDiagram(t *testing.T) {
+	metadata := &storepb.DatabaseMetadata{
+		Name: "shop",
+		Schemas: []*storepb.SchemaMetadata{
+			{
+				Name: "public",
+				Tables: []*storepb.TableMetadata{
+					{
+						Name: "users",
+						Columns: []*storepb.ColumnMetadata{
+							{Name: "id", Type: "integer"},
+							{Name: "name", Type: "character varying(20)", Nullable: true, Comment: "The user's name"},
+						},
+						Indexes: []*storepb.IndexMetadata{
+							{Name: "users_pkey", Expressions: []string{"id"}, Primary: true},
+						},
+					},
+					{
+						Name: "orders",
+						Columns: []*storepb.ColumnMetadata{
+							{Name: "id", Type: "integer"},
+							{Name: "user_id", Type: "integer"},
+							{Name: "category_id", Type: "integer", Nullable: true, Default: wrapperspb.String("0")},
+						},
+						Indexes: []*storepb.IndexMetadata{
+							{Name: "orders_pkey", Expressions: []string{"id"}, Primary: true},
+						},
+						ForeignKeys: []*storepb.ForeignKeyMetadata{
+							{Name: "fk_user", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+						},
+					},
+					{
+						Name: "categories",
+						Columns: []*storepb.ColumnMetadata{
+							{Name: "id", Type: "integer"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		format SchemaExportFormat
+		want   string
+	}{
+		{
+			format: SchemaExportFormatDBML,
+			want: "Project \"shop\" {\n  Note: 'Exported from Bytebase'\n}\n" +
+				"\nTable \"public\".\"users\" {\n" +
+				"  \"id\" \"integer\" [pk, not null]\n" +
+				"  \"name\" \"character varying(20)\" [note: 'The user\\'s name']\n" +
+				"}\n" +
+				"\nTable \"public\".\"orders\" {\n" +
+				"  \"id\" \"integer\" [pk, not null]\n" +
+				"  \"user_id\" \"integer\" [not null]\n" +
+				"  \"category_id\" \"integer\" [default: `0`]\n" +
+				"}\n" +
+				"\nTable \"public\".\"categories\" {\n" +
+				"  \"id\" \"integer\" [not null]\n" +
+				"}\n" +
+				"\nRef \"fk_user\": \"public\".\"orders\".\"user_id\" > \"public\".\"users\".\"id\"\n" +
+				"// Inferred from the column name.\n" +
+				"Ref: \"public\".\"orders\".\"category_id\" > \"public\".\"categories\".\"id\"\n",
+		},
+		{
+			format: SchemaExportFormatMermaid,
+			want: "erDiagram\n" +
+				"  \"public.users\" {\n" +
+				"    integer id PK\n" +
+				"    character_varying_20 name \"The user's name\"\n" +
+				"  }\n" +
+				"  \"public.orders\" {\n" +
+				"    integer id PK\n" +
+				"    integer user_id FK\n" +
+				"    integer category_id FK\n" +
+				"  }\n" +
+				"  \"public.categories\" {\n" +
+				"    integer id\n" +
+				"  }\n" +
+				"  \"public.users\" ||--o{ \"public.orders\" : \"fk_user\"\n" +
+				"  \"public.categories\" ||..o{ \"public.orders\" : \"inferred\"\n",
+		},
+		{
+			format: SchemaExportFormatPlantUML,
+			want: "@startuml\nhide circle\nskinparam linetype ortho\n" +
+				"\nentity \"public.users\" as t_public_users {\n" +
+				"  * id : integer\n" +
+				"  --\n" +
+				"  name : character varying(20)\n" +
+				"}\n" +
+				"\nentity \"public.orders\" as t_public_orders {\n" +
+				"  * id : integer\n" +
+				"  --\n" +
+				"  * user_id : integer <<FK>>\n" +
+				"  category_id : integer <<FK>>\n" +
+				"}\n" +
+				"\nentity \"public.categories\" as t_public_categories {\n" +
+				"  --\n" +
+				"  * id : integer\n" +
+				"}\n" +
+				"\nt_public_users ||--o{ t_public_orders : fk_user\n" +
+				"t_public_categories ||..o{ t_public_orders : inferred\n" +
+				"@enduml\n",
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := ExportSchemaDiagram(metadata, test.format)
+		a.NoError(err)
+		a.Equal(test.want, got, test.format)
+	}
+
+	_, err := ExportSchemaDiagram(metadata, "svg")
+	a.Error(err)
+}
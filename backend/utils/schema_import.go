@@ -0,0 +1,1063 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+type dbmlTokenType int
+
+const (
+	dbmlTokenWord dbmlTokenType = iota
+	// dbmlTokenQuoted is the double-quoted identifier.
+	dbmlTokenQuoted
+	// dbmlTokenString is the single-quoted string.
+	dbmlTokenString
+	// dbmlTokenExpression is the backtick-quoted expression.
+	dbmlTokenExpression
+	dbmlTokenSymbol
+	dbmlTokenNewline
+)
+
+type dbmlToken struct {
+	tp   dbmlTokenType
+	text string
+	line int
+}
+
+// dbmlRef is a relationship between the columns, the "many" side references the "one" side.
+type dbmlRef struct {
+	name     string
+	from     *dbmlEndpoint
+	to       *dbmlEndpoint
+	onDelete string
+	onUpdate string
+}
+
+type dbmlEndpoint struct {
+	schema  string
+	table   string
+	columns []string
+}
+
+type dbmlTable struct {
+	schema string
+	table  *storepb.TableMetadata
+}
+
+type dbmlParser struct {
+	engine db.Type
+	tokens []*dbmlToken
+	pos    int
+
+	schemaList []string
+	tableList  []*dbmlTable
+	// tableMap maps the "schema.table" and the alias to the table.
+	tableMap map[string]*dbmlTable
+	enumMap  map[string][]string
+	refList  []*dbmlRef
+}
+
+// ParseDBML parses the DBML (database markup language) content into the schema metadata for the engine.
+// The column types are kept as they are except the enums and the auto-increment columns, which are converted to
+// the engine-specific types. The tables without schema belong to the default schema of the engine.
+func ParseDBML(engine db.Type, content string) (*storepb.DatabaseMetadata, error) {
+	tokens, err := tokenizeDBML(content)
+	if err != nil {
+		return nil, err
+	}
+	p := &dbmlParser{
+		engine:   engine,
+		tokens:   tokens,
+		tableMap: make(map[string]*dbmlTable),
+		enumMap:  make(map[string][]string),
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	if err := p.resolveEnums(); err != nil {
+		return nil, err
+	}
+	if err := p.resolveRefs(); err != nil {
+		return nil, err
+	}
+
+	metadata := &storepb.DatabaseMetadata{}
+	schemaMap := make(map[string]*storepb.SchemaMetadata)
+	for _, schemaName := range p.schemaList {
+		schema := &storepb.SchemaMetadata{Name: schemaName}
+		schemaMap[schemaName] = schema
+		metadata.Schemas = append(metadata.Schemas, schema)
+	}
+	for _, table := range p.tableList {
+		schema := schemaMap[table.schema]
+		schema.Tables = append(schema.Tables, table.table)
+	}
+	return metadata, nil
+}
+
+func tokenizeDBML(content string) ([]*dbmlToken, error) {
+	var tokens []*dbmlToken
+	runes := []rune(content)
+	line := 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			tokens = append(tokens, &dbmlToken{tp: dbmlTokenNewline, text: "\n", line: line})
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			j := i + 2
+			for ; j+1 < len(runes) && !(runes[j] == '*' && runes[j+1] == '/'); j++ {
+				if runes[j] == '\n' {
+					line++
+				}
+			}
+			if j+1 >= len(runes) {
+				return nil, errors.Errorf("line %d: unterminated comment", line)
+			}
+			i = j + 2
+		case r == '"' || r == '`' || r == '\'':
+			tp := map[rune]dbmlTokenType{'"': dbmlTokenQuoted, '`': dbmlTokenExpression, '\'': dbmlTokenString}[r]
+			quote := string(r)
+			// The triple-quoted string is the multi-line string.
+			if r == '\'' && hasRunePrefix(runes[i:], "'''") {
+				quote = "'''"
+			}
+			start := i + len(quote)
+			var buf strings.Builder
+			j := start
+			for ; j < len(runes); j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					_, _ = buf.WriteRune(runes[j+1])
+					j++
+					continue
+				}
+				if hasRunePrefix(runes[j:], quote) {
+					break
+				}
+				_, _ = buf.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, errors.Errorf("line %d: unterminated %s", line, quote)
+			}
+			text := buf.String()
+			tokens = append(tokens, &dbmlToken{tp: tp, text: text, line: line})
+			line += strings.Count(text, "\n")
+			i = j + len(quote)
+		case r == '<' && i+1 < len(runes) && runes[i+1] == '>':
+			tokens = append(tokens, &dbmlToken{tp: dbmlTokenSymbol, text: "<>", line: line})
+			i += 2
+		case strings.ContainsRune("{}[](),:.<>-", r):
+			tokens = append(tokens, &dbmlToken{tp: dbmlTokenSymbol, text: string(r), line: line})
+			i++
+		default:
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '$' || runes[j] == '#') {
+				j++
+			}
+			if j == i {
+				return nil, errors.Errorf("line %d: unexpected character %q", line, r)
+			}
+			tokens = append(tokens, &dbmlToken{tp: dbmlTokenWord, text: string(runes[i:j]), line: line})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+func hasRunePrefix(runes []rune, prefix string) bool {
+	for i, r := range []rune(prefix) {
+		if i >= len(runes) || runes[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *dbmlParser) peek() *dbmlToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *dbmlParser) next() *dbmlToken {
+	token := p.peek()
+	if token != nil {
+		p.pos++
+	}
+	return token
+}
+
+func (p *dbmlParser) skipNewlines() {
+	for token := p.peek(); token != nil && token.tp == dbmlTokenNewline; token = p.peek() {
+		p.pos++
+	}
+}
+
+func (p *dbmlParser) isSymbol(text string) bool {
+	token := p.peek()
+	return token != nil && token.tp == dbmlTokenSymbol && token.text == text
+}
+
+func (p *dbmlParser) isKeyword(keyword string) bool {
+	token := p.peek()
+	return token != nil && token.tp == dbmlTokenWord && strings.EqualFold(token.text, keyword)
+}
+
+func (p *dbmlParser) errorf(format string, args ...any) error {
+	line := 0
+	if token := p.peek(); token != nil {
+		line = token.line
+	} else if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return errors.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *dbmlParser) expectSymbol(text string) error {
+	p.skipNewlines()
+	if !p.isSymbol(text) {
+		return p.errorf("expect %q", text)
+	}
+	p.pos++
+	return nil
+}
+
+// name parses a word or a double-quoted identifier.
+func (p *dbmlParser) name() (string, error) {
+	token := p.peek()
+	if token == nil || (token.tp != dbmlTokenWord && token.tp != dbmlTokenQuoted) {
+		return "", p.errorf("expect a name")
+	}
+	p.pos++
+	return token.text, nil
+}
+
+// qualifiedName parses the name list separated by dots.
+func (p *dbmlParser) qualifiedName() ([]string, error) {
+	var result []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, name)
+		if !p.isSymbol(".") {
+			return result, nil
+		}
+		p.pos++
+	}
+}
+
+// skipBlock skips the block in braces.
+func (p *dbmlParser) skipBlock() error {
+	if err := p.expectSymbol("{"); err != nil {
+		return err
+	}
+	for depth := 1; depth > 0; {
+		token := p.next()
+		if token == nil {
+			return p.errorf("expect \"}\"")
+		}
+		if token.tp == dbmlTokenSymbol && token.text == "{" {
+			depth++
+		}
+		if token.tp == dbmlTokenSymbol && token.text == "}" {
+			depth--
+		}
+	}
+	return nil
+}
+
+func (p *dbmlParser) parse() error {
+	for {
+		p.skipNewlines()
+		token := p.next()
+		if token == nil {
+			return nil
+		}
+		if token.tp != dbmlTokenWord {
+			p.pos--
+			return p.errorf("unexpected %q", token.text)
+		}
+		switch strings.ToLower(token.text) {
+		case "table":
+			if err := p.parseTable(); err != nil {
+				return err
+			}
+		case "ref":
+			if err := p.parseRef(); err != nil {
+				return err
+			}
+		case "enum":
+			if err := p.parseEnum(); err != nil {
+				return err
+			}
+		case "project", "tablegroup":
+			// The project and table group are for the documentation only.
+			for !p.isSymbol("{") {
+				if p.next() == nil {
+					return p.errorf("expect \"{\"")
+				}
+			}
+			if err := p.skipBlock(); err != nil {
+				return err
+			}
+		case "note":
+			if err := p.skipNote(); err != nil {
+				return err
+			}
+		default:
+			p.pos--
+			return p.errorf("unexpected %q", token.text)
+		}
+	}
+}
+
+// skipNote skips the "Note: 'text'" or "Note { 'text' }".
+func (p *dbmlParser) skipNote() error {
+	if p.isSymbol(":") {
+		p.pos++
+		p.next()
+		return nil
+	}
+	return p.skipBlock()
+}
+
+func (p *dbmlParser) parseTable() error {
+	names, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if len(names) > 2 {
+		return p.errorf("invalid table name %q", strings.Join(names, "."))
+	}
+	schemaName, tableName := "", names[len(names)-1]
+	if len(names) == 2 {
+		schemaName = names[0]
+	}
+	schemaName = p.normalizeSchema(schemaName)
+	table := &dbmlTable{schema: schemaName, table: &storepb.TableMetadata{Name: tableName}}
+	key := schemaName + "." + tableName
+	if _, ok := p.tableMap[key]; ok {
+		return p.errorf("duplicate table %q", tableName)
+	}
+	p.tableMap[key] = table
+	if p.isKeyword("as") {
+		p.pos++
+		alias, err := p.name()
+		if err != nil {
+			return err
+		}
+		p.tableMap[alias] = table
+	}
+	if p.isSymbol("[") {
+		// The table settings such as the header color are for the diagram only.
+		if _, err := p.parseSettings(); err != nil {
+			return err
+		}
+	}
+	if err := p.expectSymbol("{"); err != nil {
+		return err
+	}
+
+	hasSchema := false
+	for _, s := range p.schemaList {
+		if s == schemaName {
+			hasSchema = true
+		}
+	}
+	if !hasSchema {
+		p.schemaList = append(p.schemaList, schemaName)
+	}
+	p.tableList = append(p.tableList, table)
+
+	var primaryKey []string
+	for {
+		p.skipNewlines()
+		if p.isSymbol("}") {
+			p.pos++
+			break
+		}
+		switch {
+		case p.isKeyword("note") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].tp == dbmlTokenSymbol:
+			p.pos++
+			if p.isSymbol(":") {
+				p.pos++
+				table.table.Comment = p.next().text
+			} else {
+				if err := p.expectSymbol("{"); err != nil {
+					return err
+				}
+				p.skipNewlines()
+				table.table.Comment = p.next().text
+				if err := p.expectSymbol("}"); err != nil {
+					return err
+				}
+			}
+		case p.isKeyword("indexes") && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].tp == dbmlTokenSymbol && p.tokens[p.pos+1].text == "{":
+			p.pos++
+			indexPrimaryKey, err := p.parseIndexes(table)
+			if err != nil {
+				return err
+			}
+			if len(indexPrimaryKey) > 0 {
+				primaryKey = indexPrimaryKey
+			}
+		default:
+			isPrimaryKey, err := p.parseColumn(table)
+			if err != nil {
+				return err
+			}
+			if isPrimaryKey {
+				primaryKey = append(primaryKey, table.table.Columns[len(table.table.Columns)-1].Name)
+			}
+		}
+	}
+
+	if len(primaryKey) > 0 {
+		for _, column := range table.table.Columns {
+			for _, name := range primaryKey {
+				if column.Name == name {
+					column.Nullable = false
+				}
+			}
+		}
+		table.table.Indexes = append([]*storepb.IndexMetadata{{
+			Name:        fmt.Sprintf("pk_%s", tableName),
+			Expressions: primaryKey,
+			Unique:      true,
+			Primary:     true,
+		}}, table.table.Indexes...)
+	}
+	return nil
+}
+
+// parseSettings parses the settings in brackets, the keys are in lower case.
+func (p *dbmlParser) parseSettings() ([]*dbmlSetting, error) {
+	if err := p.expectSymbol("["); err != nil {
+		return nil, err
+	}
+	var result []*dbmlSetting
+	setting := &dbmlSetting{}
+	for {
+		p.skipNewlines()
+		token := p.next()
+		if token == nil {
+			return nil, p.errorf("expect \"]\"")
+		}
+		if token.tp == dbmlTokenSymbol && (token.text == "," || token.text == "]") {
+			if setting.key != "" {
+				result = append(result, setting)
+			}
+			if token.text == "]" {
+				return result, nil
+			}
+			setting = &dbmlSetting{}
+			continue
+		}
+		if token.tp == dbmlTokenSymbol && token.text == ":" && !setting.hasValue {
+			setting.hasValue = true
+			continue
+		}
+		if !setting.hasValue {
+			setting.key = strings.TrimSpace(setting.key + " " + strings.ToLower(token.text))
+			continue
+		}
+		setting.value = append(setting.value, token)
+	}
+}
+
+type dbmlSetting struct {
+	key      string
+	hasValue bool
+	value    []*dbmlToken
+}
+
+func (s *dbmlSetting) text() string {
+	var list []string
+	for _, token := range s.value {
+		list = append(list, token.text)
+	}
+	return strings.Join(list, "")
+}
+
+// parseColumn parses the column definition and returns whether it's the primary key.
+func (p *dbmlParser) parseColumn(table *dbmlTable) (bool, error) {
+	name, err := p.name()
+	if err != nil {
+		return false, err
+	}
+	// The type is the tokens up to the settings or the line end, such as "decimal(10, 2)" and "character varying".
+	var columnType strings.Builder
+	var last *dbmlToken
+	for token := p.peek(); token != nil && token.tp != dbmlTokenNewline && !(token.tp == dbmlTokenSymbol && (token.text == "[" || token.text == "}")); token = p.peek() {
+		if last != nil && last.tp == dbmlTokenWord && token.tp == dbmlTokenWord {
+			_, _ = columnType.WriteString(" ")
+		}
+		if token.tp == dbmlTokenSymbol && token.text == "," {
+			_, _ = columnType.WriteString(", ")
+		} else {
+			_, _ = columnType.WriteString(token.text)
+		}
+		last = token
+		p.pos++
+	}
+	if columnType.Len() == 0 {
+		return false, p.errorf("expect the type of column %q", name)
+	}
+	column := &storepb.ColumnMetadata{
+		Name:     name,
+		Position: int32(len(table.table.Columns) + 1),
+		Type:     columnType.String(),
+		Nullable: true,
+	}
+	table.table.Columns = append(table.table.Columns, column)
+
+	if !p.isSymbol("[") {
+		return false, nil
+	}
+	settings, err := p.parseSettings()
+	if err != nil {
+		return false, err
+	}
+	isPrimaryKey := false
+	for _, setting := range settings {
+		switch setting.key {
+		case "pk", "primary key":
+			isPrimaryKey = true
+		case "not null":
+			column.Nullable = false
+		case "null":
+			column.Nullable = true
+		case "unique":
+			table.table.Indexes = append(table.table.Indexes, &storepb.IndexMetadata{
+				Name:        fmt.Sprintf("uk_%s_%s", table.table.Name, name),
+				Expressions: []string{name},
+				Unique:      true,
+			})
+		case "increment":
+			column.Type = p.incrementType(column.Type)
+		case "note":
+			if len(setting.value) > 0 {
+				column.Comment = setting.value[0].text
+			}
+		case "default":
+			if len(setting.value) == 0 {
+				return false, p.errorf("expect the default value of column %q", name)
+			}
+			column.Default = wrapperspb.String(p.defaultValue(setting.value))
+		case "ref":
+			ref, err := p.inlineRef(table, name, setting.value)
+			if err != nil {
+				return false, err
+			}
+			p.refList = append(p.refList, ref)
+		}
+	}
+	return isPrimaryKey, nil
+}
+
+// incrementType returns the engine-specific type of the auto-increment column.
+func (p *dbmlParser) incrementType(columnType string) string {
+	switch p.engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		return columnType + " AUTO_INCREMENT"
+	case db.Postgres:
+		switch strings.ToLower(columnType) {
+		case "int", "integer", "int4":
+			return "serial"
+		case "bigint", "int8":
+			return "bigserial"
+		case "smallint", "int2":
+			return "smallserial"
+		}
+		return columnType + " GENERATED BY DEFAULT AS IDENTITY"
+	case db.MSSQL:
+		return columnType + " IDENTITY(1,1)"
+	case db.Oracle:
+		return columnType + " GENERATED BY DEFAULT AS IDENTITY"
+	default:
+		return columnType
+	}
+}
+
+// defaultValue returns the default value in SQL.
+func (p *dbmlParser) defaultValue(tokens []*dbmlToken) string {
+	token := tokens[0]
+	switch token.tp {
+	case dbmlTokenString:
+		return quoteSQLString(token.text)
+	case dbmlTokenExpression:
+		return token.text
+	}
+	// The negative number is tokenized as "-" and the number.
+	var list []string
+	for _, t := range tokens {
+		list = append(list, t.text)
+	}
+	value := strings.Join(list, "")
+	switch strings.ToLower(value) {
+	case "true", "false":
+		if p.engine == db.MSSQL || p.engine == db.Oracle {
+			if strings.EqualFold(value, "true") {
+				return "1"
+			}
+			return "0"
+		}
+		return strings.ToUpper(value)
+	case "null":
+		return "NULL"
+	}
+	return value
+}
+
+// inlineRef parses the inline relationship, such as "ref: > users.id".
+func (p *dbmlParser) inlineRef(table *dbmlTable, columnName string, tokens []*dbmlToken) (*dbmlRef, error) {
+	if len(tokens) == 0 || tokens[0].tp != dbmlTokenSymbol {
+		return nil, p.errorf("invalid ref of column %q", columnName)
+	}
+	endpointParser := &dbmlParser{tokens: tokens[1:]}
+	endpoint, err := endpointParser.endpoint()
+	if err != nil {
+		return nil, p.errorf("invalid ref of column %q: %v", columnName, err)
+	}
+	self := &dbmlEndpoint{schema: table.schema, table: table.table.Name, columns: []string{columnName}}
+	ref, err := newDBMLRef("", self, tokens[0].text, endpoint)
+	if err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	return ref, nil
+}
+
+func newDBMLRef(name string, left *dbmlEndpoint, op string, right *dbmlEndpoint) (*dbmlRef, error) {
+	switch op {
+	case ">", "-":
+		return &dbmlRef{name: name, from: left, to: right}, nil
+	case "<":
+		return &dbmlRef{name: name, from: right, to: left}, nil
+	case "<>":
+		return nil, errors.Errorf("the many-to-many relationship between %q and %q requires a join table", left.table, right.table)
+	default:
+		return nil, errors.Errorf("invalid relationship %q", op)
+	}
+}
+
+// endpoint parses the "schema.table.column" or "table.(column1, column2)".
+func (p *dbmlParser) endpoint() (*dbmlEndpoint, error) {
+	var names []string
+	var columns []string
+	for {
+		if p.isSymbol("(") {
+			p.pos++
+			for {
+				column, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				columns = append(columns, column)
+				if p.isSymbol(",") {
+					p.pos++
+					continue
+				}
+				if err := p.expectSymbol(")"); err != nil {
+					return nil, err
+				}
+				break
+			}
+			break
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.isSymbol(".") {
+			break
+		}
+		p.pos++
+	}
+	if len(columns) == 0 {
+		if len(names) < 2 {
+			return nil, p.errorf("invalid column reference %q", strings.Join(names, "."))
+		}
+		columns = []string{names[len(names)-1]}
+		names = names[:len(names)-1]
+	}
+	if len(names) == 0 || len(names) > 2 {
+		return nil, p.errorf("invalid table reference %q", strings.Join(names, "."))
+	}
+	endpoint := &dbmlEndpoint{table: names[len(names)-1], columns: columns}
+	if len(names) == 2 {
+		endpoint.schema = names[0]
+	}
+	return endpoint, nil
+}
+
+func (p *dbmlParser) parseRef() error {
+	name := ""
+	if !p.isSymbol(":") && !p.isSymbol("{") {
+		n, err := p.name()
+		if err != nil {
+			return err
+		}
+		name = n
+	}
+	parseOne := func() error {
+		left, err := p.endpoint()
+		if err != nil {
+			return err
+		}
+		op := p.next()
+		if op == nil || op.tp != dbmlTokenSymbol {
+			return p.errorf("expect the relationship")
+		}
+		right, err := p.endpoint()
+		if err != nil {
+			return err
+		}
+		ref, err := newDBMLRef(name, left, op.text, right)
+		if err != nil {
+			return p.errorf("%v", err)
+		}
+		if p.isSymbol("[") {
+			settings, err := p.parseSettings()
+			if err != nil {
+				return err
+			}
+			for _, setting := range settings {
+				switch setting.key {
+				case "delete":
+					ref.onDelete = strings.ToUpper(setting.text())
+				case "update":
+					ref.onUpdate = strings.ToUpper(setting.text())
+				}
+			}
+		}
+		p.refList = append(p.refList, ref)
+		return nil
+	}
+	if p.isSymbol(":") {
+		p.pos++
+		return parseOne()
+	}
+	if err := p.expectSymbol("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.isSymbol("}") {
+			p.pos++
+			return nil
+		}
+		if err := parseOne(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *dbmlParser) parseEnum() error {
+	names, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if err := p.expectSymbol("{"); err != nil {
+		return err
+	}
+	var values []string
+	for {
+		p.skipNewlines()
+		if p.isSymbol("}") {
+			p.pos++
+			break
+		}
+		value, err := p.name()
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+		if p.isSymbol("[") {
+			// The enum value settings are the notes only.
+			if _, err := p.parseSettings(); err != nil {
+				return err
+			}
+		}
+	}
+	p.enumMap[strings.Join(names, ".")] = values
+	p.enumMap[names[len(names)-1]] = values
+	return nil
+}
+
+// parseIndexes parses the indexes block and returns the primary key columns if any.
+func (p *dbmlParser) parseIndexes(table *dbmlTable) ([]string, error) {
+	if err := p.expectSymbol("{"); err != nil {
+		return nil, err
+	}
+	var primaryKey []string
+	for {
+		p.skipNewlines()
+		if p.isSymbol("}") {
+			p.pos++
+			return primaryKey, nil
+		}
+		var expressions []string
+		if p.isSymbol("(") {
+			p.pos++
+			for {
+				token := p.next()
+				if token == nil {
+					return nil, p.errorf("expect \")\"")
+				}
+				if token.tp == dbmlTokenSymbol && token.text == ")" {
+					break
+				}
+				if token.tp == dbmlTokenSymbol && token.text == "," {
+					continue
+				}
+				expressions = append(expressions, token.text)
+			}
+		} else {
+			token := p.next()
+			if token == nil || (token.tp != dbmlTokenWord && token.tp != dbmlTokenQuoted && token.tp != dbmlTokenExpression) {
+				return nil, p.errorf("expect the index columns")
+			}
+			expressions = append(expressions, token.text)
+		}
+
+		index := &storepb.IndexMetadata{
+			Name:        fmt.Sprintf("idx_%s_%s", table.table.Name, strings.Join(expressions, "_")),
+			Expressions: expressions,
+		}
+		isPrimaryKey := false
+		if p.isSymbol("[") {
+			settings, err := p.parseSettings()
+			if err != nil {
+				return nil, err
+			}
+			for _, setting := range settings {
+				switch setting.key {
+				case "pk", "primary key":
+					isPrimaryKey = true
+				case "unique":
+					index.Unique = true
+				case "name":
+					index.Name = setting.text()
+				case "type":
+					index.Type = setting.text()
+				case "note":
+					index.Comment = setting.text()
+				}
+			}
+		}
+		if isPrimaryKey {
+			primaryKey = expressions
+			continue
+		}
+		table.table.Indexes = append(table.table.Indexes, index)
+	}
+}
+
+// resolveEnums converts the enum columns to the MySQL enum type, the enums can be defined after the tables.
+func (p *dbmlParser) resolveEnums() error {
+	for _, table := range p.tableList {
+		for _, column := range table.table.Columns {
+			values, ok := p.enumMap[column.Type]
+			if !ok {
+				continue
+			}
+			if p.engine != db.MySQL && p.engine != db.TiDB && p.engine != db.MariaDB && p.engine != db.OceanBase {
+				return errors.Errorf("the enum type %q of column %q is only supported for MySQL", column.Type, column.Name)
+			}
+			var quoted []string
+			for _, value := range values {
+				quoted = append(quoted, quoteSQLString(value))
+			}
+			column.Type = fmt.Sprintf("ENUM(%s)", strings.Join(quoted, ", "))
+		}
+	}
+	return nil
+}
+
+// resolveRefs converts the relationships to the foreign keys of the referencing tables.
+func (p *dbmlParser) resolveRefs() error {
+	find := func(endpoint *dbmlEndpoint) (*dbmlTable, error) {
+		if endpoint.schema == "" {
+			if table, ok := p.tableMap[endpoint.table]; ok {
+				return table, nil
+			}
+		}
+		if table, ok := p.tableMap[p.normalizeSchema(endpoint.schema)+"."+endpoint.table]; ok {
+			return table, nil
+		}
+		return nil, errors.Errorf("table %q not found", endpoint.table)
+	}
+	for _, ref := range p.refList {
+		from, err := find(ref.from)
+		if err != nil {
+			return err
+		}
+		to, err := find(ref.to)
+		if err != nil {
+			return err
+		}
+		if len(ref.from.columns) != len(ref.to.columns) {
+			return errors.Errorf("the column count of the relationship between %q and %q mismatches", from.table.Name, to.table.Name)
+		}
+		name := ref.name
+		if name == "" {
+			name = fmt.Sprintf("fk_%s_%s", from.table.Name, strings.Join(ref.from.columns, "_"))
+		}
+		fk := &storepb.ForeignKeyMetadata{
+			Name:              name,
+			Columns:           ref.from.columns,
+			ReferencedTable:   to.table.Name,
+			ReferencedColumns: ref.to.columns,
+			OnDelete:          ref.onDelete,
+			OnUpdate:          ref.onUpdate,
+		}
+		if to.schema != from.schema {
+			fk.ReferencedSchema = to.schema
+		}
+		from.table.ForeignKeys = append(from.table.ForeignKeys, fk)
+	}
+	return nil
+}
+
+// normalizeSchema returns the schema name of the engine, the engines without the schema concept use the empty string.
+func (p *dbmlParser) normalizeSchema(schema string) string {
+	switch p.engine {
+	case db.Postgres:
+		if schema == "" {
+			return "public"
+		}
+		return schema
+	case db.MSSQL:
+		if schema == "" {
+			return "dbo"
+		}
+		return schema
+	default:
+		return ""
+	}
+}
+
+// GenerateSDL generates the SDL (schema definition language) statements for the tables in the schema metadata.
+// The statements follow the SDL format, so that they can be diffed against the database schema.
+func GenerateSDL(engine db.Type, metadata *storepb.DatabaseMetadata) (string, error) {
+	var quote func(string) string
+	defaultSchema := ""
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		quote = func(name string) string { return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``")) }
+	case db.Postgres:
+		quote = func(name string) string { return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`)) }
+		defaultSchema = "public"
+	case db.MSSQL:
+		quote = func(name string) string { return fmt.Sprintf("[%s]", strings.ReplaceAll(name, "]", "]]")) }
+		defaultSchema = "dbo"
+	case db.Oracle:
+		// The unquoted identifiers are upper case in Oracle, we keep the names unquoted to follow the convention.
+		quote = func(name string) string { return name }
+	default:
+		return "", errors.Errorf("unsupported engine %q", engine)
+	}
+	tableName := func(schema, table string) string {
+		if schema == "" || schema == defaultSchema {
+			return quote(table)
+		}
+		return fmt.Sprintf("%s.%s", quote(schema), quote(table))
+	}
+	columnList := func(columns []string) string {
+		var quoted []string
+		for _, column := range columns {
+			quoted = append(quoted, quote(column))
+		}
+		return strings.Join(quoted, ", ")
+	}
+	isMySQL := engine == db.MySQL || engine == db.TiDB || engine == db.MariaDB || engine == db.OceanBase
+
+	var buf strings.Builder
+	for _, schema := range metadata.Schemas {
+		for _, table := range schema.Tables {
+			var elements []string
+			for _, column := range table.Columns {
+				element := fmt.Sprintf("%s %s", quote(column.Name), column.Type)
+				if !column.Nullable {
+					element += " NOT NULL"
+				}
+				if column.Default != nil {
+					element += " DEFAULT " + column.Default.Value
+				}
+				if isMySQL && column.Comment != "" {
+					element += " COMMENT " + quoteSQLString(column.Comment)
+				}
+				elements = append(elements, element)
+			}
+			for _, index := range table.Indexes {
+				if index.Primary {
+					elements = append(elements, fmt.Sprintf("PRIMARY KEY (%s)", columnList(index.Expressions)))
+				}
+			}
+			for _, fk := range table.ForeignKeys {
+				referencedSchema := fk.ReferencedSchema
+				if referencedSchema == "" {
+					referencedSchema = schema.Name
+				}
+				element := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)", quote(fk.Name), columnList(fk.Columns), tableName(referencedSchema, fk.ReferencedTable), columnList(fk.ReferencedColumns))
+				if fk.OnDelete != "" {
+					element += " ON DELETE " + fk.OnDelete
+				}
+				if fk.OnUpdate != "" {
+					element += " ON UPDATE " + fk.OnUpdate
+				}
+				elements = append(elements, element)
+			}
+			_, _ = fmt.Fprintf(&buf, "CREATE TABLE %s (\n  %s\n)", tableName(schema.Name, table.Name), strings.Join(elements, ",\n  "))
+			if isMySQL && table.Comment != "" {
+				_, _ = fmt.Fprintf(&buf, " COMMENT %s", quoteSQLString(table.Comment))
+			}
+			_, _ = buf.WriteString(";\n\n")
+
+			for _, index := range table.Indexes {
+				if index.Primary {
+					continue
+				}
+				unique := ""
+				if index.Unique {
+					unique = "UNIQUE "
+				}
+				var expressions []string
+				for _, expression := range index.Expressions {
+					// The expressions in parentheses are kept as they are.
+					if strings.ContainsAny(expression, "() ") {
+						expressions = append(expressions, fmt.Sprintf("(%s)", expression))
+					} else {
+						expressions = append(expressions, quote(expression))
+					}
+				}
+				_, _ = fmt.Fprintf(&buf, "CREATE %sINDEX %s ON %s (%s);\n\n", unique, quote(index.Name), tableName(schema.Name, table.Name), strings.Join(expressions, ", "))
+			}
+		}
+	}
+	return buf.String(), nil
+}
+
+func quoteSQLString(s string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
+}
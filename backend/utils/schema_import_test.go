@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func TestImportDBML(t *testing.T) {
+	content := `
+Project shop {
+  database_type: 'PostgreSQL'
+}
+
+// The users.
+Table users as U {
+  id int [pk, increment]
+  email varchar(255) [not null, unique, note: 'The login email']
+  status user_status [default: 'active']
+  created_at timestamp [default: ` + "`now()`" + `]
+  Note: 'The registered users'
+}
+
+Enum user_status {
+  active
+  "banned"
+}
+
+Table orders {
+  id bigint [pk]
+  user_id int [not null, ref: > U.id]
+  amount decimal(10, 2) [default: 0]
+  paid boolean [default: false]
+
+  indexes {
+    (user_id, amount) [name: 'idx_orders_user_amount']
+    /* The multi-line comment. */
+    amount
+  }
+}
+
+Ref fk_order_items: order_items.order_id > orders.id [delete: cascade]
+
+Table order_items {
+  order_id bigint
+  sku varchar
+  indexes {
+    (order_id, sku) [pk]
+  }
+}
+`
+
+	tests := []struct {
+		engine db.Type
+		want   string
+		err    string
+	}{
+		{
+			engine: db.MySQL,
+			want: "CREATE TABLE `users` (\n" +
+				"  `id` int AUTO_INCREMENT NOT NULL,\n" +
+				"  `email` varchar(255) NOT NULL COMMENT 'The login email',\n" +
+				"  `status` ENUM('active', 'banned') DEFAULT 'active',\n" +
+				"  `created_at` timestamp DEFAULT now(),\n" +
+				"  PRIMARY KEY (`id`)\n" +
+				") COMMENT 'The registered users';\n\n" +
+				"CREATE UNIQUE INDEX `uk_users_email` ON `users` (`email`);\n\n" +
+				"CREATE TABLE `orders` (\n" +
+				"  `id` bigint NOT NULL,\n" +
+				"  `user_id` int NOT NULL,\n" +
+				"  `amount` decimal(10, 2) DEFAULT 0,\n" +
+				"  `paid` boolean DEFAULT FALSE,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  CONSTRAINT `fk_orders_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)\n" +
+				");\n\n" +
+				"CREATE INDEX `idx_orders_user_amount` ON `orders` (`user_id`, `amount`);\n\n" +
+				"CREATE INDEX `idx_orders_amount` ON `orders` (`amount`);\n\n" +
+				"CREATE TABLE `order_items` (\n" +
+				"  `order_id` bigint NOT NULL,\n" +
+				"  `sku` varchar NOT NULL,\n" +
+				"  PRIMARY KEY (`order_id`, `sku`),\n" +
+				"  CONSTRAINT `fk_order_items` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE CASCADE\n" +
+				");\n\n",
+		},
+		{
+			engine: db.Postgres,
+			err:    "the enum type \"user_status\" of column \"status\" is only supported for MySQL",
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		metadata, err := ParseDBML(test.engine, content)
+		if test.err != "" {
+			a.EqualError(err, test.err)
+			continue
+		}
+		a.NoError(err)
+		got, err := GenerateSDL(test.engine, metadata)
+		a.NoError(err)
+		a.Equal(test.want, got)
+	}
+}
+
+func TestGenerateSDLFromDBML(t *testing.T) {
+	content := `
+Table app.users {
+  id int [pk, increment]
+}
+
+Table posts {
+  id int [pk]
+  author_id int [ref: > app.users.id]
+}
+`
+	want := "CREATE TABLE \"app\".\"users\" (\n" +
+		"  \"id\" serial NOT NULL,\n" +
+		"  PRIMARY KEY (\"id\")\n" +
+		");\n\n" +
+		"CREATE TABLE \"posts\" (\n" +
+		"  \"id\" int NOT NULL,\n" +
+		"  \"author_id\" int,\n" +
+		"  PRIMARY KEY (\"id\"),\n" +
+		"  CONSTRAINT \"fk_posts_author_id\" FOREIGN KEY (\"author_id\") REFERENCES \"app\".\"users\" (\"id\")\n" +
+		");\n\n"
+
+	a := require.New(t)
+	metadata, err := ParseDBML(db.Postgres, content)
+	a.NoError(err)
+	got, err := GenerateSDL(db.Postgres, metadata)
+	a.NoError(err)
+	a.Equal(want, got)
+
+	_, err = ParseDBML(db.Postgres, "Table t {\n  id int [ref: <> s.id]\n}\nTable s {\n  id int\n}\n")
+	a.EqualError(err, "line 2: the many-to-many relationship between \"t\" and \"s\" requires a join table")
+}
@@ -0,0 +1,448 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+// seedDataCSVNull is the CSV field value for NULL, following the convention of MySQL LOAD DATA.
+const seedDataCSVNull = `\N`
+
+// SeedDataset is the parsed seed dataset, the nil value is NULL.
+type SeedDataset struct {
+	Columns []string
+	Rows    [][]*string
+}
+
+// SeedDataDiff is the diff between the seed dataset and the table contents.
+type SeedDataDiff struct {
+	// Inserts are the dataset rows absent from the table.
+	Inserts [][]*string
+	// Updates are the dataset rows different from the table contents.
+	Updates [][]*string
+	// Deletes are the table rows absent from the dataset, in the columns of the dataset.
+	Deletes [][]*string
+}
+
+// GenerateSeedDataChange generates the idempotent statements syncing the table contents with the seed dataset.
+func GenerateSeedDataChange(ctx context.Context, driver db.Driver, engine db.Type, config *api.SeedDataConfig) (*api.SeedDataChange, error) {
+	if config.Table == "" {
+		return nil, errors.Errorf("the target table is required")
+	}
+	dataset, err := ParseSeedDataset(config.Format, config.Dataset)
+	if err != nil {
+		return nil, err
+	}
+	current, err := QuerySeedTableRows(ctx, driver.GetDB(), engine, config.Table, dataset.Columns)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := DiffSeedData(dataset, config.KeyColumns, current, config.DeleteMissing)
+	if err != nil {
+		return nil, err
+	}
+	statement, err := GenerateSeedDataStatement(engine, config.Table, dataset.Columns, config.KeyColumns, diff)
+	if err != nil {
+		return nil, err
+	}
+	return &api.SeedDataChange{
+		Statement:   statement,
+		InsertCount: len(diff.Inserts),
+		UpdateCount: len(diff.Updates),
+		DeleteCount: len(diff.Deletes),
+	}, nil
+}
+
+// ParseSeedDataset parses the seed dataset in the format.
+func ParseSeedDataset(format api.SeedDataFormat, content string) (*SeedDataset, error) {
+	var dataset *SeedDataset
+	var err error
+	switch format {
+	case api.SeedDataFormatCSV:
+		dataset, err = parseSeedCSV(content)
+	case api.SeedDataFormatJSON:
+		dataset, err = parseSeedJSON(content)
+	default:
+		return nil, errors.Errorf("unsupported seed data format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(dataset.Columns) == 0 {
+		return nil, errors.Errorf("the seed dataset has no columns")
+	}
+	return dataset, nil
+}
+
+func parseSeedCSV(content string) (*SeedDataset, error) {
+	reader := csv.NewReader(strings.NewReader(content))
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the CSV dataset")
+	}
+	if len(records) == 0 {
+		return nil, errors.Errorf("the CSV dataset has no header")
+	}
+	dataset := &SeedDataset{}
+	seen := make(map[string]bool)
+	for _, column := range records[0] {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			return nil, errors.Errorf("the CSV header has an empty column name")
+		}
+		if seen[column] {
+			return nil, errors.Errorf("the CSV header has duplicate column %q", column)
+		}
+		seen[column] = true
+		dataset.Columns = append(dataset.Columns, column)
+	}
+	for _, record := range records[1:] {
+		var row []*string
+		for _, field := range record {
+			if field == seedDataCSVNull {
+				row = append(row, nil)
+				continue
+			}
+			value := field
+			row = append(row, &value)
+		}
+		dataset.Rows = append(dataset.Rows, row)
+	}
+	return dataset, nil
+}
+
+func parseSeedJSON(content string) (*SeedDataset, error) {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	var objects []map[string]any
+	if err := decoder.Decode(&objects); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the JSON dataset, it should be an array of objects")
+	}
+	// The key order of the objects is lost, so the columns are sorted to be deterministic.
+	columnSet := make(map[string]bool)
+	for _, object := range objects {
+		for column := range object {
+			columnSet[column] = true
+		}
+	}
+	dataset := &SeedDataset{}
+	for column := range columnSet {
+		dataset.Columns = append(dataset.Columns, column)
+	}
+	sort.Strings(dataset.Columns)
+	for i, object := range objects {
+		var row []*string
+		for _, column := range dataset.Columns {
+			value, err := seedJSONValue(object[column])
+			if err != nil {
+				return nil, errors.Wrapf(err, "row %d column %q", i+1, column)
+			}
+			row = append(row, value)
+		}
+		dataset.Rows = append(dataset.Rows, row)
+	}
+	return dataset, nil
+}
+
+func seedJSONValue(value any) (*string, error) {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		text = v
+	case json.Number:
+		text = v.String()
+	case bool:
+		text = strconv.FormatBool(v)
+	default:
+		return nil, errors.Errorf("unsupported value type %T, the value should be a string, number, boolean or null", value)
+	}
+	return &text, nil
+}
+
+// QuerySeedTableRows returns the rows of the table in the columns.
+func QuerySeedTableRows(ctx context.Context, sqlDB *sql.DB, engine db.Type, table string, columns []string) ([][]*string, error) {
+	quote, err := getSeedQuoteFunc(engine)
+	if err != nil {
+		return nil, err
+	}
+	var quotedColumns []string
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, quote(column))
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(quotedColumns, ", "), quoteSeedTable(quote, table))
+	rows, err := sqlDB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query table %q", table)
+	}
+	defer rows.Close()
+
+	var result [][]*string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		var row []*string
+		for _, value := range values {
+			if !value.Valid {
+				row = append(row, nil)
+				continue
+			}
+			text := value.String
+			row = append(row, &text)
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DiffSeedData computes the diff between the seed dataset and the rows of the table in the columns of the dataset.
+// The rows are matched by the key columns, and the rows with the same values are skipped.
+func DiffSeedData(dataset *SeedDataset, keyColumns []string, current [][]*string, deleteMissing bool) (*SeedDataDiff, error) {
+	if len(keyColumns) == 0 {
+		return nil, errors.Errorf("the key columns are required")
+	}
+	columnIndex := make(map[string]int)
+	for i, column := range dataset.Columns {
+		columnIndex[column] = i
+	}
+	var keyIndexes []int
+	for _, key := range keyColumns {
+		index, ok := columnIndex[key]
+		if !ok {
+			return nil, errors.Errorf("key column %q is not in the seed dataset", key)
+		}
+		keyIndexes = append(keyIndexes, index)
+	}
+	rowKey := func(row []*string) (string, error) {
+		var values []string
+		for _, index := range keyIndexes {
+			if row[index] == nil {
+				return "", errors.Errorf("key column %q is NULL", dataset.Columns[index])
+			}
+			values = append(values, strconv.Quote(*row[index]))
+		}
+		return strings.Join(values, ","), nil
+	}
+
+	currentRows := make(map[string][]*string)
+	for _, row := range current {
+		key, err := rowKey(row)
+		if err != nil {
+			// The rows with NULL keys cannot be matched, leave them as they are.
+			continue
+		}
+		currentRows[key] = row
+	}
+
+	diff := &SeedDataDiff{}
+	datasetKeys := make(map[string]bool)
+	for i, row := range dataset.Rows {
+		if len(row) != len(dataset.Columns) {
+			return nil, errors.Errorf("row %d has %d values, expecting %d", i+1, len(row), len(dataset.Columns))
+		}
+		key, err := rowKey(row)
+		if err != nil {
+			return nil, errors.Wrapf(err, "row %d", i+1)
+		}
+		if datasetKeys[key] {
+			return nil, errors.Errorf("row %d has duplicate key %s", i+1, key)
+		}
+		datasetKeys[key] = true
+		currentRow, ok := currentRows[key]
+		if !ok {
+			diff.Inserts = append(diff.Inserts, row)
+			continue
+		}
+		if !equalSeedRow(row, currentRow) {
+			diff.Updates = append(diff.Updates, row)
+		}
+	}
+	if deleteMissing {
+		for _, row := range current {
+			key, err := rowKey(row)
+			if err != nil || datasetKeys[key] {
+				continue
+			}
+			diff.Deletes = append(diff.Deletes, row)
+		}
+	}
+	return diff, nil
+}
+
+func equalSeedRow(a, b []*string) bool {
+	for i := range a {
+		if (a[i] == nil) != (b[i] == nil) {
+			return false
+		}
+		if a[i] != nil && *a[i] != *b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// GenerateSeedDataStatement generates the statements applying the diff to the table.
+// The inserts and updates are generated as the upserts, so the statements are idempotent and safe to retry.
+func GenerateSeedDataStatement(engine db.Type, table string, columns []string, keyColumns []string, diff *SeedDataDiff) (string, error) {
+	quote, err := getSeedQuoteFunc(engine)
+	if err != nil {
+		return "", err
+	}
+	literal := quoteSQLString
+	if isSeedMySQLFamily(engine) {
+		// The backslash is the escape character in the MySQL string literals.
+		literal = func(s string) string { return quoteSQLString(strings.ReplaceAll(s, `\`, `\\`)) }
+	}
+	value := func(v *string) string {
+		if v == nil {
+			return "NULL"
+		}
+		return literal(*v)
+	}
+	isKey := make(map[string]bool)
+	for _, key := range keyColumns {
+		isKey[key] = true
+	}
+	var quotedColumns, quotedKeys, quotedValueColumns []string
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, quote(column))
+		if isKey[column] {
+			quotedKeys = append(quotedKeys, quote(column))
+		} else {
+			quotedValueColumns = append(quotedValueColumns, quote(column))
+		}
+	}
+	tableName := quoteSeedTable(quote, table)
+	columnList := strings.Join(quotedColumns, ", ")
+
+	var buf strings.Builder
+	// The deletes go first, so that the upserts don't conflict with the unique constraints of the deleted rows.
+	for _, row := range diff.Deletes {
+		var conditions []string
+		for i, column := range columns {
+			if isKey[column] {
+				conditions = append(conditions, fmt.Sprintf("%s = %s", quote(column), value(row[i])))
+			}
+		}
+		_, _ = fmt.Fprintf(&buf, "DELETE FROM %s WHERE %s;\n", tableName, strings.Join(conditions, " AND "))
+	}
+
+	upserts := append(append([][]*string{}, diff.Inserts...), diff.Updates...)
+	for _, row := range upserts {
+		var values []string
+		for _, v := range row {
+			values = append(values, value(v))
+		}
+		valueList := strings.Join(values, ", ")
+		switch {
+		case isSeedMySQLFamily(engine):
+			if len(quotedValueColumns) == 0 {
+				_, _ = fmt.Fprintf(&buf, "INSERT IGNORE INTO %s (%s) VALUES (%s);\n", tableName, columnList, valueList)
+				continue
+			}
+			var assignments []string
+			for _, column := range quotedValueColumns {
+				assignments = append(assignments, fmt.Sprintf("%s = VALUES(%s)", column, column))
+			}
+			_, _ = fmt.Fprintf(&buf, "INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s;\n", tableName, columnList, valueList, strings.Join(assignments, ", "))
+		case engine == db.Postgres:
+			if len(quotedValueColumns) == 0 {
+				_, _ = fmt.Fprintf(&buf, "INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO NOTHING;\n", tableName, columnList, valueList, strings.Join(quotedKeys, ", "))
+				continue
+			}
+			var assignments []string
+			for _, column := range quotedValueColumns {
+				assignments = append(assignments, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+			}
+			_, _ = fmt.Fprintf(&buf, "INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s;\n", tableName, columnList, valueList, strings.Join(quotedKeys, ", "), strings.Join(assignments, ", "))
+		case engine == db.MSSQL:
+			source := fmt.Sprintf("(VALUES (%s)) AS source (%s)", valueList, columnList)
+			_, _ = fmt.Fprintf(&buf, "MERGE INTO %s AS target USING %s ON %s%s WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s);\n", tableName, source, seedMergeCondition(quotedKeys), seedMergeUpdate(quotedValueColumns), columnList, seedSourceColumns(quotedColumns))
+		case engine == db.Oracle:
+			var selectList []string
+			for i, v := range values {
+				selectList = append(selectList, fmt.Sprintf("%s %s", v, quotedColumns[i]))
+			}
+			source := fmt.Sprintf("(SELECT %s FROM DUAL) source", strings.Join(selectList, ", "))
+			_, _ = fmt.Fprintf(&buf, "MERGE INTO %s target USING %s ON (%s)%s WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s);\n", tableName, source, seedMergeCondition(quotedKeys), seedMergeUpdate(quotedValueColumns), columnList, seedSourceColumns(quotedColumns))
+		}
+	}
+	return buf.String(), nil
+}
+
+func seedMergeCondition(quotedKeys []string) string {
+	var conditions []string
+	for _, key := range quotedKeys {
+		conditions = append(conditions, fmt.Sprintf("target.%s = source.%s", key, key))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+func seedMergeUpdate(quotedValueColumns []string) string {
+	if len(quotedValueColumns) == 0 {
+		return ""
+	}
+	var assignments []string
+	for _, column := range quotedValueColumns {
+		assignments = append(assignments, fmt.Sprintf("target.%s = source.%s", column, column))
+	}
+	return fmt.Sprintf(" WHEN MATCHED THEN UPDATE SET %s", strings.Join(assignments, ", "))
+}
+
+func seedSourceColumns(quotedColumns []string) string {
+	var columns []string
+	for _, column := range quotedColumns {
+		columns = append(columns, "source."+column)
+	}
+	return strings.Join(columns, ", ")
+}
+
+func isSeedMySQLFamily(engine db.Type) bool {
+	return engine == db.MySQL || engine == db.TiDB || engine == db.MariaDB || engine == db.OceanBase
+}
+
+func getSeedQuoteFunc(engine db.Type) (func(string) string, error) {
+	switch {
+	case isSeedMySQLFamily(engine):
+		return func(name string) string { return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``")) }, nil
+	case engine == db.Postgres:
+		return func(name string) string { return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`)) }, nil
+	case engine == db.MSSQL:
+		return func(name string) string { return fmt.Sprintf("[%s]", strings.ReplaceAll(name, "]", "]]")) }, nil
+	case engine == db.Oracle:
+		// The unquoted identifiers are upper case in Oracle, we keep the names unquoted to follow the convention.
+		return func(name string) string { return name }, nil
+	default:
+		return nil, errors.Errorf("seed data change is not supported for engine %q", engine)
+	}
+}
+
+// quoteSeedTable quotes the table name qualified by the schema, e.g. "public.country".
+func quoteSeedTable(quote func(string) string, table string) string {
+	var parts []string
+	for _, part := range strings.Split(table, ".") {
+		parts = append(parts, quote(strings.TrimSpace(part)))
+	}
+	return strings.Join(parts, ".")
+}
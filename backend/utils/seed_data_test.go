@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/db"
+)
+
+func seedRow(values ...string) []*string {
+	var row []*string
+	for _, value := range values {
+		if value == seedDataCSVNull {
+			row = append(row, nil)
+			continue
+		}
+		v := value
+		row = append(row, &v)
+	}
+	return row
+}
+
+func TestParseSeedDataset(t *testing.T) {
+	tests := []struct {
+		format  api.SeedDataFormat
+		content string
+		want    *SeedDataset
+		err     string
+	}{
+		{
+			format:  api.SeedDataFormatCSV,
+			content: "code,name,note\nUS,United States,\\N\nCN,\"China, PRC\",\n",
+			want: &SeedDataset{
+				Columns: []string{"code", "name", "note"},
+				Rows: [][]*string{
+					seedRow("US", "United States", seedDataCSVNull),
+					seedRow("CN", "China, PRC", ""),
+				},
+			},
+		},
+		{
+			format:  api.SeedDataFormatJSON,
+			content: `[{"id": 1, "name": "active", "enabled": true}, {"id": 2, "name": "banned", "enabled": null}]`,
+			want: &SeedDataset{
+				Columns: []string{"enabled", "id", "name"},
+				Rows: [][]*string{
+					seedRow("true", "1", "active"),
+					seedRow(seedDataCSVNull, "2", "banned"),
+				},
+			},
+		},
+		{
+			format:  api.SeedDataFormatCSV,
+			content: "code,code\nUS,US\n",
+			err:     `the CSV header has duplicate column "code"`,
+		},
+		{
+			format:  api.SeedDataFormatJSON,
+			content: `[{"id": [1]}]`,
+			err:     `row 1 column "id": unsupported value type []interface {}, the value should be a string, number, boolean or null`,
+		},
+		{
+			format: "xml",
+			err:    `unsupported seed data format "xml"`,
+		},
+	}
+
+	for _, test := range tests {
+		dataset, err := ParseSeedDataset(test.format, test.content)
+		if test.err != "" {
+			require.EqualError(t, err, test.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.want, dataset)
+	}
+}
+
+func TestDiffSeedData(t *testing.T) {
+	dataset := &SeedDataset{
+		Columns: []string{"code", "name"},
+		Rows: [][]*string{
+			seedRow("US", "United States"),
+			seedRow("CN", "China"),
+			seedRow("FR", "France"),
+		},
+	}
+	current := [][]*string{
+		seedRow("US", "United States"),
+		seedRow("CN", "PRC"),
+		seedRow("DE", "Germany"),
+	}
+
+	diff, err := DiffSeedData(dataset, []string{"code"}, current, false /* deleteMissing */)
+	require.NoError(t, err)
+	require.Equal(t, &SeedDataDiff{
+		Inserts: [][]*string{seedRow("FR", "France")},
+		Updates: [][]*string{seedRow("CN", "China")},
+	}, diff)
+
+	diff, err = DiffSeedData(dataset, []string{"code"}, current, true /* deleteMissing */)
+	require.NoError(t, err)
+	require.Equal(t, [][]*string{seedRow("DE", "Germany")}, diff.Deletes)
+
+	_, err = DiffSeedData(dataset, []string{"id"}, current, false /* deleteMissing */)
+	require.EqualError(t, err, `key column "id" is not in the seed dataset`)
+
+	dataset.Rows = append(dataset.Rows, seedRow("US", "USA"))
+	_, err = DiffSeedData(dataset, []string{"code"}, current, false /* deleteMissing */)
+	require.EqualError(t, err, `row 4 has duplicate key "US"`)
+}
+
+func TestGenerateSeedDataStatement(t *testing.T) {
+	diff := &SeedDataDiff{
+		Inserts: [][]*string{seedRow("FR", "France's", seedDataCSVNull)},
+		Deletes: [][]*string{seedRow("DE", "Germany", seedDataCSVNull)},
+	}
+	columns := []string{"code", "name", "note"}
+
+	tests := []struct {
+		engine db.Type
+		table  string
+		want   string
+	}{
+		{
+			engine: db.MySQL,
+			table:  "country",
+			want: "DELETE FROM `country` WHERE `code` = 'DE';\n" +
+				"INSERT INTO `country` (`code`, `name`, `note`) VALUES ('FR', 'France''s', NULL) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `note` = VALUES(`note`);\n",
+		},
+		{
+			engine: db.Postgres,
+			table:  "public.country",
+			want: `DELETE FROM "public"."country" WHERE "code" = 'DE';
+INSERT INTO "public"."country" ("code", "name", "note") VALUES ('FR', 'France''s', NULL) ON CONFLICT ("code") DO UPDATE SET "name" = EXCLUDED."name", "note" = EXCLUDED."note";
+`,
+		},
+		{
+			engine: db.MSSQL,
+			table:  "country",
+			want: `DELETE FROM [country] WHERE [code] = 'DE';
+MERGE INTO [country] AS target USING (VALUES ('FR', 'France''s', NULL)) AS source ([code], [name], [note]) ON target.[code] = source.[code] WHEN MATCHED THEN UPDATE SET target.[name] = source.[name], target.[note] = source.[note] WHEN NOT MATCHED THEN INSERT ([code], [name], [note]) VALUES (source.[code], source.[name], source.[note]);
+`,
+		},
+		{
+			engine: db.Oracle,
+			table:  "COUNTRY",
+			want: `DELETE FROM COUNTRY WHERE code = 'DE';
+MERGE INTO COUNTRY target USING (SELECT 'FR' code, 'France''s' name, NULL note FROM DUAL) source ON (target.code = source.code) WHEN MATCHED THEN UPDATE SET target.name = source.name, target.note = source.note WHEN NOT MATCHED THEN INSERT (code, name, note) VALUES (source.code, source.name, source.note);
+`,
+		},
+	}
+
+	for _, test := range tests {
+		statement, err := GenerateSeedDataStatement(test.engine, test.table, columns, []string{"code"}, diff)
+		require.NoError(t, err)
+		require.Equal(t, test.want, statement, test.engine)
+	}
+
+	// The rows with only the key columns are inserted if absent.
+	statement, err := GenerateSeedDataStatement(db.Postgres, "tag", []string{"name"}, []string{"name"}, &SeedDataDiff{Inserts: [][]*string{seedRow("a")}})
+	require.NoError(t, err)
+	require.Equal(t, `INSERT INTO "tag" ("name") VALUES ('a') ON CONFLICT ("name") DO NOTHING;`+"\n", statement)
+
+	_, err = GenerateSeedDataStatement(db.Snowflake, "tag", []string{"name"}, []string{"name"}, diff)
+	require.EqualError(t, err, `seed data change is not supported for engine "SNOWFLAKE"`)
+}
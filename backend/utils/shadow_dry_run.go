@@ -0,0 +1,327 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/backend/plugin/db"
+	parser "github.com/bytebase/bytebase/backend/plugin/parser/sql"
+)
+
+// ShadowDryRunReport is the report of applying the migration to the shadow database.
+type ShadowDryRunReport struct {
+	// SampledRows is the number of the rows copied into the shadow database.
+	SampledRows int64
+	// SampleWarnings are the tables failed to be sampled, the dry run continues with the rows copied so far.
+	SampleWarnings []string
+	// StatementList is the executed statements, the last one is the failed statement if Error is not empty.
+	StatementList []*ShadowDryRunStatement
+	// Duration is the total execution time of the statements.
+	Duration time.Duration
+	// Error is the error of the failed statement.
+	Error string
+	// RowLockWaits and RowLockTimeMs are the increments of the InnoDB row lock waits and time in milliseconds
+	// during the execution. They are instance-wide, and only reported for MySQL.
+	RowLockWaits  int64
+	RowLockTimeMs int64
+	// PostCheckErrors are the problems found in the shadow database after the migration.
+	PostCheckErrors []string
+}
+
+// ShadowDryRunStatement is the execution statistics of a statement in the shadow database.
+type ShadowDryRunStatement struct {
+	Statement string
+	Duration  time.Duration
+	// ExclusiveLocks are the relations locked in the ACCESS EXCLUSIVE mode by the statement, which block all the
+	// reads and writes of the relations. They are only reported for PostgreSQL.
+	ExclusiveLocks []string
+}
+
+// SlowestStatements returns at most n statements in the descending order of the duration.
+func (r *ShadowDryRunReport) SlowestStatements(n int) []*ShadowDryRunStatement {
+	statements := make([]*ShadowDryRunStatement, len(r.StatementList))
+	copy(statements, r.StatementList)
+	sort.SliceStable(statements, func(i, j int) bool {
+		return statements[i].Duration > statements[j].Duration
+	})
+	if len(statements) > n {
+		statements = statements[:n]
+	}
+	return statements
+}
+
+// RunShadowDryRun restores the schema into the empty shadow database, copies at most sampleRows rows of each table
+// from the source database, applies the statement and runs the post-checks.
+// The statement failure is reported in the report instead of the error, which is only returned if the shadow
+// database could not be prepared.
+func RunShadowDryRun(ctx context.Context, engine db.Type, source, shadow db.Driver, schema, statement string, sampleRows int) (*ShadowDryRunReport, error) {
+	var parserEngine parser.EngineType
+	switch engine {
+	case db.MySQL:
+		parserEngine = parser.MySQL
+	case db.Postgres:
+		parserEngine = parser.Postgres
+	default:
+		return nil, errors.Errorf("shadow database dry run is not supported for engine %q", engine)
+	}
+	singleSQLs, err := parser.SplitMultiSQL(parserEngine, statement)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to split the statement")
+	}
+
+	if err := shadow.Restore(ctx, strings.NewReader(schema)); err != nil {
+		return nil, errors.Wrap(err, "failed to restore the schema into the shadow database")
+	}
+	conn, err := shadow.GetDB().Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	report := &ShadowDryRunReport{}
+	if sampleRows > 0 {
+		if err := sampleShadowTables(ctx, engine, source.GetDB(), shadow, conn, sampleRows, report); err != nil {
+			return nil, err
+		}
+	}
+
+	var waits, lockTime int64
+	if engine == db.MySQL {
+		if waits, lockTime, err = getMySQLRowLockStatus(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+	for _, singleSQL := range singleSQLs {
+		if singleSQL.Empty {
+			continue
+		}
+		stat := &ShadowDryRunStatement{Statement: singleSQL.Text}
+		start := time.Now()
+		if engine == db.Postgres && !isPostgresNonTransactionalStatement(singleSQL.Text) {
+			stat.ExclusiveLocks, err = execPostgresStatementWithLocks(ctx, conn, singleSQL.Text)
+		} else {
+			_, err = conn.ExecContext(ctx, singleSQL.Text)
+		}
+		stat.Duration = time.Since(start)
+		report.Duration += stat.Duration
+		report.StatementList = append(report.StatementList, stat)
+		if err != nil {
+			report.Error = err.Error()
+			break
+		}
+	}
+	if engine == db.MySQL {
+		newWaits, newLockTime, err := getMySQLRowLockStatus(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+		report.RowLockWaits, report.RowLockTimeMs = newWaits-waits, newLockTime-lockTime
+	}
+	if report.Error != "" {
+		return report, nil
+	}
+
+	postCheckErrors, err := runShadowPostChecks(ctx, engine, shadow, conn)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run the post-checks in the shadow database")
+	}
+	report.PostCheckErrors = postCheckErrors
+	return report, nil
+}
+
+func sampleShadowTables(ctx context.Context, engine db.Type, source *sql.DB, shadow db.Driver, conn *sql.Conn, sampleRows int, report *ShadowDryRunReport) error {
+	metadata, err := shadow.SyncDBSchema(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to sync the shadow database schema")
+	}
+	if engine == db.MySQL {
+		// The sampled rows may reference the rows not sampled.
+		if _, err := conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 0"); err != nil {
+			return err
+		}
+		defer func() {
+			_, _ = conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 1")
+		}()
+	}
+	for _, schema := range metadata.Schemas {
+		for _, table := range schema.Tables {
+			tableName := shadowTableName(engine, schema.Name, table.Name)
+			count, err := copyTableSample(ctx, engine, source, conn, tableName, sampleRows)
+			report.SampledRows += count
+			if err != nil {
+				report.SampleWarnings = append(report.SampleWarnings, fmt.Sprintf("failed to sample table %s: %v", tableName, err))
+			}
+		}
+	}
+	return nil
+}
+
+func copyTableSample(ctx context.Context, engine db.Type, source *sql.DB, conn *sql.Conn, tableName string, sampleRows int) (int64, error) {
+	rows, err := source.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s LIMIT %d", tableName, sampleRows))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	var quotedColumns, placeholders []string
+	for i, column := range columns {
+		if engine == db.Postgres {
+			quotedColumns = append(quotedColumns, quotePostgresIdentifier(column))
+			placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		} else {
+			quotedColumns = append(quotedColumns, quoteMySQLIdentifier(column))
+			placeholders = append(placeholders, "?")
+		}
+	}
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", tableName, strings.Join(quotedColumns, ", "), strings.Join(placeholders, ", "))
+
+	var count int64
+	for rows.Next() {
+		values := make([]any, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return count, err
+		}
+		if _, err := conn.ExecContext(ctx, insert, values...); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+func shadowTableName(engine db.Type, schema, table string) string {
+	if engine == db.Postgres {
+		return fmt.Sprintf("%s.%s", quotePostgresIdentifier(schema), quotePostgresIdentifier(table))
+	}
+	return quoteMySQLIdentifier(table)
+}
+
+// execPostgresStatementWithLocks executes the statement in a transaction, and returns the relations locked in the
+// ACCESS EXCLUSIVE mode before committing.
+func execPostgresStatementWithLocks(ctx context.Context, conn *sql.Conn, statement string) ([]string, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, statement); err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx, `
+		SELECT DISTINCT relation::regclass::text
+		FROM pg_catalog.pg_locks
+		WHERE pid = pg_backend_pid() AND locktype = 'relation' AND mode = 'AccessExclusiveLock' AND granted
+		ORDER BY 1`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var locks []string
+	for rows.Next() {
+		var relation string
+		if err := rows.Scan(&relation); err != nil {
+			return nil, err
+		}
+		locks = append(locks, relation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return locks, tx.Commit()
+}
+
+var concurrentIndexStatementRegexp = regexp.MustCompile(`^(CREATE\s+(UNIQUE\s+)?INDEX|DROP\s+INDEX|REINDEX\s+\w+)\s+CONCURRENTLY\b`)
+
+// isPostgresNonTransactionalStatement returns true if the statement cannot run inside a transaction block.
+func isPostgresNonTransactionalStatement(statement string) bool {
+	upper := strings.ToUpper(strings.TrimSpace(statement))
+	if concurrentIndexStatementRegexp.MatchString(upper) {
+		return true
+	}
+	for _, prefix := range []string{"VACUUM", "CREATE DATABASE", "DROP DATABASE", "ALTER SYSTEM", "CREATE TABLESPACE", "DROP TABLESPACE"} {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func getMySQLRowLockStatus(ctx context.Context, conn *sql.Conn) (int64, int64, error) {
+	rows, err := conn.QueryContext(ctx, "SHOW GLOBAL STATUS WHERE Variable_name IN ('Innodb_row_lock_waits', 'Innodb_row_lock_time')")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+	var waits, lockTime int64
+	for rows.Next() {
+		var name string
+		var value int64
+		if err := rows.Scan(&name, &value); err != nil {
+			return 0, 0, err
+		}
+		if name == "Innodb_row_lock_waits" {
+			waits = value
+		} else {
+			lockTime = value
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+	return waits, lockTime, nil
+}
+
+// runShadowPostChecks checks that the views are still queryable and, for PostgreSQL, that no index is left invalid.
+func runShadowPostChecks(ctx context.Context, engine db.Type, shadow db.Driver, conn *sql.Conn) ([]string, error) {
+	metadata, err := shadow.SyncDBSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	for _, schema := range metadata.Schemas {
+		for _, view := range schema.Views {
+			viewName := shadowTableName(engine, schema.Name, view.Name)
+			rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s LIMIT 0", viewName))
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("view %s is broken: %v", viewName, err))
+				continue
+			}
+			rows.Close()
+		}
+	}
+	if engine != db.Postgres {
+		return problems, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT indexrelid::regclass::text FROM pg_catalog.pg_index WHERE NOT indisvalid ORDER BY 1")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			return nil, err
+		}
+		problems = append(problems, fmt.Sprintf("index %s is invalid", index))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPostgresNonTransactionalStatement(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      bool
+	}{
+		{statement: "CREATE INDEX CONCURRENTLY idx_t_a ON t (a);", want: true},
+		{statement: "REINDEX INDEX CONCURRENTLY idx_t_a;", want: true},
+		{statement: "  vacuum full t;", want: true},
+		{statement: "CREATE DATABASE db;", want: true},
+		{statement: "CREATE INDEX idx_t_a ON t (a);", want: false},
+		{statement: "ALTER TABLE t ADD COLUMN concurrently int;", want: false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, isPostgresNonTransactionalStatement(test.statement), test.statement)
+	}
+}
+
+func TestShadowDryRunReportSlowestStatements(t *testing.T) {
+	report := &ShadowDryRunReport{
+		StatementList: []*ShadowDryRunStatement{
+			{Statement: "a", Duration: 2 * time.Second},
+			{Statement: "b", Duration: 5 * time.Second},
+			{Statement: "c", Duration: time.Second},
+		},
+	}
+
+	var statements []string
+	for _, statement := range report.SlowestStatements(2) {
+		statements = append(statements, statement.Statement)
+	}
+	require.Equal(t, []string{"b", "a"}, statements)
+	require.Equal(t, "a", report.StatementList[0].Statement)
+	require.Len(t, report.SlowestStatements(5), 3)
+}
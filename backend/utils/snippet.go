@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"github.com/pkg/errors"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+// ValidateSnippet validates the variables of the snippet, and that the placeholders in the content refer to them.
+// The placeholders are the same as the ones of the notebook cells.
+func ValidateSnippet(content string, variableList []*api.SnippetVariable) error {
+	variables := make(map[string]bool)
+	for _, variable := range variableList {
+		if !notebookParameterNameRegexp.MatchString(variable.Name) {
+			return errors.Errorf("invalid variable name %q", variable.Name)
+		}
+		if variables[variable.Name] {
+			return errors.Errorf("duplicate variable %q", variable.Name)
+		}
+		variables[variable.Name] = true
+	}
+	for _, match := range notebookPlaceholderRegexp.FindAllStringSubmatch(content, -1) {
+		if !variables[match[1]] {
+			return errors.Errorf("the content refers the undefined variable %q", match[1])
+		}
+	}
+	return nil
+}
+
+// RenderSnippet replaces the "{{name}}" placeholders in the content of the validated snippet with the variable
+// values, the missing ones use the defaults. The values are inserted as they are, since the snippet is inserted in
+// the editor instead of being executed.
+func RenderSnippet(content string, variableList []*api.SnippetVariable, values map[string]string) string {
+	defaultValues := make(map[string]string)
+	for _, variable := range variableList {
+		defaultValues[variable.Name] = variable.DefaultValue
+	}
+	return notebookPlaceholderRegexp.ReplaceAllStringFunc(content, func(placeholder string) string {
+		name := notebookPlaceholderRegexp.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		if value, ok := defaultValues[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
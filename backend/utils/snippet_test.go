@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	api "github.com/bytebase/bytebase/backend/legacyapi"
+)
+
+func TestValidateSnippet(t *testing.T) {
+	variableList := []*api.SnippetVariable{
+		{Name: "table", DefaultValue: "orders"},
+		{Name: "days", DefaultValue: "7"},
+	}
+	require.NoError(t, ValidateSnippet("SELECT * FROM {{table}} WHERE created_at > now() - interval '{{ days }} days'", variableList))
+	require.NoError(t, ValidateSnippet("SELECT 1", nil))
+
+	require.Error(t, ValidateSnippet("SELECT * FROM {{missing}}", variableList))
+	require.Error(t, ValidateSnippet("SELECT 1", []*api.SnippetVariable{{Name: "x"}, {Name: "x"}}))
+	require.Error(t, ValidateSnippet("SELECT 1", []*api.SnippetVariable{{Name: "1x"}}))
+}
+
+func TestRenderSnippet(t *testing.T) {
+	variableList := []*api.SnippetVariable{
+		{Name: "table", DefaultValue: "orders"},
+		{Name: "days", DefaultValue: "7"},
+	}
+	content := "SELECT * FROM {{table}} WHERE created_at > now() - interval '{{ days }} days'"
+	require.Equal(t, "SELECT * FROM orders WHERE created_at > now() - interval '7 days'", RenderSnippet(content, variableList, nil))
+	require.Equal(t, "SELECT * FROM users WHERE created_at > now() - interval '30 days'", RenderSnippet(content, variableList, map[string]string{"table": "users", "days": "30"}))
+}
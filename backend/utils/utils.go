@@ -22,6 +22,7 @@ import (
 	"github.com/bytebase/bytebase/backend/common"
 	"github.com/bytebase/bytebase/backend/common/log"
 	api "github.com/bytebase/bytebase/backend/legacyapi"
+	"github.com/bytebase/bytebase/backend/plugin/advisor"
 	"github.com/bytebase/bytebase/backend/plugin/db"
 	"github.com/bytebase/bytebase/backend/plugin/db/oracle"
 	"github.com/bytebase/bytebase/backend/plugin/db/util"
@@ -442,6 +443,17 @@ func PassAllCheck(task *store.TaskMessage, allowedStatus api.TaskCheckStatus, ta
 		}
 	}
 
+	// The breaking dependent objects fail the check unless it's overridden.
+	if (task.Type == api.TaskDatabaseSchemaUpdate || task.Type == api.TaskDatabaseSchemaUpdateGhostSync) && api.IsDependencyCheckSupported(engine) {
+		ok, err := passCheck(runs, api.TaskCheckDatabaseStatementDependency, allowedStatus)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
 	if task.Type == api.TaskDatabaseSchemaUpdateGhostSync {
 		ok, err := passCheck(runs, api.TaskCheckGhostSync, allowedStatus)
 		if err != nil {
@@ -489,7 +501,7 @@ func passCheck(taskCheckRunList []*store.TaskCheckRunMessage, checkType api.Task
 func ExecuteMigrationDefault(ctx context.Context, store *store.Store, driver db.Driver, mi *db.MigrationInfo, statement string, executeBeforeCommitTx func(tx *sql.Tx) error) (migrationHistoryID string, updatedSchema string, resErr error) {
 	execFunc := func(execStatement string) error {
 		if driver.GetType() == db.Oracle && executeBeforeCommitTx != nil {
-			oracleDriver, ok := driver.(*oracle.Driver)
+			oracleDriver, ok := db.UnwrapDriver(driver).(*oracle.Driver)
 			if !ok {
 				return errors.New("failed to cast driver to oracle driver")
 			}
@@ -527,7 +539,19 @@ func ExecuteMigrationWithFunc(ctx context.Context, s *store.Store, driver db.Dri
 	startedNs := time.Now().UnixNano()
 
 	defer func() {
-		if err := EndMigration(ctx, s, startedNs, insertedID, updatedSchema, resErr == nil /* isDone */); err != nil {
+		rollbackStatement := ""
+		if resErr == nil && (m.Type == db.Migrate || m.Type == db.MigrateSDL) {
+			// The rollback statement is only for reference, so the migration shouldn't fail if it can't be generated.
+			statement, err := GenerateDDLRollbackStatement(driver.GetType(), prevSchemaBuf.String(), updatedSchema)
+			if err != nil {
+				log.Warn("Failed to generate rollback statement",
+					zap.Error(err),
+					zap.String("migration_id", insertedID),
+				)
+			}
+			rollbackStatement = statement
+		}
+		if err := EndMigration(ctx, s, startedNs, insertedID, updatedSchema, rollbackStatement, resErr == nil /* isDone */); err != nil {
 			log.Error("Failed to update migration history record",
 				zap.Error(err),
 				zap.String("migration_id", migrationHistoryID),
@@ -635,7 +659,8 @@ func BeginMigration(ctx context.Context, store *store.Store, m *db.MigrationInfo
 }
 
 // EndMigration updates the migration history record to DONE or FAILED depending on migration is done or not.
-func EndMigration(ctx context.Context, storeInstance *store.Store, startedNs int64, insertedID string, updatedSchema string, isDone bool) error {
+// The rollback statement is attached to the payload if it's not empty.
+func EndMigration(ctx context.Context, storeInstance *store.Store, startedNs int64, insertedID string, updatedSchema string, rollbackStatement string, isDone bool) error {
 	migrationDurationNs := time.Now().UnixNano() - startedNs
 	update := &store.UpdateInstanceChangeHistoryMessage{
 		ID:                  insertedID,
@@ -646,6 +671,9 @@ func EndMigration(ctx context.Context, storeInstance *store.Store, startedNs int
 		status := db.Done
 		update.Status = &status
 		update.Schema = &updatedSchema
+		if rollbackStatement != "" {
+			update.RollbackStatement = &rollbackStatement
+		}
 	} else {
 		// Otherwise, update the migration history as 'FAILED', execution_duration.
 		status := db.Failed
@@ -685,15 +713,158 @@ func CheckIssueApproved(issue *store.IssueMessage) (bool, error) {
 	return FindNextPendingStep(issuePayload.Approval.ApprovalTemplates[0], issuePayload.Approval.Approvers) == nil, nil
 }
 
+// CanUserApproveEscalatedStep returns whether the user can approve the pending step of the issue as the escalation
+// approver, after the step is pending for longer than the escalation timeout of the risk level.
+func CanUserApproveEscalatedStep(ctx context.Context, s *store.Store, issueUID int, user *store.UserMessage, policy *store.IAMPolicyMessage) (bool, error) {
+	if !common.FeatureFlag(common.FeatureFlagApprovalChain) {
+		return false, nil
+	}
+	step, err := s.GetIssueApprovalStep(ctx, &store.FindIssueApprovalStepMessage{IssueUID: &issueUID})
+	if err != nil {
+		return false, err
+	}
+	if step == nil || step.EscalatedTs == 0 {
+		return false, nil
+	}
+	chain, err := GetApprovalChainSetting(ctx, s)
+	if err != nil {
+		return false, err
+	}
+	escalation := chain.FindEscalation(step.RiskLevel)
+	if escalation == nil {
+		return false, nil
+	}
+	if email, ok := strings.CutPrefix(escalation.Approver, api.ApprovalNodeUserPrefix); ok {
+		return user.Email == email, nil
+	}
+	for _, binding := range policy.Bindings {
+		if convertToRoleName(binding.Role) != escalation.Approver {
+			continue
+		}
+		for _, member := range binding.Members {
+			if member.ID == user.ID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// GetApprovalChainSetting returns the approval chain setting, which is empty if it's not set.
+func GetApprovalChainSetting(ctx context.Context, s *store.Store) (*api.SettingWorkspaceApprovalChainValue, error) {
+	name := api.SettingWorkspaceApprovalChain
+	setting, err := s.GetSettingV2(ctx, &store.FindSettingMessage{Name: &name})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", name)
+	}
+	if setting == nil {
+		return &api.SettingWorkspaceApprovalChainValue{}, nil
+	}
+	return api.ValidateAndGetApprovalChainSetting(setting.Value)
+}
+
+// GetSlackSetting returns the Slack app setting, which is nil if it's not set.
+func GetSlackSetting(ctx context.Context, s *store.Store) (*api.SettingAppSlackValue, error) {
+	name := api.SettingAppSlack
+	setting, err := s.GetSettingV2(ctx, &store.FindSettingMessage{Name: &name})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", name)
+	}
+	if setting == nil {
+		return nil, nil
+	}
+	return api.ValidateAndGetSlackSetting(setting.Value)
+}
+
+// GetTeamsSetting returns the Teams app setting, which is nil if it's not set.
+func GetTeamsSetting(ctx context.Context, s *store.Store) (*api.SettingAppTeamsValue, error) {
+	name := api.SettingAppTeams
+	setting, err := s.GetSettingV2(ctx, &store.FindSettingMessage{Name: &name})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get setting %s", name)
+	}
+	if setting == nil {
+		return nil, nil
+	}
+	return api.ValidateAndGetTeamsSetting(setting.Value)
+}
+
+// GetApproverProjectPolicy returns the project policy with the role bindings that can approve the issues of the
+// project. The custom roles without the approve permission are removed, and the custom roles assigned at the
+// workspace scope are added as if they're bound in the project.
+func GetApproverProjectPolicy(ctx context.Context, s *store.Store, projectUID int) (*store.IAMPolicyMessage, error) {
+	policy, err := s.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{UID: &projectUID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get project policy for project %d", projectUID)
+	}
+	roles, err := s.ListRoles(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list roles")
+	}
+	canApprove := make(map[api.Role]bool)
+	for _, role := range roles {
+		switch api.Role(role.ResourceID) {
+		case api.Owner, api.Developer:
+			canApprove[api.Role(role.ResourceID)] = true
+			continue
+		}
+		for _, permission := range role.PermissionList {
+			if permission == api.ProjectPermissionApproveIssue {
+				canApprove[api.Role(role.ResourceID)] = true
+				break
+			}
+		}
+	}
+
+	approverPolicy := &store.IAMPolicyMessage{}
+	bindings := make(map[api.Role]*store.PolicyBinding)
+	for _, binding := range policy.Bindings {
+		if !canApprove[binding.Role] {
+			continue
+		}
+		approverBinding := &store.PolicyBinding{Role: binding.Role, Members: append([]*store.UserMessage{}, binding.Members...)}
+		approverPolicy.Bindings = append(approverPolicy.Bindings, approverBinding)
+		bindings[binding.Role] = approverBinding
+	}
+	if !common.FeatureFlag(common.FeatureFlagRoleMember) {
+		return approverPolicy, nil
+	}
+	members, err := s.ListRoleMembers(ctx, &store.FindRoleMemberMessage{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list role members")
+	}
+	for _, member := range members {
+		role := api.Role(member.Role)
+		if !canApprove[role] {
+			continue
+		}
+		user, err := s.GetUserByID(ctx, member.PrincipalID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get user %d", member.PrincipalID)
+		}
+		if user == nil || user.MemberDeleted {
+			continue
+		}
+		binding, ok := bindings[role]
+		if !ok {
+			binding = &store.PolicyBinding{Role: role}
+			approverPolicy.Bindings = append(approverPolicy.Bindings, binding)
+			bindings[role] = binding
+		}
+		binding.Members = append(binding.Members, user)
+	}
+	return approverPolicy, nil
+}
+
 // SkipApprovalStepIfNeeded skips approval steps if no user can approve the step.
 func SkipApprovalStepIfNeeded(ctx context.Context, s *store.Store, projectUID int, approval *storepb.IssuePayloadApproval) (int, error) {
 	if len(approval.ApprovalTemplates) == 0 {
 		return 0, nil
 	}
 
-	policy, err := s.GetProjectPolicy(ctx, &store.GetProjectPolicyMessage{UID: &projectUID})
+	policy, err := GetApproverProjectPolicy(ctx, s, projectUID)
 	if err != nil {
-		return 0, errors.Wrapf(err, "failed to get project policy for project %d", projectUID)
+		return 0, err
 	}
 
 	var users []*store.UserMessage
@@ -714,6 +885,22 @@ func SkipApprovalStepIfNeeded(ctx context.Context, s *store.Store, projectUID in
 			users = append(users, userMessages[0])
 		}
 	}
+	// The named approvers of the steps are looked up, so the steps of the deactivated users are skipped.
+	for _, step := range approval.ApprovalTemplates[0].Flow.Steps {
+		for _, node := range step.Nodes {
+			email, ok := strings.CutPrefix(node.GetRole(), api.ApprovalNodeUserPrefix)
+			if !ok {
+				continue
+			}
+			user, err := s.GetUser(ctx, &store.FindUserMessage{Email: &email})
+			if err != nil {
+				return 0, errors.Wrapf(err, "failed to get user %q", email)
+			}
+			if user != nil && !user.MemberDeleted {
+				users = append(users, user)
+			}
+		}
+	}
 	stepsSkipped := 0
 	for {
 		step := FindNextPendingStep(approval.ApprovalTemplates[0], approval.Approvers)
@@ -787,6 +974,18 @@ func userCanApprove(step *storepb.ApprovalStep, users []*store.UserMessage, poli
 			return false, errors.Errorf("invalid group value")
 		}
 	case *storepb.ApprovalNode_Role:
+		if email, ok := strings.CutPrefix(val.Role, api.ApprovalNodeUserPrefix); ok {
+			for _, user := range users {
+				if user.Email == email {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		// The external approver is always there, the step waits for its callback.
+		if strings.HasPrefix(val.Role, api.ApprovalNodeExternalApproverPrefix) {
+			return true, nil
+		}
 		return projectRoleExist[val.Role], nil
 	default:
 		return false, errors.Errorf("invalid node payload type")
@@ -840,3 +1039,70 @@ func GetSecretMapFromDatabaseMessage(databaseMessage *store.DatabaseMessage) map
 	}
 	return materials
 }
+
+// GetUnusedIndexList gets the unused indexes of the database for the SQL review.
+func GetUnusedIndexList(ctx context.Context, s *store.Store, databaseUID int) ([]*advisor.UnusedIndex, error) {
+	if !common.FeatureFlag(common.FeatureFlagUnusedIndex) {
+		return nil, nil
+	}
+	unusedIndexes, err := s.ListUnusedIndexes(ctx, &store.FindUnusedIndexMessage{DatabaseUID: &databaseUID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list unused indexes for database %d", databaseUID)
+	}
+	var result []*advisor.UnusedIndex
+	for _, index := range unusedIndexes {
+		result = append(result, &advisor.UnusedIndex{
+			Schema:      index.SchemaName,
+			Table:       index.TableName,
+			Name:        index.IndexName,
+			UnusedSince: index.UnusedSince,
+		})
+	}
+	return result, nil
+}
+
+// maxChangeHistoryForSQLReview is the max number of the latest change history loaded for the SQL review.
+const maxChangeHistoryForSQLReview = 200
+
+// GetChangeHistoryListForSQLReview gets the applied change history of the database for the SQL review rules checking the previous changes.
+// It returns nil if none of these rules is enabled to avoid loading the change history unnecessarily.
+func GetChangeHistoryListForSQLReview(ctx context.Context, s *store.Store, database *store.DatabaseMessage, ruleList []*advisor.SQLReviewRule) ([]*advisor.ChangeHistory, error) {
+	required := false
+	for _, rule := range ruleList {
+		if rule.Type == advisor.SchemaRuleIndexDropRequireInvisible && rule.Level != advisor.SchemaRuleLevelDisabled {
+			required = true
+			break
+		}
+	}
+	if !required {
+		return nil, nil
+	}
+
+	instance, err := s.GetInstanceV2(ctx, &store.FindInstanceMessage{ResourceID: &database.InstanceID})
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, errors.Errorf("instance %q not found", database.InstanceID)
+	}
+	limit := maxChangeHistoryForSQLReview
+	histories, err := s.ListInstanceChangeHistory(ctx, &store.FindInstanceChangeHistoryMessage{
+		InstanceID: &instance.UID,
+		DatabaseID: &database.UID,
+		Limit:      &limit,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list change history for database %q", database.DatabaseName)
+	}
+	var result []*advisor.ChangeHistory
+	for _, history := range histories {
+		if history.Status != db.Done {
+			continue
+		}
+		result = append(result, &advisor.ChangeHistory{
+			Statement:   history.Statement,
+			CreatedTime: time.Unix(history.CreatedTs, 0),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,3 @@
+import DiscoveredInstancePanel from "./DiscoveredInstancePanel.vue";
+
+export { DiscoveredInstancePanel };
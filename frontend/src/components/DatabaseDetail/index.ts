@@ -1,5 +1,15 @@
+import ChangeHistoryExportButton from "./ChangeHistoryExportButton.vue";
+import ChangeHistoryImportButton from "./ChangeHistoryImportButton.vue";
 import PITRRestoreButton from "./PITRRestoreButton.vue";
 import SQLEditorButton from "./SQLEditorButton.vue";
+import SchemaCompareButton from "./SchemaCompareButton.vue";
 import { DatabaseSettingsPanel } from "./Settings";
 
-export { PITRRestoreButton, SQLEditorButton, DatabaseSettingsPanel };
+export {
+  ChangeHistoryExportButton,
+  ChangeHistoryImportButton,
+  PITRRestoreButton,
+  SQLEditorButton,
+  SchemaCompareButton,
+  DatabaseSettingsPanel,
+};
@@ -228,6 +228,13 @@ export const useCommonLogic = () => {
         sheetId: taskCreate.sheetId,
         earliestAllowedTs: taskCreate.earliestAllowedTs,
         rollbackEnabled: taskCreate.rollbackEnabled,
+        zeroDowntime: taskCreate.zeroDowntime,
+        chunked: taskCreate.chunked,
+        seed: taskCreate.seed,
+        shadowDryRun: taskCreate.shadowDryRun,
+        resumable: taskCreate.resumable,
+        hooks: taskCreate.hooks,
+        assertions: taskCreate.assertions,
       };
       // Create a new sheet to save statement.
       if (!taskCreate.sheetId || taskCreate.sheetId === UNKNOWN_ID) {
@@ -1,7 +1,14 @@
 export * from "./types";
 import SchemaDiagram from "./SchemaDiagram.vue";
 import SchemaDiagramIcon from "./SchemaDiagramIcon.vue";
+import SchemaExportButton from "./SchemaExportButton.vue";
+import SchemaImportButton from "./SchemaImportButton.vue";
 
-export { SchemaDiagram, SchemaDiagramIcon };
+export {
+  SchemaDiagram,
+  SchemaDiagramIcon,
+  SchemaExportButton,
+  SchemaImportButton,
+};
 
 export default SchemaDiagram;
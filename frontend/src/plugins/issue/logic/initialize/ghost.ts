@@ -1,4 +1,9 @@
-import { IssueCreate, IssueType, MigrationContext } from "@/types";
+import {
+  IssueCreate,
+  IssueType,
+  MigrationContext,
+  OnlineMigrationConfig,
+} from "@/types";
 import {
   findDatabaseListByQuery,
   BuildNewIssueContext,
@@ -54,6 +59,13 @@ const buildNewGhostIssue = async (
       },
     ];
   }
+  const onlineMigration = context.route.query.onlineMigration as string;
+  if (onlineMigration) {
+    // Override the online migration policy of the environments.
+    createContext.onlineMigrationConfig = JSON.parse(
+      onlineMigration
+    ) as OnlineMigrationConfig;
+  }
   helper.issueCreate!.createContext = createContext;
 
   await helper.validate();
@@ -0,0 +1,50 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  AdminSession,
+  AdminSessionCreate,
+  AdminSessionFind,
+  AdminSessionTranscript,
+} from "@/types";
+
+interface AdminSessionState {
+  adminSessionList: AdminSession[];
+}
+
+export const useAdminSessionStore = defineStore("adminSession", {
+  state: (): AdminSessionState => ({
+    adminSessionList: [],
+  }),
+  actions: {
+    async fetchAdminSessionList(find: AdminSessionFind = {}) {
+      const list: AdminSession[] = (
+        await axios.get(`/api/sql/admin-session`, { params: find })
+      ).data;
+      this.adminSessionList = list;
+      return list;
+    },
+    async createAdminSession(create: AdminSessionCreate) {
+      const session: AdminSession = (
+        await axios.post(`/api/sql/admin-session`, create)
+      ).data;
+      this.adminSessionList.unshift(session);
+      return session;
+    },
+    async endAdminSession(id: number) {
+      const session: AdminSession = (
+        await axios.post(`/api/sql/admin-session/${id}/end`)
+      ).data;
+      const i = this.adminSessionList.findIndex((item) => item.id === id);
+      if (i >= 0) {
+        this.adminSessionList[i] = session;
+      }
+      return session;
+    },
+    async fetchAdminSessionTranscript(id: number) {
+      const transcript: AdminSessionTranscript = (
+        await axios.get(`/api/sql/admin-session/${id}`)
+      ).data;
+      return transcript;
+    },
+  },
+});
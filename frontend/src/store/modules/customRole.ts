@@ -0,0 +1,47 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import { CustomRole, CustomRolePatch, PrincipalId, RoleMember } from "@/types";
+
+interface CustomRoleState {
+  roleList: CustomRole[];
+}
+
+export const useCustomRoleStore = defineStore("customRole", {
+  state: (): CustomRoleState => ({
+    roleList: [],
+  }),
+  actions: {
+    async fetchRoleList() {
+      const list: CustomRole[] = (await axios.get(`/api/role`)).data;
+      this.roleList = list;
+      return list;
+    },
+    async patchRole(resourceId: string, patch: CustomRolePatch) {
+      const role: CustomRole = (
+        await axios.patch(`/api/role/${resourceId}`, patch)
+      ).data;
+      const i = this.roleList.findIndex(
+        (item) => item.resourceId === resourceId
+      );
+      if (i >= 0) {
+        this.roleList[i] = role;
+      }
+      return role;
+    },
+    async fetchRoleMemberList(resourceId: string) {
+      const list: RoleMember[] = (
+        await axios.get(`/api/role/${resourceId}/member`)
+      ).data;
+      return list;
+    },
+    async createRoleMember(resourceId: string, principalId: PrincipalId) {
+      const member: RoleMember = (
+        await axios.post(`/api/role/${resourceId}/member`, { principalId })
+      ).data;
+      return member;
+    },
+    async deleteRoleMember(resourceId: string, principalId: PrincipalId) {
+      await axios.delete(`/api/role/${resourceId}/member/${principalId}`);
+    },
+  },
+});
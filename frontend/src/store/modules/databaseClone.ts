@@ -0,0 +1,30 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  DatabaseClone,
+  DatabaseCloneCreate,
+  DatabaseCloneStatus,
+} from "@/types";
+
+export const useDatabaseCloneStore = defineStore("databaseClone", {
+  actions: {
+    async fetchCloneList(params: { status?: DatabaseCloneStatus } = {}) {
+      const list: DatabaseClone[] = (
+        await axios.get(`/api/database-clone`, { params })
+      ).data;
+      return list;
+    },
+    async fetchCloneById(id: number) {
+      const clone: DatabaseClone = (
+        await axios.get(`/api/database-clone/${id}`)
+      ).data;
+      return clone;
+    },
+    async createClone(create: DatabaseCloneCreate) {
+      const clone: DatabaseClone = (
+        await axios.post(`/api/database-clone`, create)
+      ).data;
+      return clone;
+    },
+  },
+});
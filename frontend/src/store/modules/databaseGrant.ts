@@ -0,0 +1,36 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  DatabaseGrant,
+  DatabaseGrantCreate,
+  DatabaseGrantStatus,
+  DatabaseId,
+} from "@/types";
+
+export const useDatabaseGrantStore = defineStore("databaseGrant", {
+  actions: {
+    async fetchGrantList(
+      params: { database?: DatabaseId; status?: DatabaseGrantStatus } = {}
+    ) {
+      const list: DatabaseGrant[] = (
+        await axios.get(`/api/database-grant`, { params })
+      ).data;
+      return list;
+    },
+    async createGrant(create: DatabaseGrantCreate) {
+      const grant: DatabaseGrant = (
+        await axios.post(`/api/database-grant`, create)
+      ).data;
+      return grant;
+    },
+    async updateGrantStatus(
+      id: number,
+      action: "approve" | "reject" | "revoke"
+    ) {
+      const grant: DatabaseGrant = (
+        await axios.post(`/api/database-grant/${id}/${action}`)
+      ).data;
+      return grant;
+    },
+  },
+});
@@ -0,0 +1,30 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  ExportRequest,
+  ExportRequestCreate,
+  ExportRequestStatus,
+} from "@/types";
+
+export const useExportRequestStore = defineStore("exportRequest", {
+  actions: {
+    async fetchRequestList(params: { status?: ExportRequestStatus } = {}) {
+      const list: ExportRequest[] = (
+        await axios.get(`/api/export-request`, { params })
+      ).data;
+      return list;
+    },
+    async createRequest(create: ExportRequestCreate) {
+      const request: ExportRequest = (
+        await axios.post(`/api/export-request`, create)
+      ).data;
+      return request;
+    },
+    async updateRequestStatus(id: number, action: "approve" | "reject") {
+      const request: ExportRequest = (
+        await axios.post(`/api/export-request/${id}/${action}`)
+      ).data;
+      return request;
+    },
+  },
+});
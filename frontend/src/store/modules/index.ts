@@ -28,6 +28,15 @@ export * from "./project";
 export * from "./projectWebhook";
 export * from "./repository";
 export * from "./router";
+export * from "./scheduledQuery";
+export * from "./snippet";
+export * from "./adminSession";
+export * from "./pii";
+export * from "./maskingBundle";
+export * from "./customRole";
+export * from "./databaseGrant";
+export * from "./exportRequest";
+export * from "./databaseClone";
 export * from "./setting";
 export * from "./sheet";
 export * from "./stage";
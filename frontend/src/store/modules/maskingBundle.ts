@@ -0,0 +1,28 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import { MaskingBundleFormat, MaskingBundleImportResult } from "@/types";
+
+export const useMaskingBundleStore = defineStore("maskingBundle", {
+  actions: {
+    // exportMaskingBundle returns the bundle as the text to download.
+    async exportMaskingBundle(format: MaskingBundleFormat = "yaml") {
+      const bundle: string = (
+        await axios.get(`/api/masking/bundle`, {
+          params: { format },
+          responseType: "text",
+          transformResponse: (data) => data,
+        })
+      ).data;
+      return bundle;
+    },
+    // importMaskingBundle imports the bundle in YAML or JSON.
+    async importMaskingBundle(bundle: string) {
+      const result: MaskingBundleImportResult = (
+        await axios.post(`/api/masking/bundle/import`, bundle, {
+          headers: { "Content-Type": "text/plain" },
+        })
+      ).data;
+      return result;
+    },
+  },
+});
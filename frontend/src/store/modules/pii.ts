@@ -0,0 +1,42 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import { PIIFinding, PIIFindingFind, PIIFindingPatch } from "@/types";
+
+interface PIIFindingState {
+  piiFindingList: PIIFinding[];
+}
+
+export const usePIIFindingStore = defineStore("piiFinding", {
+  state: (): PIIFindingState => ({
+    piiFindingList: [],
+  }),
+  actions: {
+    async fetchPIIFindingList(find: PIIFindingFind = {}) {
+      const list: PIIFinding[] = (
+        await axios.get(`/api/pii/finding`, { params: find })
+      ).data;
+      this.piiFindingList = list;
+      return list;
+    },
+    async patchPIIFinding(id: number, patch: PIIFindingPatch) {
+      const finding: PIIFinding = (
+        await axios.patch(`/api/pii/finding/${id}`, patch)
+      ).data;
+      const i = this.piiFindingList.findIndex((item) => item.id === id);
+      if (i >= 0) {
+        this.piiFindingList[i] = finding;
+      }
+      return finding;
+    },
+    async scanDatabase(databaseId: number) {
+      const list: PIIFinding[] = (
+        await axios.post(`/api/database/${databaseId}/pii/scan`)
+      ).data;
+      this.piiFindingList = [
+        ...this.piiFindingList.filter((item) => item.databaseId !== databaseId),
+        ...list,
+      ];
+      return list;
+    },
+  },
+});
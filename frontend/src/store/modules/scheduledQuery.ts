@@ -0,0 +1,58 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import {
+  ScheduledQuery,
+  ScheduledQueryCreate,
+  ScheduledQueryPatch,
+  ScheduledQueryRun,
+  SheetId,
+} from "@/types";
+
+interface ScheduledQueryState {
+  scheduledQueryList: ScheduledQuery[];
+}
+
+export const useScheduledQueryStore = defineStore("scheduledQuery", {
+  state: (): ScheduledQueryState => ({
+    scheduledQueryList: [],
+  }),
+  actions: {
+    async fetchScheduledQueryList(sheetId?: SheetId) {
+      const params = sheetId ? { sheet: sheetId } : {};
+      const list: ScheduledQuery[] = (
+        await axios.get(`/api/scheduled-query`, { params })
+      ).data;
+      this.scheduledQueryList = list;
+      return list;
+    },
+    async createScheduledQuery(create: ScheduledQueryCreate) {
+      const scheduledQuery: ScheduledQuery = (
+        await axios.post(`/api/scheduled-query`, create)
+      ).data;
+      this.scheduledQueryList.push(scheduledQuery);
+      return scheduledQuery;
+    },
+    async patchScheduledQuery(id: number, patch: ScheduledQueryPatch) {
+      const scheduledQuery: ScheduledQuery = (
+        await axios.patch(`/api/scheduled-query/${id}`, patch)
+      ).data;
+      const i = this.scheduledQueryList.findIndex((item) => item.id === id);
+      if (i >= 0) {
+        this.scheduledQueryList[i] = scheduledQuery;
+      }
+      return scheduledQuery;
+    },
+    async deleteScheduledQuery(id: number) {
+      await axios.delete(`/api/scheduled-query/${id}`);
+      this.scheduledQueryList = this.scheduledQueryList.filter(
+        (item) => item.id !== id
+      );
+    },
+    async fetchScheduledQueryRunList(id: number): Promise<ScheduledQueryRun[]> {
+      return (await axios.get(`/api/scheduled-query/${id}/run`)).data;
+    },
+    async runScheduledQuery(id: number): Promise<ScheduledQueryRun> {
+      return (await axios.post(`/api/scheduled-query/${id}/run`)).data;
+    },
+  },
+});
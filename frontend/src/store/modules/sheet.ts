@@ -17,6 +17,8 @@ import {
   ProjectId,
   SheetUpsert,
   SheetPayload,
+  NotebookCellRun,
+  NotebookCellResult,
 } from "@/types";
 import { getPrincipalFromIncludedList } from "./principal";
 import { useCurrentUser } from "./auth";
@@ -321,6 +323,18 @@ export const useSheetStore = defineStore("sheet", {
         this.sheetById.delete(sheetId);
       }
     },
+    async runNotebookCell(
+      sheetId: SheetId,
+      run: NotebookCellRun
+    ): Promise<NotebookCellResult> {
+      return (await axios.post(`/api/sheet/${sheetId}/notebook/run`, run))
+        .data;
+    },
+    async fetchNotebookCellResultList(
+      sheetId: SheetId
+    ): Promise<NotebookCellResult[]> {
+      return (await axios.get(`/api/sheet/${sheetId}/notebook/result`)).data;
+    },
     async syncSheetFromVCS(projectId: ProjectId) {
       await axios.post(`/api/project/${projectId}/sync-sheet`);
     },
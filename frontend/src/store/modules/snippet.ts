@@ -0,0 +1,55 @@
+import { defineStore } from "pinia";
+import axios from "axios";
+import { Snippet, SnippetCreate, SnippetFind, SnippetPatch } from "@/types";
+
+interface SnippetState {
+  snippetList: Snippet[];
+}
+
+export const useSnippetStore = defineStore("snippet", {
+  state: (): SnippetState => ({
+    snippetList: [],
+  }),
+  actions: {
+    async fetchSnippetList(find: SnippetFind = {}) {
+      const list: Snippet[] = (
+        await axios.get(`/api/snippet`, { params: find })
+      ).data;
+      this.snippetList = list;
+      return list;
+    },
+    async createSnippet(create: SnippetCreate) {
+      const snippet: Snippet = (await axios.post(`/api/snippet`, create)).data;
+      this.snippetList.push(snippet);
+      return snippet;
+    },
+    async patchSnippet(id: number, patch: SnippetPatch) {
+      const snippet: Snippet = (await axios.patch(`/api/snippet/${id}`, patch))
+        .data;
+      const i = this.snippetList.findIndex((item) => item.id === id);
+      if (i >= 0) {
+        this.snippetList[i] = snippet;
+      }
+      return snippet;
+    },
+    async deleteSnippet(id: number) {
+      await axios.delete(`/api/snippet/${id}`);
+      this.snippetList = this.snippetList.filter((item) => item.id !== id);
+    },
+    // insertSnippet returns the content with the variables filled to insert
+    // in the editor, and records the usage.
+    async insertSnippet(
+      id: number,
+      variableValues: Record<string, string> = {}
+    ): Promise<string> {
+      const result: { content: string } = (
+        await axios.post(`/api/snippet/${id}/insert`, { variableValues })
+      ).data;
+      const snippet = this.snippetList.find((item) => item.id === id);
+      if (snippet) {
+        snippet.usageCount++;
+      }
+      return result.content;
+    },
+  },
+});
@@ -10,6 +10,15 @@ import {
   SQLResultSet,
   Advice,
   SingleSQLResult,
+  SQLCursorPage,
+  SQLExplainInfo,
+  QueryPlan,
+  SQLCompletionInfo,
+  SQLCompletionCandidate,
+  SQLResultDiffInfo,
+  SQLResultDiff,
+  SQLResultChartInfo,
+  SQLResultChart,
   Attributes,
 } from "@/types";
 import { useDatabaseStore } from "./database";
@@ -22,6 +31,9 @@ export function convertSingleSQLResult(
     return {
       data: JSON.parse((attributes.data as string) || "null"),
       error: attributes.error as string,
+      cursorId: (attributes.cursorId as string) || undefined,
+      rowCount: attributes.rowCount as number,
+      truncated: attributes.truncated as boolean,
     };
   } catch {
     return {
@@ -142,6 +154,47 @@ export const useSQLStore = defineStore("sql", {
 
       return resultSet;
     },
+    async fetchCursorPage(
+      cursorId: string,
+      offset: number,
+      limit: number
+    ): Promise<SQLCursorPage> {
+      return (
+        await axios.get(`/api/sql/cursor/${cursorId}`, {
+          params: { offset, limit },
+        })
+      ).data;
+    },
+    async fetchCursorChart(
+      cursorId: string,
+      chartInfo: SQLResultChartInfo
+    ): Promise<SQLResultChart> {
+      return (await axios.post(`/api/sql/cursor/${cursorId}/chart`, chartInfo))
+        .data;
+    },
+    async cancelQuery(queryId: string) {
+      await axios.post(`/api/sql/query/${queryId}/cancel`);
+    },
+    async explain(explainInfo: SQLExplainInfo): Promise<QueryPlan> {
+      return (
+        await axios.post(`/api/sql/explain`, explainInfo, {
+          timeout: INSTANCE_OPERATION_TIMEOUT,
+        })
+      ).data;
+    },
+    async complete(
+      completionInfo: SQLCompletionInfo
+    ): Promise<SQLCompletionCandidate[]> {
+      const res = (await axios.post(`/api/sql/complete`, completionInfo)).data;
+      return res.candidateList ?? [];
+    },
+    async diff(diffInfo: SQLResultDiffInfo): Promise<SQLResultDiff> {
+      return (
+        await axios.post(`/api/sql/diff`, diffInfo, {
+          timeout: INSTANCE_OPERATION_TIMEOUT,
+        })
+      ).data;
+    },
     async adminQuery(queryInfo: QueryInfo): Promise<SQLResultSet> {
       const res = (
         await axios.post(
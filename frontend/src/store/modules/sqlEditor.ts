@@ -1,9 +1,13 @@
 import { defineStore } from "pinia";
+import axios from "axios";
 import dayjs from "dayjs";
+import { v4 as uuidv4 } from "uuid";
 import {
   SQLEditorState,
   QueryInfo,
   QueryHistory,
+  QueryHistorySearch,
+  QueryHistoryRecord,
   ActivitySQLEditorQueryPayload,
   SingleSQLResult,
 } from "@/types";
@@ -40,30 +44,55 @@ export const useSQLEditorStore = defineStore("sqlEditor", {
       this.isFetchingQueryHistory = payload;
     },
     async executeQuery({ statement }: Pick<QueryInfo, "statement">) {
-      const { instanceId, databaseId } = useTabStore().currentTab.connection;
+      const tab = useTabStore().currentTab;
+      const { connection, sessionVariableList } = tab;
+      const { instanceId, databaseId } = connection;
       const database = useDatabaseStore().getDatabaseById(databaseId);
       const databaseName = database.id === UNKNOWN_ID ? "" : database.name;
-      const queryResult = await useSQLStore().query({
-        instanceId,
-        databaseName,
-        statement: statement,
-        limit: RESULT_ROWS_LIMIT,
-      });
-
-      return queryResult;
+      const queryId = uuidv4();
+      tab.runningQueryId = queryId;
+      try {
+        return await useSQLStore().query({
+          instanceId,
+          databaseName,
+          statement: statement,
+          limit: RESULT_ROWS_LIMIT,
+          // The result beyond the limit is paged through the cursor.
+          cursor: true,
+          sessionVariableList,
+          queryId,
+        });
+      } finally {
+        tab.runningQueryId = undefined;
+      }
     },
     async executeAdminQuery({ statement }: Pick<QueryInfo, "statement">) {
-      const { instanceId, databaseId } = useTabStore().currentTab.connection;
+      const tab = useTabStore().currentTab;
+      const { connection, adminSessionId } = tab;
+      const { instanceId, databaseId } = connection;
       const database = useDatabaseStore().getDatabaseById(databaseId);
       const databaseName = database.id === UNKNOWN_ID ? "" : database.name;
-      const queryResult = await useSQLStore().adminQuery({
-        instanceId,
-        databaseName,
-        statement: statement,
-        limit: RESULT_ROWS_LIMIT,
-      });
-
-      return queryResult;
+      const queryId = uuidv4();
+      tab.runningQueryId = queryId;
+      try {
+        return await useSQLStore().adminQuery({
+          instanceId,
+          databaseName,
+          statement: statement,
+          limit: RESULT_ROWS_LIMIT,
+          adminSessionId,
+          queryId,
+        });
+      } finally {
+        tab.runningQueryId = undefined;
+      }
+    },
+    // cancelQuery kills the query running in the current tab.
+    async cancelQuery() {
+      const { runningQueryId } = useTabStore().currentTab;
+      if (runningQueryId) {
+        await useSQLStore().cancelQuery(runningQueryId);
+      }
     },
     async fetchQueryHistoryList() {
       this.setIsFetchingQueryHistory(true);
@@ -95,6 +124,11 @@ export const useSQLEditorStore = defineStore("sqlEditor", {
       );
       this.setIsFetchingQueryHistory(false);
     },
+    async searchQueryHistoryList(
+      search: QueryHistorySearch
+    ): Promise<QueryHistoryRecord[]> {
+      return (await axios.get(`/api/sql/history`, { params: search })).data;
+    },
   },
 });
 
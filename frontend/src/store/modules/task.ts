@@ -41,7 +41,7 @@ function convertTaskRun(
   return {
     ...(taskRun.attributes as Omit<
       TaskRun,
-      "id" | "result" | "payload" | "creator" | "updater"
+      "id" | "result" | "payload" | "progress" | "creator" | "updater"
     >),
     id: parseInt(taskRun.id),
     creator: getPrincipalFromIncludedList(
@@ -54,6 +54,9 @@ function convertTaskRun(
     ),
     result,
     payload,
+    progress: taskRun.attributes.progress
+      ? convertTaskProgress(taskRun.attributes.progress)
+      : undefined,
   };
 }
 
@@ -92,9 +95,9 @@ function convertTaskProgress(attributes: any): TaskProgress {
   if (!attributes) return unknown("TASK_PROGRESS");
 
   const progress: TaskProgress = { ...attributes };
-  if (typeof attributes.comment === "string") {
+  if (typeof attributes.payload === "string" && attributes.payload !== "") {
     try {
-      progress.payload = JSON.parse(attributes.comment);
+      progress.payload = JSON.parse(attributes.payload);
     } catch {
       progress.payload = undefined;
     }
@@ -315,6 +318,52 @@ export const useTaskStore = defineStore("task", {
 
       useIssueStore().fetchIssueById(issueId);
     },
+    async patchChunkedDML({
+      issueId,
+      pipelineId,
+      taskId,
+      paused,
+    }: {
+      issueId: IssueId;
+      pipelineId: PipelineId;
+      taskId: TaskId;
+      paused: boolean;
+    }) {
+      const data = (
+        await axios.patch(
+          `/api/pipeline/${pipelineId}/task/${taskId}/chunked-dml`,
+          { paused }
+        )
+      ).data;
+      const task = this.convertPartial(data.data, data.included);
+
+      useIssueStore().fetchIssueById(issueId);
+
+      return task;
+    },
+    async overrideDependency({
+      issueId,
+      pipelineId,
+      taskId,
+      reason,
+    }: {
+      issueId: IssueId;
+      pipelineId: PipelineId;
+      taskId: TaskId;
+      reason: string;
+    }) {
+      const data = (
+        await axios.patch(
+          `/api/pipeline/${pipelineId}/task/${taskId}/dependency-override`,
+          { reason }
+        )
+      ).data;
+      const task = this.convertPartial(data.data, data.included);
+
+      useIssueStore().fetchIssueById(issueId);
+
+      return task;
+    },
     async runChecks({
       issueId,
       pipelineId,
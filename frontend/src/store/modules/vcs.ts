@@ -23,6 +23,10 @@ function convert(vcs: ResourceObject, includedList: ResourceObject[]): VCS {
     uiType = "GITHUB_COM";
   } else if (vcs.attributes.type == "BITBUCKET") {
     uiType = "BITBUCKET_ORG";
+  } else if (vcs.attributes.type == "GITEA") {
+    uiType = "GITEA_SELF_HOST";
+  } else if (vcs.attributes.type == "BITBUCKET_SERVER") {
+    uiType = "BITBUCKET_SERVER_SELF_HOST";
   }
   return {
     ...(vcs.attributes as Omit<VCS, "id">),
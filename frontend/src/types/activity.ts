@@ -17,8 +17,11 @@ import { StageStatusUpdateType, TaskStatus } from "./pipeline";
 import { Principal } from "./principal";
 import { VCSPushEvent } from "./vcs";
 import { Advice } from "./sqlAdvice";
+import { SessionVariable } from "./sql";
 import { t } from "../plugins/i18n";
 import { ApprovalEvent } from "./review";
+import { SchemaDriftObject } from "./anomaly";
+import { DatabaseGrantLevel, DatabaseGrantStatus } from "./databaseGrant";
 
 export type IssueActivityType =
   | "bb.issue.create"
@@ -44,9 +47,15 @@ export type ProjectActivityType =
   | "bb.project.member.delete"
   | "bb.project.member.role.update";
 
-export type DatabaseActivityType = "bb.database.recovery.pitr.done";
+export type DatabaseActivityType =
+  | "bb.database.recovery.pitr.done"
+  | "bb.database.schema.drift"
+  | "bb.database.grant.request"
+  | "bb.database.grant.status.update";
 
-export type SQLEditorActivityType = "bb.sql-editor.query";
+export type SQLEditorActivityType =
+  | "bb.sql-editor.query"
+  | "bb.sql-editor.export";
 
 export type ActivityType =
   | IssueActivityType
@@ -95,6 +104,12 @@ export function activityName(type: ActivityType): string {
       return t("activity.type.project-member-role-update");
     case "bb.database.recovery.pitr.done":
       return t("activity.type.database-recovery-pitr-done");
+    case "bb.database.schema.drift":
+      return t("activity.type.database-schema-drift");
+    case "bb.database.grant.request":
+      return t("activity.type.database-grant-request");
+    case "bb.database.grant.status.update":
+      return t("activity.type.database-grant-status-update");
   }
   console.assert(false, `undefined text for activity type "${type}"`);
   return "";
@@ -210,6 +225,23 @@ export type ActivityProjectDatabaseTransferPayload = {
   databaseName: string;
 };
 
+export type ActivityDatabaseSchemaDriftPayload = {
+  databaseId: DatabaseId;
+  databaseName: string;
+  version: string;
+  objects: SchemaDriftObject[];
+};
+
+export type ActivityDatabaseGrantPayload = {
+  grantId: number;
+  databaseId: DatabaseId;
+  databaseName: string;
+  level: DatabaseGrantLevel;
+  oldStatus?: DatabaseGrantStatus;
+  newStatus: DatabaseGrantStatus;
+  expireTs: number;
+};
+
 export type ActivitySQLEditorQueryPayload = {
   statement: string;
   durationNs: number;
@@ -219,6 +251,9 @@ export type ActivitySQLEditorQueryPayload = {
   databaseName: string;
   error: string;
   adviceList: Advice[];
+  sessionVariableList?: SessionVariable[];
+  // The admin mode session the statement is recorded in.
+  adminSessionId?: number;
 };
 
 export type ActionPayloadType =
@@ -235,6 +270,8 @@ export type ActionPayloadType =
   | ActivityMemberActivateDeactivatePayload
   | ActivityProjectRepositoryPushPayload
   | ActivityProjectDatabaseTransferPayload
+  | ActivityDatabaseSchemaDriftPayload
+  | ActivityDatabaseGrantPayload
   | ActivitySQLEditorQueryPayload;
 
 export type Activity = {
@@ -0,0 +1,44 @@
+import { InstanceId } from "./id";
+
+export type AdminSessionEntryKind = "COMMAND" | "RESPONSE";
+
+// AdminSession is an admin mode session of the SQL editor, whose statements
+// and results are recorded in the transcript.
+export type AdminSession = {
+  id: number;
+  creatorId: number;
+  createdTs: number;
+  instanceId: InstanceId;
+  databaseName: string;
+  // endedTs is 0 if the session is open.
+  endedTs: number;
+  entryCount: number;
+};
+
+export type AdminSessionCreate = {
+  instanceId: InstanceId;
+  databaseName: string;
+};
+
+export type AdminSessionEntry = {
+  seq: number;
+  createdTs: number;
+  kind: AdminSessionEntryKind;
+  content: string;
+  truncated: boolean;
+  prevHash: string;
+  hash: string;
+};
+
+export type AdminSessionTranscript = {
+  session: AdminSession;
+  entryList: AdminSessionEntry[];
+  // verified is false if the hash chain of the entries is broken.
+  verified: boolean;
+  verifyError: string;
+};
+
+export type AdminSessionFind = {
+  creator?: number;
+  instance?: InstanceId;
+};
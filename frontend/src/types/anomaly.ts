@@ -16,7 +16,9 @@ export type AnomalyType =
   | "bb.anomaly.database.backup.policy-violation"
   | "bb.anomaly.database.backup.missing"
   | "bb.anomaly.database.connection"
-  | "bb.anomaly.database.schema.drift";
+  | "bb.anomaly.database.schema.drift"
+  | "bb.anomaly.instance.certificate.expiry"
+  | "bb.anomaly.database.certificate.expiry";
 
 export type AnomalyInstanceConnectionPayload = {
   detail: string;
@@ -37,17 +39,36 @@ export type AnomalyDatabaseConnectionPayload = {
   detail: string;
 };
 
+export type SchemaDriftAction = "CREATED" | "DROPPED" | "ALTERED";
+
+export type SchemaDriftObject = {
+  type: string;
+  name: string;
+  action: SchemaDriftAction;
+};
+
 export type AnomalyDatabaseSchemaDriftPayload = {
   version: string;
   expect: string;
   actual: string;
+  objects?: SchemaDriftObject[];
+};
+
+export type SchemaDriftCorrectiveMigration = {
+  statement: string;
+};
+
+export type AnomalyCertificateExpiryPayload = {
+  subject: string;
+  expireTs: number;
 };
 
 export type AnomalyPayload =
   | AnomalyDatabaseBackupPolicyViolationPayload
   | AnomalyDatabaseBackupMissingPayload
   | AnomalyDatabaseConnectionPayload
-  | AnomalyDatabaseSchemaDriftPayload;
+  | AnomalyDatabaseSchemaDriftPayload
+  | AnomalyCertificateExpiryPayload;
 
 export type AnomalySeverity = "MEDIUM" | "HIGH" | "CRITICAL";
 
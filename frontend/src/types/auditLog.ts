@@ -13,8 +13,13 @@ export enum AuditActivityType {
   DatabaseTransfer = "bb.project.database.transfer",
   ProjectMemberRoleUpdate = "bb.project.member.role.update",
 
+  // Database related
+  DatabaseGrantRequest = "bb.database.grant.request",
+  DatabaseGrantStatusUpdate = "bb.database.grant.status.update",
+
   // SQL Editor related.
   SQLEditorQuery = "bb.sql-editor.query",
+  SQLEditorExport = "bb.sql-editor.export",
 }
 
 export enum AuditActivityLevel {
@@ -37,7 +42,12 @@ export const AuditActivityTypeI18nNameMap: Record<AuditActivityType, string> = {
     "audit-log.type.project.database-transfer",
   [AuditActivityType.ProjectMemberRoleUpdate]:
     "audit-log.type.project.member-role-update",
+  [AuditActivityType.DatabaseGrantRequest]:
+    "audit-log.type.database.grant-request",
+  [AuditActivityType.DatabaseGrantStatusUpdate]:
+    "audit-log.type.database.grant-status-update",
   [AuditActivityType.SQLEditorQuery]: "audit-log.type.sql-editor-query",
+  [AuditActivityType.SQLEditorExport]: "audit-log.type.sql-editor-export",
 };
 
 export type AuditLog = {
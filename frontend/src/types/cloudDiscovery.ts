@@ -0,0 +1,34 @@
+import { EngineType } from "./instance";
+
+export type CloudProvider = "AWS" | "GCP" | "AZURE";
+
+export type DiscoveredInstanceStatus =
+  | "PROPOSED"
+  | "REGISTERED"
+  | "DISMISSED"
+  | "DELETED_UPSTREAM";
+
+export type DiscoveredInstance = {
+  id: number;
+  provider: CloudProvider;
+  externalId: string;
+  name: string;
+  engine: EngineType;
+  host: string;
+  port: string;
+  region: string;
+  labels: { [key: string]: string };
+  // environmentId is the environment suggested by the environment mapping
+  // rules, 0 if no rule matches.
+  environmentId: number;
+  // instanceId is the registered instance, 0 if not registered.
+  instanceId: number;
+  status: DiscoveredInstanceStatus;
+  lastSeenTs: number;
+};
+
+export type DiscoveredInstancePatch = {
+  status?: DiscoveredInstanceStatus;
+  // instanceId links the instance registered from the discovered instance.
+  instanceId?: number;
+};
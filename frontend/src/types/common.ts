@@ -206,6 +206,7 @@ const makeUnknown = (type: ResourceType) => {
     name: "",
     url: "",
     activityList: [],
+    payloadTemplates: "",
   };
 
   const UNKNOWN_PROJECT_MEMBER: ProjectMember = {
@@ -406,6 +407,12 @@ const makeUnknown = (type: ResourceType) => {
     filePathTemplate: "",
     schemaPathTemplate: "",
     sheetPathTemplate: "",
+    releaseTagFilter: "",
+    routingRules: "[]",
+    enableRollbackPR: false,
+    schemaWriteBackPR: false,
+    metadataPathTemplate: "",
+    branchRules: "[]",
     enableSQLReviewCI: false,
     sqlReviewCIPullRequestURL: "",
     externalId: UNKNOWN_ID.toString(),
@@ -571,6 +578,7 @@ const makeEmpty = (type: ResourceType) => {
     name: "",
     url: "",
     activityList: [],
+    payloadTemplates: "",
   };
 
   const EMPTY_PROJECT_MEMBER: ProjectMember = {
@@ -771,6 +779,12 @@ const makeEmpty = (type: ResourceType) => {
     filePathTemplate: "",
     schemaPathTemplate: "",
     sheetPathTemplate: "",
+    releaseTagFilter: "",
+    routingRules: "[]",
+    enableRollbackPR: false,
+    schemaWriteBackPR: false,
+    metadataPathTemplate: "",
+    branchRules: "[]",
     enableSQLReviewCI: false,
     sqlReviewCIPullRequestURL: "",
     externalId: EMPTY_ID.toString(),
@@ -0,0 +1,39 @@
+import { PrincipalId } from "./id";
+
+export type ProjectPermissionType =
+  | "bb.permission.project.manage-general"
+  | "bb.permission.project.manage-member"
+  | "bb.permission.project.create-sheet"
+  | "bb.permission.project.admin-sheet"
+  | "bb.permission.project.organize-sheet"
+  | "bb.permission.project.sync-sheet"
+  | "bb.permission.project.change-database"
+  | "bb.permission.project.admin-database"
+  | "bb.permission.project.create-database"
+  | "bb.permission.project.transfer-database"
+  | "bb.permission.project.query-database"
+  | "bb.permission.project.export-data"
+  | "bb.permission.project.approve-issue";
+
+// CustomRole is the role with the permissions it's composed from, the
+// permissions of the built-in roles can't be changed.
+export type CustomRole = {
+  resourceId: string;
+  name: string;
+  description: string;
+  builtIn: boolean;
+  permissionList: ProjectPermissionType[];
+};
+
+export type CustomRolePatch = {
+  permissionList: ProjectPermissionType[];
+};
+
+// RoleMember is the custom role assigned at the workspace scope, whose
+// permissions are granted in all projects.
+export type RoleMember = {
+  creatorId: PrincipalId;
+  createdTs: number;
+  role: string;
+  principalId: PrincipalId;
+};
@@ -26,6 +26,97 @@ export type DataSourceOptions = {
   sshUser: string;
   sshPassword: string;
   sshPrivateKey: string;
+  // SSH bastions hopped through in order before reaching the SSH host.
+  sshJumpHostList?: SSHJumpHost[];
+  // SOCKS5 proxy to dial the database, or the first SSH hop, through.
+  socks5Proxy?: SOCKS5Proxy;
+  // The password is used if authenticationType is empty.
+  authenticationType?: AuthenticationType;
+  // AWS credential for the RDS IAM authentication, the default credential chain
+  // is used if awsAccessKeyId is empty. awsSecretAccessKey is write-only.
+  awsRegion?: string;
+  awsAccessKeyId?: string;
+  awsSecretAccessKey?: string;
+  // The Cloud SQL connector dials the instance connection name instead of the
+  // host and port. gcpCredentialsJson is write-only, the application default
+  // credentials are used if it's empty.
+  gcpCloudSqlConnectionName?: string;
+  gcpCloudSqlUsePrivateIp?: boolean;
+  gcpCredentialsJson?: string;
+  // Azure AD authentication uses the service principal if azureClientSecret
+  // is set, otherwise the managed identity. azureClientSecret is write-only.
+  azureTenantId?: string;
+  azureClientId?: string;
+  azureClientSecret?: string;
+  // Kerberos authentication logs in the username in kerberosRealm with the
+  // base64-encoded kerberosKeytab, which is write-only. The KDCs are looked
+  // up in the DNS if kerberosKdc is empty.
+  kerberosRealm?: string;
+  kerberosKdc?: string;
+  kerberosSpn?: string;
+  kerberosKeytab?: string;
+  // externalSecretReference references the password in HashiCorp Vault
+  // (vault://<path>#<key>) or AWS Secrets Manager (awssm://<arn>#<key>).
+  externalSecretReference?: string;
+  // readReplicaRoutingPolicy and readReplicaFallbackToPrimary are only used
+  // for the admin data source to route the reads among the read-only ones.
+  readReplicaRoutingPolicy?: ReadReplicaRoutingPolicy;
+  readReplicaFallbackToPrimary?: boolean;
+  // maxConnections and connectionIdleTimeoutSeconds are only used for the
+  // admin data source to limit the connections to the instance. 0 means
+  // unlimited connections and the default idle timeout respectively.
+  maxConnections?: number;
+  connectionIdleTimeoutSeconds?: number;
+};
+
+// The schema sync also reads from the read-only data sources if the routing
+// policy is specified.
+export type ReadReplicaRoutingPolicy = "" | "ROUND_ROBIN" | "NEAREST";
+
+// ReadReplicaHealth is checked whenever connecting to the read-only data source.
+export type ReadReplicaHealth = {
+  dataSourceId: DataSourceId;
+  healthy: boolean;
+  latencyMs: number;
+  checkedTs: number;
+  error: string;
+};
+
+// The counters of ConnectionPoolStats are cumulative since the server starts.
+export type ConnectionPoolStats = {
+  maxConnections: number;
+  inUse: number;
+  idle: number;
+  waiting: number;
+  openCount: number;
+  reuseCount: number;
+  waitCount: number;
+  waitDurationMs: number;
+  timeoutCount: number;
+};
+
+export type AuthenticationType =
+  | "PASSWORD"
+  | "AWS_RDS_IAM"
+  | "GCP_CLOUD_SQL_IAM"
+  | "AZURE_AD"
+  | "KERBEROS";
+
+// password and privateKey are write-only.
+export type SSHJumpHost = {
+  host: string;
+  port: string;
+  user: string;
+  password: string;
+  privateKey: string;
+};
+
+// password is write-only.
+export type SOCKS5Proxy = {
+  host: string;
+  port: string;
+  user: string;
+  password: string;
 };
 
 export type DataSource = {
@@ -0,0 +1,27 @@
+import { DatabaseId, InstanceId, PrincipalId } from "./id";
+
+export type DatabaseCloneStatus = "PENDING" | "RUNNING" | "DONE" | "FAILED";
+
+// DatabaseClone is a copy of a database into an instance of a lower
+// environment, with the classified columns masked during the copy.
+export type DatabaseClone = {
+  id: number;
+  creatorId: PrincipalId;
+  createdTs: number;
+  updaterId: PrincipalId;
+  updatedTs: number;
+  sourceDatabaseId: DatabaseId;
+  targetInstanceId: InstanceId;
+  targetDatabaseName: string;
+  status: DatabaseCloneStatus;
+  // tableCount and rowCount are the progress of the copy.
+  tableCount: number;
+  rowCount: number;
+  error: string;
+};
+
+export type DatabaseCloneCreate = {
+  sourceDatabaseId: DatabaseId;
+  targetInstanceId: InstanceId;
+  targetDatabaseName: string;
+};
@@ -0,0 +1,36 @@
+import { DatabaseId, PrincipalId } from "./id";
+
+// QUERIER grants querying the database in the SQL editor, ADMIN grants
+// executing any statements in the admin mode as well.
+export type DatabaseGrantLevel = "QUERIER" | "ADMIN";
+
+export type DatabaseGrantStatus =
+  | "PENDING"
+  | "ACTIVE"
+  | "REJECTED"
+  | "REVOKED"
+  | "EXPIRED";
+
+// DatabaseGrant is the temporary elevated access to a database, which is
+// revoked automatically after it expires.
+export type DatabaseGrant = {
+  id: number;
+  creatorId: PrincipalId;
+  createdTs: number;
+  updaterId: PrincipalId;
+  updatedTs: number;
+  databaseId: DatabaseId;
+  level: DatabaseGrantLevel;
+  status: DatabaseGrantStatus;
+  durationSeconds: number;
+  justification: string;
+  // expireTs is 0 before the grant is approved.
+  expireTs: number;
+};
+
+export type DatabaseGrantCreate = {
+  databaseId: DatabaseId;
+  level: DatabaseGrantLevel;
+  durationSeconds: number;
+  justification: string;
+};
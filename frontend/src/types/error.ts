@@ -21,6 +21,11 @@ export enum MigrationErrorCode {
   MIGRATION_BASELINE_MISSING = 204,
 }
 
+export enum TaskErrorCode {
+  TASK_TIMING_NOT_ALLOWED = 301,
+  TASK_DEPENDENT_OBJECT_BROKEN = 302,
+}
+
 export enum SQLReviewPolicyErrorCode {
   EMPTY_POLICY = 2,
   STATEMENT_SYNTAX_ERROR = 201,
@@ -119,6 +124,7 @@ export type ErrorCode =
   | GeneralErrorCode
   | DBErrorCode
   | MigrationErrorCode
+  | TaskErrorCode
   | CompatibilityErrorCode
   | SQLReviewPolicyErrorCode;
 
@@ -0,0 +1,28 @@
+import { DatabaseId, PrincipalId } from "./id";
+
+export type ExportRequestStatus = "PENDING" | "APPROVED" | "REJECTED" | "USED";
+
+// ExportRequest is the approval of exporting a query result required by the
+// export policy, each approved request allows one export within a day.
+export type ExportRequest = {
+  id: number;
+  creatorId: PrincipalId;
+  createdTs: number;
+  updaterId: PrincipalId;
+  updatedTs: number;
+  databaseId: DatabaseId;
+  status: ExportRequestStatus;
+  // rowCount is the most rows allowed to export.
+  rowCount: number;
+  columnNameList: string[];
+  justification: string;
+  // expireTs is 0 before the request is approved.
+  expireTs: number;
+};
+
+export type ExportRequestCreate = {
+  databaseId: DatabaseId;
+  rowCount: number;
+  columnNameList: string[];
+  justification: string;
+};
@@ -5,6 +5,7 @@ export * from "./auditLog";
 export * from "./auth";
 export * from "./backup";
 export * from "./bookmark";
+export * from "./cloudDiscovery";
 export * from "./common";
 export * from "./const";
 export * from "./database";
@@ -30,6 +31,15 @@ export * from "./principal";
 export * from "./project";
 export * from "./projectWebhook";
 export * from "./repository";
+export * from "./scheduledQuery";
+export * from "./snippet";
+export * from "./adminSession";
+export * from "./pii";
+export * from "./maskingBundle";
+export * from "./customRole";
+export * from "./databaseGrant";
+export * from "./exportRequest";
+export * from "./databaseClone";
 export * from "./sql";
 export * from "./sqlAdvice";
 export * from "./store";
@@ -53,3 +63,4 @@ export * from "./risk";
 export * from "./workspaceApprovalSetting";
 export * from "./review";
 export * from "./slowQuery";
+export * from "./userGroup";
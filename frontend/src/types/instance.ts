@@ -1,4 +1,9 @@
-import { DataSource } from ".";
+import {
+  AuthenticationType,
+  DataSource,
+  SOCKS5Proxy,
+  SSHJumpHost,
+} from ".";
 import { RowStatus } from "./common";
 import { Environment } from "./environment";
 import {
@@ -24,6 +29,10 @@ export const EngineTypeList = [
   "REDSHIFT",
   "MARIADB",
   "OCEANBASE",
+  "ELASTICSEARCH",
+  "CASSANDRA",
+  "DYNAMODB",
+  "NEO4J",
 ] as const;
 
 export type EngineType = typeof EngineTypeList[number];
@@ -84,6 +93,11 @@ export function defaultCharset(type: EngineType): string {
       return "";
     case "REDSHIFT":
       return "UNICODE";
+    case "ELASTICSEARCH":
+    case "CASSANDRA":
+    case "DYNAMODB":
+    case "NEO4J":
+      return "";
   }
 }
 
@@ -115,6 +129,14 @@ export function engineName(type: EngineType): string {
       return "MariaDB";
     case "OCEANBASE":
       return "OceanBase";
+    case "ELASTICSEARCH":
+      return "Elasticsearch";
+    case "CASSANDRA":
+      return "Cassandra";
+    case "DYNAMODB":
+      return "DynamoDB";
+    case "NEO4J":
+      return "Neo4j";
   }
 }
 
@@ -145,6 +167,11 @@ export function defaultCollation(type: EngineType): string {
       return "";
     case "REDSHIFT":
       return "";
+    case "ELASTICSEARCH":
+    case "CASSANDRA":
+    case "DYNAMODB":
+    case "NEO4J":
+      return "";
   }
 }
 
@@ -199,6 +226,25 @@ export type InstanceCreate = {
   sshUser: string;
   sshPassword: string;
   sshPrivateKey: string;
+  // Connection over proxies.
+  sshJumpHostList?: SSHJumpHost[];
+  socks5Proxy?: SOCKS5Proxy;
+  // Authentication.
+  authenticationType?: AuthenticationType;
+  awsRegion?: string;
+  awsAccessKeyId?: string;
+  awsSecretAccessKey?: string;
+  gcpCloudSqlConnectionName?: string;
+  gcpCloudSqlUsePrivateIp?: boolean;
+  gcpCredentialsJson?: string;
+  azureTenantId?: string;
+  azureClientId?: string;
+  azureClientSecret?: string;
+  kerberosRealm?: string;
+  kerberosKdc?: string;
+  kerberosSpn?: string;
+  kerberosKeytab?: string;
+  externalSecretReference?: string;
 };
 
 export type InstancePatch = {
@@ -217,7 +263,12 @@ export type InstanceMigration = {
   error: string;
 };
 
-export type MigrationSource = "UI" | "VCS" | "LIBRARY";
+export type MigrationSource =
+  | "UI"
+  | "VCS"
+  | "LIBRARY"
+  | "FLYWAY"
+  | "LIQUIBASE";
 
 export type MigrationType = "BASELINE" | "MIGRATE" | "BRANCH" | "DATA";
 
@@ -225,6 +276,7 @@ export type MigrationStatus = "PENDING" | "DONE" | "FAILED";
 
 export type MigrationHistoryPayload = {
   pushEvent?: VCSPushEvent;
+  rollbackStatement?: string;
 };
 
 export type MigrationHistory = {
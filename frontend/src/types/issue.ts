@@ -8,7 +8,12 @@ import {
   SheetId,
   TaskId,
 } from "./id";
-import { Pipeline, PipelineCreate } from "./pipeline";
+import {
+  MigrationAssertion,
+  MigrationHook,
+  Pipeline,
+  PipelineCreate,
+} from "./pipeline";
 import { Principal } from "./principal";
 import { Project } from "./project";
 import { MigrationType } from "./instance";
@@ -55,6 +60,76 @@ export type MigrationDetail = {
   databaseId?: DatabaseId;
   rollbackEnabled?: boolean;
   rollbackDetail?: RollbackDetail;
+  // Executes the PostgreSQL schema update in the zero-downtime steps if set.
+  zeroDowntime?: ZeroDowntimeMigrationConfig;
+  // Executes the UPDATE and DELETE of the data update in the primary key range
+  // chunks if set.
+  chunked?: ChunkedDMLConfig;
+  // Syncs the table contents with the seed dataset in the data update if set.
+  seed?: SeedDataConfig;
+  // Applies the migration to a shadow database before the rollout if set.
+  shadowDryRun?: ShadowDryRunConfig;
+  // Executes the statements one by one so that the retries skip the executed
+  // statements.
+  resumable?: boolean;
+  // Runs before and after the migration of each task.
+  hooks?: MigrationHook[];
+  // Runs after the migration of each task, and holds the next stage if fails.
+  assertions?: MigrationAssertion[];
+};
+
+// ZeroDowntimeMigrationConfig is the configuration of the PostgreSQL
+// zero-downtime migration. The omitted fields mean the defaults.
+export type ZeroDowntimeMigrationConfig = {
+  lockTimeoutMs?: number;
+  maxRetries?: number;
+  batchSize?: number;
+};
+
+// ShadowDryRunConfig is the configuration of the shadow database dry run. The
+// schema is cloned without the data if sampleRows is omitted.
+export type ShadowDryRunConfig = {
+  // The max rows copied from each table.
+  sampleRows?: number;
+};
+
+export type ZeroDowntimeMigrationStep = {
+  statement: string;
+  // The backfill step runs repeatedly until no rows are affected.
+  batched: boolean;
+  rewritten: boolean;
+};
+
+// ChunkedDMLConfig is the configuration of the chunked DML execution. The
+// omitted fields mean the defaults.
+export type ChunkedDMLConfig = {
+  // The size of the primary key range of each chunk.
+  batchSize?: number;
+  sleepMs?: number;
+  // Throttles the chunks while the replication lag exceeds it.
+  maxReplicationLagSeconds?: number;
+};
+
+export type SeedDataFormat = "csv" | "json";
+
+// SeedDataConfig is the configuration of the seed data change, which keeps
+// the reference data of the table in sync with the dataset.
+export type SeedDataConfig = {
+  // The table could be qualified by the schema, e.g. "public.country".
+  table: string;
+  format: SeedDataFormat;
+  dataset: string;
+  // Must be covered by a primary key or unique constraint.
+  keyColumns: string[];
+  // Deletes the rows of the table absent from the dataset.
+  deleteMissing?: boolean;
+};
+
+export type SeedDataChange = {
+  statement: string;
+  insertCount: number;
+  updateCount: number;
+  deleteCount: number;
 };
 
 export type UpdateSchemaGhostDetail = MigrationDetail & {
@@ -70,8 +145,35 @@ export type RollbackDetail = {
   taskId: TaskId;
 };
 
+// OnlineMigrationEngine is the engine of the online schema migration,
+// "GH_OST" is the default.
+export type OnlineMigrationEngine = "GH_OST" | "PT_OSC";
+
+// OnlineMigrationConfig overrides the online migration policy of the
+// environments. The throttling parameters are only used by pt-osc, the empty
+// values mean the pt-osc defaults.
+export type OnlineMigrationConfig = {
+  engine?: OnlineMigrationEngine;
+  chunkSize?: number;
+  maxLagSeconds?: number;
+  maxLoad?: string;
+  criticalLoad?: string;
+};
+
+// RolloutStrategy controls the rollout of a migration to the tenant
+// databases. The canaries are rolled out in a separate stage ahead, the empty
+// values mean no canary, unlimited parallelism and never stopping on failures.
+export type RolloutStrategy = {
+  canaryPercentage?: number;
+  canaryDatabaseIdList?: DatabaseId[];
+  maxParallelism?: number;
+  stopOnFailureCount?: number;
+};
+
 export type MigrationContext = {
   detailList: MigrationDetail[];
+  onlineMigrationConfig?: OnlineMigrationConfig;
+  rolloutStrategy?: RolloutStrategy;
 };
 
 export type PITRContext = {
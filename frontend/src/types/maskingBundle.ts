@@ -0,0 +1,9 @@
+export type MaskingBundleFormat = "yaml" | "json";
+
+export type MaskingBundleImportResult = {
+  updatedPolicyCount: number;
+  // The databases are referred as "{instance resource id}/{database name}".
+  skippedDatabaseList: string[];
+  dismissedPiiFindingCount: number;
+  skippedPiiFindingCount: number;
+};
@@ -62,6 +62,15 @@ export function openWindowForOAuth(
       "oauth",
       "location=yes,left=200,top=200,height=640,width=720,scrollbars=yes,status=yes"
     );
+  } else if (vcsType == "GITEA") {
+    // Gitea OAuth applications are granted the full access of the user, there is no scope to request.
+    return window.open(
+      `${endpoint}?client_id=${applicationId}&redirect_uri=${encodeURIComponent(
+        redirectUrl()
+      )}&state=${stateQueryParameter}&response_type=code`,
+      "oauth",
+      "location=yes,left=200,top=200,height=640,width=480,scrollbars=yes,status=yes"
+    );
   }
   // GITLAB
   // GitLab OAuth App scopes: https://docs.gitlab.com/ee/integration/oauth_provider.html#authorized-applications
@@ -0,0 +1,34 @@
+import { SensitiveDataMaskType } from "./policy";
+
+export type PIIClassification = "EMAIL" | "PHONE" | "SSN" | "CREDIT_CARD";
+
+export type PIIFindingStatus = "PROPOSED" | "ACCEPTED" | "DISMISSED";
+
+export type PIIFinding = {
+  id: number;
+  databaseId: number;
+  schemaName: string;
+  tableName: string;
+  columnName: string;
+  classification: PIIClassification;
+  // maskType is the mask type proposed for the sensitive data policy.
+  maskType: SensitiveDataMaskType;
+  // nameMatched is true if the column name suggests the classification.
+  nameMatched: boolean;
+  // sampleCount is the number of the non-empty values sampled, and
+  // matchCount is the number matching the classification.
+  sampleCount: number;
+  matchCount: number;
+  status: PIIFindingStatus;
+  lastScannedTs: number;
+};
+
+export type PIIFindingFind = {
+  database?: number;
+  status?: PIIFindingStatus;
+};
+
+export type PIIFindingPatch = {
+  // Accepting a finding adds the column to the sensitive data policy.
+  status: PIIFindingStatus;
+};
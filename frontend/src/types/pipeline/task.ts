@@ -1,10 +1,20 @@
-import { ErrorCode, MigrationHistoryId, TaskCheckRunId } from "..";
+import {
+  ErrorCode,
+  MigrationHistoryId,
+  TaskCheckRunId,
+  ChunkedDMLConfig,
+  RolloutStrategy,
+  SeedDataConfig,
+  ShadowDryRunConfig,
+  ZeroDowntimeMigrationConfig,
+} from "..";
 import { Database } from "../database";
 import {
   BackupId,
   DatabaseId,
   InstanceId,
   IssueId,
+  PrincipalId,
   ProjectId,
   SheetId,
   TaskId,
@@ -64,6 +74,7 @@ export type TaskDatabaseSchemaBaselinePayload = {
   sheetId: SheetId;
   schemaVersion: string;
   pushEvent?: VCSPushEvent;
+  rolloutStrategy?: RolloutStrategy;
 };
 
 export type TaskDatabaseSchemaUpdatePayload = {
@@ -72,6 +83,69 @@ export type TaskDatabaseSchemaUpdatePayload = {
   statement: string;
   sheetId: SheetId;
   pushEvent?: VCSPushEvent;
+  zeroDowntime?: ZeroDowntimeMigrationConfig;
+  shadowDryRun?: ShadowDryRunConfig;
+  dependencyOverride?: DependencyOverride;
+  resumable?: boolean;
+  checkpoint?: StatementCheckpoint;
+  hooks?: MigrationHook[];
+  assertions?: MigrationAssertion[];
+  rolloutStrategy?: RolloutStrategy;
+};
+
+// DependencyOverride is the approval to roll out the schema update breaking
+// the dependent objects. It only applies to the approved sheet.
+export type DependencyOverride = {
+  sheetId: SheetId;
+  approverId: PrincipalId;
+  reason: string;
+  createdTs: number;
+};
+
+export type MigrationHookStage = "PRE" | "POST";
+
+export type MigrationHookType = "SQL" | "WEBHOOK";
+
+// MigrationHook is a script run before or after the migration of the task.
+// The failed PRE hook fails the task, while the failed POST hook is only
+// reported in the task run result.
+export type MigrationHook = {
+  stage: MigrationHookStage;
+  type: MigrationHookType;
+  statement?: string;
+  url?: string;
+};
+
+export type MigrationHookResult = {
+  stage: MigrationHookStage;
+  type: MigrationHookType;
+  error?: string;
+  durationMs: number;
+};
+
+export type MigrationAssertionExpect = "EMPTY" | "NOT_EMPTY" | "VALUE";
+
+// MigrationAssertion is a validation query run after the migration of the
+// task. The failed assertion fails the task to hold the next stage.
+export type MigrationAssertion = {
+  name: string;
+  statement: string;
+  expect: MigrationAssertionExpect;
+  // The expected value of the VALUE assertion. NULL is compared as "NULL".
+  value?: string;
+};
+
+export type MigrationAssertionResult = {
+  name: string;
+  error?: string;
+};
+
+// StatementCheckpoint is the progress of the resumable task. It only applies
+// to the executed sheet.
+export type StatementCheckpoint = {
+  sheetId: SheetId;
+  completedCount: number;
+  totalCount: number;
 };
 
 export type TaskDatabaseSchemaUpdateSDLPayload = {
@@ -80,6 +154,7 @@ export type TaskDatabaseSchemaUpdateSDLPayload = {
   statement: string;
   sheetId: SheetId;
   pushEvent?: VCSPushEvent;
+  rolloutStrategy?: RolloutStrategy;
 };
 
 export type TaskDatabaseSchemaUpdateGhostSyncPayload = {
@@ -88,6 +163,7 @@ export type TaskDatabaseSchemaUpdateGhostSyncPayload = {
   statement: string;
   sheetId: SheetId;
   pushEvent?: VCSPushEvent;
+  dependencyOverride?: DependencyOverride;
 };
 
 export type TaskDatabaseSchemaUpdateGhostCutoverPayload = {
@@ -128,6 +204,14 @@ export type TaskDatabaseDataUpdatePayload = {
   rollbackError?: string;
   rollbackFromIssueId?: IssueId;
   rollbackFromTaskId?: TaskId;
+  chunked?: ChunkedDMLConfig;
+  seed?: SeedDataConfig;
+  shadowDryRun?: ShadowDryRunConfig;
+  resumable?: boolean;
+  checkpoint?: StatementCheckpoint;
+  hooks?: MigrationHook[];
+  assertions?: MigrationAssertion[];
+  rolloutStrategy?: RolloutStrategy;
 };
 
 export type TaskDatabaseRestorePayload = {
@@ -151,6 +235,10 @@ export type TaskPayload =
 
 export type TaskProgressPayload = {
   comment: string;
+  // Only for the chunked DML tasks.
+  paused?: boolean;
+  // Only for the long-running ALTER TABLE and CREATE INDEX tasks.
+  phase?: string;
 };
 
 export type TaskProgress = {
@@ -209,6 +297,13 @@ export type TaskCreate = {
   backupId?: BackupId;
   earliestAllowedTs: number;
   rollbackEnabled?: boolean;
+  zeroDowntime?: ZeroDowntimeMigrationConfig;
+  chunked?: ChunkedDMLConfig;
+  seed?: SeedDataConfig;
+  shadowDryRun?: ShadowDryRunConfig;
+  resumable?: boolean;
+  hooks?: MigrationHook[];
+  assertions?: MigrationAssertion[];
 };
 
 export type TaskPatch = {
@@ -222,6 +317,8 @@ export type TaskStatusPatch = {
   // Domain specific fields
   status: TaskStatus;
   comment?: string;
+  // Resets the checkpoint of the resumable task when retrying it.
+  rerunFromScratch?: boolean;
 
   updatedTs?: number;
 };
@@ -233,6 +330,8 @@ export type TaskRunResultPayload = {
   detail: string;
   migrationId?: MigrationHistoryId;
   version?: string;
+  hookResultList?: MigrationHookResult[];
+  assertionResultList?: MigrationAssertionResult[];
 };
 
 export type TaskRun = {
@@ -252,6 +351,8 @@ export type TaskRun = {
   comment: string;
   result: TaskRunResultPayload;
   payload?: TaskPayload;
+  // Only for the running task run.
+  progress?: TaskProgress;
 };
 
 export type TaskCheckRunStatus = "RUNNING" | "DONE" | "FAILED" | "CANCELED";
@@ -267,7 +368,9 @@ export type TaskCheckType =
   | "bb.task-check.issue.lgtm"
   | "bb.task-check.pitr.mysql"
   | "bb.task-check.database.statement.type.report"
-  | "bb.task-check.database.statement.affected-rows.report";
+  | "bb.task-check.database.statement.affected-rows.report"
+  | "bb.task-check.database.statement.shadow-dry-run"
+  | "bb.task-check.database.statement.dependency";
 
 export type TaskCheckStatus = "SUCCESS" | "WARN" | "ERROR";
 
@@ -2,6 +2,7 @@ import {
   RowStatus,
   Environment,
   IssueType,
+  OnlineMigrationConfig,
   PolicyId,
   RuleType,
   RuleLevel,
@@ -15,7 +16,15 @@ export type PolicyType =
   | "bb.policy.environment-tier"
   | "bb.policy.sensitive-data"
   | "bb.policy.access-control"
-  | "bb.policy.slow-query";
+  | "bb.policy.slow-query"
+  | "bb.policy.affected-rows-approval"
+  | "bb.policy.sql-review-override"
+  | "bb.policy.online-migration"
+  | "bb.policy.partition-retention"
+  | "bb.policy.query-limit"
+  | "bb.policy.auto-approval"
+  | "bb.policy.export"
+  | "bb.policy.dlp";
 
 export type PipelineApprovalPolicyValue =
   | "MANUAL_APPROVAL_NEVER"
@@ -54,6 +63,21 @@ export type SQLReviewPolicyPayload = {
     level: RuleLevel;
     payload: string;
     comment: string;
+    version?: number;
+  }[];
+  version?: number;
+};
+
+// SQLReviewOverridePolicyPayload is the project level override for the SQL review policy.
+// The rules rolled out after pinnedVersion are skipped, zero means the latest version.
+// The empty level or payload in the ruleList means using the environment one.
+export type SQLReviewOverridePolicyPayload = {
+  pinnedVersion: number;
+  ruleList: {
+    type: RuleType;
+    level?: RuleLevel;
+    payload?: string;
+    comment?: string;
   }[];
 };
 
@@ -72,13 +96,18 @@ export type AssigneeGroup = {
   value: AssigneeGroupValue;
 };
 
-export type SensitiveDataMaskType = "DEFAULT";
+// The SHA256, HMAC and format-preserving encryptions FF1 and FF3 (FF3-1) are
+// deterministic, so the masked values can still be joined.
+export type SensitiveDataMaskType = "DEFAULT" | "SHA256" | "HMAC" | "FF1" | "FF3";
 
 export type SensitiveData = {
   schema: string;
   table: string;
   column: string;
   maskType: SensitiveDataMaskType;
+  // The CEL expression over the row and the requester deciding whether to mask the value,
+  // e.g. `row.country == "DE"`. Empty means always masked.
+  condition?: string;
 };
 
 export type SensitiveDataPolicyPayload = {
@@ -97,6 +126,113 @@ export type SlowQueryPolicyPayload = {
   active: boolean;
 };
 
+// AffectedRowsApprovalPolicyPayload escalates the risk level of the tasks
+// whose estimated affected rows exceed maxAffectedRows, zero means no limit.
+export type AffectedRowsApprovalPolicyPayload = {
+  maxAffectedRows: number;
+  riskLevel: number;
+};
+
+// OnlineMigrationPolicyPayload is the default online migration engine and
+// throttling parameters of an environment.
+export type OnlineMigrationPolicyPayload = OnlineMigrationConfig;
+
+export type PartitionInterval = "DAY" | "MONTH";
+
+export type PartitionExpireAction = "DROP" | "ARCHIVE";
+
+// PartitionRetentionRule keeps precreateCount future partitions and
+// retentionCount past partitions of the range partitioned table, zero
+// retentionCount means the partitions never expire.
+export type PartitionRetentionRule = {
+  schema: string;
+  table: string;
+  interval: PartitionInterval;
+  precreateCount: number;
+  retentionCount: number;
+  expireAction: PartitionExpireAction;
+};
+
+export type PartitionRetentionPolicyPayload = {
+  ruleList: PartitionRetentionRule[];
+};
+
+// QueryLimitRule limits the queries run by the members of the project role,
+// the workspace Owners and DBAs have the project OWNER role. Zero means no
+// limit.
+export type QueryLimitRule = {
+  role: string;
+  maxExecutionSeconds: number;
+  maxRowCount: number;
+};
+
+export type QueryLimitPolicyPayload = {
+  ruleList: QueryLimitRule[];
+};
+
+// AutoApprovalRule approves an issue if the CEL condition over the requester,
+// environment, database labels and risk level is true for every database
+// changed by the issue.
+export type AutoApprovalRule = {
+  id: string;
+  title: string;
+  condition: string;
+};
+
+export type AutoApprovalPolicyPayload = {
+  ruleList: AutoApprovalRule[];
+};
+
+// AutoApproval is an issue approved by an auto-approval rule, revokerId is 0
+// if it isn't revoked.
+export type AutoApproval = {
+  id: number;
+  createdTs: number;
+  issueId: number;
+  riskLevel: number;
+  ruleId: string;
+  condition: string;
+  revokerId: number;
+  revokedTs: number;
+};
+
+// METADATA writes the export ID, requester and timestamp to the file metadata,
+// COLUMN appends them as a column to the rows as well.
+export type ExportWatermarkType = "NONE" | "METADATA" | "COLUMN";
+
+// ExportPolicyPayload requires the export approval for the results with more
+// rows than approvalRowCount, 0 means no limit, or with sensitive columns.
+export type ExportPolicyPayload = {
+  watermark: ExportWatermarkType;
+  approvalRowCount: number;
+  approvalForSensitiveData: boolean;
+};
+
+// DLPPolicyPayload blocks or redacts the exported columns derived from the
+// columns classified above the clearance of the requester.
+export type DLPPolicyPayload = {
+  action: "BLOCK" | "REDACT";
+  // classificationList classifies the columns of the PII findings.
+  classificationList: {
+    classification: string;
+    level: number;
+  }[];
+  // columnList classifies the columns explicitly, overriding the findings.
+  columnList: {
+    databaseId: number;
+    schema: string;
+    table: string;
+    column: string;
+    level: number;
+  }[];
+  // clearanceList grants the clearances to roles/{role} or users/{email},
+  // the others have the level 0.
+  clearanceList: {
+    member: string;
+    level: number;
+  }[];
+};
+
 export type PolicyPayload =
   | PipelineApprovalPolicyPayload
   | BackupPlanPolicyPayload
@@ -104,7 +240,15 @@ export type PolicyPayload =
   | EnvironmentTierPolicyPayload
   | SensitiveDataPolicyPayload
   | AccessControlPolicyPayload
-  | SlowQueryPolicyPayload;
+  | SlowQueryPolicyPayload
+  | AffectedRowsApprovalPolicyPayload
+  | SQLReviewOverridePolicyPayload
+  | OnlineMigrationPolicyPayload
+  | PartitionRetentionPolicyPayload
+  | QueryLimitPolicyPayload
+  | AutoApprovalPolicyPayload
+  | ExportPolicyPayload
+  | DLPPolicyPayload;
 
 export type PolicyResourceType =
   | ""
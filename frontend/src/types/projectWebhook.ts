@@ -86,6 +86,11 @@ export const PROJECT_HOOK_ACTIVITY_ITEM_LIST: () => ProjectWebhookActivityItem[]
       label: t("project.webhook.activity-item.issue-comment-creation.label"),
       activity: "bb.issue.comment.create",
     },
+    {
+      title: t("project.webhook.activity-item.database-schema-drift.title"),
+      label: t("project.webhook.activity-item.database-schema-drift.label"),
+      activity: "bb.database.schema.drift",
+    },
   ];
 
 // Project Member
@@ -100,6 +105,8 @@ export type ProjectWebhook = {
   name: string;
   url: string;
   activityList: ActivityType[];
+  // JSON object from the activity type, or "*" for the rest, to the Go template of the payload.
+  payloadTemplates: string;
 };
 
 export type ProjectWebhookCreate = {
@@ -108,6 +115,8 @@ export type ProjectWebhookCreate = {
   name: string;
   url: string;
   activityList: ActivityType[];
+  // JSON object from the activity type, or "*" for the rest, to the Go template of the payload.
+  payloadTemplates: string;
 };
 
 export type ProjectWebhookPatch = {
@@ -116,6 +125,7 @@ export type ProjectWebhookPatch = {
   url?: string;
   // Comma separated list. Server doesn't support deserialize into pointer to string array (*[]string in Golang)
   activityList?: string;
+  payloadTemplates?: string;
 };
 
 export type ProjectWebhookTestResult = {
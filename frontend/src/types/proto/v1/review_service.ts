@@ -277,7 +277,9 @@ export interface ApprovalNode {
   groupValue?:
     | ApprovalNode_GroupValue
     | undefined;
-  /** Format: roles/{role} */
+  /**
+   * Format: roles/{role}, users/{email} for a named user, or externalApprovers/{id} for an external approver.
+   */
   role?: string | undefined;
 }
 
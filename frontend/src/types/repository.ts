@@ -22,6 +22,16 @@ export type Repository = {
   filePathTemplate: string;
   schemaPathTemplate: string;
   sheetPathTemplate: string;
+  // The repository is in the release mode if not empty, where the tags matching
+  // the filter build the releases instead of the branch pushes.
+  releaseTagFilter: string;
+  // The JSON encoded RepositoryRoutingRule list.
+  routingRules: string;
+  enableRollbackPR: boolean;
+  schemaWriteBackPR: boolean;
+  metadataPathTemplate: string;
+  // The JSON encoded RepositoryBranchRule list.
+  branchRules: string;
   enableSQLReviewCI: boolean;
   sqlReviewCIPullRequestURL: string;
   // e.g. In GitLab, this is the corresponding project id.
@@ -45,6 +55,12 @@ export type RepositoryCreate = {
   filePathTemplate: string;
   schemaPathTemplate: string;
   sheetPathTemplate: string;
+  releaseTagFilter: string;
+  routingRules: string;
+  enableRollbackPR: boolean;
+  schemaWriteBackPR: boolean;
+  metadataPathTemplate: string;
+  branchRules: string;
   externalId: string;
   accessToken: string;
   expiresTs: number;
@@ -57,6 +73,12 @@ export type RepositoryPatch = {
   filePathTemplate?: string;
   schemaPathTemplate?: string;
   sheetPathTemplate?: string;
+  releaseTagFilter?: string;
+  routingRules?: string;
+  enableRollbackPR?: boolean;
+  schemaWriteBackPR?: boolean;
+  metadataPathTemplate?: string;
+  branchRules?: string;
   enableSQLReviewCI?: boolean;
 };
 
@@ -66,9 +88,38 @@ export type RepositoryConfig = {
   filePathTemplate: string;
   schemaPathTemplate: string;
   sheetPathTemplate: string;
+  releaseTagFilter: string;
+  routingRules: string;
+  enableRollbackPR: boolean;
+  schemaWriteBackPR: boolean;
+  metadataPathTemplate: string;
+  branchRules: string;
   enableSQLReviewCI: boolean;
 };
 
+// RepositoryRoutingRule routes the files matching the path pattern relative to
+// the base directory to another project, e.g. for a monorepo.
+export type RepositoryRoutingRule = {
+  pathPattern: string;
+  // The project resource id.
+  projectId: string;
+  // Overrides the database name parsed from the file path if not empty,
+  // {{DB_NAME}} is replaced by the parsed name.
+  databaseTemplate: string;
+};
+
+// RepositoryBranchRule rolls out the pushes to the matching branches to a
+// single environment, e.g. "release/staging" to the staging environment.
+export type RepositoryBranchRule = {
+  // The glob of the branch name.
+  branch: string;
+  // The environment resource id.
+  environmentId: string;
+  // Follows the approval policy of the environment if true, otherwise the
+  // tasks always wait for the manual rollout.
+  autoRollout: boolean;
+};
+
 export type ExternalRepositoryInfo = {
   // e.g. In GitLab, this is the corresponding project id. e.g. 123
   externalId: string;
@@ -96,6 +147,8 @@ export function baseDirectoryWebUrl(
     return repository.webUrl;
   }
   let url = "";
+  // The query is appended after the path, e.g. the branch of Bitbucket Server.
+  let query = "";
   if (repository.vcs.type == "GITLAB") {
     url = `${repository.webUrl}/-/tree/${repository.branchFilter}`;
     if (!isEmpty(repository.baseDirectory)) {
@@ -111,6 +164,19 @@ export function baseDirectoryWebUrl(
     if (!isEmpty(repository.baseDirectory)) {
       url += `/${repository.baseDirectory}`;
     }
+  } else if (repository.vcs.type == "GITEA") {
+    url = `${repository.webUrl}/src/branch/${repository.branchFilter}`;
+    if (!isEmpty(repository.baseDirectory)) {
+      url += `/${repository.baseDirectory}`;
+    }
+  } else if (repository.vcs.type == "BITBUCKET_SERVER") {
+    url = `${repository.webUrl}/browse`;
+    if (!isEmpty(repository.baseDirectory)) {
+      url += `/${repository.baseDirectory}`;
+    }
+    query = `?at=${encodeURIComponent(
+      `refs/heads/${repository.branchFilter}`
+    )}`;
   }
   if (url) {
     // Replace the patterns in the filePathTemplate if possible.
@@ -147,7 +213,7 @@ export function baseDirectoryWebUrl(
       url += `/${replaced}`;
     }
 
-    return url;
+    return url + query;
   }
 
   // Fallback for other types of VCS.
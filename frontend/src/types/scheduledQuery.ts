@@ -0,0 +1,70 @@
+import { SheetId } from "./id";
+
+export type ScheduledQueryDeliveryType = "EMAIL" | "SLACK" | "WEBHOOK";
+
+export type ScheduledQueryDelivery = {
+  type: ScheduledQueryDeliveryType;
+  // target is the comma separated email addresses for EMAIL, and the URL for
+  // SLACK and WEBHOOK.
+  target: string;
+};
+
+export type ScheduledQuery = {
+  id: number;
+  creatorId: number;
+  createdTs: number;
+  updaterId: number;
+  updatedTs: number;
+  sheetId: SheetId;
+  name: string;
+  // schedule is the cron expression with 5 fields, such as "0 9 * * 1".
+  schedule: string;
+  // timezone is the IANA time zone the schedule is evaluated in.
+  timezone: string;
+  deliveryList: ScheduledQueryDelivery[];
+  enabled: boolean;
+  nextRunTs: number;
+};
+
+export type ScheduledQueryCreate = {
+  sheetId: SheetId;
+  name: string;
+  schedule: string;
+  timezone: string;
+  deliveryList: ScheduledQueryDelivery[];
+};
+
+export type ScheduledQueryPatch = {
+  name?: string;
+  schedule?: string;
+  timezone?: string;
+  deliveryList?: ScheduledQueryDelivery[];
+  enabled?: boolean;
+};
+
+export type ScheduledQueryRunStatus = "SUCCESS" | "FAILED";
+
+export type ScheduledQueryResult = {
+  columnNames: string[];
+  columnTypeNames: string[];
+  rows: unknown[][];
+  // truncated is true if the rows beyond the first 1000 are dropped.
+  truncated: boolean;
+};
+
+export type ScheduledQueryDeliveryResult = {
+  type: ScheduledQueryDeliveryType;
+  target: string;
+  // error is empty if the result is delivered.
+  error: string;
+};
+
+export type ScheduledQueryRun = {
+  id: number;
+  createdTs: number;
+  scheduledQueryId: number;
+  status: ScheduledQueryRunStatus;
+  result?: ScheduledQueryResult;
+  error: string;
+  deliveryResultList: ScheduledQueryDeliveryResult[];
+};
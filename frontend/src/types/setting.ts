@@ -1,13 +1,23 @@
+import { CloudProvider } from "./cloudDiscovery";
 import { SettingId } from "./id";
 
 export type SettingName =
   | "bb.branding.logo"
   | "bb.app.im"
+  | "bb.app.slack"
+  | "bb.app.teams"
   | "bb.workspace.watermark"
   | "bb.workspace.profile"
   | "bb.workspace.approval"
   | "bb.plugin.openai.key"
-  | "bb.plugin.openai.endpoint";
+  | "bb.plugin.openai.endpoint"
+  | "bb.workspace.cloud-discovery"
+  | "bb.workspace.query-history-retention"
+  | "bb.workspace.approval-chain"
+  | "bb.workspace.audit-sink"
+  | "bb.workspace.alerting"
+  | "bb.workspace.scim"
+  | "bb.workspace.sso-group-mapping";
 
 export type Setting = {
   id: SettingId;
@@ -28,3 +38,155 @@ export interface SettingAppIMValue {
     enabled: boolean;
   };
 }
+
+// SettingAppSlackValue is write-only, it's never returned by the server.
+export interface SettingAppSlackValue {
+  // signingSecret verifies the requests from the Slack app, whose
+  // interactivity request URL is {external URL}/hook/slack/interaction and
+  // the /bytebase slash command request URL is
+  // {external URL}/hook/slack/command.
+  signingSecret: string;
+  // botToken maps the Slack users to the Bytebase users by email, it needs
+  // the users:read and users:read.email scopes.
+  botToken: string;
+}
+
+// SettingAppTeamsValue is write-only, it's never returned by the server.
+export interface SettingAppTeamsValue {
+  // tenantId, appId and appPassword are the Azure app registration of the
+  // bot, whose messaging endpoint is {external URL}/hook/teams/bot. The app
+  // needs the User.Read.All application permission of Microsoft Graph to map
+  // the Teams users to the Bytebase users by email.
+  tenantId: string;
+  appId: string;
+  appPassword: string;
+  // outgoingWebhookToken is the base64 security token of the outgoing
+  // webhook, whose callback URL is {external URL}/hook/teams/outgoing.
+  outgoingWebhookToken?: string;
+}
+
+export interface SettingWorkspaceCloudDiscoveryValue {
+  providers: {
+    provider: CloudProvider;
+    // regions are the AWS regions.
+    regions?: string[];
+    // projects are the GCP project IDs.
+    projects?: string[];
+    // subscriptions are the Azure subscription IDs.
+    subscriptions?: string[];
+  }[];
+  // environmentMappings suggests the environment of the discovered instances,
+  // the first matched rule wins.
+  environmentMappings: {
+    labelKey?: string;
+    labelValue?: string;
+    namePattern?: string;
+    // environment is the resource ID of the environment.
+    environment: string;
+  }[];
+}
+
+export interface SettingWorkspaceQueryHistoryRetentionValue {
+  // retentionDays is the days the query histories are kept, 0 keeps them
+  // forever.
+  retentionDays: number;
+  // maxCountPerUser is the number of the latest query histories kept for each
+  // user, 0 keeps all of them.
+  maxCountPerUser: number;
+}
+
+export interface SettingWorkspaceApprovalChainValue {
+  // escalationList lets the escalation approver approve the steps pending for
+  // longer than the timeout, by the risk levels.
+  escalationList: {
+    level: number;
+    timeoutSeconds: number;
+    // approver is either roles/{role} or users/{email}.
+    approver: string;
+  }[];
+  // externalApproverList is referred by the approval nodes in the format of
+  // externalApprovers/{id}. The pending steps are posted to the URLs of the
+  // WEBHOOK approvers, and sent to the approvers of the FEISHU approvers as
+  // Feishu approval instances.
+  externalApproverList: {
+    id: string;
+    title: string;
+    type?: "WEBHOOK" | "FEISHU";
+    url: string;
+    // approver is the email of the Feishu user of the FEISHU approver.
+    approver?: string;
+  }[];
+}
+
+export type AuditSinkType = "SYSLOG" | "SPLUNK_HEC" | "KAFKA";
+
+export interface SettingWorkspaceAuditSinkValue {
+  // sinkList is the SIEM systems every audit entry is streamed to, the failed
+  // deliveries are retried in order.
+  sinkList: {
+    // id tracks the delivery progress, a new id streams from the latest entry.
+    id: string;
+    type: AuditSinkType;
+    disabled: boolean;
+    // url is tcp://host:port or udp://host:port for syslog, the HEC endpoint
+    // for Splunk HEC, and the REST Proxy base URL for Kafka.
+    url: string;
+    // token is never returned, an empty token keeps the saved one.
+    token: string;
+    topic: string;
+  }[];
+}
+
+export type AlertReceiverType = "PAGERDUTY" | "OPSGENIE";
+
+export type AlertCondition =
+  | "TASK_RUN_FAILED"
+  | "SCHEMA_DRIFT"
+  | "INSTANCE_UNREACHABLE";
+
+export interface SettingWorkspaceAlertingValue {
+  // receiverList is the alerting systems the incidents are opened on, the
+  // incidents are resolved after the conditions clear.
+  receiverList: {
+    id: string;
+    type: AlertReceiverType;
+    disabled: boolean;
+    // url overrides the default API URL, e.g. https://api.eu.opsgenie.com.
+    url: string;
+    // key is the PagerDuty routing key or the Opsgenie API key, it's never
+    // returned, an empty key keeps the saved one.
+    key: string;
+    // conditionList is the alerted conditions, empty alerts all of them.
+    conditionList: AlertCondition[];
+  }[];
+}
+
+export interface SettingWorkspaceSCIMValue {
+  enabled: boolean;
+  // token is the bearer token of the identity providers calling /scim/v2, it's
+  // never returned, an empty token keeps the saved one.
+  token: string;
+}
+
+export interface SettingWorkspaceSSOGroupMappingValue {
+  mappingList: {
+    // identityProvider is the resource ID of the OIDC or OAuth2 identity
+    // provider.
+    identityProvider: string;
+    // groupClaim is the claim of the groups in the user info, "groups" if it's
+    // empty.
+    groupClaim: string;
+    ruleList: {
+      group: string;
+      // workspaceRole is optional, the users get the highest role of their
+      // groups, the ones in none of the groups with roles are developers.
+      workspaceRole: "" | "OWNER" | "DBA" | "DEVELOPER";
+      // projectRoleList is the project roles granted to the users in the
+      // group, they're revoked after the users leave the group.
+      projectRoleList: {
+        project: string;
+        role: string;
+      }[];
+    }[];
+  }[];
+}
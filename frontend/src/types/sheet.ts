@@ -17,9 +17,11 @@ export type SheetSource =
   | "GITLAB"
   | "GITHUB"
   | "BITBUCKET"
+  | "GITEA"
+  | "BITBUCKET_SERVER"
   | "BYTEBASE_ARTIFACT";
 
-export type SheetType = "SQL";
+export type SheetType = "SQL" | "NOTEBOOK";
 
 interface SheetVCSPayload {
   fileName: string;
@@ -40,12 +42,54 @@ export type SheetIssueBacktracePayload = {
   issueName: string;
 };
 
+export type NotebookParameterType = "TEXT" | "NUMBER";
+
+export type NotebookParameter = {
+  name: string;
+  type: NotebookParameterType;
+  defaultValue: string;
+  description: string;
+};
+
+export type NotebookCell = {
+  // id is unique in the notebook, and identifies the persisted result.
+  id: string;
+  title: string;
+  // statement refers the parameters by "{{name}}".
+  statement: string;
+};
+
+export type SheetNotebookPayload = {
+  cellList: NotebookCell[];
+  parameterList: NotebookParameter[];
+};
+
+export type NotebookCellRun = {
+  cellId: string;
+  // The default values are used for the missing parameters.
+  parameterValues: Record<string, string>;
+};
+
+export type NotebookCellResult = {
+  sheetId: SheetId;
+  cellId: string;
+  creatorId: PrincipalId;
+  createdTs: number;
+  parameterValues: Record<string, string>;
+  columnNames: string[];
+  columnTypeNames: string[];
+  rows: unknown[][];
+  truncated: boolean;
+  error: string;
+};
+
 // eslint-disable-next-line @typescript-eslint/ban-types
 type SheetEmptyPayload = {};
 
 export type SheetPayload =
   | SheetVCSPayload
   | SheetIssueBacktracePayload
+  | SheetNotebookPayload
   | SheetEmptyPayload;
 
 export interface Sheet {
@@ -97,6 +141,7 @@ export interface SheetCreate {
   visibility: SheetVisibility;
   payload: SheetPayload;
   source: SheetSource;
+  type?: SheetType;
 }
 
 export interface SheetPatch {
@@ -0,0 +1,43 @@
+import { ProjectId } from "./id";
+
+export type SnippetVisibility = "PRIVATE" | "PROJECT" | "WORKSPACE";
+
+// SnippetVariable is a variable of the "{{name}}" placeholders in the content.
+export type SnippetVariable = {
+  name: string;
+  defaultValue: string;
+  description: string;
+};
+
+export type Snippet = {
+  id: number;
+  creatorId: number;
+  createdTs: number;
+  updaterId: number;
+  updatedTs: number;
+  // projectId is only set for the PROJECT visibility.
+  projectId?: ProjectId;
+  visibility: SnippetVisibility;
+  name: string;
+  description: string;
+  content: string;
+  variableList: SnippetVariable[];
+  usageCount: number;
+  lastUsedTs: number;
+};
+
+export type SnippetCreate = {
+  projectId?: ProjectId;
+  visibility: SnippetVisibility;
+  name: string;
+  description: string;
+  content: string;
+  variableList: SnippetVariable[];
+};
+
+export type SnippetPatch = Partial<SnippetCreate>;
+
+export type SnippetFind = {
+  project?: ProjectId;
+  query?: string;
+};
@@ -1,3 +1,8 @@
+import {
+  AuthenticationType,
+  SOCKS5Proxy,
+  SSHJumpHost,
+} from "./dataSource";
 import { EngineType } from "./instance";
 import { InstanceId } from "./id";
 import { Advice } from "./sqlAdvice";
@@ -30,6 +35,32 @@ export type ConnectionInfo = {
   sshUser: string;
   sshPassword: string;
   sshPrivateKey: string;
+  // Connection over proxies.
+  sshJumpHostList?: SSHJumpHost[];
+  socks5Proxy?: SOCKS5Proxy;
+  // Authentication.
+  authenticationType?: AuthenticationType;
+  awsRegion?: string;
+  awsAccessKeyId?: string;
+  awsSecretAccessKey?: string;
+  gcpCloudSqlConnectionName?: string;
+  gcpCloudSqlUsePrivateIp?: boolean;
+  gcpCredentialsJson?: string;
+  azureTenantId?: string;
+  azureClientId?: string;
+  azureClientSecret?: string;
+  kerberosRealm?: string;
+  kerberosKdc?: string;
+  kerberosSpn?: string;
+  kerberosKeytab?: string;
+  externalSecretReference?: string;
+};
+
+// SessionVariable is a whitelisted session variable, such as time_zone of
+// MySQL or search_path of PostgreSQL, set before the statement.
+export type SessionVariable = {
+  name: string;
+  value: string;
 };
 
 export type QueryInfo = {
@@ -37,6 +68,14 @@ export type QueryInfo = {
   databaseName?: string;
   statement: string;
   limit?: number;
+  // Materializes the whole result on the server and pages through it.
+  cursor?: boolean;
+  sessionVariableList?: SessionVariable[];
+  // The admin mode session the statement is recorded in, a session is opened
+  // for the statement if not set.
+  adminSessionId?: number;
+  // queryId identifies the running query to cancel it.
+  queryId?: string;
 };
 
 // TODO(Jim): not used yet
@@ -44,6 +83,138 @@ export type SingleSQLResult = {
   // [columnNames: string[], types: string[], data: any[][], sensitive?: boolean[]]
   data: [string[], string[], any[][], boolean[]];
   error: string;
+  // The data only contains the first page of the cursor if it's set.
+  cursorId?: string;
+  rowCount?: number;
+  truncated?: boolean;
+};
+
+export type SQLCursorPage = {
+  data: [string[], string[], any[][], boolean[]];
+  rowCount: number;
+  truncated: boolean;
+};
+
+export type SQLExplainInfo = {
+  instanceId: InstanceId;
+  databaseName?: string;
+  statement: string;
+  // Runs the statement to collect the actual rows and time, only for Owners and DBAs.
+  analyze?: boolean;
+};
+
+export type QueryPlanNode = {
+  operation: string;
+  relation?: string;
+  index?: string;
+  detail?: string;
+  startupCost: number;
+  totalCost: number;
+  estimatedRows: number;
+  // Only set in the analyzed plans, the totals of all loops.
+  actualRows?: number;
+  actualTimeMs?: number;
+  loops?: number;
+  children?: QueryPlanNode[];
+};
+
+export type QueryPlan = {
+  root: QueryPlanNode;
+  analyzed: boolean;
+  planningTimeMs?: number;
+  executionTimeMs?: number;
+  raw: string;
+};
+
+export type SQLCompletionInfo = {
+  instanceId: InstanceId;
+  databaseName: string;
+  statement: string;
+  // 1-based position of the caret.
+  caretLine: number;
+  caretColumn: number;
+};
+
+export type SQLCompletionCandidateType =
+  | "TABLE"
+  | "VIEW"
+  | "COLUMN"
+  | "ALIAS"
+  | "SCHEMA"
+  | "FUNCTION";
+
+export type SQLCompletionCandidate = {
+  text: string;
+  type: SQLCompletionCandidateType;
+  // The table of the columns and the schema of the tables.
+  parent?: string;
+  detail?: string;
+};
+
+export type SQLResultDiffDatabase = {
+  instanceId: InstanceId;
+  databaseName: string;
+};
+
+export type SQLResultDiffInfo = {
+  statement: string;
+  source: SQLResultDiffDatabase;
+  target: SQLResultDiffDatabase;
+  // The columns identifying a row, the whole rows are compared if empty.
+  keyColumnList: string[];
+};
+
+export type SQLResultDiffChangedRow = {
+  source: unknown[];
+  target: unknown[];
+  changedColumnList: string[];
+};
+
+export type SQLResultDiff = {
+  columnNames: string[];
+  sourceRowCount: number;
+  targetRowCount: number;
+  sameRowCount: number;
+  // addedRows are only in the target, and removedRows are only in the source.
+  addedRows: unknown[][];
+  removedRows: unknown[][];
+  changedRows: SQLResultDiffChangedRow[];
+};
+
+export type SQLResultChartAggregation = "COUNT" | "SUM" | "AVG" | "MIN" | "MAX";
+
+export type SQLResultChartTimeBucket =
+  | ""
+  | "MINUTE"
+  | "HOUR"
+  | "DAY"
+  | "WEEK"
+  | "MONTH"
+  | "YEAR";
+
+// SQLResultChartInfo aggregates the result of the query cursor on the server.
+export type SQLResultChartInfo = {
+  // categoryColumn is grouped by as the x-axis.
+  categoryColumn: string;
+  timeBucket: SQLResultChartTimeBucket;
+  // seriesColumn pivots its distinct values into the series, it's optional.
+  seriesColumn: string;
+  // valueColumn is optional for COUNT.
+  valueColumn: string;
+  aggregation: SQLResultChartAggregation;
+};
+
+export type SQLResultChartSeries = {
+  name: string;
+  // data is aligned with the categoryList, null for no rows.
+  data: (number | null)[];
+};
+
+export type SQLResultChart = {
+  categoryList: string[];
+  seriesList: SQLResultChartSeries[];
+  rowCount: number;
+  truncated: boolean;
 };
 
 export type SQLResultSet = {
@@ -6,7 +6,12 @@ export type EditorModel = monaco.editor.ITextModel;
 export type EditorPosition = monaco.Position;
 export type CompletionItems = monaco.languages.CompletionItem[];
 
-export type Language = "sql" | "javascript" | "redis";
+export type Language =
+  | "sql"
+  | "javascript"
+  | "redis"
+  | "elasticsearch"
+  | "cypher";
 
 export const EngineTypesUsingSQL = [
   "MYSQL",
@@ -25,6 +30,12 @@ export const languageOfEngine = (engine?: EngineType | "unknown"): Language => {
   if (engine === "REDIS") {
     return "redis";
   }
+  if (engine === "ELASTICSEARCH") {
+    return "elasticsearch";
+  }
+  if (engine === "NEO4J") {
+    return "cypher";
+  }
 
   return "sql";
 };
@@ -64,3 +75,28 @@ export interface QueryHistory {
   // Customized fields
   createdAt: string;
 }
+
+export type QueryHistorySearch = {
+  // query is the full-text search, each word matches the words in the
+  // statements with the prefix.
+  query?: string;
+  instanceId?: InstanceId;
+  databaseName?: string;
+  // from and to are the unix timestamps in seconds, to is exclusive.
+  from?: number;
+  to?: number;
+  limit?: number;
+  offset?: number;
+};
+
+export type QueryHistoryRecord = {
+  id: number;
+  creatorId: number;
+  createdTs: number;
+  instanceId: InstanceId;
+  databaseId: DatabaseId;
+  databaseName: string;
+  statement: string;
+  durationNs: number;
+  error: string;
+};
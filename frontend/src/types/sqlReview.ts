@@ -9,7 +9,17 @@ import sqlReviewProdTemplate from "./sql-review.prod.yaml";
 import sqlReviewDevTemplate from "./sql-review.dev.yaml";
 
 // The engine type for rule template
-export type SchemaRuleEngineType = "MYSQL" | "POSTGRES" | "TIDB";
+export type SchemaRuleEngineType =
+  | "MYSQL"
+  | "POSTGRES"
+  | "TIDB"
+  | "SNOWFLAKE"
+  | "REDIS"
+  | "MONGODB"
+  | "ELASTICSEARCH"
+  | "CASSANDRA"
+  | "DYNAMODB"
+  | "NEO4J";
 
 // The category type for rule template
 export type CategoryType =
@@ -96,6 +106,11 @@ export type RuleType =
   | "table.no-foreign-key"
   | "table.drop-naming-convention"
   | "table.disallow-partition"
+  | "table.require-partition"
+  | "table.require-hypertable"
+  | "table.require-row-level-security"
+  | "table.require-clustering-key"
+  | "table.mongodb.disallow-drop-collection"
   | "table.comment"
   | "naming.table"
   | "naming.column"
@@ -104,6 +119,8 @@ export type RuleType =
   | "naming.index.fk"
   | "naming.index.idx"
   | "naming.column.auto-increment"
+  | "naming.stage"
+  | "naming.pipe"
   | "column.required"
   | "column.no-null"
   | "column.comment"
@@ -123,6 +140,7 @@ export type RuleType =
   | "statement.where.require"
   | "statement.where.no-leading-wildcard-like"
   | "statement.disallow-commit"
+  | "statement.disallow-hard-coded-secret"
   | "statement.disallow-limit"
   | "statement.disallow-order-by"
   | "statement.merge-alter-table"
@@ -134,7 +152,14 @@ export type RuleType =
   | "statement.disallow-add-column-with-default"
   | "statement.add-check-not-valid"
   | "statement.disallow-add-not-null"
+  | "statement.redis.command-disallow-list"
+  | "statement.mongodb.require-filter"
+  | "statement.elasticsearch.require-filter"
+  | "statement.cassandra.disallow-allow-filtering"
+  | "statement.dynamodb.disallow-full-table-scan"
   | "schema.backward-compatibility"
+  | "schema.disallow-public-object"
+  | "schema.require-idempotent-migration"
   | "database.drop-empty-database"
   | "system.charset.allowlist"
   | "system.collation.allowlist"
@@ -145,6 +170,9 @@ export type RuleType =
   | "index.total-number-limit"
   | "index.primary-key-type-allowlist"
   | "index.create-concurrently"
+  | "index.no-redundant"
+  | "index.drop-require-invisible"
+  | "index.mongodb.require-background"
   | "index.pk-type-limit";
 
 // The naming format rule payload.
@@ -173,6 +201,13 @@ interface NumberLimitPayload {
   number: number;
 }
 
+// The require partition rule payload.
+// Used by the backend.
+interface RequirePartitionPayload {
+  format: string;
+  list: string[];
+}
+
 // The SchemaPolicyRule stores the rule configuration by users.
 // Used by the backend
 export interface SchemaPolicyRule {
@@ -182,7 +217,8 @@ export interface SchemaPolicyRule {
     | NamingFormatPayload
     | StringArrayLimitPayload
     | CommentFormatPayload
-    | NumberLimitPayload;
+    | NumberLimitPayload
+    | RequirePartitionPayload;
   comment: string;
 }
 
@@ -357,9 +393,13 @@ export const convertPolicyRuleToRuleTemplate = (
   const templateComponent = ruleTemplate.componentList.find(
     (c) => c.payload.type === "TEMPLATE"
   );
+  const partitionStrategyComponent = ruleTemplate.componentList.find(
+    (c) => c.payload.type === "STRING_ARRAY"
+  );
 
   switch (ruleTemplate.type) {
     case "table.drop-naming-convention":
+    case "table.require-hypertable":
       if (!stringComponent) {
         throw new Error(`Invalid rule ${ruleTemplate.type}`);
       }
@@ -379,6 +419,8 @@ export const convertPolicyRuleToRuleTemplate = (
     case "naming.column":
     case "naming.column.auto-increment":
     case "naming.table":
+    case "naming.stage":
+    case "naming.pipe":
       if (!stringComponent || !numberComponent) {
         throw new Error(`Invalid rule ${ruleTemplate.type}`);
       }
@@ -468,9 +510,11 @@ export const convertPolicyRuleToRuleTemplate = (
       };
     }
     case "column.type-disallow-list":
+    case "table.require-row-level-security":
     case "index.primary-key-type-allowlist":
     case "system.charset.allowlist":
-    case "system.collation.allowlist": {
+    case "system.collation.allowlist":
+    case "statement.redis.command-disallow-list": {
       const stringArrayComponent = ruleTemplate.componentList[0];
       const stringArrayPayload = {
         ...stringArrayComponent.payload,
@@ -486,6 +530,30 @@ export const convertPolicyRuleToRuleTemplate = (
         ],
       };
     }
+    case "table.require-partition":
+      if (!stringComponent || !partitionStrategyComponent) {
+        throw new Error(`Invalid rule ${ruleTemplate.type}`);
+      }
+
+      return {
+        ...res,
+        componentList: [
+          {
+            ...stringComponent,
+            payload: {
+              ...stringComponent.payload,
+              value: (policyRule.payload as RequirePartitionPayload).format,
+            } as StringPayload,
+          },
+          {
+            ...partitionStrategyComponent,
+            payload: {
+              ...partitionStrategyComponent.payload,
+              value: (policyRule.payload as RequirePartitionPayload).list,
+            } as StringArrayPayload,
+          },
+        ],
+      };
     case "column.comment":
     case "table.comment":
       if (!booleanComponent || !numberComponent) {
@@ -518,6 +586,8 @@ export const convertPolicyRuleToRuleTemplate = (
     case "index.key-number-limit":
     case "index.total-number-limit":
     case "system.comment.length":
+    case "index.drop-require-invisible":
+    case "table.require-clustering-key":
       if (!numberComponent) {
         throw new Error(`Invalid rule ${ruleTemplate.type}`);
       }
@@ -565,9 +635,13 @@ export const convertRuleTemplateToPolicyRule = (
   const booleanPayload = rule.componentList.find(
     (c) => c.payload.type === "BOOLEAN"
   )?.payload as BooleanPayload | undefined;
+  const partitionStrategyPayload = rule.componentList.find(
+    (c) => c.payload.type === "STRING_ARRAY"
+  )?.payload as StringArrayPayload | undefined;
 
   switch (rule.type) {
     case "table.drop-naming-convention":
+    case "table.require-hypertable":
       if (!stringPayload) {
         throw new Error(`Invalid rule ${rule.type}`);
       }
@@ -581,6 +655,8 @@ export const convertRuleTemplateToPolicyRule = (
     case "naming.column":
     case "naming.column.auto-increment":
     case "naming.table":
+    case "naming.stage":
+    case "naming.pipe":
       if (!stringPayload || !numberPayload) {
         throw new Error(`Invalid rule ${rule.type}`);
       }
@@ -617,9 +693,11 @@ export const convertRuleTemplateToPolicyRule = (
       };
     case "column.required":
     case "column.type-disallow-list":
+    case "table.require-row-level-security":
     case "index.primary-key-type-allowlist":
     case "system.charset.allowlist":
-    case "system.collation.allowlist": {
+    case "system.collation.allowlist":
+    case "statement.redis.command-disallow-list": {
       const stringArrayPayload = rule.componentList[0]
         .payload as StringArrayPayload;
       return {
@@ -629,6 +707,18 @@ export const convertRuleTemplateToPolicyRule = (
         },
       };
     }
+    case "table.require-partition":
+      if (!stringPayload || !partitionStrategyPayload) {
+        throw new Error(`Invalid rule ${rule.type}`);
+      }
+      return {
+        ...base,
+        payload: {
+          format: stringPayload.value ?? stringPayload.default,
+          list:
+            partitionStrategyPayload.value ?? partitionStrategyPayload.default,
+        },
+      };
     case "column.comment":
     case "table.comment":
       if (!booleanPayload || !numberPayload) {
@@ -648,6 +738,8 @@ export const convertRuleTemplateToPolicyRule = (
     case "index.key-number-limit":
     case "index.total-number-limit":
     case "system.comment.length":
+    case "index.drop-require-invisible":
+    case "table.require-clustering-key":
       if (!numberPayload) {
         throw new Error(`Invalid rule ${rule.type}`);
       }
@@ -2,6 +2,7 @@ import {
   Advice,
   DatabaseId,
   InstanceId,
+  SessionVariable,
   SheetId,
   SQLResultSet,
 } from "../types";
@@ -45,6 +46,12 @@ export interface TabInfo {
   queryResult?: SQLResultSet;
   sheetId?: SheetId;
   adviceList?: Advice[];
+  // The session variables are sent with every statement run in the tab.
+  sessionVariableList?: SessionVariable[];
+  // The admin mode session the statements run in the tab are recorded in.
+  adminSessionId?: number;
+  // The ID of the query running in the tab to cancel it.
+  runningQueryId?: string;
 }
 
 export type CoreTabInfo = Pick<TabInfo, "connection" | "sheetId" | "mode">;
@@ -0,0 +1,9 @@
+// UserGroup is the group of users provisioned by the identity provider
+// through SCIM.
+export type UserGroup = {
+  id: number;
+  title: string;
+  externalId: string;
+  // memberList is the emails of the members.
+  memberList: string[];
+};
@@ -2,14 +2,21 @@ import { VCSId } from "./id";
 
 // Backend uses the same ENUM for GitLab/GitHub SaaS and self-hosted. Because they are based on the
 // same codebase.
-export type VCSType = "GITLAB" | "GITHUB" | "BITBUCKET";
+export type VCSType =
+  | "GITLAB"
+  | "GITHUB"
+  | "BITBUCKET"
+  | "GITEA"
+  | "BITBUCKET_SERVER";
 
 // When configuring the VCS, we split the SaaS and self-hosted into two types to present optimal UX.
 export type VCSUIType =
   | "GITLAB_SELF_HOST"
   | "GITLAB_COM"
   | "GITHUB_COM"
-  | "BITBUCKET_ORG";
+  | "BITBUCKET_ORG"
+  | "GITEA_SELF_HOST"
+  | "BITBUCKET_SERVER_SELF_HOST";
 
 export interface VCSConfig {
   type: VCSType;
@@ -97,6 +104,12 @@ export function isValidVCSApplicationIdOrSecret(
     return /^[a-zA-Z0-9_]{20}$|^[a-zA-Z0-9_]{40}$/.test(str);
   } else if (vcsType == "BITBUCKET") {
     return /^[a-zA-Z0-9_]{18}$|^[a-zA-Z0-9_]{32}$/.test(str);
+  } else if (vcsType == "GITEA") {
+    // The application ID is a UUID, the secret is prefixed with "gto_" since Gitea 1.17.
+    return /^[a-f0-9-]{36}$|^gto_[a-z0-9]{52}$|^[a-zA-Z0-9_=-]{44}$/.test(str);
+  } else if (vcsType == "BITBUCKET_SERVER") {
+    // The application ID is the username of the service account, the secret is its HTTP access token.
+    return /^\S+$/.test(str);
   }
   return false;
 }
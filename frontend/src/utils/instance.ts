@@ -1,5 +1,6 @@
 import { computed, unref } from "vue";
 import {
+  AuthenticationType,
   EngineType,
   Environment,
   Instance,
@@ -23,6 +24,10 @@ export const supportedEngineList = () => {
     "MARIADB",
     "MSSQL",
     "REDSHIFT",
+    "ELASTICSEARCH",
+    "CASSANDRA",
+    "DYNAMODB",
+    "NEO4J",
   ];
   return engines;
 };
@@ -90,6 +95,10 @@ export const instanceHasBackupRestore = (
   if (engine === "REDIS") return false;
   if (engine === "SPANNER") return false;
   if (engine === "REDSHIFT") return false;
+  if (engine === "ELASTICSEARCH") return false;
+  if (engine === "CASSANDRA") return false;
+  if (engine === "DYNAMODB") return false;
+  if (engine === "NEO4J") return false;
   return true;
 };
 
@@ -99,6 +108,7 @@ export const instanceHasReadonlyMode = (
   const engine = engineOfInstance(instanceOrEngine);
   if (engine === "MONGODB") return false;
   if (engine === "REDIS") return false;
+  if (engine === "ELASTICSEARCH") return false;
   return true;
 };
 
@@ -108,6 +118,10 @@ export const instanceHasCreateDatabase = (
   const engine = engineOfInstance(instanceOrEngine);
   if (engine === "REDIS") return false;
   if (engine === "ORACLE") return false;
+  if (engine === "ELASTICSEARCH") return false;
+  if (engine === "CASSANDRA") return false;
+  if (engine === "DYNAMODB") return false;
+  if (engine === "NEO4J") return false;
   return true;
 };
 
@@ -117,6 +131,7 @@ export const instanceHasStructuredQueryResult = (
   const engine = engineOfInstance(instanceOrEngine);
   if (engine === "MONGODB") return false;
   if (engine === "REDIS") return false;
+  if (engine === "ELASTICSEARCH") return false;
   return true;
 };
 
@@ -133,6 +148,9 @@ export const instanceHasSSL = (
     "ORACLE",
     "MARIADB",
     "OCEANBASE",
+    "ELASTICSEARCH",
+    "CASSANDRA",
+    "NEO4J",
   ].includes(engine);
 };
 
@@ -143,6 +161,29 @@ export const instanceHasSSH = (
   return ["MYSQL", "TIDB", "MARIADB", "OCEANBASE"].includes(engine);
 };
 
+// The password authentication is always supported, so the list is empty if
+// there is no other authentication type for the engine.
+export const authenticationTypeListOfEngine = (
+  instanceOrEngine: Instance | EngineType
+): AuthenticationType[] => {
+  const engine = engineOfInstance(instanceOrEngine);
+  switch (engine) {
+    case "MYSQL":
+      return ["PASSWORD", "AWS_RDS_IAM", "GCP_CLOUD_SQL_IAM"];
+    case "POSTGRES":
+      return [
+        "PASSWORD",
+        "AWS_RDS_IAM",
+        "GCP_CLOUD_SQL_IAM",
+        "AZURE_AD",
+        "KERBEROS",
+      ];
+    case "MSSQL":
+      return ["PASSWORD", "AZURE_AD", "KERBEROS"];
+  }
+  return [];
+};
+
 export const instanceHasCollationAndCharacterSet = (
   instanceOrEngine: Instance | EngineType
 ) => {
@@ -153,6 +194,10 @@ export const instanceHasCollationAndCharacterSet = (
     "CLICKHOUSE",
     "SNOWFLAKE",
     "REDSHIFT",
+    "ELASTICSEARCH",
+    "CASSANDRA",
+    "DYNAMODB",
+    "NEO4J",
   ];
   return !excludedList.includes(engine);
 };
@@ -561,7 +561,7 @@ type ApprovalNode_GroupValue_ struct {
 }
 
 type ApprovalNode_Role struct {
-	// Format: roles/{role}
+	// Format: roles/{role}, users/{email} for a named user, or externalApprovers/{id} for an external approver.
 	Role string `protobuf:"bytes,3,opt,name=role,proto3,oneof"`
 }
 